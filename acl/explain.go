@@ -0,0 +1,127 @@
+package acl
+
+import (
+	"fmt"
+
+	"github.com/armon/go-radix"
+)
+
+// ExplainedRule is the rule of a policy applying to a resource, as reported
+// when explaining an authorization decision.
+type ExplainedRule struct {
+	// Resource is the kind of resource the rule applies to. It can differ from
+	// the resource explained when the rule of another resource applies, such as
+	// the operator rule for the mesh resource.
+	Resource Resource
+
+	// Segment is the name or prefix the rule applies to. It is empty for the
+	// resources without segments.
+	Segment string
+
+	// Prefix is whether the rule applies to the segments starting with Segment
+	// rather than to Segment only.
+	Prefix bool
+
+	// Access is the access level granted by the rule.
+	Access string
+}
+
+// String returns the rule in the HCL syntax of the policies.
+func (r *ExplainedRule) String() string {
+	switch r.Resource {
+	case ResourceACL, ResourceKeyring, ResourceOperator, ResourceMesh, ResourcePeering:
+		return fmt.Sprintf("%s = %q", r.Resource, r.Access)
+	case ResourceIntention:
+		return fmt.Sprintf("%s %q { intentions = %q }", r.block(ResourceService), r.Segment, r.Access)
+	default:
+		return fmt.Sprintf("%s %q { policy = %q }", r.block(r.Resource), r.Segment, r.Access)
+	}
+}
+
+func (r *ExplainedRule) block(rsc Resource) string {
+	if r.Prefix {
+		return string(rsc) + "_prefix"
+	}
+	return string(rsc)
+}
+
+// ExplainRule returns the rule of the merged policies which decides the
+// access to the segment of the resource, or nil if none applies and the
+// decision is left to the default policy.
+//
+// The rules granting access to a whole set of segments at once, such as the
+// one checked by the "write-prefix" access of keys, are reported as the rule
+// applying to the segment itself.
+func ExplainRule(policies []*Policy, conf *Config, rsc Resource, segment string) (*ExplainedRule, error) {
+	authz, err := newPolicyAuthorizer(policies, conf)
+	if err != nil {
+		return nil, err
+	}
+	return authz.explain(rsc, segment), nil
+}
+
+func (p *policyAuthorizer) explain(rsc Resource, segment string) *ExplainedRule {
+	var rule *policyAuthorizerRule
+	var tree *radix.Tree
+	switch rsc {
+	case ResourceACL:
+		rule = p.aclRule
+	case ResourceKeyring:
+		rule = p.keyringRule
+	case ResourceOperator:
+		rule = p.operatorRule
+	case ResourceMesh:
+		if p.meshRule == nil {
+			// The mesh functions default to the operator access.
+			return p.explain(ResourceOperator, segment)
+		}
+		rule = p.meshRule
+	case ResourcePeering:
+		if p.peeringRule == nil {
+			// The peering functions default to the operator access.
+			return p.explain(ResourceOperator, segment)
+		}
+		rule = p.peeringRule
+	case ResourceAgent:
+		tree = p.agentRules
+	case ResourceEvent:
+		tree = p.eventRules
+	case ResourceIntention:
+		tree = p.intentionRules
+	case ResourceKey:
+		tree = p.keyRules
+	case ResourceNode:
+		tree = p.nodeRules
+	case ResourceQuery:
+		tree = p.preparedQueryRules
+	case ResourceService:
+		tree = p.serviceRules
+	case ResourceSession:
+		tree = p.sessionRules
+	default:
+		return nil
+	}
+
+	if tree == nil {
+		if rule == nil {
+			return nil
+		}
+		return &ExplainedRule{Resource: rsc, Access: rule.access.String()}
+	}
+
+	// This mirrors getPolicy, keeping track of the segment of the rule.
+	var explained *ExplainedRule
+	tree.WalkPath(segment, func(path string, leaf interface{}) bool {
+		policies := leaf.(*policyAuthorizerRadixLeaf)
+		if policies.exact != nil && path == segment {
+			explained = &ExplainedRule{Resource: rsc, Segment: path, Access: policies.exact.access.String()}
+			return true
+		}
+
+		if policies.prefix != nil {
+			explained = &ExplainedRule{Resource: rsc, Segment: path, Prefix: true, Access: policies.prefix.access.String()}
+		}
+		return false
+	})
+	return explained
+}
@@ -0,0 +1,104 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainRule(t *testing.T) {
+	parse := func(t *testing.T, rules string) *Policy {
+		policy, err := NewPolicyFromSource(rules, nil, nil)
+		require.NoError(t, err)
+		return policy
+	}
+
+	type testCase struct {
+		policies []string
+		resource Resource
+		segment  string
+		expect   string
+	}
+
+	cases := map[string]testCase{
+		"exact key": {
+			policies: []string{`
+				key_prefix "" { policy = "read" }
+				key "foo/bar" { policy = "write" }
+			`},
+			resource: ResourceKey,
+			segment:  "foo/bar",
+			expect:   `key "foo/bar" { policy = "write" }`,
+		},
+		"longest prefix": {
+			policies: []string{`
+				key_prefix "" { policy = "read" }
+				key_prefix "foo/" { policy = "deny" }
+			`},
+			resource: ResourceKey,
+			segment:  "foo/bar",
+			expect:   `key_prefix "foo/" { policy = "deny" }`,
+		},
+		"merged policies": {
+			policies: []string{
+				`service_prefix "" { policy = "read" }`,
+				`service_prefix "" { policy = "write" }`,
+			},
+			resource: ResourceService,
+			segment:  "web",
+			expect:   `service_prefix "" { policy = "write" }`,
+		},
+		"intentions": {
+			policies: []string{`
+				service "web" {
+					policy = "read"
+					intentions = "write"
+				}
+			`},
+			resource: ResourceIntention,
+			segment:  "web",
+			expect:   `service "web" { intentions = "write" }`,
+		},
+		"operator": {
+			policies: []string{`operator = "read"`},
+			resource: ResourceOperator,
+			expect:   `operator = "read"`,
+		},
+		"mesh defaults to operator": {
+			policies: []string{`operator = "write"`},
+			resource: ResourceMesh,
+			expect:   `operator = "write"`,
+		},
+		"mesh": {
+			policies: []string{`
+				operator = "write"
+				mesh = "read"
+			`},
+			resource: ResourceMesh,
+			expect:   `mesh = "read"`,
+		},
+		"no rule": {
+			policies: []string{`node "web-1" { policy = "write" }`},
+			resource: ResourceNode,
+			segment:  "web-2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var policies []*Policy
+			for _, rules := range tc.policies {
+				policies = append(policies, parse(t, rules))
+			}
+
+			rule, err := ExplainRule(policies, nil, tc.resource, tc.segment)
+			require.NoError(t, err)
+			if tc.expect == "" {
+				require.Nil(t, rule)
+				return
+			}
+			require.NotNil(t, rule)
+			require.Equal(t, tc.expect, rule.String())
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/serf/serf"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/acl/resolver"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/types"
@@ -39,7 +40,7 @@ func (a *Agent) vetServiceRegister(token string, service *structs.NodeService) e
 	return a.vetServiceRegisterWithAuthorizer(authz, service)
 }
 
-func (a *Agent) vetServiceRegisterWithAuthorizer(authz acl.Authorizer, service *structs.NodeService) error {
+func (a *Agent) vetServiceRegisterWithAuthorizer(authz resolver.Result, service *structs.NodeService) error {
 	var authzContext acl.AuthorizerContext
 
 	// Vet the service itself.
@@ -65,7 +66,10 @@ func (a *Agent) vetServiceRegisterWithAuthorizer(authz acl.Authorizer, service *
 		}
 	}
 
-	return nil
+	// The servers reject services that the service identities of the token
+	// restrict to other nodes, so reject them here rather than failing every
+	// anti-entropy sync.
+	return a.delegate.VetServiceNode(authz, service.Service, a.config.NodeName)
 }
 
 func (a *Agent) vetServiceUpdateWithAuthorizer(authz acl.Authorizer, serviceID structs.ServiceID) error {
@@ -2,8 +2,10 @@ package agent
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
@@ -297,6 +299,38 @@ func (s *HTTPHandlers) ACLTokenList(resp http.ResponseWriter, req *http.Request)
 	return out.Tokens, nil
 }
 
+func (s *HTTPHandlers) ACLTokenBulk(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTokenBulkRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Operations)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Operations decoding failed: %v", err)}
+	}
+	if len(args.Operations) == 0 {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Must provide at least one operation"}
+	}
+
+	for i := range args.Operations {
+		if err := s.parseEntMeta(req, &args.Operations[i].Token.EnterpriseMeta); err != nil {
+			return nil, err
+		}
+	}
+
+	var out structs.ACLTokenBulkResponse
+	if err := s.agent.RPC(req.Context(), "ACL.TokenBulk", &args, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Results, nil
+}
+
 func (s *HTTPHandlers) ACLTokenCRUD(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -323,6 +357,10 @@ func (s *HTTPHandlers) ACLTokenCRUD(resp http.ResponseWriter, req *http.Request)
 		tokenAccessorID = tokenAccessorID[:len(tokenAccessorID)-6]
 		fn = s.ACLTokenClone
 	}
+	if strings.HasSuffix(tokenAccessorID, "/rotate") && req.Method == "PUT" {
+		tokenAccessorID = tokenAccessorID[:len(tokenAccessorID)-7]
+		fn = s.ACLTokenRotate
+	}
 	if tokenAccessorID == "" && req.Method != "PUT" {
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing token AccessorID"}
 	}
@@ -500,6 +538,47 @@ func (s *HTTPHandlers) ACLTokenClone(resp http.ResponseWriter, req *http.Request
 	return &out, nil
 }
 
+func (s *HTTPHandlers) ACLTokenRotate(resp http.ResponseWriter, req *http.Request, tokenAccessorID string) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTokenRotateRequest{
+		Datacenter: s.agent.config.Datacenter,
+		AccessorID: tokenAccessorID,
+	}
+
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	// The body is optional, the overlap window defaults to an hour.
+	var body struct {
+		OverlapWindow string
+	}
+	if err := decodeBody(req.Body, &body); err != nil && err != io.EOF {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+	if body.OverlapWindow != "" {
+		overlap, err := time.ParseDuration(body.OverlapWindow)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid OverlapWindow: %v", err)}
+		}
+		args.OverlapWindow = overlap
+	}
+	s.parseToken(req, &args.Token)
+
+	var out structs.ACLToken
+	if err := s.agent.RPC(req.Context(), "ACL.TokenRotate", args, &out); err != nil {
+		if strings.Contains(err.Error(), acl.ErrNotFound.Error()) {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: err.Error()}
+		}
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLRoleList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1002,6 +1081,43 @@ func (s *HTTPHandlers) ACLLogin(resp http.ResponseWriter, req *http.Request) (in
 	return &out, nil
 }
 
+// ACLExplain reports why a token is, or isn't, authorized to access a
+// resource: whether a rule of its policies or the default policy decided, and
+// which rule of which policies it was.
+func (s *HTTPHandlers) ACLExplain(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	var body struct {
+		AccessorID string
+		structs.ACLAuthorizationRequest
+	}
+	if err := decodeBody(req.Body, &body); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+	if body.Resource == "" || body.Access == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Resource and Access are required"}
+	}
+
+	args := structs.ACLExplainRequest{
+		Datacenter:              s.agent.config.Datacenter,
+		AccessorID:              body.AccessorID,
+		ACLAuthorizationRequest: body.ACLAuthorizationRequest,
+	}
+	s.parseToken(req, &args.Token)
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	var out structs.ACLExplainResponse
+	if err := s.agent.RPC(req.Context(), "ACL.Explain", &args, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLLogout(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1025,6 +1141,58 @@ func (s *HTTPHandlers) ACLLogout(resp http.ResponseWriter, req *http.Request) (i
 	return true, nil
 }
 
+func (s *HTTPHandlers) ACLOIDCAuthURL(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCAuthURLRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCAuthURLParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLOIDCAuthURLResponse
+	if err := s.agent.RPC(req.Context(), "ACL.OIDCAuthURL", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLOIDCCallback(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCCallbackRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCCallbackParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLToken
+	if err := s.agent.RPC(req.Context(), "ACL.OIDCCallback", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 // A hack to fix up the config types inside of the map[string]interface{}
 // so that they get formatted correctly during json.Marshal. Without this,
 // string values that get converted to []uint8 end up getting output back
@@ -1038,6 +1206,66 @@ func fixupAuthMethodConfig(method *structs.ACLAuthMethod) {
 	}
 }
 
+// ACLTemplatedPoliciesList returns the built-in policy templates, keyed by
+// name. The templates are static, so they are served by the agent.
+func (s *HTTPHandlers) ACLTemplatedPoliciesList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	entMeta, err := s.authorizeTemplatedPolicyRead(req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*structs.ACLTemplatedPolicyResponse)
+	for _, base := range structs.GetACLTemplatedPolicyList() {
+		out[base.TemplateName] = base.Response(entMeta)
+	}
+	return out, nil
+}
+
+// ACLTemplatedPolicyReadByName returns a built-in policy template.
+func (s *HTTPHandlers) ACLTemplatedPolicyReadByName(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	templateName := strings.TrimPrefix(req.URL.Path, "/v1/acl/templated-policy/name/")
+	if templateName == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing templated policy Name"}
+	}
+
+	entMeta, err := s.authorizeTemplatedPolicyRead(req)
+	if err != nil {
+		return nil, err
+	}
+
+	base, ok := structs.GetACLTemplatedPolicyBase(templateName)
+	if !ok {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: fmt.Sprintf("Requested templated policy %q does not exist", templateName)}
+	}
+	return base.Response(entMeta), nil
+}
+
+// authorizeTemplatedPolicyRead requires acl:read to read the templated
+// policies, like the policies they are rendered into.
+func (s *HTTPHandlers) authorizeTemplatedPolicyRead(req *http.Request) (*acl.EnterpriseMeta, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	var entMeta acl.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	var token string
+	s.parseToken(req, &token)
+
+	var authzContext acl.AuthorizerContext
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &entMeta, &authzContext)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+		return nil, err
+	}
+	return &entMeta, nil
+}
+
 func (s *HTTPHandlers) ACLAuthorize(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// At first glance it may appear like this endpoint is going to leak security relevant information.
 	// There are a number of reason why this is okay.
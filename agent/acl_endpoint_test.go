@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -1283,6 +1284,86 @@ func TestACL_HTTP(t *testing.T) {
 			require.Error(t, err)
 			testutil.RequireErrorContains(t, err, "Only lowercase alphanumeric")
 		})
+
+		t.Run("Bulk", func(t *testing.T) {
+			ops := []structs.ACLTokenBulkOperation{
+				{
+					Op:    structs.ACLTokenBulkCreate,
+					Token: structs.ACLToken{Description: "bulk-1"},
+				},
+				{
+					Op:    structs.ACLTokenBulkCreate,
+					Token: structs.ACLToken{Description: "bulk-2"},
+				},
+				{
+					Op: structs.ACLTokenBulkDetachPolicies,
+					Token: structs.ACLToken{
+						AccessorID: idMap["token-test"],
+						Policies:   []structs.ACLTokenPolicyLink{{ID: idMap["policy-read-all-nodes"]}},
+					},
+				},
+				{
+					Op:    structs.ACLTokenBulkDelete,
+					Token: structs.ACLToken{AccessorID: "9d2ea9bb-1d7c-4e4b-8f4a-0d2f3c9e5d1a"},
+				},
+			}
+
+			req, _ := http.NewRequest("PUT", "/v1/acl/tokens/bulk", jsonBody(ops))
+			req.Header.Add("X-Consul-Token", "root")
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLTokenBulk(resp, req)
+			require.NoError(t, err)
+			results, ok := raw.([]structs.ACLTokenBulkResult)
+			require.True(t, ok)
+			require.Len(t, results, 4)
+
+			require.Empty(t, results[0].Error)
+			require.Equal(t, "bulk-1", results[0].Token.Description)
+			require.Empty(t, results[1].Error)
+			require.Equal(t, "bulk-2", results[1].Token.Description)
+			require.Empty(t, results[2].Error)
+			require.Len(t, results[2].Token.Policies, 1)
+			require.Equal(t, idMap["policy-test"], results[2].Token.Policies[0].ID)
+			require.Contains(t, results[3].Error, "Cannot find token")
+
+			// Both tokens were created in the same Raft log entry.
+			require.Equal(t, results[0].Token.CreateIndex, results[1].Token.CreateIndex)
+
+			// Deleting the tokens found with a filter.
+			req, _ = http.NewRequest("GET", "/v1/acl/tokens?filter="+url.QueryEscape(`Description matches "^bulk-"`), nil)
+			req.Header.Add("X-Consul-Token", "root")
+			resp = httptest.NewRecorder()
+			raw, err = a.srv.ACLTokenList(resp, req)
+			require.NoError(t, err)
+			tokens, ok := raw.(structs.ACLTokenListStubs)
+			require.True(t, ok)
+			require.Len(t, tokens, 2)
+
+			ops = nil
+			for _, token := range tokens {
+				ops = append(ops, structs.ACLTokenBulkOperation{
+					Op:    structs.ACLTokenBulkDelete,
+					Token: structs.ACLToken{AccessorID: token.AccessorID},
+				})
+			}
+			req, _ = http.NewRequest("PUT", "/v1/acl/tokens/bulk", jsonBody(ops))
+			req.Header.Add("X-Consul-Token", "root")
+			resp = httptest.NewRecorder()
+			raw, err = a.srv.ACLTokenBulk(resp, req)
+			require.NoError(t, err)
+			for _, result := range raw.([]structs.ACLTokenBulkResult) {
+				require.Empty(t, result.Error)
+			}
+		})
+
+		t.Run("Bulk without operations", func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/v1/acl/tokens/bulk", jsonBody([]structs.ACLTokenBulkOperation{}))
+			req.Header.Add("X-Consul-Token", "root")
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLTokenBulk(resp, req)
+			require.Error(t, err)
+			require.True(t, isHTTPBadRequest(err))
+		})
 	})
 }
 
@@ -127,6 +127,9 @@ func (a *TestACLAgent) ResolveTokenAndDefaultMeta(secretID string, entMeta *acl.
 func (a *TestACLAgent) GetLANCoordinate() (lib.CoordinateSet, error) {
 	return nil, fmt.Errorf("Unimplemented")
 }
+func (a *TestACLAgent) GossipHealth() consul.GossipHealth {
+	return consul.GossipHealth{}
+}
 func (a *TestACLAgent) Leave() error {
 	return fmt.Errorf("Unimplemented")
 }
@@ -145,6 +148,9 @@ func (a *TestACLAgent) JoinLAN(addrs []string, entMeta *acl.EnterpriseMeta) (n i
 func (a *TestACLAgent) RemoveFailedNode(node string, prune bool, entMeta *acl.EnterpriseMeta) error {
 	return fmt.Errorf("Unimplemented")
 }
+func (a *TestACLAgent) VetServiceNode(authz resolver.Result, service, node string) error {
+	return nil
+}
 func (a *TestACLAgent) RPC(ctx context.Context, method string, args interface{}, reply interface{}) error {
 	return fmt.Errorf("Unimplemented")
 }
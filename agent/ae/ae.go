@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/logging"
@@ -77,6 +78,12 @@ type StateSyncer struct {
 	// in a non-blocking way.
 	SyncChanges *Trigger
 
+	// SyncChangesLimiter limits the rate of partial sync runs. Triggers
+	// received while waiting are coalesced into the next run. A nil
+	// limiter disables rate limiting. This needs to be set before Run()
+	// is called.
+	SyncChangesLimiter *rate.Limiter
+
 	// paused stores whether sync runs are temporarily disabled.
 	pauseLock sync.Mutex
 	paused    int
@@ -204,6 +211,10 @@ func (s *StateSyncer) nextFSMState(fs fsmState) fsmState {
 				return partialSyncState
 			}
 
+			if !s.waitSyncChangesLimit() {
+				return doneState
+			}
+
 			err := s.State.SyncChanges()
 			if err != nil {
 				s.Logger.Error("failed to sync changes", "error", err)
@@ -293,6 +304,29 @@ func (s *StateSyncer) syncChangesEventFn() event {
 	}
 }
 
+// waitSyncChangesLimit blocks until the rate limit allows a partial sync
+// run. It returns false if the application is shutting down.
+func (s *StateSyncer) waitSyncChangesLimit() bool {
+	if s.SyncChangesLimiter == nil {
+		return true
+	}
+
+	r := s.SyncChangesLimiter.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		return true
+	}
+
+	s.Logger.Trace("delaying partial sync due to rate limit", "delay", delay)
+	select {
+	case <-time.After(delay):
+		return true
+	case <-s.ShutdownCh:
+		r.Cancel()
+		return false
+	}
+}
+
 // resetNextFullSyncCh resets nextFullSyncCh and sets it to interval+stagger.
 // Call this function everytime a full sync is performed.
 func (s *StateSyncer) resetNextFullSyncCh() {
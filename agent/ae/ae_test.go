@@ -10,6 +10,8 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -252,6 +254,22 @@ func TestAE_FSM(t *testing.T) {
 				t.Fatalf("got state %v want %v", got, want)
 			}
 		})
+		t.Run("syncChangesEvent+rate limited+shutdown -> doneState", func(t *testing.T) {
+			l := testSyncer(t)
+			m := &mock{}
+			l.State = m
+			l.ShutdownCh = make(chan struct{})
+			l.SyncChangesLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+			l.syncChangesEvent = func() event { return syncChangesNotifEvent }
+
+			// The first run uses the burst and the second one has to wait.
+			fs := l.nextFSMState(partialSyncState)
+			require.Equal(t, partialSyncState, fs)
+			close(l.ShutdownCh)
+			fs = l.nextFSMState(partialSyncState)
+			require.Equal(t, doneState, fs)
+			require.Equal(t, []string{"changes"}, m.seq)
+		})
 		t.Run("invalid event -> panic ", func(t *testing.T) {
 			defer func() {
 				err := recover()
@@ -274,6 +292,26 @@ func TestAE_FSM(t *testing.T) {
 	})
 }
 
+func TestAE_SyncChangesLimiter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	l := testSyncer(t)
+	m := &mock{}
+	l.State = m
+	l.SyncChangesLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+	l.syncChangesEvent = func() event { return syncChangesNotifEvent }
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.Equal(t, partialSyncState, l.nextFSMState(partialSyncState))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	require.Equal(t, []string{"changes", "changes", "changes"}, m.seq)
+}
+
 func TestAE_RetrySyncFullEvent(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
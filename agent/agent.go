@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -28,11 +29,13 @@ import (
 	"github.com/hashicorp/serf/serf"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/acl/resolver"
 	"github.com/hashicorp/consul/agent/ae"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	cachetype "github.com/hashicorp/consul/agent/cache-types"
 	"github.com/hashicorp/consul/agent/checks"
@@ -40,9 +43,12 @@ import (
 	"github.com/hashicorp/consul/agent/consul"
 	rpcRate "github.com/hashicorp/consul/agent/consul/rate"
 	"github.com/hashicorp/consul/agent/consul/servercert"
+	"github.com/hashicorp/consul/agent/debug"
 	"github.com/hashicorp/consul/agent/dns"
 	external "github.com/hashicorp/consul/agent/grpc-external"
 	grpcDNS "github.com/hashicorp/consul/agent/grpc-external/services/dns"
+	grpcServiceLease "github.com/hashicorp/consul/agent/grpc-external/services/servicelease"
+	grpcSession "github.com/hashicorp/consul/agent/grpc-external/services/session"
 	middleware "github.com/hashicorp/consul/agent/grpc-middleware"
 	"github.com/hashicorp/consul/agent/hcp/scada"
 	libscada "github.com/hashicorp/consul/agent/hcp/scada"
@@ -51,6 +57,8 @@ import (
 	proxycfgglue "github.com/hashicorp/consul/agent/proxycfg-glue"
 	catalogproxycfg "github.com/hashicorp/consul/agent/proxycfg-sources/catalog"
 	localproxycfg "github.com/hashicorp/consul/agent/proxycfg-sources/local"
+	rpcmiddleware "github.com/hashicorp/consul/agent/rpc/middleware"
+	"github.com/hashicorp/consul/agent/rpcclient/catalog"
 	"github.com/hashicorp/consul/agent/rpcclient/health"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/systemd"
@@ -58,6 +66,7 @@ import (
 	"github.com/hashicorp/consul/agent/xds"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/api/watch"
+	connectmetrics "github.com/hashicorp/consul/connect/metrics"
 	"github.com/hashicorp/consul/ipaddr"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/lib/file"
@@ -66,6 +75,7 @@ import (
 	"github.com/hashicorp/consul/logging"
 	"github.com/hashicorp/consul/proto/pboperator"
 	"github.com/hashicorp/consul/proto/pbpeering"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
 	"github.com/hashicorp/consul/tlsutil"
 	"github.com/hashicorp/consul/types"
 )
@@ -88,6 +98,13 @@ const (
 	defaultServiceMaintReason = "Maintenance mode is enabled for this " +
 		"service, but no reason was provided. This is a default message."
 
+	// Default reason for service draining
+	defaultServiceDrainingReason = "Draining is enabled for this service, " +
+		"but no reason was provided. This is a default message."
+
+	// Reason for the draining of the services of a leaving agent
+	leaveServiceDrainingReason = "The agent is leaving, this service will be deregistered"
+
 	// ID of the roots watch
 	rootsWatchID = "roots"
 
@@ -175,6 +192,10 @@ type delegate interface {
 	// NOTE: This assumes coordinates are enabled, so check that before calling.
 	GetLANCoordinate() (lib.CoordinateSet, error)
 
+	// GossipHealth returns the health of the agent's canonical LAN gossip
+	// pool as seen from the agent.
+	GossipHealth() consul.GossipHealth
+
 	// JoinLAN is used to have Consul join the inner-DC pool The target address
 	// should be another node inside the DC listening on the Serf LAN address
 	JoinLAN(addrs []string, entMeta *acl.EnterpriseMeta) (n int, err error)
@@ -188,6 +209,11 @@ type delegate interface {
 	// default partition and namespace from the token.
 	ResolveTokenAndDefaultMeta(token string, entMeta *acl.EnterpriseMeta, authzContext *acl.AuthorizerContext) (resolver.Result, error)
 
+	// VetServiceNode returns a permission denied error if the service
+	// identities of the token restrict the registration of the service to
+	// other nodes.
+	VetServiceNode(authz resolver.Result, service, node string) error
+
 	RPC(ctx context.Context, method string, args interface{}, reply interface{}) error
 
 	SnapshotRPC(args *structs.SnapshotRequest, in io.Reader, out io.Writer, replyFn structs.SnapshotReplyFn) error
@@ -273,12 +299,25 @@ type Agent struct {
 	// checkAliases maps the check ID to an associated Alias checks
 	checkAliases map[structs.CheckID]*checks.CheckAlias
 
+	// checkComposites maps the check ID to an associated Composite check
+	checkComposites map[structs.CheckID]*checks.CheckComposite
+
 	// checkOSServices maps the check ID to an associated OS Service check
 	checkOSServices map[structs.CheckID]*checks.CheckOSService
 
+	// checkProcesses maps the check ID to an associated Process check
+	checkProcesses map[structs.CheckID]*checks.CheckProcess
+
 	// exposedPorts tracks listener ports for checks exposed through a proxy
 	exposedPorts map[string]int
 
+	// drainingTimers maps the ID of a draining service to the timer lifting
+	// its draining state, if it was enabled with a timeout.
+	drainingTimers map[structs.ServiceID]*time.Timer
+
+	// drainingLock protects drainingTimers
+	drainingLock sync.Mutex
+
 	// stateLock protects the agent state
 	stateLock *mutex.Mutex
 
@@ -304,8 +343,12 @@ type Agent struct {
 	shutdownCh   chan struct{}
 	shutdownLock sync.Mutex
 
-	// joinLANNotifier is called after a successful JoinLAN.
-	joinLANNotifier notifier
+	// readyNotifier is notified once the agent is ready, see notifyReady.
+	readyNotifier notifier
+
+	// activatedSockets are the sockets passed by systemd with socket
+	// activation, which are used instead of the DNS and HTTP addresses.
+	activatedSockets *systemd.Sockets
 
 	// retryJoinCh transports errors from the retry join
 	// attempts.
@@ -354,6 +397,14 @@ type Agent struct {
 	// the configuration directly.
 	tokens *token.Store
 
+	// tokenUsage tracks the tokens presented to the HTTP API until they are
+	// reported to the servers.
+	tokenUsage *tokenUsageTracker
+
+	// auditor records the operations performed through the HTTP and gRPC
+	// APIs when the audit log is enabled, it is nil otherwise.
+	auditor *audit.Logger
+
 	// proxyConfig is the manager for proxy service (Kind = connect-proxy)
 	// configuration state. This ensures all state needed by a proxy registration
 	// is maintained in cache and handles pushing updates to that state into XDS
@@ -375,6 +426,11 @@ type Agent struct {
 	// IP.
 	httpConnLimiter connlimit.Limiter
 
+	// httpLimits holds the size limits of the KV and transaction endpoints,
+	// which are reloadable. It is empty until the agent starts, in which case
+	// the limits of config apply.
+	httpLimits atomic.Value
+
 	// configReloaders are subcomponents that need to be notified on a reload so
 	// they can update their internal state.
 	configReloaders []ConfigReloader
@@ -383,18 +439,34 @@ type Agent struct {
 	// into Agent, which will allow us to remove this field.
 	rpcClientHealth *health.Client
 
+	rpcClientCatalog *catalog.Client
+
 	rpcClientPeering pbpeering.PeeringServiceClient
 
+	rpcClientSubscribe pbsubscribe.StateChangeSubscriptionClient
+
 	rpcClientOperator pboperator.OperatorServiceClient
 
 	// routineManager is responsible for managing longer running go routines
 	// run by the Agent
 	routineManager *routine.Manager
 
+	// meshMetrics aggregates the golden signals of the local proxies served
+	// by the /v1/agent/metrics/mesh endpoint.
+	meshMetrics *connectmetrics.MeshAggregator
+
 	// configFileWatcher is the watcher responsible to report events when a config file
 	// changed
 	configFileWatcher config.Watcher
 
+	// serviceDefinitions registers the services of the files of
+	// services_auto_reload.dir, if it is configured.
+	serviceDefinitions *serviceDefinitions
+
+	// serviceLeases deregisters the services registered with a lease once
+	// their lease expires.
+	serviceLeases *serviceLeases
+
 	// xdsServer serves the XDS protocol for configuring Envoy proxies.
 	xdsServer *xds.Server
 
@@ -433,10 +505,13 @@ func New(bd BaseDeps) (*Agent, error) {
 		checkGRPCs:      make(map[structs.CheckID]*checks.CheckGRPC),
 		checkDockers:    make(map[structs.CheckID]*checks.CheckDocker),
 		checkAliases:    make(map[structs.CheckID]*checks.CheckAlias),
+		checkComposites: make(map[structs.CheckID]*checks.CheckComposite),
 		checkOSServices: make(map[structs.CheckID]*checks.CheckOSService),
+		checkProcesses:  make(map[structs.CheckID]*checks.CheckProcess),
+		drainingTimers:  make(map[structs.ServiceID]*time.Timer),
 		eventCh:         make(chan serf.UserEvent, 1024),
 		eventBuf:        make([]*UserEvent, 256),
-		joinLANNotifier: &systemd.Notifier{},
+		readyNotifier:   &systemd.Notifier{},
 		retryJoinCh:     make(chan error),
 		shutdownCh:      make(chan struct{}),
 		endpoints:       make(map[string]string),
@@ -449,7 +524,9 @@ func New(bd BaseDeps) (*Agent, error) {
 		config:          bd.RuntimeConfig,
 		cache:           bd.Cache,
 		routineManager:  routine.NewManager(bd.Logger),
+		meshMetrics:     connectmetrics.NewMeshAggregator(bd.Logger.Named(logging.Connect)),
 		scadaProvider:   bd.HCP.Provider,
+		tokenUsage:      newTokenUsageTracker(),
 	}
 
 	// TODO: create rpcClientHealth in BaseDeps once NetRPC is available without Agent
@@ -471,8 +548,22 @@ func New(bd BaseDeps) (*Agent, error) {
 		QueryOptionDefaults: config.ApplyDefaultQueryOptions(a.config),
 	}
 
+	a.rpcClientCatalog = &catalog.Client{
+		Cache:     bd.Cache,
+		NetRPC:    &a,
+		CacheName: cachetype.CatalogListServicesName,
+		ViewStore: bd.ViewStore,
+		MaterializerDeps: catalog.MaterializerDeps{
+			Conn:   conn,
+			Logger: bd.Logger.Named("rpcclient.catalog"),
+		},
+		UseStreamingBackend: a.config.UseStreamingBackend && a.config.UseStreamingBackendCatalogServices,
+		QueryOptionDefaults: config.ApplyDefaultQueryOptions(a.config),
+	}
+
 	a.rpcClientPeering = pbpeering.NewPeeringServiceClient(conn)
 	a.rpcClientOperator = pboperator.NewOperatorServiceClient(conn)
+	a.rpcClientSubscribe = pbsubscribe.NewStateChangeSubscriptionClient(conn)
 
 	a.serviceManager = NewServiceManager(&a)
 
@@ -510,6 +601,16 @@ func New(bd BaseDeps) (*Agent, error) {
 		a.configFileWatcher = w
 	}
 
+	if dir := a.baseDeps.RuntimeConfig.ServicesAutoReloadDir; dir != "" {
+		sd, err := newServiceDefinitions(&a, dir, a.baseDeps.RuntimeConfig.ServicesAutoReloadDebounce, a.baseDeps.Logger)
+		if err != nil {
+			return nil, err
+		}
+		a.serviceDefinitions = sd
+	}
+
+	a.serviceLeases = newServiceLeases(a.expireServiceLease)
+
 	return &a, nil
 }
 
@@ -534,6 +635,7 @@ func LocalConfig(cfg *config.RuntimeConfig) local.Config {
 		NodeName:            cfg.NodeName,
 		Partition:           cfg.PartitionOrDefault(),
 		TaggedAddresses:     map[string]string{},
+		SyncBatchSize:       cfg.AESyncBatchSize,
 	}
 	for k, v := range cfg.TaggedAddresses {
 		lc.TaggedAddresses[k] = v
@@ -559,6 +661,7 @@ func (a *Agent) Start(ctx context.Context) error {
 	// the configuration
 	c.NodeID = a.config.NodeID
 	a.config = c
+	a.setHTTPLimits(c)
 
 	if err := a.tlsConfigurator.Update(a.config.TLS); err != nil {
 		return fmt.Errorf("Failed to load TLS configurations after applying auto-config settings: %w", err)
@@ -574,6 +677,9 @@ func (a *Agent) Start(ctx context.Context) error {
 	// create the state synchronization manager which performs
 	// regular and on-demand state synchronizations (anti-entropy).
 	a.sync = ae.NewStateSyncer(a.State, c.AEInterval, a.shutdownCh, a.logger)
+	if c.AESyncRateLimit > 0 {
+		a.sync.SyncChangesLimiter = rate.NewLimiter(rate.Limit(c.AESyncRateLimit), 1)
+	}
 
 	// create the config for the rpc server/client
 	consulCfg, err := newConsulConfig(a.config, a.logger)
@@ -599,6 +705,13 @@ func (a *Agent) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start Consul enterprise component: %v", err)
 	}
 
+	if c.Audit.Enabled {
+		a.auditor, err = audit.New(c.Audit, a.logger.Named(logging.Audit))
+		if err != nil {
+			return fmt.Errorf("Failed to start the audit log: %v", err)
+		}
+	}
+
 	// Setup either the client or the server.
 	if c.ServerMode {
 		serverLogger := a.baseDeps.Logger.NamedIntercept(logging.ConsulServer)
@@ -614,6 +727,9 @@ func (a *Agent) Start(ctx context.Context) error {
 			metrics.Default(),
 			a.tlsConfigurator,
 			incomingRPCLimiter,
+			a.clientCertLoginInterceptor(),
+			a.tokenUsageInterceptor(),
+			a.auditInterceptor(),
 		)
 
 		server, err := consul.NewServer(consulCfg, a.baseDeps.Deps, a.externalGRPCServer, incomingRPCLimiter, serverLogger)
@@ -646,6 +762,9 @@ func (a *Agent) Start(ctx context.Context) error {
 			metrics.Default(),
 			a.tlsConfigurator,
 			rpcRate.NullRequestLimitsHandler(),
+			nil,
+			a.tokenUsageInterceptor(),
+			a.auditInterceptor(),
 		)
 
 		client, err := consul.NewClient(consulCfg, a.baseDeps.Deps)
@@ -741,11 +860,23 @@ func (a *Agent) Start(ctx context.Context) error {
 		go a.sendCoordinate()
 	}
 
+	// Start reporting the usage of the tokens presented to the HTTP API.
+	if c.ACLsEnabled {
+		go a.reportTokenUsage()
+	}
+
 	// Write out the PID file if necessary.
 	if err := a.storePid(); err != nil {
 		return err
 	}
 
+	// The sockets passed by systemd are used instead of the addresses of
+	// the DNS and HTTP servers.
+	a.activatedSockets, err = systemd.Activated()
+	if err != nil {
+		return err
+	}
+
 	// start DNS servers
 	if err := a.listenAndServeDNS(); err != nil {
 		return err
@@ -763,7 +894,16 @@ func (a *Agent) Start(ctx context.Context) error {
 		return err
 	}
 
-	// Start HTTP and HTTPS servers.
+	// Create listeners and unstarted servers for DNS-over-HTTPS.
+	dohServers, err := a.listenDNSOverHTTPS()
+	if err != nil {
+		return err
+	}
+	servers = append(servers, dohServers...)
+
+	a.closeUnusedSockets()
+
+	// Start HTTP, HTTPS and DNS-over-HTTPS servers.
 	for _, srv := range servers {
 		a.apiServers.Start(srv)
 	}
@@ -787,11 +927,24 @@ func (a *Agent) Start(ctx context.Context) error {
 		go a.retryJoinWAN()
 	}
 
+	// notify systemd once the agent is ready
+	go a.notifyReady(a.readyNotifier)
+
 	if a.tlsConfigurator.Cert() != nil {
 		m := tlsCertExpirationMonitor(a.tlsConfigurator, a.logger)
 		go m.Monitor(&lib.StopChannelContext{StopCh: a.shutdownCh})
 	}
 
+	// start the continuous profiler
+	if a.config.Profiling.Enabled {
+		profiling := a.config.Profiling
+		if profiling.Dir == "" {
+			profiling.Dir = filepath.Join(a.config.DataDir, "profiles")
+		}
+		profiler := debug.NewProfiler(profiling, a.logger.Named(logging.Profiler))
+		go profiler.Run(&lib.StopChannelContext{StopCh: a.shutdownCh})
+	}
+
 	// consul version metric with labels
 	metrics.SetGaugeWithLabels([]string{"version"}, 1, []metrics.Label{
 		{Name: "version", Value: a.config.VersionWithMetadata()},
@@ -813,6 +966,10 @@ func (a *Agent) Start(ctx context.Context) error {
 		}()
 	}
 
+	if a.serviceDefinitions != nil {
+		a.serviceDefinitions.Start(&lib.StopChannelContext{StopCh: a.shutdownCh})
+	}
+
 	if a.scadaProvider != nil {
 		a.scadaProvider.UpdateMeta(map[string]string{
 			"consul_server_id": string(a.config.NodeID),
@@ -872,8 +1029,20 @@ func (a *Agent) listenAndServeGRPC() error {
 		},
 		a,
 	)
+	a.xdsServer.StrictEnvoyVersion = a.config.XDSStrictEnvoyVersion
 	a.xdsServer.Register(a.externalGRPCServer)
 
+	grpcSession.NewServer(grpcSession.Config{
+		Backend:    a,
+		Logger:     a.logger.Named("grpc-api.session"),
+		Datacenter: a.config.Datacenter,
+	}).Register(a.externalGRPCServer)
+
+	grpcServiceLease.NewServer(grpcServiceLease.Config{
+		Backend: a,
+		Logger:  a.logger.Named("grpc-api.service-lease"),
+	}).Register(a.externalGRPCServer)
+
 	// Attempt to spawn listeners
 	var listeners []net.Listener
 	start := func(port_name string, addrs []net.Addr, protocol middleware.Protocol) error {
@@ -924,9 +1093,49 @@ func (a *Agent) listenAndServeGRPC() error {
 }
 
 func (a *Agent) listenAndServeDNS() error {
-	notif := make(chan net.Addr, len(a.config.DNSAddrs))
-	errCh := make(chan error, len(a.config.DNSAddrs))
-	for _, addr := range a.config.DNSAddrs {
+	// the sockets passed by systemd replace the DNS addresses
+	dnsAddrs := a.config.DNSAddrs
+	dnsListeners, dnsConns := a.activatedSockets.Take("dns")
+	if len(dnsListeners) > 0 || len(dnsConns) > 0 {
+		dnsAddrs = nil
+	}
+
+	numAddrs := len(dnsAddrs) + len(dnsListeners) + len(dnsConns) + len(a.config.DNSTLSAddrs)
+	notif := make(chan net.Addr, numAddrs)
+	errCh := make(chan error, numAddrs)
+	for _, l := range dnsListeners {
+		s, err := NewDNSServer(a)
+		if err != nil {
+			return err
+		}
+		a.dnsServers = append(a.dnsServers, s)
+
+		a.wgServers.Add(1)
+		go func(l net.Listener) {
+			defer a.wgServers.Done()
+			err := s.Serve(l, nil, func() { notif <- l.Addr() })
+			if err != nil && !strings.Contains(err.Error(), "accept") {
+				errCh <- err
+			}
+		}(l)
+	}
+	for _, pc := range dnsConns {
+		s, err := NewDNSServer(a)
+		if err != nil {
+			return err
+		}
+		a.dnsServers = append(a.dnsServers, s)
+
+		a.wgServers.Add(1)
+		go func(pc net.PacketConn) {
+			defer a.wgServers.Done()
+			err := s.Serve(nil, pc, func() { notif <- pc.LocalAddr() })
+			if err != nil && !strings.Contains(err.Error(), "accept") {
+				errCh <- err
+			}
+		}(pc)
+	}
+	for _, addr := range dnsAddrs {
 		// create server
 		s, err := NewDNSServer(a)
 		if err != nil {
@@ -944,6 +1153,24 @@ func (a *Agent) listenAndServeDNS() error {
 			}
 		}(addr)
 	}
+	for _, addr := range a.config.DNSTLSAddrs {
+		// create DNS-over-TLS server
+		s, err := NewDNSServer(a)
+		if err != nil {
+			return err
+		}
+		a.dnsServers = append(a.dnsServers, s)
+
+		// start server
+		a.wgServers.Add(1)
+		go func(addr net.Addr) {
+			defer a.wgServers.Done()
+			err := s.ListenAndServeTLS(addr.String(), a.tlsConfigurator.IncomingDNSConfig(), func() { notif <- addr })
+			if err != nil && !strings.Contains(err.Error(), "accept") {
+				errCh <- err
+			}
+		}(addr)
+	}
 	s, _ := NewDNSServer(a)
 
 	grpcDNS.NewServer(grpcDNS.Config{
@@ -957,7 +1184,7 @@ func (a *Agent) listenAndServeDNS() error {
 	// wait for servers to be up
 	timeout := time.After(time.Second)
 	var merr *multierror.Error
-	for range a.config.DNSAddrs {
+	for i := 0; i < numAddrs; i++ {
 		select {
 		case addr := <-notif:
 			a.logger.Info("Started DNS server",
@@ -999,6 +1226,13 @@ func (a *Agent) startListeners(addrs []net.Addr) ([]net.Listener, error) {
 				return nil, err
 			}
 
+		case *config.NamedPipeAddr:
+			l, err = a.listenNamedPipe(x.Path)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+
 		case *net.TCPAddr:
 			l, err = net.Listen("tcp", x.String())
 			if err != nil {
@@ -1041,11 +1275,12 @@ func (a *Agent) listenHTTP() ([]apiServer, error) {
 	var ln []net.Listener
 	var servers []apiServer
 
-	start := func(proto string, addrs []net.Addr) error {
+	start := func(proto string, addrs []net.Addr, activated []net.Listener) error {
 		listeners, err := a.startListeners(addrs)
 		if err != nil {
 			return err
 		}
+		listeners = append(listeners, activated...)
 		ln = append(ln, listeners...)
 
 		for _, l := range listeners {
@@ -1091,22 +1326,107 @@ func (a *Agent) listenHTTP() ([]apiServer, error) {
 		return nil
 	}
 
-	httpAddrs := a.config.HTTPAddrs
+	// the sockets passed by systemd replace the HTTP and HTTPS addresses
+	httpAddrs, httpListeners := a.config.HTTPAddrs, a.activatedListeners("http")
+	if len(httpListeners) > 0 {
+		httpAddrs = nil
+	}
+	httpsAddrs, httpsListeners := a.config.HTTPSAddrs, a.activatedListeners("https")
+	if len(httpsListeners) > 0 {
+		httpsAddrs = nil
+	}
+
 	if a.config.IsCloudEnabled() {
 		httpAddrs = append(httpAddrs, scada.CAPCoreAPI)
 	}
 
-	if err := start("http", httpAddrs); err != nil {
+	if err := start("http", httpAddrs, httpListeners); err != nil {
 		closeListeners(ln)
 		return nil, err
 	}
-	if err := start("https", a.config.HTTPSAddrs); err != nil {
+	if err := start("https", httpsAddrs, httpsListeners); err != nil {
 		closeListeners(ln)
 		return nil, err
 	}
 	return servers, nil
 }
 
+// activatedListeners returns the stream sockets passed by systemd with a
+// name, see systemd.Activated.
+func (a *Agent) activatedListeners(name string) []net.Listener {
+	listeners, conns := a.activatedSockets.Take(name)
+	for _, pc := range conns {
+		a.logger.Warn("Ignoring datagram socket passed by systemd", "name", name, "address", pc.LocalAddr().String())
+		pc.Close()
+	}
+	for i, l := range listeners {
+		if tl, ok := l.(*net.TCPListener); ok {
+			listeners[i] = &tcpKeepAliveListener{tl}
+		}
+	}
+	return listeners
+}
+
+// closeUnusedSockets closes the sockets passed by systemd that don't have
+// the name of a DNS or HTTP server.
+func (a *Agent) closeUnusedSockets() {
+	if a.activatedSockets == nil {
+		return
+	}
+	for name := range a.activatedSockets.Listeners {
+		a.logger.Warn("Ignoring unknown socket passed by systemd", "name", name)
+	}
+	for name := range a.activatedSockets.PacketConns {
+		a.logger.Warn("Ignoring unknown socket passed by systemd", "name", name)
+	}
+	a.activatedSockets.Close()
+	a.activatedSockets = nil
+}
+
+// listenDNSOverHTTPS creates the listeners and unstarted servers for the
+// DNS-over-HTTPS endpoint. They use the TLS configuration of the HTTPS
+// endpoint. See listenHTTP for why the servers are not started here.
+func (a *Agent) listenDNSOverHTTPS() ([]apiServer, error) {
+	if len(a.config.DNSHTTPSAddrs) == 0 {
+		return nil, nil
+	}
+
+	s, err := NewDNSServer(a)
+	if err != nil {
+		return nil, err
+	}
+	a.dnsServers = append(a.dnsServers, s)
+
+	listeners, err := a.startListeners(a.config.DNSHTTPSAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/dns-query", s)
+
+	var servers []apiServer
+	for _, l := range listeners {
+		tlscfg := a.tlsConfigurator.IncomingHTTPSConfig()
+		l = tls.NewListener(l, tlscfg)
+
+		httpServer := &http.Server{
+			Addr:           l.Addr().String(),
+			TLSConfig:      tlscfg,
+			Handler:        mux,
+			MaxHeaderBytes: a.config.HTTPMaxHeaderBytes,
+		}
+		connLimitFn := a.httpConnLimiter.HTTPConnStateFuncWithDefault429Handler(10 * time.Millisecond)
+		if err := setupHTTPS(httpServer, connLimitFn, a.config.HTTPSHandshakeTimeout); err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+
+		servers = append(servers, newAPIServerHTTP("dns_https", l, httpServer))
+	}
+	return servers, nil
+}
+
 func closeListeners(lns []net.Listener) {
 	for _, l := range lns {
 		l.Close()
@@ -1259,6 +1579,16 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 
 // newConsulConfig translates a RuntimeConfig into a consul.Config.
 // TODO: move this function to a different file, maybe config.go
+// rpcConsumerLabels returns the labels of the RPC request metrics attributing
+// the requests to the services and peers they are made for.
+func rpcConsumerLabels(telemetry lib.TelemetryConfig) rpcmiddleware.ConsumerLabelsConfig {
+	return rpcmiddleware.ConsumerLabelsConfig{
+		Service:   telemetry.RPCServiceLabels,
+		Peer:      telemetry.RPCPeerLabels,
+		MaxValues: telemetry.RPCLabelsMaxValues,
+	}
+}
+
 func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*consul.Config, error) {
 	cfg := consul.DefaultConfig()
 
@@ -1300,6 +1630,8 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	cfg.SerfLANConfig.MemberlistConfig.ProbeTimeout = runtimeCfg.GossipLANProbeTimeout
 	cfg.SerfLANConfig.MemberlistConfig.SuspicionMult = runtimeCfg.GossipLANSuspicionMult
 	cfg.SerfLANConfig.MemberlistConfig.RetransmitMult = runtimeCfg.GossipLANRetransmitMult
+	cfg.SerfLANConfig.MemberlistConfig.PushPullInterval = runtimeCfg.GossipLANPushPullInterval
+	cfg.GossipLANProfile = runtimeCfg.GossipLANProfile
 	if runtimeCfg.ReconnectTimeoutLAN != 0 {
 		cfg.SerfLANConfig.ReconnectTimeout = runtimeCfg.ReconnectTimeoutLAN
 	}
@@ -1376,9 +1708,16 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	if runtimeCfg.ACLEnableKeyListPolicy {
 		cfg.ACLEnableKeyListPolicy = runtimeCfg.ACLEnableKeyListPolicy
 	}
+	cfg.ACLClientCertAuthMethod = runtimeCfg.ACLClientCertAuthMethod
 	if runtimeCfg.SessionTTLMin != 0 {
 		cfg.SessionTTLMin = runtimeCfg.SessionTTLMin
 	}
+	if runtimeCfg.ServiceTombstoneTTL != 0 {
+		cfg.ServiceTombstoneTTL = runtimeCfg.ServiceTombstoneTTL
+	}
+	if runtimeCfg.KVCompressionThreshold != 0 {
+		cfg.KVCompressionThreshold = runtimeCfg.KVCompressionThreshold
+	}
 	if runtimeCfg.ReadReplica {
 		cfg.ReadReplica = runtimeCfg.ReadReplica
 	}
@@ -1393,6 +1732,12 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	cfg.AutopilotConfig.RedundancyZoneTag = runtimeCfg.AutopilotRedundancyZoneTag
 	cfg.AutopilotConfig.DisableUpgradeMigration = runtimeCfg.AutopilotDisableUpgradeMigration
 	cfg.AutopilotConfig.UpgradeVersionTag = runtimeCfg.AutopilotUpgradeVersionTag
+	cfg.AutopilotPromotion = consul.AutopilotPromotionConfig{
+		Strategy:        runtimeCfg.AutopilotPromotionStrategy,
+		MinServerUptime: runtimeCfg.AutopilotMinServerUptime,
+		ZoneMetaKey:     runtimeCfg.AutopilotZoneMetaKey,
+		VotersPerZone:   runtimeCfg.AutopilotVotersPerZone,
+	}
 
 	// make sure the advertise address is always set
 	if cfg.RPCAdvertise == nil {
@@ -1414,6 +1759,7 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	if runtimeCfg.RPCMaxConnsPerClient > 0 {
 		cfg.RPCMaxConnsPerClient = runtimeCfg.RPCMaxConnsPerClient
 	}
+	cfg.RPCConsumerLabels = rpcConsumerLabels(runtimeCfg.Telemetry)
 
 	// RPC-related performance configs. We allow explicit zero value to disable so
 	// copy it whatever the value.
@@ -1480,6 +1826,10 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 
 	cfg.ConfigEntryBootstrap = runtimeCfg.ConfigEntryBootstrap
 	cfg.LogStoreConfig = runtimeCfg.RaftLogStoreConfig
+	cfg.RaftCompression = runtimeCfg.RaftCompression
+	cfg.KeyringRotation = runtimeCfg.EncryptRotation
+	cfg.ExternalHealthChecks = runtimeCfg.ExternalHealthChecks
+	cfg.ChangeSinks = runtimeCfg.ChangeSinks
 
 	// Duplicate our own serf config once to make sure that the duplication
 	// function does not drift.
@@ -1487,10 +1837,22 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 
 	cfg.PeeringEnabled = runtimeCfg.PeeringEnabled
 	cfg.PeeringTestAllowPeerRegistrations = runtimeCfg.PeeringTestAllowPeerRegistrations
+	if runtimeCfg.PeeringIncomingMessageRate > 0 {
+		cfg.PeeringIncomingMessageRate = runtimeCfg.PeeringIncomingMessageRate
+	}
+	if runtimeCfg.PeeringIncomingMessageBurst > 0 {
+		cfg.PeeringIncomingMessageBurst = runtimeCfg.PeeringIncomingMessageBurst
+	}
 
 	cfg.RequestLimitsMode = runtimeCfg.RequestLimitsMode.String()
 	cfg.RequestLimitsReadRate = runtimeCfg.RequestLimitsReadRate
 	cfg.RequestLimitsWriteRate = runtimeCfg.RequestLimitsWriteRate
+	cfg.RequestLimitsIPReadRate = runtimeCfg.RequestLimitsIPReadRate
+	cfg.RequestLimitsIPWriteRate = runtimeCfg.RequestLimitsIPWriteRate
+	cfg.RequestLimitsIPOverrides = runtimeCfg.RequestLimitsIPOverrides
+	cfg.RequestLimitsTokenReadRate = runtimeCfg.RequestLimitsTokenReadRate
+	cfg.RequestLimitsTokenWriteRate = runtimeCfg.RequestLimitsTokenWriteRate
+	cfg.RequestLimitsTokenOverrides = runtimeCfg.RequestLimitsTokenOverrides
 
 	enterpriseConsulConfig(cfg, runtimeCfg)
 	return cfg, nil
@@ -1574,9 +1936,59 @@ func (a *Agent) RPC(ctx context.Context, method string, args interface{}, reply
 
 // Leave is used to prepare the agent for a graceful shutdown
 func (a *Agent) Leave() error {
+	a.drainServicesForLeave()
 	return a.delegate.Leave()
 }
 
+// drainServicesForLeave marks all the local services as draining for the
+// configured leave_service_drain_time and then deregisters them, so the
+// proxies in the mesh stop opening new connections to the instances of this
+// agent and the existing ones can finish before it leaves the cluster.
+// The services are kept in the data dir and are restored on restart.
+func (a *Agent) drainServicesForLeave() {
+	drainTime := a.config.LeaveServiceDrainTime
+	if drainTime <= 0 {
+		return
+	}
+
+	services := a.State.AllServices()
+	if len(services) == 0 {
+		return
+	}
+
+	for sid := range services {
+		if err := a.enableServiceDraining(sid, leaveServiceDrainingReason, 0, false, ""); err != nil {
+			a.logger.Warn("failed to drain service", "service", sid.String(), "error", err)
+		}
+	}
+
+	// The proxies learn about draining instances from the catalog, so sync
+	// right away rather than waiting for the next anti-entropy run.
+	if err := a.State.SyncChanges(); err != nil {
+		a.logger.Warn("failed to sync draining services", "error", err)
+	}
+
+	a.logger.Info("Waiting for services to drain", "services", len(services), "drain_time", drainTime)
+	select {
+	case <-time.After(drainTime):
+	case <-a.shutdownCh:
+		return
+	}
+
+	for sid := range services {
+		// Sidecars are removed along with the service they belong to.
+		if a.State.Service(sid) == nil {
+			continue
+		}
+		if err := a.removeService(sid, false); err != nil {
+			a.logger.Warn("failed to deregister drained service", "service", sid.String(), "error", err)
+		}
+	}
+	if err := a.State.SyncChanges(); err != nil {
+		a.logger.Warn("failed to sync drained services", "error", err)
+	}
+}
+
 // ShutdownAgent is used to hard stop the agent. Should be preceded by
 // Leave to do it gracefully. Should be followed by ShutdownEndpoints to
 // terminate the HTTP and DNS servers as well.
@@ -1596,6 +2008,11 @@ func (a *Agent) ShutdownAgent() error {
 		a.configFileWatcher.Stop()
 	}
 
+	// Stop the service definitions watcher
+	if a.serviceDefinitions != nil {
+		a.serviceDefinitions.Stop()
+	}
+
 	a.stopLicenseManager()
 
 	// this would be cancelled anyways (by the closing of the shutdown ch) but
@@ -1610,6 +2027,9 @@ func (a *Agent) ShutdownAgent() error {
 		a.serviceManager.Stop()
 	}
 
+	// Stop the leases, so that the persisted services are restored with them
+	a.serviceLeases.stop()
+
 	// Stop all the checks
 	for _, chk := range a.checkMonitors {
 		chk.Stop()
@@ -1635,15 +2055,35 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkAliases {
 		chk.Stop()
 	}
+	for _, chk := range a.checkComposites {
+		chk.Stop()
+	}
+	for _, chk := range a.checkProcesses {
+		chk.Stop()
+	}
 	for _, chk := range a.checkH2PINGs {
 		chk.Stop()
 	}
 
+	// Stop the timers of draining services
+	a.drainingLock.Lock()
+	for _, timer := range a.drainingTimers {
+		timer.Stop()
+	}
+	a.drainingLock.Unlock()
+
 	// Stop gRPC
 	if a.externalGRPCServer != nil {
 		a.externalGRPCServer.Stop()
 	}
 
+	// Stop recording the API operations
+	if a.auditor != nil {
+		if err := a.auditor.Close(); err != nil {
+			a.logger.Warn("failed to close the audit log", "error", err)
+		}
+	}
+
 	// Stop the proxy config manager
 	if a.proxyConfig != nil {
 		a.proxyConfig.Close()
@@ -1677,6 +2117,7 @@ func (a *Agent) ShutdownAgent() error {
 	}
 
 	a.logger.Info("shutdown complete")
+	a.baseDeps.MetricsConfig.ShutdownLogSink()
 	a.shutdown = true
 	close(a.shutdownCh)
 	return err
@@ -1723,17 +2164,48 @@ func (a *Agent) ShutdownCh() <-chan struct{} {
 	return a.shutdownCh
 }
 
+// readyCheckInterval is the interval between two checks of the readiness of
+// the agent, until it is ready.
+var readyCheckInterval = time.Second
+
+// notifyReady notifies systemd that the agent is ready once it is, so that
+// the units ordered after Consul are only started once it can answer their
+// requests.
+func (a *Agent) notifyReady(n notifier) {
+	for !a.isReady() {
+		select {
+		case <-a.shutdownCh:
+			return
+		case <-time.After(readyCheckInterval):
+		}
+	}
+	a.logger.Debug("agent is ready")
+	if err := n.Notify(systemd.Ready); err != nil {
+		a.logger.Debug("systemd notify failed", "error", err)
+	}
+}
+
+// isReady returns true once the agent has joined the LAN, for the clients,
+// and there is a leader in its datacenter that it can reach.
+func (a *Agent) isReady() bool {
+	if !a.config.ServerMode && len(a.delegate.LANMembersInAgentPartition()) < 2 {
+		return false
+	}
+
+	var leader string
+	args := structs.DCSpecificRequest{Datacenter: a.config.Datacenter}
+	if err := a.RPC(context.Background(), "Status.Leader", &args, &leader); err != nil {
+		return false
+	}
+	return leader != ""
+}
+
 // JoinLAN is used to have the agent join a LAN cluster
 func (a *Agent) JoinLAN(addrs []string, entMeta *acl.EnterpriseMeta) (n int, err error) {
 	a.logger.Info("(LAN) joining", "lan_addresses", addrs)
 	n, err = a.delegate.JoinLAN(addrs, entMeta)
 	if err == nil {
 		a.logger.Info("(LAN) joined", "number_of_nodes", n)
-		if a.joinLANNotifier != nil {
-			if notifErr := a.joinLANNotifier.Notify(systemd.Ready); notifErr != nil {
-				a.logger.Debug("systemd notify failed", "error", notifErr)
-			}
-		}
 	} else {
 		a.logger.Warn("(LAN) couldn't join",
 			"number_of_nodes", n,
@@ -2042,6 +2514,9 @@ type persistedService struct {
 	// to exclude it from API output, but we need it to properly deregister
 	// persisted sidecars.
 	LocallyRegisteredAsSidecar bool `json:",omitempty"`
+	// LeaseTTL is the TTL of the lease the service was registered with, if
+	// any. The lease starts again when the service is restored.
+	LeaseTTL string `json:",omitempty"`
 }
 
 func (a *Agent) makeServiceFilePath(svcID structs.ServiceID) string {
@@ -2059,6 +2534,9 @@ func (a *Agent) persistService(service *structs.NodeService, source configSource
 		Source:                     source.String(),
 		LocallyRegisteredAsSidecar: service.LocallyRegisteredAsSidecar,
 	}
+	if ttl := a.serviceLeases.ttl(svcID); ttl > 0 {
+		wrapped.LeaseTTL = ttl.String()
+	}
 	encoded, err := json.Marshal(wrapped)
 	if err != nil {
 		return err
@@ -2267,6 +2745,14 @@ func (a *Agent) addServiceLocked(req addServiceLockedRequest) error {
 		return err
 	}
 
+	// The lease starts with the registration, rather than every time the
+	// service manager updates the service with the central config.
+	if req.leaseTTL > 0 {
+		a.serviceLeases.set(req.Service.CompoundServiceID(), req.leaseTTL)
+	} else {
+		a.serviceLeases.remove(req.Service.CompoundServiceID())
+	}
+
 	if a.config.EnableCentralServiceConfig && (req.Service.IsSidecarProxy() || req.Service.IsGateway()) {
 		return a.serviceManager.AddService(req)
 	}
@@ -2304,6 +2790,11 @@ type AddServiceRequest struct {
 	token                 string
 	replaceExistingChecks bool
 	Source                configSource
+
+	// leaseTTL is the TTL of the lease of the service, which is deregistered
+	// unless the lease is renewed before it elapses. There is no lease if it
+	// is zero.
+	leaseTTL time.Duration
 }
 
 type addServiceInternalRequest struct {
@@ -2402,6 +2893,7 @@ func (a *Agent) addServiceInternal(req addServiceInternalRequest) error {
 			ServiceID:      service.ID,
 			ServiceName:    service.Service,
 			ServiceTags:    service.Tags,
+			PortName:       chkType.PortName,
 			Type:           chkType.Type(),
 			EnterpriseMeta: service.EnterpriseMeta,
 		}
@@ -2665,6 +3157,8 @@ func (a *Agent) removeServiceLocked(serviceID structs.ServiceID, persist bool) e
 		a.serviceManager.RemoveService(serviceID)
 	}
 
+	a.serviceLeases.remove(serviceID)
+
 	// Reset the HTTP check targets if they were exposed through a proxy
 	// If this is not a proxy or checks were not exposed then this is a no-op
 	svc := a.State.Service(serviceID)
@@ -2796,6 +3290,10 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			return fmt.Errorf("Check is not valid: %v", err)
 		}
 
+		if chkType.IsComposite() && check.ServiceID == "" {
+			return fmt.Errorf("Check is not valid: Composite checks must be associated with a service")
+		}
+
 		if chkType.IsScript() {
 			if source == ConfigSourceLocal && !a.config.EnableLocalScriptChecks {
 				return fmt.Errorf("Scripts are disabled on this agent; to enable, configure 'enable_script_checks' or 'enable_local_script_checks' to true")
@@ -2813,6 +3311,15 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 		check.EnterpriseMeta = service.EnterpriseMeta
 	}
 
+	if check.PortName != "" {
+		if check.ServiceID == "" {
+			return fmt.Errorf("Check is not valid: PortName can only be set for checks associated with a service")
+		}
+		if _, ok := service.Ports.Get(check.PortName); !ok {
+			return fmt.Errorf("Check is not valid: service %q has no port named %q", service.ID, check.PortName)
+		}
+	}
+
 	// Check if already registered
 	if chkType != nil {
 		maxOutputSize := a.config.CheckOutputMaxSize
@@ -2862,6 +3369,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 				TTL:           chkType.TTL,
 				Logger:        a.logger,
 				OutputMaxSize: maxOutputSize,
+				StatusHandler: statusHandler,
 			}
 
 			// Restore persisted state, if any
@@ -2987,13 +3495,24 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 
 			var tlsClientConfig *tls.Config
 			if chkType.GRPCUseTLS {
-				tlsClientConfig = a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify, chkType.TLSServerName)
+				// Default the server name to the overridden authority since
+				// the address may not be a hostname, e.g. for unix sockets.
+				serverName := chkType.TLSServerName
+				if serverName == "" && chkType.GRPCAuthority != "" {
+					serverName = chkType.GRPCAuthority
+					if host, _, err := net.SplitHostPort(serverName); err == nil {
+						serverName = host
+					}
+				}
+				tlsClientConfig = a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify, serverName)
 			}
 
 			grpc := &checks.CheckGRPC{
 				CheckID:         cid,
 				ServiceID:       sid,
 				GRPC:            chkType.GRPC,
+				Service:         chkType.GRPCService,
+				Authority:       chkType.GRPCAuthority,
 				Interval:        chkType.Interval,
 				Timeout:         chkType.Timeout,
 				Logger:          a.logger,
@@ -3001,7 +3520,8 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 				StatusHandler:   statusHandler,
 			}
 
-			if proxy != nil && proxy.Proxy.Expose.Checks {
+			// Unix sockets can't be exposed through the proxy listener.
+			if proxy != nil && proxy.Proxy.Expose.Checks && !checks.IsUnixGRPCTarget(grpc.GRPC) {
 				port, err := a.listenerPortLocked(sid, cid)
 				if err != nil {
 					a.logger.Error("error exposing check",
@@ -3031,7 +3551,11 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			}
 
 			if a.dockerClient == nil {
-				dc, err := checks.NewDockerClient(os.Getenv("DOCKER_HOST"), int64(maxOutputSize))
+				host := a.config.DockerHost
+				if host == "" {
+					host = os.Getenv("DOCKER_HOST")
+				}
+				dc, err := checks.NewDockerClient(host, int64(maxOutputSize))
 				if err != nil {
 					a.logger.Error("error creating docker client", "error", err)
 					return err
@@ -3090,6 +3614,32 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			osServiceCheck.Start()
 			a.checkOSServices[cid] = osServiceCheck
 
+		case chkType.IsProcess():
+			if existing, ok := a.checkProcesses[cid]; ok {
+				existing.Stop()
+				delete(a.checkProcesses, cid)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Warn("check has interval below minimum",
+					"check", cid.String(),
+					"minimum_interval", checks.MinInterval,
+				)
+				chkType.Interval = checks.MinInterval
+			}
+
+			processCheck := &checks.CheckProcess{
+				CheckID:       cid,
+				ServiceID:     sid,
+				PID:           chkType.ProcessPID,
+				PIDFile:       chkType.ProcessPIDFile,
+				Cgroup:        chkType.ProcessCgroup,
+				Interval:      chkType.Interval,
+				Logger:        a.logger,
+				StatusHandler: statusHandler,
+			}
+			processCheck.Start()
+			a.checkProcesses[cid] = processCheck
+
 		case chkType.IsMonitor():
 			if existing, ok := a.checkMonitors[cid]; ok {
 				existing.Stop()
@@ -3175,11 +3725,31 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 				CheckID:        cid,
 				Node:           chkType.AliasNode,
 				ServiceID:      aliasServiceID,
+				StatusHandler:  statusHandler,
 				EnterpriseMeta: check.EnterpriseMeta,
 			}
 			chkImpl.Start()
 			a.checkAliases[cid] = chkImpl
 
+		case chkType.IsComposite():
+			if existing, ok := a.checkComposites[cid]; ok {
+				existing.Stop()
+				delete(a.checkComposites, cid)
+			}
+
+			chkImpl := &checks.CheckComposite{
+				Notify:         a.State,
+				CheckID:        cid,
+				ServiceID:      sid,
+				Checks:         chkType.CompositeChecks,
+				Mode:           chkType.CompositeMode,
+				MinPassing:     chkType.CompositeMinPassing,
+				StatusHandler:  statusHandler,
+				EnterpriseMeta: check.EnterpriseMeta,
+			}
+			chkImpl.Start()
+			a.checkComposites[cid] = chkImpl
+
 		default:
 			return fmt.Errorf("Check type is not valid")
 		}
@@ -3328,6 +3898,14 @@ func (a *Agent) cancelCheckMonitors(checkID structs.CheckID) {
 		check.Stop()
 		delete(a.checkAliases, checkID)
 	}
+	if check, ok := a.checkComposites[checkID]; ok {
+		check.Stop()
+		delete(a.checkComposites, checkID)
+	}
+	if check, ok := a.checkProcesses[checkID]; ok {
+		check.Stop()
+		delete(a.checkProcesses, checkID)
+	}
 }
 
 // updateTTLCheck is used to update the status of a TTL check via the Agent API.
@@ -3542,79 +4120,95 @@ func (a *Agent) deletePid() error {
 	return nil
 }
 
-// loadServices will load service definitions from configuration and persisted
-// definitions on disk, and load them into the local agent.
-func (a *Agent) loadServices(conf *config.RuntimeConfig, snap map[structs.CheckID]*structs.HealthCheck) error {
-	// Load any persisted service configs so we can feed those into the initial
-	// registrations below.
-	persistedServiceConfigs, err := a.readPersistedServiceConfigs()
-	if err != nil {
-		return err
+// addServiceDefinitionLocked registers a service defined in the agent
+// configuration, along with its sidecar if it has one.
+func (a *Agent) addServiceDefinitionLocked(service *structs.ServiceDefinition, persistedServiceConfigs map[structs.ServiceID]*structs.ServiceConfigResponse, snap map[structs.CheckID]*structs.HealthCheck) error {
+	// Default service partition to the same as agent
+	if service.EnterpriseMeta.PartitionOrEmpty() == "" {
+		service.EnterpriseMeta.OverridePartition(a.AgentEnterpriseMeta().PartitionOrDefault())
 	}
 
-	// Register the services from config
-	for _, service := range conf.Services {
-		// Default service partition to the same as agent
-		if service.EnterpriseMeta.PartitionOrEmpty() == "" {
-			service.EnterpriseMeta.OverridePartition(a.AgentEnterpriseMeta().PartitionOrDefault())
-		}
-
-		ns := service.NodeService()
-		chkTypes, err := service.CheckTypes()
-		if err != nil {
-			return fmt.Errorf("Failed to validate checks for service %q: %v", service.Name, err)
-		}
-
-		// Grab and validate sidecar if there is one too
-		sidecar, sidecarChecks, sidecarToken, err := sidecarServiceFromNodeService(ns, service.Token)
-		if err != nil {
-			return fmt.Errorf("Failed to validate sidecar for service %q: %v", service.Name, err)
-		}
+	ns := service.NodeService()
+	chkTypes, err := service.CheckTypes()
+	if err != nil {
+		return fmt.Errorf("Failed to validate checks for service %q: %v", service.Name, err)
+	}
 
-		// Remove sidecar from NodeService now it's done it's job it's just a config
-		// syntax sugar and shouldn't be persisted in local or server state.
-		ns.Connect.SidecarService = nil
+	// Grab and validate sidecar if there is one too
+	sidecar, sidecarChecks, sidecarToken, err := sidecarServiceFromNodeService(ns, service.Token)
+	if err != nil {
+		return fmt.Errorf("Failed to validate sidecar for service %q: %v", service.Name, err)
+	}
+
+	// Remove sidecar from NodeService now it's done it's job it's just a config
+	// syntax sugar and shouldn't be persisted in local or server state.
+	ns.Connect.SidecarService = nil
+
+	sid := ns.CompoundServiceID()
+	err = a.addServiceLocked(addServiceLockedRequest{
+		AddServiceRequest: AddServiceRequest{
+			Service:               ns,
+			chkTypes:              chkTypes,
+			persist:               false, // don't rewrite the file with the same data we just read
+			token:                 service.Token,
+			replaceExistingChecks: false, // do default behavior
+			Source:                ConfigSourceLocal,
+		},
+		serviceDefaults:      serviceDefaultsFromStruct(persistedServiceConfigs[sid]),
+		persistServiceConfig: false, // don't rewrite the file with the same data we just read
+		checkStateSnapshot:   snap,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to register service %q: %v", service.Name, err)
+	}
 
-		sid := ns.CompoundServiceID()
+	// If there is a sidecar service, register that too.
+	if sidecar != nil {
+		sidecarServiceID := sidecar.CompoundServiceID()
 		err = a.addServiceLocked(addServiceLockedRequest{
 			AddServiceRequest: AddServiceRequest{
-				Service:               ns,
-				chkTypes:              chkTypes,
+				Service:               sidecar,
+				chkTypes:              sidecarChecks,
 				persist:               false, // don't rewrite the file with the same data we just read
-				token:                 service.Token,
+				token:                 sidecarToken,
 				replaceExistingChecks: false, // do default behavior
 				Source:                ConfigSourceLocal,
 			},
-			serviceDefaults:      serviceDefaultsFromStruct(persistedServiceConfigs[sid]),
+			serviceDefaults:      serviceDefaultsFromStruct(persistedServiceConfigs[sidecarServiceID]),
 			persistServiceConfig: false, // don't rewrite the file with the same data we just read
 			checkStateSnapshot:   snap,
 		})
 		if err != nil {
-			return fmt.Errorf("Failed to register service %q: %v", service.Name, err)
+			return fmt.Errorf("Failed to register sidecar for service %q: %v", service.Name, err)
 		}
+	}
+	return nil
+}
 
-		// If there is a sidecar service, register that too.
-		if sidecar != nil {
-			sidecarServiceID := sidecar.CompoundServiceID()
-			err = a.addServiceLocked(addServiceLockedRequest{
-				AddServiceRequest: AddServiceRequest{
-					Service:               sidecar,
-					chkTypes:              sidecarChecks,
-					persist:               false, // don't rewrite the file with the same data we just read
-					token:                 sidecarToken,
-					replaceExistingChecks: false, // do default behavior
-					Source:                ConfigSourceLocal,
-				},
-				serviceDefaults:      serviceDefaultsFromStruct(persistedServiceConfigs[sidecarServiceID]),
-				persistServiceConfig: false, // don't rewrite the file with the same data we just read
-				checkStateSnapshot:   snap,
-			})
-			if err != nil {
-				return fmt.Errorf("Failed to register sidecar for service %q: %v", service.Name, err)
-			}
+// loadServices will load service definitions from configuration and persisted
+// definitions on disk, and load them into the local agent.
+func (a *Agent) loadServices(conf *config.RuntimeConfig, snap map[structs.CheckID]*structs.HealthCheck) error {
+	// Load any persisted service configs so we can feed those into the initial
+	// registrations below.
+	persistedServiceConfigs, err := a.readPersistedServiceConfigs()
+	if err != nil {
+		return err
+	}
+
+	// Register the services from config
+	for _, service := range conf.Services {
+		if err := a.addServiceDefinitionLocked(service, persistedServiceConfigs, snap); err != nil {
+			return err
 		}
 	}
 
+	// Register the services from the files of services_auto_reload.dir. An
+	// invalid file must not prevent the agent from starting, the errors are
+	// reported by the watcher instead.
+	if a.serviceDefinitions != nil {
+		a.serviceDefinitions.loadLocked(persistedServiceConfigs, snap)
+	}
+
 	// Load any persisted services
 	svcDir := filepath.Join(a.config.DataDir, servicesDir)
 	files, err := os.ReadDir(svcDir)
@@ -3689,6 +4283,19 @@ func (a *Agent) loadServices(conf *config.RuntimeConfig, snap map[structs.CheckI
 		// Restore LocallyRegisteredAsSidecar, see persistedService.LocallyRegisteredAsSidecar
 		p.Service.LocallyRegisteredAsSidecar = p.LocallyRegisteredAsSidecar
 
+		var leaseTTL time.Duration
+		if p.LeaseTTL != "" {
+			ttl, err := time.ParseDuration(p.LeaseTTL)
+			if err != nil {
+				a.logger.Warn("service has an invalid lease TTL, restoring it without a lease",
+					"file", file,
+					"lease_ttl", p.LeaseTTL,
+					"error", err,
+				)
+			}
+			leaseTTL = ttl
+		}
+
 		serviceID := p.Service.CompoundServiceID()
 
 		source, ok := ConfigSourceFromName(p.Source)
@@ -3732,6 +4339,7 @@ func (a *Agent) loadServices(conf *config.RuntimeConfig, snap map[structs.CheckI
 					token:                 p.Token,
 					replaceExistingChecks: false, // do default behavior
 					Source:                source,
+					leaseTTL:              leaseTTL,
 				},
 				serviceDefaults:      serviceDefaultsFromStruct(persistedServiceConfigs[serviceID]),
 				persistServiceConfig: false, // don't rewrite the file with the same data we just read
@@ -3989,6 +4597,146 @@ func (a *Agent) DisableServiceMaintenance(serviceID structs.ServiceID) error {
 	return nil
 }
 
+// serviceDrainingCheckID returns the ID of a given service's draining check
+func serviceDrainingCheckID(serviceID structs.ServiceID) structs.CheckID {
+	cid := types.CheckID(structs.ServiceDrainingPrefix + serviceID.ID)
+	return structs.NewCheckID(cid, &serviceID.EnterpriseMeta)
+}
+
+// EnableServiceDraining will register a passing health check against the
+// given service ID that marks it as draining. The service stays registered
+// and healthy, but is given a weight of zero and the mesh stops opening new
+// connections to it while existing ones finish. Its sidecar proxies are
+// drained along with it. If timeout is not zero, the draining state is lifted
+// once it expires. Draining is only persisted across agent restarts when there
+// is no timeout.
+func (a *Agent) EnableServiceDraining(serviceID structs.ServiceID, reason string, timeout time.Duration, token string) error {
+	return a.enableServiceDraining(serviceID, reason, timeout, timeout == 0, token)
+}
+
+func (a *Agent) enableServiceDraining(serviceID structs.ServiceID, reason string, timeout time.Duration, persist bool, token string) error {
+	service := a.State.Service(serviceID)
+	if service == nil {
+		return fmt.Errorf("No service registered with ID %q", serviceID.String())
+	}
+
+	// Use default notes if no reason provided
+	if reason == "" {
+		reason = defaultServiceDrainingReason
+	}
+
+	// The mesh reaches the service through its sidecar proxies, whose
+	// instances are the endpoints of the upstream clusters, so they are
+	// drained along with it.
+	for _, svc := range append([]*structs.NodeService{service}, a.sidecarProxies(service)...) {
+		if err := a.addServiceDrainingCheck(svc, reason, persist, token); err != nil {
+			return err
+		}
+	}
+
+	a.drainingLock.Lock()
+	defer a.drainingLock.Unlock()
+
+	if timer, ok := a.drainingTimers[serviceID]; ok {
+		timer.Stop()
+		delete(a.drainingTimers, serviceID)
+	}
+	if timeout > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(timeout, func() {
+			// Ignore the timer if draining was disabled or enabled again
+			// since it was started.
+			a.drainingLock.Lock()
+			current := a.drainingTimers[serviceID]
+			a.drainingLock.Unlock()
+			if current != timer {
+				return
+			}
+
+			a.logger.Info("Service draining timed out", "service", serviceID.String())
+			if err := a.DisableServiceDraining(serviceID); err != nil {
+				a.logger.Warn("failed to lift service draining", "service", serviceID.String(), "error", err)
+			}
+		})
+		a.drainingTimers[serviceID] = timer
+	}
+
+	return nil
+}
+
+// addServiceDrainingCheck registers the draining check of the given service,
+// unless draining is already enabled.
+func (a *Agent) addServiceDrainingCheck(service *structs.NodeService, reason string, persist bool, token string) error {
+	sid := service.CompoundServiceID()
+	checkID := serviceDrainingCheckID(sid)
+	if a.State.Check(checkID) != nil {
+		return nil
+	}
+
+	check := &structs.HealthCheck{
+		Node:           a.config.NodeName,
+		CheckID:        checkID.ID,
+		Name:           "Service Draining",
+		Notes:          reason,
+		ServiceID:      service.ID,
+		ServiceName:    service.Service,
+		Status:         api.HealthPassing,
+		Type:           "draining",
+		EnterpriseMeta: checkID.EnterpriseMeta,
+	}
+	if err := a.AddCheck(check, nil, persist, token, ConfigSourceLocal); err != nil {
+		return err
+	}
+	a.logger.Info("Service started draining", "service", sid.String())
+	return nil
+}
+
+// sidecarProxies returns the local sidecar proxies of the given service.
+func (a *Agent) sidecarProxies(service *structs.NodeService) []*structs.NodeService {
+	var proxies []*structs.NodeService
+	// NOTE: Both services must live in the same namespace and
+	// partition so this will correctly scope the results.
+	for _, svc := range a.State.Services(&service.EnterpriseMeta) {
+		if svc.Kind == structs.ServiceKindConnectProxy && svc.Proxy.DestinationServiceID == service.ID {
+			proxies = append(proxies, svc)
+		}
+	}
+	return proxies
+}
+
+// DisableServiceDraining will deregister the draining check if the service
+// has been marked as draining, along with the ones of its sidecar proxies.
+func (a *Agent) DisableServiceDraining(serviceID structs.ServiceID) error {
+	a.drainingLock.Lock()
+	if timer, ok := a.drainingTimers[serviceID]; ok {
+		timer.Stop()
+		delete(a.drainingTimers, serviceID)
+	}
+	a.drainingLock.Unlock()
+
+	service := a.State.Service(serviceID)
+	if service == nil {
+		return fmt.Errorf("No service registered with ID %q", serviceID.String())
+	}
+
+	for _, svc := range append([]*structs.NodeService{service}, a.sidecarProxies(service)...) {
+		sid := svc.CompoundServiceID()
+
+		// Check if draining is enabled
+		checkID := serviceDrainingCheckID(sid)
+		if a.State.Check(checkID) == nil {
+			// draining is not enabled
+			continue
+		}
+
+		// Deregister the draining check
+		a.RemoveCheck(checkID, true)
+		a.logger.Info("Service stopped draining", "service", sid.String())
+	}
+
+	return nil
+}
+
 // EnableNodeMaintenance places a node into maintenance mode.
 func (a *Agent) EnableNodeMaintenance(reason, token string) {
 	// Ensure node maintenance is not already enabled
@@ -4155,6 +4903,7 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 	a.httpConnLimiter.SetConfig(connlimit.Config{
 		MaxConnsPerClientIP: newCfg.HTTPMaxConnsPerClient,
 	})
+	a.setHTTPLimits(newCfg)
 
 	for _, s := range a.dnsServers {
 		if err := s.ReloadConfig(newCfg); err != nil {
@@ -4169,14 +4918,21 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 
 	cc := consul.ReloadableConfig{
 		RequestLimits: &consul.RequestLimits{
-			Mode:      newCfg.RequestLimitsMode,
-			ReadRate:  newCfg.RequestLimitsReadRate,
-			WriteRate: newCfg.RequestLimitsWriteRate,
+			Mode:           newCfg.RequestLimitsMode,
+			ReadRate:       newCfg.RequestLimitsReadRate,
+			WriteRate:      newCfg.RequestLimitsWriteRate,
+			IPReadRate:     newCfg.RequestLimitsIPReadRate,
+			IPWriteRate:    newCfg.RequestLimitsIPWriteRate,
+			IPOverrides:    newCfg.RequestLimitsIPOverrides,
+			TokenReadRate:  newCfg.RequestLimitsTokenReadRate,
+			TokenWriteRate: newCfg.RequestLimitsTokenWriteRate,
+			TokenOverrides: newCfg.RequestLimitsTokenOverrides,
 		},
 		RPCClientTimeout:      newCfg.RPCClientTimeout,
 		RPCRateLimit:          newCfg.RPCRateLimit,
 		RPCMaxBurst:           newCfg.RPCMaxBurst,
 		RPCMaxConnsPerClient:  newCfg.RPCMaxConnsPerClient,
+		RPCConsumerLabels:     rpcConsumerLabels(newCfg.Telemetry),
 		ConfigEntryBootstrap:  newCfg.ConfigEntryBootstrap,
 		RaftSnapshotThreshold: newCfg.RaftSnapshotThreshold,
 		RaftSnapshotInterval:  newCfg.RaftSnapshotInterval,
@@ -4198,6 +4954,15 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 	metrics.UpdateFilter(newCfg.Telemetry.AllowedPrefixes,
 		newCfg.Telemetry.BlockedPrefixes)
 
+	// The Prometheus definitions are set up with the agent rather than read
+	// from the configuration.
+	newCfg.Telemetry.PrometheusOpts.GaugeDefinitions = a.config.Telemetry.PrometheusOpts.GaugeDefinitions
+	newCfg.Telemetry.PrometheusOpts.CounterDefinitions = a.config.Telemetry.PrometheusOpts.CounterDefinitions
+	newCfg.Telemetry.PrometheusOpts.SummaryDefinitions = a.config.Telemetry.PrometheusOpts.SummaryDefinitions
+	if err := a.baseDeps.MetricsConfig.Reload(newCfg.Telemetry); err != nil {
+		return fmt.Errorf("Failed reloading telemetry sinks: %v", err)
+	}
+
 	a.State.SetDiscardCheckOutput(newCfg.DiscardCheckOutput)
 
 	for _, r := range a.configReloaders {
@@ -4211,6 +4976,29 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 	return nil
 }
 
+// httpLimits are the size limits of the KV and transaction endpoints.
+type httpLimits struct {
+	kvMaxValueSize uint64
+	txnMaxReqLen   uint64
+}
+
+func (a *Agent) setHTTPLimits(cfg *config.RuntimeConfig) {
+	a.httpLimits.Store(httpLimits{
+		kvMaxValueSize: cfg.KVMaxValueSize,
+		txnMaxReqLen:   cfg.TxnMaxReqLen,
+	})
+}
+
+func (a *Agent) getHTTPLimits() httpLimits {
+	if limits, ok := a.httpLimits.Load().(httpLimits); ok {
+		return limits
+	}
+	return httpLimits{
+		kvMaxValueSize: a.config.KVMaxValueSize,
+		txnMaxReqLen:   a.config.TxnMaxReqLen,
+	}
+}
+
 // LocalBlockingQuery performs a blocking query in a generic way against
 // local agent state that has no RPC or raft to back it. It uses `hash` parameter
 // instead of an `index`.
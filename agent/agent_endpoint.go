@@ -3,7 +3,9 @@ package agent
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,14 +20,18 @@ import (
 	"github.com/hashicorp/serf/serf"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
 
 	"github.com/hashicorp/consul/acl"
 	cachetype "github.com/hashicorp/consul/agent/cache-types"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/debug"
+	"github.com/hashicorp/consul/agent/proxycfg"
 	"github.com/hashicorp/consul/agent/structs"
 	token_store "github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/agent/xds"
 	"github.com/hashicorp/consul/api"
+	connectmetrics "github.com/hashicorp/consul/connect/metrics"
 	"github.com/hashicorp/consul/ipaddr"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/logging"
@@ -253,6 +259,86 @@ func (m metricsEncoder) Encode(summary interface{}) error {
 	return nil
 }
 
+// AgentMeshMetrics serves the golden signals of the local sidecar proxies,
+// aggregated from their Envoy metrics, in the Prometheus text format.
+func (s *HTTPHandlers) AgentMeshMetrics(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Fetch the ACL token, if any, and enforce agent policy.
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authorize using the agent's own enterprise meta, not the token.
+	var authzContext acl.AuthorizerContext
+	s.agent.AgentEnterpriseMeta().FillAuthzContext(&authzContext)
+	if err := authz.ToAllowAuthorizer().AgentReadAllowed(s.agent.config.NodeName, &authzContext); err != nil {
+		return nil, err
+	}
+
+	targets := meshMetricsTargets(s.agent.State.AllServices())
+	families := s.agent.meshMetrics.Aggregate(req.Context(), targets)
+
+	resp.Header().Set("Content-Type", string(expfmt.FmtText))
+	for _, f := range families {
+		if _, err := expfmt.MetricFamilyToText(resp, f); err != nil {
+			s.agent.logger.Debug("failed to write the mesh metrics", "error", err)
+			break
+		}
+	}
+	return nil, nil
+}
+
+// meshMetricsTargets returns the local sidecar proxies whose Envoy metrics
+// are exposed, sorted by ID. The metrics are read from the
+// envoy_prometheus_bind_addr listener, or else from the
+// envoy_stats_bind_addr one.
+func meshMetricsTargets(services map[structs.ServiceID]*structs.NodeService) []connectmetrics.MeshTarget {
+	var targets []connectmetrics.MeshTarget
+	for _, svc := range services {
+		if svc.Kind != structs.ServiceKindConnectProxy {
+			continue
+		}
+
+		var url string
+		if addr := proxyBindAddr(svc.Proxy.Config, "envoy_prometheus_bind_addr"); addr != "" {
+			url = "http://" + addr + "/metrics"
+		} else if addr := proxyBindAddr(svc.Proxy.Config, "envoy_stats_bind_addr"); addr != "" {
+			url = "http://" + addr + connectmetrics.EnvoyMetricsPath
+		} else {
+			continue
+		}
+
+		targets = append(targets, connectmetrics.MeshTarget{
+			ProxyID: svc.ID,
+			Service: svc.Proxy.DestinationServiceName,
+			URL:     url,
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].ProxyID < targets[j].ProxyID
+	})
+	return targets
+}
+
+// proxyBindAddr returns the address of a listener of the proxy
+// configuration, with an unspecified host replaced by the loopback address.
+func proxyBindAddr(config map[string]interface{}, key string) string {
+	addr, ok := config[key].(string)
+	if !ok || addr == "" {
+		return ""
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
 func (s *HTTPHandlers) AgentReload(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Fetch the ACL token, if any, and enforce agent policy.
 	var token string
@@ -296,6 +382,7 @@ func buildAgentService(s *structs.NodeService, dc string) api.AgentService {
 		Tags:              s.Tags,
 		Meta:              s.Meta,
 		Port:              s.Port,
+		Ports:             s.Ports.ToAPI(),
 		Address:           s.Address,
 		SocketPath:        s.SocketPath,
 		TaggedAddresses:   taggedAddrs,
@@ -328,6 +415,30 @@ func buildAgentService(s *structs.NodeService, dc string) api.AgentService {
 	return as
 }
 
+// AgentServiceDefinitions returns the state of the files of
+// services_auto_reload.dir, including their validation errors.
+func (s *HTTPHandlers) AgentServiceDefinitions(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Fetch the ACL token, if any, and enforce agent policy.
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authorize using the agent's own enterprise meta, not the token.
+	var authzContext acl.AuthorizerContext
+	s.agent.AgentEnterpriseMeta().FillAuthzContext(&authzContext)
+	if err := authz.ToAllowAuthorizer().AgentReadAllowed(s.agent.config.NodeName, &authzContext); err != nil {
+		return nil, err
+	}
+
+	if s.agent.serviceDefinitions == nil {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: "services_auto_reload is not enabled"}
+	}
+	return s.agent.serviceDefinitions.Files(), nil
+}
+
 func (s *HTTPHandlers) AgentServices(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Fetch the ACL token, if any.
 	var token string
@@ -1219,6 +1330,14 @@ func (s *HTTPHandlers) AgentRegisterService(resp http.ResponseWriter, req *http.
 		replaceExistingChecks = true
 	}
 
+	var leaseTTL time.Duration
+	if lease := query.Get("lease"); lease != "" {
+		leaseTTL, err = time.ParseDuration(lease)
+		if err != nil || leaseTTL < minServiceLeaseTTL {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid lease: must be a duration of at least %s", minServiceLeaseTTL)}
+		}
+	}
+
 	addReq := AddServiceRequest{
 		Service:               ns,
 		chkTypes:              chkTypes,
@@ -1226,6 +1345,7 @@ func (s *HTTPHandlers) AgentRegisterService(resp http.ResponseWriter, req *http.
 		token:                 token,
 		Source:                ConfigSourceRemote,
 		replaceExistingChecks: replaceExistingChecks,
+		leaseTTL:              leaseTTL,
 	}
 	if err := s.agent.AddService(addReq); err != nil {
 		return nil, err
@@ -1343,6 +1463,73 @@ func (s *HTTPHandlers) AgentServiceMaintenance(resp http.ResponseWriter, req *ht
 	return nil, nil
 }
 
+func (s *HTTPHandlers) AgentServiceDraining(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Ensure we have a service ID
+	serviceID := strings.TrimPrefix(req.URL.Path, "/v1/agent/service/draining/")
+	entMeta := acl.NewEnterpriseMetaWithPartition(s.agent.config.PartitionOrDefault(), "")
+	sid := structs.NewServiceID(serviceID, &entMeta)
+
+	if sid.ID == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing service ID"}
+	}
+
+	// Ensure we have some action
+	params := req.URL.Query()
+	if _, ok := params["enable"]; !ok {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing value for enable"}
+	}
+
+	raw := params.Get("enable")
+	enable, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid value for enable: %q", raw)}
+	}
+
+	var timeout time.Duration
+	if raw := params.Get("timeout"); raw != "" {
+		timeout, err = time.ParseDuration(raw)
+		if err != nil || timeout < 0 {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid value for timeout: %q", raw)}
+		}
+	}
+
+	// Get the provided token, if any, and vet against any ACL policies.
+	var token string
+	s.parseToken(req, &token)
+
+	if err := s.parseEntMetaNoWildcard(req, &sid.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &sid.EnterpriseMeta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sid.Normalize()
+
+	if !s.validateRequestPartition(resp, &sid.EnterpriseMeta) {
+		return nil, nil
+	}
+
+	if err := s.agent.vetServiceUpdateWithAuthorizer(authz, sid); err != nil {
+		return nil, err
+	}
+
+	if enable {
+		reason := params.Get("reason")
+		if err = s.agent.EnableServiceDraining(sid, reason, timeout, token); err != nil {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: err.Error()}
+		}
+	} else {
+		if err = s.agent.DisableServiceDraining(sid); err != nil {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: err.Error()}
+		}
+	}
+	s.syncChanges()
+	return nil, nil
+}
+
 func (s *HTTPHandlers) AgentNodeMaintenance(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Ensure we have some action
 	params := req.URL.Query()
@@ -1679,3 +1866,49 @@ func (s *HTTPHandlers) AgentHost(resp http.ResponseWriter, req *http.Request) (i
 
 	return debug.CollectHostInfo(), nil
 }
+
+// proxyDebug is the state and xDS configuration of a proxy reported by
+// AgentProxyDebug.
+type proxyDebug struct {
+	proxycfg.DebugState
+
+	XDS *xds.ProxyDebug `json:"xDS,omitempty"`
+
+	// Error is the error generating the xDS configuration of the proxy.
+	Error string `json:",omitempty"`
+}
+
+// AgentProxyDebug
+//
+// GET /v1/agent/proxy/debug
+//
+// Retrieves the state of the proxies tracked by the agent and the xDS
+// configuration generated for them, with the private keys redacted. Requires
+// a operator:read ACL token.
+func (s *HTTPHandlers) AgentProxyDebug(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return nil, err
+	}
+
+	logger := s.agent.logger.Named(logging.Envoy)
+	states := s.agent.proxyConfig.DebugStates()
+	proxies := make([]proxyDebug, 0, len(states))
+	for _, state := range states {
+		proxy := proxyDebug{DebugState: state}
+		if state.Snapshot != nil {
+			proxy.XDS, err = xds.DebugProxy(logger, s.agent, state.Snapshot)
+			if err != nil {
+				proxy.Error = err.Error()
+			}
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/token"
 	tokenStore "github.com/hashicorp/consul/agent/token"
+	connectmetrics "github.com/hashicorp/consul/connect/metrics"
 	"github.com/hashicorp/consul/envoyextensions/xdscommon"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -2190,7 +2191,7 @@ func (n *mockNotifier) Notify(state string) error {
 	return nil
 }
 
-func TestAgent_JoinLANNotify(t *testing.T) {
+func TestAgent_NotifyReady(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
 	}
@@ -2206,17 +2207,27 @@ func TestAgent_JoinLANNotify(t *testing.T) {
 	`)
 	defer a2.Shutdown()
 
+	// the client is not ready until it joins the servers
+	require.False(t, a2.isReady())
+
 	notif := &mockNotifier{}
-	a1.joinLANNotifier = notif
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a2.notifyReady(notif)
+	}()
 
-	addr := fmt.Sprintf("127.0.0.1:%d", a2.Config.SerfPortLAN)
-	_, err := a1.JoinLAN([]string{addr}, nil)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
+	addr := fmt.Sprintf("127.0.0.1:%d", a1.Config.SerfPortLAN)
+	_, err := a2.JoinLAN([]string{addr}, nil)
+	require.NoError(t, err)
 
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the agent was not ready after joining the LAN")
+	}
 	if got, want := notif.s, "READY=1"; got != want {
-		t.Fatalf("got joinLAN notification %q want %q", got, want)
+		t.Fatalf("got ready notification %q want %q", got, want)
 	}
 }
 
@@ -4117,6 +4128,46 @@ func testAgent_RegisterService_ACLDeny(t *testing.T, extraHCL string) {
 	})
 }
 
+func TestAgent_RegisterService_ACLServiceIdentityNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	register := func(t *testing.T, node string) int {
+		tokenReq := structs.ACLTokenSetRequest{
+			ACLToken: structs.ACLToken{
+				ServiceIdentities: []*structs.ACLServiceIdentity{{ServiceName: "web", Node: node}},
+			},
+			Datacenter:   "dc1",
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var token structs.ACLToken
+		require.NoError(t, a.RPC(context.Background(), "ACL.TokenSet", &tokenReq, &token))
+
+		args := &structs.ServiceDefinition{Name: "web", Port: 8000}
+		req, _ := http.NewRequest("PUT", "/v1/agent/service/register", jsonReader(args))
+		req.Header.Add("X-Consul-Token", token.SecretID)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	t.Run("other node", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, register(t, "other-node"))
+		require.Nil(t, a.State.Service(structs.NewServiceID("web", nil)))
+	})
+
+	t.Run("agent node", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, register(t, a.Config.NodeName))
+		require.NotNil(t, a.State.Service(structs.NewServiceID("web", nil)))
+	})
+}
+
 func TestAgent_RegisterService_InvalidAddress(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -5755,6 +5806,164 @@ func TestAgent_ServiceMaintenance_ACLDeny(t *testing.T) {
 	})
 }
 
+func TestAgent_ServiceDraining_BadRequest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	t.Run("not enabled", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/v1/agent/service/draining/test", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("no service id", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/v1/agent/service/draining/?enable=true", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("bad timeout", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/v1/agent/service/draining/test?enable=true&timeout=soon", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Contains(t, resp.Body.String(), "Invalid value for timeout")
+	})
+
+	t.Run("bad service id", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/v1/agent/service/draining/_nope_?enable=true", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestAgent_ServiceDraining_EnableDisable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Register the service
+	serviceReq := AddServiceRequest{
+		Service: &structs.NodeService{
+			ID:      "test",
+			Service: "test",
+		},
+		chkTypes: nil,
+		persist:  false,
+		token:    "",
+		Source:   ConfigSourceLocal,
+	}
+	require.NoError(t, a.AddService(serviceReq))
+	checkID := serviceDrainingCheckID(structs.NewServiceID("test", nil))
+
+	// Start draining the service
+	req, _ := http.NewRequest("PUT", "/v1/agent/service/draining/test?enable=true&reason=deploy", nil)
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	// Ensure the passing draining check was registered
+	check := a.State.Check(checkID)
+	require.NotNil(t, check)
+	require.Equal(t, api.HealthPassing, check.Status)
+	require.Equal(t, "deploy", check.Notes)
+
+	// Stop draining the service
+	req, _ = http.NewRequest("PUT", "/v1/agent/service/draining/test?enable=false", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Nil(t, a.State.Check(checkID))
+
+	// Draining with a timeout is lifted once it expires
+	req, _ = http.NewRequest("PUT", "/v1/agent/service/draining/test?enable=true&timeout=100ms", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotNil(t, a.State.Check(checkID))
+
+	retry.Run(t, func(r *retry.R) {
+		if a.State.Check(checkID) != nil {
+			r.Fatal("should have removed draining check")
+		}
+	})
+}
+
+func TestAgent_ServiceDraining_SidecarProxy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	args := &structs.ServiceDefinition{
+		Name: "web",
+		Port: 8080,
+		Connect: &structs.ServiceConnect{
+			SidecarService: &structs.ServiceDefinition{},
+		},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/agent/service/register", jsonReader(args))
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	// The endpoints of the upstream clusters of the mesh are the instances of
+	// the sidecar proxy, which Envoy must see as draining.
+	connectNodes := func(r require.TestingT) structs.CheckServiceNodes {
+		req := structs.ServiceSpecificRequest{
+			Datacenter:  "dc1",
+			ServiceName: "web",
+			Connect:     true,
+		}
+		var out structs.IndexedCheckServiceNodes
+		require.NoError(r, a.RPC(context.Background(), "Health.ServiceNodes", &req, &out))
+		require.Len(r, out.Nodes, 1)
+		require.Equal(r, "web-sidecar-proxy", out.Nodes[0].Service.ID)
+		return out.Nodes
+	}
+	retry.Run(t, func(r *retry.R) {
+		require.False(r, connectNodes(r)[0].IsDraining())
+	})
+
+	req, _ = http.NewRequest("PUT", "/v1/agent/service/draining/web?enable=true", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotNil(t, a.State.Check(serviceDrainingCheckID(structs.NewServiceID("web-sidecar-proxy", nil))))
+
+	retry.Run(t, func(r *retry.R) {
+		require.True(r, connectNodes(r)[0].IsDraining())
+	})
+
+	req, _ = http.NewRequest("PUT", "/v1/agent/service/draining/web?enable=false", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Nil(t, a.State.Check(serviceDrainingCheckID(structs.NewServiceID("web-sidecar-proxy", nil))))
+
+	retry.Run(t, func(r *retry.R) {
+		require.False(r, connectNodes(r)[0].IsDraining())
+	})
+}
+
 func TestAgent_NodeMaintenance_BadRequest(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -8078,6 +8287,66 @@ func TestAgent_HostBadACL(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
 
+func TestAgent_ProxyDebug(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	proxy := &structs.NodeService{
+		Kind:    structs.ServiceKindConnectProxy,
+		ID:      "web-sidecar-proxy",
+		Service: "web-sidecar-proxy",
+		Port:    21000,
+		Proxy: structs.ConnectProxyConfig{
+			DestinationServiceName: "web",
+			DestinationServiceID:   "web",
+			LocalServicePort:       8080,
+		},
+	}
+	require.NoError(t, a.State.AddServiceWithChecks(proxy, nil, "", false))
+
+	retry.Run(t, func(r *retry.R) {
+		req, _ := http.NewRequest("GET", "/v1/agent/proxy/debug", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(r, http.StatusOK, resp.Code)
+		require.NotContains(r, resp.Body.String(), "PRIVATE KEY")
+
+		var proxies []*api.AgentProxyDebug
+		require.NoError(r, json.NewDecoder(resp.Body).Decode(&proxies))
+		require.Len(r, proxies, 1)
+		require.Equal(r, "web-sidecar-proxy", proxies[0].ProxyID.ID)
+		require.Equal(r, api.ServiceKindConnectProxy, proxies[0].Kind)
+		require.True(r, proxies[0].Ready)
+		require.Empty(r, proxies[0].Error)
+		require.NotNil(r, proxies[0].XDS)
+		require.NotEmpty(r, proxies[0].XDS.Resources)
+	})
+}
+
+func TestAgent_ProxyDebugBadACL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+	req, _ := http.NewRequest("GET", "/v1/agent/proxy/debug", nil)
+	resp := httptest.NewRecorder()
+	_, err := a.srv.AgentProxyDebug(resp, req)
+	require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+}
+
 // Thie tests that a proxy with an ExposeConfig is returned as expected.
 func TestAgent_Services_ExposeConfig(t *testing.T) {
 	if testing.Short() {
@@ -8128,3 +8397,32 @@ func TestAgent_Services_ExposeConfig(t *testing.T) {
 	}
 	require.Equal(t, srv1.Proxy.ToAPI(), actual.Proxy)
 }
+
+func TestMeshMetricsTargets(t *testing.T) {
+	proxy := func(id string, config map[string]interface{}) *structs.NodeService {
+		return &structs.NodeService{
+			Kind:    structs.ServiceKindConnectProxy,
+			ID:      id,
+			Service: id,
+			Proxy: structs.ConnectProxyConfig{
+				DestinationServiceName: strings.TrimSuffix(id, "-sidecar-proxy"),
+				Config:                 config,
+			},
+		}
+	}
+	services := map[structs.ServiceID]*structs.NodeService{}
+	for _, svc := range []*structs.NodeService{
+		proxy("web-sidecar-proxy", map[string]interface{}{"envoy_prometheus_bind_addr": "0.0.0.0:20200"}),
+		proxy("api-sidecar-proxy", map[string]interface{}{"envoy_stats_bind_addr": "10.0.0.1:20300"}),
+		proxy("db-sidecar-proxy", nil),
+		proxy("cache-sidecar-proxy", map[string]interface{}{"envoy_prometheus_bind_addr": "invalid"}),
+		{ID: "web", Service: "web"},
+	} {
+		services[svc.CompoundServiceID()] = svc
+	}
+
+	require.Equal(t, []connectmetrics.MeshTarget{
+		{ProxyID: "api-sidecar-proxy", Service: "api", URL: "http://10.0.0.1:20300/stats/prometheus"},
+		{ProxyID: "web-sidecar-proxy", Service: "web", URL: "http://127.0.0.1:20200/metrics"},
+	}, meshMetricsTargets(services))
+}
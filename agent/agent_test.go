@@ -197,6 +197,54 @@ func TestAgent_StartStop(t *testing.T) {
 	}
 }
 
+func TestAgent_Leave_DrainServices(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `leave_service_drain_time = "2s"`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	srv := &structs.NodeService{
+		ID:      "redis",
+		Service: "redis",
+		Port:    8000,
+	}
+	require.NoError(t, a.addServiceFromSource(srv, nil, true, "", ConfigSourceLocal))
+	sid := structs.NewServiceID("redis", nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a.Leave()
+	}()
+
+	// The service is draining, in the catalog too, while the agent leaves.
+	retry.Run(t, func(r *retry.R) {
+		req := structs.ServiceSpecificRequest{
+			Datacenter:  "dc1",
+			ServiceName: "redis",
+		}
+		var out structs.IndexedCheckServiceNodes
+		require.NoError(r, a.RPC(context.Background(), "Health.ServiceNodes", &req, &out))
+		require.Len(r, out.Nodes, 1)
+		require.True(r, out.Nodes[0].IsDraining())
+	})
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(30 * time.Second):
+		t.Fatal("timed out waiting for the agent to leave")
+	}
+
+	// The service is deregistered once drained, but kept for the next start.
+	require.Nil(t, a.State.Service(sid))
+	require.Nil(t, a.State.Check(serviceDrainingCheckID(sid)))
+	require.FileExists(t, a.makeServiceFilePath(sid))
+}
+
 func TestAgent_RPCPing(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4229,6 +4277,39 @@ func TestAgent_ReloadConfigRPCClientConfig(t *testing.T) {
 	require.Equal(t, 2*time.Minute, a.baseDeps.ConnPool.RPCClientTimeout())
 }
 
+func TestAgent_ReloadConfigHTTPLimits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	dataDir := testutil.TempDir(t, "agent") // we manage the data dir
+	hcl := `
+		data_dir = "` + dataDir + `"
+		server = false
+		bootstrap = false
+		limits {
+			kv_max_value_size = 1024
+		}
+	`
+	a := NewTestAgent(t, hcl)
+
+	require.Equal(t, uint64(1024), a.getHTTPLimits().kvMaxValueSize)
+
+	hcl = `
+		data_dir = "` + dataDir + `"
+		server = false
+		bootstrap = false
+		limits {
+			kv_max_value_size = 2048
+			txn_max_req_len = 4096
+		}
+	`
+	c := TestConfig(testutil.Logger(t), config.FileSource{Name: t.Name(), Format: "hcl", Data: hcl})
+	require.NoError(t, a.reloadConfigInternal(c))
+
+	require.Equal(t, httpLimits{kvMaxValueSize: 2048, txnMaxReqLen: 4096}, a.getHTTPLimits())
+}
+
 func TestAgent_consulConfig_RaftTrailingLogs(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4255,6 +4336,17 @@ func TestAgent_consulConfig_RequestLimits(t *testing.T) {
 				mode = "enforcing"
 				read_rate = 8888
 				write_rate = 9999
+				per_ip {
+					read_rate = 88
+					write_rate = 99
+				}
+				per_token {
+					read_rate = 8
+					override {
+						accessor_id = "ea3a9bde-5ea4-4b40-9c5b-cbf8b8d44dd5"
+						write_rate = 9
+					}
+				}
 			}
 		}
 	`
@@ -4263,6 +4355,15 @@ func TestAgent_consulConfig_RequestLimits(t *testing.T) {
 	require.Equal(t, "enforcing", a.consulConfig().RequestLimitsMode)
 	require.Equal(t, rate.Limit(8888), a.consulConfig().RequestLimitsReadRate)
 	require.Equal(t, rate.Limit(9999), a.consulConfig().RequestLimitsWriteRate)
+	require.Equal(t, rate.Limit(88), a.consulConfig().RequestLimitsIPReadRate)
+	require.Equal(t, rate.Limit(99), a.consulConfig().RequestLimitsIPWriteRate)
+	require.Equal(t, rate.Limit(8), a.consulConfig().RequestLimitsTokenReadRate)
+	require.Equal(t, rate.Inf, a.consulConfig().RequestLimitsTokenWriteRate)
+	require.Equal(t, []consul.RequestLimitsTokenOverride{{
+		AccessorID: "ea3a9bde-5ea4-4b40-9c5b-cbf8b8d44dd5",
+		ReadRate:   8,
+		WriteRate:  9,
+	}}, a.consulConfig().RequestLimitsTokenOverrides)
 }
 
 func TestAgent_grpcInjectAddr(t *testing.T) {
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/consul/agent/audit"
+	middleware "github.com/hashicorp/consul/agent/grpc-middleware"
+)
+
+// auditAccessorID returns the accessor of the token presented with a request
+// for the audit log. It is empty when ACLs are disabled.
+func (a *Agent) auditAccessorID(secretID string) string {
+	if !a.config.ACLsEnabled || secretID == "" {
+		return ""
+	}
+	return a.aclAccessorID(secretID)
+}
+
+// auditInterceptor returns the interceptors recording the calls to the
+// external gRPC server, or nil if the audit log is disabled.
+func (a *Agent) auditInterceptor() *middleware.AuditInterceptor {
+	if a.auditor == nil {
+		return nil
+	}
+	return &middleware.AuditInterceptor{
+		Auditor:    a.auditor,
+		AccessorID: a.auditAccessorID,
+	}
+}
+
+// auditResponseWriter captures the status of the response to an HTTP request
+// so that it can be recorded in the audit log.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for the streaming endpoints.
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// recordAudit records an HTTP request once its response was written. As for
+// the token usage, the address is the one of the peer since X-Forwarded-For
+// can't be trusted.
+func (s *HTTPHandlers) recordAudit(req *http.Request, resp *auditResponseWriter) {
+	code := resp.status
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	var token string
+	s.parseTokenInternal(req, &token)
+
+	addr, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		addr = req.RemoteAddr
+	}
+
+	s.agent.auditor.Record(&audit.Event{
+		Protocol: audit.ProtocolHTTP,
+		Actor: audit.Actor{
+			AccessorID: s.agent.auditAccessorID(token),
+			RemoteAddr: addr,
+		},
+		Operation: audit.OperationForHTTPMethod(req.Method),
+		Method:    req.Method,
+		Resource:  req.URL.Path,
+		Result:    audit.ResultForHTTPStatus(code),
+	})
+}
@@ -0,0 +1,281 @@
+// Package audit records the operations performed through the HTTP and gRPC
+// APIs of the agent so that operators can tell who did what and when.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+
+	OperationRead  = "read"
+	OperationWrite = "write"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+
+	SinkTypeFile   = "file"
+	SinkTypeSyslog = "syslog"
+
+	FormatJSON = "json"
+
+	DeliveryGuaranteeBestEffort = "best-effort"
+
+	FilterActionInclude = "include"
+	FilterActionExclude = "exclude"
+)
+
+// Config is the configuration of the audit log.
+type Config struct {
+	// Enabled turns on the recording of the API operations.
+	Enabled bool
+
+	// Sinks are the destinations the events are written to.
+	Sinks []SinkConfig
+
+	// Filters select the events that are written to the sinks. They are
+	// evaluated in order and the first one matching an event decides whether
+	// it is recorded. Events that match no filter are recorded.
+	Filters []FilterRule
+}
+
+// SinkConfig is the configuration of a destination of the audit log.
+type SinkConfig struct {
+	// Name identifies the sink in the logs.
+	Name string
+
+	// Type is either "file" or "syslog".
+	Type string
+
+	// Format is the encoding of the events, only "json" is supported.
+	Format string
+
+	// DeliveryGuarantee is how hard the sink tries to write the events, only
+	// "best-effort" is supported: the events that can't be written are
+	// dropped and an error is logged.
+	DeliveryGuarantee string
+
+	// Path is where a file sink writes the events. As for the agent's log
+	// file, the time of creation of each file is appended to its name.
+	Path string
+
+	// Mode is the permissions of the files created by a file sink.
+	Mode os.FileMode
+
+	// RotateBytes is the size at which a file sink rotates its file. Zero
+	// disables the rotation based on the size.
+	RotateBytes int
+
+	// RotateDuration is how often a file sink rotates its file.
+	RotateDuration time.Duration
+
+	// RotateMaxFiles is the number of rotated files a file sink keeps. Zero
+	// keeps all the files and -1 removes them all.
+	RotateMaxFiles int
+
+	// SyslogFacility is the facility the events are written to by a syslog
+	// sink.
+	SyslogFacility string
+
+	// SyslogTag is the tag of the events written by a syslog sink.
+	SyslogTag string
+}
+
+// Validate checks that the configuration can be used to create a Logger.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("at least one sink must be configured when the audit log is enabled")
+	}
+
+	var merr error
+	for _, s := range c.Sinks {
+		if err := s.validate(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("sink %q: %w", s.Name, err))
+		}
+	}
+	for i, f := range c.Filters {
+		if err := f.validate(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("filter[%d]: %w", i, err))
+		}
+	}
+	return merr
+}
+
+func (s *SinkConfig) validate() error {
+	switch s.Type {
+	case SinkTypeFile:
+		if s.Path == "" {
+			return fmt.Errorf("path is required for a %q sink", s.Type)
+		}
+	case SinkTypeSyslog:
+	default:
+		return fmt.Errorf("invalid type %q, must be %q or %q", s.Type, SinkTypeFile, SinkTypeSyslog)
+	}
+	if s.Format != FormatJSON {
+		return fmt.Errorf("invalid format %q, must be %q", s.Format, FormatJSON)
+	}
+	if s.DeliveryGuarantee != DeliveryGuaranteeBestEffort {
+		return fmt.Errorf("invalid delivery_guarantee %q, must be %q", s.DeliveryGuarantee, DeliveryGuaranteeBestEffort)
+	}
+	if s.RotateBytes < 0 {
+		return fmt.Errorf("rotate_bytes cannot be negative")
+	}
+	if s.RotateDuration < 0 {
+		return fmt.Errorf("rotate_duration cannot be negative")
+	}
+	return nil
+}
+
+// Event is an operation performed through the API.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Protocol  string    `json:"protocol"`
+	Actor     Actor     `json:"actor"`
+
+	// Operation is either "read" or "write".
+	Operation string `json:"operation"`
+
+	// Method is the HTTP method of the request, it is empty for the gRPC
+	// calls.
+	Method string `json:"method,omitempty"`
+
+	// Resource is the path of the HTTP request or the full name of the gRPC
+	// method.
+	Resource string `json:"resource"`
+
+	Result Result `json:"result"`
+}
+
+// Actor is who performed an operation.
+type Actor struct {
+	// AccessorID is the accessor of the ACL token presented with the request,
+	// it is empty if no token was presented or ACLs are disabled.
+	AccessorID string `json:"accessor_id,omitempty"`
+
+	// RemoteAddr is the address of the peer the request came from.
+	RemoteAddr string `json:"remote_addr,omitempty"`
+}
+
+// Result is the outcome of an operation.
+type Result struct {
+	// Status is one of "success", "failure" or "denied".
+	Status string `json:"status"`
+
+	// Code is the HTTP status or the gRPC status code of the response.
+	Code int `json:"code"`
+}
+
+// Logger writes the events to the configured sinks.
+type Logger struct {
+	logger  hclog.Logger
+	filters []FilterRule
+	sinks   []sink
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// New creates a Logger from the given configuration, which must have been
+// validated.
+func New(config Config, logger hclog.Logger) (*Logger, error) {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	l := &Logger{
+		logger:  logger,
+		filters: config.Filters,
+	}
+	for _, sc := range config.Sinks {
+		s, err := newSink(sc)
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to create audit sink %q: %w", sc.Name, err)
+		}
+		l.sinks = append(l.sinks, s)
+	}
+	return l, nil
+}
+
+// Record writes the event to the sinks unless it is excluded by the filters.
+// The sinks are best-effort, so the errors are logged rather than returned.
+func (l *Logger) Record(ev *Event) {
+	if !Match(l.filters, ev) {
+		return
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+
+	buf, err := json.Marshal(ev)
+	if err != nil {
+		l.logger.Error("failed to encode audit event", "error", err)
+		return
+	}
+	buf = append(buf, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+	for _, s := range l.sinks {
+		if _, err := s.Write(buf); err != nil {
+			l.logger.Error("failed to write audit event", "sink", s.name(), "error", err)
+		}
+	}
+}
+
+// Close closes the sinks. The events recorded afterwards are dropped.
+func (l *Logger) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	var merr error
+	for _, s := range l.sinks {
+		if err := s.Close(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("sink %q: %w", s.name(), err))
+		}
+	}
+	return merr
+}
+
+// ResultForHTTPStatus classifies the outcome of an HTTP request.
+func ResultForHTTPStatus(code int) Result {
+	status := ResultSuccess
+	switch {
+	case code == 401 || code == 403:
+		status = ResultDenied
+	case code >= 400:
+		status = ResultFailure
+	}
+	return Result{Status: status, Code: code}
+}
+
+// OperationForHTTPMethod returns the kind of operation performed by a request
+// with the given HTTP method.
+func OperationForHTTPMethod(method string) string {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return OperationRead
+	default:
+		return OperationWrite
+	}
+}
@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func fileSink(path string) SinkConfig {
+	return SinkConfig{
+		Name:              "file",
+		Type:              SinkTypeFile,
+		Format:            FormatJSON,
+		DeliveryGuarantee: DeliveryGuaranteeBestEffort,
+		Path:              path,
+		RotateDuration:    time.Hour,
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := map[string]struct {
+		config    Config
+		expectErr string
+	}{
+		"disabled": {
+			config: Config{Sinks: []SinkConfig{{Name: "bad"}}},
+		},
+		"valid": {
+			config: Config{
+				Enabled: true,
+				Sinks:   []SinkConfig{fileSink("/tmp/audit.json")},
+				Filters: []FilterRule{{Action: FilterActionExclude, Operations: []string{OperationRead}, Resources: []string{"/v1/agent/*"}}},
+			},
+		},
+		"no sinks": {
+			config:    Config{Enabled: true},
+			expectErr: "at least one sink must be configured",
+		},
+		"invalid type": {
+			config: Config{Enabled: true, Sinks: []SinkConfig{{
+				Name: "s", Type: "kafka", Format: FormatJSON, DeliveryGuarantee: DeliveryGuaranteeBestEffort,
+			}}},
+			expectErr: `sink "s": invalid type "kafka"`,
+		},
+		"missing path": {
+			config:    Config{Enabled: true, Sinks: []SinkConfig{fileSink("")}},
+			expectErr: "path is required",
+		},
+		"invalid format": {
+			config: Config{Enabled: true, Sinks: []SinkConfig{{
+				Name: "s", Type: SinkTypeSyslog, Format: "xml", DeliveryGuarantee: DeliveryGuaranteeBestEffort,
+			}}},
+			expectErr: `invalid format "xml"`,
+		},
+		"invalid delivery guarantee": {
+			config: Config{Enabled: true, Sinks: []SinkConfig{{
+				Name: "s", Type: SinkTypeSyslog, Format: FormatJSON, DeliveryGuarantee: "enforced",
+			}}},
+			expectErr: `invalid delivery_guarantee "enforced"`,
+		},
+		"invalid filter action": {
+			config: Config{
+				Enabled: true,
+				Sinks:   []SinkConfig{fileSink("/tmp/audit.json")},
+				Filters: []FilterRule{{Action: "drop"}},
+			},
+			expectErr: `filter[0]: invalid action "drop"`,
+		},
+		"invalid filter result": {
+			config: Config{
+				Enabled: true,
+				Sinks:   []SinkConfig{fileSink("/tmp/audit.json")},
+				Filters: []FilterRule{{Action: FilterActionInclude, Results: []string{"ok"}}},
+			},
+			expectErr: `invalid result "ok"`,
+		},
+		"invalid filter resource": {
+			config: Config{
+				Enabled: true,
+				Sinks:   []SinkConfig{fileSink("/tmp/audit.json")},
+				Filters: []FilterRule{{Action: FilterActionInclude, Resources: []string{"/v1/*/members"}}},
+			},
+			expectErr: `"*" is only allowed at the end`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			testutil.RequireErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rules := []FilterRule{
+		{Action: FilterActionInclude, Results: []string{ResultDenied}},
+		{Action: FilterActionExclude, Operations: []string{OperationRead}, Resources: []string{"/v1/agent/*", "/v1/status/leader"}},
+		{Action: FilterActionExclude, Resources: []string{"/hashicorp.consul.dns.DNSService/Query"}},
+	}
+
+	event := func(op, resource, status string) *Event {
+		return &Event{Operation: op, Resource: resource, Result: Result{Status: status}}
+	}
+
+	require.True(t, Match(nil, event(OperationRead, "/v1/agent/self", ResultSuccess)))
+	require.False(t, Match(rules, event(OperationRead, "/v1/agent/self", ResultSuccess)))
+	require.False(t, Match(rules, event(OperationRead, "/v1/status/leader", ResultSuccess)))
+	require.True(t, Match(rules, event(OperationRead, "/v1/status/peers", ResultSuccess)))
+	require.True(t, Match(rules, event(OperationWrite, "/v1/agent/service/register", ResultSuccess)))
+	require.True(t, Match(rules, event(OperationRead, "/v1/agent/self", ResultDenied)))
+	require.False(t, Match(rules, event(OperationRead, "/hashicorp.consul.dns.DNSService/Query", ResultFailure)))
+}
+
+func TestResultForHTTPStatus(t *testing.T) {
+	require.Equal(t, Result{Status: ResultSuccess, Code: 200}, ResultForHTTPStatus(200))
+	require.Equal(t, Result{Status: ResultSuccess, Code: 304}, ResultForHTTPStatus(304))
+	require.Equal(t, Result{Status: ResultDenied, Code: 403}, ResultForHTTPStatus(403))
+	require.Equal(t, Result{Status: ResultDenied, Code: 401}, ResultForHTTPStatus(401))
+	require.Equal(t, Result{Status: ResultFailure, Code: 404}, ResultForHTTPStatus(404))
+	require.Equal(t, Result{Status: ResultFailure, Code: 500}, ResultForHTTPStatus(500))
+}
+
+func TestLogger_FileSink(t *testing.T) {
+	dir := testutil.TempDir(t, "audit")
+	config := fileSink(filepath.Join(dir, "audit.json"))
+	config.RotateBytes = 1
+
+	l, err := New(Config{
+		Enabled: true,
+		Sinks:   []SinkConfig{config},
+		Filters: []FilterRule{{Action: FilterActionExclude, Resources: []string{"/v1/status/*"}}},
+	}, nil)
+	require.NoError(t, err)
+
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.Record(&Event{
+		Timestamp: ts,
+		Protocol:  ProtocolHTTP,
+		Actor:     Actor{AccessorID: "2b778dd9-f5f1-6f29-b4b4-9a5fa948757a", RemoteAddr: "10.0.0.1"},
+		Operation: OperationWrite,
+		Method:    "PUT",
+		Resource:  "/v1/kv/foo",
+		Result:    Result{Status: ResultSuccess, Code: 200},
+	})
+	l.Record(&Event{Protocol: ProtocolHTTP, Operation: OperationRead, Resource: "/v1/status/leader"})
+	l.Record(&Event{
+		Protocol:  ProtocolGRPC,
+		Operation: OperationRead,
+		Resource:  "/hashicorp.consul.dns.DNSService/Query",
+		Result:    Result{Status: ResultDenied, Code: 7},
+	})
+	require.NoError(t, l.Close())
+
+	// Dropped once closed.
+	l.Record(&Event{Protocol: ProtocolHTTP, Resource: "/v1/kv/bar"})
+
+	// Each event went to its own file since the sink rotates after each
+	// write.
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var events []Event
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var ev Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+			events = append(events, ev)
+		}
+		require.NoError(t, scanner.Err())
+		f.Close()
+	}
+	require.Len(t, events, 2)
+
+	byResource := map[string]Event{}
+	for _, ev := range events {
+		byResource[ev.Resource] = ev
+	}
+	require.Equal(t, Event{
+		Timestamp: ts,
+		Protocol:  ProtocolHTTP,
+		Actor:     Actor{AccessorID: "2b778dd9-f5f1-6f29-b4b4-9a5fa948757a", RemoteAddr: "10.0.0.1"},
+		Operation: OperationWrite,
+		Method:    "PUT",
+		Resource:  "/v1/kv/foo",
+		Result:    Result{Status: ResultSuccess, Code: 200},
+	}, byResource["/v1/kv/foo"])
+
+	grpcEvent := byResource["/hashicorp.consul.dns.DNSService/Query"]
+	require.False(t, grpcEvent.Timestamp.IsZero())
+	require.Equal(t, ProtocolGRPC, grpcEvent.Protocol)
+	require.Equal(t, Result{Status: ResultDenied, Code: 7}, grpcEvent.Result)
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterRule selects the events to include in or exclude from the audit log.
+// An event matches a rule when it matches each of its non-empty criteria.
+type FilterRule struct {
+	// Action is either "include" or "exclude".
+	Action string
+
+	// Operations are the kinds of operation matched: "read" or "write".
+	Operations []string
+
+	// Resources are the HTTP paths or gRPC methods matched. A trailing "*"
+	// matches any resource with the given prefix.
+	Resources []string
+
+	// Results are the outcomes matched: "success", "failure" or "denied".
+	Results []string
+}
+
+func (f *FilterRule) validate() error {
+	if f.Action != FilterActionInclude && f.Action != FilterActionExclude {
+		return fmt.Errorf("invalid action %q, must be %q or %q", f.Action, FilterActionInclude, FilterActionExclude)
+	}
+	for _, op := range f.Operations {
+		if op != OperationRead && op != OperationWrite {
+			return fmt.Errorf("invalid operation %q, must be %q or %q", op, OperationRead, OperationWrite)
+		}
+	}
+	for _, r := range f.Results {
+		if r != ResultSuccess && r != ResultFailure && r != ResultDenied {
+			return fmt.Errorf("invalid result %q, must be %q, %q or %q", r, ResultSuccess, ResultFailure, ResultDenied)
+		}
+	}
+	for _, r := range f.Resources {
+		if strings.Contains(strings.TrimSuffix(r, "*"), "*") {
+			return fmt.Errorf("invalid resource %q, \"*\" is only allowed at the end", r)
+		}
+	}
+	return nil
+}
+
+func (f *FilterRule) matches(ev *Event) bool {
+	if len(f.Operations) > 0 && !containsString(f.Operations, ev.Operation) {
+		return false
+	}
+	if len(f.Results) > 0 && !containsString(f.Results, ev.Result.Status) {
+		return false
+	}
+	if len(f.Resources) == 0 {
+		return true
+	}
+	for _, r := range f.Resources {
+		if prefix := strings.TrimSuffix(r, "*"); prefix != r {
+			if strings.HasPrefix(ev.Resource, prefix) {
+				return true
+			}
+		} else if r == ev.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the event should be recorded according to the rules:
+// the first rule matching the event decides, and events matching no rule are
+// recorded.
+func Match(rules []FilterRule, ev *Event) bool {
+	for _, rule := range rules {
+		if rule.matches(ev) {
+			return rule.Action == FilterActionInclude
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
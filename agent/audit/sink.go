@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+
+	gsyslog "github.com/hashicorp/go-syslog"
+
+	"github.com/hashicorp/consul/logging"
+)
+
+const defaultSyslogTag = "consul-audit"
+
+// sink is a destination of the audit log. Writes are serialized by the
+// Logger.
+type sink interface {
+	io.WriteCloser
+	name() string
+}
+
+func newSink(config SinkConfig) (sink, error) {
+	switch config.Type {
+	case SinkTypeFile:
+		f := logging.NewLogFile(config.Path, config.Mode, config.RotateDuration, config.RotateBytes, config.RotateMaxFiles)
+		return &namedSink{WriteCloser: f, sinkName: config.Name}, nil
+
+	case SinkTypeSyslog:
+		tag := config.SyslogTag
+		if tag == "" {
+			tag = defaultSyslogTag
+		}
+		facility := config.SyslogFacility
+		if facility == "" {
+			facility = "LOCAL0"
+		}
+		l, err := gsyslog.NewLogger(gsyslog.LOG_INFO, facility, tag)
+		if err != nil {
+			return nil, err
+		}
+		return &namedSink{WriteCloser: l, sinkName: config.Name}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", config.Type)
+	}
+}
+
+type namedSink struct {
+	io.WriteCloser
+	sinkName string
+}
+
+func (s *namedSink) name() string { return s.sinkName }
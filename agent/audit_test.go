@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestHTTPHandlers_Audit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir := testutil.TempDir(t, "audit")
+	a := NewTestAgent(t, TestACLConfig()+`
+		audit {
+			enabled = true
+			sink "main" {
+				type = "file"
+				path = "`+filepath.Join(dir, "audit.json")+`"
+			}
+			filter {
+				action = "exclude"
+				resources = ["/v1/status/*"]
+			}
+		}
+	`)
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	req, _ := http.NewRequest("PUT", "/v1/kv/foo", strings.NewReader("bar"))
+	req.RemoteAddr = "10.0.0.1:12345"
+	req.Header.Add("X-Consul-Token", "root")
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/v1/kv/foo", nil)
+	req.RemoteAddr = "10.0.0.2:12345"
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	// Excluded by the filter.
+	req, _ = http.NewRequest("GET", "/v1/status/leader", nil)
+	a.srv.h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Flush the events.
+	require.NoError(t, a.Shutdown())
+
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []audit.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev audit.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		if ev.Actor.RemoteAddr == "10.0.0.1" || ev.Actor.RemoteAddr == "10.0.0.2" {
+			events = append(events, ev)
+		}
+		require.NotEqual(t, "/v1/status/leader", ev.Resource)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 2)
+
+	require.Equal(t, audit.ProtocolHTTP, events[0].Protocol)
+	require.Equal(t, "/v1/kv/foo", events[0].Resource)
+	require.Equal(t, "PUT", events[0].Method)
+	require.Equal(t, audit.OperationWrite, events[0].Operation)
+	require.NotEmpty(t, events[0].Actor.AccessorID)
+	require.Equal(t, audit.Result{Status: audit.ResultSuccess, Code: http.StatusOK}, events[0].Result)
+
+	require.Equal(t, audit.OperationRead, events[1].Operation)
+	require.Empty(t, events[1].Actor.AccessorID)
+	require.Equal(t, audit.Result{Status: audit.ResultDenied, Code: http.StatusForbidden}, events[1].Result)
+}
@@ -53,6 +53,11 @@ type AutoConfig struct {
 	// events from the token store when the Agent
 	// token is updated.
 	tokenUpdates token.Notifier
+
+	// vault is the client used to obtain the agent
+	// certificates from Vault, when they are not
+	// issued by the servers.
+	vault *vaultClient
 }
 
 // New creates a new AutoConfig object for providing automatic Consul configuration.
@@ -162,6 +167,13 @@ func (ac *AutoConfig) InitialConfiguration(ctx context.Context) (*config.Runtime
 
 		ac.config = config
 		return ac.config, nil
+	case ac.vaultEnabled():
+		if err := ac.vaultInitialCerts(ctx); err != nil {
+			return nil, err
+		}
+
+		ac.logger.Info("automatically upgraded to TLS with a certificate issued by Vault")
+		return ac.config, nil
 	case ac.config.AutoEncryptTLS:
 		certs, err := ac.autoEncryptInitialCerts(ctx)
 		if err != nil {
@@ -366,11 +378,16 @@ func (ac *AutoConfig) Start(ctx context.Context) error {
 	// really we should only ever get 10 updates
 	ac.cacheUpdates = make(chan cache.UpdateEvent, 10)
 
-	// setup the cache watches
-	cancelCertWatches, err := ac.setupCertificateCacheWatches(ctx)
-	if err != nil {
-		cancel()
-		return fmt.Errorf("error setting up cache watches: %w", err)
+	// setup the cache watches, unless the certificates are issued by Vault
+	// in which case they are renewed by the run loop.
+	cancelCertWatches := context.CancelFunc(func() {})
+	if !ac.vaultEnabled() {
+		var err error
+		cancelCertWatches, err = ac.setupCertificateCacheWatches(ctx)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("error setting up cache watches: %w", err)
+		}
 	}
 
 	// start the token update notifier
@@ -45,6 +45,11 @@ func (ac *AutoConfig) handleCacheEvent(u cache.UpdateEvent) error {
 // handleTokenUpdate is used when a notification about the agent token being updated
 // is received and various watches need cancelling/restarting to use the new token.
 func (ac *AutoConfig) handleTokenUpdate(ctx context.Context) error {
+	// the agent token is not used to obtain the certificates issued by Vault
+	if ac.vaultEnabled() {
+		return nil
+	}
+
 	ac.logger.Debug("Agent token updated - resetting watches")
 
 	// TODO (autoencrypt) Prepopulate the cache with the new token with
@@ -93,6 +98,11 @@ func (ac *AutoConfig) handleFallback(ctx context.Context) error {
 		}
 
 		return ac.recordInitialConfiguration(resp)
+	case ac.vaultEnabled():
+		if err := ac.renewVaultCerts(ctx); err != nil {
+			return fmt.Errorf("error while retrieving new agent certificate from Vault: %w", err)
+		}
+		return nil
 	case ac.config.AutoEncryptTLS:
 		reply, err := ac.autoEncryptInitialCerts(ctx)
 		if err != nil {
@@ -130,12 +140,27 @@ func (ac *AutoConfig) run(ctx context.Context, exit chan struct{}) {
 	}
 	fallbackTimer := time.NewTimer(calcFallbackInterval())
 
+	// The certificates issued by Vault are not watched with the cache,
+	// they are renewed and the CA certificates of Vault are refreshed
+	// when these timers fire.
+	vaultRenewTimer := time.NewTimer(vaultCARefreshInterval)
+	vaultCATimer := time.NewTimer(vaultCARefreshInterval)
+	if ac.vaultEnabled() {
+		vaultRenewTimer.Stop()
+		vaultRenewTimer = time.NewTimer(ac.vaultRenewInterval())
+	} else {
+		vaultRenewTimer.Stop()
+		vaultCATimer.Stop()
+	}
+
 	// cleanup for once we are stopped
 	defer func() {
 		// cancel the go routines performing the cache watches
 		ac.cancelWatches()
 		// ensure we don't leak the timers go routine
 		fallbackTimer.Stop()
+		vaultRenewTimer.Stop()
+		vaultCATimer.Stop()
 		// stop receiving notifications for token updates
 		ac.acConfig.Tokens.StopNotify(ac.tokenUpdates)
 
@@ -171,6 +196,22 @@ func (ac *AutoConfig) run(ctx context.Context, exit chan struct{}) {
 			// reset the fallback timer as the certificate may have been updated
 			fallbackTimer.Stop()
 			fallbackTimer = time.NewTimer(calcFallbackInterval())
+		case <-vaultRenewTimer.C:
+			if err := ac.renewVaultCerts(ctx); err != nil {
+				ac.logger.Error("failed to renew the agent certificate with Vault", "error", err)
+				vaultRenewTimer.Reset(ac.acConfig.FallbackRetry)
+			} else {
+				ac.logger.Debug("renewed the agent certificate with Vault")
+				vaultRenewTimer.Reset(ac.vaultRenewInterval())
+			}
+
+			fallbackTimer.Stop()
+			fallbackTimer = time.NewTimer(calcFallbackInterval())
+		case <-vaultCATimer.C:
+			if err := ac.refreshVaultCA(ctx); err != nil {
+				ac.logger.Error("failed to refresh the CA certificates of Vault", "error", err)
+			}
+			vaultCATimer.Reset(vaultCARefreshInterval)
 		case <-fallbackTimer.C:
 			// This is a safety net in case the cert doesn't get renewed
 			// in time. The agent would be stuck in that case because the watches
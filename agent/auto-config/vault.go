@@ -0,0 +1,280 @@
+package autoconf
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/consul/agent/config"
+)
+
+const defaultVaultPKIPath = "pki"
+
+// vaultCARefreshInterval is the interval between two refreshes of the CA
+// certificates of Vault, so that the certificates of a new issuer are
+// trusted soon after it is added, before the servers use them.
+var vaultCARefreshInterval = time.Hour
+
+// vaultClient issues the agent certificates with the PKI secrets engine of
+// Vault.
+type vaultClient struct {
+	client *vaultapi.Client
+	cfg    config.AutoEncryptVaultConfig
+
+	// chain is the CA chain of the last certificate issued.
+	chain []string
+}
+
+func newVaultClient(cfg config.AutoEncryptVaultConfig) (*vaultClient, error) {
+	conf := vaultapi.DefaultConfig()
+	conf.Address = cfg.Address
+	err := conf.ConfigureTLS(&vaultapi.TLSConfig{
+		CACert:        cfg.CAFile,
+		CAPath:        cfg.CAPath,
+		TLSServerName: cfg.TLSServerName,
+		Insecure:      cfg.TLSSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure the TLS of the Vault client: %w", err)
+	}
+
+	client, err := vaultapi.NewClient(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Vault client: %w", err)
+	}
+	if cfg.Namespace != "" {
+		client.SetNamespace(cfg.Namespace)
+	}
+	if cfg.Token != "" {
+		client.SetToken(cfg.Token)
+	}
+	if cfg.PKIPath == "" {
+		cfg.PKIPath = defaultVaultPKIPath
+	}
+	return &vaultClient{client: client, cfg: cfg}, nil
+}
+
+// vaultCert is a certificate issued by Vault.
+type vaultCert struct {
+	CertPEM       string
+	PrivateKeyPEM string
+}
+
+// setToken reads the token file before each request, as it can be renewed
+// by a Vault agent.
+func (c *vaultClient) setToken() error {
+	if c.cfg.TokenFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.cfg.TokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the Vault token file: %w", err)
+	}
+	c.client.SetToken(strings.TrimSpace(string(data)))
+	return nil
+}
+
+// issue issues a certificate and its private key.
+func (c *vaultClient) issue(ctx context.Context, commonName string, dnsSANs []string, ipSANs []net.IP) (*vaultCert, error) {
+	if err := c.setToken(); err != nil {
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(ipSANs))
+	for _, ip := range ipSANs {
+		ips = append(ips, ip.String())
+	}
+	data := map[string]interface{}{
+		"common_name": commonName,
+		"alt_names":   strings.Join(dnsSANs, ","),
+		"ip_sans":     strings.Join(ips, ","),
+	}
+	if c.cfg.TTL > 0 {
+		data["ttl"] = c.cfg.TTL.String()
+	}
+
+	secret, err := c.client.Logical().WriteWithContext(ctx, path.Join(c.cfg.PKIPath, "issue", c.cfg.Role), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue the certificate: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("failed to issue the certificate: empty response")
+	}
+
+	certPEM, _ := secret.Data["certificate"].(string)
+	keyPEM, _ := secret.Data["private_key"].(string)
+	if certPEM == "" || keyPEM == "" {
+		return nil, fmt.Errorf("failed to issue the certificate: the certificate or its private key is missing")
+	}
+
+	c.chain = vaultStrings(secret.Data["ca_chain"])
+	if ca, _ := secret.Data["issuing_ca"].(string); ca != "" && len(c.chain) == 0 {
+		c.chain = []string{ca}
+	}
+	return &vaultCert{CertPEM: certPEM, PrivateKeyPEM: keyPEM}, nil
+}
+
+// caPEMs returns the certificates of all the issuers of the PKI secrets
+// engine, as well as the CA chain of the last certificate issued. All the
+// issuers are trusted so that the certificates issued by a new issuer are
+// accepted while the CA is rotated.
+func (c *vaultClient) caPEMs(ctx context.Context) ([]string, error) {
+	if err := c.setToken(); err != nil {
+		return nil, err
+	}
+
+	pems := append([]string{}, c.chain...)
+
+	secret, err := c.client.Logical().ListWithContext(ctx, path.Join(c.cfg.PKIPath, "issuers"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the issuers: %w", err)
+	}
+	var issuers []string
+	if secret != nil {
+		issuers = vaultStrings(secret.Data["keys"])
+	}
+	for _, issuer := range issuers {
+		secret, err := c.client.Logical().ReadWithContext(ctx, path.Join(c.cfg.PKIPath, "issuer", issuer, "json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the issuer %q: %w", issuer, err)
+		}
+		if secret == nil {
+			continue
+		}
+		if cert, _ := secret.Data["certificate"].(string); cert != "" {
+			pems = append(pems, cert)
+		}
+		pems = append(pems, vaultStrings(secret.Data["ca_chain"])...)
+	}
+
+	// Deduplicate the certificates, which are part of several chains.
+	seen := make(map[string]struct{}, len(pems))
+	unique := pems[:0]
+	for _, pem := range pems {
+		pem = strings.TrimSpace(pem)
+		if _, ok := seen[pem]; ok {
+			continue
+		}
+		seen[pem] = struct{}{}
+		unique = append(unique, pem)
+	}
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("no CA certificates found in %q", c.cfg.PKIPath)
+	}
+	return unique, nil
+}
+
+// vaultStrings returns the strings of a list in the data of a Vault secret.
+func vaultStrings(v interface{}) []string {
+	items, _ := v.([]interface{})
+	var strs []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// vaultEnabled returns true if the agent certificates are issued by Vault
+// rather than by the servers.
+func (ac *AutoConfig) vaultEnabled() bool {
+	return ac.config.AutoEncryptTLS && ac.config.AutoEncryptVault.Address != ""
+}
+
+// vaultInitialCerts obtains the initial agent certificate from Vault,
+// retrying until it succeeds or ctx is cancelled.
+func (ac *AutoConfig) vaultInitialCerts(ctx context.Context) error {
+	ac.acConfig.Waiter.Reset()
+	for {
+		err := ac.renewVaultCerts(ctx)
+		if err == nil {
+			return nil
+		}
+		ac.logger.Error("failed to obtain the agent certificate from Vault", "error", err)
+
+		if err := ac.acConfig.Waiter.Wait(ctx); err != nil {
+			ac.logger.Info("interrupted during retrieval of the Vault certificates", "err", err)
+			return err
+		}
+	}
+}
+
+// renewVaultCerts obtains a new agent certificate from Vault, and updates
+// the TLS configurator with it and with the CA certificates of Vault.
+func (ac *AutoConfig) renewVaultCerts(ctx context.Context) error {
+	client, err := ac.getVaultClient()
+	if err != nil {
+		return err
+	}
+
+	cert, err := client.issue(ctx, ac.vaultCommonName(), ac.getDNSSANs(), ac.getIPSANs())
+	if err != nil {
+		return err
+	}
+	pems, err := client.caPEMs(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The server hostnames are verified according to the configuration of
+	// the agent, as there is no server to tell.
+	if err := ac.acConfig.TLSConfigurator.UpdateAutoTLS(nil, pems, cert.CertPEM, cert.PrivateKeyPEM, false); err != nil {
+		return fmt.Errorf("failed to update the TLS configurator with the Vault certificates: %w", err)
+	}
+	return nil
+}
+
+// refreshVaultCA updates the CA certificates trusted by the agent with the
+// issuers of Vault.
+func (ac *AutoConfig) refreshVaultCA(ctx context.Context) error {
+	client, err := ac.getVaultClient()
+	if err != nil {
+		return err
+	}
+	pems, err := client.caPEMs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ac.acConfig.TLSConfigurator.UpdateAutoTLSCA(pems); err != nil {
+		return fmt.Errorf("failed to update the Vault CA certificates: %w", err)
+	}
+	return nil
+}
+
+func (ac *AutoConfig) getVaultClient() (*vaultClient, error) {
+	if ac.vault == nil {
+		client, err := newVaultClient(ac.config.AutoEncryptVault)
+		if err != nil {
+			return nil, err
+		}
+		ac.vault = client
+	}
+	return ac.vault, nil
+}
+
+// vaultCommonName is the common name of the agent certificates, the same as
+// the one of the client certificates created by "consul tls cert create".
+func (ac *AutoConfig) vaultCommonName() string {
+	return fmt.Sprintf("client.%s.%s", ac.config.Datacenter, strings.TrimSuffix(ac.config.DNSDomain, "."))
+}
+
+// vaultRenewInterval returns the time until the agent certificate should be
+// renewed, at a random point between 60% and 90% of its lifetime so that
+// the agents don't all renew their certificates at once.
+func (ac *AutoConfig) vaultRenewInterval() time.Duration {
+	cert := ac.acConfig.TLSConfigurator.AutoEncryptCert()
+	if cert == nil {
+		return 0
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	renewAt := cert.NotBefore.Add(time.Duration(float64(lifetime) * (0.6 + 0.3*rand.Float64())))
+	return time.Until(renewAt)
+}
@@ -0,0 +1,196 @@
+package autoconf
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+// fakeVault implements the endpoints of the PKI secrets engine of Vault used
+// by the vaultClient.
+type fakeVault struct {
+	t       *testing.T
+	certPEM string
+	keyPEM  string
+	chain   []string
+	issuers map[string]string
+
+	lock     sync.Mutex
+	tokens   []string
+	requests []map[string]interface{}
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.lock.Lock()
+	v.tokens = append(v.tokens, r.Header.Get("X-Vault-Token"))
+	v.lock.Unlock()
+
+	var data map[string]interface{}
+	switch {
+	case r.URL.Path == "/v1/pki/issue/consul-client" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		var req map[string]interface{}
+		require.NoError(v.t, json.NewDecoder(r.Body).Decode(&req))
+		v.lock.Lock()
+		v.requests = append(v.requests, req)
+		v.lock.Unlock()
+
+		data = map[string]interface{}{
+			"certificate": v.certPEM,
+			"private_key": v.keyPEM,
+			"issuing_ca":  v.chain[0],
+			"ca_chain":    v.chain,
+		}
+	case r.URL.Path == "/v1/pki/issuers" && r.URL.Query().Get("list") == "true":
+		var keys []string
+		for id := range v.issuers {
+			keys = append(keys, id)
+		}
+		data = map[string]interface{}{"keys": keys}
+	case strings.HasPrefix(r.URL.Path, "/v1/pki/issuer/") && strings.HasSuffix(r.URL.Path, "/json"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/pki/issuer/"), "/json")
+		cert, ok := v.issuers[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data = map[string]interface{}{"certificate": cert, "ca_chain": []string{cert}}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(v.t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+}
+
+func (v *fakeVault) lastRequest() (string, map[string]interface{}) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if len(v.requests) == 0 {
+		return "", nil
+	}
+	return v.tokens[len(v.tokens)-1], v.requests[len(v.requests)-1]
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	ca := connect.TestCA(t, nil)
+	otherCA := connect.TestCA(t, nil)
+	certPEM, keyPEM, err := connect.TestAgentLeaf(t, "autoconf", "dc1", ca, time.Hour)
+	require.NoError(t, err)
+
+	return &fakeVault{
+		t:       t,
+		certPEM: certPEM,
+		keyPEM:  keyPEM,
+		chain:   []string{ca.RootCert},
+		issuers: map[string]string{
+			"current": ca.RootCert,
+			"next":    otherCA.RootCert,
+		},
+	}
+}
+
+func TestVaultClient(t *testing.T) {
+	vault := newFakeVault(t)
+	srv := httptest.NewServer(vault)
+	defer srv.Close()
+
+	tokenFile := filepath.Join(testutil.TempDir(t, "vault"), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.first\n"), 0600))
+
+	client, err := newVaultClient(config.AutoEncryptVaultConfig{
+		Address:   srv.URL,
+		TokenFile: tokenFile,
+		Role:      "consul-client",
+		TTL:       72 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cert, err := client.issue(ctx, "client.dc1.consul", []string{"localhost"}, []net.IP{{127, 0, 0, 1}})
+	require.NoError(t, err)
+	require.Equal(t, vault.certPEM, cert.CertPEM)
+	require.Equal(t, vault.keyPEM, cert.PrivateKeyPEM)
+
+	token, req := vault.lastRequest()
+	require.Equal(t, "s.first", token)
+	require.Equal(t, map[string]interface{}{
+		"common_name": "client.dc1.consul",
+		"alt_names":   "localhost",
+		"ip_sans":     "127.0.0.1",
+		"ttl":         "72h0m0s",
+	}, req)
+
+	// The CA certificates of all the issuers are trusted, and the issuing CA
+	// is only returned once.
+	pems, err := client.caPEMs(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		strings.TrimSpace(vault.issuers["current"]),
+		strings.TrimSpace(vault.issuers["next"]),
+	}, pems)
+
+	// The token file is read again for each request.
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.second\n"), 0600))
+	_, err = client.issue(ctx, "client.dc1.consul", nil, nil)
+	require.NoError(t, err)
+	token, _ = vault.lastRequest()
+	require.Equal(t, "s.second", token)
+}
+
+func TestAutoEncrypt_VaultInitialConfiguration(t *testing.T) {
+	vault := newFakeVault(t)
+	srv := httptest.NewServer(vault)
+	defer srv.Close()
+
+	mcfg := newMockedConfig(t)
+	mcfg.loader.addConfigHCL(`
+		auto_encrypt {
+			tls = true
+			vault {
+				address = "` + srv.URL + `"
+				token = "s.token"
+				role = "consul-client"
+			}
+		}
+	`)
+
+	// the certificates are issued by Vault rather than with an RPC to the
+	// servers
+	mcfg.tlsCfg.On("UpdateAutoTLS",
+		[]string(nil),
+		[]string{strings.TrimSpace(vault.chain[0]), strings.TrimSpace(vault.issuers["next"])},
+		vault.certPEM,
+		"redacted",
+		false,
+	).Return(nil).Once()
+
+	ac, err := New(mcfg.Config)
+	require.NoError(t, err)
+
+	cfg, err := ac.InitialConfiguration(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	token, req := vault.lastRequest()
+	require.Equal(t, "s.token", token)
+	require.Equal(t, map[string]interface{}{
+		"common_name": "client.dc1.consul",
+		"alt_names":   "localhost",
+		"ip_sans":     "127.0.0.1,::1",
+	}, req)
+}
@@ -0,0 +1,122 @@
+package cachetype
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// The types whose results can be persisted across agent restarts. They are the
+// ones whose refresh after a restart puts the most load on the servers.
+var (
+	_ cache.PersistentType = (*ConnectCARoot)(nil)
+	_ cache.PersistentType = (*ConnectCALeaf)(nil)
+	_ cache.PersistentType = (*IntentionMatch)(nil)
+	_ cache.PersistentType = (*HealthServices)(nil)
+	_ cache.PersistentType = (*CatalogServices)(nil)
+)
+
+// persistedResult is the encoding of a persisted result. The value is
+// encoded separately, in the format of the RPCs, so that it's decoded into
+// its own type.
+type persistedResult struct {
+	Index uint64
+	Value []byte
+}
+
+func encodeResult(index uint64, value interface{}) ([]byte, error) {
+	var encoded []byte
+	if err := codec.NewEncoderBytes(&encoded, structs.MsgpackHandle).Encode(value); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	enc := codec.NewEncoderBytes(&data, structs.MsgpackHandle)
+	if err := enc.Encode(persistedResult{Index: index, Value: encoded}); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeResult decodes a result encoded by encodeResult into value, which
+// must be a pointer to the type of the value encoded.
+func decodeResult(data []byte, value interface{}) (cache.FetchResult, error) {
+	var result persistedResult
+	if err := codec.NewDecoderBytes(data, structs.MsgpackHandle).Decode(&result); err != nil {
+		return cache.FetchResult{}, err
+	}
+	if err := codec.NewDecoderBytes(result.Value, structs.MsgpackHandle).Decode(value); err != nil {
+		return cache.FetchResult{}, err
+	}
+	return cache.FetchResult{Value: value, Index: result.Index}, nil
+}
+
+func (c *ConnectCARoot) EncodeResult(result cache.FetchResult) ([]byte, error) {
+	return encodeResult(result.Index, result.Value)
+}
+
+func (c *ConnectCARoot) DecodeResult(data []byte) (cache.FetchResult, error) {
+	return decodeResult(data, &structs.IndexedCARoots{})
+}
+
+func (c *IntentionMatch) EncodeResult(result cache.FetchResult) ([]byte, error) {
+	return encodeResult(result.Index, result.Value)
+}
+
+func (c *IntentionMatch) DecodeResult(data []byte) (cache.FetchResult, error) {
+	return decodeResult(data, &structs.IndexedIntentionMatches{})
+}
+
+func (c *HealthServices) EncodeResult(result cache.FetchResult) ([]byte, error) {
+	return encodeResult(result.Index, result.Value)
+}
+
+func (c *HealthServices) DecodeResult(data []byte) (cache.FetchResult, error) {
+	return decodeResult(data, &structs.IndexedCheckServiceNodes{})
+}
+
+func (c *CatalogServices) EncodeResult(result cache.FetchResult) ([]byte, error) {
+	return encodeResult(result.Index, result.Value)
+}
+
+func (c *CatalogServices) DecodeResult(data []byte) (cache.FetchResult, error) {
+	return decodeResult(data, &structs.IndexedServiceNodes{})
+}
+
+// persistedLeaf is a persisted leaf certificate, along with the ID of the CA
+// key which signed it so that it's not renewed right away after a restart.
+type persistedLeaf struct {
+	Cert           *structs.IssuedCert
+	AuthorityKeyID string
+}
+
+func (c *ConnectCALeaf) EncodeResult(result cache.FetchResult) ([]byte, error) {
+	cert, ok := result.Value.(*structs.IssuedCert)
+	if !ok {
+		return nil, fmt.Errorf("Internal cache failure: value wrong type: %T", result.Value)
+	}
+	state, _ := result.State.(fetchState)
+	return encodeResult(result.Index, persistedLeaf{Cert: cert, AuthorityKeyID: state.authorityKeyID})
+}
+
+func (c *ConnectCALeaf) DecodeResult(data []byte) (cache.FetchResult, error) {
+	var leaf persistedLeaf
+	result, err := decodeResult(data, &leaf)
+	if err != nil {
+		return result, err
+	}
+	if leaf.Cert == nil {
+		return cache.FetchResult{}, fmt.Errorf("missing certificate")
+	}
+	if !time.Now().Before(leaf.Cert.ValidBefore) {
+		return cache.FetchResult{}, fmt.Errorf("certificate expired at %s", leaf.Cert.ValidBefore)
+	}
+
+	result.Value = leaf.Cert
+	result.State = fetchState{authorityKeyID: leaf.AuthorityKeyID}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package cachetype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestPersistentTypes_RoundTrip(t *testing.T) {
+	roots := &structs.IndexedCARoots{
+		ActiveRootID: "root-1",
+		TrustDomain:  "11111111-2222-3333-4444-555555555555.consul",
+		Roots:        []*structs.CARoot{{ID: "root-1", Name: "primary", Active: true}},
+		QueryMeta:    structs.QueryMeta{Index: 42},
+	}
+	typ := &ConnectCARoot{}
+	data, err := typ.EncodeResult(cache.FetchResult{Value: roots, Index: 42})
+	require.NoError(t, err)
+
+	result, err := typ.DecodeResult(data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), result.Index)
+	decodedRoots := result.Value.(*structs.IndexedCARoots)
+	require.Equal(t, "root-1", decodedRoots.ActiveRootID)
+	require.Equal(t, roots.TrustDomain, decodedRoots.TrustDomain)
+	require.Len(t, decodedRoots.Roots, 1)
+	require.True(t, decodedRoots.Roots[0].Active)
+
+	services := &structs.IndexedCheckServiceNodes{
+		Nodes: structs.CheckServiceNodes{{
+			Node:    &structs.Node{Node: "node-1", Address: "10.0.0.1"},
+			Service: &structs.NodeService{ID: "web-1", Service: "web", Port: 8080},
+		}},
+		QueryMeta: structs.QueryMeta{Index: 7},
+	}
+	health := &HealthServices{}
+	data, err = health.EncodeResult(cache.FetchResult{Value: services, Index: 7})
+	require.NoError(t, err)
+
+	result, err = health.DecodeResult(data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), result.Index)
+	decoded := result.Value.(*structs.IndexedCheckServiceNodes)
+	require.Len(t, decoded.Nodes, 1)
+	require.Equal(t, "web-1", decoded.Nodes[0].Service.ID)
+	require.Equal(t, "10.0.0.1", decoded.Nodes[0].Node.Address)
+}
+
+func TestConnectCALeaf_PersistResult(t *testing.T) {
+	typ := &ConnectCALeaf{}
+
+	cert := &structs.IssuedCert{
+		SerialNumber: "00:01",
+		CertPEM:      "cert",
+		ValidAfter:   time.Now().Add(-time.Hour),
+		ValidBefore:  time.Now().Add(time.Hour),
+		RaftIndex:    structs.RaftIndex{CreateIndex: 3, ModifyIndex: 3},
+	}
+	data, err := typ.EncodeResult(cache.FetchResult{Value: cert, State: ConnectCALeafSuccess("ab:cd"), Index: 3})
+	require.NoError(t, err)
+
+	result, err := typ.DecodeResult(data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Index)
+	decodedCert := result.Value.(*structs.IssuedCert)
+	require.Equal(t, "00:01", decodedCert.SerialNumber)
+	require.True(t, cert.ValidBefore.Equal(decodedCert.ValidBefore))
+	require.Equal(t, ConnectCALeafSuccess("ab:cd"), result.State)
+
+	t.Run("expired", func(t *testing.T) {
+		expired := *cert
+		expired.ValidBefore = time.Now().Add(-time.Minute)
+		data, err := typ.EncodeResult(cache.FetchResult{Value: &expired, State: ConnectCALeafSuccess("ab:cd"), Index: 3})
+		require.NoError(t, err)
+
+		_, err = typ.DecodeResult(data)
+		require.Error(t, err)
+	})
+}
@@ -149,6 +149,13 @@ type Cache struct {
 	options          Options
 	rateLimitContext context.Context
 	rateLimitCancel  context.CancelFunc
+
+	// persisted holds the entries loaded from Options.PersistPath which
+	// weren't requested yet, by hashed entry key. persistedCount is its
+	// length, read atomically to skip the lock once they are all requested.
+	persistedLock  sync.Mutex
+	persisted      map[string]persistedEntry
+	persistedCount int32
 }
 
 // typeEntry is a single type that is registered with a Cache.
@@ -203,6 +210,11 @@ type Options struct {
 	// CacheRefreshMaxWait is the maximum backoff wait time.
 	// Mostly configurable just for testing.
 	CacheRefreshMaxWait time.Duration
+
+	// PersistPath is the file the entries of the types implementing
+	// PersistentType are written to by Close, and restored from by New. The
+	// entries are not persisted if it is empty.
+	PersistPath string
 }
 
 // Equal return true if both options are equivalent
@@ -243,6 +255,13 @@ func New(options Options) *Cache {
 		options:           options,
 		rateLimitContext:  ctx,
 		rateLimitCancel:   cancel,
+		persisted:         make(map[string]persistedEntry),
+	}
+
+	if options.PersistPath != "" {
+		if err := c.loadPersisted(); err != nil {
+			options.Logger.Warn("discarding the persisted cache entries", "error", err)
+		}
 	}
 
 	// Start the expiry watcher
@@ -452,6 +471,7 @@ func (c *Cache) getWithIndex(ctx context.Context, r getOptions) (interface{}, Re
 	}
 
 	key := makeEntryKey(r.TypeEntry.Name, r.Info.Datacenter, r.Info.PeerName, r.Info.Token, r.Info.Key)
+	c.rehydrate(key, r)
 
 	// First time through
 	first := true
@@ -981,13 +1001,20 @@ func (c *Cache) runExpiryLoop() {
 // still access the current cache values so coordination isn't needed with
 // callers, however no background activity will continue. It's intended to close
 // the cache at agent shutdown so no further requests should be made, however
-// concurrent or in-flight ones won't break.
+// concurrent or in-flight ones won't break. The entries are persisted to
+// Options.PersistPath if set.
 func (c *Cache) Close() error {
 	wasStopped := atomic.SwapUint32(&c.stopped, 1)
 	if wasStopped == 0 {
 		// First time only, close stop chan
 		close(c.stopCh)
 		c.rateLimitCancel()
+
+		if c.options.PersistPath != "" {
+			if err := c.persist(); err != nil {
+				c.options.Logger.Warn("failed to persist the cache entries", "error", err)
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"golang.org/x/time/rate"
+
+	"github.com/hashicorp/consul/lib/file"
+)
+
+// PersistentType is implemented by the types whose results can be persisted
+// across agent restarts, see Options.PersistPath.
+type PersistentType interface {
+	Type
+
+	// EncodeResult encodes the value and state of a result of the type.
+	EncodeResult(result FetchResult) ([]byte, error)
+
+	// DecodeResult decodes a result encoded by EncodeResult. It returns an
+	// error if the result can no longer be used, such as an expired
+	// certificate, in which case it is fetched again.
+	DecodeResult(data []byte) (FetchResult, error)
+}
+
+// persistVersion is the version of the format of the persisted entries. The
+// entries persisted with another version are discarded.
+const persistVersion = 1
+
+// persistedEntries is the content of the file the entries are persisted to.
+type persistedEntries struct {
+	Version int
+
+	// Checksum is the SHA256 of Entries, to discard the entries of a corrupted
+	// file rather than serve them.
+	Checksum string
+
+	Entries json.RawMessage
+}
+
+// persistedEntry is a cache entry persisted by Close.
+type persistedEntry struct {
+	// Key is the hash of the key of the entry, which includes the ACL token
+	// of the request. The entry is restored by the first Get with a matching
+	// key.
+	Key  string
+	Type string
+
+	Result    []byte
+	FetchedAt time.Time
+
+	// ExpiresAt is when the entry would have expired from the cache if the
+	// agent kept running. The entries are discarded past it.
+	ExpiresAt time.Time
+}
+
+func hashEntryKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadPersisted reads the entries persisted to Options.PersistPath. They are
+// only decoded when requested since the types are registered after the cache
+// is created.
+func (c *Cache) loadPersisted() error {
+	data, err := os.ReadFile(c.options.PersistPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var persisted persistedEntries
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", c.options.PersistPath, err)
+	}
+	if persisted.Version != persistVersion {
+		return fmt.Errorf("unsupported version %d", persisted.Version)
+	}
+	sum := sha256.Sum256(persisted.Entries)
+	if hex.EncodeToString(sum[:]) != persisted.Checksum {
+		return fmt.Errorf("checksum mismatch, %s is corrupted", c.options.PersistPath)
+	}
+
+	var entries []persistedEntry
+	if err := json.Unmarshal(persisted.Entries, &entries); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", c.options.PersistPath, err)
+	}
+
+	now := time.Now()
+	c.persistedLock.Lock()
+	defer c.persistedLock.Unlock()
+	for _, entry := range entries {
+		if entry.ExpiresAt.After(now) {
+			c.persisted[entry.Key] = entry
+		}
+	}
+	atomic.StoreInt32(&c.persistedCount, int32(len(c.persisted)))
+	return nil
+}
+
+// rehydrate moves the persisted entry matching the key, if any, into the
+// cache. Entries of the types which refresh in the background start
+// refreshing right away; the others are revalidated like any other entry.
+func (c *Cache) rehydrate(key string, r getOptions) {
+	if atomic.LoadInt32(&c.persistedCount) == 0 {
+		return
+	}
+
+	hashed := hashEntryKey(key)
+	c.persistedLock.Lock()
+	persisted, ok := c.persisted[hashed]
+	if ok {
+		delete(c.persisted, hashed)
+		atomic.StoreInt32(&c.persistedCount, int32(len(c.persisted)))
+	}
+	c.persistedLock.Unlock()
+	if !ok {
+		return
+	}
+
+	typ, ok := r.TypeEntry.Type.(PersistentType)
+	if !ok || persisted.Type != r.TypeEntry.Name {
+		return
+	}
+	ttl := time.Until(persisted.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+	result, err := typ.DecodeResult(persisted.Result)
+	if err != nil {
+		c.options.Logger.Debug("discarding persisted cache entry", "type", r.TypeEntry.Name, "error", err)
+		return
+	}
+
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	entry := cacheEntry{
+		Valid:     true,
+		Value:     result.Value,
+		State:     result.State,
+		Index:     result.Index,
+		FetchedAt: persisted.FetchedAt,
+		Waiter:    make(chan struct{}),
+		Expiry:    c.entriesExpiryHeap.Add(key, ttl),
+		FetchRateLimiter: rate.NewLimiter(
+			c.options.EntryFetchRate,
+			c.options.EntryFetchMaxBurst,
+		),
+	}
+	if r.TypeEntry.Opts.Refresh {
+		// Nothing else would start the background refresh of a valid entry.
+		c.lastGoroutineID++
+		entry.GoroutineID = c.lastGoroutineID
+		go c.launchBackgroundFetcher(entry.GoroutineID, key, r)
+	}
+	c.entries[key] = entry
+
+	metrics.IncrCounter([]string{"cache", r.TypeEntry.Name, "rehydrated"}, 1)
+	metrics.SetGauge([]string{"consul", "cache", "entries_count"}, float32(len(c.entries)))
+	metrics.SetGauge([]string{"cache", "entries_count"}, float32(len(c.entries)))
+}
+
+// persist writes the valid entries of the types implementing PersistentType
+// to Options.PersistPath, along with the persisted entries which weren't
+// requested since they were loaded.
+func (c *Cache) persist() error {
+	c.typesLock.RLock()
+	types := make(map[string]PersistentType, len(c.types))
+	for name, tEntry := range c.types {
+		if typ, ok := tEntry.Type.(PersistentType); ok {
+			types[name] = typ
+		}
+	}
+	c.typesLock.RUnlock()
+
+	var entries []persistedEntry
+	c.entriesLock.RLock()
+	for key, entry := range c.entries {
+		if !entry.Valid || entry.Expiry == nil {
+			continue
+		}
+		// The key starts with the name of the type, see makeEntryKey.
+		name := strings.SplitN(key, "/", 2)[0]
+		typ, ok := types[name]
+		if !ok {
+			continue
+		}
+
+		data, err := typ.EncodeResult(FetchResult{Value: entry.Value, State: entry.State, Index: entry.Index})
+		if err != nil {
+			c.options.Logger.Debug("failed to persist cache entry", "type", name, "error", err)
+			continue
+		}
+		entries = append(entries, persistedEntry{
+			Key:       hashEntryKey(key),
+			Type:      name,
+			Result:    data,
+			FetchedAt: entry.FetchedAt,
+			ExpiresAt: entry.Expiry.Expiry(),
+		})
+	}
+	c.entriesLock.RUnlock()
+
+	now := time.Now()
+	c.persistedLock.Lock()
+	for _, entry := range c.persisted {
+		if entry.ExpiresAt.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+	c.persistedLock.Unlock()
+
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(encoded)
+	data, err := json.Marshal(persistedEntries{
+		Version:  persistVersion,
+		Checksum: hex.EncodeToString(sum[:]),
+		Entries:  encoded,
+	})
+	if err != nil {
+		return err
+	}
+	return file.WriteAtomic(c.options.PersistPath, data)
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestCache_PersistRehydrate(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t, "cache"), "entries.json")
+	req := fakeRequest{
+		info: RequestInfo{
+			Key:        "v1",
+			Token:      "secret-token",
+			Datacenter: "dc1",
+		},
+	}
+
+	typ := &persistentFakeType{fakeType: fakeType{index: 5}}
+	c := New(Options{PersistPath: path})
+	c.RegisterType("t", typ)
+
+	result, meta, err := c.Get(context.Background(), "t", req)
+	require.NoError(t, err)
+	require.Equal(t, 10, result)
+	require.False(t, meta.Hit)
+	require.NoError(t, c.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret-token")
+
+	t.Run("rehydrated", func(t *testing.T) {
+		typ := &persistentFakeType{fakeType: fakeType{index: 6}}
+		c := New(Options{PersistPath: path})
+		c.RegisterType("t", typ)
+
+		result, meta, err := c.Get(context.Background(), "t", req)
+		require.NoError(t, err)
+		require.Equal(t, 10, result)
+		require.True(t, meta.Hit)
+		require.Equal(t, uint64(5), meta.Index)
+		require.Equal(t, uint32(0), atomic.LoadUint32(&typ.fetches))
+	})
+
+	t.Run("other token", func(t *testing.T) {
+		typ := &persistentFakeType{fakeType: fakeType{index: 6}}
+		c := New(Options{PersistPath: path})
+		c.RegisterType("t", typ)
+
+		other := req
+		other.info.Token = "other-token"
+		result, meta, err := c.Get(context.Background(), "t", other)
+		require.NoError(t, err)
+		require.Equal(t, 12, result)
+		require.False(t, meta.Hit)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		corrupted := filepath.Join(filepath.Dir(path), "corrupted.json")
+		var persisted persistedEntries
+		require.NoError(t, json.Unmarshal(data, &persisted))
+		persisted.Checksum = "0000"
+		encoded, err := json.Marshal(persisted)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(corrupted, encoded, 0600))
+
+		typ := &persistentFakeType{fakeType: fakeType{index: 6}}
+		c := New(Options{PersistPath: corrupted})
+		c.RegisterType("t", typ)
+
+		result, meta, err := c.Get(context.Background(), "t", req)
+		require.NoError(t, err)
+		require.Equal(t, 12, result)
+		require.False(t, meta.Hit)
+	})
+}
+
+// persistentFakeType is a blocking fakeType whose results can be persisted.
+type persistentFakeType struct {
+	fakeType
+	fetches uint32
+}
+
+func (f *persistentFakeType) Fetch(opts FetchOptions, req Request) (FetchResult, error) {
+	atomic.AddUint32(&f.fetches, 1)
+	return f.fakeType.Fetch(opts, req)
+}
+
+func (f *persistentFakeType) RegisterOptions() RegisterOptions {
+	return RegisterOptions{SupportsBlocking: true}
+}
+
+type persistedFakeResult struct {
+	Value int
+	Index uint64
+}
+
+func (f *persistentFakeType) EncodeResult(result FetchResult) ([]byte, error) {
+	return json.Marshal(persistedFakeResult{Value: result.Value.(int), Index: result.Index})
+}
+
+func (f *persistentFakeType) DecodeResult(data []byte) (FetchResult, error) {
+	var result persistedFakeResult
+	err := json.Unmarshal(data, &result)
+	return FetchResult{Value: result.Value, Index: result.Index}, err
+}
+
+var _ PersistentType = (*persistentFakeType)(nil)
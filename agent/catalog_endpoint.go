@@ -1,15 +1,24 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-metrics/prometheus"
 
+	"github.com/hashicorp/consul/acl"
 	cachetype "github.com/hashicorp/consul/agent/cache-types"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/proto/pbservice"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
 )
 
 var CatalogCounters = []prometheus.CounterDefinition{
@@ -29,6 +38,18 @@ var CatalogCounters = []prometheus.CounterDefinition{
 		Name: []string{"client", "api", "catalog_deregister"},
 		Help: "Increments whenever a Consul agent receives a catalog deregister request.",
 	},
+	{
+		Name: []string{"client", "api", "catalog_batch"},
+		Help: "Increments whenever a Consul agent receives a catalog batch request.",
+	},
+	{
+		Name: []string{"client", "rpc", "error", "catalog_batch"},
+		Help: "Increments whenever a Consul agent receives an RPC error for a catalog batch request.",
+	},
+	{
+		Name: []string{"client", "api", "success", "catalog_batch"},
+		Help: "Increments whenever a Consul agent successfully responds to a catalog batch request.",
+	},
 	{
 		Name: []string{"client", "api", "catalog_datacenters"},
 		Help: "Increments whenever a Consul agent receives a request to list datacenters in the catalog.",
@@ -125,6 +146,26 @@ var CatalogCounters = []prometheus.CounterDefinition{
 		Name: []string{"client", "api", "success", "catalog_gateway_services"},
 		Help: "Increments whenever a Consul agent successfully responds to a request to list services associated with a gateway.",
 	},
+	{
+		Name: []string{"client", "api", "catalog_changes"},
+		Help: "Increments whenever a Consul agent receives a request to stream the changes to the catalog.",
+	},
+	{
+		Name: []string{"client", "rpc", "error", "catalog_changes"},
+		Help: "Increments whenever a Consul agent receives an RPC error for a request to stream the changes to the catalog.",
+	},
+	{
+		Name: []string{"client", "api", "catalog_prometheus_sd"},
+		Help: "Increments whenever a Consul agent receives a request for the Prometheus scrape targets.",
+	},
+	{
+		Name: []string{"client", "api", "success", "catalog_prometheus_sd"},
+		Help: "Increments whenever a Consul agent successfully responds to a request for the Prometheus scrape targets.",
+	},
+	{
+		Name: []string{"client", "rpc", "error", "catalog_prometheus_sd"},
+		Help: "Increments whenever a Consul agent receives an RPC error for a request for the Prometheus scrape targets.",
+	},
 }
 
 func (s *HTTPHandlers) CatalogRegister(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -188,6 +229,61 @@ func (s *HTTPHandlers) CatalogDeregister(resp http.ResponseWriter, req *http.Req
 	return true, nil
 }
 
+func (s *HTTPHandlers) CatalogBatch(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_batch"}, 1,
+		s.nodeMetricsLabels())
+
+	// The whole batch is committed as a single Raft entry, so it's limited
+	// like a transaction.
+	maxLen := int64(s.agent.getHTTPLimits().txnMaxReqLen)
+	if req.ContentLength > maxLen {
+		return nil, HTTPError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Reason: fmt.Sprintf("Request body(%d bytes) too large, max size: %d bytes. See %s.",
+				req.ContentLength, maxLen, "https://www.consul.io/docs/agent/config/config-files#txn_max_req_len"),
+		}
+	}
+
+	var args structs.CatalogBatchRequest
+	req.Body = http.MaxBytesReader(resp, req.Body, maxLen)
+	if err := s.rewordUnknownEnterpriseFieldError(decodeBody(req.Body, &args)); err != nil {
+		if err.Error() == "http: request body too large" {
+			return nil, HTTPError{
+				StatusCode: http.StatusRequestEntityTooLarge,
+				Reason: fmt.Sprintf("Request body too large, max size: %d bytes. See %s.",
+					maxLen, "https://www.consul.io/docs/agent/config/config-files#txn_max_req_len"),
+			}
+		}
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if len(args.Register) == 0 && len(args.Deregister) == 0 {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Must provide at least one registration or deregistration"}
+	}
+	if size := len(args.Register) + len(args.Deregister); size > structs.MaxCatalogBatchEntries {
+		return nil, HTTPError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Reason:     fmt.Sprintf("Batch contains too many entries (%d > %d)", size, structs.MaxCatalogBatchEntries),
+		}
+	}
+
+	// Setup the default DC if not provided
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+	s.parseToken(req, &args.Token)
+
+	// Forward to the servers
+	var out struct{}
+	if err := s.agent.RPC(req.Context(), "Catalog.Batch", &args, &out); err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_batch"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	}
+	metrics.IncrCounterWithLabels([]string{"client", "api", "success", "catalog_batch"}, 1,
+		s.nodeMetricsLabels())
+	return true, nil
+}
+
 func (s *HTTPHandlers) CatalogDatacenters(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_datacenters"}, 1,
 		s.nodeMetricsLabels())
@@ -278,35 +374,18 @@ func (s *HTTPHandlers) CatalogServices(resp http.ResponseWriter, req *http.Reque
 	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
 		return nil, nil
 	}
-	var out structs.IndexedServices
+	if err := parsePagination(req, &args.PageOptions); err != nil {
+		return nil, err
+	}
+	out, md, err := s.agent.rpcClientCatalog.ListServices(req.Context(), args)
+	if err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_services"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	}
 	defer setMeta(resp, &out.QueryMeta)
-
 	if args.QueryOptions.UseCache {
-		raw, m, err := s.agent.cache.Get(req.Context(), cachetype.CatalogListServicesName, &args)
-		if err != nil {
-			metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_services"}, 1,
-				s.nodeMetricsLabels())
-			return nil, err
-		}
-		reply, ok := raw.(*structs.IndexedServices)
-		if !ok {
-			// This should never happen, but we want to protect against panics
-			return nil, fmt.Errorf("internal error: response type not correct")
-		}
-		defer setCacheMeta(resp, &m)
-		out = *reply
-	} else {
-	RETRY_ONCE:
-		if err := s.agent.RPC(req.Context(), "Catalog.ListServices", &args, &out); err != nil {
-			metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_services"}, 1,
-				s.nodeMetricsLabels())
-			return nil, err
-		}
-		if args.QueryOptions.AllowStale && args.MaxStaleDuration > 0 && args.MaxStaleDuration < out.LastContact {
-			args.AllowStale = false
-			args.MaxStaleDuration = 0
-			goto RETRY_ONCE
-		}
+		setCacheMeta(resp, &md)
 	}
 
 	out.ConsistencyLevel = args.QueryOptions.ConsistencyLevel()
@@ -315,6 +394,8 @@ func (s *HTTPHandlers) CatalogServices(resp http.ResponseWriter, req *http.Reque
 	if out.Services == nil {
 		out.Services = make(structs.Services)
 	}
+
+	setNextCursor(resp, out.NextCursor, out.Index)
 	metrics.IncrCounterWithLabels([]string{"client", "api", "success", "catalog_services"}, 1,
 		s.nodeMetricsLabels())
 	return out.Services, nil
@@ -418,6 +499,376 @@ func (s *HTTPHandlers) catalogServiceNodes(resp http.ResponseWriter, req *http.R
 	return out.ServiceNodes, nil
 }
 
+func (s *HTTPHandlers) CatalogServiceTombstones(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_service_tombstones"}, 1,
+		s.nodeMetricsLabels())
+
+	args := structs.ServiceTombstonesRequest{}
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	args.ServiceName = req.URL.Query().Get("service")
+	args.PeerName = req.URL.Query().Get("peer")
+
+	var out structs.IndexedServiceTombstones
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "Catalog.ServiceTombstones", &args, &out); err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_service_tombstones"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	}
+
+	// Use empty list instead of nil
+	if out.Tombstones == nil {
+		out.Tombstones = make(structs.ServiceTombstones, 0)
+	}
+	metrics.IncrCounterWithLabels([]string{"client", "api", "success", "catalog_service_tombstones"}, 1,
+		s.nodeMetricsLabels())
+	return out.Tombstones, nil
+}
+
+// CatalogChanges streams the changes to the service instances of the catalog
+// as newline-delimited JSON events. A stream started with a cursor resumes
+// after the events with that index if the servers still hold them, and
+// otherwise starts with a reset event followed by a snapshot of the catalog.
+func (s *HTTPHandlers) CatalogChanges(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_changes"}, 1,
+		s.nodeMetricsLabels())
+
+	var entMeta acl.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	var dc, token string
+	s.parseDC(req, &dc)
+	s.parseToken(req, &token)
+
+	subReq := &pbsubscribe.SubscribeRequest{
+		Topic:      pbsubscribe.Topic_ServiceHealth,
+		Token:      token,
+		Datacenter: dc,
+	}
+	if cursor := req.URL.Query().Get("cursor"); cursor != "" {
+		index, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid cursor: %v", err)}
+		}
+		subReq.Index = index
+	}
+	if service := req.URL.Query().Get("service"); service != "" {
+		subReq.Subject = &pbsubscribe.SubscribeRequest_NamedSubject{
+			NamedSubject: &pbsubscribe.NamedSubject{
+				Key:       service,
+				Namespace: entMeta.NamespaceOrEmpty(),
+				Partition: entMeta.PartitionOrEmpty(),
+			},
+		}
+	} else {
+		subReq.Subject = &pbsubscribe.SubscribeRequest_WildcardSubject{WildcardSubject: true}
+	}
+
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("Streaming not supported")
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	handle, err := s.agent.rpcClientSubscribe.Subscribe(ctx, subReq)
+	if err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_changes"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	}
+
+	// The first event is received before the header is sent, so that a
+	// subscription refused by the servers, for example for lack of
+	// permissions, is reported with its status code.
+	event, err := handle.Recv()
+	if err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_changes"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	}
+
+	// Send header so client can start streaming body
+	resp.Header().Set("Content-Type", "application/x-ndjson")
+	resp.WriteHeader(http.StatusOK)
+
+	// 0 byte write is needed before the Flush call so that if we are using
+	// a gzip stream it will go ahead and write out the HTTP response header
+	resp.Write([]byte(""))
+	flusher.Flush()
+
+	// Stream the events until the connection is closed, or until the servers
+	// close the stream, in which case the client resumes from its cursor.
+	enc := json.NewEncoder(resp)
+	for {
+		changes, err := catalogChangeEvents(event)
+		if err != nil {
+			s.agent.logger.Warn("failed to decode catalog change event", "error", err)
+			return nil, nil
+		}
+		for _, change := range changes {
+			if err := enc.Encode(change); err != nil {
+				return nil, nil
+			}
+		}
+		flusher.Flush()
+
+		event, err = handle.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				s.agent.logger.Debug("catalog change stream closed", "error", err)
+			}
+			return nil, nil
+		}
+	}
+}
+
+// catalogChangeEvents converts a subscription event to catalog change events.
+func catalogChangeEvents(event *pbsubscribe.Event) ([]structs.CatalogChangeEvent, error) {
+	index := event.GetIndex()
+	switch {
+	case event.GetNewSnapshotToFollow():
+		return []structs.CatalogChangeEvent{{Index: index, Op: structs.CatalogChangeReset}}, nil
+
+	case event.GetEndOfSnapshot():
+		return []structs.CatalogChangeEvent{{Index: index, Op: structs.CatalogChangeSnapshotDone}}, nil
+
+	case event.GetEventBatch() != nil:
+		var changes []structs.CatalogChangeEvent
+		for _, e := range event.GetEventBatch().GetEvents() {
+			batch, err := catalogChangeEvents(e)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, batch...)
+		}
+		return changes, nil
+
+	case event.GetServiceHealth() != nil:
+		update := event.GetServiceHealth()
+		csn, err := pbservice.CheckServiceNodeToStructs(update.GetCheckServiceNode())
+		if err != nil {
+			return nil, err
+		}
+
+		// Services imported from peers are not part of the local catalog.
+		if csn.Node != nil && csn.Node.PeerName != "" {
+			return nil, nil
+		}
+
+		op := structs.CatalogChangeRegister
+		if update.GetOp() == pbsubscribe.CatalogOp_Deregister {
+			op = structs.CatalogChangeDeregister
+		}
+		return []structs.CatalogChangeEvent{{Index: index, Op: op, Entry: csn}}, nil
+	}
+	return nil, nil
+}
+
+// prometheusTargetGroup is a target group of the Prometheus HTTP service
+// discovery.
+type prometheusTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// prometheusLabelName matches the valid Prometheus label names.
+var prometheusLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CatalogPrometheusSD returns the service instances of the catalog as the
+// target groups of the Prometheus HTTP service discovery, with the same
+// labels as the Consul service discovery of Prometheus. Unlike the Consul
+// service discovery, which runs a blocking query per service, Prometheus
+// polls it for all the services at once.
+func (s *HTTPHandlers) CatalogPrometheusSD(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_prometheus_sd"}, 1,
+		s.nodeMetricsLabels())
+
+	args := structs.ServiceDumpRequest{}
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	parseCacheControl(resp, req, &args.QueryOptions)
+	s.parseFilter(req, &args.Filter)
+
+	query := req.URL.Query()
+	services := make(map[string]struct{})
+	for _, service := range query["service"] {
+		services[service] = struct{}{}
+	}
+	tags := query["tag"]
+	_, passing := query["passing"]
+	metaLabels, err := parsePrometheusMetaLabels(query["meta-label"])
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: err.Error()}
+	}
+
+	var out structs.IndexedNodesWithGateways
+	if args.QueryOptions.UseCache {
+		raw, m, err := s.agent.cache.Get(req.Context(), cachetype.InternalServiceDumpName, &args)
+		if err != nil {
+			metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_prometheus_sd"}, 1,
+				s.nodeMetricsLabels())
+			return nil, err
+		}
+		defer setCacheMeta(resp, &m)
+		reply, ok := raw.(*structs.IndexedNodesWithGateways)
+		if !ok {
+			// This should never happen, but we want to protect against panics
+			return nil, fmt.Errorf("internal error: response type not correct")
+		}
+		out = *reply
+	} else {
+		defer setMeta(resp, &out.QueryMeta)
+		if err := s.agent.RPC(req.Context(), "Internal.ServiceDump", &args, &out); err != nil {
+			metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_prometheus_sd"}, 1,
+				s.nodeMetricsLabels())
+			return nil, err
+		}
+	}
+
+	nodes := out.Nodes
+	if passing {
+		// Filter modifies the nodes, which can be shared with the cache.
+		nodes = nodes.ShallowClone().Filter(true)
+	}
+
+	// Use empty list instead of nil
+	groups := make([]prometheusTargetGroup, 0)
+	for _, csn := range nodes {
+		if len(services) > 0 {
+			if _, ok := services[csn.Service.Service]; !ok {
+				continue
+			}
+		}
+		if !serviceTagsMatch(csn.Service.Tags, tags) {
+			continue
+		}
+		groups = append(groups, prometheusTargetGroupFor(args.Datacenter, csn, metaLabels))
+	}
+
+	metrics.IncrCounterWithLabels([]string{"client", "api", "success", "catalog_prometheus_sd"}, 1,
+		s.nodeMetricsLabels())
+	return groups, nil
+}
+
+// parsePrometheusMetaLabels parses the meta-label query parameters, in the
+// "<service meta key>:<label name>" form, to a map of the label names by
+// service meta key.
+func parsePrometheusMetaLabels(params []string) (map[string]string, error) {
+	labels := make(map[string]string, len(params))
+	for _, param := range params {
+		key, label, ok := strings.Cut(param, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("Invalid meta-label %q, must be <service meta key>:<label name>", param)
+		}
+		if !prometheusLabelName.MatchString(label) || strings.HasPrefix(label, "__") {
+			return nil, fmt.Errorf("Invalid meta-label %q, %q is not a valid label name", param, label)
+		}
+		labels[key] = label
+	}
+	return labels, nil
+}
+
+// serviceTagsMatch returns true if the service has all the tags.
+func serviceTagsMatch(serviceTags, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range serviceTags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// prometheusTargetGroupFor returns the target group of a service instance.
+func prometheusTargetGroupFor(dc string, csn structs.CheckServiceNode, metaLabels map[string]string) prometheusTargetGroup {
+	addr := csn.Service.Address
+	if addr == "" {
+		addr = csn.Node.Address
+	}
+
+	labels := map[string]string{
+		"__meta_consul_address":         csn.Node.Address,
+		"__meta_consul_dc":              dc,
+		"__meta_consul_health":          prometheusHealth(csn.Checks),
+		"__meta_consul_node":            csn.Node.Node,
+		"__meta_consul_service":         csn.Service.Service,
+		"__meta_consul_service_address": csn.Service.Address,
+		"__meta_consul_service_id":      csn.Service.ID,
+		"__meta_consul_service_port":    strconv.Itoa(csn.Service.Port),
+		// The tags are joined with a leading and a trailing separator so that
+		// they can be matched with regular expressions like ".*,tag,.*".
+		"__meta_consul_tags": "," + strings.Join(csn.Service.Tags, ",") + ",",
+	}
+	if partition := csn.Service.PartitionOrEmpty(); partition != "" {
+		labels["__meta_consul_partition"] = partition
+	}
+	if namespace := csn.Service.NamespaceOrEmpty(); namespace != "" {
+		labels["__meta_consul_namespace"] = namespace
+	}
+	for k, v := range csn.Node.Meta {
+		labels["__meta_consul_metadata_"+prometheusLabelSuffix(k)] = v
+	}
+	for k, v := range csn.Node.TaggedAddresses {
+		labels["__meta_consul_tagged_address_"+prometheusLabelSuffix(k)] = v
+	}
+	for k, v := range csn.Service.Meta {
+		labels["__meta_consul_service_metadata_"+prometheusLabelSuffix(k)] = v
+		if label, ok := metaLabels[k]; ok {
+			labels[label] = v
+		}
+	}
+
+	return prometheusTargetGroup{
+		Targets: []string{net.JoinHostPort(addr, strconv.Itoa(csn.Service.Port))},
+		Labels:  labels,
+	}
+}
+
+// prometheusLabelSuffix replaces the characters that are invalid in the
+// Prometheus label names with underscores, like Prometheus does.
+func prometheusLabelSuffix(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// prometheusHealth returns the aggregated status of the health checks of a
+// service instance.
+func prometheusHealth(checks structs.HealthChecks) string {
+	status := api.HealthPassing
+	for _, check := range checks {
+		switch check.Status {
+		case api.HealthCritical, api.HealthMaint:
+			return api.HealthCritical
+		case api.HealthWarning:
+			status = api.HealthWarning
+		}
+	}
+	return status
+}
+
 func (s *HTTPHandlers) CatalogNodeServices(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_node_services"}, 1,
 		s.nodeMetricsLabels())
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +19,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbservice"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
@@ -127,6 +131,72 @@ func TestCatalogDeregister(t *testing.T) {
 	}
 }
 
+func TestCatalogBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	args := &structs.CatalogBatchRequest{
+		Register: []*structs.RegisterRequest{
+			{Node: "foo", Address: "127.0.0.1"},
+			{Node: "bar", Address: "127.0.0.2"},
+		},
+		Deregister: []*structs.DeregisterRequest{
+			{Node: "baz"},
+		},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/catalog/batch", jsonReader(args))
+	obj, err := a.srv.CatalogBatch(nil, req)
+	require.NoError(t, err)
+	require.Equal(t, true, obj)
+
+	req, _ = http.NewRequest("GET", "/v1/catalog/nodes?dc=dc1", nil)
+	obj, err = a.srv.CatalogNodes(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	var names []string
+	for _, n := range obj.(structs.Nodes) {
+		names = append(names, n.Node)
+	}
+	require.Contains(t, names, "foo")
+	require.Contains(t, names, "bar")
+
+	// An empty batch is rejected before reaching the servers.
+	req, _ = http.NewRequest("PUT", "/v1/catalog/batch", jsonReader(&structs.CatalogBatchRequest{}))
+	_, err = a.srv.CatalogBatch(nil, req)
+	httpErr, ok := err.(HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+
+	// So are batches with too many entries.
+	args = &structs.CatalogBatchRequest{}
+	for i := 0; i <= structs.MaxCatalogBatchEntries; i++ {
+		args.Deregister = append(args.Deregister, &structs.DeregisterRequest{Node: fmt.Sprintf("node-%d", i)})
+	}
+	req, _ = http.NewRequest("PUT", "/v1/catalog/batch", jsonReader(args))
+	_, err = a.srv.CatalogBatch(nil, req)
+	httpErr, ok = err.(HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusRequestEntityTooLarge, httpErr.StatusCode)
+	require.Contains(t, httpErr.Reason, "Batch contains too many entries (129 > 128)")
+
+	// And bodies larger than txn_max_req_len, whether or not their length
+	// is known up front.
+	body := `{"Register":[{"Node":"foo","Address":"` + strings.Repeat("a", 600*1024) + `"}]}`
+	for _, contentLength := range []int64{int64(len(body)), -1} {
+		req, _ = http.NewRequest("PUT", "/v1/catalog/batch", strings.NewReader(body))
+		req.ContentLength = contentLength
+		_, err = a.srv.CatalogBatch(httptest.NewRecorder(), req)
+		httpErr, ok = err.(HTTPError)
+		require.True(t, ok)
+		require.Equal(t, http.StatusRequestEntityTooLarge, httpErr.StatusCode)
+	}
+}
+
 func TestCatalogDatacenters(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -601,6 +671,62 @@ func TestCatalogServices_NodeMetaFilter(t *testing.T) {
 	}
 }
 
+func TestCatalogServices_ACLFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			Service: "api",
+			Tags:    []string{"v1"},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	// The token can read the api service but no node, which is enough for
+	// the ListServices RPC.
+	token := testCreateToken(t, a, `
+		service "api" {
+			policy = "read"
+		}
+	`)
+
+	rpcReq := structs.DCSpecificRequest{
+		Datacenter:   "dc1",
+		QueryOptions: structs.QueryOptions{Token: token},
+	}
+	var rpcOut structs.IndexedServices
+	require.NoError(t, a.RPC(context.Background(), "Catalog.ListServices", &rpcReq, &rpcOut))
+	require.Equal(t, structs.Services{"api": {"v1"}}, rpcOut.Services)
+	require.True(t, rpcOut.QueryMeta.ResultsFilteredByACLs)
+
+	// The blocking and cached queries of the HTTP API return the same
+	// services as the RPC, and report that results were filtered.
+	for _, query := range []string{"", "?cached", fmt.Sprintf("?index=%d&wait=10ms", rpcOut.Index)} {
+		t.Run(query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/catalog/services"+query, nil)
+			req.Header.Add("X-Consul-Token", token)
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.CatalogServices(resp, req)
+			require.NoError(t, err)
+
+			require.Equal(t, rpcOut.Services, obj.(structs.Services))
+			require.Equal(t, "true", resp.Header().Get("X-Consul-Results-Filtered-By-ACLs"))
+		})
+	}
+}
+
 func TestCatalogRegister_checkRegistration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -2025,3 +2151,182 @@ func TestCatalog_GatewayServices_Ingress(t *testing.T) {
 		require.Equal(r, expect, gatewayServices)
 	})
 }
+
+func TestCatalogChanges_Events(t *testing.T) {
+	t.Parallel()
+
+	csn := func(node, peer string) *pbservice.CheckServiceNode {
+		return pbservice.NewCheckServiceNodeFromStructs(&structs.CheckServiceNode{
+			Node:    &structs.Node{Node: node, PeerName: peer},
+			Service: &structs.NodeService{ID: "web1", Service: "web", PeerName: peer},
+		})
+	}
+	serviceHealth := func(op pbsubscribe.CatalogOp, node, peer string) *pbsubscribe.Event {
+		return &pbsubscribe.Event{
+			Index: 10,
+			Payload: &pbsubscribe.Event_ServiceHealth{
+				ServiceHealth: &pbsubscribe.ServiceHealthUpdate{Op: op, CheckServiceNode: csn(node, peer)},
+			},
+		}
+	}
+
+	changes, err := catalogChangeEvents(&pbsubscribe.Event{
+		Index:   5,
+		Payload: &pbsubscribe.Event_NewSnapshotToFollow{NewSnapshotToFollow: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []structs.CatalogChangeEvent{{Index: 5, Op: structs.CatalogChangeReset}}, changes)
+
+	changes, err = catalogChangeEvents(&pbsubscribe.Event{
+		Index:   5,
+		Payload: &pbsubscribe.Event_EndOfSnapshot{EndOfSnapshot: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []structs.CatalogChangeEvent{{Index: 5, Op: structs.CatalogChangeSnapshotDone}}, changes)
+
+	// Batches are flattened and services imported from peers are skipped.
+	changes, err = catalogChangeEvents(&pbsubscribe.Event{
+		Index: 10,
+		Payload: &pbsubscribe.Event_EventBatch{
+			EventBatch: &pbsubscribe.EventBatch{
+				Events: []*pbsubscribe.Event{
+					serviceHealth(pbsubscribe.CatalogOp_Register, "node1", ""),
+					serviceHealth(pbsubscribe.CatalogOp_Register, "node2", "peer1"),
+					serviceHealth(pbsubscribe.CatalogOp_Deregister, "node3", ""),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, uint64(10), changes[0].Index)
+	require.Equal(t, structs.CatalogChangeRegister, changes[0].Op)
+	require.Equal(t, "node1", changes[0].Entry.Node.Node)
+	require.Equal(t, "web", changes[0].Entry.Service.Service)
+	require.Equal(t, structs.CatalogChangeDeregister, changes[1].Op)
+	require.Equal(t, "node3", changes[1].Entry.Node.Node)
+}
+
+func TestCatalogChanges_BadCursor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	req, _ := http.NewRequest("GET", "/v1/catalog/changes?cursor=nope", nil)
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "Invalid cursor")
+}
+
+func TestCatalogPrometheusSD(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	register := func(req *structs.RegisterRequest) {
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", req, &out))
+	}
+	register(&structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		NodeMeta:   map[string]string{"rack": "r1"},
+		Service: &structs.NodeService{
+			ID:      "web1",
+			Service: "web",
+			Tags:    []string{"a", "b"},
+			Port:    8080,
+			Meta:    map[string]string{"version": "1.2", "team-name": "edge"},
+		},
+	})
+	register(&structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "bar",
+		Address:    "127.0.0.2",
+		Service: &structs.NodeService{
+			ID:      "web2",
+			Service: "web",
+			Address: "10.0.0.2",
+			Port:    8081,
+		},
+		Check: &structs.HealthCheck{
+			Node:      "bar",
+			Name:      "web check",
+			ServiceID: "web2",
+			Status:    api.HealthCritical,
+		},
+	})
+
+	get := func(t *testing.T, query string) []prometheusTargetGroup {
+		req, _ := http.NewRequest("GET", "/v1/catalog/prometheus-sd?"+query, nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.CatalogPrometheusSD(resp, req)
+		require.NoError(t, err)
+		return obj.([]prometheusTargetGroup)
+	}
+
+	t.Run("all instances", func(t *testing.T) {
+		groups := get(t, "service=web&meta-label=version:app_version")
+		require.Len(t, groups, 2)
+		sort.Slice(groups, func(i, j int) bool {
+			return groups[i].Labels["__meta_consul_service_id"] < groups[j].Labels["__meta_consul_service_id"]
+		})
+
+		require.Equal(t, prometheusTargetGroup{
+			Targets: []string{"127.0.0.1:8080"},
+			Labels: map[string]string{
+				"__meta_consul_address":                    "127.0.0.1",
+				"__meta_consul_dc":                         "dc1",
+				"__meta_consul_health":                     api.HealthPassing,
+				"__meta_consul_metadata_rack":              "r1",
+				"__meta_consul_node":                       "foo",
+				"__meta_consul_service":                    "web",
+				"__meta_consul_service_address":            "",
+				"__meta_consul_service_id":                 "web1",
+				"__meta_consul_service_metadata_team_name": "edge",
+				"__meta_consul_service_metadata_version":   "1.2",
+				"__meta_consul_service_port":               "8080",
+				"__meta_consul_tags":                       ",a,b,",
+				"app_version":                              "1.2",
+			},
+		}, groups[0])
+		require.Equal(t, []string{"10.0.0.2:8081"}, groups[1].Targets)
+		require.Equal(t, api.HealthCritical, groups[1].Labels["__meta_consul_health"])
+		require.NotContains(t, groups[1].Labels, "app_version")
+	})
+
+	t.Run("passing", func(t *testing.T) {
+		groups := get(t, "service=web&passing")
+		require.Len(t, groups, 1)
+		require.Equal(t, []string{"127.0.0.1:8080"}, groups[0].Targets)
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		groups := get(t, "tag=a&tag=b")
+		require.Len(t, groups, 1)
+		require.Equal(t, "web1", groups[0].Labels["__meta_consul_service_id"])
+
+		require.Empty(t, get(t, "tag=a&tag=c"))
+	})
+
+	t.Run("invalid meta-label", func(t *testing.T) {
+		for _, param := range []string{"version", "version:1label", "version:__label"} {
+			req, _ := http.NewRequest("GET", "/v1/catalog/prometheus-sd?meta-label="+param, nil)
+			resp := httptest.NewRecorder()
+			a.srv.h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusBadRequest, resp.Code, param)
+			require.Contains(t, resp.Body.String(), "Invalid meta-label")
+		}
+	})
+}
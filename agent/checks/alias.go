@@ -32,6 +32,10 @@ type CheckAlias struct {
 	RPCReq  structs.NodeSpecificRequest // Base request
 	Notify  AliasNotifier               // For updating the check state
 
+	// StatusHandler, if set, applies the success and failure thresholds to
+	// the status updates.
+	StatusHandler *StatusHandler
+
 	stop     bool
 	stopCh   chan struct{}
 	stopLock sync.Mutex
@@ -211,7 +215,7 @@ func (c *CheckAlias) runQuery(stopCh chan struct{}) {
 		if err := c.RPC.RPC(context.Background(), "Health.NodeChecks", &args, &out); err != nil {
 			attempt++
 			if attempt > 1 {
-				c.Notify.UpdateCheck(c.CheckID, api.HealthCritical,
+				c.updateCheck(api.HealthCritical,
 					fmt.Sprintf("Failure checking aliased node or service: %s", err))
 			}
 
@@ -283,5 +287,13 @@ func (c *CheckAlias) processChecks(checks []*structs.HealthCheck, CheckIfService
 			health = api.HealthCritical
 		}
 	}
-	c.Notify.UpdateCheck(c.CheckID, health, msg)
+	c.updateCheck(health, msg)
+}
+
+func (c *CheckAlias) updateCheck(status, output string) {
+	if c.StatusHandler != nil {
+		c.StatusHandler.updateCheck(c.CheckID, status, output)
+		return
+	}
+	c.Notify.UpdateCheck(c.CheckID, status, output)
 }
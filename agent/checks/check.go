@@ -22,6 +22,8 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/armon/circbuf"
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
 	"github.com/hashicorp/consul/agent/exec"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
@@ -44,6 +46,26 @@ const (
 	UserAgent = "Consul Health Check"
 )
 
+var CheckCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"agent", "check", "output", "truncated"},
+		Help: "Increments whenever the output of a check exceeds the output size limit and is truncated.",
+	},
+}
+
+// truncatedOutput prefixes output that only holds the trailing part of
+// the total output with an explicit truncation marker and records the
+// truncation for the given check type.
+func truncatedOutput(checkType, output string, total int64) string {
+	recordOutputTruncated(checkType)
+	return fmt.Sprintf("Captured %d of %d bytes\n...\n%s", len(output), total, output)
+}
+
+func recordOutputTruncated(checkType string) {
+	metrics.IncrCounterWithLabels([]string{"agent", "check", "output", "truncated"}, 1,
+		[]metrics.Label{{Name: "type", Value: checkType}})
+}
+
 // RPC is an interface that an RPC client must implement. This is a helper
 // interface that is implemented by the agent delegate for checks that need
 // to make RPC calls.
@@ -145,8 +167,7 @@ func (c *CheckMonitor) check() {
 	truncateAndLogOutput := func() string {
 		outputStr := string(output.Bytes())
 		if output.TotalWritten() > output.Size() {
-			outputStr = fmt.Sprintf("Captured %d of %d bytes\n...\n%s",
-				output.Size(), output.TotalWritten(), outputStr)
+			outputStr = truncatedOutput("script", outputStr, output.TotalWritten())
 		}
 		c.Logger.Trace("Check output",
 			"check", c.CheckID.String(),
@@ -239,6 +260,10 @@ type CheckTTL struct {
 	TTL       time.Duration
 	Logger    hclog.Logger
 
+	// StatusHandler, if set, applies the success and failure thresholds
+	// to the status updates. Each missed TTL counts as a failure.
+	StatusHandler *StatusHandler
+
 	timer *time.Timer
 
 	lastOutput     string
@@ -283,7 +308,13 @@ func (c *CheckTTL) run() {
 			c.Logger.Warn("Check missed TTL, is now critical",
 				"check", c.CheckID.String(),
 			)
-			c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, c.getExpiredOutput())
+			c.updateCheck(api.HealthCritical, c.getExpiredOutput())
+
+			// Keep counting missed TTLs until the failure threshold is
+			// reached, otherwise the check could never become critical.
+			if c.StatusHandler != nil && !c.StatusHandler.isCritical() {
+				c.timer.Reset(c.TTL)
+			}
 
 		case <-c.stopCh:
 			return
@@ -314,10 +345,11 @@ func (c *CheckTTL) SetStatus(status, output string) string {
 	)
 	total := len(output)
 	if total > c.OutputMaxSize {
+		recordOutputTruncated("ttl")
 		output = fmt.Sprintf("%s ... (captured %d of %d bytes)",
 			output[:c.OutputMaxSize], c.OutputMaxSize, total)
 	}
-	c.Notify.UpdateCheck(c.CheckID, status, output)
+	c.updateCheck(status, output)
 	// Store the last output so we can retain it if the TTL expires.
 	c.lastOutputLock.Lock()
 	c.lastOutput = output
@@ -327,6 +359,14 @@ func (c *CheckTTL) SetStatus(status, output string) string {
 	return output
 }
 
+func (c *CheckTTL) updateCheck(status, output string) {
+	if c.StatusHandler != nil {
+		c.StatusHandler.updateCheck(c.CheckID, status, output)
+		return
+	}
+	c.Notify.UpdateCheck(c.CheckID, status, output)
+}
+
 // CheckHTTP is used to periodically make an HTTP request to
 // determine the health of a given check.
 // The check is passing if the response code is 2XX.
@@ -498,7 +538,11 @@ func (c *CheckHTTP) check() {
 	}
 
 	// Format the response body
-	result := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, target, resp.Status, output.String())
+	body := output.String()
+	if output.TotalWritten() > output.Size() {
+		body = truncatedOutput("http", body, output.TotalWritten())
+	}
+	result := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, target, resp.Status, body)
 
 	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
 		// PASSING (2xx)
@@ -837,6 +881,9 @@ func (c *CheckUDP) check() {
 // determine the health of an application running inside a
 // Docker Container. We assume that the script is compatible
 // with nagios plugins and expects the output in the same format.
+// When no script is given, the container is inspected instead
+// and its health is the one reported by its HEALTHCHECK, or
+// whether it is running if it has none.
 // Supports failures_before_critical and success_before_passing.
 type CheckDocker struct {
 	CheckID           structs.CheckID
@@ -895,6 +942,12 @@ func (c *CheckDocker) run() {
 }
 
 func (c *CheckDocker) check() {
+	if c.Script == "" && len(c.ScriptArgs) == 0 {
+		status, out := c.doInspect()
+		c.StatusHandler.updateCheck(c.CheckID, status, out)
+		return
+	}
+
 	var out string
 	status, b, err := c.doCheck()
 	if err != nil {
@@ -909,7 +962,7 @@ func (c *CheckDocker) check() {
 		// that it was truncated.
 		out = string(b.Bytes())
 		if int(b.TotalWritten()) > len(out) {
-			out = fmt.Sprintf("Captured %d of %d bytes\n...\n%s", len(out), b.TotalWritten(), out)
+			out = truncatedOutput("docker", out, b.TotalWritten())
 		}
 		c.Logger.Trace("Check output",
 			"check", c.CheckID.String(),
@@ -960,6 +1013,43 @@ func (c *CheckDocker) doCheck() (string, *circbuf.Buffer, error) {
 	}
 }
 
+// doInspect returns the health of the container from its state.
+func (c *CheckDocker) doInspect() (string, string) {
+	state, err := c.Client.InspectContainer(c.DockerContainerID)
+	if err != nil {
+		c.Logger.Debug("Check failed",
+			"check", c.CheckID.String(),
+			"error", err,
+		)
+		return api.HealthCritical, err.Error()
+	}
+
+	if state.Health == nil || state.Health.Status == "" || state.Health.Status == "none" {
+		if state.Running {
+			return api.HealthPassing, fmt.Sprintf("Container %s is running", c.DockerContainerID)
+		}
+		return api.HealthCritical, fmt.Sprintf("Container %s is %s", c.DockerContainerID, state.Status)
+	}
+
+	out := fmt.Sprintf("Container %s is %s", c.DockerContainerID, state.Health.Status)
+	if n := len(state.Health.Log); n > 0 {
+		if output := strings.TrimSpace(state.Health.Log[n-1].Output); output != "" {
+			out += ": " + output
+		}
+	}
+	if total := int64(len(out)); total > c.Client.maxbuf {
+		out = truncatedOutput("docker", out[total-c.Client.maxbuf:], total)
+	}
+	switch state.Health.Status {
+	case "healthy":
+		return api.HealthPassing, out
+	default:
+		// "starting" containers are critical until their first health
+		// check passes, as Consul checks are until their first run.
+		return api.HealthCritical, out
+	}
+}
+
 // CheckGRPC is used to periodically send request to a gRPC server
 // application that implements gRPC health-checking protocol.
 // The check is passing if returned status is SERVING.
@@ -970,6 +1060,8 @@ type CheckGRPC struct {
 	CheckID         structs.CheckID
 	ServiceID       structs.ServiceID
 	GRPC            string
+	Service         string // Overrides the service in GRPC
+	Authority       string // Overrides the :authority derived from GRPC
 	Interval        time.Duration
 	Timeout         time.Duration
 	TLSClientConfig *tls.Config
@@ -988,11 +1080,13 @@ type CheckGRPC struct {
 
 func (c *CheckGRPC) CheckType() structs.CheckType {
 	return structs.CheckType{
-		CheckID:   c.CheckID.ID,
-		GRPC:      c.GRPC,
-		ProxyGRPC: c.ProxyGRPC,
-		Interval:  c.Interval,
-		Timeout:   c.Timeout,
+		CheckID:       c.CheckID.ID,
+		GRPC:          c.GRPC,
+		GRPCService:   c.Service,
+		GRPCAuthority: c.Authority,
+		ProxyGRPC:     c.ProxyGRPC,
+		Interval:      c.Interval,
+		Timeout:       c.Timeout,
 	}
 }
 
@@ -1003,7 +1097,7 @@ func (c *CheckGRPC) Start() {
 	if c.Timeout > 0 {
 		timeout = c.Timeout
 	}
-	c.probe = NewGrpcHealthProbe(c.GRPC, timeout, c.TLSClientConfig)
+	c.probe = NewGrpcHealthProbe(c.GRPC, c.Service, c.Authority, timeout, c.TLSClientConfig)
 	c.stop = false
 	c.stopCh = make(chan struct{})
 	go c.run()
@@ -1170,6 +1264,10 @@ func (c *CheckOSService) check() {
 // that status can be set to critical/passing only once the successive number of event
 // reaches the given threshold.
 type StatusHandler struct {
+	// lock guards the counters since checks such as TTL checks can be
+	// updated concurrently.
+	lock sync.Mutex
+
 	inner                  CheckNotifier
 	logger                 hclog.Logger
 	successBeforePassing   int
@@ -1193,6 +1291,8 @@ func NewStatusHandler(inner CheckNotifier, logger hclog.Logger, successBeforePas
 }
 
 func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	if status == api.HealthPassing || status == api.HealthWarning {
 		s.successCounter++
@@ -1234,3 +1334,11 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 		)
 	}
 }
+
+// isCritical returns true if enough consecutive failures have been seen for
+// the check to be critical.
+func (s *StatusHandler) isCritical() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.failuresCounter >= s.failuresBeforeCritical
+}
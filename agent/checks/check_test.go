@@ -277,6 +277,42 @@ func TestCheckTTL(t *testing.T) {
 	}
 }
 
+func TestCheckTTL_Thresholds(t *testing.T) {
+	t.Parallel()
+	notif := mock.NewNotify()
+	logger := testutil.Logger(t)
+	cid := structs.NewCheckID("foo", nil)
+
+	check := &CheckTTL{
+		Notify:        notif,
+		CheckID:       cid,
+		TTL:           50 * time.Millisecond,
+		Logger:        logger,
+		StatusHandler: NewStatusHandler(notif, logger, 0, 2, 3),
+	}
+	check.Start()
+	defer check.Stop()
+
+	check.SetStatus(api.HealthPassing, "test-output")
+	require.Equal(t, 1, notif.Updates(cid))
+	require.Equal(t, api.HealthPassing, notif.State(cid))
+
+	// The first missed TTL is only a single failure, the second one makes
+	// the check warning and the third one critical.
+	retry.Run(t, func(r *retry.R) {
+		require.Equal(r, 3, notif.Updates(cid))
+		require.Equal(r, api.HealthCritical, notif.State(cid))
+	})
+
+	// Once critical the TTL isn't rearmed until the status is set again.
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, 3, notif.Updates(cid))
+
+	check.SetStatus(api.HealthPassing, "test-output")
+	require.Equal(t, 4, notif.Updates(cid))
+	require.Equal(t, api.HealthPassing, notif.State(cid))
+}
+
 func TestCheckHTTP(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -615,7 +651,7 @@ func TestCheckMaxOutputSize(t *testing.T) {
 		if got, want := notif.State(cid), api.HealthPassing; got != want {
 			r.Fatalf("got state %q want %q", got, want)
 		}
-		if got, want := notif.Output(cid), "HTTP GET "+server.URL+"/v1/agent/self: 200 OK Output: "+strings.Repeat("x", maxOutputSize); got != want {
+		if got, want := notif.Output(cid), "HTTP GET "+server.URL+"/v1/agent/self: 200 OK Output: Captured 32 of 8192 bytes\n...\n"+strings.Repeat("x", maxOutputSize); got != want {
 			r.Fatalf("got state %q want %q", got, want)
 		}
 	})
@@ -1806,3 +1842,92 @@ func TestCheck_Docker(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_DockerInspect(t *testing.T) {
+	tests := []struct {
+		desc   string
+		code   int
+		body   string
+		output string
+		state  string
+	}{
+		{
+			desc:   "unknown container",
+			code:   404,
+			output: "inspect container failed for unknown container 123",
+			state:  api.HealthCritical,
+		},
+		{
+			desc:   "running without healthcheck",
+			code:   200,
+			body:   `{"Id":"123","State":{"Status":"running","Running":true}}`,
+			output: "Container 123 is running",
+			state:  api.HealthPassing,
+		},
+		{
+			desc:   "exited without healthcheck",
+			code:   200,
+			body:   `{"Id":"123","State":{"Status":"exited","Running":false}}`,
+			output: "Container 123 is exited",
+			state:  api.HealthCritical,
+		},
+		{
+			desc:   "healthy",
+			code:   200,
+			body:   `{"Id":"123","State":{"Status":"running","Running":true,"Health":{"Status":"healthy","Log":[{"ExitCode":0,"Output":"OK\n"}]}}}`,
+			output: "Container 123 is healthy: OK",
+			state:  api.HealthPassing,
+		},
+		{
+			desc:   "starting",
+			code:   200,
+			body:   `{"Id":"123","State":{"Status":"running","Running":true,"Health":{"Status":"starting"}}}`,
+			output: "Container 123 is starting",
+			state:  api.HealthCritical,
+		},
+		{
+			desc:   "unhealthy",
+			code:   200,
+			body:   `{"Id":"123","State":{"Status":"running","Running":true,"Health":{"Status":"unhealthy","Log":[{"ExitCode":0,"Output":"OK"},{"ExitCode":1,"Output":"NOK"}]}}}`,
+			output: "Container 123 is unhealthy: NOK",
+			state:  api.HealthCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if x := r.Method + " " + r.RequestURI; x != "GET /containers/123/json" {
+					t.Errorf("bad url %s", x)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.code)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			// the inspection response is larger than the output buffer
+			c, err := NewDockerClient(srv.URL, 64)
+			require.NoError(t, err)
+
+			notif, upd := mock.NewNotifyChan()
+			statusHandler := NewStatusHandler(notif, testutil.Logger(t), 0, 0, 0)
+			id := structs.NewCheckID("chk", nil)
+
+			check := &CheckDocker{
+				CheckID:           id,
+				DockerContainerID: "123",
+				Interval:          25 * time.Millisecond,
+				Client:            c,
+				StatusHandler:     statusHandler,
+			}
+			check.Start()
+			defer check.Stop()
+
+			<-upd // wait for update
+
+			require.Equal(t, tt.output, notif.Output(id))
+			require.Equal(t, tt.state, notif.State(id))
+		})
+	}
+}
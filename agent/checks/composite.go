@@ -0,0 +1,175 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/types"
+)
+
+// CheckComposite is a check type that aggregates the health of other checks
+// registered for the same service instance on this agent.
+//
+// The number of aggregated checks that must be passing is determined by Mode:
+// all of them for "and" and at least one for "or". MinPassing overrides this
+// when set. If not enough checks are passing but enough are passing or
+// warning, this check is warning. Otherwise it is critical.
+type CheckComposite struct {
+	CheckID   structs.CheckID   // ID of this check
+	ServiceID structs.ServiceID // ID of the service whose checks are aggregated
+
+	// Checks are the IDs of the checks to aggregate. If empty, all of the other
+	// non-composite checks of the service are aggregated.
+	Checks     []types.CheckID
+	Mode       string
+	MinPassing int
+
+	Notify AliasNotifier // For updating the check state
+
+	// StatusHandler, if set, applies the success and failure thresholds to
+	// the status updates.
+	StatusHandler *StatusHandler
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+	stopWg   sync.WaitGroup
+
+	acl.EnterpriseMeta
+}
+
+// Start is used to start the check, runs until Stop()
+func (c *CheckComposite) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	c.stopWg.Add(1)
+	go c.run(c.stopCh)
+}
+
+// Stop is used to stop the check.
+func (c *CheckComposite) Stop() {
+	c.stopLock.Lock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+	c.stopLock.Unlock()
+
+	// Wait until the goroutine is complete so that a replacement check can't
+	// race with this one when updating the status.
+	c.stopWg.Wait()
+}
+
+// run is invoked in a goroutine until Stop() is called.
+func (c *CheckComposite) run(stopCh chan struct{}) {
+	defer c.stopWg.Done()
+
+	// Buffered as 1 so that we do not lose any queued updates, see
+	// CheckAlias.runLocal.
+	notifyCh := make(chan struct{}, 1)
+	c.Notify.AddAliasCheck(c.CheckID, c.ServiceID, notifyCh)
+	defer c.Notify.RemoveAliasCheck(c.CheckID, c.ServiceID)
+
+	// Re-evaluate periodically in case we miss an edge triggered event.
+	const maxDurationBetweenUpdates = 1 * time.Minute
+
+	for {
+		checks := c.Notify.Checks(c.WithWildcardNamespace())
+		health, msg := c.evaluate(checks)
+		c.updateCheck(health, msg)
+
+		select {
+		case <-time.After(maxDurationBetweenUpdates):
+		case <-notifyCh:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (c *CheckComposite) updateCheck(status, output string) {
+	if c.StatusHandler != nil {
+		c.StatusHandler.updateCheck(c.CheckID, status, output)
+		return
+	}
+	c.Notify.UpdateCheck(c.CheckID, status, output)
+}
+
+// evaluate computes the status and output of the composite check from the
+// current state of the local checks.
+func (c *CheckComposite) evaluate(checks map[structs.CheckID]*structs.HealthCheck) (string, string) {
+	var aggregated []*structs.HealthCheck
+	var failures []string
+
+	if len(c.Checks) == 0 {
+		for id, chk := range checks {
+			if id == c.CheckID || chk.Type == "composite" {
+				continue
+			}
+			if !c.ServiceID.Matches(chk.CompoundServiceID()) {
+				continue
+			}
+			aggregated = append(aggregated, chk)
+		}
+		if len(aggregated) == 0 {
+			return api.HealthCritical, "No checks found to aggregate."
+		}
+	} else {
+		for _, id := range c.Checks {
+			chk, ok := checks[structs.NewCheckID(id, &c.EnterpriseMeta)]
+			switch {
+			case !ok || !c.ServiceID.Matches(chk.CompoundServiceID()):
+				failures = append(failures, fmt.Sprintf("Check %q not found for service %q", id, c.ServiceID.ID))
+			case chk.Type == "composite":
+				failures = append(failures, fmt.Sprintf("Check %q is a composite check and cannot be aggregated", id))
+			default:
+				aggregated = append(aggregated, chk)
+			}
+		}
+	}
+
+	total := len(aggregated) + len(failures)
+	required := total
+	if c.Mode == structs.CompositeModeOr {
+		required = 1
+	}
+	if c.MinPassing > 0 {
+		required = c.MinPassing
+	}
+
+	var passing, warning int
+	for _, chk := range aggregated {
+		switch chk.Status {
+		case api.HealthPassing:
+			passing++
+		case api.HealthWarning:
+			warning++
+			failures = append(failures, fmt.Sprintf("Check %q is warning: %s", chk.CheckID, chk.Output))
+		default:
+			failures = append(failures, fmt.Sprintf("Check %q is %s: %s", chk.CheckID, chk.Status, chk.Output))
+		}
+	}
+	sort.Strings(failures)
+
+	health := api.HealthCritical
+	switch {
+	case passing >= required:
+		health = api.HealthPassing
+	case passing+warning >= required:
+		health = api.HealthWarning
+	}
+
+	msg := fmt.Sprintf("%d of %d checks passing, %d required.", passing, total, required)
+	if len(failures) > 0 {
+		msg += "\n" + strings.Join(failures, "\n")
+	}
+	return health, msg
+}
@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/types"
+)
+
+func TestCheckComposite_evaluate(t *testing.T) {
+	t.Parallel()
+
+	webID := structs.ServiceID{ID: "web"}
+	checks := func(statuses map[string]string) map[structs.CheckID]*structs.HealthCheck {
+		out := map[structs.CheckID]*structs.HealthCheck{
+			// Checks for other services and composite checks are never aggregated.
+			structs.NewCheckID("db-check", nil): {
+				CheckID:   "db-check",
+				ServiceID: "db",
+				Status:    api.HealthCritical,
+			},
+			structs.NewCheckID("other-composite", nil): {
+				CheckID:   "other-composite",
+				ServiceID: "web",
+				Type:      "composite",
+				Status:    api.HealthCritical,
+			},
+		}
+		for id, status := range statuses {
+			out[structs.NewCheckID(types.CheckID(id), nil)] = &structs.HealthCheck{
+				CheckID:   types.CheckID(id),
+				ServiceID: "web",
+				Status:    status,
+			}
+		}
+		return out
+	}
+
+	cases := map[string]struct {
+		mode       string
+		minPassing int
+		ids        []types.CheckID
+		statuses   map[string]string
+		expect     string
+	}{
+		"and all passing": {
+			mode:     structs.CompositeModeAnd,
+			statuses: map[string]string{"a": api.HealthPassing, "b": api.HealthPassing},
+			expect:   api.HealthPassing,
+		},
+		"and one warning": {
+			mode:     structs.CompositeModeAnd,
+			statuses: map[string]string{"a": api.HealthPassing, "b": api.HealthWarning},
+			expect:   api.HealthWarning,
+		},
+		"and one critical": {
+			mode:     structs.CompositeModeAnd,
+			statuses: map[string]string{"a": api.HealthWarning, "b": api.HealthCritical},
+			expect:   api.HealthCritical,
+		},
+		"or one passing": {
+			mode:     structs.CompositeModeOr,
+			statuses: map[string]string{"a": api.HealthCritical, "b": api.HealthPassing},
+			expect:   api.HealthPassing,
+		},
+		"or one warning": {
+			mode:     structs.CompositeModeOr,
+			statuses: map[string]string{"a": api.HealthCritical, "b": api.HealthWarning},
+			expect:   api.HealthWarning,
+		},
+		"or all critical": {
+			mode:     structs.CompositeModeOr,
+			statuses: map[string]string{"a": api.HealthCritical, "b": api.HealthCritical},
+			expect:   api.HealthCritical,
+		},
+		"min passing met": {
+			mode:       structs.CompositeModeOr,
+			minPassing: 2,
+			statuses:   map[string]string{"a": api.HealthPassing, "b": api.HealthPassing, "c": api.HealthCritical},
+			expect:     api.HealthPassing,
+		},
+		"min passing not met": {
+			mode:       structs.CompositeModeOr,
+			minPassing: 2,
+			statuses:   map[string]string{"a": api.HealthPassing, "b": api.HealthCritical, "c": api.HealthCritical},
+			expect:     api.HealthCritical,
+		},
+		"explicit checks": {
+			mode:     structs.CompositeModeAnd,
+			ids:      []types.CheckID{"a"},
+			statuses: map[string]string{"a": api.HealthPassing, "b": api.HealthCritical},
+			expect:   api.HealthPassing,
+		},
+		"explicit missing check": {
+			mode:     structs.CompositeModeAnd,
+			ids:      []types.CheckID{"a", "missing"},
+			statuses: map[string]string{"a": api.HealthPassing},
+			expect:   api.HealthCritical,
+		},
+		"explicit check of other service": {
+			mode:     structs.CompositeModeOr,
+			ids:      []types.CheckID{"db-check"},
+			statuses: map[string]string{"a": api.HealthPassing},
+			expect:   api.HealthCritical,
+		},
+		"no checks": {
+			mode:   structs.CompositeModeOr,
+			expect: api.HealthCritical,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			chk := &CheckComposite{
+				CheckID:    structs.NewCheckID("composite", nil),
+				ServiceID:  webID,
+				Checks:     tc.ids,
+				Mode:       tc.mode,
+				MinPassing: tc.minPassing,
+			}
+			status, _ := chk.evaluate(checks(tc.statuses))
+			require.Equal(t, tc.expect, status)
+		})
+	}
+}
+
+type mockCompositeNotify struct {
+	*mockAliasNotify
+	checks map[structs.CheckID]*structs.HealthCheck
+}
+
+func (m *mockCompositeNotify) Checks(*acl.EnterpriseMeta) map[structs.CheckID]*structs.HealthCheck {
+	return m.checks
+}
+
+func TestCheckComposite_Start(t *testing.T) {
+	t.Parallel()
+
+	notify := &mockCompositeNotify{
+		mockAliasNotify: newMockAliasNotify(),
+		checks: map[structs.CheckID]*structs.HealthCheck{
+			structs.NewCheckID("a", nil): {CheckID: "a", ServiceID: "web", Status: api.HealthPassing},
+			structs.NewCheckID("b", nil): {CheckID: "b", ServiceID: "web", Status: api.HealthWarning},
+		},
+	}
+	chkID := structs.NewCheckID("composite", nil)
+	chk := &CheckComposite{
+		CheckID:   chkID,
+		ServiceID: structs.ServiceID{ID: "web"},
+		Mode:      structs.CompositeModeOr,
+		Notify:    notify,
+	}
+
+	chk.Start()
+	defer chk.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthPassing; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+	require.Contains(t, notify.Output(chkID), "1 of 2 checks passing, 1 required.")
+}
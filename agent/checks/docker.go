@@ -86,10 +86,25 @@ func ParseHost(host string) (string, string, string, error) {
 }
 
 func (c *DockerClient) call(method, uri string, v interface{}) (*circbuf.Buffer, int, error) {
-	req, err := http.NewRequest(method, uri, nil)
+	resp, err := c.do(method, uri, v)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	b, err := circbuf.NewBuffer(c.maxbuf)
 	if err != nil {
 		return nil, 0, err
 	}
+	_, err = io.Copy(b, resp.Body)
+	return b, resp.StatusCode, err
+}
+
+func (c *DockerClient) do(method, uri string, v interface{}) (*http.Response, error) {
+	req, err := http.NewRequest(method, uri, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	if c.proto == "unix" || c.proto == "npipe" {
 		// For local communications, it doesn't matter what the host is. We just
@@ -103,24 +118,13 @@ func (c *DockerClient) call(method, uri string, v interface{}) (*circbuf.Buffer,
 	if v != nil {
 		var b bytes.Buffer
 		if err := json.NewEncoder(&b).Encode(v); err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		req.Body = io.NopCloser(&b)
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer resp.Body.Close()
-
-	b, err := circbuf.NewBuffer(c.maxbuf)
-	if err != nil {
-		return nil, 0, err
-	}
-	_, err = io.Copy(b, resp.Body)
-	return b, resp.StatusCode, err
+	return c.client.Do(req)
 }
 
 func (c *DockerClient) CreateExec(containerID string, cmd []string) (string, error) {
@@ -198,3 +202,45 @@ func (c *DockerClient) InspectExec(containerID, execID string) (int, error) {
 		return 0, fmt.Errorf("inspect exec failed for container %s with status %d: %s", containerID, code, b)
 	}
 }
+
+// DockerContainerState is the state of a container, as returned by the
+// container inspection endpoint.
+type DockerContainerState struct {
+	Status  string
+	Running bool
+	Health  *DockerContainerHealth
+}
+
+// DockerContainerHealth is the result of the HEALTHCHECK of a container.
+type DockerContainerHealth struct {
+	Status string
+	Log    []struct {
+		ExitCode int
+		Output   string
+	}
+}
+
+func (c *DockerClient) InspectContainer(containerID string) (*DockerContainerState, error) {
+	// The response is not read through the ring buffer since the whole
+	// description of the container is needed to decode it.
+	uri := fmt.Sprintf("/containers/%s/json", url.QueryEscape(containerID))
+	resp, err := c.do("GET", uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("inspect container failed for container %s: %s", containerID, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var body struct{ State DockerContainerState }
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return nil, fmt.Errorf("inspect container response for container %s cannot be parsed: %v", containerID, err)
+		}
+		return &body.State, nil
+	case 404:
+		return nil, fmt.Errorf("inspect container failed for unknown container %s", containerID)
+	default:
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, c.maxbuf))
+		return nil, fmt.Errorf("inspect container failed for container %s with status %d: %s", containerID, resp.StatusCode, b)
+	}
+}
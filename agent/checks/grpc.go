@@ -22,14 +22,19 @@ type GrpcHealthProbe struct {
 }
 
 // NewGrpcHealthProbe constructs GrpcHealthProbe from target string in format
-// server[/service]
-// If service is omitted, health of the entire application is probed
-func NewGrpcHealthProbe(target string, timeout time.Duration, tlsConfig *tls.Config) *GrpcHealthProbe {
-	serverAndService := strings.SplitN(target, "/", 2)
-
+// server[/service] or unix:///path/to/socket.
+// If service is omitted, health of the entire application is probed. A
+// non-empty service overrides the one in the target, which is the only way to
+// probe a specific service over a unix socket. A non-empty authority overrides
+// the :authority header sent to the server, which is otherwise derived from
+// the target.
+func NewGrpcHealthProbe(target, service, authority string, timeout time.Duration, tlsConfig *tls.Config) *GrpcHealthProbe {
 	request := hv1.HealthCheckRequest{}
-	if len(serverAndService) > 1 {
-		request.Service = serverAndService[1]
+	if _, targetService := splitGRPCTarget(target); targetService != "" {
+		request.Service = targetService
+	}
+	if service != "" {
+		request.Service = service
 	}
 
 	var dialOptions = []grpc.DialOption{}
@@ -40,6 +45,9 @@ func NewGrpcHealthProbe(target string, timeout time.Duration, tlsConfig *tls.Con
 		//nolint:staticcheck
 		dialOptions = append(dialOptions, grpc.WithInsecure())
 	}
+	if authority != "" {
+		dialOptions = append(dialOptions, grpc.WithAuthority(authority))
+	}
 
 	return &GrpcHealthProbe{
 		request:     &request,
@@ -48,11 +56,30 @@ func NewGrpcHealthProbe(target string, timeout time.Duration, tlsConfig *tls.Con
 	}
 }
 
+// splitGRPCTarget splits a gRPC check target into the address to dial and the
+// service to probe. Unix socket targets are passed through to the unix
+// resolver and never contain a service since socket paths contain slashes.
+func splitGRPCTarget(target string) (string, string) {
+	if IsUnixGRPCTarget(target) {
+		return target, ""
+	}
+	serverAndService := strings.SplitN(target, "/", 2)
+	server := fmt.Sprintf("%s:///%s", resolver.GetDefaultScheme(), serverAndService[0])
+	if len(serverAndService) > 1 {
+		return server, serverAndService[1]
+	}
+	return server, ""
+}
+
+// IsUnixGRPCTarget returns true if the gRPC check target is a unix socket.
+func IsUnixGRPCTarget(target string) bool {
+	return strings.HasPrefix(target, "unix:")
+}
+
 // Check if the target of this GrpcHealthProbe is healthy
 // If nil is returned, target is healthy, otherwise target is not healthy
 func (probe *GrpcHealthProbe) Check(target string) error {
-	serverAndService := strings.SplitN(target, "/", 2)
-	serverWithScheme := fmt.Sprintf("%s:///%s", resolver.GetDefaultScheme(), serverAndService[0])
+	serverWithScheme, _ := splitGRPCTarget(target)
 
 	ctx, cancel := context.WithTimeout(context.Background(), probe.timeout)
 	defer cancel()
@@ -8,18 +8,21 @@ import (
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul/agent/mock"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/go-hclog"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	hv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
 )
 
 var (
@@ -94,7 +97,7 @@ func TestCheck(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			probe := NewGrpcHealthProbe(tt.args.target, tt.args.timeout, tt.args.tlsConfig)
+			probe := NewGrpcHealthProbe(tt.args.target, "", "", tt.args.timeout, tt.args.tlsConfig)
 			actualError := probe.Check(tt.args.target)
 			actuallyHealthy := actualError == nil
 			if tt.healthy != actuallyHealthy {
@@ -171,3 +174,68 @@ func TestGRPC_NotProxied(t *testing.T) {
 		}
 	})
 }
+
+func TestCheck_UnixSocket(t *testing.T) {
+	socket := filepath.Join(testutil.TempDir(t, "grpc"), "health.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	srv := health.NewServer()
+	srv.SetServingStatus("healthy", hv1.HealthCheckResponse_SERVING)
+	srv.SetServingStatus("unhealthy", hv1.HealthCheckResponse_NOT_SERVING)
+	hv1.RegisterHealthServer(grpcServer, srv)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	target := "unix://" + socket
+	tests := []struct {
+		name      string
+		service   string
+		authority string
+		healthy   bool
+	}{
+		{"should pass for healthy server", "", "", true},
+		{"should pass for healthy service", "healthy", "", true},
+		{"should pass with authority override", "healthy", "api.example.com", true},
+		{"should fail for unhealthy service", "unhealthy", "", false},
+		{"should fail for missing service", "missing", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := NewGrpcHealthProbe(target, tt.service, tt.authority, time.Second, nil)
+			actualError := probe.Check(target)
+			actuallyHealthy := actualError == nil
+			if tt.healthy != actuallyHealthy {
+				t.Errorf("FAIL: %s. Expected healthy %t, but err == %v", tt.name, tt.healthy, actualError)
+			}
+		})
+	}
+}
+
+func TestSplitGRPCTarget(t *testing.T) {
+	scheme := resolver.GetDefaultScheme()
+	tests := []struct {
+		target  string
+		server  string
+		service string
+	}{
+		{"localhost:5000", scheme + ":///localhost:5000", ""},
+		{"localhost:5000/foo", scheme + ":///localhost:5000", "foo"},
+		{"localhost:5000/foo/bar", scheme + ":///localhost:5000", "foo/bar"},
+		{"unix:///var/run/app.sock", "unix:///var/run/app.sock", ""},
+		{"unix:relative/app.sock", "unix:relative/app.sock", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			server, service := splitGRPCTarget(tt.target)
+			if server != tt.server {
+				t.Errorf("got server %q want %q", server, tt.server)
+			}
+			if service != tt.service {
+				t.Errorf("got service %q want %q", service, tt.service)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package checks
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+)
+
+// pingSeq is the sequence number of the last ICMP echo request sent by a
+// CheckPing.
+var pingSeq uint32
+
+// CheckPing is used to periodically send an ICMP echo request to a host to
+// determine its health. The check is passing if a reply is received before
+// the timeout, and critical otherwise.
+//
+// It uses unprivileged ICMP sockets when the system allows them (see the
+// net.ipv4.ping_group_range sysctl on Linux), and falls back to raw sockets,
+// which require privileges.
+type CheckPing struct {
+	CheckID       structs.CheckID
+	Address       string
+	Interval      time.Duration
+	Timeout       time.Duration
+	Logger        hclog.Logger
+	StatusHandler *StatusHandler
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+}
+
+// Start is used to start a ping check.
+// The check runs until stop is called
+func (c *CheckPing) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a ping check.
+func (c *CheckPing) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckPing) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the ping check
+func (c *CheckPing) check() {
+	rtt, err := ping(c.Address, c.Timeout)
+	if err != nil {
+		c.Logger.Warn("Check ping failed",
+			"check", c.CheckID.String(),
+			"error", err,
+		)
+		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, fmt.Sprintf("Ping %s: reply in %s", c.Address, rtt))
+}
+
+// ping sends an ICMP echo request to addr and waits for the reply.
+func ping(addr string, timeout time.Duration) (time.Duration, error) {
+	ip, err := net.ResolveIPAddr("ip", addr)
+	if err != nil {
+		return 0, err
+	}
+
+	network, rawNetwork, protocol := "udp4", "ip4:icmp", 1
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.IP.To4() == nil {
+		network, rawNetwork, protocol = "udp6", "ip6:ipv6-icmp", 58
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+
+	var dst net.Addr = &net.UDPAddr{IP: ip.IP, Zone: ip.Zone}
+	conn, err := icmp.ListenPacket(network, "")
+	if err != nil {
+		dst = ip
+		if conn, err = icmp.ListenPacket(rawNetwork, ""); err != nil {
+			return 0, fmt.Errorf("failed to open an ICMP socket: %w", err)
+		}
+	}
+	defer conn.Close()
+
+	// The kernel replaces the ID of the requests sent on unprivileged
+	// sockets, so the replies are matched on their sequence number.
+	seq := int(atomic.AddUint32(&pingSeq, 1) & 0xffff)
+	msg := icmp.Message{
+		Type: requestType,
+		Body: &icmp.Echo{
+			ID:   os.Getpid() & 0xffff,
+			Seq:  seq,
+			Data: []byte("consul"),
+		},
+	}
+	request, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.WriteTo(request, dst); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		if echo, ok := reply.Body.(*icmp.Echo); ok && echo.Seq == seq {
+			return time.Since(start), nil
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+)
+
+// CheckProcess is used to periodically check that a process is still
+// running. The process is identified by its PID, by a file containing its
+// PID, or by a cgroup.
+//
+// If only a cgroup is given the check is passing as long as the cgroup
+// contains at least one process. If a cgroup is given along with a PID or
+// pidfile, the process must also still be a member of the cgroup. This guards
+// against the PID being reused by an unrelated process.
+type CheckProcess struct {
+	CheckID       structs.CheckID
+	ServiceID     structs.ServiceID
+	PID           int
+	PIDFile       string
+	Cgroup        string
+	Interval      time.Duration
+	Logger        hclog.Logger
+	StatusHandler *StatusHandler
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+	stopWg   sync.WaitGroup
+}
+
+func (c *CheckProcess) CheckType() structs.CheckType {
+	return structs.CheckType{
+		CheckID:        c.CheckID.ID,
+		ProcessPID:     c.PID,
+		ProcessPIDFile: c.PIDFile,
+		ProcessCgroup:  c.Cgroup,
+		Interval:       c.Interval,
+	}
+}
+
+// Start is used to start a process check.
+// The check runs until stop is called
+func (c *CheckProcess) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	c.stopWg.Add(1)
+	go c.run()
+}
+
+// Stop is used to stop a process check.
+func (c *CheckProcess) Stop() {
+	c.stopLock.Lock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+	c.stopLock.Unlock()
+
+	// Wait for the c.run() goroutine to complete before returning.
+	c.stopWg.Wait()
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckProcess) run() {
+	defer c.stopWg.Done()
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the process check
+func (c *CheckProcess) check() {
+	out, err := c.doCheck()
+	if err != nil {
+		c.Logger.Debug("Check failed",
+			"check", c.CheckID.String(),
+			"error", err,
+		)
+		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, out)
+}
+
+func (c *CheckProcess) doCheck() (string, error) {
+	if c.PID == 0 && c.PIDFile == "" {
+		found, err := cgroupHasProcesses(c.Cgroup)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read cgroup %q: %v", c.Cgroup, err)
+		}
+		if !found {
+			return "", fmt.Errorf("No processes running in cgroup %q", c.Cgroup)
+		}
+		return fmt.Sprintf("Processes running in cgroup %q", c.Cgroup), nil
+	}
+
+	pid := c.PID
+	if c.PIDFile != "" {
+		var err error
+		pid, err = readPIDFile(c.PIDFile)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	running, err := processExists(pid)
+	if err != nil {
+		return "", fmt.Errorf("Failed to check process %d: %v", pid, err)
+	}
+	if !running {
+		return "", fmt.Errorf("Process %d is not running", pid)
+	}
+
+	if c.Cgroup != "" {
+		member, err := processInCgroup(pid, c.Cgroup)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read cgroups of process %d: %v", pid, err)
+		}
+		if !member {
+			return "", fmt.Errorf("Process %d is not in cgroup %q", pid, c.Cgroup)
+		}
+	}
+	return fmt.Sprintf("Process %d is running", pid), nil
+}
+
+// readPIDFile returns the PID stored in the given file.
+func readPIDFile(path string) (int, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to read pidfile: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("Invalid PID in pidfile %q", path)
+	}
+	return pid, nil
+}
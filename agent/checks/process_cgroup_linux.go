@@ -0,0 +1,54 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// These are variables so that tests can use a fake filesystem layout.
+var (
+	procRoot   = "/proc"
+	cgroupRoot = "/sys/fs/cgroup"
+)
+
+// processInCgroup returns whether the process is a member of the given cgroup
+// or one of its descendants, in either the cgroup v1 or v2 hierarchies.
+func processInCgroup(pid int, cgroup string) (bool, error) {
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	cgroup = "/" + strings.Trim(cgroup, "/")
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines are formatted as hierarchy-ID:controller-list:cgroup-path.
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if path == cgroup || strings.HasPrefix(path, cgroup+"/") {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// cgroupHasProcesses returns whether the given cgroup v2 cgroup contains any
+// processes.
+func cgroupHasProcesses(cgroup string) (bool, error) {
+	buf, err := os.ReadFile(filepath.Join(cgroupRoot, cgroup, "cgroup.procs"))
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(buf)) > 0, nil
+}
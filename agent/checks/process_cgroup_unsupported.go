@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import "fmt"
+
+func processInCgroup(pid int, cgroup string) (bool, error) {
+	return false, fmt.Errorf("cgroups are only supported on Linux")
+}
+
+func cgroupHasProcesses(cgroup string) (bool, error) {
+	return false, fmt.Errorf("cgroups are only supported on Linux")
+}
@@ -0,0 +1,144 @@
+package checks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+// exitedPID returns the PID of a process that has already exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	// The exit status is irrelevant here; only the PID of the reaped process
+	// is needed.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	_ = cmd.Run()
+	require.NotNil(t, cmd.ProcessState)
+	return cmd.Process.Pid
+}
+
+func TestCheckProcess_doCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := testutil.TempDir(t, "process-check")
+	writePIDFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	cases := map[string]struct {
+		check *CheckProcess
+		err   string
+	}{
+		"running pid": {
+			check: &CheckProcess{PID: os.Getpid()},
+		},
+		"exited pid": {
+			check: &CheckProcess{PID: exitedPID(t)},
+			err:   "is not running",
+		},
+		"pidfile": {
+			check: &CheckProcess{PIDFile: writePIDFile("running", strconv.Itoa(os.Getpid())+"\n")},
+		},
+		"missing pidfile": {
+			check: &CheckProcess{PIDFile: filepath.Join(dir, "missing")},
+			err:   "Failed to read pidfile",
+		},
+		"invalid pidfile": {
+			check: &CheckProcess{PIDFile: writePIDFile("invalid", "not-a-pid")},
+			err:   "Invalid PID in pidfile",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.check.doCheck()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestCheckProcess_Cgroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are only supported on Linux")
+	}
+
+	dir := testutil.TempDir(t, "process-check")
+	oldProcRoot, oldCgroupRoot := procRoot, cgroupRoot
+	procRoot = filepath.Join(dir, "proc")
+	cgroupRoot = filepath.Join(dir, "cgroup")
+	t.Cleanup(func() {
+		procRoot, cgroupRoot = oldProcRoot, oldCgroupRoot
+	})
+
+	pid := os.Getpid()
+	procDir := filepath.Join(procRoot, strconv.Itoa(pid))
+	require.NoError(t, os.MkdirAll(procDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(procDir, "cgroup"),
+		[]byte("0::/system.slice/web.service/main\n"), 0600))
+
+	webDir := filepath.Join(cgroupRoot, "system.slice", "web.service")
+	require.NoError(t, os.MkdirAll(webDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, "cgroup.procs"), []byte(strconv.Itoa(pid)+"\n"), 0600))
+
+	dbDir := filepath.Join(cgroupRoot, "system.slice", "db.service")
+	require.NoError(t, os.MkdirAll(dbDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "cgroup.procs"), nil, 0600))
+
+	_, err := (&CheckProcess{PID: pid, Cgroup: "/system.slice/web.service"}).doCheck()
+	require.NoError(t, err)
+
+	_, err = (&CheckProcess{PID: pid, Cgroup: "/system.slice/db.service"}).doCheck()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not in cgroup")
+
+	_, err = (&CheckProcess{Cgroup: "/system.slice/web.service"}).doCheck()
+	require.NoError(t, err)
+
+	_, err = (&CheckProcess{Cgroup: "/system.slice/db.service"}).doCheck()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "No processes running")
+}
+
+func TestCheckProcess(t *testing.T) {
+	t.Parallel()
+
+	notif := mock.NewNotify()
+	logger := testutil.Logger(t)
+	statusHandler := NewStatusHandler(notif, logger, 0, 0, 0)
+
+	cid := structs.NewCheckID("foo", nil)
+	check := &CheckProcess{
+		CheckID:       cid,
+		PID:           exitedPID(t),
+		Interval:      10 * time.Millisecond,
+		Logger:        logger,
+		StatusHandler: statusHandler,
+	}
+	check.Start()
+	defer check.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notif.State(cid), api.HealthCritical; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package checks
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processExists returns whether a process with the given PID is running.
+func processExists(pid int) (bool, error) {
+	// Signal 0 performs the existence and permission checks without actually
+	// sending a signal. EPERM means the process exists but belongs to another
+	// user.
+	err := syscall.Kill(pid, syscall.Signal(0))
+	switch {
+	case err == nil, errors.Is(err, syscall.EPERM):
+		return true, nil
+	case errors.Is(err, syscall.ESRCH):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code reported by GetExitCodeProcess for a process
+// that has not exited yet.
+const stillActive = 259
+
+// processExists returns whether a process with the given PID is running.
+func processExists(pid int) (bool, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		if errors.Is(err, windows.ERROR_INVALID_PARAMETER) {
+			return false, nil
+		}
+		if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false, err
+	}
+	return code == stillActive, nil
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/consul/agent/consul"
+	middleware "github.com/hashicorp/consul/agent/grpc-middleware"
+)
+
+// clientCertToken returns the secret of the ACL token of a client which
+// presented the verified chain of certificates, or an empty string if the
+// client certificates can't be used to login. Only the servers login with
+// them, since they are the ones who verified the chain.
+func (a *Agent) clientCertToken(chain []*x509.Certificate) (string, error) {
+	srv, ok := a.delegate.(*consul.Server)
+	if !ok {
+		return "", errors.New("client certificate logins are only supported by the servers")
+	}
+	return srv.ClientCertToken(chain)
+}
+
+// clientCertLoginInterceptor returns the interceptors setting the token of
+// the calls to the external gRPC server made with a client certificate, or
+// nil if no auth method is configured to login with them.
+func (a *Agent) clientCertLoginInterceptor() *middleware.ClientCertLoginInterceptor {
+	if !a.config.ACLsEnabled || a.config.ACLClientCertAuthMethod == "" {
+		return nil
+	}
+	return &middleware.ClientCertLoginInterceptor{Login: a.clientCertToken}
+}
+
+// clientCertLogin sets the token of the requests made with a client
+// certificate but without a token, as if it was provided in the
+// X-Consul-Token header.
+func (s *HTTPHandlers) clientCertLogin(req *http.Request) error {
+	if !s.agent.config.ACLsEnabled || s.agent.config.ACLClientCertAuthMethod == "" {
+		return nil
+	}
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+		return nil
+	}
+
+	var token string
+	s.parseTokenInternal(req, &token)
+	if token != "" {
+		return nil
+	}
+
+	token, err := s.agent.clientCertToken(req.TLS.VerifiedChains[0])
+	if err != nil {
+		return HTTPError{StatusCode: http.StatusForbidden, Reason: err.Error()}
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+	return nil
+}
@@ -27,12 +27,15 @@ import (
 
 	hcpconfig "github.com/hashicorp/consul/agent/hcp/config"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/connect/ca"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/consul/authmethod/ssoauth"
+	"github.com/hashicorp/consul/agent/consul/changesink"
 	consulrate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/debug"
 	"github.com/hashicorp/consul/agent/dns"
 	"github.com/hashicorp/consul/agent/rpc/middleware"
 	"github.com/hashicorp/consul/agent/structs"
@@ -66,6 +69,10 @@ type LoadOpts struct {
 	// independent of their extension. Value may be `hcl` or `json`.
 	ConfigFormat string
 
+	// Interpolation lists the environment variables and files that may be
+	// referenced from ConfigFiles and HCL.
+	Interpolation Interpolation
+
 	// DevMode indicates whether the agent should be started in development
 	// mode. This cannot be configured in a config file.
 	DevMode *bool
@@ -200,9 +207,10 @@ func newBuilder(opts LoadOpts) (*builder, error) {
 	b.Tail = append(b.Tail, LiteralSource{Name: "flags.values", Config: values})
 	for i, s := range opts.HCL {
 		b.Tail = append(b.Tail, FileSource{
-			Name:   fmt.Sprintf("flags-%d.hcl", i),
-			Format: "hcl",
-			Data:   s,
+			Name:          fmt.Sprintf("flags-%d.hcl", i),
+			Format:        "hcl",
+			Data:          s,
+			Interpolation: opts.Interpolation,
 		})
 	}
 	b.Tail = append(b.Tail, NonUserSource(), DefaultConsulSource(), OverrideEnterpriseSource(), defaultVersionSource())
@@ -235,7 +243,7 @@ func (b *builder) sourcesFromPath(path string, format string) ([]Source, error)
 			return nil, fmt.Errorf("file %v has unknown extension; must be .hcl or .json, or config format must be set", path)
 		}
 
-		src, err := newSourceFromFile(path, format)
+		src, err := newSourceFromFile(path, format, b.opts.Interpolation)
 		if err != nil {
 			return nil, err
 		}
@@ -274,7 +282,7 @@ func (b *builder) sourcesFromPath(path string, format string) ([]Source, error)
 			b.warn("skipping file %v, extension must be .hcl or .json, or config format must be set", fp)
 			continue
 		}
-		src, err := newSourceFromFile(fp, format)
+		src, err := newSourceFromFile(fp, format, b.opts.Interpolation)
 		if err != nil {
 			return nil, err
 		}
@@ -284,7 +292,7 @@ func (b *builder) sourcesFromPath(path string, format string) ([]Source, error)
 }
 
 // newSourceFromFile creates a Source from the contents of the file at path.
-func newSourceFromFile(path string, format string) (Source, error) {
+func newSourceFromFile(path string, format string, interpolation Interpolation) (Source, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("config: failed to read %s: %s", path, err)
@@ -292,7 +300,7 @@ func newSourceFromFile(path string, format string) (Source, error) {
 	if format == "" {
 		format = formatFromFileExtension(path)
 	}
-	return FileSource{Name: path, Data: string(data), Format: format}, nil
+	return FileSource{Name: path, Data: string(data), Format: format, Interpolation: interpolation}, nil
 }
 
 // shouldParse file determines whether the file to be read is of a supported extension
@@ -386,6 +394,31 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		dnsServiceTTL[k] = b.durationVal(fmt.Sprintf("dns_config.service_ttl[%q]", k), &v)
 	}
 
+	dnsVirtualAnswers := map[string]dns.VirtualAnswer{}
+	for k, v := range c.DNS.VirtualAnswers {
+		dnsVirtualAnswers[k] = b.dnsVirtualAnswerVal(fmt.Sprintf("dns_config.virtual_answers[%q]", k), v)
+	}
+
+	dnsNodeTTL := b.durationVal("dns_config.node_ttl", c.DNS.NodeTTL)
+	dnsVirtualTTL := dnsNodeTTL
+	if c.DNS.VirtualTTL != nil {
+		dnsVirtualTTL = b.durationVal("dns_config.virtual_ttl", c.DNS.VirtualTTL)
+	}
+
+	var dnsViews []RuntimeDNSView
+	for _, v := range c.DNS.Views {
+		onlyPassing := boolVal(c.DNS.OnlyPassing)
+		if v.OnlyPassing != nil {
+			onlyPassing = *v.OnlyPassing
+		}
+		dnsViews = append(dnsViews, RuntimeDNSView{
+			Domain:      stringVal(v.Domain),
+			OnlyPassing: onlyPassing,
+			Tags:        v.Tags,
+			ExcludeTags: v.ExcludeTags,
+		})
+	}
+
 	soa := RuntimeSOAConfig{Refresh: 3600, Retry: 600, Expire: 86400, Minttl: 0}
 	if c.DNS.SOA != nil {
 		if c.DNS.SOA.Expire != nil {
@@ -438,6 +471,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 	// determine port values and replace values <= 0 and > 65535 with -1
 	dnsPort := b.portVal("ports.dns", c.Ports.DNS)
+	dnsTLSPort := b.portVal("ports.dns_tls", c.Ports.DNSTLS)
+	dnsHTTPSPort := b.portVal("ports.dns_https", c.Ports.DNSHTTPS)
 	httpPort := b.portVal("ports.http", c.Ports.HTTP)
 	httpsPort := b.portVal("ports.https", c.Ports.HTTPS)
 	serverPort := b.portVal("ports.server", c.Ports.Server)
@@ -574,6 +609,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		b.warn("client_addr is empty, client services (DNS, HTTP, HTTPS, GRPC) will not be listening for connections")
 	}
 	dnsAddrs := b.makeAddrs(b.expandAddrs("addresses.dns", c.Addresses.DNS), clientAddrs, dnsPort)
+	dnsTLSAddrs := b.makeAddrs(b.expandAddrs("addresses.dns_tls", c.Addresses.DNSTLS), clientAddrs, dnsTLSPort)
+	dnsHTTPSAddrs := b.makeAddrs(b.expandAddrs("addresses.dns_https", c.Addresses.DNSHTTPS), clientAddrs, dnsHTTPSPort)
 	httpAddrs := b.makeAddrs(b.expandAddrs("addresses.http", c.Addresses.HTTP), clientAddrs, httpPort)
 	httpsAddrs := b.makeAddrs(b.expandAddrs("addresses.https", c.Addresses.HTTPS), clientAddrs, httpsPort)
 	grpcAddrs := b.makeAddrs(b.expandAddrs("addresses.grpc", c.Addresses.GRPC), clientAddrs, grpcPort)
@@ -661,6 +698,14 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 	// Parse the metric filters
 	telemetryAllowedPrefixes, telemetryBlockedPrefixes := b.parsePrefixFilter(&c.Telemetry)
 
+	// gossip profile
+	gossipLANProfileName := stringValWithDefault(c.GossipLAN.Profile, consul.GossipProfileDefault)
+	gossipLANProfile, ok := consul.GossipProfiles[gossipLANProfileName]
+	if !ok {
+		return RuntimeConfig{}, fmt.Errorf("gossip_lan.profile must be one of %q, %q or %q, got %q",
+			consul.GossipProfileDefault, consul.GossipProfileLarge, consul.GossipProfileXLarge, gossipLANProfileName)
+	}
+
 	// raft performance scaling
 	performanceRaftMultiplier := intVal(c.Performance.RaftMultiplier)
 	if performanceRaftMultiplier < 1 || uint(performanceRaftMultiplier) > consul.MaxRaftMultiplier {
@@ -831,18 +876,20 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		ConsulServerHealthInterval:       b.durationVal("consul.server.health_interval", c.Consul.Server.HealthInterval),
 
 		// gossip configuration
-		GossipLANGossipInterval: b.durationVal("gossip_lan..gossip_interval", c.GossipLAN.GossipInterval),
-		GossipLANGossipNodes:    intVal(c.GossipLAN.GossipNodes),
-		GossipLANProbeInterval:  b.durationVal("gossip_lan..probe_interval", c.GossipLAN.ProbeInterval),
-		GossipLANProbeTimeout:   b.durationVal("gossip_lan..probe_timeout", c.GossipLAN.ProbeTimeout),
-		GossipLANSuspicionMult:  intVal(c.GossipLAN.SuspicionMult),
-		GossipLANRetransmitMult: intVal(c.GossipLAN.RetransmitMult),
-		GossipWANGossipInterval: b.durationVal("gossip_wan..gossip_interval", c.GossipWAN.GossipInterval),
-		GossipWANGossipNodes:    intVal(c.GossipWAN.GossipNodes),
-		GossipWANProbeInterval:  b.durationVal("gossip_wan..probe_interval", c.GossipWAN.ProbeInterval),
-		GossipWANProbeTimeout:   b.durationVal("gossip_wan..probe_timeout", c.GossipWAN.ProbeTimeout),
-		GossipWANSuspicionMult:  intVal(c.GossipWAN.SuspicionMult),
-		GossipWANRetransmitMult: intVal(c.GossipWAN.RetransmitMult),
+		GossipLANProfile:          gossipLANProfileName,
+		GossipLANGossipInterval:   b.durationValWithDefault("gossip_lan..gossip_interval", c.GossipLAN.GossipInterval, gossipLANProfile.GossipInterval),
+		GossipLANGossipNodes:      intValWithDefault(c.GossipLAN.GossipNodes, gossipLANProfile.GossipNodes),
+		GossipLANProbeInterval:    b.durationValWithDefault("gossip_lan..probe_interval", c.GossipLAN.ProbeInterval, gossipLANProfile.ProbeInterval),
+		GossipLANProbeTimeout:     b.durationValWithDefault("gossip_lan..probe_timeout", c.GossipLAN.ProbeTimeout, gossipLANProfile.ProbeTimeout),
+		GossipLANSuspicionMult:    intValWithDefault(c.GossipLAN.SuspicionMult, gossipLANProfile.SuspicionMult),
+		GossipLANRetransmitMult:   intValWithDefault(c.GossipLAN.RetransmitMult, gossipLANProfile.RetransmitMult),
+		GossipLANPushPullInterval: b.durationValWithDefault("gossip_lan..push_pull_interval", c.GossipLAN.PushPullInterval, gossipLANProfile.PushPullInterval),
+		GossipWANGossipInterval:   b.durationVal("gossip_wan..gossip_interval", c.GossipWAN.GossipInterval),
+		GossipWANGossipNodes:      intVal(c.GossipWAN.GossipNodes),
+		GossipWANProbeInterval:    b.durationVal("gossip_wan..probe_interval", c.GossipWAN.ProbeInterval),
+		GossipWANProbeTimeout:     b.durationVal("gossip_wan..probe_timeout", c.GossipWAN.ProbeTimeout),
+		GossipWANSuspicionMult:    intVal(c.GossipWAN.SuspicionMult),
+		GossipWANRetransmitMult:   intVal(c.GossipWAN.RetransmitMult),
 
 		// ACL
 		ACLsEnabled: aclsEnabled,
@@ -859,6 +906,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 		ACLEnableKeyListPolicy:    boolVal(c.ACL.EnableKeyListPolicy),
 		ACLInitialManagementToken: stringVal(c.ACL.Tokens.InitialManagement),
+		ACLClientCertAuthMethod:   stringVal(c.ACL.ClientCertAuthMethod),
 
 		ACLTokenReplication: boolVal(c.ACL.TokenReplication),
 
@@ -878,31 +926,46 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		AutopilotLastContactThreshold:    b.durationVal("autopilot.last_contact_threshold", c.Autopilot.LastContactThreshold),
 		AutopilotMaxTrailingLogs:         intVal(c.Autopilot.MaxTrailingLogs),
 		AutopilotMinQuorum:               uintVal(c.Autopilot.MinQuorum),
+		AutopilotMinServerUptime:         b.durationVal("autopilot.min_server_uptime", c.Autopilot.MinServerUptime),
+		AutopilotPromotionStrategy:       stringVal(c.Autopilot.PromotionStrategy),
 		AutopilotRedundancyZoneTag:       stringVal(c.Autopilot.RedundancyZoneTag),
 		AutopilotServerStabilizationTime: b.durationVal("autopilot.server_stabilization_time", c.Autopilot.ServerStabilizationTime),
 		AutopilotUpgradeVersionTag:       stringVal(c.Autopilot.UpgradeVersionTag),
+		AutopilotVotersPerZone:           intVal(c.Autopilot.VotersPerZone),
+		AutopilotZoneMetaKey:             stringVal(c.Autopilot.ZoneMetaKey),
 
 		// DNS
-		DNSAddrs:              dnsAddrs,
-		DNSAllowStale:         boolVal(c.DNS.AllowStale),
-		DNSARecordLimit:       intVal(c.DNS.ARecordLimit),
-		DNSDisableCompression: boolVal(c.DNS.DisableCompression),
-		DNSDomain:             stringVal(c.DNSDomain),
-		DNSAltDomain:          altDomain,
-		DNSEnableTruncate:     boolVal(c.DNS.EnableTruncate),
-		DNSMaxStale:           b.durationVal("dns_config.max_stale", c.DNS.MaxStale),
-		DNSNodeTTL:            b.durationVal("dns_config.node_ttl", c.DNS.NodeTTL),
-		DNSOnlyPassing:        boolVal(c.DNS.OnlyPassing),
-		DNSPort:               dnsPort,
-		DNSRecursorStrategy:   b.dnsRecursorStrategyVal(stringVal(c.DNS.RecursorStrategy)),
-		DNSRecursorTimeout:    b.durationVal("recursor_timeout", c.DNS.RecursorTimeout),
-		DNSRecursors:          dnsRecursors,
-		DNSServiceTTL:         dnsServiceTTL,
-		DNSSOA:                soa,
-		DNSUDPAnswerLimit:     intVal(c.DNS.UDPAnswerLimit),
-		DNSNodeMetaTXT:        boolValWithDefault(c.DNS.NodeMetaTXT, true),
-		DNSUseCache:           boolVal(c.DNS.UseCache),
-		DNSCacheMaxAge:        b.durationVal("dns_config.cache_max_age", c.DNS.CacheMaxAge),
+		DNSAddrs:               dnsAddrs,
+		DNSAllowStale:          boolVal(c.DNS.AllowStale),
+		DNSARecordLimit:        intVal(c.DNS.ARecordLimit),
+		DNSDisableCompression:  boolVal(c.DNS.DisableCompression),
+		DNSDomain:              stringVal(c.DNSDomain),
+		DNSAltDomain:           altDomain,
+		DNSPeerDomain:          stringVal(c.DNS.PeerDomain),
+		DNSViews:               dnsViews,
+		DNSEnableTruncate:      boolVal(c.DNS.EnableTruncate),
+		DNSMaxStale:            b.durationVal("dns_config.max_stale", c.DNS.MaxStale),
+		DNSNodeTTL:             dnsNodeTTL,
+		DNSOnlyPassing:         boolVal(c.DNS.OnlyPassing),
+		DNSPort:                dnsPort,
+		DNSTLSAddrs:            dnsTLSAddrs,
+		DNSTLSPort:             dnsTLSPort,
+		DNSHTTPSAddrs:          dnsHTTPSAddrs,
+		DNSHTTPSPort:           dnsHTTPSPort,
+		DNSRecursorStrategy:    b.dnsRecursorStrategyVal(stringVal(c.DNS.RecursorStrategy)),
+		DNSRecursorTimeout:     b.durationVal("recursor_timeout", c.DNS.RecursorTimeout),
+		DNSRecursors:           dnsRecursors,
+		DNSServiceTTL:          dnsServiceTTL,
+		DNSVirtualAnswers:      dnsVirtualAnswers,
+		DNSVirtualTTL:          dnsVirtualTTL,
+		DNSServiceMetricsLimit: intVal(c.DNS.ServiceMetricsLimit),
+		DNSSOA:                 soa,
+		DNSUDPAnswerLimit:      intVal(c.DNS.UDPAnswerLimit),
+		DNSNodeMetaTXT:         boolValWithDefault(c.DNS.NodeMetaTXT, true),
+		DNSUseCache:            boolVal(c.DNS.UseCache),
+		DNSCacheMaxAge:         b.durationVal("dns_config.cache_max_age", c.DNS.CacheMaxAge),
+		DNSAnswerCacheTTL:      b.durationVal("dns_config.answer_cache_ttl", c.DNS.AnswerCacheTTL),
+		DNSAnswerCacheStale:    b.durationVal("dns_config.answer_cache_stale_if_error", c.DNS.AnswerCacheStale),
 
 		// HTTP
 		HTTPPort:            httpPort,
@@ -938,6 +1001,17 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			AllowedPrefixes:                    telemetryAllowedPrefixes,
 			BlockedPrefixes:                    telemetryBlockedPrefixes,
 			MetricsPrefix:                      stringVal(c.Telemetry.MetricsPrefix),
+			OTLPEndpoint:                       stringVal(c.Telemetry.OTLPEndpoint),
+			OTLPProtocol:                       stringVal(c.Telemetry.OTLPProtocol),
+			OTLPInsecure:                       boolVal(c.Telemetry.OTLPInsecure),
+			OTLPHeaders:                        c.Telemetry.OTLPHeaders,
+			OTLPExportInterval:                 b.durationVal("telemetry.otlp_export_interval", c.Telemetry.OTLPExportInterval),
+			OTLPTemporality:                    stringVal(c.Telemetry.OTLPTemporality),
+			OTLPResourceAttributes:             c.Telemetry.OTLPResourceAttributes,
+			OTLPLogs:                           boolVal(c.Telemetry.OTLPLogs),
+			RPCServiceLabels:                   boolVal(c.Telemetry.RPCServiceLabels),
+			RPCPeerLabels:                      boolVal(c.Telemetry.RPCPeerLabels),
+			RPCLabelsMaxValues:                 intVal(c.Telemetry.RPCLabelsMaxValues),
 			StatsdAddr:                         stringVal(c.Telemetry.StatsdAddr),
 			StatsiteAddr:                       stringVal(c.Telemetry.StatsiteAddr),
 			PrometheusOpts: prometheus.PrometheusOpts{
@@ -950,6 +1024,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		AdvertiseAddrLAN:          advertiseAddrLAN,
 		AdvertiseAddrWAN:          advertiseAddrWAN,
 		AdvertiseReconnectTimeout: b.durationVal("advertise_reconnect_timeout", c.AdvertiseReconnectTimeout),
+		AESyncBatchSize:           intVal(c.AESyncBatchSize),
+		AESyncRateLimit:           float64Val(c.AESyncRateLimit),
 		BindAddr:                  bindAddr,
 		Bootstrap:                 boolVal(c.Bootstrap),
 		BootstrapExpect:           intVal(c.BootstrapExpect),
@@ -960,17 +1036,21 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			EntryFetchMaxBurst: intValWithDefault(
 				c.Cache.EntryFetchMaxBurst, cache.DefaultEntryFetchMaxBurst,
 			),
+			PersistPath: cachePersistPath(c.Cache.Persist, dataDir),
 		},
+		ChangeSinks:                            b.changeSinksVal(c.ChangeSinks),
 		AutoReloadConfig:                       boolVal(c.AutoReloadConfig),
 		CheckUpdateInterval:                    b.durationVal("check_update_interval", c.CheckUpdateInterval),
 		CheckOutputMaxSize:                     intValWithDefault(c.CheckOutputMaxSize, 4096),
 		Checks:                                 checks,
 		ClientAddrs:                            clientAddrs,
 		ConfigEntryBootstrap:                   configEntries,
+		Audit:                                  b.auditVal(c.Audit),
 		AutoEncryptTLS:                         boolVal(c.AutoEncrypt.TLS),
 		AutoEncryptDNSSAN:                      autoEncryptDNSSAN,
 		AutoEncryptIPSAN:                       autoEncryptIPSAN,
 		AutoEncryptAllowTLS:                    autoEncryptAllowTLS,
+		AutoEncryptVault:                       b.autoEncryptVaultVal(c.AutoEncrypt.Vault),
 		AutoConfig:                             autoConfig,
 		Cloud:                                  b.cloudConfigVal(c.Cloud),
 		ConnectEnabled:                         connectEnabled,
@@ -996,6 +1076,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		DiscardCheckOutput:                     boolVal(c.DiscardCheckOutput),
 
 		DiscoveryMaxStale:          b.durationVal("discovery_max_stale", c.DiscoveryMaxStale),
+		DockerHost:                 stringVal(c.DockerHost),
 		EnableAgentTLSForChecks:    boolVal(c.EnableAgentTLSForChecks),
 		EnableCentralServiceConfig: boolVal(c.EnableCentralServiceConfig),
 		EnableDebug:                boolVal(c.EnableDebug),
@@ -1008,9 +1089,11 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		GRPCTLSPort:                grpcTlsPort,
 		HTTPMaxConnsPerClient:      intVal(c.Limits.HTTPMaxConnsPerClient),
 		HTTPSHandshakeTimeout:      b.durationVal("limits.https_handshake_timeout", c.Limits.HTTPSHandshakeTimeout),
+		KVCompressionThreshold:     intVal(c.KVCompressionThreshold),
 		KVMaxValueSize:             uint64Val(c.Limits.KVMaxValueSize),
 		LeaveDrainTime:             b.durationVal("performance.leave_drain_time", c.Performance.LeaveDrainTime),
 		LeaveOnTerm:                leaveOnTerm,
+		LeaveServiceDrainTime:      b.durationVal("leave_service_drain_time", c.LeaveServiceDrainTime),
 		StaticRuntimeConfig: StaticRuntimeConfig{
 			EncryptVerifyIncoming: boolVal(c.EncryptVerifyIncoming),
 			EncryptVerifyOutgoing: boolVal(c.EncryptVerifyOutgoing),
@@ -1027,16 +1110,20 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			LogRotateMaxFiles: intVal(c.LogRotateMaxFiles),
 		},
 		MaxQueryTime:                      b.durationVal("max_query_time", c.MaxQueryTime),
+		NamedPipeSecurityDescriptor:       stringVal(c.NamedPipe.SecurityDescriptor),
 		NodeID:                            types.NodeID(stringVal(c.NodeID)),
 		NodeMeta:                          c.NodeMeta,
 		NodeName:                          b.nodeName(c.NodeName),
 		ReadReplica:                       boolVal(c.ReadReplica),
 		PeeringEnabled:                    boolVal(c.Peering.Enabled),
 		PeeringTestAllowPeerRegistrations: boolValWithDefault(c.Peering.TestAllowPeerRegistrations, false),
+		PeeringIncomingMessageRate:        limitVal(c.Peering.IncomingMessageRate),
+		PeeringIncomingMessageBurst:       intVal(c.Peering.IncomingMessageBurst),
 		PidFile:                           stringVal(c.PidFile),
 		PrimaryDatacenter:                 primaryDatacenter,
 		PrimaryGateways:                   b.expandAllOptionalAddrs("primary_gateways", c.PrimaryGateways),
 		PrimaryGatewaysInterval:           b.durationVal("primary_gateways_interval", c.PrimaryGatewaysInterval),
+		Profiling:                         b.profilingVal(c.Profiling),
 		RPCAdvertiseAddr:                  rpcAdvertiseAddr,
 		RPCBindAddr:                       rpcBindAddr,
 		RPCHandshakeTimeout:               b.durationVal("limits.rpc_handshake_timeout", c.Limits.RPCHandshakeTimeout),
@@ -1052,12 +1139,22 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		RaftSnapshotInterval:              b.durationVal("raft_snapshot_interval", c.RaftSnapshotInterval),
 		RaftTrailingLogs:                  intVal(c.RaftTrailingLogs),
 		RaftLogStoreConfig:                b.raftLogStoreConfigVal(&c.RaftLogStore),
+		RaftCompression:                   b.raftCompressionVal(c.RaftCompression),
+		EncryptRotation:                   b.encryptRotationVal(c.EncryptRotation),
+		ExternalHealthChecks:              b.externalHealthChecksVal(c.ExternalHealthChecks),
 		ReconnectTimeoutLAN:               b.durationVal("reconnect_timeout", c.ReconnectTimeoutLAN),
 		ReconnectTimeoutWAN:               b.durationVal("reconnect_timeout_wan", c.ReconnectTimeoutWAN),
 		RejoinAfterLeave:                  boolVal(c.RejoinAfterLeave),
 		RequestLimitsMode:                 b.requestsLimitsModeVal(stringVal(c.Limits.RequestLimits.Mode)),
 		RequestLimitsReadRate:             limitVal(c.Limits.RequestLimits.ReadRate),
 		RequestLimitsWriteRate:            limitVal(c.Limits.RequestLimits.WriteRate),
+		RequestLimitsIPReadRate:           limitVal(c.Limits.RequestLimits.PerIP.ReadRate),
+		RequestLimitsIPWriteRate:          limitVal(c.Limits.RequestLimits.PerIP.WriteRate),
+		RequestLimitsIPOverrides:          b.requestLimitsIPOverridesVal(c.Limits.RequestLimits.PerIP),
+		RequestLimitsTokenReadRate:        limitVal(c.Limits.RequestLimits.PerToken.ReadRate),
+		RequestLimitsTokenWriteRate:       limitVal(c.Limits.RequestLimits.PerToken.WriteRate),
+		RequestLimitsTokenOverrides:       b.requestLimitsTokenOverridesVal(c.Limits.RequestLimits.PerToken),
+		RetryJoinFilters:                  c.RetryJoinFilters,
 		RetryJoinIntervalLAN:              b.durationVal("retry_interval", c.RetryJoinIntervalLAN),
 		RetryJoinIntervalWAN:              b.durationVal("retry_interval_wan", c.RetryJoinIntervalWAN),
 		RetryJoinLAN:                      b.expandAllOptionalAddrs("retry_join", c.RetryJoinLAN),
@@ -1078,7 +1175,10 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		ServerMode:                        serverMode,
 		ServerName:                        stringVal(c.ServerName),
 		ServerPort:                        serverPort,
+		ServiceTombstoneTTL:               b.durationVal("service_tombstone_ttl", c.ServiceTombstoneTTL),
 		Services:                          services,
+		ServicesAutoReloadDir:             stringVal(c.ServicesAutoReload.Dir),
+		ServicesAutoReloadDebounce:        b.durationValWithDefault("services_auto_reload.debounce", c.ServicesAutoReload.Debounce, time.Second),
 		SessionTTLMin:                     b.durationVal("session_ttl_min", c.SessionTTLMin),
 		SkipLeaveOnInt:                    skipLeaveOnInt,
 		TaggedAddresses:                   c.TaggedAddresses,
@@ -1090,6 +1190,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		UnixSocketUser:                    stringVal(c.UnixSocket.User),
 		Watches:                           c.Watches,
 		XDSUpdateRateLimit:                limitVal(c.XDS.UpdateMaxPerSecond),
+		XDSStrictEnvoyVersion:             boolVal(c.XDS.StrictEnvoyVersion),
 		AutoReloadConfigCoalesceInterval:  1 * time.Second,
 	}
 
@@ -1109,6 +1210,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 	}
 
 	rt.UseStreamingBackend = boolValWithDefault(c.UseStreamingBackend, true)
+	rt.UseStreamingBackendCatalogServices = boolVal(c.UseStreamingBackendCatalogServices)
 
 	if rt.Cache.EntryFetchMaxBurst <= 0 {
 		return RuntimeConfig{}, fmt.Errorf("cache.entry_fetch_max_burst must be strictly positive, was: %v", rt.Cache.EntryFetchMaxBurst)
@@ -1179,6 +1281,37 @@ func validateBasicName(field, value string, allowEmpty bool) error {
 	return nil
 }
 
+// validateOTLPTelemetry validates the settings of the OTLP metrics and log
+// sinks.
+func validateOTLPTelemetry(telemetry lib.TelemetryConfig) error {
+	if telemetry.OTLPLogs && telemetry.OTLPEndpoint == "" {
+		return fmt.Errorf("telemetry.otlp_logs requires telemetry.otlp_endpoint to be set")
+	}
+	switch telemetry.OTLPProtocol {
+	case lib.OTLPProtocolGRPC, lib.OTLPProtocolHTTP:
+	default:
+		return fmt.Errorf("telemetry.otlp_protocol must be %q or %q. received: %q",
+			lib.OTLPProtocolGRPC, lib.OTLPProtocolHTTP, telemetry.OTLPProtocol)
+	}
+	switch telemetry.OTLPTemporality {
+	case lib.OTLPTemporalityCumulative, lib.OTLPTemporalityDelta:
+	default:
+		return fmt.Errorf("telemetry.otlp_temporality must be %q or %q. received: %q",
+			lib.OTLPTemporalityCumulative, lib.OTLPTemporalityDelta, telemetry.OTLPTemporality)
+	}
+	if telemetry.OTLPExportInterval <= 0 {
+		return fmt.Errorf("telemetry.otlp_export_interval must be greater than 0")
+	}
+	if telemetry.OTLPEndpoint != "" && telemetry.OTLPProtocol == lib.OTLPProtocolHTTP && strings.Contains(telemetry.OTLPEndpoint, "://") {
+		u, err := url.Parse(telemetry.OTLPEndpoint)
+		if err != nil || !(u.Scheme == "http" || u.Scheme == "https") {
+			return fmt.Errorf("telemetry.otlp_endpoint must be a valid http or https URL. received: %q",
+				telemetry.OTLPEndpoint)
+		}
+	}
+	return nil
+}
+
 // validate performs semantic validation of the runtime configuration.
 func (b *builder) validate(rt RuntimeConfig) error {
 	// validContentPath defines a regexp for a valid content path name.
@@ -1286,6 +1419,25 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		if _, ok := a.(*net.UnixAddr); ok {
 			return fmt.Errorf("DNS address cannot be a unix socket")
 		}
+		if _, ok := a.(*NamedPipeAddr); ok {
+			return fmt.Errorf("DNS address cannot be a named pipe")
+		}
+	}
+	for _, a := range rt.DNSTLSAddrs {
+		if _, ok := a.(*net.UnixAddr); ok {
+			return fmt.Errorf("DNS-over-TLS address cannot be a unix socket")
+		}
+		if _, ok := a.(*NamedPipeAddr); ok {
+			return fmt.Errorf("DNS-over-TLS address cannot be a named pipe")
+		}
+	}
+	for _, a := range rt.DNSHTTPSAddrs {
+		if _, ok := a.(*net.UnixAddr); ok {
+			return fmt.Errorf("DNS-over-HTTPS address cannot be a unix socket")
+		}
+		if _, ok := a.(*NamedPipeAddr); ok {
+			return fmt.Errorf("DNS-over-HTTPS address cannot be a named pipe")
+		}
 	}
 	for _, a := range rt.DNSRecursors {
 		if ipaddr.IsAny(a) {
@@ -1295,6 +1447,39 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if !isValidAltDomain(rt.DNSAltDomain, rt.Datacenter) {
 		return fmt.Errorf("alt_domain cannot start with {service,connect,node,query,addr,%s}", rt.Datacenter)
 	}
+	normalizeDomain := func(domain string) string {
+		return strings.TrimSuffix(strings.ToLower(domain), ".")
+	}
+	if rt.DNSPeerDomain != "" {
+		if !isValidAltDomain(rt.DNSPeerDomain, rt.Datacenter) {
+			return fmt.Errorf("dns_config.peer_domain cannot start with {service,connect,node,query,addr,%s}", rt.Datacenter)
+		}
+		peerDomain := normalizeDomain(rt.DNSPeerDomain)
+		if peerDomain == normalizeDomain(rt.DNSDomain) || peerDomain == normalizeDomain(rt.DNSAltDomain) {
+			return fmt.Errorf("dns_config.peer_domain must be different from domain and alt_domain")
+		}
+	}
+	dnsDomains := map[string]struct{}{
+		normalizeDomain(rt.DNSDomain):     {},
+		normalizeDomain(rt.DNSAltDomain):  {},
+		normalizeDomain(rt.DNSPeerDomain): {},
+	}
+	if rt.DNSServiceMetricsLimit < 0 {
+		return fmt.Errorf("dns_config.service_metrics_limit cannot be negative")
+	}
+	for i, view := range rt.DNSViews {
+		if view.Domain == "" {
+			return fmt.Errorf("dns_config.views[%d].domain is required", i)
+		}
+		if !isValidAltDomain(view.Domain, rt.Datacenter) {
+			return fmt.Errorf("dns_config.views[%d].domain cannot start with {service,connect,node,query,addr,%s}", i, rt.Datacenter)
+		}
+		domain := normalizeDomain(view.Domain)
+		if _, ok := dnsDomains[domain]; ok {
+			return fmt.Errorf("dns_config.views[%d].domain %q is already used by another domain", i, view.Domain)
+		}
+		dnsDomains[domain] = struct{}{}
+	}
 	if rt.Bootstrap && !rt.ServerMode {
 		return fmt.Errorf("'bootstrap = true' requires 'server = true'")
 	}
@@ -1316,9 +1501,30 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if rt.AEInterval <= 0 {
 		return fmt.Errorf("ae_interval cannot be %s. Must be positive", rt.AEInterval)
 	}
+	if rt.AESyncBatchSize < 0 {
+		return fmt.Errorf("ae_sync_batch_size cannot be %d. Must be greater than or equal to zero", rt.AESyncBatchSize)
+	}
+	if rt.AESyncRateLimit < 0 {
+		return fmt.Errorf("ae_sync_rate_limit cannot be %v. Must be greater than or equal to zero", rt.AESyncRateLimit)
+	}
 	if rt.AutopilotMaxTrailingLogs < 0 {
 		return fmt.Errorf("autopilot.max_trailing_logs cannot be %d. Must be greater than or equal to zero", rt.AutopilotMaxTrailingLogs)
 	}
+	if rt.AutopilotMinServerUptime < 0 {
+		return fmt.Errorf("autopilot.min_server_uptime cannot be %s. Must be greater than or equal to zero", rt.AutopilotMinServerUptime)
+	}
+	switch rt.AutopilotPromotionStrategy {
+	case "", "stable":
+	case "zones":
+		if rt.AutopilotZoneMetaKey == "" {
+			return fmt.Errorf("autopilot.zone_meta_key is required when autopilot.promotion_strategy is \"zones\"")
+		}
+		if rt.AutopilotVotersPerZone < 1 {
+			return fmt.Errorf("autopilot.voters_per_zone cannot be %d. Must be greater than zero when autopilot.promotion_strategy is \"zones\"", rt.AutopilotVotersPerZone)
+		}
+	default:
+		return fmt.Errorf("autopilot.promotion_strategy must be one of \"stable\" or \"zones\", got %q", rt.AutopilotPromotionStrategy)
+	}
 	if err := validateBasicName("primary_datacenter", rt.PrimaryDatacenter, true); err != nil {
 		return err
 	}
@@ -1355,6 +1561,11 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			return fmt.Errorf("'retry_join_wan' is incompatible with 'connect.enable_mesh_gateway_wan_federation = true'")
 		}
 	}
+	for provider, filters := range rt.RetryJoinFilters {
+		if _, ok := filters["provider"]; ok {
+			return fmt.Errorf("retry_join_filters: the filters of provider %q cannot set the provider", provider)
+		}
+	}
 	if len(rt.PrimaryGateways) > 0 {
 		if !rt.ServerMode {
 			return fmt.Errorf("'primary_gateways' requires 'server = true'")
@@ -1364,6 +1575,17 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		}
 	}
 
+	if rt.ACLClientCertAuthMethod != "" {
+		// The servers login on behalf of the clients with their management
+		// token, which is only valid in their own datacenter.
+		if !rt.ServerMode {
+			return fmt.Errorf("'acl.client_cert_auth_method' requires 'server = true'")
+		}
+		if rt.PrimaryDatacenter != rt.Datacenter {
+			b.warn("acl.client_cert_auth_method is only supported in the primary datacenter, the logins with client certificates will fail")
+		}
+	}
+
 	// Check the data dir for signs of an un-migrated Consul 0.5.x or older
 	// server. Consul refuses to start if this is present to protect a server
 	// with existing data from starting on a fresh data set.
@@ -1393,6 +1615,23 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		if rt.RaftLogStoreConfig.WAL.SegmentSize > 1024*1024*1024 {
 			return fmt.Errorf("raft_logstore.wal.segment_size_mb cannot be greater than 1024 (1GiB)")
 		}
+		if rt.RaftLogStoreConfig.WAL.Canary.Enabled {
+			if rt.RaftLogStoreConfig.Backend != consul.LogStoreBackendWAL {
+				return fmt.Errorf("raft_logstore.wal.canary requires raft_logstore.backend to be '%s'",
+					consul.LogStoreBackendWAL)
+			}
+			if rt.RaftLogStoreConfig.WAL.Canary.VerifyInterval <= 0 {
+				return fmt.Errorf("raft_logstore.wal.canary.verify_interval must be greater than 0")
+			}
+		}
+
+		// Raft compression validation
+		if rt.RaftCompression.LogThreshold < 0 {
+			return fmt.Errorf("raft_compression.log_threshold cannot be negative")
+		}
+		if _, err := rt.RaftCompression.EncoderLevel(); err != nil {
+			return err
+		}
 	}
 
 	inuse := map[string]string{}
@@ -1401,6 +1640,12 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		// we leave this for consistency
 		return err
 	}
+	if err := addrsUnique(inuse, "DNS-over-TLS", rt.DNSTLSAddrs); err != nil {
+		return err
+	}
+	if err := addrsUnique(inuse, "DNS-over-HTTPS", rt.DNSHTTPSAddrs); err != nil {
+		return err
+	}
 	if err := addrsUnique(inuse, "HTTP", rt.HTTPAddrs); err != nil {
 		return err
 	}
@@ -1513,6 +1758,77 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		return fmt.Errorf("both auto_encrypt.tls and auto_config.enabled cannot be set to true.")
 	}
 
+	if rt.AutoEncryptVault.Address != "" {
+		if !rt.AutoEncryptTLS {
+			return fmt.Errorf("auto_encrypt.vault requires auto_encrypt.tls to be enabled")
+		}
+		if rt.AutoEncryptVault.Role == "" {
+			return fmt.Errorf("auto_encrypt.vault.role is required")
+		}
+		if rt.AutoEncryptVault.Token != "" && rt.AutoEncryptVault.TokenFile != "" {
+			return fmt.Errorf("auto_encrypt.vault.token and auto_encrypt.vault.token_file cannot both be set")
+		}
+	}
+
+	if rt.ServicesAutoReloadDir != "" && rt.ServicesAutoReloadDebounce <= 0 {
+		return fmt.Errorf("services_auto_reload.debounce must be greater than 0")
+	}
+
+	if rt.LeaveServiceDrainTime < 0 {
+		return fmt.Errorf("leave_service_drain_time cannot be negative")
+	}
+
+	if err := validateOTLPTelemetry(rt.Telemetry); err != nil {
+		return err
+	}
+
+	if rt.Telemetry.RPCLabelsMaxValues < 1 {
+		return fmt.Errorf("telemetry.rpc_labels_max_values must be greater than 0")
+	}
+
+	if rt.EncryptRotation.Enabled {
+		if rt.EncryptRotation.GracePeriod <= 0 {
+			return fmt.Errorf("encrypt_rotation.grace_period must be greater than 0")
+		}
+		// A rotation promotes the new key and retires the previous one after
+		// a grace period each, so it can't be started more often.
+		if rt.EncryptRotation.Interval <= 2*rt.EncryptRotation.GracePeriod {
+			return fmt.Errorf("encrypt_rotation.interval must be greater than twice encrypt_rotation.grace_period")
+		}
+		if !rt.ServerMode {
+			b.warn("encrypt_rotation is only used by servers and will have no effect")
+		}
+	}
+
+	if rt.ExternalHealthChecks.Enabled {
+		if rt.ExternalHealthChecks.NodeProbeInterval < checks.MinInterval {
+			return fmt.Errorf("external_health_checks.node_probe_interval must be at least %s", checks.MinInterval)
+		}
+		if !rt.ServerMode {
+			b.warn("external_health_checks is only used by servers and will have no effect")
+		}
+	}
+
+	if err := rt.Profiling.Validate(); err != nil {
+		return err
+	}
+	if rt.Profiling.Enabled && rt.Profiling.Dir == "" && rt.DataDir == "" {
+		return fmt.Errorf("profiling.dir must be set when data_dir is not")
+	}
+
+	if err := rt.Audit.Validate(); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+
+	for _, sink := range rt.ChangeSinks {
+		if err := sink.Validate(); err != nil {
+			return fmt.Errorf("change_sink[%s]: %w", sink.Name, err)
+		}
+	}
+	if len(rt.ChangeSinks) > 0 && !rt.ServerMode {
+		b.warn("change_sink is only used by servers and will have no effect")
+	}
+
 	if err := b.validateAutoConfig(rt); err != nil {
 		return err
 	}
@@ -1583,11 +1899,17 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 
 	id := types.CheckID(stringVal(v.ID))
 
+	var compositeChecks []types.CheckID
+	for _, checkID := range v.CompositeChecks {
+		compositeChecks = append(compositeChecks, types.CheckID(checkID))
+	}
+
 	return &structs.CheckDefinition{
 		ID:                             id,
 		Name:                           stringVal(v.Name),
 		Notes:                          stringVal(v.Notes),
 		ServiceID:                      stringVal(v.ServiceID),
+		PortName:                       stringVal(v.PortName),
 		Token:                          stringVal(v.Token),
 		Status:                         stringVal(v.Status),
 		ScriptArgs:                     v.ScriptArgs,
@@ -1603,10 +1925,15 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		Shell:                          stringVal(v.Shell),
 		GRPC:                           stringVal(v.GRPC),
 		GRPCUseTLS:                     boolVal(v.GRPCUseTLS),
+		GRPCService:                    stringVal(v.GRPCService),
+		GRPCAuthority:                  stringVal(v.GRPCAuthority),
 		TLSServerName:                  stringVal(v.TLSServerName),
 		TLSSkipVerify:                  boolVal(v.TLSSkipVerify),
 		AliasNode:                      stringVal(v.AliasNode),
 		AliasService:                   stringVal(v.AliasService),
+		CompositeMode:                  stringVal(v.CompositeMode),
+		CompositeChecks:                compositeChecks,
+		CompositeMinPassing:            intVal(v.CompositeMinPassing),
 		Timeout:                        b.durationVal(fmt.Sprintf("check[%s].timeout", id), v.Timeout),
 		TTL:                            b.durationVal(fmt.Sprintf("check[%s].ttl", id), v.TTL),
 		SuccessBeforePassing:           intVal(v.SuccessBeforePassing),
@@ -1615,6 +1942,9 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		H2PING:                         stringVal(v.H2PING),
 		H2PingUseTLS:                   H2PingUseTLSVal,
 		OSService:                      stringVal(v.OSService),
+		ProcessPID:                     intVal(v.ProcessPID),
+		ProcessPIDFile:                 stringVal(v.ProcessPIDFile),
+		ProcessCgroup:                  stringVal(v.ProcessCgroup),
 		DeregisterCriticalServiceAfter: b.durationVal(fmt.Sprintf("check[%s].deregister_critical_service_after", id), v.DeregisterCriticalServiceAfter),
 		OutputMaxSize:                  intValWithDefault(v.OutputMaxSize, checks.DefaultBufSize),
 		EnterpriseMeta:                 v.EnterpriseMeta.ToStructs(),
@@ -1641,6 +1971,21 @@ func (b *builder) svcTaggedAddresses(v map[string]ServiceAddress) map[string]str
 	return svcAddrs
 }
 
+func (b *builder) svcPorts(v []ServicePort) structs.ServicePorts {
+	if len(v) == 0 {
+		return nil
+	}
+
+	ports := make(structs.ServicePorts, 0, len(v))
+	for _, port := range v {
+		ports = append(ports, structs.ServicePort{
+			Name: stringVal(port.Name),
+			Port: intVal(port.Port),
+		})
+	}
+	return ports
+}
+
 func (b *builder) serviceVal(v *ServiceDefinition) *structs.ServiceDefinition {
 	if v == nil {
 		return nil
@@ -1691,6 +2036,7 @@ func (b *builder) serviceVal(v *ServiceDefinition) *structs.ServiceDefinition {
 		TaggedAddresses:   b.svcTaggedAddresses(v.TaggedAddresses),
 		Meta:              meta,
 		Port:              intVal(v.Port),
+		Ports:             b.svcPorts(v.Ports),
 		SocketPath:        stringVal(v.SocketPath),
 		Token:             stringVal(v.Token),
 		EnableTagOverride: boolVal(v.EnableTagOverride),
@@ -1732,6 +2078,7 @@ func (b *builder) serviceProxyVal(v *ServiceProxy) *structs.ConnectProxyConfig {
 		DestinationServiceID:   stringVal(v.DestinationServiceID),
 		LocalServiceAddress:    stringVal(v.LocalServiceAddress),
 		LocalServicePort:       intVal(v.LocalServicePort),
+		LocalServicePorts:      b.svcPorts(v.LocalServicePorts),
 		LocalServiceSocketPath: stringVal(&v.LocalServiceSocketPath),
 		Config:                 v.Config,
 		Upstreams:              b.upstreamsVal(v.Upstreams),
@@ -1751,6 +2098,7 @@ func (b *builder) upstreamsVal(v []Upstream) structs.Upstreams {
 			DestinationPartition: stringVal(u.DestinationPartition),
 			DestinationPeer:      stringVal(u.DestinationPeer),
 			DestinationName:      stringVal(u.DestinationName),
+			DestinationPort:      stringVal(u.DestinationPort),
 			Datacenter:           stringVal(u.Datacenter),
 			LocalBindAddress:     stringVal(u.LocalBindAddress),
 			LocalBindPort:        intVal(u.LocalBindPort),
@@ -1797,6 +2145,17 @@ func (b *builder) dnsRecursorStrategyVal(v string) dns.RecursorStrategy {
 	return out
 }
 
+func (b *builder) dnsVirtualAnswerVal(name, v string) dns.VirtualAnswer {
+	switch dns.VirtualAnswer(v) {
+	case dns.VirtualAnswerVirtualIP, dns.VirtualAnswerInstances:
+		return dns.VirtualAnswer(v)
+	default:
+		b.err = multierror.Append(b.err, fmt.Errorf("%s: invalid answer: %q, must be %q or %q",
+			name, v, dns.VirtualAnswerVirtualIP, dns.VirtualAnswerInstances))
+		return ""
+	}
+}
+
 func (b *builder) requestsLimitsModeVal(v string) consulrate.Mode {
 	var out consulrate.Mode
 
@@ -1810,6 +2169,50 @@ func (b *builder) requestsLimitsModeVal(v string) consulrate.Mode {
 	return out
 }
 
+// requestLimitsIPOverridesVal returns the per-IP limits overrides. The rates
+// which are not set in an override are the per-IP ones.
+func (b *builder) requestLimitsIPOverridesVal(v RequestLimitsPerIP) []consul.RequestLimitsIPOverride {
+	var out []consul.RequestLimitsIPOverride
+	for i, o := range v.Overrides {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(stringVal(o.CIDR)))
+		if err != nil {
+			b.err = multierror.Append(b.err, fmt.Errorf("limits.request_limits.per_ip.override[%d].cidr: invalid cidr: %q", i, stringVal(o.CIDR)))
+			continue
+		}
+		out = append(out, consul.RequestLimitsIPOverride{
+			CIDR:      cidr,
+			ReadRate:  limitVal(float64PtrWithDefault(o.ReadRate, v.ReadRate)),
+			WriteRate: limitVal(float64PtrWithDefault(o.WriteRate, v.WriteRate)),
+		})
+	}
+	return out
+}
+
+// requestLimitsTokenOverridesVal returns the per-token limits overrides. The
+// rates which are not set in an override are the per-token ones.
+func (b *builder) requestLimitsTokenOverridesVal(v RequestLimitsPerToken) []consul.RequestLimitsTokenOverride {
+	var out []consul.RequestLimitsTokenOverride
+	seen := make(map[string]struct{})
+	for i, o := range v.Overrides {
+		accessorID := stringVal(o.AccessorID)
+		if accessorID == "" {
+			b.err = multierror.Append(b.err, fmt.Errorf("limits.request_limits.per_token.override[%d].accessor_id: cannot be empty", i))
+			continue
+		}
+		if _, ok := seen[accessorID]; ok {
+			b.err = multierror.Append(b.err, fmt.Errorf("limits.request_limits.per_token.override[%d].accessor_id: duplicate override for %q", i, accessorID))
+			continue
+		}
+		seen[accessorID] = struct{}{}
+		out = append(out, consul.RequestLimitsTokenOverride{
+			AccessorID: accessorID,
+			ReadRate:   limitVal(float64PtrWithDefault(o.ReadRate, v.ReadRate)),
+			WriteRate:  limitVal(float64PtrWithDefault(o.WriteRate, v.WriteRate)),
+		})
+	}
+	return out
+}
+
 func (b *builder) exposeConfVal(v *ExposeConfig) structs.ExposeConfig {
 	var out structs.ExposeConfig
 	if v == nil {
@@ -2021,6 +2424,13 @@ func float64ValWithDefault(v *float64, defaultVal float64) float64 {
 	return *v
 }
 
+func float64PtrWithDefault(v, defaultVal *float64) *float64 {
+	if v == nil {
+		return defaultVal
+	}
+	return v
+}
+
 func float64Val(v *float64) float64 {
 	return float64ValWithDefault(v, 0)
 }
@@ -2039,6 +2449,15 @@ func limitValWithDefault(v *float64, defaultVal float64) rate.Limit {
 	return limitVal(&f)
 }
 
+// cachePersistPath returns the file the agent cache entries are persisted to,
+// or an empty path if they are not persisted.
+func cachePersistPath(persist *bool, dataDir string) string {
+	if !boolVal(persist) || dataDir == "" {
+		return ""
+	}
+	return filepath.Join(dataDir, "cache", "entries.json")
+}
+
 func (b *builder) cidrsVal(name string, v []string) (nets []*net.IPNet) {
 	if v == nil {
 		return
@@ -2134,7 +2553,7 @@ func (b *builder) nodeName(v *string) string {
 }
 
 // expandAddrs expands the go-sockaddr template in s and returns the
-// result as a list of *net.IPAddr and *net.UnixAddr.
+// result as a list of *net.IPAddr, *net.UnixAddr and *NamedPipeAddr.
 func (b *builder) expandAddrs(name string, s *string) []net.Addr {
 	if s == nil || *s == "" {
 		return nil
@@ -2151,6 +2570,8 @@ func (b *builder) expandAddrs(name string, s *string) []net.Addr {
 		switch {
 		case strings.HasPrefix(a, "unix://"):
 			addrs = append(addrs, &net.UnixAddr{Name: a[len("unix://"):], Net: "unix"})
+		case strings.HasPrefix(a, "npipe://"):
+			addrs = append(addrs, &NamedPipeAddr{Path: a[len("npipe://"):]})
 		default:
 			// net.ParseIP does not like '[::]'
 			ip := net.ParseIP(a)
@@ -2222,6 +2643,9 @@ func (b *builder) expandIPs(name string, s *string) []*net.IPAddr {
 		case *net.UnixAddr:
 			b.err = multierror.Append(b.err, fmt.Errorf("%s cannot be a unix socket", name))
 			return nil
+		case *NamedPipeAddr:
+			b.err = multierror.Append(b.err, fmt.Errorf("%s cannot be a named pipe", name))
+			return nil
 		default:
 			b.err = multierror.Append(b.err, fmt.Errorf("%s has invalid address type %T", name, a))
 			return nil
@@ -2272,6 +2696,9 @@ func (b *builder) expandFirstIP(name string, s *string) *net.IPAddr {
 	case *net.UnixAddr:
 		b.err = multierror.Append(b.err, fmt.Errorf("%s cannot be a unix socket", name))
 		return nil
+	case *NamedPipeAddr:
+		b.err = multierror.Append(b.err, fmt.Errorf("%s cannot be a named pipe", name))
+		return nil
 	default:
 		b.err = multierror.Append(b.err, fmt.Errorf("%s has invalid address type %T", name, a))
 		return nil
@@ -2303,9 +2730,9 @@ func (b *builder) makeTCPAddr(pri *net.IPAddr, sec net.Addr, port int) *net.TCPA
 	return &net.TCPAddr{IP: addr.IP, Port: port}
 }
 
-// makeAddr creates an *net.TCPAddr or a *net.UnixAddr from either the
-// primary or secondary address and the given port. If the port is <= 0
-// then the address is considered to be disabled and nil is returned.
+// makeAddr creates an *net.TCPAddr, a *net.UnixAddr or a *NamedPipeAddr from
+// either the primary or secondary address and the given port. If the port is
+// <= 0 then the address is considered to be disabled and nil is returned.
 func (b *builder) makeAddr(pri, sec net.Addr, port int) net.Addr {
 	if reflect.ValueOf(pri).IsNil() && reflect.ValueOf(sec).IsNil() || port <= 0 {
 		return nil
@@ -2317,7 +2744,7 @@ func (b *builder) makeAddr(pri, sec net.Addr, port int) net.Addr {
 	switch a := addr.(type) {
 	case *net.IPAddr:
 		return &net.TCPAddr{IP: a.IP, Port: port}
-	case *net.UnixAddr:
+	case *net.UnixAddr, *NamedPipeAddr:
 		return a
 	default:
 		panic(fmt.Sprintf("invalid address type %T", a))
@@ -2724,6 +3151,128 @@ func (b *builder) parsePrefixFilter(telemetry *Telemetry) ([]string, []string) {
 	return telemetryAllowedPrefixes, telemetryBlockedPrefixes
 }
 
+func (b *builder) auditVal(raw Audit) audit.Config {
+	cfg := audit.Config{
+		Enabled: boolVal(raw.Enabled),
+	}
+
+	names := make([]string, 0, len(raw.Sinks))
+	for name := range raw.Sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := raw.Sinks[name]
+		sink := audit.SinkConfig{
+			Name:              name,
+			Type:              stringVal(s.Type),
+			Format:            stringValWithDefault(s.Format, audit.FormatJSON),
+			DeliveryGuarantee: stringValWithDefault(s.DeliveryGuarantee, audit.DeliveryGuaranteeBestEffort),
+			Path:              stringVal(s.Path),
+			RotateBytes:       intVal(s.RotateBytes),
+			RotateDuration:    b.durationVal(fmt.Sprintf("audit.sink[%s].rotate_duration", name), s.RotateDuration),
+			RotateMaxFiles:    intVal(s.RotateMaxFiles),
+			SyslogFacility:    stringVal(s.SyslogFacility),
+			SyslogTag:         stringVal(s.SyslogTag),
+		}
+		if mode := b.unixPermissionsVal(fmt.Sprintf("audit.sink[%s].mode", name), s.Mode); mode != "" {
+			m, _ := strconv.ParseUint(mode, 8, 32)
+			sink.Mode = os.FileMode(m)
+		}
+		cfg.Sinks = append(cfg.Sinks, sink)
+	}
+
+	for _, f := range raw.Filters {
+		cfg.Filters = append(cfg.Filters, audit.FilterRule{
+			Action:     stringVal(f.Action),
+			Operations: f.Operations,
+			Resources:  f.Resources,
+			Results:    f.Results,
+		})
+	}
+	return cfg
+}
+
+func (b *builder) encryptRotationVal(raw EncryptRotationRaw) consul.KeyringRotationConfig {
+	return consul.KeyringRotationConfig{
+		Enabled:     boolVal(raw.Enabled),
+		Interval:    b.durationVal("encrypt_rotation.interval", raw.Interval),
+		GracePeriod: b.durationVal("encrypt_rotation.grace_period", raw.GracePeriod),
+	}
+}
+
+func (b *builder) autoEncryptVaultVal(raw AutoEncryptVault) AutoEncryptVaultConfig {
+	return AutoEncryptVaultConfig{
+		Address:       stringVal(raw.Address),
+		Token:         stringVal(raw.Token),
+		TokenFile:     stringVal(raw.TokenFile),
+		Namespace:     stringVal(raw.Namespace),
+		PKIPath:       stringVal(raw.PKIPath),
+		Role:          stringVal(raw.Role),
+		TTL:           b.durationVal("auto_encrypt.vault.ttl", raw.TTL),
+		CAFile:        stringVal(raw.CAFile),
+		CAPath:        stringVal(raw.CAPath),
+		TLSServerName: stringVal(raw.TLSServerName),
+		TLSSkipVerify: boolVal(raw.TLSSkipVerify),
+	}
+}
+
+func (b *builder) changeSinksVal(raw ChangeSinks) []changesink.Config {
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sinks []changesink.Config
+	for _, name := range names {
+		s := raw[name]
+		sinks = append(sinks, changesink.Config{
+			Name:       name,
+			Type:       stringVal(s.Type),
+			URL:        stringVal(s.URL),
+			Headers:    s.Headers,
+			KafkaTopic: stringVal(s.KafkaTopic),
+			Topics:     s.Topics,
+			Timeout:    b.durationVal(fmt.Sprintf("change_sink[%s].timeout", name), s.Timeout),
+		})
+	}
+	return sinks
+}
+
+func (b *builder) externalHealthChecksVal(raw ExternalHealthRaw) consul.ExternalHealthChecksConfig {
+	return consul.ExternalHealthChecksConfig{
+		Enabled:           boolVal(raw.Enabled),
+		NodeProbeInterval: b.durationVal("external_health_checks.node_probe_interval", raw.NodeProbeInterval),
+	}
+}
+
+func (b *builder) profilingVal(raw ProfilingRaw) debug.ProfilerConfig {
+	// The default profiles are applied here rather than in the default config,
+	// since lists from the config files are appended to the defaults.
+	profiles := raw.Profiles
+	if len(profiles) == 0 {
+		profiles = debug.DefaultProfileTypes
+	}
+	return debug.ProfilerConfig{
+		Enabled:     boolVal(raw.Enabled),
+		Dir:         stringVal(raw.Dir),
+		Interval:    b.durationVal("profiling.interval", raw.Interval),
+		CPUDuration: b.durationVal("profiling.cpu_duration", raw.CPUDuration),
+		Profiles:    profiles,
+		Retain:      intVal(raw.Retain),
+		PushURL:     stringVal(raw.PushURL),
+	}
+}
+
+func (b *builder) raftCompressionVal(raw RaftCompressionRaw) consul.RaftCompressionConfig {
+	return consul.RaftCompressionConfig{
+		LogThreshold: intVal(raw.LogThreshold),
+		Snapshots:    boolVal(raw.Snapshots),
+		Level:        stringVal(raw.Level),
+	}
+}
+
 func (b *builder) raftLogStoreConfigVal(raw *RaftLogStoreRaw) consul.RaftLogStoreConfig {
 	var cfg consul.RaftLogStoreConfig
 	if raw != nil {
@@ -2736,6 +3285,9 @@ func (b *builder) raftLogStoreConfigVal(raw *RaftLogStoreRaw) consul.RaftLogStor
 		cfg.BoltDB.NoFreelistSync = boolVal(raw.BoltDBConfig.NoFreelistSync)
 
 		cfg.WAL.SegmentSize = intVal(raw.WALConfig.SegmentSizeMB) * 1024 * 1024
+		cfg.WAL.Canary.Enabled = boolVal(raw.WALConfig.Canary.Enabled)
+		cfg.WAL.Canary.VerifyInterval = b.durationVal("raft_logstore.wal.canary.verify_interval", raw.WALConfig.Canary.VerifyInterval)
+		cfg.WAL.Canary.Failback = boolVal(raw.WALConfig.Canary.Failback)
 	}
 	return cfg
 }
@@ -17,9 +17,6 @@ func validateEnterpriseConfigKeys(config *Config) []error {
 		result = append(result, enterpriseConfigKeyError{key: k})
 	}
 
-	if config.ReadReplica != nil {
-		add(`read_replica (or the deprecated non_voting_server)`)
-	}
 	if stringVal(config.SegmentName) != "" {
 		add("segment")
 	}
@@ -50,9 +47,6 @@ func validateEnterpriseConfigKeys(config *Config) []error {
 		add("acl.tokens.managed_service_provider")
 		config.ACL.Tokens.ManagedServiceProvider = nil
 	}
-	if boolVal(config.Audit.Enabled) || len(config.Audit.Sinks) > 0 {
-		add("audit")
-	}
 	if config.LicensePath != nil {
 		add("license_path")
 		config.LicensePath = nil
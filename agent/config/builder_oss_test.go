@@ -21,12 +21,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 	stringVal := "string"
 
 	cases := map[string]testCase{
-		"read_replica": {
-			config: Config{
-				ReadReplica: &boolVal,
-			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)"},
-		},
 		"segment": {
 			config: Config{
 				SegmentName: &stringVal,
@@ -109,7 +103,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 		},
 		"multi": {
 			config: Config{
-				ReadReplica: &boolVal,
 				SegmentName: &stringVal,
 				ACL: ACL{
 					Tokens: Tokens{
@@ -117,7 +110,7 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 					},
 				},
 			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)", "segment"},
+			badKeys: []string{"segment"},
 		},
 	}
 
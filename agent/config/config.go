@@ -28,6 +28,10 @@ type FileSource struct {
 	Name   string
 	Format string
 	Data   string
+
+	// Interpolation lists the references that are replaced in the string
+	// values of the config.
+	Interpolation Interpolation
 }
 
 func (f FileSource) Source() string {
@@ -56,6 +60,12 @@ func (f FileSource) Parse() (Config, Metadata, error) {
 		return Config{}, m, err
 	}
 
+	if f.Interpolation.Enabled() {
+		if _, err := f.Interpolation.interpolate(raw, ""); err != nil {
+			return Config{}, m, err
+		}
+	}
+
 	var target decodeTarget
 	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
@@ -119,6 +129,9 @@ type Cache struct {
 	EntryFetchMaxBurst *int `mapstructure:"entry_fetch_max_burst"`
 	// EntryFetchRate represents the max calls/sec for a single cache entry
 	EntryFetchRate *float64 `mapstructure:"entry_fetch_rate"`
+	// Persist enables persisting the cache entries to the data directory
+	// across agent restarts.
+	Persist *bool `mapstructure:"persist"`
 }
 
 // Config defines the format of a configuration file in either JSON or
@@ -140,12 +153,15 @@ type Config struct {
 	AdvertiseAddrWANIPv4             *string             `mapstructure:"advertise_addr_wan_ipv4" json:"advertise_addr_wan_ipv4,omitempty"`
 	AdvertiseAddrWANIPv6             *string             `mapstructure:"advertise_addr_wan_ipv6" json:"advertise_addr_wan_ipv6,omitempty"`
 	AdvertiseReconnectTimeout        *string             `mapstructure:"advertise_reconnect_timeout" json:"-"`
+	AESyncBatchSize                  *int                `mapstructure:"ae_sync_batch_size" json:"ae_sync_batch_size,omitempty"`
+	AESyncRateLimit                  *float64            `mapstructure:"ae_sync_rate_limit" json:"ae_sync_rate_limit,omitempty"`
 	AutoConfig                       AutoConfigRaw       `mapstructure:"auto_config" json:"-"`
 	Autopilot                        Autopilot           `mapstructure:"autopilot" json:"-"`
 	BindAddr                         *string             `mapstructure:"bind_addr" json:"bind_addr,omitempty"`
 	Bootstrap                        *bool               `mapstructure:"bootstrap" json:"bootstrap,omitempty"`
 	BootstrapExpect                  *int                `mapstructure:"bootstrap_expect" json:"bootstrap_expect,omitempty"`
 	Cache                            Cache               `mapstructure:"cache" json:"-"`
+	ChangeSinks                      ChangeSinks         `mapstructure:"change_sink" json:"-"`
 	Check                            *CheckDefinition    `mapstructure:"check" json:"-"` // needs to be a pointer to avoid partial merges
 	CheckOutputMaxSize               *int                `mapstructure:"check_output_max_size" json:"check_output_max_size,omitempty"`
 	CheckUpdateInterval              *string             `mapstructure:"check_update_interval" json:"check_update_interval,omitempty"`
@@ -171,6 +187,7 @@ type Config struct {
 	DisableUpdateCheck               *bool               `mapstructure:"disable_update_check" json:"disable_update_check,omitempty"`
 	DiscardCheckOutput               *bool               `mapstructure:"discard_check_output" json:"discard_check_output,omitempty"`
 	DiscoveryMaxStale                *string             `mapstructure:"discovery_max_stale" json:"discovery_max_stale,omitempty"`
+	DockerHost                       *string             `mapstructure:"docker_host" json:"docker_host,omitempty"`
 	EnableAgentTLSForChecks          *bool               `mapstructure:"enable_agent_tls_for_checks" json:"enable_agent_tls_for_checks,omitempty"`
 	EnableCentralServiceConfig       *bool               `mapstructure:"enable_central_service_config" json:"enable_central_service_config,omitempty"`
 	EnableDebug                      *bool               `mapstructure:"enable_debug" json:"enable_debug,omitempty"`
@@ -178,12 +195,16 @@ type Config struct {
 	EnableLocalScriptChecks          *bool               `mapstructure:"enable_local_script_checks" json:"enable_local_script_checks,omitempty"`
 	EnableSyslog                     *bool               `mapstructure:"enable_syslog" json:"enable_syslog,omitempty"`
 	EncryptKey                       *string             `mapstructure:"encrypt" json:"encrypt,omitempty"`
+	EncryptRotation                  EncryptRotationRaw  `mapstructure:"encrypt_rotation" json:"-"`
 	EncryptVerifyIncoming            *bool               `mapstructure:"encrypt_verify_incoming" json:"encrypt_verify_incoming,omitempty"`
 	EncryptVerifyOutgoing            *bool               `mapstructure:"encrypt_verify_outgoing" json:"encrypt_verify_outgoing,omitempty"`
+	ExternalHealthChecks             ExternalHealthRaw   `mapstructure:"external_health_checks" json:"-"`
 	GossipLAN                        GossipLANConfig     `mapstructure:"gossip_lan" json:"-"`
 	GossipWAN                        GossipWANConfig     `mapstructure:"gossip_wan" json:"-"`
 	HTTPConfig                       HTTPConfig          `mapstructure:"http_config" json:"-"`
+	KVCompressionThreshold           *int                `mapstructure:"kv_compression_threshold" json:"kv_compression_threshold,omitempty"`
 	LeaveOnTerm                      *bool               `mapstructure:"leave_on_terminate" json:"leave_on_terminate,omitempty"`
+	LeaveServiceDrainTime            *string             `mapstructure:"leave_service_drain_time" json:"leave_service_drain_time,omitempty"`
 	LicensePath                      *string             `mapstructure:"license_path" json:"license_path,omitempty"`
 	Limits                           Limits              `mapstructure:"limits" json:"-"`
 	LogLevel                         *string             `mapstructure:"log_level" json:"log_level,omitempty"`
@@ -203,6 +224,7 @@ type Config struct {
 	PrimaryDatacenter                *string             `mapstructure:"primary_datacenter" json:"primary_datacenter,omitempty"`
 	PrimaryGateways                  []string            `mapstructure:"primary_gateways" json:"primary_gateways,omitempty"`
 	PrimaryGatewaysInterval          *string             `mapstructure:"primary_gateways_interval" json:"primary_gateways_interval,omitempty"`
+	Profiling                        ProfilingRaw        `mapstructure:"profiling" json:"-"`
 	RPCProtocol                      *int                `mapstructure:"protocol" json:"protocol,omitempty"`
 	RaftProtocol                     *int                `mapstructure:"raft_protocol" json:"raft_protocol,omitempty"`
 	RaftSnapshotThreshold            *int                `mapstructure:"raft_snapshot_threshold" json:"raft_snapshot_threshold,omitempty"`
@@ -212,6 +234,7 @@ type Config struct {
 	ReconnectTimeoutWAN              *string             `mapstructure:"reconnect_timeout_wan" json:"reconnect_timeout_wan,omitempty"`
 	RejoinAfterLeave                 *bool               `mapstructure:"rejoin_after_leave" json:"rejoin_after_leave,omitempty"`
 	AutoReloadConfig                 *bool               `mapstructure:"auto_reload_config" json:"auto_reload_config,omitempty"`
+	RetryJoinFilters                 RetryJoinFilters    `mapstructure:"retry_join_filters" json:"retry_join_filters,omitempty"`
 	RetryJoinIntervalLAN             *string             `mapstructure:"retry_interval" json:"retry_interval,omitempty"`
 	RetryJoinIntervalWAN             *string             `mapstructure:"retry_interval_wan" json:"retry_interval_wan,omitempty"`
 	RetryJoinLAN                     []string            `mapstructure:"retry_join" json:"retry_join,omitempty"`
@@ -225,7 +248,9 @@ type Config struct {
 	ServerMode                       *bool               `mapstructure:"server" json:"server,omitempty"`
 	ServerName                       *string             `mapstructure:"server_name" json:"server_name,omitempty"`
 	Service                          *ServiceDefinition  `mapstructure:"service" json:"-"`
+	ServiceTombstoneTTL              *string             `mapstructure:"service_tombstone_ttl" json:"service_tombstone_ttl,omitempty"`
 	Services                         []ServiceDefinition `mapstructure:"services" json:"-"`
+	ServicesAutoReload               ServicesAutoReload  `mapstructure:"services_auto_reload" json:"-"`
 	SessionTTLMin                    *string             `mapstructure:"session_ttl_min" json:"session_ttl_min,omitempty"`
 	SkipLeaveOnInt                   *bool               `mapstructure:"skip_leave_on_interrupt" json:"skip_leave_on_interrupt,omitempty"`
 	SyslogFacility                   *string             `mapstructure:"syslog_facility" json:"syslog_facility,omitempty"`
@@ -243,6 +268,7 @@ type Config struct {
 	UIDir    *string     `mapstructure:"ui_dir" json:"-"`
 	UIConfig RawUIConfig `mapstructure:"ui_config" json:"-"`
 
+	NamedPipe  NamedPipe                `mapstructure:"named_pipes" json:"-"`
 	UnixSocket UnixSocket               `mapstructure:"unix_sockets" json:"-"`
 	Watches    []map[string]interface{} `mapstructure:"watches" json:"-"`
 
@@ -250,10 +276,16 @@ type Config struct {
 
 	RaftLogStore RaftLogStoreRaw `mapstructure:"raft_logstore" json:"raft_logstore,omitempty"`
 
+	RaftCompression RaftCompressionRaw `mapstructure:"raft_compression" json:"raft_compression,omitempty"`
+
 	// UseStreamingBackend instead of blocking queries for service health and
 	// any other endpoints which support streaming.
 	UseStreamingBackend *bool `mapstructure:"use_streaming_backend" json:"-"`
 
+	// UseStreamingBackendCatalogServices serves the catalog list of services
+	// from the streaming backend as well.
+	UseStreamingBackendCatalogServices *bool `mapstructure:"use_streaming_backend_catalog_services" json:"-"`
+
 	// This isn't used by Consul but we've documented a feature where users
 	// can deploy their snapshot agent configs alongside their Consul configs
 	// so we have a placeholder here so it can be parsed but this doesn't
@@ -294,12 +326,14 @@ type Config struct {
 }
 
 type GossipLANConfig struct {
-	GossipNodes    *int    `mapstructure:"gossip_nodes"`
-	GossipInterval *string `mapstructure:"gossip_interval"`
-	ProbeInterval  *string `mapstructure:"probe_interval"`
-	ProbeTimeout   *string `mapstructure:"probe_timeout"`
-	SuspicionMult  *int    `mapstructure:"suspicion_mult"`
-	RetransmitMult *int    `mapstructure:"retransmit_mult"`
+	Profile          *string `mapstructure:"profile"`
+	GossipNodes      *int    `mapstructure:"gossip_nodes"`
+	GossipInterval   *string `mapstructure:"gossip_interval"`
+	ProbeInterval    *string `mapstructure:"probe_interval"`
+	ProbeTimeout     *string `mapstructure:"probe_timeout"`
+	SuspicionMult    *int    `mapstructure:"suspicion_mult"`
+	RetransmitMult   *int    `mapstructure:"retransmit_mult"`
+	PushPullInterval *string `mapstructure:"push_pull_interval"`
 }
 
 type GossipWANConfig struct {
@@ -330,11 +364,13 @@ type Consul struct {
 }
 
 type Addresses struct {
-	DNS     *string `mapstructure:"dns"`
-	HTTP    *string `mapstructure:"http"`
-	HTTPS   *string `mapstructure:"https"`
-	GRPC    *string `mapstructure:"grpc"`
-	GRPCTLS *string `mapstructure:"grpc_tls"`
+	DNS      *string `mapstructure:"dns"`
+	DNSTLS   *string `mapstructure:"dns_tls"`
+	DNSHTTPS *string `mapstructure:"dns_https"`
+	HTTP     *string `mapstructure:"http"`
+	HTTPS    *string `mapstructure:"https"`
+	GRPC     *string `mapstructure:"grpc"`
+	GRPCTLS  *string `mapstructure:"grpc_tls"`
 }
 
 type AdvertiseAddrsConfig struct {
@@ -350,6 +386,13 @@ type Autopilot struct {
 	MinQuorum               *uint   `mapstructure:"min_quorum"`
 	ServerStabilizationTime *string `mapstructure:"server_stabilization_time"`
 
+	// PromotionStrategy, MinServerUptime, ZoneMetaKey and VotersPerZone
+	// configure how the leader promotes servers to voters.
+	PromotionStrategy *string `mapstructure:"promotion_strategy"`
+	MinServerUptime   *string `mapstructure:"min_server_uptime"`
+	ZoneMetaKey       *string `mapstructure:"zone_meta_key"`
+	VotersPerZone     *int    `mapstructure:"voters_per_zone"`
+
 	// Enterprise Only
 	DisableUpgradeMigration *bool `mapstructure:"disable_upgrade_migration"`
 	// Enterprise Only
@@ -369,6 +412,12 @@ type ServiceAddress struct {
 	Port    *int    `mapstructure:"port"`
 }
 
+// ServicePort defines a named port of a service
+type ServicePort struct {
+	Name *string `mapstructure:"name"`
+	Port *int    `mapstructure:"port"`
+}
+
 type ServiceDefinition struct {
 	Kind              *string                   `mapstructure:"kind"`
 	ID                *string                   `mapstructure:"id"`
@@ -378,6 +427,7 @@ type ServiceDefinition struct {
 	TaggedAddresses   map[string]ServiceAddress `mapstructure:"tagged_addresses"`
 	Meta              map[string]string         `mapstructure:"meta"`
 	Port              *int                      `mapstructure:"port"`
+	Ports             []ServicePort             `mapstructure:"ports"`
 	SocketPath        *string                   `mapstructure:"socket_path"`
 	Check             *CheckDefinition          `mapstructure:"check"`
 	Checks            []CheckDefinition         `mapstructure:"checks"`
@@ -395,6 +445,7 @@ type CheckDefinition struct {
 	Name                           *string             `mapstructure:"name"`
 	Notes                          *string             `mapstructure:"notes"`
 	ServiceID                      *string             `mapstructure:"service_id" alias:"serviceid"`
+	PortName                       *string             `mapstructure:"port_name"`
 	Token                          *string             `mapstructure:"token"`
 	Status                         *string             `mapstructure:"status"`
 	ScriptArgs                     []string            `mapstructure:"args" alias:"scriptargs"`
@@ -411,15 +462,23 @@ type CheckDefinition struct {
 	Shell                          *string             `mapstructure:"shell"`
 	GRPC                           *string             `mapstructure:"grpc"`
 	GRPCUseTLS                     *bool               `mapstructure:"grpc_use_tls"`
+	GRPCService                    *string             `mapstructure:"grpc_service"`
+	GRPCAuthority                  *string             `mapstructure:"grpc_authority"`
 	TLSServerName                  *string             `mapstructure:"tls_server_name"`
 	TLSSkipVerify                  *bool               `mapstructure:"tls_skip_verify" alias:"tlsskipverify"`
 	AliasNode                      *string             `mapstructure:"alias_node"`
 	AliasService                   *string             `mapstructure:"alias_service"`
+	CompositeMode                  *string             `mapstructure:"composite_mode"`
+	CompositeChecks                []string            `mapstructure:"composite_checks"`
+	CompositeMinPassing            *int                `mapstructure:"composite_min_passing"`
 	Timeout                        *string             `mapstructure:"timeout"`
 	TTL                            *string             `mapstructure:"ttl"`
 	H2PING                         *string             `mapstructure:"h2ping"`
 	H2PingUseTLS                   *bool               `mapstructure:"h2ping_use_tls"`
 	OSService                      *string             `mapstructure:"os_service"`
+	ProcessPID                     *int                `mapstructure:"process_pid"`
+	ProcessPIDFile                 *string             `mapstructure:"process_pidfile"`
+	ProcessCgroup                  *string             `mapstructure:"process_cgroup"`
 	SuccessBeforePassing           *int                `mapstructure:"success_before_passing"`
 	FailuresBeforeWarning          *int                `mapstructure:"failures_before_warning"`
 	FailuresBeforeCritical         *int                `mapstructure:"failures_before_critical"`
@@ -470,6 +529,11 @@ type ServiceProxy struct {
 	// (DestinationServiceID is set) but otherwise will be ignored.
 	LocalServicePort *int `mapstructure:"local_service_port"`
 
+	// LocalServicePorts are the named ports of the local service instance. It
+	// will default to the named ports of the instance if the proxy is a
+	// "side-car".
+	LocalServicePorts []ServicePort `mapstructure:"local_service_ports"`
+
 	// LocalServiceSocketPath is the socket of the local service instance. It is optional
 	// and should only be specified for "side-car" style proxies.
 	LocalServiceSocketPath string `mapstructure:"local_service_socket_path"`
@@ -513,6 +577,10 @@ type Upstream struct {
 	DestinationPeer      *string `mapstructure:"destination_peer"`
 	DestinationName      *string `mapstructure:"destination_name"`
 
+	// DestinationPort is the name of the port of the destination service to
+	// connect to, when it isn't its main port.
+	DestinationPort *string `mapstructure:"destination_port"`
+
 	// Datacenter that the service discovery request should be run against. Note
 	// for prepared queries, the actual results might be from a different
 	// datacenter.
@@ -599,6 +667,25 @@ type AutoEncrypt struct {
 	// AllowTLS enables the RPC endpoint on the server to answer
 	// AutoEncrypt.Sign requests.
 	AllowTLS *bool `mapstructure:"allow_tls" json:"allow_tls,omitempty"`
+
+	// Vault configures the clients to obtain their certificates from the PKI
+	// secrets engine of Vault rather than from the servers.
+	Vault AutoEncryptVault `mapstructure:"vault" json:"-"`
+}
+
+// AutoEncryptVault is the auto_encrypt.vault configuration.
+type AutoEncryptVault struct {
+	Address       *string `mapstructure:"address"`
+	Token         *string `mapstructure:"token"`
+	TokenFile     *string `mapstructure:"token_file"`
+	Namespace     *string `mapstructure:"namespace"`
+	PKIPath       *string `mapstructure:"pki_path"`
+	Role          *string `mapstructure:"role"`
+	TTL           *string `mapstructure:"ttl"`
+	CAFile        *string `mapstructure:"ca_file"`
+	CAPath        *string `mapstructure:"ca_path"`
+	TLSServerName *string `mapstructure:"tls_server_name"`
+	TLSSkipVerify *bool   `mapstructure:"tls_skip_verify"`
 }
 
 // Connect is the agent-global connect configuration.
@@ -624,22 +711,38 @@ type SOA struct {
 	Minttl  *uint32 `mapstructure:"min_ttl"`
 }
 
+// DNSView is the configuration of an additional DNS domain with its own
+// answer policy.
+type DNSView struct {
+	Domain      *string  `mapstructure:"domain"`
+	OnlyPassing *bool    `mapstructure:"only_passing"`
+	Tags        []string `mapstructure:"tags"`
+	ExcludeTags []string `mapstructure:"exclude_tags"`
+}
+
 type DNS struct {
-	AllowStale         *bool             `mapstructure:"allow_stale"`
-	ARecordLimit       *int              `mapstructure:"a_record_limit"`
-	DisableCompression *bool             `mapstructure:"disable_compression"`
-	EnableTruncate     *bool             `mapstructure:"enable_truncate"`
-	MaxStale           *string           `mapstructure:"max_stale"`
-	NodeTTL            *string           `mapstructure:"node_ttl"`
-	OnlyPassing        *bool             `mapstructure:"only_passing"`
-	RecursorStrategy   *string           `mapstructure:"recursor_strategy"`
-	RecursorTimeout    *string           `mapstructure:"recursor_timeout"`
-	ServiceTTL         map[string]string `mapstructure:"service_ttl"`
-	UDPAnswerLimit     *int              `mapstructure:"udp_answer_limit"`
-	NodeMetaTXT        *bool             `mapstructure:"enable_additional_node_meta_txt"`
-	SOA                *SOA              `mapstructure:"soa"`
-	UseCache           *bool             `mapstructure:"use_cache"`
-	CacheMaxAge        *string           `mapstructure:"cache_max_age"`
+	AllowStale          *bool             `mapstructure:"allow_stale"`
+	ARecordLimit        *int              `mapstructure:"a_record_limit"`
+	DisableCompression  *bool             `mapstructure:"disable_compression"`
+	EnableTruncate      *bool             `mapstructure:"enable_truncate"`
+	MaxStale            *string           `mapstructure:"max_stale"`
+	NodeTTL             *string           `mapstructure:"node_ttl"`
+	OnlyPassing         *bool             `mapstructure:"only_passing"`
+	RecursorStrategy    *string           `mapstructure:"recursor_strategy"`
+	RecursorTimeout     *string           `mapstructure:"recursor_timeout"`
+	ServiceTTL          map[string]string `mapstructure:"service_ttl"`
+	UDPAnswerLimit      *int              `mapstructure:"udp_answer_limit"`
+	NodeMetaTXT         *bool             `mapstructure:"enable_additional_node_meta_txt"`
+	SOA                 *SOA              `mapstructure:"soa"`
+	UseCache            *bool             `mapstructure:"use_cache"`
+	CacheMaxAge         *string           `mapstructure:"cache_max_age"`
+	PeerDomain          *string           `mapstructure:"peer_domain"`
+	Views               []DNSView         `mapstructure:"views"`
+	AnswerCacheTTL      *string           `mapstructure:"answer_cache_ttl"`
+	AnswerCacheStale    *string           `mapstructure:"answer_cache_stale_if_error"`
+	VirtualAnswers      map[string]string `mapstructure:"virtual_answers"`
+	VirtualTTL          *string           `mapstructure:"virtual_ttl"`
+	ServiceMetricsLimit *int              `mapstructure:"service_metrics_limit"`
 
 	// Enterprise Only
 	PreferNamespace *bool `mapstructure:"prefer_namespace"`
@@ -660,33 +763,46 @@ type Performance struct {
 }
 
 type Telemetry struct {
-	CirconusAPIApp                     *string  `mapstructure:"circonus_api_app" json:"circonus_api_app,omitempty"`
-	CirconusAPIToken                   *string  `mapstructure:"circonus_api_token" json:"circonus_api_token,omitempty"`
-	CirconusAPIURL                     *string  `mapstructure:"circonus_api_url" json:"circonus_api_url,omitempty"`
-	CirconusBrokerID                   *string  `mapstructure:"circonus_broker_id" json:"circonus_broker_id,omitempty"`
-	CirconusBrokerSelectTag            *string  `mapstructure:"circonus_broker_select_tag" json:"circonus_broker_select_tag,omitempty"`
-	CirconusCheckDisplayName           *string  `mapstructure:"circonus_check_display_name" json:"circonus_check_display_name,omitempty"`
-	CirconusCheckForceMetricActivation *string  `mapstructure:"circonus_check_force_metric_activation" json:"circonus_check_force_metric_activation,omitempty"`
-	CirconusCheckID                    *string  `mapstructure:"circonus_check_id" json:"circonus_check_id,omitempty"`
-	CirconusCheckInstanceID            *string  `mapstructure:"circonus_check_instance_id" json:"circonus_check_instance_id,omitempty"`
-	CirconusCheckSearchTag             *string  `mapstructure:"circonus_check_search_tag" json:"circonus_check_search_tag,omitempty"`
-	CirconusCheckTags                  *string  `mapstructure:"circonus_check_tags" json:"circonus_check_tags,omitempty"`
-	CirconusSubmissionInterval         *string  `mapstructure:"circonus_submission_interval" json:"circonus_submission_interval,omitempty"`
-	CirconusSubmissionURL              *string  `mapstructure:"circonus_submission_url" json:"circonus_submission_url,omitempty"`
-	DisableHostname                    *bool    `mapstructure:"disable_hostname" json:"disable_hostname,omitempty"`
-	DogstatsdAddr                      *string  `mapstructure:"dogstatsd_addr" json:"dogstatsd_addr,omitempty"`
-	DogstatsdTags                      []string `mapstructure:"dogstatsd_tags" json:"dogstatsd_tags,omitempty"`
-	RetryFailedConfiguration           *bool    `mapstructure:"retry_failed_connection" json:"retry_failed_connection,omitempty"`
-	FilterDefault                      *bool    `mapstructure:"filter_default" json:"filter_default,omitempty"`
-	PrefixFilter                       []string `mapstructure:"prefix_filter" json:"prefix_filter,omitempty"`
-	MetricsPrefix                      *string  `mapstructure:"metrics_prefix" json:"metrics_prefix,omitempty"`
-	PrometheusRetentionTime            *string  `mapstructure:"prometheus_retention_time" json:"prometheus_retention_time,omitempty"`
-	StatsdAddr                         *string  `mapstructure:"statsd_address" json:"statsd_address,omitempty"`
-	StatsiteAddr                       *string  `mapstructure:"statsite_address" json:"statsite_address,omitempty"`
+	CirconusAPIApp                     *string           `mapstructure:"circonus_api_app" json:"circonus_api_app,omitempty"`
+	CirconusAPIToken                   *string           `mapstructure:"circonus_api_token" json:"circonus_api_token,omitempty"`
+	CirconusAPIURL                     *string           `mapstructure:"circonus_api_url" json:"circonus_api_url,omitempty"`
+	CirconusBrokerID                   *string           `mapstructure:"circonus_broker_id" json:"circonus_broker_id,omitempty"`
+	CirconusBrokerSelectTag            *string           `mapstructure:"circonus_broker_select_tag" json:"circonus_broker_select_tag,omitempty"`
+	CirconusCheckDisplayName           *string           `mapstructure:"circonus_check_display_name" json:"circonus_check_display_name,omitempty"`
+	CirconusCheckForceMetricActivation *string           `mapstructure:"circonus_check_force_metric_activation" json:"circonus_check_force_metric_activation,omitempty"`
+	CirconusCheckID                    *string           `mapstructure:"circonus_check_id" json:"circonus_check_id,omitempty"`
+	CirconusCheckInstanceID            *string           `mapstructure:"circonus_check_instance_id" json:"circonus_check_instance_id,omitempty"`
+	CirconusCheckSearchTag             *string           `mapstructure:"circonus_check_search_tag" json:"circonus_check_search_tag,omitempty"`
+	CirconusCheckTags                  *string           `mapstructure:"circonus_check_tags" json:"circonus_check_tags,omitempty"`
+	CirconusSubmissionInterval         *string           `mapstructure:"circonus_submission_interval" json:"circonus_submission_interval,omitempty"`
+	CirconusSubmissionURL              *string           `mapstructure:"circonus_submission_url" json:"circonus_submission_url,omitempty"`
+	DisableHostname                    *bool             `mapstructure:"disable_hostname" json:"disable_hostname,omitempty"`
+	DogstatsdAddr                      *string           `mapstructure:"dogstatsd_addr" json:"dogstatsd_addr,omitempty"`
+	DogstatsdTags                      []string          `mapstructure:"dogstatsd_tags" json:"dogstatsd_tags,omitempty"`
+	RetryFailedConfiguration           *bool             `mapstructure:"retry_failed_connection" json:"retry_failed_connection,omitempty"`
+	FilterDefault                      *bool             `mapstructure:"filter_default" json:"filter_default,omitempty"`
+	PrefixFilter                       []string          `mapstructure:"prefix_filter" json:"prefix_filter,omitempty"`
+	MetricsPrefix                      *string           `mapstructure:"metrics_prefix" json:"metrics_prefix,omitempty"`
+	OTLPEndpoint                       *string           `mapstructure:"otlp_endpoint" json:"otlp_endpoint,omitempty"`
+	OTLPProtocol                       *string           `mapstructure:"otlp_protocol" json:"otlp_protocol,omitempty"`
+	OTLPInsecure                       *bool             `mapstructure:"otlp_insecure" json:"otlp_insecure,omitempty"`
+	OTLPHeaders                        map[string]string `mapstructure:"otlp_headers" json:"otlp_headers,omitempty"`
+	OTLPExportInterval                 *string           `mapstructure:"otlp_export_interval" json:"otlp_export_interval,omitempty"`
+	OTLPTemporality                    *string           `mapstructure:"otlp_temporality" json:"otlp_temporality,omitempty"`
+	OTLPResourceAttributes             map[string]string `mapstructure:"otlp_resource_attributes" json:"otlp_resource_attributes,omitempty"`
+	OTLPLogs                           *bool             `mapstructure:"otlp_logs" json:"otlp_logs,omitempty"`
+	PrometheusRetentionTime            *string           `mapstructure:"prometheus_retention_time" json:"prometheus_retention_time,omitempty"`
+	RPCServiceLabels                   *bool             `mapstructure:"rpc_service_labels" json:"rpc_service_labels,omitempty"`
+	RPCPeerLabels                      *bool             `mapstructure:"rpc_peer_labels" json:"rpc_peer_labels,omitempty"`
+	RPCLabelsMaxValues                 *int              `mapstructure:"rpc_labels_max_values" json:"rpc_labels_max_values,omitempty"`
+	StatsdAddr                         *string           `mapstructure:"statsd_address" json:"statsd_address,omitempty"`
+	StatsiteAddr                       *string           `mapstructure:"statsite_address" json:"statsite_address,omitempty"`
 }
 
 type Ports struct {
 	DNS            *int `mapstructure:"dns" json:"dns,omitempty"`
+	DNSTLS         *int `mapstructure:"dns_tls" json:"dns_tls,omitempty"`
+	DNSHTTPS       *int `mapstructure:"dns_https" json:"dns_https,omitempty"`
 	HTTP           *int `mapstructure:"http" json:"http,omitempty"`
 	HTTPS          *int `mapstructure:"https" json:"https,omitempty"`
 	SerfLAN        *int `mapstructure:"serf_lan" json:"serf_lan,omitempty"`
@@ -702,6 +818,10 @@ type Ports struct {
 	ExposeMaxPort  *int `mapstructure:"expose_max_port" json:"expose_max_port,omitempty"`
 }
 
+type NamedPipe struct {
+	SecurityDescriptor *string `mapstructure:"security_descriptor"`
+}
+
 type UnixSocket struct {
 	Group *string `mapstructure:"group"`
 	Mode  *string `mapstructure:"mode"`
@@ -709,11 +829,41 @@ type UnixSocket struct {
 }
 
 type RequestLimits struct {
-	Mode      *string  `mapstructure:"mode"`
+	Mode      *string               `mapstructure:"mode"`
+	ReadRate  *float64              `mapstructure:"read_rate"`
+	WriteRate *float64              `mapstructure:"write_rate"`
+	PerIP     RequestLimitsPerIP    `mapstructure:"per_ip"`
+	PerToken  RequestLimitsPerToken `mapstructure:"per_token"`
+}
+
+// RequestLimitsPerIP limits the requests of each source IP address
+type RequestLimitsPerIP struct {
+	ReadRate  *float64                  `mapstructure:"read_rate"`
+	WriteRate *float64                  `mapstructure:"write_rate"`
+	Overrides []RequestLimitsIPOverride `mapstructure:"override"`
+}
+
+// RequestLimitsIPOverride replaces the per-IP limits of the addresses in a CIDR block
+type RequestLimitsIPOverride struct {
+	CIDR      *string  `mapstructure:"cidr"`
 	ReadRate  *float64 `mapstructure:"read_rate"`
 	WriteRate *float64 `mapstructure:"write_rate"`
 }
 
+// RequestLimitsPerToken limits the requests made with each ACL token
+type RequestLimitsPerToken struct {
+	ReadRate  *float64                     `mapstructure:"read_rate"`
+	WriteRate *float64                     `mapstructure:"write_rate"`
+	Overrides []RequestLimitsTokenOverride `mapstructure:"override"`
+}
+
+// RequestLimitsTokenOverride replaces the per-token limits of an ACL token
+type RequestLimitsTokenOverride struct {
+	AccessorID *string  `mapstructure:"accessor_id"`
+	ReadRate   *float64 `mapstructure:"read_rate"`
+	WriteRate  *float64 `mapstructure:"write_rate"`
+}
+
 type Limits struct {
 	HTTPMaxConnsPerClient *int          `mapstructure:"http_max_conns_per_client"`
 	HTTPSHandshakeTimeout *string       `mapstructure:"https_handshake_timeout"`
@@ -744,6 +894,7 @@ type ACL struct {
 	DownPolicy             *string `mapstructure:"down_policy"`
 	DefaultPolicy          *string `mapstructure:"default_policy"`
 	EnableKeyListPolicy    *bool   `mapstructure:"enable_key_list_policy"`
+	ClientCertAuthMethod   *string `mapstructure:"client_cert_auth_method"`
 	Tokens                 Tokens  `mapstructure:"tokens"`
 	EnableTokenPersistence *bool   `mapstructure:"enable_token_persistence"`
 
@@ -791,6 +942,7 @@ type ConfigEntries struct {
 type Audit struct {
 	Enabled *bool                `mapstructure:"enabled"`
 	Sinks   map[string]AuditSink `mapstructure:"sink"`
+	Filters []AuditFilter        `mapstructure:"filter"`
 }
 
 // AuditSink can be provided multiple times to define pipelines for auditing
@@ -803,6 +955,16 @@ type AuditSink struct {
 	RotateBytes       *int    `mapstructure:"rotate_bytes"`
 	RotateDuration    *string `mapstructure:"rotate_duration"`
 	RotateMaxFiles    *int    `mapstructure:"rotate_max_files"`
+	SyslogFacility    *string `mapstructure:"syslog_facility"`
+	SyslogTag         *string `mapstructure:"syslog_tag"`
+}
+
+// AuditFilter includes or excludes the audit events matching all its criteria
+type AuditFilter struct {
+	Action     *string  `mapstructure:"action"`
+	Operations []string `mapstructure:"operations"`
+	Resources  []string `mapstructure:"resources"`
+	Results    []string `mapstructure:"results"`
 }
 
 type AutoConfigRaw struct {
@@ -914,10 +1076,59 @@ type Peering struct {
 	// TestAllowPeerRegistrations controls whether CatalogRegister endpoints allow registrations for objects with `PeerName`
 	// This always gets overridden in NonUserSource()
 	TestAllowPeerRegistrations *bool `mapstructure:"test_allow_peer_registrations" json:"test_allow_peer_registrations,omitempty"`
+
+	// IncomingMessageRate and IncomingMessageBurst limit how quickly servers
+	// process messages received on the replication stream of each peer.
+	IncomingMessageRate  *float64 `mapstructure:"incoming_message_rate" json:"incoming_message_rate,omitempty"`
+	IncomingMessageBurst *int     `mapstructure:"incoming_message_burst" json:"incoming_message_burst,omitempty"`
 }
 
 type XDS struct {
 	UpdateMaxPerSecond *float64 `mapstructure:"update_max_per_second"`
+	StrictEnvoyVersion *bool    `mapstructure:"strict_envoy_version"`
+}
+
+type ServicesAutoReload struct {
+	Dir      *string `mapstructure:"dir" json:"dir,omitempty"`
+	Debounce *string `mapstructure:"debounce" json:"debounce,omitempty"`
+}
+
+type EncryptRotationRaw struct {
+	Enabled     *bool   `mapstructure:"enabled" json:"enabled,omitempty"`
+	Interval    *string `mapstructure:"interval" json:"interval,omitempty"`
+	GracePeriod *string `mapstructure:"grace_period" json:"grace_period,omitempty"`
+}
+
+// RetryJoinFilters are the arguments added to the go-discover configurations
+// of retry_join, retry_join_wan and primary_gateways, by provider.
+type RetryJoinFilters map[string]map[string]string
+
+// ChangeSinks are the sinks the changes of the catalog and of the config
+// entries are pushed to, by name.
+type ChangeSinks map[string]ChangeSink
+
+type ChangeSink struct {
+	Type       *string           `mapstructure:"type"`
+	URL        *string           `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	KafkaTopic *string           `mapstructure:"kafka_topic"`
+	Topics     []string          `mapstructure:"topics"`
+	Timeout    *string           `mapstructure:"timeout"`
+}
+
+type ExternalHealthRaw struct {
+	Enabled           *bool   `mapstructure:"enabled" json:"enabled,omitempty"`
+	NodeProbeInterval *string `mapstructure:"node_probe_interval" json:"node_probe_interval,omitempty"`
+}
+
+type ProfilingRaw struct {
+	Enabled     *bool    `mapstructure:"enabled" json:"enabled,omitempty"`
+	Dir         *string  `mapstructure:"dir" json:"dir,omitempty"`
+	Interval    *string  `mapstructure:"interval" json:"interval,omitempty"`
+	CPUDuration *string  `mapstructure:"cpu_duration" json:"cpu_duration,omitempty"`
+	Profiles    []string `mapstructure:"profiles" json:"profiles,omitempty"`
+	Retain      *int     `mapstructure:"retain" json:"retain,omitempty"`
+	PushURL     *string  `mapstructure:"push_url" json:"push_url,omitempty"`
 }
 
 type RaftLogStoreRaw struct {
@@ -931,6 +1142,12 @@ type RaftLogStoreRaw struct {
 	WALConfig RaftWALConfigRaw `mapstructure:"wal" json:"wal,omitempty"`
 }
 
+type RaftCompressionRaw struct {
+	LogThreshold *int    `mapstructure:"log_threshold" json:"log_threshold,omitempty"`
+	Snapshots    *bool   `mapstructure:"snapshots" json:"snapshots,omitempty"`
+	Level        *string `mapstructure:"level" json:"level,omitempty"`
+}
+
 type RaftLogStoreVerificationRaw struct {
 	Enabled  *bool   `mapstructure:"enabled" json:"enabled,omitempty"`
 	Interval *string `mapstructure:"interval" json:"interval,omitempty"`
@@ -942,4 +1159,12 @@ type RaftBoltDBConfigRaw struct {
 
 type RaftWALConfigRaw struct {
 	SegmentSizeMB *int `mapstructure:"segment_size_mb" json:"segment_size_mb,omitempty"`
+
+	Canary RaftWALCanaryRaw `mapstructure:"canary" json:"canary,omitempty"`
+}
+
+type RaftWALCanaryRaw struct {
+	Enabled        *bool   `mapstructure:"enabled" json:"enabled,omitempty"`
+	VerifyInterval *string `mapstructure:"verify_interval" json:"verify_interval,omitempty"`
+	Failback       *bool   `mapstructure:"failback" json:"failback,omitempty"`
 }
@@ -71,14 +71,7 @@ func DefaultSource() Source {
 			max_trailing_logs = 250
 			server_stabilization_time = "10s"
 		}
-		gossip_lan = {
-			gossip_interval = "` + serfLAN.GossipInterval.String() + `"
-			gossip_nodes = ` + strconv.Itoa(serfLAN.GossipNodes) + `
-			retransmit_mult = ` + strconv.Itoa(serfLAN.RetransmitMult) + `
-			probe_interval = "` + serfLAN.ProbeInterval.String() + `"
-			probe_timeout = "` + serfLAN.ProbeTimeout.String() + `"
-			suspicion_mult = ` + strconv.Itoa(serfLAN.SuspicionMult) + `
-		}
+		// The gossip_lan defaults are taken from the gossip_lan.profile.
 		gossip_wan = {
 			gossip_interval = "` + serfWAN.GossipInterval.String() + `"
 			gossip_nodes = ` + strconv.Itoa(serfLAN.GossipNodes) + `
@@ -101,6 +94,14 @@ func DefaultSource() Source {
 				mode = "disabled"
 				read_rate = -1
 				write_rate = -1
+				per_ip = {
+					read_rate = -1
+					write_rate = -1
+				}
+				per_token = {
+					read_rate = -1
+					write_rate = -1
+				}
 			}
 			rpc_handshake_timeout = "5s"
 			rpc_client_timeout = "60s"
@@ -136,14 +137,37 @@ func DefaultSource() Source {
 			filter_default = true
 			prefix_filter = []
 			retry_failed_connection = true
+			otlp_protocol = "grpc"
+			otlp_export_interval = "60s"
+			otlp_temporality = "cumulative"
+			rpc_labels_max_values = 100
 		}
 		raft_snapshot_threshold = ` + strconv.Itoa(int(cfg.RaftConfig.SnapshotThreshold)) + `
 		raft_snapshot_interval =  "` + cfg.RaftConfig.SnapshotInterval.String() + `"
 		raft_trailing_logs = ` + strconv.Itoa(int(cfg.RaftConfig.TrailingLogs)) + `
+		encrypt_rotation {
+			interval = "720h"
+			grace_period = "1h"
+		}
+		external_health_checks {
+			node_probe_interval = "10s"
+		}
+		profiling {
+			interval = "5m"
+			cpu_duration = "10s"
+			retain = 12
+		}
+		raft_compression {
+			level = "default"
+		}
 		raft_logstore {
 			backend = "boltdb"
 			wal {
 				segment_size_mb = 64
+				canary {
+					verify_interval = "1m"
+					failback = true
+				}
 			}
 		}
 		xds {
@@ -156,6 +180,8 @@ func DefaultSource() Source {
 
 		peering = {
 			enabled = true
+			incoming_message_rate = -1
+			incoming_message_burst = 1000
 		}
 	`,
 	}
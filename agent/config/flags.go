@@ -40,6 +40,8 @@ func AddFlags(fs *flag.FlagSet, f *LoadOpts) {
 	add(&f.ConfigFiles, "config-dir", "Path to a directory to read configuration files from. This will read every file ending in '.json' as configuration in this directory in alphabetical order. Can be specified multiple times.")
 	add(&f.ConfigFiles, "config-file", "Path to a file in JSON or HCL format with a matching file extension. Can be specified multiple times.")
 	fs.StringVar(&f.ConfigFormat, "config-format", "", "Config files are in this format irrespective of their extension. Must be 'hcl' or 'json'")
+	add(&f.Interpolation.EnvVars, "config-interpolate-env", "Name of an environment variable that config files may reference with ${env:NAME}. A trailing '*' allows all the variables with that prefix. Can be specified multiple times.")
+	add(&f.Interpolation.FilePaths, "config-interpolate-file", "Absolute path of a file, or of a directory of files, that config files may reference with ${file:PATH}. Can be specified multiple times.")
 	add(&f.FlagValues.DataDir, "data-dir", "Path to a data directory to store agent state.")
 	add(&f.FlagValues.Datacenter, "datacenter", "Datacenter of the agent.")
 	add(&f.FlagValues.DefaultQueryTime, "default-query-time", "the amount of time a blocking query will wait before Consul will force a response. This value can be overridden by the 'wait' query parameter.")
@@ -69,8 +71,8 @@ func AddFlags(fs *flag.FlagSet, f *LoadOpts) {
 	add(&f.FlagValues.NodeName, "node", "Name of this node. Must be unique in the cluster.")
 	add(&f.FlagValues.NodeID, "node-id", "A unique ID for this node across space and time. Defaults to a randomly-generated ID that persists in the data-dir.")
 	add(&f.FlagValues.NodeMeta, "node-meta", "An arbitrary metadata key/value pair for this node, of the format `key:value`. Can be specified multiple times.")
-	add(&f.FlagValues.ReadReplica, "non-voting-server", "(Enterprise-only) DEPRECATED: -read-replica should be used instead")
-	add(&f.FlagValues.ReadReplica, "read-replica", "(Enterprise-only) This flag is used to make the server not participate in the Raft quorum, and have it only receive the data replication stream. This can be used to add read scalability to a cluster in cases where a high volume of reads to servers are needed.")
+	add(&f.FlagValues.ReadReplica, "non-voting-server", "DEPRECATED: -read-replica should be used instead")
+	add(&f.FlagValues.ReadReplica, "read-replica", "This flag is used to make the server not participate in the Raft quorum, and have it only receive the data replication stream. This can be used to add read scalability to a cluster in cases where a high volume of reads to servers are needed, as the clients send their stale reads to the read replicas.")
 	add(&f.FlagValues.PidFile, "pid-file", "Path to file to store agent PID.")
 	add(&f.FlagValues.RPCProtocol, "protocol", "Sets the protocol version. Defaults to latest.")
 	add(&f.FlagValues.RaftProtocol, "raft-protocol", "Sets the Raft protocol version. Defaults to latest.")
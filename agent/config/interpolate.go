@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Interpolation lists the environment variables and files that the config
+// files are allowed to reference with ${env:NAME} and ${file:PATH}. The
+// references are replaced with the value of the environment variable or the
+// contents of the file, without the trailing newline, before the config is
+// decoded. Only string values are interpolated, and "$${" escapes a
+// reference. References are left untouched when nothing is allowed, so that
+// existing configs keep their meaning.
+type Interpolation struct {
+	// EnvVars are the names of the environment variables that may be
+	// referenced. A name ending with "*" allows all the variables with that
+	// prefix.
+	EnvVars []string
+
+	// FilePaths are the absolute paths of the files, or of the directories
+	// containing the files, that may be referenced.
+	FilePaths []string
+}
+
+// Enabled returns true if any reference is allowed.
+func (i Interpolation) Enabled() bool {
+	return len(i.EnvVars) > 0 || len(i.FilePaths) > 0
+}
+
+func (i Interpolation) allowEnv(name string) bool {
+	for _, allowed := range i.EnvVars {
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// allowFile returns whether path is one of the allowed files or is in one of
+// the allowed directories. The allowed paths are compared both as given and
+// with their symlinks resolved, on whole segments so that a sibling sharing a
+// prefix doesn't match.
+func (i Interpolation) allowFile(path string) bool {
+	for _, allowed := range i.FilePaths {
+		allowed = filepath.Clean(allowed)
+		candidates := []string{allowed}
+		if resolved, err := filepath.EvalSymlinks(allowed); err == nil && resolved != allowed {
+			candidates = append(candidates, resolved)
+		}
+		for _, c := range candidates {
+			dir := strings.TrimSuffix(c, string(filepath.Separator)) + string(filepath.Separator)
+			if path == c || strings.HasPrefix(path, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookup returns the value of a reference such as "env:NAME".
+func (i Interpolation) lookup(ref string) (string, error) {
+	kind, arg, ok := strings.Cut(ref, ":")
+	if !ok || arg == "" {
+		return "", fmt.Errorf("invalid reference ${%s}, must be ${env:NAME} or ${file:PATH}", ref)
+	}
+
+	switch kind {
+	case "env":
+		if !i.allowEnv(arg) {
+			return "", fmt.Errorf("environment variable %q is not allowed, use -config-interpolate-env to allow it", arg)
+		}
+		v, ok := os.LookupEnv(arg)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", arg)
+		}
+		return v, nil
+	case "file":
+		if !filepath.IsAbs(arg) {
+			return "", fmt.Errorf("file path %q must be absolute", arg)
+		}
+		path := filepath.Clean(arg)
+		if !i.allowFile(path) {
+			return "", fmt.Errorf("file %q is not allowed, use -config-interpolate-file to allow it", path)
+		}
+		// The file is checked again once its symlinks are resolved, so that a
+		// symlink in an allowed directory can't point outside of them.
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", err
+		}
+		if !i.allowFile(resolved) {
+			return "", fmt.Errorf("file %q is not allowed, it resolves to %q outside of the allowed paths", path, resolved)
+		}
+		data, err := os.ReadFile(resolved)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("invalid reference ${%s}, must be ${env:NAME} or ${file:PATH}", ref)
+	}
+}
+
+// interpolateString replaces the references in s.
+func (i Interpolation) interpolateString(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for {
+		idx := strings.Index(s, "${")
+		if idx < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+
+		// "$${" is an escaped reference and is kept as "${".
+		if idx > 0 && s[idx-1] == '$' {
+			sb.WriteString(s[:idx-1])
+			sb.WriteString("${")
+			s = s[idx+2:]
+			continue
+		}
+
+		end := strings.Index(s[idx:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", s)
+		}
+		v, err := i.lookup(s[idx+2 : idx+end])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s[:idx])
+		sb.WriteString(v)
+		s = s[idx+end+1:]
+	}
+}
+
+// interpolate replaces the references in the string values of raw, as
+// decoded from HCL or JSON. The keys are never interpolated.
+func (i Interpolation) interpolate(raw interface{}, path string) (interface{}, error) {
+	switch v := raw.(type) {
+	case string:
+		s, err := i.interpolateString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return s, nil
+	case map[string]interface{}:
+		for k, elem := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			res, err := i.interpolate(elem, p)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = res
+		}
+		return v, nil
+	case []map[string]interface{}:
+		for idx, elem := range v {
+			if _, err := i.interpolate(elem, fmt.Sprintf("%s[%d]", path, idx)); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []interface{}:
+		for idx, elem := range v {
+			res, err := i.interpolate(elem, fmt.Sprintf("%s[%d]", path, idx))
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = res
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
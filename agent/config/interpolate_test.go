@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Interpolation(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0600))
+
+	// A symlink in the allowed directory pointing outside of it, and a sibling
+	// directory sharing its prefix.
+	outside := t.TempDir()
+	secretFile := filepath.Join(outside, "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0600))
+	require.NoError(t, os.Symlink(secretFile, filepath.Join(dir, "escape")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "escape-dir")))
+	sibling := dir + "-sibling"
+	require.NoError(t, os.Mkdir(sibling, 0700))
+	t.Cleanup(func() { os.RemoveAll(sibling) })
+	require.NoError(t, os.WriteFile(filepath.Join(sibling, "token"), []byte("sibling\n"), 0600))
+
+	// A symlink to the allowed directory may be used in its place.
+	linkDir := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(dir, linkDir))
+
+	t.Setenv("CONSUL_TEST_NODE", "interpolated-node")
+	t.Setenv("CONSUL_TEST_DC", "dc9")
+	t.Setenv("CONSUL_OTHER", "not-allowed")
+
+	load := func(t *testing.T, interpolation Interpolation, hcl string) (*RuntimeConfig, error) {
+		devMode := true
+		result, err := Load(LoadOpts{
+			DevMode:       &devMode,
+			HCL:           []string{hcl},
+			Interpolation: interpolation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.RuntimeConfig, nil
+	}
+
+	allowed := Interpolation{
+		EnvVars:   []string{"CONSUL_TEST_*"},
+		FilePaths: []string{dir},
+	}
+
+	t.Run("env and file", func(t *testing.T) {
+		rt, err := load(t, allowed, `
+			node_name = "${env:CONSUL_TEST_NODE}"
+			datacenter = "${env:CONSUL_TEST_DC}"
+			node_meta {
+				token = "prefix-${file:`+tokenFile+`}"
+				escaped = "$${env:CONSUL_TEST_DC}"
+			}
+		`)
+		require.NoError(t, err)
+		require.Equal(t, "interpolated-node", rt.NodeName)
+		require.Equal(t, "dc9", rt.Datacenter)
+		require.Equal(t, "prefix-file-token", rt.NodeMeta["token"])
+		require.Equal(t, "${env:CONSUL_TEST_DC}", rt.NodeMeta["escaped"])
+	})
+
+	t.Run("symlinked allowed dir", func(t *testing.T) {
+		linked := Interpolation{FilePaths: []string{linkDir}}
+		rt, err := load(t, linked, `node_meta { token = "${file:`+linkDir+`/token}" }`)
+		require.NoError(t, err)
+		require.Equal(t, "file-token", rt.NodeMeta["token"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rt, err := load(t, Interpolation{}, `node_meta { ref = "${env:CONSUL_TEST_DC}" }`)
+		require.NoError(t, err)
+		require.Equal(t, "${env:CONSUL_TEST_DC}", rt.NodeMeta["ref"])
+	})
+
+	cases := map[string]struct {
+		hcl    string
+		expect string
+	}{
+		"env not allowed": {
+			hcl:    `node_name = "${env:CONSUL_OTHER}"`,
+			expect: `node_name: environment variable "CONSUL_OTHER" is not allowed`,
+		},
+		"env not set": {
+			hcl:    `node_name = "${env:CONSUL_TEST_MISSING}"`,
+			expect: `environment variable "CONSUL_TEST_MISSING" is not set`,
+		},
+		"file not allowed": {
+			hcl:    `node_name = "${file:/etc/passwd}"`,
+			expect: `file "/etc/passwd" is not allowed`,
+		},
+		"file escaping the allowed dir": {
+			hcl:    `node_name = "${file:` + dir + `/../token}"`,
+			expect: "is not allowed",
+		},
+		"symlink escaping the allowed dir": {
+			hcl:    `node_name = "${file:` + dir + `/escape}"`,
+			expect: "outside of the allowed paths",
+		},
+		"symlinked dir escaping the allowed dir": {
+			hcl:    `node_name = "${file:` + dir + `/escape-dir/secret}"`,
+			expect: "outside of the allowed paths",
+		},
+		"sibling sharing the prefix of the allowed dir": {
+			hcl:    `node_name = "${file:` + sibling + `/token}"`,
+			expect: "is not allowed",
+		},
+		"relative file": {
+			hcl:    `node_name = "${file:token}"`,
+			expect: `file path "token" must be absolute`,
+		},
+		"unknown kind": {
+			hcl:    `node_name = "${vault:secret}"`,
+			expect: "invalid reference ${vault:secret}",
+		},
+	}
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := load(t, allowed, tc.hcl)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expect)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+// NamedPipeAddr is the address of a Windows named pipe, configured with the
+// "npipe://" scheme, e.g. "npipe:////./pipe/consul".
+type NamedPipeAddr struct {
+	Path string
+}
+
+func (a *NamedPipeAddr) Network() string {
+	return "npipe"
+}
+
+func (a *NamedPipeAddr) String() string {
+	return a.Path
+}
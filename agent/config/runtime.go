@@ -10,9 +10,12 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/consul"
+	"github.com/hashicorp/consul/agent/consul/changesink"
 	consulrate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/debug"
 	"github.com/hashicorp/consul/agent/dns"
 	hcpconfig "github.com/hashicorp/consul/agent/hcp/config"
 	"github.com/hashicorp/consul/agent/structs"
@@ -31,6 +34,54 @@ type RuntimeSOAConfig struct {
 	Minttl  uint32 // 0,
 }
 
+// AutoEncryptVaultConfig is the configuration of the Vault server the clients
+// obtain their TLS certificate from.
+type AutoEncryptVaultConfig struct {
+	// Address is the address of the Vault server, or of a Vault agent
+	// proxying the requests to it.
+	Address string
+
+	// Token is the token used to issue the certificates. TokenFile is read
+	// before each request instead when it is set, so that it can be renewed
+	// by a Vault agent.
+	Token     string
+	TokenFile string
+
+	// Namespace is the Vault namespace of the PKI secrets engine.
+	Namespace string
+
+	// PKIPath is the path the PKI secrets engine is mounted at, "pki" by
+	// default, and Role the role the certificates are issued with.
+	PKIPath string
+	Role    string
+
+	// TTL is the requested lifetime of the certificates. The default of the
+	// role is used when it is zero.
+	TTL time.Duration
+
+	// The TLS configuration of the connections to Vault.
+	CAFile        string
+	CAPath        string
+	TLSServerName string
+	TLSSkipVerify bool
+}
+
+// RuntimeDNSView is an additional DNS domain served by the agent with its own
+// answer policy for service lookups.
+type RuntimeDNSView struct {
+	// Domain is the domain of the view. Should end with a dot.
+	Domain string
+
+	// OnlyPassing limits answers to instances with passing health checks.
+	OnlyPassing bool
+
+	// Tags limits answers to instances with all of the tags.
+	Tags []string
+
+	// ExcludeTags removes instances with any of the tags from answers.
+	ExcludeTags []string
+}
+
 // StaticRuntimeConfig specifies the subset of configuration the consul agent actually
 // uses and that are not reloadable by configuration auto reload.
 type StaticRuntimeConfig struct {
@@ -93,6 +144,13 @@ type RuntimeConfig struct {
 	// hcl: acl.enable_key_list_policy = (true|false)
 	ACLEnableKeyListPolicy bool
 
+	// ACLClientCertAuthMethod is the name of the auth method the servers login
+	// with on behalf of the clients presenting a certificate to the HTTPS or
+	// external gRPC API without a token.
+	//
+	// hcl: acl.client_cert_auth_method = string
+	ACLClientCertAuthMethod string
+
 	// ACLInitialManagementToken is used to bootstrap the ACL system. It should be specified
 	// on the servers in the PrimaryDatacenter. When the leader comes online, it ensures
 	// that the initial management token is available. This provides the initial token.
@@ -131,12 +189,26 @@ type RuntimeConfig struct {
 	// hcl: autopilot { max_trailing_logs = int }
 	AutopilotMaxTrailingLogs int
 
+	// AutopilotMinServerUptime is the minimum amount of time the leader must
+	// have seen a server alive before promoting it to a voter, in addition to
+	// the server stabilization time.
+	//
+	// hcl: autopilot { min_server_uptime = "duration" }
+	AutopilotMinServerUptime time.Duration
+
 	// AutopilotMinQuorum sets the minimum number of servers required in a cluster
 	// before autopilot can prune dead servers.
 	//
 	// hcl: autopilot { min_quorum = int }
 	AutopilotMinQuorum uint
 
+	// AutopilotPromotionStrategy is the strategy the leader uses to promote
+	// the servers to voters and demote them: "stable" promotes every stable
+	// server, "zones" keeps AutopilotVotersPerZone voters in each zone.
+	//
+	// hcl: autopilot { promotion_strategy = ("stable"|"zones") }
+	AutopilotPromotionStrategy string
+
 	// AutopilotRedundancyZoneTag is the Meta tag to use for separating servers
 	// into zones for redundancy. If left blank, this feature will be disabled.
 	// (Enterprise-only)
@@ -159,6 +231,18 @@ type RuntimeConfig struct {
 	// hcl: autopilot { upgrade_version_tag = string }
 	AutopilotUpgradeVersionTag string
 
+	// AutopilotVotersPerZone is the number of voters the "zones" promotion
+	// strategy keeps in each zone.
+	//
+	// hcl: autopilot { voters_per_zone = int }
+	AutopilotVotersPerZone int
+
+	// AutopilotZoneMetaKey is the node meta key holding the zone of the
+	// servers for the "zones" promotion strategy.
+	//
+	// hcl: autopilot { zone_meta_key = string }
+	AutopilotZoneMetaKey string
+
 	// Cloud contains configuration for agents to connect to HCP.
 	//
 	// hcl: cloud { ... }
@@ -213,6 +297,26 @@ type RuntimeConfig struct {
 	// flag: -alt-domain string
 	DNSAltDomain string
 
+	// DNSPeerDomain can be set to resolve services imported from cluster
+	// peers on a separate domain, where the label before the domain is the
+	// peer name. For example with "mesh" a query for
+	// api.virtual.peer-east.mesh is answered like
+	// api.virtual.peer-east.peer.consul. Should end with a dot.
+	// If left blank, imported services are only resolved on the primary
+	// and alternate domains.
+	//
+	// hcl: dns_config { peer_domain = string }
+	DNSPeerDomain string
+
+	// DNSViews are additional domains answered by the DNS server, each with
+	// its own policy for service lookups. Queries in a view are resolved like
+	// queries in the primary domain, so api.service.internal is answered like
+	// api.service.consul for a view with the "internal" domain. The
+	// only_passing setting of a view defaults to dns_config.only_passing.
+	//
+	// hcl: dns_config { views = [{ domain = string only_passing = (true|false) tags = []string exclude_tags = []string }] }
+	DNSViews []RuntimeDNSView
+
 	// DNSEnableTruncate is used to enable setting the truncate
 	// flag for UDP DNS queries.  This allows unmodified
 	// clients to re-query the consul server using TCP
@@ -266,6 +370,28 @@ type RuntimeConfig struct {
 	// hcl: dns_config { service_ttl = map[string]"duration" }
 	DNSServiceTTL map[string]time.Duration
 
+	// DNSVirtualAnswers selects, per service, whether <service>.virtual
+	// queries are answered with the virtual IP of the service or with the
+	// addresses of its instances. Like DNSServiceTTL, keys ending with "*"
+	// match services by prefix. Services without a match are answered with
+	// their virtual IP.
+	//
+	// hcl: dns_config { virtual_answers = map[string]("virtual-ip"|"instances") }
+	DNSVirtualAnswers map[string]dns.VirtualAnswer
+
+	// DNSVirtualTTL is the TTL of virtual IP answers. Defaults to
+	// DNSNodeTTL.
+	//
+	// hcl: dns_config { virtual_ttl = "duration" }
+	DNSVirtualTTL time.Duration
+
+	// DNSServiceMetricsLimit is the number of services that get their own
+	// label in the dns.service_query metric. Lookups of other services are
+	// counted under the "_other" service. A value of 0 disables the metric.
+	//
+	// hcl: dns_config { service_metrics_limit = int }
+	DNSServiceMetricsLimit int
+
 	// DNSUDPAnswerLimit is used to limit the maximum number of DNS Resource
 	// Records returned in the ANSWER section of a DNS response for UDP
 	// responses without EDNS support (limited to 512 bytes).
@@ -298,6 +424,20 @@ type RuntimeConfig struct {
 	// hcl: dns_config { cache_max_age = "duration" }
 	DNSCacheMaxAge time.Duration
 
+	// DNSAnswerCacheTTL is how long the agent reuses the answer to a DNS
+	// query before resolving it again. A value of 0 disables the answer
+	// cache.
+	//
+	// hcl: dns_config { answer_cache_ttl = "duration" }
+	DNSAnswerCacheTTL time.Duration
+
+	// DNSAnswerCacheStale is how long after DNSAnswerCacheTTL has passed a
+	// cached answer can still be served when resolving the query again fails,
+	// for example while the servers are unavailable.
+	//
+	// hcl: dns_config { answer_cache_stale_if_error = "duration" }
+	DNSAnswerCacheStale time.Duration
+
 	// HTTPUseCache whether or not to use cache for http queries. Defaults
 	// to true.
 	//
@@ -402,6 +542,12 @@ type RuntimeConfig struct {
 	// Cache represent cache configuration of agent
 	Cache cache.Options
 
+	// ChangeSinks are the webhooks and Kafka topics the leader pushes the
+	// changes of the catalog and of the config entries to, sorted by name.
+	//
+	// hcl: change_sink "name" { type = ("webhook"|"kafka") url = string headers { string = string } kafka_topic = string topics = []string timeout = "duration" }
+	ChangeSinks []changesink.Config
+
 	// CheckUpdateInterval controls the interval on which the output of a health check
 	// is updated if there is no change to the state. For example, a check in a steady
 	// state may run every 5 second generating a unique output (timestamp, etc), forcing
@@ -443,6 +589,14 @@ type RuntimeConfig struct {
 	//     timeout = "duration"
 	//     ttl = "duration"
 	//     os_service = string
+	//     process_pid = int
+	//     process_pidfile = string
+	//     process_cgroup = string
+	//     grpc_service = string
+	//     grpc_authority = string
+	//     composite_mode = (and|or)
+	//     composite_checks = []string
+	//     composite_min_passing = int
 	//     success_before_passing = int
 	//     failures_before_warning = int
 	//     failures_before_critical = int
@@ -471,6 +625,12 @@ type RuntimeConfig struct {
 	// If entries of the same Kind/Name exist already these will not update them.
 	ConfigEntryBootstrap []structs.ConfigEntry
 
+	// Audit configures the recording of the operations performed through the
+	// HTTP and gRPC APIs.
+	//
+	// hcl: audit { enabled = (true|false) sink "name" { ... } filter { ... } }
+	Audit audit.Config
+
 	// AutoEncryptTLS requires the client to acquire TLS certificates from
 	// servers.
 	AutoEncryptTLS bool
@@ -487,6 +647,14 @@ type RuntimeConfig struct {
 	// AutoEncrypt.Sign requests.
 	AutoEncryptAllowTLS bool
 
+	// AutoEncryptVault configures the clients to obtain their TLS certificate
+	// from the PKI secrets engine of Vault, and to trust the certificates of
+	// its issuers, rather than to use the Connect CA of the servers. It is
+	// only used when Address is set.
+	//
+	// hcl: auto_encrypt { vault { address = string token = string token_file = string namespace = string pki_path = string role = string ttl = "duration" ca_file = string ca_path = string tls_server_name = string tls_skip_verify = (true|false) } }
+	AutoEncryptVault AutoEncryptVaultConfig
+
 	// AutoConfig is a grouping of the configurations around the agent auto configuration
 	// process including how servers can authorize requests.
 	AutoConfig AutoConfig
@@ -552,6 +720,48 @@ type RuntimeConfig struct {
 	// flags: -dns-port int
 	DNSPort int
 
+	// DNSTLSAddrs contains the list of TCP addresses the DNS-over-TLS server
+	// will bind to. If the DNS-over-TLS endpoint is disabled (ports.dns_tls
+	// <= 0) the list is empty.
+	//
+	// The ip addresses are taken from 'addresses.dns_tls' which should
+	// contain a space separated list of ip addresses and/or go-sockaddr
+	// templates.
+	//
+	// If 'addresses.dns_tls' was not provided the 'client_addr' addresses
+	// are used.
+	//
+	// hcl: client_addr = string addresses { dns_tls = string } ports { dns_tls = int }
+	DNSTLSAddrs []net.Addr
+
+	// DNSTLSPort is the port the DNS-over-TLS server listens on. It is
+	// disabled by default. The server uses the certificate of the HTTPS
+	// endpoint.
+	//
+	// hcl: ports { dns_tls = int }
+	DNSTLSPort int
+
+	// DNSHTTPSAddrs contains the list of TCP addresses the DNS-over-HTTPS
+	// server will bind to. If the DNS-over-HTTPS endpoint is disabled
+	// (ports.dns_https <= 0) the list is empty.
+	//
+	// The ip addresses are taken from 'addresses.dns_https' which should
+	// contain a space separated list of ip addresses and/or go-sockaddr
+	// templates.
+	//
+	// If 'addresses.dns_https' was not provided the 'client_addr' addresses
+	// are used.
+	//
+	// hcl: client_addr = string addresses { dns_https = string } ports { dns_https = int }
+	DNSHTTPSAddrs []net.Addr
+
+	// DNSHTTPSPort is the port the DNS-over-HTTPS server listens on. It is
+	// disabled by default. The server uses the certificate of the HTTPS
+	// endpoint.
+	//
+	// hcl: ports { dns_https = int }
+	DNSHTTPSPort int
+
 	// DNSSOA is the settings applied for DNS SOA
 	// hcl: soa {}
 	DNSSOA RuntimeSOAConfig
@@ -632,6 +842,13 @@ type RuntimeConfig struct {
 	// hcl: discard_check_output = (true|false)
 	DiscardCheckOutput bool
 
+	// DockerHost is the address of the Docker Engine API used by the Docker
+	// checks, e.g. "unix:///run/podman/podman.sock". The DOCKER_HOST
+	// environment variable is used when it is empty.
+	//
+	// hcl: docker_host = string
+	DockerHost string
+
 	// EnableAgentTLSForChecks is used to apply the agent's TLS settings in
 	// order to configure the HTTP client used for health checks. Enabling
 	// this allows HTTP checks to present a client certificate and verify
@@ -672,6 +889,21 @@ type RuntimeConfig struct {
 	// flag: -encrypt string
 	EncryptKey string
 
+	// EncryptRotation configures the automated rotation of the gossip
+	// encryption key by the leader of the datacenter. The key of the WAN pool
+	// is only rotated by the leader of the primary datacenter.
+	//
+	// hcl: encrypt_rotation { enabled = (true|false) interval = "duration" grace_period = "duration" }
+	EncryptRotation consul.KeyringRotationConfig
+
+	// ExternalHealthChecks configures the health checks of the external nodes,
+	// which are run by the leader of the datacenter. The external nodes are
+	// the nodes registered with the "external-node" meta set to "true", which
+	// aren't running a Consul agent.
+	//
+	// hcl: external_health_checks { enabled = (true|false) node_probe_interval = "duration" }
+	ExternalHealthChecks consul.ExternalHealthChecksConfig
+
 	// GRPCPort is the port the gRPC server listens on. It is disabled by default.
 	//
 	// hcl: ports { grpc = int }
@@ -778,6 +1010,12 @@ type RuntimeConfig struct {
 	// flags: -https-port int
 	HTTPSPort int
 
+	// KVCompressionThreshold is the size in bytes above which the servers
+	// compress KV values before storing them. Zero disables compression.
+	//
+	// hcl: kv_compression_threshold = int
+	KVCompressionThreshold int
+
 	// KVMaxValueSize controls the max allowed value size. If not set defaults
 	// to raft's suggested max value size.
 	//
@@ -796,6 +1034,14 @@ type RuntimeConfig struct {
 	// hcl: leave_on_terminate = (true|false)
 	LeaveOnTerm bool
 
+	// LeaveServiceDrainTime is how long the locally registered services are
+	// marked as draining when the agent gracefully leaves, before they are
+	// deregistered. The mesh stops opening new connections to draining
+	// instances while the existing ones finish. Disabled when zero.
+	//
+	// hcl: leave_service_drain_time = "duration"
+	LeaveServiceDrainTime time.Duration
+
 	// Logging configuration used to initialize agent logging.
 	Logging logging.Config
 
@@ -807,6 +1053,13 @@ type RuntimeConfig struct {
 	// flags: -max-query-time string
 	MaxQueryTime time.Duration
 
+	// NamedPipeSecurityDescriptor is the security descriptor, in the SDDL
+	// format, of the named pipes Consul binds to on Windows. It controls which
+	// users can connect to them.
+	//
+	// hcl: named_pipes { security_descriptor = string }
+	NamedPipeSecurityDescriptor string
+
 	// Node ID is a unique ID for this node across space and time. Defaults
 	// to a randomly-generated ID that persists in the data-dir.
 	//
@@ -826,10 +1079,10 @@ type RuntimeConfig struct {
 	NodeMeta map[string]string
 
 	// ReadReplica is whether this server will act as a non-voting member
-	// of the cluster to help provide read scalability. (Enterprise-only)
+	// of the cluster to help provide read scalability.
 	//
-	// hcl: non_voting_server = (true|false)
-	// flag: -non-voting-server
+	// hcl: read_replica = (true|false)
+	// flag: -read-replica
 	ReadReplica bool
 
 	// PeeringEnabled enables cluster peering. This setting only applies for servers.
@@ -844,6 +1097,16 @@ type RuntimeConfig struct {
 	// registrations for objects with `PeerName`
 	PeeringTestAllowPeerRegistrations bool
 
+	// PeeringIncomingMessageRate and PeeringIncomingMessageBurst control how
+	// quickly servers process messages received on the replication stream of
+	// each peer. Once the limit is reached the server stops reading from the
+	// stream until more messages are allowed, which applies backpressure to
+	// the peer.
+	//
+	// hcl: peering { incoming_message_rate = (float64|MaxFloat64) incoming_message_burst = int }
+	PeeringIncomingMessageRate  rate.Limit
+	PeeringIncomingMessageBurst int
+
 	// PidFile is the file to store our PID in.
 	//
 	// hcl: pid_file = string
@@ -872,6 +1135,12 @@ type RuntimeConfig struct {
 	// hcl: primary_gateways_interval = "duration"
 	PrimaryGatewaysInterval time.Duration
 
+	// Profiling configures the continuous profiler, which periodically
+	// captures the profiles of the agent to a bounded set of files.
+	//
+	// hcl: profiling { enabled = (true|false) dir = string interval = "duration" cpu_duration = "duration" profiles = []string retain = int push_url = string }
+	Profiling debug.ProfilerConfig
+
 	// RPCAdvertiseAddr is the TCP address Consul advertises for its RPC endpoint.
 	// By default this is the bind address on the default RPC Server port. If the
 	// advertise address is specified then it is used.
@@ -945,6 +1214,14 @@ type RuntimeConfig struct {
 	// in the client agent for endpoints which support streaming.
 	UseStreamingBackend bool
 
+	// UseStreamingBackendCatalogServices enables streaming for the catalog
+	// list of services, when UseStreamingBackend is enabled. It is disabled by
+	// default because the streamed events are filtered with node:read in
+	// addition to service:read, unlike the ListServices RPC.
+	//
+	// hcl: use_streaming_backend_catalog_services = (true|false)
+	UseStreamingBackendCatalogServices bool
+
 	// RaftProtocol sets the Raft protocol version to use on this server.
 	// Defaults to 3.
 	//
@@ -980,6 +1257,12 @@ type RuntimeConfig struct {
 
 	RaftLogStoreConfig consul.RaftLogStoreConfig
 
+	// RaftCompression configures the zstd compression of the raft log entries
+	// from a size threshold, and of the raft snapshots.
+	//
+	// hcl: raft_compression { log_threshold = int snapshots = (true|false) level = string }
+	RaftCompression consul.RaftCompressionConfig
+
 	// ReconnectTimeoutLAN specifies the amount of time to wait to reconnect with
 	// another agent before deciding it's permanently gone. This can be used to
 	// control the time it takes to reap failed nodes from the cluster.
@@ -1001,6 +1284,21 @@ type RuntimeConfig struct {
 	// would otherwise.
 	AdvertiseReconnectTimeout time.Duration
 
+	// AESyncBatchSize is the maximum number of service and check records
+	// that are synced with the servers in a single anti-entropy sync run.
+	// Larger numbers of changes are spread over multiple runs. A value of 0
+	// disables batching.
+	//
+	// hcl: ae_sync_batch_size = int
+	AESyncBatchSize int
+
+	// AESyncRateLimit is the maximum number of partial anti-entropy sync
+	// runs per second. Changes made while a run is delayed are synced
+	// together in the next run. A value of 0 disables the limit.
+	//
+	// hcl: ae_sync_rate_limit = float64
+	AESyncRateLimit float64
+
 	// RejoinAfterLeave controls our interaction with the cluster after leave.
 	// When set to false (default), a leave causes Consul to not rejoin
 	// the cluster until an explicit join is received. If this is set to
@@ -1041,6 +1339,44 @@ type RuntimeConfig struct {
 	// hcl: limits { request_limits { write_rate = (float64|MaxFloat64) } }
 	RequestLimitsWriteRate rate.Limit
 
+	// RequestLimitsIPReadRate and RequestLimitsIPWriteRate limit how
+	// frequently each source IP address is allowed to make RPC and gRPC
+	// queries and writes, so that a single client can't exhaust the global
+	// limits. The requests forwarded by other servers are not limited.
+	//
+	// hcl: limits { request_limits { per_ip { read_rate = (float64|MaxFloat64) write_rate = (float64|MaxFloat64) } } }
+	RequestLimitsIPReadRate  rate.Limit
+	RequestLimitsIPWriteRate rate.Limit
+
+	// RequestLimitsIPOverrides replace the per-IP limits of the addresses in
+	// their CIDR block.
+	//
+	// hcl: limits { request_limits { per_ip { override { cidr = string read_rate = float64 write_rate = float64 } } } }
+	RequestLimitsIPOverrides []consul.RequestLimitsIPOverride
+
+	// RequestLimitsTokenReadRate and RequestLimitsTokenWriteRate limit how
+	// frequently the RPC queries and writes made with each ACL token are
+	// allowed to happen. The requests without a token share the limits of
+	// the anonymous token.
+	//
+	// hcl: limits { request_limits { per_token { read_rate = (float64|MaxFloat64) write_rate = (float64|MaxFloat64) } } }
+	RequestLimitsTokenReadRate  rate.Limit
+	RequestLimitsTokenWriteRate rate.Limit
+
+	// RequestLimitsTokenOverrides replace the per-token limits of the tokens
+	// with the given accessor IDs.
+	//
+	// hcl: limits { request_limits { per_token { override { accessor_id = string read_rate = float64 write_rate = float64 } } } }
+	RequestLimitsTokenOverrides []consul.RequestLimitsTokenOverride
+
+	// RetryJoinFilters are the arguments added to the go-discover expressions
+	// of RetryJoinLAN, RetryJoinWAN and PrimaryGateways, by provider. They
+	// are typically the tag or label filters shared by all the expressions of
+	// a provider. The arguments of the expressions take precedence.
+	//
+	// hcl: retry_join_filters { (provider) { (argument) = string } }
+	RetryJoinFilters map[string]map[string]string
+
 	// RetryJoinIntervalLAN specifies the amount of time to wait in between join
 	// attempts on agent start. The minimum allowed value is 1 second and
 	// the default is 30s.
@@ -1179,6 +1515,15 @@ type RuntimeConfig struct {
 	// hcl: ports { serf_wan = int }
 	SerfPortWAN int
 
+	// GossipLANProfile is the name of the gossip profile the LAN gossip
+	// settings that aren't set explicitly are taken from. The larger profiles
+	// are meant for clusters of thousands of agents.
+	//
+	// The default is: default
+	//
+	// hcl: gossip_lan { profile = ("default"|"large"|"xlarge") }
+	GossipLANProfile string
+
 	// GossipLANGossipInterval is the interval between sending messages that need
 	// to be gossiped that haven't been able to piggyback on probing messages.
 	// If this is set to zero, non-piggyback gossip is disabled. By lowering
@@ -1256,6 +1601,16 @@ type RuntimeConfig struct {
 	// hcl: gossip_lan { retransmit_mult = int }
 	GossipLANRetransmitMult int
 
+	// GossipLANPushPullInterval is the interval between complete state syncs
+	// with a random node. The interval is scaled up with the logarithm of the
+	// cluster size beyond 32 nodes. This configuration only applies to LAN
+	// gossip communications
+	//
+	// The default is: 30s
+	//
+	// hcl: gossip_lan { push_pull_interval = duration }
+	GossipLANPushPullInterval time.Duration
+
 	// GossipWANGossipInterval  is the interval between sending messages that need
 	// to be gossiped that haven't been able to piggyback on probing messages.
 	// If this is set to zero, non-piggyback gossip is disabled. By lowering
@@ -1370,6 +1725,25 @@ type RuntimeConfig struct {
 	// ]
 	Services []*structs.ServiceDefinition
 
+	// ServicesAutoReloadDir is a directory of service definition files that
+	// the agent watches. Services are registered, updated and deregistered as
+	// the files are added, changed and removed, without a reload of the agent.
+	//
+	// hcl: services_auto_reload { dir = string }
+	ServicesAutoReloadDir string
+
+	// ServicesAutoReloadDebounce is how long the agent waits for the changes
+	// to ServicesAutoReloadDir to settle before applying them.
+	//
+	// hcl: services_auto_reload { debounce = "duration" }
+	ServicesAutoReloadDebounce time.Duration
+
+	// ServiceTombstoneTTL is how long the servers keep the tombstones of
+	// deregistered service instances before reaping them.
+	//
+	// hcl: service_tombstone_ttl = "duration"
+	ServiceTombstoneTTL time.Duration
+
 	// Minimum Session TTL.
 	//
 	// hcl: session_ttl_min = "duration"
@@ -1472,6 +1846,12 @@ type RuntimeConfig struct {
 	// hcl: xds { update_max_per_second = (float64|MaxFloat64) }
 	XDSUpdateRateLimit rate.Limit
 
+	// XDSStrictEnvoyVersion rejects the xDS streams of the Envoy proxies of
+	// an unsupported version, instead of logging a warning.
+	//
+	// hcl: xds { strict_envoy_version = (true|false) }
+	XDSStrictEnvoyVersion bool
+
 	// AutoReloadConfigCoalesceInterval Coalesce Interval for auto reload config
 	AutoReloadConfigCoalesceInterval time.Duration
 
@@ -1539,6 +1919,8 @@ func (c *RuntimeConfig) apiAddresses(maxPerType int) (unixAddrs, httpAddrs, http
 					unixAddrs = append(unixAddrs, addr.String())
 					unix_count += 1
 				}
+			case *NamedPipeAddr:
+				// the api client can't connect to named pipes
 			default:
 				if maxPerType < 1 || http_count < maxPerType {
 					httpAddrs = append(httpAddrs, addr.String())
@@ -1725,8 +2107,9 @@ func (c *RuntimeConfig) IsCloudEnabled() bool {
 // isSecret determines whether a field name represents a field which
 // may contain a secret.
 func isSecret(name string) bool {
-	// special cases for AuthMethod locality and intro token file
-	if name == "TokenLocality" || name == "IntroTokenFile" {
+	// special cases for AuthMethod locality, intro token file and the autopilot
+	// zone meta key, which is the name of a node meta key
+	if name == "TokenLocality" || name == "IntroTokenFile" || name == "AutopilotZoneMetaKey" {
 		return false
 	}
 	name = strings.ToLower(name)
@@ -1763,6 +2146,8 @@ func sanitize(name string, v reflect.Value) reflect.Value {
 			return reflect.ValueOf("udp://" + x.String())
 		case *net.UnixAddr:
 			return reflect.ValueOf("unix://" + x.String())
+		case *NamedPipeAddr:
+			return reflect.ValueOf("npipe://" + x.String())
 		case *net.IPAddr:
 			return reflect.ValueOf(x.IP.String())
 		case *net.IPNet:
@@ -1841,6 +2226,12 @@ func sanitize(name string, v reflect.Value) reflect.Value {
 		m := map[string]interface{}{}
 		for _, k := range v.MapKeys() {
 			key := k.String()
+			// The headers sent to the OTLP collector and to the change sinks
+			// may hold their credentials.
+			if name == "OTLPHeaders" || name == "Headers" {
+				m[key] = "hidden"
+				continue
+			}
 			m[key] = sanitize(key, v.MapIndex(k)).Interface()
 		}
 		return reflect.ValueOf(m)
@@ -25,10 +25,14 @@ import (
 	hcpconfig "github.com/hashicorp/consul/agent/hcp/config"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/consul"
+	"github.com/hashicorp/consul/agent/consul/changesink"
 	consulrate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/debug"
+	"github.com/hashicorp/consul/agent/dns"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/token"
 	"github.com/hashicorp/consul/lib"
@@ -453,6 +457,99 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		},
 		expectedErr: "alt_domain cannot start with {service,connect,node,query,addr,dc1}",
 	})
+	run(t, testCase{
+		desc: "dns_config.peer_domain",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{ "dns_config": { "peer_domain": "mesh" } }`},
+		hcl:  []string{`dns_config { peer_domain = "mesh" }`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DNSPeerDomain = "mesh"
+			rt.DataDir = dataDir
+		},
+	})
+	run(t, testCase{
+		desc:        "dns_config.peer_domain can't be prefixed by service",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "peer_domain": "service.mesh" } }`},
+		hcl:         []string{`dns_config { peer_domain = "service.mesh" }`},
+		expectedErr: "dns_config.peer_domain cannot start with {service,connect,node,query,addr,dc1}",
+	})
+	run(t, testCase{
+		desc:        "dns_config.peer_domain can't be the same as domain",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "peer_domain": "consul" } }`},
+		hcl:         []string{`dns_config { peer_domain = "consul" }`},
+		expectedErr: "dns_config.peer_domain must be different from domain and alt_domain",
+	})
+	run(t, testCase{
+		desc: "dns_config.views",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{ "dns_config": { "only_passing": true, "views": [
+			{ "domain": "internal", "tags": ["v1"] },
+			{ "domain": "svc.example.internal.", "only_passing": false, "exclude_tags": ["canary"] }
+		] } }`},
+		hcl: []string{`dns_config {
+			only_passing = true
+			views = [
+				{ domain = "internal" tags = ["v1"] },
+				{ domain = "svc.example.internal." only_passing = false exclude_tags = ["canary"] }
+			]
+		}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DNSOnlyPassing = true
+			rt.DNSViews = []RuntimeDNSView{
+				{Domain: "internal", OnlyPassing: true, Tags: []string{"v1"}},
+				{Domain: "svc.example.internal.", ExcludeTags: []string{"canary"}},
+			}
+			rt.DataDir = dataDir
+		},
+	})
+	run(t, testCase{
+		desc: "dns_config.virtual_ttl defaults to node_ttl",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{ "dns_config": { "node_ttl": "5s" } }`},
+		hcl:  []string{`dns_config { node_ttl = "5s" }`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DNSNodeTTL = 5 * time.Second
+			rt.DNSVirtualTTL = 5 * time.Second
+			rt.DataDir = dataDir
+		},
+	})
+	run(t, testCase{
+		desc:        "dns_config.virtual_answers invalid answer",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "virtual_answers": { "db": "nope" } } }`},
+		hcl:         []string{`dns_config { virtual_answers = { db = "nope" } }`},
+		expectedErr: `dns_config.virtual_answers["db"]: invalid answer: "nope", must be "virtual-ip" or "instances"`,
+	})
+	run(t, testCase{
+		desc:        "dns_config.service_metrics_limit negative",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "service_metrics_limit": -1 } }`},
+		hcl:         []string{`dns_config { service_metrics_limit = -1 }`},
+		expectedErr: "dns_config.service_metrics_limit cannot be negative",
+	})
+	run(t, testCase{
+		desc:        "dns_config.views requires a domain",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "views": [{ "tags": ["v1"] }] } }`},
+		hcl:         []string{`dns_config { views = [{ tags = ["v1"] }] }`},
+		expectedErr: "dns_config.views[0].domain is required",
+	})
+	run(t, testCase{
+		desc:        "dns_config.views can't use the same domain twice",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "views": [{ "domain": "internal" }, { "domain": "Internal." }] } }`},
+		hcl:         []string{`dns_config { views = [{ domain = "internal" }, { domain = "Internal." }] }`},
+		expectedErr: `dns_config.views[1].domain "Internal." is already used by another domain`,
+	})
+	run(t, testCase{
+		desc:        "dns_config.views can't use the primary domain",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "dns_config": { "views": [{ "domain": "consul." }] } }`},
+		hcl:         []string{`dns_config { views = [{ domain = "consul." }] }`},
+		expectedErr: `dns_config.views[0].domain "consul." is already used by another domain`,
+	})
 	run(t, testCase{
 		desc: "-enable-script-checks",
 		args: []string{
@@ -650,7 +747,6 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.ReadReplica = true
 			rt.DataDir = dataDir
 		},
-		expectedWarnings: enterpriseReadReplicaWarnings,
 	})
 	run(t, testCase{
 		desc: "-pid-file",
@@ -1229,6 +1325,33 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.DataDir = dataDir
 		},
 	})
+	run(t, testCase{
+		desc: "named pipe addresses",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+					"addresses": {
+						"http": "127.0.0.1 npipe:////./pipe/consul-http",
+						"grpc": "npipe:////./pipe/consul-grpc"
+					},
+					"ports": { "grpc": 8502 },
+					"named_pipes": { "security_descriptor": "D:P(A;;GA;;;BA)" }
+				}`},
+		hcl: []string{`
+					addresses = {
+						http = "127.0.0.1 npipe:////./pipe/consul-http"
+						grpc = "npipe:////./pipe/consul-grpc"
+					}
+					ports { grpc = 8502 }
+					named_pipes { security_descriptor = "D:P(A;;GA;;;BA)" }
+				`},
+		expected: func(rt *RuntimeConfig) {
+			rt.HTTPAddrs = []net.Addr{tcpAddr("127.0.0.1:8500"), &NamedPipeAddr{Path: "//./pipe/consul-http"}}
+			rt.GRPCPort = 8502
+			rt.GRPCAddrs = []net.Addr{&NamedPipeAddr{Path: "//./pipe/consul-grpc"}}
+			rt.NamedPipeSecurityDescriptor = "D:P(A;;GA;;;BA)"
+			rt.DataDir = dataDir
+		},
+	})
 	run(t, testCase{
 		desc: "advertise address lan template",
 		args: []string{`-data-dir=` + dataDir},
@@ -1840,6 +1963,100 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`autopilot = { max_trailing_logs = -1 }`},
 		expectedErr: "autopilot.max_trailing_logs cannot be -1. Must be greater than or equal to zero",
 	})
+	run(t, testCase{
+		desc: "autopilot.promotion_strategy invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "autopilot": { "promotion_strategy": "random" } }`},
+		hcl:         []string{`autopilot = { promotion_strategy = "random" }`},
+		expectedErr: `autopilot.promotion_strategy must be one of "stable" or "zones", got "random"`,
+	})
+	run(t, testCase{
+		desc: "autopilot.promotion_strategy zones requires zone_meta_key",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "autopilot": { "promotion_strategy": "zones", "voters_per_zone": 1 } }`},
+		hcl: []string{`
+			autopilot = {
+				promotion_strategy = "zones"
+				voters_per_zone = 1
+			}`},
+		expectedErr: `autopilot.zone_meta_key is required when autopilot.promotion_strategy is "zones"`,
+	})
+	run(t, testCase{
+		desc: "autopilot.promotion_strategy zones requires voters_per_zone",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "autopilot": { "promotion_strategy": "zones", "zone_meta_key": "zone" } }`},
+		hcl: []string{`
+			autopilot = {
+				promotion_strategy = "zones"
+				zone_meta_key = "zone"
+			}`},
+		expectedErr: `autopilot.voters_per_zone cannot be 0. Must be greater than zero when autopilot.promotion_strategy is "zones"`,
+	})
+	run(t, testCase{
+		desc: "gossip_lan.profile sets the LAN gossip defaults",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "gossip_lan": { "profile": "xlarge", "probe_timeout": "2s" } }`},
+		hcl: []string{`
+			gossip_lan = {
+				profile = "xlarge"
+				probe_timeout = "2s"
+			}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.Datacenter = "a"
+			rt.PrimaryDatacenter = "a"
+			rt.DataDir = dataDir
+			rt.GossipLANProfile = "xlarge"
+			rt.GossipLANGossipNodes = 5
+			rt.GossipLANGossipInterval = 300 * time.Millisecond
+			rt.GossipLANProbeInterval = 3 * time.Second
+			rt.GossipLANProbeTimeout = 2 * time.Second
+			rt.GossipLANSuspicionMult = 8
+			rt.GossipLANRetransmitMult = 6
+			rt.GossipLANPushPullInterval = 120 * time.Second
+		},
+	})
+	run(t, testCase{
+		desc: "gossip_lan.profile invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "gossip_lan": { "profile": "huge" } }`},
+		hcl:         []string{`gossip_lan = { profile = "huge" }`},
+		expectedErr: `gossip_lan.profile must be one of "default", "large" or "xlarge", got "huge"`,
+	})
+	run(t, testCase{
+		desc: "ae_sync_batch_size invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "ae_sync_batch_size": -1 }`},
+		hcl:         []string{`ae_sync_batch_size = -1`},
+		expectedErr: "ae_sync_batch_size cannot be -1. Must be greater than or equal to zero",
+	})
+	run(t, testCase{
+		desc: "ae_sync_rate_limit invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "ae_sync_rate_limit": -0.5 }`},
+		hcl:         []string{`ae_sync_rate_limit = -0.5`},
+		expectedErr: "ae_sync_rate_limit cannot be -0.5. Must be greater than or equal to zero",
+	})
 	run(t, testCase{
 		desc:        "bind_addr cannot be empty",
 		args:        []string{`-data-dir=` + dataDir},
@@ -2021,6 +2238,26 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`addresses = { dns = "unix:///foo" }`},
 		expectedErr: "DNS address cannot be a unix socket",
 	})
+	run(t, testCase{
+		desc: "dns does not allow named pipe",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "addresses": {"dns": "npipe:////./pipe/consul" } }`},
+		hcl:         []string{`addresses = { dns = "npipe:////./pipe/consul" }`},
+		expectedErr: "DNS address cannot be a named pipe",
+	})
+	run(t, testCase{
+		desc: "dns-over-tls addr cannot be unix socket",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "addresses": {"dns_tls": "unix:///foo" }, "ports": { "dns_tls": 853 } }`},
+		hcl:         []string{`addresses = { dns_tls = "unix:///foo" } ports { dns_tls = 853 }`},
+		expectedErr: "DNS-over-TLS address cannot be a unix socket",
+	})
 	run(t, testCase{
 		desc: "ui enabled and dir specified",
 		args: []string{
@@ -2508,7 +2745,7 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl: []string{
 			`check = { name = "a", os_service = "foo" }`,
 		},
-		expectedErr: `Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks`,
+		expectedErr: `Interval must be > 0 for Script, Docker, HTTP, H2PING, TCP, UDP, OSService or Process checks`,
 	})
 	run(t, testCase{
 		desc: "os_service check",
@@ -3640,6 +3877,38 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			`},
 		expectedErr: "'connect.enable_mesh_gateway_wan_federation = true' requires that 'node_name' not contain '/' characters",
 	})
+	run(t, testCase{
+		desc: "retry_join_filters",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "retry_join": [ "provider=k8s namespace=consul" ],
+			  "retry_join_filters": { "k8s": { "label_selector": "app=consul" } }
+			}`},
+		hcl: []string{`
+			  retry_join = [ "provider=k8s namespace=consul" ]
+			  retry_join_filters { k8s { label_selector = "app=consul" } }
+			`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.RetryJoinLAN = []string{"provider=k8s namespace=consul"}
+			rt.RetryJoinFilters = map[string]map[string]string{"k8s": {"label_selector": "app=consul"}}
+		},
+	})
+	run(t, testCase{
+		desc: "retry_join_filters cannot set the provider",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "retry_join_filters": { "aws": { "provider": "gce" } }
+			}`},
+		hcl: []string{`
+			  retry_join_filters { aws { provider = "gce" } }
+			`},
+		expectedErr: `retry_join_filters: the filters of provider "aws" cannot set the provider`,
+	})
 	run(t, testCase{
 		desc: "primary_gateways requires server mode",
 		args: []string{
@@ -4644,6 +4913,10 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.RequestLimitsMode = consulrate.ModeDisabled
 			rt.RequestLimitsReadRate = rate.Inf
 			rt.RequestLimitsWriteRate = rate.Inf
+			rt.RequestLimitsIPReadRate = rate.Inf
+			rt.RequestLimitsIPWriteRate = rate.Inf
+			rt.RequestLimitsTokenReadRate = rate.Inf
+			rt.RequestLimitsTokenWriteRate = rate.Inf
 			rt.SegmentLimit = 64
 			rt.XDSUpdateRateLimit = 250
 			rt.RPCRateLimit = rate.Inf
@@ -5767,6 +6040,8 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.DataDir = dataDir
 			rt.RaftLogStoreConfig.Backend = consul.LogStoreBackendBoltDB
 			rt.RaftLogStoreConfig.WAL.SegmentSize = 64 * 1024 * 1024
+			rt.RaftLogStoreConfig.WAL.Canary.VerifyInterval = time.Minute
+			rt.RaftLogStoreConfig.WAL.Canary.Failback = true
 		},
 	})
 	run(t, testCase{
@@ -5790,6 +6065,8 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.DataDir = dataDir
 			rt.RaftLogStoreConfig.Backend = consul.LogStoreBackendBoltDB
 			rt.RaftLogStoreConfig.WAL.SegmentSize = 64 * 1024 * 1024
+			rt.RaftLogStoreConfig.WAL.Canary.VerifyInterval = time.Minute
+			rt.RaftLogStoreConfig.WAL.Canary.Failback = true
 		},
 	})
 	run(t, testCase{
@@ -5857,6 +6134,414 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			}`},
 		expectedErr: "raft_logstore.backend must be one of 'boltdb' or 'wal'",
 	})
+	run(t, testCase{
+		desc: "wal canary requires wal backend",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"raft_logstore": {
+					"wal": {
+						"canary": {
+							"enabled": true
+						}
+					}
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			raft_logstore {
+				wal {
+					canary {
+						enabled = true
+					}
+				}
+			}`},
+		expectedErr: "raft_logstore.wal.canary requires raft_logstore.backend to be 'wal'",
+	})
+	run(t, testCase{
+		desc: "wal canary verify interval",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"raft_logstore": {
+					"backend": "wal",
+					"wal": {
+						"canary": {
+							"enabled": true,
+							"verify_interval": "0s"
+						}
+					}
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			raft_logstore {
+				backend = "wal"
+				wal {
+					canary {
+						enabled = true
+						verify_interval = "0s"
+					}
+				}
+			}`},
+		expectedErr: "raft_logstore.wal.canary.verify_interval must be greater than 0",
+	})
+	run(t, testCase{
+		desc: "raft_compression negative log_threshold",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"raft_compression": {
+					"log_threshold": -1
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			raft_compression {
+				log_threshold = -1
+			}`},
+		expectedErr: "raft_compression.log_threshold cannot be negative",
+	})
+	run(t, testCase{
+		desc: "raft_compression invalid level",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"raft_compression": {
+					"level": "max"
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			raft_compression {
+				level = "max"
+			}`},
+		expectedErr: `raft_compression.level must be one of fastest, default, better or best. received: "max"`,
+	})
+	run(t, testCase{
+		desc: "encrypt_rotation interval too short",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "encrypt_rotation": { "enabled": true, "interval": "1h", "grace_period": "30m" } }`},
+		hcl:         []string{`encrypt_rotation { enabled = true interval = "1h" grace_period = "30m" }`},
+		expectedErr: "encrypt_rotation.interval must be greater than twice encrypt_rotation.grace_period",
+	})
+	run(t, testCase{
+		desc: "encrypt_rotation on a client",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "encrypt_rotation": { "enabled": true } }`},
+		hcl:  []string{`encrypt_rotation { enabled = true }`},
+		expectedWarnings: []string{
+			"encrypt_rotation is only used by servers and will have no effect",
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.EncryptRotation.Enabled = true
+		},
+	})
+	run(t, testCase{
+		desc: "external_health_checks node_probe_interval too short",
+		args: []string{
+			`-server`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "external_health_checks": { "enabled": true, "node_probe_interval": "100ms" } }`},
+		hcl:         []string{`external_health_checks { enabled = true node_probe_interval = "100ms" }`},
+		expectedErr: "external_health_checks.node_probe_interval must be at least 1s",
+	})
+	run(t, testCase{
+		desc: "external_health_checks on a client",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "external_health_checks": { "enabled": true } }`},
+		hcl:  []string{`external_health_checks { enabled = true }`},
+		expectedWarnings: []string{
+			"external_health_checks is only used by servers and will have no effect",
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.ExternalHealthChecks.Enabled = true
+		},
+	})
+	run(t, testCase{
+		desc: "acl.client_cert_auth_method on a client",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "acl": { "enabled": true, "client_cert_auth_method": "tools" } }`},
+		hcl:         []string{`acl { enabled = true client_cert_auth_method = "tools" }`},
+		expectedErr: "'acl.client_cert_auth_method' requires 'server = true'",
+	})
+	run(t, testCase{
+		desc: "acl.client_cert_auth_method in a secondary datacenter",
+		args: []string{
+			`-server`,
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "datacenter": "dc2", "primary_datacenter": "dc1", "acl": { "client_cert_auth_method": "tools" } }`},
+		hcl:  []string{`datacenter = "dc2" primary_datacenter = "dc1" acl { client_cert_auth_method = "tools" }`},
+		expectedWarnings: []string{
+			"acl.client_cert_auth_method is only supported in the primary datacenter, the logins with client certificates will fail",
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Datacenter = "dc2"
+			rt.PrimaryDatacenter = "dc1"
+			rt.ACLClientCertAuthMethod = "tools"
+			rt.ServerMode = true
+			rt.TLS.ServerMode = true
+			rt.LeaveOnTerm = false
+			rt.SkipLeaveOnInt = true
+			rt.DataDir = dataDir
+			rt.RPCConfig.EnableStreaming = true
+			rt.GRPCTLSPort = 8503
+			rt.GRPCTLSAddrs = []net.Addr{defaultGrpcTlsAddr}
+		},
+	})
+	run(t, testCase{
+		desc: "auto_encrypt.vault",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			"auto_encrypt": {
+				"tls": true,
+				"vault": {
+					"address": "http://127.0.0.1:8100",
+					"token_file": "/run/vault/token",
+					"pki_path": "pki-agents",
+					"role": "consul-client",
+					"ttl": "72h"
+				}
+			}
+		}`},
+		hcl: []string{`
+			auto_encrypt {
+				tls = true
+				vault {
+					address = "http://127.0.0.1:8100"
+					token_file = "/run/vault/token"
+					pki_path = "pki-agents"
+					role = "consul-client"
+					ttl = "72h"
+				}
+			}
+		`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.AutoEncryptTLS = true
+			rt.TLS.AutoTLS = true
+			rt.AutoEncryptVault = AutoEncryptVaultConfig{
+				Address:   "http://127.0.0.1:8100",
+				TokenFile: "/run/vault/token",
+				PKIPath:   "pki-agents",
+				Role:      "consul-client",
+				TTL:       72 * time.Hour,
+			}
+		},
+	})
+	run(t, testCase{
+		desc: "auto_encrypt.vault without auto_encrypt.tls",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "auto_encrypt": { "vault": { "address": "http://127.0.0.1:8100", "role": "consul-client" } } }`},
+		hcl:         []string{`auto_encrypt { vault { address = "http://127.0.0.1:8100" role = "consul-client" } }`},
+		expectedErr: "auto_encrypt.vault requires auto_encrypt.tls to be enabled",
+	})
+	run(t, testCase{
+		desc: "auto_encrypt.vault without role",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "auto_encrypt": { "tls": true, "vault": { "address": "http://127.0.0.1:8100" } } }`},
+		hcl:         []string{`auto_encrypt { tls = true vault { address = "http://127.0.0.1:8100" } }`},
+		expectedErr: "auto_encrypt.vault.role is required",
+	})
+	run(t, testCase{
+		desc: "change_sink invalid type",
+		args: []string{
+			`-server`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "change_sink": { "hook": { "type": "sqs", "url": "https://example.com/hook" } } }`},
+		hcl:         []string{`change_sink "hook" { type = "sqs" url = "https://example.com/hook" }`},
+		expectedErr: `change_sink[hook]: type must be "webhook" or "kafka"`,
+	})
+	run(t, testCase{
+		desc: "change_sink on a client",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "change_sink": { "hook": { "type": "webhook", "url": "https://example.com/hook" } } }`},
+		hcl:  []string{`change_sink "hook" { type = "webhook" url = "https://example.com/hook" }`},
+		expectedWarnings: []string{
+			"change_sink is only used by servers and will have no effect",
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.ChangeSinks = []changesink.Config{
+				{Name: "hook", Type: changesink.TypeWebhook, URL: "https://example.com/hook"},
+			}
+		},
+	})
+	run(t, testCase{
+		desc: "profiling enabled",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "profiling": { "enabled": true } }`},
+		hcl:  []string{`profiling { enabled = true }`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.Profiling.Enabled = true
+		},
+	})
+	run(t, testCase{
+		desc: "profiling cpu_duration longer than interval",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "profiling": { "enabled": true, "interval": "30s", "cpu_duration": "1m" } }`},
+		hcl:         []string{`profiling { enabled = true interval = "30s" cpu_duration = "1m" }`},
+		expectedErr: "profiling.cpu_duration must be greater than 0 and less than profiling.interval",
+	})
+	run(t, testCase{
+		desc: "profiling unknown profile",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "profiling": { "enabled": true, "profiles": ["trace"] } }`},
+		hcl:         []string{`profiling { enabled = true profiles = ["trace"] }`},
+		expectedErr: `profiling.profiles must be one of cpu, heap, allocs, goroutine, block, mutex, threadcreate. received: "trace"`,
+	})
+	run(t, testCase{
+		desc: "services_auto_reload debounce zero",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "services_auto_reload": { "dir": "/etc/consul.d/services", "debounce": "0s" } }`},
+		hcl:         []string{`services_auto_reload { dir = "/etc/consul.d/services" debounce = "0s" }`},
+		expectedErr: "services_auto_reload.debounce must be greater than 0",
+	})
+	run(t, testCase{
+		desc: "leave_service_drain_time negative",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "leave_service_drain_time": "-5s" }`},
+		hcl:         []string{`leave_service_drain_time = "-5s"`},
+		expectedErr: "leave_service_drain_time cannot be negative",
+	})
+	run(t, testCase{
+		desc: "telemetry.otlp_protocol invalid",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "telemetry": { "otlp_endpoint": "localhost:4317", "otlp_protocol": "udp" } }`},
+		hcl:         []string{`telemetry { otlp_endpoint = "localhost:4317" otlp_protocol = "udp" }`},
+		expectedErr: `telemetry.otlp_protocol must be "grpc" or "http/protobuf". received: "udp"`,
+	})
+	run(t, testCase{
+		desc: "telemetry.otlp_temporality invalid",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "telemetry": { "otlp_temporality": "sometimes" } }`},
+		hcl:         []string{`telemetry { otlp_temporality = "sometimes" }`},
+		expectedErr: `telemetry.otlp_temporality must be "cumulative" or "delta". received: "sometimes"`,
+	})
+	run(t, testCase{
+		desc: "telemetry.otlp_endpoint invalid URL",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "telemetry": { "otlp_endpoint": "udp://localhost:4318", "otlp_protocol": "http/protobuf" } }`},
+		hcl:         []string{`telemetry { otlp_endpoint = "udp://localhost:4318" otlp_protocol = "http/protobuf" }`},
+		expectedErr: "telemetry.otlp_endpoint must be a valid http or https URL",
+	})
+	run(t, testCase{
+		desc: "telemetry.otlp_logs without endpoint",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "telemetry": { "otlp_logs": true } }`},
+		hcl:         []string{`telemetry { otlp_logs = true }`},
+		expectedErr: "telemetry.otlp_logs requires telemetry.otlp_endpoint to be set",
+	})
+	run(t, testCase{
+		desc: "telemetry.rpc_labels_max_values zero",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "telemetry": { "rpc_labels_max_values": 0 } }`},
+		hcl:         []string{`telemetry { rpc_labels_max_values = 0 }`},
+		expectedErr: "telemetry.rpc_labels_max_values must be greater than 0",
+	})
+	run(t, testCase{
+		desc: "request_limits per_ip override invalid cidr",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "limits": { "request_limits": { "per_ip": { "override": [{ "cidr": "10.0.0.1", "read_rate": 10 }] } } } }`},
+		hcl:         []string{`limits { request_limits { per_ip { override { cidr = "10.0.0.1" read_rate = 10 } } } }`},
+		expectedErr: `limits.request_limits.per_ip.override[0].cidr: invalid cidr: "10.0.0.1"`,
+	})
+	run(t, testCase{
+		desc: "request_limits per_token override without accessor_id",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "limits": { "request_limits": { "per_token": { "override": [{ "read_rate": 10 }] } } } }`},
+		hcl:         []string{`limits { request_limits { per_token { override { read_rate = 10 } } } }`},
+		expectedErr: "limits.request_limits.per_token.override[0].accessor_id: cannot be empty",
+	})
+	run(t, testCase{
+		desc: "audit enabled without sinks",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "audit": { "enabled": true } }`},
+		hcl:         []string{`audit { enabled = true }`},
+		expectedErr: "audit: at least one sink must be configured",
+	})
+	run(t, testCase{
+		desc: "audit invalid sink",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			"audit": {
+				"enabled": true,
+				"sink": {
+					"main": { "type": "socket" }
+				}
+			}
+		}`},
+		hcl: []string{`
+			audit {
+				enabled = true
+				sink "main" {
+					type = "socket"
+				}
+			}`},
+		expectedErr: `sink "main": invalid type "socket"`,
+	})
 	run(t, testCase{
 		desc: "raft_logstore merging",
 		args: []string{
@@ -6021,6 +6706,8 @@ func TestLoad_FullConfig(t *testing.T) {
 		GossipLANProbeTimeout:            102 * time.Millisecond,
 		GossipLANSuspicionMult:           1235,
 		GossipLANRetransmitMult:          1234,
+		GossipLANProfile:                 "large",
+		GossipLANPushPullInterval:        103 * time.Millisecond,
 		GossipWANGossipInterval:          6966 * time.Second,
 		GossipWANGossipNodes:             2,
 		GossipWANProbeInterval:           103 * time.Millisecond,
@@ -6055,23 +6742,42 @@ func TestLoad_FullConfig(t *testing.T) {
 		},
 		ACLEnableKeyListPolicy:           true,
 		ACLInitialManagementToken:        "3820e09a",
+		ACLClientCertAuthMethod:          "4f1e6c2a",
 		ACLTokenReplication:              true,
 		AdvertiseAddrLAN:                 ipAddr("17.99.29.16"),
 		AdvertiseAddrWAN:                 ipAddr("78.63.37.19"),
 		AdvertiseReconnectTimeout:        0 * time.Second,
+		AESyncBatchSize:                  4123,
+		AESyncRateLimit:                  12.5,
 		AutopilotCleanupDeadServers:      true,
 		AutopilotDisableUpgradeMigration: true,
 		AutopilotLastContactThreshold:    12705 * time.Second,
 		AutopilotMaxTrailingLogs:         17849,
 		AutopilotMinQuorum:               3,
+		AutopilotMinServerUptime:         4417 * time.Second,
+		AutopilotPromotionStrategy:       "zones",
 		AutopilotRedundancyZoneTag:       "3IsufDJf",
 		AutopilotServerStabilizationTime: 23057 * time.Second,
 		AutopilotUpgradeVersionTag:       "W9pDwFAL",
+		AutopilotVotersPerZone:           2,
+		AutopilotZoneMetaKey:             "zJ6kS2aE",
 		BindAddr:                         ipAddr("16.99.34.17"),
 		BootstrapExpect:                  53,
 		Cache: cache.Options{
 			EntryFetchMaxBurst: 42,
 			EntryFetchRate:     0.334,
+			PersistPath:        filepath.Join(dataDir, "cache", "entries.json"),
+		},
+		ChangeSinks: []changesink.Config{
+			{
+				Name:       "Vq3sKm8T",
+				Type:       changesink.TypeKafka,
+				URL:        "http://fX2dPq7w:8082",
+				Headers:    map[string]string{"Authorization": "Bearer j5WmQ9cR"},
+				KafkaTopic: "tB8nZk4L",
+				Topics:     []string{changesink.TopicCatalog},
+				Timeout:    23 * time.Second,
+			},
 		},
 		CheckOutputMaxSize: checks.DefaultBufSize,
 		Checks: []*structs.CheckDefinition{
@@ -6133,6 +6839,30 @@ func TestLoad_FullConfig(t *testing.T) {
 				Timeout:                        18506 * time.Second,
 				DeregisterCriticalServiceAfter: 2366 * time.Second,
 			},
+			{
+				ID:                  "Lw9cTs1N",
+				Name:                "Cn2hVt5J",
+				ServiceID:           "L8G0QNmR",
+				CompositeMode:       "and",
+				CompositeChecks:     []types.CheckID{"x2JgMRp8", "Qv4hnW6e"},
+				CompositeMinPassing: 1,
+				OutputMaxSize:       checks.DefaultBufSize,
+			},
+			{
+				ID:            "Hp6sDk1V",
+				Name:          "Ye9qBr3T",
+				ProcessPID:    5122,
+				ProcessCgroup: "dE8pV2kM",
+				Interval:      7391 * time.Second,
+				OutputMaxSize: checks.DefaultBufSize,
+			},
+			{
+				ID:             "Fj2nXc7M",
+				Name:           "Ub5wKe8P",
+				ProcessPIDFile: "Rt7cXq0Y",
+				Interval:       8123 * time.Second,
+				OutputMaxSize:  checks.DefaultBufSize,
+			},
 			{
 				ID:         "fZaCAXww",
 				Name:       "OOM2eo0f",
@@ -6154,6 +6884,9 @@ func TestLoad_FullConfig(t *testing.T) {
 				H2PING:                         "rQ8eyCSF",
 				H2PingUseTLS:                   false,
 				OSService:                      "aZaCAXww",
+				GRPC:                           "Vu6pXq3S",
+				GRPCService:                    "Hn3xWq7T",
+				GRPCAuthority:                  "uK5sMb9C",
 				Interval:                       18714 * time.Second,
 				DockerContainerID:              "qF66POS9",
 				Shell:                          "sOnDy228",
@@ -6177,6 +6910,26 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
+		Audit: audit.Config{
+			Enabled: true,
+			Sinks: []audit.SinkConfig{{
+				Name:              "Kd2BSq9w",
+				Type:              "file",
+				Format:            "json",
+				Path:              "/tmp/lUhEk7Gq/audit.json",
+				DeliveryGuarantee: "best-effort",
+				Mode:              0600,
+				RotateBytes:       5912,
+				RotateDuration:    13 * time.Hour,
+				RotateMaxFiles:    6,
+			}},
+			Filters: []audit.FilterRule{{
+				Action:     "exclude",
+				Operations: []string{"read"},
+				Resources:  []string{"/v1/agent/*"},
+				Results:    []string{"success"},
+			}},
+		},
 		AutoEncryptTLS:      false,
 		AutoEncryptDNSSAN:   []string{"a.com", "b.com"},
 		AutoEncryptIPSAN:    []net.IP{net.ParseIP("192.168.4.139"), net.ParseIP("192.168.4.140")},
@@ -6246,20 +6999,31 @@ func TestLoad_FullConfig(t *testing.T) {
 		DNSDisableCompression:            true,
 		DNSDomain:                        "7W1xXSqd",
 		DNSAltDomain:                     "1789hsd",
+		DNSPeerDomain:                    "Q2fPqXbB",
+		DNSViews:                         []RuntimeDNSView{{Domain: "Ws4bTf9m", OnlyPassing: true, Tags: []string{"Kd7vRy2L"}, ExcludeTags: []string{"Pq3nZx6H"}}},
 		DNSEnableTruncate:                true,
 		DNSMaxStale:                      29685 * time.Second,
 		DNSNodeTTL:                       7084 * time.Second,
 		DNSOnlyPassing:                   true,
 		DNSPort:                          7001,
+		DNSTLSAddrs:                      []net.Addr{tcpAddr("93.95.95.82:7853")},
+		DNSTLSPort:                       7853,
+		DNSHTTPSAddrs:                    []net.Addr{tcpAddr("93.95.95.83:7443")},
+		DNSHTTPSPort:                     7443,
 		DNSRecursorStrategy:              "sequential",
 		DNSRecursorTimeout:               4427 * time.Second,
 		DNSRecursors:                     []string{"63.38.39.58", "92.49.18.18"},
 		DNSSOA:                           RuntimeSOAConfig{Refresh: 3600, Retry: 600, Expire: 86400, Minttl: 0},
 		DNSServiceTTL:                    map[string]time.Duration{"*": 32030 * time.Second},
+		DNSVirtualAnswers:                map[string]dns.VirtualAnswer{"legacy-*": dns.VirtualAnswerInstances},
+		DNSVirtualTTL:                    19 * time.Second,
+		DNSServiceMetricsLimit:           3817,
 		DNSUDPAnswerLimit:                29909,
 		DNSNodeMetaTXT:                   true,
 		DNSUseCache:                      true,
 		DNSCacheMaxAge:                   5 * time.Minute,
+		DNSAnswerCacheTTL:                9 * time.Second,
+		DNSAnswerCacheStale:              4 * time.Minute,
 		DataDir:                          dataDir,
 		Datacenter:                       "rzo029wg",
 		DefaultQueryTime:                 16743 * time.Second,
@@ -6272,82 +7036,114 @@ func TestLoad_FullConfig(t *testing.T) {
 		DisableUpdateCheck:               true,
 		DiscardCheckOutput:               true,
 		DiscoveryMaxStale:                5 * time.Second,
+		DockerHost:                       "unix:///run/podman/podman.sock",
 		EnableAgentTLSForChecks:          true,
 		EnableCentralServiceConfig:       false,
 		EnableDebug:                      true,
 		EnableRemoteScriptChecks:         true,
 		EnableLocalScriptChecks:          true,
 		EncryptKey:                       "A4wELWqH",
+		EncryptRotation: consul.KeyringRotationConfig{
+			Enabled:     true,
+			Interval:    411 * time.Hour,
+			GracePeriod: 7 * time.Hour,
+		},
 		StaticRuntimeConfig: StaticRuntimeConfig{
 			EncryptVerifyIncoming: true,
 			EncryptVerifyOutgoing: true,
 		},
-
-		GRPCPort:              4881,
-		GRPCAddrs:             []net.Addr{tcpAddr("32.31.61.91:4881")},
-		GRPCTLSPort:           5201,
-		GRPCTLSAddrs:          []net.Addr{tcpAddr("23.14.88.19:5201")},
-		HTTPAddrs:             []net.Addr{tcpAddr("83.39.91.39:7999")},
-		HTTPBlockEndpoints:    []string{"RBvAFcGD", "fWOWFznh"},
-		AllowWriteHTTPFrom:    []*net.IPNet{cidr("127.0.0.0/8"), cidr("22.33.44.55/32"), cidr("0.0.0.0/0")},
-		HTTPPort:              7999,
-		HTTPResponseHeaders:   map[string]string{"M6TKa9NP": "xjuxjOzQ", "JRCrHZed": "rl0mTx81"},
-		HTTPSAddrs:            []net.Addr{tcpAddr("95.17.17.19:15127")},
-		HTTPMaxConnsPerClient: 100,
-		HTTPMaxHeaderBytes:    10,
-		HTTPSHandshakeTimeout: 2391 * time.Millisecond,
-		HTTPSPort:             15127,
-		HTTPUseCache:          false,
-		KVMaxValueSize:        1234567800,
-		LeaveDrainTime:        8265 * time.Second,
-		LeaveOnTerm:           true,
+		ExternalHealthChecks: consul.ExternalHealthChecksConfig{
+			Enabled:           true,
+			NodeProbeInterval: 17 * time.Second,
+		},
+
+		GRPCPort:               4881,
+		GRPCAddrs:              []net.Addr{tcpAddr("32.31.61.91:4881")},
+		GRPCTLSPort:            5201,
+		GRPCTLSAddrs:           []net.Addr{tcpAddr("23.14.88.19:5201")},
+		HTTPAddrs:              []net.Addr{tcpAddr("83.39.91.39:7999")},
+		HTTPBlockEndpoints:     []string{"RBvAFcGD", "fWOWFznh"},
+		AllowWriteHTTPFrom:     []*net.IPNet{cidr("127.0.0.0/8"), cidr("22.33.44.55/32"), cidr("0.0.0.0/0")},
+		HTTPPort:               7999,
+		HTTPResponseHeaders:    map[string]string{"M6TKa9NP": "xjuxjOzQ", "JRCrHZed": "rl0mTx81"},
+		HTTPSAddrs:             []net.Addr{tcpAddr("95.17.17.19:15127")},
+		HTTPMaxConnsPerClient:  100,
+		HTTPMaxHeaderBytes:     10,
+		HTTPSHandshakeTimeout:  2391 * time.Millisecond,
+		HTTPSPort:              15127,
+		HTTPUseCache:           false,
+		KVCompressionThreshold: 4096,
+		KVMaxValueSize:         1234567800,
+		LeaveDrainTime:         8265 * time.Second,
+		LeaveOnTerm:            true,
+		LeaveServiceDrainTime:  27 * time.Second,
 		Logging: logging.Config{
 			LogLevel:       "k1zo9Spt",
 			LogJSON:        true,
 			EnableSyslog:   true,
 			SyslogFacility: "hHv79Uia",
 		},
-		MaxQueryTime:            18237 * time.Second,
-		NodeID:                  types.NodeID("AsUIlw99"),
-		NodeMeta:                map[string]string{"5mgGQMBk": "mJLtVMSG", "A7ynFMJB": "0Nx6RGab"},
-		NodeName:                "otlLxGaI",
-		ReadReplica:             true,
-		PeeringEnabled:          true,
-		PidFile:                 "43xN80Km",
-		PrimaryGateways:         []string{"aej8eeZo", "roh2KahS"},
-		PrimaryGatewaysInterval: 18866 * time.Second,
-		RPCAdvertiseAddr:        tcpAddr("17.99.29.16:3757"),
-		RPCBindAddr:             tcpAddr("16.99.34.17:3757"),
-		RPCHandshakeTimeout:     1932 * time.Millisecond,
-		RPCClientTimeout:        62 * time.Second,
-		RPCHoldTimeout:          15707 * time.Second,
-		RPCProtocol:             30793,
-		RPCRateLimit:            12029.43,
-		RPCMaxBurst:             44848,
-		RPCMaxConnsPerClient:    2954,
-		RaftProtocol:            3,
-		RaftSnapshotThreshold:   16384,
-		RaftSnapshotInterval:    30 * time.Second,
-		RaftTrailingLogs:        83749,
-		ReconnectTimeoutLAN:     23739 * time.Second,
-		ReconnectTimeoutWAN:     26694 * time.Second,
-		RequestLimitsMode:       consulrate.ModePermissive,
-		RequestLimitsReadRate:   99.0,
-		RequestLimitsWriteRate:  101.0,
-		RejoinAfterLeave:        true,
-		RetryJoinIntervalLAN:    8067 * time.Second,
-		RetryJoinIntervalWAN:    28866 * time.Second,
-		RetryJoinLAN:            []string{"pbsSFY7U", "l0qLtWij", "LR3hGDoG", "MwVpZ4Up"},
-		RetryJoinMaxAttemptsLAN: 913,
-		RetryJoinMaxAttemptsWAN: 23160,
-		RetryJoinWAN:            []string{"PFsR02Ye", "rJdQIhER", "EbFSc3nA", "kwXTh623"},
-		RPCConfig:               consul.RPCConfig{EnableStreaming: true},
-		SegmentLimit:            123,
-		SerfPortLAN:             8301,
-		SerfPortWAN:             8302,
-		ServerMode:              true,
-		ServerName:              "Oerr9n1G",
-		ServerPort:              3757,
+		MaxQueryTime:                18237 * time.Second,
+		NamedPipeSecurityDescriptor: "D:P(A;;GA;;;BA)(A;;GA;;;SY)",
+		NodeID:                      types.NodeID("AsUIlw99"),
+		NodeMeta:                    map[string]string{"5mgGQMBk": "mJLtVMSG", "A7ynFMJB": "0Nx6RGab"},
+		NodeName:                    "otlLxGaI",
+		ReadReplica:                 true,
+		PeeringEnabled:              true,
+		PeeringIncomingMessageRate:  4215.9,
+		PeeringIncomingMessageBurst: 7265,
+		PidFile:                     "43xN80Km",
+		PrimaryGateways:             []string{"aej8eeZo", "roh2KahS"},
+		PrimaryGatewaysInterval:     18866 * time.Second,
+		Profiling: debug.ProfilerConfig{
+			Enabled:     true,
+			Dir:         "Gx7Lnq3Z",
+			Interval:    713 * time.Second,
+			CPUDuration: 23 * time.Second,
+			Profiles:    []string{"cpu", "mutex"},
+			Retain:      31,
+			PushURL:     "https://vK5pTm2W:4040/ingest",
+		},
+		RPCAdvertiseAddr:            tcpAddr("17.99.29.16:3757"),
+		RPCBindAddr:                 tcpAddr("16.99.34.17:3757"),
+		RPCHandshakeTimeout:         1932 * time.Millisecond,
+		RPCClientTimeout:            62 * time.Second,
+		RPCHoldTimeout:              15707 * time.Second,
+		RPCProtocol:                 30793,
+		RPCRateLimit:                12029.43,
+		RPCMaxBurst:                 44848,
+		RPCMaxConnsPerClient:        2954,
+		RaftProtocol:                3,
+		RaftSnapshotThreshold:       16384,
+		RaftSnapshotInterval:        30 * time.Second,
+		RaftTrailingLogs:            83749,
+		ReconnectTimeoutLAN:         23739 * time.Second,
+		ReconnectTimeoutWAN:         26694 * time.Second,
+		RequestLimitsMode:           consulrate.ModePermissive,
+		RequestLimitsReadRate:       99.0,
+		RequestLimitsWriteRate:      101.0,
+		RequestLimitsIPReadRate:     31.0,
+		RequestLimitsIPWriteRate:    37.0,
+		RequestLimitsIPOverrides:    []consul.RequestLimitsIPOverride{{CIDR: cidr("10.16.0.0/16"), ReadRate: 301.0, WriteRate: 37.0}},
+		RequestLimitsTokenReadRate:  41.0,
+		RequestLimitsTokenWriteRate: 43.0,
+		RequestLimitsTokenOverrides: []consul.RequestLimitsTokenOverride{{AccessorID: "f2a3c7e4-0a7b-4c39-9d2e-52a7f6b1d0e3", ReadRate: 401.0, WriteRate: 403.0}},
+		RejoinAfterLeave:            true,
+		RetryJoinFilters:            map[string]map[string]string{"aws": {"tag_key": "ZRv8bSOy", "tag_value": "p8yBzFD3"}},
+		RetryJoinIntervalLAN:        8067 * time.Second,
+		RetryJoinIntervalWAN:        28866 * time.Second,
+		RetryJoinLAN:                []string{"pbsSFY7U", "l0qLtWij", "LR3hGDoG", "MwVpZ4Up"},
+		RetryJoinMaxAttemptsLAN:     913,
+		RetryJoinMaxAttemptsWAN:     23160,
+		RetryJoinWAN:                []string{"PFsR02Ye", "rJdQIhER", "EbFSc3nA", "kwXTh623"},
+		RPCConfig:                   consul.RPCConfig{EnableStreaming: true},
+		SegmentLimit:                123,
+		SerfPortLAN:                 8301,
+		SerfPortWAN:                 8302,
+		ServerMode:                  true,
+		ServerName:                  "Oerr9n1G",
+		ServerPort:                  3757,
+		ServiceTombstoneTTL:         31415 * time.Second,
 		Services: []*structs.ServiceDefinition{
 			{
 				ID:      "wI1dzxS4",
@@ -6410,6 +7206,7 @@ func TestLoad_FullConfig(t *testing.T) {
 				Address: "R6H6g8h0",
 				Token:   "ZgY8gjMI",
 				Port:    38292,
+				Ports:   structs.ServicePorts{{Name: "admin", Port: 38293}},
 				Weights: &structs.Weights{
 					Passing: 1979,
 					Warning: 6,
@@ -6446,6 +7243,7 @@ func TestLoad_FullConfig(t *testing.T) {
 						CheckID:                        "UHsDeLxG",
 						Name:                           "PQSaPWlT",
 						Notes:                          "jKChDOdl",
+						PortName:                       "admin",
 						Status:                         "5qFz6OZn",
 						OutputMaxSize:                  checks.DefaultBufSize,
 						Timeout:                        4868 * time.Second,
@@ -6465,6 +7263,7 @@ func TestLoad_FullConfig(t *testing.T) {
 					DestinationServiceID:   "6L6BVfgH-id",
 					LocalServiceAddress:    "127.0.0.2",
 					LocalServicePort:       23759,
+					LocalServicePorts:      structs.ServicePorts{{Name: "admin", Port: 23760}},
 					Config: map[string]interface{}{
 						"cedGGtZf": "pWrUNiWw",
 					},
@@ -6472,6 +7271,7 @@ func TestLoad_FullConfig(t *testing.T) {
 						{
 							DestinationType:      "service", // Default should be explicitly filled
 							DestinationName:      "KPtAj2cb",
+							DestinationPort:      "admin",
 							DestinationPartition: defaultEntMeta.PartitionOrEmpty(),
 							DestinationNamespace: defaultEntMeta.NamespaceOrEmpty(),
 							LocalBindPort:        4051,
@@ -6645,15 +7445,18 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
-		UseStreamingBackend:  true,
-		SerfAdvertiseAddrLAN: tcpAddr("17.99.29.16:8301"),
-		SerfAdvertiseAddrWAN: tcpAddr("78.63.37.19:8302"),
-		SerfBindAddrLAN:      tcpAddr("99.43.63.15:8301"),
-		SerfBindAddrWAN:      tcpAddr("67.88.33.19:8302"),
-		SerfAllowedCIDRsLAN:  []net.IPNet{},
-		SerfAllowedCIDRsWAN:  []net.IPNet{},
-		SessionTTLMin:        26627 * time.Second,
-		SkipLeaveOnInt:       true,
+		UseStreamingBackend:                true,
+		UseStreamingBackendCatalogServices: true,
+		SerfAdvertiseAddrLAN:               tcpAddr("17.99.29.16:8301"),
+		SerfAdvertiseAddrWAN:               tcpAddr("78.63.37.19:8302"),
+		SerfBindAddrLAN:                    tcpAddr("99.43.63.15:8301"),
+		SerfBindAddrWAN:                    tcpAddr("67.88.33.19:8302"),
+		SerfAllowedCIDRsLAN:                []net.IPNet{},
+		SerfAllowedCIDRsWAN:                []net.IPNet{},
+		ServicesAutoReloadDir:              "/etc/consul.d/services",
+		ServicesAutoReloadDebounce:         3 * time.Second,
+		SessionTTLMin:                      26627 * time.Second,
+		SkipLeaveOnInt:                     true,
 		Telemetry: lib.TelemetryConfig{
 			CirconusAPIApp:                     "p4QOTe9j",
 			CirconusAPIToken:                   "E3j35V23",
@@ -6676,6 +7479,17 @@ func TestLoad_FullConfig(t *testing.T) {
 			AllowedPrefixes:                    []string{"oJotS8XJ"},
 			BlockedPrefixes:                    []string{"cazlEhGn", "ftO6DySn.rpc.server.call"},
 			MetricsPrefix:                      "ftO6DySn",
+			OTLPEndpoint:                       "https://q9MgVMJE:4318",
+			OTLPProtocol:                       "http/protobuf",
+			OTLPInsecure:                       true,
+			OTLPHeaders:                        map[string]string{"X-Api-Key": "yT6Zz3Mi"},
+			OTLPExportInterval:                 17 * time.Second,
+			OTLPTemporality:                    "delta",
+			OTLPResourceAttributes:             map[string]string{"deployment.environment": "dY7nGb3V"},
+			OTLPLogs:                           true,
+			RPCServiceLabels:                   true,
+			RPCPeerLabels:                      true,
+			RPCLabelsMaxValues:                 37,
 			StatsdAddr:                         "drce87cy",
 			StatsiteAddr:                       "HpFwKB8R",
 			PrometheusOpts: prometheus.PrometheusOpts{
@@ -6766,7 +7580,8 @@ func TestLoad_FullConfig(t *testing.T) {
 				"args":       []interface{}{"dltjDJ2a", "flEa7C2d"},
 			},
 		},
-		XDSUpdateRateLimit: 9526.2,
+		XDSUpdateRateLimit:    9526.2,
+		XDSStrictEnvoyVersion: true,
 		RaftLogStoreConfig: consul.RaftLogStoreConfig{
 			Backend:         consul.LogStoreBackendWAL,
 			DisableLogCache: true,
@@ -6775,7 +7590,19 @@ func TestLoad_FullConfig(t *testing.T) {
 				Interval: 12345 * time.Second,
 			},
 			BoltDB: consul.RaftBoltDBConfig{NoFreelistSync: true},
-			WAL:    consul.WALConfig{SegmentSize: 15 * 1024 * 1024},
+			WAL: consul.WALConfig{
+				SegmentSize: 15 * 1024 * 1024,
+				Canary: consul.WALCanaryConfig{
+					Enabled:        true,
+					VerifyInterval: 4321 * time.Second,
+					Failback:       false,
+				},
+			},
+		},
+		RaftCompression: consul.RaftCompressionConfig{
+			LogThreshold: 8192,
+			Snapshots:    true,
+			Level:        "better",
 		},
 		AutoReloadConfigCoalesceInterval: 1 * time.Second,
 	}
@@ -6808,6 +7635,7 @@ func TestLoad_FullConfig(t *testing.T) {
 		deprecationWarning("verify_outgoing", "tls.defaults.verify_outgoing"),
 		deprecationWarning("verify_server_hostname", "tls.internal_rpc.verify_server_hostname"),
 		"The 'tls_prefer_server_cipher_suites' field is deprecated and will be ignored.",
+		"acl.client_cert_auth_method is only supported in the primary datacenter, the logins with client certificates will fail",
 		deprecationWarning("start_join", "retry_join"),
 		deprecationWarning("start_join_wan", "retry_join_wan"),
 	}
@@ -13,6 +13,7 @@ acl = {
     down_policy = "03eb2aee"
     default_policy = "72c2e7a0"
     enable_key_list_policy = true
+    client_cert_auth_method = "4f1e6c2a"
     enable_token_persistence = true
     policy_ttl = "1123s"
     role_ttl = "9876s"
@@ -41,6 +42,8 @@ acl = {
 }
 addresses = {
     dns = "93.95.95.81"
+    dns_tls = "93.95.95.82"
+    dns_https = "93.95.95.83"
     http = "83.39.91.39"
     https = "95.17.17.19"
     grpc = "32.31.61.91"
@@ -49,8 +52,26 @@ addresses = {
 advertise_addr = "17.99.29.16"
 advertise_addr_wan = "78.63.37.19"
 advertise_reconnect_timeout = "0s"
+ae_sync_batch_size = 4123
+ae_sync_rate_limit = 12.5
 audit = {
     enabled = true
+    sink "Kd2BSq9w" {
+        type = "file"
+        format = "json"
+        path = "/tmp/lUhEk7Gq/audit.json"
+        delivery_guarantee = "best-effort"
+        mode = "0600"
+        rotate_bytes = 5912
+        rotate_duration = "13h"
+        rotate_max_files = 6
+    }
+    filter {
+        action = "exclude"
+        operations = ["read"]
+        resources = ["/v1/agent/*"]
+        results = ["success"]
+    }
 }
 auto_config = {
     enabled = false
@@ -82,17 +103,23 @@ autopilot = {
     last_contact_threshold = "12705s"
     max_trailing_logs = 17849
     min_quorum = 3
+    min_server_uptime = "4417s"
+    promotion_strategy = "zones"
     redundancy_zone_tag = "3IsufDJf"
     server_stabilization_time = "23057s"
     upgrade_version_tag = "W9pDwFAL"
+    voters_per_zone = 2
+    zone_meta_key = "zJ6kS2aE"
 }
 bind_addr = "16.99.34.17"
 bootstrap_expect = 53
 cache = {
     entry_fetch_max_burst = 42
     entry_fetch_rate = 0.334
+    persist = true
 },
 use_streaming_backend = true
+use_streaming_backend_catalog_services = true
 ca_file = "erA7T0PM"
 ca_path = "mQEN1Mfp"
 cert_file = "7s4QAzDk"
@@ -120,6 +147,9 @@ check = {
     docker_container_id = "qF66POS9"
     shell = "sOnDy228"
     os_service = "aZaCAXww"
+    grpc = "Vu6pXq3S"
+    grpc_service = "Hn3xWq7T"
+    grpc_authority = "uK5sMb9C"
     tls_server_name = "7BdnzBYk"
     tls_skip_verify = true
     timeout = "5954s"
@@ -183,8 +213,39 @@ checks = [
         tls_skip_verify = true
         timeout = "18506s"
         deregister_critical_service_after = "2366s"
+    },
+    {
+        id = "Lw9cTs1N"
+        name = "Cn2hVt5J"
+        service_id = "L8G0QNmR"
+        composite_mode = "and"
+        composite_checks = ["x2JgMRp8", "Qv4hnW6e"]
+        composite_min_passing = 1
+    },
+    {
+        id = "Hp6sDk1V"
+        name = "Ye9qBr3T"
+        process_pid = 5122
+        process_cgroup = "dE8pV2kM"
+        interval = "7391s"
+    },
+    {
+        id = "Fj2nXc7M"
+        name = "Ub5wKe8P"
+        process_pidfile = "Rt7cXq0Y"
+        interval = "8123s"
     }
 ]
+change_sink "Vq3sKm8T" {
+    type = "kafka"
+    url = "http://fX2dPq7w:8082"
+    headers {
+        Authorization = "Bearer j5WmQ9cR"
+    }
+    kafka_topic = "tB8nZk4L"
+    topics = ["catalog"]
+    timeout = "23s"
+}
 check_update_interval = "16507s"
 client_addr = "93.83.18.19"
 config_entries {
@@ -227,12 +288,14 @@ connect {
     enabled = true
 }
 gossip_lan {
-    gossip_nodes    = 6
-    gossip_interval = "25252s"
-    retransmit_mult = 1234
-    suspicion_mult  = 1235
-    probe_interval  = "101ms"
-    probe_timeout   = "102ms"
+    profile            = "large"
+    gossip_nodes       = 6
+    gossip_interval    = "25252s"
+    retransmit_mult    = 1234
+    suspicion_mult     = 1235
+    probe_interval     = "101ms"
+    probe_timeout      = "102ms"
+    push_pull_interval = "103ms"
 }
 gossip_wan {
     gossip_nodes    = 2
@@ -253,6 +316,7 @@ disable_remote_exec = true
 disable_update_check = true
 discard_check_output = true
 discovery_max_stale = "5s"
+docker_host = "unix:///run/podman/podman.sock"
 domain = "7W1xXSqd"
 alt_domain = "1789hsd"
 dns_config {
@@ -270,6 +334,21 @@ dns_config {
     udp_answer_limit = 29909
     use_cache = true
     cache_max_age = "5m"
+    peer_domain = "Q2fPqXbB"
+    answer_cache_ttl = "9s"
+    virtual_answers = {
+        "legacy-*" = "instances"
+    }
+    virtual_ttl = "19s"
+    service_metrics_limit = 3817
+    answer_cache_stale_if_error = "4m"
+    views = [
+        {
+            domain = "Ws4bTf9m"
+            tags = ["Kd7vRy2L"]
+            exclude_tags = ["Pq3nZx6H"]
+        }
+    ]
     prefer_namespace = true
 }
 enable_acl_replication = true
@@ -280,8 +359,17 @@ enable_script_checks = true
 enable_local_script_checks = true
 enable_syslog = true
 encrypt = "A4wELWqH"
+encrypt_rotation {
+    enabled = true
+    interval = "411h"
+    grace_period = "7h"
+}
 encrypt_verify_incoming = true
 encrypt_verify_outgoing = true
+external_health_checks {
+    enabled = true
+    node_probe_interval = "17s"
+}
 http_config {
     block_endpoints = [ "RBvAFcGD", "fWOWFznh" ]
     allow_write_http_from = [ "127.0.0.1/8", "22.33.44.55/32", "0.0.0.0/0" ]
@@ -293,7 +381,9 @@ http_config {
     max_header_bytes = 10
 }
 key_file = "IEkkwgIA"
+kv_compression_threshold = 4096
 leave_on_terminate = true
+leave_service_drain_time = "27s"
 license_path = "/path/to/license.lic"
 limits {
     http_max_conns_per_client = 100
@@ -309,6 +399,23 @@ limits {
         mode = "permissive"
         read_rate = 99.0
         write_rate = 101.0
+        per_ip {
+            read_rate = 31.0
+            write_rate = 37.0
+            override {
+                cidr = "10.16.0.0/16"
+                read_rate = 301.0
+            }
+        }
+        per_token {
+            read_rate = 41.0
+            write_rate = 43.0
+            override {
+                accessor_id = "f2a3c7e4-0a7b-4c39-9d2e-52a7f6b1d0e3"
+                read_rate = 401.0
+                write_rate = 403.0
+            }
+        }
     }
 }
 log_level = "k1zo9Spt"
@@ -324,6 +431,8 @@ non_voting_server = true
 partition = ""
 peering {
     enabled = true
+    incoming_message_rate = 4215.9
+    incoming_message_burst = 7265
 }
 performance {
     leave_drain_time = "8265s"
@@ -333,6 +442,8 @@ performance {
 pid_file = "43xN80Km"
 ports {
     dns = 7001
+    dns_tls = 7853
+    dns_https = 7443
     http = 7999
     https = 15127
     server = 3757
@@ -349,10 +460,24 @@ protocol = 30793
 primary_datacenter = "ejtmd43d"
 primary_gateways = [ "aej8eeZo", "roh2KahS" ]
 primary_gateways_interval = "18866s"
+profiling {
+    enabled = true
+    dir = "Gx7Lnq3Z"
+    interval = "713s"
+    cpu_duration = "23s"
+    profiles = ["cpu", "mutex"]
+    retain = 31
+    push_url = "https://vK5pTm2W:4040/ingest"
+}
 raft_protocol = 3
 raft_snapshot_threshold = 16384
 raft_snapshot_interval = "30s"
 raft_trailing_logs = 83749
+raft_compression {
+    log_threshold = 8192
+    snapshots = true
+    level = "better"
+}
 raft_logstore {
     backend = "wal"
     disable_log_cache = true
@@ -365,6 +490,11 @@ raft_logstore {
     }
     wal {
        segment_size_mb = 15
+       canary {
+           enabled = true
+           verify_interval = "4321s"
+           failback = false
+       }
     }
 }
 read_replica = true
@@ -375,6 +505,12 @@ rejoin_after_leave = true
 retry_interval = "8067s"
 retry_interval_wan = "28866s"
 retry_join = [ "pbsSFY7U", "l0qLtWij" ]
+retry_join_filters {
+    aws {
+        tag_key = "ZRv8bSOy"
+        tag_value = "p8yBzFD3"
+    }
+}
 retry_join_wan = [ "PFsR02Ye", "rJdQIhER" ]
 retry_max = 913
 retry_max_wan = 23160
@@ -497,6 +633,7 @@ service = {
         native = true
     }
 }
+service_tombstone_ttl = "31415s"
 services = [
     {
         id = "wI1dzxS4"
@@ -544,6 +681,12 @@ services = [
         address = "R6H6g8h0"
         token = "ZgY8gjMI"
         port = 38292
+        ports = [
+            {
+                name = "admin"
+                port = 38293
+            }
+        ]
         weights = {
             passing = 1979,
             warning = 6
@@ -581,6 +724,7 @@ services = [
                 id = "UHsDeLxG"
                 name = "PQSaPWlT"
                 notes = "jKChDOdl"
+                port_name = "admin"
                 status = "5qFz6OZn"
                 output_max_size = 4096
                 timeout = "4868s"
@@ -600,12 +744,19 @@ services = [
             destination_service_id = "6L6BVfgH-id"
             local_service_address = "127.0.0.2"
             local_service_port = 23759
+            local_service_ports = [
+                {
+                    name = "admin"
+                    port = 23760
+                }
+            ]
             config {
                 cedGGtZf = "pWrUNiWw"
             }
             upstreams = [
                 {
                     destination_name = "KPtAj2cb"
+                    destination_port = "admin"
                     local_bind_port = 4051
                     config {
                         kzRnZOyd = "nUNKoL8H"
@@ -658,6 +809,10 @@ services = [
         }
     }
 ]
+services_auto_reload {
+    dir = "/etc/consul.d/services"
+    debounce = "3s"
+}
 session_ttl_min = "26627s"
 skip_leave_on_interrupt = true
 start_join = [ "LR3hGDoG", "MwVpZ4Up" ]
@@ -688,7 +843,22 @@ telemetry {
     filter_default = true
     prefix_filter = [ "+oJotS8XJ","-cazlEhGn" ]
     metrics_prefix = "ftO6DySn"
+    otlp_endpoint = "https://q9MgVMJE:4318"
+    otlp_protocol = "http/protobuf"
+    otlp_insecure = true
+    otlp_headers {
+        "X-Api-Key" = "yT6Zz3Mi"
+    }
+    otlp_export_interval = "17s"
+    otlp_temporality = "delta"
+    otlp_resource_attributes {
+        "deployment.environment" = "dY7nGb3V"
+    }
+    otlp_logs = true
     prometheus_retention_time = "15s"
+    rpc_service_labels = true
+    rpc_peer_labels = true
+    rpc_labels_max_values = 37
     statsd_address = "drce87cy"
     statsite_address = "HpFwKB8R"
 }
@@ -758,6 +928,9 @@ ui_config {
         u2eziu2n_lower_case = "http://lkjasd.otr"
     }
 }
+named_pipes = {
+    security_descriptor = "D:P(A;;GA;;;BA)(A;;GA;;;SY)"
+}
 unix_sockets = {
     group = "8pFodrV8"
     mode = "E8sAwOv4"
@@ -781,4 +954,5 @@ watches = [{
 }]
 xds {
   update_max_per_second = 9526.2
+  strict_envoy_version = true
 }
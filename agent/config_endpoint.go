@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -64,18 +66,74 @@ func (s *HTTPHandlers) configGet(resp http.ResponseWriter, req *http.Request) (i
 		// Only kind provided, list entries.
 		args.Kind = pathArgs[0]
 
+		if err := parsePagination(req, &args.PageOptions); err != nil {
+			return nil, err
+		}
+		fields := parseConfigEntryFields(req)
+
 		var reply structs.IndexedConfigEntries
 		if err := s.agent.RPC(req.Context(), "ConfigEntry.List", &args, &reply); err != nil {
 			return nil, err
 		}
 		setMeta(resp, &reply.QueryMeta)
+		setNextCursor(resp, reply.NextCursor, reply.Index)
 
+		if fields != nil {
+			return maskConfigEntries(reply.Entries, fields)
+		}
 		return reply.Entries, nil
 	default:
 		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: "Must provide either a kind or both kind and name"}
 	}
 }
 
+// parseConfigEntryFields parses the ?fields query parameter, the comma
+// separated list of the fields of the config entries to return. It returns nil
+// if it wasn't given so that all the fields are returned.
+func parseConfigEntryFields(req *http.Request) map[string]struct{} {
+	raw := req.URL.Query().Get("fields")
+	if raw == "" {
+		return nil
+	}
+	fields := make(map[string]struct{})
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = struct{}{}
+		}
+	}
+	return fields
+}
+
+// maskConfigEntries returns the config entries with only the given top-level
+// fields of their JSON encoding. The fields of the entries vary with their
+// kind, so they are masked on the encoded entries rather than on the structs.
+func maskConfigEntries(entries []structs.ConfigEntry, fields map[string]struct{}) ([]map[string]interface{}, error) {
+	out := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		buf, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		// Decode the numbers as json.Number so that the indexes don't lose
+		// precision as float64.
+		dec := json.NewDecoder(bytes.NewReader(buf))
+		dec.UseNumber()
+		var all map[string]interface{}
+		if err := dec.Decode(&all); err != nil {
+			return nil, err
+		}
+
+		masked := make(map[string]interface{}, len(fields))
+		for field := range fields {
+			if value, ok := all[field]; ok {
+				masked[field] = value
+			}
+		}
+		out = append(out, masked)
+	}
+	return out, nil
+}
+
 // configDelete deletes the given config entry.
 func (s *HTTPHandlers) configDelete(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var args structs.ConfigEntryRequest
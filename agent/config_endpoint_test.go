@@ -91,6 +91,63 @@ func TestConfig_Get(t *testing.T) {
 		require.Equal(t, value[0].(*structs.ServiceConfigEntry).Name, "bar")
 		require.Equal(t, value[1].(*structs.ServiceConfigEntry).Name, "foo")
 	})
+	t.Run("list service entries by page", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/config/service-defaults?limit=1", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.Config(resp, req)
+		require.NoError(t, err)
+
+		value := obj.([]structs.ConfigEntry)
+		require.Len(t, value, 1)
+		require.Equal(t, "bar", value[0].GetName())
+		next := resp.Header().Get("X-Consul-Next-Cursor")
+		require.NotEmpty(t, next)
+
+		req, _ = http.NewRequest("GET", "/v1/config/service-defaults?limit=1&cursor="+next, nil)
+		resp = httptest.NewRecorder()
+		obj, err = a.srv.Config(resp, req)
+		require.NoError(t, err)
+
+		value = obj.([]structs.ConfigEntry)
+		require.Len(t, value, 1)
+		require.Equal(t, "foo", value[0].GetName())
+		require.Empty(t, resp.Header().Get("X-Consul-Next-Cursor"))
+
+		// The cursor is rejected once the config entries changed.
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind:     structs.ServiceDefaults,
+				Name:     "baz",
+				Protocol: "tcp",
+			},
+		}, new(bool)))
+
+		req, _ = http.NewRequest("GET", "/v1/config/service-defaults?limit=1&cursor="+next, nil)
+		_, err = a.srv.Config(httptest.NewRecorder(), req)
+		require.True(t, structs.IsErrPaginationConflict(err))
+
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Delete", &structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind: structs.ServiceDefaults,
+				Name: "baz",
+			},
+		}, &structs.ConfigEntryDeleteResponse{}))
+	})
+	t.Run("list service entries fields", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/config/service-defaults?fields=Name,ModifyIndex,Unknown", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.Config(resp, req)
+		require.NoError(t, err)
+
+		value := obj.([]map[string]interface{})
+		require.Len(t, value, 2)
+		require.Len(t, value[0], 2)
+		require.Equal(t, "bar", value[0]["Name"])
+		require.Contains(t, value[0], "ModifyIndex")
+		require.Equal(t, "foo", value[1]["Name"])
+	})
 	t.Run("get global proxy config", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/v1/config/proxy-defaults/global", nil)
 		resp := httptest.NewRecorder()
@@ -129,6 +186,7 @@ func TestConfig_Get(t *testing.T) {
 		expected := `
 {
 	"Kind": "mesh",
+	"MeshGateway": {},
 	"TransparentProxy": {
 		"MeshDestinationsOnly": true
 	},
@@ -1,6 +1,7 @@
 package configentry
 
 import (
+	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
@@ -14,6 +15,10 @@ type DiscoveryChainSet struct {
 	Resolvers     map[structs.ServiceID]*structs.ServiceResolverConfigEntry
 	Services      map[structs.ServiceID]*structs.ServiceConfigEntry
 	ProxyDefaults map[string]*structs.ProxyConfigEntry
+	MeshDefaults  map[string]*structs.MeshConfigEntry
+
+	// SamenessGroups are the sameness groups that resolvers fail over to.
+	SamenessGroups map[KindName]*structs.SamenessGroupConfigEntry
 }
 
 func NewDiscoveryChainSet() *DiscoveryChainSet {
@@ -23,6 +28,9 @@ func NewDiscoveryChainSet() *DiscoveryChainSet {
 		Resolvers:     make(map[structs.ServiceID]*structs.ServiceResolverConfigEntry),
 		Services:      make(map[structs.ServiceID]*structs.ServiceConfigEntry),
 		ProxyDefaults: make(map[string]*structs.ProxyConfigEntry),
+		MeshDefaults:  make(map[string]*structs.MeshConfigEntry),
+
+		SamenessGroups: make(map[KindName]*structs.SamenessGroupConfigEntry),
 	}
 }
 
@@ -61,6 +69,20 @@ func (e *DiscoveryChainSet) GetProxyDefaults(partition string) *structs.ProxyCon
 	return nil
 }
 
+func (e *DiscoveryChainSet) GetMeshDefaults(partition string) *structs.MeshConfigEntry {
+	if e.MeshDefaults != nil {
+		return e.MeshDefaults[partition]
+	}
+	return nil
+}
+
+func (e *DiscoveryChainSet) GetSamenessGroup(name string, entMeta *acl.EnterpriseMeta) *structs.SamenessGroupConfigEntry {
+	if e.SamenessGroups != nil {
+		return e.SamenessGroups[NewKindName(structs.SamenessGroup, name, entMeta)]
+	}
+	return nil
+}
+
 // AddRouters adds router configs. Convenience function for testing.
 func (e *DiscoveryChainSet) AddRouters(entries ...*structs.ServiceRouterConfigEntry) {
 	if e.Routers == nil {
@@ -111,6 +133,27 @@ func (e *DiscoveryChainSet) AddProxyDefaults(entries ...*structs.ProxyConfigEntr
 	}
 }
 
+// AddMeshDefaults adds mesh configs. Convenience function for testing.
+func (e *DiscoveryChainSet) AddMeshDefaults(entries ...*structs.MeshConfigEntry) {
+	if e.MeshDefaults == nil {
+		e.MeshDefaults = make(map[string]*structs.MeshConfigEntry)
+	}
+	for _, entry := range entries {
+		e.MeshDefaults[entry.PartitionOrDefault()] = entry
+	}
+}
+
+// AddSamenessGroups adds sameness group configs. Convenience function for
+// testing.
+func (e *DiscoveryChainSet) AddSamenessGroups(entries ...*structs.SamenessGroupConfigEntry) {
+	if e.SamenessGroups == nil {
+		e.SamenessGroups = make(map[KindName]*structs.SamenessGroupConfigEntry)
+	}
+	for _, entry := range entries {
+		e.SamenessGroups[NewKindNameForEntry(entry)] = entry
+	}
+}
+
 // AddEntries adds generic configs. Convenience function for testing. Panics on
 // operator error.
 func (e *DiscoveryChainSet) AddEntries(entries ...structs.ConfigEntry) {
@@ -129,6 +172,10 @@ func (e *DiscoveryChainSet) AddEntries(entries ...structs.ConfigEntry) {
 				panic("the only supported proxy-defaults name is '" + structs.ProxyConfigGlobal + "'")
 			}
 			e.AddProxyDefaults(entry.(*structs.ProxyConfigEntry))
+		case structs.MeshConfig:
+			e.AddMeshDefaults(entry.(*structs.MeshConfigEntry))
+		case structs.SamenessGroup:
+			e.AddSamenessGroups(entry.(*structs.SamenessGroupConfigEntry))
 		default:
 			panic("unhandled config entry kind: " + entry.GetKind())
 		}
@@ -138,7 +185,7 @@ func (e *DiscoveryChainSet) AddEntries(entries ...structs.ConfigEntry) {
 // IsEmpty returns true if there are no config entries at all in the response.
 // You should prefer this over IsChainEmpty() in most cases.
 func (e *DiscoveryChainSet) IsEmpty() bool {
-	return e.IsChainEmpty() && len(e.Services) == 0 && len(e.ProxyDefaults) == 0
+	return e.IsChainEmpty() && len(e.Services) == 0 && len(e.ProxyDefaults) == 0 && len(e.MeshDefaults) == 0
 }
 
 // IsChainEmpty returns true if there are no service-routers,
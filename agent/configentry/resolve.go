@@ -33,6 +33,14 @@ func ComputeResolvedServiceConfig(
 	// blocking query, this function will be rerun and these state store lookups will both be current.
 	// We use the default enterprise meta to look up the global proxy defaults because they are not namespaced.
 
+	// The mesh config entry holds the partition-wide mesh gateway default,
+	// which any of the more specific config entries can override.
+	meshConf := entries.GetMeshDefaults(args.PartitionOrDefault())
+	if meshConf != nil && meshConf.MeshGateway.Mode != structs.MeshGatewayModeDefault {
+		thisReply.MeshGateway = meshConf.MeshGateway
+		wildcardUpstreamDefaults["mesh_gateway"] = meshConf.MeshGateway
+	}
+
 	proxyConf := entries.GetProxyDefaults(args.PartitionOrDefault())
 	if proxyConf != nil {
 		// Apply the proxy defaults to the sidecar's proxy config
@@ -44,7 +52,9 @@ func ComputeResolvedServiceConfig(
 		thisReply.ProxyConfig = mapCopy.(map[string]interface{})
 		thisReply.Mode = proxyConf.Mode
 		thisReply.TransparentProxy = proxyConf.TransparentProxy
-		thisReply.MeshGateway = proxyConf.MeshGateway
+		if proxyConf.MeshGateway.Mode != structs.MeshGatewayModeDefault {
+			thisReply.MeshGateway = proxyConf.MeshGateway
+		}
 		thisReply.Expose = proxyConf.Expose
 		thisReply.EnvoyExtensions = proxyConf.EnvoyExtensions
 		thisReply.AccessLogs = proxyConf.AccessLogs
@@ -91,6 +101,9 @@ func ComputeResolvedServiceConfig(
 		if serviceConf.Destination != nil {
 			thisReply.Destination = *serviceConf.Destination
 		}
+		if serviceConf.AccessLogs != nil {
+			thisReply.AccessLogs = *serviceConf.AccessLogs
+		}
 
 		// Populate values for the proxy config map
 		proxyConf := thisReply.ProxyConfig
@@ -240,7 +253,7 @@ func ComputeResolvedServiceConfig(
 
 		// When dialing an upstream, the goal is to flatten the mesh gateway mode in this order
 		// (larger number wins):
-		//  1. Value from the proxy-defaults
+		//  1. Value from the proxy-defaults, falling back to the mesh config entry
 		//  2. Value from top-level of service-defaults (ServiceDefaults.MeshGateway)
 		//  3. Value from centralized upstream defaults (ServiceDefaults.UpstreamConfig.Defaults)
 		//  4. Value from local proxy registration (NodeService.Proxy.MeshGateway)
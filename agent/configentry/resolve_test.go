@@ -138,6 +138,77 @@ func Test_ComputeResolvedServiceConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "proxy upstream mesh-gateway inherits mesh config entry",
+			args: args{
+				scReq: &structs.ServiceConfigRequest{
+					Name:                 "sid",
+					UpstreamServiceNames: uids,
+				},
+				entries: &ResolvedServiceConfigSet{
+					MeshDefaults: map[string]*structs.MeshConfigEntry{
+						acl.DefaultEnterpriseMeta().PartitionOrDefault(): {
+							MeshGateway: localMeshGW,
+						},
+					},
+				},
+			},
+			want: &structs.ServiceConfigResponse{
+				MeshGateway: localMeshGW,
+				UpstreamConfigs: structs.OpaqueUpstreamConfigs{
+					{
+						Upstream: wildcard,
+						Config: map[string]interface{}{
+							"mesh_gateway": localMeshGW,
+						},
+					},
+					{
+						Upstream: uid,
+						Config: map[string]interface{}{
+							"mesh_gateway": localMeshGW,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "proxy upstream mesh-gateway proxy-defaults overrides mesh config entry",
+			args: args{
+				scReq: &structs.ServiceConfigRequest{
+					Name:                 "sid",
+					UpstreamServiceNames: uids,
+				},
+				entries: &ResolvedServiceConfigSet{
+					MeshDefaults: map[string]*structs.MeshConfigEntry{
+						acl.DefaultEnterpriseMeta().PartitionOrDefault(): {
+							MeshGateway: localMeshGW, // applied 1st
+						},
+					},
+					ProxyDefaults: map[string]*structs.ProxyConfigEntry{
+						acl.DefaultEnterpriseMeta().PartitionOrDefault(): {
+							MeshGateway: remoteMeshGW, // applied 2nd
+						},
+					},
+				},
+			},
+			want: &structs.ServiceConfigResponse{
+				MeshGateway: remoteMeshGW,
+				UpstreamConfigs: structs.OpaqueUpstreamConfigs{
+					{
+						Upstream: wildcard,
+						Config: map[string]interface{}{
+							"mesh_gateway": remoteMeshGW,
+						},
+					},
+					{
+						Upstream: uid,
+						Config: map[string]interface{}{
+							"mesh_gateway": remoteMeshGW,
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "proxy inherits kitchen sink from proxy-defaults",
 			args: args{
@@ -209,6 +280,43 @@ func Test_ComputeResolvedServiceConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "proxy access logs inherit service-defaults",
+			args: args{
+				scReq: &structs.ServiceConfigRequest{
+					Name: "sid",
+				},
+				entries: &ResolvedServiceConfigSet{
+					ProxyDefaults: map[string]*structs.ProxyConfigEntry{
+						acl.DefaultEnterpriseMeta().PartitionOrDefault(): {
+							AccessLogs: structs.AccessLogsConfig{
+								Enabled:    true,
+								Type:       structs.FileLogSinkType,
+								Path:       "/tmp/accesslog.txt",
+								JSONFormat: "{ \"custom_start_time\": \"%START_TIME%\" }",
+							},
+						},
+					},
+					ServiceDefaults: map[structs.ServiceID]*structs.ServiceConfigEntry{
+						sid: {
+							AccessLogs: &structs.AccessLogsConfig{
+								Enabled:    true,
+								Type:       structs.StdOutLogSinkType,
+								TextFormat: "%START_TIME% %RESPONSE_CODE%",
+							},
+						},
+					},
+				},
+			},
+			want: &structs.ServiceConfigResponse{
+				// service-defaults replaces the access logs of proxy-defaults.
+				AccessLogs: structs.AccessLogsConfig{
+					Enabled:    true,
+					Type:       structs.StdOutLogSinkType,
+					TextFormat: "%START_TIME% %RESPONSE_CODE%",
+				},
+			},
+		},
 		{
 			name: "proxy upstream mesh-gateway inherits service-defaults",
 			args: args{
@@ -11,10 +11,11 @@ import (
 type ResolvedServiceConfigSet struct {
 	ServiceDefaults map[structs.ServiceID]*structs.ServiceConfigEntry
 	ProxyDefaults   map[string]*structs.ProxyConfigEntry
+	MeshDefaults    map[string]*structs.MeshConfigEntry
 }
 
 func (r *ResolvedServiceConfigSet) IsEmpty() bool {
-	return len(r.ServiceDefaults) == 0 && len(r.ProxyDefaults) == 0
+	return len(r.ServiceDefaults) == 0 && len(r.ProxyDefaults) == 0 && len(r.MeshDefaults) == 0
 }
 
 func (r *ResolvedServiceConfigSet) GetServiceDefaults(sid structs.ServiceID) *structs.ServiceConfigEntry {
@@ -31,6 +32,13 @@ func (r *ResolvedServiceConfigSet) GetProxyDefaults(partition string) *structs.P
 	return r.ProxyDefaults[partition]
 }
 
+func (r *ResolvedServiceConfigSet) GetMeshDefaults(partition string) *structs.MeshConfigEntry {
+	if r.MeshDefaults == nil {
+		return nil
+	}
+	return r.MeshDefaults[partition]
+}
+
 func (r *ResolvedServiceConfigSet) AddServiceDefaults(entry *structs.ServiceConfigEntry) {
 	if entry == nil {
 		return
@@ -55,3 +63,15 @@ func (r *ResolvedServiceConfigSet) AddProxyDefaults(entry *structs.ProxyConfigEn
 
 	r.ProxyDefaults[entry.PartitionOrDefault()] = entry
 }
+
+func (r *ResolvedServiceConfigSet) AddMeshDefaults(entry *structs.MeshConfigEntry) {
+	if entry == nil {
+		return
+	}
+
+	if r.MeshDefaults == nil {
+		r.MeshDefaults = make(map[string]*structs.MeshConfigEntry)
+	}
+
+	r.MeshDefaults[entry.PartitionOrDefault()] = entry
+}
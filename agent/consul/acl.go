@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/structs/aclfilter"
 	"github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/lib/stringslice"
 	"github.com/hashicorp/consul/logging"
 )
 
@@ -60,6 +61,10 @@ const (
 	// due to the data being more variable in its size.
 	aclBatchUpsertSize = 256 * 1024
 
+	// aclTokenUsageFlushInterval is how often the leader writes the token
+	// usages reported by the agents to Raft.
+	aclTokenUsageFlushInterval = 10 * time.Minute
+
 	// Maximum number of re-resolution requests to be made if the token is modified between
 	// resolving the token and resolving its policies that would remove one of its policies.
 	tokenPolicyResolutionMaxRetries = 5
@@ -99,6 +104,10 @@ func (id *missingIdentity) NodeIdentityList() []*structs.ACLNodeIdentity {
 	return nil
 }
 
+func (id *missingIdentity) TemplatedPolicyList() []*structs.ACLTemplatedPolicy {
+	return nil
+}
+
 func (id *missingIdentity) IsExpired(asOf time.Time) bool {
 	return false
 }
@@ -593,9 +602,10 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 		roleIDs           = identity.RoleIDs()
 		serviceIdentities = structs.ACLServiceIdentities(identity.ServiceIdentityList())
 		nodeIdentities    = structs.ACLNodeIdentities(identity.NodeIdentityList())
+		templatedPolicies = structs.ACLTemplatedPolicies(identity.TemplatedPolicyList())
 	)
 
-	if len(policyIDs) == 0 && len(serviceIdentities) == 0 && len(roleIDs) == 0 && len(nodeIdentities) == 0 {
+	if len(policyIDs) == 0 && len(serviceIdentities) == 0 && len(roleIDs) == 0 && len(nodeIdentities) == 0 && len(templatedPolicies) == 0 {
 		// In this case the default policy will be all that is in effect.
 		return nil, nil
 	}
@@ -613,16 +623,19 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 		}
 		serviceIdentities = append(serviceIdentities, role.ServiceIdentities...)
 		nodeIdentities = append(nodeIdentities, role.NodeIdentityList()...)
+		templatedPolicies = append(templatedPolicies, role.TemplatedPolicies...)
 	}
 
 	// Now deduplicate any policies or service identities that occur more than once.
 	policyIDs = dedupeStringSlice(policyIDs)
 	serviceIdentities = serviceIdentities.Deduplicate()
 	nodeIdentities = nodeIdentities.Deduplicate()
+	templatedPolicies = templatedPolicies.Deduplicate()
 
 	// Generate synthetic policies for all service identities in effect.
 	syntheticPolicies := r.synthesizePoliciesForServiceIdentities(serviceIdentities, identity.EnterpriseMetadata())
 	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForNodeIdentities(nodeIdentities, identity.EnterpriseMetadata())...)
+	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForTemplatedPolicies(templatedPolicies, identity.EnterpriseMetadata())...)
 
 	// For the new ACLs policy replication is mandatory for correct operation on servers. Therefore
 	// we only attempt to resolve policies locally
@@ -634,12 +647,53 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 	policies = append(policies, syntheticPolicies...)
 	filtered := r.filterPoliciesByScope(policies)
 	if len(policies) > 0 && len(filtered) == 0 {
-		r.logger.Warn("ACL token used lacks permissions in this datacenter: its associated ACL policies, service identities, and/or node identities are scoped to other datacenters", "accessor_id", identity.ID(), "datacenter", r.config.Datacenter)
+		r.logger.Warn("ACL token used lacks permissions in this datacenter: its associated ACL policies, service identities, node identities, and/or templated policies are scoped to other datacenters", "accessor_id", identity.ID(), "datacenter", r.config.Datacenter)
 	}
 
 	return filtered, nil
 }
 
+// resolveServiceIdentitiesForIdentity returns the service identities of the
+// token and of its roles which are valid in this datacenter.
+func (r *ACLResolver) resolveServiceIdentitiesForIdentity(identity structs.ACLIdentity) (structs.ACLServiceIdentities, error) {
+	serviceIdentities := structs.ACLServiceIdentities(identity.ServiceIdentityList())
+
+	roles, err := r.collectRolesForIdentity(identity, identity.RoleIDs())
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		serviceIdentities = append(serviceIdentities, role.ServiceIdentities...)
+	}
+
+	var out structs.ACLServiceIdentities
+	for _, id := range serviceIdentities {
+		if len(id.Datacenters) == 0 || stringslice.Contains(id.Datacenters, r.config.Datacenter) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// VetServiceNode returns a permission denied error if the service identities
+// of the token restrict the registration of the service to other nodes.
+func (r *ACLResolver) VetServiceNode(authz resolver.Result, service, node string) error {
+	if authz.ACLIdentity == nil {
+		return nil
+	}
+
+	serviceIdentities, err := r.resolveServiceIdentitiesForIdentity(authz.ACLIdentity)
+	if err != nil {
+		return err
+	}
+	if !serviceIdentities.AllowsServiceOnNode(service, node) {
+		return acl.PermissionDeniedError{
+			Cause: fmt.Sprintf("The service identities of the token do not permit registering service %q on node %q", service, node),
+		}
+	}
+	return nil
+}
+
 func (r *ACLResolver) synthesizePoliciesForServiceIdentities(serviceIdentities []*structs.ACLServiceIdentity, entMeta *acl.EnterpriseMeta) []*structs.ACLPolicy {
 	if len(serviceIdentities) == 0 {
 		return nil
@@ -666,6 +720,26 @@ func (r *ACLResolver) synthesizePoliciesForNodeIdentities(nodeIdentities []*stru
 	return syntheticPolicies
 }
 
+func (r *ACLResolver) synthesizePoliciesForTemplatedPolicies(templatedPolicies []*structs.ACLTemplatedPolicy, entMeta *acl.EnterpriseMeta) []*structs.ACLPolicy {
+	if len(templatedPolicies) == 0 {
+		return nil
+	}
+
+	syntheticPolicies := make([]*structs.ACLPolicy, 0, len(templatedPolicies))
+	for _, tp := range templatedPolicies {
+		policy, err := tp.SyntheticPolicy(entMeta)
+		if err != nil {
+			// This can only happen if the templated policy was replicated from
+			// a datacenter running a version with more templates.
+			r.logger.Warn("ignoring templated policy", "template", tp.TemplateName, "error", err)
+			continue
+		}
+		syntheticPolicies = append(syntheticPolicies, policy)
+	}
+
+	return syntheticPolicies
+}
+
 func mergeStringSlice(a, b []string) []string {
 	out := make([]string, 0, len(a)+len(b))
 	out = append(out, a...)
@@ -1057,6 +1131,93 @@ func (r *ACLResolver) ResolveToken(tokenSecretID string) (resolver.Result, error
 	return resolver.Result{Authorizer: acl.NewChainedAuthorizer(chain), ACLIdentity: identity}, nil
 }
 
+// explainToken reports why the token is, or isn't, authorized to make the
+// request: whether a rule of its policies or the default policy decided, and
+// which rule of which policies it was.
+func (r *ACLResolver) explainToken(tokenSecretID string, req structs.ACLAuthorizationRequest) (*structs.ACLExplainResponse, error) {
+	result, err := r.ResolveToken(tokenSecretID)
+	if err != nil {
+		return nil, err
+	}
+
+	var authzContext acl.AuthorizerContext
+	req.FillAuthzContext(&authzContext)
+	decision, err := acl.Enforce(result.Authorizer, req.Resource, req.Segment, req.Access, &authzContext)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &structs.ACLExplainResponse{
+		ACLAuthorizationRequest: req,
+		Allow:                   decision == acl.Allow,
+	}
+	if result.ACLIdentity != nil {
+		reply.AccessorID = result.ACLIdentity.ID()
+	}
+
+	if tokenSecretID == "" {
+		tokenSecretID = anonymousToken
+	}
+	if _, _, ok := r.resolveLocallyManagedToken(tokenSecretID); ok {
+		reply.DecidedBy = structs.ACLExplainDecidedByBuiltinToken
+		return reply, nil
+	}
+
+	identity, policies, err := r.resolveTokenToIdentityAndPolicies(tokenSecretID)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf acl.Config
+	if r.aclConf != nil {
+		conf = *r.aclConf
+	}
+	setEnterpriseConf(identity.EnterpriseMetadata(), &conf)
+
+	parsed := make([]*acl.Policy, 0, len(policies))
+	for _, policy := range policies {
+		p, err := acl.NewPolicyFromSource(policy.Rules, &conf, policy.EnterprisePolicyMeta())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %v", policy.Name, err)
+		}
+		parsed = append(parsed, p)
+	}
+
+	// The policies are the first authorizer of the chain, the default policy
+	// only decides when they don't.
+	authz, err := acl.NewPolicyAuthorizer(parsed, &conf)
+	if err != nil {
+		return nil, err
+	}
+	decision, err = acl.Enforce(authz, req.Resource, req.Segment, req.Access, &authzContext)
+	if err != nil {
+		return nil, err
+	}
+	if decision == acl.Default {
+		reply.DecidedBy = structs.ACLExplainDecidedByDefaultPolicy
+		return reply, nil
+	}
+	reply.DecidedBy = structs.ACLExplainDecidedByPolicy
+
+	rule, err := acl.ExplainRule(parsed, &conf, req.Resource, req.Segment)
+	if err != nil || rule == nil {
+		return reply, err
+	}
+	reply.Rule = rule.String()
+
+	// The merged rule comes from the policies with the same rule and access.
+	for i, p := range parsed {
+		own, err := acl.ExplainRule([]*acl.Policy{p}, &conf, req.Resource, req.Segment)
+		if err != nil {
+			return nil, err
+		}
+		if own != nil && *own == *rule {
+			reply.Policies = append(reply.Policies, structs.ACLTokenPolicyLink{ID: policies[i].ID, Name: policies[i].Name})
+		}
+	}
+	return reply, nil
+}
+
 func (r *ACLResolver) ACLsEnabled() bool {
 	// Whether we desire ACLs to be enabled according to configuration
 	if !r.config.ACLsEnabled {
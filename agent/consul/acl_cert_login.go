@@ -0,0 +1,118 @@
+package consul
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// clientCertTokenCache holds the tokens created for the client certificates
+// until they expire, so that the servers don't login on every request.
+type clientCertTokenCache struct {
+	lock   sync.Mutex
+	tokens map[[sha256.Size]byte]clientCertToken
+}
+
+type clientCertToken struct {
+	secretID  string
+	expiresAt time.Time
+}
+
+func newClientCertTokenCache() *clientCertTokenCache {
+	return &clientCertTokenCache{tokens: make(map[[sha256.Size]byte]clientCertToken)}
+}
+
+func (c *clientCertTokenCache) get(key [sha256.Size]byte, now time.Time) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	token, ok := c.tokens[key]
+	if !ok || !now.Before(token.expiresAt) {
+		return "", false
+	}
+	return token.secretID, true
+}
+
+func (c *clientCertTokenCache) put(key [sha256.Size]byte, token clientCertToken, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for k, t := range c.tokens {
+		if !now.Before(t.expiresAt) {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = token
+}
+
+// clientCertTokenRenewMargin is how long before its expiration a cached token
+// is replaced, so that it doesn't expire while a request is being served.
+const clientCertTokenRenewMargin = 30 * time.Second
+
+// ClientCertToken returns the secret of the ACL token of a client which
+// presented the given chain of certificates to the HTTPS or external gRPC
+// API, starting with its own certificate. The chain must have been verified
+// during the TLS handshake.
+//
+// The token is created by logging in with the auth method configured in
+// ACLClientCertAuthMethod. An empty string is returned when it isn't set.
+//
+// The servers login with their management token, which the servers of other
+// datacenters don't accept, so the logins are only supported in the primary
+// datacenter where the auth method can create both local and global tokens.
+func (s *Server) ClientCertToken(chain []*x509.Certificate) (string, error) {
+	authMethod := s.config.ACLClientCertAuthMethod
+	if authMethod == "" || !s.config.ACLsEnabled || len(chain) == 0 {
+		return "", nil
+	}
+	if !s.InPrimaryDatacenter() {
+		return "", fmt.Errorf("client certificate logins are only supported in the primary datacenter %q", s.config.PrimaryDatacenter)
+	}
+
+	now := time.Now()
+	key := sha256.Sum256(chain[0].Raw)
+	if secretID, ok := s.clientCertTokens.get(key, now); ok {
+		return secretID, nil
+	}
+
+	// The servers login with their management token, which ACL.Login
+	// requires for the TLS certificate auth methods.
+	mgmt, err := s.getSystemMetadata(structs.ServerManagementTokenAccessorID)
+	if err != nil {
+		return "", err
+	}
+	if mgmt == "" {
+		return "", errors.New("the server management token is not available yet")
+	}
+
+	args := structs.ACLLoginRequest{
+		Auth: &structs.ACLLoginParams{
+			AuthMethod:  authMethod,
+			BearerToken: certauth.EncodeChain(chain),
+			Meta:        map[string]string{"client_cert_serial": chain[0].SerialNumber.String()},
+		},
+		Datacenter:   s.config.Datacenter,
+		WriteRequest: structs.WriteRequest{Token: mgmt},
+	}
+	var token structs.ACLToken
+	if err := s.RPC(context.Background(), "ACL.Login", &args, &token); err != nil {
+		return "", fmt.Errorf("failed to login with the client certificate: %w", err)
+	}
+	if token.ExpirationTime == nil {
+		return "", fmt.Errorf("auth method %q created a token without expiration", authMethod)
+	}
+
+	expiresAt := token.ExpirationTime.Add(-clientCertTokenRenewMargin)
+	if leafExpiry := chain[0].NotAfter; leafExpiry.Before(expiresAt) {
+		expiresAt = leafExpiry
+	}
+	s.clientCertTokens.put(key, clientCertToken{secretID: token.SecretID, expiresAt: expiresAt}, now)
+	return token.SecretID, nil
+}
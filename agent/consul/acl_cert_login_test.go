@@ -0,0 +1,107 @@
+package consul
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestServer_ClientCertToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, func(c *Config) {
+		c.ACLClientCertAuthMethod = "tools"
+	}, false)
+	waitForLeaderEstablishment(t, srv)
+
+	root := connect.TestCA(t, nil)
+	certPEM, _ := connect.TestLeaf(t, "web", root)
+	leaf, err := connect.ParseCert(certPEM)
+	require.NoError(t, err)
+	chain := []*x509.Certificate{leaf}
+
+	setMethod := func(maxTokenTTL time.Duration) error {
+		_, err := upsertTestCustomizedAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+			method.Name = "tools"
+			method.Type = certauth.AuthMethodType
+			method.MaxTokenTTL = maxTokenTTL
+			method.Config = map[string]interface{}{
+				"CACerts": []string{root.RootCert},
+			}
+		})
+		return err
+	}
+
+	// The tokens must expire since the servers cache them.
+	testutil.RequireErrorContains(t, setMethod(0), "MaxTokenTTL is required")
+	require.NoError(t, setMethod(5*time.Minute))
+
+	_, err = upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", "tools",
+		"",
+		structs.BindingRuleBindTypeService,
+		"web",
+	)
+	require.NoError(t, err)
+
+	secretID, err := srv.ClientCertToken(chain)
+	require.NoError(t, err)
+	require.NotEmpty(t, secretID)
+
+	_, token, err := srv.fsm.State().ACLTokenGetBySecret(nil, secretID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	require.Equal(t, "tools", token.AuthMethod)
+	require.NotNil(t, token.ExpirationTime)
+	require.Equal(t, structs.ACLServiceIdentities{{ServiceName: "web"}}, token.ServiceIdentities)
+
+	// The token is reused until it expires.
+	again, err := srv.ClientCertToken(chain)
+	require.NoError(t, err)
+	require.Equal(t, secretID, again)
+
+	// The certificate chain isn't secret, only the servers may use it to
+	// login.
+	aclEp := ACL{srv: srv}
+	req := structs.ACLLoginRequest{
+		Auth: &structs.ACLLoginParams{
+			AuthMethod:  "tools",
+			BearerToken: certauth.EncodeChain(chain),
+		},
+		Datacenter: "dc1",
+	}
+	err = aclEp.Login(&req, &structs.ACLToken{})
+	testutil.RequireErrorContains(t, err, "only accepts the logins of the servers")
+
+	req.Token = TestDefaultInitialManagementToken
+	err = aclEp.Login(&req, &structs.ACLToken{})
+	testutil.RequireErrorContains(t, err, "do not provide a token when logging in")
+}
+
+func TestServer_ClientCertToken_SecondaryDatacenter(t *testing.T) {
+	root := connect.TestCA(t, nil)
+	certPEM, _ := connect.TestLeaf(t, "web", root)
+	leaf, err := connect.ParseCert(certPEM)
+	require.NoError(t, err)
+
+	srv := &Server{config: &Config{
+		Datacenter:              "dc2",
+		PrimaryDatacenter:       "dc1",
+		ACLsEnabled:             true,
+		ACLClientCertAuthMethod: "tools",
+	}}
+
+	_, err = srv.ClientCertToken([]*x509.Certificate{leaf})
+	testutil.RequireErrorContains(t, err, `client certificate logins are only supported in the primary datacenter "dc1"`)
+}
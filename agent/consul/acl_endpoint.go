@@ -2,6 +2,7 @@ package consul
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"github.com/hashicorp/consul/acl/resolver"
 	"github.com/hashicorp/consul/agent/consul/auth"
 	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
 	"github.com/hashicorp/consul/agent/consul/state"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/structs/aclfilter"
@@ -36,6 +38,14 @@ var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"acl", "token", "clone"},
 		Help: "",
 	},
+	{
+		Name: []string{"acl", "token", "rotate"},
+		Help: "",
+	},
+	{
+		Name: []string{"acl", "token", "usage_report"},
+		Help: "",
+	},
 	{
 		Name: []string{"acl", "token", "upsert"},
 		Help: "",
@@ -96,6 +106,14 @@ var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"acl", "logout"},
 		Help: "",
 	},
+	{
+		Name: []string{"acl", "oidc", "auth_url"},
+		Help: "",
+	},
+	{
+		Name: []string{"acl", "oidc", "callback"},
+		Help: "",
+	},
 }
 
 // ACL endpoint is used to manipulate ACLs
@@ -328,6 +346,11 @@ func (a *ACL) TokenRead(args *structs.ACLTokenGetRequest, reply *structs.ACLToke
 				return fmt.Errorf("token does not exist: %w", acl.ErrNotFound)
 			}
 
+			token, err = tokenWithUsage(state, token)
+			if err != nil {
+				return err
+			}
+
 			reply.Index, reply.Token = index, token
 			reply.SourceDatacenter = args.Datacenter
 
@@ -343,6 +366,23 @@ func (a *ACL) TokenRead(args *structs.ACLTokenGetRequest, reply *structs.ACLToke
 		})
 }
 
+// tokenWithUsage returns a copy of token with the time and address of its
+// last use filled in, if it was ever used. Changes to the usage of the token
+// don't unblock blocking queries, as they happen much more often than changes
+// to the token.
+func tokenWithUsage(state *state.Store, token *structs.ACLToken) (*structs.ACLToken, error) {
+	usage, err := state.ACLTokenUsageGet(nil, token.AccessorID)
+	if err != nil || usage == nil {
+		return token, err
+	}
+
+	token = token.Clone()
+	lastUsed := usage.LastUsedTime
+	token.LastUsedTime = &lastUsed
+	token.LastUsedAddr = usage.LastUsedAddr
+	return token, nil
+}
+
 func (a *ACL) lookupExpandedTokenInfo(ws memdb.WatchSet, state *state.Store, token *structs.ACLToken) (structs.ExpandedTokenInfo, error) {
 	policyIDs := make(map[string]struct{})
 	roleIDs := make(map[string]struct{})
@@ -365,6 +405,13 @@ func (a *ACL) lookupExpandedTokenInfo(ws memdb.WatchSet, state *state.Store, tok
 		policy := identity.SyntheticPolicy(&token.EnterpriseMeta)
 		identityPolicies[policy.ID] = policy
 	}
+	for _, tp := range token.TemplatedPolicies {
+		policy, err := tp.SyntheticPolicy(&token.EnterpriseMeta)
+		if err != nil {
+			return tokenInfo, err
+		}
+		identityPolicies[policy.ID] = policy
+	}
 
 	// Get any namespace default roles/policies to look up
 	nsPolicies, nsRoles, err := getTokenNamespaceDefaults(ws, state, &token.EnterpriseMeta)
@@ -402,6 +449,13 @@ func (a *ACL) lookupExpandedTokenInfo(ws memdb.WatchSet, state *state.Store, tok
 			policy := identity.SyntheticPolicy(&role.EnterpriseMeta)
 			identityPolicies[policy.ID] = policy
 		}
+		for _, tp := range role.TemplatedPolicies {
+			policy, err := tp.SyntheticPolicy(&role.EnterpriseMeta)
+			if err != nil {
+				return tokenInfo, err
+			}
+			identityPolicies[policy.ID] = policy
+		}
 
 		tokenInfo.ExpandedRoles = append(tokenInfo.ExpandedRoles, role)
 	}
@@ -483,6 +537,7 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 		Roles:             token.Roles,
 		ServiceIdentities: token.ServiceIdentities,
 		NodeIdentities:    token.NodeIdentities,
+		TemplatedPolicies: token.TemplatedPolicies,
 		Local:             token.Local,
 		Description:       token.Description,
 		ExpirationTime:    token.ExpirationTime,
@@ -500,6 +555,60 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 	return err
 }
 
+// TokenRotate issues a replacement for an existing token with the same
+// privileges. The existing token expires at the end of the overlap window.
+func (a *ACL) TokenRotate(args *structs.ACLTokenRotateRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	// clients will not know whether the server has local token store. In the case
+	// where it doesn't we will transparently forward requests.
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenRotate", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "rotate"}, time.Now())
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	_, token, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.AccessorID, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	} else if token == nil {
+		if ns := args.EnterpriseMeta.NamespaceOrEmpty(); ns != "" {
+			return fmt.Errorf("token not found in namespace %s: %w", ns, acl.ErrNotFound)
+		}
+		return fmt.Errorf("token does not exist: %w", acl.ErrNotFound)
+	} else if token.IsExpired(time.Now()) {
+		return fmt.Errorf("token is expired: %w", acl.ErrNotFound)
+	} else if !a.srv.InPrimaryDatacenter() && !token.Local {
+		// global token writes must be forwarded to the primary DC
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+		return a.srv.forwardDC("ACL.TokenRotate", a.srv.config.PrimaryDatacenter, args, reply)
+	}
+
+	updated, err := a.srv.aclTokenWriter().Rotate(args.AccessorID, args.OverlapWindow, &args.EnterpriseMeta)
+	if err == nil {
+		*reply = *updated
+	}
+	return err
+}
+
 func (a *ACL) TokenSet(args *structs.ACLTokenSetRequest, reply *structs.ACLToken) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -631,6 +740,76 @@ func (a *ACL) TokenDelete(args *structs.ACLTokenDeleteRequest, reply *string) er
 	return nil
 }
 
+// TokenBulk creates, updates and deletes many tokens at once. The operations
+// are validated and authorized individually, and the valid ones are applied
+// in a single Raft log entry. Unlike TokenSet and TokenDelete the request is
+// not forwarded to the primary datacenter for global tokens, so operations on
+// global tokens fail unless the request is sent to the primary datacenter.
+func (a *ACL) TokenBulk(args *structs.ACLTokenBulkRequest, reply *structs.ACLTokenBulkResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	for i := range args.Operations {
+		if err := a.srv.validateEnterpriseRequest(&args.Operations[i].Token.EnterpriseMeta, true); err != nil {
+			return fmt.Errorf("Operations[%d]: %w", i, err)
+		}
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenBulk", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "bulk"}, time.Now())
+
+	if len(args.Operations) == 0 {
+		return fmt.Errorf("Must provide at least one operation")
+	}
+	if !a.srv.canApplyACLTokenBulk() {
+		return fmt.Errorf("Bulk token operations are not supported until all servers are upgraded")
+	}
+
+	// Fail the whole request if the token is invalid, the operations are then
+	// authorized individually.
+	if _, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	}
+	authorize := func(entMeta *acl.EnterpriseMeta) error {
+		var authzContext acl.AuthorizerContext
+		authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, entMeta, &authzContext)
+		if err != nil {
+			return err
+		}
+		return authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext)
+	}
+
+	results, err := a.srv.aclTokenWriter().BulkWrite(args.Operations, args.Token, authorize)
+	if err != nil {
+		return err
+	}
+	reply.Results = results
+	return nil
+}
+
+// canApplyACLTokenBulk returns whether the bulk token operations are
+// accepted. They are only once every server of the datacenter can apply them,
+// otherwise the older servers would fail on their Raft entries. This is
+// remembered once true.
+func (s *Server) canApplyACLTokenBulk() bool {
+	if s.aclTokenBulkReady.Load() {
+		return true
+	}
+	if ok, found := ServersInDCSupportFeature(s, s.config.Datacenter, "tb"); !ok || !found {
+		return false
+	}
+	s.aclTokenBulkReady.Store(true)
+	return true
+}
+
 func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTokenListResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -676,6 +855,11 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 		methodMeta.Merge(&requestMeta)
 	}
 
+	filter, err := bexpr.CreateFilter(args.Filter, nil, reply.Tokens)
+	if err != nil {
+		return err
+	}
+
 	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
 			index, tokens, err := state.ACLTokenList(ws, args.IncludeLocal, args.IncludeGlobal, args.Policy, args.Role, args.AuthMethod, methodMeta, &args.EnterpriseMeta)
@@ -683,6 +867,13 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 				return err
 			}
 
+			// Changes to the usage of the tokens don't unblock the query, as
+			// they happen much more often than changes to the tokens.
+			usages, err := state.ACLTokenUsageList(nil)
+			if err != nil {
+				return err
+			}
+
 			now := time.Now()
 
 			stubs := make([]*structs.ACLTokenListStub, 0, len(tokens))
@@ -690,8 +881,20 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 				if token.IsExpired(now) {
 					continue
 				}
-				stubs = append(stubs, token.Stub())
+				stub := token.Stub()
+				if usage, ok := usages[token.AccessorID]; ok {
+					lastUsed := usage.LastUsedTime
+					stub.LastUsedTime = &lastUsed
+					stub.LastUsedAddr = usage.LastUsedAddr
+				}
+				stubs = append(stubs, stub)
+			}
+
+			raw, err := filter.Execute(stubs)
+			if err != nil {
+				return err
 			}
+			stubs = raw.([]*structs.ACLTokenListStub)
 
 			// filter down to just the tokens that the requester has permissions to read
 			a.srv.filterACLWithAuthorizer(authz, &stubs)
@@ -701,6 +904,79 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 		})
 }
 
+// TokenUsageReport records the tokens that were presented to the HTTP API of
+// an agent. Uses that are within ACLTokenUsageGranularity of the recorded
+// usage of a token are dropped, and the others are queued on the leader and
+// written to Raft periodically, so busy tokens and agents don't cause a Raft
+// write for every report.
+func (a *ACL) TokenUsageReport(args *structs.ACLTokenUsageReportRequest, reply *struct{}) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	// The usage is recorded where the tokens are stored, so when the server
+	// doesn't have a local token store it is recorded in the primary.
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenUsageReport", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "usage_report"}, time.Now())
+
+	// Agents report the usage with their own token, in the same way as they
+	// update their coordinates.
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().NodeWriteAllowed(args.Node, &authzContext); err != nil {
+		return err
+	}
+
+	state := a.srv.fsm.State()
+	now := time.Now()
+
+	var usages []*structs.ACLTokenUsage
+	for _, entry := range args.Entries {
+		_, token, err := state.ACLTokenGetBySecret(nil, entry.SecretID, nil)
+		if err != nil {
+			return err
+		}
+		if token == nil || token.IsExpired(now) {
+			continue
+		}
+
+		// Don't record uses in the future if the clock of the agent is ahead.
+		usedAt := entry.Time
+		if usedAt.After(now) {
+			usedAt = now
+		}
+
+		existing, err := state.ACLTokenUsageGet(nil, token.AccessorID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && usedAt.Sub(existing.LastUsedTime) < structs.ACLTokenUsageGranularity {
+			continue
+		}
+
+		usages = append(usages, &structs.ACLTokenUsage{
+			AccessorID:   token.AccessorID,
+			LastUsedTime: usedAt.UTC(),
+			LastUsedAddr: entry.Addr,
+		})
+	}
+	a.srv.queueACLTokenUsages(usages)
+	return nil
+}
+
 func (a *ACL) TokenBatchRead(args *structs.ACLTokenBatchGetRequest, reply *structs.ACLTokenBatchResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -1335,6 +1611,9 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 		if !acl.IsValidServiceIdentityName(svcid.ServiceName) {
 			return fmt.Errorf("Service identity %q has an invalid name. Only lowercase alphanumeric characters, '-' and '_' are allowed", svcid.ServiceName)
 		}
+		if svcid.Node != "" && svcid.NodePrefix != "" {
+			return fmt.Errorf("Service identity %q cannot specify both a node and a node prefix", svcid.ServiceName)
+		}
 	}
 	role.ServiceIdentities = role.ServiceIdentities.Deduplicate()
 
@@ -1351,6 +1630,13 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 	}
 	role.NodeIdentities = role.NodeIdentities.Deduplicate()
 
+	for _, tp := range role.TemplatedPolicies {
+		if err := tp.Validate(); err != nil {
+			return err
+		}
+	}
+	role.TemplatedPolicies = role.TemplatedPolicies.Deduplicate()
+
 	// calculate the hash for this role
 	role.SetHash(true)
 
@@ -1672,7 +1958,15 @@ func (a *ACL) BindingRuleSet(args *structs.ACLBindingRuleSetRequest, reply *stru
 		return fmt.Errorf("Invalid Binding Rule: unknown BindType %q", rule.BindType)
 	}
 
-	if valid, err := auth.IsValidBindName(rule.BindType, rule.BindName, blankID.ProjectedVarNames()); err != nil {
+	// Groups captured by the regular expressions of the Selector can be
+	// interpolated into the BindName too.
+	captureNames, err := auth.SelectorCaptureNames(rule.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid Binding Rule: Selector is invalid: %v", err)
+	}
+	availableVars := append(blankID.ProjectedVarNames(), captureNames...)
+
+	if valid, err := auth.IsValidBindName(rule.BindType, rule.BindName, availableVars); err != nil {
 		return fmt.Errorf("Invalid Binding Rule: invalid BindName: %v", err)
 	} else if !valid {
 		return fmt.Errorf("Invalid Binding Rule: invalid BindName")
@@ -1891,6 +2185,12 @@ func (a *ACL) AuthMethodSet(args *structs.ACLAuthMethodSetRequest, reply *struct
 		return fmt.Errorf("Invalid Auth Method: Type should be one of: %v", authmethod.Types())
 	}
 
+	// The servers cache the tokens created for the client certificates until
+	// they expire, so they must not live forever.
+	if method.Type == certauth.AuthMethodType && method.MaxTokenTTL == 0 {
+		return fmt.Errorf("Invalid Auth Method: MaxTokenTTL is required for the %s type", certauth.AuthMethodType)
+	}
+
 	if method.MaxTokenTTL != 0 {
 		if method.MaxTokenTTL > a.srv.config.ACLTokenMaxExpirationTTL {
 			return fmt.Errorf("MaxTokenTTL %s cannot be more than %s",
@@ -2064,7 +2364,10 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLToken) erro
 		return err
 	}
 
-	if args.Token != "" { // This shouldn't happen.
+	// Only the servers provide a token, when logging in on behalf of the
+	// clients whose certificate they verified, see Server.ClientCertToken.
+	serverLogin := args.Token != ""
+	if serverLogin && !a.srv.isServerManagementToken(args.Token) {
 		return errors.New("do not provide a token when logging in")
 	}
 
@@ -2079,6 +2382,12 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLToken) erro
 		return err
 	}
 
+	// The certificate chain is not secret, only the servers which verified
+	// the client holds its private key may use it to login.
+	if authMethod.Type == certauth.AuthMethodType && !serverLogin {
+		return fmt.Errorf("auth method %q only accepts the logins of the servers: %w", authMethod.Name, acl.ErrPermissionDenied)
+	}
+
 	verifiedIdentity, err := validator.ValidateLogin(context.Background(), args.Auth.BearerToken)
 	if err != nil {
 		return err
@@ -2130,6 +2439,158 @@ func (a *ACL) Logout(args *structs.ACLLogoutRequest, reply *bool) error {
 	return nil
 }
 
+// oidcValidator is implemented by the validators of the auth methods that
+// support the OIDC authorization code flow.
+type oidcValidator interface {
+	authmethod.Validator
+	GetAuthCodeURL(ctx context.Context, redirectURI string, statePayload interface{}) (string, error)
+	ClaimsFromAuthCode(ctx context.Context, state, code string) (*authmethod.Identity, interface{}, error)
+}
+
+// oidcStatePayload is kept by the validator between the two steps of the OIDC
+// flow. When the client provided a nonce, the callback can only be completed
+// by presenting it again.
+type oidcStatePayload struct {
+	ClientNonce string
+	Meta        map[string]string
+}
+
+// loadOIDCAuthMethod is like loadAuthMethod but requires the auth method to be
+// of type oidc.
+func (a *ACL) loadOIDCAuthMethod(methodName string, entMeta *acl.EnterpriseMeta) (*structs.ACLAuthMethod, oidcValidator, error) {
+	authMethod, validator, err := a.srv.loadAuthMethod(methodName, entMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oidcV, ok := validator.(oidcValidator)
+	if authMethod.Type != "oidc" || !ok {
+		return nil, nil, fmt.Errorf("auth method %q is not of type %q", methodName, "oidc")
+	}
+	return authMethod, oidcV, nil
+}
+
+// OIDCAuthURL starts the OIDC login flow and returns the URL of the provider
+// the user must authenticate with.
+//
+// The state of the flow is kept in memory by the validator of the leader, so
+// both this and OIDCCallback are always forwarded to it.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDCAuthURL request: Missing auth parameters")
+	}
+	if args.Auth.AuthMethod == "" {
+		return fmt.Errorf("Invalid OIDCAuthURL request: Missing auth method name")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.OIDCAuthURL", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "auth_url"}, time.Now())
+
+	_, validator, err := a.loadOIDCAuthMethod(args.Auth.AuthMethod, &args.Auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	// Validate the meta now so that a bad value fails before the user goes
+	// through the provider.
+	if _, err := auth.BuildTokenDescription("token created via OIDC login", args.Auth.Meta); err != nil {
+		return err
+	}
+
+	payload := &oidcStatePayload{
+		ClientNonce: args.Auth.ClientNonce,
+		Meta:        args.Auth.Meta,
+	}
+	authURL, err := validator.GetAuthCodeURL(context.Background(), args.Auth.RedirectURI, payload)
+	if err != nil {
+		return err
+	}
+
+	reply.AuthURL = authURL
+	return nil
+}
+
+// OIDCCallback completes the OIDC login flow started by OIDCAuthURL and
+// returns the token created for the user.
+func (a *ACL) OIDCCallback(args *structs.ACLOIDCCallbackRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDCCallback request: Missing auth parameters")
+	}
+	if args.Auth.AuthMethod == "" {
+		return fmt.Errorf("Invalid OIDCCallback request: Missing auth method name")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.OIDCCallback", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "callback"}, time.Now())
+
+	authMethod, validator, err := a.loadOIDCAuthMethod(args.Auth.AuthMethod, &args.Auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	verifiedIdentity, rawPayload, err := validator.ClaimsFromAuthCode(context.Background(), args.Auth.State, args.Auth.Code)
+	if err != nil {
+		return err
+	}
+
+	payload, ok := rawPayload.(*oidcStatePayload)
+	if !ok {
+		return fmt.Errorf("Invalid OIDCCallback request: Unexpected OIDC state")
+	}
+	if payload.ClientNonce != "" && subtle.ConstantTimeCompare([]byte(payload.ClientNonce), []byte(args.Auth.ClientNonce)) != 1 {
+		return fmt.Errorf("Invalid OIDCCallback request: Client nonce does not match")
+	}
+
+	description, err := auth.BuildTokenDescription("token created via OIDC login", payload.Meta)
+	if err != nil {
+		return err
+	}
+
+	token, err := a.srv.aclLogin().TokenForVerifiedIdentity(verifiedIdentity, authMethod, description)
+	if err == nil {
+		*reply = *token
+	}
+	return err
+}
+
 func (a *ACL) Authorize(args *structs.RemoteACLAuthorizationRequest, reply *[]structs.ACLAuthorizationResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -2152,3 +2613,45 @@ func (a *ACL) Authorize(args *structs.RemoteACLAuthorizationRequest, reply *[]st
 	*reply = responses
 	return nil
 }
+
+// Explain reports why a token is, or isn't, authorized to access a resource,
+// to help debugging authorization failures. The token of the request is
+// explained unless the accessor of another token is given, which requires
+// acl:read.
+func (a *ACL) Explain(args *structs.ACLExplainRequest, reply *structs.ACLExplainResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.Explain", args, reply); done {
+		return err
+	}
+
+	secretID := args.Token
+	if args.AccessorID != "" {
+		var authzContext acl.AuthorizerContext
+		authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+		if err != nil {
+			return err
+		}
+		if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+			return err
+		}
+
+		_, token, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.AccessorID, &args.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return fmt.Errorf("token %q: %w", args.AccessorID, acl.ErrNotFound)
+		}
+		secretID = token.SecretID
+	}
+
+	explained, err := a.srv.ACLResolver.explainToken(secretID, args.ACLAuthorizationRequest)
+	if err != nil {
+		return err
+	}
+	*reply = *explained
+	return nil
+}
@@ -2,6 +2,7 @@ package consul
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -1915,6 +1916,275 @@ func TestACLEndpoint_TokenList(t *testing.T) {
 			require.Equal(t, aclfilter.RedactedToken, token.SecretID)
 		}
 	})
+
+	t.Run("filter expression", func(t *testing.T) {
+		req := structs.ACLTokenListRequest{
+			Datacenter: "dc1",
+			QueryOptions: structs.QueryOptions{
+				Token:  TestDefaultInitialManagementToken,
+				Filter: fmt.Sprintf("AccessorID == %q", t1.AccessorID),
+			},
+		}
+
+		resp := structs.ACLTokenListResponse{}
+
+		err = aclEp.TokenList(&req, &resp)
+		require.NoError(t, err)
+		require.ElementsMatch(t, gatherIDs(t, resp.Tokens), []string{t1.AccessorID})
+	})
+}
+
+func TestACLEndpoint_TokenBulk(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	policy, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
+	require.NoError(t, err)
+
+	existing, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+	require.NoError(t, err)
+
+	t.Run("operations", func(t *testing.T) {
+		req := structs.ACLTokenBulkRequest{
+			Datacenter: "dc1",
+			Operations: []structs.ACLTokenBulkOperation{
+				{
+					Op:    structs.ACLTokenBulkCreate,
+					Token: structs.ACLToken{Description: "created"},
+				},
+				{
+					Op: structs.ACLTokenBulkAttachPolicies,
+					Token: structs.ACLToken{
+						AccessorID: existing.AccessorID,
+						Policies:   []structs.ACLTokenPolicyLink{{ID: policy.ID}},
+					},
+				},
+				{
+					Op:    structs.ACLTokenBulkDelete,
+					Token: structs.ACLToken{AccessorID: acl.AnonymousTokenID},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+
+		var resp structs.ACLTokenBulkResponse
+		require.NoError(t, aclEp.TokenBulk(&req, &resp))
+		require.Len(t, resp.Results, 3)
+
+		require.Empty(t, resp.Results[0].Error)
+		created, err := retrieveTestToken(codec, TestDefaultInitialManagementToken, "dc1", resp.Results[0].AccessorID)
+		require.NoError(t, err)
+		require.Equal(t, "created", created.Token.Description)
+
+		require.Empty(t, resp.Results[1].Error)
+		updated, err := retrieveTestToken(codec, TestDefaultInitialManagementToken, "dc1", existing.AccessorID)
+		require.NoError(t, err)
+		require.Len(t, updated.Token.Policies, len(existing.Policies)+1)
+
+		require.Equal(t, "Delete operation not permitted on the anonymous token", resp.Results[2].Error)
+	})
+
+	t.Run("no operations", func(t *testing.T) {
+		req := structs.ACLTokenBulkRequest{
+			Datacenter:   "dc1",
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+
+		var resp structs.ACLTokenBulkResponse
+		require.EqualError(t, aclEp.TokenBulk(&req, &resp), "Must provide at least one operation")
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		readOnly, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `acl = "read"`)
+		require.NoError(t, err)
+
+		req := structs.ACLTokenBulkRequest{
+			Datacenter: "dc1",
+			Operations: []structs.ACLTokenBulkOperation{
+				{
+					Op:    structs.ACLTokenBulkDelete,
+					Token: structs.ACLToken{AccessorID: existing.AccessorID},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: readOnly.SecretID},
+		}
+
+		var resp structs.ACLTokenBulkResponse
+		require.NoError(t, aclEp.TokenBulk(&req, &resp))
+		require.Contains(t, resp.Results[0].Error, "Permission denied")
+
+		token, err := retrieveTestToken(codec, TestDefaultInitialManagementToken, "dc1", existing.AccessorID)
+		require.NoError(t, err)
+		require.NotNil(t, token.Token)
+	})
+}
+
+func TestACLEndpoint_TokenBulk_MixedVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1, codec := testACLServerWithConfig(t, nil, false)
+	defer codec.Close()
+
+	dir2, s2 := testServerWithConfig(t, testServerACLConfig, func(c *Config) {
+		c.Bootstrap = false
+		c.OverrideInitialSerfTags = func(tags map[string]string) {
+			delete(tags, "ft_tb")
+		}
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	waitForLeaderEstablishment(t, s1)
+	retry.Run(t, func(r *retry.R) {
+		if ok, _ := ServersInDCSupportFeature(s1, "dc1", "tb"); ok {
+			r.Fatal("expected the older server to be known")
+		}
+	})
+
+	req := structs.ACLTokenBulkRequest{
+		Datacenter: "dc1",
+		Operations: []structs.ACLTokenBulkOperation{
+			{
+				Op:    structs.ACLTokenBulkCreate,
+				Token: structs.ACLToken{Description: "created"},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+
+	// The older server can't apply the operations, so they are rejected.
+	var resp structs.ACLTokenBulkResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.TokenBulk", &req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not supported until all servers are upgraded")
+
+	// Once it is upgraded the operations are applied.
+	updateSerfTags(s2, "ft_tb", "1")
+	retry.Run(t, func(r *retry.R) {
+		var resp structs.ACLTokenBulkResponse
+		require.NoError(r, msgpackrpc.CallWithCodec(codec, "ACL.TokenBulk", &req, &resp))
+		require.Len(r, resp.Results, 1)
+	})
+}
+
+func TestACLEndpoint_TokenUsageReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+	require.NoError(t, err)
+
+	report := func(t *testing.T, secretID, addr string, usedAt time.Time) {
+		req := structs.ACLTokenUsageReportRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Entries: []structs.ACLTokenUsageEntry{
+				{SecretID: secretID, Time: usedAt, Addr: addr},
+				// Unknown secrets are ignored.
+				{SecretID: "b5a4a0fa-6fb5-4b1c-a0a3-54e8c2b2b5f1", Time: usedAt, Addr: addr},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		require.NoError(t, aclEp.TokenUsageReport(&req, &struct{}{}))
+		require.NoError(t, srv.flushACLTokenUsages())
+	}
+
+	read := func(t *testing.T) *structs.ACLToken {
+		req := structs.ACLTokenGetRequest{
+			Datacenter:   "dc1",
+			TokenID:      token.AccessorID,
+			TokenIDType:  structs.ACLTokenAccessor,
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTokenResponse{}
+		require.NoError(t, aclEp.TokenRead(&req, &resp))
+		return resp.Token
+	}
+
+	// The token was never used.
+	require.Nil(t, read(t).LastUsedTime)
+
+	usedAt := time.Now().Add(-3 * structs.ACLTokenUsageGranularity).UTC().Round(0)
+	report(t, token.SecretID, "10.0.0.1", usedAt)
+
+	rtoken := read(t)
+	require.NotNil(t, rtoken.LastUsedTime)
+	require.True(t, usedAt.Equal(*rtoken.LastUsedTime))
+	require.Equal(t, "10.0.0.1", rtoken.LastUsedAddr)
+
+	// Uses within the granularity of the recorded one are dropped.
+	report(t, token.SecretID, "10.0.0.2", usedAt.Add(structs.ACLTokenUsageGranularity/2))
+	rtoken = read(t)
+	require.True(t, usedAt.Equal(*rtoken.LastUsedTime))
+	require.Equal(t, "10.0.0.1", rtoken.LastUsedAddr)
+
+	// Later uses are recorded, and also returned when listing.
+	usedAt = usedAt.Add(structs.ACLTokenUsageGranularity)
+	report(t, token.SecretID, "10.0.0.3", usedAt)
+
+	req := structs.ACLTokenListRequest{
+		Datacenter:   "dc1",
+		QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+	}
+	resp := structs.ACLTokenListResponse{}
+	require.NoError(t, aclEp.TokenList(&req, &resp))
+	var found bool
+	for _, stub := range resp.Tokens {
+		if stub.AccessorID != token.AccessorID {
+			continue
+		}
+		found = true
+		require.NotNil(t, stub.LastUsedTime)
+		require.True(t, usedAt.Equal(*stub.LastUsedTime))
+		require.Equal(t, "10.0.0.3", stub.LastUsedAddr)
+	}
+	require.True(t, found)
+
+	// Uses reported before a flush are coalesced, keeping the latest one.
+	usedAt = usedAt.Add(structs.ACLTokenUsageGranularity)
+	srv.queueACLTokenUsages([]*structs.ACLTokenUsage{
+		{AccessorID: token.AccessorID, LastUsedTime: usedAt, LastUsedAddr: "10.0.0.4"},
+	})
+	srv.queueACLTokenUsages([]*structs.ACLTokenUsage{
+		{AccessorID: token.AccessorID, LastUsedTime: usedAt.Add(-time.Minute), LastUsedAddr: "10.0.0.5"},
+	})
+	require.NoError(t, srv.flushACLTokenUsages())
+	rtoken = read(t)
+	require.True(t, usedAt.Equal(*rtoken.LastUsedTime))
+	require.Equal(t, "10.0.0.4", rtoken.LastUsedAddr)
+
+	t.Run("requires node write", func(t *testing.T) {
+		req := structs.ACLTokenUsageReportRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Entries: []structs.ACLTokenUsageEntry{
+				{SecretID: token.SecretID, Time: time.Now()},
+			},
+		}
+		err := aclEp.TokenUsageReport(&req, &struct{}{})
+		require.True(t, acl.IsErrPermissionDenied(err))
+	})
 }
 
 func TestACLEndpoint_TokenBatchRead(t *testing.T) {
@@ -5244,6 +5514,151 @@ func TestACLEndpoint_Login_jwt(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	const redirectURI = "http://localhost:8550/oidc/callback"
+
+	// spin up a fake oidc server
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("abc", "def")
+	oidcServer.SetAllowedRedirectURIs([]string{redirectURI})
+	oidcServer.SetExpectedAuthCode("code")
+	oidcServer.DisableUserInfo()
+
+	method, err := upsertTestCustomizedAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "oidc"
+		method.Config = map[string]interface{}{
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "abc",
+			"OIDCClientSecret":    "def",
+			"BoundAudiences":      []string{"abc"},
+			"AllowedRedirectURIs": []string{redirectURI},
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"ClaimMappings": map[string]string{
+				"COLOR": "color",
+			},
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", method.Name,
+		"value.color == green",
+		structs.BindingRuleBindTypeService,
+		"web",
+	)
+	require.NoError(t, err)
+
+	// startLogin returns the state of a new login flow, and makes the provider
+	// reply with an ID token for it.
+	startLogin := func(t *testing.T, clientNonce string) string {
+		t.Helper()
+
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  method.Name,
+				RedirectURI: redirectURI,
+				ClientNonce: clientNonce,
+				Meta:        map[string]string{"host": "laptop"},
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLOIDCAuthURLResponse
+		require.NoError(t, aclEp.OIDCAuthURL(&req, &resp))
+		require.True(t, strings.HasPrefix(resp.AuthURL, oidcServer.Addr()+"/auth?"))
+
+		authURL, err := url.Parse(resp.AuthURL)
+		require.NoError(t, err)
+		oidcServer.SetCustomClaims(map[string]interface{}{
+			"nonce": authURL.Query().Get("nonce"),
+			"COLOR": "green",
+		})
+		return authURL.Query().Get("state")
+	}
+
+	t.Run("not an oidc auth method", func(t *testing.T) {
+		testSessionID := testauth.StartSession()
+		defer testauth.ResetSession(testSessionID)
+
+		other, err := upsertTestAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", testSessionID)
+		require.NoError(t, err)
+
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  other.Name,
+				RedirectURI: redirectURI,
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLOIDCAuthURLResponse
+		testutil.RequireErrorContains(t, aclEp.OIDCAuthURL(&req, &resp), `is not of type "oidc"`)
+	})
+
+	t.Run("unauthorized redirect uri", func(t *testing.T) {
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  method.Name,
+				RedirectURI: "http://evil.example.com/oidc/callback",
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLOIDCAuthURLResponse
+		testutil.RequireErrorContains(t, aclEp.OIDCAuthURL(&req, &resp), "unauthorized redirect_uri")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		state := startLogin(t, "nonce")
+
+		req := structs.ACLOIDCCallbackRequest{
+			Auth: &structs.ACLOIDCCallbackParams{
+				AuthMethod:  method.Name,
+				State:       state,
+				Code:        "code",
+				ClientNonce: "nonce",
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLToken
+		require.NoError(t, aclEp.OIDCCallback(&req, &resp))
+
+		require.Equal(t, method.Name, resp.AuthMethod)
+		require.Equal(t, `token created via OIDC login: {"host":"laptop"}`, resp.Description)
+		require.True(t, resp.Local)
+		require.Len(t, resp.ServiceIdentities, 1)
+		require.Equal(t, "web", resp.ServiceIdentities[0].ServiceName)
+
+		// The state can only be used once.
+		testutil.RequireErrorContains(t, aclEp.OIDCCallback(&req, &resp), "Expired or missing OAuth state")
+	})
+
+	t.Run("client nonce mismatch", func(t *testing.T) {
+		state := startLogin(t, "nonce")
+
+		req := structs.ACLOIDCCallbackRequest{
+			Auth: &structs.ACLOIDCCallbackParams{
+				AuthMethod:  method.Name,
+				State:       state,
+				Code:        "code",
+				ClientNonce: "other",
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLToken
+		testutil.RequireErrorContains(t, aclEp.OIDCCallback(&req, &resp), "Client nonce does not match")
+	})
+}
+
 func TestACLEndpoint_Logout(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -5329,6 +5744,97 @@ func TestACLEndpoint_Logout(t *testing.T) {
 	})
 }
 
+func TestACLEndpoint_Explain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	policy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `
+		key_prefix "" {
+			policy = "read"
+		}
+		key_prefix "foo/" {
+			policy = "deny"
+		}
+	`)
+	require.NoError(t, err)
+
+	token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+		token.Policies = []structs.ACLTokenPolicyLink{{ID: policy.ID}}
+	})
+	require.NoError(t, err)
+
+	explain := func(secretID, accessorID string, req structs.ACLAuthorizationRequest) (*structs.ACLExplainResponse, error) {
+		args := structs.ACLExplainRequest{
+			Datacenter:              "dc1",
+			AccessorID:              accessorID,
+			ACLAuthorizationRequest: req,
+			QueryOptions:            structs.QueryOptions{Token: secretID},
+		}
+		var out structs.ACLExplainResponse
+		err := msgpackrpc.CallWithCodec(codec, "ACL.Explain", &args, &out)
+		return &out, err
+	}
+
+	t.Run("policy", func(t *testing.T) {
+		out, err := explain(token.SecretID, "", structs.ACLAuthorizationRequest{
+			Resource: acl.ResourceKey,
+			Segment:  "foo/bar",
+			Access:   "read",
+		})
+		require.NoError(t, err)
+		require.False(t, out.Allow)
+		require.Equal(t, token.AccessorID, out.AccessorID)
+		require.Equal(t, structs.ACLExplainDecidedByPolicy, out.DecidedBy)
+		require.Equal(t, `key_prefix "foo/" { policy = "deny" }`, out.Rule)
+		require.Equal(t, []structs.ACLTokenPolicyLink{{ID: policy.ID, Name: policy.Name}}, out.Policies)
+	})
+
+	t.Run("default policy", func(t *testing.T) {
+		out, err := explain(token.SecretID, "", structs.ACLAuthorizationRequest{
+			Resource: acl.ResourceNode,
+			Segment:  "web-1",
+			Access:   "read",
+		})
+		require.NoError(t, err)
+		require.False(t, out.Allow)
+		require.Equal(t, structs.ACLExplainDecidedByDefaultPolicy, out.DecidedBy)
+		require.Empty(t, out.Rule)
+		require.Empty(t, out.Policies)
+	})
+
+	t.Run("other token requires acl read", func(t *testing.T) {
+		req := structs.ACLAuthorizationRequest{
+			Resource: acl.ResourceKey,
+			Segment:  "bar",
+			Access:   "read",
+		}
+		_, err := explain(token.SecretID, token.AccessorID, req)
+		require.True(t, acl.IsErrPermissionDenied(err))
+
+		out, err := explain(TestDefaultInitialManagementToken, token.AccessorID, req)
+		require.NoError(t, err)
+		require.True(t, out.Allow)
+		require.Equal(t, token.AccessorID, out.AccessorID)
+		require.Equal(t, structs.ACLExplainDecidedByPolicy, out.DecidedBy)
+		require.Equal(t, `key_prefix "" { policy = "read" }`, out.Rule)
+	})
+
+	t.Run("unknown accessor", func(t *testing.T) {
+		_, err := explain(TestDefaultInitialManagementToken, "3a4d8a2e-5c7f-4b43-9bd4-cbd2ad6c3f0e", structs.ACLAuthorizationRequest{
+			Resource: acl.ResourceKey,
+			Segment:  "bar",
+			Access:   "read",
+		})
+		testutil.RequireErrorContains(t, err, acl.ErrNotFound.Error())
+	})
+}
+
 func gatherIDs(t *testing.T, v interface{}) []string {
 	t.Helper()
 
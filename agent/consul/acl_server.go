@@ -110,6 +110,12 @@ type serverACLResolverBackend struct {
 }
 
 func (s *serverACLResolverBackend) IsServerManagementToken(token string) bool {
+	return s.isServerManagementToken(token)
+}
+
+// isServerManagementToken returns true if the token is the management token
+// the servers of the datacenter use to call their own APIs.
+func (s *Server) isServerManagementToken(token string) bool {
 	mgmt, err := s.getSystemMetadata(structs.ServerManagementTokenAccessorID)
 	if err != nil {
 		s.logger.Debug("failed to fetch server management token: %w", err)
@@ -0,0 +1,80 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// queueACLTokenUsages records token usages to be written to Raft by the next
+// flush, keeping only the latest use of each token.
+func (s *Server) queueACLTokenUsages(usages []*structs.ACLTokenUsage) {
+	s.aclTokenUsagesLock.Lock()
+	defer s.aclTokenUsagesLock.Unlock()
+
+	if s.aclTokenUsages == nil {
+		s.aclTokenUsages = make(map[string]*structs.ACLTokenUsage)
+	}
+	for _, usage := range usages {
+		if pending, ok := s.aclTokenUsages[usage.AccessorID]; ok && !usage.LastUsedTime.After(pending.LastUsedTime) {
+			continue
+		}
+		s.aclTokenUsages[usage.AccessorID] = usage
+	}
+}
+
+// flushACLTokenUsages writes the pending token usages to Raft in a single
+// request.
+func (s *Server) flushACLTokenUsages() error {
+	// Grab the pending usages and release the lock so we can still handle
+	// incoming reports.
+	s.aclTokenUsagesLock.Lock()
+	pending := s.aclTokenUsages
+	s.aclTokenUsages = nil
+	s.aclTokenUsagesLock.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usages := make([]*structs.ACLTokenUsage, 0, len(pending))
+	for _, usage := range pending {
+		usages = append(usages, usage)
+	}
+
+	// We set the "safe to ignore" flag on this update type so old servers
+	// don't crash if they see one of these.
+	t := structs.ACLTokenUsageSetRequestType | structs.IgnoreUnknownTypeFlag
+
+	_, err := s.raftApply(t, &structs.ACLTokenUsageBatchSetRequest{Usages: usages})
+	if err != nil {
+		return fmt.Errorf("Failed to apply token usage request: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) runACLTokenUsageFlush(ctx context.Context) error {
+	ticker := time.NewTicker(aclTokenUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.flushACLTokenUsages(); err != nil {
+				s.logger.Warn("failed to flush ACL token usages", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Server) startACLTokenUsageFlush(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, aclTokenUsageFlushRoutineName, s.runACLTokenUsageFlush)
+}
+
+func (s *Server) stopACLTokenUsageFlush() {
+	s.leaderRoutineManager.Stop(aclTokenUsageFlushRoutineName)
+}
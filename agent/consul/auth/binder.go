@@ -84,9 +84,21 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 	}
 
 	// Compute role, service identity, or node identity names by interpolating
-	// the identity's projected variables into the rule BindName templates.
+	// the identity's projected variables, and the groups captured by the rule
+	// Selector, into the rule BindName templates.
 	for _, rule := range matchingRules {
-		bindName, valid, err := computeBindName(rule.BindType, rule.BindName, verifiedIdentity.ProjectedVars)
+		vars := verifiedIdentity.ProjectedVars
+		if captures := selectorCaptures(rule.Selector, verifiedIdentity.SelectableFields); len(captures) != 0 {
+			vars = make(map[string]string, len(verifiedIdentity.ProjectedVars)+len(captures))
+			for k, v := range verifiedIdentity.ProjectedVars {
+				vars[k] = v
+			}
+			for k, v := range captures {
+				vars[k] = v
+			}
+		}
+
+		bindName, valid, err := computeBindName(rule.BindType, rule.BindName, vars)
 		switch {
 		case err != nil:
 			return nil, fmt.Errorf("cannot compute %q bind name for bind target: %w", rule.BindType, err)
@@ -93,6 +93,72 @@ func TestBinder_Roles_Success(t *testing.T) {
 	}, result.Roles)
 }
 
+func TestBinder_SelectorCaptures(t *testing.T) {
+	store := testStateStore(t)
+	binder := &Binder{store: store}
+
+	authMethod := &structs.ACLAuthMethod{
+		Name: "test-auth-method",
+		Type: "testing",
+	}
+	require.NoError(t, store.ACLAuthMethodSet(0, authMethod))
+
+	bindingRules := structs.ACLBindingRules{
+		{
+			ID:         generateID(t),
+			Selector:   `app matches "^(?P<name>[a-z]+)-(v[0-9]+)$"`,
+			BindType:   structs.BindingRuleBindTypeService,
+			BindName:   "${match.name}-${match.2}",
+			AuthMethod: authMethod.Name,
+		},
+		{
+			ID:         generateID(t),
+			Selector:   `team matches "^(.+)-team$" and not (app matches "^(admin)")`,
+			BindType:   structs.BindingRuleBindTypeNode,
+			BindName:   "${match.1}-${editor}",
+			AuthMethod: authMethod.Name,
+		},
+		{
+			ID:         generateID(t),
+			Selector:   `app matches "^(?P<name>[0-9]+)$"`,
+			BindType:   structs.BindingRuleBindTypeService,
+			BindName:   "never-${match.name}",
+			AuthMethod: authMethod.Name,
+		},
+	}
+	require.NoError(t, store.ACLBindingRuleBatchSet(0, bindingRules))
+
+	result, err := binder.Bind(&structs.ACLAuthMethod{}, &authmethod.Identity{
+		SelectableFields: map[string]string{
+			"app":  "billing-v2",
+			"team": "payments-team",
+		},
+		ProjectedVars: map[string]string{
+			"editor": "vim",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*structs.ACLServiceIdentity{
+		{ServiceName: "billing-v2"},
+	}, result.ServiceIdentities)
+	require.Equal(t, []*structs.ACLNodeIdentity{
+		{NodeName: "payments-vim"},
+	}, result.NodeIdentities)
+}
+
+func TestSelectorCaptureNames(t *testing.T) {
+	names, err := SelectorCaptureNames(`a matches "^(?P<name>x)-(y)$" and b == c and not (d matches "(z)") and e matches "^(w)$"`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"match.1", "match.name", "match.2"}, names)
+
+	names, err = SelectorCaptureNames("")
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	_, err = SelectorCaptureNames(`a matches "("`)
+	require.Error(t, err)
+}
+
 func TestBinder_Roles_NameValidation(t *testing.T) {
 	store := testStateStore(t)
 	binder := &Binder{store: store}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/go-bexpr"
+)
+
+// captureVarPrefix is the prefix of the variables holding the groups captured
+// by the regular expressions of a binding rule selector.
+const captureVarPrefix = "match."
+
+// SelectorCaptureNames returns the names of the variables holding the groups
+// captured by the "matches" expressions of a binding rule selector, which can
+// be interpolated into the rule's BindName in addition to the projected vars
+// of the auth method. Each group is available by index as "match.N" and, if
+// it is a named group, by name as "match.NAME".
+func SelectorCaptureNames(selector string) ([]string, error) {
+	exprs, err := captureExpressions(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, expr := range exprs {
+		for _, name := range captureNames(expr.re) {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// selectorCaptures returns the groups captured by the "matches" expressions of
+// selector against selectableVars. Every variable returned by
+// SelectorCaptureNames is set, to an empty string if its group didn't match.
+// When several expressions capture the same variable, the leftmost expression
+// that matched wins.
+func selectorCaptures(selector string, selectableVars interface{}) map[string]string {
+	exprs, err := captureExpressions(selector)
+	if err != nil || len(exprs) == 0 {
+		return nil
+	}
+
+	fields, err := bexpr.GenerateFieldConfigurations(selectableVars)
+	if err != nil {
+		return nil
+	}
+
+	captures := make(map[string]string)
+	for _, expr := range exprs {
+		for _, name := range captureNames(expr.re) {
+			captures[name] = ""
+		}
+	}
+
+	captured := make(map[string]struct{})
+	for _, expr := range exprs {
+		for _, value := range selectorValues(reflect.ValueOf(selectableVars), expr.selector, fields) {
+			groups := expr.re.FindStringSubmatch(value)
+			if groups == nil {
+				continue
+			}
+			for i, name := range expr.re.SubexpNames() {
+				if i == 0 {
+					continue
+				}
+				vars := []string{captureVarPrefix + strconv.Itoa(i)}
+				if name != "" {
+					vars = append(vars, captureVarPrefix+name)
+				}
+				for _, v := range vars {
+					if _, ok := captured[v]; !ok {
+						captured[v] = struct{}{}
+						captures[v] = groups[i]
+					}
+				}
+			}
+			break
+		}
+	}
+	return captures
+}
+
+type captureExpression struct {
+	selector bexpr.Selector
+	re       *regexp.Regexp
+}
+
+// captureExpressions returns the "matches" expressions of selector that can
+// capture groups, which excludes the expressions that are negated.
+func captureExpressions(selector string) ([]captureExpression, error) {
+	if selector == "" {
+		return nil, nil
+	}
+
+	ast, err := bexpr.Parse("", []byte(selector))
+	if err != nil {
+		return nil, err
+	}
+
+	var exprs []captureExpression
+	var walk func(node bexpr.Expression, negated bool) error
+	walk = func(node bexpr.Expression, negated bool) error {
+		switch node := node.(type) {
+		case *bexpr.UnaryExpression:
+			return walk(node.Operand, !negated)
+		case *bexpr.BinaryExpression:
+			if err := walk(node.Left, negated); err != nil {
+				return err
+			}
+			return walk(node.Right, negated)
+		case *bexpr.MatchExpression:
+			if negated || node.Operator != bexpr.MatchMatches || node.Value == nil {
+				return nil
+			}
+			re, err := regexp.Compile(node.Value.Raw)
+			if err != nil {
+				return fmt.Errorf("invalid regular expression %q: %w", node.Value.Raw, err)
+			}
+			if re.NumSubexp() > 0 {
+				exprs = append(exprs, captureExpression{selector: node.Selector, re: re})
+			}
+		}
+		return nil
+	}
+	if err := walk(ast.(bexpr.Expression), false); err != nil {
+		return nil, err
+	}
+	return exprs, nil
+}
+
+func captureNames(re *regexp.Regexp) []string {
+	var names []string
+	for i, name := range re.SubexpNames() {
+		if i == 0 {
+			continue
+		}
+		names = append(names, captureVarPrefix+strconv.Itoa(i))
+		if name != "" {
+			names = append(names, captureVarPrefix+name)
+		}
+	}
+	return names
+}
+
+// selectorValues returns the strings found at sel in v, resolving the
+// selector the same way bexpr does. A selector going through a slice returns
+// the values found in each of its elements.
+func selectorValues(v reflect.Value, sel bexpr.Selector, fields bexpr.FieldConfigurations) []string {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if len(sel) == 0 {
+			return []string{v.String()}
+		}
+	case reflect.Struct:
+		if len(sel) == 0 {
+			return nil
+		}
+		config, ok := fields[bexpr.FieldName(sel[0])]
+		if !ok || config == nil {
+			return nil
+		}
+		name := sel[0]
+		if config.StructFieldName != "" {
+			name = config.StructFieldName
+		}
+		return selectorValues(v.FieldByName(name), sel[1:], config.SubFields)
+	case reflect.Map:
+		if len(sel) == 0 || v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		var subFields bexpr.FieldConfigurations
+		if config, ok := fields[bexpr.FieldNameAny]; ok && config != nil {
+			subFields = config.SubFields
+		}
+		key := reflect.ValueOf(sel[0]).Convert(v.Type().Key())
+		return selectorValues(v.MapIndex(key), sel[1:], subFields)
+	case reflect.Slice, reflect.Array:
+		var out []string
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, selectorValues(v.Index(i), sel, fields)...)
+		}
+		return out
+	}
+	return nil
+}
@@ -62,26 +62,35 @@ type TokenWriterStore interface {
 // Create a new token. Setting fromLogin to true changes behavior slightly for
 // tokens created by login (as opposed to set manually via the API).
 func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.ACLToken, error) {
-	if err := w.checkCanWriteToken(token); err != nil {
+	if err := w.prepareCreate(token, fromLogin); err != nil {
 		return nil, err
 	}
+	return w.write(token, nil, fromLogin)
+}
+
+// prepareCreate validates a token to create, and fills in its generated
+// fields.
+func (w *TokenWriter) prepareCreate(token *structs.ACLToken, fromLogin bool) error {
+	if err := w.checkCanWriteToken(token); err != nil {
+		return err
+	}
 
 	if token.AccessorID == "" {
 		// Caller didn't provide an AccessorID, so generate one.
 		id, err := lib.GenerateUUID(w.CheckUUID)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to generate AccessorID: %w", err)
+			return fmt.Errorf("Failed to generate AccessorID: %w", err)
 		}
 		token.AccessorID = id
 	} else {
 		// Check the AccessorID is valid and not already in-use.
 		if err := validateTokenID(token.AccessorID); err != nil {
-			return nil, fmt.Errorf("Invalid Token: AccessorID - %w", err)
+			return fmt.Errorf("Invalid Token: AccessorID - %w", err)
 		}
 		if inUse, err := w.tokenIDInUse(token.AccessorID); err != nil {
-			return nil, fmt.Errorf("Failed to lookup ACL token: %w", err)
+			return fmt.Errorf("Failed to lookup ACL token: %w", err)
 		} else if inUse {
-			return nil, errors.New("Invalid Token: AccessorID is already in use")
+			return errors.New("Invalid Token: AccessorID is already in use")
 		}
 	}
 
@@ -89,29 +98,32 @@ func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.
 		// Caller didn't provide a SecretID, so generate one.
 		id, err := lib.GenerateUUID(w.CheckUUID)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to generate SecretID: %w", err)
+			return fmt.Errorf("Failed to generate SecretID: %w", err)
 		}
 		token.SecretID = id
 	} else {
 		// Check the SecretID is valid and not already in-use.
 		if err := validateTokenID(token.SecretID); err != nil {
-			return nil, fmt.Errorf("Invalid Token: SecretID - %w", err)
+			return fmt.Errorf("Invalid Token: SecretID - %w", err)
 		}
 		if inUse, err := w.tokenIDInUse(token.SecretID); err != nil {
-			return nil, fmt.Errorf("Failed to lookup ACL token: %w", err)
+			return fmt.Errorf("Failed to lookup ACL token: %w", err)
 		} else if inUse {
-			return nil, errors.New("Invalid Token: SecretID is already in use")
+			return errors.New("Invalid Token: SecretID is already in use")
 		}
 	}
 
 	token.CreateTime = time.Now()
 
+	// Only rotating a token marks it as replaced.
+	token.ReplacedBy = ""
+
 	// Ensure ExpirationTTL is valid if provided.
 	if token.ExpirationTTL < 0 {
-		return nil, fmt.Errorf("Token Expiration TTL '%s' should be > 0", token.ExpirationTTL)
+		return fmt.Errorf("Token Expiration TTL '%s' should be > 0", token.ExpirationTTL)
 	} else if token.ExpirationTTL > 0 {
 		if token.HasExpirationTime() {
-			return nil, errors.New("Token Expiration TTL and Expiration Time cannot both be set")
+			return errors.New("Token Expiration TTL and Expiration Time cannot both be set")
 		}
 
 		expirationTime := token.CreateTime.Add(token.ExpirationTTL)
@@ -121,37 +133,47 @@ func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.
 
 	if token.HasExpirationTime() {
 		if token.ExpirationTime.Before(token.CreateTime) {
-			return nil, errors.New("ExpirationTime cannot be before CreateTime")
+			return errors.New("ExpirationTime cannot be before CreateTime")
 		}
 
 		expiresIn := token.ExpirationTime.Sub(token.CreateTime)
 
 		if expiresIn > w.MaxExpirationTTL {
-			return nil, fmt.Errorf("ExpirationTime cannot be more than %s in the future (was %s)",
+			return fmt.Errorf("ExpirationTime cannot be more than %s in the future (was %s)",
 				w.MaxExpirationTTL, expiresIn)
 		}
 
 		if expiresIn < w.MinExpirationTTL {
-			return nil, fmt.Errorf("ExpirationTime cannot be less than %s in the future (was %s)",
+			return fmt.Errorf("ExpirationTime cannot be less than %s in the future (was %s)",
 				w.MinExpirationTTL, expiresIn)
 		}
 	}
 
 	if fromLogin {
 		if token.AuthMethod == "" {
-			return nil, errors.New("AuthMethod field is required during login")
+			return errors.New("AuthMethod field is required during login")
 		}
 	} else {
 		if token.AuthMethod != "" {
-			return nil, errors.New("AuthMethod field is disallowed outside of login")
+			return errors.New("AuthMethod field is disallowed outside of login")
 		}
 	}
 
-	return w.write(token, nil, fromLogin)
+	return nil
 }
 
 // Update an existing token.
 func (w *TokenWriter) Update(token *structs.ACLToken) (*structs.ACLToken, error) {
+	existing, err := w.prepareUpdate(token)
+	if err != nil {
+		return nil, err
+	}
+	return w.write(token, existing, false)
+}
+
+// prepareUpdate validates an update of a token, fills in the fields that
+// can't be changed from the existing token and returns the existing token.
+func (w *TokenWriter) prepareUpdate(token *structs.ACLToken) (*structs.ACLToken, error) {
 	if err := w.checkCanWriteToken(token); err != nil {
 		return nil, err
 	}
@@ -209,8 +231,111 @@ func (w *TokenWriter) Update(token *structs.ACLToken) (*structs.ACLToken, error)
 	}
 
 	token.CreateTime = match.CreateTime
+	token.ReplacedBy = match.ReplacedBy
 
-	return w.write(token, match, false)
+	return match, nil
+}
+
+// Rotate issues a replacement for an existing token, with a new AccessorID and
+// SecretID but the same privileges. The existing token remains valid for the
+// overlap window, after which it expires and is reaped like any other expired
+// token. Both tokens are written in the same Raft log entry so clients never
+// observe one without the other.
+func (w *TokenWriter) Rotate(accessorID string, overlap time.Duration, entMeta *acl.EnterpriseMeta) (*structs.ACLToken, error) {
+	_, existing, err := w.Store.ACLTokenGetByAccessor(nil, accessorID, entMeta)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("Failed acl token lookup by accessor: %w", err)
+	case existing == nil || existing.IsExpired(time.Now()):
+		return nil, fmt.Errorf("Cannot find token %q: %w", accessorID, acl.ErrNotFound)
+	}
+
+	if err := w.checkCanWriteToken(existing); err != nil {
+		return nil, err
+	}
+
+	if acl.RootAuthorizer(existing.SecretID) != nil {
+		return nil, acl.PermissionDeniedError{Cause: "Cannot modify root ACL"}
+	}
+
+	if existing.AuthMethod != "" {
+		return nil, errors.New("Cannot rotate a token created from an auth method")
+	}
+
+	// The expiration of a rotated token was shortened to the overlap window,
+	// so its lifetime can't be given to another replacement.
+	if existing.ReplacedBy != "" {
+		return nil, fmt.Errorf("Token %q was already rotated, rotate its replacement %q instead",
+			existing.AccessorID, existing.ReplacedBy)
+	}
+
+	if overlap == 0 {
+		overlap = structs.ACLTokenRotateDefaultOverlap
+	}
+	if overlap < w.MinExpirationTTL || overlap > w.MaxExpirationTTL {
+		return nil, fmt.Errorf("Overlap window must be between %s and %s (was %s)",
+			w.MinExpirationTTL, w.MaxExpirationTTL, overlap)
+	}
+
+	accessor, err := lib.GenerateUUID(w.CheckUUID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate AccessorID: %w", err)
+	}
+	secret, err := lib.GenerateUUID(w.CheckUUID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate SecretID: %w", err)
+	}
+
+	now := time.Now()
+	clone := existing.Clone()
+	replacement := &structs.ACLToken{
+		AccessorID:        accessor,
+		SecretID:          secret,
+		Description:       clone.Description,
+		Policies:          clone.Policies,
+		Roles:             clone.Roles,
+		ServiceIdentities: clone.ServiceIdentities,
+		NodeIdentities:    clone.NodeIdentities,
+		TemplatedPolicies: clone.TemplatedPolicies,
+		Local:             clone.Local,
+		CreateTime:        now,
+		EnterpriseMeta:    clone.EnterpriseMeta,
+	}
+	if existing.HasExpirationTime() {
+		// The replacement gets the same lifetime as the rotated token.
+		expirationTime := now.Add(existing.ExpirationTime.Sub(existing.CreateTime))
+		replacement.ExpirationTime = &expirationTime
+	}
+	if err := w.enterpriseValidation(replacement, nil); err != nil {
+		return nil, err
+	}
+	replacement.SetHash(true)
+
+	// The rotated token expires at the end of the overlap window, unless it
+	// was already going to expire before that.
+	rotated := existing.Clone()
+	rotated.ReplacedBy = replacement.AccessorID
+	expirationTime := now.Add(overlap)
+	if !rotated.HasExpirationTime() || expirationTime.Before(*rotated.ExpirationTime) {
+		rotated.ExpirationTime = &expirationTime
+	}
+	rotated.SetHash(true)
+
+	_, err = w.RaftApply(structs.ACLTokenSetRequestType, &structs.ACLTokenBatchSetRequest{
+		Tokens: structs.ACLTokens{rotated, replacement},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply token rotate request: %w", err)
+	}
+
+	// Purge the rotated token from the ACL cache so its expiration is enforced.
+	w.ACLCache.RemoveIdentityWithSecretToken(rotated.SecretID)
+
+	_, updatedToken, err := w.Store.ACLTokenGetByAccessor(nil, replacement.AccessorID, nil)
+	if err != nil || updatedToken == nil {
+		return nil, errors.New("Failed to retrieve token after insertion")
+	}
+	return updatedToken, nil
 }
 
 // Delete the ACL token with the given SecretID from the state store.
@@ -239,6 +364,180 @@ func (w *TokenWriter) Delete(secretID string, fromLogout bool) error {
 	return nil
 }
 
+// BulkWrite validates the operations of a bulk request, and applies the valid
+// ones in a single Raft log entry. The results are in the same order as the
+// operations: an operation that fails validation is reported in its result
+// and doesn't prevent the others from being applied.
+//
+// authorize is called with the enterprise meta of the token of every
+// operation before it is validated. requestSecretID is the SecretID of the
+// token making the request, which can't be deleted by it.
+func (w *TokenWriter) BulkWrite(ops []structs.ACLTokenBulkOperation, requestSecretID string, authorize func(*acl.EnterpriseMeta) error) ([]structs.ACLTokenBulkResult, error) {
+	results := make([]structs.ACLTokenBulkResult, len(ops))
+
+	var (
+		req     structs.ACLTokenBulkWriteRequest
+		secrets []string
+
+		// seen maps the IDs of the tokens of the batch to the index of
+		// their operation, as a token can only be modified once per batch.
+		seen = make(map[string]int)
+	)
+	for i := range ops {
+		results[i].AccessorID = ops[i].Token.AccessorID
+
+		token, err := w.bulkOperation(&ops[i], requestSecretID, authorize)
+		if err == nil {
+			for _, id := range []string{token.AccessorID, token.SecretID} {
+				if j, ok := seen[id]; ok {
+					err = fmt.Errorf("Token %q is already modified by Operations[%d]", token.AccessorID, j)
+					break
+				}
+			}
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		seen[token.AccessorID], seen[token.SecretID] = i, i
+		results[i].AccessorID = token.AccessorID
+		secrets = append(secrets, token.SecretID)
+		if ops[i].Op == structs.ACLTokenBulkDelete {
+			req.TokenIDs = append(req.TokenIDs, token.AccessorID)
+		} else {
+			req.Tokens = append(req.Tokens, token)
+		}
+	}
+
+	if len(secrets) == 0 {
+		return results, nil
+	}
+
+	if _, err := w.RaftApply(structs.ACLTokenBulkRequestType, &req); err != nil {
+		return nil, fmt.Errorf("Failed to apply token bulk request: %w", err)
+	}
+
+	// Purge the tokens from the ACL cache.
+	for _, secret := range secrets {
+		w.ACLCache.RemoveIdentityWithSecretToken(secret)
+	}
+
+	// Refresh the written tokens from the state store.
+	for i := range results {
+		if results[i].Error != "" || ops[i].Op == structs.ACLTokenBulkDelete {
+			continue
+		}
+		_, updatedToken, err := w.Store.ACLTokenGetByAccessor(nil, results[i].AccessorID, nil)
+		if err != nil || updatedToken == nil {
+			results[i].Error = "Failed to retrieve token after insertion"
+			continue
+		}
+		results[i].Token = updatedToken
+	}
+	return results, nil
+}
+
+// bulkOperation validates an operation of a bulk request, and returns the
+// token to write, or the existing token to delete for delete operations.
+func (w *TokenWriter) bulkOperation(op *structs.ACLTokenBulkOperation, requestSecretID string, authorize func(*acl.EnterpriseMeta) error) (*structs.ACLToken, error) {
+	token := op.Token.Clone()
+	if err := authorize(&token.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case structs.ACLTokenBulkCreate:
+		if err := w.prepareCreate(token, false); err != nil {
+			return nil, err
+		}
+		if err := w.normalize(token, nil); err != nil {
+			return nil, err
+		}
+		return token, nil
+
+	case structs.ACLTokenBulkUpdate:
+		existing, err := w.prepareUpdate(token)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.normalize(token, existing); err != nil {
+			return nil, err
+		}
+		return token, nil
+
+	case structs.ACLTokenBulkDelete, structs.ACLTokenBulkAttachPolicies, structs.ACLTokenBulkDetachPolicies:
+		// These operations apply to an existing token, which is looked up
+		// below.
+
+	default:
+		return nil, fmt.Errorf("Invalid operation %q", op.Op)
+	}
+
+	if _, err := uuid.ParseUUID(token.AccessorID); err != nil {
+		return nil, errors.New("AccessorID is not a valid UUID")
+	}
+
+	_, existing, err := w.Store.ACLTokenGetByAccessor(nil, token.AccessorID, &token.EnterpriseMeta)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("Failed acl token lookup by accessor: %w", err)
+	case existing == nil:
+		return nil, fmt.Errorf("Cannot find token %q: %w", token.AccessorID, acl.ErrNotFound)
+	}
+
+	if err := w.checkCanWriteToken(existing); err != nil {
+		return nil, err
+	}
+
+	if op.Op == structs.ACLTokenBulkDelete {
+		// No need to check expiration time because it's being deleted.
+		switch {
+		case existing.AccessorID == acl.AnonymousTokenID:
+			return nil, errors.New("Delete operation not permitted on the anonymous token")
+		case existing.SecretID == requestSecretID:
+			return nil, errors.New("Deletion of the request's authorization token is not permitted")
+		}
+		return existing, nil
+	}
+
+	if existing.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("Cannot find token %q: %w", token.AccessorID, acl.ErrNotFound)
+	}
+	if acl.RootAuthorizer(existing.SecretID) != nil {
+		return nil, acl.PermissionDeniedError{Cause: "Cannot modify root ACL"}
+	}
+	if len(token.Policies) == 0 {
+		return nil, fmt.Errorf("Operation %q requires at least one policy", op.Op)
+	}
+
+	policies, err := w.normalizePolicyLinks(token.Policies, &existing.EnterpriseMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := existing.Clone()
+	if op.Op == structs.ACLTokenBulkAttachPolicies {
+		updated.Policies = append(updated.Policies, policies...)
+	} else {
+		detached := make(map[string]struct{}, len(policies))
+		for _, link := range policies {
+			detached[link.ID] = struct{}{}
+		}
+		updated.Policies = nil
+		for _, link := range existing.Policies {
+			if _, ok := detached[link.ID]; !ok {
+				updated.Policies = append(updated.Policies, link)
+			}
+		}
+	}
+
+	if err := w.normalize(updated, existing); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
 func validateTokenID(id string) error {
 	if structs.ACLIDReserved(id) {
 		return fmt.Errorf("UUIDs with the prefix %q are reserved", structs.ACLReservedPrefix)
@@ -282,58 +581,78 @@ func (w *TokenWriter) tokenIDInUse(id string) (bool, error) {
 }
 
 func (w *TokenWriter) write(token, existing *structs.ACLToken, fromLogin bool) (*structs.ACLToken, error) {
+	if err := w.normalize(token, existing); err != nil {
+		return nil, err
+	}
+
+	// Persist the token by writing to Raft.
+	_, err := w.RaftApply(structs.ACLTokenSetRequestType, &structs.ACLTokenBatchSetRequest{
+		Tokens: structs.ACLTokens{token},
+		// Logins may attempt to link to roles that do not exist. These may be
+		// persisted, but don't allow tokens to be created that have no privileges
+		// (i.e. role links that point nowhere).
+		AllowMissingLinks:    fromLogin,
+		ProhibitUnprivileged: fromLogin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply token write request: %w", err)
+	}
+
+	// Purge the token from the ACL cache.
+	w.ACLCache.RemoveIdentityWithSecretToken(token.SecretID)
+
+	// Refresh the token from the state store.
+	_, updatedToken, err := w.Store.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	if err != nil || updatedToken == nil {
+		return nil, errors.New("Failed to retrieve token after insertion")
+	}
+	return updatedToken, nil
+}
+
+// normalize validates the links and identities of a token before it is
+// written, and computes its hash.
+func (w *TokenWriter) normalize(token, existing *structs.ACLToken) error {
 	roles, err := w.normalizeRoleLinks(token.Roles, &token.EnterpriseMeta)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	token.Roles = roles
 
 	policies, err := w.normalizePolicyLinks(token.Policies, &token.EnterpriseMeta)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	token.Policies = policies
 
 	serviceIdentities, err := w.normalizeServiceIdentities(token.ServiceIdentities, token.Local)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	token.ServiceIdentities = serviceIdentities
 
 	nodeIdentities, err := w.normalizeNodeIdentities(token.NodeIdentities)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	token.NodeIdentities = nodeIdentities
 
-	if err := w.enterpriseValidation(token, existing); err != nil {
-		return nil, err
+	templatedPolicies, err := w.normalizeTemplatedPolicies(token.TemplatedPolicies, token.Local)
+	if err != nil {
+		return err
 	}
+	token.TemplatedPolicies = templatedPolicies
 
-	token.SetHash(true)
-
-	// Persist the token by writing to Raft.
-	_, err = w.RaftApply(structs.ACLTokenSetRequestType, &structs.ACLTokenBatchSetRequest{
-		Tokens: structs.ACLTokens{token},
-		// Logins may attempt to link to roles that do not exist. These may be
-		// persisted, but don't allow tokens to be created that have no privileges
-		// (i.e. role links that point nowhere).
-		AllowMissingLinks:    fromLogin,
-		ProhibitUnprivileged: fromLogin,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("Failed to apply token write request: %w", err)
+	if err := w.enterpriseValidation(token, existing); err != nil {
+		return err
 	}
 
-	// Purge the token from the ACL cache.
-	w.ACLCache.RemoveIdentityWithSecretToken(token.SecretID)
+	// The usage of the token is recorded separately, so ignore the one the
+	// token may have been read with.
+	token.LastUsedTime = nil
+	token.LastUsedAddr = ""
 
-	// Refresh the token from the state store.
-	_, updatedToken, err := w.Store.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
-	if err != nil || updatedToken == nil {
-		return nil, errors.New("Failed to retrieve token after insertion")
-	}
-	return updatedToken, nil
+	token.SetHash(true)
+	return nil
 }
 
 func (w *TokenWriter) normalizeRoleLinks(links []structs.ACLTokenRoleLink, entMeta *acl.EnterpriseMeta) ([]structs.ACLTokenRoleLink, error) {
@@ -421,6 +740,9 @@ func (w *TokenWriter) normalizeServiceIdentities(svcIDs structs.ACLServiceIdenti
 		if !acl.IsValidServiceIdentityName(id.ServiceName) {
 			return nil, fmt.Errorf("Service identity %q has an invalid name. Only lowercase alphanumeric characters, '-' and '_' are allowed", id.ServiceName)
 		}
+		if id.Node != "" && id.NodePrefix != "" {
+			return nil, fmt.Errorf("Service identity %q cannot specify both a node and a node prefix", id.ServiceName)
+		}
 	}
 	return svcIDs.Deduplicate(), nil
 }
@@ -439,3 +761,15 @@ func (w *TokenWriter) normalizeNodeIdentities(nodeIDs structs.ACLNodeIdentities)
 	}
 	return nodeIDs.Deduplicate(), nil
 }
+
+func (w *TokenWriter) normalizeTemplatedPolicies(templatedPolicies structs.ACLTemplatedPolicies, tokenLocal bool) (structs.ACLTemplatedPolicies, error) {
+	for _, tp := range templatedPolicies {
+		if tokenLocal && len(tp.Datacenters) > 0 {
+			return nil, fmt.Errorf("Templated policy %q cannot specify a list of datacenters on a local token", tp.TemplateName)
+		}
+		if err := tp.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return templatedPolicies.Deduplicate(), nil
+}
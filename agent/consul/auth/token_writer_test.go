@@ -278,6 +278,10 @@ func TestTokenWriter_ServiceIdentities(t *testing.T) {
 			input:         []*structs.ACLServiceIdentity{{ServiceName: "INVALID!"}},
 			errorContains: "has an invalid name",
 		},
+		"both node and node prefix": {
+			input:         []*structs.ACLServiceIdentity{{ServiceName: "web", Node: "node-1", NodePrefix: "node-"}},
+			errorContains: "cannot specify both a node and a node prefix",
+		},
 		"duplicate identities are merged": {
 			input: []*structs.ACLServiceIdentity{
 				{ServiceName: "web", Datacenters: []string{"dc1"}},
@@ -354,6 +358,81 @@ func TestTokenWriter_NodeIdentities(t *testing.T) {
 	}
 }
 
+func TestTokenWriter_TemplatedPolicies(t *testing.T) {
+	aclCache := &MockACLCache{}
+	aclCache.On("RemoveIdentityWithSecretToken", mock.Anything)
+
+	store := testStateStore(t)
+
+	writer := buildTokenWriter(store, aclCache)
+
+	testCases := map[string]struct {
+		input         []*structs.ACLTemplatedPolicy
+		tokenLocal    bool
+		output        []*structs.ACLTemplatedPolicy
+		errorContains string
+	}{
+		"unknown template": {
+			input:         []*structs.ACLTemplatedPolicy{{TemplateName: "builtin/unknown"}},
+			errorContains: "does not exist",
+		},
+		"missing name": {
+			input:         []*structs.ACLTemplatedPolicy{{TemplateName: structs.ACLTemplatedPolicyServiceName}},
+			errorContains: "requires the name variable",
+		},
+		"invalid name": {
+			input: []*structs.ACLTemplatedPolicy{{
+				TemplateName:      structs.ACLTemplatedPolicyServiceName,
+				TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "INVALID!"},
+			}},
+			errorContains: "has an invalid name",
+		},
+		"datacenters given on local token": {
+			input:         []*structs.ACLTemplatedPolicy{{TemplateName: structs.ACLTemplatedPolicyDNSName, Datacenters: []string{"dc1"}}},
+			tokenLocal:    true,
+			errorContains: "cannot specify a list of datacenters on a local token",
+		},
+		"duplicate templated policies are merged": {
+			input: []*structs.ACLTemplatedPolicy{
+				{
+					TemplateName:      structs.ACLTemplatedPolicyServiceName,
+					TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"},
+					Datacenters:       []string{"dc1"},
+				},
+				{
+					TemplateName:      structs.ACLTemplatedPolicyServiceName,
+					TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"},
+					Datacenters:       []string{"dc2"},
+				},
+				{TemplateName: structs.ACLTemplatedPolicyDNSName},
+			},
+			output: []*structs.ACLTemplatedPolicy{
+				{
+					TemplateName:      structs.ACLTemplatedPolicyServiceName,
+					TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"},
+					Datacenters:       []string{"dc1", "dc2"},
+				},
+				{TemplateName: structs.ACLTemplatedPolicyDNSName},
+			},
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			updated, err := writer.Create(&structs.ACLToken{
+				TemplatedPolicies: tc.input,
+				Local:             tc.tokenLocal,
+			}, false)
+			if tc.errorContains == "" {
+				require.NoError(t, err)
+				require.ElementsMatch(t, tc.output, updated.TemplatedPolicies)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+			}
+		})
+	}
+}
+
 func TestTokenWriter_Create_Expiration(t *testing.T) {
 	aclCache := &MockACLCache{}
 	aclCache.On("RemoveIdentityWithSecretToken", mock.Anything)
@@ -539,6 +618,113 @@ func TestTokenWriter_Update_Success(t *testing.T) {
 	require.NotEqual(t, token.Hash, updated.Hash)
 }
 
+func TestTokenWriter_Rotate(t *testing.T) {
+	store := testStateStore(t)
+
+	policyID := generateID(t)
+	require.NoError(t, store.ACLPolicySet(0, &structs.ACLPolicy{
+		ID:   policyID,
+		Name: "some-policy",
+	}))
+
+	token := &structs.ACLToken{
+		AccessorID:        generateID(t),
+		SecretID:          generateID(t),
+		Description:       "agent token",
+		Policies:          []structs.ACLTokenPolicyLink{{ID: policyID}},
+		ServiceIdentities: []*structs.ACLServiceIdentity{{ServiceName: "web"}},
+		TemplatedPolicies: []*structs.ACLTemplatedPolicy{{TemplateName: structs.ACLTemplatedPolicyDNSName}},
+		CreateTime:        time.Now().Add(-1 * time.Hour),
+		ExpirationTime:    timePointer(time.Now().Add(3 * time.Hour)),
+	}
+	token.SetHash(true)
+	require.NoError(t, store.ACLTokenSet(0, token))
+
+	aclCache := &MockACLCache{}
+	aclCache.On("RemoveIdentityWithSecretToken", token.SecretID)
+	defer aclCache.AssertExpectations(t)
+
+	writer := buildTokenWriter(store, aclCache)
+
+	t.Run("overlap out of bounds", func(t *testing.T) {
+		_, err := writer.Rotate(token.AccessorID, 48*time.Hour, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Overlap window must be between")
+	})
+
+	t.Run("token not found", func(t *testing.T) {
+		_, err := writer.Rotate(generateID(t), 0, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, acl.ErrNotFound))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		before := time.Now()
+		replacement, err := writer.Rotate(token.AccessorID, 30*time.Minute, nil)
+		require.NoError(t, err)
+
+		require.NotEqual(t, token.AccessorID, replacement.AccessorID)
+		require.NotEqual(t, token.SecretID, replacement.SecretID)
+		require.Equal(t, token.Description, replacement.Description)
+		require.Equal(t, token.ServiceIdentities, replacement.ServiceIdentities)
+		require.Equal(t, token.TemplatedPolicies, replacement.TemplatedPolicies)
+		require.Len(t, replacement.Policies, 1)
+		require.Equal(t, policyID, replacement.Policies[0].ID)
+
+		// The replacement has the same 4h lifetime as the rotated token.
+		require.WithinDuration(t, before.Add(4*time.Hour), *replacement.ExpirationTime, time.Second)
+
+		// The rotated token expires at the end of the overlap window.
+		_, rotated, err := store.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+		require.NoError(t, err)
+		require.Equal(t, token.SecretID, rotated.SecretID)
+		require.WithinDuration(t, before.Add(30*time.Minute), *rotated.ExpirationTime, time.Second)
+		require.Equal(t, replacement.AccessorID, rotated.ReplacedBy)
+		require.Empty(t, replacement.ReplacedBy)
+
+		// The rotated token can't be rotated again, as its lifetime is now the
+		// overlap window.
+		_, err = writer.Rotate(token.AccessorID, 30*time.Minute, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "was already rotated")
+
+		// Its replacement is rotated with the lifetime of the original token.
+		aclCache.On("RemoveIdentityWithSecretToken", replacement.SecretID)
+		before = time.Now()
+		second, err := writer.Rotate(replacement.AccessorID, 30*time.Minute, nil)
+		require.NoError(t, err)
+		require.WithinDuration(t, before.Add(4*time.Hour), *second.ExpirationTime, time.Second)
+
+		// Updating the rotated token doesn't allow rotating it again.
+		update := rotated.Clone()
+		update.ReplacedBy = ""
+		update.Description = "updated"
+		updated, err := writer.Update(update)
+		require.NoError(t, err)
+		require.Equal(t, replacement.AccessorID, updated.ReplacedBy)
+	})
+
+	t.Run("auth method token", func(t *testing.T) {
+		authMethod := &structs.ACLAuthMethod{
+			Name: generateID(t),
+			Type: "jwt",
+		}
+		require.NoError(t, store.ACLAuthMethodSet(0, authMethod))
+
+		loginToken := &structs.ACLToken{
+			AccessorID: generateID(t),
+			SecretID:   generateID(t),
+			AuthMethod: authMethod.Name,
+			Local:      true,
+		}
+		require.NoError(t, store.ACLTokenSet(0, loginToken))
+
+		_, err := writer.Rotate(loginToken.AccessorID, 0, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Cannot rotate a token created from an auth method")
+	})
+}
+
 func TestTokenWriter_Delete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		store := testStateStore(t)
@@ -651,6 +837,133 @@ func TestTokenWriter_Delete(t *testing.T) {
 	})
 }
 
+func TestTokenWriter_BulkWrite(t *testing.T) {
+	store := testStateStore(t)
+
+	policyA := &structs.ACLPolicy{ID: generateID(t), Name: "policy-a"}
+	policyB := &structs.ACLPolicy{ID: generateID(t), Name: "policy-b"}
+	require.NoError(t, store.ACLPolicyBatchSet(0, structs.ACLPolicies{policyA, policyB}))
+
+	existing := &structs.ACLToken{
+		AccessorID: generateID(t),
+		SecretID:   generateID(t),
+		Policies:   []structs.ACLTokenPolicyLink{{ID: policyA.ID}},
+		Local:      true,
+	}
+	toDelete := &structs.ACLToken{
+		AccessorID: generateID(t),
+		SecretID:   generateID(t),
+		Local:      true,
+	}
+	requestToken := &structs.ACLToken{
+		AccessorID: generateID(t),
+		SecretID:   generateID(t),
+		Local:      true,
+	}
+	require.NoError(t, store.ACLTokenBatchSet(0, structs.ACLTokens{existing, toDelete, requestToken}, state.ACLTokenSetOptions{}))
+
+	aclCache := &MockACLCache{}
+	aclCache.On("RemoveIdentityWithSecretToken", mock.Anything)
+
+	var applied int
+	writer := buildTokenWriter(store, aclCache)
+	writer.RaftApply = func(msgType structs.MessageType, msg interface{}) (interface{}, error) {
+		if msgType != structs.ACLTokenBulkRequestType {
+			return nil, fmt.Errorf("unexpected message type: %v", msgType)
+		}
+		req, ok := msg.(*structs.ACLTokenBulkWriteRequest)
+		if !ok {
+			return nil, fmt.Errorf("unexpected message: %T", msg)
+		}
+		applied++
+		return nil, store.ACLTokenBulkWrite(0, req.Tokens, req.TokenIDs)
+	}
+
+	authorize := func(*acl.EnterpriseMeta) error { return nil }
+
+	ops := []structs.ACLTokenBulkOperation{
+		{
+			Op:    structs.ACLTokenBulkCreate,
+			Token: structs.ACLToken{Description: "new", Policies: []structs.ACLTokenPolicyLink{{Name: "policy-b"}}, Local: true},
+		},
+		{
+			Op:    structs.ACLTokenBulkAttachPolicies,
+			Token: structs.ACLToken{AccessorID: existing.AccessorID, Policies: []structs.ACLTokenPolicyLink{{Name: "policy-b"}}},
+		},
+		{
+			Op:    structs.ACLTokenBulkDelete,
+			Token: structs.ACLToken{AccessorID: toDelete.AccessorID},
+		},
+		{
+			Op:    structs.ACLTokenBulkDetachPolicies,
+			Token: structs.ACLToken{AccessorID: existing.AccessorID, Policies: []structs.ACLTokenPolicyLink{{ID: policyA.ID}}},
+		},
+		{
+			Op:    structs.ACLTokenBulkCreate,
+			Token: structs.ACLToken{Policies: []structs.ACLTokenPolicyLink{{Name: "missing"}}, Local: true},
+		},
+		{
+			Op:    structs.ACLTokenBulkDelete,
+			Token: structs.ACLToken{AccessorID: requestToken.AccessorID},
+		},
+		{
+			Op:    "rename",
+			Token: structs.ACLToken{AccessorID: existing.AccessorID},
+		},
+	}
+
+	results, err := writer.BulkWrite(ops, requestToken.SecretID, authorize)
+	require.NoError(t, err)
+	require.Len(t, results, len(ops))
+	require.Equal(t, 1, applied)
+
+	// The created token.
+	require.Empty(t, results[0].Error)
+	require.NotEmpty(t, results[0].AccessorID)
+	require.Equal(t, "new", results[0].Token.Description)
+	require.Equal(t, policyB.ID, results[0].Token.Policies[0].ID)
+
+	// The attached policy.
+	require.Empty(t, results[1].Error)
+	require.Len(t, results[1].Token.Policies, 2)
+
+	// The deleted token.
+	require.Empty(t, results[2].Error)
+	require.Nil(t, results[2].Token)
+	_, token, err := store.ACLTokenGetByAccessor(nil, toDelete.AccessorID, nil)
+	require.NoError(t, err)
+	require.Nil(t, token)
+
+	// A token can only be modified once per batch.
+	require.Contains(t, results[3].Error, "is already modified by Operations[1]")
+
+	require.Contains(t, results[4].Error, `No such ACL policy with name "missing"`)
+	require.Contains(t, results[5].Error, "Deletion of the request's authorization token is not permitted")
+	require.Contains(t, results[6].Error, `Invalid operation "rename"`)
+
+	// Detaching the policy in another batch.
+	results, err = writer.BulkWrite(ops[3:4], requestToken.SecretID, authorize)
+	require.NoError(t, err)
+	require.Empty(t, results[0].Error)
+	require.Len(t, results[0].Token.Policies, 1)
+	require.Equal(t, policyB.ID, results[0].Token.Policies[0].ID)
+
+	t.Run("nothing to apply", func(t *testing.T) {
+		results, err := writer.BulkWrite(ops[4:], requestToken.SecretID, authorize)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Equal(t, 2, applied)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		deny := func(*acl.EnterpriseMeta) error { return acl.ErrPermissionDenied }
+		results, err := writer.BulkWrite(ops[:1], requestToken.SecretID, deny)
+		require.NoError(t, err)
+		require.Contains(t, results[0].Error, acl.ErrPermissionDenied.Error())
+		require.Equal(t, 2, applied)
+	})
+}
+
 func raftApplyACLTokenSet(store *state.Store) RaftApplyFn {
 	return func(msgType structs.MessageType, msg interface{}) (interface{}, error) {
 		if msgType != structs.ACLTokenSetRequestType {
@@ -0,0 +1,239 @@
+package azureauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/internal/go-sso/oidcauth"
+)
+
+const (
+	authMethodType string = "azure-msi"
+
+	// DefaultJWKSURL is where Azure AD publishes the keys used to sign the
+	// managed identity tokens.
+	DefaultJWKSURL string = "https://login.microsoftonline.com/common/discovery/keys"
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register(authMethodType, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+type Config struct {
+	// TenantID is the Azure AD tenant that issues the managed identity tokens.
+	TenantID string `json:",omitempty"`
+
+	// BoundAudiences are the resources that the managed identity tokens must
+	// have been requested for. At least one is required so that a token minted
+	// for another service cannot be replayed against Consul.
+	BoundAudiences []string `json:",omitempty"`
+
+	// BoundPrincipalIDs are the object IDs of the managed identities that are
+	// permitted to login to the auth method. If empty, any managed identity of
+	// the tenant is permitted.
+	BoundPrincipalIDs []string `json:",omitempty"`
+
+	// BoundSubscriptionIDs are the subscriptions that the resources holding
+	// the managed identities must belong to. If empty, any subscription is
+	// permitted.
+	BoundSubscriptionIDs []string `json:",omitempty"`
+
+	// BoundResourceGroups are the resource groups that the resources holding
+	// the managed identities must belong to. If empty, any resource group is
+	// permitted.
+	BoundResourceGroups []string `json:",omitempty"`
+
+	// JWKSURL overrides the URL where the keys used to verify the managed
+	// identity tokens are fetched from. Defaults to DefaultJWKSURL.
+	JWKSURL string `json:",omitempty"`
+	// JWKSCACert is the PEM encoded CA certificate used to verify the TLS
+	// connection to JWKSURL.
+	JWKSCACert string `json:",omitempty"`
+}
+
+func (c *Config) convertForLibrary() *oidcauth.Config {
+	jwksURL := c.JWKSURL
+	if jwksURL == "" {
+		jwksURL = DefaultJWKSURL
+	}
+	return &oidcauth.Config{
+		Type:           oidcauth.TypeJWT,
+		JWKSURL:        jwksURL,
+		JWKSCACert:     c.JWKSCACert,
+		BoundIssuer:    issuerForTenant(c.TenantID),
+		BoundAudiences: c.BoundAudiences,
+		ClaimMappings: map[string]string{
+			"oid":       "principal_id",
+			"tid":       "tenant_id",
+			"appid":     "client_id",
+			"xms_mirid": "resource_id",
+		},
+	}
+}
+
+// issuerForTenant returns the issuer of the tokens minted for the managed
+// identities of the given tenant.
+func issuerForTenant(tenantID string) string {
+	return "https://sts.windows.net/" + tenantID + "/"
+}
+
+type Validator struct {
+	name   string
+	config *Config
+	logger hclog.Logger
+
+	oa *oidcauth.Authenticator
+}
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != authMethodType {
+		return nil, fmt.Errorf("%q is not an Azure managed identity auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+	if config.TenantID == "" {
+		return nil, errors.New("TenantID is required")
+	}
+	if len(config.BoundAudiences) == 0 {
+		return nil, errors.New("BoundAudiences is required")
+	}
+
+	oa, err := oidcauth.New(config.convertForLibrary(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		name:   method.Name,
+		config: &config,
+		logger: logger,
+		oa:     oa,
+	}, nil
+}
+
+// Name implements authmethod.Validator.
+func (v *Validator) Name() string { return v.name }
+
+// Stop implements authmethod.Validator.
+func (v *Validator) Stop() { v.oa.Stop() }
+
+// ValidateLogin implements authmethod.Validator.
+func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*authmethod.Identity, error) {
+	c, err := v.oa.ClaimsFromJWT(ctx, loginToken)
+	if err != nil {
+		return nil, err
+	}
+
+	principalID := c.Values["principal_id"]
+	if principalID == "" {
+		return nil, errors.New("managed identity token has no principal ID")
+	}
+	if !strings.EqualFold(c.Values["tenant_id"], v.config.TenantID) {
+		return nil, fmt.Errorf("managed identity token was not issued for tenant %q", v.config.TenantID)
+	}
+
+	fields := &azureSelectableFields{
+		PrincipalID: principalID,
+		TenantID:    c.Values["tenant_id"],
+		ClientID:    c.Values["client_id"],
+		ResourceID:  c.Values["resource_id"],
+	}
+	fields.SubscriptionID, fields.ResourceGroup, fields.ResourceName = parseResourceID(fields.ResourceID)
+
+	if len(v.config.BoundPrincipalIDs) > 0 && !containsFold(v.config.BoundPrincipalIDs, principalID) {
+		return nil, fmt.Errorf("managed identity %q is not permitted to login", principalID)
+	}
+	if len(v.config.BoundSubscriptionIDs) > 0 && !containsFold(v.config.BoundSubscriptionIDs, fields.SubscriptionID) {
+		return nil, fmt.Errorf("subscription %q is not permitted to login", fields.SubscriptionID)
+	}
+	if len(v.config.BoundResourceGroups) > 0 && !containsFold(v.config.BoundResourceGroups, fields.ResourceGroup) {
+		return nil, fmt.Errorf("resource group %q is not permitted to login", fields.ResourceGroup)
+	}
+
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+		EnterpriseMeta:   nil,
+	}, nil
+}
+
+// NewIdentity implements authmethod.Validator.
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	fields := &azureSelectableFields{}
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+	}
+}
+
+// parseResourceID extracts the subscription, resource group and name of the
+// resource that holds a managed identity from its ID, which has the form:
+//
+//	/subscriptions/<sub>/resourcegroups/<group>/providers/<provider>/<type>/<name>
+//
+// Azure doesn't preserve the case of the segment names, so they are matched
+// case-insensitively. Empty strings are returned for the parts that are not
+// found.
+func parseResourceID(id string) (subscriptionID, resourceGroup, resourceName string) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	for i := 0; i+1 < len(parts); i += 2 {
+		switch strings.ToLower(parts[i]) {
+		case "subscriptions":
+			subscriptionID = parts[i+1]
+		case "resourcegroups":
+			resourceGroup = parts[i+1]
+		}
+	}
+	if subscriptionID != "" && resourceGroup != "" && len(parts) > 4 {
+		resourceName = parts[len(parts)-1]
+	}
+	return subscriptionID, resourceGroup, resourceName
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+type azureSelectableFields struct {
+	PrincipalID string `bexpr:"principal_id"`
+	TenantID    string `bexpr:"tenant_id"`
+	ClientID    string `bexpr:"client_id"`
+
+	ResourceID     string `bexpr:"resource_id"`
+	SubscriptionID string `bexpr:"subscription_id"`
+	ResourceGroup  string `bexpr:"resource_group"`
+	ResourceName   string `bexpr:"resource_name"`
+}
+
+func (f *azureSelectableFields) projectedVars() map[string]string {
+	return map[string]string{
+		"principal_id":    f.PrincipalID,
+		"tenant_id":       f.TenantID,
+		"client_id":       f.ClientID,
+		"resource_id":     f.ResourceID,
+		"subscription_id": f.SubscriptionID,
+		"resource_group":  f.ResourceGroup,
+		"resource_name":   f.ResourceName,
+	}
+}
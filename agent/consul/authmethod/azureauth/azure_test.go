@@ -0,0 +1,258 @@
+package azureauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+const (
+	testTenantID    = "72f988bf-86f1-41af-91ab-2d7cd011db47"
+	testPrincipalID = "2a7b6c04-9f4e-4d43-8b3b-0c5e4fa5b2d1"
+	testResourceID  = "/subscriptions/0f6a5b1c-3c2d-4e5f-8a9b-1c2d3e4f5a6b/resourcegroups/consul-rg/providers/Microsoft.Compute/virtualMachines/web-1"
+)
+
+func TestNewValidator(t *testing.T) {
+	oidcServer := oidcauthtest.Start(t)
+
+	type AM = *structs.ACLAuthMethod
+	// Create the auth method, with an optional modification function.
+	makeMethod := func(modifyFn func(AM)) AM {
+		m := &structs.ACLAuthMethod{
+			Name:        "test-azure",
+			Type:        "azure-msi",
+			Description: "azure msi auth",
+			Config: map[string]interface{}{
+				"TenantID":       testTenantID,
+				"BoundAudiences": []string{"https://consul.test"},
+				"JWKSURL":        oidcServer.Addr() + "/certs",
+				"JWKSCACert":     oidcServer.CACert(),
+			},
+		}
+		if modifyFn != nil {
+			modifyFn(m)
+		}
+		return m
+	}
+
+	for name, tc := range map[string]struct {
+		method    AM
+		expectErr string
+	}{
+		"success": {makeMethod(nil), ""},
+		"wrong type": {makeMethod(func(m AM) {
+			m.Type = "jwt"
+		}), `"test-azure" is not an Azure managed identity auth method`},
+		"missing tenant": {makeMethod(func(m AM) {
+			delete(m.Config, "TenantID")
+		}), "TenantID is required"},
+		"missing audiences": {makeMethod(func(m AM) {
+			delete(m.Config, "BoundAudiences")
+		}), "BoundAudiences is required"},
+		"extra config": {makeMethod(func(m AM) {
+			m.Config["BoundIssuer"] = "https://legit.issuer.internal/"
+		}), "has invalid keys"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				require.Nil(t, v)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, v)
+				require.Equal(t, "test-azure", v.Name())
+				v.Stop()
+			}
+		})
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	oidcServer := oidcauthtest.Start(t)
+	_, privKey := oidcServer.SigningKeys()
+
+	type privateClaims struct {
+		ObjectID   string `json:"oid,omitempty"`
+		TenantID   string `json:"tid,omitempty"`
+		AppID      string `json:"appid,omitempty"`
+		ResourceID string `json:"xms_mirid,omitempty"`
+	}
+
+	vmClaims := privateClaims{
+		ObjectID:   testPrincipalID,
+		TenantID:   testTenantID,
+		AppID:      "5e1f0c3a-7b2d-4c8e-9f6a-3b4c5d6e7f80",
+		ResourceID: testResourceID,
+	}
+
+	cases := map[string]struct {
+		config     map[string]interface{}
+		issuer     string
+		audience   string
+		claims     privateClaims
+		expectErr  string
+		expectVars map[string]string
+	}{
+		"success": {
+			claims: vmClaims,
+			expectVars: map[string]string{
+				"principal_id":    testPrincipalID,
+				"tenant_id":       testTenantID,
+				"client_id":       "5e1f0c3a-7b2d-4c8e-9f6a-3b4c5d6e7f80",
+				"resource_id":     testResourceID,
+				"subscription_id": "0f6a5b1c-3c2d-4e5f-8a9b-1c2d3e4f5a6b",
+				"resource_group":  "consul-rg",
+				"resource_name":   "web-1",
+			},
+		},
+		"success - bound identity, subscription and resource group": {
+			config: map[string]interface{}{
+				"BoundPrincipalIDs":    []string{testPrincipalID},
+				"BoundSubscriptionIDs": []string{"0F6A5B1C-3C2D-4E5F-8A9B-1C2D3E4F5A6B"},
+				"BoundResourceGroups":  []string{"Consul-RG"},
+			},
+			claims: vmClaims,
+		},
+		"wrong issuer": {
+			issuer:    "https://sts.windows.net/00000000-0000-0000-0000-000000000000/",
+			claims:    vmClaims,
+			expectErr: "error validating claims",
+		},
+		"wrong audience": {
+			audience:  "https://other.test",
+			claims:    vmClaims,
+			expectErr: "error validating claims",
+		},
+		"missing principal": {
+			claims:    privateClaims{TenantID: testTenantID},
+			expectErr: "managed identity token has no principal ID",
+		},
+		"wrong tenant claim": {
+			claims:    privateClaims{ObjectID: testPrincipalID, TenantID: "other"},
+			expectErr: "was not issued for tenant",
+		},
+		"principal not bound": {
+			config: map[string]interface{}{
+				"BoundPrincipalIDs": []string{"8c0b2a6e-0000-0000-0000-000000000000"},
+			},
+			claims:    vmClaims,
+			expectErr: "is not permitted to login",
+		},
+		"subscription not bound": {
+			config: map[string]interface{}{
+				"BoundSubscriptionIDs": []string{"8c0b2a6e-0000-0000-0000-000000000000"},
+			},
+			claims:    vmClaims,
+			expectErr: "subscription \"0f6a5b1c-3c2d-4e5f-8a9b-1c2d3e4f5a6b\" is not permitted to login",
+		},
+		"resource group not bound": {
+			config: map[string]interface{}{
+				"BoundResourceGroups": []string{"other-rg"},
+			},
+			claims:    vmClaims,
+			expectErr: `resource group "consul-rg" is not permitted to login`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"TenantID":       testTenantID,
+				"BoundAudiences": []string{"https://consul.test"},
+				"JWKSURL":        oidcServer.Addr() + "/certs",
+				"JWKSCACert":     oidcServer.CACert(),
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+			v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+				Name:   "test-azure",
+				Type:   "azure-msi",
+				Config: config,
+			})
+			require.NoError(t, err)
+			t.Cleanup(v.Stop)
+
+			issuer := tc.issuer
+			if issuer == "" {
+				issuer = issuerForTenant(testTenantID)
+			}
+			audience := tc.audience
+			if audience == "" {
+				audience = "https://consul.test"
+			}
+			cl := jwt.Claims{
+				Subject:   tc.claims.ObjectID,
+				Audience:  jwt.Audience{audience},
+				Issuer:    issuer,
+				NotBefore: jwt.NewNumericDate(time.Now().Add(-5 * time.Second)),
+				Expiry:    jwt.NewNumericDate(time.Now().Add(5 * time.Second)),
+			}
+			jwtData, err := oidcauthtest.SignJWT(privKey, cl, tc.claims)
+			require.NoError(t, err)
+
+			id, err := v.ValidateLogin(context.Background(), jwtData)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectVars != nil {
+				authmethod.RequireIdentityMatch(t, id, tc.expectVars,
+					`principal_id == "`+testPrincipalID+`"`,
+					`resource_group == "consul-rg"`,
+				)
+			}
+		})
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	v := &Validator{}
+	id := v.NewIdentity()
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"principal_id":    "",
+		"tenant_id":       "",
+		"client_id":       "",
+		"resource_id":     "",
+		"subscription_id": "",
+		"resource_group":  "",
+		"resource_name":   "",
+	},
+		`principal_id == ""`,
+		`resource_group == ""`,
+	)
+}
+
+func TestParseResourceID(t *testing.T) {
+	cases := map[string]struct {
+		id                  string
+		sub, group, resName string
+	}{
+		"empty": {"", "", "", ""},
+		"vm":    {testResourceID, "0f6a5b1c-3c2d-4e5f-8a9b-1c2d3e4f5a6b", "consul-rg", "web-1"},
+		"user assigned identity": {
+			"/subscriptions/sub/resourceGroups/RG/providers/Microsoft.ManagedIdentity/userAssignedIdentities/consul-id",
+			"sub", "RG", "consul-id",
+		},
+		"resource group only": {"/subscriptions/sub/resourceGroups/RG", "sub", "RG", ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sub, group, resName := parseResourceID(tc.id)
+			require.Equal(t, tc.sub, sub)
+			require.Equal(t, tc.group, group)
+			require.Equal(t, tc.resName, resName)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package certauth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	// AuthMethodType is the type of the auth methods verifying the client
+	// certificates presented to the TLS-enabled HTTP and gRPC APIs.
+	AuthMethodType string = "tls-cert"
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register(AuthMethodType, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+type Config struct {
+	// CACerts are the PEM encoded certificates of the CAs issuing the client
+	// certificates permitted to login. At least one is required.
+	CACerts []string `json:",omitempty"`
+
+	// BoundCommonNames are patterns, as supported by path.Match, that the
+	// common name of the subject of the certificate must match. If empty, any
+	// common name is permitted.
+	BoundCommonNames []string `json:",omitempty"`
+
+	// BoundURISANs are patterns that one of the URI SANs of the certificate
+	// must match. If empty, any certificate is permitted.
+	BoundURISANs []string `json:",omitempty"`
+
+	// BoundDNSSANs are patterns that one of the DNS SANs of the certificate
+	// must match. If empty, any certificate is permitted.
+	BoundDNSSANs []string `json:",omitempty"`
+}
+
+type Validator struct {
+	name   string
+	config *Config
+	logger hclog.Logger
+
+	roots *x509.CertPool
+	// now is overridden in the tests.
+	now func() time.Time
+}
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != AuthMethodType {
+		return nil, fmt.Errorf("%q is not a TLS certificate auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+	if len(config.CACerts) == 0 {
+		return nil, errors.New("CACerts is required")
+	}
+
+	roots := x509.NewCertPool()
+	for i, caCert := range config.CACerts {
+		if !roots.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, fmt.Errorf("CACerts[%d] is not a valid PEM encoded certificate", i)
+		}
+	}
+	for _, patterns := range [][]string{config.BoundCommonNames, config.BoundURISANs, config.BoundDNSSANs} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return &Validator{
+		name:   method.Name,
+		config: &config,
+		logger: logger,
+		roots:  roots,
+		now:    time.Now,
+	}, nil
+}
+
+// Name implements authmethod.Validator.
+func (v *Validator) Name() string { return v.name }
+
+// Stop implements authmethod.Validator.
+func (v *Validator) Stop() {}
+
+// ValidateLogin implements authmethod.Validator. The login token is the PEM
+// encoded chain of certificates presented by the client, starting with its
+// own certificate.
+//
+// The chain proves nothing on its own since it isn't secret, so the logins
+// are only accepted from the servers which verified that the client holds
+// the private key during the TLS handshake, see ACL.Login.
+func (v *Validator) ValidateLogin(_ context.Context, loginToken string) (*authmethod.Identity, error) {
+	chain, err := parseChain(loginToken)
+	if err != nil {
+		return nil, err
+	}
+	leaf := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the client certificate: %w", err)
+	}
+
+	fields := &certSelectableFields{
+		SubjectCommonName: leaf.Subject.CommonName,
+		SerialNumber:      leaf.SerialNumber.String(),
+		DNSSANs:           leaf.DNSNames,
+		EmailSANs:         leaf.EmailAddresses,
+	}
+	for _, uri := range leaf.URIs {
+		fields.URISANs = append(fields.URISANs, uri.String())
+	}
+
+	if len(v.config.BoundCommonNames) > 0 && !matchAny(v.config.BoundCommonNames, []string{fields.SubjectCommonName}) {
+		return nil, fmt.Errorf("common name %q is not permitted to login", fields.SubjectCommonName)
+	}
+	if len(v.config.BoundURISANs) > 0 && !matchAny(v.config.BoundURISANs, fields.URISANs) {
+		return nil, errors.New("URI SANs of the certificate are not permitted to login")
+	}
+	if len(v.config.BoundDNSSANs) > 0 && !matchAny(v.config.BoundDNSSANs, fields.DNSSANs) {
+		return nil, errors.New("DNS SANs of the certificate are not permitted to login")
+	}
+
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+		EnterpriseMeta:   nil,
+	}, nil
+}
+
+// NewIdentity implements authmethod.Validator.
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	fields := &certSelectableFields{}
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+	}
+}
+
+// EncodeChain returns the login token for the given chain of certificates,
+// as expected by ValidateLogin.
+func EncodeChain(chain []*x509.Certificate) string {
+	var sb strings.Builder
+	for _, cert := range chain {
+		_ = pem.Encode(&sb, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return sb.String()
+}
+
+func parseChain(loginToken string) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	rest := []byte(loginToken)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the client certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("login token is not a PEM encoded certificate chain")
+	}
+	return chain, nil
+}
+
+func matchAny(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type certSelectableFields struct {
+	SubjectCommonName string `bexpr:"subject_common_name"`
+	SerialNumber      string `bexpr:"serial_number"`
+
+	URISANs   []string `bexpr:"uri_sans"`
+	DNSSANs   []string `bexpr:"dns_sans"`
+	EmailSANs []string `bexpr:"email_sans"`
+}
+
+func (f *certSelectableFields) projectedVars() map[string]string {
+	return map[string]string{
+		"subject_common_name": f.SubjectCommonName,
+		"serial_number":       f.SerialNumber,
+		"uri_san":             first(f.URISANs),
+		"dns_san":             first(f.DNSSANs),
+		"email_san":           first(f.EmailSANs),
+	}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,265 @@
+package certauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  string
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})),
+	}
+}
+
+func (ca *testCA) issue(t *testing.T, modifyFn func(*x509.Certificate)) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "deployer"},
+		DNSNames:     []string{"deployer.tools.internal"},
+		URIs:         []*url.URL{{Scheme: "spiffe", Host: "tools.internal", Path: "/ci/deployer"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if modifyFn != nil {
+		modifyFn(template)
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestNewValidator(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+
+	type AM = *structs.ACLAuthMethod
+	// Create the auth method, with an optional modification function.
+	makeMethod := func(modifyFn func(AM)) AM {
+		m := &structs.ACLAuthMethod{
+			Name:        "test-cert",
+			Type:        "tls-cert",
+			Description: "tls cert auth",
+			Config: map[string]interface{}{
+				"CACerts": []string{ca.pem},
+			},
+		}
+		if modifyFn != nil {
+			modifyFn(m)
+		}
+		return m
+	}
+
+	for name, tc := range map[string]struct {
+		method    AM
+		expectErr string
+	}{
+		"success": {makeMethod(nil), ""},
+		"wrong type": {makeMethod(func(m AM) {
+			m.Type = "jwt"
+		}), `"test-cert" is not a TLS certificate auth method`},
+		"missing CA certs": {makeMethod(func(m AM) {
+			delete(m.Config, "CACerts")
+		}), "CACerts is required"},
+		"invalid CA cert": {makeMethod(func(m AM) {
+			m.Config["CACerts"] = []string{ca.pem, "invalid"}
+		}), "CACerts[1] is not a valid PEM encoded certificate"},
+		"invalid pattern": {makeMethod(func(m AM) {
+			m.Config["BoundCommonNames"] = []string{"deploy[er"}
+		}), `invalid pattern "deploy[er"`},
+		"extra config": {makeMethod(func(m AM) {
+			m.Config["BoundIssuer"] = "test-ca"
+		}), "has invalid keys"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				require.Nil(t, v)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, v)
+				require.Equal(t, "test-cert", v.Name())
+				v.Stop()
+			}
+		})
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	otherCA := newTestCA(t, "other-ca")
+
+	cases := map[string]struct {
+		config     map[string]interface{}
+		chain      func(t *testing.T) []*x509.Certificate
+		expectErr  string
+		expectVars map[string]string
+	}{
+		"success": {
+			expectVars: map[string]string{
+				"subject_common_name": "deployer",
+				"serial_number":       "42",
+				"uri_san":             "spiffe://tools.internal/ci/deployer",
+				"dns_san":             "deployer.tools.internal",
+				"email_san":           "",
+			},
+		},
+		"success - bound names": {
+			config: map[string]interface{}{
+				"BoundCommonNames": []string{"other", "deploy*"},
+				"BoundURISANs":     []string{"spiffe://tools.internal/ci/*"},
+				"BoundDNSSANs":     []string{"*.tools.internal"},
+			},
+		},
+		"untrusted CA": {
+			chain: func(t *testing.T) []*x509.Certificate {
+				return []*x509.Certificate{otherCA.issue(t, nil)}
+			},
+			expectErr: "failed to verify the client certificate",
+		},
+		"expired": {
+			chain: func(t *testing.T) []*x509.Certificate {
+				return []*x509.Certificate{ca.issue(t, func(c *x509.Certificate) {
+					c.NotAfter = time.Now().Add(-time.Second)
+				})}
+			},
+			expectErr: "failed to verify the client certificate",
+		},
+		"server certificate": {
+			chain: func(t *testing.T) []*x509.Certificate {
+				return []*x509.Certificate{ca.issue(t, func(c *x509.Certificate) {
+					c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+				})}
+			},
+			expectErr: "failed to verify the client certificate",
+		},
+		"common name not bound": {
+			config: map[string]interface{}{
+				"BoundCommonNames": []string{"admin"},
+			},
+			expectErr: `common name "deployer" is not permitted to login`,
+		},
+		"URI SAN not bound": {
+			config: map[string]interface{}{
+				"BoundURISANs": []string{"spiffe://tools.internal/admin/*"},
+			},
+			expectErr: "URI SANs of the certificate are not permitted to login",
+		},
+		"DNS SAN not bound": {
+			config: map[string]interface{}{
+				"BoundDNSSANs": []string{"*.prod.internal"},
+			},
+			expectErr: "DNS SANs of the certificate are not permitted to login",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"CACerts": []string{ca.pem},
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+			v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+				Name:   "test-cert",
+				Type:   "tls-cert",
+				Config: config,
+			})
+			require.NoError(t, err)
+			t.Cleanup(v.Stop)
+
+			chain := []*x509.Certificate{ca.issue(t, nil)}
+			if tc.chain != nil {
+				chain = tc.chain(t)
+			}
+
+			id, err := v.ValidateLogin(context.Background(), EncodeChain(chain))
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectVars != nil {
+				authmethod.RequireIdentityMatch(t, id, tc.expectVars,
+					`subject_common_name == "deployer"`,
+					`"spiffe://tools.internal/ci/deployer" in uri_sans`,
+				)
+			}
+		})
+	}
+
+	t.Run("invalid login token", func(t *testing.T) {
+		v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+			Name:   "test-cert",
+			Type:   "tls-cert",
+			Config: map[string]interface{}{"CACerts": []string{ca.pem}},
+		})
+		require.NoError(t, err)
+
+		_, err = v.ValidateLogin(context.Background(), "not a certificate")
+		testutil.RequireErrorContains(t, err, "login token is not a PEM encoded certificate chain")
+	})
+}
+
+func TestNewIdentity(t *testing.T) {
+	v := &Validator{}
+	id := v.NewIdentity()
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"subject_common_name": "",
+		"serial_number":       "",
+		"uri_san":             "",
+		"dns_san":             "",
+		"email_san":           "",
+	},
+		`subject_common_name == ""`,
+	)
+}
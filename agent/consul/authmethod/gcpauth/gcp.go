@@ -0,0 +1,224 @@
+package gcpauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/internal/go-sso/oidcauth"
+)
+
+const (
+	authMethodType string = "gcp-iam"
+
+	// googleIssuer is the issuer of the identity tokens minted by the GCP
+	// metadata server.
+	googleIssuer string = "https://accounts.google.com"
+
+	// DefaultJWKSURL is where Google publishes the keys used to sign the
+	// identity tokens.
+	DefaultJWKSURL string = "https://www.googleapis.com/oauth2/v3/certs"
+
+	// serviceAccountDomain is the domain of the emails of the service
+	// accounts managed by GCP IAM: <name>@<project>.iam.gserviceaccount.com
+	serviceAccountDomain string = ".iam.gserviceaccount.com"
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register(authMethodType, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+type Config struct {
+	// BoundAudiences are the audiences that the identity tokens must have been
+	// requested for. At least one is required so that a token minted for
+	// another service cannot be replayed against Consul.
+	BoundAudiences []string `json:",omitempty"`
+
+	// BoundServiceAccounts are the emails of the GCP service accounts that are
+	// permitted to login to the auth method. If empty, any service account is
+	// permitted.
+	BoundServiceAccounts []string `json:",omitempty"`
+
+	// BoundProjectIDs are the GCP projects that are permitted to login to the
+	// auth method. If empty, any project is permitted.
+	BoundProjectIDs []string `json:",omitempty"`
+
+	// JWKSURL overrides the URL where the keys used to verify the identity
+	// tokens are fetched from. Defaults to DefaultJWKSURL.
+	JWKSURL string `json:",omitempty"`
+	// JWKSCACert is the PEM encoded CA certificate used to verify the TLS
+	// connection to JWKSURL.
+	JWKSCACert string `json:",omitempty"`
+}
+
+func (c *Config) convertForLibrary() *oidcauth.Config {
+	jwksURL := c.JWKSURL
+	if jwksURL == "" {
+		jwksURL = DefaultJWKSURL
+	}
+	return &oidcauth.Config{
+		Type:           oidcauth.TypeJWT,
+		JWKSURL:        jwksURL,
+		JWKSCACert:     c.JWKSCACert,
+		BoundIssuer:    googleIssuer,
+		BoundAudiences: c.BoundAudiences,
+		ClaimMappings: map[string]string{
+			"email":                                 "service_account_email",
+			"email_verified":                        "email_verified",
+			"sub":                                   "service_account_id",
+			"/google/compute_engine/project_id":     "project_id",
+			"/google/compute_engine/project_number": "project_number",
+			"/google/compute_engine/zone":           "zone",
+			"/google/compute_engine/instance_id":    "instance_id",
+			"/google/compute_engine/instance_name":  "instance_name",
+		},
+	}
+}
+
+type Validator struct {
+	name   string
+	config *Config
+	logger hclog.Logger
+
+	oa *oidcauth.Authenticator
+}
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != authMethodType {
+		return nil, fmt.Errorf("%q is not a GCP IAM auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+	if len(config.BoundAudiences) == 0 {
+		return nil, errors.New("BoundAudiences is required")
+	}
+
+	// The compute engine claims are only present in the tokens requested with
+	// format=full from a VM, so a nil logger is passed to avoid a warning for
+	// each login of a service account that isn't attached to an instance.
+	oa, err := oidcauth.New(config.convertForLibrary(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		name:   method.Name,
+		config: &config,
+		logger: logger,
+		oa:     oa,
+	}, nil
+}
+
+// Name implements authmethod.Validator.
+func (v *Validator) Name() string { return v.name }
+
+// Stop implements authmethod.Validator.
+func (v *Validator) Stop() { v.oa.Stop() }
+
+// ValidateLogin implements authmethod.Validator.
+func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*authmethod.Identity, error) {
+	c, err := v.oa.ClaimsFromJWT(ctx, loginToken)
+	if err != nil {
+		return nil, err
+	}
+
+	email := c.Values["service_account_email"]
+	if email == "" {
+		return nil, errors.New("identity token has no service account email")
+	}
+	if c.Values["email_verified"] != "true" {
+		return nil, fmt.Errorf("service account email %q is not verified", email)
+	}
+
+	fields := &gcpSelectableFields{
+		ServiceAccountEmail: email,
+		ServiceAccountID:    c.Values["service_account_id"],
+		ProjectID:           c.Values["project_id"],
+		ProjectNumber:       c.Values["project_number"],
+		Zone:                c.Values["zone"],
+		InstanceID:          c.Values["instance_id"],
+		InstanceName:        c.Values["instance_name"],
+	}
+	if fields.ProjectID == "" {
+		fields.ProjectID = projectFromServiceAccount(email)
+	}
+
+	if len(v.config.BoundServiceAccounts) > 0 && !contains(v.config.BoundServiceAccounts, email) {
+		return nil, fmt.Errorf("service account %q is not permitted to login", email)
+	}
+	if len(v.config.BoundProjectIDs) > 0 && !contains(v.config.BoundProjectIDs, fields.ProjectID) {
+		return nil, fmt.Errorf("project %q is not permitted to login", fields.ProjectID)
+	}
+
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+		EnterpriseMeta:   nil,
+	}, nil
+}
+
+// NewIdentity implements authmethod.Validator.
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	fields := &gcpSelectableFields{}
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    fields.projectedVars(),
+	}
+}
+
+// projectFromServiceAccount returns the project of a service account managed
+// by GCP IAM, or an empty string for the other accounts.
+func projectFromServiceAccount(email string) string {
+	idx := strings.LastIndex(email, "@")
+	if idx == -1 || !strings.HasSuffix(email, serviceAccountDomain) {
+		return ""
+	}
+	return strings.TrimSuffix(email[idx+1:], serviceAccountDomain)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+type gcpSelectableFields struct {
+	ServiceAccountEmail string `bexpr:"service_account_email"`
+	ServiceAccountID    string `bexpr:"service_account_id"`
+	ProjectID           string `bexpr:"project_id"`
+
+	ProjectNumber string `bexpr:"project_number"`
+	Zone          string `bexpr:"zone"`
+	InstanceID    string `bexpr:"instance_id"`
+	InstanceName  string `bexpr:"instance_name"`
+}
+
+func (f *gcpSelectableFields) projectedVars() map[string]string {
+	return map[string]string{
+		"service_account_email": f.ServiceAccountEmail,
+		"service_account_id":    f.ServiceAccountID,
+		"project_id":            f.ProjectID,
+		"project_number":        f.ProjectNumber,
+		"zone":                  f.Zone,
+		"instance_id":           f.InstanceID,
+		"instance_name":         f.InstanceName,
+	}
+}
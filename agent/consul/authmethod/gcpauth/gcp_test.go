@@ -0,0 +1,253 @@
+package gcpauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestNewValidator(t *testing.T) {
+	oidcServer := oidcauthtest.Start(t)
+
+	type AM = *structs.ACLAuthMethod
+	// Create the auth method, with an optional modification function.
+	makeMethod := func(modifyFn func(AM)) AM {
+		m := &structs.ACLAuthMethod{
+			Name:        "test-gcp",
+			Type:        "gcp-iam",
+			Description: "gcp iam auth",
+			Config: map[string]interface{}{
+				"BoundAudiences": []string{"https://consul.test"},
+				"JWKSURL":        oidcServer.Addr() + "/certs",
+				"JWKSCACert":     oidcServer.CACert(),
+			},
+		}
+		if modifyFn != nil {
+			modifyFn(m)
+		}
+		return m
+	}
+
+	for name, tc := range map[string]struct {
+		method    AM
+		expectErr string
+	}{
+		"success": {makeMethod(nil), ""},
+		"wrong type": {makeMethod(func(m AM) {
+			m.Type = "jwt"
+		}), `"test-gcp" is not a GCP IAM auth method`},
+		"missing audiences": {makeMethod(func(m AM) {
+			delete(m.Config, "BoundAudiences")
+		}), "BoundAudiences is required"},
+		"extra config": {makeMethod(func(m AM) {
+			m.Config["BoundIssuer"] = "https://legit.issuer.internal/"
+		}), "has invalid keys"},
+		"invalid CA cert": {makeMethod(func(m AM) {
+			m.Config["JWKSCACert"] = "invalid"
+		}), "error checking JWKSCACert"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				require.Nil(t, v)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, v)
+				require.Equal(t, "test-gcp", v.Name())
+				v.Stop()
+			}
+		})
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	oidcServer := oidcauthtest.Start(t)
+	_, privKey := oidcServer.SigningKeys()
+
+	const email = "consul-client@my-project.iam.gserviceaccount.com"
+
+	type computeEngine struct {
+		ProjectID     string `json:"project_id,omitempty"`
+		ProjectNumber int64  `json:"project_number,omitempty"`
+		Zone          string `json:"zone,omitempty"`
+		InstanceID    string `json:"instance_id,omitempty"`
+		InstanceName  string `json:"instance_name,omitempty"`
+	}
+	type google struct {
+		ComputeEngine *computeEngine `json:"compute_engine,omitempty"`
+	}
+	type privateClaims struct {
+		Email         string  `json:"email,omitempty"`
+		EmailVerified bool    `json:"email_verified"`
+		Google        *google `json:"google,omitempty"`
+	}
+
+	fullClaims := privateClaims{
+		Email:         email,
+		EmailVerified: true,
+		Google: &google{&computeEngine{
+			ProjectID:     "my-project",
+			ProjectNumber: 1234567890,
+			Zone:          "us-central1-a",
+			InstanceID:    "5678",
+			InstanceName:  "web-1",
+		}},
+	}
+
+	cases := map[string]struct {
+		config     map[string]interface{}
+		issuer     string
+		audience   string
+		claims     privateClaims
+		expectErr  string
+		expectVars map[string]string
+	}{
+		"success - full token": {
+			claims: fullClaims,
+			expectVars: map[string]string{
+				"service_account_email": email,
+				"service_account_id":    "1000",
+				"project_id":            "my-project",
+				"project_number":        "1234567890",
+				"zone":                  "us-central1-a",
+				"instance_id":           "5678",
+				"instance_name":         "web-1",
+			},
+		},
+		"success - standard token": {
+			claims: privateClaims{Email: email, EmailVerified: true},
+			expectVars: map[string]string{
+				"service_account_email": email,
+				"service_account_id":    "1000",
+				"project_id":            "my-project",
+				"project_number":        "",
+				"zone":                  "",
+				"instance_id":           "",
+				"instance_name":         "",
+			},
+		},
+		"success - bound service account and project": {
+			config: map[string]interface{}{
+				"BoundServiceAccounts": []string{"other@my-project.iam.gserviceaccount.com", email},
+				"BoundProjectIDs":      []string{"my-project"},
+			},
+			claims: fullClaims,
+		},
+		"wrong issuer": {
+			issuer:    "https://legit.issuer.internal/",
+			claims:    fullClaims,
+			expectErr: "error validating claims",
+		},
+		"wrong audience": {
+			audience:  "https://other.test",
+			claims:    fullClaims,
+			expectErr: "error validating claims",
+		},
+		"missing email": {
+			claims:    privateClaims{EmailVerified: true},
+			expectErr: "identity token has no service account email",
+		},
+		"unverified email": {
+			claims:    privateClaims{Email: email},
+			expectErr: "is not verified",
+		},
+		"service account not bound": {
+			config: map[string]interface{}{
+				"BoundServiceAccounts": []string{"other@my-project.iam.gserviceaccount.com"},
+			},
+			claims:    fullClaims,
+			expectErr: "is not permitted to login",
+		},
+		"project not bound": {
+			config: map[string]interface{}{
+				"BoundProjectIDs": []string{"other-project"},
+			},
+			claims:    fullClaims,
+			expectErr: `project "my-project" is not permitted to login`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"BoundAudiences": []string{"https://consul.test"},
+				"JWKSURL":        oidcServer.Addr() + "/certs",
+				"JWKSCACert":     oidcServer.CACert(),
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+			v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+				Name:   "test-gcp",
+				Type:   "gcp-iam",
+				Config: config,
+			})
+			require.NoError(t, err)
+			t.Cleanup(v.Stop)
+
+			issuer := tc.issuer
+			if issuer == "" {
+				issuer = googleIssuer
+			}
+			audience := tc.audience
+			if audience == "" {
+				audience = "https://consul.test"
+			}
+			cl := jwt.Claims{
+				Subject:   "1000",
+				Audience:  jwt.Audience{audience},
+				Issuer:    issuer,
+				NotBefore: jwt.NewNumericDate(time.Now().Add(-5 * time.Second)),
+				Expiry:    jwt.NewNumericDate(time.Now().Add(5 * time.Second)),
+			}
+			jwtData, err := oidcauthtest.SignJWT(privKey, cl, tc.claims)
+			require.NoError(t, err)
+
+			id, err := v.ValidateLogin(context.Background(), jwtData)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectVars != nil {
+				authmethod.RequireIdentityMatch(t, id, tc.expectVars,
+					`service_account_email == "`+email+`"`,
+					`project_id == "my-project"`,
+				)
+			}
+		})
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	v := &Validator{}
+	id := v.NewIdentity()
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"service_account_email": "",
+		"service_account_id":    "",
+		"project_id":            "",
+		"project_number":        "",
+		"zone":                  "",
+		"instance_id":           "",
+		"instance_name":         "",
+	},
+		`service_account_email == ""`,
+		`project_id == ""`,
+	)
+}
+
+func TestProjectFromServiceAccount(t *testing.T) {
+	require.Equal(t, "my-project", projectFromServiceAccount("sa@my-project.iam.gserviceaccount.com"))
+	require.Equal(t, "", projectFromServiceAccount("1234-compute@developer.gserviceaccount.com"))
+	require.Equal(t, "", projectFromServiceAccount("user@example.com"))
+}
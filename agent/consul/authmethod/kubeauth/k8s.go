@@ -189,6 +189,7 @@ func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*auth
 			Namespace: fields[serviceAccountNamespaceField],
 			Name:      fields[serviceAccountNameField],
 			UID:       fields[serviceAccountUIDField],
+			Labels:    sa.GetObjectMeta().GetLabels(),
 		},
 	}
 	for k, val := range fields {
@@ -224,4 +225,8 @@ type k8sFieldDetailsServiceAccount struct {
 	Namespace string `bexpr:"namespace"`
 	Name      string `bexpr:"name"`
 	UID       string `bexpr:"uid"`
+
+	// Labels of the service account, which can be selected by binding rules
+	// but are not projected since their keys vary between service accounts.
+	Labels map[string]string `bexpr:"labels"`
 }
@@ -173,6 +173,24 @@ func TestValidateLogin(t *testing.T) {
 			`serviceaccount.uid == "76091af4-4b56-11e9-ac4b-708b11801cbe"`,
 		)
 	})
+
+	// label the account
+	testSrv.SetServiceAccountLabels(map[string]string{"app": "web", "tier": "frontend"})
+
+	t.Run("valid bearer token with labels", func(t *testing.T) {
+		id, err := validator.ValidateLogin(context.Background(), goodJWT_B)
+		require.NoError(t, err)
+
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"serviceaccount.namespace": "default",
+			"serviceaccount.name":      "alternate-name",
+			"serviceaccount.uid":       "76091af4-4b56-11e9-ac4b-708b11801cbe",
+		},
+			`serviceaccount.labels.app == web`,
+			`serviceaccount.labels.tier matches "^front"`,
+			`"tier" in serviceaccount.labels`,
+		)
+	})
 }
 
 func TestNewValidator(t *testing.T) {
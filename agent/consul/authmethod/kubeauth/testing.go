@@ -87,6 +87,17 @@ func (s *TestAPIServer) SetAllowedServiceAccount(
 	s.replyStatus = createTokenReviewFound(namespace, name, uid, jwt)
 }
 
+// SetServiceAccountLabels sets the labels of the Service Account configured
+// with SetAllowedServiceAccount.
+func (s *TestAPIServer) SetServiceAccountLabels(labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.replyRead != nil {
+		s.replyRead.ObjectMeta.Labels = labels
+	}
+}
+
 // Stop stops the running TestAPIServer.
 func (s *TestAPIServer) Stop() {
 	s.srv.Close()
@@ -11,13 +11,15 @@ import (
 )
 
 func init() {
-	authmethod.Register("jwt", func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
-		v, err := NewValidator(logger, method)
-		if err != nil {
-			return nil, err
-		}
-		return v, nil
-	})
+	for _, typ := range []string{oidcauth.TypeJWT, oidcauth.TypeOIDC} {
+		authmethod.Register(typ, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+			v, err := NewValidator(logger, method)
+			if err != nil {
+				return nil, err
+			}
+			return v, nil
+		})
+	}
 }
 
 // Validator is the wrapper around the go-sso library that also conforms to the
@@ -75,6 +77,25 @@ func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*auth
 	return v.identityFromClaims(c), nil
 }
 
+// GetAuthCodeURL starts the OIDC authorization code flow and returns the URL
+// of the provider the user must be sent to. The statePayload is returned by
+// ClaimsFromAuthCode once the flow completes.
+func (v *Validator) GetAuthCodeURL(ctx context.Context, redirectURI string, statePayload interface{}) (string, error) {
+	return v.oa.GetAuthCodeURL(ctx, redirectURI, statePayload)
+}
+
+// ClaimsFromAuthCode completes the OIDC authorization code flow by exchanging
+// the code for an ID token with the provider, and returns the identity of the
+// user along with the statePayload passed to GetAuthCodeURL.
+func (v *Validator) ClaimsFromAuthCode(ctx context.Context, state, code string) (*authmethod.Identity, interface{}, error) {
+	c, payload, err := v.oa.ClaimsFromAuthCode(ctx, state, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v.identityFromClaims(c), payload, nil
+}
+
 func (v *Validator) identityFromClaims(c *oidcauth.Claims) *authmethod.Identity {
 	id := v.NewIdentity()
 	id.SelectableFields = &fieldDetails{
@@ -127,6 +148,14 @@ type Config struct {
 	OIDCDiscoveryURL    string            `json:",omitempty"`
 	OIDCDiscoveryCACert string            `json:",omitempty"`
 
+	// just for type=oidc
+	OIDCClientID        string   `json:",omitempty"`
+	OIDCClientSecret    string   `json:",omitempty"`
+	OIDCScopes          []string `json:",omitempty"`
+	OIDCACRValues       []string `json:",omitempty"`
+	AllowedRedirectURIs []string `json:",omitempty"`
+	VerboseOIDCLogging  bool     `json:",omitempty"`
+
 	// just for type=jwt
 	JWKSURL              string        `json:",omitempty"`
 	JWKSCACert           string        `json:",omitempty"`
@@ -151,6 +180,14 @@ func (c *Config) convertForLibrary(methodType string) *oidcauth.Config {
 		OIDCDiscoveryURL:    c.OIDCDiscoveryURL,
 		OIDCDiscoveryCACert: c.OIDCDiscoveryCACert,
 
+		// just for type=oidc
+		OIDCClientID:        c.OIDCClientID,
+		OIDCClientSecret:    c.OIDCClientSecret,
+		OIDCScopes:          c.OIDCScopes,
+		OIDCACRValues:       c.OIDCACRValues,
+		AllowedRedirectURIs: c.AllowedRedirectURIs,
+		VerboseOIDCLogging:  c.VerboseOIDCLogging,
+
 		// just for type=jwt
 		JWKSURL:              c.JWKSURL,
 		JWKSCACert:           c.JWKSCACert,
@@ -11,8 +11,8 @@ import (
 )
 
 func validateType(typ string) error {
-	if typ != "jwt" {
-		return fmt.Errorf("type should be %q", "jwt")
+	if typ != oidcauth.TypeJWT && typ != oidcauth.TypeOIDC {
+		return fmt.Errorf("type should be %q or %q", oidcauth.TypeJWT, oidcauth.TypeOIDC)
 	}
 	return nil
 }
@@ -61,6 +61,17 @@ func TestJWT_NewValidator(t *testing.T) {
 			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
 			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
 		}), ""},
+		"normal oidc": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+			method.Config["OIDCClientID"] = "abc"
+			method.Config["OIDCClientSecret"] = "def"
+			method.Config["AllowedRedirectURIs"] = []string{"http://localhost:8550/oidc/callback"}
+		}), ""},
+		"oidc without client": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+		}), "'OIDCClientID' must be set"},
 	} {
 		tc := tc
 		t.Run(name, func(t *testing.T) {
@@ -4,14 +4,81 @@
 package consul
 
 import (
-	"github.com/hashicorp/consul/agent/metadata"
+	"github.com/hashicorp/raft"
 	autopilot "github.com/hashicorp/raft-autopilot"
+
+	"github.com/hashicorp/consul/agent/metadata"
 )
 
+// autopilotNodeTypeReadReplica is the type of the read replica servers, which
+// are kept as non-voters.
+const autopilotNodeTypeReadReplica autopilot.NodeType = "read-replica"
+
 func (s *Server) autopilotPromoter() autopilot.Promoter {
-	return autopilot.DefaultPromoter()
+	return &readReplicaPromoter{
+		Promoter: newAutopilotPromotionPromoter(s.config.AutopilotPromotion, isReadReplica),
+	}
+}
+
+// autopilotServerInfo is the extension of the autopilot servers.
+type autopilotServerInfo struct {
+	ReadReplica bool
+}
+
+func (_ *Server) autopilotServerExt(srv *metadata.Server) interface{} {
+	return &autopilotServerInfo{ReadReplica: srv.ReadReplica}
 }
 
-func (_ *Server) autopilotServerExt(_ *metadata.Server) interface{} {
-	return nil
+func isReadReplica(srv *autopilot.ServerState) bool {
+	info, ok := srv.Server.Ext.(*autopilotServerInfo)
+	return ok && info.ReadReplica
+}
+
+// readReplicaPromoter promotes the servers like the configured promoter, except
+// for the read replicas which are never promoted to voters so that they don't
+// count towards the quorum and can't become the leader. The read replicas which
+// are voters, for example those which were restarted as read replicas, are
+// demoted.
+type readReplicaPromoter struct {
+	autopilot.Promoter
+}
+
+func (p *readReplicaPromoter) GetNodeTypes(c *autopilot.Config, s *autopilot.State) map[raft.ServerID]autopilot.NodeType {
+	types := p.Promoter.GetNodeTypes(c, s)
+	for id, srv := range s.Servers {
+		if isReadReplica(srv) {
+			types[id] = autopilotNodeTypeReadReplica
+		}
+	}
+	return types
+}
+
+func (p *readReplicaPromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	changes := p.Promoter.CalculatePromotionsAndDemotions(c, s)
+
+	promotions := changes.Promotions[:0]
+	for _, id := range changes.Promotions {
+		if srv, ok := s.Servers[id]; ok && isReadReplica(srv) {
+			continue
+		}
+		promotions = append(promotions, id)
+	}
+	changes.Promotions = promotions
+
+	// The configured promoter may already demote some of the read replicas.
+	// The leader is never demoted, it must first step down.
+	demoted := make(map[raft.ServerID]struct{}, len(changes.Demotions))
+	for _, id := range changes.Demotions {
+		demoted[id] = struct{}{}
+	}
+	for id, srv := range s.Servers {
+		if !isReadReplica(srv) || srv.State != autopilot.RaftVoter || id == s.Leader {
+			continue
+		}
+		if _, ok := demoted[id]; ok {
+			continue
+		}
+		changes.Demotions = append(changes.Demotions, id)
+	}
+	return changes
 }
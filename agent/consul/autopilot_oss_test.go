@@ -0,0 +1,98 @@
+//go:build !consulent
+// +build !consulent
+
+package consul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/metadata"
+)
+
+func TestReadReplicaPromoter(t *testing.T) {
+	s := &Server{config: &Config{}}
+	server := func(id string, state autopilot.RaftState, readReplica bool) *autopilot.ServerState {
+		return &autopilot.ServerState{
+			Server: autopilot.Server{
+				ID:  raft.ServerID(id),
+				Ext: s.autopilotServerExt(&metadata.Server{ReadReplica: readReplica}),
+			},
+			State: state,
+			Health: autopilot.ServerHealth{
+				Healthy:     true,
+				StableSince: time.Now().Add(-time.Hour),
+			},
+		}
+	}
+	state := &autopilot.State{
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"leader":          server("leader", autopilot.RaftLeader, false),
+			"server":          server("server", autopilot.RaftNonVoter, false),
+			"replica":         server("replica", autopilot.RaftNonVoter, true),
+			"voter-replica":   server("voter-replica", autopilot.RaftVoter, true),
+			"existing-server": server("existing-server", autopilot.RaftVoter, false),
+		},
+	}
+
+	promoter := s.autopilotPromoter()
+
+	require.Equal(t, map[raft.ServerID]autopilot.NodeType{
+		"leader":          autopilot.NodeVoter,
+		"server":          autopilot.NodeVoter,
+		"replica":         autopilotNodeTypeReadReplica,
+		"voter-replica":   autopilotNodeTypeReadReplica,
+		"existing-server": autopilot.NodeVoter,
+	}, promoter.GetNodeTypes(&autopilot.Config{}, state))
+
+	changes := promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Equal(t, []raft.ServerID{"server"}, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"voter-replica"}, changes.Demotions)
+}
+
+// demotingPromoter is a promoter which demotes the given servers.
+type demotingPromoter struct {
+	autopilot.Promoter
+	demotions []raft.ServerID
+}
+
+func (p *demotingPromoter) CalculatePromotionsAndDemotions(_ *autopilot.Config, _ *autopilot.State) autopilot.RaftChanges {
+	return autopilot.RaftChanges{Demotions: p.demotions}
+}
+
+func TestReadReplicaPromoter_Demotions(t *testing.T) {
+	s := &Server{config: &Config{}}
+	server := func(id string, state autopilot.RaftState) *autopilot.ServerState {
+		return &autopilot.ServerState{
+			Server: autopilot.Server{
+				ID:  raft.ServerID(id),
+				Ext: s.autopilotServerExt(&metadata.Server{ReadReplica: true}),
+			},
+			State: state,
+		}
+	}
+	state := &autopilot.State{
+		Leader: "leader-replica",
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"leader-replica":  server("leader-replica", autopilot.RaftVoter),
+			"demoted-replica": server("demoted-replica", autopilot.RaftVoter),
+		},
+	}
+
+	// The read replica already demoted by the configured promoter is only
+	// demoted once, and the leader isn't demoted.
+	promoter := &readReplicaPromoter{
+		Promoter: &demotingPromoter{demotions: []raft.ServerID{"demoted-replica"}},
+	}
+	changes := promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Empty(t, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"demoted-replica"}, changes.Demotions)
+
+	promoter = &readReplicaPromoter{Promoter: &demotingPromoter{}}
+	changes = promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Equal(t, []raft.ServerID{"demoted-replica"}, changes.Demotions)
+}
@@ -0,0 +1,169 @@
+package consul
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+)
+
+const (
+	autopilotPromotionStable = "stable"
+	autopilotPromotionZones  = "zones"
+)
+
+// newAutopilotPromotionPromoter returns the promoter of the configured
+// promotion strategy. The servers for which ineligible returns true are never
+// promoted by the strategy, nor counted as voters of their zone.
+func newAutopilotPromotionPromoter(config AutopilotPromotionConfig, ineligible func(*autopilot.ServerState) bool) autopilot.Promoter {
+	if (config.Strategy == "" || config.Strategy == autopilotPromotionStable) && config.MinServerUptime == 0 {
+		return autopilot.DefaultPromoter()
+	}
+	return &promotionStrategyPromoter{
+		Promoter:   autopilot.DefaultPromoter(),
+		config:     config,
+		ineligible: ineligible,
+		now:        time.Now,
+		aliveSince: make(map[raft.ServerID]time.Time),
+	}
+}
+
+// promotionStrategyPromoter promotes the servers once they are stable and have
+// been alive for the minimum uptime. With the "zones" strategy it only promotes
+// servers until each zone has the configured number of voters, and demotes the
+// voters in excess in a zone, the unhealthy ones first. The servers without
+// the zone node meta form a zone of their own.
+type promotionStrategyPromoter struct {
+	autopilot.Promoter
+
+	config     AutopilotPromotionConfig
+	ineligible func(*autopilot.ServerState) bool
+
+	// now is replaced in tests.
+	now func() time.Time
+
+	// aliveSince is the time the leader first saw each server alive, since
+	// it was last seen in another status.
+	lock       sync.Mutex
+	aliveSince map[raft.ServerID]time.Time
+}
+
+func (p *promotionStrategyPromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	now := p.now()
+	uptimes := p.updateUptimes(s, now)
+
+	minStableDuration := s.ServerStabilizationTime(c)
+	promotable := func(id raft.ServerID, srv *autopilot.ServerState) bool {
+		return srv.State == autopilot.RaftNonVoter &&
+			srv.Health.IsStable(now, minStableDuration) &&
+			uptimes[id] >= p.config.MinServerUptime
+	}
+
+	var changes autopilot.RaftChanges
+	if p.config.Strategy != autopilotPromotionZones {
+		for id, srv := range s.Servers {
+			if !p.ineligible(srv) && promotable(id, srv) {
+				changes.Promotions = append(changes.Promotions, id)
+			}
+		}
+		return changes
+	}
+
+	type zone struct {
+		voters     []*autopilot.ServerState
+		candidates []*autopilot.ServerState
+	}
+	zones := make(map[string]*zone)
+	for id, srv := range s.Servers {
+		if p.ineligible(srv) {
+			continue
+		}
+		name := srv.Server.Meta[p.config.ZoneMetaKey]
+		z, ok := zones[name]
+		if !ok {
+			z = &zone{}
+			zones[name] = z
+		}
+		if srv.HasVotingRights() {
+			z.voters = append(z.voters, srv)
+		} else if promotable(id, srv) {
+			z.candidates = append(z.candidates, srv)
+		}
+	}
+
+	for _, z := range zones {
+		missing := p.config.VotersPerZone - len(z.voters)
+		if missing > 0 {
+			// Promote the servers which have been stable the longest.
+			sort.Slice(z.candidates, func(i, j int) bool {
+				return serverStableBefore(z.candidates[i], z.candidates[j])
+			})
+			for i := 0; i < missing && i < len(z.candidates); i++ {
+				changes.Promotions = append(changes.Promotions, z.candidates[i].Server.ID)
+			}
+			continue
+		}
+
+		// Demote the unhealthy voters first, then the ones which have been
+		// stable for the shortest time. The leader is never demoted.
+		sort.Slice(z.voters, func(i, j int) bool {
+			left, right := z.voters[i], z.voters[j]
+			if left.Health.Healthy != right.Health.Healthy {
+				return !left.Health.Healthy
+			}
+			return serverStableBefore(right, left)
+		})
+		excess := -missing
+		for _, srv := range z.voters {
+			if excess == 0 {
+				break
+			}
+			if srv.Server.ID == s.Leader {
+				continue
+			}
+			changes.Demotions = append(changes.Demotions, srv.Server.ID)
+			excess--
+		}
+	}
+	return changes
+}
+
+// updateUptimes records the servers seen alive for the first time, forgets
+// the ones which aren't alive anymore, and returns the uptimes of the alive
+// servers.
+func (p *promotionStrategyPromoter) updateUptimes(s *autopilot.State, now time.Time) map[raft.ServerID]time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	uptimes := make(map[raft.ServerID]time.Duration)
+	for id, srv := range s.Servers {
+		if srv.Server.NodeStatus != autopilot.NodeAlive {
+			delete(p.aliveSince, id)
+			continue
+		}
+		since, ok := p.aliveSince[id]
+		if !ok {
+			since = now
+			p.aliveSince[id] = since
+		}
+		uptimes[id] = now.Sub(since)
+	}
+	for id := range p.aliveSince {
+		if _, ok := s.Servers[id]; !ok {
+			delete(p.aliveSince, id)
+		}
+	}
+	return uptimes
+}
+
+// serverStableBefore returns whether the left server has been stable since
+// before the right one, ordering them by ID when they have been stable since
+// the same time so that the choices are deterministic.
+func serverStableBefore(left, right *autopilot.ServerState) bool {
+	if !left.Health.StableSince.Equal(right.Health.StableSince) {
+		return left.Health.StableSince.Before(right.Health.StableSince)
+	}
+	return left.Server.ID < right.Server.ID
+}
@@ -0,0 +1,108 @@
+package consul
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/stretchr/testify/require"
+)
+
+func testPromotionServer(id, zone string, state autopilot.RaftState, healthy bool, stableFor time.Duration) *autopilot.ServerState {
+	return &autopilot.ServerState{
+		Server: autopilot.Server{
+			ID:         raft.ServerID(id),
+			NodeStatus: autopilot.NodeAlive,
+			Meta:       map[string]string{"zone": zone},
+		},
+		State: state,
+		Health: autopilot.ServerHealth{
+			Healthy:     healthy,
+			StableSince: time.Now().Add(-stableFor),
+		},
+	}
+}
+
+func sortedServerIDs(ids []raft.ServerID) []raft.ServerID {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestAutopilotPromotion_Stable(t *testing.T) {
+	never := func(*autopilot.ServerState) bool { return false }
+
+	_, ok := newAutopilotPromotionPromoter(AutopilotPromotionConfig{}, never).(*autopilot.StablePromoter)
+	require.True(t, ok)
+
+	promoter := newAutopilotPromotionPromoter(AutopilotPromotionConfig{MinServerUptime: time.Minute}, never).(*promotionStrategyPromoter)
+	now := time.Now()
+	promoter.now = func() time.Time { return now }
+
+	state := &autopilot.State{
+		Leader: "leader",
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"leader": testPromotionServer("leader", "", autopilot.RaftLeader, true, time.Hour),
+			"server": testPromotionServer("server", "", autopilot.RaftNonVoter, true, time.Hour),
+		},
+	}
+
+	// The server isn't promoted until the leader saw it alive for the minimum
+	// uptime.
+	changes := promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Empty(t, changes.Promotions)
+
+	now = now.Add(time.Minute)
+	changes = promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Equal(t, []raft.ServerID{"server"}, changes.Promotions)
+
+	// The uptime restarts once the server was seen failed.
+	state.Servers["server"].Server.NodeStatus = autopilot.NodeFailed
+	promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	state.Servers["server"].Server.NodeStatus = autopilot.NodeAlive
+	changes = promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Empty(t, changes.Promotions)
+}
+
+func TestAutopilotPromotion_Zones(t *testing.T) {
+	config := AutopilotPromotionConfig{
+		Strategy:      autopilotPromotionZones,
+		ZoneMetaKey:   "zone",
+		VotersPerZone: 2,
+	}
+	replica := func(srv *autopilot.ServerState) bool { return srv.Server.ID == "c-replica" }
+	promoter := newAutopilotPromotionPromoter(config, replica)
+
+	state := &autopilot.State{
+		Leader: "a-leader",
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			// Zone a has one voter missing, the server stable the longest
+			// is promoted.
+			"a-leader": testPromotionServer("a-leader", "a", autopilot.RaftLeader, true, time.Hour),
+			"a-newer":  testPromotionServer("a-newer", "a", autopilot.RaftNonVoter, true, time.Minute),
+			"a-older":  testPromotionServer("a-older", "a", autopilot.RaftNonVoter, true, time.Hour),
+
+			// Zone b has one voter too many, the unhealthy one is demoted.
+			"b-1":         testPromotionServer("b-1", "b", autopilot.RaftVoter, true, time.Hour),
+			"b-2":         testPromotionServer("b-2", "b", autopilot.RaftVoter, true, time.Hour),
+			"b-unhealthy": testPromotionServer("b-unhealthy", "b", autopilot.RaftVoter, false, 0),
+
+			// Zone c has only an unhealthy server besides the read replica,
+			// which isn't promoted.
+			"c-unhealthy": testPromotionServer("c-unhealthy", "c", autopilot.RaftNonVoter, false, 0),
+			"c-replica":   testPromotionServer("c-replica", "c", autopilot.RaftNonVoter, true, time.Hour),
+		},
+	}
+
+	changes := promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Equal(t, []raft.ServerID{"a-older"}, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"b-unhealthy"}, changes.Demotions)
+
+	// The leader is never demoted, even when it is in excess.
+	state.Servers["a-older"].State = autopilot.RaftVoter
+	state.Servers["a-newer"].State = autopilot.RaftVoter
+	changes = promoter.CalculatePromotionsAndDemotions(&autopilot.Config{}, state)
+	require.Empty(t, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"a-newer", "b-unhealthy"}, sortedServerIDs(changes.Demotions))
+}
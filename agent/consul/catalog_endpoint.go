@@ -60,6 +60,10 @@ var CatalogCounters = []prometheus.CounterDefinition{
 }
 
 var CatalogSummaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"catalog", "batch"},
+		Help: "Measures the time it takes to complete a catalog batch operation.",
+	},
 	{
 		Name: []string{"catalog", "deregister"},
 		Help: "Measures the time it takes to complete a catalog deregister operation.",
@@ -111,8 +115,19 @@ func (c *Catalog) Register(args *structs.RegisterRequest, reply *struct{}) error
 	}
 	defer metrics.MeasureSince([]string{"catalog", "register"}, time.Now())
 
+	if err := c.registerPreApply(args.Token, args); err != nil {
+		return err
+	}
+
+	_, err := c.srv.raftApply(structs.RegisterRequestType, args)
+	return err
+}
+
+// registerPreApply verifies the register request using the given token, and
+// fixes up its services and checks before it is applied to Raft.
+func (c *Catalog) registerPreApply(token string, args *structs.RegisterRequest) error {
 	// Fetch the ACL token, if any.
-	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, nil)
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(token, &args.EnterpriseMeta, nil)
 	if err != nil {
 		return err
 	}
@@ -172,8 +187,11 @@ func (c *Catalog) Register(args *structs.RegisterRequest, reply *struct{}) error
 		return err
 	}
 
-	_, err = c.srv.raftApply(structs.RegisterRequestType, args)
-	return err
+	// Node-scoped service identities restrict where the service can go.
+	if args.Service != nil {
+		return c.srv.ACLResolver.VetServiceNode(authz, args.Service.Service, args.Node)
+	}
+	return nil
 }
 
 // nodePreApply does the verification of a node before it is applied to Raft.
@@ -378,13 +396,24 @@ func (c *Catalog) Deregister(args *structs.DeregisterRequest, reply *struct{}) e
 	}
 	defer metrics.MeasureSince([]string{"catalog", "deregister"}, time.Now())
 
+	if err := c.deregisterPreApply(args.Token, args); err != nil {
+		return err
+	}
+
+	_, err := c.srv.raftApply(structs.DeregisterRequestType, args)
+	return err
+}
+
+// deregisterPreApply verifies the deregister request using the given token
+// before it is applied to Raft.
+func (c *Catalog) deregisterPreApply(token string, args *structs.DeregisterRequest) error {
 	// Verify the args
 	if args.Node == "" {
 		return fmt.Errorf("Must provide node")
 	}
 
 	// Fetch the ACL token, if any.
-	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, nil)
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(token, &args.EnterpriseMeta, nil)
 	if err != nil {
 		return err
 	}
@@ -412,14 +441,73 @@ func (c *Catalog) Deregister(args *structs.DeregisterRequest, reply *struct{}) e
 		}
 	}
 
-	if err := vetDeregisterWithACL(authz, args, ns, nc); err != nil {
+	return vetDeregisterWithACL(authz, args, ns, nc)
+}
+
+// Batch is used to register and deregister many nodes, services and checks
+// atomically in a single Raft transaction.
+func (c *Catalog) Batch(args *structs.CatalogBatchRequest, reply *struct{}) error {
+	if !c.srv.config.PeeringTestAllowPeerRegistrations {
+		for _, reg := range args.Register {
+			if hasPeerNameInRequest(reg) {
+				return fmt.Errorf("cannot register requests with PeerName in them")
+			}
+		}
+	}
+
+	if done, err := c.srv.ForwardRPC("Catalog.Batch", args, reply); done {
 		return err
 	}
+	defer metrics.MeasureSince([]string{"catalog", "batch"}, time.Now())
+
+	if len(args.Register) == 0 && len(args.Deregister) == 0 {
+		return fmt.Errorf("Must provide at least one registration or deregistration")
+	}
+	if size := len(args.Register) + len(args.Deregister); size > structs.MaxCatalogBatchEntries {
+		return fmt.Errorf("Batch contains too many entries (%d > %d)", size, structs.MaxCatalogBatchEntries)
+	}
+	if !c.srv.canApplyCatalogBatches() {
+		return fmt.Errorf("Catalog batches are not supported until all servers are upgraded")
+	}
 
-	_, err = c.srv.raftApply(structs.DeregisterRequestType, args)
+	for i, reg := range args.Register {
+		if reg == nil {
+			return fmt.Errorf("Register[%d]: missing registration", i)
+		}
+		reg.Datacenter = args.Datacenter
+		if err := c.registerPreApply(args.Token, reg); err != nil {
+			return fmt.Errorf("Register[%d]: %w", i, err)
+		}
+	}
+	for i, dereg := range args.Deregister {
+		if dereg == nil {
+			return fmt.Errorf("Deregister[%d]: missing deregistration", i)
+		}
+		dereg.Datacenter = args.Datacenter
+		if err := c.deregisterPreApply(args.Token, dereg); err != nil {
+			return fmt.Errorf("Deregister[%d]: %w", i, err)
+		}
+	}
+
+	_, err := c.srv.raftApply(structs.CatalogBatchRequestType, args)
 	return err
 }
 
+// canApplyCatalogBatches returns whether the catalog batches are accepted.
+// They are only once every server of the datacenter can apply them, otherwise
+// the older servers would fail on their Raft entries. This is remembered once
+// true.
+func (s *Server) canApplyCatalogBatches() bool {
+	if s.catalogBatchReady.Load() {
+		return true
+	}
+	if ok, found := ServersInDCSupportFeature(s, s.config.Datacenter, "cb"); !ok || !found {
+		return false
+	}
+	s.catalogBatchReady.Store(true)
+	return true
+}
+
 // vetDeregisterWithACL applies the given ACL's policy to the catalog update and
 // determines if it is allowed. Since the catalog deregister request is so
 // dynamic, this is a pretty complex algorithm and was worth breaking out of the
@@ -554,6 +642,7 @@ func isUnmodified(opts structs.QueryOptions, index uint64) bool {
 // ListServices is used to query the services in a DC.
 // Returns services as a map of service names to available tags.
 func (c *Catalog) ListServices(args *structs.DCSpecificRequest, reply *structs.IndexedServices) error {
+	c.srv.forwardPageIfBehind(&args.QueryOptions, args.PageOptions)
 	if done, err := c.srv.ForwardRPC("Catalog.ListServices", args, reply); done {
 		return err
 	}
@@ -613,10 +702,38 @@ func (c *Catalog) ListServices(args *structs.DCSpecificRequest, reply *structs.I
 
 			c.srv.filterACLWithAuthorizer(authz, reply)
 
+			// The services are paginated once filtered, so that the pages
+			// are full and the cursors only point at readable services.
+			if err := args.CheckIndex(reply.Index); err != nil {
+				return err
+			}
+			reply.Services, reply.NextCursor = pageServices(args.PageOptions, reply.Services)
+
 			return nil
 		})
 }
 
+// pageServices returns the requested page of the services, which are ordered
+// by name, and the cursor of the next page.
+func pageServices(page structs.PageOptions, services structs.Services) (structs.Services, string) {
+	if !page.IsPaginated() {
+		return services, ""
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	start, end, next := page.Page(names)
+	paged := make(structs.Services, end-start)
+	for _, name := range names[start:end] {
+		paged[name] = services[name]
+	}
+	return paged, next
+}
+
 func servicesTagsByName(services []*structs.ServiceNode) structs.Services {
 	unique := make(map[string]map[string]struct{})
 	for _, svc := range services {
@@ -672,6 +789,50 @@ func (c *Catalog) ServiceList(args *structs.DCSpecificRequest, reply *structs.In
 		})
 }
 
+// ServiceTombstones is used to query the service instances that were recently
+// removed from the catalog, along with the index and reason of their removal.
+func (c *Catalog) ServiceTombstones(args *structs.ServiceTombstonesRequest, reply *structs.IndexedServiceTombstones) error {
+	if done, err := c.srv.ForwardRPC("Catalog.ServiceTombstones", args, reply); done {
+		return err
+	}
+
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	filter, err := bexpr.CreateFilter(args.Filter, nil, reply.Tombstones)
+	if err != nil {
+		return err
+	}
+
+	return c.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, stones, err := state.ServiceTombstones(ws, args.ServiceName, &args.EnterpriseMeta, args.PeerName)
+			if err != nil {
+				return err
+			}
+
+			raw, err := filter.Execute(stones)
+			if err != nil {
+				return err
+			}
+			reply.Index, reply.Tombstones = index, raw.(structs.ServiceTombstones)
+
+			// Note: we filter the results with ACLs *after* applying the user-supplied
+			// bexpr filter, to ensure QueryMeta.ResultsFilteredByACLs does not include
+			// results that would be filtered out even if the user did have permission.
+			c.srv.filterACLWithAuthorizer(authz, reply)
+			return nil
+		})
+}
+
 // ServiceNodes returns all the nodes registered as part of a service.
 func (c *Catalog) ServiceNodes(args *structs.ServiceSpecificRequest, reply *structs.IndexedServiceNodes) error {
 	if done, err := c.srv.ForwardRPC("Catalog.ServiceNodes", args, reply); done {
@@ -692,11 +853,19 @@ func (c *Catalog) ServiceNodes(args *structs.ServiceSpecificRequest, reply *stru
 		}
 
 	default:
+		// Resolve what we can of the filters using the catalog indexes, so
+		// that only the matching instances are read.
+		indexedFilter := serviceIndexedFilter(args, serviceNodeIndexedSelectors)
+
 		f = func(ws memdb.WatchSet, s *state.Store) (uint64, structs.ServiceNodes, error) {
 			if args.ServiceAddress != "" {
 				return s.ServiceAddressNodes(ws, args.ServiceAddress, &args.EnterpriseMeta, args.PeerName)
 			}
 
+			if !indexedFilter.IsEmpty() {
+				return s.ServiceNodesWithFilter(ws, args.ServiceName, indexedFilter, &args.EnterpriseMeta, args.PeerName)
+			}
+
 			if args.TagFilter {
 				tags := args.ServiceTags
 				// DEPRECATED (singular-service-tag) - remove this when backwards RPC compat
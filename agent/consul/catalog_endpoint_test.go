@@ -264,6 +264,61 @@ node "foo" {
 	}
 }
 
+func TestCatalog_Register_ACLServiceIdentityNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1", testrpc.WithToken("root"))
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	token, err := upsertTestToken(codec, "root", "dc1", func(token *structs.ACLToken) {
+		token.ServiceIdentities = []*structs.ACLServiceIdentity{{ServiceName: "web", NodePrefix: "web-"}}
+	})
+	require.NoError(t, err)
+
+	register := func(node, service, token string) error {
+		args := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: service,
+				Port:    8000,
+			},
+			WriteRequest: structs.WriteRequest{Token: token},
+		}
+		var out struct{}
+		return msgpackrpc.CallWithCodec(codec, "Catalog.Register", &args, &out)
+	}
+
+	// The nodes are registered with the root token, the service identity
+	// only grants the service.
+	for _, node := range []string{"web-1", "db-1"} {
+		require.NoError(t, register(node, "consul-agent", "root"))
+	}
+
+	require.NoError(t, register("web-1", "web", token.SecretID))
+	require.NoError(t, register("web-1", "web-sidecar-proxy", token.SecretID))
+
+	err = register("db-1", "web", token.SecretID)
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+	require.Contains(t, err.Error(), `registering service "web" on node "db-1"`)
+
+	err = register("db-1", "web-sidecar-proxy", token.SecretID)
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+}
+
 func createTokenFull(t *testing.T, cc rpc.ClientCodec, policyRules string) *structs.ACLToken {
 	t.Helper()
 	return createTokenWithPolicyNameFull(t, cc, "the-policy", policyRules, "root")
@@ -541,6 +596,147 @@ func TestCatalog_Register_ConnectNative(t *testing.T) {
 	assert.True(t, v.ServiceConnect.Native)
 }
 
+func TestCatalog_Batch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	var out struct{}
+
+	// An empty batch is rejected.
+	err := msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &structs.CatalogBatchRequest{Datacenter: "dc1"}, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one registration")
+
+	// So is a batch with too many entries.
+	tooLarge := structs.CatalogBatchRequest{Datacenter: "dc1"}
+	for i := 0; i <= structs.MaxCatalogBatchEntries; i++ {
+		tooLarge.Deregister = append(tooLarge.Deregister, &structs.DeregisterRequest{Node: fmt.Sprintf("ext%d", i)})
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &tooLarge, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too many entries")
+
+	arg := structs.CatalogBatchRequest{
+		Datacenter: "dc1",
+		Register: []*structs.RegisterRequest{
+			{
+				Node:    "ext1",
+				Address: "10.0.0.1",
+				Service: &structs.NodeService{Service: "billing", Port: 8080},
+			},
+			{
+				Node:    "ext2",
+				Address: "10.0.0.2",
+				Service: &structs.NodeService{Service: "billing", Port: 8080},
+			},
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &arg, &out))
+
+	req := structs.ServiceSpecificRequest{
+		Datacenter:  "dc1",
+		ServiceName: "billing",
+	}
+	var resp structs.IndexedServiceNodes
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ServiceNodes", &req, &resp))
+	require.Len(t, resp.ServiceNodes, 2)
+
+	// An invalid entry rejects the whole batch.
+	arg = structs.CatalogBatchRequest{
+		Datacenter: "dc1",
+		Register: []*structs.RegisterRequest{
+			{
+				Node:    "ext3",
+				Address: "10.0.0.3",
+				Service: &structs.NodeService{Service: "billing", Port: 8080},
+			},
+			{
+				Node:    "ext4",
+				Address: "10.0.0.4",
+				Service: &structs.NodeService{Service: "billing", Port: 8080, Address: "0.0.0.0"},
+			},
+		},
+		Deregister: []*structs.DeregisterRequest{
+			{Node: "ext1"},
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &arg, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Register[1]")
+
+	resp = structs.IndexedServiceNodes{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ServiceNodes", &req, &resp))
+	require.Len(t, resp.ServiceNodes, 2)
+}
+
+func TestCatalog_Batch_MixedVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Bootstrap = false
+		c.OverrideInitialSerfTags = func(tags map[string]string) {
+			delete(tags, "ft_cb")
+		}
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	retry.Run(t, func(r *retry.R) {
+		if ok, _ := ServersInDCSupportFeature(s1, "dc1", "cb"); ok {
+			r.Fatal("expected the older server to be known")
+		}
+	})
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	arg := structs.CatalogBatchRequest{
+		Datacenter: "dc1",
+		Register: []*structs.RegisterRequest{
+			{
+				Node:    "ext1",
+				Address: "10.0.0.1",
+				Service: &structs.NodeService{Service: "billing", Port: 8080},
+			},
+		},
+	}
+	var out struct{}
+
+	// The older server can't apply the batches, so they are rejected.
+	err := msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &arg, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not supported until all servers are upgraded")
+
+	// Once it is upgraded the batches are applied.
+	updateSerfTags(s2, "ft_cb", "1")
+	retry.Run(t, func(r *retry.R) {
+		require.NoError(r, msgpackrpc.CallWithCodec(codec, "Catalog.Batch", &arg, &out))
+	})
+
+	_, nodes, err := s1.fsm.State().ServiceNodes(nil, "billing", nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+}
+
 func TestCatalog_Deregister(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1562,6 +1758,106 @@ func TestCatalog_ListServices_Filter(t *testing.T) {
 	})
 }
 
+func TestCatalog_ListServices_Pagination(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServer(t)
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	for i, name := range []string{"api", "db", "web"} {
+		require.NoError(t, s1.fsm.State().EnsureRegistration(uint64(100+i), &structs.RegisterRequest{
+			Node:    "foo",
+			Address: "127.0.0.1",
+			Service: &structs.NodeService{ID: name, Service: name},
+		}))
+	}
+
+	list := func(t *testing.T, page structs.PageOptions) *structs.IndexedServices {
+		args := structs.DCSpecificRequest{Datacenter: "dc1", PageOptions: page}
+		out := new(structs.IndexedServices)
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ListServices", &args, out))
+		return out
+	}
+
+	// The services are paged in order of their name, after the consul service.
+	out := list(t, structs.PageOptions{Limit: 2})
+	require.Len(t, out.Services, 2)
+	require.Contains(t, out.Services, "api")
+	require.Contains(t, out.Services, "consul")
+	require.Equal(t, "consul", out.NextCursor)
+	cursor, index := out.NextCursor, out.Index
+
+	out = list(t, structs.PageOptions{Limit: 2, Cursor: cursor, CursorIndex: index})
+	require.Len(t, out.Services, 2)
+	require.Contains(t, out.Services, "db")
+	require.Contains(t, out.Services, "web")
+	require.Empty(t, out.NextCursor)
+
+	// The next pages are rejected once the services changed.
+	require.NoError(t, s1.fsm.State().DeleteService(200, "foo", "db", nil, ""))
+	args := structs.DCSpecificRequest{
+		Datacenter:  "dc1",
+		PageOptions: structs.PageOptions{Limit: 2, Cursor: cursor, CursorIndex: index},
+	}
+	err := msgpackrpc.CallWithCodec(codec, "Catalog.ListServices", &args, new(structs.IndexedServices))
+	require.True(t, structs.IsErrPaginationConflict(err))
+
+	// Without pagination all of the services are returned.
+	out = list(t, structs.PageOptions{})
+	require.Len(t, out.Services, 3)
+	require.Empty(t, out.NextCursor)
+}
+
+func TestCatalog_ListServices_PaginationStale(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServer(t)
+	_, s2 := testServerDCBootstrap(t, "dc1", false)
+
+	joinLAN(t, s2, s1)
+	retry.Run(t, func(r *retry.R) { r.Check(wantRaft([]*Server{s1, s2})) })
+	testrpc.WaitForLeader(t, s2.RPC, "dc1")
+
+	// The services are only in the state of the leader, as if the follower
+	// was lagging behind it.
+	for i, name := range []string{"api", "db", "web"} {
+		require.NoError(t, s1.fsm.State().EnsureRegistration(uint64(1000+i), &structs.RegisterRequest{
+			Node:    "foo",
+			Address: "127.0.0.1",
+			Service: &structs.NodeService{ID: name, Service: name},
+		}))
+	}
+
+	list := func(t *testing.T, s *Server, page structs.PageOptions) *structs.IndexedServices {
+		codec := rpcClient(t, s)
+		args := structs.DCSpecificRequest{Datacenter: "dc1", PageOptions: page}
+		args.AllowStale = true
+		out := new(structs.IndexedServices)
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ListServices", &args, out))
+		return out
+	}
+
+	out := list(t, s1, structs.PageOptions{Limit: 2})
+	require.Equal(t, "consul", out.NextCursor)
+	require.Equal(t, uint64(1002), out.Index)
+
+	// The follower forwards the next page to the leader, as it didn't apply
+	// the log up to the index of the first page.
+	out = list(t, s2, structs.PageOptions{Limit: 2, Cursor: out.NextCursor, CursorIndex: out.Index})
+	require.Len(t, out.Services, 2)
+	require.Contains(t, out.Services, "db")
+	require.Contains(t, out.Services, "web")
+	require.Empty(t, out.NextCursor)
+}
+
 func TestCatalog_ListServices_Blocking(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
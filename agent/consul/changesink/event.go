@@ -0,0 +1,132 @@
+// Package changesink pushes the changes of the catalog and of the config
+// entries to external systems, so that they can react to them without
+// polling the HTTP API.
+package changesink
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+// The topics that can be pushed to a sink.
+const (
+	// TopicCatalog is the topic of the changes of the service instances and
+	// of their health.
+	TopicCatalog = "catalog"
+
+	// TopicConfigEntries is the topic of the changes of the config entries.
+	TopicConfigEntries = "config-entries"
+)
+
+// Topics are the topics of the event publisher whose events are pushed for
+// each topic of a sink.
+var Topics = map[string][]stream.Topic{
+	TopicCatalog: {state.EventTopicServiceHealth},
+	TopicConfigEntries: {
+		state.EventTopicMeshConfig,
+		state.EventTopicServiceResolver,
+		state.EventTopicIngressGateway,
+		state.EventTopicServiceIntentions,
+		state.EventTopicServiceDefaults,
+		state.EventTopicAPIGateway,
+		state.EventTopicTCPRoute,
+		state.EventTopicHTTPRoute,
+		state.EventTopicInlineCertificate,
+		state.EventTopicBoundAPIGateway,
+		state.EventTopicAPIGatewayPolicy,
+	},
+}
+
+// The operations of the events.
+const (
+	OpUpsert = "upsert"
+	OpDelete = "delete"
+)
+
+// Event is a change pushed to a sink, encoded as JSON.
+type Event struct {
+	// Index is the Raft index of the change. The events of a topic are
+	// pushed in the order of their index, but an event can be pushed more
+	// than once, so receivers must be idempotent.
+	Index uint64 `json:"index"`
+
+	// Topic is the topic of the event, either TopicCatalog or
+	// TopicConfigEntries.
+	Topic string `json:"topic"`
+
+	// Op is either OpUpsert or OpDelete.
+	Op string `json:"op"`
+
+	// Key identifies the entity that changed. It is
+	// "<partition>/<namespace>/<node>/<service ID>" for the service instances
+	// and "<partition>/<namespace>/<kind>/<name>" for the config entries.
+	Key string `json:"key"`
+
+	// Snapshot is true if the event is part of the snapshot of all the
+	// entities of the topic that is pushed when a sink starts without a
+	// checkpoint, or when it fell too far behind. The entities deleted while
+	// the sink was behind are not part of the snapshot.
+	Snapshot bool `json:"snapshot,omitempty"`
+
+	// Service is set for the TopicCatalog events.
+	Service *structs.CheckServiceNode `json:"service,omitempty"`
+
+	// ConfigEntry is set for the TopicConfigEntries events.
+	ConfigEntry structs.ConfigEntry `json:"config_entry,omitempty"`
+}
+
+// newEvents converts an event of the event publisher to the events pushed to
+// the sinks.
+func newEvents(e stream.Event, snapshot bool) ([]Event, error) {
+	switch payload := e.Payload.(type) {
+	case *stream.PayloadEvents:
+		var events []Event
+		for _, item := range payload.Items {
+			items, err := newEvents(item, snapshot)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, items...)
+		}
+		return events, nil
+
+	case state.EventPayloadCheckServiceNode:
+		csn := payload.Value
+		event := Event{
+			Index:    e.Index,
+			Topic:    TopicCatalog,
+			Op:       OpUpsert,
+			Key:      path.Join(csn.Service.PartitionOrDefault(), csn.Service.NamespaceOrDefault(), csn.Node.Node, csn.Service.ID),
+			Snapshot: snapshot,
+			Service:  csn,
+		}
+		if payload.Op == pbsubscribe.CatalogOp_Deregister {
+			event.Op = OpDelete
+		}
+		return []Event{event}, nil
+
+	case state.EventPayloadConfigEntry:
+		entry := payload.Value
+		entMeta := entry.GetEnterpriseMeta()
+		event := Event{
+			Index:       e.Index,
+			Topic:       TopicConfigEntries,
+			Op:          OpUpsert,
+			Key:         path.Join(entMeta.PartitionOrDefault(), entMeta.NamespaceOrDefault(), entry.GetKind(), entry.GetName()),
+			Snapshot:    snapshot,
+			ConfigEntry: entry,
+		}
+		if payload.Op == pbsubscribe.ConfigEntryUpdate_Delete {
+			event.Op = OpDelete
+		}
+		return []Event{event}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected payload %T", e.Payload)
+	}
+}
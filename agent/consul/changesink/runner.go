@@ -0,0 +1,209 @@
+package changesink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/lib/retry"
+)
+
+var (
+	metricsKeyDelivered      = []string{"change_sink", "delivered"}
+	metricsKeyDeliveryFailed = []string{"change_sink", "delivery_failed"}
+)
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: metricsKeyDelivered,
+		Help: "Counts the events delivered to a change sink.",
+	},
+	{
+		Name: metricsKeyDeliveryFailed,
+		Help: "Increments each time the delivery of a batch of events to a change sink fails.",
+	},
+}
+
+// checkpointInterval is the minimum time between two updates of the
+// checkpoint of a topic.
+var checkpointInterval = 5 * time.Second
+
+// Publisher is the event publisher the events are read from.
+type Publisher interface {
+	Subscribe(req *stream.SubscribeRequest) (*stream.Subscription, error)
+}
+
+// Checkpoints persist the index of the last event delivered to the sinks,
+// by sink and topic of the event publisher.
+type Checkpoints interface {
+	GetCheckpoint(key string) (uint64, error)
+	SetCheckpoint(key string, index uint64) error
+}
+
+// Runner pushes the events of the event publisher to a sink.
+//
+// The delivery is at-least-once: each topic of the event publisher is
+// resumed from its checkpoint, which is only updated after the events were
+// delivered, and at most every checkpointInterval.
+type Runner struct {
+	Config      Config
+	Sink        Sink
+	Publisher   Publisher
+	Checkpoints Checkpoints
+	Logger      hclog.Logger
+}
+
+// Run pushes the events until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) error {
+	topics := r.Config.Topics
+	if len(topics) == 0 {
+		topics = []string{TopicCatalog, TopicConfigEntries}
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range topics {
+		for _, topic := range Topics[name] {
+			wg.Add(1)
+			go func(topic stream.Topic) {
+				defer wg.Done()
+				r.runTopic(ctx, topic)
+			}(topic)
+		}
+	}
+	wg.Wait()
+	return nil
+}
+
+// CheckpointKey is the key of the checkpoint of a topic of a sink.
+func CheckpointKey(sink string, topic stream.Topic) string {
+	return fmt.Sprintf("change-sink/%s/%s", sink, topic)
+}
+
+func (r *Runner) runTopic(ctx context.Context, topic stream.Topic) {
+	logger := r.Logger.With("topic", topic.String())
+	waiter := &retry.Waiter{
+		MinFailures: 1,
+		MinWait:     time.Second,
+		MaxWait:     time.Minute,
+		Jitter:      retry.NewJitter(20),
+	}
+	for {
+		err := r.streamTopic(ctx, topic, logger)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("change sink subscription failed, will retry",
+			"error", err,
+			"retry_interval", waiter.NextWait(),
+		)
+		if err := waiter.Wait(ctx); err != nil {
+			return
+		}
+	}
+}
+
+func (r *Runner) streamTopic(ctx context.Context, topic stream.Topic, logger hclog.Logger) error {
+	key := CheckpointKey(r.Config.Name, topic)
+	index, err := r.Checkpoints.GetCheckpoint(key)
+	if err != nil {
+		return fmt.Errorf("failed to read the checkpoint: %w", err)
+	}
+
+	sub, err := r.Publisher.Subscribe(&stream.SubscribeRequest{
+		Topic:   topic,
+		Subject: stream.SubjectWildcard,
+		Index:   index,
+	})
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	// The subscriptions without an index start with a snapshot, the others
+	// are preceded by a NewSnapshotToFollow event if they can't be resumed.
+	snapshot := index == 0
+	saved := index
+	lastSave := time.Now()
+	save := func() {
+		if err := r.Checkpoints.SetCheckpoint(key, index); err != nil {
+			logger.Warn("failed to save the checkpoint", "error", err)
+		} else {
+			saved = index
+		}
+		lastSave = time.Now()
+	}
+	for {
+		// The checkpoint is also saved when no event follows the last one
+		// delivered before checkpointInterval.
+		nextCtx, cancel := ctx, context.CancelFunc(func() {})
+		if index != saved {
+			nextCtx, cancel = context.WithDeadline(ctx, lastSave.Add(checkpointInterval))
+		}
+		event, err := sub.Next(nextCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				save()
+				continue
+			}
+			return err
+		}
+
+		switch {
+		case event.IsNewSnapshotToFollow():
+			logger.Info("change sink is too far behind, sending a snapshot")
+			snapshot = true
+			continue
+		case event.IsEndOfSnapshot():
+			snapshot = false
+		default:
+			events, err := newEvents(event, snapshot)
+			if err != nil {
+				logger.Error("skipping event", "index", event.Index, "error", err)
+				continue
+			}
+			if err := r.deliver(ctx, events, logger); err != nil {
+				return err
+			}
+		}
+
+		index = event.Index
+		if index != saved && time.Since(lastSave) >= checkpointInterval {
+			save()
+		}
+	}
+}
+
+// deliver delivers events to the sink, retrying until it succeeds or ctx is
+// cancelled.
+func (r *Runner) deliver(ctx context.Context, events []Event, logger hclog.Logger) error {
+	labels := []metrics.Label{{Name: "sink", Value: r.Config.Name}}
+	waiter := &retry.Waiter{
+		MinFailures: 1,
+		MinWait:     time.Second,
+		MaxWait:     time.Minute,
+		Jitter:      retry.NewJitter(20),
+	}
+	for {
+		err := r.Sink.Deliver(ctx, events)
+		if err == nil {
+			metrics.IncrCounterWithLabels(metricsKeyDelivered, float32(len(events)), labels)
+			return nil
+		}
+		metrics.IncrCounterWithLabels(metricsKeyDeliveryFailed, 1, labels)
+		logger.Warn("failed to deliver events to the change sink, will retry",
+			"error", err,
+			"retry_interval", waiter.NextWait(),
+		)
+		if err := waiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package changesink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+func TestRunner(t *testing.T) {
+	checkpointInterval = 0
+	t.Cleanup(func() { checkpointInterval = 5 * time.Second })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher := stream.NewEventPublisher(0)
+	store := state.NewStateStoreWithEventPublisher(nil, publisher)
+	require.NoError(t, publisher.RegisterHandler(state.EventTopicServiceHealth, store.ServiceHealthSnapshot, true))
+	go publisher.Run(ctx)
+
+	register := func(idx uint64, service string) {
+		require.NoError(t, store.EnsureRegistration(idx, &structs.RegisterRequest{
+			Node:    "node1",
+			Address: "127.0.0.1",
+			Service: &structs.NodeService{ID: service, Service: service},
+		}))
+	}
+	register(1, "web")
+
+	sink := &fakeSink{fail: 1}
+	checkpoints := &fakeCheckpoints{}
+	start := func() context.CancelFunc {
+		ctx, cancel := context.WithCancel(ctx)
+		runner := &Runner{
+			Config:      Config{Name: "test", Topics: []string{TopicCatalog}},
+			Sink:        sink,
+			Publisher:   publisher,
+			Checkpoints: checkpoints,
+			Logger:      hclog.NewNullLogger(),
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			runner.Run(ctx)
+		}()
+		return func() {
+			cancel()
+			<-done
+		}
+	}
+	stop := start()
+
+	// The first delivery fails and is retried.
+	retry.Run(t, func(r *retry.R) {
+		// The registration can also be received after the snapshot, depending
+		// on when it is published.
+		events := sink.received(0)
+		require.NotEmpty(r, events)
+		require.Equal(r, OpUpsert, events[0].Op)
+		require.Equal(r, "default/default/node1/web", events[0].Key)
+		require.True(r, events[0].Snapshot)
+	})
+
+	register(2, "db")
+	require.NoError(t, store.DeleteService(3, "node1", "web", nil, ""))
+	retry.Run(t, func(r *retry.R) {
+		events := sink.received(2)
+		require.Len(r, events, 2)
+		require.Equal(r, Event{Index: 2, Topic: TopicCatalog, Op: OpUpsert, Key: "default/default/node1/db"},
+			withoutValue(events[0]))
+		require.Equal(r, Event{Index: 3, Topic: TopicCatalog, Op: OpDelete, Key: "default/default/node1/web"},
+			withoutValue(events[1]))
+		require.Equal(r, uint64(3), checkpoints.get(CheckpointKey("test", state.EventTopicServiceHealth)))
+	})
+	stop()
+
+	// The runner restarts from its checkpoint. The topic buffer was freed
+	// when the subscription ended, so it can't be resumed and a snapshot
+	// is sent instead.
+	sink.reset()
+	stop = start()
+	defer stop()
+	register(4, "api")
+	retry.Run(t, func(r *retry.R) {
+		var keys []string
+		for _, e := range sink.received(0) {
+			keys = append(keys, e.Key)
+		}
+		require.Contains(r, keys, "default/default/node1/api")
+		require.Contains(r, keys, "default/default/node1/db")
+		require.NotContains(r, keys, "default/default/node1/web")
+	})
+}
+
+func withoutValue(e Event) Event {
+	e.Service = nil
+	e.ConfigEntry = nil
+	return e
+}
+
+type fakeSink struct {
+	lock   sync.Mutex
+	fail   int
+	events []Event
+}
+
+func (s *fakeSink) Deliver(_ context.Context, events []Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("failed")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+// received returns the events received with an index of at least minIndex.
+func (s *fakeSink) received(minIndex uint64) []Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var events []Event
+	for _, e := range s.events {
+		if e.Index >= minIndex {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (s *fakeSink) reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = nil
+}
+
+type fakeCheckpoints struct {
+	lock        sync.Mutex
+	checkpoints map[string]uint64
+}
+
+func (c *fakeCheckpoints) GetCheckpoint(key string) (uint64, error) {
+	return c.get(key), nil
+}
+
+func (c *fakeCheckpoints) SetCheckpoint(key string, index uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.checkpoints == nil {
+		c.checkpoints = make(map[string]uint64)
+	}
+	c.checkpoints[key] = index
+	return nil
+}
+
+func (c *fakeCheckpoints) get(key string) uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.checkpoints[key]
+}
@@ -0,0 +1,167 @@
+package changesink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The types of sinks.
+const (
+	TypeWebhook = "webhook"
+	TypeKafka   = "kafka"
+)
+
+// Config is the configuration of a sink.
+type Config struct {
+	// Name identifies the sink, and its checkpoints.
+	Name string
+
+	// Type is either TypeWebhook or TypeKafka.
+	Type string
+
+	// URL is the URL the events are posted to for the webhooks, and the base
+	// URL of the Kafka REST Proxy for Kafka.
+	URL string
+
+	// Headers are added to the requests made to URL, e.g. for authentication.
+	Headers map[string]string
+
+	// KafkaTopic is the Kafka topic the events are produced to.
+	KafkaTopic string
+
+	// Topics are the topics of the events pushed to the sink. All the topics
+	// are pushed when it's empty.
+	Topics []string
+
+	// Timeout is the timeout of the requests made to URL.
+	Timeout time.Duration
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch c.Type {
+	case TypeWebhook:
+	case TypeKafka:
+		if c.KafkaTopic == "" {
+			return fmt.Errorf("kafka_topic is required for the %q sinks", TypeKafka)
+		}
+	default:
+		return fmt.Errorf("type must be %q or %q", TypeWebhook, TypeKafka)
+	}
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("url must be an HTTP or HTTPS URL")
+	}
+	for _, topic := range c.Topics {
+		if _, ok := Topics[topic]; !ok {
+			return fmt.Errorf("unknown topic %q, must be %q or %q", topic, TopicCatalog, TopicConfigEntries)
+		}
+	}
+	return nil
+}
+
+// Sink delivers events to an external system.
+type Sink interface {
+	// Deliver delivers a batch of events. The events must be redelivered if
+	// it returns an error.
+	Deliver(ctx context.Context, events []Event) error
+}
+
+// New returns the sink for a configuration.
+func New(cfg Config) (Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Type {
+	case TypeKafka:
+		return &kafkaSink{
+			url:     strings.TrimSuffix(cfg.URL, "/") + "/topics/" + url.PathEscape(cfg.KafkaTopic),
+			headers: cfg.Headers,
+			client:  client,
+		}, nil
+	default:
+		return &webhookSink{
+			url:     cfg.URL,
+			headers: cfg.Headers,
+			client:  client,
+		}, nil
+	}
+}
+
+// webhookSink posts the batches of events to a URL, as a JSON array.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookSink) Deliver(ctx context.Context, events []Event) error {
+	return post(ctx, s.client, s.url, "application/json", s.headers, events)
+}
+
+// kafkaSink produces the events to a Kafka topic through the v2 API of a
+// Kafka REST Proxy. The records are keyed by the key of the events so that
+// the changes of an entity are kept in order.
+type kafkaSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+func (s *kafkaSink) Deliver(ctx context.Context, events []Event) error {
+	records := make([]kafkaRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, kafkaRecord{Key: event.Key, Value: event})
+	}
+	body := struct {
+		Records []kafkaRecord `json:"records"`
+	}{records}
+	return post(ctx, s.client, s.url, "application/vnd.kafka.json.v2+json", s.headers, body)
+}
+
+func post(ctx context.Context, client *http.Client, url, contentType string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package changesink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks(t *testing.T) {
+	events := []Event{
+		{Index: 10, Topic: TopicCatalog, Op: OpUpsert, Key: "default/default/node1/web"},
+		{Index: 10, Topic: TopicCatalog, Op: OpDelete, Key: "default/default/node1/db"},
+	}
+
+	type request struct {
+		path        string
+		contentType string
+		auth        string
+		body        string
+	}
+	requests := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests <- request{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			body:        string(body),
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	headers := map[string]string{"Authorization": "Bearer token"}
+
+	t.Run("webhook", func(t *testing.T) {
+		sink, err := New(Config{Name: "webhook", Type: TypeWebhook, URL: srv.URL + "/hook", Headers: headers})
+		require.NoError(t, err)
+		require.NoError(t, sink.Deliver(context.Background(), events))
+
+		req := <-requests
+		require.Equal(t, "/hook", req.path)
+		require.Equal(t, "application/json", req.contentType)
+		require.Equal(t, "Bearer token", req.auth)
+		expected, err := json.Marshal(events)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), req.body)
+	})
+
+	t.Run("kafka", func(t *testing.T) {
+		sink, err := New(Config{Name: "kafka", Type: TypeKafka, URL: srv.URL + "/", KafkaTopic: "consul-changes", Headers: headers})
+		require.NoError(t, err)
+		require.NoError(t, sink.Deliver(context.Background(), events))
+
+		req := <-requests
+		require.Equal(t, "/topics/consul-changes", req.path)
+		require.Equal(t, "application/vnd.kafka.json.v2+json", req.contentType)
+		require.Equal(t, "Bearer token", req.auth)
+		require.JSONEq(t, `{"records": [
+			{"key": "default/default/node1/web", "value": {"index": 10, "topic": "catalog", "op": "upsert", "key": "default/default/node1/web"}},
+			{"key": "default/default/node1/db", "value": {"index": 10, "topic": "catalog", "op": "delete", "key": "default/default/node1/db"}}
+		]}`, req.body)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		sink, err := New(Config{Name: "webhook", Type: TypeWebhook, URL: srv.URL + "/fail"})
+		require.NoError(t, err)
+		require.EqualError(t, sink.Deliver(context.Background(), events), "unexpected response code 500: ")
+		<-requests
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := map[string]struct {
+		cfg Config
+		err string
+	}{
+		"valid webhook": {
+			cfg: Config{Name: "a", Type: TypeWebhook, URL: "https://example.com/hook", Topics: []string{TopicCatalog}},
+		},
+		"valid kafka": {
+			cfg: Config{Name: "a", Type: TypeKafka, URL: "http://kafka-rest:8082", KafkaTopic: "consul"},
+		},
+		"missing name": {
+			cfg: Config{Type: TypeWebhook, URL: "https://example.com/hook"},
+			err: "name is required",
+		},
+		"invalid type": {
+			cfg: Config{Name: "a", Type: "sqs", URL: "https://example.com/hook"},
+			err: `type must be "webhook" or "kafka"`,
+		},
+		"missing kafka topic": {
+			cfg: Config{Name: "a", Type: TypeKafka, URL: "http://kafka-rest:8082"},
+			err: `kafka_topic is required for the "kafka" sinks`,
+		},
+		"invalid url": {
+			cfg: Config{Name: "a", Type: TypeWebhook, URL: "example.com/hook"},
+			err: "url must be an HTTP or HTTPS URL",
+		},
+		"invalid topic": {
+			cfg: Config{Name: "a", Type: TypeWebhook, URL: "https://example.com/hook", Topics: []string{"kv"}},
+			err: `unknown topic "kv", must be "catalog" or "config-entries"`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
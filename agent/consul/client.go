@@ -17,6 +17,7 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	rpcRate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/router"
 	"github.com/hashicorp/consul/agent/structs"
@@ -39,6 +40,10 @@ var ClientCounters = []prometheus.CounterDefinition{
 		Name: []string{"client", "rpc", "failed"},
 		Help: "Increments whenever a Consul agent in client mode makes an RPC request to a Consul server and fails.",
 	},
+	{
+		Name: []string{"client", "rpc", "read_replica"},
+		Help: "Increments whenever a Consul agent in client mode sends a stale read RPC request to a read replica server.",
+	},
 }
 
 const (
@@ -82,6 +87,10 @@ type Client struct {
 	// which contains all the DC nodes
 	serf *serf.Serf
 
+	// gossipHealth records the failures and recoveries of the members of
+	// the serf cluster.
+	gossipHealth *gossipHealthTracker
+
 	shutdown     bool
 	shutdownCh   chan struct{}
 	shutdownLock sync.Mutex
@@ -108,6 +117,7 @@ func NewClient(config *Config, deps Deps) (*Client, error) {
 		config:          config,
 		connPool:        deps.ConnPool,
 		eventCh:         make(chan serf.Event, serfEventBacklog),
+		gossipHealth:    newGossipHealthTracker(),
 		logger:          deps.Logger.NamedIntercept(logging.ConsulClient),
 		shutdownCh:      make(chan struct{}),
 		tlsConfigurator: deps.TLSConfigurator,
@@ -151,6 +161,7 @@ func NewClient(config *Config, deps Deps) (*Client, error) {
 	// Start LAN event handlers after the router is complete since the event
 	// handlers depend on the router and the router depends on Serf.
 	go c.lanEventHandler()
+	go monitorGossipProfile(c.logger, c.serf, config.GossipLANProfile, c.shutdownCh)
 
 	return c, nil
 }
@@ -218,6 +229,12 @@ func (c *Client) LANMembersInAgentPartition() []serf.Member {
 	return c.serf.Members()
 }
 
+// GossipHealth returns the health of the LAN gossip pool as seen from the
+// client.
+func (c *Client) GossipHealth() GossipHealth {
+	return c.gossipHealth.health(c.serf)
+}
+
 // LANMembers returns the LAN members for one of:
 //
 // - the requested partition
@@ -274,9 +291,22 @@ func (c *Client) RPC(ctx context.Context, method string, args interface{}, reply
 	firstCheck := time.Now()
 	retryCount := 0
 	previousJitter := time.Duration(0)
+
+	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
+	info, _ := args.(structs.RPCInfo)
+
+	// The stale reads are sent to the read replicas if there are any, until
+	// one fails.
+	preferReadReplica := info != nil && info.IsRead() && info.AllowStaleRead()
 TRY:
 	retryCount++
-	manager, server := c.router.FindLANRoute()
+	var manager *router.Manager
+	var server *metadata.Server
+	if preferReadReplica {
+		manager, server = c.router.FindLANReadRoute()
+	} else {
+		manager, server = c.router.FindLANRoute()
+	}
 	if server == nil {
 		return structs.ErrNoServers
 	}
@@ -287,6 +317,9 @@ TRY:
 		metrics.IncrCounter([]string{"client", "rpc", "exceeded"}, 1)
 		return structs.ErrRPCRateExceeded
 	}
+	if server.ReadReplica {
+		metrics.IncrCounter([]string{"client", "rpc", "read_replica"}, 1)
+	}
 
 	// Make the request.
 	rpcErr := c.connPool.RPC(c.config.Datacenter, server.ShortName, server.Addr, method, args, reply)
@@ -296,9 +329,8 @@ TRY:
 
 	// Move off to another server, and see if we can retry.
 	manager.NotifyFailedServer(server)
+	preferReadReplica = false
 
-	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
-	info, _ := args.(structs.RPCInfo)
 	retryableMessages := []error{
 		// If we are chunking and it doesn't seem to have completed, try again.
 		ErrChunkingResubmit,
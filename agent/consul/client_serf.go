@@ -87,6 +87,9 @@ func (c *Client) lanEventHandler() {
 
 		select {
 		case e := <-c.eventCh:
+			if me, ok := e.(serf.MemberEvent); ok {
+				c.gossipHealth.handleEvent(me)
+			}
 			switch e.EventType() {
 			case serf.EventMemberJoin:
 				c.nodeJoin(e.(serf.MemberEvent))
@@ -9,10 +9,13 @@ import (
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
+	"github.com/klauspost/compress/zstd"
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/consul/agent/checks"
+	"github.com/hashicorp/consul/agent/consul/changesink"
 	consulrate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/rpc/middleware"
 	"github.com/hashicorp/consul/agent/structs"
 	libserf "github.com/hashicorp/consul/lib/serf"
 	"github.com/hashicorp/consul/tlsutil"
@@ -113,8 +116,9 @@ type Config struct {
 	// RaftConfig is the configuration used for Raft in the local DC
 	RaftConfig *raft.Config
 
-	// (Enterprise-only) ReadReplica is used to prevent this server from being added
-	// as a voting member of the Raft cluster.
+	// ReadReplica is used to prevent this server from being added as a voting
+	// member of the Raft cluster. Read replicas serve the stale reads of the
+	// clients.
 	ReadReplica bool
 
 	// NotifyListen is called after the RPC listener has been configured.
@@ -152,6 +156,11 @@ type Config struct {
 	// SerfLANConfig is the configuration for the intra-dc serf
 	SerfLANConfig *serf.Config
 
+	// GossipLANProfile is the name of the gossip profile the LAN gossip
+	// settings were taken from, which is used to report when the LAN gossip
+	// pool outgrows it.
+	GossipLANProfile string
+
 	// SerfWANConfig is the configuration for the cross-dc serf
 	SerfWANConfig *serf.Config
 
@@ -223,6 +232,11 @@ type Config struct {
 	// by default in Consul 1.0 and later.
 	ACLEnableKeyListPolicy bool
 
+	// ACLClientCertAuthMethod is the name of the auth method the servers
+	// login with on behalf of the clients presenting a certificate to the
+	// HTTPS or external gRPC API without a token.
+	ACLClientCertAuthMethod string
+
 	AutoConfigEnabled              bool
 	AutoConfigIntroToken           string
 	AutoConfigIntroTokenFile       string
@@ -257,9 +271,21 @@ type Config struct {
 	// to reduce overhead. It is unlikely a user would ever need to tune this.
 	TombstoneTTLGranularity time.Duration
 
+	// ServiceTombstoneTTL is used to control how long the tombstones of
+	// service instances removed from the catalog are retained. These are only
+	// used to tell recently deregistered service instances apart from ones
+	// that never existed. TombstoneTTLGranularity also applies to them.
+	ServiceTombstoneTTL time.Duration
+
 	// Minimum Session TTL
 	SessionTTLMin time.Duration
 
+	// KVCompressionThreshold is the size in bytes above which KV values are
+	// compressed before being committed to Raft. Compressed values are kept
+	// compressed in the state store and in snapshots, and are decompressed
+	// when read. Zero disables compression.
+	KVCompressionThreshold int
+
 	// maxTokenExpirationDuration is the maximum difference allowed between
 	// ACLToken CreateTime and ExpirationTime values if ExpirationTime is set
 	// on a token.
@@ -343,6 +369,25 @@ type Config struct {
 	// limiter limits the rate to RequestLimitsWriteRate tokens per second.
 	RequestLimitsWriteRate rate.Limit
 
+	// RequestLimitsIPReadRate and RequestLimitsIPWriteRate limit how
+	// frequently each source IP address is allowed to make queries and
+	// writes, so that a single client can't exhaust the global limits.
+	RequestLimitsIPReadRate  rate.Limit
+	RequestLimitsIPWriteRate rate.Limit
+
+	// RequestLimitsIPOverrides replace the per-IP limits of some addresses.
+	RequestLimitsIPOverrides []RequestLimitsIPOverride
+
+	// RequestLimitsTokenReadRate and RequestLimitsTokenWriteRate limit how
+	// frequently the requests made with each ACL token are allowed to
+	// happen.
+	RequestLimitsTokenReadRate  rate.Limit
+	RequestLimitsTokenWriteRate rate.Limit
+
+	// RequestLimitsTokenOverrides replace the per-token limits of some
+	// tokens.
+	RequestLimitsTokenOverrides []RequestLimitsTokenOverride
+
 	// RPCHandshakeTimeout limits how long we will wait for the initial magic byte
 	// on an RPC client connection. It also governs how long we will wait for a
 	// TLS handshake when TLS is configured however the timout applies separately
@@ -378,6 +423,10 @@ type Config struct {
 	// allowed from a single source IP.
 	RPCMaxConnsPerClient int
 
+	// RPCConsumerLabels configures the labels of the RPC request metrics
+	// attributing the requests to the services and peers they are made for.
+	RPCConsumerLabels middleware.ConsumerLabelsConfig
+
 	// LeaveDrainTime is used to wait after a server has left the LAN Serf
 	// pool for RPCs to drain and new requests to be sent to other servers.
 	LeaveDrainTime time.Duration
@@ -395,6 +444,10 @@ type Config struct {
 	// dead servers.
 	AutopilotInterval time.Duration
 
+	// AutopilotPromotion configures how the leader promotes the servers to
+	// voters and demotes them.
+	AutopilotPromotion AutopilotPromotionConfig
+
 	// MetricsReportingInterval is the frequency with which the server will
 	// report usage metrics to the configured go-metrics Sinks.
 	MetricsReportingInterval time.Duration
@@ -431,11 +484,34 @@ type Config struct {
 
 	LogStoreConfig RaftLogStoreConfig
 
+	// RaftCompression configures the compression of the raft log entries
+	// and snapshots written by the server.
+	RaftCompression RaftCompressionConfig
+
+	// KeyringRotation configures the automated rotation of the gossip
+	// encryption key by the leader.
+	KeyringRotation KeyringRotationConfig
+
+	// ExternalHealthChecks configures the health checks of the external nodes
+	// run by the leader.
+	ExternalHealthChecks ExternalHealthChecksConfig
+
+	// ChangeSinks are the sinks the leader pushes the changes of the catalog
+	// and of the config entries to.
+	ChangeSinks []changesink.Config
+
 	// PeeringEnabled enables cluster peering.
 	PeeringEnabled bool
 
 	PeeringTestAllowPeerRegistrations bool
 
+	// PeeringIncomingMessageRate and PeeringIncomingMessageBurst limit how
+	// quickly messages received on the replication stream of each peer are
+	// processed. As a special case, if PeeringIncomingMessageRate == Inf (the
+	// infinite rate), PeeringIncomingMessageBurst is ignored.
+	PeeringIncomingMessageRate  rate.Limit
+	PeeringIncomingMessageBurst int
+
 	// Embedded Consul Enterprise specific configuration
 	*EnterpriseConfig
 }
@@ -513,6 +589,7 @@ func DefaultConfig() *Config {
 		FederationStateReplicationApplyLimit: 100, // ops / sec
 		TombstoneTTL:                         15 * time.Minute,
 		TombstoneTTLGranularity:              30 * time.Second,
+		ServiceTombstoneTTL:                  1 * time.Hour,
 		SessionTTLMin:                        10 * time.Second,
 		ACLTokenMinExpirationTTL:             1 * time.Minute,
 		ACLTokenMaxExpirationTTL:             24 * time.Hour,
@@ -530,6 +607,11 @@ func DefaultConfig() *Config {
 		RequestLimitsReadRate:  rate.Inf, // ops / sec
 		RequestLimitsWriteRate: rate.Inf, // ops / sec
 
+		RequestLimitsIPReadRate:     rate.Inf, // ops / sec
+		RequestLimitsIPWriteRate:    rate.Inf, // ops / sec
+		RequestLimitsTokenReadRate:  rate.Inf, // ops / sec
+		RequestLimitsTokenWriteRate: rate.Inf, // ops / sec
+
 		RPCRateLimit: rate.Inf,
 		RPCMaxBurst:  1000,
 
@@ -561,6 +643,8 @@ func DefaultConfig() *Config {
 		MaxQueryTime:             600 * time.Second,
 
 		PeeringTestAllowPeerRegistrations: false,
+		PeeringIncomingMessageRate:        rate.Inf,
+		PeeringIncomingMessageBurst:       1000,
 
 		EnterpriseConfig: DefaultEnterpriseConfig(),
 	}
@@ -627,6 +711,7 @@ func CloneSerfLANConfig(base *serf.Config) *serf.Config {
 	cfg.MemberlistConfig.ProbeTimeout = base.MemberlistConfig.ProbeTimeout
 	cfg.MemberlistConfig.SuspicionMult = base.MemberlistConfig.SuspicionMult
 	cfg.MemberlistConfig.RetransmitMult = base.MemberlistConfig.RetransmitMult
+	cfg.MemberlistConfig.PushPullInterval = base.MemberlistConfig.PushPullInterval
 	cfg.MemberlistConfig.MetricLabels = base.MemberlistConfig.MetricLabels
 
 	// agent/keyring.go
@@ -655,6 +740,29 @@ type RequestLimits struct {
 	Mode      consulrate.Mode
 	ReadRate  rate.Limit
 	WriteRate rate.Limit
+
+	IPReadRate     rate.Limit
+	IPWriteRate    rate.Limit
+	IPOverrides    []RequestLimitsIPOverride
+	TokenReadRate  rate.Limit
+	TokenWriteRate rate.Limit
+	TokenOverrides []RequestLimitsTokenOverride
+}
+
+// RequestLimitsIPOverride replaces the per-IP limits of the addresses in
+// CIDR. Each address is still limited separately.
+type RequestLimitsIPOverride struct {
+	CIDR      *net.IPNet
+	ReadRate  rate.Limit
+	WriteRate rate.Limit
+}
+
+// RequestLimitsTokenOverride replaces the per-token limits of the ACL token
+// with the given accessor ID.
+type RequestLimitsTokenOverride struct {
+	AccessorID string
+	ReadRate   rate.Limit
+	WriteRate  rate.Limit
 }
 
 // ReloadableConfig is the configuration that is passed to ReloadConfig when
@@ -665,6 +773,7 @@ type ReloadableConfig struct {
 	RPCRateLimit          rate.Limit
 	RPCMaxBurst           int
 	RPCMaxConnsPerClient  int
+	RPCConsumerLabels     middleware.ConsumerLabelsConfig
 	ConfigEntryBootstrap  []structs.ConfigEntry
 	RaftSnapshotThreshold int
 	RaftSnapshotInterval  time.Duration
@@ -673,6 +782,17 @@ type ReloadableConfig struct {
 	ElectionTimeout       time.Duration
 }
 
+type KeyringRotationConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	GracePeriod time.Duration
+}
+
+type ExternalHealthChecksConfig struct {
+	Enabled           bool
+	NodeProbeInterval time.Duration
+}
+
 type RaftLogStoreConfig struct {
 	Backend         string
 	DisableLogCache bool
@@ -681,6 +801,32 @@ type RaftLogStoreConfig struct {
 	WAL             WALConfig
 }
 
+// RaftCompressionConfig configures the zstd compression of the raft log
+// entries and snapshots written by the server. The log entries are only
+// compressed once every server can decompress them, the snapshots must only
+// be compressed once they all can.
+type RaftCompressionConfig struct {
+	// LogThreshold is the size in bytes from which the raft log entries are
+	// compressed. The log entries aren't compressed when it is 0.
+	LogThreshold int
+
+	// Snapshots compresses the raft snapshots, on disk and when they are sent
+	// to the other servers.
+	Snapshots bool
+
+	// Level is the zstd level, one of fastest, default, better or best.
+	Level string
+}
+
+// EncoderLevel returns the zstd encoder level of the configured level.
+func (c RaftCompressionConfig) EncoderLevel() (zstd.EncoderLevel, error) {
+	ok, level := zstd.EncoderLevelFromString(c.Level)
+	if !ok {
+		return 0, fmt.Errorf("raft_compression.level must be one of fastest, default, better or best. received: %q", c.Level)
+	}
+	return level, nil
+}
+
 type RaftLogStoreVerificationConfig struct {
 	Enabled  bool
 	Interval time.Duration
@@ -692,4 +838,38 @@ type RaftBoltDBConfig struct {
 
 type WALConfig struct {
 	SegmentSize int
+	Canary      WALCanaryConfig
+}
+
+// WALCanaryConfig configures the canary mode of the WAL, in which the logs
+// are also written to a BoltDB shadow copy the WAL is verified against.
+type WALCanaryConfig struct {
+	Enabled bool
+
+	// VerifyInterval is how often the logs written since the last
+	// verification are compared.
+	VerifyInterval time.Duration
+
+	// Failback is whether the server serves from the shadow copy once the WAL
+	// diverged from it, and replaces the WAL with it on its next start.
+	Failback bool
+}
+
+// AutopilotPromotionConfig configures the promotion strategy of autopilot,
+// which decides the servers the leader promotes to voters and demotes.
+type AutopilotPromotionConfig struct {
+	// Strategy is either "stable", the default, which promotes every server
+	// once it is stable, or "zones" which keeps VotersPerZone voters in each
+	// zone.
+	Strategy string
+
+	// MinServerUptime is how long the leader must have seen a server alive
+	// before promoting it, in addition to the server stabilization time.
+	MinServerUptime time.Duration
+
+	// ZoneMetaKey is the node meta key holding the zone of the servers.
+	ZoneMetaKey string
+
+	// VotersPerZone is the number of voters kept in each zone.
+	VotersPerZone int
 }
@@ -3,6 +3,7 @@ package consul
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -229,6 +230,7 @@ func (c *ConfigEntry) List(args *structs.ConfigEntryQuery, reply *structs.Indexe
 		return err
 	}
 
+	c.srv.forwardPageIfBehind(&args.QueryOptions, args.PageOptions)
 	if done, err := c.srv.ForwardRPC("ConfigEntry.List", args, reply); done {
 		return err
 	}
@@ -271,11 +273,14 @@ func (c *ConfigEntry) List(args *structs.ConfigEntryQuery, reply *structs.Indexe
 
 			reply.Kind = args.Kind
 			reply.Index = index
-			reply.Entries = filteredEntries
+			if err := args.CheckIndex(index); err != nil {
+				return err
+			}
+			reply.Entries, reply.NextCursor = pageConfigEntries(args.PageOptions, filteredEntries)
 
 			// Generate a hash of the content driving this response. Use it to
 			// determine if the response is identical to a prior wakeup.
-			newHash, err := hashstructure_v2.Hash(filteredEntries, hashstructure_v2.FormatV2, nil)
+			newHash, err := hashstructure_v2.Hash([]interface{}{reply.Entries, reply.NextCursor}, hashstructure_v2.FormatV2, nil)
 			if err != nil {
 				return fmt.Errorf("error hashing reply for spurious wakeup suppression: %w", err)
 			}
@@ -296,6 +301,30 @@ func (c *ConfigEntry) List(args *structs.ConfigEntryQuery, reply *structs.Indexe
 		})
 }
 
+// pageConfigEntries returns the requested page of the config entries, which
+// are ordered by partition, namespace and name, and the cursor of the next
+// page.
+func pageConfigEntries(page structs.PageOptions, entries []structs.ConfigEntry) ([]structs.ConfigEntry, string) {
+	if !page.IsPaginated() {
+		return entries, ""
+	}
+
+	key := func(e structs.ConfigEntry) string {
+		entMeta := e.GetEnterpriseMeta()
+		return entMeta.PartitionOrDefault() + "/" + entMeta.NamespaceOrDefault() + "/" + e.GetName()
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return key(entries[i]) < key(entries[j])
+	})
+
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = key(e)
+	}
+	start, end, next := page.Page(keys)
+	return entries[start:end], next
+}
+
 var configEntryKindsFromConsul_1_8_0 = []string{
 	structs.ServiceDefaults,
 	structs.ProxyDefaults,
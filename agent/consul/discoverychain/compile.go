@@ -2,6 +2,7 @@ package discoverychain
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -174,6 +175,12 @@ type compiler struct {
 	// This is an OUTPUT field.
 	envoyExtensions []structs.EnvoyExtension
 
+	// retryPolicy is the default retry policy from the service-defaults or
+	// proxy-defaults config entries for this discovery chain.
+	//
+	// This is an OUTPUT field.
+	retryPolicy *structs.RouteRetryPolicy
+
 	// startNode is computed inside of assembleChain()
 	//
 	// This is an OUTPUT field.
@@ -311,6 +318,11 @@ func (c *compiler) compile() (*structs.CompiledDiscoveryChain, error) {
 		}
 	}
 
+	// Retries are only possible for the protocols that generate routes.
+	if !enableAdvancedRoutingForProtocol(c.protocol) {
+		c.retryPolicy = nil
+	}
+
 	var customizationHash string
 	if !c.customizedBy.IsZero() {
 		var customization struct {
@@ -345,6 +357,7 @@ func (c *compiler) compile() (*structs.CompiledDiscoveryChain, error) {
 		Protocol:          c.protocol,
 		ServiceMeta:       c.serviceMeta,
 		EnvoyExtensions:   c.envoyExtensions,
+		RetryPolicy:       c.retryPolicy,
 		StartNode:         c.startNode,
 		Nodes:             c.nodes,
 		Targets:           c.loadedTargets,
@@ -365,6 +378,11 @@ func (c *compiler) determineIfDefaultChain() bool {
 		return true
 	}
 
+	// A default retry policy needs routes, which default chains don't have.
+	if c.retryPolicy != nil {
+		return false
+	}
+
 	node := c.nodes[c.startNode]
 	if node == nil {
 		panic("not possible: missing node named '" + c.startNode + "' in chain '" + c.serviceName + "'")
@@ -566,6 +584,7 @@ func (c *compiler) assembleChain() error {
 	proxyDefaults := c.entries.GetProxyDefaults(c.GetEnterpriseMeta().PartitionOrDefault())
 	if proxyDefaults != nil {
 		c.envoyExtensions = proxyDefaults.EnvoyExtensions
+		c.retryPolicy = proxyDefaults.RetryPolicy
 	}
 
 	// Extract the service meta for the service named by this discovery chain and add extensions from the service
@@ -573,6 +592,9 @@ func (c *compiler) assembleChain() error {
 	if serviceDefault := c.entries.GetService(sid); serviceDefault != nil {
 		c.serviceMeta = serviceDefault.GetMeta()
 		c.envoyExtensions = append(c.envoyExtensions, serviceDefault.EnvoyExtensions...)
+		if serviceDefault.RetryPolicy != nil {
+			c.retryPolicy = serviceDefault.RetryPolicy
+		}
 	}
 
 	// Check for short circuit path.
@@ -955,7 +977,17 @@ RESOLVE_AGAIN:
 		}
 	}
 
+	// The connect timeout of the resolver takes precedence over the upstream
+	// connection pool defaults of the service.
+	var connectionPool structs.UpstreamConnectionPool
+	if serviceDefault := c.entries.GetService(targetID); serviceDefault != nil && serviceDefault.UpstreamConnectionPool != nil {
+		connectionPool = *serviceDefault.UpstreamConnectionPool
+	}
+
 	connectTimeout := resolver.ConnectTimeout
+	if connectTimeout < 1 {
+		connectTimeout = time.Duration(connectionPool.ConnectTimeoutMs) * time.Millisecond
+	}
 	if connectTimeout < 1 {
 		connectTimeout = 5 * time.Second
 	}
@@ -969,6 +1001,8 @@ RESOLVE_AGAIN:
 
 	// Expose a copy of this on the targets for ease of access.
 	target.ConnectTimeout = connectTimeout
+	target.MaxConcurrentStreams = connectionPool.MaxConcurrentStreams
+	target.MaxRequestsPerConnection = connectionPool.MaxRequestsPerConnection
 
 	// Build node.
 	node := &structs.DiscoveryGraphNode{
@@ -1039,6 +1073,11 @@ RESOLVE_AGAIN:
 				target.TransparentProxy.DialedDirectly = proxyDefault.TransparentProxy.DialedDirectly
 			}
 		}
+		if target.MeshGateway.Mode == structs.MeshGatewayModeDefault {
+			if meshDefault := c.entries.GetMeshDefaults(targetID.PartitionOrDefault()); meshDefault != nil {
+				target.MeshGateway.Mode = meshDefault.MeshGateway.Mode
+			}
+		}
 
 		if c.overrideMeshGateway.Mode != structs.MeshGatewayModeDefault {
 			if target.MeshGateway.Mode != c.overrideMeshGateway.Mode {
@@ -1077,7 +1116,11 @@ RESOLVE_AGAIN:
 		}
 
 		// Determine which failover definitions apply.
-		var failoverTargets []*structs.DiscoveryTarget
+		var (
+			failoverTargets    []*structs.DiscoveryTarget
+			failoverPriorities []int
+			hasPriorities      bool
+		)
 		if len(failover.Datacenters) > 0 {
 			opts := failover.ToDiscoveryTargetOpts()
 			for _, dc := range failover.Datacenters {
@@ -1088,14 +1131,41 @@ RESOLVE_AGAIN:
 					failoverTargets = append(failoverTargets, failoverTarget)
 				}
 			}
+		} else if failover.SamenessGroup != "" {
+			// A missing sameness group is treated like an empty one so that
+			// the chain still compiles while the group is being written.
+			groupMeta := structs.DefaultEnterpriseMetaInPartition(resolver.PartitionOrDefault())
+			group := c.entries.GetSamenessGroup(failover.SamenessGroup, groupMeta)
+			if group != nil {
+				opts := failover.ToDiscoveryTargetOpts()
+				// The members run the same service in the same namespace.
+				opts.Namespace = defaultIfEmpty(opts.Namespace, target.Namespace)
+				for _, member := range group.Members {
+					// Rewrite the target as per the failover policy.
+					opts.Peer = member.Peer
+					failoverTarget := c.rewriteTarget(target, opts)
+					if failoverTarget.ID != target.ID { // don't failover to yourself
+						failoverTargets = append(failoverTargets, failoverTarget)
+					}
+				}
+			}
 		} else if len(failover.Targets) > 0 {
 			for _, t := range failover.Targets {
 				// Rewrite the target as per the failover policy.
 				failoverTarget := c.rewriteTarget(target, t.ToDiscoveryTargetOpts())
 				if failoverTarget.ID != target.ID { // don't failover to yourself
 					failoverTargets = append(failoverTargets, failoverTarget)
+					failoverPriorities = append(failoverPriorities, t.Priority)
+					if t.Priority != 0 {
+						hasPriorities = true
+					}
 				}
 			}
+
+			if hasPriorities {
+				// Keep the configured order of targets within a priority group.
+				sort.Stable(failoverTargetsByPriority{targets: failoverTargets, priorities: failoverPriorities})
+			}
 		} else {
 			// Rewrite the target as per the failover policy.
 			failoverTarget := c.rewriteTarget(target, failover.ToDiscoveryTargetOpts())
@@ -1120,12 +1190,33 @@ RESOLVE_AGAIN:
 				failoverTarget := failoverResolveNode.Resolver.Target
 				df.Targets = append(df.Targets, failoverTarget)
 			}
+			if hasPriorities {
+				df.Priorities = failoverPriorities
+			}
 		}
 	}
 
 	return node, nil
 }
 
+// failoverTargetsByPriority sorts failover targets in ascending order of
+// their priority group.
+type failoverTargetsByPriority struct {
+	targets    []*structs.DiscoveryTarget
+	priorities []int
+}
+
+func (s failoverTargetsByPriority) Len() int { return len(s.targets) }
+
+func (s failoverTargetsByPriority) Less(i, j int) bool {
+	return s.priorities[i] < s.priorities[j]
+}
+
+func (s failoverTargetsByPriority) Swap(i, j int) {
+	s.targets[i], s.targets[j] = s.targets[j], s.targets[i]
+	s.priorities[i], s.priorities[j] = s.priorities[j], s.priorities[i]
+}
+
 func newDefaultServiceResolver(sid structs.ServiceID) *structs.ServiceResolverConfigEntry {
 	return &structs.ServiceResolverConfigEntry{
 		Kind:           structs.ServiceResolver,
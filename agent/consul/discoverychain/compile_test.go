@@ -50,6 +50,8 @@ func TestCompile(t *testing.T) {
 		"datacenter failover":                              testcase_DatacenterFailover(),
 		"datacenter failover with mesh gateways":           testcase_DatacenterFailover_WithMeshGateways(),
 		"target failover":                                  testcase_Failover_Targets(),
+		"target failover with priorities":                  testcase_Failover_TargetsWithPriorities(),
+		"sameness group failover":                          testcase_Failover_SamenessGroup(),
 		"noop split to resolver with default subset":       testcase_NoopSplit_WithDefaultSubset(),
 		"resolver with default subset":                     testcase_Resolve_WithDefaultSubset(),
 		"default resolver with external sni":               testcase_DefaultResolver_ExternalSNI(),
@@ -58,9 +60,13 @@ func TestCompile(t *testing.T) {
 		"loadbalancer splitter and resolver":               testcase_LBSplitterAndResolver(),
 		"loadbalancer resolver":                            testcase_LBResolver(),
 		"service redirect to service with default resolver is not a default chain": testcase_RedirectToDefaultResolverIsNotDefaultChain(),
-		"extensions":                            testcase_Extensions(),
-		"service meta projection":               testcase_ServiceMetaProjection(),
-		"service meta projection with redirect": testcase_ServiceMetaProjectionWithRedirect(),
+		"extensions":                             testcase_Extensions(),
+		"service meta projection":                testcase_ServiceMetaProjection(),
+		"service meta projection with redirect":  testcase_ServiceMetaProjectionWithRedirect(),
+		"retry policy":                           testcase_RetryPolicy(),
+		"retry policy ignored for tcp":           testcase_RetryPolicyIgnoredForTCP(),
+		"upstream connection pool":               testcase_UpstreamConnectionPool(),
+		"upstream connection pool with resolver": testcase_UpstreamConnectionPoolWithResolver(),
 
 		"all the bells and whistles": testcase_AllBellsAndWhistles(),
 		"multi dc canary":            testcase_MultiDatacenterCanary(),
@@ -1639,6 +1645,130 @@ func testcase_Failover_Targets() compileTestCase {
 	return compileTestCase{entries: entries, expect: expect}
 }
 
+func testcase_Failover_TargetsWithPriorities() compileTestCase {
+	entries := newEntries()
+
+	entries.AddResolvers(
+		&structs.ServiceResolverConfigEntry{
+			Kind: "service-resolver",
+			Name: "main",
+			Failover: map[string]structs.ServiceResolverFailover{
+				"*": {
+					Targets: []structs.ServiceResolverFailoverTarget{
+						{Datacenter: "dc3", Priority: 2},
+						{Peer: "cluster-01", Priority: 1},
+						{Peer: "cluster-02", Priority: 1},
+					},
+				},
+			},
+		},
+	)
+
+	newPeerTarget := func(peer string) *structs.DiscoveryTarget {
+		return newTarget(structs.DiscoveryTargetOpts{
+			Service: "main",
+			Peer:    peer,
+		}, func(t *structs.DiscoveryTarget) {
+			t.SNI = ""
+			t.Name = ""
+			t.Datacenter = ""
+		})
+	}
+
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "tcp",
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					Target:         "main.default.default.dc1",
+					Failover: &structs.DiscoveryFailover{
+						Targets: []string{
+							"main.default.default.external.cluster-01",
+							"main.default.default.external.cluster-02",
+							"main.default.default.dc3",
+						},
+						Priorities: []int{1, 1, 2},
+					},
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1": newTarget(structs.DiscoveryTargetOpts{Service: "main"}, nil),
+			"main.default.default.dc3": newTarget(structs.DiscoveryTargetOpts{
+				Service:    "main",
+				Datacenter: "dc3",
+			}, nil),
+			"main.default.default.external.cluster-01": newPeerTarget("cluster-01"),
+			"main.default.default.external.cluster-02": newPeerTarget("cluster-02"),
+		},
+	}
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_Failover_SamenessGroup() compileTestCase {
+	entries := newEntries()
+
+	entries.AddSamenessGroups(&structs.SamenessGroupConfigEntry{
+		Name: "group",
+		Members: []structs.SamenessGroupMember{
+			{Peer: "cluster-02"},
+			{Peer: "cluster-01"},
+		},
+	})
+
+	entries.AddResolvers(
+		&structs.ServiceResolverConfigEntry{
+			Kind: "service-resolver",
+			Name: "main",
+			Failover: map[string]structs.ServiceResolverFailover{
+				"*": {SamenessGroup: "group"},
+			},
+		},
+	)
+
+	newPeerTarget := func(peer string) *structs.DiscoveryTarget {
+		return newTarget(structs.DiscoveryTargetOpts{
+			Service: "main",
+			Peer:    peer,
+		}, func(t *structs.DiscoveryTarget) {
+			t.SNI = ""
+			t.Name = ""
+			t.Datacenter = ""
+		})
+	}
+
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "tcp",
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					Target:         "main.default.default.dc1",
+					Failover: &structs.DiscoveryFailover{
+						Targets: []string{
+							"main.default.default.external.cluster-02",
+							"main.default.default.external.cluster-01",
+						},
+					},
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1":                 newTarget(structs.DiscoveryTargetOpts{Service: "main"}, nil),
+			"main.default.default.external.cluster-01": newPeerTarget("cluster-01"),
+			"main.default.default.external.cluster-02": newPeerTarget("cluster-02"),
+		},
+	}
+	return compileTestCase{entries: entries, expect: expect}
+}
+
 func testcase_NoopSplit_WithDefaultSubset() compileTestCase {
 	entries := newEntries()
 	setServiceProtocol(entries, "main", "http")
@@ -1823,6 +1953,172 @@ func testcase_Extensions() compileTestCase {
 	return compileTestCase{entries: entries, expect: expect}
 }
 
+func testcase_RetryPolicy() compileTestCase {
+	entries := newEntries()
+	entries.AddProxyDefaults(&structs.ProxyConfigEntry{
+		Kind: structs.ProxyDefaults,
+		Name: structs.ProxyConfigGlobal,
+		RetryPolicy: &structs.RouteRetryPolicy{
+			NumRetries: 1,
+		},
+	})
+	entries.AddServices(
+		&structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     "main",
+			Protocol: "http",
+			RetryPolicy: &structs.RouteRetryPolicy{
+				NumRetries:    3,
+				RetryOn:       []string{"5xx"},
+				PerTryTimeout: 2 * time.Second,
+			},
+		},
+	)
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol: "http",
+		// The chain isn't default so that its routes are generated.
+		Default: false,
+		RetryPolicy: &structs.RouteRetryPolicy{
+			NumRetries:    3,
+			RetryOn:       []string{"5xx"},
+			PerTryTimeout: 2 * time.Second,
+		},
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					Default:        true,
+					ConnectTimeout: 5 * time.Second,
+					Target:         "main.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1": newTarget(structs.DiscoveryTargetOpts{Service: "main"}, nil),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_UpstreamConnectionPool() compileTestCase {
+	entries := newEntries()
+	entries.AddServices(
+		&structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     "main",
+			Protocol: "http2",
+			UpstreamConnectionPool: &structs.UpstreamConnectionPool{
+				ConnectTimeoutMs:         2500,
+				MaxConcurrentStreams:     100,
+				MaxRequestsPerConnection: 1000,
+			},
+		},
+	)
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "http2",
+		Default:   true,
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					Default:        true,
+					ConnectTimeout: 2500 * time.Millisecond,
+					Target:         "main.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1": newTarget(structs.DiscoveryTargetOpts{Service: "main"}, func(t *structs.DiscoveryTarget) {
+				t.ConnectTimeout = 2500 * time.Millisecond
+				t.MaxConcurrentStreams = 100
+				t.MaxRequestsPerConnection = 1000
+			}),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_UpstreamConnectionPoolWithResolver() compileTestCase {
+	entries := newEntries()
+	entries.AddServices(
+		&structs.ServiceConfigEntry{
+			Kind: structs.ServiceDefaults,
+			Name: "main",
+			UpstreamConnectionPool: &structs.UpstreamConnectionPool{
+				ConnectTimeoutMs:         2500,
+				MaxRequestsPerConnection: 1000,
+			},
+		},
+	)
+	entries.AddResolvers(
+		&structs.ServiceResolverConfigEntry{
+			Kind:           "service-resolver",
+			Name:           "main",
+			ConnectTimeout: 33 * time.Second,
+		},
+	)
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "tcp",
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					ConnectTimeout: 33 * time.Second,
+					Target:         "main.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1": newTarget(structs.DiscoveryTargetOpts{Service: "main"}, func(t *structs.DiscoveryTarget) {
+				t.ConnectTimeout = 33 * time.Second
+				t.MaxRequestsPerConnection = 1000
+			}),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_RetryPolicyIgnoredForTCP() compileTestCase {
+	entries := newEntries()
+	entries.AddProxyDefaults(&structs.ProxyConfigEntry{
+		Kind: structs.ProxyDefaults,
+		Name: structs.ProxyConfigGlobal,
+		RetryPolicy: &structs.RouteRetryPolicy{
+			NumRetries: 1,
+		},
+	})
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "tcp",
+		Default:   true,
+		StartNode: "resolver:main.default.default.dc1",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					Default:        true,
+					ConnectTimeout: 5 * time.Second,
+					Target:         "main.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1": newTarget(structs.DiscoveryTargetOpts{Service: "main"}, nil),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
 func testcase_ServiceMetaProjection() compileTestCase {
 	entries := newEntries()
 	entries.AddServices(
@@ -6,6 +6,7 @@ import (
 
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/raft"
 
 	"github.com/hashicorp/consul/agent/consul/state"
 	"github.com/hashicorp/consul/agent/structs"
@@ -18,10 +19,18 @@ var CommandsSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"fsm", "register"},
 		Help: "Measures the time it takes to apply a catalog register operation to the FSM.",
 	},
+	{
+		Name: []string{"fsm", "register_batch"},
+		Help: "Measures the time it takes to apply the catalog register operations of consecutive raft logs to the FSM in a single transaction.",
+	},
 	{
 		Name: []string{"fsm", "deregister"},
 		Help: "Measures the time it takes to apply a catalog deregister operation to the FSM.",
 	},
+	{
+		Name: []string{"fsm", "catalog_batch"},
+		Help: "Measures the time it takes to apply a catalog batch operation to the FSM.",
+	},
 	{
 		Name: []string{"fsm", "kvs"},
 		Help: "Measures the time it takes to apply the given KV operation to the FSM.",
@@ -38,6 +47,10 @@ var CommandsSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"fsm", "tombstone"},
 		Help: "Measures the time it takes to apply the given tombstone operation to the FSM.",
 	},
+	{
+		Name: []string{"fsm", "service_tombstone"},
+		Help: "Measures the time it takes to apply the given service tombstone operation to the FSM.",
+	},
 	{
 		Name: []string{"fsm", "coordinate", "batch-update"},
 		Help: "Measures the time it takes to apply the given batch coordinate update to the FSM.",
@@ -142,6 +155,12 @@ func init() {
 	registerCommand(structs.PeeringTrustBundleWriteType, (*FSM).applyPeeringTrustBundleWrite)
 	registerCommand(structs.PeeringTrustBundleDeleteType, (*FSM).applyPeeringTrustBundleDelete)
 	registerCommand(structs.PeeringSecretsWriteType, (*FSM).applyPeeringSecretsWrite)
+	registerCommand(structs.PeeringConfigEntriesWriteType, (*FSM).applyPeeringConfigEntriesWrite)
+	registerCommand(structs.PeeringConfigEntriesDeleteType, (*FSM).applyPeeringConfigEntriesDelete)
+	registerCommand(structs.CatalogBatchRequestType, (*FSM).applyCatalogBatch)
+	registerCommand(structs.ServiceTombstoneRequestType, (*FSM).applyServiceTombstoneOperation)
+	registerCommand(structs.ACLTokenUsageSetRequestType, (*FSM).applyACLTokenUsageSetOperation)
+	registerCommand(structs.ACLTokenBulkRequestType, (*FSM).applyACLTokenBulkOperation)
 }
 
 func (c *FSM) applyRegister(buf []byte, index uint64) interface{} {
@@ -159,6 +178,35 @@ func (c *FSM) applyRegister(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyRegisterBatch applies the catalog registrations of consecutive raft
+// logs in a single state store transaction. If one of them fails, they are
+// applied again one at a time so that the others are still applied and each
+// log gets the same response as it would have without batching.
+func (c *FSM) applyRegisterBatch(logs []*raft.Log) []interface{} {
+	defer metrics.MeasureSince([]string{"fsm", "register_batch"}, time.Now())
+	regs := make([]state.IndexedRegistration, len(logs))
+	for i, log := range logs {
+		var req structs.RegisterRequest
+		if err := structs.Decode(log.Data[1:], &req); err != nil {
+			panic(fmt.Errorf("failed to decode request: %v", err))
+		}
+		regs[i] = state.IndexedRegistration{Index: log.Index, Request: &req}
+	}
+
+	responses := make([]interface{}, len(logs))
+	if err := c.state.EnsureRegistrations(regs); err != nil {
+		c.logger.Debug("batched registrations failed, applying them one at a time",
+			"registrations", len(regs), "error", err)
+		for i, reg := range regs {
+			if err := c.state.EnsureRegistration(reg.Index, reg.Request); err != nil {
+				c.logger.Warn("EnsureRegistration failed", "error", err)
+				responses[i] = err
+			}
+		}
+	}
+	return responses
+}
+
 func (c *FSM) applyDeregister(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"fsm", "deregister"}, time.Now())
 	var req structs.DeregisterRequest
@@ -170,7 +218,7 @@ func (c *FSM) applyDeregister(buf []byte, index uint64) interface{} {
 	// here is also baked into vetDeregisterWithACL() in acl.go, so if you
 	// make changes here, be sure to also adjust the code over there.
 	if req.ServiceID != "" {
-		if err := c.state.DeleteService(index, req.Node, req.ServiceID, &req.EnterpriseMeta, req.PeerName); err != nil {
+		if err := c.state.DeleteServiceWithReason(index, req.Node, req.ServiceID, &req.EnterpriseMeta, req.PeerName, req.Reason); err != nil {
 			c.logger.Warn("DeleteNodeService failed", "error", err)
 			return err
 		}
@@ -180,7 +228,7 @@ func (c *FSM) applyDeregister(buf []byte, index uint64) interface{} {
 			return err
 		}
 	} else {
-		if err := c.state.DeleteNode(index, req.Node, &req.EnterpriseMeta, req.PeerName); err != nil {
+		if err := c.state.DeleteNodeWithReason(index, req.Node, &req.EnterpriseMeta, req.PeerName, req.Reason); err != nil {
 			c.logger.Warn("DeleteNode failed", "error", err)
 			return err
 		}
@@ -188,6 +236,21 @@ func (c *FSM) applyDeregister(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+func (c *FSM) applyCatalogBatch(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"fsm", "catalog_batch"}, time.Now())
+	var req structs.CatalogBatchRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Apply all updates in a single transaction
+	if err := c.state.CatalogBatch(index, &req); err != nil {
+		c.logger.Warn("CatalogBatch failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (c *FSM) applyKVSOperation(buf []byte, index uint64) interface{} {
 	var req structs.KVSRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -274,6 +337,22 @@ func (c *FSM) applyTombstoneOperation(buf []byte, index uint64) interface{} {
 	}
 }
 
+func (c *FSM) applyServiceTombstoneOperation(buf []byte, index uint64) interface{} {
+	var req structs.TombstoneRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "service_tombstone"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: string(req.Op)}})
+	switch req.Op {
+	case structs.TombstoneReap:
+		return c.state.ReapServiceTombstones(index, req.ReapIndex)
+	default:
+		c.logger.Warn("Invalid Service Tombstone operation", "operation", req.Op)
+		return fmt.Errorf("Invalid Service Tombstone operation '%s'", req.Op)
+	}
+}
+
 // applyCoordinateBatchUpdate processes a batch of coordinate updates and applies
 // them in a single underlying transaction. This interface isn't 1:1 with the outer
 // update interface that the coordinate endpoint exposes, so we made it single
@@ -506,6 +585,28 @@ func (c *FSM) applyACLTokenDeleteOperation(buf []byte, index uint64) interface{}
 	return c.state.ACLTokenBatchDelete(index, req.TokenIDs)
 }
 
+func (c *FSM) applyACLTokenUsageSetOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTokenUsageBatchSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "token"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "usage"}})
+
+	return c.state.ACLTokenUsageBatchSet(index, req.Usages)
+}
+
+func (c *FSM) applyACLTokenBulkOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTokenBulkWriteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "token"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "bulk"}})
+
+	return c.state.ACLTokenBulkWrite(index, req.Tokens, req.TokenIDs)
+}
+
 func (c *FSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
 	var req structs.ACLTokenBootstrapRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -778,3 +879,31 @@ func (c *FSM) applyPeeringTrustBundleDelete(buf []byte, index uint64) interface{
 	}
 	return c.state.PeeringTrustBundleDelete(index, q)
 }
+
+func (c *FSM) applyPeeringConfigEntriesWrite(buf []byte, index uint64) interface{} {
+	var req pbpeering.PeeringConfigEntriesWriteRequest
+	if err := structs.DecodeProto(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode peering config entries write request: %v", err))
+	}
+
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "peering_config_entries"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "write"}})
+
+	return c.state.PeeringConfigEntriesWrite(index, req.PeeringConfigEntries)
+}
+
+func (c *FSM) applyPeeringConfigEntriesDelete(buf []byte, index uint64) interface{} {
+	var req pbpeering.PeeringConfigEntriesDeleteRequest
+	if err := structs.DecodeProto(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode peering config entries delete request: %v", err))
+	}
+
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "peering_config_entries"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "delete"}})
+
+	q := state.Query{
+		Value:          req.Name,
+		EnterpriseMeta: *structs.NodeEnterpriseMetaInPartition(req.Partition),
+	}
+	return c.state.PeeringConfigEntriesDelete(index, q)
+}
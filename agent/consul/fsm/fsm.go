@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-raftchunking"
 	"github.com/hashicorp/raft"
+	"github.com/klauspost/compress/zstd"
 
 	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
 
@@ -46,7 +47,7 @@ func registerCommand(msg structs.MessageType, fn unboundCommand) {
 type FSM struct {
 	deps    Deps
 	logger  hclog.Logger
-	chunker *raftchunking.ChunkingFSM
+	chunker *raftchunking.ChunkingBatchingFSM
 
 	// apply is built off the commands global and is used to route apply
 	// operations to their appropriate handlers.
@@ -83,6 +84,16 @@ type Deps struct {
 	NewStateStore func() *state.Store
 
 	Publisher *stream.EventPublisher
+
+	// SnapshotCompressionLevel is the zstd level the snapshots are compressed
+	// with. The snapshots aren't compressed when it is not set.
+	SnapshotCompressionLevel zstd.EncoderLevel
+
+	// CanCompressSnapshots returns whether the snapshots can be compressed
+	// yet, which they are only once every server is able to restore them. It
+	// is optional, the snapshots being compressed whenever
+	// SnapshotCompressionLevel is set when it is nil.
+	CanCompressSnapshots func() bool
 }
 
 // NewFromDeps creates a new FSM from its dependencies.
@@ -106,7 +117,7 @@ func NewFromDeps(deps Deps) *FSM {
 		}
 	}
 
-	fsm.chunker = raftchunking.NewChunkingFSM(fsm, nil)
+	fsm.chunker = raftchunking.NewChunkingBatchingFSM(fsm, nil)
 
 	// register the streaming snapshot handlers if an event publisher was provided.
 	fsm.registerStreamSnapshotHandlers()
@@ -145,6 +156,12 @@ func (c *FSM) Apply(log *raft.Log) interface{} {
 		ignoreUnknown = true
 	}
 
+	// Compressed entries wrap an entry of another type, which is applied once
+	// decompressed.
+	if msgType == structs.CompressedRequestType {
+		return c.Apply(decompressLog(log))
+	}
+
 	// Apply based on the dispatch table, if possible.
 	if fn := c.apply[msgType]; fn != nil {
 		return fn(buf[1:], log.Index)
@@ -159,6 +176,76 @@ func (c *FSM) Apply(log *raft.Log) interface{} {
 	panic(fmt.Errorf("failed to apply request: %#v", buf))
 }
 
+// ApplyBatch implements raft.BatchingFSM. Raft hands over the logs committed
+// together, and runs of consecutive catalog registrations among them are
+// applied in a single state store transaction, which increases the write
+// throughput of registration-heavy workloads such as catalog sync. The other
+// logs are applied one at a time, in order.
+func (c *FSM) ApplyBatch(logs []*raft.Log) []interface{} {
+	// The compressed logs are classified by the type of the entry they wrap.
+	decompressed := make([]*raft.Log, len(logs))
+	for i, log := range logs {
+		decompressed[i] = decompressLog(log)
+	}
+	logs = decompressed
+
+	responses := make([]interface{}, len(logs))
+	for i := 0; i < len(logs); {
+		// Only commands are applied to the FSM, raft also hands over the
+		// configuration changes.
+		if logs[i].Type != raft.LogCommand {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(logs) && isRegisterLog(logs[j]) {
+			j++
+		}
+		if j-i > 1 {
+			copy(responses[i:j], c.applyRegisterBatch(logs[i:j]))
+			i = j
+			continue
+		}
+
+		responses[i] = c.Apply(logs[i])
+		i++
+	}
+	return responses
+}
+
+// decompressLog returns a copy of the log with the entry wrapped in it if it is
+// compressed, and the log itself otherwise.
+func decompressLog(log *raft.Log) *raft.Log {
+	if log.Type != raft.LogCommand ||
+		len(log.Data) == 0 ||
+		structs.MessageType(log.Data[0]) != structs.CompressedRequestType {
+		return log
+	}
+	data, err := structs.DecompressEntry(log.Data[1:])
+	if err != nil {
+		panic(fmt.Errorf("failed to decompress request: %v", err))
+	}
+	decompressed := *log
+	decompressed.Data = data
+	return &decompressed
+}
+
+func isRegisterLog(log *raft.Log) bool {
+	return log.Type == raft.LogCommand &&
+		len(log.Data) > 0 &&
+		structs.MessageType(log.Data[0]) == structs.RegisterRequestType
+}
+
+// snapshotCompressionLevel returns the zstd level the snapshots are
+// compressed with, or zero if they aren't compressed.
+func (c *FSM) snapshotCompressionLevel() zstd.EncoderLevel {
+	if c.deps.CanCompressSnapshots != nil && !c.deps.CanCompressSnapshots() {
+		return 0
+	}
+	return c.deps.SnapshotCompressionLevel
+}
+
 func (c *FSM) Snapshot() (raft.FSMSnapshot, error) {
 	defer func(start time.Time) {
 		c.logger.Info("snapshot created", "duration", time.Since(start).String())
@@ -170,8 +257,9 @@ func (c *FSM) Snapshot() (raft.FSMSnapshot, error) {
 	}
 
 	return &snapshot{
-		state:      c.state.Snapshot(),
-		chunkState: chunkState,
+		state:            c.state.Snapshot(),
+		chunkState:       chunkState,
+		compressionLevel: c.snapshotCompressionLevel(),
 	}, nil
 }
 
@@ -187,6 +275,15 @@ func (c *FSM) Restore(old io.ReadCloser) error {
 	defer restore.Abort()
 
 	handler := func(header *SnapshotHeader, msg structs.MessageType, dec *codec.Decoder) error {
+		// Records of types that older servers may not know are written with
+		// the IgnoreUnknownTypeFlag, so that they can be skipped rather than
+		// failing the restore.
+		ignoreUnknown := false
+		if msg&structs.IgnoreUnknownTypeFlag == structs.IgnoreUnknownTypeFlag {
+			msg &= ^structs.IgnoreUnknownTypeFlag
+			ignoreUnknown = true
+		}
+
 		switch {
 		case msg == structs.ChunkingStateType:
 			chunkState := &raftchunking.State{
@@ -203,6 +300,12 @@ func (c *FSM) Restore(old io.ReadCloser) error {
 			if err := fn(header, restore, dec); err != nil {
 				return err
 			}
+		case ignoreUnknown:
+			var discard interface{}
+			if err := dec.Decode(&discard); err != nil {
+				return err
+			}
+			c.logger.Warn("ignoring unknown snapshot record type, upgrade to newer version", "type", msg)
 		default:
 			if msg >= 64 {
 				return fmt.Errorf("msg type <%d> is a Consul Enterprise log entry. Consul OSS cannot restore it", msg)
@@ -253,6 +356,12 @@ func (c *FSM) Restore(old io.ReadCloser) error {
 // ReadSnapshot decodes each message type and utilizes the handler function to
 // process each message type individually
 func ReadSnapshot(r io.Reader, handler func(header *SnapshotHeader, msg structs.MessageType, dec *codec.Decoder) error) error {
+	r, release, err := DecompressSnapshot(r)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Create a decoder
 	dec := codec.NewDecoder(r, structs.MsgpackHandle)
 
@@ -289,7 +398,7 @@ func (c *FSM) registerStreamSnapshotHandlers() {
 
 	err := c.deps.Publisher.RegisterHandler(state.EventTopicServiceHealth, func(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
 		return c.State().ServiceHealthSnapshot(req, buf)
-	}, false)
+	}, true)
 	if err != nil {
 		panic(fmt.Errorf("fatal error encountered registering streaming snapshot handlers: %w", err))
 	}
@@ -384,4 +493,18 @@ func (c *FSM) registerStreamSnapshotHandlers() {
 	if err != nil {
 		panic(fmt.Errorf("fatal error encountered registering streaming snapshot handlers: %w", err))
 	}
+
+	err = c.deps.Publisher.RegisterHandler(state.EventTopicAPIGatewayPolicy, func(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+		return c.State().APIGatewayPolicySnapshot(req, buf)
+	}, true)
+	if err != nil {
+		panic(fmt.Errorf("fatal error encountered registering streaming snapshot handlers: %w", err))
+	}
+
+	err = c.deps.Publisher.RegisterHandler(state.EventTopicKV, func(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+		return c.State().KVSnapshot(req, buf)
+	}, false)
+	if err != nil {
+		panic(fmt.Errorf("fatal error encountered registering streaming snapshot handlers: %w", err))
+	}
 }
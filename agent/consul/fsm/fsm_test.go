@@ -5,9 +5,12 @@ import (
 	"testing"
 
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/raft"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type MockSink struct {
@@ -63,3 +66,144 @@ func TestFSM_NilLogger(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, fsm)
 }
+
+func TestFSM_ApplyBatch(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+
+	register := func(node string, check *structs.HealthCheck) []byte {
+		buf, err := structs.Encode(structs.RegisterRequestType, structs.RegisterRequest{
+			Datacenter:     "dc1",
+			Node:           node,
+			Address:        "127.0.0.1",
+			Check:          check,
+			EnterpriseMeta: *structs.NodeEnterpriseMetaInDefaultPartition(),
+		})
+		require.NoError(t, err)
+		return buf
+	}
+	kvs, err := structs.Encode(structs.KVSRequestType, structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt:     structs.DirEntry{Key: "foo", Value: []byte("bar")},
+	})
+	require.NoError(t, err)
+
+	var logs []*raft.Log
+	for _, buf := range [][]byte{
+		register("foo", nil),
+		register("bar", nil),
+		// The check of another node fails the registration.
+		register("baz", &structs.HealthCheck{Node: "other", CheckID: "check"}),
+		kvs,
+		register("qux", nil),
+	} {
+		logs = append(logs, &raft.Log{
+			Index: uint64(len(logs) + 1),
+			Term:  1,
+			Type:  raft.LogCommand,
+			Data:  buf,
+		})
+	}
+	logs = append(logs, &raft.Log{Index: 6, Term: 1, Type: raft.LogConfiguration})
+
+	responses := fsm.ChunkingFSM().(raft.BatchingFSM).ApplyBatch(logs)
+	require.Len(t, responses, len(logs))
+	for i, resp := range responses {
+		if i == 2 {
+			require.ErrorContains(t, resp.(error), `check node "other" does not match node "baz"`)
+			continue
+		}
+		require.Nil(t, resp, "response %d", i)
+	}
+
+	// The registrations are applied at the index of their own log.
+	for node, idx := range map[string]uint64{"foo": 1, "bar": 2, "qux": 5} {
+		_, n, err := fsm.state.GetNode(node, nil, "")
+		require.NoError(t, err)
+		require.NotNil(t, n, node)
+		require.Equal(t, idx, n.ModifyIndex, node)
+	}
+	_, n, err := fsm.state.GetNode("baz", nil, "")
+	require.NoError(t, err)
+	require.Nil(t, n)
+
+	_, entry, err := fsm.state.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), entry.ModifyIndex)
+}
+
+func TestFSM_ApplyBatch_Compressed(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	var logs []*raft.Log
+	for _, node := range []string{"foo", "bar", "baz"} {
+		buf, err := structs.Encode(structs.RegisterRequestType, structs.RegisterRequest{
+			Datacenter:     "dc1",
+			Node:           node,
+			Address:        "127.0.0.1",
+			EnterpriseMeta: *structs.NodeEnterpriseMetaInDefaultPartition(),
+		})
+		require.NoError(t, err)
+		if node == "bar" {
+			buf = structs.CompressEntry(enc, buf)
+		}
+		logs = append(logs, &raft.Log{
+			Index: uint64(len(logs) + 1),
+			Term:  1,
+			Type:  raft.LogCommand,
+			Data:  buf,
+		})
+	}
+
+	// The compressed registration is batched with the others.
+	require.False(t, isRegisterLog(logs[1]))
+	require.True(t, isRegisterLog(decompressLog(logs[1])))
+
+	responses := fsm.ChunkingFSM().(raft.BatchingFSM).ApplyBatch(logs)
+	require.Equal(t, []interface{}{nil, nil, nil}, responses)
+
+	for node, idx := range map[string]uint64{"foo": 1, "bar": 2, "baz": 3} {
+		_, n, err := fsm.state.GetNode(node, nil, "")
+		require.NoError(t, err)
+		require.NotNil(t, n, node)
+		require.Equal(t, idx, n.ModifyIndex, node)
+	}
+}
+
+func TestFSM_Apply_Compressed(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+
+	buf, err := structs.Encode(structs.KVSRequestType, structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt:     structs.DirEntry{Key: "foo", Value: bytes.Repeat([]byte("bar"), 1024)},
+	})
+	require.NoError(t, err)
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	compressed := structs.CompressEntry(enc, buf)
+	require.Equal(t, uint8(structs.CompressedRequestType), compressed[0])
+	require.Less(t, len(compressed), len(buf))
+
+	resp := fsm.Apply(makeLog(compressed))
+	require.Nil(t, resp)
+
+	_, entry, err := fsm.state.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, bytes.Repeat([]byte("bar"), 1024), entry.Value)
+	require.Equal(t, uint64(1), entry.ModifyIndex)
+}
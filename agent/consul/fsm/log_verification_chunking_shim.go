@@ -13,7 +13,7 @@ import (
 )
 
 type logVerificationChunkingShim struct {
-	chunker *raftchunking.ChunkingFSM
+	chunker *raftchunking.ChunkingBatchingFSM
 }
 
 var logVerifierMagicBytes [8]byte
@@ -24,6 +24,42 @@ func init() {
 
 // Apply implements raft.FSM.
 func (s *logVerificationChunkingShim) Apply(l *raft.Log) interface{} {
+	if isLogVerifierCheckpoint(l) {
+		// Handle the checkpoint here since the lower level FSM doesn't know
+		// anything about it! The LogStore has already done what we need, we just
+		// need to return the index so that the caller can know which index the
+		// checkpoint ended up at.
+		return l.Index
+	}
+	return s.chunker.Apply(l)
+}
+
+// ApplyBatch implements raft.BatchingFSM. The checkpoints are answered here,
+// like in Apply, and the other logs are passed on to the chunker in order.
+func (s *logVerificationChunkingShim) ApplyBatch(logs []*raft.Log) []interface{} {
+	responses := make([]interface{}, len(logs))
+	sendLogs := make([]*raft.Log, 0, len(logs))
+	sentIdx := make([]int, 0, len(logs))
+	for i, l := range logs {
+		if isLogVerifierCheckpoint(l) {
+			responses[i] = l.Index
+			continue
+		}
+		sendLogs = append(sendLogs, l)
+		sentIdx = append(sentIdx, i)
+	}
+
+	if len(sendLogs) > 0 {
+		for i, resp := range s.chunker.ApplyBatch(sendLogs) {
+			responses[sentIdx[i]] = resp
+		}
+	}
+	return responses
+}
+
+// isLogVerifierCheckpoint returns whether the log is a checkpoint of the
+// verifier.LogStore.
+func isLogVerifierCheckpoint(l *raft.Log) bool {
 	// This is a hack because raftchunking doesn't play nicely with lower-level
 	// usage of Extensions field like we need for LogStore verification. We might
 	// change that instead but just seeing if I can get this to work here without
@@ -39,17 +75,10 @@ func (s *logVerificationChunkingShim) Apply(l *raft.Log) interface{} {
 	// docs on that value for more detail on why not. Note the data length for a
 	// checkpoint is actually 2 because msgpack encodes the nil slice as a typed
 	// nil byte (0xc0).
-	if len(l.Data) == 2 &&
+	return len(l.Data) == 2 &&
 		structs.MessageType(l.Data[0]) == (structs.RaftLogVerifierCheckpoint|structs.IgnoreUnknownTypeFlag) &&
 		len(l.Extensions) > 8 &&
-		bytes.Equal(logVerifierMagicBytes[:], l.Extensions[0:8]) {
-		// Handle the checkpoint here since the lower level FSM doesn't know
-		// anything about it! The LogStore has already done what we need, we just
-		// need to return the index so that the caller can know which index the
-		// checkpoint ended up at.
-		return l.Index
-	}
-	return s.chunker.Apply(l)
+		bytes.Equal(logVerifierMagicBytes[:], l.Extensions[0:8])
 }
 
 // Snapshot implements raft.FSM
@@ -1,7 +1,9 @@
 package fsm
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -11,6 +13,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/go-raftchunking"
 	"github.com/hashicorp/raft"
+	"github.com/klauspost/compress/zstd"
 )
 
 var SnapshotSummaries = []prometheus.SummaryDefinition{
@@ -26,6 +29,10 @@ var SnapshotSummaries = []prometheus.SummaryDefinition{
 type snapshot struct {
 	state      *state.Snapshot
 	chunkState *raftchunking.State
+
+	// compressionLevel is the zstd level the snapshot is compressed with, if
+	// set.
+	compressionLevel zstd.EncoderLevel
 }
 
 // SnapshotHeader is the first entry in our snapshot
@@ -69,6 +76,28 @@ func registerRestorer(msg structs.MessageType, fn restorer) {
 func (s *snapshot) Persist(sink raft.SnapshotSink) error {
 	defer metrics.MeasureSince([]string{"fsm", "persist"}, time.Now())
 
+	// The compressed snapshots are written through the encoder, which must
+	// be closed to flush the end of the snapshot.
+	if s.compressionLevel != 0 {
+		zw, err := zstd.NewWriter(sink, zstd.WithEncoderLevel(s.compressionLevel))
+		if err != nil {
+			sink.Cancel()
+			return err
+		}
+		if err := s.persist(&compressedSink{SnapshotSink: sink, zw: zw}); err != nil {
+			zw.Close()
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			sink.Cancel()
+			return err
+		}
+		return nil
+	}
+	return s.persist(sink)
+}
+
+func (s *snapshot) persist(sink raft.SnapshotSink) error {
 	// Write the header
 	header := SnapshotHeader{
 		LastIndex: s.state.LastIndex(),
@@ -104,3 +133,42 @@ func (s *snapshot) Persist(sink raft.SnapshotSink) error {
 func (s *snapshot) Release() {
 	s.state.Close()
 }
+
+// compressedSink is a raft.SnapshotSink writing to the underlying sink
+// through a zstd encoder.
+type compressedSink struct {
+	raft.SnapshotSink
+	zw *zstd.Encoder
+}
+
+func (s *compressedSink) Write(p []byte) (int, error) {
+	return s.zw.Write(p)
+}
+
+// zstdMagic is the magic number zstd frames start with. It can't be the first
+// byte of a snapshot that isn't compressed, which starts with the msgpack
+// encoding of the SnapshotHeader.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// DecompressSnapshot returns a reader of the snapshot, which decompresses it
+// if it was compressed when persisted. The returned function releases the
+// resources of the decompression, once the snapshot is read.
+func DecompressSnapshot(r io.Reader) (io.Reader, func(), error) {
+	// Read the magic number without reading ahead, so that the readers of the
+	// snapshot can keep counting the bytes they read.
+	magic := make([]byte, len(zstdMagic))
+	n, err := io.ReadFull(r, magic)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	r = io.MultiReader(bytes.NewReader(magic[:n]), r)
+	if !bytes.Equal(magic[:n], zstdMagic) {
+		return r, func() {}, nil
+	}
+
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, zr.Close, nil
+}
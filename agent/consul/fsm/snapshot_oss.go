@@ -41,6 +41,9 @@ func init() {
 	registerRestorer(structs.PeeringWriteType, restorePeering)
 	registerRestorer(structs.PeeringTrustBundleWriteType, restorePeeringTrustBundle)
 	registerRestorer(structs.PeeringSecretsWriteType, restorePeeringSecrets)
+	registerRestorer(structs.PeeringConfigEntriesWriteType, restorePeeringConfigEntries)
+	registerRestorer(structs.ServiceTombstoneRequestType, restoreServiceTombstone)
+	registerRestorer(structs.ACLTokenUsageSetRequestType, restoreTokenUsage)
 }
 
 func persistOSS(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) error {
@@ -62,6 +65,9 @@ func persistOSS(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) err
 	if err := s.persistTombstones(sink, encoder); err != nil {
 		return err
 	}
+	if err := s.persistServiceTombstones(sink, encoder); err != nil {
+		return err
+	}
 	if err := s.persistPreparedQueries(sink, encoder); err != nil {
 		return err
 	}
@@ -101,6 +107,9 @@ func persistOSS(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) err
 	if err := s.persistPeeringSecrets(sink, encoder); err != nil {
 		return err
 	}
+	if err := s.persistPeeringConfigEntries(sink, encoder); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -215,6 +224,21 @@ func (s *snapshot) persistACLs(sink raft.SnapshotSink,
 		}
 	}
 
+	usages, err := s.state.ACLTokenUsages()
+	if err != nil {
+		return err
+	}
+
+	for usage := usages.Next(); usage != nil; usage = usages.Next() {
+		// The usages are flagged so that servers without them can skip them.
+		if _, err := sink.Write([]byte{byte(structs.ACLTokenUsageSetRequestType | structs.IgnoreUnknownTypeFlag)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(usage.(*structs.ACLTokenUsage)); err != nil {
+			return err
+		}
+	}
+
 	policies, err := s.state.ACLPolicies()
 	if err != nil {
 		return err
@@ -321,6 +345,25 @@ func (s *snapshot) persistTombstones(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *snapshot) persistServiceTombstones(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	stones, err := s.state.ServiceTombstones()
+	if err != nil {
+		return err
+	}
+
+	for stone := stones.Next(); stone != nil; stone = stones.Next() {
+		// Older servers skip the service tombstones, which they don't know.
+		if _, err := sink.Write([]byte{byte(structs.ServiceTombstoneRequestType | structs.IgnoreUnknownTypeFlag)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(stone.(*structs.ServiceTombstone)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *snapshot) persistPreparedQueries(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	queries, err := s.state.PreparedQueries()
@@ -606,6 +649,24 @@ func (s *snapshot) persistPeeringSecrets(sink raft.SnapshotSink, encoder *codec.
 	return nil
 }
 
+func (s *snapshot) persistPeeringConfigEntries(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	pces, err := s.state.PeeringConfigEntries()
+	if err != nil {
+		return err
+	}
+
+	for entry := pces.Next(); entry != nil; entry = pces.Next() {
+		if _, err := sink.Write([]byte{byte(structs.PeeringConfigEntriesWriteType | structs.IgnoreUnknownTypeFlag)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(entry.(*pbpeering.PeeringConfigEntries)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func restoreRegistration(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req structs.RegisterRequest
 	if err := decoder.Decode(&req); err != nil {
@@ -647,6 +708,17 @@ func restoreTombstone(header *SnapshotHeader, restore *state.Restore, decoder *c
 	return nil
 }
 
+func restoreServiceTombstone(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.ServiceTombstone
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	if err := restore.ServiceTombstone(&req); err != nil {
+		return err
+	}
+	return nil
+}
+
 func restoreSession(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req structs.Session
 	if err := decoder.Decode(&req); err != nil {
@@ -756,6 +828,14 @@ func restoreToken(header *SnapshotHeader, restore *state.Restore, decoder *codec
 	return restore.ACLToken(&req)
 }
 
+func restoreTokenUsage(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.ACLTokenUsage
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	return restore.ACLTokenUsage(&req)
+}
+
 func restorePolicy(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req structs.ACLPolicy
 	if err := decoder.Decode(&req); err != nil {
@@ -898,3 +978,14 @@ func restorePeeringSecrets(header *SnapshotHeader, restore *state.Restore, decod
 	}
 	return nil
 }
+
+func restorePeeringConfigEntries(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req pbpeering.PeeringConfigEntries
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	if err := restore.PeeringConfigEntries(&req); err != nil {
+		return err
+	}
+	return nil
+}
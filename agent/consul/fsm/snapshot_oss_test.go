@@ -46,3 +46,29 @@ func TestRestoreFromEnterprise(t *testing.T) {
 	require.EqualError(t, fsm.Restore(sink), "msg type <65> is a Consul Enterprise log entry. Consul OSS cannot restore it")
 	sink.Cancel()
 }
+
+func TestRestore_IgnoreUnknownType(t *testing.T) {
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+
+	encoder := codec.NewEncoder(sink, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(&SnapshotHeader{LastIndex: 0}))
+
+	// A record of a type added by a newer version is skipped.
+	sink.Write([]byte{byte(structs.MessageType(63) | structs.IgnoreUnknownTypeFlag)})
+	require.NoError(t, encoder.Encode(map[string]interface{}{"Name": "from the future"}))
+
+	// A known record type with the flag is restored as usual.
+	sink.Write([]byte{byte(structs.RegisterRequestType | structs.IgnoreUnknownTypeFlag)})
+	require.NoError(t, encoder.Encode(&structs.RegisterRequest{Node: "foo", Address: "127.0.0.1"}))
+
+	require.NoError(t, fsm.Restore(sink))
+
+	_, node, err := fsm.state.GetNode("foo", nil, "")
+	require.NoError(t, err)
+	require.NotNil(t, node)
+}
@@ -2,11 +2,13 @@ package fsm
 
 import (
 	"bytes"
+	"fmt"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-raftchunking"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
@@ -116,6 +118,13 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	}
 	require.NoError(t, fsm.state.ACLBootstrap(10, 0, token))
 
+	tokenUsage := &structs.ACLTokenUsage{
+		AccessorID:   token.AccessorID,
+		LastUsedTime: time.Now().UTC().Round(0),
+		LastUsedAddr: "10.0.0.1",
+	}
+	require.NoError(t, fsm.state.ACLTokenUsageBatchSet(11, []*structs.ACLTokenUsage{tokenUsage}))
+
 	method := &structs.ACLAuthMethod{
 		Name:        "some-method",
 		Type:        "testing",
@@ -659,6 +668,14 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	// adds the Hash to our local var.
 	require.Equal(t, token, rtoken)
 
+	// Verify the usage of the ACL Token is restored
+	rusage, err := fsm2.state.ACLTokenUsageGet(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, rusage)
+	require.True(t, tokenUsage.LastUsedTime.Equal(rusage.LastUsedTime))
+	require.Equal(t, tokenUsage.LastUsedAddr, rusage.LastUsedAddr)
+	require.Equal(t, uint64(11), rusage.ModifyIndex)
+
 	// Verify ACLToken without hash computes the Hash during restoration
 	_, rtoken, err = fsm2.state.ACLTokenGetByAccessor(nil, token2.AccessorID, nil)
 	require.NoError(t, err)
@@ -890,6 +907,71 @@ func TestFSM_BadRestore_OSS(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_Compressed(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	fsm := NewFromDeps(Deps{
+		Logger:                   logger,
+		NewStateStore:            func() *state.Store { return state.NewStateStore(nil) },
+		SnapshotCompressionLevel: zstd.SpeedBestCompression,
+	})
+	for i := 0; i < 100; i++ {
+		require.NoError(t, fsm.state.EnsureNode(uint64(i+1), &structs.Node{
+			Node:    fmt.Sprintf("node-%d", i),
+			Address: "127.0.0.1",
+		}))
+	}
+
+	// Snapshot
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+
+	// Persist
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+	require.NoError(t, snap.Persist(sink))
+	require.Equal(t, zstdMagic, buf.Bytes()[:len(zstdMagic)])
+
+	// Restore on an FSM which doesn't compress its snapshots.
+	fsm2, err := New(nil, logger)
+	require.NoError(t, err)
+	require.NoError(t, fsm2.Restore(sink))
+
+	_, nodes, err := fsm2.state.Nodes(nil, nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 100)
+}
+
+func TestFSM_Snapshot_CompressionNotSupported(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	canCompress := false
+	fsm := NewFromDeps(Deps{
+		Logger:                   logger,
+		NewStateStore:            func() *state.Store { return state.NewStateStore(nil) },
+		SnapshotCompressionLevel: zstd.SpeedBestCompression,
+		CanCompressSnapshots:     func() bool { return canCompress },
+	})
+	require.NoError(t, fsm.state.EnsureNode(1, &structs.Node{Node: "node", Address: "127.0.0.1"}))
+
+	persist := func() []byte {
+		snap, err := fsm.Snapshot()
+		require.NoError(t, err)
+		defer snap.Release()
+
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, snap.Persist(&MockSink{buf, false}))
+		return buf.Bytes()
+	}
+
+	// The snapshot isn't compressed until every server can restore it.
+	require.NotEqual(t, zstdMagic, persist()[:len(zstdMagic)])
+
+	canCompress = true
+	require.Equal(t, zstdMagic, persist()[:len(zstdMagic)])
+}
+
 func TestFSM_BadSnapshot_NilCAConfig(t *testing.T) {
 	t.Parallel()
 
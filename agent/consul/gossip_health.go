@@ -0,0 +1,179 @@
+package consul
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/serf/serf"
+)
+
+const (
+	// gossipPartitionWindow is how recent the failures of the peers must be
+	// to count towards a suspected partition. Older failures are more likely
+	// nodes that are gone and not yet reaped.
+	gossipPartitionWindow = 5 * time.Minute
+
+	// gossipPartitionMinFailed is the minimum number of peers that must have
+	// recently failed for a partition to be suspected.
+	gossipPartitionMinFailed = 2
+)
+
+// GossipHealth is the health of the LAN gossip pool of the agent, as seen
+// from the agent.
+type GossipHealth struct {
+	// Node is the name of the local node.
+	Node string
+
+	// HealthScore is the awareness score of memberlist. It rises above 0 when
+	// the local node misses probe acks or has to refute suspicions of itself,
+	// which points at the local node rather than at its peers.
+	HealthScore int
+
+	// PartitionSuspected is true when a large share of the peers failed
+	// recently, which is more likely a network partition between the local
+	// node and those peers than that many independent node failures.
+	PartitionSuspected bool
+
+	// Peers are the other members of the gossip pool, sorted by name.
+	Peers []GossipPeerHealth
+}
+
+// GossipPeerHealth is the health of the gossip with one peer.
+type GossipPeerHealth struct {
+	Node    string
+	Address string
+	Status  string
+
+	// RTT is the round trip time to the peer estimated from the network
+	// coordinates, which are updated from the RTTs of the gossip probes. It
+	// is 0 when the coordinate of the peer is unknown.
+	RTT time.Duration
+
+	// Failures is the number of times the peer was declared failed after
+	// going unanswered while suspected, since the agent started.
+	Failures int
+
+	// Flaps is the number of times the peer came back alive after having
+	// been declared failed, since the agent started.
+	Flaps int
+
+	// LastFailure is the time the peer was last declared failed, if ever.
+	LastFailure time.Time
+}
+
+// gossipHealthTracker records the failures and recoveries of the members of
+// a gossip pool from its member events, which serf doesn't keep track of.
+type gossipHealthTracker struct {
+	lock  sync.Mutex
+	peers map[string]*gossipPeerEvents
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+type gossipPeerEvents struct {
+	failed      bool
+	failures    int
+	flaps       int
+	lastFailure time.Time
+}
+
+func newGossipHealthTracker() *gossipHealthTracker {
+	return &gossipHealthTracker{
+		peers: make(map[string]*gossipPeerEvents),
+		now:   time.Now,
+	}
+}
+
+// handleEvent records the member event of the gossip pool.
+func (t *gossipHealthTracker) handleEvent(me serf.MemberEvent) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, m := range me.Members {
+		switch me.Type {
+		case serf.EventMemberLeave, serf.EventMemberReap:
+			delete(t.peers, m.Name)
+			continue
+		case serf.EventMemberJoin, serf.EventMemberFailed:
+		default:
+			continue
+		}
+
+		p, ok := t.peers[m.Name]
+		if !ok {
+			p = &gossipPeerEvents{}
+			t.peers[m.Name] = p
+		}
+		if me.Type == serf.EventMemberFailed {
+			p.failed = true
+			p.failures++
+			p.lastFailure = t.now()
+		} else if p.failed {
+			p.failed = false
+			p.flaps++
+		}
+	}
+}
+
+// health returns the health of the gossip pool of the serf instance.
+func (t *gossipHealthTracker) health(s *serf.Serf) GossipHealth {
+	local := s.LocalMember()
+	out := GossipHealth{
+		Node:        local.Name,
+		HealthScore: s.Memberlist().GetHealthScore(),
+		Peers:       []GossipPeerHealth{},
+	}
+
+	// The coordinates are disabled with disable_coordinates, in which case
+	// there are no RTTs to report.
+	coord, err := s.GetCoordinate()
+	if err != nil {
+		coord = nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	var recentlyFailed int
+	for _, m := range s.Members() {
+		if m.Name == local.Name || m.Status == serf.StatusLeft {
+			continue
+		}
+
+		peer := GossipPeerHealth{
+			Node:    m.Name,
+			Address: m.Addr.String(),
+			Status:  m.Status.String(),
+		}
+		if coord != nil {
+			if other, ok := s.GetCachedCoordinate(m.Name); ok && coord.IsCompatibleWith(other) {
+				peer.RTT = coord.DistanceTo(other)
+			}
+		}
+		if p, ok := t.peers[m.Name]; ok {
+			peer.Failures = p.failures
+			peer.Flaps = p.flaps
+			peer.LastFailure = p.lastFailure
+			if m.Status == serf.StatusFailed && now.Sub(p.lastFailure) < gossipPartitionWindow {
+				recentlyFailed++
+			}
+		}
+		out.Peers = append(out.Peers, peer)
+	}
+	sort.Slice(out.Peers, func(i, j int) bool {
+		return out.Peers[i].Node < out.Peers[j].Node
+	})
+
+	out.PartitionSuspected = partitionSuspected(recentlyFailed, len(out.Peers))
+	return out
+}
+
+// partitionSuspected returns whether the number of peers that failed recently
+// out of all the peers is more likely a partition than independent failures:
+// at least a third of the peers, and more than one.
+func partitionSuspected(recentlyFailed, peers int) bool {
+	return recentlyFailed >= gossipPartitionMinFailed && recentlyFailed*3 >= peers
+}
@@ -0,0 +1,54 @@
+package consul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGossipHealthTracker_HandleEvent(t *testing.T) {
+	now := time.Date(2023, 10, 16, 10, 0, 0, 0, time.UTC)
+	tracker := newGossipHealthTracker()
+	tracker.now = func() time.Time { return now }
+
+	event := func(typ serf.EventType, names ...string) serf.MemberEvent {
+		me := serf.MemberEvent{Type: typ}
+		for _, name := range names {
+			me.Members = append(me.Members, serf.Member{Name: name})
+		}
+		return me
+	}
+
+	tracker.handleEvent(event(serf.EventMemberJoin, "node1", "node2", "node3"))
+	tracker.handleEvent(event(serf.EventMemberFailed, "node1", "node2"))
+	failedAt := now
+	now = now.Add(time.Minute)
+	tracker.handleEvent(event(serf.EventMemberJoin, "node1"))
+	tracker.handleEvent(event(serf.EventMemberUpdate, "node2"))
+	tracker.handleEvent(event(serf.EventMemberLeave, "node3"))
+
+	require.Equal(t, map[string]*gossipPeerEvents{
+		"node1": {failures: 1, flaps: 1, lastFailure: failedAt},
+		"node2": {failed: true, failures: 1, lastFailure: failedAt},
+	}, tracker.peers)
+}
+
+func TestPartitionSuspected(t *testing.T) {
+	cases := []struct {
+		recentlyFailed int
+		peers          int
+		expected       bool
+	}{
+		{recentlyFailed: 0, peers: 10, expected: false},
+		{recentlyFailed: 1, peers: 2, expected: false},
+		{recentlyFailed: 2, peers: 10, expected: false},
+		{recentlyFailed: 2, peers: 6, expected: true},
+		{recentlyFailed: 5, peers: 5, expected: true},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.expected, partitionSuspected(tc.recentlyFailed, tc.peers),
+			"%d of %d peers failed", tc.recentlyFailed, tc.peers)
+	}
+}
@@ -0,0 +1,144 @@
+package consul
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/serf/serf"
+)
+
+const (
+	GossipProfileDefault = "default"
+	GossipProfileLarge   = "large"
+	GossipProfileXLarge  = "xlarge"
+
+	// gossipProfileMetricsInterval is how often the size of the LAN gossip
+	// pool is compared with the size its gossip profile is meant for.
+	gossipProfileMetricsInterval = 10 * time.Second
+)
+
+var GossipProfileGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"serf", "lan", "profile", "utilization"},
+		Help: "Ratio of the number of alive members of the LAN gossip pool to the maximum number of members its gossip profile is meant for.",
+	},
+	{
+		Name: []string{"serf", "lan", "profile", "undersized"},
+		Help: "1 if the LAN gossip pool has more alive members than its gossip profile is meant for, 0 otherwise.",
+	},
+}
+
+// GossipProfile is a predefined set of LAN gossip settings meant for clusters
+// up to a number of members. The larger profiles probe less often and wait
+// longer before declaring the nodes failed, which keeps the gossip traffic
+// and the false failures down in very large clusters at the expense of
+// slower failure detection.
+type GossipProfile struct {
+	// MaxMembers is the number of members of the gossip pool above which the
+	// next larger profile should be used.
+	MaxMembers int
+
+	GossipNodes    int
+	GossipInterval time.Duration
+	ProbeInterval  time.Duration
+	ProbeTimeout   time.Duration
+	SuspicionMult  int
+	RetransmitMult int
+
+	// PushPullInterval is the interval between full state syncs with a random
+	// member for a pool of up to 32 members. Memberlist scales it up with the
+	// logarithm of the number of members beyond that.
+	PushPullInterval time.Duration
+}
+
+// gossipProfileNames are the names of the gossip profiles, from the smallest to
+// the largest.
+var gossipProfileNames = []string{GossipProfileDefault, GossipProfileLarge, GossipProfileXLarge}
+
+// GossipProfiles are the gossip profiles by name. The default profile matches
+// the LAN defaults of memberlist.
+var GossipProfiles = map[string]GossipProfile{
+	GossipProfileDefault: {
+		MaxMembers:       5000,
+		GossipNodes:      3,
+		GossipInterval:   200 * time.Millisecond,
+		ProbeInterval:    time.Second,
+		ProbeTimeout:     500 * time.Millisecond,
+		SuspicionMult:    4,
+		RetransmitMult:   4,
+		PushPullInterval: 30 * time.Second,
+	},
+	GossipProfileLarge: {
+		MaxMembers:       15000,
+		GossipNodes:      4,
+		GossipInterval:   200 * time.Millisecond,
+		ProbeInterval:    2 * time.Second,
+		ProbeTimeout:     time.Second,
+		SuspicionMult:    6,
+		RetransmitMult:   5,
+		PushPullInterval: 60 * time.Second,
+	},
+	GossipProfileXLarge: {
+		MaxMembers:       40000,
+		GossipNodes:      5,
+		GossipInterval:   300 * time.Millisecond,
+		ProbeInterval:    3 * time.Second,
+		ProbeTimeout:     1500 * time.Millisecond,
+		SuspicionMult:    8,
+		RetransmitMult:   6,
+		PushPullInterval: 120 * time.Second,
+	},
+}
+
+// recommendedGossipProfile returns the smallest gossip profile meant for a pool
+// of the given number of members, or the largest profile when none is.
+func recommendedGossipProfile(members int) string {
+	for _, name := range gossipProfileNames {
+		if members <= GossipProfiles[name].MaxMembers {
+			return name
+		}
+	}
+	return gossipProfileNames[len(gossipProfileNames)-1]
+}
+
+// monitorGossipProfile periodically reports how full the LAN gossip pool is
+// compared with the size its gossip profile is meant for, and warns once the
+// pool outgrows the profile. It returns when shutdownCh is closed.
+func monitorGossipProfile(logger hclog.Logger, s *serf.Serf, profileName string, shutdownCh <-chan struct{}) {
+	profile, ok := GossipProfiles[profileName]
+	if !ok {
+		return
+	}
+
+	var undersized bool
+	for {
+		select {
+		case <-time.After(gossipProfileMetricsInterval):
+		case <-shutdownCh:
+			return
+		}
+
+		members := s.Memberlist().NumMembers()
+		labels := []metrics.Label{{Name: "profile", Value: profileName}}
+		metrics.SetGaugeWithLabels([]string{"serf", "lan", "profile", "utilization"},
+			float32(members)/float32(profile.MaxMembers), labels)
+
+		wasUndersized := undersized
+		undersized = members > profile.MaxMembers
+		if undersized {
+			metrics.SetGaugeWithLabels([]string{"serf", "lan", "profile", "undersized"}, 1, labels)
+		} else {
+			metrics.SetGaugeWithLabels([]string{"serf", "lan", "profile", "undersized"}, 0, labels)
+		}
+		if undersized && !wasUndersized {
+			logger.Warn("LAN gossip pool is larger than its gossip profile is meant for, which may cause false node failures",
+				"profile", profileName,
+				"members", members,
+				"max_members", profile.MaxMembers,
+				"recommended_profile", recommendedGossipProfile(members),
+			)
+		}
+	}
+}
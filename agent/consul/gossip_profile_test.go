@@ -0,0 +1,37 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGossipProfiles_DefaultMatchesMemberlist(t *testing.T) {
+	lan := memberlist.DefaultLANConfig()
+	profile := GossipProfiles[GossipProfileDefault]
+
+	require.Equal(t, lan.GossipNodes, profile.GossipNodes)
+	require.Equal(t, lan.GossipInterval, profile.GossipInterval)
+	require.Equal(t, lan.ProbeInterval, profile.ProbeInterval)
+	require.Equal(t, lan.ProbeTimeout, profile.ProbeTimeout)
+	require.Equal(t, lan.SuspicionMult, profile.SuspicionMult)
+	require.Equal(t, lan.RetransmitMult, profile.RetransmitMult)
+	require.Equal(t, lan.PushPullInterval, profile.PushPullInterval)
+}
+
+func TestGossipProfiles_Sizes(t *testing.T) {
+	// Every profile is meant for larger pools than the previous one.
+	for i := 1; i < len(gossipProfileNames); i++ {
+		smaller := GossipProfiles[gossipProfileNames[i-1]]
+		larger := GossipProfiles[gossipProfileNames[i]]
+		require.Greater(t, larger.MaxMembers, smaller.MaxMembers)
+		require.GreaterOrEqual(t, larger.ProbeInterval, smaller.ProbeInterval)
+	}
+
+	require.Equal(t, GossipProfileDefault, recommendedGossipProfile(1))
+	require.Equal(t, GossipProfileDefault, recommendedGossipProfile(5000))
+	require.Equal(t, GossipProfileLarge, recommendedGossipProfile(5001))
+	require.Equal(t, GossipProfileXLarge, recommendedGossipProfile(20000))
+	require.Equal(t, GossipProfileXLarge, recommendedGossipProfile(100000))
+}
@@ -189,6 +189,7 @@ func (h *Health) ServiceChecks(args *structs.ServiceSpecificRequest,
 
 // ServiceNodes returns all the nodes registered as part of a service including health info
 func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *structs.IndexedCheckServiceNodes) error {
+	h.srv.forwardPageIfBehind(&args.QueryOptions, args.PageOptions)
 	if done, err := h.srv.ForwardRPC("Health.ServiceNodes", args, reply); done {
 		return err
 	}
@@ -211,6 +212,28 @@ func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *struc
 		f = h.serviceNodesDefault
 	}
 
+	// Resolve what we can of the filters of tag and default lookups using the
+	// catalog indexes, so that only the matching instances are read.
+	if !args.Connect && (args.TagFilter || !args.Ingress) {
+		if indexedFilter := serviceIndexedFilter(args, checkServiceNodeIndexedSelectors); !indexedFilter.IsEmpty() {
+			f = h.serviceNodesIndexedFilter(indexedFilter)
+		}
+	}
+
+	// Connect lookups can't use the indexes, but still skip reading the nodes
+	// and checks of the instances that don't match, which matters for the
+	// service-resolver subsets. They ignore the tag filters, so only the filter
+	// expression and the node meta filters are used.
+	if args.Connect {
+		indexedFilter := serviceIndexedFilter(&structs.ServiceSpecificRequest{
+			NodeMetaFilters: args.NodeMetaFilters,
+			QueryOptions:    structs.QueryOptions{Filter: args.Filter},
+		}, checkServiceNodeIndexedSelectors)
+		if !indexedFilter.IsEmpty() {
+			f = h.serviceNodesConnectIndexedFilter(indexedFilter)
+		}
+	}
+
 	authzContext := acl.AuthorizerContext{
 		Peer: args.PeerName,
 	}
@@ -243,20 +266,38 @@ func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *struc
 		ranMergeOnce   bool
 	)
 
+	// The reads of the service instances are shared with the identical
+	// blocking queries, which are keyed by the arguments of the read. The key
+	// is empty when the arguments couldn't be hashed, and the read isn't
+	// shared then.
+	var sharedKey string
+	if key := args.CacheInfo().Key; key != "" {
+		sharedKey = "Health.ServiceNodes/" + key
+	}
+
 	err = h.srv.blockingQuery(
 		&args.QueryOptions,
 		&reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
 			var thisReply structs.IndexedCheckServiceNodes
 
-			index, nodes, err := f(ws, state, args)
+			result, err := h.srv.sharedReads.read(sharedKey, state, ws, func(ws memdb.WatchSet) (interface{}, error) {
+				index, nodes, err := f(ws, state, args)
+				return structs.IndexedCheckServiceNodes{Nodes: nodes, QueryMeta: structs.QueryMeta{Index: index}}, err
+			})
 			if err != nil {
 				return err
 			}
 
-			resolvedNodes := nodes
+			// The result is shared, so its nodes are copied before they are
+			// filtered and sorted in place.
+			shared := result.(structs.IndexedCheckServiceNodes)
+			index := shared.Index
+			resolvedNodes := make(structs.CheckServiceNodes, len(shared.Nodes))
+			copy(resolvedNodes, shared.Nodes)
+
 			if args.MergeCentralConfig {
-				for _, node := range resolvedNodes {
+				for i, node := range resolvedNodes {
 					ns := node.Service
 					if ns.IsSidecarProxy() || ns.IsGateway() {
 						cfgIndex, mergedns, err := configentry.MergeNodeServiceWithCentralConfig(ws, state, ns, h.logger)
@@ -266,7 +307,7 @@ func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *struc
 						if cfgIndex > index {
 							index = cfgIndex
 						}
-						*node.Service = *mergedns
+						resolvedNodes[i].Service = mergedns
 					}
 				}
 
@@ -309,7 +350,18 @@ func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *struc
 				return err
 			}
 
-			if err := h.srv.sortNodesByDistanceFrom(args.Source, thisReply.Nodes); err != nil {
+			if args.OnlyPassing {
+				thisReply.Nodes = thisReply.Nodes.Filter(true)
+			}
+
+			// The paginated instances are ordered by their key rather than by
+			// distance, so that the cursors are stable.
+			if args.IsPaginated() {
+				if err := args.CheckIndex(thisReply.Index); err != nil {
+					return err
+				}
+				thisReply.Nodes, thisReply.NextCursor = pageCheckServiceNodes(args.PageOptions, thisReply.Nodes)
+			} else if err := h.srv.sortNodesByDistanceFrom(args.Source, thisReply.Nodes); err != nil {
 				return err
 			}
 
@@ -377,6 +429,37 @@ func (h *Health) serviceNodesTagFilter(ws memdb.WatchSet, s *state.Store, args *
 	return s.CheckServiceTagNodes(ws, args.ServiceName, args.ServiceTags, &args.EnterpriseMeta, args.PeerName)
 }
 
+func (h *Health) serviceNodesIndexedFilter(filter *state.ServiceIndexedFilter) func(memdb.WatchSet, *state.Store, *structs.ServiceSpecificRequest) (uint64, structs.CheckServiceNodes, error) {
+	return func(ws memdb.WatchSet, s *state.Store, args *structs.ServiceSpecificRequest) (uint64, structs.CheckServiceNodes, error) {
+		return s.CheckServiceNodesWithFilter(ws, args.ServiceName, filter, &args.EnterpriseMeta, args.PeerName)
+	}
+}
+
+func (h *Health) serviceNodesConnectIndexedFilter(filter *state.ServiceIndexedFilter) func(memdb.WatchSet, *state.Store, *structs.ServiceSpecificRequest) (uint64, structs.CheckServiceNodes, error) {
+	return func(ws memdb.WatchSet, s *state.Store, args *structs.ServiceSpecificRequest) (uint64, structs.CheckServiceNodes, error) {
+		return s.CheckConnectServiceNodesWithFilter(ws, args.ServiceName, filter, &args.EnterpriseMeta, args.PeerName)
+	}
+}
+
 func (h *Health) serviceNodesDefault(ws memdb.WatchSet, s *state.Store, args *structs.ServiceSpecificRequest) (uint64, structs.CheckServiceNodes, error) {
 	return s.CheckServiceNodes(ws, args.ServiceName, &args.EnterpriseMeta, args.PeerName)
 }
+
+// pageCheckServiceNodes returns the requested page of the service instances,
+// which are ordered by node name and service ID, and the cursor of the next
+// page. The nodes are sorted in place.
+func pageCheckServiceNodes(page structs.PageOptions, nodes structs.CheckServiceNodes) (structs.CheckServiceNodes, string) {
+	key := func(n structs.CheckServiceNode) string {
+		return n.Node.Node + "/" + n.Service.ID
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return key(nodes[i]) < key(nodes[j])
+	})
+
+	keys := make([]string, len(nodes))
+	for i, n := range nodes {
+		keys[i] = key(n)
+	}
+	start, end, next := page.Page(keys)
+	return nodes[start:end], next
+}
@@ -0,0 +1,103 @@
+package consul
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// indexedFilterSelectors holds the filter selectors of the fields that can be
+// looked up using the catalog indexes, which differ between result types.
+type indexedFilterSelectors struct {
+	ServiceMeta string
+	NodeMeta    string
+	ServiceTags string
+}
+
+var (
+	serviceNodeIndexedSelectors = indexedFilterSelectors{
+		ServiceMeta: "ServiceMeta",
+		NodeMeta:    "NodeMeta",
+		ServiceTags: "ServiceTags",
+	}
+	checkServiceNodeIndexedSelectors = indexedFilterSelectors{
+		ServiceMeta: "Service.Meta",
+		NodeMeta:    "Node.Meta",
+		ServiceTags: "Service.Tags",
+	}
+)
+
+// serviceIndexedFilter returns the constraints of the given request that can
+// be resolved using the catalog indexes, gathered from its filter expression
+// as well as its tag and node meta filters. The filters still have to be
+// applied to the results, since the constraints only narrow down the lookup.
+func serviceIndexedFilter(args *structs.ServiceSpecificRequest, selectors indexedFilterSelectors) *state.ServiceIndexedFilter {
+	filter := &state.ServiceIndexedFilter{
+		ServiceMeta: make(map[string]string),
+		NodeMeta:    make(map[string]string),
+	}
+
+	if args.Filter != "" {
+		// Invalid expressions are reported when the filter is created, so
+		// they can be ignored here.
+		if ast, err := bexpr.Parse("", []byte(args.Filter)); err == nil {
+			if expr, ok := ast.(bexpr.Expression); ok {
+				collectIndexedConstraints(expr, selectors, filter)
+			}
+		}
+	}
+
+	if args.TagFilter {
+		// DEPRECATED (singular-service-tag) - remove this when backwards RPC compat
+		// with 1.2.x is not required.
+		if args.ServiceTag != "" {
+			filter.Tags = append(filter.Tags, args.ServiceTag)
+		} else {
+			filter.Tags = append(filter.Tags, args.ServiceTags...)
+		}
+	}
+	for key, value := range args.NodeMetaFilters {
+		filter.NodeMeta[key] = value
+	}
+
+	return filter
+}
+
+// collectIndexedConstraints adds the equality matches of the expression that
+// must hold for it to match to the filter. Anything under an "or" or a "not"
+// is skipped, since the matches it contains may not have to hold.
+func collectIndexedConstraints(expr bexpr.Expression, selectors indexedFilterSelectors, filter *state.ServiceIndexedFilter) {
+	switch e := expr.(type) {
+	case *bexpr.BinaryExpression:
+		if e.Operator == bexpr.BinaryOpAnd {
+			collectIndexedConstraints(e.Left, selectors, filter)
+			collectIndexedConstraints(e.Right, selectors, filter)
+		}
+
+	case *bexpr.MatchExpression:
+		if e.Value == nil || e.Value.Raw == "" || len(e.Selector) == 0 {
+			return
+		}
+
+		switch e.Operator {
+		case bexpr.MatchEqual:
+			// Meta selectors are the map selector followed by the key.
+			last := len(e.Selector) - 1
+			prefix := strings.Join(e.Selector[:last], ".")
+			switch prefix {
+			case selectors.ServiceMeta:
+				filter.ServiceMeta[e.Selector[last]] = e.Value.Raw
+			case selectors.NodeMeta:
+				filter.NodeMeta[e.Selector[last]] = e.Value.Raw
+			}
+
+		case bexpr.MatchIn:
+			if e.Selector.String() == selectors.ServiceTags {
+				filter.Tags = append(filter.Tags, e.Value.Raw)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestServiceIndexedFilter(t *testing.T) {
+	empty := func() *state.ServiceIndexedFilter {
+		return &state.ServiceIndexedFilter{
+			ServiceMeta: map[string]string{},
+			NodeMeta:    map[string]string{},
+		}
+	}
+
+	cases := map[string]struct {
+		args      structs.ServiceSpecificRequest
+		selectors indexedFilterSelectors
+		expected  func() *state.ServiceIndexedFilter
+	}{
+		"no filter": {
+			selectors: serviceNodeIndexedSelectors,
+			expected:  empty,
+		},
+		"invalid filter": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{Filter: "ServiceMeta.env =="},
+			},
+			selectors: serviceNodeIndexedSelectors,
+			expected:  empty,
+		},
+		"service node selectors": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{
+					Filter: `ServiceMeta.env == "prod" and NodeMeta.rack == a and "primary" in ServiceTags and Port == 80`,
+				},
+			},
+			selectors: serviceNodeIndexedSelectors,
+			expected: func() *state.ServiceIndexedFilter {
+				f := empty()
+				f.ServiceMeta["env"] = "prod"
+				f.NodeMeta["rack"] = "a"
+				f.Tags = []string{"primary"}
+				return f
+			},
+		},
+		"check service node selectors": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{
+					Filter: `Service.Meta.env == "prod" and (Node.Meta.rack == "a" and Service.Tags contains "primary")`,
+				},
+			},
+			selectors: checkServiceNodeIndexedSelectors,
+			expected: func() *state.ServiceIndexedFilter {
+				f := empty()
+				f.ServiceMeta["env"] = "prod"
+				f.NodeMeta["rack"] = "a"
+				f.Tags = []string{"primary"}
+				return f
+			},
+		},
+		"service-resolver subset": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{
+					Filter: structs.ServiceResolverSubset{
+						Filter: `Service.Meta.env == "prod" or "canary" in Service.Tags`,
+						Meta:   map[string]string{"version": "v2", "shard-id": "eu"},
+					}.FilterExpression(),
+				},
+			},
+			selectors: checkServiceNodeIndexedSelectors,
+			expected: func() *state.ServiceIndexedFilter {
+				f := empty()
+				f.ServiceMeta["version"] = "v2"
+				f.ServiceMeta["shard-id"] = "eu"
+				return f
+			},
+		},
+		"or and not are skipped": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{
+					Filter: `ServiceMeta.env == "prod" and (NodeMeta.rack == "a" or "primary" in ServiceTags) and not ServiceMeta.team == "x"`,
+				},
+			},
+			selectors: serviceNodeIndexedSelectors,
+			expected: func() *state.ServiceIndexedFilter {
+				f := empty()
+				f.ServiceMeta["env"] = "prod"
+				return f
+			},
+		},
+		"other operators are skipped": {
+			args: structs.ServiceSpecificRequest{
+				QueryOptions: structs.QueryOptions{
+					Filter: `ServiceMeta.env != "prod" and "primary" not in ServiceTags and ServiceMeta.team matches "x.*"`,
+				},
+			},
+			selectors: serviceNodeIndexedSelectors,
+			expected:  empty,
+		},
+		"tag and node meta filters": {
+			args: structs.ServiceSpecificRequest{
+				TagFilter:       true,
+				ServiceTags:     []string{"primary", "v1"},
+				NodeMetaFilters: map[string]string{"rack": "a"},
+				QueryOptions: structs.QueryOptions{
+					Filter: `"canary" in Service.Tags`,
+				},
+			},
+			selectors: checkServiceNodeIndexedSelectors,
+			expected: func() *state.ServiceIndexedFilter {
+				f := empty()
+				f.NodeMeta["rack"] = "a"
+				f.Tags = []string{"canary", "primary", "v1"}
+				return f
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := serviceIndexedFilter(&tc.args, tc.selectors)
+			require.Equal(t, tc.expected(), actual)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package consul
+
+import (
+	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// intentionDecisionCacheMaxEntries bounds the number of decisions cached, so
+// that checks of many distinct pairs of services don't grow the cache
+// without limit. The cache is emptied once it is full.
+const intentionDecisionCacheMaxEntries = 65536
+
+var IntentionCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"intention", "decision_cache", "hit"},
+		Help: "Increments when an intention check is answered from the decision cache of the server.",
+	},
+	{
+		Name: []string{"intention", "decision_cache", "miss"},
+		Help: "Increments when an intention check has to match the intentions because its decision isn't cached.",
+	},
+}
+
+// intentionDecisionKey identifies an intention decision. The default decision
+// depends on the token of the check, so it is part of the key.
+type intentionDecisionKey struct {
+	Source          structs.IntentionMatchEntry
+	Destination     structs.IntentionMatchEntry
+	DefaultDecision acl.EnforcementDecision
+}
+
+// intentionDecisionCache caches the decisions of the intention checks, so
+// that the intentions of the source aren't matched against the destination
+// again for every check of the same pair of services. The decisions are
+// valid as long as the intentions don't change, so the whole cache is
+// emptied once the intentions index moves past the one it was filled at.
+type intentionDecisionCache struct {
+	lock sync.Mutex
+
+	// store and index are the state store and the intentions index the
+	// cached decisions were made at.
+	store     *state.Store
+	index     uint64
+	decisions map[intentionDecisionKey]structs.IntentionDecisionSummary
+}
+
+func newIntentionDecisionCache() *intentionDecisionCache {
+	return &intentionDecisionCache{
+		decisions: make(map[intentionDecisionKey]structs.IntentionDecisionSummary),
+	}
+}
+
+// decide returns the decision of the key from the cache, or makes it with fn
+// and caches it.
+func (c *intentionDecisionCache) decide(
+	store *state.Store,
+	key intentionDecisionKey,
+	fn func() (structs.IntentionDecisionSummary, error),
+) (structs.IntentionDecisionSummary, error) {
+	// The index is read before the decision is made, so that a decision made
+	// from intentions written after the index was read is thrown away at
+	// the next check rather than kept.
+	index := store.IntentionsIndex()
+
+	c.lock.Lock()
+	if c.store != store || c.index != index {
+		// The state store is replaced when a snapshot is restored, after
+		// which the indexes can't be compared anymore.
+		c.store = store
+		c.index = index
+		c.decisions = make(map[intentionDecisionKey]structs.IntentionDecisionSummary)
+	}
+	decision, ok := c.decisions[key]
+	c.lock.Unlock()
+
+	if ok {
+		metrics.IncrCounter([]string{"intention", "decision_cache", "hit"}, 1)
+		return decision, nil
+	}
+	metrics.IncrCounter([]string{"intention", "decision_cache", "miss"}, 1)
+
+	decision, err := fn()
+	if err != nil {
+		return decision, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.store == store && c.index == index {
+		if len(c.decisions) >= intentionDecisionCacheMaxEntries {
+			c.decisions = make(map[intentionDecisionKey]structs.IntentionDecisionSummary)
+		}
+		c.decisions[key] = decision
+	}
+	return decision, nil
+}
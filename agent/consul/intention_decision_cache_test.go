@@ -0,0 +1,54 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestIntentionDecisionCache(t *testing.T) {
+	store := state.NewStateStore(nil)
+	c := newIntentionDecisionCache()
+
+	var calls int
+	decide := func(store *state.Store, key intentionDecisionKey) structs.IntentionDecisionSummary {
+		t.Helper()
+		decision, err := c.decide(store, key, func() (structs.IntentionDecisionSummary, error) {
+			calls++
+			return structs.IntentionDecisionSummary{Allowed: key.DefaultDecision == acl.Allow}, nil
+		})
+		require.NoError(t, err)
+		return decision
+	}
+
+	web := structs.IntentionMatchEntry{Namespace: "default", Name: "web"}
+	db := structs.IntentionMatchEntry{Namespace: "default", Name: "db"}
+	allow := intentionDecisionKey{Source: web, Destination: db, DefaultDecision: acl.Allow}
+	deny := intentionDecisionKey{Source: web, Destination: db, DefaultDecision: acl.Deny}
+
+	require.True(t, decide(store, allow).Allowed)
+	require.True(t, decide(store, allow).Allowed)
+	require.Equal(t, 1, calls)
+
+	// The default decision is part of the key.
+	require.False(t, decide(store, deny).Allowed)
+	require.Equal(t, 2, calls)
+
+	// The decisions are made again once the intentions may have changed,
+	// which any write of a config entry may do.
+	require.NoError(t, store.EnsureConfigEntry(10, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "db",
+	}))
+	decide(store, allow)
+	decide(store, allow)
+	require.Equal(t, 3, calls)
+
+	// And once the state store is replaced by a snapshot restore.
+	decide(state.NewStateStore(nil), allow)
+	require.Equal(t, 4, calls)
+}
@@ -80,6 +80,9 @@ func (s *Intention) Apply(args *structs.IntentionRequest, reply *string) error {
 	if args.Intention != nil && args.Intention.SourcePeer != "" {
 		return fmt.Errorf("SourcePeer field is not supported on this endpoint. Use config entries instead")
 	}
+	if args.Intention != nil && args.Intention.SourceSamenessGroup != "" {
+		return fmt.Errorf("SourceSamenessGroup field is not supported on this endpoint. Use config entries instead")
+	}
 
 	// Ensure that all service-intentions config entry writes go to the primary
 	// datacenter. These will then be replicated to all the other datacenters.
@@ -770,24 +773,39 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 		Partition: query.SourcePartition,
 		Name:      query.SourceName,
 	}
-	_, intentions, err := store.IntentionMatchOne(nil, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
-	if err != nil {
-		return fmt.Errorf("failed to query intentions for %s/%s", query.SourceNS, query.SourceName)
+	key := intentionDecisionKey{
+		Source: entry,
+		Destination: structs.IntentionMatchEntry{
+			Namespace: query.DestinationNS,
+			Partition: query.DestinationPartition,
+			Name:      query.DestinationName,
+		},
+		DefaultDecision: defaultDecision,
 	}
+	decision, err := s.srv.intentionDecisions.decide(store, key, func() (structs.IntentionDecisionSummary, error) {
+		_, intentions, err := store.IntentionMatchOne(nil, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
+		if err != nil {
+			return structs.IntentionDecisionSummary{}, fmt.Errorf("failed to query intentions for %s/%s", query.SourceNS, query.SourceName)
+		}
 
-	opts := state.IntentionDecisionOpts{
-		Target:           query.DestinationName,
-		Namespace:        query.DestinationNS,
-		Partition:        query.DestinationPartition,
-		Intentions:       intentions,
-		MatchType:        structs.IntentionMatchDestination,
-		DefaultDecision:  defaultDecision,
-		AllowPermissions: false,
-	}
-	decision, err := store.IntentionDecision(opts)
+		opts := state.IntentionDecisionOpts{
+			Target:           query.DestinationName,
+			Namespace:        query.DestinationNS,
+			Partition:        query.DestinationPartition,
+			Intentions:       intentions,
+			MatchType:        structs.IntentionMatchDestination,
+			DefaultDecision:  defaultDecision,
+			AllowPermissions: false,
+		}
+		decision, err := store.IntentionDecision(opts)
+		if err != nil {
+			return decision, fmt.Errorf("failed to get intention decision from (%s/%s) to (%s/%s): %v",
+				query.SourceNS, query.SourceName, query.DestinationNS, query.DestinationName, err)
+		}
+		return decision, nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get intention decision from (%s/%s) to (%s/%s): %v",
-			query.SourceNS, query.SourceName, query.DestinationNS, query.DestinationName, err)
+		return err
 	}
 	reply.Allowed = decision.Allowed
 
@@ -90,9 +90,66 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 		}
 	}
 
+	// The expiration time is based on wall-time too, so it is set from the
+	// TTL before commit using the wall-time of the leader.
+	if dirEnt.ExpirationTTL != 0 {
+		if dirEnt.ExpirationTTL < 0 {
+			return false, fmt.Errorf("Key TTL cannot be negative")
+		}
+		expires := time.Now().Add(dirEnt.ExpirationTTL)
+		dirEnt.ExpirationTime = &expires
+		dirEnt.ExpirationTTL = 0
+	}
+
+	// Large values are compressed before commit so they stay compressed in
+	// the Raft log, the state store and snapshots. They are decompressed when
+	// read.
+	dirEnt.Compressed = false
+	switch op {
+	case api.KVSet, api.KVCAS, api.KVLock, api.KVUnlock:
+		if srv.canCompressKVValues() {
+			if err := dirEnt.CompressValue(srv.config.KVCompressionThreshold); err != nil {
+				return false, err
+			}
+		}
+	}
+
 	return true, nil
 }
 
+// canCompressKVValues returns whether the large KV values are compressed. They
+// are only once every server of the datacenter can decompress them, otherwise
+// the older servers would serve the compressed values to their readers. This
+// is remembered once true, as for the other features which require every
+// server to be upgraded.
+func (s *Server) canCompressKVValues() bool {
+	if s.config.KVCompressionThreshold <= 0 {
+		return false
+	}
+	if s.kvCompressionReady.Load() {
+		return true
+	}
+	if ok, found := ServersInDCSupportFeature(s, s.config.Datacenter, "kc"); !ok || !found {
+		return false
+	}
+	s.kvCompressionReady.Store(true)
+	return true
+}
+
+// canPatchKVValues returns whether the KV patch operations are accepted. They
+// are only once every server of the datacenter can apply them, otherwise the
+// older servers would fail on the transactions containing them.
+func (s *Server) canPatchKVValues() bool {
+	if s.kvPatchReady.Load() {
+		return true
+	}
+	if ok, found := ServersInDCSupportFeature(s, s.config.Datacenter, "kp"); !ok || !found {
+		return false
+	}
+	s.kvPatchReady.Store(true)
+	return true
+}
+
 // Apply is used to apply a KVS update request to the data store.
 func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 	if done, err := k.srv.ForwardRPC("KVS.Apply", args, reply); done {
@@ -115,6 +172,7 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 		return err
 	}
 	if !ok {
+		k.srv.lockTracker.observe(args.Op, &args.DirEnt, false)
 		*reply = false
 		return nil
 	}
@@ -128,6 +186,7 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 	// Check if the return type is a bool.
 	if respBool, ok := resp.(bool); ok {
 		*reply = respBool
+		k.srv.lockTracker.observe(args.Op, &args.DirEnt, respBool)
 	}
 	return nil
 }
@@ -166,6 +225,10 @@ func (k *KVS) Get(args *structs.KeyRequest, reply *structs.IndexedDirEntries) er
 				return errNotFound
 			}
 
+			if ent, err = ent.Decompressed(); err != nil {
+				return err
+			}
+
 			reply.Index = ent.ModifyIndex
 			reply.Entries = structs.DirEntries{ent}
 			return nil
@@ -206,6 +269,9 @@ func (k *KVS) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) e
 			total := len(ent)
 			ent = FilterDirEnt(authz, ent)
 			reply.QueryMeta.ResultsFilteredByACLs = total != len(ent)
+			if ent, err = ent.Decompressed(); err != nil {
+				return err
+			}
 
 			if len(ent) == 0 {
 				// Must provide non-zero index to prevent blocking
@@ -2,6 +2,7 @@ package consul
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
 
@@ -76,6 +78,136 @@ func TestKVS_Apply(t *testing.T) {
 	}
 }
 
+func TestKVS_Apply_Compression(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.KVCompressionThreshold = 64
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	large := []byte(strings.Repeat("compress me ", 100))
+	for key, value := range map[string][]byte{"small": []byte("test"), "large": large} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Value: value,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	// Only the large value is stored compressed.
+	state := s1.fsm.State()
+	_, d, err := state.KVSGet(nil, "small", nil)
+	require.NoError(t, err)
+	require.False(t, d.Compressed)
+	require.Equal(t, []byte("test"), d.Value)
+
+	_, d, err = state.KVSGet(nil, "large", nil)
+	require.NoError(t, err)
+	require.True(t, d.Compressed)
+	require.Less(t, len(d.Value), len(large))
+
+	// Reads return the original value.
+	var dirent structs.IndexedDirEntries
+	getR := structs.KeyRequest{Datacenter: "dc1", Key: "large"}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Get", &getR, &dirent))
+	require.Len(t, dirent.Entries, 1)
+	require.False(t, dirent.Entries[0].Compressed)
+	require.Equal(t, large, dirent.Entries[0].Value)
+
+	dirent = structs.IndexedDirEntries{}
+	getR.Key = ""
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.List", &getR, &dirent))
+	require.Len(t, dirent.Entries, 2)
+	require.Equal(t, large, dirent.Entries[0].Value)
+	require.Equal(t, []byte("test"), dirent.Entries[1].Value)
+
+	// The entry in the state store is left compressed.
+	_, d, err = state.KVSGet(nil, "large", nil)
+	require.NoError(t, err)
+	require.True(t, d.Compressed)
+}
+
+func TestKVS_Apply_Compression_MixedVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	compressValues := func(c *Config) {
+		c.KVCompressionThreshold = 64
+	}
+
+	dir1, s1 := testServerWithConfig(t, compressValues)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, compressValues, func(c *Config) {
+		c.Bootstrap = false
+		c.OverrideInitialSerfTags = func(tags map[string]string) {
+			delete(tags, "ft_kc")
+		}
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	retry.Run(t, func(r *retry.R) {
+		if ok, _ := ServersInDCSupportFeature(s1, "dc1", "kc"); ok {
+			r.Fatal("expected the older server to be known")
+		}
+	})
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	large := []byte(strings.Repeat("compress me ", 100))
+	apply := func(t require.TestingT, key string) *structs.DirEntry {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Value: large,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+
+		_, d, err := s1.fsm.State().KVSGet(nil, key, nil)
+		require.NoError(t, err)
+		require.NotNil(t, d)
+		return d
+	}
+
+	// The older server can't decompress the values, so they are stored as is.
+	d := apply(t, "before")
+	require.False(t, d.Compressed)
+	require.Equal(t, large, d.Value)
+
+	// Once it is upgraded the values are compressed.
+	updateSerfTags(s2, "ft_kc", "1")
+	retry.Run(t, func(r *retry.R) {
+		d := apply(r, "after")
+		require.True(r, d.Compressed)
+		require.Less(r, len(d.Value), len(large))
+	})
+}
+
 func TestKVS_Apply_ACLDeny(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,117 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+var KVCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"kvs", "expired"},
+		Help: "Increments when the leader deletes a key whose TTL has expired.",
+	},
+}
+
+const (
+	// kvsReapingRateLimit is the number of expired key reaping passes per
+	// second allowed.
+	kvsReapingRateLimit rate.Limit = 1.0
+
+	// kvsReapingBatchSize is the maximum number of expired keys deleted in a
+	// single reaping pass.
+	kvsReapingBatchSize = 256
+)
+
+func (s *Server) startKVSReaping(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, kvsReapingRoutineName, s.reapExpiredKVs)
+}
+
+func (s *Server) stopKVSReaping() {
+	s.leaderRoutineManager.Stop(kvsReapingRoutineName)
+}
+
+// reapExpiredKVs is a long running routine that deletes the keys whose TTL
+// has expired while the server is the leader.
+func (s *Server) reapExpiredKVs(ctx context.Context) error {
+	limiter := rate.NewLimiter(kvsReapingRateLimit, 1)
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		if _, err := s.reapExpiredKVsOnce(); err != nil {
+			s.logger.Error("error reaping expired keys", "error", err)
+		}
+	}
+}
+
+// reapExpiredKVsOnce deletes a batch of expired keys in a single transaction
+// and returns how many were deleted. Keys are deleted with a check-and-set on
+// their modify index, so a key written again since it was listed is kept.
+func (s *Server) reapExpiredKVsOnce() (int, error) {
+	entries, err := s.fsm.State().KVSListExpired(time.Now(), kvsReapingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for len(entries) > 0 {
+		ops := make(structs.TxnOps, 0, len(entries))
+		for _, entry := range entries {
+			ops = append(ops, &structs.TxnOp{
+				KV: &structs.TxnKVOp{
+					Verb: api.KVDeleteCAS,
+					DirEnt: structs.DirEntry{
+						Key:            entry.Key,
+						RaftIndex:      structs.RaftIndex{ModifyIndex: entry.ModifyIndex},
+						EnterpriseMeta: entry.EnterpriseMeta,
+					},
+				},
+			})
+		}
+
+		req := structs.TxnRequest{
+			Datacenter: s.config.Datacenter,
+			Ops:        ops,
+		}
+		resp, err := s.leaderRaftApply("Txn.Apply", structs.TxnRequestType, &req)
+		if err != nil {
+			return 0, err
+		}
+		txnResp, ok := resp.(structs.TxnResponse)
+		if !ok {
+			return 0, fmt.Errorf("unexpected return type %T", resp)
+		}
+		if len(txnResp.Errors) == 0 {
+			break
+		}
+
+		// The transaction is rolled back when any of the keys was written
+		// since it was listed, so the other keys are deleted again without
+		// them.
+		stale := make(map[int]struct{}, len(txnResp.Errors))
+		for _, txnErr := range txnResp.Errors {
+			stale[txnErr.OpIndex] = struct{}{}
+		}
+		kept := entries[:0]
+		for i, entry := range entries {
+			if _, ok := stale[i]; !ok {
+				kept = append(kept, entry)
+			}
+		}
+		entries = kept
+	}
+
+	for _, entry := range entries {
+		metrics.IncrCounter([]string{"kvs", "expired"}, 1)
+		s.logger.Debug("deleted expired key", "key", entry.Key, "expiration_time", entry.ExpirationTime)
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,145 @@
+package consul
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestKVS_ReapExpired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	// Stop the leader routine so that the test controls the reaping.
+	s1.stopKVSReaping()
+
+	set := func(key string, ttl time.Duration) {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:           key,
+				Value:         []byte("test"),
+				ExpirationTTL: ttl,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	set("expiring", time.Second)
+	set("rewritten", time.Second)
+	set("permanent", 0)
+
+	state := s1.fsm.State()
+	_, d, err := state.KVSGet(nil, "expiring", nil)
+	require.NoError(t, err)
+	require.NotNil(t, d.ExpirationTime)
+	require.Zero(t, d.ExpirationTTL)
+
+	// Writing the key again without a TTL removes its expiration.
+	set("rewritten", 0)
+
+	// The index used to find expired keys has a granularity of 1 second.
+	time.Sleep(2 * time.Second)
+
+	deleted, err := s1.reapExpiredKVsOnce()
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	for key, exists := range map[string]bool{"expiring": false, "rewritten": true, "permanent": true} {
+		_, d, err := state.KVSGet(nil, key, nil)
+		require.NoError(t, err)
+		require.Equal(t, exists, d != nil, key)
+	}
+}
+
+func TestKVS_ReapExpired_Batch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	// Stop the leader routine so that the test controls the reaping.
+	s1.stopKVSReaping()
+
+	keys := kvsReapingBatchSize + 10
+	for i := 0; i < keys; i++ {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:           fmt.Sprintf("expiring/%03d", i),
+				Value:         []byte("test"),
+				ExpirationTTL: time.Second,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	// The index used to find expired keys has a granularity of 1 second.
+	time.Sleep(2 * time.Second)
+
+	// Each batch of keys is deleted by a single transaction, which leaves
+	// tombstones with the same index.
+	batchIndexes := func() map[uint64]int {
+		snap := s1.fsm.State().Snapshot()
+		defer snap.Close()
+		stones, err := snap.Tombstones()
+		require.NoError(t, err)
+
+		indexes := make(map[uint64]int)
+		for stone := stones.Next(); stone != nil; stone = stones.Next() {
+			indexes[stone.(*state.Tombstone).Index]++
+		}
+		return indexes
+	}
+
+	deleted, err := s1.reapExpiredKVsOnce()
+	require.NoError(t, err)
+	require.Equal(t, kvsReapingBatchSize, deleted)
+	first := batchIndexes()
+	require.Len(t, first, 1)
+
+	deleted, err = s1.reapExpiredKVsOnce()
+	require.NoError(t, err)
+	require.Equal(t, 10, deleted)
+	indexes := batchIndexes()
+	require.Len(t, indexes, 2)
+	for index, count := range first {
+		require.Equal(t, kvsReapingBatchSize, count)
+		require.Equal(t, kvsReapingBatchSize, indexes[index])
+	}
+
+	_, entries, err := s1.fsm.State().KVSList(nil, "expiring/", nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
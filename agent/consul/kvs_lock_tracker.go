@@ -0,0 +1,279 @@
+package consul
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+var LockCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"kvs", "lock", "contended"},
+		Help: "Increments when an attempt to acquire a lock fails because it is held by another session.",
+	},
+}
+
+var LockGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"kvs", "lock", "waiters"},
+		Help: "Measures the number of sessions waiting to acquire a lock or a semaphore.",
+	},
+}
+
+var LockSummaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"kvs", "lock", "wait_time"},
+		Help: "Measures the time sessions waited to acquire a lock or a semaphore.",
+	},
+}
+
+// lockStatsRetention is how long the stats of a lock or a semaphore without
+// holders or waiters are kept.
+const lockStatsRetention = time.Hour
+
+type lockTrackerKey struct {
+	key       string
+	semaphore bool
+	acl.EnterpriseMeta
+}
+
+// lockStats are the stats of a lock or a semaphore.
+type lockStats struct {
+	holders      map[string]time.Time
+	waiters      map[string]time.Time
+	acquisitions uint64
+	contentions  uint64
+	totalWait    time.Duration
+	maxWait      time.Duration
+	lastActive   time.Time
+}
+
+// lockTracker follows the lock operations applied through the KVS endpoint to
+// report the contention of the locks and semaphores built on the KV store,
+// following the conventions of the api package. Lock operations are forwarded
+// to the leader, so only the leader's tracker is complete and it starts empty
+// when a new leader is elected.
+type lockTracker struct {
+	lock      sync.Mutex
+	locks     map[lockTrackerKey]*lockStats
+	lastPrune time.Time
+}
+
+func newLockTracker() *lockTracker {
+	return &lockTracker{locks: make(map[lockTrackerKey]*lockStats)}
+}
+
+// observe updates the tracker after op was applied to entry. ok is the
+// result of the operation.
+func (t *lockTracker) observe(op api.KVOp, entry *structs.DirEntry, ok bool) {
+	switch {
+	case op == api.KVLock && isSemaphoreContender(entry):
+		// Contenders register by locking their own entry, and start waiting
+		// for the semaphore.
+		if ok {
+			t.update(semaphoreKey(entry), func(stats *lockStats, now time.Time) {
+				if _, ok := stats.waiters[entry.Session]; !ok {
+					stats.waiters[entry.Session] = now
+				}
+			})
+		}
+
+	case op == api.KVLock:
+		t.update(lockKey(entry), func(stats *lockStats, now time.Time) {
+			if !ok {
+				stats.contentions++
+				metrics.IncrCounter([]string{"kvs", "lock", "contended"}, 1)
+				if _, ok := stats.waiters[entry.Session]; !ok {
+					stats.waiters[entry.Session] = now
+				}
+				return
+			}
+			if _, ok := stats.holders[entry.Session]; ok {
+				return
+			}
+			stats.acquired(entry.Session, now)
+			stats.holders = map[string]time.Time{entry.Session: now}
+		})
+
+	case op == api.KVUnlock && ok && isSemaphoreContender(entry):
+		t.update(semaphoreKey(entry), func(stats *lockStats, _ time.Time) {
+			delete(stats.waiters, entry.Session)
+			delete(stats.holders, entry.Session)
+		})
+
+	case op == api.KVUnlock && ok:
+		t.update(lockKey(entry), func(stats *lockStats, _ time.Time) {
+			delete(stats.holders, entry.Session)
+		})
+
+	case (op == api.KVDelete || op == api.KVDeleteCAS) && ok:
+		// Contenders delete their entry when they stop waiting for a
+		// semaphore.
+		t.forget(semaphoreKey(entry), path.Base(entry.Key))
+
+	case (op == api.KVSet || op == api.KVCAS) && ok && isSemaphoreLock(entry):
+		var holders map[string]bool
+		if e, err := entry.Decompressed(); err == nil {
+			if sem, err := decodeSemaphoreLock(e.Value); err == nil {
+				holders = sem.Holders
+			}
+		}
+		t.update(semaphoreKey(entry), func(stats *lockStats, now time.Time) {
+			for session := range holders {
+				if _, ok := stats.holders[session]; !ok {
+					stats.acquired(session, now)
+					stats.holders[session] = now
+				}
+			}
+			for session := range stats.holders {
+				if !holders[session] {
+					delete(stats.holders, session)
+				}
+			}
+		})
+	}
+}
+
+// acquired records that session acquired the lock at now.
+func (s *lockStats) acquired(session string, now time.Time) {
+	s.acquisitions++
+	if since, ok := s.waiters[session]; ok {
+		wait := now.Sub(since)
+		s.totalWait += wait
+		if wait > s.maxWait {
+			s.maxWait = wait
+		}
+		metrics.AddSample([]string{"kvs", "lock", "wait_time"}, float32(wait.Milliseconds()))
+		delete(s.waiters, session)
+	}
+}
+
+func (t *lockTracker) update(key lockTrackerKey, fn func(stats *lockStats, now time.Time)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	stats, ok := t.locks[key]
+	if !ok {
+		stats = &lockStats{
+			holders: make(map[string]time.Time),
+			waiters: make(map[string]time.Time),
+		}
+		t.locks[key] = stats
+	}
+	fn(stats, now)
+	stats.lastActive = now
+
+	t.pruneLocked(now)
+	t.setWaitersGaugeLocked()
+}
+
+// forget removes a session that stopped waiting, or that no longer exists,
+// from the waiters of a lock or a semaphore.
+func (t *lockTracker) forget(key lockTrackerKey, session string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if stats, ok := t.locks[key]; ok {
+		delete(stats.waiters, session)
+		t.setWaitersGaugeLocked()
+	}
+}
+
+// stats returns a copy of the stats of a lock or a semaphore.
+func (t *lockTracker) stats(key lockTrackerKey) (lockStats, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats, ok := t.locks[key]
+	if !ok {
+		return lockStats{}, false
+	}
+	out := *stats
+	out.holders = make(map[string]time.Time, len(stats.holders))
+	for k, v := range stats.holders {
+		out.holders[k] = v
+	}
+	out.waiters = make(map[string]time.Time, len(stats.waiters))
+	for k, v := range stats.waiters {
+		out.waiters[k] = v
+	}
+	return out, true
+}
+
+// keys returns the keys of the locks and semaphores under prefix that have
+// waiters.
+func (t *lockTracker) keys(prefix string, entMeta *acl.EnterpriseMeta) []lockTrackerKey {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var keys []lockTrackerKey
+	for key, stats := range t.locks {
+		if len(stats.waiters) != 0 && strings.HasPrefix(key.key, prefix) && key.EnterpriseMeta.IsSame(entMeta) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (t *lockTracker) pruneLocked(now time.Time) {
+	if now.Sub(t.lastPrune) < time.Minute {
+		return
+	}
+	t.lastPrune = now
+	for key, stats := range t.locks {
+		if len(stats.holders) == 0 && len(stats.waiters) == 0 && now.Sub(stats.lastActive) > lockStatsRetention {
+			delete(t.locks, key)
+		}
+	}
+}
+
+func (t *lockTracker) setWaitersGaugeLocked() {
+	var waiters int
+	for _, stats := range t.locks {
+		waiters += len(stats.waiters)
+	}
+	metrics.SetGauge([]string{"kvs", "lock", "waiters"}, float32(waiters))
+}
+
+func lockKey(entry *structs.DirEntry) lockTrackerKey {
+	return lockTrackerKey{key: entry.Key, EnterpriseMeta: entry.EnterpriseMeta}
+}
+
+func semaphoreKey(entry *structs.DirEntry) lockTrackerKey {
+	return lockTrackerKey{key: path.Dir(entry.Key), semaphore: true, EnterpriseMeta: entry.EnterpriseMeta}
+}
+
+// isSemaphoreContender returns true if entry is the entry of a contender of
+// a semaphore created by the api package.
+func isSemaphoreContender(entry *structs.DirEntry) bool {
+	return entry.Flags == api.SemaphoreFlagValue && entry.Session != "" && path.Base(entry.Key) == entry.Session
+}
+
+// isSemaphoreLock returns true if entry is the entry used by the api package
+// to coordinate the contenders of a semaphore.
+func isSemaphoreLock(entry *structs.DirEntry) bool {
+	return entry.Flags == api.SemaphoreFlagValue && path.Base(entry.Key) == api.DefaultSemaphoreKey
+}
+
+// semaphoreLock mirrors the value written by the api package under the
+// semaphore key.
+type semaphoreLock struct {
+	Limit   int
+	Holders map[string]bool
+}
+
+func decodeSemaphoreLock(value []byte) (semaphoreLock, error) {
+	var sem semaphoreLock
+	err := json.Unmarshal(value, &sem)
+	return sem, err
+}
@@ -0,0 +1,105 @@
+package consul
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestLockTracker_Lock(t *testing.T) {
+	tracker := newLockTracker()
+	entry := func(session string) *structs.DirEntry {
+		return &structs.DirEntry{Key: "service/leader", Session: session}
+	}
+	key := lockKey(entry(""))
+
+	tracker.observe(api.KVLock, entry("a"), true)
+	tracker.observe(api.KVLock, entry("b"), false)
+	tracker.observe(api.KVLock, entry("c"), false)
+	tracker.observe(api.KVLock, entry("b"), false)
+
+	stats, ok := tracker.stats(key)
+	require.True(t, ok)
+	require.Contains(t, stats.holders, "a")
+	require.Len(t, stats.waiters, 2)
+	require.Equal(t, uint64(1), stats.acquisitions)
+	require.Equal(t, uint64(3), stats.contentions)
+	require.Equal(t, []lockTrackerKey{key}, tracker.keys("service/", nil))
+	require.Empty(t, tracker.keys("other/", nil))
+
+	tracker.observe(api.KVUnlock, entry("a"), true)
+	tracker.observe(api.KVLock, entry("b"), true)
+
+	stats, _ = tracker.stats(key)
+	require.Len(t, stats.holders, 1)
+	require.Contains(t, stats.holders, "b")
+	require.Equal(t, []string{"c"}, keysOf(stats.waiters))
+	require.Equal(t, uint64(2), stats.acquisitions)
+	require.NotZero(t, stats.maxWait)
+	require.Equal(t, stats.maxWait, stats.totalWait)
+
+	tracker.forget(key, "c")
+	stats, _ = tracker.stats(key)
+	require.Empty(t, stats.waiters)
+	require.Empty(t, tracker.keys("", nil))
+}
+
+func TestLockTracker_Semaphore(t *testing.T) {
+	tracker := newLockTracker()
+	contender := func(session string) *structs.DirEntry {
+		return &structs.DirEntry{Key: "service/workers/" + session, Session: session, Flags: api.SemaphoreFlagValue}
+	}
+	semLock := func(holders ...string) *structs.DirEntry {
+		sem := semaphoreLock{Limit: 2, Holders: make(map[string]bool)}
+		for _, h := range holders {
+			sem.Holders[h] = true
+		}
+		value, err := json.Marshal(sem)
+		require.NoError(t, err)
+		e := &structs.DirEntry{Key: "service/workers/.lock", Value: value, Flags: api.SemaphoreFlagValue}
+		require.NoError(t, e.CompressValue(1))
+		return e
+	}
+	key := semaphoreKey(contender("a"))
+	require.Equal(t, "service/workers", key.key)
+
+	for _, session := range []string{"a", "b", "c"} {
+		tracker.observe(api.KVLock, contender(session), true)
+	}
+	tracker.observe(api.KVCAS, semLock("a", "b"), true)
+
+	stats, ok := tracker.stats(key)
+	require.True(t, ok)
+	require.Len(t, stats.holders, 2)
+	require.Equal(t, []string{"c"}, keysOf(stats.waiters))
+	require.Equal(t, uint64(2), stats.acquisitions)
+
+	tracker.observe(api.KVCAS, semLock("b"), true)
+	tracker.observe(api.KVDelete, &structs.DirEntry{Key: "service/workers/a"}, true)
+	tracker.observe(api.KVCAS, semLock("b", "c"), true)
+
+	stats, _ = tracker.stats(key)
+	require.Len(t, stats.holders, 2)
+	require.Contains(t, stats.holders, "c")
+	require.Empty(t, stats.waiters)
+	require.Equal(t, uint64(3), stats.acquisitions)
+
+	// Deleting a contender that gave up stops it from waiting.
+	tracker.observe(api.KVLock, contender("d"), true)
+	tracker.observe(api.KVDelete, &structs.DirEntry{Key: "service/workers/d"}, true)
+	stats, _ = tracker.stats(key)
+	require.Empty(t, stats.waiters)
+}
+
+func keysOf(m map[string]time.Time) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
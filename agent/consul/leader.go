@@ -43,6 +43,10 @@ var LeaderSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"leader", "reapTombstones"},
 		Help: "Measures the time spent clearing tombstones.",
 	},
+	{
+		Name: []string{"leader", "reapServiceTombstones"},
+		Help: "Measures the time spent clearing service tombstones.",
+	},
 }
 
 const (
@@ -226,6 +230,8 @@ WAIT:
 			s.reconcileMember(member)
 		case index := <-s.tombstoneGC.ExpireCh():
 			go s.reapTombstones(index)
+		case index := <-s.serviceTombstoneGC.ExpireCh():
+			go s.reapServiceTombstones(index)
 		case errCh := <-s.reassertLeaderCh:
 			// we can get into this state when the initial
 			// establishLeadership has failed as well as the follow
@@ -288,6 +294,8 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 	s.tombstoneGC.SetEnabled(true)
 	lastIndex := s.raft.LastIndex()
 	s.tombstoneGC.Hint(lastIndex)
+	s.serviceTombstoneGC.SetEnabled(true)
+	s.serviceTombstoneGC.Hint(lastIndex)
 
 	// Setup the session timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
@@ -321,6 +329,20 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 
 	s.startDeferredDeletion(ctx)
 
+	s.startKVSReaping(ctx)
+
+	if s.config.KeyringRotation.Enabled {
+		s.startKeyringRotation(ctx)
+	}
+
+	if s.config.ExternalHealthChecks.Enabled {
+		s.startExternalHealthChecks(ctx)
+	}
+
+	if len(s.config.ChangeSinks) > 0 {
+		s.startChangeSinks(ctx)
+	}
+
 	if err := s.startConnectLeader(ctx); err != nil {
 		return err
 	}
@@ -350,6 +372,7 @@ func (s *Server) revokeLeadership() {
 
 	// Disable the tombstone GC, since it is only useful as a leader
 	s.tombstoneGC.SetEnabled(false)
+	s.serviceTombstoneGC.SetEnabled(false)
 
 	// Clear the session timers on either shutdown or step down, since we
 	// are no longer responsible for session expirations.
@@ -371,6 +394,16 @@ func (s *Server) revokeLeadership() {
 
 	s.stopACLTokenReaping()
 
+	s.stopACLTokenUsageFlush()
+
+	s.stopKVSReaping()
+
+	s.stopKeyringRotation()
+
+	s.stopExternalHealthChecks()
+
+	s.stopChangeSinks()
+
 	s.resetConsistentReadReady()
 
 	s.autopilot.DisableReconciliation()
@@ -527,6 +560,7 @@ func (s *Server) initializeACLs(ctx context.Context) error {
 	}
 
 	s.startACLTokenReaping(ctx)
+	s.startACLTokenUsageFlush(ctx)
 
 	return nil
 }
@@ -1203,6 +1237,7 @@ func (s *Server) handleDeregisterMember(reason string, member serf.Member, nodeE
 	req := structs.DeregisterRequest{
 		Datacenter:     s.config.Datacenter,
 		Node:           member.Name,
+		Reason:         "member-" + reason,
 		EnterpriseMeta: *nodeEntMeta,
 	}
 	_, err = s.raftApply(structs.DeregisterRequestType, &req)
@@ -1275,6 +1310,26 @@ func (s *Server) reapTombstones(index uint64) {
 	}
 }
 
+// reapServiceTombstones is invoked by the current leader to clear the
+// tombstones of service instances that were removed before the given index,
+// once they have been retained for the service tombstone TTL.
+func (s *Server) reapServiceTombstones(index uint64) {
+	defer metrics.MeasureSince([]string{"leader", "reapServiceTombstones"}, time.Now())
+	req := structs.TombstoneRequest{
+		Datacenter: s.config.Datacenter,
+		Op:         structs.TombstoneReap,
+		ReapIndex:  index,
+	}
+	// Older servers ignore the reap, as they don't keep service tombstones.
+	_, err := s.raftApply(structs.ServiceTombstoneRequestType|structs.IgnoreUnknownTypeFlag, &req)
+	if err != nil {
+		s.logger.Error("failed to reap service tombstones up to index",
+			"index", index,
+			"error", err,
+		)
+	}
+}
+
 func (s *Server) setDatacenterSupportsFederationStates() {
 	atomic.StoreInt32(&s.dcSupportsFederationStates, 1)
 }
@@ -0,0 +1,67 @@
+package consul
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/consul/agent/consul/changesink"
+	"github.com/hashicorp/consul/logging"
+)
+
+func (s *Server) startChangeSinks(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, changeSinksRoutineName, s.runChangeSinks)
+}
+
+func (s *Server) stopChangeSinks() {
+	s.leaderRoutineManager.Stop(changeSinksRoutineName)
+}
+
+// runChangeSinks is a long running routine that pushes the changes of the
+// catalog and of the config entries to the configured sinks while the server
+// is the leader. The checkpoints of the sinks are stored in the system
+// metadata, so that a new leader resumes from where the previous one stopped.
+func (s *Server) runChangeSinks(ctx context.Context) error {
+	logger := s.loggers.Named(logging.ChangeSink)
+
+	var wg sync.WaitGroup
+	for _, cfg := range s.config.ChangeSinks {
+		sink, err := changesink.New(cfg)
+		if err != nil {
+			logger.Error("invalid change sink", "sink", cfg.Name, "error", err)
+			continue
+		}
+		runner := &changesink.Runner{
+			Config:      cfg,
+			Sink:        sink,
+			Publisher:   s.publisher,
+			Checkpoints: changeSinkCheckpoints{srv: s},
+			Logger:      logger.With("sink", cfg.Name),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.Run(ctx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// changeSinkCheckpoints stores the checkpoints of the change sinks in the
+// system metadata.
+type changeSinkCheckpoints struct {
+	srv *Server
+}
+
+func (c changeSinkCheckpoints) GetCheckpoint(key string) (uint64, error) {
+	val, err := c.srv.getSystemMetadata(key)
+	if err != nil || val == "" {
+		return 0, err
+	}
+	return strconv.ParseUint(val, 10, 64)
+}
+
+func (c changeSinkCheckpoints) SetCheckpoint(key string, index uint64) error {
+	return c.srv.setSystemMetadataKey(key, strconv.FormatUint(index, 10))
+}
@@ -0,0 +1,91 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/changesink"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeader_ChangeSinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	var (
+		lock   sync.Mutex
+		events []changesink.Event
+	)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []changesink.Event
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		events = append(events, batch...)
+		lock.Unlock()
+	}))
+	defer hook.Close()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ChangeSinks = []changesink.Config{{
+			Name:   "hook",
+			Type:   changesink.TypeWebhook,
+			URL:    hook.URL,
+			Topics: []string{changesink.TopicCatalog},
+		}}
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	req := structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "node1",
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			ID:      "web",
+			Service: "web",
+			Port:    8080,
+		},
+	}
+	var out struct{}
+	require.NoError(t, s1.RPC(context.Background(), "Catalog.Register", &req, &out))
+
+	var index uint64
+	retry.Run(t, func(r *retry.R) {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, e := range events {
+			if e.Key == "default/default/node1/web" && e.Op == changesink.OpUpsert {
+				require.Equal(r, "web", e.Service.Service.Service)
+				index = e.Index
+				return
+			}
+		}
+		r.Fatal("the registration was not delivered")
+	})
+
+	// The checkpoint is saved after the delivery.
+	key := changesink.CheckpointKey("hook", state.EventTopicServiceHealth)
+	retry.RunWith(&retry.Timer{Timeout: 15 * time.Second, Wait: 100 * time.Millisecond}, t, func(r *retry.R) {
+		checkpoint, err := changeSinkCheckpoints{srv: s1}.GetCheckpoint(key)
+		require.NoError(r, err)
+		require.GreaterOrEqual(r, checkpoint, index)
+	})
+}
@@ -0,0 +1,276 @@
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/agent/checks"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/logging"
+)
+
+// externalHealthReconcileWait is the minimum time between two updates of the
+// set of external health checks run by the leader. It batches the catalog
+// changes, including the ones made by the checks themselves.
+var externalHealthReconcileWait = time.Second
+
+// externalCheckDefaultInterval is the interval of the external health checks
+// that were registered without one.
+const externalCheckDefaultInterval = 30 * time.Second
+
+func (s *Server) startExternalHealthChecks(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, externalHealthChecksRoutineName, s.runExternalHealthChecks)
+}
+
+func (s *Server) stopExternalHealthChecks() {
+	s.leaderRoutineManager.Stop(externalHealthChecksRoutineName)
+}
+
+// runExternalHealthChecks is a long running routine that runs the health
+// checks of the external nodes while the server is the leader. The external
+// nodes are the ones registered in the catalog with the "external-node" meta
+// set to "true", whose services aren't monitored by a Consul agent. Their
+// HTTP and TCP checks are run according to their definitions in the catalog,
+// and the ones with the "external-probe" meta set to "true" are also pinged.
+func (s *Server) runExternalHealthChecks(ctx context.Context) error {
+	m := &externalHealthMonitor{
+		srv:    s,
+		logger: s.loggers.Named(logging.ExternalHealth),
+		checks: make(map[externalCheckKey]*externalCheck),
+	}
+	defer m.stopAll()
+
+	for {
+		ws := memdb.NewWatchSet()
+		if err := m.reconcile(ws); err != nil {
+			m.logger.Error("failed to update the external health checks", "error", err)
+			ws = nil
+		}
+
+		if ws != nil {
+			if err := ws.WatchCtx(ctx); err != nil {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(externalHealthReconcileWait):
+		}
+	}
+}
+
+type externalCheckKey struct {
+	Node    string
+	CheckID structs.CheckID
+}
+
+// externalCheck is a check run by the leader for an external node.
+type externalCheck struct {
+	check interface{ Stop() }
+
+	// The check is restarted when any of these change.
+	ServiceID  structs.ServiceID
+	Address    string
+	Definition structs.HealthCheckDefinition
+}
+
+type externalHealthMonitor struct {
+	srv    *Server
+	logger hclog.Logger
+	checks map[externalCheckKey]*externalCheck
+}
+
+// reconcile starts and stops the checks so that they match the external nodes
+// and their checks in the catalog.
+func (m *externalHealthMonitor) reconcile(ws memdb.WatchSet) error {
+	state := m.srv.fsm.State()
+	ws.Add(state.AbandonCh())
+
+	filter := map[string]string{structs.MetaExternalNode: "true"}
+	_, nodes, err := state.NodesByMeta(ws, filter, structs.WildcardEnterpriseMetaInPartition(structs.WildcardSpecifier), structs.DefaultPeerKeyword)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[externalCheckKey]*externalCheck)
+	for _, node := range nodes {
+		entMeta := structs.WildcardEnterpriseMetaInPartition(node.PartitionOrDefault())
+		_, nodeChecks, err := state.NodeChecks(ws, node.Node, entMeta, structs.DefaultPeerKeyword)
+		if err != nil {
+			return err
+		}
+		for _, check := range nodeChecks {
+			if check.Definition.HTTP == "" && check.Definition.TCP == "" {
+				continue
+			}
+			key := externalCheckKey{Node: node.Node, CheckID: check.CompoundCheckID()}
+			wanted[key] = &externalCheck{
+				ServiceID:  check.CompoundServiceID(),
+				Definition: check.Definition,
+			}
+		}
+
+		if node.Meta[structs.MetaExternalProbe] == "true" && node.Address != "" {
+			checkID := structs.NewCheckID(structs.ExternalNodeCheckID, node.GetEnterpriseMeta())
+			wanted[externalCheckKey{Node: node.Node, CheckID: checkID}] = &externalCheck{
+				Address: node.Address,
+			}
+		}
+	}
+
+	for key, running := range m.checks {
+		if want, ok := wanted[key]; ok && running.sameAs(want) {
+			delete(wanted, key)
+			continue
+		}
+		running.check.Stop()
+		delete(m.checks, key)
+	}
+	for key, check := range wanted {
+		m.start(key, check)
+		m.checks[key] = check
+	}
+	return nil
+}
+
+func (c *externalCheck) sameAs(other *externalCheck) bool {
+	return c.ServiceID == other.ServiceID &&
+		c.Address == other.Address &&
+		reflect.DeepEqual(c.Definition, other.Definition)
+}
+
+func (m *externalHealthMonitor) start(key externalCheckKey, check *externalCheck) {
+	logger := m.logger.With("node", key.Node, "check", key.CheckID.ID)
+	notifier := &externalCheckNotifier{srv: m.srv, node: key.Node, logger: logger}
+	statusHandler := checks.NewStatusHandler(notifier, logger, 0, 0, 0)
+
+	def := check.Definition
+	interval := def.Interval
+	if interval == 0 {
+		interval = externalCheckDefaultInterval
+	} else if interval < checks.MinInterval {
+		interval = checks.MinInterval
+	}
+
+	switch {
+	case check.Address != "":
+		probe := &checks.CheckPing{
+			CheckID:       key.CheckID,
+			Address:       check.Address,
+			Interval:      m.srv.config.ExternalHealthChecks.NodeProbeInterval,
+			Timeout:       m.srv.config.ExternalHealthChecks.NodeProbeInterval,
+			Logger:        logger,
+			StatusHandler: statusHandler,
+		}
+		probe.Start()
+		check.check = probe
+
+	case def.HTTP != "":
+		outputMaxSize := int(def.OutputMaxSize)
+		if outputMaxSize == 0 {
+			outputMaxSize = checks.DefaultBufSize
+		}
+		http := &checks.CheckHTTP{
+			CheckID:          key.CheckID,
+			ServiceID:        check.ServiceID,
+			HTTP:             def.HTTP,
+			Header:           def.Header,
+			Method:           def.Method,
+			Body:             def.Body,
+			Interval:         interval,
+			Timeout:          def.Timeout,
+			Logger:           logger,
+			OutputMaxSize:    outputMaxSize,
+			StatusHandler:    statusHandler,
+			DisableRedirects: def.DisableRedirects,
+			TLSClientConfig: &tls.Config{
+				ServerName:         def.TLSServerName,
+				InsecureSkipVerify: def.TLSSkipVerify,
+			},
+		}
+		http.Start()
+		check.check = http
+
+	default:
+		tcp := &checks.CheckTCP{
+			CheckID:       key.CheckID,
+			ServiceID:     check.ServiceID,
+			TCP:           def.TCP,
+			Interval:      interval,
+			Timeout:       def.Timeout,
+			Logger:        logger,
+			StatusHandler: statusHandler,
+		}
+		tcp.Start()
+		check.check = tcp
+	}
+}
+
+func (m *externalHealthMonitor) stopAll() {
+	for key, check := range m.checks {
+		check.check.Stop()
+		delete(m.checks, key)
+	}
+}
+
+// externalCheckNotifier writes the status of the checks of an external node
+// to the catalog.
+type externalCheckNotifier struct {
+	srv    *Server
+	node   string
+	logger hclog.Logger
+}
+
+func (n *externalCheckNotifier) UpdateCheck(checkID structs.CheckID, status, output string) {
+	if err := n.srv.updateExternalCheck(n.node, checkID, status, output); err != nil {
+		n.logger.Error("failed to update the status of the check", "error", err)
+	}
+}
+
+func (n *externalCheckNotifier) ServiceExists(structs.ServiceID) bool {
+	return true
+}
+
+// updateExternalCheck sets the status of a check of an external node, unless
+// it didn't change. The check of the node's ping is created if needed.
+func (s *Server) updateExternalCheck(node string, checkID structs.CheckID, status, output string) error {
+	_, check, err := s.fsm.State().NodeCheck(node, checkID.ID, &checkID.EnterpriseMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return err
+	}
+	switch {
+	case check != nil:
+		if check.Status == status && check.Output == output {
+			return nil
+		}
+		check = check.Clone()
+	case checkID.ID == structs.ExternalNodeCheckID:
+		check = &structs.HealthCheck{
+			Node:           node,
+			CheckID:        checkID.ID,
+			Name:           structs.ExternalNodeCheckName,
+			EnterpriseMeta: checkID.EnterpriseMeta,
+		}
+	default:
+		// The check was deregistered in the meantime.
+		return nil
+	}
+	check.Status = status
+	check.Output = output
+
+	req := structs.RegisterRequest{
+		Datacenter:     s.config.Datacenter,
+		Node:           node,
+		SkipNodeUpdate: true,
+		Check:          check,
+		EnterpriseMeta: *structs.NodeEnterpriseMetaInPartition(checkID.PartitionOrDefault()),
+	}
+	_, err = s.raftApply(structs.RegisterRequestType, &req)
+	return err
+}
@@ -0,0 +1,94 @@
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeader_ExternalHealthChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	var healthy atomic.Bool
+	healthy.Store(true)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer target.Close()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ExternalHealthChecks = ExternalHealthChecksConfig{
+			Enabled:           true,
+			NodeProbeInterval: 10 * time.Second,
+		}
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	register := func(def structs.HealthCheckDefinition) {
+		req := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "external",
+			Address:    "127.0.0.1",
+			NodeMeta:   map[string]string{structs.MetaExternalNode: "true"},
+			Service: &structs.NodeService{
+				ID:      "web",
+				Service: "web",
+				Port:    8080,
+			},
+			Check: &structs.HealthCheck{
+				Node:       "external",
+				CheckID:    "web-http",
+				Name:       "web",
+				ServiceID:  "web",
+				Status:     api.HealthCritical,
+				Definition: def,
+			},
+		}
+		var out struct{}
+		require.NoError(t, s1.RPC(context.Background(), "Catalog.Register", &req, &out))
+	}
+	waitForStatus := func(status string) {
+		retry.Run(t, func(r *retry.R) {
+			_, check, err := s1.fsm.State().NodeCheck("external", "web-http", nil, "")
+			require.NoError(r, err)
+			require.NotNil(r, check)
+			require.Equal(r, status, check.Status)
+		})
+	}
+
+	register(structs.HealthCheckDefinition{
+		HTTP:     target.URL,
+		Interval: time.Second,
+	})
+	waitForStatus(api.HealthPassing)
+
+	healthy.Store(false)
+	waitForStatus(api.HealthCritical)
+
+	// Changing the definition restarts the check.
+	healthy.Store(true)
+	register(structs.HealthCheckDefinition{
+		TCP:      target.Listener.Addr().String(),
+		Interval: time.Second,
+	})
+	waitForStatus(api.HealthPassing)
+}
@@ -0,0 +1,443 @@
+package consul
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/serf/serf"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/logging"
+)
+
+var KeyringRotationCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"leader", "keyring", "rotation"},
+		Help: "Increments when the leader completes the rotation of the gossip encryption key.",
+	},
+}
+
+// keyringRotationCheckInterval is how often the leader checks whether the
+// rotation of the gossip encryption key must move forward.
+var keyringRotationCheckInterval = time.Minute
+
+// keyringRotationState is the progress of the rotation of the gossip
+// encryption key. It is stored in the system metadata so that a new leader
+// resumes the rotation started by the previous one. The keys are only
+// referred to by their fingerprint so that they aren't stored in raft; a new
+// leader finds them among the keys installed on the members.
+type keyringRotationState struct {
+	Phase          string
+	PhaseStartedAt time.Time
+	LastRotation   time.Time
+	LastError      string `json:",omitempty"`
+
+	// KeyFingerprint is the fingerprint of the key being rotated in.
+	KeyFingerprint string `json:",omitempty"`
+
+	// PreviousKeyFingerprints are the fingerprints of the primary keys of the
+	// pools when the rotation started, indexed by pool. Those keys are
+	// removed once the new key is promoted.
+	PreviousKeyFingerprints map[string]string `json:",omitempty"`
+}
+
+func (s *Server) startKeyringRotation(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, keyringRotationRoutineName, s.runKeyringRotation)
+}
+
+func (s *Server) stopKeyringRotation() {
+	s.leaderRoutineManager.Stop(keyringRotationRoutineName)
+}
+
+// runKeyringRotation is a long running routine that rotates the gossip
+// encryption key while the server is the leader.
+func (s *Server) runKeyringRotation(ctx context.Context) error {
+	logger := s.loggers.Named(logging.Keyring)
+	if !s.serfLAN.EncryptionEnabled() {
+		logger.Warn("gossip encryption is disabled, the key will not be rotated")
+		return nil
+	}
+
+	ticker := time.NewTicker(keyringRotationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.keyringRotationStep(time.Now()); err != nil {
+			logger.Error("failed to rotate the gossip encryption key", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// keyringRotationStep moves the rotation forward if its current phase is
+// over. A rotation goes through the following phases:
+//
+//   - idle: once the interval elapsed since the last rotation, a new key is
+//     generated and installed on all the members.
+//   - installed: the key is installed again on each check so that the
+//     members which missed it get it. Once the grace period elapsed and all
+//     the members of every pool have it, it is promoted to primary key.
+//   - promoted: once the grace period elapsed and all the members of every
+//     pool use the new key, the previous primary keys are removed.
+func (s *Server) keyringRotationStep(now time.Time) error {
+	state, err := s.getKeyringRotationState()
+	if err != nil {
+		return err
+	}
+	config := s.config.KeyringRotation
+
+	var changed bool
+	switch state.Phase {
+	case "", structs.KeyringRotationIdle:
+		changed, err = s.keyringRotationStart(state, config, now)
+	case structs.KeyringRotationInstalled:
+		changed, err = s.keyringRotationPromote(state, config, now)
+	case structs.KeyringRotationPromoted:
+		changed, err = s.keyringRotationRetire(state, config, now)
+	default:
+		err = fmt.Errorf("unknown keyring rotation phase %q", state.Phase)
+	}
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	if lastError != state.LastError {
+		state.LastError = lastError
+		changed = true
+	}
+	if changed {
+		if err := s.setKeyringRotationState(state); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Server) keyringRotationStart(state *keyringRotationState, config KeyringRotationConfig, now time.Time) (bool, error) {
+	if state.Phase == "" || state.LastRotation.IsZero() {
+		// Count the interval from when the rotation was enabled rather than
+		// rotating the key as soon as a leader is elected.
+		state.Phase = structs.KeyringRotationIdle
+		state.PhaseStartedAt = now
+		state.LastRotation = now
+		return true, nil
+	}
+	if now.Before(state.LastRotation.Add(config.Interval)) {
+		return false, nil
+	}
+
+	previous, err := s.keyringRotationPrimaryKeys()
+	if err != nil {
+		return false, err
+	}
+	key, err := generateGossipKey()
+	if err != nil {
+		return false, err
+	}
+
+	// The state is stored before installing the key so that a new leader
+	// finishes installing it if this one fails midway.
+	state.Phase = structs.KeyringRotationInstalled
+	state.PhaseStartedAt = now
+	state.KeyFingerprint = keyFingerprint(key)
+	state.PreviousKeyFingerprints = make(map[string]string, len(previous))
+	for pool, previousKey := range previous {
+		state.PreviousKeyFingerprints[pool] = keyFingerprint(previousKey)
+	}
+	if err := s.setKeyringRotationState(state); err != nil {
+		return false, err
+	}
+	s.loggers.Named(logging.Keyring).Info("installing a new gossip encryption key")
+
+	return false, s.keyringRotationApply(structs.KeyringInstall, func(string) string { return key })
+}
+
+func (s *Server) keyringRotationPromote(state *keyringRotationState, config KeyringRotationConfig, now time.Time) (bool, error) {
+	lists, err := s.keyringRotationListKeys()
+	if err != nil {
+		return false, err
+	}
+	key := keyringRotationFindKey(lists, state.KeyFingerprint)
+	if key == "" {
+		// The previous leader stored the state but failed before installing
+		// the key anywhere, so the rotation starts over with a new key.
+		state.Phase = structs.KeyringRotationIdle
+		state.PhaseStartedAt = now
+		state.KeyFingerprint = ""
+		state.PreviousKeyFingerprints = nil
+		return true, fmt.Errorf("the new key is not installed on any member, the rotation will start over")
+	}
+
+	if err := s.keyringRotationApply(structs.KeyringInstall, func(string) string { return key }); err != nil {
+		return false, err
+	}
+	if now.Before(state.PhaseStartedAt.Add(config.GracePeriod)) {
+		return false, nil
+	}
+
+	lists, err = s.keyringRotationListKeys()
+	if err != nil {
+		return false, err
+	}
+	if err := keyringRotationCheckMembers(lists, key, "installed", func(resp *serf.KeyResponse) map[string]int { return resp.Keys }); err != nil {
+		return false, err
+	}
+
+	s.loggers.Named(logging.Keyring).Info("promoting the new gossip encryption key")
+	state.Phase = structs.KeyringRotationPromoted
+	state.PhaseStartedAt = now
+	if err := s.setKeyringRotationState(state); err != nil {
+		return false, err
+	}
+	return false, s.keyringRotationApply(structs.KeyringUse, func(string) string { return key })
+}
+
+func (s *Server) keyringRotationRetire(state *keyringRotationState, config KeyringRotationConfig, now time.Time) (bool, error) {
+	lists, err := s.keyringRotationListKeys()
+	if err != nil {
+		return false, err
+	}
+	key := keyringRotationFindKey(lists, state.KeyFingerprint)
+	if key == "" {
+		return false, fmt.Errorf("the new key is not installed on any member")
+	}
+	if err := keyringRotationCheckMembers(lists, key, "installed", func(resp *serf.KeyResponse) map[string]int { return resp.Keys }); err != nil {
+		return false, err
+	}
+
+	if err := s.keyringRotationApply(structs.KeyringUse, func(string) string { return key }); err != nil {
+		return false, err
+	}
+	if now.Before(state.PhaseStartedAt.Add(config.GracePeriod)) {
+		return false, nil
+	}
+
+	lists, err = s.keyringRotationListKeys()
+	if err != nil {
+		return false, err
+	}
+	if err := keyringRotationCheckMembers(lists, key, "used", func(resp *serf.KeyResponse) map[string]int { return resp.PrimaryKeys }); err != nil {
+		return false, err
+	}
+
+	s.loggers.Named(logging.Keyring).Info("removing the previous gossip encryption keys")
+	err = s.keyringRotationApply(structs.KeyringRemove, func(pool string) string {
+		resp, ok := lists[pool]
+		if !ok {
+			return ""
+		}
+		for installed := range resp.Keys {
+			if installed != key && keyFingerprint(installed) == state.PreviousKeyFingerprints[pool] {
+				return installed
+			}
+		}
+		return ""
+	})
+	if err != nil {
+		return false, err
+	}
+
+	state.Phase = structs.KeyringRotationIdle
+	state.PhaseStartedAt = now
+	state.LastRotation = now
+	state.KeyFingerprint = ""
+	state.PreviousKeyFingerprints = nil
+	metrics.IncrCounter([]string{"leader", "keyring", "rotation"}, 1)
+	return true, nil
+}
+
+// keyringRotationPools calls fn with the key manager of each pool whose key is
+// rotated by this server: the LAN pools of the datacenter, and the WAN pool in
+// the primary datacenter.
+func (s *Server) keyringRotationPools(fn func(pool string, mgr *serf.KeyManager) error) error {
+	var merr error
+	_ = s.DoWithLANSerfs(func(name, poolKind string, pool *serf.Serf) error {
+		if err := fn("lan"+keyringRotationPoolSuffix(name), pool.KeyManager()); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+		return nil
+	}, nil)
+	if s.serfWAN != nil && s.config.InPrimaryDatacenter() {
+		if err := fn("wan", s.KeyManagerWAN()); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+	return merr
+}
+
+func keyringRotationPoolSuffix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return ":" + name
+}
+
+// keyringRotationApply applies the operation to all the pools with the key
+// returned by keyFn for each of them. The pools for which keyFn returns an
+// empty string are skipped.
+func (s *Server) keyringRotationApply(op structs.KeyringOp, keyFn func(pool string) string) error {
+	return s.keyringRotationPools(func(pool string, mgr *serf.KeyManager) error {
+		key := keyFn(pool)
+		if key == "" {
+			return nil
+		}
+
+		var err error
+		switch op {
+		case structs.KeyringInstall:
+			_, err = mgr.InstallKey(key)
+		case structs.KeyringUse:
+			_, err = mgr.UseKey(key)
+		case structs.KeyringRemove:
+			_, err = mgr.RemoveKey(key)
+		default:
+			err = fmt.Errorf("unsupported operation %q", op)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s the key in the %s pool: %w", op, pool, err)
+		}
+		return nil
+	})
+}
+
+// keyringRotationListKeys returns the keys installed on the members of each
+// pool, indexed by pool.
+func (s *Server) keyringRotationListKeys() (map[string]*serf.KeyResponse, error) {
+	lists := make(map[string]*serf.KeyResponse)
+	err := s.keyringRotationPools(func(pool string, mgr *serf.KeyManager) error {
+		resp, err := mgr.ListKeys()
+		if err != nil {
+			return fmt.Errorf("failed to list the keys of the %s pool: %w", pool, err)
+		}
+		lists[pool] = resp
+		return nil
+	})
+	return lists, err
+}
+
+// keyringRotationPrimaryKeys returns the primary key used by most of the
+// members of each pool.
+func (s *Server) keyringRotationPrimaryKeys() (map[string]string, error) {
+	lists, err := s.keyringRotationListKeys()
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]string)
+	for pool, resp := range lists {
+		var count int
+		for key, n := range resp.PrimaryKeys {
+			if n > count {
+				keys[pool], count = key, n
+			}
+		}
+	}
+	return keys, nil
+}
+
+// keyringRotationFindKey returns the key installed in any of the pools whose
+// fingerprint matches, or an empty string if there is none.
+func keyringRotationFindKey(lists map[string]*serf.KeyResponse, fingerprint string) string {
+	if fingerprint == "" {
+		return ""
+	}
+	for _, resp := range lists {
+		for key := range resp.Keys {
+			if keyFingerprint(key) == fingerprint {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// keyringRotationCheckMembers returns an error unless all the members of every
+// pool are counted for the key by counts, which returns either the installed
+// keys or the primary keys of the pool.
+func keyringRotationCheckMembers(lists map[string]*serf.KeyResponse, key, what string, counts func(*serf.KeyResponse) map[string]int) error {
+	var merr error
+	for pool, resp := range lists {
+		if n := counts(resp)[key]; n != resp.NumNodes {
+			merr = multierror.Append(merr, fmt.Errorf("the new key is %s by %d of the %d members of the %s pool", what, n, resp.NumNodes, pool))
+		}
+	}
+	return merr
+}
+
+func (s *Server) getKeyringRotationState() (*keyringRotationState, error) {
+	raw, err := s.getSystemMetadata(structs.SystemMetadataKeyringRotationKey)
+	if err != nil {
+		return nil, err
+	}
+	var state keyringRotationState
+	if raw == "" {
+		return &state, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, fmt.Errorf("failed to decode the keyring rotation state: %w", err)
+	}
+	return &state, nil
+}
+
+func (s *Server) setKeyringRotationState(state *keyringRotationState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.setSystemMetadataKey(structs.SystemMetadataKeyringRotationKey, string(raw))
+}
+
+// keyringRotationStatus reports the progress of the rotation for the
+// operators, without the keys.
+func (s *Server) keyringRotationStatus(reply *structs.KeyringRotationStatus) error {
+	state, err := s.getKeyringRotationState()
+	if err != nil {
+		return err
+	}
+
+	config := s.config.KeyringRotation
+	reply.Enabled = config.Enabled
+	reply.Interval = config.Interval
+	reply.GracePeriod = config.GracePeriod
+	reply.Phase = state.Phase
+	if reply.Phase == "" {
+		reply.Phase = structs.KeyringRotationIdle
+	}
+	reply.PhaseStartedAt = state.PhaseStartedAt
+	reply.LastRotation = state.LastRotation
+	reply.LastError = state.LastError
+	if config.Enabled && !state.LastRotation.IsZero() {
+		reply.NextRotation = state.LastRotation.Add(config.Interval)
+	}
+	return nil
+}
+
+// generateGossipKey returns a new random key suitable for the gossip
+// encryption, encoded as expected by the keyring operations.
+func generateGossipKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate a gossip encryption key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// keyFingerprint returns the fingerprint by which a key is referred to in the
+// state of the rotation.
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,182 @@
+package consul
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeader_KeyringRotation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	key1 := "H1dfkSZOVnP/JUnaBfTzXg=="
+	keyBytes1, err := base64.StdEncoding.DecodeString(key1)
+	require.NoError(t, err)
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.SerfLANConfig.MemberlistConfig.SecretKey = keyBytes1
+		c.SerfWANConfig.MemberlistConfig.SecretKey = keyBytes1
+		// The rotation is driven by the test rather than the leader routine.
+		c.KeyringRotation = KeyringRotationConfig{
+			Interval:    time.Hour,
+			GracePeriod: 10 * time.Minute,
+		}
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	status := func() *structs.KeyringRotationStatus {
+		var out structs.KeyringRotationStatus
+		req := structs.DCSpecificRequest{Datacenter: "dc1"}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.KeyringRotationStatus", &req, &out))
+		return &out
+	}
+	keys := func() (map[string]int, map[string]int) {
+		lan, err := s1.KeyManagerLAN().ListKeys()
+		require.NoError(t, err)
+		wan, err := s1.KeyManagerWAN().ListKeys()
+		require.NoError(t, err)
+		require.Equal(t, lan.Keys, wan.Keys)
+		require.Equal(t, lan.PrimaryKeys, wan.PrimaryKeys)
+		return lan.Keys, lan.PrimaryKeys
+	}
+
+	now := time.Now()
+
+	// The first step only starts counting the interval.
+	require.NoError(t, s1.keyringRotationStep(now))
+	out := status()
+	require.Equal(t, structs.KeyringRotationIdle, out.Phase)
+	require.True(t, out.LastRotation.Equal(now))
+	require.True(t, out.NextRotation.IsZero())
+
+	require.NoError(t, s1.keyringRotationStep(now.Add(30*time.Minute)))
+	all, primary := keys()
+	require.Len(t, all, 1)
+
+	// Once the interval elapsed, a new key is installed.
+	now = now.Add(time.Hour)
+	require.NoError(t, s1.keyringRotationStep(now))
+	require.Equal(t, structs.KeyringRotationInstalled, status().Phase)
+	all, primary = keys()
+	require.Len(t, all, 2)
+	require.Equal(t, map[string]int{key1: 1}, primary)
+
+	var key2 string
+	for key := range all {
+		if key != key1 {
+			key2 = key
+		}
+	}
+	require.NotEmpty(t, key2)
+
+	// Only the fingerprints of the keys are stored in raft.
+	raw, err := s1.getSystemMetadata(structs.SystemMetadataKeyringRotationKey)
+	require.NoError(t, err)
+	require.NotContains(t, raw, key1)
+	require.NotContains(t, raw, key2)
+	state, err := s1.getKeyringRotationState()
+	require.NoError(t, err)
+	require.Equal(t, keyFingerprint(key2), state.KeyFingerprint)
+	require.Equal(t, map[string]string{"lan": keyFingerprint(key1), "wan": keyFingerprint(key1)}, state.PreviousKeyFingerprints)
+
+	// The new key is promoted after the grace period.
+	require.NoError(t, s1.keyringRotationStep(now.Add(5*time.Minute)))
+	require.Equal(t, structs.KeyringRotationInstalled, status().Phase)
+
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, s1.keyringRotationStep(now))
+	require.Equal(t, structs.KeyringRotationPromoted, status().Phase)
+	all, primary = keys()
+	require.Len(t, all, 2)
+	require.Equal(t, map[string]int{key2: 1}, primary)
+
+	// The previous key is removed after another grace period.
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, s1.keyringRotationStep(now))
+	out = status()
+	require.Equal(t, structs.KeyringRotationIdle, out.Phase)
+	require.True(t, out.LastRotation.Equal(now))
+	require.Empty(t, out.LastError)
+	all, primary = keys()
+	require.Equal(t, map[string]int{key2: 1}, all)
+	require.Equal(t, map[string]int{key2: 1}, primary)
+}
+
+func TestLeader_KeyringRotation_KeyNotInstalled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	keyBytes, err := base64.StdEncoding.DecodeString("H1dfkSZOVnP/JUnaBfTzXg==")
+	require.NoError(t, err)
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.SerfLANConfig.MemberlistConfig.SecretKey = keyBytes
+		c.SerfWANConfig.MemberlistConfig.SecretKey = keyBytes
+		c.KeyringRotation = KeyringRotationConfig{
+			Interval:    time.Hour,
+			GracePeriod: 10 * time.Minute,
+		}
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// A leader that stored the state but failed before installing the key.
+	now := time.Now()
+	require.NoError(t, s1.setKeyringRotationState(&keyringRotationState{
+		Phase:          structs.KeyringRotationInstalled,
+		PhaseStartedAt: now,
+		LastRotation:   now.Add(-time.Hour),
+		KeyFingerprint: keyFingerprint("lost"),
+	}))
+
+	err = s1.keyringRotationStep(now.Add(time.Minute))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not installed on any member")
+
+	state, err := s1.getKeyringRotationState()
+	require.NoError(t, err)
+	require.Equal(t, structs.KeyringRotationIdle, state.Phase)
+	require.Empty(t, state.KeyFingerprint)
+
+	// The rotation starts over with a new key.
+	require.NoError(t, s1.keyringRotationStep(now.Add(2*time.Minute)))
+	state, err = s1.getKeyringRotationState()
+	require.NoError(t, err)
+	require.Equal(t, structs.KeyringRotationInstalled, state.Phase)
+	require.NotEmpty(t, state.KeyFingerprint)
+}
+
+func TestKeyringRotationCheckMembers(t *testing.T) {
+	installed := func(resp *serf.KeyResponse) map[string]int { return resp.Keys }
+
+	lists := map[string]*serf.KeyResponse{
+		"lan": {NumNodes: 3, Keys: map[string]int{"old": 3, "new": 3}},
+		"wan": {NumNodes: 2, Keys: map[string]int{"old": 2, "new": 2}},
+	}
+	require.NoError(t, keyringRotationCheckMembers(lists, "new", "installed", installed))
+
+	lists["wan"].Keys["new"] = 1
+	err := keyringRotationCheckMembers(lists, "new", "installed", installed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "installed by 1 of the 2 members of the wan pool")
+}
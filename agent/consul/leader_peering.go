@@ -429,7 +429,11 @@ func (s *Server) establishStream(ctx context.Context,
 		case err != nil:
 			logger.Error("error managing peering stream", "error", err)
 		}
-	}, peeringRetryTimeout)
+	}, func(failedAttempts uint, loopErr error) time.Duration {
+		retryTime := peeringRetryTimeout(failedAttempts, loopErr)
+		streamStatus.TrackRetry(failedAttempts, retryTime)
+		return retryTime
+	})
 
 	return nil
 }
@@ -545,6 +549,10 @@ func (s *Server) removePeeringAndData(ctx context.Context, logger hclog.Logger,
 		logger.Error("Failed to remove trust bundle for peer", "error", err)
 		return
 	}
+	if err := s.deleteConfigEntriesFromPeer(ctx, limiter, entMeta, peer.Name); err != nil {
+		logger.Error("Failed to remove config entries for peer", "error", err)
+		return
+	}
 
 	if err := limiter.Wait(ctx); err != nil {
 		return
@@ -647,6 +655,28 @@ func (s *Server) deleteTrustBundleFromPeer(ctx context.Context, limiter *rate.Li
 	return err
 }
 
+// deleteConfigEntriesFromPeer deletes the config entries imported from a peer, if present.
+func (s *Server) deleteConfigEntriesFromPeer(ctx context.Context, limiter *rate.Limiter, entMeta acl.EnterpriseMeta, peerName string) error {
+	_, entries, err := s.fsm.State().PeeringConfigEntriesRead(nil, state.Query{Value: peerName, EnterpriseMeta: entMeta})
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		return nil
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req := &pbpeering.PeeringConfigEntriesDeleteRequest{
+		Name:      peerName,
+		Partition: entMeta.PartitionOrDefault(),
+	}
+	_, err = s.raftApplyProtobuf(structs.PeeringConfigEntriesDeleteType, req)
+	return err
+}
+
 // retryLoopBackoffPeering re-runs loopFn with a backoff on error. errFn is run whenever
 // loopFn returns an error. retryTimeFn is used to calculate the time between retries on error.
 // It is passed the number of errors in a row that loopFn has returned and the latest error
@@ -0,0 +1,28 @@
+package consul
+
+import (
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// KeyringRotationStatus is used to retrieve the state of the automated
+// rotation of the gossip encryption key. The request is handled by the leader
+// since it is the one rotating the key with its own configuration.
+func (op *Operator) KeyringRotationStatus(args *structs.DCSpecificRequest, reply *structs.KeyringRotationStatus) error {
+	if done, err := op.srv.ForwardRPC("Operator.KeyringRotationStatus", args, reply); done {
+		return err
+	}
+
+	// This action requires keyring read access.
+	authz, err := op.srv.ACLResolver.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := op.srv.validateEnterpriseToken(authz.Identity()); err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().KeyringReadAllowed(nil); err != nil {
+		return err
+	}
+
+	return op.srv.keyringRotationStatus(reply)
+}
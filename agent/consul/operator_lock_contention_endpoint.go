@@ -0,0 +1,220 @@
+package consul
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// LockContention returns the holders and the waiters of the locks and
+// semaphores under a key prefix, along with their wait time stats. Waiters
+// are only tracked by the leader, so stale reads are not allowed.
+func (op *Operator) LockContention(args *structs.LockContentionRequest, reply *structs.IndexedLockContention) error {
+	args.AllowStale = false
+	if done, err := op.srv.ForwardRPC("Operator.LockContention", args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access, the locks are then
+	// filtered by key read access.
+	var authzContext acl.AuthorizerContext
+	authz, err := op.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	if err := op.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	return op.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, locks, err := lockContention(ws, state, op.srv.lockTracker, args.Prefix, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+
+			total := len(locks)
+			filtered := locks[:0]
+			for _, l := range locks {
+				var authzContext acl.AuthorizerContext
+				l.FillAuthzContext(&authzContext)
+				if authz.KeyRead(l.Key, &authzContext) == acl.Allow {
+					filtered = append(filtered, l)
+				}
+			}
+			reply.QueryMeta.ResultsFilteredByACLs = total != len(filtered)
+
+			// Must provide non-zero index to prevent blocking.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			reply.Locks = filtered
+			return nil
+		})
+}
+
+// lockContention builds the contention of the locks and semaphores under
+// prefix from the KV store, which knows their holders and the contenders of
+// the semaphores, and from the tracker, which knows the sessions that failed
+// to acquire a lock and the wait time stats.
+func lockContention(ws memdb.WatchSet, s *state.Store, tracker *lockTracker, prefix string, entMeta *acl.EnterpriseMeta) (uint64, []*structs.LockContention, error) {
+	index, entries, err := s.KVSList(ws, prefix, entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	type lock struct {
+		limit      int
+		holders    map[string]bool
+		contenders map[string]bool
+	}
+	locks := make(map[lockTrackerKey]*lock)
+	get := func(key lockTrackerKey) *lock {
+		l, ok := locks[key]
+		if !ok {
+			l = &lock{holders: make(map[string]bool), contenders: make(map[string]bool)}
+			locks[key] = l
+		}
+		return l
+	}
+
+	for _, e := range entries {
+		switch {
+		case isSemaphoreLock(e):
+			d, err := e.Decompressed()
+			if err != nil {
+				return 0, nil, err
+			}
+			sem, err := decodeSemaphoreLock(d.Value)
+			if err != nil {
+				// This isn't a semaphore created by the api package.
+				continue
+			}
+			l := get(semaphoreKey(e))
+			l.limit = sem.Limit
+			for session, held := range sem.Holders {
+				if held {
+					l.holders[session] = true
+				}
+			}
+
+		case isSemaphoreContender(e):
+			get(semaphoreKey(e)).contenders[e.Session] = true
+
+		case e.Session != "":
+			get(lockKey(e)).holders[e.Session] = true
+		}
+	}
+
+	// Locks that aren't held but have waiters are only known to the tracker.
+	for _, key := range tracker.keys(prefix, entMeta) {
+		get(key)
+	}
+
+	sessionExists := func(session string, entMeta *acl.EnterpriseMeta) (bool, error) {
+		_, sess, err := s.SessionGet(ws, session, entMeta)
+		return sess != nil, err
+	}
+
+	var out []*structs.LockContention
+	for key, l := range locks {
+		stats, _ := tracker.stats(key)
+		c := &structs.LockContention{
+			Key:            key.key,
+			Semaphore:      key.semaphore,
+			Limit:          l.limit,
+			Acquisitions:   stats.acquisitions,
+			Contentions:    stats.contentions,
+			MaxWaitTime:    stats.maxWait,
+			EnterpriseMeta: key.EnterpriseMeta,
+		}
+		if stats.acquisitions > 0 {
+			c.MeanWaitTime = stats.totalWait / time.Duration(stats.acquisitions)
+		}
+
+		if key.semaphore {
+			// The holders of a semaphore are removed from its lock entry by the
+			// next contender that acquires it, so only the holders that are
+			// still contending are reported.
+			for session := range l.contenders {
+				if l.holders[session] {
+					c.Holders = append(c.Holders, lockSession(session, stats.holders))
+				} else {
+					c.Waiters = append(c.Waiters, lockSession(session, stats.waiters))
+				}
+			}
+		} else {
+			for session := range l.holders {
+				c.Holders = append(c.Holders, lockSession(session, stats.holders))
+			}
+			for session := range stats.waiters {
+				if l.holders[session] {
+					continue
+				}
+				ok, err := sessionExists(session, &key.EnterpriseMeta)
+				if err != nil {
+					return 0, nil, err
+				}
+				if !ok {
+					tracker.forget(key, session)
+					continue
+				}
+				c.Waiters = append(c.Waiters, lockSession(session, stats.waiters))
+			}
+		}
+
+		if len(c.Holders) == 0 && len(c.Waiters) == 0 {
+			continue
+		}
+		sortLockSessions(c.Holders)
+		sortLockSessions(c.Waiters)
+		c.QueueDepth = len(c.Waiters)
+		out = append(out, c)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].QueueDepth != out[j].QueueDepth {
+			return out[i].QueueDepth > out[j].QueueDepth
+		}
+		return out[i].Key < out[j].Key
+	})
+	return index, out, nil
+}
+
+func lockSession(session string, since map[string]time.Time) structs.LockSession {
+	s := structs.LockSession{Session: session}
+	if t, ok := since[session]; ok {
+		s.Since = &t
+	}
+	return s
+}
+
+// sortLockSessions sorts the sessions by the time they acquired or started
+// waiting for the lock, the sessions that predate the leader coming first.
+func sortLockSessions(sessions []structs.LockSession) {
+	sort.Slice(sessions, func(i, j int) bool {
+		a, b := sessions[i].Since, sessions[j].Since
+		switch {
+		case a == nil && b == nil:
+			return sessions[i].Session < sessions[j].Session
+		case a == nil || b == nil:
+			return a == nil
+		case !a.Equal(*b):
+			return a.Before(*b)
+		default:
+			return sessions[i].Session < sessions[j].Session
+		}
+	})
+}
@@ -10,6 +10,9 @@ import (
 // Usage returns counts for service usage within catalog.
 func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usage) error {
 	reply.Usage = make(map[string]structs.ServiceUsage)
+	if args.History {
+		reply.History = make(map[string][]structs.ServiceUsageSample)
+	}
 
 	if args.Global {
 		remoteDCs := op.srv.router.GetDatacenters()
@@ -21,6 +24,7 @@ func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usa
 						Token: args.Token,
 					},
 				},
+				History: args.History,
 			}
 			var resp structs.Usage
 			if _, err := op.srv.ForwardRPC("Operator.Usage", remoteArgs, &resp); err != nil {
@@ -29,6 +33,9 @@ func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usa
 			if usage, ok := resp.Usage[dc]; ok {
 				reply.Usage[dc] = usage
 			}
+			if history, ok := resp.History[dc]; ok && args.History {
+				reply.History[dc] = history
+			}
 		}
 	}
 
@@ -57,6 +64,9 @@ func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usa
 			}
 
 			reply.QueryMeta.Index, reply.Usage[op.srv.config.Datacenter] = index, serviceUsage
+			if args.History {
+				reply.History[op.srv.config.Datacenter] = op.srv.usageHistory.Samples()
+			}
 			return nil
 		})
 }
@@ -331,6 +331,13 @@ func (b *PeeringBackend) PeeringTrustBundleWrite(req *pbpeering.PeeringTrustBund
 	return err
 }
 
+func (b *PeeringBackend) PeeringConfigEntriesWrite(req *pbpeering.PeeringConfigEntriesWriteRequest) error {
+	// The "safe to ignore" flag lets older servers skip the config entries
+	// replicated from peers, which they can't use anyway.
+	_, err := b.srv.raftApplyProtobuf(structs.PeeringConfigEntriesWriteType|structs.IgnoreUnknownTypeFlag, req)
+	return err
+}
+
 func (b *PeeringBackend) CatalogRegister(req *structs.RegisterRequest) error {
 	_, err := b.srv.leaderRaftApply("Catalog.Register", structs.RegisterRequestType, req)
 	return err
@@ -197,6 +197,10 @@ func parseService(svc *structs.ServiceQuery) error {
 		return fmt.Errorf("Targets cannot be populated with NearestN or Datacenters")
 	}
 
+	if failover.SamenessGroup != "" && (failover.NearestN != 0 || len(failover.Datacenters) != 0 || len(failover.Targets) != 0) {
+		return fmt.Errorf("SamenessGroup cannot be populated with NearestN, Datacenters or Targets")
+	}
+
 	// Make sure the metadata filters are valid
 	if err := structs.ValidateNodeMetadata(svc.NodeMeta, true); err != nil {
 		return err
@@ -663,6 +667,7 @@ type queryServer interface {
 	GetOtherDatacentersByDistance() ([]string, error)
 	GetLocalDC() string
 	ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error
+	GetSamenessGroupFailoverTargets(name string, entMeta acl.EnterpriseMeta) ([]structs.QueryFailoverTarget, error)
 }
 
 // queryServerWrapper applies the queryServer interface to a Server.
@@ -681,6 +686,20 @@ func (q *queryServerWrapper) ExecuteRemote(args *structs.PreparedQueryExecuteRem
 	return q.executeRemote(args, reply)
 }
 
+// GetSamenessGroupFailoverTargets returns the failover targets for the members
+// of the given sameness group.
+func (q *queryServerWrapper) GetSamenessGroupFailoverTargets(name string, entMeta acl.EnterpriseMeta) ([]structs.QueryFailoverTarget, error) {
+	_, entry, err := q.srv.fsm.State().ConfigEntry(nil, structs.SamenessGroup, name, &entMeta)
+	if err != nil {
+		return nil, err
+	}
+	group, ok := entry.(*structs.SamenessGroupConfigEntry)
+	if !ok {
+		return nil, fmt.Errorf("sameness group %q not found", name)
+	}
+	return group.FailoverTargets(), nil
+}
+
 // GetLogger returns the server's logger.
 func (q *queryServerWrapper) GetLogger() hclog.Logger {
 	return q.srv.loggers.Named(logging.PreparedQuery)
@@ -740,7 +759,14 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 	}
 
 	// Then add any DCs explicitly listed that weren't selected above.
-	for _, target := range query.Service.Failover.AsTargets() {
+	failoverTargets := query.Service.Failover.AsTargets()
+	if group := query.Service.Failover.SamenessGroup; group != "" {
+		failoverTargets, err = q.GetSamenessGroupFailoverTargets(group, query.Service.EnterpriseMeta)
+		if err != nil {
+			q.GetLogger().Warn("Skipping failover to sameness group in prepared query", "sameness_group", group, "error", err)
+		}
+	}
+	for _, target := range failoverTargets {
 		// This will prevent a log of other log spammage if we do not
 		// attempt to talk to datacenters we don't know about.
 		if dc := target.Datacenter; dc != "" {
@@ -105,8 +105,16 @@ func TestPreparedQuery_Apply(t *testing.T) {
 		t.Fatalf("bad: %v", err)
 	}
 
-	// Fix that and make sure it propagates an error from the Raft apply.
+	// Fix that and ensure SamenessGroup and Datacenters cannot be set at the same time.
 	query.Query.Service.Failover.Targets = nil
+	query.Query.Service.Failover.SamenessGroup = "group"
+	err = msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &reply)
+	if err == nil || !strings.Contains(err.Error(), "SamenessGroup cannot be populated with") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Fix that and make sure it propagates an error from the Raft apply.
+	query.Query.Service.Failover.SamenessGroup = ""
 	query.Query.Session = "nope"
 	err = msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &reply)
 	if err == nil || !strings.Contains(err.Error(), "invalid session") {
@@ -2936,6 +2944,7 @@ type mockQueryServer struct {
 	QueryFn          func(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error
 	Logger           hclog.Logger
 	LogBuffer        *bytes.Buffer
+	SamenessGroups   map[string][]structs.QueryFailoverTarget
 }
 
 func (m *mockQueryServer) JoinQueryLog() string {
@@ -2963,6 +2972,14 @@ func (m *mockQueryServer) GetOtherDatacentersByDistance() ([]string, error) {
 	return m.Datacenters, m.DatacentersError
 }
 
+func (m *mockQueryServer) GetSamenessGroupFailoverTargets(name string, _ acl.EnterpriseMeta) ([]structs.QueryFailoverTarget, error) {
+	targets, ok := m.SamenessGroups[name]
+	if !ok {
+		return nil, fmt.Errorf("sameness group %q not found", name)
+	}
+	return targets, nil
+}
+
 func (m *mockQueryServer) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error {
 	peerName := args.Query.Service.Peer
 	dc := args.Datacenter
@@ -3340,4 +3357,45 @@ func TestPreparedQuery_queryFailover(t *testing.T) {
 		require.Equal(t, nodes(), reply.Nodes)
 		require.Equal(t, "peer:cluster-01|dc44:PreparedQuery.ExecuteRemote|peer:cluster-02", mock.JoinQueryLog())
 	}
+
+	// Failover returns data from the first member of the sameness group with data.
+	query.Service.Failover.Targets = nil
+	query.Service.Failover.SamenessGroup = "group"
+	{
+		mock := &mockQueryServer{
+			SamenessGroups: map[string][]structs.QueryFailoverTarget{
+				"group": {{Peer: "cluster-01"}, {Peer: "cluster-02"}, {Peer: "cluster-03"}},
+			},
+			QueryFn: func(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error {
+				if args.Query.Service.Peer == "cluster-02" {
+					reply.Nodes = nodes()
+				}
+				return nil
+			},
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		if err := queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{}, &reply); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		require.Equal(t, "cluster-02", reply.PeerName)
+		require.Equal(t, 2, reply.Failovers)
+		require.Equal(t, nodes(), reply.Nodes)
+		require.Equal(t, "peer:cluster-01|peer:cluster-02", mock.JoinQueryLog())
+	}
+
+	// A missing sameness group doesn't fail the query.
+	query.Service.Failover.SamenessGroup = "missing"
+	{
+		mock := &mockQueryServer{}
+
+		var reply structs.PreparedQueryExecuteResponse
+		if err := queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{}, &reply); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		require.Equal(t, 0, reply.Failovers)
+		require.Empty(t, reply.Nodes)
+		require.Empty(t, mock.JoinQueryLog())
+		require.Contains(t, mock.LogBuffer.String(), "Skipping failover to sameness group")
+	}
 }
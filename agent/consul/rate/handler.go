@@ -11,6 +11,7 @@ import (
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-hclog"
+	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/consul/agent/consul/multilimiter"
 )
@@ -104,6 +105,12 @@ type Operation struct {
 
 	// Type of operation to be performed (e.g. read or write).
 	Type OperationType
+
+	// AccessorID is the accessor of the ACL token used for the operation. The
+	// token is only known once the request has been decoded, after the global
+	// and per-IP limits were checked, so an operation with an AccessorID is
+	// only checked against the per-token limits.
+	AccessorID string
 }
 
 //go:generate mockery --name RequestLimitsHandler --inpackage
@@ -127,10 +134,9 @@ type Handler struct {
 type HandlerConfig struct {
 	multilimiter.Config
 
-	// GlobalMode configures the action that will be taken when a global rate-limit
-	// has been exhausted.
-	//
-	// Note: in the future there'll be a separate Mode for IP-based limits.
+	// GlobalMode configures the action that will be taken when a rate-limit
+	// has been exhausted. It applies to the global, per-IP and per-token
+	// limits.
 	GlobalMode Mode
 
 	// GlobalWriteConfig configures the global rate limiter for write operations.
@@ -138,6 +144,46 @@ type HandlerConfig struct {
 
 	// GlobalReadConfig configures the global rate limiter for read operations.
 	GlobalReadConfig multilimiter.LimiterConfig
+
+	// IPConfig configures the rate limiters applied to each source IP
+	// address, or is nil if there are none.
+	IPConfig *IPLimitsConfig
+
+	// TokenConfig configures the rate limiters applied to each ACL token, or
+	// is nil if there are none.
+	TokenConfig *TokenLimitsConfig
+}
+
+// ReadWriteConfig configures the rate limiters of the read and write
+// operations.
+type ReadWriteConfig struct {
+	ReadConfig  multilimiter.LimiterConfig
+	WriteConfig multilimiter.LimiterConfig
+}
+
+// IPLimitsConfig configures the rate limiters applied to each source IP
+// address.
+type IPLimitsConfig struct {
+	ReadWriteConfig
+
+	// Overrides replace the limits of the IP addresses in their CIDR block.
+	// Each address still gets its own rate limiter. The first matching
+	// override applies.
+	Overrides []IPLimitsOverride
+}
+
+// IPLimitsOverride replaces the per-IP limits of the addresses in CIDR.
+type IPLimitsOverride struct {
+	CIDR *net.IPNet
+	ReadWriteConfig
+}
+
+// TokenLimitsConfig configures the rate limiters applied to each ACL token.
+type TokenLimitsConfig struct {
+	ReadWriteConfig
+
+	// Overrides replace the limits of the tokens, indexed by accessor ID.
+	Overrides map[string]ReadWriteConfig
 }
 
 //go:generate mockery --name LeaderStatusProvider --inpackage --filename mock_LeaderStatusProvider_test.go
@@ -147,6 +193,10 @@ type LeaderStatusProvider interface {
 	// the leader (e.g. write operations) we don't tell clients to retry against
 	// a different server.
 	IsLeader() bool
+
+	// IsServer is used to exempt the operations of the other servers from the
+	// per-IP limits, since they forward the requests of many clients.
+	IsServer(addr net.Addr) bool
 }
 
 func NewHandlerWithLimiter(
@@ -156,6 +206,8 @@ func NewHandlerWithLimiter(
 
 	limiter.UpdateConfig(cfg.GlobalWriteConfig, globalWrite)
 	limiter.UpdateConfig(cfg.GlobalReadConfig, globalRead)
+	updateIPLimiterConfig(limiter, cfg.IPConfig)
+	updateTokenLimiterConfig(limiter, cfg.TokenConfig)
 
 	h := &Handler{
 		cfg:     new(atomic.Pointer[HandlerConfig]),
@@ -253,6 +305,12 @@ func (h *Handler) UpdateConfig(cfg HandlerConfig) {
 	if !reflect.DeepEqual(existingCfg.GlobalReadConfig, cfg.GlobalReadConfig) {
 		h.limiter.UpdateConfig(cfg.GlobalReadConfig, globalRead)
 	}
+	if !reflect.DeepEqual(existingCfg.IPConfig, cfg.IPConfig) {
+		updateIPLimiterConfig(h.limiter, cfg.IPConfig)
+	}
+	if !reflect.DeepEqual(existingCfg.TokenConfig, cfg.TokenConfig) {
+		updateTokenLimiterConfig(h.limiter, cfg.TokenConfig)
+	}
 }
 
 func (h *Handler) Register(leaderStatusProvider LeaderStatusProvider) {
@@ -270,9 +328,19 @@ type limit struct {
 func (h *Handler) limits(op Operation) []limit {
 	limits := make([]limit, 0)
 
+	if op.AccessorID != "" {
+		if token := h.tokenLimit(op); token != nil {
+			limits = append(limits, *token)
+		}
+		return limits
+	}
+
 	if global := h.globalLimit(op); global != nil {
 		limits = append(limits, *global)
 	}
+	if ip := h.ipLimit(op); ip != nil {
+		limits = append(limits, *ip)
+	}
 
 	return limits
 }
@@ -297,6 +365,135 @@ func (h *Handler) globalLimit(op Operation) *limit {
 	return lim
 }
 
+func (h *Handler) ipLimit(op Operation) *limit {
+	cfg := h.cfg.Load()
+	if op.Type == OperationTypeExempt || cfg.IPConfig == nil || op.SourceAddr == nil {
+		return nil
+	}
+	ip := sourceIP(op.SourceAddr)
+	if ip == nil || h.leaderStatusProvider.IsServer(op.SourceAddr) {
+		return nil
+	}
+
+	prefix, rw := ipPrefix, cfg.IPConfig.ReadWriteConfig
+	for _, o := range cfg.IPConfig.Overrides {
+		if o.CIDR.Contains(ip) {
+			prefix, rw = ipOverridePrefix(o.CIDR), o.ReadWriteConfig
+			break
+		}
+	}
+	return keyedLimitFor(cfg.GlobalMode, op.Type, "ip", prefix, rw, ip.String())
+}
+
+func (h *Handler) tokenLimit(op Operation) *limit {
+	cfg := h.cfg.Load()
+	if op.Type == OperationTypeExempt || cfg.TokenConfig == nil {
+		return nil
+	}
+
+	prefix, rw := tokenPrefix, cfg.TokenConfig.ReadWriteConfig
+	if o, ok := cfg.TokenConfig.Overrides[op.AccessorID]; ok {
+		prefix, rw = tokenOverridePrefix(op.AccessorID), o
+	}
+	return keyedLimitFor(cfg.GlobalMode, op.Type, "token", prefix, rw, op.AccessorID)
+}
+
+// keyedLimitFor returns the limit of the operation for the given key, or nil
+// if the rate of its type is unlimited, to avoid tracking a rate limiter per
+// key for nothing.
+func keyedLimitFor(mode Mode, typ OperationType, kind, prefix string, rw ReadWriteConfig, key string) *limit {
+	lim := &limit{mode: mode}
+	switch typ {
+	case OperationTypeRead:
+		if rw.ReadConfig.Rate == rate.Inf {
+			return nil
+		}
+		lim.desc = kind + "/read"
+		lim.ent = keyedLimit{prefix: prefix + readSuffix, key: key}
+	case OperationTypeWrite:
+		if rw.WriteConfig.Rate == rate.Inf {
+			return nil
+		}
+		lim.desc = kind + "/write"
+		lim.ent = keyedLimit{prefix: prefix + writeSuffix, key: key}
+	default:
+		panic(fmt.Sprintf("unknown operation type %d", typ))
+	}
+	return lim
+}
+
+// sourceIP returns the IP address of the client, or nil if it doesn't have
+// one (e.g. over a unix socket).
+func sourceIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+func updateIPLimiterConfig(limiter multilimiter.RateLimiter, cfg *IPLimitsConfig) {
+	if cfg == nil {
+		return
+	}
+	updateReadWriteLimiterConfig(limiter, ipPrefix, cfg.ReadWriteConfig)
+	for _, o := range cfg.Overrides {
+		updateReadWriteLimiterConfig(limiter, ipOverridePrefix(o.CIDR), o.ReadWriteConfig)
+	}
+}
+
+func updateTokenLimiterConfig(limiter multilimiter.RateLimiter, cfg *TokenLimitsConfig) {
+	if cfg == nil {
+		return
+	}
+	updateReadWriteLimiterConfig(limiter, tokenPrefix, cfg.ReadWriteConfig)
+	for accessorID, o := range cfg.Overrides {
+		updateReadWriteLimiterConfig(limiter, tokenOverridePrefix(accessorID), o)
+	}
+}
+
+func updateReadWriteLimiterConfig(limiter multilimiter.RateLimiter, prefix string, cfg ReadWriteConfig) {
+	limiter.UpdateConfig(cfg.ReadConfig, []byte(prefix+readSuffix))
+	limiter.UpdateConfig(cfg.WriteConfig, []byte(prefix+writeSuffix))
+}
+
+const (
+	ipPrefix    = "ip"
+	tokenPrefix = "token"
+	readSuffix  = ".read"
+	writeSuffix = ".write"
+)
+
+// ipOverridePrefix returns the prefix of the rate limiters of the addresses
+// matching an override, so that they use its configuration.
+func ipOverridePrefix(cidr *net.IPNet) string {
+	return ipPrefix + "." + cidr.String()
+}
+
+// tokenOverridePrefix returns the prefix of the rate limiters of the token
+// matching an override, so that they use its configuration.
+func tokenOverridePrefix(accessorID string) string {
+	return tokenPrefix + "." + accessorID
+}
+
+// keyedLimit represents a limit that applies to the operations of a single
+// source IP address or ACL token.
+type keyedLimit struct {
+	prefix string
+	key    string
+}
+
+// Key satisfies the multilimiter.LimitedEntity interface.
+func (l keyedLimit) Key() multilimiter.KeyType {
+	return multilimiter.Key([]byte(l.prefix), []byte(l.key))
+}
+
 var (
 	// globalWrite identifies the global rate limit applied to write operations.
 	globalWrite = globalLimit("global.write")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -260,6 +261,122 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandler_KeyedLimits(t *testing.T) {
+	_, cidr, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	limited := ReadWriteConfig{
+		ReadConfig:  multilimiter.LimiterConfig{Rate: 10, Burst: 10},
+		WriteConfig: multilimiter.LimiterConfig{Rate: 1, Burst: 1},
+	}
+	cfg := HandlerConfig{
+		GlobalMode: ModeEnforcing,
+		IPConfig: &IPLimitsConfig{
+			ReadWriteConfig: limited,
+			Overrides: []IPLimitsOverride{{
+				CIDR: cidr,
+				ReadWriteConfig: ReadWriteConfig{
+					ReadConfig:  multilimiter.LimiterConfig{Rate: 100, Burst: 100},
+					WriteConfig: multilimiter.LimiterConfig{Rate: rate.Inf},
+				},
+			}},
+		},
+		TokenConfig: &TokenLimitsConfig{
+			ReadWriteConfig: limited,
+			Overrides: map[string]ReadWriteConfig{
+				"unlimited": {
+					ReadConfig:  multilimiter.LimiterConfig{Rate: rate.Inf},
+					WriteConfig: multilimiter.LimiterConfig{Rate: rate.Inf},
+				},
+			},
+		},
+	}
+
+	addr := func(s string) net.Addr {
+		return net.TCPAddrFromAddrPort(netip.MustParseAddrPort(s))
+	}
+
+	type limitCheck struct {
+		limit multilimiter.LimitedEntity
+		allow bool
+	}
+	testCases := map[string]struct {
+		op               Operation
+		isServer         bool
+		checks           []limitCheck
+		expectErr        error
+		expectMetricName string
+	}{
+		"per-IP write limit exceeded": {
+			op: Operation{Type: OperationTypeWrite, Name: "Foo.Bar", SourceAddr: addr("1.2.3.4:5678")},
+			checks: []limitCheck{
+				{limit: globalWrite, allow: true},
+				{limit: keyedLimit{prefix: "ip.write", key: "1.2.3.4"}, allow: false},
+			},
+			expectErr:        ErrRetryElsewhere,
+			expectMetricName: "rpc.rate_limit.exceeded;limit_type=ip/write;op=Foo.Bar;mode=enforcing",
+		},
+		"per-IP limits do not apply to servers": {
+			op:       Operation{Type: OperationTypeWrite, Name: "Foo.Bar", SourceAddr: addr("1.2.3.4:5678")},
+			isServer: true,
+			checks: []limitCheck{
+				{limit: globalWrite, allow: true},
+			},
+		},
+		"per-IP override": {
+			op: Operation{Type: OperationTypeRead, Name: "Foo.Bar", SourceAddr: addr("10.1.2.3:5678")},
+			checks: []limitCheck{
+				{limit: globalRead, allow: true},
+				{limit: keyedLimit{prefix: "ip.10.0.0.0/8.read", key: "10.1.2.3"}, allow: true},
+			},
+		},
+		"per-IP override without limit": {
+			op: Operation{Type: OperationTypeWrite, Name: "Foo.Bar", SourceAddr: addr("10.1.2.3:5678")},
+			checks: []limitCheck{
+				{limit: globalWrite, allow: true},
+			},
+		},
+		"per-token read limit exceeded": {
+			op: Operation{Type: OperationTypeRead, Name: "Foo.Bar", AccessorID: "accessor"},
+			checks: []limitCheck{
+				{limit: keyedLimit{prefix: "token.read", key: "accessor"}, allow: false},
+			},
+			expectErr:        ErrRetryElsewhere,
+			expectMetricName: "rpc.rate_limit.exceeded;limit_type=token/read;op=Foo.Bar;mode=enforcing",
+		},
+		"per-token override without limit": {
+			op:     Operation{Type: OperationTypeWrite, Name: "Foo.Bar", AccessorID: "unlimited"},
+			checks: []limitCheck{},
+		},
+		"operation exempt from limiting": {
+			op:     Operation{Type: OperationTypeExempt, Name: "Foo.Bar", AccessorID: "accessor"},
+			checks: []limitCheck{},
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			sink := metrics.TestSetupMetrics(t, "")
+			limiter := newMockLimiter(t)
+			limiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
+			for _, c := range tc.checks {
+				limiter.On("Allow", c.limit).Return(c.allow)
+			}
+
+			leaderStatusProvider := NewMockLeaderStatusProvider(t)
+			leaderStatusProvider.On("IsLeader").Return(false).Maybe()
+			leaderStatusProvider.On("IsServer", mock.Anything).Return(tc.isServer).Maybe()
+
+			handler := NewHandlerWithLimiter(cfg, limiter, hclog.NewNullLogger())
+			handler.Register(leaderStatusProvider)
+
+			require.Equal(t, tc.expectErr, handler.Allow(tc.op))
+			if tc.expectMetricName != "" {
+				metrics.AssertCounter(t, sink, tc.expectMetricName, 1)
+			}
+		})
+	}
+}
+
 func TestNewHandlerWithLimiter_CallsUpdateConfig(t *testing.T) {
 	mockRateLimiter := multilimiter.NewMockRateLimiter(t)
 	mockRateLimiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
@@ -309,6 +426,22 @@ func TestUpdateConfig(t *testing.T) {
 				mockRateLimiter.AssertCalled(t, "UpdateConfig", cfg.GlobalWriteConfig, []byte("global.write"))
 			},
 		},
+		{
+			description: "RateLimiter gets updated when IPConfig changes.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.IPConfig = &IPLimitsConfig{
+					ReadWriteConfig: ReadWriteConfig{
+						ReadConfig:  multilimiter.LimiterConfig{Rate: 10, Burst: 10},
+						WriteConfig: multilimiter.LimiterConfig{Rate: 5, Burst: 5},
+					},
+				}
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 2)
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", cfg.IPConfig.ReadConfig, []byte("ip.read"))
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", cfg.IPConfig.WriteConfig, []byte("ip.write"))
+			},
+		},
 		{
 			description: "RateLimiter does not get updated when GlobalMode changes.",
 			configModFunc: func(cfg *HandlerConfig) {
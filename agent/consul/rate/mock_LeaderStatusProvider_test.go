@@ -3,6 +3,7 @@
 package rate
 
 import (
+	net "net"
 	testing "testing"
 
 	mock "github.com/stretchr/testify/mock"
@@ -27,6 +28,20 @@ func (_m *MockLeaderStatusProvider) IsLeader() bool {
 	return r0
 }
 
+// IsServer provides a mock function with given fields: addr
+func (_m *MockLeaderStatusProvider) IsServer(addr net.Addr) bool {
+	ret := _m.Called(addr)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(net.Addr) bool); ok {
+		r0 = rf(addr)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewMockLeaderStatusProvider creates a new instance of MockLeaderStatusProvider. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewMockLeaderStatusProvider(t testing.TB) *MockLeaderStatusProvider {
 	mock := &MockLeaderStatusProvider{}
@@ -63,6 +63,10 @@ var RPCCounters = []prometheus.CounterDefinition{
 		Name: []string{"rpc", "query"},
 		Help: "Increments when a server receives a read request, indicating the rate of new read queries.",
 	},
+	{
+		Name: []string{"rpc", "query", "shared"},
+		Help: "Increments when a server reuses the result of an identical read of another query rather than reading the state store.",
+	},
 }
 
 var RPCGauges = []prometheus.GaugeDefinition{
@@ -77,8 +81,31 @@ var RPCSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"rpc", "consistentRead"},
 		Help: "Measures the time spent confirming that a consistent read can be performed.",
 	},
+	{
+		Name: metricRaftApply,
+		Help: "Measures the time in milliseconds it takes to commit and apply a Raft log entry submitted by the server, labeled by message type.",
+	},
+	{
+		Name: metricRaftApplySize,
+		Help: "Measures the size in bytes of the Raft log entries submitted by the server, labeled by message type.",
+	},
+	{
+		Name: metricRaftApplyCompressedSize,
+		Help: "Measures the size in bytes of the Raft log entries submitted by the server once compressed, for the entries large enough to be compressed, labeled by message type.",
+	},
+	{
+		Name: metricRaftApplyBatchSize,
+		Help: "Measures the number of operations batched in the Raft log entries submitted by the server, labeled by message type.",
+	},
 }
 
+var (
+	metricRaftApply               = []string{"rpc", "raft_apply"}
+	metricRaftApplySize           = []string{"rpc", "raft_apply", "size"}
+	metricRaftApplyCompressedSize = []string{"rpc", "raft_apply", "compressed_size"}
+	metricRaftApplyBatchSize      = []string{"rpc", "raft_apply", "batch_size"}
+)
+
 const (
 	// Warn if the Raft command is larger than this.
 	// If it's over 1MB something is probably being abusive.
@@ -620,7 +647,38 @@ func (s *Server) ForwardRPC(method string, info structs.RPCInfo, reply interface
 		return s.connPool.RPC(s.config.Datacenter, leader.ShortName, leader.Addr,
 			method, info, reply)
 	}
-	return s.forwardRPC(info, forwardToDC, forwardToLeader)
+	if handled, err := s.forwardRPC(info, forwardToDC, forwardToLeader); handled || err != nil {
+		return handled, err
+	}
+
+	// The request is handled by this server, which is the only one charging
+	// it to the per-token rate limits.
+	if err := s.rateLimitToken(method, info); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// rateLimitToken checks an RPC against the per-token rate limits. Unlike the
+// global and per-IP limits, they can't be checked before the request is
+// decoded since the token is part of it, and they are only checked by the
+// server handling the request so that forwarding it doesn't count it twice.
+func (s *Server) rateLimitToken(method string, info structs.RPCInfo) error {
+	if !s.config.ACLsEnabled || !s.tokenRateLimited.Load() {
+		return nil
+	}
+
+	authz, err := s.ResolveToken(info.TokenSecret())
+	if err != nil || authz.AccessorID() == "" {
+		// The ACL errors are reported by the endpoint.
+		return nil
+	}
+
+	return s.incomingRPCLimiter.Allow(rate.Operation{
+		Name:       method,
+		Type:       middleware.NetRPCOperationType(method),
+		AccessorID: authz.AccessorID(),
+	})
 }
 
 // ForwardGRPC is used to potentially forward an RPC request to a remote DC or
@@ -911,6 +969,28 @@ func (s *Server) raftApplyProtobuf(t structs.MessageType, msg interface{}) (inte
 	return s.raftApplyWithEncoder(t, msg, structs.EncodeProtoInterface)
 }
 
+// canCompressRaftLogs returns whether the raft log entries are compressed. They
+// are only once every server of the datacenter can decompress them, otherwise
+// the older servers would fail to apply them.
+func (s *Server) canCompressRaftLogs() bool {
+	return s.raftLogCompressor != nil && s.serversSupportRaftCompression()
+}
+
+// serversSupportRaftCompression returns whether every server of the datacenter
+// advertises that it can decompress the raft log entries and snapshots. This
+// is remembered once true, as for the other features which require every
+// server to be upgraded.
+func (s *Server) serversSupportRaftCompression() bool {
+	if s.raftCompressionReady.Load() {
+		return true
+	}
+	if ok, found := ServersInDCSupportFeature(s, s.config.Datacenter, "rc"); !ok || !found {
+		return false
+	}
+	s.raftCompressionReady.Store(true)
+	return true
+}
+
 // raftApplyWithEncoder encodes a message, and then calls raft.Apply with the
 // encoded message. Returns the FSM response along with any errors. If the
 // FSM.Apply response is an error it will be returned as the error return
@@ -933,6 +1013,21 @@ func (s *Server) raftApplyWithEncoder(
 		s.rpcLogger().Warn("Attempting to apply large raft entry", "size_in_bytes", n)
 	}
 
+	labels := []metrics.Label{{Name: "type", Value: (t &^ structs.IgnoreUnknownTypeFlag).String()}}
+	metrics.AddSampleWithLabels(metricRaftApplySize, float32(len(buf)), labels)
+	metrics.AddSampleWithLabels(metricRaftApplyBatchSize, float32(raftApplyBatchSize(msg)), labels)
+
+	// The compressed entries are kept only if they are actually smaller.
+	if len(buf) >= s.config.RaftCompression.LogThreshold && s.canCompressRaftLogs() {
+		compressed := structs.CompressEntry(s.raftLogCompressor, buf)
+		metrics.AddSampleWithLabels(metricRaftApplyCompressedSize, float32(len(compressed)), labels)
+		if len(compressed) < len(buf) {
+			buf = compressed
+		}
+	}
+
+	start := time.Now()
+
 	var chunked bool
 	var future raft.ApplyFuture
 	switch {
@@ -943,7 +1038,9 @@ func (s *Server) raftApplyWithEncoder(
 		future = raftchunking.ChunkingApply(buf, nil, enqueueLimit, s.raft.ApplyLog)
 	}
 
-	if err := future.Error(); err != nil {
+	err = future.Error()
+	metrics.MeasureSinceWithLabels(metricRaftApply, start, labels)
+	if err != nil {
 		return nil, err
 	}
 
@@ -969,6 +1066,43 @@ func (s *Server) raftApplyWithEncoder(
 	return resp, nil
 }
 
+// raftApplyBatchSize returns the number of operations batched in a message
+// applied to Raft. The messages which aren't batches count as one operation.
+func raftApplyBatchSize(msg interface{}) int {
+	switch m := msg.(type) {
+	case *structs.CatalogBatchRequest:
+		return len(m.Register) + len(m.Deregister)
+	case *structs.TxnRequest:
+		return len(m.Ops)
+	case structs.Coordinates:
+		return len(m)
+	case *structs.ACLTokenBatchSetRequest:
+		return len(m.Tokens)
+	case *structs.ACLTokenBatchDeleteRequest:
+		return len(m.TokenIDs)
+	case *structs.ACLTokenUsageBatchSetRequest:
+		return len(m.Usages)
+	case *structs.ACLPolicyBatchSetRequest:
+		return len(m.Policies)
+	case *structs.ACLPolicyBatchDeleteRequest:
+		return len(m.PolicyIDs)
+	case *structs.ACLRoleBatchSetRequest:
+		return len(m.Roles)
+	case *structs.ACLRoleBatchDeleteRequest:
+		return len(m.RoleIDs)
+	case *structs.ACLBindingRuleBatchSetRequest:
+		return len(m.BindingRules)
+	case *structs.ACLBindingRuleBatchDeleteRequest:
+		return len(m.BindingRuleIDs)
+	case *structs.ACLAuthMethodBatchSetRequest:
+		return len(m.AuthMethods)
+	case *structs.ACLAuthMethodBatchDeleteRequest:
+		return len(m.AuthMethodNames)
+	default:
+		return 1
+	}
+}
+
 // queryFn is used to perform a query operation. See Server.blockingQuery for
 // the requirements of this function.
 type queryFn func(memdb.WatchSet, *state.Store) error
@@ -1207,6 +1341,16 @@ func (s *Server) consistentRead() error {
 	return structs.ErrNotReadyForConsistentReads
 }
 
+// forwardPageIfBehind makes a stale server that hasn't applied the log up to
+// the index of the first page of a paginated query yet forward the next pages
+// to the leader, rather than serve them from an older state than the first
+// page or reject them.
+func (s *Server) forwardPageIfBehind(q *structs.QueryOptions, page structs.PageOptions) {
+	if q.AllowStale && page.Cursor != "" && s.raft.AppliedIndex() < page.CursorIndex {
+		q.AllowStale = false
+	}
+}
+
 // rpcQueryTimeout calculates the timeout for the query, ensures it is
 // constrained to the configured limit, and adds jitter to prevent multiple
 // blocking queries from all timing out at the same time.
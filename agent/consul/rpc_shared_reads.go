@@ -0,0 +1,144 @@
+package consul
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+)
+
+// sharedReadTTL bounds how long the result of a read is kept for. A result is
+// only found to be outdated by the next query making the same read, so this
+// releases the results of the queries which stopped being made.
+const sharedReadTTL = 10 * time.Minute
+
+// sharedReads shares the results of the identical reads of the state store
+// made by the blocking queries, for as long as the data they were read from
+// doesn't change. When a write wakes up many blocking queries watching the
+// same data, the first one to run reads it again and the others reuse its
+// result instead of reading it too.
+type sharedReads struct {
+	lock  sync.Mutex
+	reads map[string]*sharedRead
+}
+
+// sharedRead is the result of a read. It is immutable once done is closed,
+// the readers which change the result must copy it first.
+type sharedRead struct {
+	// done is closed once the read completed.
+	done chan struct{}
+
+	// invalidated is closed once the data the result was read from was found
+	// to have changed, or the result expired.
+	invalidated chan struct{}
+
+	// expiry invalidates the result once it expired. It is stopped when the
+	// result is invalidated before.
+	expiry *time.Timer
+
+	watches memdb.WatchSet
+	result  interface{}
+	err     error
+}
+
+func newSharedReads() *sharedReads {
+	return &sharedReads{
+		reads: make(map[string]*sharedRead),
+	}
+}
+
+// read returns the result of the read identified by the key, which is shared
+// with the other queries making the same read, and adds the watches of the
+// read to the watch set of the query. The reads with an empty key, which can't
+// be told apart from the others, aren't shared.
+func (r *sharedReads) read(key string, store *state.Store, ws memdb.WatchSet, fn func(ws memdb.WatchSet) (interface{}, error)) (interface{}, error) {
+	if key == "" {
+		return fn(ws)
+	}
+
+	r.lock.Lock()
+	sr, ok := r.reads[key]
+	if ok {
+		select {
+		case <-sr.invalidated:
+			ok = false
+		default:
+		}
+	}
+	if ok {
+		r.lock.Unlock()
+		<-sr.done
+
+		// The data may have changed since the result was read, and the query
+		// must not miss a write which happened before it.
+		if sr.changed() {
+			r.invalidate(key, sr)
+			return r.read(key, store, ws, fn)
+		}
+		metrics.IncrCounter([]string{"rpc", "query", "shared"}, 1)
+	} else {
+		sr = &sharedRead{
+			done:        make(chan struct{}),
+			invalidated: make(chan struct{}),
+			watches:     memdb.NewWatchSet(),
+		}
+		r.reads[key] = sr
+		r.lock.Unlock()
+
+		// The result is also invalidated when the state store is replaced by
+		// the restore of a snapshot.
+		sr.watches.Add(store.AbandonCh())
+		sr.result, sr.err = fn(sr.watches)
+		if sr.err == nil {
+			r.lock.Lock()
+			sr.expiry = time.AfterFunc(sharedReadTTL, func() {
+				r.invalidate(key, sr)
+			})
+			r.lock.Unlock()
+		}
+		close(sr.done)
+
+		if sr.err != nil {
+			r.invalidate(key, sr)
+		}
+	}
+
+	for ch := range sr.watches {
+		ws.Add(ch)
+	}
+	return sr.result, sr.err
+}
+
+// changed returns true if the data the result was read from changed. It must
+// only be called once the read completed.
+func (sr *sharedRead) changed() bool {
+	for ch := range sr.watches {
+		select {
+		case <-ch:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+func (r *sharedReads) invalidate(key string, sr *sharedRead) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-sr.invalidated:
+		// Already invalidated by a query which found the data changed.
+	default:
+		close(sr.invalidated)
+		if sr.expiry != nil {
+			sr.expiry.Stop()
+		}
+	}
+	if r.reads[key] == sr {
+		delete(r.reads, key)
+	}
+}
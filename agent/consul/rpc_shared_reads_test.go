@@ -0,0 +1,84 @@
+package consul
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestSharedReads(t *testing.T) {
+	r := newSharedReads()
+
+	store := state.NewStateStore(nil)
+	require.NoError(t, store.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+
+	var reads int
+	read := func() (uint64, error) {
+		ws := memdb.NewWatchSet()
+		result, err := r.read("nodes", store, ws, func(ws memdb.WatchSet) (interface{}, error) {
+			reads++
+			idx, _, err := store.Nodes(ws, nil, "")
+			return idx, err
+		})
+		if err != nil {
+			return 0, err
+		}
+		// The watches of the read are added to the watch set of the query.
+		require.NotEmpty(t, ws)
+		return result.(uint64), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		idx, err := read()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), idx)
+	}
+	require.Equal(t, 1, reads)
+
+	// A write invalidates the shared result, and a read made right after it
+	// observes it without waiting for the result to expire.
+	for i := uint64(2); i < 10; i++ {
+		require.NoError(t, store.EnsureNode(i, &structs.Node{Node: fmt.Sprintf("node-%d", i), Address: "127.0.0.2"}))
+		idx, err := read()
+		require.NoError(t, err)
+		require.Equal(t, i, idx)
+	}
+	require.Equal(t, 9, reads)
+
+	// Reads without a key aren't shared.
+	for i := 0; i < 3; i++ {
+		_, err := r.read("", store, memdb.NewWatchSet(), func(ws memdb.WatchSet) (interface{}, error) {
+			reads++
+			return nil, nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 12, reads)
+}
+
+func TestSharedReads_Invalidate(t *testing.T) {
+	r := newSharedReads()
+	store := state.NewStateStore(nil)
+
+	_, err := r.read("nodes", store, memdb.NewWatchSet(), func(ws memdb.WatchSet) (interface{}, error) {
+		_, _, err := store.Nodes(ws, nil, "")
+		return nil, err
+	})
+	require.NoError(t, err)
+
+	r.lock.Lock()
+	sr := r.reads["nodes"]
+	r.lock.Unlock()
+	require.NotNil(t, sr)
+	require.NotNil(t, sr.expiry)
+
+	// Invalidating the result releases it and stops its expiry timer.
+	r.invalidate("nodes", sr)
+	require.False(t, sr.expiry.Stop())
+	require.Empty(t, r.reads)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/raft"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
@@ -516,6 +517,63 @@ func TestRPC_ReadyForConsistentReads(t *testing.T) {
 	})
 }
 
+func TestRPC_CanCompressRaftLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	compressLogs := func(c *Config) {
+		c.RaftCompression.LogThreshold = 1
+		c.RaftCompression.Snapshots = true
+		c.RaftCompression.Level = "default"
+	}
+
+	dir1, s1 := testServerWithConfig(t, compressLogs)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, compressLogs, func(c *Config) {
+		c.Bootstrap = false
+		c.OverrideInitialSerfTags = func(tags map[string]string) {
+			delete(tags, "ft_rc")
+		}
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	retry.Run(t, func(r *retry.R) {
+		if ok, _ := ServersInDCSupportFeature(s1, "dc1", "rc"); ok {
+			r.Fatal("expected the older server to be known")
+		}
+	})
+
+	// The older server can't decompress the log entries nor the snapshots.
+	require.False(t, s1.canCompressRaftLogs())
+	require.False(t, s1.serversSupportRaftCompression())
+
+	dir3, s3 := testServerWithConfig(t, compressLogs)
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+	testrpc.WaitForLeader(t, s3.RPC, "dc1")
+
+	require.True(t, s3.canCompressRaftLogs())
+
+	// The compressed log entries are applied.
+	_, err := s3.raftApply(structs.KVSRequestType, &structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt:     structs.DirEntry{Key: "foo", Value: []byte("bar")},
+	})
+	require.NoError(t, err)
+	_, entry, err := s3.fsm.State().KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, []byte("bar"), entry.Value)
+}
+
 func TestRPC_MagicByteTimeout(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1078,6 +1136,69 @@ func TestRPC_LocalTokenStrippedOnForward(t *testing.T) {
 	require.Equal(t, localToken2.SecretID, arg.WriteRequest.Token, "token should not be stripped")
 }
 
+func TestRPC_TokenRateLimitedOnceOnForward(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+		c.ACLInitialManagementToken = "root"
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	_, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+		c.ACLTokenReplication = true
+		c.ACLReplicationRate = 100
+		c.ACLReplicationBurst = 100
+		c.ACLReplicationApplyLimit = 1000000
+	})
+	s2.tokens.UpdateReplicationToken("root", tokenStore.TokenSourceConfig)
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+
+	joinWAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc2")
+	waitForNewACLReplication(t, s2, structs.ACLReplicateTokens, 1, 1, 0)
+
+	// The limiters of the servers only see the per-token checks once they are
+	// swapped after the servers started.
+	limiters := make(map[*Server]*rpcRate.MockRequestLimitsHandler)
+	for _, s := range []*Server{s1, s2} {
+		limiter := rpcRate.NewMockRequestLimitsHandler(t)
+		limiter.On("Allow", mock.Anything).Return(nil).Maybe()
+		s.incomingRPCLimiter = limiter
+		s.tokenRateLimited.Store(true)
+		limiters[s] = limiter
+	}
+	checks := func(s *Server) int {
+		var n int
+		for _, call := range limiters[s].Calls {
+			if call.Arguments.Get(0).(rpcRate.Operation).Name == "Catalog.ListNodes" {
+				n++
+			}
+		}
+		return n
+	}
+
+	args := structs.DCSpecificRequest{
+		Datacenter:   "dc2",
+		QueryOptions: structs.QueryOptions{Token: "root"},
+	}
+	var out structs.IndexedNodes
+	require.NoError(t, s1.RPC(context.Background(), "Catalog.ListNodes", &args, &out))
+
+	// Only the server of dc2 handled the request.
+	require.Equal(t, 0, checks(s1))
+	require.Equal(t, 1, checks(s2))
+}
+
 func TestRPC_LocalTokenStrippedOnForward_GRPC(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1674,6 +1795,60 @@ func TestGetWaitTime(t *testing.T) {
 	}
 }
 
+func TestRaftApplyBatchSize(t *testing.T) {
+	type testCase struct {
+		name     string
+		msg      interface{}
+		expected int
+	}
+
+	run := func(t *testing.T, tc testCase) {
+		require.Equal(t, tc.expected, raftApplyBatchSize(tc.msg))
+	}
+
+	var testCases = []testCase{
+		{
+			name:     "not a batch",
+			msg:      &structs.RegisterRequest{Node: "node1"},
+			expected: 1,
+		},
+		{
+			name: "catalog batch",
+			msg: &structs.CatalogBatchRequest{
+				Register:   []*structs.RegisterRequest{{Node: "node1"}, {Node: "node2"}},
+				Deregister: []*structs.DeregisterRequest{{Node: "node3"}},
+			},
+			expected: 3,
+		},
+		{
+			name:     "txn",
+			msg:      &structs.TxnRequest{Ops: structs.TxnOps{{}, {}}},
+			expected: 2,
+		},
+		{
+			name:     "coordinates",
+			msg:      structs.Coordinates{{Node: "node1"}, {Node: "node2"}},
+			expected: 2,
+		},
+		{
+			name:     "acl token batch delete",
+			msg:      &structs.ACLTokenBatchDeleteRequest{TokenIDs: []string{"a", "b", "c", "d"}},
+			expected: 4,
+		},
+		{
+			name:     "empty batch",
+			msg:      &structs.ACLPolicyBatchSetRequest{},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
 func doRaftRPC(conn net.Conn, leader string) (raft.AppendEntriesResponse, error) {
 	var resp raft.AppendEntriesResponse
 
@@ -27,6 +27,7 @@ import (
 	walmetrics "github.com/hashicorp/raft-wal/metrics"
 	"github.com/hashicorp/raft-wal/verifier"
 	"github.com/hashicorp/serf/serf"
+	"github.com/klauspost/compress/zstd"
 	"go.etcd.io/bbolt"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
@@ -47,6 +48,7 @@ import (
 	aclgrpc "github.com/hashicorp/consul/agent/grpc-external/services/acl"
 	"github.com/hashicorp/consul/agent/grpc-external/services/connectca"
 	"github.com/hashicorp/consul/agent/grpc-external/services/dataplane"
+	"github.com/hashicorp/consul/agent/grpc-external/services/eventstream"
 	"github.com/hashicorp/consul/agent/grpc-external/services/peerstream"
 	"github.com/hashicorp/consul/agent/grpc-external/services/serverdiscovery"
 	agentgrpc "github.com/hashicorp/consul/agent/grpc-internal"
@@ -121,6 +123,11 @@ const (
 	aclRoleReplicationRoutineName         = "ACL role replication"
 	aclTokenReplicationRoutineName        = "ACL token replication"
 	aclTokenReapingRoutineName            = "acl token reaping"
+	aclTokenUsageFlushRoutineName         = "acl token usage flush"
+	kvsReapingRoutineName                 = "kv expiration reaping"
+	keyringRotationRoutineName            = "gossip keyring rotation"
+	externalHealthChecksRoutineName       = "external health checks"
+	changeSinksRoutineName                = "change sinks"
 	caRootPruningRoutineName              = "CA root pruning"
 	caRootMetricRoutineName               = "CA root expiration metric"
 	caSigningMetricRoutineName            = "CA signing expiration metric"
@@ -175,6 +182,10 @@ type Server struct {
 
 	aclAuthMethodValidators authmethod.Cache
 
+	// clientCertTokens caches the tokens created for the client certificates
+	// presented to the HTTPS and external gRPC APIs.
+	clientCertTokens *clientCertTokenCache
+
 	// autopilot is the Autopilot instance for this server.
 	autopilot *autopilot.Autopilot
 
@@ -230,6 +241,36 @@ type Server struct {
 	// strong consistency.
 	fsm *fsm.FSM
 
+	// raftLogCompressor compresses the raft log entries from the
+	// configured threshold. It is nil when they aren't compressed.
+	raftLogCompressor *zstd.Encoder
+
+	// raftCompressionReady is set once every server of the datacenter can
+	// decompress the raft log entries and snapshots.
+	raftCompressionReady atomic.Bool
+
+	// kvCompressionReady is set once every server of the datacenter can
+	// decompress the KV values.
+	kvCompressionReady atomic.Bool
+
+	// kvPatchReady is set once every server of the datacenter can apply the
+	// KV patch operations.
+	kvPatchReady atomic.Bool
+
+	// catalogBatchReady is set once every server of the datacenter can apply
+	// the catalog batches.
+	catalogBatchReady atomic.Bool
+
+	// aclTokenBulkReady is set once every server of the datacenter can apply
+	// the bulk token operations.
+	aclTokenBulkReady atomic.Bool
+
+	// aclTokenUsages holds the token usages reported to the leader that
+	// weren't written to Raft yet, keyed by accessor ID. Only the latest use
+	// of each token is kept.
+	aclTokenUsages     map[string]*structs.ACLTokenUsage
+	aclTokenUsagesLock sync.Mutex
+
 	// Logger uses the provided LogOutput
 	logger  hclog.InterceptLogger
 	loggers *loggerStore
@@ -284,6 +325,13 @@ type Server struct {
 	// from an agent.
 	rpcLimiter atomic.Value
 
+	// sharedReads shares the results of the identical reads of the blocking
+	// queries.
+	sharedReads *sharedReads
+
+	// intentionDecisions caches the decisions of the intention checks.
+	intentionDecisions *intentionDecisionCache
+
 	// rpcConnLimiter limits the number of RPC connections from a single source IP
 	rpcConnLimiter connlimit.Limiter
 
@@ -295,6 +343,10 @@ type Server struct {
 	// incomingRPCLimiter rate-limits incoming net/rpc and gRPC calls.
 	incomingRPCLimiter rpcRate.RequestLimitsHandler
 
+	// tokenRateLimited is whether the incoming RPCs are rate-limited per ACL
+	// token, in which case their token is resolved before handling them.
+	tokenRateLimited atomic.Bool
+
 	// insecureRPCServer is a RPC server that is configure with
 	// IncomingInsecureRPCConfig to allow clients to call AutoEncrypt.Sign
 	// to request client certificates. At this point a client doesn't have
@@ -320,6 +372,10 @@ type Server struct {
 	//
 	serfLAN *serf.Serf
 
+	// gossipHealth records the failures and recoveries of the members of
+	// serfLAN.
+	gossipHealth *gossipHealthTracker
+
 	// serfWAN is the Serf cluster maintained between DC's
 	// which SHOULD only consist of Consul servers
 	serfWAN                *serf.Serf
@@ -344,6 +400,10 @@ type Server struct {
 	// destroy the session via standard session destroy processing
 	sessionTimers *SessionTimers
 
+	// lockTracker follows the contention of the locks and semaphores built
+	// on the KV store.
+	lockTracker *lockTracker
+
 	// statsFetcher is used by autopilot to check the status of the other
 	// Consul router.
 	statsFetcher *StatsFetcher
@@ -352,6 +412,10 @@ type Server struct {
 	// and emit node/service/check health metrics.
 	overviewManager *OverviewManager
 
+	// usageHistory keeps the daily samples of the service usage reported by
+	// the Operator.Usage endpoint.
+	usageHistory *usagemetrics.History
+
 	// reassertLeaderCh is used to signal the leader loop should re-run
 	// leadership actions after a snapshot restore.
 	reassertLeaderCh chan chan error
@@ -360,6 +424,10 @@ type Server struct {
 	// for the KV tombstones
 	tombstoneGC *state.TombstoneGC
 
+	// serviceTombstoneGC is used to track the pending GC invocations
+	// for the service tombstones
+	serviceTombstoneGC *state.TombstoneGC
+
 	// aclReplicationStatus (and its associated lock) provide information
 	// about the health of the ACL replication goroutine.
 	aclReplicationStatus     structs.ACLReplicationStatus
@@ -436,6 +504,10 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 	if err != nil {
 		return nil, err
 	}
+	serviceGC, err := state.NewTombstoneGC(config.ServiceTombstoneTTL, config.TombstoneTTLGranularity)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the shutdown channel - this is closed but never written to.
 	shutdownCh := make(chan struct{})
@@ -445,11 +517,32 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 	fsmDeps := fsm.Deps{
 		Logger: flat.Logger,
 		NewStateStore: func() *state.Store {
-			return state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			store := state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			store.SetServiceTombstoneGC(serviceGC)
+			return store
 		},
 		Publisher: flat.EventPublisher,
 	}
 
+	// The log entries are compressed when they are applied, and the snapshots
+	// when the FSM persists them.
+	var raftLogCompressor *zstd.Encoder
+	if compression := config.RaftCompression; compression.LogThreshold > 0 || compression.Snapshots {
+		level, err := compression.EncoderLevel()
+		if err != nil {
+			return nil, err
+		}
+		if compression.LogThreshold > 0 {
+			raftLogCompressor, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if compression.Snapshots {
+			fsmDeps.SnapshotCompressionLevel = level
+		}
+	}
+
 	if incomingRPCLimiter == nil {
 		incomingRPCLimiter = rpcRate.NullRequestLimitsHandler()
 	}
@@ -462,6 +555,7 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 		grpcConnPool:            flat.GRPCConnPool,
 		eventChLAN:              make(chan serf.Event, serfEventChSize),
 		eventChWAN:              make(chan serf.Event, serfEventChSize),
+		gossipHealth:            newGossipHealthTracker(),
 		logger:                  serverLogger,
 		loggers:                 loggers,
 		leaveCh:                 make(chan struct{}),
@@ -471,18 +565,30 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 		externalGRPCServer:      externalGRPCServer,
 		reassertLeaderCh:        make(chan chan error),
 		sessionTimers:           NewSessionTimers(),
+		lockTracker:             newLockTracker(),
 		tombstoneGC:             gc,
+		serviceTombstoneGC:      serviceGC,
 		serverLookup:            NewServerLookup(),
 		shutdownCh:              shutdownCh,
+		sharedReads:             newSharedReads(),
+		intentionDecisions:      newIntentionDecisionCache(),
 		leaderRoutineManager:    routine.NewManager(logger.Named(logging.Leader)),
 		aclAuthMethodValidators: authmethod.NewCache(),
-		fsm:                     fsm.NewFromDeps(fsmDeps),
+		clientCertTokens:        newClientCertTokenCache(),
+		raftLogCompressor:       raftLogCompressor,
 		publisher:               flat.EventPublisher,
 		incomingRPCLimiter:      incomingRPCLimiter,
 	}
 
 	incomingRPCLimiter.Register(s)
 
+	// The snapshots are only compressed once every server can restore them.
+	if fsmDeps.SnapshotCompressionLevel != 0 {
+		fsmDeps.CanCompressSnapshots = s.serversSupportRaftCompression
+	}
+	s.fsm = fsm.NewFromDeps(fsmDeps)
+	s.tokenRateLimited.Store(isTokenRateLimited(convertConsulConfigToRateLimitHandlerConfig(*requestLimitsFromConfig(config), nil)))
+
 	s.hcpManager = hcp.NewManager(hcp.ManagerConfig{
 		Client:   flat.HCP.Client,
 		StatusFn: s.hcpServerStatus(flat),
@@ -498,6 +604,7 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 	if recorder == nil {
 		return nil, fmt.Errorf("cannot initialize server with a nil RPC request recorder")
 	}
+	recorder.SetConsumerLabels(config.RPCConsumerLabels)
 
 	rpcServerOpts := []func(*rpc.Server){
 		rpc.WithPreBodyInterceptor(middleware.GetNetRPCRateLimitingInterceptor(s.incomingRPCLimiter, middleware.NewPanicHandler(s.logger))),
@@ -725,6 +832,13 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 	}
 	go reporter.Run(&lib.StopChannelContext{StopCh: s.shutdownCh})
 
+	var usageHistoryPath string
+	if s.config.DataDir != "" {
+		usageHistoryPath = filepath.Join(s.config.DataDir, usagemetrics.HistoryFile)
+	}
+	s.usageHistory = usagemetrics.NewHistory(s.logger, s.fsm, usageHistoryPath)
+	go s.usageHistory.Run(&lib.StopChannelContext{StopCh: s.shutdownCh})
+
 	s.overviewManager = NewOverviewManager(s.logger, s.fsm, s.config.MetricsReportingInterval)
 	go s.overviewManager.Run(&lib.StopChannelContext{StopCh: s.shutdownCh})
 
@@ -773,6 +887,15 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 		Logger:      logger.Named("grpc-api.server-discovery"),
 	}).Register(s.externalGRPCServer)
 
+	if s.config.RPCConfig.EnableStreaming {
+		eventstream.NewServer(eventstream.Config{
+			Subscriber: subscribe.NewServer(
+				&subscribeBackend{srv: s, connPool: s.grpcConnPool},
+				logger.Named("grpc-api.subscription")),
+			Logger: logger.Named("grpc-api.event-stream"),
+		}).Register(s.externalGRPCServer)
+	}
+
 	s.peeringBackend = NewPeeringBackend(s)
 	s.operatorBackend = NewOperatorBackend(s)
 	s.peerStreamServer = peerstream.NewServer(peerstream.Config{
@@ -789,6 +912,8 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 			}
 			return s.ForwardGRPC(s.grpcConnPool, info, fn)
 		},
+		IncomingMessageRateLimit: s.config.PeeringIncomingMessageRate,
+		IncomingMessageBurst:     s.config.PeeringIncomingMessageBurst,
 	})
 	s.peerStreamServer.Register(s.externalGRPCServer)
 
@@ -834,6 +959,7 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 
 	// Start the metrics handlers.
 	go s.updateMetrics()
+	go monitorGossipProfile(s.logger, s.serfLAN, config.GossipLANProfile, s.shutdownCh)
 
 	// Now we are setup, configure the HCP manager
 	go s.hcpManager.Run(&lib.StopChannelContext{StopCh: shutdownCh})
@@ -975,6 +1101,10 @@ func (s *Server) setupRaft() error {
 			return err
 		}
 
+		if err := applyCanaryFailback(path, s.logger.Named("raft.logstore.canary")); err != nil {
+			return err
+		}
+
 		boltDBFile := filepath.Join(path, "raft.db")
 		boltFileExists, err := fileExists(boltDBFile)
 		if err != nil {
@@ -1001,6 +1131,29 @@ func (s *Server) setupRaft() error {
 			s.raftStore = wal
 			log = wal
 			stable = wal
+
+			// In canary mode, the WAL is verified against a BoltDB shadow copy.
+			if canary := s.config.LogStoreConfig.WAL.Canary; canary.Enabled {
+				shadow, err := raftboltdb.New(raftboltdb.Options{
+					BoltOptions: &bbolt.Options{
+						NoFreelistSync: s.config.LogStoreConfig.BoltDB.NoFreelistSync,
+					},
+					Path: filepath.Join(path, canaryShadowFile),
+				})
+				if err != nil {
+					return fmt.Errorf("fail to open the shadow copy of the write-ahead-log: %w", err)
+				}
+				store, err := newCanaryLogStore(wal, shadow, canary.Failback, canary.VerifyInterval,
+					filepath.Join(path, canaryFailbackFile), s.logger.Named("raft.logstore.canary"))
+				if err != nil {
+					return err
+				}
+				s.raftStore = store
+				log = store
+				stable = store
+
+				go store.run(&lib.StopChannelContext{StopCh: s.shutdownCh})
+			}
 		} else {
 			if s.config.LogStoreConfig.Backend == LogStoreBackendWAL {
 				// User configured the new storage, but still has old raft.db. Warn
@@ -1087,7 +1240,9 @@ func (s *Server) setupRaft() error {
 			tmpFsm := fsm.NewFromDeps(fsm.Deps{
 				Logger: s.logger,
 				NewStateStore: func() *state.Store {
-					return state.NewStateStore(s.tombstoneGC)
+					store := state.NewStateStore(s.tombstoneGC)
+					store.SetServiceTombstoneGC(s.serviceTombstoneGC)
+					return store
 				},
 			})
 			if err := raft.RecoverCluster(s.config.RaftConfig, tmpFsm,
@@ -1474,6 +1629,12 @@ func (s *Server) LANMembersInAgentPartition() []serf.Member {
 	return s.serfLAN.Members()
 }
 
+// GossipHealth returns the health of the LAN gossip pool of the default
+// segment and partition as seen from the server.
+func (s *Server) GossipHealth() GossipHealth {
+	return s.gossipHealth.health(s.serfLAN)
+}
+
 // WANMembers is used to return the members of the WAN cluster
 func (s *Server) WANMembers() []serf.Member {
 	if s.serfWAN == nil {
@@ -1524,6 +1685,35 @@ func (s *Server) IsLeader() bool {
 	return s.raft.State() == raft.Leader
 }
 
+// IsServer checks if the address is the one of a server, in this datacenter
+// or another one.
+func (s *Server) IsServer(addr net.Addr) bool {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	var found bool
+	match := func(srv *metadata.Server) bool {
+		if tcpAddr, ok := srv.Addr.(*net.TCPAddr); ok && tcpAddr.IP.Equal(ip) {
+			found = true
+		}
+		return !found
+	}
+	s.serverLookup.CheckServers(match)
+	for _, dc := range s.router.GetDatacenters() {
+		if found {
+			break
+		}
+		s.router.CheckServers(dc, match)
+	}
+	return found
+}
+
 // LeaderLastContact returns the time of last contact by a leader.
 // This only makes sense if we are currently a follower.
 func (s *Server) LeaderLastContact() time.Time {
@@ -1749,13 +1939,16 @@ func (s *Server) ReloadConfig(config ReloadableConfig) error {
 	s.rpcLimiter.Store(rate.NewLimiter(config.RPCRateLimit, config.RPCMaxBurst))
 
 	if config.RequestLimits != nil {
-		s.incomingRPCLimiter.UpdateConfig(*convertConsulConfigToRateLimitHandlerConfig(*config.RequestLimits, nil))
+		hc := convertConsulConfigToRateLimitHandlerConfig(*config.RequestLimits, nil)
+		s.incomingRPCLimiter.UpdateConfig(*hc)
+		s.tokenRateLimited.Store(isTokenRateLimited(hc))
 	}
 
 	s.rpcConnLimiter.SetConfig(connlimit.Config{
 		MaxConnsPerClientIP: config.RPCMaxConnsPerClient,
 	})
 	s.connPool.SetRPCClientTimeout(config.RPCClientTimeout)
+	s.rpcRecorder.SetConsumerLabels(config.RPCConsumerLabels)
 
 	if s.IsLeader() {
 		// only bootstrap the config entries if we are the leader
@@ -1918,11 +2111,7 @@ func fileExists(name string) (bool, error) {
 
 func ConfiguredIncomingRPCLimiter(ctx context.Context, serverLogger hclog.InterceptLogger, consulCfg *Config) *rpcRate.Handler {
 	mlCfg := &multilimiter.Config{ReconcileCheckLimit: 30 * time.Second, ReconcileCheckInterval: time.Second}
-	limitsConfig := &RequestLimits{
-		Mode:      rpcRate.RequestLimitsModeFromNameWithDefault(consulCfg.RequestLimitsMode),
-		ReadRate:  consulCfg.RequestLimitsReadRate,
-		WriteRate: consulCfg.RequestLimitsWriteRate,
-	}
+	limitsConfig := requestLimitsFromConfig(consulCfg)
 
 	sink := logdrop.NewLogDropSink(ctx, 100, serverLogger.Named("rpc-rate-limit"), func(l logdrop.Log) {
 		metrics.IncrCounter([]string{"rpc", "rate_limit", "log_dropped"}, 1)
@@ -1935,6 +2124,26 @@ func ConfiguredIncomingRPCLimiter(ctx context.Context, serverLogger hclog.Interc
 	return rpcRate.NewHandler(*rateLimiterConfig, logger)
 }
 
+func requestLimitsFromConfig(consulCfg *Config) *RequestLimits {
+	return &RequestLimits{
+		Mode:           rpcRate.RequestLimitsModeFromNameWithDefault(consulCfg.RequestLimitsMode),
+		ReadRate:       consulCfg.RequestLimitsReadRate,
+		WriteRate:      consulCfg.RequestLimitsWriteRate,
+		IPReadRate:     consulCfg.RequestLimitsIPReadRate,
+		IPWriteRate:    consulCfg.RequestLimitsIPWriteRate,
+		IPOverrides:    consulCfg.RequestLimitsIPOverrides,
+		TokenReadRate:  consulCfg.RequestLimitsTokenReadRate,
+		TokenWriteRate: consulCfg.RequestLimitsTokenWriteRate,
+		TokenOverrides: consulCfg.RequestLimitsTokenOverrides,
+	}
+}
+
+// isTokenRateLimited returns whether the RPCs must be checked against the
+// per-token limits, which requires resolving their token first.
+func isTokenRateLimited(hc *rpcRate.HandlerConfig) bool {
+	return hc.GlobalMode != rpcRate.ModeDisabled && hc.TokenConfig != nil
+}
+
 func convertConsulConfigToRateLimitHandlerConfig(limitsConfig RequestLimits, multilimiterConfig *multilimiter.Config) *rpcRate.HandlerConfig {
 	hc := &rpcRate.HandlerConfig{
 		GlobalMode: limitsConfig.Mode,
@@ -1947,6 +2156,28 @@ func convertConsulConfigToRateLimitHandlerConfig(limitsConfig RequestLimits, mul
 			Burst: int(limitsConfig.WriteRate) * requestLimitsBurstMultiplier,
 		},
 	}
+	if limitsConfig.IPReadRate != rate.Inf || limitsConfig.IPWriteRate != rate.Inf || len(limitsConfig.IPOverrides) > 0 {
+		hc.IPConfig = &rpcRate.IPLimitsConfig{
+			ReadWriteConfig: requestLimitsReadWriteConfig(limitsConfig.IPReadRate, limitsConfig.IPWriteRate),
+		}
+		for _, o := range limitsConfig.IPOverrides {
+			hc.IPConfig.Overrides = append(hc.IPConfig.Overrides, rpcRate.IPLimitsOverride{
+				CIDR:            o.CIDR,
+				ReadWriteConfig: requestLimitsReadWriteConfig(o.ReadRate, o.WriteRate),
+			})
+		}
+	}
+	if limitsConfig.TokenReadRate != rate.Inf || limitsConfig.TokenWriteRate != rate.Inf || len(limitsConfig.TokenOverrides) > 0 {
+		hc.TokenConfig = &rpcRate.TokenLimitsConfig{
+			ReadWriteConfig: requestLimitsReadWriteConfig(limitsConfig.TokenReadRate, limitsConfig.TokenWriteRate),
+		}
+		for _, o := range limitsConfig.TokenOverrides {
+			if hc.TokenConfig.Overrides == nil {
+				hc.TokenConfig.Overrides = make(map[string]rpcRate.ReadWriteConfig)
+			}
+			hc.TokenConfig.Overrides[o.AccessorID] = requestLimitsReadWriteConfig(o.ReadRate, o.WriteRate)
+		}
+	}
 	if multilimiterConfig != nil {
 		hc.Config = *multilimiterConfig
 	}
@@ -1954,6 +2185,19 @@ func convertConsulConfigToRateLimitHandlerConfig(limitsConfig RequestLimits, mul
 	return hc
 }
 
+func requestLimitsReadWriteConfig(readRate, writeRate rate.Limit) rpcRate.ReadWriteConfig {
+	return rpcRate.ReadWriteConfig{
+		ReadConfig: multilimiter.LimiterConfig{
+			Rate:  readRate,
+			Burst: int(readRate) * requestLimitsBurstMultiplier,
+		},
+		WriteConfig: multilimiter.LimiterConfig{
+			Rate:  writeRate,
+			Burst: int(writeRate) * requestLimitsBurstMultiplier,
+		},
+	}
+}
+
 // peersInfoContent is used to help operators understand what happened to the
 // peers.json file. This is written to a file called peers.info in the same
 // location.
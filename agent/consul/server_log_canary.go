@@ -0,0 +1,429 @@
+package consul
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/raft"
+)
+
+var LogStoreCanaryCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"raft", "logstore", "canary", "divergence"},
+		Help: "Increments when the WAL log store in canary mode is found to diverge from its BoltDB shadow copy.",
+	},
+}
+
+var LogStoreCanaryGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"raft", "logstore", "canary", "failed_back"},
+		Help: "Is 1 once the WAL log store in canary mode failed back to its BoltDB shadow copy, 0 otherwise.",
+	},
+	{
+		Name: []string{"raft", "logstore", "canary", "verified_index"},
+		Help: "The index up to which the WAL log store in canary mode was verified against its BoltDB shadow copy.",
+	},
+}
+
+const (
+	// canaryShadowFile is the BoltDB shadow copy of the WAL, in the raft
+	// directory. It must not be named raft.db, whose presence disables the WAL.
+	canaryShadowFile = "wal-shadow.db"
+
+	// canaryFailbackFile is the marker left in the raft directory after a
+	// failback, so that the shadow copy replaces the WAL on the next start.
+	canaryFailbackFile = "wal-failback"
+
+	// canaryVerifyBatch is the number of logs compared while holding the
+	// lock of the store, which blocks the writes.
+	canaryVerifyBatch = 256
+)
+
+// canaryStableUint64Keys and canaryStableKeys are the keys raft writes to its
+// stable store with SetUint64 and Set respectively, which are copied to the
+// shadow when it is seeded.
+var (
+	canaryStableUint64Keys = [][]byte{
+		[]byte("CurrentTerm"),
+		[]byte("LastVoteTerm"),
+	}
+	canaryStableKeys = [][]byte{
+		[]byte("LastVoteCand"),
+	}
+)
+
+// raftLogStableStore is a raft log store which is also its stable store, as
+// the WAL and BoltDB stores are.
+type raftLogStableStore interface {
+	raftStore
+	raft.StableStore
+}
+
+// canaryLogStore is the raft log and stable store of a server running the
+// WAL in canary mode. It writes to both the WAL and a BoltDB shadow copy, and
+// serves from the WAL until its logs are found to diverge from the shadow
+// copy, from which it serves from then on when failback is enabled.
+type canaryLogStore struct {
+	wal      raftLogStableStore
+	shadow   raftLogStableStore
+	failback bool
+	interval time.Duration
+	// failbackPath is the path of the marker of the failback.
+	failbackPath string
+	logger       hclog.Logger
+
+	// lock is held for writing by the writes to the stores, and for reading
+	// while comparing the stores so that the logs don't change midway.
+	lock sync.RWMutex
+	// failedBack is set once the store serves from the shadow copy only.
+	failedBack bool
+	// verifiedIndex is the last index verified, which is only accessed by
+	// the verification.
+	verifiedIndex uint64
+}
+
+// newCanaryLogStore returns the canary store of the WAL, with the shadow copy
+// seeded from the WAL if they don't hold the same logs, for example when the
+// canary mode is enabled on a server which has been running the WAL.
+func newCanaryLogStore(wal, shadow raftLogStableStore, failback bool, interval time.Duration, failbackPath string, logger hclog.Logger) (*canaryLogStore, error) {
+	c := &canaryLogStore{
+		wal:          wal,
+		shadow:       shadow,
+		failback:     failback,
+		interval:     interval,
+		failbackPath: failbackPath,
+		logger:       logger,
+	}
+	if err := c.seed(); err != nil {
+		return nil, fmt.Errorf("failed to seed the shadow copy of the WAL: %w", err)
+	}
+	metrics.SetGauge([]string{"raft", "logstore", "canary", "failed_back"}, 0)
+	return c, nil
+}
+
+func (c *canaryLogStore) seed() error {
+	walFirst, walLast, err := storeRange(c.wal)
+	if err != nil {
+		return err
+	}
+	shadowFirst, shadowLast, err := storeRange(c.shadow)
+	if err != nil {
+		return err
+	}
+	if walFirst == shadowFirst && walLast == shadowLast {
+		return nil
+	}
+
+	c.logger.Info("seeding the shadow copy of the WAL", "first", walFirst, "last", walLast)
+	if shadowLast > 0 {
+		if err := c.shadow.DeleteRange(shadowFirst, shadowLast); err != nil {
+			return err
+		}
+	}
+	for _, key := range canaryStableUint64Keys {
+		val, err := c.wal.GetUint64(key)
+		if err != nil || val == 0 {
+			continue
+		}
+		if err := c.shadow.SetUint64(key, val); err != nil {
+			return err
+		}
+	}
+	for _, key := range canaryStableKeys {
+		val, err := c.wal.Get(key)
+		if err != nil || len(val) == 0 {
+			continue
+		}
+		if err := c.shadow.Set(key, val); err != nil {
+			return err
+		}
+	}
+	if walLast == 0 {
+		return nil
+	}
+
+	batch := make([]*raft.Log, 0, canaryVerifyBatch)
+	for idx := walFirst; idx <= walLast; idx++ {
+		var l raft.Log
+		if err := c.wal.GetLog(idx, &l); err != nil {
+			return err
+		}
+		batch = append(batch, &l)
+		if len(batch) == cap(batch) || idx == walLast {
+			if err := c.shadow.StoreLogs(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	return nil
+}
+
+func storeRange(s raft.LogStore) (uint64, uint64, error) {
+	first, err := s.FirstIndex()
+	if err != nil {
+		return 0, 0, err
+	}
+	last, err := s.LastIndex()
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, last, nil
+}
+
+// active returns the store the reads are served from.
+func (c *canaryLogStore) active() raftLogStableStore {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.failedBack {
+		return c.shadow
+	}
+	return c.wal
+}
+
+// write applies the write to the shadow copy and, unless the store failed
+// back, to the WAL first.
+func (c *canaryLogStore) write(fn func(s raftLogStableStore) error) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.failedBack {
+		if err := fn(c.wal); err != nil {
+			return err
+		}
+	}
+	return fn(c.shadow)
+}
+
+func (c *canaryLogStore) FirstIndex() (uint64, error) {
+	return c.active().FirstIndex()
+}
+
+func (c *canaryLogStore) LastIndex() (uint64, error) {
+	return c.active().LastIndex()
+}
+
+func (c *canaryLogStore) GetLog(index uint64, log *raft.Log) error {
+	return c.active().GetLog(index, log)
+}
+
+func (c *canaryLogStore) StoreLog(log *raft.Log) error {
+	return c.StoreLogs([]*raft.Log{log})
+}
+
+func (c *canaryLogStore) StoreLogs(logs []*raft.Log) error {
+	return c.write(func(s raftLogStableStore) error { return s.StoreLogs(logs) })
+}
+
+func (c *canaryLogStore) DeleteRange(min, max uint64) error {
+	return c.write(func(s raftLogStableStore) error { return s.DeleteRange(min, max) })
+}
+
+func (c *canaryLogStore) Set(key []byte, val []byte) error {
+	return c.write(func(s raftLogStableStore) error { return s.Set(key, val) })
+}
+
+func (c *canaryLogStore) Get(key []byte) ([]byte, error) {
+	return c.active().Get(key)
+}
+
+func (c *canaryLogStore) SetUint64(key []byte, val uint64) error {
+	return c.write(func(s raftLogStableStore) error { return s.SetUint64(key, val) })
+}
+
+func (c *canaryLogStore) GetUint64(key []byte) (uint64, error) {
+	return c.active().GetUint64(key)
+}
+
+func (c *canaryLogStore) Close() error {
+	var merr error
+	if err := c.wal.Close(); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+	if err := c.shadow.Close(); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+	return merr
+}
+
+// run verifies the WAL against the shadow copy every interval, until the
+// context is done or the store failed back.
+func (c *canaryLogStore) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		diverged, err := c.verify()
+		if err != nil {
+			c.logger.Warn("failed to verify the WAL against its shadow copy", "error", err)
+			continue
+		}
+		if diverged && c.failback {
+			return
+		}
+	}
+}
+
+// verify compares the logs written since the last verification in the WAL
+// and in the shadow copy, and handles the first divergence found.
+func (c *canaryLogStore) verify() (bool, error) {
+	c.lock.RLock()
+	failedBack := c.failedBack
+	c.lock.RUnlock()
+	if failedBack {
+		return false, nil
+	}
+
+	walFirst, walLast, err := storeRange(c.wal)
+	if err != nil {
+		return false, err
+	}
+	shadowFirst, shadowLast, err := storeRange(c.shadow)
+	if err != nil {
+		return false, err
+	}
+	start := c.verifiedIndex + 1
+	if first := maxUint64(walFirst, shadowFirst); start < first {
+		start = first
+	}
+	end := minUint64(walLast, shadowLast)
+
+	for start <= end {
+		batchEnd := minUint64(start+canaryVerifyBatch-1, end)
+		idx, err := c.compareRange(start, batchEnd)
+		if err != nil {
+			return false, err
+		}
+		if idx != 0 {
+			c.diverged(idx)
+			return true, nil
+		}
+		c.verifiedIndex = batchEnd
+		start = batchEnd + 1
+	}
+	metrics.SetGauge([]string{"raft", "logstore", "canary", "verified_index"}, float32(c.verifiedIndex))
+	return false, nil
+}
+
+// compareRange returns the first index of the range whose log differs
+// between the WAL and the shadow copy, or 0 if they hold the same logs.
+func (c *canaryLogStore) compareRange(start, end uint64) (uint64, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var walLog, shadowLog raft.Log
+	for idx := start; idx <= end; idx++ {
+		walErr := c.wal.GetLog(idx, &walLog)
+		shadowErr := c.shadow.GetLog(idx, &shadowLog)
+		switch {
+		case walErr == raft.ErrLogNotFound && shadowErr == raft.ErrLogNotFound:
+			// Both were truncated since the range was read.
+			continue
+		case walErr != nil && walErr != raft.ErrLogNotFound:
+			return 0, walErr
+		case shadowErr != nil && shadowErr != raft.ErrLogNotFound:
+			return 0, shadowErr
+		case walErr != nil || shadowErr != nil:
+			// Only one of the stores holds the log.
+			return idx, nil
+		}
+		if logChecksum(&walLog) != logChecksum(&shadowLog) {
+			return idx, nil
+		}
+	}
+	return 0, nil
+}
+
+// diverged reports the divergence of the WAL from the shadow copy at the
+// index, and fails back to the shadow copy if enabled.
+func (c *canaryLogStore) diverged(idx uint64) {
+	metrics.IncrCounter([]string{"raft", "logstore", "canary", "divergence"}, 1)
+	if !c.failback {
+		c.logger.Error("the WAL diverged from its BoltDB shadow copy, failback is disabled so the server keeps serving from the WAL",
+			"index", idx,
+		)
+		return
+	}
+
+	c.lock.Lock()
+	c.failedBack = true
+	c.lock.Unlock()
+	metrics.SetGauge([]string{"raft", "logstore", "canary", "failed_back"}, 1)
+
+	c.logger.Error("the WAL diverged from its BoltDB shadow copy, the server failed back to BoltDB and will use it in place of the WAL from its next start",
+		"index", idx,
+	)
+	if err := os.WriteFile(c.failbackPath, []byte(fmt.Sprintf("diverged at index %d\n", idx)), 0644); err != nil {
+		c.logger.Error("failed to record the failback to BoltDB, the server will use the WAL again from its next start",
+			"path", c.failbackPath,
+			"error", err,
+		)
+	}
+}
+
+// logChecksum returns the checksum of the fields of the log which are stored
+// the same by both stores.
+func logChecksum(l *raft.Log) uint32 {
+	h := crc32.New(castagnoliTable)
+	var buf [17]byte
+	binary.BigEndian.PutUint64(buf[0:8], l.Index)
+	binary.BigEndian.PutUint64(buf[8:16], l.Term)
+	buf[16] = byte(l.Type)
+	h.Write(buf[:])
+	h.Write(l.Data)
+	h.Write(l.Extensions)
+	return h.Sum32()
+}
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// applyCanaryFailback replaces the WAL with its shadow copy in the raft
+// directory if the WAL in canary mode failed back to it, by renaming the
+// shadow copy to raft.db which takes precedence over the WAL.
+func applyCanaryFailback(raftDir string, logger hclog.Logger) error {
+	failbackPath := filepath.Join(raftDir, canaryFailbackFile)
+	if _, err := os.Stat(failbackPath); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	boltDBFile := filepath.Join(raftDir, "raft.db")
+	if exists, err := fileExists(boltDBFile); err != nil {
+		return err
+	} else if !exists {
+		if err := os.Rename(filepath.Join(raftDir, canaryShadowFile), boltDBFile); err != nil {
+			return fmt.Errorf("failed to replace the WAL with its shadow copy after a failback: %w", err)
+		}
+		logger.Warn("replaced the WAL with its BoltDB shadow copy after a failback, the WAL directory can be removed once the cause of the divergence is investigated")
+	}
+	return os.Remove(failbackPath)
+}
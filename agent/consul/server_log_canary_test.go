@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+type inmemRaftStore struct {
+	*raft.InmemStore
+}
+
+func (inmemRaftStore) Close() error {
+	return nil
+}
+
+func canaryTestLogs(first, last uint64, data string) []*raft.Log {
+	var logs []*raft.Log
+	for idx := first; idx <= last; idx++ {
+		logs = append(logs, &raft.Log{Index: idx, Term: 1, Type: raft.LogCommand, Data: []byte(data)})
+	}
+	return logs
+}
+
+func TestCanaryLogStore(t *testing.T) {
+	dir := testutil.TempDir(t, "raft")
+	wal := inmemRaftStore{raft.NewInmemStore()}
+	shadow := inmemRaftStore{raft.NewInmemStore()}
+
+	// The logs already in the WAL are copied to the shadow.
+	require.NoError(t, wal.StoreLogs(canaryTestLogs(1, 300, "foo")))
+	require.NoError(t, wal.SetUint64([]byte("CurrentTerm"), 1))
+
+	failbackPath := filepath.Join(dir, canaryFailbackFile)
+	c, err := newCanaryLogStore(wal, shadow, true, time.Minute, failbackPath, testutil.Logger(t))
+	require.NoError(t, err)
+
+	last, err := shadow.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(300), last)
+	term, err := shadow.GetUint64([]byte("CurrentTerm"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), term)
+
+	require.NoError(t, c.StoreLogs(canaryTestLogs(301, 400, "bar")))
+	diverged, err := c.verify()
+	require.NoError(t, err)
+	require.False(t, diverged)
+	require.Equal(t, uint64(400), c.verifiedIndex)
+
+	// Corrupt the WAL past the verified index.
+	require.NoError(t, c.StoreLogs(canaryTestLogs(401, 410, "baz")))
+	require.NoError(t, wal.StoreLogs(canaryTestLogs(405, 405, "corrupted")))
+
+	diverged, err = c.verify()
+	require.NoError(t, err)
+	require.True(t, diverged)
+	require.Equal(t, uint64(400), c.verifiedIndex)
+	require.FileExists(t, failbackPath)
+
+	// The store serves from the shadow once failed back, which is the only one
+	// still written.
+	var l raft.Log
+	require.NoError(t, c.GetLog(405, &l))
+	require.Equal(t, "baz", string(l.Data))
+
+	require.NoError(t, c.StoreLogs(canaryTestLogs(411, 411, "qux")))
+	last, err = wal.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(410), last)
+	last, err = c.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(411), last)
+}
+
+func TestApplyCanaryFailback(t *testing.T) {
+	dir := testutil.TempDir(t, "raft")
+	logger := testutil.Logger(t)
+
+	// Nothing happens without a failback.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, canaryShadowFile), []byte("shadow"), 0644))
+	require.NoError(t, applyCanaryFailback(dir, logger))
+	require.NoFileExists(t, filepath.Join(dir, "raft.db"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, canaryFailbackFile), nil, 0644))
+	require.NoError(t, applyCanaryFailback(dir, logger))
+
+	data, err := os.ReadFile(filepath.Join(dir, "raft.db"))
+	require.NoError(t, err)
+	require.Equal(t, "shadow", string(data))
+	require.NoFileExists(t, filepath.Join(dir, canaryShadowFile))
+	require.NoFileExists(t, filepath.Join(dir, canaryFailbackFile))
+}
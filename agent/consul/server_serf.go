@@ -140,6 +140,21 @@ func (s *Server) setupSerfConfig(opts setupSerfOptions) (*serf.Config, error) {
 	// feature flag: advertise support for service-intentions
 	conf.Tags["ft_si"] = "1"
 
+	// feature flag: advertise support for compressed raft logs and snapshots
+	conf.Tags["ft_rc"] = "1"
+
+	// feature flag: advertise support for compressed KV values
+	conf.Tags["ft_kc"] = "1"
+
+	// feature flag: advertise support for KV patch operations
+	conf.Tags["ft_kp"] = "1"
+
+	// feature flag: advertise support for catalog batches
+	conf.Tags["ft_cb"] = "1"
+
+	// feature flag: advertise support for bulk token operations
+	conf.Tags["ft_tb"] = "1"
+
 	var subLoggerName string
 	if opts.WAN {
 		subLoggerName = logging.WAN
@@ -266,6 +281,9 @@ func (s *Server) lanEventHandler() {
 	for {
 		select {
 		case e := <-s.eventChLAN:
+			if me, ok := e.(serf.MemberEvent); ok {
+				s.gossipHealth.handleEvent(me)
+			}
 			switch e.EventType() {
 			case serf.EventMemberJoin:
 				s.lanNodeJoin(e.(serf.MemberEvent))
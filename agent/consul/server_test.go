@@ -333,7 +333,7 @@ func newServerWithDeps(t *testing.T, c *Config, deps Deps) (*Server, error) {
 			oldNotify()
 		}
 	}
-	grpcServer := external.NewServer(deps.Logger.Named("grpc.external"), nil, deps.TLSConfigurator, rpcRate.NullRequestLimitsHandler())
+	grpcServer := external.NewServer(deps.Logger.Named("grpc.external"), nil, deps.TLSConfigurator, rpcRate.NullRequestLimitsHandler(), nil, nil, nil)
 	srv, err := NewServer(c, deps, grpcServer, nil, deps.Logger)
 	if err != nil {
 		return nil, err
@@ -1799,6 +1799,22 @@ func TestServer_RevokeLeadershipIdempotent(t *testing.T) {
 	s1.revokeLeadership()
 }
 
+func TestServer_IsServer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s := testServer(t)
+	testrpc.WaitForLeader(t, s.RPC, "dc1")
+
+	addr := s.config.RPCAddr
+	retry.Run(t, func(r *retry.R) {
+		require.True(r, s.IsServer(&net.TCPAddr{IP: addr.IP, Port: 45678}))
+	})
+	require.False(t, s.IsServer(&net.TCPAddr{IP: net.ParseIP("198.18.0.1"), Port: addr.Port}))
+}
+
 func TestServer_ReloadConfig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1841,9 +1857,13 @@ func TestServer_ReloadConfig(t *testing.T) {
 
 	rc := ReloadableConfig{
 		RequestLimits: &RequestLimits{
-			Mode:      rpcRate.ModeEnforcing,
-			ReadRate:  1000,
-			WriteRate: 1100,
+			Mode:           rpcRate.ModeEnforcing,
+			ReadRate:       1000,
+			WriteRate:      1100,
+			IPReadRate:     100,
+			IPWriteRate:    10,
+			TokenReadRate:  rate.Inf,
+			TokenWriteRate: rate.Inf,
 		},
 		RPCClientTimeout:     2 * time.Minute,
 		RPCRateLimit:         1000,
@@ -1890,7 +1910,14 @@ func TestServer_ReloadConfig(t *testing.T) {
 			Rate:  rc.RequestLimits.WriteRate,
 			Burst: int(rc.RequestLimits.WriteRate) * requestLimitsBurstMultiplier,
 		},
+		IPConfig: &rpcRate.IPLimitsConfig{
+			ReadWriteConfig: rpcRate.ReadWriteConfig{
+				ReadConfig:  multilimiter.LimiterConfig{Rate: 100, Burst: 1000},
+				WriteConfig: multilimiter.LimiterConfig{Rate: 10, Burst: 100},
+			},
+		},
 	})
+	require.False(t, s.tokenRateLimited.Load())
 
 	// Check RPC client timeout got updated
 	require.Equal(t, 2*time.Minute, s.connPool.RPCClientTimeout())
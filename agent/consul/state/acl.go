@@ -522,8 +522,14 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 		}
 	}
 
+	for _, tp := range token.TemplatedPolicies {
+		if tp.TemplateName == "" {
+			return fmt.Errorf("Encountered a Token with an empty templated policy name in the state store")
+		}
+	}
+
 	if opts.ProhibitUnprivileged {
-		if numValidRoles == 0 && numValidPolicies == 0 && len(token.ServiceIdentities) == 0 && len(token.NodeIdentities) == 0 {
+		if numValidRoles == 0 && numValidPolicies == 0 && len(token.ServiceIdentities) == 0 && len(token.NodeIdentities) == 0 && len(token.TemplatedPolicies) == 0 {
 			return ErrTokenHasNoPrivileges
 		}
 	}
@@ -774,6 +780,27 @@ func (s *Store) ACLTokenBatchDelete(idx uint64, tokenIDs []string) error {
 	return tx.Commit()
 }
 
+// ACLTokenBulkWrite creates or updates the tokens and deletes the tokens with
+// the given AccessorIDs in a single transaction.
+func (s *Store) ACLTokenBulkWrite(idx uint64, tokens structs.ACLTokens, deleteIDs []string) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, token := range tokens {
+		if err := aclTokenSetTxn(tx, idx, token, ACLTokenSetOptions{}); err != nil {
+			return err
+		}
+	}
+
+	for _, accessorID := range deleteIDs {
+		if err := aclTokenDeleteTxn(tx, idx, accessorID, indexAccessor, nil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (s *Store) aclTokenDelete(idx uint64, value, index string, entMeta *acl.EnterpriseMeta) error {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
@@ -1139,6 +1166,12 @@ func aclRoleSetTxn(tx WriteTxn, idx uint64, role *structs.ACLRole, allowMissing
 		}
 	}
 
+	for _, tp := range role.TemplatedPolicies {
+		if tp.TemplateName == "" {
+			return fmt.Errorf("Encountered a Role with an empty templated policy name in the state store")
+		}
+	}
+
 	if err := aclRoleUpsertValidateEnterprise(tx, role, existing); err != nil {
 		return err
 	}
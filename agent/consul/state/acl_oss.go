@@ -79,6 +79,11 @@ func aclTokenDeleteWithToken(tx WriteTxn, token *structs.ACLToken, idx uint64) e
 		return fmt.Errorf("failed deleting acl token: %v", err)
 	}
 
+	// remove the recorded usage of the token
+	if err := aclTokenUsageDeleteTxn(tx, idx, token.AccessorID); err != nil {
+		return err
+	}
+
 	// update the overall acl-tokens index
 	if err := indexUpdateMaxTxn(tx, idx, tableACLTokens); err != nil {
 		return fmt.Errorf("failed updating acl tokens index: %v", err)
@@ -1359,6 +1359,49 @@ func TestStateStore_ACLToken_Delete(t *testing.T) {
 		require.Nil(t, rtoken)
 	})
 
+	t.Run("Bulk", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		require.NoError(t, s.ACLTokenSet(2, &structs.ACLToken{
+			AccessorID: "f1093997-b6c7-496d-bfb8-6b1b1895641b",
+			SecretID:   "34ec8eb3-095d-417a-a937-b439af7a8e8b",
+			Local:      true,
+		}))
+
+		created := &structs.ACLToken{
+			AccessorID: "a0bfe8d4-b2f3-4b48-b387-f28afb820eab",
+			SecretID:   "be444e46-fb95-4ccc-80d5-c873f34e6fa6",
+			Policies: []structs.ACLTokenPolicyLink{
+				{
+					ID: testPolicyID_A,
+				},
+			},
+			Local: true,
+		}
+		require.NoError(t, s.ACLTokenBulkWrite(3, structs.ACLTokens{created},
+			[]string{"f1093997-b6c7-496d-bfb8-6b1b1895641b"}))
+
+		_, rtoken, err := s.ACLTokenGetByAccessor(nil, "f1093997-b6c7-496d-bfb8-6b1b1895641b", nil)
+		require.NoError(t, err)
+		require.Nil(t, rtoken)
+		_, rtoken, err = s.ACLTokenGetByAccessor(nil, "a0bfe8d4-b2f3-4b48-b387-f28afb820eab", nil)
+		require.NoError(t, err)
+		require.NotNil(t, rtoken)
+		require.Equal(t, uint64(3), rtoken.CreateIndex)
+
+		// Nothing is written if one of the operations fails.
+		require.Error(t, s.ACLTokenBulkWrite(4, structs.ACLTokens{{
+			AccessorID: "9b2d5ccb-8bd5-4c40-a6dd-a4e7d0b8b1e5",
+			SecretID:   "0c3f9b1a-7f5e-4b61-9c2d-3f8a5f2b6d1e",
+			Local:      true,
+		}}, []string{acl.AnonymousTokenID}))
+
+		_, rtoken, err = s.ACLTokenGetByAccessor(nil, "9b2d5ccb-8bd5-4c40-a6dd-a4e7d0b8b1e5", nil)
+		require.NoError(t, err)
+		require.Nil(t, rtoken)
+	})
+
 	t.Run("Anonymous", func(t *testing.T) {
 		t.Parallel()
 		s := testACLTokensStateStore(t)
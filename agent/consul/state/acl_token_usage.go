@@ -0,0 +1,171 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const tableACLTokenUsage = "acl-token-usage"
+
+// tokenUsageTableSchema returns a new table schema used for storing the last
+// time each token was used, keyed by the accessor ID of the token.
+func tokenUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableACLTokenUsage,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: indexerSingle[string, *structs.ACLTokenUsage]{
+					readIndex:  indexFromUUIDString,
+					writeIndex: indexAccessorIDFromACLTokenUsage,
+				},
+			},
+		},
+	}
+}
+
+func indexAccessorIDFromACLTokenUsage(u *structs.ACLTokenUsage) ([]byte, error) {
+	if u.AccessorID == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	uuid, err := uuidStringToBytes(u.AccessorID)
+	if err != nil {
+		return nil, err
+	}
+	var b indexBuilder
+	b.Raw(uuid)
+	return b.Bytes(), nil
+}
+
+// ACLTokenUsageBatchSet records the usage of the given tokens. Usages of
+// tokens that no longer exist, or that are older than the recorded usage, are
+// ignored.
+func (s *Store) ACLTokenUsageBatchSet(idx uint64, usages []*structs.ACLTokenUsage) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	var updated bool
+	for _, usage := range usages {
+		token, err := tx.First(tableACLTokens, indexAccessor, usage.AccessorID)
+		if err != nil {
+			return fmt.Errorf("failed acl token lookup: %v", err)
+		}
+		if token == nil {
+			continue
+		}
+
+		raw, err := tx.First(tableACLTokenUsage, indexID, usage.AccessorID)
+		if err != nil {
+			return fmt.Errorf("failed acl token usage lookup: %v", err)
+		}
+
+		// Copy the usage so the one in the request isn't modified.
+		u := *usage
+		u.CreateIndex = idx
+		if raw != nil {
+			existing := raw.(*structs.ACLTokenUsage)
+			if !u.LastUsedTime.After(existing.LastUsedTime) {
+				continue
+			}
+			u.CreateIndex = existing.CreateIndex
+		}
+		u.ModifyIndex = idx
+
+		if err := tx.Insert(tableACLTokenUsage, &u); err != nil {
+			return fmt.Errorf("failed inserting acl token usage: %v", err)
+		}
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+
+	if err := indexUpdateMaxTxn(tx, idx, tableACLTokenUsage); err != nil {
+		return fmt.Errorf("failed updating acl token usage index: %v", err)
+	}
+	return tx.Commit()
+}
+
+// ACLTokenUsageGet returns the recorded usage of the token with the given
+// accessor ID, or nil if the token was never used.
+func (s *Store) ACLTokenUsageGet(ws memdb.WatchSet, accessorID string) (*structs.ACLTokenUsage, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	return aclTokenUsageGetTxn(tx, ws, accessorID)
+}
+
+// ACLTokenUsageList returns the recorded usage of all the tokens, keyed by
+// their accessor ID.
+func (s *Store) ACLTokenUsageList(ws memdb.WatchSet) (map[string]*structs.ACLTokenUsage, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableACLTokenUsage, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl token usage lookup: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	usages := make(map[string]*structs.ACLTokenUsage)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		usage := raw.(*structs.ACLTokenUsage)
+		usages[usage.AccessorID] = usage
+	}
+	return usages, nil
+}
+
+func aclTokenUsageGetTxn(tx ReadTxn, ws memdb.WatchSet, accessorID string) (*structs.ACLTokenUsage, error) {
+	watchCh, raw, err := tx.FirstWatch(tableACLTokenUsage, indexID, accessorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl token usage lookup: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.ACLTokenUsage), nil
+}
+
+// aclTokenUsageDeleteTxn removes the recorded usage of a token that is being
+// deleted.
+func aclTokenUsageDeleteTxn(tx WriteTxn, idx uint64, accessorID string) error {
+	raw, err := tx.First(tableACLTokenUsage, indexID, accessorID)
+	if err != nil {
+		return fmt.Errorf("failed acl token usage lookup: %v", err)
+	}
+	if raw == nil {
+		return nil
+	}
+
+	if err := tx.Delete(tableACLTokenUsage, raw); err != nil {
+		return fmt.Errorf("failed deleting acl token usage: %v", err)
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableACLTokenUsage); err != nil {
+		return fmt.Errorf("failed updating acl token usage index: %v", err)
+	}
+	return nil
+}
+
+// ACLTokenUsages is used to pull all the token usages for a snapshot.
+func (s *Snapshot) ACLTokenUsages() (memdb.ResultIterator, error) {
+	return s.tx.Get(tableACLTokenUsage, indexID)
+}
+
+// ACLTokenUsage is used when restoring from a snapshot.
+func (s *Restore) ACLTokenUsage(usage *structs.ACLTokenUsage) error {
+	if err := s.tx.Insert(tableACLTokenUsage, usage); err != nil {
+		return fmt.Errorf("failed restoring acl token usage: %v", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, usage.ModifyIndex, tableACLTokenUsage); err != nil {
+		return fmt.Errorf("failed updating acl token usage index: %v", err)
+	}
+	return nil
+}
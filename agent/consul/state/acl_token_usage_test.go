@@ -0,0 +1,129 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func testIndexerTableACLTokenUsage() map[string]indexerTestCase {
+	return map[string]indexerTestCase{
+		indexID: {
+			read: indexValue{
+				source:   "123e4567-e89a-12d7-a456-426614174abc",
+				expected: []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9a, 0x12, 0xd7, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x4a, 0xbc},
+			},
+			write: indexValue{
+				source: &structs.ACLTokenUsage{
+					AccessorID: "123e4567-e89a-12d7-a456-426614174abc",
+				},
+				expected: []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9a, 0x12, 0xd7, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x4a, 0xbc},
+			},
+		},
+	}
+}
+
+func TestStateStore_ACLTokenUsage(t *testing.T) {
+	s := testACLTokensStateStore(t)
+
+	const accessorID = "f1093997-b6c7-496d-bfb8-6b1b1895641b"
+	token := &structs.ACLToken{
+		AccessorID: accessorID,
+		SecretID:   "34ec8eb3-095d-417a-a937-b439af7a8e8b",
+		Policies: []structs.ACLTokenPolicyLink{
+			{ID: structs.ACLPolicyGlobalManagementID},
+		},
+	}
+	require.NoError(t, s.ACLTokenSet(2, token.Clone()))
+
+	// No usage is recorded to begin with.
+	usage, err := s.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.Nil(t, usage)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, s.ACLTokenUsageBatchSet(3, []*structs.ACLTokenUsage{
+		{AccessorID: accessorID, LastUsedTime: now, LastUsedAddr: "10.0.0.1"},
+		// Usages of tokens that don't exist are ignored.
+		{AccessorID: "a0bfe8d4-b2f3-4b48-b387-f28afb820eab", LastUsedTime: now},
+	}))
+
+	usage, err = s.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+	require.Equal(t, now, usage.LastUsedTime)
+	require.Equal(t, "10.0.0.1", usage.LastUsedAddr)
+	require.Equal(t, uint64(3), usage.CreateIndex)
+	require.Equal(t, uint64(3), usage.ModifyIndex)
+
+	usages, err := s.ACLTokenUsageList(nil)
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	require.Contains(t, usages, accessorID)
+
+	// An older usage doesn't overwrite the recorded one.
+	require.NoError(t, s.ACLTokenUsageBatchSet(4, []*structs.ACLTokenUsage{
+		{AccessorID: accessorID, LastUsedTime: now.Add(-time.Hour), LastUsedAddr: "10.0.0.2"},
+	}))
+	usage, err = s.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", usage.LastUsedAddr)
+	require.Equal(t, uint64(3), usage.ModifyIndex)
+
+	// A newer usage does.
+	require.NoError(t, s.ACLTokenUsageBatchSet(5, []*structs.ACLTokenUsage{
+		{AccessorID: accessorID, LastUsedTime: now.Add(time.Hour), LastUsedAddr: "10.0.0.3"},
+	}))
+	usage, err = s.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Hour), usage.LastUsedTime)
+	require.Equal(t, "10.0.0.3", usage.LastUsedAddr)
+	require.Equal(t, uint64(3), usage.CreateIndex)
+	require.Equal(t, uint64(5), usage.ModifyIndex)
+
+	// Deleting the token removes its usage.
+	require.NoError(t, s.ACLTokenDeleteByAccessor(6, accessorID, nil))
+	usage, err = s.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.Nil(t, usage)
+}
+
+func TestStateStore_ACLTokenUsage_Snapshot_Restore(t *testing.T) {
+	s := testACLTokensStateStore(t)
+
+	const accessorID = "f1093997-b6c7-496d-bfb8-6b1b1895641b"
+	require.NoError(t, s.ACLTokenSet(2, &structs.ACLToken{
+		AccessorID: accessorID,
+		SecretID:   "34ec8eb3-095d-417a-a937-b439af7a8e8b",
+	}))
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, s.ACLTokenUsageBatchSet(3, []*structs.ACLTokenUsage{
+		{AccessorID: accessorID, LastUsedTime: now, LastUsedAddr: "10.0.0.1"},
+	}))
+
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	iter, err := snap.ACLTokenUsages()
+	require.NoError(t, err)
+	var dump []*structs.ACLTokenUsage
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		dump = append(dump, raw.(*structs.ACLTokenUsage))
+	}
+	require.Len(t, dump, 1)
+
+	s2 := testStateStore(t)
+	restore := s2.Restore()
+	for _, usage := range dump {
+		require.NoError(t, restore.ACLTokenUsage(usage))
+	}
+	restore.Commit()
+
+	usage, err := s2.ACLTokenUsageGet(nil, accessorID)
+	require.NoError(t, err)
+	require.Equal(t, dump[0], usage)
+	require.Equal(t, uint64(3), s2.maxIndex(tableACLTokenUsage))
+}
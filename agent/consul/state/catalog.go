@@ -145,6 +145,72 @@ func (s *Store) EnsureRegistration(idx uint64, req *structs.RegisterRequest) err
 	return tx.Commit()
 }
 
+// IndexedRegistration is a registration applied at the index of its own raft
+// log.
+type IndexedRegistration struct {
+	Index   uint64
+	Request *structs.RegisterRequest
+}
+
+// EnsureRegistrations applies the registrations of consecutive raft logs
+// within a single transaction, each at its own index, which is much cheaper
+// than a transaction per registration when many are committed at once. If any
+// of them fails, none of them are applied.
+//
+// The rows and table indexes record the index of their own registration, but
+// the change events of the whole batch are published together at the index of
+// the last registration. No subscriber can observe a state within the batch:
+// one resuming from an index inside it doesn't match any published event, so
+// it is sent a new snapshot rather than missing or repeating events.
+func (s *Store) EnsureRegistrations(regs []IndexedRegistration) error {
+	if len(regs) == 0 {
+		return nil
+	}
+
+	tx := s.db.WriteTxn(regs[len(regs)-1].Index)
+	defer tx.Abort()
+
+	for i, reg := range regs {
+		if err := s.ensureRegistrationTxn(tx, reg.Index, false, reg.Request, false); err != nil {
+			return fmt.Errorf("registration %d at index %d: %w", i, reg.Index, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CatalogBatch applies all the registrations and then all the deregistrations
+// of the batch within a single transaction. If any of them fails, none of
+// them are applied.
+func (s *Store) CatalogBatch(idx uint64, req *structs.CatalogBatchRequest) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for i, reg := range req.Register {
+		if err := s.ensureRegistrationTxn(tx, idx, false, reg, false); err != nil {
+			return fmt.Errorf("Register[%d]: %w", i, err)
+		}
+	}
+
+	// The precedence here matches applyDeregister in the FSM.
+	for i, dereg := range req.Deregister {
+		var err error
+		switch {
+		case dereg.ServiceID != "":
+			err = s.deleteServiceWithReasonTxn(tx, idx, dereg.Node, dereg.ServiceID, &dereg.EnterpriseMeta, dereg.PeerName, dereg.Reason)
+		case dereg.CheckID != "":
+			err = s.deleteCheckTxn(tx, idx, dereg.Node, dereg.CheckID, &dereg.EnterpriseMeta, dereg.PeerName)
+		default:
+			err = s.deleteNodeWithReasonTxn(tx, idx, dereg.Node, &dereg.EnterpriseMeta, dereg.PeerName, dereg.Reason)
+		}
+		if err != nil {
+			return fmt.Errorf("Deregister[%d]: %w", i, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (s *Store) ensureCheckIfNodeMatches(
 	tx WriteTxn,
 	idx uint64,
@@ -631,6 +697,13 @@ func (s *Store) NodesByMeta(ws memdb.WatchSet, filters map[string]string, entMet
 
 // DeleteNode is used to delete a given node by its ID.
 func (s *Store) DeleteNode(idx uint64, nodeName string, entMeta *acl.EnterpriseMeta, peerName string) error {
+	return s.DeleteNodeWithReason(idx, nodeName, entMeta, peerName, "")
+}
+
+// DeleteNodeWithReason is used to delete a given node by its ID, recording the
+// given reason in the tombstones of its services. If reason is empty,
+// structs.ServiceTombstoneReasonNodeDeregistered is recorded.
+func (s *Store) DeleteNodeWithReason(idx uint64, nodeName string, entMeta *acl.EnterpriseMeta, peerName, reason string) error {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
 
@@ -640,7 +713,7 @@ func (s *Store) DeleteNode(idx uint64, nodeName string, entMeta *acl.EnterpriseM
 	}
 
 	// Call the node deletion.
-	if err := s.deleteNodeTxn(tx, idx, nodeName, entMeta, peerName); err != nil {
+	if err := s.deleteNodeWithReasonTxn(tx, idx, nodeName, entMeta, peerName, reason); err != nil {
 		return err
 	}
 
@@ -678,6 +751,16 @@ func (s *Store) deleteNodeCASTxn(tx WriteTxn, idx, cidx uint64, nodeName string,
 // deleteNodeTxn is the inner method used for removing a node from
 // the store within a given transaction.
 func (s *Store) deleteNodeTxn(tx WriteTxn, idx uint64, nodeName string, entMeta *acl.EnterpriseMeta, peerName string) error {
+	return s.deleteNodeWithReasonTxn(tx, idx, nodeName, entMeta, peerName, "")
+}
+
+// deleteNodeWithReasonTxn is deleteNodeTxn with the reason recorded in the
+// tombstones of the node's services.
+func (s *Store) deleteNodeWithReasonTxn(tx WriteTxn, idx uint64, nodeName string, entMeta *acl.EnterpriseMeta, peerName, reason string) error {
+	if reason == "" {
+		reason = structs.ServiceTombstoneReasonNodeDeregistered
+	}
+
 	// TODO: accept non-pointer value
 	if entMeta == nil {
 		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
@@ -720,7 +803,7 @@ func (s *Store) deleteNodeTxn(tx WriteTxn, idx uint64, nodeName string, entMeta
 
 	// Do the delete in a separate loop so we don't trash the iterator.
 	for _, svc := range deleteServices {
-		if err := s.deleteServiceTxn(tx, idx, nodeName, svc.ServiceID, &svc.EnterpriseMeta, svc.PeerName); err != nil {
+		if err := s.deleteServiceWithReasonTxn(tx, idx, nodeName, svc.ServiceID, &svc.EnterpriseMeta, svc.PeerName, reason); err != nil {
 			return err
 		}
 	}
@@ -982,6 +1065,13 @@ func ensureServiceTxn(tx WriteTxn, idx uint64, node string, preserveIndexes bool
 		}
 	}
 
+	// The service instance is back, so it no longer needs a tombstone.
+	if existing == nil {
+		if err := deleteServiceTombstoneTxn(tx, idx, entry); err != nil {
+			return err
+		}
+	}
+
 	// Insert the service and update the index
 	return catalogInsertService(tx, entry)
 }
@@ -1437,25 +1527,10 @@ func (s *Store) ServiceTagNodes(ws memdb.WatchSet, service string, tags []string
 		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
 	}
 
-	services, err := tx.Get(tableServices, indexService, Query{
-		Value:          service,
-		EnterpriseMeta: *entMeta,
-		PeerName:       peerName,
-	})
+	q := Query{Value: service, EnterpriseMeta: *entMeta, PeerName: peerName}
+	serviceExists, results, err := serviceTagNodesTxn(tx, ws, q, tags)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed service lookup: %s", err)
-	}
-	ws.Add(services.WatchCh())
-
-	// Gather all the services and apply the tag filter.
-	serviceExists := false
-	var results structs.ServiceNodes
-	for service := services.Next(); service != nil; service = services.Next() {
-		svc := service.(*structs.ServiceNode)
-		serviceExists = true
-		if !serviceTagsFilter(svc, tags) {
-			results = append(results, svc)
-		}
+		return 0, nil, err
 	}
 
 	// Fill in the node details.
@@ -1469,6 +1544,43 @@ func (s *Store) ServiceTagNodes(ws memdb.WatchSet, service string, tags []string
 	return idx, results, nil
 }
 
+// serviceTagNodesTxn returns whether the service exists, and its instances
+// having all the given tags. The instances are looked up in the index of the
+// tags by the first tag, so that only the instances having it are scanned
+// rather than all the instances of the service.
+func serviceTagNodesTxn(tx ReadTxn, ws memdb.WatchSet, q Query, tags []string) (bool, structs.ServiceNodes, error) {
+	// Watch for the service being registered, as the lookup by tag isn't
+	// woken by the instances which don't have the tag.
+	watchCh, existing, err := tx.FirstWatch(tableServices, indexService, q)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed service lookup: %s", err)
+	}
+	ws.Add(watchCh)
+
+	if len(tags) == 0 {
+		results, err := serviceNodesByIndexTxn(tx, ws, indexService, q)
+		return existing != nil, results, err
+	}
+
+	candidates, err := serviceNodesByIndexTxn(tx, ws, indexTag, ServiceKeyValueQuery{
+		Service:        q.Value,
+		Value:          tags[0],
+		EnterpriseMeta: q.EnterpriseMeta,
+		PeerName:       q.PeerName,
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	var results structs.ServiceNodes
+	for _, svc := range candidates {
+		if !serviceTagsFilter(svc, tags[1:]) {
+			results = append(results, svc)
+		}
+	}
+	return existing != nil, results, nil
+}
+
 // serviceTagFilter returns true (should filter) if the given service node
 // doesn't contain the given tag.
 func serviceTagFilter(sn *structs.ServiceNode, tag string) bool {
@@ -1812,11 +1924,18 @@ func (s *Store) NodeServiceList(ws memdb.WatchSet, nodeNameOrID string, entMeta
 
 // DeleteService is used to delete a given service associated with a node.
 func (s *Store) DeleteService(idx uint64, nodeName, serviceID string, entMeta *acl.EnterpriseMeta, peerName string) error {
+	return s.DeleteServiceWithReason(idx, nodeName, serviceID, entMeta, peerName, "")
+}
+
+// DeleteServiceWithReason is used to delete a given service associated with a
+// node, recording the given reason in its tombstone. If reason is empty,
+// structs.ServiceTombstoneReasonDeregistered is recorded.
+func (s *Store) DeleteServiceWithReason(idx uint64, nodeName, serviceID string, entMeta *acl.EnterpriseMeta, peerName, reason string) error {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
 
 	// Call the service deletion
-	if err := s.deleteServiceTxn(tx, idx, nodeName, serviceID, entMeta, peerName); err != nil {
+	if err := s.deleteServiceWithReasonTxn(tx, idx, nodeName, serviceID, entMeta, peerName, reason); err != nil {
 		return err
 	}
 
@@ -1854,6 +1973,16 @@ func (s *Store) deleteServiceCASTxn(tx WriteTxn, idx, cidx uint64, nodeName, ser
 // deleteServiceTxn is the inner method called to remove a service
 // registration within an existing transaction.
 func (s *Store) deleteServiceTxn(tx WriteTxn, idx uint64, nodeName, serviceID string, entMeta *acl.EnterpriseMeta, peerName string) error {
+	return s.deleteServiceWithReasonTxn(tx, idx, nodeName, serviceID, entMeta, peerName, "")
+}
+
+// deleteServiceWithReasonTxn is deleteServiceTxn with the reason recorded in
+// the tombstone of the service.
+func (s *Store) deleteServiceWithReasonTxn(tx WriteTxn, idx uint64, nodeName, serviceID string, entMeta *acl.EnterpriseMeta, peerName, reason string) error {
+	if reason == "" {
+		reason = structs.ServiceTombstoneReasonDeregistered
+	}
+
 	// TODO: pass non-pointer type for ent meta
 	if entMeta == nil {
 		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
@@ -1908,6 +2037,9 @@ func (s *Store) deleteServiceTxn(tx WriteTxn, idx uint64, nodeName, serviceID st
 	}
 
 	svc := service.(*structs.ServiceNode)
+	if err := s.insertServiceTombstoneTxn(tx, idx, svc, reason); err != nil {
+		return err
+	}
 	if err := catalogUpdateServicesIndexes(tx, idx, entMeta, svc.PeerName); err != nil {
 		return fmt.Errorf("failed updating services indexes: %w", err)
 	}
@@ -2695,6 +2827,14 @@ func (s *Store) checkServiceNodes(ws memdb.WatchSet, serviceName string, connect
 }
 
 func checkServiceNodesTxn(tx ReadTxn, ws memdb.WatchSet, serviceName string, connect bool, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error) {
+	return checkServiceNodesWithFilterTxn(tx, ws, serviceName, connect, nil, entMeta, peerName)
+}
+
+// checkServiceNodesWithFilterTxn is checkServiceNodesTxn, only reading the
+// nodes and checks of the instances that satisfy the service meta and tag
+// constraints of the given filter, if any. The index and the watches are the
+// same as those of the unfiltered lookup.
+func checkServiceNodesWithFilterTxn(tx ReadTxn, ws memdb.WatchSet, serviceName string, connect bool, filter *ServiceIndexedFilter, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error) {
 	index := indexService
 	if connect {
 		index = indexConnect
@@ -2732,7 +2872,9 @@ func checkServiceNodesTxn(tx ReadTxn, ws memdb.WatchSet, serviceName string, con
 	serviceNames := make(map[structs.ServiceName]struct{}, 2)
 	for service := iter.Next(); service != nil; service = iter.Next() {
 		sn := service.(*structs.ServiceNode)
-		results = append(results, sn)
+		if filter == nil || filter.matchesService(sn) {
+			results = append(results, sn)
+		}
 
 		name := structs.NewServiceName(sn.ServiceName, &sn.EnterpriseMeta)
 		serviceNames[name] = struct{}{}
@@ -2752,7 +2894,9 @@ func checkServiceNodesTxn(tx ReadTxn, ws memdb.WatchSet, serviceName string, con
 		}
 		idx = lib.MaxUint64(idx, gwIdx)
 		for i := 0; i < len(nodes); i++ {
-			results = append(results, nodes[i])
+			if filter == nil || filter.matchesService(nodes[i]) {
+				results = append(results, nodes[i])
+			}
 
 			name := structs.NewServiceName(nodes[i].ServiceName, &nodes[i].EnterpriseMeta)
 			serviceNames[name] = struct{}{}
@@ -2840,21 +2984,9 @@ func (s *Store) CheckServiceTagNodes(ws memdb.WatchSet, serviceName string, tags
 	}
 
 	q := Query{Value: serviceName, EnterpriseMeta: *entMeta, PeerName: peerName}
-	iter, err := tx.Get(tableServices, indexService, q)
+	serviceExists, results, err := serviceTagNodesTxn(tx, ws, q, tags)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed service lookup: %s", err)
-	}
-	ws.Add(iter.WatchCh())
-
-	// Return the results, filtering by tag.
-	serviceExists := false
-	var results structs.ServiceNodes
-	for service := iter.Next(); service != nil; service = iter.Next() {
-		svc := service.(*structs.ServiceNode)
-		serviceExists = true
-		if !serviceTagsFilter(svc, tags) {
-			results = append(results, svc)
-		}
+		return 0, nil, err
 	}
 
 	// Get the table index.
@@ -112,6 +112,10 @@ func (s *Store) ServiceHealthSnapshot(req stream.SubscribeRequest, buf stream.Sn
 
 	connect := req.Topic == EventTopicServiceHealthConnect
 
+	if req.Subject == stream.SubjectWildcard && !connect {
+		return serviceHealthWildcardSnapshot(tx, req, buf)
+	}
+
 	subject, ok := req.Subject.(EventSubjectService)
 	if !ok {
 		return 0, fmt.Errorf("expected SubscribeRequest.Subject to be a: state.EventSubjectService, was a: %T", req.Subject)
@@ -150,6 +154,47 @@ func (s *Store) ServiceHealthSnapshot(req stream.SubscribeRequest, buf stream.Sn
 	return idx, err
 }
 
+// serviceHealthWildcardSnapshot appends the health of every instance of every
+// service in the local catalog to the snapshot.
+func serviceHealthWildcardSnapshot(tx ReadTxn, req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+	entMeta := structs.WildcardEnterpriseMetaInPartition(structs.WildcardSpecifier)
+	iter, err := catalogServiceListNoWildcard(tx, entMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return 0, fmt.Errorf("failed service lookup: %s", err)
+	}
+
+	var names []structs.ServiceName
+	seen := make(map[structs.ServiceName]struct{})
+	for service := iter.Next(); service != nil; service = iter.Next() {
+		name := service.(*structs.ServiceNode).CompoundServiceName()
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	idx := catalogMaxIndex(tx, entMeta, structs.DefaultPeerKeyword, true)
+	for _, name := range names {
+		_, nodes, err := checkServiceNodesTxn(tx, nil, name.Name, false, &name.EnterpriseMeta, structs.DefaultPeerKeyword)
+		if err != nil {
+			return 0, err
+		}
+
+		for i := range nodes {
+			n := nodes[i]
+			buf.Append([]stream.Event{{
+				Index: idx,
+				Topic: req.Topic,
+				Payload: EventPayloadCheckServiceNode{
+					Op:    pbsubscribe.CatalogOp_Register,
+					Value: &n,
+				},
+			}})
+		}
+	}
+	return idx, nil
+}
+
 // TODO: this could use NodeServiceQuery
 type nodeServiceTuple struct {
 	Node      string
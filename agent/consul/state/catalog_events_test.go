@@ -70,6 +70,41 @@ func TestServiceHealthSnapshot(t *testing.T) {
 	prototest.AssertDeepEqual(t, expected, buf.events, cmpEvents)
 }
 
+func TestServiceHealthSnapshot_Wildcard(t *testing.T) {
+	store := NewStateStore(nil)
+
+	counter := newIndexCounter()
+	err := store.EnsureRegistration(counter.Next(), testServiceRegistration(t, "db"))
+	require.NoError(t, err)
+	err = store.EnsureRegistration(counter.Next(), testServiceRegistration(t, "web"))
+	require.NoError(t, err)
+	err = store.EnsureRegistration(counter.Next(), testServiceRegistration(t, "web", regNode2))
+	require.NoError(t, err)
+
+	buf := &snapshotAppender{}
+	req := stream.SubscribeRequest{Topic: EventTopicServiceHealth, Subject: stream.SubjectWildcard}
+
+	idx, err := store.ServiceHealthSnapshot(req, buf)
+	require.NoError(t, err)
+	require.Equal(t, counter.Last(), idx)
+
+	type instance struct{ service, node string }
+	var instances []instance
+	for _, events := range buf.events {
+		for _, e := range events {
+			require.Equal(t, counter.Last(), e.Index)
+			csn := getPayloadCheckServiceNode(e.Payload)
+			instances = append(instances, instance{service: csn.Service.Service, node: csn.Node.Node})
+		}
+	}
+	expected := []instance{
+		{service: "db", node: "node1"},
+		{service: "web", node: "node1"},
+		{service: "web", node: "node2"},
+	}
+	require.Equal(t, expected, instances)
+}
+
 func TestServiceHealthSnapshot_ConnectTopic(t *testing.T) {
 	store := NewStateStore(nil)
 
@@ -0,0 +1,265 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// ServiceIndexedFilter holds the constraints on the instances of a service
+// that can be resolved using the catalog indexes, rather than by inspecting
+// every instance of the service. It is typically derived from a bexpr filter,
+// which must still be applied to the results since it may contain other
+// expressions.
+type ServiceIndexedFilter struct {
+	// ServiceMeta holds the service meta key/value pairs that must all match.
+	ServiceMeta map[string]string
+
+	// NodeMeta holds the node meta key/value pairs that must all match.
+	NodeMeta map[string]string
+
+	// Tags holds the tags that the service must all have. Tags are matched
+	// case-insensitively.
+	Tags []string
+}
+
+// IsEmpty returns true if the filter doesn't hold any constraint.
+func (f *ServiceIndexedFilter) IsEmpty() bool {
+	return f == nil || (len(f.ServiceMeta) == 0 && len(f.NodeMeta) == 0 && len(f.Tags) == 0)
+}
+
+// matchesService returns true if the given service instance satisfies the
+// service meta and tag constraints of the filter.
+func (f *ServiceIndexedFilter) matchesService(sn *structs.ServiceNode) bool {
+	return structs.SatisfiesMetaFilters(sn.ServiceMeta, f.ServiceMeta) && !serviceTagsFilter(sn, f.Tags)
+}
+
+// ServiceNodesWithFilter returns the nodes associated with a given service
+// name, using the catalog indexes to only read the instances that satisfy the
+// given filter.
+func (s *Store) ServiceNodesWithFilter(ws memdb.WatchSet, serviceName string, filter *ServiceIndexedFilter, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.ServiceNodes, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// TODO: accept non-pointer value
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+	if filter == nil {
+		filter = &ServiceIndexedFilter{}
+	}
+
+	q := Query{Value: serviceName, EnterpriseMeta: *entMeta, PeerName: peerName}
+	idx, services, fallbackWS, err := serviceNodesWithFilterTxn(tx, ws, q, filter, false)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Fill in the node details.
+	services, err = parseServiceNodes(tx, fallbackWS, services, entMeta, peerName)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed parsing service nodes: %s", err)
+	}
+
+	var results structs.ServiceNodes
+	for _, sn := range services {
+		if structs.SatisfiesMetaFilters(sn.NodeMeta, filter.NodeMeta) {
+			results = append(results, sn)
+		}
+	}
+	return idx, results, nil
+}
+
+// CheckServiceNodesWithFilter is used to query all nodes and checks for a
+// given service, using the catalog indexes to only read the instances that
+// satisfy the given filter.
+func (s *Store) CheckServiceNodesWithFilter(ws memdb.WatchSet, serviceName string, filter *ServiceIndexedFilter, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// TODO: accept non-pointer value
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+	if filter == nil {
+		filter = &ServiceIndexedFilter{}
+	}
+
+	q := Query{Value: serviceName, EnterpriseMeta: *entMeta, PeerName: peerName}
+	idx, services, fallbackWS, err := serviceNodesWithFilterTxn(tx, ws, q, filter, true)
+	idx, nodes, err := parseCheckServiceNodes(tx, fallbackWS, idx, services, entMeta, peerName, err)
+	if err != nil || len(filter.NodeMeta) == 0 {
+		return idx, nodes, err
+	}
+
+	var results structs.CheckServiceNodes
+	for _, csn := range nodes {
+		if structs.SatisfiesMetaFilters(csn.Node.Meta, filter.NodeMeta) {
+			results = append(results, csn)
+		}
+	}
+	return idx, results, nil
+}
+
+// CheckConnectServiceNodesWithFilter is used to query all nodes and checks
+// for Connect compatible endpoints for a given service, only reading the nodes
+// and checks of the endpoints that satisfy the given filter. Since the
+// endpoints are registered under their own names, they can't be looked up by
+// the service indexes, but filtering them before their nodes and checks are
+// read still saves most of the work.
+func (s *Store) CheckConnectServiceNodesWithFilter(ws memdb.WatchSet, serviceName string, filter *ServiceIndexedFilter, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	if filter == nil {
+		filter = &ServiceIndexedFilter{}
+	}
+
+	idx, nodes, err := checkServiceNodesWithFilterTxn(tx, ws, serviceName, true, filter, entMeta, peerName)
+	if err != nil || len(filter.NodeMeta) == 0 {
+		return idx, nodes, err
+	}
+
+	var results structs.CheckServiceNodes
+	for _, csn := range nodes {
+		if structs.SatisfiesMetaFilters(csn.Node.Meta, filter.NodeMeta) {
+			results = append(results, csn)
+		}
+	}
+	return idx, results, nil
+}
+
+// serviceNodesWithFilterTxn returns the instances of the service that satisfy
+// the service meta and tag constraints of the filter. The node meta
+// constraints are only used to narrow down the lookup, so the caller is
+// responsible for checking them once the node details are filled in.
+//
+// Like checkServiceNodesTxn, it only watches the service-specific index when
+// possible. The returned WatchSet is nil in that case, and is otherwise the
+// given WatchSet, to be used for anything read from the results.
+func serviceNodesWithFilterTxn(tx ReadTxn, ws memdb.WatchSet, q Query, filter *ServiceIndexedFilter, checks bool) (uint64, structs.ServiceNodes, memdb.WatchSet, error) {
+	// The index we return depends on whether the service exists at all,
+	// regardless of the filter.
+	iter, err := tx.Get(tableServices, indexService, q)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed service lookup: %s", err)
+	}
+	serviceExists := iter.Next() != nil
+
+	idx, svcCh := maxIndexAndWatchChForService(tx, q.Value, serviceExists, checks, &q.EnterpriseMeta, q.PeerName)
+
+	var fallbackWS memdb.WatchSet
+	if svcCh != nil {
+		// Every change to the instances of the service, or to their nodes and
+		// checks, updates the service-specific index, so it is enough to watch.
+		ws.Add(svcCh)
+	} else {
+		fallbackWS = ws
+		fallbackWS.Add(iter.WatchCh())
+	}
+
+	// Pick a single constraint to use for the lookup, which over-matches if
+	// multiple constraints are given, and finish filtering below. Service
+	// constraints are preferred since they are specific to the service.
+	var candidates structs.ServiceNodes
+	switch {
+	case len(filter.ServiceMeta) > 0:
+		var firstKey, firstValue string
+		for firstKey, firstValue = range filter.ServiceMeta {
+			break
+		}
+		candidates, err = serviceNodesByIndexTxn(tx, fallbackWS, indexMeta, ServiceKeyValueQuery{
+			Service:        q.Value,
+			Key:            firstKey,
+			Value:          firstValue,
+			EnterpriseMeta: q.EnterpriseMeta,
+			PeerName:       q.PeerName,
+		})
+
+	case len(filter.Tags) > 0:
+		candidates, err = serviceNodesByIndexTxn(tx, fallbackWS, indexTag, ServiceKeyValueQuery{
+			Service:        q.Value,
+			Value:          filter.Tags[0],
+			EnterpriseMeta: q.EnterpriseMeta,
+			PeerName:       q.PeerName,
+		})
+
+	case len(filter.NodeMeta) > 0:
+		candidates, err = serviceNodesByNodeMetaTxn(tx, fallbackWS, q, filter.NodeMeta)
+
+	default:
+		candidates, err = serviceNodesByIndexTxn(tx, fallbackWS, indexService, q)
+	}
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	var results structs.ServiceNodes
+	for _, sn := range candidates {
+		if filter.matchesService(sn) {
+			results = append(results, sn)
+		}
+	}
+	return idx, results, fallbackWS, nil
+}
+
+func serviceNodesByIndexTxn(tx ReadTxn, ws memdb.WatchSet, index string, q interface{}) (structs.ServiceNodes, error) {
+	iter, err := tx.Get(tableServices, index, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed service lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var results structs.ServiceNodes
+	for service := iter.Next(); service != nil; service = iter.Next() {
+		results = append(results, service.(*structs.ServiceNode))
+	}
+	return results, nil
+}
+
+// serviceNodesByNodeMetaTxn returns the instances of the service registered on
+// the nodes matching one of the given node meta key/value pairs.
+func serviceNodesByNodeMetaTxn(tx ReadTxn, ws memdb.WatchSet, q Query, nodeMeta map[string]string) (structs.ServiceNodes, error) {
+	var firstKey, firstValue string
+	for firstKey, firstValue = range nodeMeta {
+		break
+	}
+
+	nodes, err := tx.Get(tableNodes, indexMeta, KeyValueQuery{
+		Key:            firstKey,
+		Value:          firstValue,
+		EnterpriseMeta: q.EnterpriseMeta,
+		PeerName:       q.PeerName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed nodes lookup: %s", err)
+	}
+	nodesCh := nodes.WatchCh()
+	ws.Add(nodesCh)
+
+	var results structs.ServiceNodes
+	for node := nodes.Next(); node != nil; node = nodes.Next() {
+		n := node.(*structs.Node)
+		services, err := tx.Get(tableServices, indexNode, Query{
+			Value:          n.Node,
+			EnterpriseMeta: *n.GetEnterpriseMeta(),
+			PeerName:       n.PeerName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed service lookup: %s", err)
+		}
+		ws.AddWithLimit(watchLimit, services.WatchCh(), nodesCh)
+
+		for service := services.Next(); service != nil; service = services.Next() {
+			sn := service.(*structs.ServiceNode)
+			if strings.EqualFold(sn.ServiceName, q.Value) && q.EnterpriseMeta.Matches(&sn.EnterpriseMeta) {
+				results = append(results, sn)
+			}
+		}
+	}
+	return results, nil
+}
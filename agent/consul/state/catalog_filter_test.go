@@ -0,0 +1,180 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+func testRegisterIndexedFilterServices(t *testing.T, s *Store) {
+	testRegisterNodeWithMeta(t, s, 1, "node1", map[string]string{"rack": "a"})
+	testRegisterNodeWithMeta(t, s, 2, "node2", map[string]string{"rack": "b"})
+
+	web := func(tags []string, meta map[string]string) func(*structs.NodeService) {
+		return func(svc *structs.NodeService) {
+			svc.Service = "web"
+			svc.Tags = tags
+			svc.Meta = meta
+		}
+	}
+	testRegisterServiceOpts(t, s, 3, "node1", "web1", web([]string{"Primary"}, map[string]string{"env": "prod"}))
+	testRegisterServiceOpts(t, s, 4, "node2", "web2", web([]string{"secondary"}, map[string]string{"env": "prod"}))
+	testRegisterServiceOpts(t, s, 5, "node2", "web3", web([]string{"primary"}, map[string]string{"env": "dev"}))
+	testRegisterServiceOpts(t, s, 6, "node1", "db1", func(svc *structs.NodeService) {
+		svc.Service = "db"
+		svc.Meta = map[string]string{"env": "prod"}
+	})
+}
+
+func TestStateStore_ServiceNodesWithFilter(t *testing.T) {
+	s := testStateStore(t)
+	testRegisterIndexedFilterServices(t, s)
+
+	serviceIDs := func(nodes structs.ServiceNodes) []string {
+		var ids []string
+		for _, n := range nodes {
+			ids = append(ids, n.ServiceID)
+		}
+		return ids
+	}
+
+	cases := map[string]struct {
+		filter   *ServiceIndexedFilter
+		expected []string
+	}{
+		"no filter": {
+			filter:   nil,
+			expected: []string{"web1", "web2", "web3"},
+		},
+		"service meta": {
+			filter:   &ServiceIndexedFilter{ServiceMeta: map[string]string{"env": "prod"}},
+			expected: []string{"web1", "web2"},
+		},
+		"tags are case-insensitive": {
+			filter:   &ServiceIndexedFilter{Tags: []string{"PRIMARY"}},
+			expected: []string{"web1", "web3"},
+		},
+		"node meta": {
+			filter:   &ServiceIndexedFilter{NodeMeta: map[string]string{"rack": "b"}},
+			expected: []string{"web2", "web3"},
+		},
+		"all constraints": {
+			filter: &ServiceIndexedFilter{
+				ServiceMeta: map[string]string{"env": "prod"},
+				NodeMeta:    map[string]string{"rack": "a"},
+				Tags:        []string{"primary"},
+			},
+			expected: []string{"web1"},
+		},
+		"no match": {
+			filter:   &ServiceIndexedFilter{ServiceMeta: map[string]string{"env": "staging"}},
+			expected: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			idx, nodes, err := s.ServiceNodesWithFilter(nil, "web", tc.filter, nil, "")
+			require.NoError(t, err)
+			require.Equal(t, uint64(5), idx)
+			require.ElementsMatch(t, tc.expected, serviceIDs(nodes))
+			for _, n := range nodes {
+				require.NotEmpty(t, n.NodeMeta["rack"])
+			}
+		})
+	}
+
+	// A change to a matching instance fires the watch.
+	ws := memdb.NewWatchSet()
+	filter := &ServiceIndexedFilter{ServiceMeta: map[string]string{"env": "prod"}}
+	_, _, err := s.ServiceNodesWithFilter(ws, "web", filter, nil, "")
+	require.NoError(t, err)
+	testRegisterServiceOpts(t, s, 7, "node1", "web4", func(svc *structs.NodeService) {
+		svc.Service = "web"
+		svc.Meta = map[string]string{"env": "prod"}
+	})
+	require.True(t, watchFired(ws))
+
+	idx, nodes, err := s.ServiceNodesWithFilter(nil, "web", filter, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.ElementsMatch(t, []string{"web1", "web2", "web4"}, serviceIDs(nodes))
+}
+
+func TestStateStore_CheckServiceNodesWithFilter(t *testing.T) {
+	s := testStateStore(t)
+	testRegisterIndexedFilterServices(t, s)
+	testRegisterCheck(t, s, 7, "node2", "web3", "check1", api.HealthCritical)
+
+	filter := &ServiceIndexedFilter{
+		NodeMeta: map[string]string{"rack": "b"},
+		Tags:     []string{"primary"},
+	}
+	idx, nodes, err := s.CheckServiceNodesWithFilter(nil, "web", filter, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "node2", nodes[0].Node.Node)
+	require.Equal(t, "web3", nodes[0].Service.ID)
+	require.Len(t, nodes[0].Checks, 1)
+
+	// Missing services use the same index as the unfiltered lookup.
+	expectedIdx, _, err := s.CheckServiceNodes(nil, "nope", nil, "")
+	require.NoError(t, err)
+	idx, nodes, err = s.CheckServiceNodesWithFilter(nil, "nope", filter, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, expectedIdx, idx)
+	require.Nil(t, nodes)
+}
+
+func TestStateStore_CheckConnectServiceNodesWithFilter(t *testing.T) {
+	s := testStateStore(t)
+	testRegisterNodeWithMeta(t, s, 1, "node1", map[string]string{"rack": "a"})
+	testRegisterNodeWithMeta(t, s, 2, "node2", map[string]string{"rack": "b"})
+	testRegisterService(t, s, 3, "node1", "web")
+	testRegisterService(t, s, 4, "node2", "web")
+	testRegisterSidecarProxyOpts(t, s, 5, "node1", "web", func(svc *structs.NodeService) {
+		svc.Meta = map[string]string{"version": "v1"}
+	})
+	testRegisterSidecarProxyOpts(t, s, 6, "node2", "web", func(svc *structs.NodeService) {
+		svc.Meta = map[string]string{"version": "v2"}
+	})
+	testRegisterCheck(t, s, 7, "node2", "web-sidecar-proxy", "check1", api.HealthPassing)
+
+	filter := &ServiceIndexedFilter{ServiceMeta: map[string]string{"version": "v2"}}
+	ws := memdb.NewWatchSet()
+	idx, nodes, err := s.CheckConnectServiceNodesWithFilter(ws, "web", filter, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "node2", nodes[0].Node.Node)
+	require.Equal(t, "web-sidecar-proxy", nodes[0].Service.ID)
+	require.Len(t, nodes[0].Checks, 1)
+
+	// The index is the same as the one of the unfiltered lookup.
+	expectedIdx, _, err := s.CheckConnectServiceNodes(nil, "web", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, expectedIdx, idx)
+
+	// Updating an instance that doesn't match fires the watch, since it may
+	// now match.
+	testRegisterSidecarProxyOpts(t, s, 8, "node1", "web", func(svc *structs.NodeService) {
+		svc.Meta = map[string]string{"version": "v2"}
+	})
+	require.True(t, watchFired(ws))
+
+	idx, nodes, err = s.CheckConnectServiceNodesWithFilter(nil, "web", filter, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), idx)
+	require.Len(t, nodes, 2)
+
+	filter.NodeMeta = map[string]string{"rack": "a"}
+	_, nodes, err = s.CheckConnectServiceNodesWithFilter(nil, "web", filter, nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "node1", nodes[0].Node.Node)
+}
@@ -669,6 +669,95 @@ func testIndexerTableServices() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexMeta: {
+			read: indexValue{
+				source: ServiceKeyValueQuery{
+					Service: "ServiceName",
+					Key:     "KeY",
+					Value:   "VaLuE",
+				},
+				expected: []byte("~\x00servicename\x00KeY\x00VaLuE\x00"),
+			},
+			writeMulti: indexValueMulti{
+				source: &structs.ServiceNode{
+					ServiceName: "ServiceName",
+					ServiceMeta: map[string]string{
+						"MaP-kEy-1": "mAp-VaL-1",
+						"mAp-KeY-2": "MaP-vAl-2",
+					},
+				},
+				expected: [][]byte{
+					[]byte("~\x00servicename\x00MaP-kEy-1\x00mAp-VaL-1\x00"),
+					[]byte("~\x00servicename\x00mAp-KeY-2\x00MaP-vAl-2\x00"),
+				},
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source: ServiceKeyValueQuery{
+							Service:  "ServiceName",
+							Key:      "KeY",
+							Value:    "VaLuE",
+							PeerName: "Peer1",
+						},
+						expected: []byte("peer1\x00servicename\x00KeY\x00VaLuE\x00"),
+					},
+					writeMulti: indexValueMulti{
+						source: &structs.ServiceNode{
+							ServiceName: "ServiceName",
+							ServiceMeta: map[string]string{
+								"MaP-kEy-1": "mAp-VaL-1",
+							},
+							PeerName: "Peer1",
+						},
+						expected: [][]byte{
+							[]byte("peer1\x00servicename\x00MaP-kEy-1\x00mAp-VaL-1\x00"),
+						},
+					},
+				},
+			},
+		},
+		indexTag: {
+			read: indexValue{
+				source: ServiceKeyValueQuery{
+					Service: "ServiceName",
+					Value:   "TaG",
+				},
+				expected: []byte("~\x00servicename\x00tag\x00"),
+			},
+			writeMulti: indexValueMulti{
+				source: &structs.ServiceNode{
+					ServiceName: "ServiceName",
+					ServiceTags: []string{"TaG-1", "tAg-2", "tag-1"},
+				},
+				expected: [][]byte{
+					[]byte("~\x00servicename\x00tag-1\x00"),
+					[]byte("~\x00servicename\x00tag-2\x00"),
+				},
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source: ServiceKeyValueQuery{
+							Service:  "ServiceName",
+							Value:    "TaG",
+							PeerName: "Peer1",
+						},
+						expected: []byte("peer1\x00servicename\x00tag\x00"),
+					},
+					writeMulti: indexValueMulti{
+						source: &structs.ServiceNode{
+							ServiceName: "ServiceName",
+							ServiceTags: []string{"TaG-1"},
+							PeerName:    "Peer1",
+						},
+						expected: [][]byte{
+							[]byte("peer1\x00servicename\x00tag-1\x00"),
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -777,3 +866,71 @@ func testIndexerTableKindServiceNames() map[string]indexerTestCase {
 		},
 	}
 }
+
+func testIndexerTableServiceTombstones() map[string]indexerTestCase {
+	obj := &structs.ServiceTombstone{
+		Node:        "NoDeId",
+		ServiceID:   "SeRviCe",
+		ServiceName: "ServiceName",
+	}
+	objWPeer := &structs.ServiceTombstone{
+		Node:        "NoDeId",
+		ServiceID:   "SeRviCe",
+		ServiceName: "ServiceName",
+		PeerName:    "Peer1",
+	}
+
+	return map[string]indexerTestCase{
+		indexID: {
+			read: indexValue{
+				source: NodeServiceQuery{
+					Node:    "NoDeId",
+					Service: "SeRvIcE",
+				},
+				expected: []byte("~\x00nodeid\x00service\x00"),
+			},
+			write: indexValue{
+				source:   obj,
+				expected: []byte("~\x00nodeid\x00service\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source: NodeServiceQuery{
+							Node:     "NoDeId",
+							PeerName: "Peer1",
+							Service:  "SeRvIcE",
+						},
+						expected: []byte("peer1\x00nodeid\x00service\x00"),
+					},
+					write: indexValue{
+						source:   objWPeer,
+						expected: []byte("peer1\x00nodeid\x00service\x00"),
+					},
+				},
+			},
+		},
+		indexService: {
+			read: indexValue{
+				source:   Query{Value: "ServiceName"},
+				expected: []byte("~\x00servicename\x00"),
+			},
+			write: indexValue{
+				source:   obj,
+				expected: []byte("~\x00servicename\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source:   Query{Value: "ServiceName", PeerName: "Peer1"},
+						expected: []byte("peer1\x00servicename\x00"),
+					},
+					write: indexValue{
+						source:   objWPeer,
+						expected: []byte("peer1\x00servicename\x00"),
+					},
+				},
+			},
+		},
+	}
+}
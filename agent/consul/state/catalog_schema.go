@@ -35,6 +35,7 @@ const (
 	indexGateway     = "gateway"
 	indexUUID        = "uuid"
 	indexMeta        = "meta"
+	indexTag         = "tag"
 	indexCounterOnly = "counter"
 )
 
@@ -172,6 +173,24 @@ func servicesTableSchema() *memdb.TableSchema {
 					writeIndex: indexWithPeerName(indexKindFromServiceNode),
 				},
 			},
+			indexMeta: {
+				Name:         indexMeta,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerMulti[ServiceKeyValueQuery, *structs.ServiceNode]{
+					readIndex:       indexWithPeerName(indexFromServiceMetaQuery),
+					writeIndexMulti: multiIndexWithPeerName(indexMetaFromServiceNode),
+				},
+			},
+			indexTag: {
+				Name:         indexTag,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerMulti[ServiceKeyValueQuery, *structs.ServiceNode]{
+					readIndex:       indexWithPeerName(indexFromServiceTagQuery),
+					writeIndexMulti: multiIndexWithPeerName(indexTagsFromServiceNode),
+				},
+			},
 		},
 	}
 }
@@ -194,6 +213,77 @@ func indexFromServiceNode(n *structs.ServiceNode) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+func indexFromServiceMetaQuery(q ServiceKeyValueQuery) ([]byte, error) {
+	// NOTE: the meta key and value are case-sensitive!
+
+	var b indexBuilder
+	b.String(strings.ToLower(q.Service))
+	b.String(q.Key)
+	b.String(q.Value)
+	return b.Bytes(), nil
+}
+
+func indexMetaFromServiceNode(n *structs.ServiceNode) ([][]byte, error) {
+	if n.ServiceName == "" {
+		return nil, errMissingValueForIndex
+	}
+	name := strings.ToLower(n.ServiceName)
+
+	vals := make([][]byte, 0, len(n.ServiceMeta))
+	for key, val := range n.ServiceMeta {
+		if key == "" {
+			continue
+		}
+
+		var b indexBuilder
+		b.String(name)
+		b.String(key)
+		b.String(val)
+		vals = append(vals, b.Bytes())
+	}
+	if len(vals) == 0 {
+		return nil, errMissingValueForIndex
+	}
+
+	return vals, nil
+}
+
+func indexFromServiceTagQuery(q ServiceKeyValueQuery) ([]byte, error) {
+	var b indexBuilder
+	b.String(strings.ToLower(q.Service))
+	b.String(strings.ToLower(q.Value))
+	return b.Bytes(), nil
+}
+
+func indexTagsFromServiceNode(n *structs.ServiceNode) ([][]byte, error) {
+	if n.ServiceName == "" {
+		return nil, errMissingValueForIndex
+	}
+	name := strings.ToLower(n.ServiceName)
+
+	// Tags are indexed case-insensitively to match the tag filter of the
+	// service endpoints, so tags only differing in case are indexed once.
+	seen := make(map[string]struct{}, len(n.ServiceTags))
+	vals := make([][]byte, 0, len(n.ServiceTags))
+	for _, tag := range n.ServiceTags {
+		tag = strings.ToLower(tag)
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+
+		var b indexBuilder
+		b.String(name)
+		b.String(tag)
+		vals = append(vals, b.Bytes())
+	}
+	if len(vals) == 0 {
+		return nil, errMissingValueForIndex
+	}
+
+	return vals, nil
+}
+
 type nodeIdentifier interface {
 	partitionIndexable
 	peerIndexable
@@ -4340,6 +4340,54 @@ func TestStateStore_CheckServiceTagNodes(t *testing.T) {
 	}
 }
 
+func TestStateStore_CheckServiceTagNodes_TagIndex(t *testing.T) {
+	s := testStateStore(t)
+
+	require.NoError(t, s.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	require.NoError(t, s.EnsureService(2, "foo", &structs.NodeService{ID: "db1", Service: "db", Tags: []string{"Primary", "v1"}, Port: 8000}))
+	require.NoError(t, s.EnsureService(3, "foo", &structs.NodeService{ID: "db2", Service: "db", Tags: []string{"replica", "v1"}, Port: 8001}))
+	require.NoError(t, s.EnsureService(4, "foo", &structs.NodeService{ID: "web", Service: "web", Tags: []string{"primary"}, Port: 80}))
+
+	// The tags are matched case-insensitively, and only the instances of
+	// the service are returned.
+	ws := memdb.NewWatchSet()
+	_, nodes, err := s.CheckServiceTagNodes(ws, "db", []string{"PRIMARY", "v1"}, nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "db1", nodes[0].Service.ID)
+
+	// An instance gaining the tag fires the watch.
+	require.NoError(t, s.EnsureService(5, "foo", &structs.NodeService{ID: "db2", Service: "db", Tags: []string{"primary", "v1"}, Port: 8001}))
+	require.True(t, watchFired(ws))
+
+	_, nodes, err = s.CheckServiceTagNodes(nil, "db", []string{"primary"}, nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+}
+
+func TestStateStore_CheckServiceTagNodes_WatchServiceRegistration(t *testing.T) {
+	s := testStateStore(t)
+
+	require.NoError(t, s.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+
+	// A query for a service which doesn't exist yet is woken when the
+	// service is registered, even without the tag.
+	ws := memdb.NewWatchSet()
+	_, nodes, err := s.CheckServiceTagNodes(ws, "db", []string{"primary"}, nil, "")
+	require.NoError(t, err)
+	require.Len(t, nodes, 0)
+
+	require.NoError(t, s.EnsureService(2, "foo", &structs.NodeService{ID: "db1", Service: "db", Tags: []string{"replica"}, Port: 8000}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	_, _, err = s.ServiceTagNodes(ws, "web", []string{"primary"}, nil, "")
+	require.NoError(t, err)
+
+	require.NoError(t, s.EnsureService(3, "foo", &structs.NodeService{ID: "web", Service: "web", Port: 80}))
+	require.True(t, watchFired(ws))
+}
+
 func TestStateStore_Check_Snapshot(t *testing.T) {
 	s := testStateStore(t)
 
@@ -8818,3 +8866,61 @@ func assertDeepEqual(t *testing.T, x, y interface{}, opts ...cmp.Option) {
 		t.Fatalf("assertion failed: values are not equal\n--- expected\n+++ actual\n%v", diff)
 	}
 }
+
+func TestStateStore_CatalogBatch(t *testing.T) {
+	s := testStateStore(t)
+
+	// Seed a node that will be removed by the batch.
+	testRegisterNode(t, s, 1, "old")
+	testRegisterService(t, s, 2, "old", "web")
+
+	req := &structs.CatalogBatchRequest{
+		Register: []*structs.RegisterRequest{
+			{
+				Node:    "ext1",
+				Address: "10.0.0.1",
+				Service: &structs.NodeService{ID: "billing", Service: "billing", Port: 8080},
+			},
+			{
+				Node:    "ext2",
+				Address: "10.0.0.2",
+				Service: &structs.NodeService{ID: "billing", Service: "billing", Port: 8080},
+			},
+		},
+		Deregister: []*structs.DeregisterRequest{
+			{Node: "old"},
+		},
+	}
+	require.NoError(t, s.CatalogBatch(3, req))
+
+	idx, nodes, err := s.ServiceNodes(nil, "billing", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.Len(t, nodes, 2)
+
+	_, n, err := s.GetNode("old", nil, "")
+	require.NoError(t, err)
+	require.Nil(t, n)
+
+	// A batch with an invalid entry must not apply any of its entries.
+	req = &structs.CatalogBatchRequest{
+		Register: []*structs.RegisterRequest{
+			{
+				Node:    "ext3",
+				Address: "10.0.0.3",
+			},
+			{
+				Node:    "ext4",
+				Address: "10.0.0.4",
+				Check:   &structs.HealthCheck{Node: "other", CheckID: "check"},
+			},
+		},
+	}
+	err = s.CatalogBatch(4, req)
+	testutil.RequireErrorContains(t, err, "Register[1]")
+
+	_, n, err = s.GetNode("ext3", nil, "")
+	require.NoError(t, err)
+	require.Nil(t, n)
+	require.Equal(t, uint64(3), s.maxIndex(tableNodes))
+}
@@ -0,0 +1,215 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const tableServiceTombstones = "service-tombstones"
+
+// serviceTombstonesTableSchema returns a new table schema used for storing
+// the tombstones of service instances that were removed from the catalog.
+func serviceTombstonesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableServiceTombstones,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: indexerSingle[NodeServiceQuery, *structs.ServiceTombstone]{
+					readIndex:  indexWithPeerName(indexFromNodeServiceQuery),
+					writeIndex: indexWithPeerName(indexFromServiceTombstone),
+				},
+			},
+			indexService: {
+				Name:         indexService,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: indexerSingle[Query, *structs.ServiceTombstone]{
+					readIndex:  indexWithPeerName(indexFromQuery),
+					writeIndex: indexWithPeerName(indexServiceNameFromServiceTombstone),
+				},
+			},
+		},
+	}
+}
+
+func indexFromServiceTombstone(t *structs.ServiceTombstone) ([]byte, error) {
+	if t.Node == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(t.Node))
+	b.String(strings.ToLower(t.ServiceID))
+	return b.Bytes(), nil
+}
+
+func indexServiceNameFromServiceTombstone(t *structs.ServiceTombstone) ([]byte, error) {
+	if t.ServiceName == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(t.ServiceName))
+	return b.Bytes(), nil
+}
+
+// SetServiceTombstoneGC sets the GC that is hinted whenever a service
+// tombstone is created, so that the leader can reap them once they expire.
+func (s *Store) SetServiceTombstoneGC(gc *TombstoneGC) {
+	s.serviceTombstoneGC = gc
+}
+
+// insertServiceTombstoneTxn records that the given service instance was
+// removed from the catalog at idx.
+func (s *Store) insertServiceTombstoneTxn(tx WriteTxn, idx uint64, svc *structs.ServiceNode, reason string) error {
+	stone := &structs.ServiceTombstone{
+		Node:           svc.Node,
+		ServiceID:      svc.ServiceID,
+		ServiceName:    svc.ServiceName,
+		ServiceKind:    svc.ServiceKind,
+		PeerName:       svc.PeerName,
+		Reason:         reason,
+		EnterpriseMeta: svc.EnterpriseMeta,
+		RaftIndex: structs.RaftIndex{
+			CreateIndex: idx,
+			ModifyIndex: idx,
+		},
+	}
+	if err := tx.Insert(tableServiceTombstones, stone); err != nil {
+		return fmt.Errorf("failed inserting service tombstone: %s", err)
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableServiceTombstones); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	// If GC is configured, then we hint that this index requires reaping.
+	if gc := s.serviceTombstoneGC; gc != nil {
+		tx.Defer(func() { gc.Hint(idx) })
+	}
+	return nil
+}
+
+// deleteServiceTombstoneTxn removes the tombstone of a service instance that
+// is registered again, since it no longer disappeared.
+func deleteServiceTombstoneTxn(tx WriteTxn, idx uint64, svc *structs.ServiceNode) error {
+	stone, err := tx.First(tableServiceTombstones, indexID, NodeServiceQuery{
+		Node:           svc.Node,
+		Service:        svc.ServiceID,
+		EnterpriseMeta: svc.EnterpriseMeta,
+		PeerName:       svc.PeerName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed service tombstone lookup: %s", err)
+	}
+	if stone == nil {
+		return nil
+	}
+
+	if err := tx.Delete(tableServiceTombstones, stone); err != nil {
+		return fmt.Errorf("failed deleting service tombstone: %s", err)
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableServiceTombstones); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+	return nil
+}
+
+// ServiceTombstones returns the tombstones of the service instances that were
+// recently removed from the catalog. If serviceName is empty, the tombstones
+// of all services are returned.
+func (s *Store) ServiceTombstones(ws memdb.WatchSet, serviceName string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.ServiceTombstones, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+
+	idx := maxIndexWatchTxn(tx, ws, tableServiceTombstones)
+
+	var (
+		iter memdb.ResultIterator
+		err  error
+	)
+	if serviceName != "" {
+		iter, err = tx.Get(tableServiceTombstones, indexService, Query{
+			Value:          serviceName,
+			EnterpriseMeta: *entMeta,
+			PeerName:       peerName,
+		})
+	} else {
+		iter, err = tx.Get(tableServiceTombstones, indexID)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed service tombstone lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var results structs.ServiceTombstones
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		stone := raw.(*structs.ServiceTombstone)
+		if !strings.EqualFold(stone.PeerName, peerName) || !entMeta.Matches(&stone.EnterpriseMeta) {
+			continue
+		}
+		results = append(results, stone)
+	}
+	return idx, results, nil
+}
+
+// ReapServiceTombstones deletes all of the service tombstones with an index
+// less than or equal to the given index. This is used to prevent unbounded
+// storage growth of the tombstones.
+func (s *Store) ReapServiceTombstones(idx uint64, index uint64) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	// This does a full table scan, the same as reaping KV tombstones.
+	stones, err := tx.Get(tableServiceTombstones, indexID)
+	if err != nil {
+		return fmt.Errorf("failed querying service tombstones: %s", err)
+	}
+	var objs []interface{}
+	for stone := stones.Next(); stone != nil; stone = stones.Next() {
+		if stone.(*structs.ServiceTombstone).ModifyIndex <= index {
+			objs = append(objs, stone)
+		}
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	// Do the delete in a separate loop so we don't trash the iterator.
+	for _, obj := range objs {
+		if err := tx.Delete(tableServiceTombstones, obj); err != nil {
+			return fmt.Errorf("failed deleting service tombstone: %s", err)
+		}
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableServiceTombstones); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+	return tx.Commit()
+}
+
+// ServiceTombstones is used to pull all the service tombstones for a snapshot.
+func (s *Snapshot) ServiceTombstones() (memdb.ResultIterator, error) {
+	return s.tx.Get(tableServiceTombstones, indexID)
+}
+
+// ServiceTombstone is used when restoring from a snapshot.
+func (s *Restore) ServiceTombstone(stone *structs.ServiceTombstone) error {
+	if err := s.tx.Insert(tableServiceTombstones, stone); err != nil {
+		return fmt.Errorf("failed restoring service tombstone: %s", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, stone.ModifyIndex, tableServiceTombstones); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestStateStore_ServiceTombstones(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterNode(t, s, 2, "node2")
+	testRegisterService(t, s, 3, "node1", "service1")
+	testRegisterService(t, s, 4, "node2", "service1")
+	testRegisterService(t, s, 5, "node2", "service2")
+
+	// No tombstones to begin with.
+	ws := memdb.NewWatchSet()
+	idx, stones, err := s.ServiceTombstones(ws, "", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Len(t, stones, 0)
+
+	// Deregistering a single service records its tombstone.
+	require.NoError(t, s.DeleteServiceWithReason(6, "node1", "service1", nil, "", "custom"))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	idx, stones, err = s.ServiceTombstones(ws, "service1", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), idx)
+	require.Len(t, stones, 1)
+	require.Equal(t, "node1", stones[0].Node)
+	require.Equal(t, "service1", stones[0].ServiceID)
+	require.Equal(t, "custom", stones[0].Reason)
+	require.Equal(t, uint64(6), stones[0].ModifyIndex)
+
+	// Deregistering a node records a tombstone for each of its services.
+	require.NoError(t, s.DeleteNode(7, "node2", nil, ""))
+	require.True(t, watchFired(ws))
+
+	_, stones, err = s.ServiceTombstones(nil, "", nil, "")
+	require.NoError(t, err)
+	require.Len(t, stones, 3)
+	for _, stone := range stones {
+		if stone.Node == "node2" {
+			require.Equal(t, structs.ServiceTombstoneReasonNodeDeregistered, stone.Reason)
+			require.Equal(t, uint64(7), stone.ModifyIndex)
+		}
+	}
+
+	// Registering the service again clears its tombstone.
+	testRegisterService(t, s, 8, "node1", "service1")
+	idx, stones, err = s.ServiceTombstones(nil, "service1", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), idx)
+	require.Len(t, stones, 1)
+	require.Equal(t, "node2", stones[0].Node)
+
+	// Reaping removes the tombstones up to the given index.
+	require.NoError(t, s.ReapServiceTombstones(9, 7))
+	idx, stones, err = s.ServiceTombstones(nil, "", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), idx)
+	require.Len(t, stones, 0)
+}
+
+func TestStateStore_ServiceTombstones_Snapshot_Restore(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterService(t, s, 2, "node1", "service1")
+	require.NoError(t, s.DeleteService(3, "node1", "service1", nil, ""))
+
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	iter, err := snap.ServiceTombstones()
+	require.NoError(t, err)
+	var dump structs.ServiceTombstones
+	for stone := iter.Next(); stone != nil; stone = iter.Next() {
+		dump = append(dump, stone.(*structs.ServiceTombstone))
+	}
+	require.Len(t, dump, 1)
+	require.Equal(t, structs.ServiceTombstoneReasonDeregistered, dump[0].Reason)
+
+	s2 := testStateStore(t)
+	restore := s2.Restore()
+	for _, stone := range dump {
+		require.NoError(t, restore.ServiceTombstone(stone))
+	}
+	require.NoError(t, restore.Commit())
+
+	idx, stones, err := s2.ServiceTombstones(nil, "service1", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.Equal(t, dump, stones)
+}
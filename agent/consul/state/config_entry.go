@@ -547,6 +547,8 @@ func validateProposedConfigEntryInGraph(
 	case structs.InlineCertificate:
 	case structs.HTTPRoute:
 	case structs.TCPRoute:
+	case structs.APIGatewayPolicy:
+	case structs.SamenessGroup:
 	default:
 		return fmt.Errorf("unhandled kind %q during validation of %q", kindName.Kind, kindName.Name)
 	}
@@ -1150,6 +1152,21 @@ func (s *Store) ReadResolvedServiceConfigEntries(
 		inferredProxyMode = proxyConf.Mode
 	}
 
+	index, meshEntry, err := configEntryTxn(tx, ws, structs.MeshConfig, structs.MeshConfigMesh, entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+	if index > maxIndex {
+		maxIndex = index
+	}
+	if meshEntry != nil {
+		meshConf, ok := meshEntry.(*structs.MeshConfigEntry)
+		if !ok {
+			return 0, nil, fmt.Errorf("invalid mesh config type %T", meshEntry)
+		}
+		res.AddMeshDefaults(meshConf)
+	}
+
 	index, serviceEntry, err := configEntryTxn(tx, ws, structs.ServiceDefaults, serviceName, entMeta)
 	if err != nil {
 		return 0, nil, err
@@ -1394,6 +1411,27 @@ func readDiscoveryChainConfigEntriesTxn(
 		for _, svc := range resolver.ListRelatedServices() {
 			todoResolvers[svc] = struct{}{}
 		}
+
+		for _, failover := range resolver.Failover {
+			if failover.SamenessGroup == "" {
+				continue
+			}
+			// Sameness groups are defined at the partition level.
+			groupMeta := structs.DefaultEnterpriseMetaInPartition(resolver.PartitionOrDefault())
+			groupID := configentry.NewKindName(structs.SamenessGroup, failover.SamenessGroup, groupMeta)
+			if _, ok := res.SamenessGroups[groupID]; ok {
+				continue // already fetched
+			}
+
+			idx, group, err := getSamenessGroupConfigEntryTxn(tx, ws, groupID.Name, overrides, &groupID.EnterpriseMeta)
+			if err != nil {
+				return 0, nil, err
+			}
+			if idx > maxIdx {
+				maxIdx = idx
+			}
+			res.SamenessGroups[groupID] = group
+		}
 	}
 
 	for {
@@ -1420,6 +1458,19 @@ func readDiscoveryChainConfigEntriesTxn(
 			}
 		}
 
+		if _, ok := res.MeshDefaults[svcID.PartitionOrDefault()]; !ok {
+			idx, mesh, err := getMeshConfigEntryTxn(tx, ws, overrides, &svcID.EnterpriseMeta)
+			if err != nil {
+				return 0, nil, err
+			}
+			if idx > maxIdx {
+				maxIdx = idx
+			}
+			if mesh != nil {
+				res.MeshDefaults[mesh.PartitionOrDefault()] = mesh
+			}
+		}
+
 		idx, entry, err := getServiceConfigEntryTxn(tx, ws, svcID.ID, overrides, &svcID.EnterpriseMeta)
 		if err != nil {
 			return 0, nil, err
@@ -1456,6 +1507,11 @@ func readDiscoveryChainConfigEntriesTxn(
 			delete(res.Services, sid)
 		}
 	}
+	for kn, entry := range res.SamenessGroups {
+		if entry == nil {
+			delete(res.SamenessGroups, kn)
+		}
+	}
 
 	return maxIdx, res, nil
 }
@@ -1499,6 +1555,32 @@ func getProxyConfigEntryTxn(
 	return idx, proxy, nil
 }
 
+// getMeshConfigEntryTxn is a convenience method for fetching a
+// mesh kind of config entry.
+//
+// If an override KEY is present for the requested config entry, the index
+// returned will be 0. Any override VALUE (nil or otherwise) will be returned
+// if there is a KEY match.
+func getMeshConfigEntryTxn(
+	tx ReadTxn,
+	ws memdb.WatchSet,
+	overrides map[configentry.KindName]structs.ConfigEntry,
+	entMeta *acl.EnterpriseMeta,
+) (uint64, *structs.MeshConfigEntry, error) {
+	idx, entry, err := configEntryWithOverridesTxn(tx, ws, structs.MeshConfig, structs.MeshConfigMesh, overrides, entMeta)
+	if err != nil {
+		return 0, nil, err
+	} else if entry == nil {
+		return idx, nil, nil
+	}
+
+	mesh, ok := entry.(*structs.MeshConfigEntry)
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid mesh config type %T", entry)
+	}
+	return idx, mesh, nil
+}
+
 // getServiceConfigEntryTxn is a convenience method for fetching a
 // service-defaults kind of config entry.
 //
@@ -1607,6 +1689,33 @@ func getResolverConfigEntryTxn(
 	return idx, resolver, nil
 }
 
+// getSamenessGroupConfigEntryTxn is a convenience method for fetching a
+// sameness-group kind of config entry.
+//
+// If an override KEY is present for the requested config entry, the index
+// returned will be 0. Any override VALUE (nil or otherwise) will be returned
+// if there is a KEY match.
+func getSamenessGroupConfigEntryTxn(
+	tx ReadTxn,
+	ws memdb.WatchSet,
+	name string,
+	overrides map[configentry.KindName]structs.ConfigEntry,
+	entMeta *acl.EnterpriseMeta,
+) (uint64, *structs.SamenessGroupConfigEntry, error) {
+	idx, entry, err := configEntryWithOverridesTxn(tx, ws, structs.SamenessGroup, name, overrides, entMeta)
+	if err != nil {
+		return 0, nil, err
+	} else if entry == nil {
+		return idx, nil, nil
+	}
+
+	group, ok := entry.(*structs.SamenessGroupConfigEntry)
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid service config type %T", entry)
+	}
+	return idx, group, nil
+}
+
 // getServiceIntentionsConfigEntryTxn is a convenience method for fetching a
 // service-intentions kind of config entry.
 //
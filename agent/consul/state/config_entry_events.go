@@ -22,6 +22,7 @@ var configEntryKindToTopic = map[string]stream.Topic{
 	structs.HTTPRoute:         EventTopicHTTPRoute,
 	structs.InlineCertificate: EventTopicInlineCertificate,
 	structs.BoundAPIGateway:   EventTopicBoundAPIGateway,
+	structs.APIGatewayPolicy:  EventTopicAPIGatewayPolicy,
 }
 
 // EventSubjectConfigEntry is a stream.Subject used to route and receive events
@@ -152,6 +153,12 @@ func (s *Store) BoundAPIGatewaySnapshot(req stream.SubscribeRequest, buf stream.
 	return s.configEntrySnapshot(structs.BoundAPIGateway, req, buf)
 }
 
+// APIGatewayPolicySnapshot is a stream.SnapshotFunc that returns a snapshot of
+// api-gateway-policy config entries.
+func (s *Store) APIGatewayPolicySnapshot(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+	return s.configEntrySnapshot(structs.APIGatewayPolicy, req, buf)
+}
+
 func (s *Store) configEntrySnapshot(kind string, req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
 	var (
 		idx     uint64
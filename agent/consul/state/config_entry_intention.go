@@ -101,6 +101,10 @@ func (s *ServiceIntentionSourceIndex) FromObject(obj interface{}) (bool, [][]byt
 
 	vals := make([][]byte, 0, len(ixnEntry.Sources))
 	for _, src := range ixnEntry.Sources {
+		// Sources in a sameness group are always on cluster peers.
+		if src.SamenessGroup != "" {
+			continue
+		}
 		peer := src.Peer
 		if peer == "" {
 			peer = structs.LocalPeerKeyword
@@ -316,7 +320,7 @@ func readSourceIntentionsFromConfigEntriesForServiceTxn(
 			}
 		}
 		for _, src := range entry.Sources {
-			if src.SourceServiceName() == sn {
+			if src.SourceServiceName() == sn && src.SamenessGroup == "" {
 				switch targetType {
 				case structs.IntentionTargetService:
 					if kind == structs.GatewayServiceKindService || kind == structs.GatewayServiceKindUnknown {
@@ -348,7 +352,19 @@ func readDestinationIntentionsFromConfigEntriesTxn(tx ReadTxn, ws memdb.WatchSet
 		if err != nil {
 			return 0, nil, err
 		} else if entry != nil {
-			results = append(results, entry.ToIntentions()...)
+			for _, ixn := range entry.ToIntentions() {
+				if ixn.SourceSamenessGroup == "" {
+					results = append(results, ixn)
+					continue
+				}
+				// Sameness groups are defined at the partition level.
+				groupMeta := structs.DefaultEnterpriseMetaInPartition(sn.PartitionOrDefault())
+				expanded, err := expandSamenessGroupIntentionTxn(tx, ws, ixn, groupMeta)
+				if err != nil {
+					return 0, nil, err
+				}
+				results = append(results, expanded...)
+			}
 		}
 	}
 	// Sort the results by precedence
@@ -356,3 +372,23 @@ func readDestinationIntentionsFromConfigEntriesTxn(tx ReadTxn, ws memdb.WatchSet
 
 	return idx, results, nil
 }
+
+// expandSamenessGroupIntentionTxn returns a copy of an intention whose source
+// is in a sameness group for each cluster peer in the group, so that the
+// intention is enforced like the ones of peered sources.
+func expandSamenessGroupIntentionTxn(tx ReadTxn, ws memdb.WatchSet, ixn *structs.Intention, entMeta *acl.EnterpriseMeta) (structs.Intentions, error) {
+	_, group, err := getSamenessGroupConfigEntryTxn(tx, ws, ixn.SourceSamenessGroup, nil, entMeta)
+	if err != nil {
+		return nil, err
+	} else if group == nil {
+		return nil, nil
+	}
+
+	out := make(structs.Intentions, 0, len(group.Members))
+	for _, member := range group.Members {
+		peered := *ixn
+		peered.SourcePeer = member.Peer
+		out = append(out, &peered)
+	}
+	return out, nil
+}
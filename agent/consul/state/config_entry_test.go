@@ -2065,6 +2065,37 @@ func TestStore_ReadDiscoveryChainConfigEntries_SubsetSplit(t *testing.T) {
 	require.Len(t, entrySet.Services, 1)
 }
 
+func TestStore_ReadDiscoveryChainConfigEntries_SamenessGroupFailover(t *testing.T) {
+	s := testConfigStateStore(t)
+
+	resolver := &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "main",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {SamenessGroup: "group"},
+		},
+	}
+	require.NoError(t, s.EnsureConfigEntry(1, resolver))
+
+	// The chain is read before the group exists.
+	ws := memdb.NewWatchSet()
+	_, entrySet, err := s.readDiscoveryChainConfigEntries(ws, "main", nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, entrySet.SamenessGroups)
+
+	group := &structs.SamenessGroupConfigEntry{
+		Name:    "group",
+		Members: []structs.SamenessGroupMember{{Peer: "cluster-01"}},
+	}
+	require.NoError(t, s.EnsureConfigEntry(2, group))
+	require.True(t, watchFired(ws))
+
+	_, entrySet, err = s.readDiscoveryChainConfigEntries(nil, "main", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, entrySet.SamenessGroups, 1)
+	require.Equal(t, group, entrySet.GetSamenessGroup("group", nil))
+}
+
 // TODO(rb): add ServiceIntentions tests
 
 func TestStore_ValidateGatewayNamesCannotBeShared(t *testing.T) {
@@ -29,7 +29,8 @@ func PBToStreamSubscribeRequest(req *pbsubscribe.SubscribeRequest, entMeta acl.E
 			}
 		}
 
-		if named.Key == "" {
+		// An empty key subscribes to every entry on the KV topic.
+		if named.Key == "" && req.Topic != EventTopicKV {
 			return nil, errors.New("either WildcardSubject or NamedSubject.Key is required")
 		}
 
@@ -43,11 +44,16 @@ func PBToStreamSubscribeRequest(req *pbsubscribe.SubscribeRequest, entMeta acl.E
 		case EventTopicMeshConfig, EventTopicServiceResolver, EventTopicIngressGateway,
 			EventTopicServiceIntentions, EventTopicServiceDefaults, EventTopicAPIGateway,
 			EventTopicTCPRoute, EventTopicHTTPRoute, EventTopicInlineCertificate,
-			EventTopicBoundAPIGateway:
+			EventTopicBoundAPIGateway, EventTopicAPIGatewayPolicy:
 			subject = EventSubjectConfigEntry{
 				Name:           named.Key,
 				EnterpriseMeta: &entMeta,
 			}
+		case EventTopicKV:
+			subject = EventSubjectKV{
+				Key:            named.Key,
+				EnterpriseMeta: entMeta,
+			}
 		case EventTopicServiceList:
 			// Events on this topic are published to SubjectNone, but rather than
 			// exposing this in (and further complicating) the streaming API we rely
@@ -107,6 +107,27 @@ func TestPBToStreamSubscribeRequest(t *testing.T) {
 			},
 			err: nil,
 		},
+		"KV prefix": {
+			req: &pbsubscribe.SubscribeRequest{
+				Topic: EventTopicKV,
+				Subject: &pbsubscribe.SubscribeRequest_NamedSubject{
+					NamedSubject: &pbsubscribe.NamedSubject{},
+				},
+				Token: aclToken,
+				Index: 2,
+			},
+			entMeta: acl.EnterpriseMeta{},
+			expectedSubscribeRequest: &stream.SubscribeRequest{
+				Topic: EventTopicKV,
+				Subject: EventSubjectKV{
+					Key:            "",
+					EnterpriseMeta: acl.EnterpriseMeta{},
+				},
+				Token: aclToken,
+				Index: 2,
+			},
+			err: nil,
+		},
 		"Service list without wildcard returns error": {
 			req: &pbsubscribe.SubscribeRequest{
 				Topic: EventTopicServiceList,
@@ -148,6 +148,13 @@ func areIntentionsInConfigEntries(tx ReadTxn, ws memdb.WatchSet) (bool, error) {
 	return entry.Value == structs.SystemMetadataIntentionFormatConfigValue, nil
 }
 
+// IntentionsIndex returns an index that changes whenever the intentions may
+// have changed, whether they are stored as legacy intentions or in config
+// entries, and when they are migrated from one to the other.
+func (s *Store) IntentionsIndex() uint64 {
+	return s.maxIndex(tableConnectIntentions, tableConfigEntries, tableSystemMetadata)
+}
+
 // LegacyIntentions is like Intentions() but only returns legacy intentions.
 // This is exposed for migration purposes.
 func (s *Store) LegacyIntentions(ws memdb.WatchSet, entMeta *acl.EnterpriseMeta) (uint64, structs.Intentions, error) {
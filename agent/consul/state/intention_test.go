@@ -1268,6 +1268,7 @@ func TestStore_IntentionExact_ConfigEntries(t *testing.T) {
 func TestStore_IntentionMatch_ConfigEntries(t *testing.T) {
 	type testcase struct {
 		name   string
+		groups []*structs.SamenessGroupConfigEntry
 		input  []*structs.ServiceIntentionsConfigEntry
 		query  structs.IntentionQueryMatch
 		expect []structs.Intentions
@@ -1275,6 +1276,10 @@ func TestStore_IntentionMatch_ConfigEntries(t *testing.T) {
 	run := func(t *testing.T, tc testcase) {
 		s := testConfigStateStore(t)
 		idx := uint64(0)
+		for _, group := range tc.groups {
+			idx++
+			require.NoError(t, s.EnsureConfigEntry(idx, group))
+		}
 		for _, conf := range tc.input {
 			require.NoError(t, conf.Normalize())
 			require.NoError(t, conf.Validate())
@@ -1353,6 +1358,117 @@ func TestStore_IntentionMatch_ConfigEntries(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "sameness group intention matched with destination query",
+			groups: []*structs.SamenessGroupConfigEntry{
+				{
+					Name: "group",
+					Members: []structs.SamenessGroupMember{
+						{Peer: "bar"},
+						{Peer: "baz"},
+					},
+				},
+			},
+			input: []*structs.ServiceIntentionsConfigEntry{
+				{
+					Kind: structs.ServiceIntentions,
+					Name: "foo",
+					Sources: []*structs.SourceIntention{
+						{
+							Action:        structs.IntentionActionAllow,
+							Name:          "example",
+							SamenessGroup: "group",
+						},
+						{
+							Action: structs.IntentionActionDeny,
+							Name:   "example",
+							Peer:   "baz",
+						},
+					},
+				},
+			},
+			query: structs.IntentionQueryMatch{
+				Type: structs.IntentionMatchDestination,
+				Entries: []structs.IntentionMatchEntry{
+					{
+						Namespace: "default",
+						Name:      "foo",
+					},
+				},
+			},
+			expect: []structs.Intentions{
+				{
+					{
+						Action:               structs.IntentionActionAllow,
+						SourceType:           structs.IntentionSourceConsul,
+						DestinationPartition: acl.DefaultPartitionName,
+						DestinationNS:        "default",
+						DestinationName:      "foo",
+						SourcePeer:           "bar",
+						SourceSamenessGroup:  "group",
+						SourceNS:             "default",
+						SourceName:           "example",
+						Precedence:           9,
+					},
+					// The intention for the peer wins over the one for the group.
+					{
+						Action:               structs.IntentionActionDeny,
+						SourceType:           structs.IntentionSourceConsul,
+						DestinationPartition: acl.DefaultPartitionName,
+						DestinationNS:        "default",
+						DestinationName:      "foo",
+						SourcePeer:           "baz",
+						SourceNS:             "default",
+						SourceName:           "example",
+						Precedence:           9,
+					},
+					{
+						Action:               structs.IntentionActionAllow,
+						SourceType:           structs.IntentionSourceConsul,
+						DestinationPartition: acl.DefaultPartitionName,
+						DestinationNS:        "default",
+						DestinationName:      "foo",
+						SourcePeer:           "baz",
+						SourceSamenessGroup:  "group",
+						SourceNS:             "default",
+						SourceName:           "example",
+						Precedence:           9,
+					},
+				},
+			},
+		},
+		{
+			name: "sameness group intention cannot be queried by source",
+			groups: []*structs.SamenessGroupConfigEntry{
+				{
+					Name:    "group",
+					Members: []structs.SamenessGroupMember{{Peer: "bar"}},
+				},
+			},
+			input: []*structs.ServiceIntentionsConfigEntry{
+				{
+					Kind: structs.ServiceIntentions,
+					Name: "foo",
+					Sources: []*structs.SourceIntention{
+						{
+							Action:        structs.IntentionActionAllow,
+							Name:          "example",
+							SamenessGroup: "group",
+						},
+					},
+				},
+			},
+			query: structs.IntentionQueryMatch{
+				Type: structs.IntentionMatchSource,
+				Entries: []structs.IntentionMatchEntry{
+					{
+						Namespace: "default",
+						Name:      "example",
+					},
+				},
+			},
+			expect: []structs.Intentions{nil},
+		},
 		{
 			// This behavior may change in the future but this test is in place
 			// to ensure peered intentions cannot accidentally be queried by source
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib/jsonpatch"
 )
 
 const (
@@ -15,6 +16,7 @@ const (
 	tableTombstones = "tombstones"
 
 	indexSession = "session"
+	indexExpires = "expires"
 )
 
 // kvsTableSchema returns a new table schema used for storing structs.DirEntry
@@ -36,10 +38,32 @@ func kvsTableSchema() *memdb.TableSchema {
 					Field: "Session",
 				},
 			},
+			indexExpires: {
+				Name:         indexExpires,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[*TimeQuery, *structs.DirEntry]{
+					readIndex:  indexFromTimeQuery,
+					writeIndex: indexExpiresFromDirEntry,
+				},
+			},
 		},
 	}
 }
 
+func indexExpiresFromDirEntry(e *structs.DirEntry) ([]byte, error) {
+	if !e.HasExpirationTime() {
+		return nil, errMissingValueForIndex
+	}
+	if e.ExpirationTime.Unix() < 0 {
+		return nil, fmt.Errorf("key expiration time cannot be before the unix epoch: %s", e.ExpirationTime)
+	}
+
+	var b indexBuilder
+	b.Time(*e.ExpirationTime)
+	return b.Bytes(), nil
+}
+
 // indexFromIDValue creates an index key from any struct that implements singleValueID
 func indexFromIDValue(e singleValueID) ([]byte, error) {
 	v := e.IDValue()
@@ -256,6 +280,28 @@ func (s *Store) kvsListTxn(tx ReadTxn,
 	return idx, entries, nil
 }
 
+// KVSListExpired returns up to max entries that expired before asOf, in
+// order of expiration.
+func (s *Store) KVSListExpired(asOf time.Time, max int) (structs.DirEntries, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableKVs, indexExpires)
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+
+	var entries structs.DirEntries
+	for raw := iter.Next(); raw != nil && len(entries) < max; raw = iter.Next() {
+		entry := raw.(*structs.DirEntry)
+		if !entry.ExpirationTime.Before(asOf) {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // KVSDelete is used to perform a shallow delete on a single key in the
 // the state store.
 func (s *Store) KVSDelete(idx uint64, key string, entMeta *acl.EnterpriseMeta) error {
@@ -383,6 +429,50 @@ func kvsSetCASTxn(tx WriteTxn, idx uint64, entry *structs.DirEntry) (bool, error
 	return true, nil
 }
 
+// kvsPatchTxn is used to apply the JSON patch given as the value of entry to
+// the value of an existing key inside an existing transaction. The patch is
+// applied as a JSON Patch if it's an array and as a JSON Merge Patch
+// otherwise. A non-zero ModifyIndex gives the patch CAS semantics. The flags
+// and session of the existing entry are kept, and so is its expiration unless
+// the patch sets a new one. The patched value must not be longer than maxSize,
+// and is compressed from the given threshold, which is carried by the op as
+// the server setting can't be used in the FSM.
+func kvsPatchTxn(tx WriteTxn, idx uint64, entry *structs.DirEntry, maxSize, compressionThreshold int) (bool, error) {
+	existing, err := tx.First(tableKVs, indexID, entry)
+	if err != nil {
+		return false, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	e, ok := existing.(*structs.DirEntry)
+	if !ok {
+		return false, fmt.Errorf("key %q doesn't exist", entry.Key)
+	}
+	if entry.ModifyIndex != 0 && entry.ModifyIndex != e.ModifyIndex {
+		return false, nil
+	}
+
+	if e, err = e.Decompressed(); err != nil {
+		return false, err
+	}
+	value, err := jsonpatch.Apply(e.Value, entry.Value, maxSize)
+	if err != nil {
+		return false, fmt.Errorf("failed to patch key %q: %v", entry.Key, err)
+	}
+	entry.Value = value
+	entry.Compressed = false
+	if err := entry.CompressValue(compressionThreshold); err != nil {
+		return false, err
+	}
+	entry.Flags = e.Flags
+	if entry.ExpirationTime == nil {
+		entry.ExpirationTime = e.ExpirationTime
+	}
+
+	if err := kvsSetTxn(tx, idx, entry, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // KVSDeleteTree is used to do a recursive delete on a key prefix
 // in the state store. If any keys are modified, the last index is
 // set, otherwise this is a no-op.
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbcommon"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+// EventSubjectKV is a stream.Subject used to route and receive events for a
+// single KV entry, or for all the entries under a prefix when Key is empty or
+// ends with "/".
+type EventSubjectKV struct {
+	Key            string
+	EnterpriseMeta acl.EnterpriseMeta
+}
+
+func (s EventSubjectKV) String() string {
+	return fmt.Sprintf(
+		"%s/%s/%s",
+		s.EnterpriseMeta.PartitionOrDefault(),
+		s.EnterpriseMeta.NamespaceOrDefault(),
+		s.Key,
+	)
+}
+
+// EventPayloadKV is used as the Payload for a stream.Event to indicate changes
+// to a KV entry.
+//
+// The event for a change is published once to the subject of the entry's key
+// and once to the subject of each of its parent prefixes, so that subscribers
+// to a prefix receive it without the publisher having to match prefixes.
+type EventPayloadKV struct {
+	Op    pbsubscribe.KVUpdate_UpdateOp
+	Value *structs.DirEntry
+
+	// key is the subject key the event is published to. It is either the key
+	// of the entry or one of its parent prefixes.
+	key string
+}
+
+func (e EventPayloadKV) Subject() stream.Subject {
+	return EventSubjectKV{
+		Key:            e.key,
+		EnterpriseMeta: e.Value.EnterpriseMeta,
+	}
+}
+
+func (e EventPayloadKV) HasReadPermission(authz acl.Authorizer) bool {
+	var authzContext acl.AuthorizerContext
+	e.Value.EnterpriseMeta.FillAuthzContext(&authzContext)
+	return authz.KeyRead(e.Value.Key, &authzContext) == acl.Allow
+}
+
+func (e EventPayloadKV) ToSubscriptionEvent(idx uint64) *pbsubscribe.Event {
+	update := &pbsubscribe.KVUpdate{
+		Op:             e.Op,
+		Key:            e.Value.Key,
+		Flags:          e.Value.Flags,
+		Session:        e.Value.Session,
+		LockIndex:      e.Value.LockIndex,
+		CreateIndex:    e.Value.CreateIndex,
+		ModifyIndex:    e.Value.ModifyIndex,
+		EnterpriseMeta: pbcommon.NewEnterpriseMetaFromStructs(e.Value.EnterpriseMeta),
+	}
+	if e.Op == pbsubscribe.KVUpdate_Upsert {
+		update.Value = e.Value.Value
+	}
+	return &pbsubscribe.Event{
+		Index:   idx,
+		Payload: &pbsubscribe.Event_KV{KV: update},
+	}
+}
+
+// kvSubjectKeys returns the subject keys the events for key are published to:
+// the empty prefix, each of the parent prefixes of key, and key itself.
+func kvSubjectKeys(key string) []string {
+	keys := []string{""}
+	for i := 0; i < len(key); i++ {
+		if key[i] == '/' {
+			keys = append(keys, key[:i+1])
+		}
+	}
+	if key != "" && !strings.HasSuffix(key, "/") {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func kvEvents(idx uint64, op pbsubscribe.KVUpdate_UpdateOp, entry *structs.DirEntry) []stream.Event {
+	keys := kvSubjectKeys(entry.Key)
+	events := make([]stream.Event, 0, len(keys))
+	for _, key := range keys {
+		events = append(events, stream.Event{
+			Topic: EventTopicKV,
+			Index: idx,
+			Payload: EventPayloadKV{
+				Op:    op,
+				Value: entry,
+				key:   key,
+			},
+		})
+	}
+	return events
+}
+
+// KVEventsFromChanges returns events that will be emitted when KV entries
+// change in the state store.
+func KVEventsFromChanges(_ ReadTxn, changes Changes) ([]stream.Event, error) {
+	var events []stream.Event
+	for _, c := range changes.Changes {
+		if c.Table != tableKVs {
+			continue
+		}
+
+		entry := changeObject(c).(*structs.DirEntry)
+		op := pbsubscribe.KVUpdate_Upsert
+		if c.Deleted() {
+			op = pbsubscribe.KVUpdate_Delete
+		} else {
+			var err error
+			if entry, err = entry.Decompressed(); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, kvEvents(changes.Index, op, entry)...)
+	}
+	return events, nil
+}
+
+// KVSnapshot is a stream.SnapshotFunc that returns a snapshot of the KV entry
+// or the KV entries under the prefix of the subscription.
+func (s *Store) KVSnapshot(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+	subject, ok := req.Subject.(EventSubjectKV)
+	if !ok {
+		return 0, fmt.Errorf("expected SubscribeRequest.Subject to be a: state.EventSubjectKV, was a: %T", req.Subject)
+	}
+
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	var (
+		idx     uint64
+		entries structs.DirEntries
+		err     error
+	)
+	if subject.Key == "" || strings.HasSuffix(subject.Key, "/") {
+		idx, entries, err = s.kvsListTxn(tx, nil, subject.Key, subject.EnterpriseMeta)
+	} else {
+		var entry *structs.DirEntry
+		idx, entry, err = kvsGetTxn(tx, nil, subject.Key, subject.EnterpriseMeta)
+		if entry != nil {
+			entries = structs.DirEntries{entry}
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if entries, err = entries.Decompressed(); err != nil {
+		return 0, err
+	}
+
+	if l := len(entries); l != 0 {
+		events := make([]stream.Event, l)
+		for i, entry := range entries {
+			events[i] = stream.Event{
+				Topic: req.Topic,
+				Index: idx,
+				Payload: EventPayloadKV{
+					Op:    pbsubscribe.KVUpdate_Upsert,
+					Value: entry,
+					key:   subject.Key,
+				},
+			}
+		}
+		buf.Append(events)
+	}
+	return idx, nil
+}
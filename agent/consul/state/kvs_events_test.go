@@ -0,0 +1,121 @@
+package state
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+func TestKVSubjectKeys(t *testing.T) {
+	require.Equal(t, []string{""}, kvSubjectKeys(""))
+	require.Equal(t, []string{"", "foo"}, kvSubjectKeys("foo"))
+	require.Equal(t, []string{"", "foo/"}, kvSubjectKeys("foo/"))
+	require.Equal(t, []string{"", "foo/", "foo/bar/", "foo/bar/baz"}, kvSubjectKeys("foo/bar/baz"))
+}
+
+func TestKVEventsFromChanges(t *testing.T) {
+	const changeIndex uint64 = 123
+
+	store := testStateStore(t)
+
+	tx := store.db.WriteTxn(0)
+	require.NoError(t, kvsSetTxn(tx, 0, &structs.DirEntry{Key: "foo/bar", Value: []byte("1")}, false))
+	events, err := KVEventsFromChanges(tx, Changes{Index: changeIndex, Changes: tx.Changes()})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	require.Len(t, events, 3)
+	var subjects []string
+	for _, event := range events {
+		require.Equal(t, EventTopicKV, event.Topic)
+		require.Equal(t, changeIndex, event.Index)
+
+		payload := event.Payload.(EventPayloadKV)
+		require.Equal(t, pbsubscribe.KVUpdate_Upsert, payload.Op)
+		require.Equal(t, "foo/bar", payload.Value.Key)
+		subjects = append(subjects, payload.Subject().(EventSubjectKV).Key)
+	}
+	require.Equal(t, []string{"", "foo/", "foo/bar"}, subjects)
+
+	tx = store.db.WriteTxn(0)
+	t.Cleanup(tx.Abort)
+	require.NoError(t, store.kvsDeleteTreeTxn(tx, 1, "foo/", nil))
+	events, err = KVEventsFromChanges(tx, Changes{Index: changeIndex, Changes: tx.Changes()})
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+	for _, event := range events {
+		payload := event.Payload.(EventPayloadKV)
+		require.Equal(t, pbsubscribe.KVUpdate_Delete, payload.Op)
+		require.Equal(t, "foo/bar", payload.Value.Key)
+
+		update := payload.ToSubscriptionEvent(event.Index).GetKV()
+		require.Equal(t, pbsubscribe.KVUpdate_Delete, update.Op)
+		require.Nil(t, update.Value)
+	}
+}
+
+func TestKVEventsFromChanges_Compressed(t *testing.T) {
+	store := testStateStore(t)
+
+	value := []byte(strings.Repeat("abc", 100))
+	entry := &structs.DirEntry{Key: "foo", Value: value}
+	require.NoError(t, entry.CompressValue(1))
+
+	tx := store.db.WriteTxn(0)
+	t.Cleanup(tx.Abort)
+	require.NoError(t, kvsSetTxn(tx, 0, entry, false))
+	events, err := KVEventsFromChanges(tx, Changes{Index: 1, Changes: tx.Changes()})
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	for _, event := range events {
+		update := event.Payload.(EventPayloadKV).ToSubscriptionEvent(event.Index).GetKV()
+		require.Equal(t, value, update.Value)
+	}
+	require.True(t, entry.Compressed, "the stored entry should be left compressed")
+}
+
+func TestKVSnapshot(t *testing.T) {
+	store := testStateStore(t)
+	require.NoError(t, store.KVSSet(1, &structs.DirEntry{Key: "foo/bar", Value: []byte("1")}))
+	require.NoError(t, store.KVSSet(2, &structs.DirEntry{Key: "foo/baz", Value: []byte("2")}))
+	require.NoError(t, store.KVSSet(3, &structs.DirEntry{Key: "qux", Value: []byte("3")}))
+
+	testCases := map[string]struct {
+		key      string
+		expected []string
+		index    uint64
+	}{
+		"all":      {key: "", expected: []string{"foo/bar", "foo/baz", "qux"}, index: 3},
+		"prefix":   {key: "foo/", expected: []string{"foo/bar", "foo/baz"}, index: 2},
+		"key":      {key: "foo/bar", expected: []string{"foo/bar"}, index: 3},
+		"no match": {key: "nope/", expected: nil, index: 3},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			buf := &snapshotAppender{}
+
+			req := stream.SubscribeRequest{Topic: EventTopicKV, Subject: EventSubjectKV{Key: tc.key}}
+			idx, err := store.KVSnapshot(req, buf)
+			require.NoError(t, err)
+			require.Equal(t, tc.index, idx)
+
+			var keys []string
+			for _, events := range buf.events {
+				for _, event := range events {
+					payload := event.Payload.(EventPayloadKV)
+					require.Equal(t, pbsubscribe.KVUpdate_Upsert, payload.Op)
+					require.Equal(t, req.Subject, payload.Subject())
+					keys = append(keys, payload.Value.Key)
+				}
+			}
+			require.Equal(t, tc.expected, keys)
+		})
+	}
+}
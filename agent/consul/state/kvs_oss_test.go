@@ -4,11 +4,14 @@
 package state
 
 import (
+	"time"
+
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
 func testIndexerTableKVs() map[string]indexerTestCase {
+	expirationTime := time.Unix(1, 0)
 	return map[string]indexerTestCase{
 		indexID: {
 			read: indexValue{
@@ -34,6 +37,24 @@ func testIndexerTableKVs() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexExpires: {
+			read: indexValue{
+				source:   &TimeQuery{Value: time.Unix(1, 0)},
+				expected: []byte{0, 0, 0, 0, 0, 0, 0, 1},
+			},
+			write: indexValue{
+				source:   &structs.DirEntry{Key: "TheKey", ExpirationTime: &expirationTime},
+				expected: []byte{0, 0, 0, 0, 0, 0, 0, 1},
+			},
+			extra: []indexerTestCase{
+				{
+					write: indexValue{
+						source:               &structs.DirEntry{Key: "TheKey"},
+						expectedIndexMissing: true,
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -485,6 +485,46 @@ func TestStateStore_KVSList(t *testing.T) {
 	}
 }
 
+func TestStateStore_KVSListExpired(t *testing.T) {
+	s := testStateStore(t)
+
+	now := time.Now()
+	expires := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	entries := []*structs.DirEntry{
+		{Key: "later", Value: []byte("a"), ExpirationTime: expires(time.Hour)},
+		{Key: "expired2", Value: []byte("b"), ExpirationTime: expires(-time.Minute)},
+		{Key: "permanent", Value: []byte("c")},
+		{Key: "expired1", Value: []byte("d"), ExpirationTime: expires(-time.Hour)},
+	}
+	for i, entry := range entries {
+		require.NoError(t, s.KVSSet(uint64(i+1), entry))
+	}
+
+	keys := func(entries structs.DirEntries) []string {
+		var keys []string
+		for _, entry := range entries {
+			keys = append(keys, entry.Key)
+		}
+		return keys
+	}
+
+	// Expired entries are returned in order of expiration.
+	expired, err := s.KVSListExpired(now, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"expired1", "expired2"}, keys(expired))
+
+	expired, err = s.KVSListExpired(now, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"expired1"}, keys(expired))
+
+	expired, err = s.KVSListExpired(now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"expired1", "expired2", "later"}, keys(expired))
+}
+
 func TestStateStore_KVSDelete(t *testing.T) {
 	s := testStateStore(t)
 
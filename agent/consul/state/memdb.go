@@ -191,6 +191,8 @@ var (
 	EventTopicHTTPRoute            = pbsubscribe.Topic_HTTPRoute
 	EventTopicInlineCertificate    = pbsubscribe.Topic_InlineCertificate
 	EventTopicBoundAPIGateway      = pbsubscribe.Topic_BoundAPIGateway
+	EventTopicAPIGatewayPolicy     = pbsubscribe.Topic_APIGatewayPolicy
+	EventTopicKV                   = pbsubscribe.Topic_KV
 )
 
 func processDBChanges(tx ReadTxn, changes Changes) ([]stream.Event, error) {
@@ -201,6 +203,7 @@ func processDBChanges(tx ReadTxn, changes Changes) ([]stream.Event, error) {
 		ServiceHealthEventsFromChanges,
 		ServiceListUpdateEventsFromChanges,
 		ConfigEntryEventsFromChanges,
+		KVEventsFromChanges,
 		// TODO: add other table handlers here.
 	}
 	for _, fn := range fns {
@@ -18,10 +18,11 @@ import (
 )
 
 const (
-	tablePeering             = "peering"
-	tablePeeringTrustBundles = "peering-trust-bundles"
-	tablePeeringSecrets      = "peering-secrets"
-	tablePeeringSecretUUIDs  = "peering-secret-uuids"
+	tablePeering              = "peering"
+	tablePeeringTrustBundles  = "peering-trust-bundles"
+	tablePeeringSecrets       = "peering-secrets"
+	tablePeeringSecretUUIDs   = "peering-secret-uuids"
+	tablePeeringConfigEntries = "peering-config-entries"
 )
 
 func peeringTableSchema() *memdb.TableSchema {
@@ -78,6 +79,24 @@ func peeringTrustBundlesTableSchema() *memdb.TableSchema {
 	}
 }
 
+func peeringConfigEntriesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tablePeeringConfigEntries,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: indexerSingleWithPrefix[Query, *pbpeering.PeeringConfigEntries, any]{
+					readIndex:   indexPeeringFromQuery,
+					writeIndex:  indexFromPeeringConfigEntries,
+					prefixIndex: prefixIndexFromQueryNoNamespace,
+				},
+			},
+		},
+	}
+}
+
 func peeringSecretsTableSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
 		Name: tablePeeringSecrets,
@@ -782,8 +801,10 @@ func exportedServicesForPeerTxn(
 	}
 
 	var (
-		normalSet = make(map[structs.ServiceName]struct{})
-		discoSet  = make(map[structs.ServiceName]struct{})
+		normalSet      = make(map[structs.ServiceName]struct{})
+		discoSet       = make(map[structs.ServiceName]struct{})
+		configEntries  = make(map[structs.ServiceName][]string)
+		redactedFields = make(map[structs.ServiceName][]string)
 	)
 
 	// At least one of the following should be true for a name for it to
@@ -801,6 +822,7 @@ func exportedServicesForPeerTxn(
 		svcMeta := acl.NewEnterpriseMetaWithPartition(entMeta.PartitionOrDefault(), svc.Namespace)
 
 		sawPeer := false
+		var redact []string
 		for _, consumer := range svc.Consumers {
 			name := structs.NewServiceName(svc.Name, &svcMeta)
 
@@ -812,9 +834,16 @@ func exportedServicesForPeerTxn(
 				continue
 			}
 			sawPeer = true
+			redact = consumer.RedactFields
 
 			if svc.Name != structs.WildcardSpecifier {
 				normalSet[name] = struct{}{}
+				if len(svc.ConfigEntries) > 0 {
+					configEntries[name] = svc.ConfigEntries
+				}
+				if len(consumer.RedactFields) > 0 {
+					redactedFields[name] = consumer.RedactFields
+				}
 			}
 		}
 
@@ -832,7 +861,18 @@ func exportedServicesForPeerTxn(
 				if s.Service.Name == structs.ConsulServiceName {
 					continue
 				}
+				if svc.Excludes(s.Service.Name) {
+					continue
+				}
 				normalSet[s.Service] = struct{}{}
+
+				// Services exported explicitly take precedence over the wildcard.
+				if _, ok := configEntries[s.Service]; !ok && len(svc.ConfigEntries) > 0 {
+					configEntries[s.Service] = svc.ConfigEntries
+				}
+				if _, ok := redactedFields[s.Service]; !ok && len(redact) > 0 {
+					redactedFields[s.Service] = redact
+				}
 			}
 
 			// list all config entries of kind service-resolver, service-router, service-splitter?
@@ -844,6 +884,9 @@ func exportedServicesForPeerTxn(
 				maxIdx = idx
 			}
 			for _, sn := range discoChains {
+				if svc.Excludes(sn.Name) {
+					continue
+				}
 				discoSet[sn] = struct{}{}
 			}
 		}
@@ -912,9 +955,18 @@ func exportedServicesForPeerTxn(
 
 	structs.ServiceList(normal).Sort()
 
+	if len(configEntries) == 0 {
+		configEntries = nil
+	}
+	if len(redactedFields) == 0 {
+		redactedFields = nil
+	}
+
 	list := &structs.ExportedServiceList{
-		Services:    normal,
-		DiscoChains: chainInfo,
+		Services:       normal,
+		DiscoChains:    chainInfo,
+		ConfigEntries:  configEntries,
+		RedactedFields: redactedFields,
 	}
 
 	return maxIdx, list, nil
@@ -989,6 +1041,9 @@ func listServicesExportedToAnyPeerByConfigEntry(
 				maxIdx = idx
 			}
 			for _, sn := range discoChains {
+				if svc.Excludes(sn.Name) {
+					continue
+				}
 				found[sn] = struct{}{}
 			}
 		}
@@ -1251,6 +1306,150 @@ func (s *Store) PeeringTrustBundleDelete(idx uint64, q Query) error {
 	return tx.Commit()
 }
 
+// PeeringConfigEntriesRead returns the config entries imported from the peer
+// name given as the query value.
+func (s *Store) PeeringConfigEntriesRead(ws memdb.WatchSet, q Query) (uint64, *pbpeering.PeeringConfigEntries, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	watchCh, pceRaw, err := tx.FirstWatch(tablePeeringConfigEntries, indexID, q)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed peering config entries lookup: %w", err)
+	}
+
+	pce, ok := pceRaw.(*pbpeering.PeeringConfigEntries)
+	if pceRaw != nil && !ok {
+		return 0, nil, fmt.Errorf("invalid type %T", pce)
+	}
+	ws.Add(watchCh)
+
+	if pce == nil {
+		return maxIndexWatchTxn(tx, ws, partitionedIndexEntryName(tablePeeringConfigEntries, q.PartitionOrDefault())), nil, nil
+	}
+	return pce.ModifyIndex, pce, nil
+}
+
+// PeeringConfigEntriesWrite stores the config entries imported from a peer.
+// Any config entries previously imported from the same peer are replaced.
+// If there is no corresponding peering, then an error is returned.
+func (s *Store) PeeringConfigEntriesWrite(idx uint64, pce *pbpeering.PeeringConfigEntries) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if pce.PeerName == "" {
+		return errors.New("missing peer name")
+	}
+
+	q := Query{
+		Value:          pce.PeerName,
+		EnterpriseMeta: *structs.NodeEnterpriseMetaInPartition(pce.Partition),
+	}
+	_, existingPeering, err := peeringReadTxn(tx, nil, q)
+	if err != nil {
+		return err
+	}
+	if existingPeering == nil {
+		return fmt.Errorf("cannot write peering config entries for unknown peering %s", pce.PeerName)
+	}
+	if existingPeering.State == pbpeering.PeeringState_DELETING {
+		return fmt.Errorf("cannot write to peering that is marked for deletion")
+	}
+
+	existingRaw, err := tx.First(tablePeeringConfigEntries, indexID, q)
+	if err != nil {
+		return fmt.Errorf("failed peering config entries lookup: %w", err)
+	}
+
+	existing, ok := existingRaw.(*pbpeering.PeeringConfigEntries)
+	if existingRaw != nil && !ok {
+		return fmt.Errorf("invalid type %T", existingRaw)
+	}
+
+	if existing != nil {
+		pce.CreateIndex = existing.CreateIndex
+	} else {
+		pce.CreateIndex = idx
+	}
+	pce.ModifyIndex = idx
+
+	if err := tx.Insert(tablePeeringConfigEntries, pce); err != nil {
+		return fmt.Errorf("failed inserting peering config entries: %w", err)
+	}
+	if err := updatePeeringConfigEntriesTableIndexes(tx, idx, pce.PartitionOrDefault()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) PeeringConfigEntriesDelete(idx uint64, q Query) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	existing, err := tx.First(tablePeeringConfigEntries, indexID, q)
+	if err != nil {
+		return fmt.Errorf("failed peering config entries lookup: %v", err)
+	}
+
+	if existing == nil {
+		return nil
+	}
+
+	if err := tx.Delete(tablePeeringConfigEntries, existing); err != nil {
+		return fmt.Errorf("failed deleting peering config entries: %v", err)
+	}
+
+	if err := updatePeeringConfigEntriesTableIndexes(tx, idx, q.PartitionOrDefault()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExportedConfigEntriesForPeer returns the config entries that have been
+// opted in for replication to the given peer by the exported-services config
+// entry of the peering's partition.
+func (s *Store) ExportedConfigEntriesForPeer(ws memdb.WatchSet, peerID string, dc string) (uint64, []structs.ConfigEntry, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	peering, err := peeringReadByIDTxn(tx, ws, peerID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read peering: %w", err)
+	}
+	if peering == nil {
+		return 0, nil, nil
+	}
+
+	maxIdx, list, err := exportedServicesForPeerTxn(ws, tx, peering, dc)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(list.ConfigEntries) == 0 {
+		return maxIdx, nil, nil
+	}
+
+	var entries []structs.ConfigEntry
+	for _, sn := range list.Services {
+		kinds, ok := list.ConfigEntries[sn]
+		if !ok {
+			continue
+		}
+		for _, kind := range kinds {
+			idx, entry, err := configEntryTxn(tx, ws, kind, sn.Name, &sn.EnterpriseMeta)
+			if err != nil {
+				return 0, nil, err
+			}
+			if idx > maxIdx {
+				maxIdx = idx
+			}
+			if entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return maxIdx, entries, nil
+}
+
 func (s *Snapshot) Peerings() (memdb.ResultIterator, error) {
 	return s.tx.Get(tablePeering, indexName)
 }
@@ -1259,6 +1458,10 @@ func (s *Snapshot) PeeringTrustBundles() (memdb.ResultIterator, error) {
 	return s.tx.Get(tablePeeringTrustBundles, indexID)
 }
 
+func (s *Snapshot) PeeringConfigEntries() (memdb.ResultIterator, error) {
+	return s.tx.Get(tablePeeringConfigEntries, indexID)
+}
+
 func (s *Snapshot) PeeringSecrets() (memdb.ResultIterator, error) {
 	return s.tx.Get(tablePeeringSecrets, indexID)
 }
@@ -1285,6 +1488,16 @@ func (r *Restore) PeeringTrustBundle(ptb *pbpeering.PeeringTrustBundle) error {
 	return nil
 }
 
+func (r *Restore) PeeringConfigEntries(pce *pbpeering.PeeringConfigEntries) error {
+	if err := r.tx.Insert(tablePeeringConfigEntries, pce); err != nil {
+		return fmt.Errorf("failed restoring peering config entries: %w", err)
+	}
+	if err := updatePeeringConfigEntriesTableIndexes(r.tx, pce.ModifyIndex, pce.PartitionOrDefault()); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (r *Restore) PeeringSecrets(p *pbpeering.PeeringSecrets) error {
 	if err := r.tx.Insert(tablePeeringSecrets, p); err != nil {
 		return fmt.Errorf("failed restoring peering secrets: %w", err)
@@ -1351,6 +1564,9 @@ func peersForServiceTxn(
 	// 		Namespace: Exact, Service: Exact
 	for i, service := range entry.Services {
 		switch {
+		case service.Excludes(serviceName):
+			// Excluded services are not exported by this wildcard.
+
 		case service.Namespace == structs.WildcardSpecifier:
 			wildcardNamespaceIdx = i
 
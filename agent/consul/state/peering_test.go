@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbconfigentry"
 	"github.com/hashicorp/consul/proto/pbpeering"
 	"github.com/hashicorp/consul/proto/prototest"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -1740,6 +1741,135 @@ func TestStore_PeeringTrustBundleDelete(t *testing.T) {
 	require.Nil(t, ptb)
 }
 
+func TestStore_PeeringConfigEntries(t *testing.T) {
+	s := NewStateStore(nil)
+	insertTestPeeringTrustBundles(t, s)
+
+	q := Query{Value: "foo"}
+
+	_, pce, err := s.PeeringConfigEntriesRead(nil, q)
+	require.NoError(t, err)
+	require.Nil(t, pce)
+
+	entries := &pbpeering.PeeringConfigEntries{
+		PeerName: "foo",
+		Entries: []*pbconfigentry.ConfigEntry{
+			pbconfigentry.ConfigEntryFromStructs(&structs.ServiceConfigEntry{
+				Kind:     structs.ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+			}),
+		},
+	}
+	require.NoError(t, s.PeeringConfigEntriesWrite(10, entries))
+
+	idx, pce, err := s.PeeringConfigEntriesRead(nil, q)
+	require.NoError(t, err)
+	require.NotNil(t, pce)
+	require.Equal(t, uint64(10), idx)
+	require.Len(t, pce.Entries, 1)
+
+	// Overwriting keeps the original create index.
+	entries.Entries = nil
+	require.NoError(t, s.PeeringConfigEntriesWrite(11, entries))
+
+	_, pce, err = s.PeeringConfigEntriesRead(nil, q)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), pce.CreateIndex)
+	require.Equal(t, uint64(11), pce.ModifyIndex)
+	require.Empty(t, pce.Entries)
+
+	err = s.PeeringConfigEntriesWrite(12, &pbpeering.PeeringConfigEntries{PeerName: "unknown"})
+	require.ErrorContains(t, err, "unknown peering")
+
+	require.NoError(t, s.PeeringConfigEntriesDelete(13, q))
+
+	_, pce, err = s.PeeringConfigEntriesRead(nil, q)
+	require.NoError(t, err)
+	require.Nil(t, pce)
+}
+
+func TestStateStore_ExportedConfigEntriesForPeer(t *testing.T) {
+	s := NewStateStore(nil)
+
+	var lastIdx uint64
+
+	lastIdx++
+	require.NoError(t, s.CASetConfig(lastIdx, &structs.CAConfiguration{
+		Provider:  "consul",
+		ClusterID: connect.TestClusterID,
+	}))
+
+	lastIdx++
+	require.NoError(t, s.PeeringWrite(lastIdx, &pbpeering.PeeringWriteRequest{
+		Peering: &pbpeering.Peering{
+			ID:   testUUID(),
+			Name: "my-peering",
+		},
+	}))
+
+	_, p, err := s.PeeringRead(nil, Query{Value: "my-peering"})
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	ws := memdb.NewWatchSet()
+	_, entries, err := s.ExportedConfigEntriesForPeer(ws, p.ID, "dc1")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	lastIdx++
+	require.NoError(t, s.EnsureConfigEntry(lastIdx, &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	}))
+
+	lastIdx++
+	require.NoError(t, s.EnsureConfigEntry(lastIdx, &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "db",
+		Protocol: "tcp",
+	}))
+
+	lastIdx++
+	require.NoError(t, s.EnsureConfigEntry(lastIdx, &structs.ExportedServicesConfigEntry{
+		Name: "default",
+		Services: []structs.ExportedService{
+			{
+				Name:          "web",
+				Consumers:     []structs.ServiceConsumer{{Peer: "my-peering"}},
+				ConfigEntries: []string{structs.ServiceDefaults, structs.ServiceResolver},
+			},
+			{
+				Name:      "db",
+				Consumers: []structs.ServiceConsumer{{Peer: "my-peering"}},
+			},
+		},
+	}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	_, entries, err = s.ExportedConfigEntriesForPeer(ws, p.ID, "dc1")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, structs.ServiceDefaults, entries[0].GetKind())
+	require.Equal(t, "web", entries[0].GetName())
+
+	// Writing the opted-in resolver is picked up by the watch.
+	lastIdx++
+	require.NoError(t, s.EnsureConfigEntry(lastIdx, &structs.ServiceResolverConfigEntry{
+		Kind:           structs.ServiceResolver,
+		Name:           "web",
+		ConnectTimeout: 5 * time.Second,
+	}))
+	require.True(t, watchFired(ws))
+
+	_, entries, err = s.ExportedConfigEntriesForPeer(nil, p.ID, "dc1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, structs.ServiceResolver, entries[1].GetKind())
+}
+
 func TestStateStore_ExportedServicesForAllPeersByName(t *testing.T) {
 	s := NewStateStore(nil)
 	var lastIdx uint64
@@ -2160,6 +2290,86 @@ func TestStateStore_ExportedServicesForPeer(t *testing.T) {
 		require.Equal(t, expect, got)
 	})
 
+	testutil.RunStep(t, "config entry with wildcard service name skips excluded services", func(t *testing.T) {
+		entry := &structs.ExportedServicesConfigEntry{
+			Name: "default",
+			Services: []structs.ExportedService{
+				{
+					Name:    "*",
+					Exclude: []string{"payments", "router"},
+					Consumers: []structs.ServiceConsumer{
+						{Peer: "my-peering"},
+					},
+				},
+			},
+		}
+		ensureConfigEntry(t, entry)
+
+		require.True(t, watchFired(ws))
+		ws = memdb.NewWatchSet()
+
+		// Only the resolver remains since the other services are excluded.
+		expect := &structs.ExportedServiceList{
+			DiscoChains: map[structs.ServiceName]structs.ExportedDiscoveryChainInfo{
+				newSN("resolver"): {
+					Protocol: "http",
+				},
+			},
+		}
+		idx, got, err := s.ExportedServicesForPeer(ws, id, "dc1")
+		require.NoError(t, err)
+		require.Equal(t, lastIdx, idx)
+		require.Equal(t, expect, got)
+	})
+
+	testutil.RunStep(t, "config entry with wildcard service name redacts fields for the peer", func(t *testing.T) {
+		entry := &structs.ExportedServicesConfigEntry{
+			Name: "default",
+			Services: []structs.ExportedService{
+				{
+					Name: "*",
+					Consumers: []structs.ServiceConsumer{
+						{
+							Peer:         "my-peering",
+							RedactFields: []string{structs.RedactFieldNodeMeta, structs.RedactFieldServiceTags},
+						},
+					},
+				},
+			},
+		}
+		ensureConfigEntry(t, entry)
+
+		require.True(t, watchFired(ws))
+		ws = memdb.NewWatchSet()
+
+		expect := &structs.ExportedServiceList{
+			Services: []structs.ServiceName{
+				{
+					Name:           "payments",
+					EnterpriseMeta: *defaultEntMeta,
+				},
+			},
+			DiscoChains: map[structs.ServiceName]structs.ExportedDiscoveryChainInfo{
+				newSN("payments"): {
+					Protocol: "http",
+				},
+				newSN("resolver"): {
+					Protocol: "http",
+				},
+				newSN("router"): {
+					Protocol: "http",
+				},
+			},
+			RedactedFields: map[structs.ServiceName][]string{
+				newSN("payments"): {structs.RedactFieldNodeMeta, structs.RedactFieldServiceTags},
+			},
+		}
+		idx, got, err := s.ExportedServicesForPeer(ws, id, "dc1")
+		require.NoError(t, err)
+		require.Equal(t, lastIdx, idx)
+		require.Equal(t, expect, got)
+	})
+
 	testutil.RunStep(t, "deleting the config entry clears exported services", func(t *testing.T) {
 		expect := &structs.ExportedServiceList{}
 
@@ -168,6 +168,32 @@ func indexFromKeyValueQuery(q KeyValueQuery) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// ServiceKeyValueQuery is a type used to query for the instances of a service
+// by a key and a value, such as a meta key and value or a tag.
+type ServiceKeyValueQuery struct {
+	Service  string
+	Key      string
+	Value    string
+	PeerName string
+	acl.EnterpriseMeta
+}
+
+func (q ServiceKeyValueQuery) PeerOrEmpty() string {
+	return q.PeerName
+}
+
+// NamespaceOrDefault exists because structs.EnterpriseMeta uses a pointer
+// receiver for this method. Remove once that is fixed.
+func (q ServiceKeyValueQuery) NamespaceOrDefault() string {
+	return q.EnterpriseMeta.NamespaceOrDefault()
+}
+
+// PartitionOrDefault exists because structs.EnterpriseMeta uses a pointer
+// receiver for this method. Remove once that is fixed.
+func (q ServiceKeyValueQuery) PartitionOrDefault() string {
+	return q.EnterpriseMeta.PartitionOrDefault()
+}
+
 type AuthMethodQuery struct {
 	Value             string
 	AuthMethodEntMeta acl.EnterpriseMeta
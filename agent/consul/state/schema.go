@@ -32,17 +32,20 @@ func newDBSchema() *memdb.DBSchema {
 		nodesTableSchema,
 		peeringTableSchema,
 		peeringTrustBundlesTableSchema,
+		peeringConfigEntriesTableSchema,
 		peeringSecretsTableSchema,
 		peeringSecretUUIDsTableSchema,
 		policiesTableSchema,
 		preparedQueriesTableSchema,
 		rolesTableSchema,
 		servicesTableSchema,
+		serviceTombstonesTableSchema,
 		serviceVirtualIPTableSchema,
 		sessionChecksTableSchema,
 		sessionsTableSchema,
 		systemMetadataTableSchema,
 		tokensTableSchema,
+		tokenUsageTableSchema,
 		tombstonesTableSchema,
 		usageTableSchema,
 	)
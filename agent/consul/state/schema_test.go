@@ -44,6 +44,7 @@ func TestNewDBSchema_Indexers(t *testing.T) {
 		tableACLPolicies:     testIndexerTableACLPolicies,
 		tableACLRoles:        testIndexerTableACLRoles,
 		tableACLTokens:       testIndexerTableACLTokens,
+		tableACLTokenUsage:   testIndexerTableACLTokenUsage,
 		// catalog
 		tableChecks:            testIndexerTableChecks,
 		tableServices:          testIndexerTableServices,
@@ -53,6 +54,7 @@ func TestNewDBSchema_Indexers(t *testing.T) {
 		tableGatewayServices:   testIndexerTableGatewayServices,
 		tableServiceVirtualIPs: testIndexerTableServiceVirtualIPs,
 		tableKindServiceNames:  testIndexerTableKindServiceNames,
+		tableServiceTombstones: testIndexerTableServiceTombstones,
 		// KV
 		tableKVs:        testIndexerTableKVs,
 		tableTombstones: testIndexerTableTombstones,
@@ -110,6 +110,10 @@ type Store struct {
 	// kvsGraveyard manages tombstones for the key value store.
 	kvsGraveyard *Graveyard
 
+	// serviceTombstoneGC is hinted when service tombstones are created. It
+	// may be nil, in which case the tombstones are never reaped.
+	serviceTombstoneGC *TombstoneGC
+
 	// lockDelay holds expiration times for locks associated with keys.
 	lockDelay *Delay
 }
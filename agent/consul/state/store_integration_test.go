@@ -486,3 +486,74 @@ func createTokenAndWaitForACLEventPublish(t *testing.T, s *Store) *structs.ACLTo
 
 	return token
 }
+
+func TestStore_IntegrationWithEventPublisher_EnsureRegistrations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	publisher := stream.NewEventPublisher(0)
+	go publisher.Run(ctx)
+
+	s := NewStateStoreWithEventPublisher(nil, publisher)
+	require.NoError(t, publisher.RegisterHandler(EventTopicServiceHealth, s.ServiceHealthSnapshot, false))
+
+	subscribe := func(index uint64) <-chan nextResult {
+		sub, err := publisher.Subscribe(&stream.SubscribeRequest{
+			Topic:   EventTopicServiceHealth,
+			Subject: EventSubjectService{Key: "web"},
+			Index:   index,
+		})
+		require.NoError(t, err)
+		t.Cleanup(sub.Unsubscribe)
+		return testRunSub(sub)
+	}
+
+	eventCh := subscribe(0)
+	require.True(t, assertEvent(t, eventCh).IsEndOfSnapshot())
+
+	var regs []IndexedRegistration
+	for i, node := range []string{"node1", "node2", "node3"} {
+		regs = append(regs, IndexedRegistration{
+			Index: uint64(10 + i),
+			Request: &structs.RegisterRequest{
+				Node:    node,
+				Address: "127.0.0.1",
+				Service: &structs.NodeService{ID: "web", Service: "web", Port: 8080},
+			},
+		})
+	}
+	require.NoError(t, s.EnsureRegistrations(regs))
+
+	// The rows keep the index of their own registration.
+	idx, nodes, err := s.ServiceNodes(nil, "web", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), idx)
+	require.Len(t, nodes, 3)
+	for i, node := range nodes {
+		require.Equal(t, uint64(10+i), node.ModifyIndex)
+	}
+
+	// All the events of the batch are published at the last index.
+	event := assertEvent(t, eventCh)
+	require.Equal(t, uint64(12), event.Index)
+	require.IsType(t, &stream.PayloadEvents{}, event.Payload)
+	require.Equal(t, 3, event.Payload.(*stream.PayloadEvents).Len())
+	assertNoEvent(t, eventCh)
+
+	// Resuming from the last index of the batch doesn't repeat its events.
+	assertNoEvent(t, subscribe(12))
+
+	// Resuming from an index inside the batch is sent a new snapshot of every
+	// registration, so none of the events are missed.
+	eventCh = subscribe(11)
+	require.True(t, assertEvent(t, eventCh).IsNewSnapshotToFollow())
+	for i := 0; i < 3; i++ {
+		event := assertEvent(t, eventCh)
+		require.False(t, event.IsFramingEvent())
+	}
+	require.True(t, assertEvent(t, eventCh).IsEndOfSnapshot())
+}
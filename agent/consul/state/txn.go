@@ -38,6 +38,14 @@ func (s *Store) txnKVS(tx WriteTxn, idx uint64, op *structs.TxnKVOp) (structs.Tx
 			err = fmt.Errorf("failed to set key %q, index is stale", op.DirEnt.Key)
 		}
 
+	case api.KVPatch:
+		var ok bool
+		entry = &op.DirEnt
+		ok, err = kvsPatchTxn(tx, idx, entry, op.PatchLimit(), op.CompressionThreshold)
+		if !ok && err == nil {
+			err = fmt.Errorf("failed to patch key %q, index is stale", op.DirEnt.Key)
+		}
+
 	case api.KVLock:
 		var ok bool
 		entry = &op.DirEnt
@@ -109,6 +117,7 @@ func (s *Store) txnKVS(tx WriteTxn, idx uint64, op *structs.TxnKVOp) (structs.Tx
 
 		clone := entry.Clone()
 		clone.Value = nil
+		clone.Compressed = false
 		result := structs.TxnResult{KV: clone}
 		return structs.TxnResults{&result}, nil
 	}
@@ -1098,6 +1098,182 @@ func TestStateStore_Txn_KVS_Rollback(t *testing.T) {
 	}
 }
 
+func TestStateStore_Txn_KVS_Patch(t *testing.T) {
+	s := testStateStore(t)
+
+	testSetKey(t, s, 1, "foo", `{"a":1,"b":{"c":2}}`, nil)
+	require.NoError(t, s.KVSSet(2, &structs.DirEntry{Key: "text", Value: []byte("not json")}))
+
+	// Apply a JSON patch and a merge patch in the same transaction.
+	ops := structs.TxnOps{
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVPatch,
+				DirEnt: structs.DirEntry{
+					Key:   "foo",
+					Value: []byte(`[{"op":"test","path":"/a","value":1},{"op":"replace","path":"/a","value":3}]`),
+				},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVPatch,
+				DirEnt: structs.DirEntry{
+					Key:       "foo",
+					Value:     []byte(`{"b":{"c":null,"d":4}}`),
+					RaftIndex: structs.RaftIndex{ModifyIndex: 3},
+				},
+			},
+		},
+	}
+	results, errors := s.TxnRW(3, ops)
+	require.Empty(t, errors)
+	require.Len(t, results, 2)
+	require.Nil(t, results[1].KV.Value)
+
+	_, entry, err := s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":3,"b":{"d":4}}`, string(entry.Value))
+	require.Equal(t, uint64(1), entry.CreateIndex)
+	require.Equal(t, uint64(3), entry.ModifyIndex)
+
+	// Failed patches roll back the whole transaction.
+	ops = structs.TxnOps{
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   api.KVPatch,
+				DirEnt: structs.DirEntry{Key: "foo", Value: []byte(`{"a":4}`)},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb: api.KVPatch,
+				DirEnt: structs.DirEntry{
+					Key:       "foo",
+					Value:     []byte(`{"a":5}`),
+					RaftIndex: structs.RaftIndex{ModifyIndex: 1},
+				},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   api.KVPatch,
+				DirEnt: structs.DirEntry{Key: "text", Value: []byte(`{"a":5}`)},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   api.KVPatch,
+				DirEnt: structs.DirEntry{Key: "nope", Value: []byte(`{"a":5}`)},
+			},
+		},
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   api.KVPatch,
+				DirEnt: structs.DirEntry{Key: "foo", Value: []byte(`[{"op":"test","path":"/a","value":1}]`)},
+			},
+		},
+	}
+	results, errors = s.TxnRW(4, ops)
+	require.Empty(t, results)
+
+	expected := []string{
+		`failed to patch key "foo", index is stale`,
+		`failed to patch key "text": invalid document`,
+		`key "nope" doesn't exist`,
+		`failed to patch key "foo": operation 0 (test): value at "/a" doesn't match`,
+	}
+	require.Len(t, errors, len(expected))
+	for i, msg := range expected {
+		require.Equal(t, i+1, errors[i].OpIndex)
+		require.Contains(t, errors[i].Error(), msg)
+	}
+
+	_, entry, err = s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":3,"b":{"d":4}}`, string(entry.Value))
+
+	// Compressed values are decompressed before being patched, and the
+	// patched value is only compressed from the threshold carried by the op.
+	compressed := &structs.DirEntry{Key: "big", Value: []byte(`{"a":"` + strings.Repeat("x", 100) + `"}`)}
+	require.NoError(t, compressed.CompressValue(1))
+	require.True(t, compressed.Compressed)
+	require.NoError(t, s.KVSSet(5, compressed))
+
+	ops = structs.TxnOps{
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   api.KVPatch,
+				DirEnt: structs.DirEntry{Key: "big", Value: []byte(`{"a":"y"}`)},
+			},
+		},
+	}
+	_, errors = s.TxnRW(6, ops)
+	require.Empty(t, errors)
+
+	_, entry, err = s.KVSGet(nil, "big", nil)
+	require.NoError(t, err)
+	require.False(t, entry.Compressed)
+	require.Equal(t, `{"a":"y"}`, string(entry.Value))
+
+	large := `{"a":"y","b":"` + strings.Repeat("z", 100) + `"}`
+	ops = structs.TxnOps{
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:                 api.KVPatch,
+				DirEnt:               structs.DirEntry{Key: "big", Value: []byte(`{"b":"` + strings.Repeat("z", 100) + `"}`)},
+				CompressionThreshold: 64,
+			},
+		},
+	}
+	_, errors = s.TxnRW(7, ops)
+	require.Empty(t, errors)
+
+	_, entry, err = s.KVSGet(nil, "big", nil)
+	require.NoError(t, err)
+	require.True(t, entry.Compressed)
+	entry, err = entry.Decompressed()
+	require.NoError(t, err)
+	require.Equal(t, large, string(entry.Value))
+}
+
+func TestStateStore_Txn_KVS_Patch_TooLarge(t *testing.T) {
+	s := testStateStore(t)
+
+	testSetKey(t, s, 1, "foo", `{"a":"0123456789"}`, nil)
+
+	// Repeatedly copying the whole document into one of its members grows it
+	// exponentially, so the FSM enforces the limit carried by the op.
+	var copies []string
+	for i := 0; i < 64; i++ {
+		copies = append(copies, fmt.Sprintf(`{"op":"copy","from":"","path":"/a%d"}`, i))
+	}
+	ops := structs.TxnOps{
+		&structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:         api.KVPatch,
+				DirEnt:       structs.DirEntry{Key: "foo", Value: []byte("[" + strings.Join(copies, ",") + "]")},
+				MaxValueSize: 1024,
+			},
+		},
+	}
+	results, errors := s.TxnRW(2, ops)
+	require.Empty(t, results)
+	require.Len(t, errors, 1)
+	require.Contains(t, errors[0].Error(), "patched document is too large")
+
+	// Ops without a limit use the default one.
+	ops[0].KV.MaxValueSize = 0
+	results, errors = s.TxnRW(3, ops)
+	require.Empty(t, results)
+	require.Len(t, errors, 1)
+	require.Contains(t, errors[0].Error(), "patched document is too large")
+
+	_, entry, err := s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"0123456789"}`, string(entry.Value))
+}
+
 func TestStateStore_Txn_KVS_RO(t *testing.T) {
 	s := testStateStore(t)
 
@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/go-memdb"
 
@@ -13,6 +14,8 @@ const (
 	kvUsageTable                = "kv-entries"
 	connectNativeInstancesTable = "connect-native"
 	connectPrefix               = "connect-mesh"
+	serviceKindPrefix           = "service-kind"
+	externalInstancesTable      = "external-service-instances"
 
 	tableUsage = "usage"
 )
@@ -25,6 +28,17 @@ var allConnectKind = []string{
 	connectNativeInstancesTable,
 }
 
+// allServiceKind is the list of service kinds reported in the
+// ServiceKindInstances of the service usage.
+var allServiceKind = []string{
+	structs.ServiceKindTypical.Normalized(),
+	string(structs.ServiceKindConnectProxy),
+	string(structs.ServiceKindIngressGateway),
+	string(structs.ServiceKindMeshGateway),
+	string(structs.ServiceKindTerminatingGateway),
+	string(structs.ServiceKindAPIGateway),
+}
+
 // usageTableSchema returns a new table schema used for tracking various indexes
 // for the Raft log.
 func usageTableSchema() *memdb.TableSchema {
@@ -88,6 +102,7 @@ const (
 func updateUsage(tx WriteTxn, changes Changes) error {
 	usageDeltas := make(map[string]int)
 	serviceNameChanges := make(map[structs.ServiceName]int)
+	external := newExternalInstances(changes)
 	for _, change := range changes.Changes {
 		var delta int
 		if change.Created() {
@@ -121,6 +136,10 @@ func updateUsage(tx WriteTxn, changes Changes) error {
 
 			connectDeltas(change, usageDeltas, delta)
 			billableServiceInstancesDeltas(change, usageDeltas, delta)
+			serviceKindDeltas(change, usageDeltas)
+			if err := external.serviceDeltas(tx, change, usageDeltas); err != nil {
+				return err
+			}
 
 			// Construct a mapping of all of the various service names that were
 			// changed, in order to compare it with the finished memdb state.
@@ -143,6 +162,10 @@ func updateUsage(tx WriteTxn, changes Changes) error {
 		}
 	}
 
+	if err := external.nodeDeltas(tx, usageDeltas); err != nil {
+		return err
+	}
+
 	serviceStates, err := updateServiceNameUsage(tx, usageDeltas, serviceNameChanges)
 	if err != nil {
 		return err
@@ -265,6 +288,155 @@ func connectDeltas(change memdb.Change, usageDeltas map[string]int, delta int) {
 	}
 }
 
+// serviceKindUsageTableName is a convenience function to get the prefix +
+// service kind in 1 place.
+func serviceKindUsageTableName(kind string) string {
+	return fmt.Sprintf("%s-%s", serviceKindPrefix, kind)
+}
+
+// serviceKindDeltas counts the service instances by service kind, including
+// the typical services. An update moves the instance from the kind it had
+// before to the kind it has after, which is a no-op unless it changed.
+func serviceKindDeltas(change memdb.Change, usageDeltas map[string]int) {
+	if change.Before != nil {
+		before := change.Before.(*structs.ServiceNode)
+		usageDeltas[serviceKindUsageTableName(before.ServiceKind.Normalized())] -= 1
+		addEnterpriseServiceKindInstanceUsage(usageDeltas, before, -1)
+	}
+	if change.After != nil {
+		after := change.After.(*structs.ServiceNode)
+		usageDeltas[serviceKindUsageTableName(after.ServiceKind.Normalized())] += 1
+		addEnterpriseServiceKindInstanceUsage(usageDeltas, after, 1)
+	}
+}
+
+// nodeKey identifies a local node in the changes of a transaction.
+type nodeKey struct {
+	partition string
+	name      string
+}
+
+// serviceKey identifies a local service instance in the changes of a
+// transaction.
+type serviceKey struct {
+	node nodeKey
+	id   string
+}
+
+// externalInstances counts the service instances registered on external
+// nodes, which are marked with the "external-node" node meta. Since the meta
+// is stored on the node rather than the service instances, the instances of
+// a node are counted against the state of the node before the transaction
+// for their removal and after it for their addition, and the instances left
+// untouched by the transaction move in or out of the count when the meta of
+// their node changes.
+type externalInstances struct {
+	nodes    map[nodeKey]memdb.Change
+	services map[serviceKey]struct{}
+}
+
+func newExternalInstances(changes Changes) *externalInstances {
+	e := &externalInstances{
+		nodes:    make(map[nodeKey]memdb.Change),
+		services: make(map[serviceKey]struct{}),
+	}
+	for _, change := range changes.Changes {
+		if change.Table != tableNodes {
+			continue
+		}
+		node := changeObject(change).(*structs.Node)
+		if node.PeerName != "" {
+			continue
+		}
+		e.nodes[nodeKey{partition: node.PartitionOrDefault(), name: strings.ToLower(node.Node)}] = change
+	}
+	return e
+}
+
+// serviceDeltas counts the change of a local service instance.
+func (e *externalInstances) serviceDeltas(tx ReadTxn, change memdb.Change, usageDeltas map[string]int) error {
+	svc := changeObject(change).(*structs.ServiceNode)
+	key := nodeKey{partition: svc.PartitionOrDefault(), name: strings.ToLower(svc.Node)}
+	e.services[serviceKey{node: key, id: svc.ServiceID}] = struct{}{}
+
+	nodeChange, nodeChanged := e.nodes[key]
+	if change.Before != nil {
+		before := change.Before.(*structs.ServiceNode)
+		external, err := e.isExternal(tx, before, nodeChange.Before, nodeChanged)
+		if err != nil {
+			return err
+		}
+		if external {
+			usageDeltas[externalInstancesTable] -= 1
+			addEnterpriseExternalServiceInstanceUsage(usageDeltas, before, -1)
+		}
+	}
+	if change.After != nil {
+		after := change.After.(*structs.ServiceNode)
+		external, err := e.isExternal(tx, after, nodeChange.After, nodeChanged)
+		if err != nil {
+			return err
+		}
+		if external {
+			usageDeltas[externalInstancesTable] += 1
+			addEnterpriseExternalServiceInstanceUsage(usageDeltas, after, 1)
+		}
+	}
+	return nil
+}
+
+// isExternal returns whether the node of the service instance is external,
+// using the state of the node from the changes if it changed during the
+// transaction, and from the state store otherwise.
+func (e *externalInstances) isExternal(tx ReadTxn, svc *structs.ServiceNode, changed interface{}, nodeChanged bool) (bool, error) {
+	if nodeChanged {
+		node, _ := changed.(*structs.Node)
+		return isExternalNode(node), nil
+	}
+	node, err := getNodeTxn(tx, svc.Node, &svc.EnterpriseMeta, "")
+	if err != nil {
+		return false, err
+	}
+	return isExternalNode(node), nil
+}
+
+// nodeDeltas counts the service instances that were left untouched by the
+// transaction, on the nodes that became external or stopped being external.
+func (e *externalInstances) nodeDeltas(tx ReadTxn, usageDeltas map[string]int) error {
+	for key, change := range e.nodes {
+		if !change.Updated() {
+			continue
+		}
+		before := change.Before.(*structs.Node)
+		after := change.After.(*structs.Node)
+		if isExternalNode(before) == isExternalNode(after) {
+			continue
+		}
+		delta := 1
+		if isExternalNode(before) {
+			delta = -1
+		}
+
+		services, err := catalogServiceListByNode(tx, after.Node, after.GetEnterpriseMeta(), "", false)
+		if err != nil {
+			return fmt.Errorf("failed service lookup: %s", err)
+		}
+		for raw := services.Next(); raw != nil; raw = services.Next() {
+			svc := raw.(*structs.ServiceNode)
+			if _, ok := e.services[serviceKey{node: key, id: svc.ServiceID}]; ok {
+				continue
+			}
+			usageDeltas[externalInstancesTable] += delta
+			addEnterpriseExternalServiceInstanceUsage(usageDeltas, svc, delta)
+		}
+	}
+	return nil
+}
+
+func isExternalNode(node *structs.Node) bool {
+	return node != nil && node.Meta[structs.MetaExternalNode] == "true"
+}
+
 // billableServiceInstancesDeltas calculates deltas for the billable services. Billable services
 // are of "typical" service kind (i.e. non-connect or connect-native), excluding the "consul" service.
 func billableServiceInstancesDeltas(change memdb.Change, usageDeltas map[string]int, delta int) {
@@ -434,11 +606,27 @@ func (s *Store) ServiceUsage(ws memdb.WatchSet) (uint64, structs.ServiceUsage, e
 		return 0, structs.ServiceUsage{}, fmt.Errorf("failed billable services lookup: %s", err)
 	}
 
+	kindInstances := make(map[string]int)
+	for _, kind := range allServiceKind {
+		usage, err := firstUsageEntry(ws, tx, serviceKindUsageTableName(kind))
+		if err != nil {
+			return 0, structs.ServiceUsage{}, fmt.Errorf("failed services lookup: %s", err)
+		}
+		kindInstances[kind] = usage.Count
+	}
+
+	externalInstances, err := firstUsageEntry(ws, tx, externalInstancesTable)
+	if err != nil {
+		return 0, structs.ServiceUsage{}, fmt.Errorf("failed external services lookup: %s", err)
+	}
+
 	usage := structs.ServiceUsage{
 		ServiceInstances:         serviceInstances.Count,
 		Services:                 services.Count,
 		ConnectServiceInstances:  serviceKindInstances,
 		BillableServiceInstances: billableServiceInstances.Count,
+		ServiceKindInstances:     kindInstances,
+		ExternalServiceInstances: externalInstances.Count,
 	}
 	results, err := compileEnterpriseServiceUsage(ws, tx, usage)
 	if err != nil {
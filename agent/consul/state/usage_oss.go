@@ -27,6 +27,10 @@ func addEnterpriseConnectServiceInstanceUsage(map[string]int, *structs.ServiceNo
 
 func addEnterpriseBillableServiceInstanceUsage(map[string]int, *structs.ServiceNode, int) {}
 
+func addEnterpriseServiceKindInstanceUsage(map[string]int, *structs.ServiceNode, int) {}
+
+func addEnterpriseExternalServiceInstanceUsage(map[string]int, *structs.ServiceNode, int) {}
+
 func addEnterpriseKVUsage(map[string]int, memdb.Change) {}
 
 func addEnterpriseConfigEntryUsage(map[string]int, memdb.Change) {}
@@ -242,6 +242,55 @@ func TestStateStore_Usage_ServiceUsage_DeleteNode(t *testing.T) {
 	require.Equal(t, 0, usage.BillableServiceInstances)
 }
 
+func TestStateStore_Usage_ServiceUsage_KindsAndExternal(t *testing.T) {
+	s := testStateStore(t)
+	external := map[string]string{structs.MetaExternalNode: "true"}
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterNodeWithMeta(t, s, 2, "external1", external)
+	testRegisterService(t, s, 3, "node1", "service1")
+	testRegisterSidecarProxy(t, s, 4, "node1", "service1")
+	testRegisterIngressService(t, s, 5, "node1", "ingress")
+	testRegisterService(t, s, 6, "external1", "db")
+	testRegisterService(t, s, 7, "external1", "cache")
+
+	_, usage, err := s.ServiceUsage(nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		"typical":             3,
+		"connect-proxy":       1,
+		"ingress-gateway":     1,
+		"mesh-gateway":        0,
+		"terminating-gateway": 0,
+		"api-gateway":         0,
+	}, usage.ServiceKindInstances)
+	require.Equal(t, 2, usage.ExternalServiceInstances)
+
+	testutil.RunStep(t, "node becomes external", func(t *testing.T) {
+		testRegisterNodeWithMeta(t, s, 8, "node1", external)
+		_, usage, err := s.ServiceUsage(nil)
+		require.NoError(t, err)
+		require.Equal(t, 5, usage.ExternalServiceInstances)
+	})
+
+	testutil.RunStep(t, "node stops being external", func(t *testing.T) {
+		testRegisterNodeWithMeta(t, s, 9, "external1", nil)
+		_, usage, err := s.ServiceUsage(nil)
+		require.NoError(t, err)
+		require.Equal(t, 3, usage.ExternalServiceInstances)
+	})
+
+	testutil.RunStep(t, "delete node", func(t *testing.T) {
+		require.NoError(t, s.DeleteNode(10, "node1", nil, ""))
+		_, usage, err := s.ServiceUsage(nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, usage.ExternalServiceInstances)
+		require.Equal(t, 2, usage.ServiceKindInstances["typical"])
+		require.Equal(t, 0, usage.ServiceKindInstances["connect-proxy"])
+		require.Equal(t, 0, usage.ServiceKindInstances["ingress-gateway"])
+	})
+}
+
 // Test that services from remote peers aren't counted in writes or deletes.
 func TestStateStore_Usage_ServiceUsagePeering(t *testing.T) {
 	s := testStateStore(t)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync/atomic"
+	"time"
 )
 
 // eventBuffer is a single-writer, multiple-reader, unlimited length concurrent
@@ -139,6 +140,10 @@ type bufferItem struct {
 	// to read from the buffer.
 	Err error
 
+	// publishedAt is the time the events were published to the topic buffer.
+	// It is zero for the items of the snapshots.
+	publishedAt time.Time
+
 	// link holds the next pointer and channel. This extra bit of indirection
 	// allows us to splice buffers together at arbitrary points without including
 	// events in one buffer just for the side-effect of watching for the next set.
@@ -63,6 +63,9 @@ type subscriptions struct {
 	// reloaded.
 	// A subscription may be unsubscribed by using the pointer to the request.
 	byToken map[string]map[*SubscribeRequest]*Subscription
+
+	// byTopic counts the active subscriptions by topic.
+	byTopic subscriptionCounts
 }
 
 // topicBuffer augments the eventBuffer with a reference counter, enabling
@@ -104,6 +107,7 @@ func NewEventPublisher(snapCacheTTL time.Duration) *EventPublisher {
 		publishCh:    make(chan []Event, 64),
 		subscriptions: &subscriptions{
 			byToken: make(map[string]map[*SubscribeRequest]*Subscription),
+			byTopic: make(subscriptionCounts),
 		},
 		snapshotHandlers: make(map[Topic]SnapshotFunc),
 		wildcards:        make(map[Topic]topicSubject),
@@ -210,6 +214,7 @@ func (e *EventPublisher) publishEvent(events []Event) {
 		}
 	}
 
+	now := time.Now()
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	for groupKey, events := range groupedEvents {
@@ -217,7 +222,9 @@ func (e *EventPublisher) publishEvent(events []Event) {
 		// given topic and subject, in which case events will be dropped on the floor and
 		// future subscribers will catch up by consuming the snapshot.
 		if buf := e.bufferForPublishing(groupKey); buf != nil {
-			buf.Append(events)
+			item := newBufferItem(events)
+			item.publishedAt = now
+			buf.AppendItem(item)
 		}
 	}
 }
@@ -312,10 +319,12 @@ func (e *EventPublisher) Subscribe(req *SubscribeRequest) (*Subscription, error)
 		// the subscription will receive new events.
 		next, _ := topicHead.NextNoBlock()
 		buf.AppendItem(next)
+		recordSubscribe(req.Topic, subscribeModeResume)
 		return e.subscriptions.add(req, subscriptionHead, freeBuf), nil
 	}
 
 	snapFromCache := e.getCachedSnapshotLocked(req)
+	recordSnapshot(req.Topic, snapFromCache != nil)
 	if snapFromCache == nil {
 		snap := newEventSnapshot()
 		snap.appendAndSplice(*req, handler, topicHead)
@@ -325,8 +334,10 @@ func (e *EventPublisher) Subscribe(req *SubscribeRequest) (*Subscription, error)
 
 	// If the request.Index is 0 the client has no view, send a full snapshot.
 	if req.Index == 0 {
+		recordSubscribe(req.Topic, subscribeModeSnapshot)
 		return e.subscriptions.add(req, snapFromCache.First, freeBuf), nil
 	}
+	recordSubscribe(req.Topic, subscribeModeReset)
 
 	// otherwise the request has an Index, the client view is stale and must be reset
 	// with a NewSnapshotToFollow event.
@@ -357,6 +368,7 @@ func (s *subscriptions) add(req *SubscribeRequest, head *bufferItem, freeBuf fun
 		s.byToken[req.Token] = subsByToken
 	}
 	subsByToken[req] = sub
+	s.byTopic.add(req.Topic, 1)
 	return sub
 }
 
@@ -381,7 +393,11 @@ func (s *subscriptions) unsubscribe(req *SubscribeRequest) {
 	if !ok {
 		return
 	}
+	if _, ok := subsByToken[req]; !ok {
+		return
+	}
 	delete(subsByToken, req)
+	s.byTopic.add(req.Topic, -1)
 	if len(subsByToken) == 0 {
 		delete(s.byToken, req.Token)
 	}
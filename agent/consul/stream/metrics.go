@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+)
+
+var (
+	metricSubscriptions = []string{"stream", "subscriptions"}
+	metricSubscribe     = []string{"stream", "subscribe"}
+	metricSnapshots     = []string{"stream", "snapshots"}
+	metricLag           = []string{"stream", "lag"}
+)
+
+// The values of the mode label of the stream.subscribe counter.
+const (
+	// subscribeModeResume is a subscription resumed from the index of the
+	// client, which is still in the topic buffer.
+	subscribeModeResume = "resume"
+
+	// subscribeModeSnapshot is a subscription of a client without a view,
+	// which receives a snapshot.
+	subscribeModeSnapshot = "snapshot"
+
+	// subscribeModeReset is a subscription of a client whose view is stale,
+	// which must reset its view and receive a new snapshot.
+	subscribeModeReset = "reset"
+)
+
+var Gauges = []prometheus.GaugeDefinition{
+	{
+		Name: metricSubscriptions,
+		Help: "Shows the current number of subscriptions to the event stream, labeled by topic.",
+	},
+}
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: metricSubscribe,
+		Help: "Increments when a subscription to the event stream starts, labeled by topic and by whether it resumed from the index of the subscriber or required a snapshot.",
+	},
+	{
+		Name: metricSnapshots,
+		Help: "Increments when a subscription receives a snapshot of the state, labeled by topic and by whether the snapshot was cached.",
+	},
+}
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: metricLag,
+		Help: "Measures the time in milliseconds between the publication of an event and its delivery to a subscriber, labeled by topic.",
+	},
+}
+
+func topicLabel(topic Topic) metrics.Label {
+	return metrics.Label{Name: "topic", Value: topic.String()}
+}
+
+// subscriptionCounts counts the subscriptions by topic. It's guarded by the
+// lock of subscriptions.
+type subscriptionCounts map[string]int
+
+func (c subscriptionCounts) add(topic Topic, delta int) {
+	key := topic.String()
+	c[key] += delta
+	metrics.SetGaugeWithLabels(metricSubscriptions, float32(c[key]), []metrics.Label{topicLabel(topic)})
+	if c[key] == 0 {
+		delete(c, key)
+	}
+}
+
+func recordSubscribe(topic Topic, mode string) {
+	metrics.IncrCounterWithLabels(metricSubscribe, 1, []metrics.Label{
+		topicLabel(topic),
+		{Name: "mode", Value: mode},
+	})
+}
+
+func recordSnapshot(topic Topic, cached bool) {
+	value := "false"
+	if cached {
+		value = "true"
+	}
+	metrics.IncrCounterWithLabels(metricSnapshots, 1, []metrics.Label{
+		topicLabel(topic),
+		{Name: "cached", Value: value},
+	})
+}
+
+// recordLag measures the time since the item was published to the topic
+// buffer. The items of the snapshots aren't measured.
+func recordLag(topic Topic, item *bufferItem) {
+	if item.publishedAt.IsZero() {
+		return
+	}
+	lag := float32(time.Since(item.publishedAt).Microseconds()) / 1000
+	metrics.AddSampleWithLabels(metricLag, lag, []metrics.Label{topicLabel(topic)})
+}
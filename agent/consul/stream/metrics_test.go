@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventPublisher_Metrics(t *testing.T) {
+	sink := metrics.NewInmemSink(1*time.Minute, 1*time.Minute)
+	cfg := metrics.DefaultConfig("consul")
+	cfg.EnableHostname = false
+	metrics.NewGlobal(cfg, sink)
+
+	t.Cleanup(func() {
+		sink := &metrics.BlackholeSink{}
+		metrics.NewGlobal(cfg, sink)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	publisher := NewEventPublisher(time.Minute)
+	registerTestSnapshotHandlers(t, publisher)
+	go publisher.Run(ctx)
+
+	subscribe := func(index uint64) *Subscription {
+		sub, err := publisher.Subscribe(&SubscribeRequest{
+			Topic:   testTopic,
+			Subject: StringSubject("sub-key"),
+			Index:   index,
+		})
+		require.NoError(t, err)
+		return sub
+	}
+
+	// The first subscription builds the snapshot, the second one gets it from
+	// the cache.
+	sub1 := subscribe(0)
+	sub2 := subscribe(0)
+	eventCh := runSubscription(ctx, sub1)
+	require.Equal(t, testSnapshotEvent, getNextEvent(t, eventCh))
+	require.True(t, getNextEvent(t, eventCh).IsEndOfSnapshot())
+
+	publisher.Publish([]Event{{
+		Topic:   testTopic,
+		Index:   2,
+		Payload: simplePayload{key: "sub-key", value: "update"},
+	}})
+	require.Equal(t, uint64(2), getNextEvent(t, eventCh).Index)
+
+	// The subscriber with the last index resumes, the stale one is reset.
+	sub3 := subscribe(2)
+	sub4 := subscribe(1)
+
+	data := sink.Data()
+	require.Len(t, data, 1)
+	gauge, ok := data[0].Gauges["consul.stream.subscriptions;topic=999"]
+	require.True(t, ok)
+	require.Equal(t, float32(4), gauge.Value)
+
+	counters := data[0].Counters
+	require.Equal(t, 2, counters["consul.stream.subscribe;topic=999;mode=snapshot"].Count)
+	require.Equal(t, 1, counters["consul.stream.subscribe;topic=999;mode=resume"].Count)
+	require.Equal(t, 1, counters["consul.stream.subscribe;topic=999;mode=reset"].Count)
+	require.Equal(t, 1, counters["consul.stream.snapshots;topic=999;cached=false"].Count)
+	require.Equal(t, 2, counters["consul.stream.snapshots;topic=999;cached=true"].Count)
+
+	// Only the published event is measured, not the snapshot.
+	lag, ok := data[0].Samples["consul.stream.lag;topic=999"]
+	require.True(t, ok)
+	require.Equal(t, 1, lag.Count)
+
+	for _, sub := range []*Subscription{sub1, sub2, sub3, sub4} {
+		sub.Unsubscribe()
+	}
+	// Unsubscribe is idempotent.
+	sub1.Unsubscribe()
+
+	gauge = sink.Data()[0].Gauges["consul.stream.subscriptions;topic=999"]
+	require.Equal(t, float32(0), gauge.Value)
+}
@@ -118,6 +118,7 @@ func (s *Subscription) Next(ctx context.Context) (Event, error) {
 		if len(next.Events) == 0 {
 			continue
 		}
+		recordLag(s.req.Topic, next)
 		return newEventFromBatch(s.req, next.Events), nil
 	}
 }
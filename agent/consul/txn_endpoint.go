@@ -1,6 +1,7 @@
 package consul
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/consul/acl/resolver"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib/jsonpatch"
 )
 
 var TxnSummaries = []prometheus.SummaryDefinition{
@@ -40,6 +42,22 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 	for i, op := range ops {
 		switch {
 		case op.KV != nil:
+			if op.KV.Verb == api.KVPatch {
+				if !t.srv.canPatchKVValues() {
+					errors = append(errors, &structs.TxnError{
+						OpIndex: i,
+						What:    "KV patch operations are not supported until all servers are upgraded",
+					})
+					break
+				}
+
+				// The patched value is compressed in the FSM, as for a set.
+				op.KV.CompressionThreshold = 0
+				if t.srv.canCompressKVValues() {
+					op.KV.CompressionThreshold = t.srv.config.KVCompressionThreshold
+				}
+			}
+
 			ok, err := kvsPreApply(t.logger, t.srv, authorizer, op.KV.Verb, &op.KV.DirEnt)
 			if err != nil {
 				errors = append(errors, &structs.TxnError{
@@ -87,6 +105,16 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 					OpIndex: i,
 					What:    err.Error(),
 				})
+				break
+			}
+
+			if op.Service.Verb != api.ServiceDelete && op.Service.Verb != api.ServiceDeleteCAS {
+				if err := t.srv.ACLResolver.VetServiceNode(authorizer, service.Service, op.Service.Node); err != nil {
+					errors = append(errors, &structs.TxnError{
+						OpIndex: i,
+						What:    err.Error(),
+					})
+				}
 			}
 		case op.Check != nil:
 			// Skip the pre-apply checks if this is a GET.
@@ -109,6 +137,39 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 	return errors
 }
 
+// preCheckPatches applies the KV patch operations to the current values of
+// their keys before the transaction goes into Raft, so that a patch making a
+// value too large is rejected here rather than applied by every server. Other
+// failures are left to the FSM, as earlier operations of the transaction may
+// change the values the patches are applied to.
+func (t *Txn) preCheckPatches(ops structs.TxnOps) (structs.TxnErrors, error) {
+	var errs structs.TxnErrors
+	state := t.srv.fsm.State()
+	for i, op := range ops {
+		if op.KV == nil || op.KV.Verb != api.KVPatch {
+			continue
+		}
+		_, entry, err := state.KVSGet(nil, op.KV.DirEnt.Key, &op.KV.DirEnt.EnterpriseMeta)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		if entry, err = entry.Decompressed(); err != nil {
+			return nil, err
+		}
+		_, err = jsonpatch.Apply(entry.Value, op.KV.DirEnt.Value, op.KV.PatchLimit())
+		if errors.Is(err, jsonpatch.ErrTooLarge) {
+			errs = append(errs, &structs.TxnError{
+				OpIndex: i,
+				What:    fmt.Sprintf("failed to patch key %q: %v", op.KV.DirEnt.Key, err),
+			})
+		}
+	}
+	return errs, nil
+}
+
 // vetNodeTxnOp applies the given ACL policy to a node transaction operation.
 func vetNodeTxnOp(op *structs.TxnNodeOp, authz resolver.Result) error {
 	var authzContext acl.AuthorizerContext
@@ -155,6 +216,9 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	if len(reply.Errors) > 0 {
 		return nil
 	}
+	if reply.Errors, err = t.preCheckPatches(args.Ops); err != nil || len(reply.Errors) > 0 {
+		return err
+	}
 
 	// Apply the update.
 	resp, err := t.srv.raftApply(structs.TxnRequestType, args)
@@ -166,6 +230,9 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	// just taking the two slices.
 	if txnResp, ok := resp.(structs.TxnResponse); ok {
 		txnResp.Results = FilterTxnResults(authz, txnResp.Results)
+		if err := decompressTxnResults(txnResp.Results); err != nil {
+			return err
+		}
 		*reply = txnResp
 	} else {
 		return fmt.Errorf("unexpected return type %T", resp)
@@ -215,9 +282,29 @@ func (t *Txn) Read(args *structs.TxnReadRequest, reply *structs.TxnReadResponse)
 	total := len(reply.Results)
 	reply.Results = FilterTxnResults(authz, reply.Results)
 	reply.QueryMeta.ResultsFilteredByACLs = total != len(reply.Results)
+	if err := decompressTxnResults(reply.Results); err != nil {
+		return err
+	}
 
 	// We have to do this ourselves since we are not doing a blocking RPC.
 	t.srv.setQueryMeta(&reply.QueryMeta, args.Token)
 
 	return nil
 }
+
+// decompressTxnResults decompresses the values of the KV entries returned by
+// a transaction. The results are owned by the caller, but the entries may be
+// owned by the state store so they are replaced rather than modified.
+func decompressTxnResults(results structs.TxnResults) error {
+	for _, result := range results {
+		if result.KV == nil {
+			continue
+		}
+		kv, err := (*structs.DirEntry)(result.KV).Decompressed()
+		if err != nil {
+			return err
+		}
+		result.KV = kv
+	}
+	return nil
+}
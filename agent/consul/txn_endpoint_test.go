@@ -3,6 +3,7 @@ package consul
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/consul/types"
 )
@@ -708,6 +710,136 @@ func TestTxn_Apply_LockDelay(t *testing.T) {
 	}
 }
 
+func TestTxn_Apply_PatchTooLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	require.NoError(t, s1.fsm.State().KVSSet(1, &structs.DirEntry{Key: "doc", Value: []byte(`{"a":"0123456789"}`)}))
+
+	// Every copy doubles the document, so the patch is rejected before it
+	// goes into Raft.
+	var ops []string
+	for i := 0; i < 64; i++ {
+		ops = append(ops, fmt.Sprintf(`{"op":"copy","from":"","path":"/a%d"}`, i))
+	}
+	arg := structs.TxnRequest{
+		Datacenter: "dc1",
+		Ops: structs.TxnOps{
+			&structs.TxnOp{
+				KV: &structs.TxnKVOp{
+					Verb: api.KVPatch,
+					DirEnt: structs.DirEntry{
+						Key:   "doc",
+						Value: []byte("[" + strings.Join(ops, ",") + "]"),
+					},
+					MaxValueSize: 1024,
+				},
+			},
+		},
+	}
+	var out structs.TxnResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Txn.Apply", &arg, &out))
+	require.Empty(t, out.Results)
+	require.Len(t, out.Errors, 1)
+	require.Contains(t, out.Errors[0].What, `failed to patch key "doc"`)
+	require.Contains(t, out.Errors[0].What, "patched document is too large")
+
+	idx, entry, err := s1.fsm.State().KVSGet(nil, "doc", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), idx)
+	require.Equal(t, `{"a":"0123456789"}`, string(entry.Value))
+}
+
+func TestTxn_Apply_Patch_MixedVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	compressValues := func(c *Config) {
+		c.KVCompressionThreshold = 64
+	}
+
+	dir1, s1 := testServerWithConfig(t, compressValues)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, compressValues, func(c *Config) {
+		c.Bootstrap = false
+		c.OverrideInitialSerfTags = func(tags map[string]string) {
+			delete(tags, "ft_kp")
+		}
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	retry.Run(t, func(r *retry.R) {
+		if ok, _ := ServersInDCSupportFeature(s1, "dc1", "kp"); ok {
+			r.Fatal("expected the older server to be known")
+		}
+	})
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	require.NoError(t, s1.fsm.State().KVSSet(1, &structs.DirEntry{Key: "doc", Value: []byte(`{"a":1}`)}))
+
+	large := strings.Repeat("patch me ", 100)
+	patch := func(t require.TestingT) structs.TxnResponse {
+		arg := structs.TxnRequest{
+			Datacenter: "dc1",
+			Ops: structs.TxnOps{
+				&structs.TxnOp{
+					KV: &structs.TxnKVOp{
+						Verb: api.KVPatch,
+						DirEnt: structs.DirEntry{
+							Key:   "doc",
+							Value: []byte(`{"b":"` + large + `"}`),
+						},
+					},
+				},
+			},
+		}
+		var out structs.TxnResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Txn.Apply", &arg, &out))
+		return out
+	}
+
+	// The older server can't apply the patches, so they are rejected.
+	out := patch(t)
+	require.Empty(t, out.Results)
+	require.Len(t, out.Errors, 1)
+	require.Contains(t, out.Errors[0].What, "not supported until all servers are upgraded")
+
+	// Once it is upgraded the patches are applied, and the patched values are
+	// compressed as they would be by a set.
+	updateSerfTags(s2, "ft_kp", "1")
+	retry.Run(t, func(r *retry.R) {
+		out := patch(r)
+		require.Empty(r, out.Errors)
+		require.Len(r, out.Results, 1)
+	})
+
+	_, entry, err := s1.fsm.State().KVSGet(nil, "doc", nil)
+	require.NoError(t, err)
+	require.True(t, entry.Compressed)
+	entry, err = entry.Decompressed()
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1,"b":"`+large+`"}`, string(entry.Value))
+}
+
 func TestTxn_Read(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
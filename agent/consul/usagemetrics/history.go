@@ -0,0 +1,162 @@
+package usagemetrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib/file"
+	"github.com/hashicorp/consul/logging"
+)
+
+const (
+	// HistoryFile is the name of the file, in the data directory of the
+	// servers, where the samples of the service usage are persisted.
+	HistoryFile = "usage-history.json"
+
+	// historySampleInterval is the interval between two samples of the
+	// service usage.
+	historySampleInterval = 24 * time.Hour
+
+	// historyRetention is how long the samples are kept.
+	historyRetention = 30 * 24 * time.Hour
+
+	// historyCheckInterval is how often the History checks whether a new
+	// sample is due, so that samples are taken on time even after the
+	// server was restarted.
+	historyCheckInterval = time.Hour
+)
+
+// History keeps a daily sample of the service usage of the datacenter for
+// the last 30 days. Every server samples its own copy of the state, and
+// persists the samples to a file in its data directory so that they survive
+// restarts.
+type History struct {
+	logger        hclog.Logger
+	stateProvider StateProvider
+
+	// path is the file the samples are persisted to, or empty to keep them
+	// in memory only.
+	path string
+
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+
+	lock    sync.RWMutex
+	samples []structs.ServiceUsageSample
+}
+
+// NewHistory returns a History sampling the state of the StateProvider, and
+// persisting the samples to path unless it is empty.
+func NewHistory(logger hclog.Logger, sp StateProvider, path string) *History {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &History{
+		logger:        logger.Named(logging.UsageMetrics),
+		stateProvider: sp,
+		path:          path,
+		now:           time.Now,
+	}
+}
+
+// Run must be run in a goroutine, and can be stopped by cancelling the
+// context.
+func (h *History) Run(ctx context.Context) {
+	if err := h.load(); err != nil {
+		h.logger.Warn("failed to load the usage history", "path", h.path, "error", err)
+	}
+	h.sampleIfDue()
+
+	ticker := time.NewTicker(historyCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.sampleIfDue()
+		}
+	}
+}
+
+// Samples returns the samples of the service usage, oldest first.
+func (h *History) Samples() []structs.ServiceUsageSample {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	samples := make([]structs.ServiceUsageSample, len(h.samples))
+	copy(samples, h.samples)
+	return samples
+}
+
+// sampleIfDue samples the service usage if the last sample is older than the
+// sample interval, and drops the samples older than the retention.
+func (h *History) sampleIfDue() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	now := h.now()
+	if n := len(h.samples); n > 0 && now.Sub(h.samples[n-1].Time) < historySampleInterval {
+		return
+	}
+
+	_, usage, err := h.stateProvider.State().ServiceUsage(nil)
+	if err != nil {
+		h.logger.Warn("failed to retrieve services from state store", "error", err)
+		return
+	}
+	h.samples = append(h.samples, structs.ServiceUsageSample{
+		Time:         now,
+		ServiceUsage: usage,
+	})
+
+	cutoff := now.Add(-historyRetention)
+	for len(h.samples) > 0 && !h.samples[0].Time.After(cutoff) {
+		h.samples = h.samples[1:]
+	}
+
+	if err := h.persist(); err != nil {
+		h.logger.Warn("failed to persist the usage history", "path", h.path, "error", err)
+	}
+}
+
+func (h *History) load() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var samples []structs.ServiceUsageSample
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.samples = samples
+	return nil
+}
+
+func (h *History) persist() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(h.samples)
+	if err != nil {
+		return err
+	}
+	return file.WriteAtomic(h.path, data)
+}
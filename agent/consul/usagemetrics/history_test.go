@@ -0,0 +1,62 @@
+package usagemetrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestHistory(t *testing.T) {
+	s := state.NewStateStore(nil)
+	require.NoError(t, s.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	require.NoError(t, s.EnsureService(2, "foo", &structs.NodeService{ID: "web", Service: "web"}))
+
+	sp := &mockStateProvider{}
+	sp.On("State").Return(s)
+
+	path := filepath.Join(testutil.TempDir(t, "usage-history"), HistoryFile)
+	h := NewHistory(nil, sp, path)
+	now := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	h.sampleIfDue()
+	samples := h.Samples()
+	require.Len(t, samples, 1)
+	require.True(t, now.Equal(samples[0].Time))
+	require.Equal(t, 1, samples[0].ServiceInstances)
+	require.Equal(t, 1, samples[0].ServiceKindInstances["typical"])
+
+	// The next sample is only taken once the sample interval elapsed.
+	require.NoError(t, s.EnsureService(3, "foo", &structs.NodeService{ID: "api", Service: "api"}))
+	now = now.Add(time.Hour)
+	h.sampleIfDue()
+	require.Len(t, h.Samples(), 1)
+
+	now = now.Add(historySampleInterval)
+	h.sampleIfDue()
+	samples = h.Samples()
+	require.Len(t, samples, 2)
+	require.Equal(t, 2, samples[1].ServiceInstances)
+
+	// The samples are persisted, and reloaded by a new History.
+	reloaded := NewHistory(nil, sp, path)
+	require.NoError(t, reloaded.load())
+	require.Len(t, reloaded.Samples(), 2)
+	for i, sample := range reloaded.Samples() {
+		require.True(t, samples[i].Time.Equal(sample.Time))
+		require.Equal(t, samples[i].ServiceInstances, sample.ServiceInstances)
+	}
+
+	// The samples older than the retention are dropped.
+	now = now.Add(historyRetention - time.Hour)
+	h.sampleIfDue()
+	samples = h.Samples()
+	require.Len(t, samples, 2)
+	require.True(t, now.Equal(samples[1].Time))
+}
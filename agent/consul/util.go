@@ -140,6 +140,21 @@ func ServersInDCMeetMinimumVersion(provider checkServersProvider, datacenter str
 	})
 }
 
+// ServersInDCSupportFeature returns whether the given alive servers from a
+// particular datacenter advertise the given feature flag. This also returns
+// whether any alive or failed servers are known in that datacenter (ignoring
+// left and leaving ones)
+func ServersInDCSupportFeature(provider checkServersProvider, datacenter string, flag string) (ok bool, found bool) {
+	return ServersInDCMeetRequirements(provider, datacenter, func(srv *metadata.Server) (bool, bool) {
+		if srv.Status != serf.StatusAlive && srv.Status != serf.StatusFailed {
+			// filter out the left servers as those should not be factored into our requirements
+			return true, true
+		}
+
+		return srv.FeatureFlags[flag] == 1, false
+	})
+}
+
 // CheckServers implements the checkServersProvider interface for the Server
 func (s *Server) CheckServers(datacenter string, fn func(*metadata.Server) bool) {
 	if datacenter == s.config.Datacenter {
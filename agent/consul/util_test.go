@@ -245,3 +245,75 @@ func TestServersInDCMeetMinimumVersion(t *testing.T) {
 		require.Equal(t, tc.expectedFound, found)
 	}
 }
+
+func TestServersInDCSupportFeature(t *testing.T) {
+	t.Parallel()
+	makeServer := func(datacenter string, status serf.MemberStatus, flags map[string]int) metadata.Server {
+		return metadata.Server{
+			Name:         "foo",
+			ShortName:    "foo",
+			ID:           "asdf",
+			Port:         10000,
+			Expect:       3,
+			RaftVersion:  3,
+			Status:       status,
+			WanJoinPort:  1234,
+			Version:      1,
+			Build:        *version.Must(version.NewVersion("1.15.0")),
+			Datacenter:   datacenter,
+			FeatureFlags: flags,
+		}
+	}
+	supported := map[string]int{"rc": 1}
+
+	cases := map[string]struct {
+		servers       testServersProvider
+		expected      bool
+		expectedFound bool
+	}{
+		"no servers": {
+			expected:      true,
+			expectedFound: false,
+		},
+		"all supported": {
+			servers: testServersProvider{
+				makeServer("primary", serf.StatusAlive, supported),
+				makeServer("primary", serf.StatusFailed, supported),
+			},
+			expected:      true,
+			expectedFound: true,
+		},
+		"one unsupported": {
+			servers: testServersProvider{
+				makeServer("primary", serf.StatusAlive, supported),
+				makeServer("primary", serf.StatusAlive, map[string]int{"fs": 1}),
+			},
+			expected:      false,
+			expectedFound: true,
+		},
+		"unsupported left server": {
+			servers: testServersProvider{
+				makeServer("primary", serf.StatusAlive, supported),
+				makeServer("primary", serf.StatusLeft, nil),
+			},
+			expected:      true,
+			expectedFound: true,
+		},
+		"unsupported in other datacenter": {
+			servers: testServersProvider{
+				makeServer("primary", serf.StatusAlive, supported),
+				makeServer("secondary", serf.StatusAlive, nil),
+			},
+			expected:      true,
+			expectedFound: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, found := ServersInDCSupportFeature(tc.servers, "primary", "rc")
+			require.Equal(t, tc.expected, result)
+			require.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
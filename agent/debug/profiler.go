@@ -0,0 +1,264 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// ProfileCPU is the CPU profile, captured for the CPUDuration of the
+	// ProfilerConfig.
+	ProfileCPU = "cpu"
+
+	// profileExtension is the extension of the files the profiles are
+	// written to, which are gzipped protobufs readable by go tool pprof.
+	profileExtension = ".pprof"
+
+	// profileTimeFormat is the format of the time of the capture in the file
+	// names. It sorts in the order of the captures.
+	profileTimeFormat = "20060102T150405.000Z"
+
+	// profilePushTimeout caps how long pushing a profile can take.
+	profilePushTimeout = 30 * time.Second
+)
+
+// ProfileTypes are the profiles the profiler can capture. Besides the CPU
+// profile, they are the profiles of runtime/pprof.
+var ProfileTypes = []string{ProfileCPU, "heap", "allocs", "goroutine", "block", "mutex", "threadcreate"}
+
+// DefaultProfileTypes are the profiles captured when none are configured.
+var DefaultProfileTypes = []string{ProfileCPU, "heap", "goroutine"}
+
+// ProfilerConfig configures the continuous profiler of the agent.
+type ProfilerConfig struct {
+	// Enabled turns the profiler on.
+	Enabled bool
+
+	// Dir is the directory the profiles are written to. The agent defaults it
+	// to the profiles directory of its data directory.
+	Dir string
+
+	// Interval is the time between two captures of the profiles.
+	Interval time.Duration
+
+	// CPUDuration is how long the CPU profile is captured for.
+	CPUDuration time.Duration
+
+	// Profiles are the types of the profiles captured, from ProfileTypes.
+	Profiles []string
+
+	// Retain is the number of captures of each profile kept on disk. The
+	// oldest ones are deleted first.
+	Retain int
+
+	// PushURL is the URL of a pprof compatible endpoint the profiles are
+	// also pushed to, if set.
+	PushURL string
+}
+
+// Validate checks that the configuration of an enabled profiler is usable.
+func (c ProfilerConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("profiling.interval must be greater than 0")
+	}
+	if c.Retain <= 0 {
+		return fmt.Errorf("profiling.retain must be greater than 0")
+	}
+	for _, p := range c.Profiles {
+		if !isProfileType(p) {
+			return fmt.Errorf("profiling.profiles must be one of %s. received: %q",
+				strings.Join(ProfileTypes, ", "), p)
+		}
+		if p == ProfileCPU && (c.CPUDuration <= 0 || c.CPUDuration >= c.Interval) {
+			return fmt.Errorf("profiling.cpu_duration must be greater than 0 and less than profiling.interval")
+		}
+	}
+	if c.PushURL != "" {
+		u, err := url.Parse(c.PushURL)
+		if err != nil || !(u.Scheme == "http" || u.Scheme == "https") {
+			return fmt.Errorf("profiling.push_url must be a valid http or https URL. received: %q", c.PushURL)
+		}
+	}
+	return nil
+}
+
+func isProfileType(name string) bool {
+	for _, p := range ProfileTypes {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Profiler periodically captures the profiles of the agent to a bounded set
+// of files on disk, so that they are available after an incident without
+// having to reproduce it.
+type Profiler struct {
+	cfg    ProfilerConfig
+	logger hclog.Logger
+	client *http.Client
+}
+
+// NewProfiler returns a profiler for the configuration. It must be started
+// with Run.
+func NewProfiler(cfg ProfilerConfig, logger hclog.Logger) *Profiler {
+	return &Profiler{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{Timeout: profilePushTimeout},
+	}
+}
+
+// Run captures the profiles every interval until the context is done.
+func (p *Profiler) Run(ctx context.Context) {
+	if err := os.MkdirAll(p.cfg.Dir, 0700); err != nil {
+		p.logger.Error("failed to create the profiles directory, the profiler is disabled",
+			"dir", p.cfg.Dir, "error", err)
+		return
+	}
+	p.logger.Info("capturing profiles", "dir", p.cfg.Dir, "interval", p.cfg.Interval,
+		"profiles", p.cfg.Profiles)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		p.captureAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Profiler) captureAll(ctx context.Context) {
+	for _, name := range p.cfg.Profiles {
+		if ctx.Err() != nil {
+			return
+		}
+
+		from := time.Now().UTC()
+		data, err := p.capture(ctx, name)
+		if err != nil {
+			p.logger.Warn("failed to capture profile", "profile", name, "error", err)
+			continue
+		}
+		until := time.Now().UTC()
+
+		if err := p.write(name, from, data); err != nil {
+			p.logger.Warn("failed to write profile", "profile", name, "error", err)
+		}
+		if p.cfg.PushURL != "" {
+			if err := p.push(ctx, name, from, until, data); err != nil {
+				p.logger.Warn("failed to push profile", "profile", name, "error", err)
+			}
+		}
+	}
+}
+
+// capture returns the profile in the gzipped protobuf format of pprof.
+func (p *Profiler) capture(ctx context.Context, name string) ([]byte, error) {
+	var buf bytes.Buffer
+	if name == ProfileCPU {
+		// This fails when a CPU profile is already being captured, for example
+		// by consul debug; the capture is skipped until the next interval.
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, err
+		}
+		timer := time.NewTimer(p.cfg.CPUDuration)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+		pprof.StopCPUProfile()
+		return buf.Bytes(), nil
+	}
+
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	if err := profile.WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write writes the profile to its file and deletes the oldest files of the
+// profile beyond the number to retain.
+func (p *Profiler) write(name string, from time.Time, data []byte) error {
+	path := filepath.Join(p.cfg.Dir, name+"-"+from.Format(profileTimeFormat)+profileExtension)
+
+	// Write to a temporary file first so that a partial profile is never
+	// read or kept.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(p.cfg.Dir, name+"-*"+profileExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for len(files) > p.cfg.Retain {
+		if err := os.Remove(files[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// push sends the profile to the push URL as the body of a POST request, with
+// its type and the unix times of the start and end of its capture as query
+// parameters.
+func (p *Profiler) push(ctx context.Context, name string, from, until time.Time, data []byte) error {
+	u, err := url.Parse(p.cfg.PushURL)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	query.Set("type", name)
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("until", strconv.FormatInt(until.Unix(), 10))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package debug
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+func TestProfiler_Run(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		pushed = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) == 0 || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		pushed[r.URL.Query().Get("type")]++
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfg := ProfilerConfig{
+		Enabled:     true,
+		Dir:         filepath.Join(dir, "profiles"),
+		Interval:    50 * time.Millisecond,
+		CPUDuration: 10 * time.Millisecond,
+		Profiles:    []string{ProfileCPU, "heap", "goroutine"},
+		Retain:      2,
+		PushURL:     srv.URL + "/ingest",
+	}
+	require.NoError(t, cfg.Validate())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewProfiler(cfg, hclog.NewNullLogger()).Run(ctx)
+		close(done)
+	}()
+
+	// Wait for more captures than are retained.
+	retry.Run(t, func(r *retry.R) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, name := range cfg.Profiles {
+			if pushed[name] < 3 {
+				r.Fatalf("profile %s was pushed %d times", name, pushed[name])
+			}
+		}
+	})
+	cancel()
+	<-done
+
+	for _, name := range cfg.Profiles {
+		files, err := filepath.Glob(filepath.Join(cfg.Dir, name+"-*.pprof"))
+		require.NoError(t, err)
+		require.Len(t, files, 2, name)
+	}
+	tmp, err := filepath.Glob(filepath.Join(cfg.Dir, "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, tmp)
+}
+
+func TestProfilerConfig_Validate(t *testing.T) {
+	valid := ProfilerConfig{
+		Enabled:     true,
+		Dir:         "/tmp/profiles",
+		Interval:    time.Minute,
+		CPUDuration: 10 * time.Second,
+		Profiles:    []string{ProfileCPU, "heap"},
+		Retain:      10,
+	}
+
+	cases := map[string]struct {
+		modify func(*ProfilerConfig)
+		err    string
+	}{
+		"valid": {
+			modify: func(*ProfilerConfig) {},
+		},
+		"disabled": {
+			modify: func(c *ProfilerConfig) {
+				*c = ProfilerConfig{}
+			},
+		},
+		"unknown profile": {
+			modify: func(c *ProfilerConfig) { c.Profiles = []string{"trace"} },
+			err:    `profiling.profiles must be one of cpu, heap, allocs, goroutine, block, mutex, threadcreate. received: "trace"`,
+		},
+		"cpu duration longer than interval": {
+			modify: func(c *ProfilerConfig) { c.CPUDuration = 2 * time.Minute },
+			err:    "profiling.cpu_duration must be greater than 0 and less than profiling.interval",
+		},
+		"cpu duration without cpu profile": {
+			modify: func(c *ProfilerConfig) {
+				c.Profiles = []string{"heap"}
+				c.CPUDuration = 0
+			},
+		},
+		"no retain": {
+			modify: func(c *ProfilerConfig) { c.Retain = 0 },
+			err:    "profiling.retain must be greater than 0",
+		},
+		"invalid push url": {
+			modify: func(c *ProfilerConfig) { c.PushURL = "localhost:4040" },
+			err:    "profiling.push_url must be a valid http or https URL",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			tc.modify(&cfg)
+			err := cfg.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
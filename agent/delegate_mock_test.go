@@ -23,6 +23,10 @@ func (m *delegateMock) GetLANCoordinate() (lib.CoordinateSet, error) {
 	return ret.Get(0).(lib.CoordinateSet), ret.Error(1)
 }
 
+func (m *delegateMock) GossipHealth() consul.GossipHealth {
+	return m.Called().Get(0).(consul.GossipHealth)
+}
+
 func (m *delegateMock) Leave() error {
 	return m.Called().Error(0)
 }
@@ -54,6 +58,10 @@ func (m *delegateMock) ResolveTokenAndDefaultMeta(token string, entMeta *acl.Ent
 	return ret.Get(0).(resolver.Result), ret.Error(1)
 }
 
+func (m *delegateMock) VetServiceNode(authz resolver.Result, service, node string) error {
+	return m.Called(authz, service, node).Error(0)
+}
+
 func (m *delegateMock) RPC(ctx context.Context, method string, args interface{}, reply interface{}) error {
 	return m.Called(method, args, reply).Error(0)
 }
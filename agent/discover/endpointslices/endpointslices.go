@@ -0,0 +1,161 @@
+// Package endpointslices provides node discovery from the EndpointSlices of a
+// Kubernetes service.
+package endpointslices
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type Provider struct{}
+
+func (p *Provider) Help() string {
+	return `Kubernetes EndpointSlices:
+
+    provider:       "k8s-endpointslices"
+    kubeconfig:     Path to the kubeconfig file.
+    namespace:      Namespace of the service (defaults to "default").
+    service:        Name of the service whose endpoints are returned.
+    label_selector: Label selector value to filter the EndpointSlices.
+    port_name:      Name of the port of the EndpointSlices to append to the
+                    addresses. No port is used by default.
+
+    The kubeconfig file is searched in the same locations as with the "k8s"
+    provider, and in-cluster auth is used as a fallback.
+
+    Only the endpoints that are ready are returned. Unlike the "k8s" provider,
+    this works with endpoints that are not pods, such as the ones of services
+    without selectors.
+`
+}
+
+func (p *Provider) Addrs(args map[string]string, l *log.Logger) ([]string, error) {
+	if args["provider"] != "k8s-endpointslices" {
+		return nil, fmt.Errorf("discover-k8s-endpointslices: invalid provider " + args["provider"])
+	}
+
+	selector := labels.Set{}
+	if args["service"] != "" {
+		selector[discoveryv1beta1.LabelServiceName] = args["service"]
+	}
+	labelSelector := selector.String()
+	if v := args["label_selector"]; v != "" {
+		if labelSelector != "" {
+			labelSelector += ","
+		}
+		labelSelector += v
+	}
+	if labelSelector == "" {
+		return nil, fmt.Errorf("discover-k8s-endpointslices: service or label_selector is required")
+	}
+
+	config, err := restConfig(args["kubeconfig"])
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("discover-k8s-endpointslices: error initializing k8s client: %s", err)
+	}
+
+	namespace := args["namespace"]
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	slices, err := clientset.DiscoveryV1beta1().EndpointSlices(namespace).List(
+		context.Background(),
+		metav1.ListOptions{LabelSelector: labelSelector},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("discover-k8s-endpointslices: error listing EndpointSlices: %s", err)
+	}
+
+	return EndpointSliceAddrs(slices, args, l), nil
+}
+
+// restConfig loads the configuration of the client from the kubeconfig file,
+// with the in-cluster configuration as a fallback.
+func restConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("discover-k8s-endpointslices: error retrieving home directory: %s", err)
+		}
+		kubeconfig = filepath.Join(dir, ".kube", "config")
+	}
+
+	config, configErr := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if configErr == nil {
+		return config, nil
+	}
+	configErr = fmt.Errorf("discover-k8s-endpointslices: error loading kubeconfig: %s", configErr)
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, multierror.Append(configErr, fmt.Errorf(
+			"discover-k8s-endpointslices: error loading in-cluster config: %s", err))
+	}
+	return config, nil
+}
+
+// EndpointSliceAddrs extracts the addresses from a list of EndpointSlices.
+//
+// This is a separate function so that it can be tested without a Kubernetes
+// cluster.
+func EndpointSliceAddrs(slices *discoveryv1beta1.EndpointSliceList, args map[string]string, l *log.Logger) []string {
+	portName := args["port_name"]
+
+	var addrs []string
+	for _, slice := range slices.Items {
+		if slice.AddressType == discoveryv1beta1.AddressTypeFQDN {
+			l.Printf("[DEBUG] discover-k8s-endpointslices: ignoring EndpointSlice %q, FQDN addresses are not supported", slice.Name)
+			continue
+		}
+
+		port := ""
+		if portName != "" {
+			for _, p := range slice.Ports {
+				if p.Name != nil && *p.Name == portName && p.Port != nil {
+					port = strconv.Itoa(int(*p.Port))
+					break
+				}
+			}
+			if port == "" {
+				l.Printf("[DEBUG] discover-k8s-endpointslices: ignoring EndpointSlice %q, no port named %q", slice.Name, portName)
+				continue
+			}
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// A nil Ready condition must be interpreted as ready.
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			// All the addresses of an endpoint are fungible, so only the
+			// first one is used.
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
+			addr := endpoint.Addresses[0]
+			if port != "" {
+				addr = net.JoinHostPort(addr, port)
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
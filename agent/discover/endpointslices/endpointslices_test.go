@@ -0,0 +1,75 @@
+package endpointslices
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEndpointSliceAddrs(t *testing.T) {
+	ready, notReady := true, false
+	rpc, serfLAN := "rpc", "serf-lan"
+	rpcPort, serfLANPort := int32(8300), int32(8301)
+
+	slices := &discoveryv1beta1.EndpointSliceList{
+		Items: []discoveryv1beta1.EndpointSlice{
+			{
+				ObjectMeta:  metav1.ObjectMeta{Name: "consul-ipv4"},
+				AddressType: discoveryv1beta1.AddressTypeIPv4,
+				Ports: []discoveryv1beta1.EndpointPort{
+					{Name: &rpc, Port: &rpcPort},
+					{Name: &serfLAN, Port: &serfLANPort},
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{
+					{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1beta1.EndpointConditions{Ready: &ready}},
+					{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1beta1.EndpointConditions{Ready: &notReady}},
+					{Addresses: []string{"10.0.0.3", "10.0.0.4"}},
+				},
+			},
+			{
+				ObjectMeta:  metav1.ObjectMeta{Name: "consul-ipv6"},
+				AddressType: discoveryv1beta1.AddressTypeIPv6,
+				Ports: []discoveryv1beta1.EndpointPort{
+					{Name: &serfLAN, Port: &serfLANPort},
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{
+					{Addresses: []string{"2001:db8::1"}},
+				},
+			},
+			{
+				ObjectMeta:  metav1.ObjectMeta{Name: "consul-fqdn"},
+				AddressType: discoveryv1beta1.AddressTypeFQDN,
+				Endpoints: []discoveryv1beta1.Endpoint{
+					{Addresses: []string{"consul.example.com"}},
+				},
+			},
+		},
+	}
+
+	l := log.New(os.Stderr, "", log.LstdFlags)
+
+	t.Run("without port", func(t *testing.T) {
+		addrs := EndpointSliceAddrs(slices, map[string]string{}, l)
+		require.Equal(t, []string{"10.0.0.1", "10.0.0.3", "2001:db8::1"}, addrs)
+	})
+
+	t.Run("with port", func(t *testing.T) {
+		addrs := EndpointSliceAddrs(slices, map[string]string{"port_name": "serf-lan"}, l)
+		require.Equal(t, []string{"10.0.0.1:8301", "10.0.0.3:8301", "[2001:db8::1]:8301"}, addrs)
+	})
+
+	t.Run("with port missing in a slice", func(t *testing.T) {
+		addrs := EndpointSliceAddrs(slices, map[string]string{"port_name": "rpc"}, l)
+		require.Equal(t, []string{"10.0.0.1:8300", "10.0.0.3:8300"}, addrs)
+	})
+}
+
+func TestAddrs_RequiresSelector(t *testing.T) {
+	p := &Provider{}
+	_, err := p.Addrs(map[string]string{"provider": "k8s-endpointslices"}, log.New(os.Stderr, "", log.LstdFlags))
+	require.EqualError(t, err, "discover-k8s-endpointslices: service or label_selector is required")
+}
@@ -0,0 +1,201 @@
+// Package hetzner provides node discovery for Hetzner Cloud.
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultEndpoint is the base URL of the Hetzner Cloud API.
+	defaultEndpoint = "https://api.hetzner.cloud/v1"
+
+	defaultTimeout = 10 * time.Second
+)
+
+type Provider struct {
+	userAgent string
+
+	// endpoint overrides the URL of the API in tests.
+	endpoint string
+}
+
+func (p *Provider) SetUserAgent(s string) {
+	p.userAgent = s
+}
+
+func (p *Provider) Help() string {
+	return `Hetzner Cloud:
+
+    provider:       "hetzner"
+    api_token:      The Hetzner Cloud API token. Defaults to the HCLOUD_TOKEN
+                    environment variable.
+    label_selector: The label selector to filter servers, e.g. "consul=server".
+    address_type:   "private_v4", "public_v4" or "public_v6". Defaults to
+                    "private_v4".
+    network_id:     The ID of the private network to take the address from when
+                    address_type is "private_v4". Defaults to the first network
+                    of each server.
+
+    Only the servers in the "running" state are returned.
+`
+}
+
+func (p *Provider) Addrs(args map[string]string, l *log.Logger) ([]string, error) {
+	if args["provider"] != "hetzner" {
+		return nil, fmt.Errorf("discover-hetzner: invalid provider " + args["provider"])
+	}
+
+	token := args["api_token"]
+	if token == "" {
+		token = os.Getenv("HCLOUD_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("discover-hetzner: api_token is required")
+	}
+
+	addressType := args["address_type"]
+	switch addressType {
+	case "":
+		addressType = "private_v4"
+	case "private_v4", "public_v4", "public_v6":
+	default:
+		return nil, fmt.Errorf("discover-hetzner: invalid address_type %q", addressType)
+	}
+
+	var networkID int64
+	if v := args["network_id"]; v != "" {
+		var err error
+		networkID, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("discover-hetzner: network_id must be an integer: %s", err)
+		}
+	}
+
+	endpoint := p.endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var addrs []string
+	for page := 1; page != 0; {
+		resp, err := p.listServers(ctx, endpoint, token, args["label_selector"], page)
+		if err != nil {
+			return nil, fmt.Errorf("discover-hetzner: error listing servers: %s", err)
+		}
+		for _, srv := range resp.Servers {
+			if srv.Status != "running" {
+				l.Printf("[DEBUG] discover-hetzner: ignoring server %q, not running: %q", srv.Name, srv.Status)
+				continue
+			}
+			addr := srv.addr(addressType, networkID)
+			if addr == "" {
+				l.Printf("[DEBUG] discover-hetzner: ignoring server %q, no %s address", srv.Name, addressType)
+				continue
+			}
+			addrs = append(addrs, addr)
+		}
+		page = resp.Meta.Pagination.NextPage
+	}
+
+	l.Printf("[DEBUG] discover-hetzner: Found ip addresses: %v", addrs)
+	return addrs, nil
+}
+
+func (p *Provider) listServers(ctx context.Context, endpoint, token, labelSelector string, page int) (*serversResponse, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", "50")
+	if labelSelector != "" {
+		query.Set("label_selector", labelSelector)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/servers?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+
+	var out serversResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type serversResponse struct {
+	Servers []server `json:"servers"`
+	Meta    struct {
+		Pagination struct {
+			NextPage int `json:"next_page"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+type server struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	PublicNet struct {
+		IPv4 *struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+		IPv6 *struct {
+			IP string `json:"ip"`
+		} `json:"ipv6"`
+	} `json:"public_net"`
+	PrivateNet []struct {
+		Network int64  `json:"network"`
+		IP      string `json:"ip"`
+	} `json:"private_net"`
+}
+
+func (s *server) addr(addressType string, networkID int64) string {
+	switch addressType {
+	case "public_v4":
+		if s.PublicNet.IPv4 != nil {
+			return s.PublicNet.IPv4.IP
+		}
+	case "public_v6":
+		// Servers are assigned a whole /64 network, the first address is
+		// the one configured on the server.
+		if s.PublicNet.IPv6 != nil {
+			ip, _, err := net.ParseCIDR(s.PublicNet.IPv6.IP)
+			if err != nil {
+				return ""
+			}
+			ip[len(ip)-1] = 1
+			return ip.String()
+		}
+	default:
+		for _, n := range s.PrivateNet {
+			if networkID == 0 || n.Network == networkID {
+				return n.IP
+			}
+		}
+	}
+	return ""
+}
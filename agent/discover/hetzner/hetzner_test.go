@@ -0,0 +1,109 @@
+package hetzner
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "/servers", r.URL.Path)
+		require.Equal(t, "consul=server", r.URL.Query().Get("label_selector"))
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{
+				"servers": [
+					{
+						"name": "server-1",
+						"status": "running",
+						"public_net": {"ipv4": {"ip": "198.51.100.1"}, "ipv6": {"ip": "2001:db8:1::/64"}},
+						"private_net": [{"network": 1, "ip": "10.0.0.1"}, {"network": 2, "ip": "10.1.0.1"}]
+					},
+					{
+						"name": "server-2",
+						"status": "off",
+						"public_net": {"ipv4": {"ip": "198.51.100.2"}},
+						"private_net": [{"network": 1, "ip": "10.0.0.2"}]
+					}
+				],
+				"meta": {"pagination": {"next_page": 2}}
+			}`)
+		case "2":
+			fmt.Fprint(w, `{
+				"servers": [
+					{
+						"name": "server-3",
+						"status": "running",
+						"public_net": {"ipv4": null, "ipv6": {"ip": "2001:db8:3::/64"}},
+						"private_net": [{"network": 2, "ip": "10.1.0.3"}]
+					}
+				],
+				"meta": {"pagination": {"next_page": null}}
+			}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cases := map[string]struct {
+		args     map[string]string
+		expected []string
+	}{
+		"private": {
+			args:     map[string]string{},
+			expected: []string{"10.0.0.1", "10.1.0.3"},
+		},
+		"private in network": {
+			args:     map[string]string{"network_id": "2"},
+			expected: []string{"10.1.0.1", "10.1.0.3"},
+		},
+		"public v4": {
+			args:     map[string]string{"address_type": "public_v4"},
+			expected: []string{"198.51.100.1"},
+		},
+		"public v6": {
+			args:     map[string]string{"address_type": "public_v6"},
+			expected: []string{"2001:db8:1::1", "2001:db8:3::1"},
+		},
+	}
+
+	l := log.New(os.Stderr, "", log.LstdFlags)
+	p := &Provider{endpoint: srv.URL}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := map[string]string{
+				"provider":       "hetzner",
+				"api_token":      "token",
+				"label_selector": "consul=server",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			addrs, err := p.Addrs(args, l)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, addrs)
+		})
+	}
+}
+
+func TestAddrs_Errors(t *testing.T) {
+	t.Setenv("HCLOUD_TOKEN", "")
+
+	l := log.New(os.Stderr, "", log.LstdFlags)
+	p := &Provider{}
+
+	_, err := p.Addrs(map[string]string{"provider": "hetzner"}, l)
+	require.EqualError(t, err, "discover-hetzner: api_token is required")
+
+	_, err = p.Addrs(map[string]string{"provider": "hetzner", "api_token": "token", "address_type": "private_v6"}, l)
+	require.EqualError(t, err, `discover-hetzner: invalid address_type "private_v6"`)
+}
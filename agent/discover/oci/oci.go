@@ -0,0 +1,314 @@
+// Package oci provides node discovery for Oracle Cloud Infrastructure.
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// apiVersion is the version of the Core Services API used to list the
+	// instances.
+	apiVersion = "20160918"
+
+	defaultTimeout = 10 * time.Second
+)
+
+type Provider struct {
+	userAgent string
+
+	// endpoint overrides the URL of the API in tests.
+	endpoint string
+}
+
+func (p *Provider) SetUserAgent(s string) {
+	p.userAgent = s
+}
+
+func (p *Provider) Help() string {
+	return `Oracle Cloud Infrastructure:
+
+    provider:         "oci"
+    region:           The region of the instances, e.g. "us-ashburn-1".
+    tenancy_ocid:     The OCID of the tenancy.
+    user_ocid:        The OCID of the user making the API requests.
+    fingerprint:      The fingerprint of the API signing key of the user.
+    key_file:         The path to the PEM encoded API signing key of the user.
+    compartment_ocid: The OCID of the compartment of the instances. Defaults to
+                      the tenancy.
+    tag_namespace:    The namespace of the defined tag to filter instances on.
+                      Free-form tags are used when it is empty.
+    tag_key:          The key of the tag to filter instances on.
+    tag_value:        The value of the tag to filter instances on.
+    address_type:     "private_v4" or "public_v4". Defaults to "private_v4".
+
+    Only the instances in the "RUNNING" state are returned, with the address
+    of their primary VNIC.
+`
+}
+
+func (p *Provider) Addrs(args map[string]string, l *log.Logger) ([]string, error) {
+	if args["provider"] != "oci" {
+		return nil, fmt.Errorf("discover-oci: invalid provider " + args["provider"])
+	}
+
+	for _, k := range []string{"region", "tenancy_ocid", "user_ocid", "fingerprint", "key_file"} {
+		if args[k] == "" {
+			return nil, fmt.Errorf("discover-oci: %s is required", k)
+		}
+	}
+
+	addressType := args["address_type"]
+	switch addressType {
+	case "":
+		addressType = "private_v4"
+	case "private_v4", "public_v4":
+	default:
+		return nil, fmt.Errorf("discover-oci: invalid address_type %q", addressType)
+	}
+
+	key, err := loadKey(args["key_file"])
+	if err != nil {
+		return nil, fmt.Errorf("discover-oci: error loading key_file: %s", err)
+	}
+
+	compartment := args["compartment_ocid"]
+	if compartment == "" {
+		compartment = args["tenancy_ocid"]
+	}
+
+	endpoint := p.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://iaas.%s.oraclecloud.com", args["region"])
+	}
+
+	c := &client{
+		endpoint:  endpoint + "/" + apiVersion,
+		keyID:     args["tenancy_ocid"] + "/" + args["user_ocid"] + "/" + args["fingerprint"],
+		key:       key,
+		userAgent: p.userAgent,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("compartmentId", compartment)
+	query.Set("lifecycleState", "RUNNING")
+	instances, err := list[instance](ctx, c, "/instances", query)
+	if err != nil {
+		return nil, fmt.Errorf("discover-oci: error listing instances: %s", err)
+	}
+
+	var addrs []string
+	for _, inst := range instances {
+		if !inst.hasTag(args["tag_namespace"], args["tag_key"], args["tag_value"]) {
+			continue
+		}
+
+		nic, err := c.primaryVNIC(ctx, compartment, inst.ID)
+		if err != nil {
+			return nil, fmt.Errorf("discover-oci: error getting the VNIC of instance %q: %s", inst.DisplayName, err)
+		}
+		if nic == nil {
+			l.Printf("[DEBUG] discover-oci: ignoring instance %q, no primary VNIC", inst.DisplayName)
+			continue
+		}
+
+		addr := nic.PrivateIP
+		if addressType == "public_v4" {
+			addr = nic.PublicIP
+		}
+		if addr == "" {
+			l.Printf("[DEBUG] discover-oci: ignoring instance %q, no %s address", inst.DisplayName, addressType)
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+
+	l.Printf("[DEBUG] discover-oci: Found ip addresses: %v", addrs)
+	return addrs, nil
+}
+
+type instance struct {
+	ID           string                            `json:"id"`
+	DisplayName  string                            `json:"displayName"`
+	FreeformTags map[string]string                 `json:"freeformTags"`
+	DefinedTags  map[string]map[string]interface{} `json:"definedTags"`
+}
+
+// hasTag returns true if the instance has the given tag. The free-form tags
+// are used when namespace is empty, and the defined tags otherwise. All the
+// instances match when key is empty.
+func (i *instance) hasTag(namespace, key, value string) bool {
+	if key == "" {
+		return true
+	}
+	if namespace == "" {
+		v, ok := i.FreeformTags[key]
+		return ok && v == value
+	}
+	v, ok := i.DefinedTags[namespace][key]
+	return ok && fmt.Sprint(v) == value
+}
+
+type vnicAttachment struct {
+	VNICID         string `json:"vnicId"`
+	LifecycleState string `json:"lifecycleState"`
+}
+
+type vnic struct {
+	IsPrimary bool   `json:"isPrimary"`
+	PrivateIP string `json:"privateIp"`
+	PublicIP  string `json:"publicIp"`
+}
+
+// primaryVNIC returns the primary VNIC of an instance, or nil if it has none
+// attached.
+func (c *client) primaryVNIC(ctx context.Context, compartment, instanceID string) (*vnic, error) {
+	query := url.Values{}
+	query.Set("compartmentId", compartment)
+	query.Set("instanceId", instanceID)
+	attachments, err := list[vnicAttachment](ctx, c, "/vnicAttachments", query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.LifecycleState != "ATTACHED" {
+			continue
+		}
+		var v vnic
+		if _, err := c.get(ctx, "/vnics/"+url.PathEscape(attachment.VNICID), nil, &v); err != nil {
+			return nil, err
+		}
+		if v.IsPrimary {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+// client makes requests to the OCI API, signed with the API key of a user.
+type client struct {
+	endpoint  string
+	keyID     string
+	key       *rsa.PrivateKey
+	userAgent string
+}
+
+// list gets all the pages of a list operation.
+func list[T any](ctx context.Context, c *client, path string, query url.Values) ([]T, error) {
+	query.Set("limit", "100")
+	var items []T
+	for {
+		var page []T
+		next, err := c.get(ctx, path, query, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if next == "" {
+			return items, nil
+		}
+		query.Set("page", next)
+	}
+}
+
+// get makes a signed GET request and decodes its JSON response in out. It
+// returns the token of the next page, if any.
+func (c *client) get(ctx context.Context, path string, query url.Values, out interface{}) (string, error) {
+	u := c.endpoint + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if err := c.sign(req); err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("opc-next-page"), nil
+}
+
+// signedHeaders are the headers included in the signature of the requests.
+const signedHeaders = "date (request-target) host"
+
+// sign adds the signature of a GET request to its headers, as described in
+// https://docs.oracle.com/en-us/iaas/Content/API/Concepts/signingrequests.htm.
+func (c *client) sign(req *http.Request) error {
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("Date", date)
+
+	digest := sha256.Sum256([]byte(signingString(req)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`Signature version="1",keyId=%q,algorithm="rsa-sha256",headers=%q,signature=%q`,
+		c.keyID, signedHeaders, base64.StdEncoding.EncodeToString(signature),
+	))
+	return nil
+}
+
+func signingString(req *http.Request) string {
+	return fmt.Sprintf("date: %s\n(request-target): get %s\nhost: %s",
+		req.Header.Get("Date"), req.URL.RequestURI(), req.URL.Host)
+}
+
+func loadKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the key must be an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,140 @@
+package oci
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrs(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+
+	authRe := regexp.MustCompile(`^Signature version="1",keyId="tenancy/user/fp",algorithm="rsa-sha256",headers="date \(request-target\) host",signature="(.+)"$`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := authRe.FindStringSubmatch(r.Header.Get("Authorization"))
+		if m == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		signature, err := base64.StdEncoding.DecodeString(m[1])
+		require.NoError(t, err)
+		r.URL.Host = r.Host
+		digest := sha256.Sum256([]byte(signingString(r)))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/20160918/instances":
+			require.Equal(t, "compartment", q.Get("compartmentId"))
+			require.Equal(t, "RUNNING", q.Get("lifecycleState"))
+			if q.Get("page") == "" {
+				w.Header().Set("opc-next-page", "2")
+				fmt.Fprint(w, `[
+					{"id": "i1", "displayName": "one", "freeformTags": {"consul": "server"}, "definedTags": {"ns": {"role": "server"}}},
+					{"id": "i2", "displayName": "two", "freeformTags": {"consul": "client"}}
+				]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id": "i3", "displayName": "three", "freeformTags": {"consul": "server"}}]`)
+		case "/20160918/vnicAttachments":
+			instance := q.Get("instanceId")
+			fmt.Fprintf(w, `[
+				{"vnicId": "%[1]s-detached", "lifecycleState": "DETACHED"},
+				{"vnicId": "%[1]s-secondary", "lifecycleState": "ATTACHED"},
+				{"vnicId": "%[1]s", "lifecycleState": "ATTACHED"}
+			]`, instance)
+		case "/20160918/vnics/i1", "/20160918/vnics/i2", "/20160918/vnics/i3":
+			id := r.URL.Path[len("/20160918/vnics/i"):]
+			fmt.Fprintf(w, `{"isPrimary": true, "privateIp": "10.0.0.%[1]s", "publicIp": "198.51.100.%[1]s"}`, id)
+		case "/20160918/vnics/i1-secondary", "/20160918/vnics/i2-secondary", "/20160918/vnics/i3-secondary":
+			fmt.Fprint(w, `{"isPrimary": false, "privateIp": "10.1.0.1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cases := map[string]struct {
+		args     map[string]string
+		expected []string
+	}{
+		"all": {
+			args:     map[string]string{},
+			expected: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		"free-form tag": {
+			args:     map[string]string{"tag_key": "consul", "tag_value": "server"},
+			expected: []string{"10.0.0.1", "10.0.0.3"},
+		},
+		"defined tag": {
+			args:     map[string]string{"tag_namespace": "ns", "tag_key": "role", "tag_value": "server"},
+			expected: []string{"10.0.0.1"},
+		},
+		"public": {
+			args:     map[string]string{"tag_key": "consul", "tag_value": "client", "address_type": "public_v4"},
+			expected: []string{"198.51.100.2"},
+		},
+	}
+
+	l := log.New(os.Stderr, "", log.LstdFlags)
+	p := &Provider{endpoint: srv.URL}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := map[string]string{
+				"provider":         "oci",
+				"region":           "us-ashburn-1",
+				"tenancy_ocid":     "tenancy",
+				"user_ocid":        "user",
+				"fingerprint":      "fp",
+				"key_file":         keyFile,
+				"compartment_ocid": "compartment",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			addrs, err := p.Addrs(args, l)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, addrs)
+		})
+	}
+}
+
+func TestAddrs_Errors(t *testing.T) {
+	l := log.New(os.Stderr, "", log.LstdFlags)
+	p := &Provider{}
+
+	_, err := p.Addrs(map[string]string{"provider": "oci", "region": "us-ashburn-1"}, l)
+	require.EqualError(t, err, "discover-oci: tenancy_ocid is required")
+
+	_, err = p.Addrs(map[string]string{
+		"provider":     "oci",
+		"region":       "us-ashburn-1",
+		"tenancy_ocid": "tenancy",
+		"user_ocid":    "user",
+		"fingerprint":  "fp",
+		"key_file":     filepath.Join(t.TempDir(), "missing.pem"),
+	}, l)
+	require.ErrorContains(t, err, "discover-oci: error loading key_file")
+}
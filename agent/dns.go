@@ -2,13 +2,18 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net"
+	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -35,6 +40,22 @@ var DNSCounters = []prometheus.CounterDefinition{
 		Name: []string{"dns", "stale_queries"},
 		Help: "Increments when an agent serves a query within the allowed stale threshold.",
 	},
+	{
+		Name: []string{"dns", "query"},
+		Help: "Increments for each DNS query in the agent domains, labeled by query type, domain and response code.",
+	},
+	{
+		Name: []string{"dns", "service_query"},
+		Help: "Increments for each DNS service lookup, labeled by service and response code, when dns_config.service_metrics_limit is set.",
+	},
+	{
+		Name: []string{"dns", "answer_cache", "hit"},
+		Help: "Increments when an agent answers a DNS query from its answer cache.",
+	},
+	{
+		Name: []string{"dns", "answer_cache", "stale_served"},
+		Help: "Increments when an agent serves a stale cached answer because resolving the DNS query failed.",
+	},
 }
 
 var DNSSummaries = []prometheus.SummaryDefinition{
@@ -60,6 +81,10 @@ const (
 	// Increment a counter when requests staler than this are served
 	staleCounterThreshold = 5 * time.Second
 
+	// serviceMetricsOtherLabel is the service label of the DNS service
+	// metrics for services past dns_config.service_metrics_limit.
+	serviceMetricsOtherLabel = "_other"
+
 	defaultMaxUDPSize = 512
 
 	// If a consumer sets a buffer size greater than this amount we will default it down
@@ -101,6 +126,28 @@ type dnsConfig struct {
 	TTLStrict          map[string]time.Duration
 	DisableCompression bool
 
+	// VirtualAnswerRadix and VirtualAnswerStrict select the answer of
+	// <service>.virtual queries by service name prefix and full service name,
+	// like TTLRadix and TTLStrict.
+	VirtualAnswerRadix  *radix.Tree
+	VirtualAnswerStrict map[string]agentdns.VirtualAnswer
+	VirtualTTL          time.Duration
+
+	// ViewTags and ViewExcludeTags filter the instances returned by service
+	// lookups made with a view.
+	ViewTags        []string
+	ViewExcludeTags []string
+
+	// AnswerCacheTTL is how long answers are reused from the answer cache,
+	// and AnswerCacheStale how much longer they can be served when resolving
+	// the query fails.
+	AnswerCacheTTL   time.Duration
+	AnswerCacheStale time.Duration
+
+	// ServiceMetricsLimit is the number of services labeled in the DNS
+	// service metrics. A value of 0 disables the service metrics.
+	ServiceMetricsLimit int
+
 	enterpriseDNSConfig
 }
 
@@ -134,6 +181,14 @@ type DNSServer struct {
 	altDomain string
 	logger    hclog.Logger
 
+	// peerDomain is the domain for services imported from peers, where
+	// the label before the domain is the peer name.
+	peerDomain string
+
+	// views are additional domains with their own policy for service
+	// lookups. Their domains are FQDNs and lowercase.
+	views []config.RuntimeDNSView
+
 	// config stores the config as an atomic value (for hot-reloading). It is always of type *dnsConfig
 	config atomic.Value
 
@@ -141,6 +196,15 @@ type DNSServer struct {
 	// the recursor handler is only enabled if recursors are configured. This flag is used during config hot-reloading
 	recursorEnabled uint32
 
+	// answerCache keeps the answers to queries in the domains served by the
+	// agent when dns_config.answer_cache_ttl is set.
+	answerCache *dnsAnswerCache
+
+	// serviceMetricsLock protects serviceMetricsLabels, the services that
+	// have their own label in the DNS service metrics.
+	serviceMetricsLock   sync.Mutex
+	serviceMetricsLabels map[string]struct{}
+
 	defaultEnterpriseMeta acl.EnterpriseMeta
 }
 
@@ -148,13 +212,21 @@ func NewDNSServer(a *Agent) (*DNSServer, error) {
 	// Make sure domains are FQDN, make them case insensitive for ServeMux
 	domain := dns.Fqdn(strings.ToLower(a.config.DNSDomain))
 	altDomain := dns.Fqdn(strings.ToLower(a.config.DNSAltDomain))
+	peerDomain := dns.Fqdn(strings.ToLower(a.config.DNSPeerDomain))
 	srv := &DNSServer{
 		agent:                 a,
 		domain:                domain,
 		altDomain:             altDomain,
+		peerDomain:            peerDomain,
 		logger:                a.logger.Named(logging.DNS),
 		defaultEnterpriseMeta: *a.AgentEnterpriseMeta(),
 		mux:                   dns.NewServeMux(),
+		answerCache:           newDNSAnswerCache(),
+		serviceMetricsLabels:  make(map[string]struct{}),
+	}
+	for _, view := range a.config.DNSViews {
+		view.Domain = dns.Fqdn(strings.ToLower(view.Domain))
+		srv.views = append(srv.views, view)
 	}
 	cfg, err := GetDNSConfig(a.config)
 	if err != nil {
@@ -173,6 +245,12 @@ func NewDNSServer(a *Agent) (*DNSServer, error) {
 	if srv.altDomain != "." {
 		srv.mux.HandleFunc(srv.altDomain, srv.handleQuery)
 	}
+	if srv.peerDomain != "." {
+		srv.mux.HandleFunc(srv.peerDomain, srv.handleQuery)
+	}
+	for _, view := range srv.views {
+		srv.mux.HandleFunc(view.Domain, srv.handleQuery)
+	}
 	srv.toggleRecursorHandlerFromConfig(cfg)
 
 	return srv, nil
@@ -181,22 +259,26 @@ func NewDNSServer(a *Agent) (*DNSServer, error) {
 // GetDNSConfig takes global config and creates the config used by DNS server
 func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 	cfg := &dnsConfig{
-		AllowStale:         conf.DNSAllowStale,
-		ARecordLimit:       conf.DNSARecordLimit,
-		Datacenter:         conf.Datacenter,
-		EnableTruncate:     conf.DNSEnableTruncate,
-		MaxStale:           conf.DNSMaxStale,
-		NodeName:           conf.NodeName,
-		NodeTTL:            conf.DNSNodeTTL,
-		OnlyPassing:        conf.DNSOnlyPassing,
-		RecursorStrategy:   conf.DNSRecursorStrategy,
-		RecursorTimeout:    conf.DNSRecursorTimeout,
-		SegmentName:        conf.SegmentName,
-		UDPAnswerLimit:     conf.DNSUDPAnswerLimit,
-		NodeMetaTXT:        conf.DNSNodeMetaTXT,
-		DisableCompression: conf.DNSDisableCompression,
-		UseCache:           conf.DNSUseCache,
-		CacheMaxAge:        conf.DNSCacheMaxAge,
+		AllowStale:          conf.DNSAllowStale,
+		ARecordLimit:        conf.DNSARecordLimit,
+		Datacenter:          conf.Datacenter,
+		EnableTruncate:      conf.DNSEnableTruncate,
+		MaxStale:            conf.DNSMaxStale,
+		NodeName:            conf.NodeName,
+		NodeTTL:             conf.DNSNodeTTL,
+		OnlyPassing:         conf.DNSOnlyPassing,
+		RecursorStrategy:    conf.DNSRecursorStrategy,
+		RecursorTimeout:     conf.DNSRecursorTimeout,
+		SegmentName:         conf.SegmentName,
+		UDPAnswerLimit:      conf.DNSUDPAnswerLimit,
+		NodeMetaTXT:         conf.DNSNodeMetaTXT,
+		DisableCompression:  conf.DNSDisableCompression,
+		UseCache:            conf.DNSUseCache,
+		CacheMaxAge:         conf.DNSCacheMaxAge,
+		AnswerCacheTTL:      conf.DNSAnswerCacheTTL,
+		AnswerCacheStale:    conf.DNSAnswerCacheStale,
+		VirtualTTL:          conf.DNSVirtualTTL,
+		ServiceMetricsLimit: conf.DNSServiceMetricsLimit,
 		SOAConfig: dnsSOAConfig{
 			Expire:  conf.DNSSOA.Expire,
 			Minttl:  conf.DNSSOA.Minttl,
@@ -219,6 +301,18 @@ func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 			}
 		}
 	}
+	if len(conf.DNSVirtualAnswers) > 0 {
+		cfg.VirtualAnswerRadix = radix.New()
+		cfg.VirtualAnswerStrict = make(map[string]agentdns.VirtualAnswer)
+
+		for key, answer := range conf.DNSVirtualAnswers {
+			if strings.HasSuffix(key, "*") {
+				cfg.VirtualAnswerRadix.Insert(key[:len(key)-1], answer)
+			} else {
+				cfg.VirtualAnswerStrict[key] = answer
+			}
+		}
+	}
 	for _, r := range conf.DNSRecursors {
 		ra, err := recursorAddr(r)
 		if err != nil {
@@ -230,6 +324,16 @@ func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 	return cfg, nil
 }
 
+// withView returns a copy of the config with the answer policy of the view
+// applied.
+func (cfg *dnsConfig) withView(view *config.RuntimeDNSView) *dnsConfig {
+	viewCfg := *cfg
+	viewCfg.OnlyPassing = view.OnlyPassing
+	viewCfg.ViewTags = view.Tags
+	viewCfg.ViewExcludeTags = view.ExcludeTags
+	return &viewCfg
+}
+
 // GetTTLForService Find the TTL for a given service.
 // return ttl, true if found, 0, false otherwise
 func (cfg *dnsConfig) GetTTLForService(service string) (time.Duration, bool) {
@@ -248,6 +352,21 @@ func (cfg *dnsConfig) GetTTLForService(service string) (time.Duration, bool) {
 	return 0, false
 }
 
+// GetVirtualAnswerForService returns whether <service>.virtual queries for the
+// service are answered with its virtual IP or with the addresses of its
+// instances.
+func (cfg *dnsConfig) GetVirtualAnswerForService(service string) agentdns.VirtualAnswer {
+	if answer, ok := cfg.VirtualAnswerStrict[service]; ok {
+		return answer
+	}
+	if cfg.VirtualAnswerRadix != nil {
+		if _, answer, ok := cfg.VirtualAnswerRadix.LongestPrefix(service); ok {
+			return answer.(agentdns.VirtualAnswer)
+		}
+	}
+	return agentdns.VirtualAnswerVirtualIP
+}
+
 func (d *DNSServer) ListenAndServe(network, addr string, notif func()) error {
 	d.Server = &dns.Server{
 		Addr:              addr,
@@ -261,6 +380,119 @@ func (d *DNSServer) ListenAndServe(network, addr string, notif func()) error {
 	return d.Server.ListenAndServe()
 }
 
+// Serve starts a DNS server on a listener or a packet connection, e.g. the
+// sockets passed by systemd.
+func (d *DNSServer) Serve(l net.Listener, pc net.PacketConn, notif func()) error {
+	d.Server = &dns.Server{
+		Listener:          l,
+		PacketConn:        pc,
+		Handler:           d.mux,
+		NotifyStartedFunc: notif,
+	}
+	if pc != nil {
+		d.UDPSize = 65535
+	}
+	return d.Server.ActivateAndServe()
+}
+
+// ListenAndServeTLS starts a DNS-over-TLS server on the given TCP address.
+func (d *DNSServer) ListenAndServeTLS(addr string, tlsConfig *tls.Config, notif func()) error {
+	d.Server = &dns.Server{
+		Addr:              addr,
+		Net:               "tcp-tls",
+		TLSConfig:         tlsConfig,
+		Handler:           d.mux,
+		NotifyStartedFunc: notif,
+	}
+	return d.Server.ListenAndServe()
+}
+
+// dnsMessageContentType is the media type of the DNS messages exchanged
+// with DNS-over-HTTPS clients.
+const dnsMessageContentType = "application/dns-message"
+
+// ServeHTTP implements DNS-over-HTTPS as defined in RFC 8484. The DNS
+// message is taken from the "dns" query parameter of GET requests or from
+// the body of POST requests and is answered by the same handlers as the DNS
+// server.
+func (d *DNSServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	var raw []byte
+	var err error
+	switch req.Method {
+	case http.MethodGet:
+		raw, err = base64.RawURLEncoding.DecodeString(req.URL.Query().Get("dns"))
+	case http.MethodPost:
+		if contentType := req.Header.Get("Content-Type"); contentType != dnsMessageContentType {
+			http.Error(resp, fmt.Sprintf("Unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+			return
+		}
+		raw, err = io.ReadAll(io.LimitReader(req.Body, dns.MaxMsgSize))
+	default:
+		resp.Header().Set("Allow", "GET, POST")
+		http.Error(resp, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(resp, fmt.Sprintf("Invalid DNS message: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	msg := new(dns.Msg)
+	if err := msg.Unpack(raw); err != nil {
+		http.Error(resp, fmt.Sprintf("Invalid DNS message: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The answer is not limited by the size of a UDP datagram, so the
+	// request is handled as if it was received over TCP.
+	w := &httpDNSResponseWriter{}
+	if addr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		w.localAddr = addr
+	}
+	if addr, err := net.ResolveTCPAddr("tcp", req.RemoteAddr); err == nil {
+		w.remoteAddr = addr
+	} else {
+		w.remoteAddr = &net.TCPAddr{}
+	}
+	d.mux.ServeDNS(w, msg)
+
+	if w.msg == nil {
+		http.Error(resp, "No DNS response", http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", dnsMessageContentType)
+	resp.Write(w.msg)
+}
+
+// httpDNSResponseWriter is a dns.ResponseWriter that captures the packed
+// answer to a DNS-over-HTTPS request.
+type httpDNSResponseWriter struct {
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	msg        []byte
+}
+
+func (w *httpDNSResponseWriter) LocalAddr() net.Addr  { return w.localAddr }
+func (w *httpDNSResponseWriter) RemoteAddr() net.Addr { return w.remoteAddr }
+func (w *httpDNSResponseWriter) Close() error         { return nil }
+func (w *httpDNSResponseWriter) TsigStatus() error    { return nil }
+func (w *httpDNSResponseWriter) TsigTimersOnly(bool)  {}
+func (w *httpDNSResponseWriter) Hijack()              {}
+
+func (w *httpDNSResponseWriter) WriteMsg(m *dns.Msg) error {
+	msg, err := m.Pack()
+	if err != nil {
+		return err
+	}
+	w.msg = msg
+	return nil
+}
+
+func (w *httpDNSResponseWriter) Write(b []byte) (int, error) {
+	w.msg = append([]byte(nil), b...)
+	return len(b), nil
+}
+
 // toggleRecursorHandlerFromConfig enables or disables the recursor handler based on config idempotently
 func (d *DNSServer) toggleRecursorHandlerFromConfig(cfg *dnsConfig) {
 	shouldEnable := len(cfg.Recursors) > 0
@@ -364,7 +596,7 @@ func serviceIngressDNSName(service, datacenter, domain string, entMeta *acl.Ente
 }
 
 // getResponseDomain returns alt-domain if it is configured and request is made with alt-domain,
-// respects DNS case insensitivity
+// or the domain of the view the request is made with, respects DNS case insensitivity
 func (d *DNSServer) getResponseDomain(questionName string) string {
 	labels := dns.SplitDomainName(questionName)
 	domain := d.domain
@@ -373,10 +605,54 @@ func (d *DNSServer) getResponseDomain(questionName string) string {
 		if strings.EqualFold(currentSuffix, d.domain) || strings.EqualFold(currentSuffix, d.altDomain) {
 			domain = currentSuffix
 		}
+		for _, view := range d.views {
+			if strings.EqualFold(currentSuffix, view.Domain) {
+				domain = currentSuffix
+			}
+		}
 	}
 	return domain
 }
 
+// getView returns the view the request is made with, or nil if the request
+// is made with the primary or alternate domain.
+func (d *DNSServer) getView(questionName string) *config.RuntimeDNSView {
+	domain := d.getResponseDomain(questionName)
+	for i, view := range d.views {
+		if strings.EqualFold(domain, view.Domain) {
+			return &d.views[i]
+		}
+	}
+	return nil
+}
+
+// metricsDomain returns the domain a question is in, for use as a metric
+// label.
+func (d *DNSServer) metricsDomain(questionName string) string {
+	name := strings.ToLower(dns.Fqdn(questionName))
+	if d.inPeerDomain(name) {
+		return d.peerDomain
+	}
+	return strings.ToLower(d.getResponseDomain(name))
+}
+
+// serviceMetricsLabel returns the label of the service in the DNS service
+// metrics. Only the first services up to the limit get their own label so
+// that a client querying many names can't create unbounded metrics.
+func (d *DNSServer) serviceMetricsLabel(service string, limit int) string {
+	d.serviceMetricsLock.Lock()
+	defer d.serviceMetricsLock.Unlock()
+
+	if _, ok := d.serviceMetricsLabels[service]; ok {
+		return service
+	}
+	if len(d.serviceMetricsLabels) >= limit {
+		return serviceMetricsOtherLabel
+	}
+	d.serviceMetricsLabels[service] = struct{}{}
+	return service
+}
+
 // handlePtr is used to handle "reverse" DNS queries
 func (d *DNSServer) handlePtr(resp dns.ResponseWriter, req *dns.Msg) {
 	q := req.Question[0]
@@ -537,18 +813,54 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 		m.SetRcode(req, dns.RcodeNotImplemented)
 
 	default:
+		// Answers depending on the client are not cached since the cache is
+		// only keyed by the question: the ones for a client subnet, and the
+		// prepared queries that can sort their results by the distance to the
+		// client.
+		cacheable := cfg.AnswerCacheTTL > 0 && ednsSubnetForRequest(req) == nil &&
+			!isPreparedQueryName(q.Name)
+		if cacheable {
+			if cached, ok := d.answerCache.get(q, cfg.AnswerCacheTTL); ok {
+				metrics.IncrCounter([]string{"dns", "answer_cache", "hit"}, 1)
+				cached.writeTo(req, m)
+				break
+			}
+		}
+
 		err = d.dispatch(resp.RemoteAddr(), req, m, maxRecursionLevelDefault)
 		rCode := rCodeFromError(err)
 		if rCode == dns.RcodeNameError || errors.Is(err, errNoData) {
 			d.addSOA(cfg, m, q.Name)
 		}
 		m.SetRcode(req, rCode)
+
+		if cacheable {
+			maxAge := cfg.AnswerCacheTTL + cfg.AnswerCacheStale
+			if rCode != dns.RcodeServerFailure {
+				d.answerCache.set(q, m, maxAge)
+			} else if stale, ok := d.answerCache.get(q, maxAge); ok {
+				d.logger.Warn("serving stale answer after failing to resolve query",
+					"name", q.Name,
+					"type", dns.Type(q.Qtype),
+					"error", err,
+				)
+				metrics.IncrCounter([]string{"dns", "answer_cache", "stale_served"}, 1)
+				stale.writeTo(req, m)
+				err = nil
+			}
+		}
 	}
 
 	setEDNS(req, m, !errors.Is(err, errECSNotGlobal))
 
 	d.trimDNSResponse(cfg, network, req, m)
 
+	metrics.IncrCounterWithLabels([]string{"dns", "query"}, 1, []metrics.Label{
+		{Name: "type", Value: dns.Type(q.Qtype).String()},
+		{Name: "domain", Value: d.metricsDomain(q.Name)},
+		{Name: "rcode", Value: dns.RcodeToString[m.Rcode]},
+	})
+
 	if err := resp.WriteMsg(m); err != nil {
 		d.logger.Warn("failed to respond", "error", err)
 	}
@@ -559,6 +871,9 @@ func (d *DNSServer) soa(cfg *dnsConfig, questionName string) *dns.SOA {
 	if d.altDomain != "" && strings.HasSuffix(questionName, "."+d.altDomain) {
 		domain = d.altDomain
 	}
+	if view := d.getView(questionName); view != nil {
+		domain = view.Domain
+	}
 
 	return &dns.SOA{
 		Hdr: dns.RR_Header{
@@ -722,6 +1037,18 @@ func (l queryLocality) effectiveDatacenter(defaultDC string) string {
 	return defaultDC
 }
 
+// isPreparedQueryName returns true if the name may be the one of a prepared
+// query lookup. It is conservative: any name with a "query" label matches,
+// such as the one of a service named query.
+func isPreparedQueryName(name string) bool {
+	for _, label := range dns.SplitDomainName(strings.ToLower(name)) {
+		if label == "query" {
+			return true
+		}
+	}
+	return false
+}
+
 // dispatch is used to parse a request and invoke the correct handler.
 // parameter maxRecursionLevel will handle whether recursive call can be performed
 func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursionLevel int) error {
@@ -730,12 +1057,24 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 
 	// Get the QName without the domain suffix
 	qName := strings.ToLower(dns.Fqdn(req.Question[0].Name))
-	qName = d.trimDomain(qName)
+	inPeerDomain := d.inPeerDomain(qName)
+	view := d.getView(qName)
+	switch {
+	case inPeerDomain:
+		qName = strings.TrimSuffix(qName, d.peerDomain)
+	case view != nil:
+		qName = strings.TrimSuffix(qName, view.Domain)
+	default:
+		qName = d.trimDomain(qName)
+	}
 
 	// Split into the label parts
 	labels := dns.SplitDomainName(qName)
 
 	cfg := d.config.Load().(*dnsConfig)
+	if view != nil {
+		cfg = cfg.withView(view)
+	}
 
 	var queryKind string
 	var queryParts []string
@@ -766,6 +1105,21 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 		return errNameNotFound
 	}
 
+	if inPeerDomain {
+		// In the peer domain the last label is always the peer name, so
+		// <service>.virtual.<peer>.<peer domain> is handled like
+		// <service>.virtual.<peer>.peer.<domain>.
+		switch queryKind {
+		case "service", "virtual", "node":
+		default:
+			return invalid()
+		}
+		if len(querySuffixes)%2 != 1 {
+			return invalid()
+		}
+		querySuffixes = append(querySuffixes, "peer")
+	}
+
 	switch queryKind {
 	case "service":
 		n := len(queryParts)
@@ -855,12 +1209,32 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 			return invalid()
 		}
 
+		service := queryParts[len(queryParts)-1]
+		if cfg.GetVirtualAnswerForService(service) == agentdns.VirtualAnswerInstances {
+			// Answer with the instances in the local datacenter or the peer,
+			// for clients that can't route to the virtual IP.
+			lookup := serviceLookup{
+				Datacenter:        d.agent.config.Datacenter,
+				PeerName:          locality.peer,
+				Service:           service,
+				MaxRecursionLevel: maxRecursionLevel,
+				EnterpriseMeta:    locality.EnterpriseMeta,
+			}
+			if lookup.PeerName == "" {
+				lookup.PeerName = locality.peerOrDatacenter
+			}
+			if lookup.PeerName != "" {
+				lookup.Datacenter = ""
+			}
+			return d.serviceLookup(cfg, lookup, req, resp)
+		}
+
 		args := structs.ServiceSpecificRequest{
 			// The datacenter of the request is not specified because cross-datacenter virtual IP
 			// queries are not supported. This guard rail is in place because virtual IPs are allocated
 			// within a DC, therefore their uniqueness is not guaranteed globally.
 			PeerName:       locality.peer,
-			ServiceName:    queryParts[len(queryParts)-1],
+			ServiceName:    service,
 			EnterpriseMeta: locality.EnterpriseMeta,
 			QueryOptions: structs.QueryOptions{
 				Token: d.agent.tokens.UserToken(),
@@ -876,12 +1250,16 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 			return err
 		}
 		if out != "" {
+			name := qName + respDomain
+			if inPeerDomain {
+				name = qName + d.peerDomain
+			}
 			resp.Answer = append(resp.Answer, &dns.A{
 				Hdr: dns.RR_Header{
-					Name:   qName + respDomain,
+					Name:   name,
 					Rrtype: dns.TypeA,
 					Class:  dns.ClassINET,
-					Ttl:    uint32(cfg.NodeTTL / time.Second),
+					Ttl:    uint32(cfg.VirtualTTL / time.Second),
 				},
 				A: net.ParseIP(out),
 			})
@@ -1049,6 +1427,11 @@ func (d *DNSServer) trimDomain(query string) string {
 	return strings.TrimSuffix(query, shorter)
 }
 
+// inPeerDomain returns true if the lowercased FQDN query is in the peer domain.
+func (d *DNSServer) inPeerDomain(query string) bool {
+	return d.peerDomain != "." && strings.HasSuffix(query, "."+d.peerDomain)
+}
+
 // rCodeFromError return the appropriate DNS response code for a given error
 func rCodeFromError(err error) int {
 	switch {
@@ -1191,21 +1574,28 @@ func indexRRs(rrs []dns.RR, index map[string]dns.RR) {
 // syncExtra takes a DNS response message and sets the extra data to the most
 // minimal set needed to cover the answer data. A pre-made index of RRs is given
 // so that can be re-used between calls. This assumes that the extra data is
-// only used to provide info for SRV records. If that's not the case, then this
-// will wipe out any additional data.
+// only used to provide info for SRV, SVCB and HTTPS records. If that's not the
+// case, then this will wipe out any additional data.
 func syncExtra(index map[string]dns.RR, resp *dns.Msg) {
 	extra := make([]dns.RR, 0, len(resp.Answer))
 	resolved := make(map[string]struct{}, len(resp.Answer))
 	for _, ansRR := range resp.Answer {
-		srv, ok := ansRR.(*dns.SRV)
-		if !ok {
+		var target string
+		switch rr := ansRR.(type) {
+		case *dns.SRV:
+			target = rr.Target
+		case *dns.SVCB:
+			target = rr.Target
+		case *dns.HTTPS:
+			target = rr.Target
+		default:
 			continue
 		}
 
 		// Note that we always use lower case when using the index so
 		// that compares are not case-sensitive. We don't alter the actual
 		// RRs we add into the extra section, however.
-		target := strings.ToLower(srv.Target)
+		target = strings.ToLower(target)
 
 	RESOLVE:
 		if _, ok := resolved[target]; ok {
@@ -1432,11 +1822,56 @@ func (d *DNSServer) lookupServiceNodes(cfg *dnsConfig, lookup serviceLookup) (st
 	nodes := make(structs.CheckServiceNodes, len(out.Nodes))
 	copy(nodes, out.Nodes)
 	out.Nodes = nodes.Filter(cfg.OnlyPassing)
+	if len(cfg.ViewTags) > 0 || len(cfg.ViewExcludeTags) > 0 {
+		out.Nodes = filterServiceNodesByTags(out.Nodes, cfg.ViewTags, cfg.ViewExcludeTags)
+	}
 	return out, nil
 }
 
+// filterServiceNodesByTags returns the nodes with a service that has all of
+// the tags and none of the excluded tags. Tags are compared case-insensitively.
+func filterServiceNodesByTags(nodes structs.CheckServiceNodes, tags, excludeTags []string) structs.CheckServiceNodes {
+	hasTag := func(node structs.CheckServiceNode, tag string) bool {
+		for _, t := range node.Service.Tags {
+			if strings.EqualFold(t, tag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	filtered := make(structs.CheckServiceNodes, 0, len(nodes))
+OUTER:
+	for _, node := range nodes {
+		if node.Service == nil {
+			continue
+		}
+		for _, tag := range tags {
+			if !hasTag(node, tag) {
+				continue OUTER
+			}
+		}
+		for _, tag := range excludeTags {
+			if hasTag(node, tag) {
+				continue OUTER
+			}
+		}
+		filtered = append(filtered, node)
+	}
+	return filtered
+}
+
 // serviceLookup is used to handle a service query
-func (d *DNSServer) serviceLookup(cfg *dnsConfig, lookup serviceLookup, req, resp *dns.Msg) error {
+func (d *DNSServer) serviceLookup(cfg *dnsConfig, lookup serviceLookup, req, resp *dns.Msg) (err error) {
+	if cfg.ServiceMetricsLimit > 0 {
+		defer func() {
+			metrics.IncrCounterWithLabels([]string{"dns", "service_query"}, 1, []metrics.Label{
+				{Name: "service", Value: d.serviceMetricsLabel(lookup.Service, cfg.ServiceMetricsLimit)},
+				{Name: "rcode", Value: dns.RcodeToString[rCodeFromError(err)]},
+			})
+		}()
+	}
+
 	out, err := d.lookupServiceNodes(cfg, lookup)
 	if err != nil {
 		return fmt.Errorf("rpc request failed: %w", err)
@@ -1450,21 +1885,72 @@ func (d *DNSServer) serviceLookup(cfg *dnsConfig, lookup serviceLookup, req, res
 	// Perform a random shuffle
 	out.Nodes.Shuffle()
 
+	// Answer with the instances local to the client subnet first, if known
+	ecsLocal := preferECSLocalNodes(req, out.Nodes)
+
 	// Determine the TTL
 	ttl, _ := cfg.GetTTLForService(lookup.Service)
 
 	// Add various responses depending on the request
 	qType := req.Question[0].Qtype
-	if qType == dns.TypeSRV {
+	if isServiceTargetQuery(qType) {
 		d.serviceSRVRecords(cfg, lookup, out.Nodes, req, resp, ttl, lookup.MaxRecursionLevel)
 	} else {
 		d.serviceNodeRecords(cfg, lookup, out.Nodes, req, resp, ttl, lookup.MaxRecursionLevel)
 	}
 
 	if len(resp.Answer) == 0 {
-		return errNoData
+		err = errNoData
 	}
-	return nil
+	if ecsLocal {
+		// The order of the answer depends on the client subnet
+		return ecsNotGlobalError{error: err}
+	}
+	return err
+}
+
+// preferECSLocalNodes moves the nodes whose subnets metadata contains the EDNS
+// client subnet of the request to the front of the list, keeping the relative
+// order of the nodes otherwise. It returns true if any node was moved.
+func preferECSLocalNodes(req *dns.Msg, nodes structs.CheckServiceNodes) bool {
+	subnet := ednsSubnetForRequest(req)
+	if subnet == nil || subnet.Address == nil {
+		return false
+	}
+
+	var local, remote structs.CheckServiceNodes
+	for _, node := range nodes {
+		if nodeHasSubnet(node.Node, subnet.Address) {
+			local = append(local, node)
+		} else {
+			remote = append(remote, node)
+		}
+	}
+	if len(local) == 0 {
+		return false
+	}
+
+	copy(nodes, local)
+	copy(nodes[len(local):], remote)
+	return true
+}
+
+// nodeHasSubnet returns true if the given IP is within one of the subnets
+// listed in the node metadata.
+func nodeHasSubnet(node *structs.Node, ip net.IP) bool {
+	if node == nil {
+		return false
+	}
+	for _, cidr := range strings.Split(node.Meta[structs.MetaDNSSubnets], ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 func ednsSubnetForRequest(req *dns.Msg) *dns.EDNS0_SUBNET {
@@ -1565,7 +2051,7 @@ func (d *DNSServer) preparedQueryLookup(cfg *dnsConfig, datacenter, query string
 	// This serviceLookup only needs the datacenter field populated,
 	// because peering is not supported with prepared queries.
 	lookup := serviceLookup{Datacenter: out.Datacenter}
-	if qType == dns.TypeSRV {
+	if isServiceTargetQuery(qType) {
 		d.serviceSRVRecords(cfg, lookup, out.Nodes, req, resp, ttl, maxRecursionLevel)
 	} else {
 		d.serviceNodeRecords(cfg, lookup, out.Nodes, req, resp, ttl, maxRecursionLevel)
@@ -1666,6 +2152,12 @@ func (d *DNSServer) serviceNodeRecords(cfg *dnsConfig, lookup serviceLookup, nod
 }
 
 func findWeight(node structs.CheckServiceNode) int {
+	// Draining instances still resolve, but are given no weight so that
+	// clients stop picking them for new connections.
+	if node.IsDraining() {
+		return 0
+	}
+
 	// By default, when only_passing is false, warning and passing nodes are returned
 	// Those values will be used if using a client with support while server has no
 	// support for weights
@@ -1731,7 +2223,7 @@ func makeARecord(qType uint16, ip net.IP, ttl time.Duration) dns.RR {
 	var ipRecord dns.RR
 	ipv4 := ip.To4()
 	if ipv4 != nil {
-		if qType == dns.TypeSRV || qType == dns.TypeA || qType == dns.TypeANY || qType == dns.TypeNS || qType == dns.TypeTXT {
+		if isServiceTargetQuery(qType) || qType == dns.TypeA || qType == dns.TypeANY || qType == dns.TypeNS || qType == dns.TypeTXT {
 			ipRecord = &dns.A{
 				Hdr: dns.RR_Header{
 					Rrtype: dns.TypeA,
@@ -1741,7 +2233,7 @@ func makeARecord(qType uint16, ip net.IP, ttl time.Duration) dns.RR {
 				A: ipv4,
 			}
 		}
-	} else if qType == dns.TypeSRV || qType == dns.TypeAAAA || qType == dns.TypeANY || qType == dns.TypeNS || qType == dns.TypeTXT {
+	} else if isServiceTargetQuery(qType) || qType == dns.TypeAAAA || qType == dns.TypeANY || qType == dns.TypeNS || qType == dns.TypeTXT {
 		ipRecord = &dns.AAAA{
 			Hdr: dns.RR_Header{
 				Rrtype: dns.TypeAAAA,
@@ -1809,22 +2301,11 @@ func (d *DNSServer) makeRecordFromServiceNode(lookup serviceLookup, serviceNode
 		return nil, nil
 	}
 
-	if q.Qtype == dns.TypeSRV {
+	if isServiceTargetQuery(q.Qtype) {
 		respDomain := d.getResponseDomain(q.Name)
 		nodeFQDN := nodeCanonicalDNSName(lookup, serviceNode.Node.Node, respDomain)
 		answers := []dns.RR{
-			&dns.SRV{
-				Hdr: dns.RR_Header{
-					Name:   q.Name,
-					Rrtype: dns.TypeSRV,
-					Class:  dns.ClassINET,
-					Ttl:    uint32(ttl / time.Second),
-				},
-				Priority: 1,
-				Weight:   uint16(findWeight(serviceNode)),
-				Port:     uint16(d.agent.TranslateServicePort(lookup.Datacenter, serviceNode.Service.Port, serviceNode.Service.TaggedAddresses)),
-				Target:   nodeFQDN,
-			},
+			d.makeServiceTargetRecord(lookup, serviceNode, q, ttl, nodeFQDN, addr),
 		}
 
 		ipRecord.Header().Name = nodeFQDN
@@ -1845,21 +2326,10 @@ func (d *DNSServer) makeRecordFromIP(lookup serviceLookup, addr net.IP, serviceN
 		return nil, nil
 	}
 
-	if q.Qtype == dns.TypeSRV {
+	if isServiceTargetQuery(q.Qtype) {
 		ipFQDN := d.encodeIPAsFqdn(q.Name, lookup, addr)
 		answers := []dns.RR{
-			&dns.SRV{
-				Hdr: dns.RR_Header{
-					Name:   q.Name,
-					Rrtype: dns.TypeSRV,
-					Class:  dns.ClassINET,
-					Ttl:    uint32(ttl / time.Second),
-				},
-				Priority: 1,
-				Weight:   uint16(findWeight(serviceNode)),
-				Port:     uint16(d.agent.TranslateServicePort(lookup.Datacenter, serviceNode.Service.Port, serviceNode.Service.TaggedAddresses)),
-				Target:   ipFQDN,
-			},
+			d.makeServiceTargetRecord(lookup, serviceNode, q, ttl, ipFQDN, addr),
 		}
 
 		ipRecord.Header().Name = ipFQDN
@@ -1895,20 +2365,9 @@ MORE_REC:
 		}
 	}
 
-	if q.Qtype == dns.TypeSRV {
+	if isServiceTargetQuery(q.Qtype) {
 		answers := []dns.RR{
-			&dns.SRV{
-				Hdr: dns.RR_Header{
-					Name:   q.Name,
-					Rrtype: dns.TypeSRV,
-					Class:  dns.ClassINET,
-					Ttl:    uint32(ttl / time.Second),
-				},
-				Priority: 1,
-				Weight:   uint16(findWeight(serviceNode)),
-				Port:     uint16(d.agent.TranslateServicePort(lookup.Datacenter, serviceNode.Service.Port, serviceNode.Service.TaggedAddresses)),
-				Target:   dns.Fqdn(fqdn),
-			},
+			d.makeServiceTargetRecord(lookup, serviceNode, q, ttl, dns.Fqdn(fqdn), nil),
 		}
 		return answers, additional
 	}
@@ -2000,7 +2459,76 @@ func (d *DNSServer) generateMeta(qName string, node *structs.Node, ttl time.Dura
 	return extra
 }
 
-// serviceARecords is used to add the SRV records for a service lookup
+// isServiceTargetQuery returns true if the query type is answered with
+// records pointing at the target and port of each service instance rather
+// than with the address records directly.
+func isServiceTargetQuery(qType uint16) bool {
+	return qType == dns.TypeSRV || qType == dns.TypeSVCB || qType == dns.TypeHTTPS
+}
+
+// makeServiceTargetRecord crafts the SRV, SVCB or HTTPS record pointing at
+// the given target for a service instance. For SVCB and HTTPS records the
+// port, the ALPN protocol IDs from the service metadata and the address of
+// the target, when known, are added as service parameters.
+func (d *DNSServer) makeServiceTargetRecord(lookup serviceLookup, serviceNode structs.CheckServiceNode, q dns.Question, ttl time.Duration, target string, addr net.IP) dns.RR {
+	hdr := dns.RR_Header{
+		Name:   q.Name,
+		Rrtype: q.Qtype,
+		Class:  dns.ClassINET,
+		Ttl:    uint32(ttl / time.Second),
+	}
+	port := uint16(d.agent.TranslateServicePort(lookup.Datacenter, serviceNode.Service.Port, serviceNode.Service.TaggedAddresses))
+
+	if q.Qtype == dns.TypeSRV {
+		return &dns.SRV{
+			Hdr:      hdr,
+			Priority: 1,
+			Weight:   uint16(findWeight(serviceNode)),
+			Port:     port,
+			Target:   target,
+		}
+	}
+
+	svcb := dns.SVCB{
+		Hdr:      hdr,
+		Priority: 1,
+		Target:   target,
+	}
+	// Instances without weight, such as draining ones, are still returned
+	// but with a lower priority so that clients prefer the other instances.
+	if findWeight(serviceNode) == 0 {
+		svcb.Priority = 2
+	}
+	if alpn := serviceNode.Service.Meta[structs.MetaDNSALPN]; alpn != "" {
+		var ids []string
+		for _, id := range strings.Split(alpn, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			svcb.Value = append(svcb.Value, &dns.SVCBAlpn{Alpn: ids})
+		}
+	}
+	if port != 0 {
+		svcb.Value = append(svcb.Value, &dns.SVCBPort{Port: port})
+	}
+	if addr != nil {
+		if ipv4 := addr.To4(); ipv4 != nil {
+			svcb.Value = append(svcb.Value, &dns.SVCBIPv4Hint{Hint: []net.IP{ipv4}})
+		} else {
+			svcb.Value = append(svcb.Value, &dns.SVCBIPv6Hint{Hint: []net.IP{addr}})
+		}
+	}
+
+	if q.Qtype == dns.TypeHTTPS {
+		return &dns.HTTPS{SVCB: svcb}
+	}
+	return &svcb
+}
+
+// serviceSRVRecords is used to add the SRV, SVCB or HTTPS records for a
+// service lookup
 func (d *DNSServer) serviceSRVRecords(cfg *dnsConfig, lookup serviceLookup, nodes structs.CheckServiceNodes, req, resp *dns.Msg, ttl time.Duration, maxRecursionLevel int) {
 	handled := make(map[string]struct{})
 
@@ -2113,7 +2641,7 @@ func (d *DNSServer) resolveCNAME(cfg *dnsConfig, name string, maxRecursionLevel
 	// Convert query to lowercase because DNS is case insensitive; d.domain and
 	// d.altDomain are already converted
 
-	if ln := strings.ToLower(name); strings.HasSuffix(ln, "."+d.domain) || strings.HasSuffix(ln, "."+d.altDomain) {
+	if ln := strings.ToLower(name); strings.HasSuffix(ln, "."+d.domain) || strings.HasSuffix(ln, "."+d.altDomain) || d.getView(ln) != nil {
 		if maxRecursionLevel < 1 {
 			d.logger.Error("Infinite recursion detected for name, won't perform any CNAME resolution.", "name", name)
 			return nil
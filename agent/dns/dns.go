@@ -19,6 +19,19 @@ const (
 	RecursorStrategyRandom     RecursorStrategy = "random"
 )
 
+// VirtualAnswer selects the addresses returned for <service>.virtual queries.
+type VirtualAnswer string
+
+const (
+	// VirtualAnswerVirtualIP answers with the virtual IP of the service,
+	// which is only routable by clients using transparent proxy.
+	VirtualAnswerVirtualIP VirtualAnswer = "virtual-ip"
+
+	// VirtualAnswerInstances answers with the addresses of the instances of
+	// the service, like <service>.service queries.
+	VirtualAnswerInstances VirtualAnswer = "instances"
+)
+
 func (s RecursorStrategy) Indexes(max int) []int {
 	switch s {
 	case RecursorStrategyRandom:
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// dnsAnswerCacheMaxEntries bounds the number of answers kept by the DNS
+// answer cache.
+const dnsAnswerCacheMaxEntries = 10000
+
+type dnsAnswerCacheKey struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+type dnsAnswerCacheEntry struct {
+	Rcode  int
+	Answer []dns.RR
+	Ns     []dns.RR
+	Extra  []dns.RR
+
+	stored time.Time
+}
+
+// dnsAnswerCache keeps recent answers to DNS queries so that they can be
+// served again without querying the servers, and served stale when querying
+// the servers fails.
+type dnsAnswerCache struct {
+	lock    sync.Mutex
+	entries map[dnsAnswerCacheKey]*dnsAnswerCacheEntry
+
+	// now is used to get the current time, and is replaced in tests.
+	now func() time.Time
+}
+
+func newDNSAnswerCache() *dnsAnswerCache {
+	return &dnsAnswerCache{
+		entries: make(map[dnsAnswerCacheKey]*dnsAnswerCacheEntry),
+		now:     time.Now,
+	}
+}
+
+func dnsAnswerCacheKeyFor(q dns.Question) dnsAnswerCacheKey {
+	return dnsAnswerCacheKey{
+		Name:  strings.ToLower(q.Name),
+		Type:  q.Qtype,
+		Class: q.Qclass,
+	}
+}
+
+// get returns the answer cached for the question if it was stored less than
+// maxAge ago.
+func (c *dnsAnswerCache) get(q dns.Question, maxAge time.Duration) (*dnsAnswerCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[dnsAnswerCacheKeyFor(q)]
+	if !ok || c.now().Sub(entry.stored) >= maxAge {
+		return nil, false
+	}
+	return entry, true
+}
+
+// set stores the answer of msg for the question. Entries older than maxAge
+// are purged when the cache is full, and the answer is dropped if that
+// doesn't make room for it.
+func (c *dnsAnswerCache) set(q dns.Question, msg *dns.Msg, maxAge time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	key := dnsAnswerCacheKeyFor(q)
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= dnsAnswerCacheMaxEntries {
+		for k, entry := range c.entries {
+			if now.Sub(entry.stored) >= maxAge {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= dnsAnswerCacheMaxEntries {
+			return
+		}
+	}
+
+	c.entries[key] = &dnsAnswerCacheEntry{
+		Rcode:  msg.Rcode,
+		Answer: copyRRs(msg.Answer),
+		Ns:     copyRRs(msg.Ns),
+		Extra:  copyRRs(msg.Extra),
+		stored: now,
+	}
+}
+
+// writeTo copies the cached answer into msg. The answer records are shuffled
+// again, like the lookups shuffle the instances of a service, so that the
+// clients don't all get the same order and, once the response is trimmed, the
+// same subset of the records.
+func (e *dnsAnswerCacheEntry) writeTo(req, msg *dns.Msg) {
+	msg.Answer = shuffleRRs(copyRRs(e.Answer))
+	msg.Ns = copyRRs(e.Ns)
+	msg.Extra = copyRRs(e.Extra)
+	msg.SetRcode(req, e.Rcode)
+}
+
+// shuffleRRs shuffles the records in place. A CNAME record is kept in front
+// of the records of its target that follow it.
+func shuffleRRs(rrs []dns.RR) []dns.RR {
+	var groups [][]dns.RR
+	target := ""
+	for _, rr := range rrs {
+		name := strings.ToLower(rr.Header().Name)
+		if len(groups) > 0 && target != "" && name == target {
+			groups[len(groups)-1] = append(groups[len(groups)-1], rr)
+		} else {
+			groups = append(groups, []dns.RR{rr})
+			target = ""
+		}
+		if cname, ok := rr.(*dns.CNAME); ok {
+			target = strings.ToLower(cname.Target)
+		}
+	}
+
+	rand.Shuffle(len(groups), func(i, j int) {
+		groups[i], groups[j] = groups[j], groups[i]
+	})
+	rrs = rrs[:0]
+	for _, group := range groups {
+		rrs = append(rrs, group...)
+	}
+	return rrs
+}
+
+func copyRRs(rrs []dns.RR) []dns.RR {
+	if rrs == nil {
+		return nil
+	}
+	out := make([]dns.RR, 0, len(rrs))
+	for _, rr := range rrs {
+		out = append(out, dns.Copy(rr))
+	}
+	return out
+}
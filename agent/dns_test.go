@@ -1,12 +1,17 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"strings"
@@ -23,6 +28,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/sdk/freeport"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
@@ -1651,6 +1657,564 @@ func TestDNS_ServiceLookup(t *testing.T) {
 	}
 }
 
+func TestDNS_ServiceLookup_SVCB(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register a node with a service advertising ALPN protocol IDs.
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			Service: "web",
+			Port:    8443,
+			Meta:    map[string]string{structs.MetaDNSALPN: "h2, http/1.1"},
+		},
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	for _, qType := range []uint16{dns.TypeSVCB, dns.TypeHTTPS} {
+		t.Run(dns.TypeToString[qType], func(t *testing.T) {
+			m := new(dns.Msg)
+			m.SetQuestion("web.service.consul.", qType)
+
+			c := new(dns.Client)
+			in, _, err := c.Exchange(m, a.DNSAddr())
+			require.NoError(t, err)
+			require.Len(t, in.Answer, 1)
+
+			var rec *dns.SVCB
+			switch rr := in.Answer[0].(type) {
+			case *dns.SVCB:
+				rec = rr
+			case *dns.HTTPS:
+				rec = &rr.SVCB
+			}
+			require.NotNil(t, rec, "unexpected answer %#v", in.Answer[0])
+			require.Equal(t, qType, rec.Hdr.Rrtype)
+			require.Equal(t, uint16(1), rec.Priority)
+			require.Equal(t, "foo.node.dc1.consul.", rec.Target)
+			require.Equal(t, []dns.SVCBKeyValue{
+				&dns.SVCBAlpn{Alpn: []string{"h2", "http/1.1"}},
+				&dns.SVCBPort{Port: 8443},
+				&dns.SVCBIPv4Hint{Hint: []net.IP{net.ParseIP("127.0.0.1").To4()}},
+			}, rec.Value)
+
+			require.Len(t, in.Extra, 1)
+			aRec, ok := in.Extra[0].(*dns.A)
+			require.True(t, ok, "unexpected extra %#v", in.Extra[0])
+			require.Equal(t, "foo.node.dc1.consul.", aRec.Hdr.Name)
+			require.Equal(t, "127.0.0.1", aRec.A.String())
+		})
+	}
+}
+
+func TestDNS_ServiceLookup_ECSLocalNodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register instances of the same service on nodes in different subnets.
+	nodes := map[string]string{
+		"foo": "10.1.0.0/16, 10.2.0.0/16",
+		"bar": "192.168.0.0/16",
+		"baz": "",
+	}
+	for node, subnets := range nodes {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			NodeMeta:   map[string]string{structs.MetaDNSSubnets: subnets},
+			Service: &structs.NodeService{
+				Service: "db",
+				Port:    12345,
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	cases := map[string]struct {
+		subnet        string
+		expectedFirst string
+		expectedScope uint8
+	}{
+		"local subnet": {
+			subnet:        "10.2.3.0/24",
+			expectedFirst: "foo.node.dc1.consul.",
+			expectedScope: 24,
+		},
+		"other local subnet": {
+			subnet:        "192.168.1.0/24",
+			expectedFirst: "bar.node.dc1.consul.",
+			expectedScope: 24,
+		},
+		"unknown subnet": {
+			subnet: "172.16.0.0/24",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ip, ipNet, err := net.ParseCIDR(tc.subnet)
+			require.NoError(t, err)
+			ones, _ := ipNet.Mask.Size()
+
+			// Answers are shuffled so query a few times to make sure the
+			// local instance always comes first.
+			for i := 0; i < 5; i++ {
+				m := new(dns.Msg)
+				m.SetQuestion("db.service.consul.", dns.TypeSRV)
+				m.SetEdns0(12345, true)
+				o := m.IsEdns0()
+				o.Option = append(o.Option, &dns.EDNS0_SUBNET{
+					Code:          dns.EDNS0SUBNET,
+					Family:        1,
+					SourceNetmask: uint8(ones),
+					Address:       ip.To4(),
+				})
+
+				c := new(dns.Client)
+				in, _, err := c.Exchange(m, a.DNSAddr())
+				require.NoError(t, err)
+				require.Len(t, in.Answer, 3)
+
+				if tc.expectedFirst != "" {
+					srv, ok := in.Answer[0].(*dns.SRV)
+					require.True(t, ok, "unexpected answer %#v", in.Answer[0])
+					require.Equal(t, tc.expectedFirst, srv.Target)
+				}
+
+				optRR := in.IsEdns0()
+				require.NotNil(t, optRR)
+				require.Len(t, optRR.Option, 1)
+				subnet, ok := optRR.Option[0].(*dns.EDNS0_SUBNET)
+				require.True(t, ok)
+				require.Equal(t, tc.expectedScope, subnet.SourceScope)
+			}
+		})
+	}
+}
+
+func TestDNS_Views(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			views = [
+				{ domain = "internal" tags = ["v1"] exclude_tags = ["canary"] },
+				{ domain = "svc.example.internal" only_passing = true },
+			]
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register instances of the same service with different tags and health.
+	instances := map[string]struct {
+		tags   []string
+		status string
+	}{
+		"foo": {tags: []string{"v1"}, status: api.HealthPassing},
+		"bar": {tags: []string{"v1", "canary"}, status: api.HealthPassing},
+		"baz": {tags: []string{"v2"}, status: api.HealthWarning},
+	}
+	for node, inst := range instances {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: "web",
+				Tags:    inst.tags,
+				Port:    8080,
+			},
+			Check: &structs.HealthCheck{
+				Name:      "web",
+				ServiceID: "web",
+				Status:    inst.status,
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	cases := map[string][]string{
+		"web.service.consul.":               {"bar.node.dc1.consul.", "baz.node.dc1.consul.", "foo.node.dc1.consul."},
+		"web.service.internal.":             {"foo.node.dc1.internal."},
+		"web.service.svc.example.internal.": {"bar.node.dc1.svc.example.internal.", "foo.node.dc1.svc.example.internal."},
+	}
+	for question, expected := range cases {
+		t.Run(question, func(t *testing.T) {
+			m := new(dns.Msg)
+			m.SetQuestion(question, dns.TypeSRV)
+
+			c := new(dns.Client)
+			in, _, err := c.Exchange(m, a.DNSAddr())
+			require.NoError(t, err)
+
+			var targets []string
+			for _, rr := range in.Answer {
+				srv, ok := rr.(*dns.SRV)
+				require.True(t, ok, "unexpected answer %#v", rr)
+				targets = append(targets, srv.Target)
+			}
+			sort.Strings(targets)
+			require.Equal(t, expected, targets)
+		})
+	}
+
+	// Node lookups are answered in the domain of the view.
+	m := new(dns.Msg)
+	m.SetQuestion("foo.node.svc.example.internal.", dns.TypeA)
+	c := new(dns.Client)
+	in, _, err := c.Exchange(m, a.DNSAddr())
+	require.NoError(t, err)
+	require.Len(t, in.Answer, 1)
+	require.Equal(t, "foo.node.svc.example.internal.", in.Answer[0].Header().Name)
+}
+
+func TestDNS_AnswerCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			answer_cache_ttl = "1h"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	lookup := func() *dns.Msg {
+		m := new(dns.Msg)
+		m.SetQuestion("foo.node.consul.", dns.TypeA)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	in := lookup()
+	require.Len(t, in.Answer, 1)
+
+	// The node is still answered from the cache after it is deregistered.
+	dereg := &structs.DeregisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+	}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Deregister", dereg, &out))
+
+	in = lookup()
+	require.Len(t, in.Answer, 1)
+	aRec, ok := in.Answer[0].(*dns.A)
+	require.True(t, ok, "unexpected answer %#v", in.Answer[0])
+	require.Equal(t, "127.0.0.1", aRec.A.String())
+}
+
+func TestDNS_AnswerCache_ShuffledAndPreparedQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			answer_cache_ttl = "1h"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	for i := 0; i < 10; i++ {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("foo%d", i),
+			Address:    fmt.Sprintf("127.0.0.%d", i+1),
+			Service: &structs.NodeService{
+				Service: "web",
+				Port:    8080,
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	var id string
+	query := &structs.PreparedQueryRequest{
+		Datacenter: "dc1",
+		Op:         structs.PreparedQueryCreate,
+		Query: &structs.PreparedQuery{
+			Name:    "web-near",
+			Service: structs.ServiceQuery{Service: "web", Near: "_ip"},
+		},
+	}
+	require.NoError(t, a.RPC(context.Background(), "PreparedQuery.Apply", query, &id))
+
+	lookup := func(name string) *dns.Msg {
+		m := new(dns.Msg)
+		m.SetQuestion(name, dns.TypeA)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	// The cached answers are shuffled for each query, like the resolved ones,
+	// so the UDP answers don't all keep the same instances.
+	seen := make(map[string]struct{})
+	for i := 0; i < 20; i++ {
+		in := lookup("web.service.consul.")
+		require.Len(t, in.Answer, 3)
+		for _, rr := range in.Answer {
+			seen[rr.(*dns.A).A.String()] = struct{}{}
+		}
+	}
+	require.Greater(t, len(seen), 3)
+
+	// The prepared queries are resolved for each client.
+	require.Len(t, lookup("web-near.query.consul.").Answer, 3)
+	for i := 0; i < 10; i++ {
+		dereg := &structs.DeregisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("foo%d", i),
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Deregister", dereg, &out))
+	}
+	require.Empty(t, lookup("web-near.query.consul.").Answer)
+	require.Len(t, lookup("web.service.consul.").Answer, 3)
+}
+
+func TestDNSAnswerCache(t *testing.T) {
+	now := time.Now()
+	cache := newDNSAnswerCache()
+	cache.now = func() time.Time { return now }
+
+	q := dns.Question{Name: "Foo.Node.Consul.", Qtype: dns.TypeA, Qclass: dns.ClassINET}
+	msg := new(dns.Msg)
+	msg.SetQuestion(q.Name, q.Qtype)
+	msg.Answer = []dns.RR{&dns.A{
+		Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0},
+		A:   net.ParseIP("127.0.0.1"),
+	}}
+	cache.set(q, msg, time.Minute)
+
+	// Questions are matched case-insensitively.
+	lower := q
+	lower.Name = "foo.node.consul."
+	entry, ok := cache.get(lower, 10*time.Second)
+	require.True(t, ok)
+	require.Len(t, entry.Answer, 1)
+
+	// The answer is only returned while it is younger than the max age.
+	now = now.Add(30 * time.Second)
+	_, ok = cache.get(q, 10*time.Second)
+	require.False(t, ok)
+	_, ok = cache.get(q, time.Minute)
+	require.True(t, ok)
+
+	// The cached answer isn't shared with the responses it is written to.
+	resp := new(dns.Msg)
+	resp.SetReply(msg)
+	entry.writeTo(msg, resp)
+	resp.Answer[0].Header().Ttl = 30
+	require.Equal(t, uint32(0), entry.Answer[0].Header().Ttl)
+}
+
+func TestDNSAnswerCache_shuffleRRs(t *testing.T) {
+	a := func(name, ip string) dns.RR {
+		return &dns.A{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA}, A: net.ParseIP(ip)}
+	}
+	cname := &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: "web.service.consul.", Rrtype: dns.TypeCNAME},
+		Target: "web.example.com.",
+	}
+	rrs := []dns.RR{
+		a("web.service.consul.", "10.0.0.1"),
+		cname,
+		a("web.example.com.", "10.0.0.2"),
+		a("web.example.com.", "10.0.0.3"),
+		a("web.service.consul.", "10.0.0.4"),
+	}
+
+	for i := 0; i < 20; i++ {
+		shuffled := shuffleRRs(append([]dns.RR(nil), rrs...))
+		require.ElementsMatch(t, rrs, shuffled)
+
+		// The CNAME record is still followed by the records of its target.
+		for j, rr := range shuffled {
+			if rr == cname {
+				require.Equal(t, rrs[2:4], shuffled[j+1:j+3])
+			}
+		}
+	}
+}
+
+func TestDNS_ServiceMetricsLabel(t *testing.T) {
+	d := &DNSServer{serviceMetricsLabels: make(map[string]struct{})}
+
+	require.Equal(t, "web", d.serviceMetricsLabel("web", 2))
+	require.Equal(t, "db", d.serviceMetricsLabel("db", 2))
+
+	// Services past the limit share a label, while the first ones keep theirs.
+	require.Equal(t, serviceMetricsOtherLabel, d.serviceMetricsLabel("cache", 2))
+	require.Equal(t, "web", d.serviceMetricsLabel("web", 2))
+}
+
+func TestDNS_MetricsDomain(t *testing.T) {
+	d := &DNSServer{
+		domain:     "consul.",
+		altDomain:  "test-domain.",
+		peerDomain: "mesh.",
+		views:      []config.RuntimeDNSView{{Domain: "svc.example.internal."}},
+	}
+
+	cases := map[string]string{
+		"web.service.consul.":               "consul.",
+		"Web.Service.Test-Domain.":          "test-domain.",
+		"web.virtual.frontend.mesh.":        "mesh.",
+		"web.service.svc.example.internal.": "svc.example.internal.",
+	}
+	for question, expected := range cases {
+		require.Equal(t, expected, d.metricsDomain(question), question)
+	}
+}
+
+func TestDNS_OverTLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	port := freeport.GetOne(t)
+	a := NewTestAgent(t, fmt.Sprintf(`
+		ports {
+			dns_tls = %d
+		}
+		tls {
+			https {
+				cert_file = "../test/key/ourdomain.cer"
+				key_file = "../test/key/ourdomain.key"
+			}
+		}
+	`, port))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	m := new(dns.Msg)
+	m.SetQuestion(a.config.NodeName+".node.consul.", dns.TypeA)
+
+	c := &dns.Client{
+		Net:       "tcp-tls",
+		TLSConfig: &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"dot"}},
+	}
+	in, _, err := c.Exchange(m, fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	require.Len(t, in.Answer, 1)
+
+	aRec, ok := in.Answer[0].(*dns.A)
+	require.True(t, ok, "unexpected answer %#v", in.Answer[0])
+	require.Equal(t, "127.0.0.1", aRec.A.String())
+}
+
+func TestDNS_OverHTTPS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	srv, err := NewDNSServer(a.Agent)
+	require.NoError(t, err)
+
+	m := new(dns.Msg)
+	m.SetQuestion(a.config.NodeName+".node.consul.", dns.TypeA)
+	raw, err := m.Pack()
+	require.NoError(t, err)
+
+	requireAnswer := func(t *testing.T, resp *httptest.ResponseRecorder) {
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Equal(t, "application/dns-message", resp.Header().Get("Content-Type"))
+
+		in := new(dns.Msg)
+		require.NoError(t, in.Unpack(resp.Body.Bytes()))
+		require.Equal(t, m.Id, in.Id)
+		require.Len(t, in.Answer, 1)
+
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "unexpected answer %#v", in.Answer[0])
+		require.Equal(t, "127.0.0.1", aRec.A.String())
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dns-query?dns="+base64.RawURLEncoding.EncodeToString(raw), nil)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		requireAnswer(t, resp)
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/dns-query", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/dns-message")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		requireAnswer(t, resp)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/dns-query", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/dns-query", bytes.NewReader(raw))
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dns-query?dns=AAAA", nil)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
 func TestDNS_ServiceLookupWithInternalServiceAddress(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1768,7 +2332,7 @@ func TestDNS_VirtualIPLookup(t *testing.T) {
 
 	t.Parallel()
 
-	a := StartTestAgent(t, TestAgent{HCL: ``, Overrides: `peering = { test_allow_peer_registrations = true }`})
+	a := StartTestAgent(t, TestAgent{HCL: `dns_config { peer_domain = "mesh" }`, Overrides: `peering = { test_allow_peer_registrations = true }`})
 	defer a.Shutdown()
 
 	testrpc.WaitForLeader(t, a.RPC, "dc1")
@@ -1846,6 +2410,26 @@ func TestDNS_VirtualIPLookup(t *testing.T) {
 			question: "db.virtual.frontend.consul.",
 			expect:   "240.0.0.2",
 		},
+		{
+			name: "query for imported service in peer domain",
+			reg: &structs.RegisterRequest{
+				PeerName:   "frontend",
+				Datacenter: "dc1",
+				Node:       "foo",
+				Address:    "127.0.0.55",
+				Service: &structs.NodeService{
+					PeerName: "frontend",
+					Kind:     structs.ServiceKindConnectProxy,
+					Service:  "web-proxy",
+					Port:     12345,
+					Proxy: structs.ConnectProxyConfig{
+						DestinationServiceName: "db",
+					},
+				},
+			},
+			question: "db.virtual.frontend.mesh.",
+			expect:   "240.0.0.2",
+		},
 	}
 
 	for _, tc := range tt {
@@ -1855,6 +2439,73 @@ func TestDNS_VirtualIPLookup(t *testing.T) {
 	}
 }
 
+func TestDNS_VirtualIPLookup_VirtualAnswers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			virtual_answers = {
+				"legacy-*" = "instances"
+			}
+			virtual_ttl = "7s"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	regs := []*structs.RegisterRequest{
+		{
+			Datacenter: "dc1",
+			Node:       "foo",
+			Address:    "127.0.0.55",
+			Service: &structs.NodeService{
+				Kind:    structs.ServiceKindConnectProxy,
+				Service: "web-proxy",
+				Port:    12345,
+				Proxy: structs.ConnectProxyConfig{
+					DestinationServiceName: "db",
+				},
+			},
+		},
+		{
+			Datacenter: "dc1",
+			Node:       "bar",
+			Address:    "127.0.0.56",
+			Service: &structs.NodeService{
+				Service: "legacy-db",
+				Port:    5432,
+			},
+		},
+	}
+	for _, reg := range regs {
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", reg, &out))
+	}
+
+	lookup := func(question string) *dns.A {
+		m := new(dns.Msg)
+		m.SetQuestion(question, dns.TypeA)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Len(t, in.Answer, 1)
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "unexpected answer %#v", in.Answer[0])
+		return aRec
+	}
+
+	// Services without a match are answered with their virtual IP.
+	aRec := lookup("db.virtual.consul.")
+	require.Equal(t, "240.0.0.1", aRec.A.String())
+	require.Equal(t, uint32(7), aRec.Hdr.Ttl)
+
+	aRec = lookup("legacy-db.virtual.consul.")
+	require.Equal(t, "127.0.0.56", aRec.A.String())
+}
+
 func TestDNS_IngressServiceLookup(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,229 @@
+package activehealthcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpProtocolOptionsName = "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
+
+	defaultIntervalMs         = 10000
+	defaultTimeoutMs          = 1000
+	defaultUnhealthyThreshold = 2
+	defaultHealthyThreshold   = 2
+)
+
+// activeHealthCheck makes the proxies of the downstreams of an upstream
+// service actively health check its instances, so that they stop sending
+// traffic to the failing instances before the Consul health checks catch up.
+//
+// The instances are checked with HTTP requests to Path if it is set, and
+// with TCP connections otherwise. The checks go through the mTLS connections
+// of the upstream cluster.
+type activeHealthCheck struct {
+	ProxyType string
+
+	// Path is the path of the HTTP health checks.
+	Path string
+
+	// ExpectedStatuses are the HTTP statuses of the healthy instances. They
+	// default to 200.
+	ExpectedStatuses []int
+
+	// IntervalMs is the interval between the checks, in milliseconds. It
+	// defaults to 10000.
+	IntervalMs *int
+
+	// TimeoutMs is the timeout of the checks, in milliseconds. It defaults
+	// to 1000.
+	TimeoutMs *int
+
+	// UnhealthyThreshold is the number of failed checks after which an
+	// instance is unhealthy. It defaults to 2.
+	UnhealthyThreshold *int
+
+	// HealthyThreshold is the number of successful checks after which an
+	// unhealthy instance is healthy again. It defaults to 2.
+	HealthyThreshold *int
+
+	// PanicThresholdPercent is the percentage of healthy instances under
+	// which Envoy ignores the health of the instances and balances the
+	// traffic across all of them. A value of 0 disables the panic mode.
+	PanicThresholdPercent *float64
+}
+
+var _ extensioncommon.BasicExtension = (*activeHealthCheck)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var a activeHealthCheck
+	if name := ext.Name; name != api.BuiltinActiveHealthCheckExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinActiveHealthCheckExtension, name)
+	}
+
+	if err := a.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &a,
+	}, nil
+}
+
+func (a *activeHealthCheck) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, a); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return a.validate()
+}
+
+func (a *activeHealthCheck) validate() error {
+	var resultErr error
+
+	if a.ProxyType != "connect-proxy" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", a.ProxyType))
+	}
+
+	if a.Path != "" && !strings.HasPrefix(a.Path, "/") {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("Path %q must begin with a '/'", a.Path))
+	}
+
+	if len(a.ExpectedStatuses) > 0 && a.Path == "" {
+		resultErr = multierror.Append(resultErr, errors.New("ExpectedStatuses requires Path"))
+	}
+	for i, status := range a.ExpectedStatuses {
+		if status < 100 || status > 599 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("ExpectedStatuses[%d]: invalid HTTP status %d", i, status))
+		}
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value *int
+	}{
+		{"IntervalMs", a.IntervalMs},
+		{"TimeoutMs", a.TimeoutMs},
+		{"UnhealthyThreshold", a.UnhealthyThreshold},
+		{"HealthyThreshold", a.HealthyThreshold},
+	} {
+		if setting.value != nil && *setting.value < 1 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("%s must be at least 1, got %d", setting.name, *setting.value))
+		}
+	}
+
+	if a.PanicThresholdPercent != nil && (*a.PanicThresholdPercent < 0 || *a.PanicThresholdPercent > 100) {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("PanicThresholdPercent must be between 0 and 100, got %v", *a.PanicThresholdPercent))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (a *activeHealthCheck) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == a.ProxyType
+}
+
+// PatchRoute does nothing.
+func (a *activeHealthCheck) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster configures the active health checks of the clusters of the
+// upstream service.
+func (a *activeHealthCheck) PatchCluster(config *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() {
+		return c, false, nil
+	}
+
+	healthCheck := &envoy_core_v3.HealthCheck{
+		Interval:           millisecondsToDuration(valueOrDefault(a.IntervalMs, defaultIntervalMs)),
+		Timeout:            millisecondsToDuration(valueOrDefault(a.TimeoutMs, defaultTimeoutMs)),
+		UnhealthyThreshold: &wrappers.UInt32Value{Value: uint32(valueOrDefault(a.UnhealthyThreshold, defaultUnhealthyThreshold))},
+		HealthyThreshold:   &wrappers.UInt32Value{Value: uint32(valueOrDefault(a.HealthyThreshold, defaultHealthyThreshold))},
+	}
+
+	if a.Path != "" {
+		httpHealthCheck := &envoy_core_v3.HealthCheck_HttpHealthCheck{
+			Path: a.Path,
+		}
+		for _, status := range a.ExpectedStatuses {
+			httpHealthCheck.ExpectedStatuses = append(httpHealthCheck.ExpectedStatuses, &envoy_type_v3.Int64Range{
+				Start: int64(status),
+				End:   int64(status) + 1,
+			})
+		}
+		isHTTP2, err := usesHTTP2(c)
+		if err != nil {
+			return c, false, err
+		}
+		if isHTTP2 {
+			httpHealthCheck.CodecClientType = envoy_type_v3.CodecClientType_HTTP2
+		}
+		healthCheck.HealthChecker = &envoy_core_v3.HealthCheck_HttpHealthCheck_{
+			HttpHealthCheck: httpHealthCheck,
+		}
+	} else {
+		healthCheck.HealthChecker = &envoy_core_v3.HealthCheck_TcpHealthCheck_{
+			TcpHealthCheck: &envoy_core_v3.HealthCheck_TcpHealthCheck{},
+		}
+	}
+
+	c.HealthChecks = []*envoy_core_v3.HealthCheck{healthCheck}
+
+	if a.PanicThresholdPercent != nil {
+		if c.CommonLbConfig == nil {
+			c.CommonLbConfig = &envoy_cluster_v3.Cluster_CommonLbConfig{}
+		}
+		c.CommonLbConfig.HealthyPanicThreshold = &envoy_type_v3.Percent{Value: *a.PanicThresholdPercent}
+	}
+
+	return c, true, nil
+}
+
+// PatchFilter does nothing.
+func (a *activeHealthCheck) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	return filter, false, nil
+}
+
+// usesHTTP2 returns whether the cluster sends its requests with HTTP/2, in
+// which case the HTTP health checks must use it too.
+func usesHTTP2(c *envoy_cluster_v3.Cluster) (bool, error) {
+	typedConfig, ok := c.TypedExtensionProtocolOptions[httpProtocolOptionsName]
+	if !ok {
+		return false, nil
+	}
+
+	options := &envoy_upstreams_v3.HttpProtocolOptions{}
+	if err := typedConfig.UnmarshalTo(options); err != nil {
+		return false, fmt.Errorf("error unmarshalling http protocol options: %w", err)
+	}
+	return options.GetExplicitHttpConfig().GetHttp2ProtocolOptions() != nil, nil
+}
+
+func valueOrDefault(value *int, defaultValue int) int {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func millisecondsToDuration(ms int) *durationpb.Duration {
+	return durationpb.New(time.Duration(ms) * time.Millisecond)
+}
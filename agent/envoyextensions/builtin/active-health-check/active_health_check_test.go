@@ -0,0 +1,211 @@
+package activehealthcheck
+
+import (
+	"testing"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       activeHealthCheck
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"invalid path": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "Path": "healthz"},
+			expectedErrMsg: `Path "healthz" must begin with a '/'`,
+			ok:             false,
+		},
+		"expected statuses without path": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "ExpectedStatuses": []int{200}},
+			expectedErrMsg: "ExpectedStatuses requires Path",
+			ok:             false,
+		},
+		"invalid expected status": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "Path": "/healthz", "ExpectedStatuses": []int{200, 42}},
+			expectedErrMsg: "ExpectedStatuses[1]: invalid HTTP status 42",
+			ok:             false,
+		},
+		"invalid interval": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "IntervalMs": 0},
+			expectedErrMsg: "IntervalMs must be at least 1, got 0",
+			ok:             false,
+		},
+		"invalid panic threshold": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "PanicThresholdPercent": 101},
+			expectedErrMsg: "PanicThresholdPercent must be between 0 and 100, got 101",
+			ok:             false,
+		},
+		"tcp": {
+			arguments: map[string]interface{}{"ProxyType": "connect-proxy"},
+			expected:  activeHealthCheck{ProxyType: "connect-proxy"},
+			ok:        true,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{
+				"ProxyType":             "connect-proxy",
+				"Path":                  "/healthz",
+				"ExpectedStatuses":      []int{200, 204},
+				"IntervalMs":            5000,
+				"TimeoutMs":             500,
+				"UnhealthyThreshold":    3,
+				"HealthyThreshold":      1,
+				"PanicThresholdPercent": 25.5,
+			},
+			expected: activeHealthCheck{
+				ProxyType:             "connect-proxy",
+				Path:                  "/healthz",
+				ExpectedStatuses:      []int{200, 204},
+				IntervalMs:            intPointer(5000),
+				TimeoutMs:             intPointer(500),
+				UnhealthyThreshold:    intPointer(3),
+				HealthyThreshold:      intPointer(1),
+				PanicThresholdPercent: floatPointer(25.5),
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinActiveHealthCheckExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchCluster(t *testing.T) {
+	svc := api.CompoundServiceName{Name: "api"}
+	upstream := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}
+
+	t.Run("tcp", func(t *testing.T) {
+		a := &activeHealthCheck{ProxyType: "connect-proxy"}
+
+		patched, ok, err := a.PatchCluster(upstream, &envoy_cluster_v3.Cluster{Name: "api.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, patched.HealthChecks, 1)
+		healthCheck := patched.HealthChecks[0]
+		require.NotNil(t, healthCheck.GetTcpHealthCheck())
+		require.Equal(t, 10*time.Second, healthCheck.Interval.AsDuration())
+		require.Equal(t, time.Second, healthCheck.Timeout.AsDuration())
+		require.Equal(t, uint32(2), healthCheck.UnhealthyThreshold.GetValue())
+		require.Equal(t, uint32(2), healthCheck.HealthyThreshold.GetValue())
+		require.Nil(t, patched.CommonLbConfig)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		a := &activeHealthCheck{
+			ProxyType:             "connect-proxy",
+			Path:                  "/healthz",
+			ExpectedStatuses:      []int{200, 204},
+			IntervalMs:            intPointer(5000),
+			TimeoutMs:             intPointer(500),
+			UnhealthyThreshold:    intPointer(3),
+			HealthyThreshold:      intPointer(1),
+			PanicThresholdPercent: floatPointer(0),
+		}
+
+		typedConfig, err := anypb.New(&envoy_upstreams_v3.HttpProtocolOptions{
+			UpstreamProtocolOptions: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+				ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+					ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+						Http2ProtocolOptions: &envoy_core_v3.Http2ProtocolOptions{},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		patched, ok, err := a.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+			Name: "api.default.dc1.internal.domain",
+			TypedExtensionProtocolOptions: map[string]*anypb.Any{
+				httpProtocolOptionsName: typedConfig,
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, patched.HealthChecks, 1)
+		healthCheck := patched.HealthChecks[0]
+		require.Equal(t, 5*time.Second, healthCheck.Interval.AsDuration())
+		require.Equal(t, 500*time.Millisecond, healthCheck.Timeout.AsDuration())
+		require.Equal(t, uint32(3), healthCheck.UnhealthyThreshold.GetValue())
+		require.Equal(t, uint32(1), healthCheck.HealthyThreshold.GetValue())
+
+		httpHealthCheck := healthCheck.GetHttpHealthCheck()
+		require.NotNil(t, httpHealthCheck)
+		require.Equal(t, "/healthz", httpHealthCheck.Path)
+		require.Equal(t, envoy_type_v3.CodecClientType_HTTP2, httpHealthCheck.CodecClientType)
+		require.Equal(t, []*envoy_type_v3.Int64Range{
+			{Start: 200, End: 201},
+			{Start: 204, End: 205},
+		}, httpHealthCheck.ExpectedStatuses)
+
+		require.NotNil(t, patched.CommonLbConfig.HealthyPanicThreshold)
+		require.Equal(t, float64(0), patched.CommonLbConfig.HealthyPanicThreshold.Value)
+	})
+
+	t.Run("local app", func(t *testing.T) {
+		a := &activeHealthCheck{ProxyType: "connect-proxy"}
+
+		_, ok, err := a.PatchCluster(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+		}, &envoy_cluster_v3.Cluster{Name: "local_app"})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
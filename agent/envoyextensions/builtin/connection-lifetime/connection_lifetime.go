@@ -0,0 +1,225 @@
+package connectionlifetime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	tcpProxyFilterName              = "envoy.filters.network.tcp_proxy"
+	httpProtocolOptionsName         = "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
+)
+
+// connectionLifetime limits the lifetime of the connections of a service, so
+// that long-lived connections are periodically closed and rebalanced across
+// its instances.
+//
+// Configured for the local service, it applies to the connections accepted
+// by the inbound listener. Configured for an upstream service, it applies to
+// the connections to the service on the proxies of its downstreams.
+type connectionLifetime struct {
+	ProxyType string
+
+	// MaxConnectionDurationMs is the maximum lifetime of the connections, in
+	// milliseconds. The HTTP connections are drained, and the TCP ones are
+	// closed, once it is reached.
+	MaxConnectionDurationMs *int
+
+	// MaxRequestsPerConnection is the maximum number of requests sent over
+	// an HTTP connection before it is drained.
+	MaxRequestsPerConnection *int
+
+	// DrainTimeoutMs is how long the inbound listener waits for the clients
+	// to close the HTTP connections being drained, in milliseconds. It
+	// doesn't apply to upstream services.
+	DrainTimeoutMs *int
+}
+
+var _ extensioncommon.BasicExtension = (*connectionLifetime)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var c connectionLifetime
+	if name := ext.Name; name != api.BuiltinConnectionLifetimeExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinConnectionLifetimeExtension, name)
+	}
+
+	if err := c.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &c,
+	}, nil
+}
+
+func (c *connectionLifetime) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, c); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return c.validate()
+}
+
+func (c *connectionLifetime) validate() error {
+	var resultErr error
+
+	if c.ProxyType != "connect-proxy" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", c.ProxyType))
+	}
+
+	if c.MaxConnectionDurationMs == nil && c.MaxRequestsPerConnection == nil && c.DrainTimeoutMs == nil {
+		resultErr = multierror.Append(resultErr, errors.New("one of MaxConnectionDurationMs, MaxRequestsPerConnection or DrainTimeoutMs is required"))
+	}
+
+	// NOTE: Envoy disables the limits set to 0.
+	if c.MaxConnectionDurationMs != nil && *c.MaxConnectionDurationMs < 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("MaxConnectionDurationMs cannot be negative, got %d", *c.MaxConnectionDurationMs))
+	}
+	if c.MaxRequestsPerConnection != nil && *c.MaxRequestsPerConnection < 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("MaxRequestsPerConnection cannot be negative, got %d", *c.MaxRequestsPerConnection))
+	}
+	if c.DrainTimeoutMs != nil && *c.DrainTimeoutMs < 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("DrainTimeoutMs cannot be negative, got %d", *c.DrainTimeoutMs))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (c *connectionLifetime) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == c.ProxyType
+}
+
+// PatchRoute does nothing.
+func (c *connectionLifetime) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster limits the lifetime of the connections of the cluster of the
+// upstream service.
+func (c *connectionLifetime) PatchCluster(config *extensioncommon.RuntimeConfig, cluster *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() {
+		return cluster, false, nil
+	}
+	if c.MaxConnectionDurationMs == nil && c.MaxRequestsPerConnection == nil {
+		return cluster, false, nil
+	}
+
+	options := &envoy_upstreams_v3.HttpProtocolOptions{}
+	if typedConfig, ok := cluster.TypedExtensionProtocolOptions[httpProtocolOptionsName]; ok {
+		if err := typedConfig.UnmarshalTo(options); err != nil {
+			return cluster, false, fmt.Errorf("error unmarshalling http protocol options: %w", err)
+		}
+	}
+	if options.UpstreamProtocolOptions == nil {
+		// Envoy requires a protocol, and uses HTTP/1.1 by default.
+		options.UpstreamProtocolOptions = &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
+					HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{},
+				},
+			},
+		}
+	}
+	if options.CommonHttpProtocolOptions == nil {
+		options.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{}
+	}
+	c.patchCommonHTTPProtocolOptions(options.CommonHttpProtocolOptions)
+
+	typedConfig, err := anypb.New(options)
+	if err != nil {
+		return cluster, false, err
+	}
+	if cluster.TypedExtensionProtocolOptions == nil {
+		cluster.TypedExtensionProtocolOptions = make(map[string]*anypb.Any)
+	}
+	cluster.TypedExtensionProtocolOptions[httpProtocolOptionsName] = typedConfig
+
+	return cluster, true, nil
+}
+
+// PatchFilter limits the lifetime of the connections accepted by the
+// envoy.filters.network.http_connection_manager and
+// envoy.filters.network.tcp_proxy filters of the inbound listener.
+func (c *connectionLifetime) PatchFilter(config *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if config.IsUpstream() {
+		return filter, false, nil
+	}
+
+	switch filter.Name {
+	case httpConnectionManagerFilterName:
+		if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+			return filter, false, errors.New("error getting typed config for http filter")
+		}
+
+		hcm := envoy_resource_v3.GetHTTPConnectionManager(filter)
+		if hcm == nil {
+			return filter, false, errors.New("error unmarshalling filter")
+		}
+
+		if c.MaxConnectionDurationMs != nil || c.MaxRequestsPerConnection != nil {
+			if hcm.CommonHttpProtocolOptions == nil {
+				hcm.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{}
+			}
+			c.patchCommonHTTPProtocolOptions(hcm.CommonHttpProtocolOptions)
+		}
+		if c.DrainTimeoutMs != nil {
+			hcm.DrainTimeout = millisecondsToDuration(*c.DrainTimeoutMs)
+		}
+
+		newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, hcm)
+		if err != nil {
+			return filter, false, errors.New("error making new filter")
+		}
+		return newFilter, true, nil
+
+	case tcpProxyFilterName:
+		if c.MaxConnectionDurationMs == nil {
+			return filter, false, nil
+		}
+
+		tcpProxy := extensioncommon.GetTCPProxy(filter)
+		if tcpProxy == nil {
+			return filter, false, errors.New("error unmarshalling filter")
+		}
+		tcpProxy.MaxDownstreamConnectionDuration = millisecondsToDuration(*c.MaxConnectionDurationMs)
+
+		newFilter, err := extensioncommon.MakeFilter(tcpProxyFilterName, tcpProxy)
+		if err != nil {
+			return filter, false, errors.New("error making new filter")
+		}
+		return newFilter, true, nil
+	}
+
+	return filter, false, nil
+}
+
+func (c *connectionLifetime) patchCommonHTTPProtocolOptions(options *envoy_core_v3.HttpProtocolOptions) {
+	if c.MaxConnectionDurationMs != nil {
+		options.MaxConnectionDuration = millisecondsToDuration(*c.MaxConnectionDurationMs)
+	}
+	if c.MaxRequestsPerConnection != nil {
+		options.MaxRequestsPerConnection = &wrappers.UInt32Value{Value: uint32(*c.MaxRequestsPerConnection)}
+	}
+}
+
+func millisecondsToDuration(ms int) *durationpb.Duration {
+	return durationpb.New(time.Duration(ms) * time.Millisecond)
+}
@@ -0,0 +1,240 @@
+package connectionlifetime
+
+import (
+	"testing"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       connectionLifetime
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy", "DrainTimeoutMs": 5000},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"without limits": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy"},
+			expectedErrMsg: "one of MaxConnectionDurationMs, MaxRequestsPerConnection or DrainTimeoutMs is required",
+			ok:             false,
+		},
+		"negative limits": {
+			arguments: map[string]interface{}{
+				"ProxyType":                "connect-proxy",
+				"MaxConnectionDurationMs":  -1,
+				"MaxRequestsPerConnection": -2,
+				"DrainTimeoutMs":           -3,
+			},
+			expectedErrMsg: "MaxRequestsPerConnection cannot be negative, got -2",
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{
+				"ProxyType":                "connect-proxy",
+				"MaxConnectionDurationMs":  600000,
+				"MaxRequestsPerConnection": 1000,
+				"DrainTimeoutMs":           5000,
+			},
+			expected: connectionLifetime{
+				ProxyType:                "connect-proxy",
+				MaxConnectionDurationMs:  intPointer(600000),
+				MaxRequestsPerConnection: intPointer(1000),
+				DrainTimeoutMs:           intPointer(5000),
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinConnectionLifetimeExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	c := &connectionLifetime{
+		ProxyType:                "connect-proxy",
+		MaxConnectionDurationMs:  intPointer(600000),
+		MaxRequestsPerConnection: intPointer(1000),
+		DrainTimeoutMs:           intPointer(5000),
+	}
+	svc := api.CompoundServiceName{Name: "svc"}
+	inbound := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}
+
+	t.Run("http", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+			StatPrefix: "public_listener",
+		})
+		require.NoError(t, err)
+
+		patched, ok, err := c.PatchFilter(inbound, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		hcm := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.NotNil(t, hcm)
+		require.Equal(t, 10*time.Minute, hcm.CommonHttpProtocolOptions.MaxConnectionDuration.AsDuration())
+		require.Equal(t, uint32(1000), hcm.CommonHttpProtocolOptions.MaxRequestsPerConnection.GetValue())
+		require.Equal(t, 5*time.Second, hcm.DrainTimeout.AsDuration())
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(tcpProxyFilterName, &envoy_tcp_proxy_v3.TcpProxy{
+			StatPrefix:       "public_listener",
+			ClusterSpecifier: &envoy_tcp_proxy_v3.TcpProxy_Cluster{Cluster: "local_app"},
+		})
+		require.NoError(t, err)
+
+		patched, ok, err := c.PatchFilter(inbound, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		tcpProxy := extensioncommon.GetTCPProxy(patched)
+		require.NotNil(t, tcpProxy)
+		require.Equal(t, 10*time.Minute, tcpProxy.MaxDownstreamConnectionDuration.AsDuration())
+	})
+
+	t.Run("upstream", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{})
+		require.NoError(t, err)
+
+		_, ok, err := c.PatchFilter(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+			Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+				svc: {},
+			},
+		}, filter)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestPatchCluster(t *testing.T) {
+	c := &connectionLifetime{
+		ProxyType:                "connect-proxy",
+		MaxConnectionDurationMs:  intPointer(600000),
+		MaxRequestsPerConnection: intPointer(1000),
+	}
+	svc := api.CompoundServiceName{Name: "svc"}
+	upstream := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}
+
+	t.Run("local app", func(t *testing.T) {
+		_, ok, err := c.PatchCluster(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+		}, &envoy_cluster_v3.Cluster{Name: "local_app"})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("http1 upstream", func(t *testing.T) {
+		patched, ok, err := c.PatchCluster(upstream, &envoy_cluster_v3.Cluster{Name: "svc.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		options := protocolOptions(t, patched)
+		require.NotNil(t, options.GetExplicitHttpConfig().GetHttpProtocolOptions())
+		requireLimits(t, options.CommonHttpProtocolOptions)
+	})
+
+	t.Run("http2 upstream", func(t *testing.T) {
+		typedConfig, err := anypb.New(&envoy_upstreams_v3.HttpProtocolOptions{
+			UpstreamProtocolOptions: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+				ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+					ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+						Http2ProtocolOptions: &envoy_core_v3.Http2ProtocolOptions{},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		patched, ok, err := c.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+			Name: "svc.default.dc1.internal.domain",
+			TypedExtensionProtocolOptions: map[string]*anypb.Any{
+				httpProtocolOptionsName: typedConfig,
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		options := protocolOptions(t, patched)
+		require.NotNil(t, options.GetExplicitHttpConfig().GetHttp2ProtocolOptions())
+		requireLimits(t, options.CommonHttpProtocolOptions)
+	})
+
+	t.Run("only a drain timeout", func(t *testing.T) {
+		c := &connectionLifetime{ProxyType: "connect-proxy", DrainTimeoutMs: intPointer(5000)}
+		_, ok, err := c.PatchCluster(upstream, &envoy_cluster_v3.Cluster{Name: "svc.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func protocolOptions(t *testing.T, c *envoy_cluster_v3.Cluster) *envoy_upstreams_v3.HttpProtocolOptions {
+	t.Helper()
+
+	options := &envoy_upstreams_v3.HttpProtocolOptions{}
+	require.NoError(t, c.TypedExtensionProtocolOptions[httpProtocolOptionsName].UnmarshalTo(options))
+	return options
+}
+
+func requireLimits(t *testing.T, options *envoy_core_v3.HttpProtocolOptions) {
+	t.Helper()
+
+	require.Equal(t, 10*time.Minute, options.MaxConnectionDuration.AsDuration())
+	require.Equal(t, uint32(1000), options.MaxRequestsPerConnection.GetValue())
+}
+
+func intPointer(i int) *int {
+	return &i
+}
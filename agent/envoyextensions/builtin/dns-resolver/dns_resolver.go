@@ -0,0 +1,207 @@
+package dnsresolver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_cares_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/network/dns_resolver/cares/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	caresDNSResolverName = "envoy.network.dns_resolver.cares"
+	defaultDNSPort       = 53
+)
+
+// dnsResolver configures how the clusters of an upstream service that are
+// resolved with DNS, such as the clusters of the external services of
+// terminating gateways, look up their hostnames.
+//
+// It only applies to the LOGICAL_DNS and STRICT_DNS clusters of the upstream
+// service.
+type dnsResolver struct {
+	ProxyType string
+
+	// Resolvers are the addresses of the DNS servers, in the ip or ip:port
+	// format. The port defaults to 53. The system resolvers are used if it
+	// is empty.
+	Resolvers []string
+
+	// UseTCPForDNSLookups makes the lookups use TCP instead of UDP.
+	UseTCPForDNSLookups bool
+
+	// DNSRefreshRateMs is the interval between lookups, in milliseconds.
+	DNSRefreshRateMs *int
+
+	// RespectDNSTTL makes the interval between lookups the TTL of the DNS
+	// records instead of DNSRefreshRateMs.
+	RespectDNSTTL *bool
+
+	// LookupFamily is the IP address family to look up. It's one of AUTO,
+	// V4_ONLY, V6_ONLY, V4_PREFERRED or ALL.
+	LookupFamily string
+}
+
+var _ extensioncommon.BasicExtension = (*dnsResolver)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var d dnsResolver
+	if name := ext.Name; name != api.BuiltinDNSResolverExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinDNSResolverExtension, name)
+	}
+
+	if err := d.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &d,
+	}, nil
+}
+
+func (d *dnsResolver) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, d); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return d.validate()
+}
+
+func (d *dnsResolver) validate() error {
+	var resultErr error
+
+	switch d.ProxyType {
+	case "connect-proxy", "terminating-gateway":
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", d.ProxyType))
+	}
+
+	if len(d.Resolvers) == 0 && !d.UseTCPForDNSLookups && d.DNSRefreshRateMs == nil && d.RespectDNSTTL == nil && d.LookupFamily == "" {
+		resultErr = multierror.Append(resultErr, errors.New("one of Resolvers, UseTCPForDNSLookups, DNSRefreshRateMs, RespectDNSTTL or LookupFamily is required"))
+	}
+
+	for i, resolver := range d.Resolvers {
+		if _, err := parseResolver(resolver); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Resolvers[%d]: %w", i, err))
+		}
+	}
+
+	// Envoy requires a refresh rate of at least 1ms.
+	if d.DNSRefreshRateMs != nil && *d.DNSRefreshRateMs < 1 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("DNSRefreshRateMs must be at least 1, got %d", *d.DNSRefreshRateMs))
+	}
+
+	if d.LookupFamily != "" {
+		if _, ok := envoy_cluster_v3.Cluster_DnsLookupFamily_value[d.LookupFamily]; !ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected LookupFamily %q", d.LookupFamily))
+		}
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (d *dnsResolver) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == d.ProxyType
+}
+
+// PatchRoute does nothing.
+func (d *dnsResolver) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster configures the DNS resolution of the DNS clusters of the
+// upstream service.
+func (d *dnsResolver) PatchCluster(config *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() {
+		return c, false, nil
+	}
+
+	switch c.GetType() {
+	case envoy_cluster_v3.Cluster_LOGICAL_DNS, envoy_cluster_v3.Cluster_STRICT_DNS:
+	default:
+		return c, false, nil
+	}
+
+	if len(d.Resolvers) > 0 || d.UseTCPForDNSLookups {
+		resolverConfig := &envoy_cares_v3.CaresDnsResolverConfig{
+			DnsResolverOptions: &envoy_core_v3.DnsResolverOptions{
+				UseTcpForDnsLookups: d.UseTCPForDNSLookups,
+			},
+		}
+		for _, resolver := range d.Resolvers {
+			// The resolvers are validated by the constructor.
+			address, _ := parseResolver(resolver)
+			resolverConfig.Resolvers = append(resolverConfig.Resolvers, address)
+		}
+
+		typedConfig, err := anypb.New(resolverConfig)
+		if err != nil {
+			return c, false, err
+		}
+		c.TypedDnsResolverConfig = &envoy_core_v3.TypedExtensionConfig{
+			Name:        caresDNSResolverName,
+			TypedConfig: typedConfig,
+		}
+	}
+
+	if d.DNSRefreshRateMs != nil {
+		c.DnsRefreshRate = durationpb.New(time.Duration(*d.DNSRefreshRateMs) * time.Millisecond)
+	}
+	if d.RespectDNSTTL != nil {
+		c.RespectDnsTtl = *d.RespectDNSTTL
+	}
+	if d.LookupFamily != "" {
+		c.DnsLookupFamily = envoy_cluster_v3.Cluster_DnsLookupFamily(envoy_cluster_v3.Cluster_DnsLookupFamily_value[d.LookupFamily])
+	}
+
+	return c, true, nil
+}
+
+// PatchFilter does nothing.
+func (d *dnsResolver) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	return filter, false, nil
+}
+
+// parseResolver parses a resolver in the ip or ip:port format.
+func parseResolver(resolver string) (*envoy_core_v3.Address, error) {
+	host, port := resolver, defaultDNSPort
+	if h, p, err := net.SplitHostPort(resolver); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid port in resolver %q", resolver)
+		}
+		host, port = h, n
+	}
+	host = strings.Trim(host, "[]")
+
+	if net.ParseIP(host) == nil {
+		return nil, fmt.Errorf("resolver %q must be an IP address", resolver)
+	}
+
+	return &envoy_core_v3.Address{
+		Address: &envoy_core_v3.Address_SocketAddress{
+			SocketAddress: &envoy_core_v3.SocketAddress{
+				Protocol: envoy_core_v3.SocketAddress_UDP,
+				Address:  host,
+				PortSpecifier: &envoy_core_v3.SocketAddress_PortValue{
+					PortValue: uint32(port),
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,178 @@
+package dnsresolver
+
+import (
+	"testing"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_cares_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/network/dns_resolver/cares/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       dnsResolver
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "terminating-gateway", "LookupFamily": "V4_ONLY"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"without settings": {
+			arguments:      map[string]interface{}{"ProxyType": "terminating-gateway"},
+			expectedErrMsg: "one of Resolvers, UseTCPForDNSLookups, DNSRefreshRateMs, RespectDNSTTL or LookupFamily is required",
+			ok:             false,
+		},
+		"invalid resolver": {
+			arguments: map[string]interface{}{
+				"ProxyType": "terminating-gateway",
+				"Resolvers": []string{"10.0.0.2", "dns.example.com"},
+			},
+			expectedErrMsg: `Resolvers[1]: resolver "dns.example.com" must be an IP address`,
+			ok:             false,
+		},
+		"invalid resolver port": {
+			arguments: map[string]interface{}{
+				"ProxyType": "terminating-gateway",
+				"Resolvers": []string{"10.0.0.2:99999"},
+			},
+			expectedErrMsg: `Resolvers[0]: invalid port in resolver "10.0.0.2:99999"`,
+			ok:             false,
+		},
+		"invalid refresh rate": {
+			arguments:      map[string]interface{}{"ProxyType": "terminating-gateway", "DNSRefreshRateMs": 0},
+			expectedErrMsg: "DNSRefreshRateMs must be at least 1, got 0",
+			ok:             false,
+		},
+		"invalid lookup family": {
+			arguments:      map[string]interface{}{"ProxyType": "terminating-gateway", "LookupFamily": "V5_ONLY"},
+			expectedErrMsg: `unexpected LookupFamily "V5_ONLY"`,
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{
+				"ProxyType":           "connect-proxy",
+				"Resolvers":           []string{"10.0.0.2", "[fd00::2]:5353"},
+				"UseTCPForDNSLookups": true,
+				"DNSRefreshRateMs":    10000,
+				"RespectDNSTTL":       true,
+				"LookupFamily":        "V4_PREFERRED",
+			},
+			expected: dnsResolver{
+				ProxyType:           "connect-proxy",
+				Resolvers:           []string{"10.0.0.2", "[fd00::2]:5353"},
+				UseTCPForDNSLookups: true,
+				DNSRefreshRateMs:    intPointer(10000),
+				RespectDNSTTL:       boolPointer(true),
+				LookupFamily:        "V4_PREFERRED",
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinDNSResolverExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchCluster(t *testing.T) {
+	d := &dnsResolver{
+		ProxyType:           "terminating-gateway",
+		Resolvers:           []string{"10.0.0.2", "[fd00::2]:5353"},
+		UseTCPForDNSLookups: true,
+		DNSRefreshRateMs:    intPointer(10000),
+		RespectDNSTTL:       boolPointer(true),
+		LookupFamily:        "V4_PREFERRED",
+	}
+	svc := api.CompoundServiceName{Name: "external"}
+	upstream := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindTerminatingGateway,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}
+
+	t.Run("DNS cluster", func(t *testing.T) {
+		patched, ok, err := d.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+			Name:                 "external.default.dc1.internal.domain",
+			ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_LOGICAL_DNS},
+			DnsLookupFamily:      envoy_cluster_v3.Cluster_V4_ONLY,
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Equal(t, 10*time.Second, patched.DnsRefreshRate.AsDuration())
+		require.True(t, patched.RespectDnsTtl)
+		require.Equal(t, envoy_cluster_v3.Cluster_V4_PREFERRED, patched.DnsLookupFamily)
+
+		require.Equal(t, caresDNSResolverName, patched.TypedDnsResolverConfig.Name)
+		resolverConfig := &envoy_cares_v3.CaresDnsResolverConfig{}
+		require.NoError(t, patched.TypedDnsResolverConfig.TypedConfig.UnmarshalTo(resolverConfig))
+		require.True(t, resolverConfig.DnsResolverOptions.UseTcpForDnsLookups)
+		require.Len(t, resolverConfig.Resolvers, 2)
+		require.Equal(t, "10.0.0.2", resolverConfig.Resolvers[0].GetSocketAddress().Address)
+		require.Equal(t, uint32(53), resolverConfig.Resolvers[0].GetSocketAddress().GetPortValue())
+		require.Equal(t, "fd00::2", resolverConfig.Resolvers[1].GetSocketAddress().Address)
+		require.Equal(t, uint32(5353), resolverConfig.Resolvers[1].GetSocketAddress().GetPortValue())
+	})
+
+	t.Run("EDS cluster", func(t *testing.T) {
+		_, ok, err := d.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+			Name:                 "external.default.dc1.internal.domain",
+			ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_EDS},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("local app", func(t *testing.T) {
+		_, ok, err := d.PatchCluster(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+		}, &envoy_cluster_v3.Cluster{
+			Name:                 "local_app",
+			ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_STRICT_DNS},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func boolPointer(b bool) *bool {
+	return &b
+}
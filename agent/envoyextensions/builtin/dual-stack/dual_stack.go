@@ -0,0 +1,120 @@
+package dualstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+// lookupFamilies maps the address preferences to the DNS lookup families of
+// the clusters.
+var lookupFamilies = map[string]envoy_cluster_v3.Cluster_DnsLookupFamily{
+	// Envoy connects with Happy Eyeballs (RFC 8305) when the LOGICAL_DNS
+	// clusters resolve both the A and AAAA records. The STRICT_DNS clusters
+	// use all the addresses as endpoints instead.
+	"happy-eyeballs": envoy_cluster_v3.Cluster_ALL,
+	"ipv4-preferred": envoy_cluster_v3.Cluster_V4_PREFERRED,
+	"ipv6-preferred": envoy_cluster_v3.Cluster_AUTO,
+	"ipv4-only":      envoy_cluster_v3.Cluster_V4_ONLY,
+	"ipv6-only":      envoy_cluster_v3.Cluster_V6_ONLY,
+}
+
+// dualStack configures which addresses the DNS clusters of an upstream
+// service connect to when its hostnames resolve to both IPv4 and IPv6
+// addresses, such as the external services of terminating gateways. By
+// default, Consul configures them to only use the IPv4 addresses.
+type dualStack struct {
+	ProxyType string
+
+	// AddressPreference is one of happy-eyeballs, ipv4-preferred,
+	// ipv6-preferred, ipv4-only or ipv6-only.
+	AddressPreference string
+}
+
+var _ extensioncommon.BasicExtension = (*dualStack)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var d dualStack
+	if name := ext.Name; name != api.BuiltinDualStackExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinDualStackExtension, name)
+	}
+
+	if err := d.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &d,
+	}, nil
+}
+
+func (d *dualStack) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, d); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return d.validate()
+}
+
+func (d *dualStack) validate() error {
+	var resultErr error
+
+	switch d.ProxyType {
+	case "connect-proxy", "terminating-gateway":
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", d.ProxyType))
+	}
+
+	if _, ok := lookupFamilies[d.AddressPreference]; !ok {
+		var preferences []string
+		for preference := range lookupFamilies {
+			preferences = append(preferences, preference)
+		}
+		sort.Strings(preferences)
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected AddressPreference %q, must be one of %s", d.AddressPreference, strings.Join(preferences, ", ")))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (d *dualStack) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == d.ProxyType
+}
+
+// PatchRoute does nothing.
+func (d *dualStack) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster sets the DNS lookup family of the DNS clusters of the upstream
+// service.
+func (d *dualStack) PatchCluster(config *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() {
+		return c, false, nil
+	}
+
+	switch c.GetType() {
+	case envoy_cluster_v3.Cluster_LOGICAL_DNS, envoy_cluster_v3.Cluster_STRICT_DNS:
+	default:
+		return c, false, nil
+	}
+
+	c.DnsLookupFamily = lookupFamilies[d.AddressPreference]
+
+	return c, true, nil
+}
+
+// PatchFilter does nothing.
+func (d *dualStack) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	return filter, false, nil
+}
@@ -0,0 +1,119 @@
+package dualstack
+
+import (
+	"testing"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       dualStack
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "terminating-gateway", "AddressPreference": "happy-eyeballs"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"invalid address preference": {
+			arguments:      map[string]interface{}{"ProxyType": "terminating-gateway", "AddressPreference": "ipv5"},
+			expectedErrMsg: `unexpected AddressPreference "ipv5", must be one of happy-eyeballs, ipv4-only, ipv4-preferred, ipv6-only, ipv6-preferred`,
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{"ProxyType": "terminating-gateway", "AddressPreference": "happy-eyeballs"},
+			expected: dualStack{
+				ProxyType:         "terminating-gateway",
+				AddressPreference: "happy-eyeballs",
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinDualStackExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchCluster(t *testing.T) {
+	svc := api.CompoundServiceName{Name: "external"}
+	upstream := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindTerminatingGateway,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}
+
+	cases := map[string]struct {
+		preference    string
+		discoveryType envoy_cluster_v3.Cluster_DiscoveryType
+		expected      envoy_cluster_v3.Cluster_DnsLookupFamily
+		ok            bool
+	}{
+		"happy eyeballs": {
+			preference:    "happy-eyeballs",
+			discoveryType: envoy_cluster_v3.Cluster_LOGICAL_DNS,
+			expected:      envoy_cluster_v3.Cluster_ALL,
+			ok:            true,
+		},
+		"ipv6 preferred": {
+			preference:    "ipv6-preferred",
+			discoveryType: envoy_cluster_v3.Cluster_STRICT_DNS,
+			expected:      envoy_cluster_v3.Cluster_AUTO,
+			ok:            true,
+		},
+		"EDS cluster": {
+			preference:    "happy-eyeballs",
+			discoveryType: envoy_cluster_v3.Cluster_EDS,
+			expected:      envoy_cluster_v3.Cluster_V4_ONLY,
+			ok:            false,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			d := &dualStack{ProxyType: "terminating-gateway", AddressPreference: tc.preference}
+
+			patched, ok, err := d.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+				Name:                 "external.default.dc1.internal.domain",
+				ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: tc.discoveryType},
+				DnsLookupFamily:      envoy_cluster_v3.Cluster_V4_ONLY,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, patched.DnsLookupFamily)
+		})
+	}
+}
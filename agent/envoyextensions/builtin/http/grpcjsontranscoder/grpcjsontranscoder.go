@@ -0,0 +1,266 @@
+package grpcjsontranscoder
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_transcoder_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_json_transcoder/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	transcoderFilterName            = "envoy.filters.http.grpc_json_transcoder"
+	routerFilterName                = "envoy.filters.http.router"
+)
+
+// grpcJSONTranscoder exposes the methods of gRPC services as REST endpoints,
+// by transcoding the JSON requests to gRPC and the gRPC responses to JSON.
+//
+// It applies to the inbound listener of a connect-proxy, or to the listeners
+// of an API gateway.
+type grpcJSONTranscoder struct {
+	ProxyType string
+
+	// ProtoDescriptorBin is the base64 encoded binary FileDescriptorSet of
+	// the services, with their google.api.http annotations.
+	ProtoDescriptorBin string
+
+	// ProtoDescriptorFile is the path to the binary FileDescriptorSet of the
+	// services on the host of Envoy. Envoy doesn't support fetching the
+	// descriptor from a remote data source.
+	ProtoDescriptorFile string
+
+	// Services are the fully qualified names of the gRPC services to
+	// transcode.
+	Services []string
+
+	// PrintOptions controls how the JSON responses are printed.
+	PrintOptions *printOptions
+
+	// MatchIncomingRequestRoute routes the requests on their original path
+	// instead of the path of the gRPC method.
+	MatchIncomingRequestRoute bool
+
+	// IgnoredQueryParameters are the query parameters that aren't mapped to
+	// fields of the gRPC request.
+	IgnoredQueryParameters []string
+
+	// AutoMapping maps the methods without a google.api.http annotation to
+	// POST /<package>.<service>/<method>.
+	AutoMapping bool
+
+	// IgnoreUnknownQueryParameters ignores the query parameters that can't
+	// be mapped to fields of the gRPC request, instead of rejecting the
+	// request.
+	IgnoreUnknownQueryParameters bool
+
+	// ConvertGRPCStatus converts the gRPC status of the failed responses to
+	// a JSON body.
+	ConvertGRPCStatus bool
+}
+
+type printOptions struct {
+	AddWhitespace              bool
+	AlwaysPrintPrimitiveFields bool
+	AlwaysPrintEnumsAsInts     bool
+	PreserveProtoFieldNames    bool
+}
+
+var _ extensioncommon.BasicExtension = (*grpcJSONTranscoder)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var g grpcJSONTranscoder
+	if name := ext.Name; name != api.BuiltinGRPCJSONTranscoderExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinGRPCJSONTranscoderExtension, name)
+	}
+
+	if err := g.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &g,
+	}, nil
+}
+
+func (g *grpcJSONTranscoder) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, g); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return g.validate()
+}
+
+func (g *grpcJSONTranscoder) validate() error {
+	var resultErr error
+
+	switch api.ServiceKind(g.ProxyType) {
+	case api.ServiceKindConnectProxy, api.ServiceKindAPIGateway:
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", g.ProxyType))
+	}
+
+	if len(g.Services) == 0 {
+		resultErr = multierror.Append(resultErr, errors.New("Services is missing"))
+	}
+
+	switch {
+	case g.ProtoDescriptorBin != "" && g.ProtoDescriptorFile != "":
+		resultErr = multierror.Append(resultErr, errors.New("only one of ProtoDescriptorBin or ProtoDescriptorFile can be set"))
+	case g.ProtoDescriptorBin != "":
+		if err := g.validateProtoDescriptorBin(); err != nil {
+			resultErr = multierror.Append(resultErr, err)
+		}
+	case g.ProtoDescriptorFile == "":
+		resultErr = multierror.Append(resultErr, errors.New("one of ProtoDescriptorBin or ProtoDescriptorFile is required"))
+	}
+
+	return resultErr
+}
+
+// validateProtoDescriptorBin checks that the inline descriptor is a valid
+// FileDescriptorSet that contains all the services to transcode, so that the
+// mistakes are reported when the extension is configured rather than by
+// Envoy.
+func (g *grpcJSONTranscoder) validateProtoDescriptorBin() error {
+	b, err := base64.StdEncoding.DecodeString(g.ProtoDescriptorBin)
+	if err != nil {
+		return fmt.Errorf("ProtoDescriptorBin is not valid base64: %w", err)
+	}
+
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(b, &descriptorSet); err != nil {
+		return fmt.Errorf("ProtoDescriptorBin is not a valid FileDescriptorSet: %w", err)
+	}
+
+	services := make(map[string]struct{})
+	for _, file := range descriptorSet.File {
+		for _, service := range file.Service {
+			name := service.GetName()
+			if file.GetPackage() != "" {
+				name = file.GetPackage() + "." + name
+			}
+			services[name] = struct{}{}
+		}
+	}
+
+	var resultErr error
+	for _, service := range g.Services {
+		if _, ok := services[service]; !ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("service %q is not in ProtoDescriptorBin", service))
+		}
+	}
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (g *grpcJSONTranscoder) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	// The requests are transcoded where they enter the mesh.
+	return string(config.Kind) == g.ProxyType && !config.IsUpstream()
+}
+
+// PatchRoute does nothing.
+func (g *grpcJSONTranscoder) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster does nothing.
+func (g *grpcJSONTranscoder) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter inserts the envoy.filters.http.grpc_json_transcoder filter
+// before the router of the envoy.filters.network.http_connection_manager
+// filters.
+func (g *grpcJSONTranscoder) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	transcoder, err := g.toEnvoy()
+	if err != nil {
+		return filter, false, err
+	}
+	transcoderFilter, err := extensioncommon.MakeEnvoyHTTPFilter(transcoderFilterName, transcoder)
+	if err != nil {
+		return filter, false, err
+	}
+
+	changedFilters := make([]*envoy_http_v3.HttpFilter, 0, len(config.HttpFilters)+1)
+	inserted := false
+	for _, httpFilter := range config.HttpFilters {
+		if httpFilter.Name == transcoderFilterName {
+			// Replace the transcoder of a previous configuration.
+			continue
+		}
+		if httpFilter.Name == routerFilterName && !inserted {
+			changedFilters = append(changedFilters, transcoderFilter)
+			inserted = true
+		}
+		changedFilters = append(changedFilters, httpFilter)
+	}
+	if !inserted {
+		return filter, false, errors.New("no router filter found")
+	}
+	config.HttpFilters = changedFilters
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+func (g *grpcJSONTranscoder) toEnvoy() (*envoy_transcoder_v3.GrpcJsonTranscoder, error) {
+	transcoder := &envoy_transcoder_v3.GrpcJsonTranscoder{
+		Services:                     g.Services,
+		MatchIncomingRequestRoute:    g.MatchIncomingRequestRoute,
+		IgnoredQueryParameters:       g.IgnoredQueryParameters,
+		AutoMapping:                  g.AutoMapping,
+		IgnoreUnknownQueryParameters: g.IgnoreUnknownQueryParameters,
+		ConvertGrpcStatus:            g.ConvertGRPCStatus,
+	}
+
+	if g.ProtoDescriptorBin != "" {
+		b, err := base64.StdEncoding.DecodeString(g.ProtoDescriptorBin)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ProtoDescriptorBin: %w", err)
+		}
+		transcoder.DescriptorSet = &envoy_transcoder_v3.GrpcJsonTranscoder_ProtoDescriptorBin{ProtoDescriptorBin: b}
+	} else {
+		transcoder.DescriptorSet = &envoy_transcoder_v3.GrpcJsonTranscoder_ProtoDescriptor{ProtoDescriptor: g.ProtoDescriptorFile}
+	}
+
+	if g.PrintOptions != nil {
+		transcoder.PrintOptions = &envoy_transcoder_v3.GrpcJsonTranscoder_PrintOptions{
+			AddWhitespace:              g.PrintOptions.AddWhitespace,
+			AlwaysPrintPrimitiveFields: g.PrintOptions.AlwaysPrintPrimitiveFields,
+			AlwaysPrintEnumsAsInts:     g.PrintOptions.AlwaysPrintEnumsAsInts,
+			PreserveProtoFieldNames:    g.PrintOptions.PreserveProtoFieldNames,
+		}
+	}
+
+	return transcoder, nil
+}
@@ -0,0 +1,235 @@
+package grpcjsontranscoder
+
+import (
+	"encoding/base64"
+	"testing"
+
+	envoy_transcoder_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_json_transcoder/v3"
+	envoy_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	descriptor := testProtoDescriptorBin(t)
+
+	makeArguments := func(overrides map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"ProxyType":          "connect-proxy",
+			"ProtoDescriptorBin": descriptor,
+			"Services":           []string{"helloworld.Greeter"},
+		}
+
+		for k, v := range overrides {
+			m[k] = v
+		}
+
+		return m
+	}
+
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       grpcJSONTranscoder
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     makeArguments(map[string]interface{}{}),
+			extensionName: "bad",
+			ok:            false,
+		},
+		"without services": {
+			arguments:      makeArguments(map[string]interface{}{"Services": nil}),
+			expectedErrMsg: "Services is missing",
+			ok:             false,
+		},
+		"without descriptor": {
+			arguments:      makeArguments(map[string]interface{}{"ProtoDescriptorBin": ""}),
+			expectedErrMsg: "one of ProtoDescriptorBin or ProtoDescriptorFile is required",
+			ok:             false,
+		},
+		"with both descriptors": {
+			arguments:      makeArguments(map[string]interface{}{"ProtoDescriptorFile": "/etc/envoy/helloworld.pb"}),
+			expectedErrMsg: "only one of ProtoDescriptorBin or ProtoDescriptorFile can be set",
+			ok:             false,
+		},
+		"invalid base64": {
+			arguments:      makeArguments(map[string]interface{}{"ProtoDescriptorBin": "not base64!"}),
+			expectedErrMsg: "ProtoDescriptorBin is not valid base64",
+			ok:             false,
+		},
+		"invalid descriptor": {
+			arguments:      makeArguments(map[string]interface{}{"ProtoDescriptorBin": base64.StdEncoding.EncodeToString([]byte("garbage"))}),
+			expectedErrMsg: "ProtoDescriptorBin is not a valid FileDescriptorSet",
+			ok:             false,
+		},
+		"service not in the descriptor": {
+			arguments:      makeArguments(map[string]interface{}{"Services": []string{"helloworld.Greeter", "Greeter"}}),
+			expectedErrMsg: `service "Greeter" is not in ProtoDescriptorBin`,
+			ok:             false,
+		},
+		"descriptor file": {
+			arguments: makeArguments(map[string]interface{}{
+				"ProxyType":           "api-gateway",
+				"ProtoDescriptorBin":  "",
+				"ProtoDescriptorFile": "/etc/envoy/helloworld.pb",
+			}),
+			expected: grpcJSONTranscoder{
+				ProxyType:           "api-gateway",
+				ProtoDescriptorFile: "/etc/envoy/helloworld.pb",
+				Services:            []string{"helloworld.Greeter"},
+			},
+			ok: true,
+		},
+		"valid everything": {
+			arguments: makeArguments(map[string]interface{}{
+				"PrintOptions": map[string]interface{}{
+					"AddWhitespace":           true,
+					"PreserveProtoFieldNames": true,
+				},
+				"MatchIncomingRequestRoute":    true,
+				"IgnoredQueryParameters":       []string{"api_key"},
+				"AutoMapping":                  true,
+				"IgnoreUnknownQueryParameters": true,
+				"ConvertGRPCStatus":            true,
+			}),
+			expected: grpcJSONTranscoder{
+				ProxyType:          "connect-proxy",
+				ProtoDescriptorBin: descriptor,
+				Services:           []string{"helloworld.Greeter"},
+				PrintOptions: &printOptions{
+					AddWhitespace:           true,
+					PreserveProtoFieldNames: true,
+				},
+				MatchIncomingRequestRoute:    true,
+				IgnoredQueryParameters:       []string{"api_key"},
+				AutoMapping:                  true,
+				IgnoreUnknownQueryParameters: true,
+				ConvertGRPCStatus:            true,
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinGRPCJSONTranscoderExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	router, err := extensioncommon.MakeEnvoyHTTPFilter(routerFilterName, &envoy_router_v3.Router{})
+	require.NoError(t, err)
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		HttpFilters: []*envoy_http_v3.HttpFilter{router},
+	})
+	require.NoError(t, err)
+
+	g := &grpcJSONTranscoder{
+		ProxyType:          "connect-proxy",
+		ProtoDescriptorBin: testProtoDescriptorBin(t),
+		Services:           []string{"helloworld.Greeter"},
+		PrintOptions:       &printOptions{AlwaysPrintPrimitiveFields: true},
+		ConvertGRPCStatus:  true,
+	}
+	require.NoError(t, g.validate())
+
+	patched, ok, err := g.PatchFilter(nil, filter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Applying the extension again replaces the transcoder.
+	g.IgnoredQueryParameters = []string{"api_key"}
+	patched, ok, err = g.PatchFilter(nil, patched)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+	require.NotNil(t, config)
+	require.Len(t, config.HttpFilters, 2)
+	require.Equal(t, transcoderFilterName, config.HttpFilters[0].Name)
+	require.Equal(t, routerFilterName, config.HttpFilters[1].Name)
+
+	transcoder := &envoy_transcoder_v3.GrpcJsonTranscoder{}
+	require.NoError(t, config.HttpFilters[0].GetTypedConfig().UnmarshalTo(transcoder))
+	require.Equal(t, []string{"helloworld.Greeter"}, transcoder.Services)
+	require.Equal(t, []string{"api_key"}, transcoder.IgnoredQueryParameters)
+	require.True(t, transcoder.ConvertGrpcStatus)
+	require.True(t, transcoder.PrintOptions.AlwaysPrintPrimitiveFields)
+
+	var descriptorSet descriptorpb.FileDescriptorSet
+	require.NoError(t, proto.Unmarshal(transcoder.GetProtoDescriptorBin(), &descriptorSet))
+	require.Equal(t, "Greeter", descriptorSet.File[0].Service[0].GetName())
+}
+
+func TestPatchFilter_DescriptorFile(t *testing.T) {
+	router, err := extensioncommon.MakeEnvoyHTTPFilter(routerFilterName, &envoy_router_v3.Router{})
+	require.NoError(t, err)
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		HttpFilters: []*envoy_http_v3.HttpFilter{router},
+	})
+	require.NoError(t, err)
+
+	g := &grpcJSONTranscoder{
+		ProxyType:           "api-gateway",
+		ProtoDescriptorFile: "/etc/envoy/helloworld.pb",
+		Services:            []string{"helloworld.Greeter"},
+	}
+
+	patched, ok, err := g.PatchFilter(nil, filter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+	transcoder := &envoy_transcoder_v3.GrpcJsonTranscoder{}
+	require.NoError(t, config.HttpFilters[0].GetTypedConfig().UnmarshalTo(transcoder))
+	require.Equal(t, "/etc/envoy/helloworld.pb", transcoder.GetProtoDescriptor())
+	require.Equal(t, []string{"helloworld.Greeter"}, transcoder.Services)
+}
+
+func testProtoDescriptorBin(t *testing.T) string {
+	t.Helper()
+
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("helloworld.proto"),
+				Package: proto.String("helloworld"),
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{Name: proto.String("Greeter")},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
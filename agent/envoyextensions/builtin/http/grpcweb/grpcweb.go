@@ -0,0 +1,267 @@
+package grpcweb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
+	envoy_grpc_web_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_web/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	corsFilterName                  = "envoy.filters.http.cors"
+	grpcWebFilterName               = "envoy.filters.http.grpc_web"
+	routerFilterName                = "envoy.filters.http.router"
+)
+
+var (
+	// defaultAllowMethods and defaultAllowHeaders are the methods and
+	// headers the gRPC-Web clients send in their requests.
+	defaultAllowMethods = []string{"GET", "PUT", "DELETE", "POST", "OPTIONS"}
+	defaultAllowHeaders = []string{
+		"keep-alive", "user-agent", "cache-control", "content-type", "content-transfer-encoding",
+		"x-accept-content-transfer-encoding", "x-accept-response-streaming", "x-user-agent",
+		"x-grpc-web", "grpc-timeout",
+	}
+	// defaultExposeHeaders are the headers the gRPC-Web clients read from
+	// the responses.
+	defaultExposeHeaders = []string{"grpc-status", "grpc-message"}
+)
+
+// grpcWeb translates the gRPC-Web requests of browser clients to gRPC, so
+// they can call the gRPC services of the mesh.
+//
+// It applies to the inbound listener of a connect-proxy, or to the listeners
+// of an API gateway.
+type grpcWeb struct {
+	ProxyType string
+
+	// CORS is the CORS policy of the listeners, for the browser clients
+	// that are served from another origin. No CORS policy is configured if
+	// it is nil.
+	CORS *corsPolicy
+}
+
+type corsPolicy struct {
+	// AllowOrigins are the origins allowed to make requests. The "*"
+	// origin allows all origins.
+	AllowOrigins []string
+
+	// AllowMethods, AllowHeaders and ExposeHeaders default to the methods
+	// and headers used by gRPC-Web.
+	AllowMethods  []string
+	AllowHeaders  []string
+	ExposeHeaders []string
+
+	// MaxAge is how long the result of a preflight request can be cached,
+	// in seconds.
+	MaxAge *int
+
+	// AllowCredentials allows the requests with credentials.
+	AllowCredentials bool
+}
+
+var _ extensioncommon.BasicExtension = (*grpcWeb)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var g grpcWeb
+	if name := ext.Name; name != api.BuiltinGRPCWebExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinGRPCWebExtension, name)
+	}
+
+	if err := g.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &g,
+	}, nil
+}
+
+func (g *grpcWeb) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, g); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return g.validate()
+}
+
+func (g *grpcWeb) validate() error {
+	var resultErr error
+
+	switch api.ServiceKind(g.ProxyType) {
+	case api.ServiceKindConnectProxy, api.ServiceKindAPIGateway:
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", g.ProxyType))
+	}
+
+	if g.CORS != nil {
+		if len(g.CORS.AllowOrigins) == 0 {
+			resultErr = multierror.Append(resultErr, errors.New("CORS.AllowOrigins is missing"))
+		}
+		for _, origin := range g.CORS.AllowOrigins {
+			if origin == "" {
+				resultErr = multierror.Append(resultErr, errors.New("CORS.AllowOrigins: origin cannot be empty"))
+			}
+		}
+		if g.CORS.MaxAge != nil && *g.CORS.MaxAge < 0 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("CORS.MaxAge(in second) cannot be negative, got %d", *g.CORS.MaxAge))
+		}
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (g *grpcWeb) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	// The requests of the browser clients are translated where they enter
+	// the mesh.
+	return string(config.Kind) == g.ProxyType && !config.IsUpstream()
+}
+
+// PatchRoute configures the CORS policy of the routes of the listeners of
+// API gateways.
+func (g *grpcWeb) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	if g.CORS == nil {
+		return route, false, nil
+	}
+	g.patchRouteConfiguration(route)
+	return route, true, nil
+}
+
+// PatchCluster does nothing.
+func (g *grpcWeb) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter inserts the envoy.filters.http.grpc_web filter, and the
+// envoy.filters.http.cors filter if there is a CORS policy, before the router
+// of the envoy.filters.network.http_connection_manager filters.
+func (g *grpcWeb) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	var insert []*envoy_http_v3.HttpFilter
+	if g.CORS != nil && !hasHTTPFilter(config, corsFilterName) {
+		// The CORS filter must come first to answer the preflight requests.
+		corsFilter, err := extensioncommon.MakeEnvoyHTTPFilter(corsFilterName, &envoy_cors_v3.Cors{})
+		if err != nil {
+			return filter, false, err
+		}
+		insert = append(insert, corsFilter)
+	}
+	if !hasHTTPFilter(config, grpcWebFilterName) {
+		grpcWebFilter, err := extensioncommon.MakeEnvoyHTTPFilter(grpcWebFilterName, &envoy_grpc_web_v3.GrpcWeb{})
+		if err != nil {
+			return filter, false, err
+		}
+		insert = append(insert, grpcWebFilter)
+	}
+
+	changedFilters := make([]*envoy_http_v3.HttpFilter, 0, len(config.HttpFilters)+len(insert))
+	inserted := false
+	for _, httpFilter := range config.HttpFilters {
+		if httpFilter.Name == routerFilterName && !inserted {
+			changedFilters = append(changedFilters, insert...)
+			inserted = true
+		}
+		changedFilters = append(changedFilters, httpFilter)
+	}
+	if !inserted && len(insert) > 0 {
+		return filter, false, errors.New("no router filter found")
+	}
+	config.HttpFilters = changedFilters
+
+	if routeConfig := config.GetRouteConfig(); routeConfig != nil && g.CORS != nil {
+		g.patchRouteConfiguration(routeConfig)
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+// patchRouteConfiguration sets the CORS policy of the virtual hosts of the
+// route configuration.
+func (g *grpcWeb) patchRouteConfiguration(routeConfig *envoy_route_v3.RouteConfiguration) {
+	for _, vh := range routeConfig.VirtualHosts {
+		vh.Cors = g.CORS.toEnvoy()
+	}
+}
+
+func (c *corsPolicy) toEnvoy() *envoy_route_v3.CorsPolicy {
+	policy := &envoy_route_v3.CorsPolicy{
+		AllowMethods:  joinOrDefault(c.AllowMethods, defaultAllowMethods),
+		AllowHeaders:  joinOrDefault(c.AllowHeaders, defaultAllowHeaders),
+		ExposeHeaders: joinOrDefault(c.ExposeHeaders, defaultExposeHeaders),
+	}
+
+	for _, origin := range c.AllowOrigins {
+		matcher := &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: origin},
+		}
+		if origin == "*" {
+			matcher.MatchPattern = &envoy_matcher_v3.StringMatcher_SafeRegex{
+				SafeRegex: &envoy_matcher_v3.RegexMatcher{
+					EngineType: &envoy_matcher_v3.RegexMatcher_GoogleRe2{
+						GoogleRe2: &envoy_matcher_v3.RegexMatcher_GoogleRE2{},
+					},
+					Regex: ".*",
+				},
+			}
+		}
+		policy.AllowOriginStringMatch = append(policy.AllowOriginStringMatch, matcher)
+	}
+
+	if c.MaxAge != nil {
+		policy.MaxAge = strconv.Itoa(*c.MaxAge)
+	}
+	if c.AllowCredentials {
+		policy.AllowCredentials = &wrappers.BoolValue{Value: true}
+	}
+
+	return policy
+}
+
+func hasHTTPFilter(config *envoy_http_v3.HttpConnectionManager, name string) bool {
+	for _, httpFilter := range config.HttpFilters {
+		if httpFilter.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func joinOrDefault(values, defaults []string) string {
+	if len(values) == 0 {
+		values = defaults
+	}
+	return strings.Join(values, ",")
+}
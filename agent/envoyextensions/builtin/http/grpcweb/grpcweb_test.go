@@ -0,0 +1,241 @@
+package grpcweb
+
+import (
+	"testing"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       grpcWeb
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"terminating gateway": {
+			arguments:      map[string]interface{}{"ProxyType": "terminating-gateway"},
+			expectedErrMsg: `unexpected ProxyType "terminating-gateway"`,
+			ok:             false,
+		},
+		"CORS without origins": {
+			arguments: map[string]interface{}{
+				"ProxyType": "connect-proxy",
+				"CORS":      map[string]interface{}{"MaxAge": -1},
+			},
+			expectedErrMsg: "CORS.AllowOrigins is missing",
+			ok:             false,
+		},
+		"CORS with a negative max age": {
+			arguments: map[string]interface{}{
+				"ProxyType": "connect-proxy",
+				"CORS":      map[string]interface{}{"AllowOrigins": []string{"*"}, "MaxAge": -1},
+			},
+			expectedErrMsg: "CORS.MaxAge(in second) cannot be negative, got -1",
+			ok:             false,
+		},
+		"connect proxy": {
+			arguments: map[string]interface{}{"ProxyType": "connect-proxy"},
+			expected:  grpcWeb{ProxyType: "connect-proxy"},
+			ok:        true,
+		},
+		"api gateway with CORS": {
+			arguments: map[string]interface{}{
+				"ProxyType": "api-gateway",
+				"CORS": map[string]interface{}{
+					"AllowOrigins":     []string{"https://example.com"},
+					"MaxAge":           600,
+					"AllowCredentials": true,
+				},
+			},
+			expected: grpcWeb{
+				ProxyType: "api-gateway",
+				CORS: &corsPolicy{
+					AllowOrigins:     []string{"https://example.com"},
+					MaxAge:           intPointer(600),
+					AllowCredentials: true,
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinGRPCWebExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	makeHCM := func(t *testing.T, routeSpecifier envoy_http_v3.HttpConnectionManager_RouteConfig) *envoy_http_v3.HttpConnectionManager {
+		router, err := extensioncommon.MakeEnvoyHTTPFilter(routerFilterName, &envoy_router_v3.Router{})
+		require.NoError(t, err)
+		return &envoy_http_v3.HttpConnectionManager{
+			HttpFilters:    []*envoy_http_v3.HttpFilter{router},
+			RouteSpecifier: &routeSpecifier,
+		}
+	}
+
+	t.Run("without CORS", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, makeHCM(t, envoy_http_v3.HttpConnectionManager_RouteConfig{
+			RouteConfig: testRouteConfiguration(),
+		}))
+		require.NoError(t, err)
+
+		g := &grpcWeb{ProxyType: "connect-proxy"}
+		patched, ok, err := g.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.Equal(t, []string{grpcWebFilterName, routerFilterName}, httpFilterNames(config))
+		require.Nil(t, config.GetRouteConfig().VirtualHosts[0].Cors)
+
+		// Applying the extension twice doesn't duplicate the filter.
+		patched, ok, err = g.PatchFilter(nil, patched)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []string{grpcWebFilterName, routerFilterName}, httpFilterNames(envoy_resource_v3.GetHTTPConnectionManager(patched)))
+	})
+
+	t.Run("with CORS", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, makeHCM(t, envoy_http_v3.HttpConnectionManager_RouteConfig{
+			RouteConfig: testRouteConfiguration(),
+		}))
+		require.NoError(t, err)
+
+		g := &grpcWeb{
+			ProxyType: "connect-proxy",
+			CORS: &corsPolicy{
+				AllowOrigins: []string{"https://example.com", "*"},
+				MaxAge:       intPointer(600),
+			},
+		}
+		patched, ok, err := g.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.Equal(t, []string{corsFilterName, grpcWebFilterName, routerFilterName}, httpFilterNames(config))
+		requireCORS(t, config.GetRouteConfig())
+	})
+
+	t.Run("without a router", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{})
+		require.NoError(t, err)
+
+		g := &grpcWeb{ProxyType: "connect-proxy"}
+		_, ok, err := g.PatchFilter(nil, filter)
+		require.EqualError(t, err, "no router filter found")
+		require.False(t, ok)
+	})
+}
+
+func TestPatchRoute(t *testing.T) {
+	g := &grpcWeb{ProxyType: "api-gateway"}
+	_, ok, err := g.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	g.CORS = &corsPolicy{
+		AllowOrigins: []string{"https://example.com", "*"},
+		MaxAge:       intPointer(600),
+	}
+	patched, ok, err := g.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.True(t, ok)
+	requireCORS(t, patched)
+}
+
+func TestCanApply(t *testing.T) {
+	svc := api.CompoundServiceName{Name: "svc"}
+
+	g := &grpcWeb{ProxyType: "api-gateway"}
+	require.True(t, g.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindAPIGateway,
+		ServiceName: svc,
+	}))
+	require.False(t, g.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}))
+
+	g = &grpcWeb{ProxyType: "connect-proxy"}
+	require.False(t, g.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}))
+}
+
+func testRouteConfiguration() *envoy_route_v3.RouteConfiguration {
+	return &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{
+			{Name: "public_listener", Domains: []string{"*"}},
+		},
+	}
+}
+
+func requireCORS(t *testing.T, routeConfig *envoy_route_v3.RouteConfiguration) {
+	t.Helper()
+
+	cors := routeConfig.VirtualHosts[0].Cors
+	require.NotNil(t, cors)
+	require.Len(t, cors.AllowOriginStringMatch, 2)
+	require.Equal(t, "https://example.com", cors.AllowOriginStringMatch[0].GetExact())
+	require.Equal(t, ".*", cors.AllowOriginStringMatch[1].GetSafeRegex().GetRegex())
+	require.Equal(t, "GET,PUT,DELETE,POST,OPTIONS", cors.AllowMethods)
+	require.Contains(t, cors.AllowHeaders, "x-grpc-web")
+	require.Equal(t, "grpc-status,grpc-message", cors.ExposeHeaders)
+	require.Equal(t, "600", cors.MaxAge)
+	require.Nil(t, cors.AllowCredentials)
+}
+
+func httpFilterNames(config *envoy_http_v3.HttpConnectionManager) []string {
+	var names []string
+	for _, httpFilter := range config.HttpFilters {
+		names = append(names, httpFilter.Name)
+	}
+	return names
+}
+
+func intPointer(i int) *int {
+	return &i
+}
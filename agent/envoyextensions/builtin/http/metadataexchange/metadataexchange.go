@@ -0,0 +1,182 @@
+package metadataexchange
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	defaultHeaderPrefix             = "x-consul-peer"
+)
+
+var headerPrefixRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// metadataExchange exchanges the identity of the services between the
+// sidecars through HTTP headers, so that the access logs of each side and the
+// applications can tell which peer service they are talking to.
+//
+// The inbound listener of the local service adds the following headers:
+//   - <HeaderPrefix>-identity to the requests, with the SPIFFE ID of the
+//     downstream service from its client certificate.
+//   - <HeaderPrefix>-service, <HeaderPrefix>-namespace,
+//     <HeaderPrefix>-partition and <HeaderPrefix>-identity to the responses,
+//     with the name, namespace, partition and SPIFFE ID of the local service.
+//
+// They can be referenced in the access logs with the %REQ()% and %RESP()%
+// command operators. The request headers always overwrite the values sent by
+// the downstream, so they can't be spoofed.
+type metadataExchange struct {
+	ProxyType string
+
+	// HeaderPrefix is the prefix of the names of the headers. It defaults
+	// to x-consul-peer.
+	HeaderPrefix string
+}
+
+var _ extensioncommon.BasicExtension = (*metadataExchange)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var m metadataExchange
+	if name := ext.Name; name != api.BuiltinMetadataExchangeExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinMetadataExchangeExtension, name)
+	}
+
+	if err := m.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &m,
+	}, nil
+}
+
+func (m *metadataExchange) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, m); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	if m.HeaderPrefix == "" {
+		m.HeaderPrefix = defaultHeaderPrefix
+	}
+	return m.validate()
+}
+
+func (m *metadataExchange) validate() error {
+	var resultErr error
+
+	if m.ProxyType != "connect-proxy" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", m.ProxyType))
+	}
+
+	if !headerPrefixRegexp.MatchString(m.HeaderPrefix) {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("HeaderPrefix %q must only contain lowercase alphanumeric characters separated by hyphens", m.HeaderPrefix))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (m *metadataExchange) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == m.ProxyType
+}
+
+// PatchRoute does nothing.
+func (m *metadataExchange) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster does nothing.
+func (m *metadataExchange) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter adds the identity headers to the inline route configuration of
+// the envoy.filters.network.http_connection_manager filter of the inbound
+// listener.
+func (m *metadataExchange) PatchFilter(config *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if config.IsUpstream() {
+		return filter, false, nil
+	}
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	hcm := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if hcm == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	routeConfig := hcm.GetRouteConfig()
+	if routeConfig == nil {
+		return filter, false, nil
+	}
+
+	requestHeaders := []*envoy_core_v3.HeaderValueOption{
+		m.header("identity", "%DOWNSTREAM_PEER_URI_SAN%"),
+	}
+	responseHeaders := []*envoy_core_v3.HeaderValueOption{
+		m.header("service", config.ServiceName.Name),
+		m.header("namespace", orDefault(config.ServiceName.Namespace)),
+		m.header("partition", orDefault(config.ServiceName.Partition)),
+		m.header("identity", "%DOWNSTREAM_LOCAL_URI_SAN%"),
+	}
+	for _, vh := range routeConfig.VirtualHosts {
+		vh.RequestHeadersToAdd = append(m.withoutOwnHeaders(vh.RequestHeadersToAdd), requestHeaders...)
+		vh.ResponseHeadersToAdd = append(m.withoutOwnHeaders(vh.ResponseHeadersToAdd), responseHeaders...)
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, hcm)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+func (m *metadataExchange) header(name, value string) *envoy_core_v3.HeaderValueOption {
+	return &envoy_core_v3.HeaderValueOption{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   m.HeaderPrefix + "-" + name,
+			Value: value,
+		},
+		Append: &wrappers.BoolValue{Value: false},
+	}
+}
+
+// withoutOwnHeaders removes the headers previously added by the extension so
+// that applying it again doesn't duplicate them.
+func (m *metadataExchange) withoutOwnHeaders(headers []*envoy_core_v3.HeaderValueOption) []*envoy_core_v3.HeaderValueOption {
+	var result []*envoy_core_v3.HeaderValueOption
+	for _, h := range headers {
+		if strings.HasPrefix(h.GetHeader().GetKey(), m.HeaderPrefix+"-") {
+			continue
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+func orDefault(s string) string {
+	if s == "" {
+		return "default"
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package metadataexchange
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       metadataExchange
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"invalid header prefix": {
+			arguments:      map[string]interface{}{"ProxyType": "connect-proxy", "HeaderPrefix": "X-Peer_"},
+			expectedErrMsg: `HeaderPrefix "X-Peer_" must only contain lowercase alphanumeric characters separated by hyphens`,
+			ok:             false,
+		},
+		"default header prefix": {
+			arguments: map[string]interface{}{"ProxyType": "connect-proxy"},
+			expected: metadataExchange{
+				ProxyType:    "connect-proxy",
+				HeaderPrefix: "x-consul-peer",
+			},
+			ok: true,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{"ProxyType": "connect-proxy", "HeaderPrefix": "x-peer"},
+			expected: metadataExchange{
+				ProxyType:    "connect-proxy",
+				HeaderPrefix: "x-peer",
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinMetadataExchangeExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	m := &metadataExchange{ProxyType: "connect-proxy", HeaderPrefix: "x-consul-peer"}
+	svc := api.CompoundServiceName{Name: "api", Namespace: "ns1"}
+	inbound := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}
+
+	makeHCM := func() *envoy_http_v3.HttpConnectionManager {
+		return &envoy_http_v3.HttpConnectionManager{
+			RouteSpecifier: &envoy_http_v3.HttpConnectionManager_RouteConfig{
+				RouteConfig: &envoy_route_v3.RouteConfiguration{
+					VirtualHosts: []*envoy_route_v3.VirtualHost{
+						{
+							Name:    "public_listener",
+							Domains: []string{"*"},
+							ResponseHeadersToAdd: []*envoy_core_v3.HeaderValueOption{
+								{Header: &envoy_core_v3.HeaderValue{Key: "x-custom", Value: "1"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("inbound", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, makeHCM())
+		require.NoError(t, err)
+
+		patched, ok, err := m.PatchFilter(inbound, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// Applying the extension again doesn't duplicate the headers.
+		patched, ok, err = m.PatchFilter(inbound, patched)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		vh := envoy_resource_v3.GetHTTPConnectionManager(patched).GetRouteConfig().VirtualHosts[0]
+		require.Equal(t, map[string]string{
+			"x-consul-peer-identity": "%DOWNSTREAM_PEER_URI_SAN%",
+		}, headerValues(vh.RequestHeadersToAdd))
+		require.Equal(t, map[string]string{
+			"x-custom":                "1",
+			"x-consul-peer-service":   "api",
+			"x-consul-peer-namespace": "ns1",
+			"x-consul-peer-partition": "default",
+			"x-consul-peer-identity":  "%DOWNSTREAM_LOCAL_URI_SAN%",
+		}, headerValues(vh.ResponseHeadersToAdd))
+		for _, h := range vh.RequestHeadersToAdd {
+			require.False(t, h.Append.GetValue())
+		}
+	})
+
+	t.Run("RDS", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+			RouteSpecifier: &envoy_http_v3.HttpConnectionManager_Rds{
+				Rds: &envoy_http_v3.Rds{RouteConfigName: "db"},
+			},
+		})
+		require.NoError(t, err)
+
+		_, ok, err := m.PatchFilter(inbound, filter)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("upstream", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, makeHCM())
+		require.NoError(t, err)
+
+		_, ok, err := m.PatchFilter(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+			Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+				svc: {},
+			},
+		}, filter)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func headerValues(headers []*envoy_core_v3.HeaderValueOption) map[string]string {
+	values := make(map[string]string)
+	for _, h := range headers {
+		values[h.Header.Key] = h.Header.Value
+	}
+	return values
+}
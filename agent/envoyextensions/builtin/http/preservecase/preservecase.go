@@ -0,0 +1,185 @@
+package preservecase
+
+import (
+	"errors"
+	"fmt"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_preserve_case_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/header_formatters/preserve_case/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	httpProtocolOptionsName         = "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
+	preserveCaseFormatterName       = "preserve_case"
+)
+
+// preserveCase keeps the case of the HTTP/1.1 header keys of the requests and
+// responses of a service, instead of lowercasing them.
+//
+// On the proxy of the service, it applies to the inbound listener and the
+// connections to the local application. On the proxies of its downstreams, it
+// applies to the outbound listener and the connections to the service.
+type preserveCase struct {
+	ProxyType string
+
+	// ForwardReasonPhrase forwards the reason phrase of the responses
+	// instead of the standard one of their status code.
+	ForwardReasonPhrase bool
+}
+
+var _ extensioncommon.BasicExtension = (*preserveCase)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var p preserveCase
+	if name := ext.Name; name != api.BuiltinPreserveCaseExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinPreserveCaseExtension, name)
+	}
+
+	if err := p.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &p,
+	}, nil
+}
+
+func (p *preserveCase) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, p); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return p.validate()
+}
+
+func (p *preserveCase) validate() error {
+	if p.ProxyType != "connect-proxy" {
+		return fmt.Errorf("unexpected ProxyType %q", p.ProxyType)
+	}
+	return nil
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (p *preserveCase) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == p.ProxyType
+}
+
+// PatchRoute does nothing.
+func (p *preserveCase) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster preserves the header case of the HTTP/1.1 connections of the
+// cluster of the upstream service, or of the local application.
+func (p *preserveCase) PatchCluster(config *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() && c.Name != xdscommon.LocalAppClusterName {
+		return c, false, nil
+	}
+
+	options := &envoy_upstreams_v3.HttpProtocolOptions{}
+	if typedConfig, ok := c.TypedExtensionProtocolOptions[httpProtocolOptionsName]; ok {
+		if err := typedConfig.UnmarshalTo(options); err != nil {
+			return c, false, fmt.Errorf("error unmarshalling http protocol options: %w", err)
+		}
+	}
+
+	var http1 *envoy_core_v3.Http1ProtocolOptions
+	switch cfg := options.UpstreamProtocolOptions.(type) {
+	case nil:
+		http1 = &envoy_core_v3.Http1ProtocolOptions{}
+		options.UpstreamProtocolOptions = &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
+					HttpProtocolOptions: http1,
+				},
+			},
+		}
+	case *envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_:
+		http1 = cfg.ExplicitHttpConfig.GetHttpProtocolOptions()
+		if http1 == nil {
+			// The cluster doesn't use HTTP/1.1.
+			return c, false, nil
+		}
+	default:
+		return c, false, nil
+	}
+
+	formatter, err := p.headerKeyFormat()
+	if err != nil {
+		return c, false, err
+	}
+	http1.HeaderKeyFormat = formatter
+
+	typedConfig, err := anypb.New(options)
+	if err != nil {
+		return c, false, err
+	}
+	if c.TypedExtensionProtocolOptions == nil {
+		c.TypedExtensionProtocolOptions = make(map[string]*anypb.Any)
+	}
+	c.TypedExtensionProtocolOptions[httpProtocolOptionsName] = typedConfig
+
+	return c, true, nil
+}
+
+// PatchFilter preserves the header case of the HTTP/1.1 connections of the
+// envoy.filters.network.http_connection_manager filters.
+func (p *preserveCase) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	formatter, err := p.headerKeyFormat()
+	if err != nil {
+		return filter, false, err
+	}
+	if config.HttpProtocolOptions == nil {
+		config.HttpProtocolOptions = &envoy_core_v3.Http1ProtocolOptions{}
+	}
+	config.HttpProtocolOptions.HeaderKeyFormat = formatter
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+func (p *preserveCase) headerKeyFormat() (*envoy_core_v3.Http1ProtocolOptions_HeaderKeyFormat, error) {
+	typedConfig, err := anypb.New(&envoy_preserve_case_v3.PreserveCaseFormatterConfig{
+		ForwardReasonPhrase: p.ForwardReasonPhrase,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_core_v3.Http1ProtocolOptions_HeaderKeyFormat{
+		HeaderFormat: &envoy_core_v3.Http1ProtocolOptions_HeaderKeyFormat_StatefulFormatter{
+			StatefulFormatter: &envoy_core_v3.TypedExtensionConfig{
+				Name:        preserveCaseFormatterName,
+				TypedConfig: typedConfig,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,169 @@
+package preservecase
+
+import (
+	"testing"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_preserve_case_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/header_formatters/preserve_case/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       preserveCase
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{
+				"ProxyType":           "connect-proxy",
+				"ForwardReasonPhrase": true,
+			},
+			expected: preserveCase{
+				ProxyType:           "connect-proxy",
+				ForwardReasonPhrase: true,
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinPreserveCaseExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	p := &preserveCase{ProxyType: "connect-proxy", ForwardReasonPhrase: true}
+
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		StatPrefix: "public_listener",
+	})
+	require.NoError(t, err)
+
+	patched, ok, err := p.PatchFilter(nil, filter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+	require.NotNil(t, config)
+	require.Equal(t, "public_listener", config.StatPrefix)
+	requirePreserveCase(t, config.HttpProtocolOptions, true)
+}
+
+func TestPatchCluster(t *testing.T) {
+	p := &preserveCase{ProxyType: "connect-proxy"}
+
+	svc := api.CompoundServiceName{Name: "svc"}
+	inbound := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}
+	upstream := &extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}
+
+	t.Run("local app", func(t *testing.T) {
+		c, ok, err := p.PatchCluster(inbound, &envoy_cluster_v3.Cluster{Name: xdscommon.LocalAppClusterName})
+		require.NoError(t, err)
+		require.True(t, ok)
+		requirePreserveCase(t, http1Options(t, c), false)
+	})
+
+	t.Run("other clusters of the inbound service", func(t *testing.T) {
+		_, ok, err := p.PatchCluster(inbound, &envoy_cluster_v3.Cluster{Name: "db.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("upstream", func(t *testing.T) {
+		c, ok, err := p.PatchCluster(upstream, &envoy_cluster_v3.Cluster{Name: "svc.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		requirePreserveCase(t, http1Options(t, c), false)
+	})
+
+	t.Run("http2 upstream", func(t *testing.T) {
+		options, err := anypb.New(&envoy_upstreams_v3.HttpProtocolOptions{
+			UpstreamProtocolOptions: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+				ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+					ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+						Http2ProtocolOptions: &envoy_core_v3.Http2ProtocolOptions{},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, ok, err := p.PatchCluster(upstream, &envoy_cluster_v3.Cluster{
+			Name: "svc.default.dc1.internal.domain",
+			TypedExtensionProtocolOptions: map[string]*anypb.Any{
+				httpProtocolOptionsName: options,
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func http1Options(t *testing.T, c *envoy_cluster_v3.Cluster) *envoy_core_v3.Http1ProtocolOptions {
+	t.Helper()
+
+	options := &envoy_upstreams_v3.HttpProtocolOptions{}
+	require.NoError(t, c.TypedExtensionProtocolOptions[httpProtocolOptionsName].UnmarshalTo(options))
+	return options.GetExplicitHttpConfig().GetHttpProtocolOptions()
+}
+
+func requirePreserveCase(t *testing.T, options *envoy_core_v3.Http1ProtocolOptions, forwardReasonPhrase bool) {
+	t.Helper()
+
+	formatter := options.GetHeaderKeyFormat().GetStatefulFormatter()
+	require.NotNil(t, formatter)
+	require.Equal(t, preserveCaseFormatterName, formatter.Name)
+
+	config := &envoy_preserve_case_v3.PreserveCaseFormatterConfig{}
+	require.NoError(t, formatter.TypedConfig.UnmarshalTo(config))
+	require.Equal(t, forwardReasonPhrase, config.ForwardReasonPhrase)
+}
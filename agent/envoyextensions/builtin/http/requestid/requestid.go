@@ -0,0 +1,291 @@
+package requestid
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_uuid_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/request_id/uuid/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+	routerFilterName                = "envoy.filters.http.router"
+)
+
+// requestID controls how the inbound listener of a proxy, or the listeners of
+// an API gateway, handle the x-request-id header of the requests they receive,
+// and sanitizes the headers that are internal to the mesh before they are
+// forwarded.
+type requestID struct {
+	ProxyType string
+
+	// GenerateRequestID controls whether Envoy generates an x-request-id for
+	// the requests that don't have one. Envoy generates them by default.
+	GenerateRequestID *bool
+
+	// PreserveExternalRequestID keeps the x-request-id of the requests from
+	// external clients instead of replacing it.
+	PreserveExternalRequestID bool
+
+	// AlwaysSetRequestIDInResponse sets the x-request-id of the request in
+	// the response, even if the request isn't traced.
+	AlwaysSetRequestIDInResponse bool
+
+	// UUID configures the UUID request ID extension that generates and
+	// parses the x-request-id.
+	UUID *uuidConfig
+
+	// SuppressEnvoyHeaders stops Envoy from adding the x-envoy-* headers to
+	// the requests it forwards and the responses it returns.
+	SuppressEnvoyHeaders bool
+
+	// RemoveRequestHeaders are the names of the request headers that are
+	// removed before the requests are forwarded.
+	RemoveRequestHeaders []string
+
+	// RenameRequestHeaders maps the names of request headers to the names
+	// they are forwarded under.
+	RenameRequestHeaders map[string]string
+}
+
+type uuidConfig struct {
+	// PackTraceReason controls whether the tracing decision is packed into
+	// the generated UUIDs. It defaults to true.
+	PackTraceReason *bool
+
+	// UseRequestIDForTraceSampling controls whether the x-request-id is used
+	// for trace sampling. It defaults to true.
+	UseRequestIDForTraceSampling *bool
+}
+
+var _ extensioncommon.BasicExtension = (*requestID)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var r requestID
+	if name := ext.Name; name != api.BuiltinRequestIDExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinRequestIDExtension, name)
+	}
+
+	if err := r.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &r,
+	}, nil
+}
+
+func (r *requestID) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, r); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return r.validate()
+}
+
+func (r *requestID) validate() error {
+	var resultErr error
+
+	switch api.ServiceKind(r.ProxyType) {
+	case api.ServiceKindConnectProxy, api.ServiceKindAPIGateway:
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", r.ProxyType))
+	}
+
+	removed := make(map[string]struct{}, len(r.RemoveRequestHeaders))
+	for _, name := range r.RemoveRequestHeaders {
+		if err := validateHeaderName(name); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("RemoveRequestHeaders: %w", err))
+		}
+		removed[strings.ToLower(name)] = struct{}{}
+	}
+
+	for from, to := range r.RenameRequestHeaders {
+		if err := validateHeaderName(from); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("RenameRequestHeaders: %w", err))
+		}
+		if err := validateHeaderName(to); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("RenameRequestHeaders[%q]: %w", from, err))
+		}
+		if strings.EqualFold(from, to) {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("RenameRequestHeaders[%q]: cannot rename a header to itself", from))
+		}
+		if _, ok := removed[strings.ToLower(to)]; ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("RenameRequestHeaders[%q]: %q is also in RemoveRequestHeaders", from, to))
+		}
+	}
+
+	return resultErr
+}
+
+func validateHeaderName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("header name cannot be empty")
+	case strings.HasPrefix(name, ":"):
+		return fmt.Errorf("pseudo-header %q cannot be changed", name)
+	case strings.EqualFold(name, "host"):
+		return errors.New(`header "host" cannot be changed`)
+	}
+	return nil
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (r *requestID) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	// The request ID is set and the headers are sanitized where the requests
+	// enter the proxy, on the inbound listener of the local service or the
+	// listeners of the gateway.
+	return string(config.Kind) == r.ProxyType && !config.IsUpstream()
+}
+
+// PatchRoute sanitizes the request headers of the routes of the listeners of
+// API gateways.
+func (r *requestID) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	if !r.sanitizesHeaders() {
+		return route, false, nil
+	}
+	r.sanitizeRequestHeaders(route)
+	return route, true, nil
+}
+
+// PatchCluster does nothing.
+func (r *requestID) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter configures the request ID and the header sanitization of the
+// envoy.filters.network.http_connection_manager filters.
+func (r *requestID) PatchFilter(cfg *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	if r.GenerateRequestID != nil {
+		config.GenerateRequestId = &wrappers.BoolValue{Value: *r.GenerateRequestID}
+	}
+	config.PreserveExternalRequestId = r.PreserveExternalRequestID
+	config.AlwaysSetRequestIdInResponse = r.AlwaysSetRequestIDInResponse
+
+	if r.UUID != nil {
+		uuid := &envoy_uuid_v3.UuidRequestIdConfig{}
+		if r.UUID.PackTraceReason != nil {
+			uuid.PackTraceReason = &wrappers.BoolValue{Value: *r.UUID.PackTraceReason}
+		}
+		if r.UUID.UseRequestIDForTraceSampling != nil {
+			uuid.UseRequestIdForTraceSampling = &wrappers.BoolValue{Value: *r.UUID.UseRequestIDForTraceSampling}
+		}
+		typedConfig, err := anypb.New(uuid)
+		if err != nil {
+			return filter, false, err
+		}
+		config.RequestIdExtension = &envoy_http_v3.RequestIDExtension{TypedConfig: typedConfig}
+	}
+
+	if r.SuppressEnvoyHeaders {
+		if err := suppressEnvoyHeaders(config); err != nil {
+			return filter, false, err
+		}
+	}
+
+	if r.sanitizesHeaders() {
+		// The listeners of API gateways get their routes through RDS, which
+		// are sanitized by PatchRoute.
+		if routeConfig := config.GetRouteConfig(); routeConfig != nil {
+			r.sanitizeRequestHeaders(routeConfig)
+		} else if cfg.Kind != api.ServiceKindAPIGateway {
+			return filter, false, errors.New("headers can only be sanitized with an inline route configuration")
+		}
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+// suppressEnvoyHeaders stops the router filter of the http connection
+// manager from adding the x-envoy-* headers.
+func suppressEnvoyHeaders(config *envoy_http_v3.HttpConnectionManager) error {
+	for i, httpFilter := range config.HttpFilters {
+		if httpFilter.Name != routerFilterName {
+			continue
+		}
+
+		router := &envoy_router_v3.Router{}
+		if typedConfig := httpFilter.GetTypedConfig(); typedConfig != nil {
+			if err := typedConfig.UnmarshalTo(router); err != nil {
+				return fmt.Errorf("error unmarshalling router filter: %w", err)
+			}
+		}
+		router.SuppressEnvoyHeaders = true
+
+		newHTTPFilter, err := extensioncommon.MakeEnvoyHTTPFilter(routerFilterName, router)
+		if err != nil {
+			return err
+		}
+		config.HttpFilters[i] = newHTTPFilter
+		return nil
+	}
+	return errors.New("no router filter found")
+}
+
+func (r *requestID) sanitizesHeaders() bool {
+	return len(r.RemoveRequestHeaders) > 0 || len(r.RenameRequestHeaders) > 0
+}
+
+// sanitizeRequestHeaders removes and renames the request headers in the given
+// route configuration.
+//
+// Envoy evaluates the request headers to add of the virtual hosts before the
+// headers to remove of the route configuration, so a header is renamed by
+// copying it at the virtual host level and removing it at the route
+// configuration level.
+func (r *requestID) sanitizeRequestHeaders(routeConfig *envoy_route_v3.RouteConfiguration) {
+	// Sort the renamed headers so that the generated configuration is stable.
+	renamed := make([]string, 0, len(r.RenameRequestHeaders))
+	for from := range r.RenameRequestHeaders {
+		renamed = append(renamed, from)
+	}
+	sort.Strings(renamed)
+
+	for _, vh := range routeConfig.VirtualHosts {
+		for _, from := range renamed {
+			vh.RequestHeadersToAdd = append(vh.RequestHeadersToAdd, &envoy_core_v3.HeaderValueOption{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   r.RenameRequestHeaders[from],
+					Value: "%REQ(" + from + ")%",
+				},
+				Append: &wrappers.BoolValue{Value: false},
+			})
+		}
+	}
+
+	routeConfig.RequestHeadersToRemove = append(routeConfig.RequestHeadersToRemove, r.RemoveRequestHeaders...)
+	routeConfig.RequestHeadersToRemove = append(routeConfig.RequestHeadersToRemove, renamed...)
+}
@@ -0,0 +1,301 @@
+package requestid
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_uuid_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/request_id/uuid/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	makeArguments := func(overrides map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"ProxyType": "connect-proxy",
+		}
+
+		for k, v := range overrides {
+			m[k] = v
+		}
+
+		return m
+	}
+
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       requestID
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     makeArguments(map[string]interface{}{}),
+			extensionName: "bad",
+			ok:            false,
+		},
+		"removing a pseudo-header": {
+			arguments: makeArguments(map[string]interface{}{
+				"RemoveRequestHeaders": []string{":path"},
+			}),
+			expectedErrMsg: `RemoveRequestHeaders: pseudo-header ":path" cannot be changed`,
+			ok:             false,
+		},
+		"renaming a header to itself": {
+			arguments: makeArguments(map[string]interface{}{
+				"RenameRequestHeaders": map[string]string{"x-envoy-foo": "X-Envoy-Foo"},
+			}),
+			expectedErrMsg: `RenameRequestHeaders["x-envoy-foo"]: cannot rename a header to itself`,
+			ok:             false,
+		},
+		"renaming a header to a removed header": {
+			arguments: makeArguments(map[string]interface{}{
+				"RemoveRequestHeaders": []string{"x-internal"},
+				"RenameRequestHeaders": map[string]string{"x-envoy-foo": "x-internal"},
+			}),
+			expectedErrMsg: `RenameRequestHeaders["x-envoy-foo"]: "x-internal" is also in RemoveRequestHeaders`,
+			ok:             false,
+		},
+		"api gateway": {
+			arguments: makeArguments(map[string]interface{}{
+				"ProxyType":            "api-gateway",
+				"RemoveRequestHeaders": []string{"x-envoy-original-path"},
+			}),
+			expected: requestID{
+				ProxyType:            "api-gateway",
+				RemoveRequestHeaders: []string{"x-envoy-original-path"},
+			},
+			ok: true,
+		},
+		"valid everything": {
+			arguments: makeArguments(map[string]interface{}{
+				"GenerateRequestID":         false,
+				"PreserveExternalRequestID": true,
+				"UUID": map[string]interface{}{
+					"PackTraceReason": false,
+				},
+				"SuppressEnvoyHeaders": true,
+				"RemoveRequestHeaders": []string{"x-envoy-original-path"},
+				"RenameRequestHeaders": map[string]string{"x-envoy-upstream-rq-timeout-ms": "x-timeout-ms"},
+			}),
+			expected: requestID{
+				ProxyType:                 "connect-proxy",
+				GenerateRequestID:         boolPointer(false),
+				PreserveExternalRequestID: true,
+				UUID: &uuidConfig{
+					PackTraceReason: boolPointer(false),
+				},
+				SuppressEnvoyHeaders: true,
+				RemoveRequestHeaders: []string{"x-envoy-original-path"},
+				RenameRequestHeaders: map[string]string{"x-envoy-upstream-rq-timeout-ms": "x-timeout-ms"},
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+
+			extensionName := api.BuiltinRequestIDExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			svc := api.CompoundServiceName{Name: "svc"}
+			ext := extensioncommon.RuntimeConfig{
+				ServiceName: svc,
+				EnvoyExtension: api.EnvoyExtension{
+					Name:      extensionName,
+					Arguments: tc.arguments,
+				},
+			}
+
+			e, err := Constructor(ext.EnvoyExtension)
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	router, err := extensioncommon.MakeEnvoyHTTPFilter(routerFilterName, &envoy_router_v3.Router{})
+	require.NoError(t, err)
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		HttpFilters: []*envoy_http_v3.HttpFilter{router},
+		RouteSpecifier: &envoy_http_v3.HttpConnectionManager_RouteConfig{
+			RouteConfig: &envoy_route_v3.RouteConfiguration{
+				VirtualHosts: []*envoy_route_v3.VirtualHost{
+					{Name: "public_listener", Domains: []string{"*"}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	r := &requestID{
+		ProxyType:                 "connect-proxy",
+		GenerateRequestID:         boolPointer(false),
+		PreserveExternalRequestID: true,
+		UUID: &uuidConfig{
+			UseRequestIDForTraceSampling: boolPointer(false),
+		},
+		SuppressEnvoyHeaders: true,
+		RemoveRequestHeaders: []string{"x-envoy-original-path"},
+		RenameRequestHeaders: map[string]string{
+			"x-envoy-upstream-rq-timeout-ms": "x-timeout-ms",
+			"x-envoy-attempt-count":          "x-attempt-count",
+		},
+	}
+	require.NoError(t, r.validate())
+
+	patched, ok, err := r.PatchFilter(&extensioncommon.RuntimeConfig{Kind: api.ServiceKindConnectProxy}, filter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+	require.NotNil(t, config)
+	require.False(t, config.GenerateRequestId.GetValue())
+	require.True(t, config.PreserveExternalRequestId)
+
+	uuid := &envoy_uuid_v3.UuidRequestIdConfig{}
+	require.NoError(t, config.RequestIdExtension.TypedConfig.UnmarshalTo(uuid))
+	require.Nil(t, uuid.PackTraceReason)
+	require.False(t, uuid.UseRequestIdForTraceSampling.GetValue())
+
+	patchedRouter := &envoy_router_v3.Router{}
+	require.NoError(t, config.HttpFilters[0].GetTypedConfig().UnmarshalTo(patchedRouter))
+	require.True(t, patchedRouter.SuppressEnvoyHeaders)
+
+	routeConfig := config.GetRouteConfig()
+	require.Equal(t, []string{
+		"x-envoy-original-path",
+		"x-envoy-attempt-count",
+		"x-envoy-upstream-rq-timeout-ms",
+	}, routeConfig.RequestHeadersToRemove)
+	require.Equal(t, []*envoy_core_v3.HeaderValueOption{
+		{
+			Header: &envoy_core_v3.HeaderValue{Key: "x-attempt-count", Value: "%REQ(x-envoy-attempt-count)%"},
+			Append: &wrappers.BoolValue{Value: false},
+		},
+		{
+			Header: &envoy_core_v3.HeaderValue{Key: "x-timeout-ms", Value: "%REQ(x-envoy-upstream-rq-timeout-ms)%"},
+			Append: &wrappers.BoolValue{Value: false},
+		},
+	}, routeConfig.VirtualHosts[0].RequestHeadersToAdd)
+}
+
+func TestPatchFilter_RDS(t *testing.T) {
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		RouteSpecifier: &envoy_http_v3.HttpConnectionManager_Rds{
+			Rds: &envoy_http_v3.Rds{RouteConfigName: "8080"},
+		},
+	})
+	require.NoError(t, err)
+
+	r := &requestID{
+		ProxyType:                 "api-gateway",
+		PreserveExternalRequestID: true,
+		RemoveRequestHeaders:      []string{"x-envoy-original-path"},
+	}
+
+	t.Run("api gateway", func(t *testing.T) {
+		patched, ok, err := r.PatchFilter(&extensioncommon.RuntimeConfig{Kind: api.ServiceKindAPIGateway}, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.NotNil(t, config)
+		require.True(t, config.PreserveExternalRequestId)
+		require.Equal(t, "8080", config.GetRds().GetRouteConfigName())
+	})
+
+	t.Run("connect proxy", func(t *testing.T) {
+		_, _, err := r.PatchFilter(&extensioncommon.RuntimeConfig{Kind: api.ServiceKindConnectProxy}, filter)
+		require.EqualError(t, err, "headers can only be sanitized with an inline route configuration")
+	})
+}
+
+func TestPatchRoute(t *testing.T) {
+	route := &envoy_route_v3.RouteConfiguration{
+		Name: "8080",
+		VirtualHosts: []*envoy_route_v3.VirtualHost{
+			{Name: "api-gateway-http", Domains: []string{"*"}},
+		},
+	}
+
+	t.Run("no sanitized headers", func(t *testing.T) {
+		r := &requestID{ProxyType: "api-gateway"}
+
+		_, ok, err := r.PatchRoute(nil, route)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("sanitized headers", func(t *testing.T) {
+		r := &requestID{
+			ProxyType:            "api-gateway",
+			RemoveRequestHeaders: []string{"x-envoy-original-path"},
+			RenameRequestHeaders: map[string]string{"x-envoy-attempt-count": "x-attempt-count"},
+		}
+
+		patched, ok, err := r.PatchRoute(nil, route)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []string{"x-envoy-original-path", "x-envoy-attempt-count"}, patched.RequestHeadersToRemove)
+		require.Equal(t, []*envoy_core_v3.HeaderValueOption{
+			{
+				Header: &envoy_core_v3.HeaderValue{Key: "x-attempt-count", Value: "%REQ(x-envoy-attempt-count)%"},
+				Append: &wrappers.BoolValue{Value: false},
+			},
+		}, patched.VirtualHosts[0].RequestHeadersToAdd)
+	})
+}
+
+func TestCanApply(t *testing.T) {
+	r := &requestID{ProxyType: "connect-proxy"}
+	svc := api.CompoundServiceName{Name: "svc"}
+
+	require.True(t, r.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}))
+	require.False(t, r.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}))
+	require.False(t, r.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindTerminatingGateway,
+		ServiceName: svc,
+	}))
+
+	r = &requestID{ProxyType: "api-gateway"}
+	require.True(t, r.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindAPIGateway,
+		ServiceName: api.CompoundServiceName{Name: "api-gateway"},
+	}))
+}
+
+func boolPointer(b bool) *bool {
+	return &b
+}
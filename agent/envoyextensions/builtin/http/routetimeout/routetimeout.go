@@ -0,0 +1,224 @@
+package routetimeout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+
+// routeTimeout sets the timeouts of specific routes of a service.
+//
+// Configured for an upstream service, it applies to the routes to the service
+// on the proxies of its downstreams. Configured for the local service, it
+// applies to the routes of the inbound listener.
+type routeTimeout struct {
+	ProxyType string
+
+	// Routes are the timeouts of the routes.
+	Routes []route
+}
+
+type route struct {
+	// PathPrefix selects the routes that only match this path or the
+	// paths below it. Whole path segments are matched, so /slow doesn't
+	// select the routes for /slowness. The routes are selected regardless
+	// of their path if it is empty.
+	PathPrefix string
+
+	// RequestTimeoutMs is the timeout for the whole response to be
+	// received, in milliseconds. A value of 0 disables it.
+	RequestTimeoutMs *int
+
+	// IdleTimeoutMs is the idle timeout of the streams, in milliseconds.
+	// A value of 0 disables it.
+	IdleTimeoutMs *int
+
+	// MaxStreamDurationMs is the maximum duration of the streams, in
+	// milliseconds, including the long-lived ones such as gRPC streams.
+	// A value of 0 disables it.
+	MaxStreamDurationMs *int
+}
+
+var _ extensioncommon.BasicExtension = (*routeTimeout)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var r routeTimeout
+	if name := ext.Name; name != api.BuiltinRouteTimeoutExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinRouteTimeoutExtension, name)
+	}
+
+	if err := r.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &r,
+	}, nil
+}
+
+func (r *routeTimeout) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, r); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return r.validate()
+}
+
+func (r *routeTimeout) validate() error {
+	var resultErr error
+
+	if r.ProxyType != "connect-proxy" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", r.ProxyType))
+	}
+
+	if len(r.Routes) == 0 {
+		resultErr = multierror.Append(resultErr, errors.New("Routes is missing"))
+	}
+
+	for i, rt := range r.Routes {
+		if rt.PathPrefix != "" && !strings.HasPrefix(rt.PathPrefix, "/") {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: PathPrefix %q must begin with a '/'", i, rt.PathPrefix))
+		}
+		if rt.RequestTimeoutMs == nil && rt.IdleTimeoutMs == nil && rt.MaxStreamDurationMs == nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: one of RequestTimeoutMs, IdleTimeoutMs or MaxStreamDurationMs is required", i))
+		}
+		for _, timeout := range []struct {
+			name  string
+			value *int
+		}{
+			{"RequestTimeoutMs", rt.RequestTimeoutMs},
+			{"IdleTimeoutMs", rt.IdleTimeoutMs},
+			{"MaxStreamDurationMs", rt.MaxStreamDurationMs},
+		} {
+			if timeout.value != nil && *timeout.value < 0 {
+				resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: %s cannot be negative, got %d", i, timeout.name, *timeout.value))
+			}
+		}
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (r *routeTimeout) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == r.ProxyType
+}
+
+// PatchRoute sets the timeouts of the routes to the upstream service.
+func (r *routeTimeout) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, r.patchRouteConfiguration(route), nil
+}
+
+// PatchCluster does nothing.
+func (r *routeTimeout) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter sets the timeouts of the routes of the inline route
+// configuration of the envoy.filters.network.http_connection_manager filters.
+func (r *routeTimeout) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	// The routes of the filters that use RDS are patched by PatchRoute.
+	routeConfig := config.GetRouteConfig()
+	if routeConfig == nil || !r.patchRouteConfiguration(routeConfig) {
+		return filter, false, nil
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+// patchRouteConfiguration sets the timeouts of the matching routes of the
+// route configuration, and returns whether any route was patched. When
+// several entries of Routes match a route, the last one wins.
+func (r *routeTimeout) patchRouteConfiguration(routeConfig *envoy_route_v3.RouteConfiguration) bool {
+	var patched bool
+	for _, vh := range routeConfig.VirtualHosts {
+		r.splitRoutes(vh)
+		for _, envoyRoute := range vh.Routes {
+			action := envoyRoute.GetRoute()
+			if action == nil {
+				continue
+			}
+			for _, rt := range r.Routes {
+				if !extensioncommon.RouteMatchesPathPrefix(envoyRoute, rt.PathPrefix) {
+					continue
+				}
+				rt.patchRouteAction(action)
+				patched = true
+			}
+		}
+	}
+	return patched
+}
+
+// splitRoutes inserts, before each prefix route whose prefix is the path
+// prefix of one of Routes, copies of the route that only match the path
+// prefix and the paths below it. The timeouts are set on the copies, so that
+// a /slow prefix route still serves /slowness with its own timeouts.
+func (r *routeTimeout) splitRoutes(vh *envoy_route_v3.VirtualHost) {
+	prefixes := make(map[string]bool)
+	for _, rt := range r.Routes {
+		if rt.PathPrefix != "" {
+			prefixes[rt.PathPrefix] = true
+		}
+	}
+
+	var routes []*envoy_route_v3.Route
+	for _, route := range vh.Routes {
+		prefix := route.GetMatch().GetPrefix()
+		if prefixes[prefix] && !extensioncommon.RouteMatchesPathPrefix(route, prefix) {
+			routes = append(routes, extensioncommon.PathPrefixRoutes(route, prefix)...)
+		}
+		routes = append(routes, route)
+	}
+	vh.Routes = routes
+}
+
+func (rt route) patchRouteAction(action *envoy_route_v3.RouteAction) {
+	if rt.RequestTimeoutMs != nil {
+		action.Timeout = millisecondsToDuration(*rt.RequestTimeoutMs)
+	}
+	if rt.IdleTimeoutMs != nil {
+		action.IdleTimeout = millisecondsToDuration(*rt.IdleTimeoutMs)
+	}
+	if rt.MaxStreamDurationMs != nil {
+		if action.MaxStreamDuration == nil {
+			action.MaxStreamDuration = &envoy_route_v3.RouteAction_MaxStreamDuration{}
+		}
+		action.MaxStreamDuration.MaxStreamDuration = millisecondsToDuration(*rt.MaxStreamDurationMs)
+	}
+}
+
+func millisecondsToDuration(ms int) *durationpb.Duration {
+	return durationpb.New(time.Duration(ms) * time.Millisecond)
+}
@@ -0,0 +1,277 @@
+package routetimeout
+
+import (
+	"testing"
+	"time"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	makeArguments := func(overrides map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"ProxyType": "connect-proxy",
+			"Routes": []map[string]interface{}{
+				{"PathPrefix": "/slow", "RequestTimeoutMs": 30000},
+			},
+		}
+
+		for k, v := range overrides {
+			m[k] = v
+		}
+
+		return m
+	}
+
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       routeTimeout
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     makeArguments(map[string]interface{}{}),
+			extensionName: "bad",
+			ok:            false,
+		},
+		"without routes": {
+			arguments:      makeArguments(map[string]interface{}{"Routes": nil}),
+			expectedErrMsg: "Routes is missing",
+			ok:             false,
+		},
+		"route without timeouts": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{{"PathPrefix": "/slow"}},
+			}),
+			expectedErrMsg: "Routes[0]: one of RequestTimeoutMs, IdleTimeoutMs or MaxStreamDurationMs is required",
+			ok:             false,
+		},
+		"route with an invalid prefix": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{{"PathPrefix": "slow", "IdleTimeoutMs": 1000}},
+			}),
+			expectedErrMsg: `Routes[0]: PathPrefix "slow" must begin with a '/'`,
+			ok:             false,
+		},
+		"route with a negative timeout": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{{"MaxStreamDurationMs": -1}},
+			}),
+			expectedErrMsg: "Routes[0]: MaxStreamDurationMs cannot be negative, got -1",
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{
+					{"RequestTimeoutMs": 5000},
+					{"PathPrefix": "/stream", "RequestTimeoutMs": 0, "IdleTimeoutMs": 60000, "MaxStreamDurationMs": 3600000},
+				},
+			}),
+			expected: routeTimeout{
+				ProxyType: "connect-proxy",
+				Routes: []route{
+					{RequestTimeoutMs: intPointer(5000)},
+					{
+						PathPrefix:          "/stream",
+						RequestTimeoutMs:    intPointer(0),
+						IdleTimeoutMs:       intPointer(60000),
+						MaxStreamDurationMs: intPointer(3600000),
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinRouteTimeoutExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchRoute(t *testing.T) {
+	r := &routeTimeout{
+		ProxyType: "connect-proxy",
+		Routes: []route{
+			{RequestTimeoutMs: intPointer(5000)},
+			{PathPrefix: "/stream", RequestTimeoutMs: intPointer(0), IdleTimeoutMs: intPointer(60000), MaxStreamDurationMs: intPointer(3600000)},
+		},
+	}
+
+	patched, ok, err := r.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.True(t, ok)
+	requireTimeouts(t, patched)
+
+	r.Routes = []route{{PathPrefix: "/other", RequestTimeoutMs: intPointer(5000)}}
+	_, ok, err = r.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestPatchRoute_SiblingPath(t *testing.T) {
+	r := &routeTimeout{
+		ProxyType: "connect-proxy",
+		Routes:    []route{{PathPrefix: "/slow", RequestTimeoutMs: intPointer(30000)}},
+	}
+
+	prefixRoute := func(prefix string) *envoy_route_v3.Route {
+		return &envoy_route_v3.Route{
+			Match: &envoy_route_v3.RouteMatch{
+				PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: prefix},
+			},
+			Action: &envoy_route_v3.Route_Route{Route: &envoy_route_v3.RouteAction{
+				ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{Cluster: "db"},
+			}},
+		}
+	}
+	routeConfig := &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{
+			{
+				Name:    "db",
+				Domains: []string{"*"},
+				Routes:  []*envoy_route_v3.Route{prefixRoute("/slowness"), prefixRoute("/slow")},
+			},
+		},
+	}
+
+	patched, ok, err := r.PatchRoute(nil, routeConfig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The /slow prefix route also serves /slowness, so the timeout is set
+	// on copies of it that only match /slow and the paths below it.
+	routes := patched.VirtualHosts[0].Routes
+	require.Len(t, routes, 4)
+
+	require.Equal(t, "/slowness", routes[0].GetMatch().GetPrefix())
+	require.Nil(t, routes[0].GetRoute().Timeout)
+
+	require.Equal(t, "/slow", routes[1].GetMatch().GetPath())
+	require.Equal(t, 30*time.Second, routes[1].GetRoute().Timeout.AsDuration())
+	require.Equal(t, "/slow/", routes[2].GetMatch().GetPrefix())
+	require.Equal(t, 30*time.Second, routes[2].GetRoute().Timeout.AsDuration())
+
+	require.Equal(t, "/slow", routes[3].GetMatch().GetPrefix())
+	require.Nil(t, routes[3].GetRoute().Timeout)
+}
+
+func TestPatchFilter(t *testing.T) {
+	r := &routeTimeout{
+		ProxyType: "connect-proxy",
+		Routes: []route{
+			{RequestTimeoutMs: intPointer(5000)},
+			{PathPrefix: "/stream", RequestTimeoutMs: intPointer(0), IdleTimeoutMs: intPointer(60000), MaxStreamDurationMs: intPointer(3600000)},
+		},
+	}
+
+	t.Run("inline route configuration", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+			RouteSpecifier: &envoy_http_v3.HttpConnectionManager_RouteConfig{
+				RouteConfig: testRouteConfiguration(),
+			},
+		})
+		require.NoError(t, err)
+
+		patched, ok, err := r.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+		requireTimeouts(t, envoy_resource_v3.GetHTTPConnectionManager(patched).GetRouteConfig())
+	})
+
+	t.Run("RDS", func(t *testing.T) {
+		filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+			RouteSpecifier: &envoy_http_v3.HttpConnectionManager_Rds{
+				Rds: &envoy_http_v3.Rds{RouteConfigName: "db"},
+			},
+		})
+		require.NoError(t, err)
+
+		_, ok, err := r.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func testRouteConfiguration() *envoy_route_v3.RouteConfiguration {
+	routeAction := func() *envoy_route_v3.Route_Route {
+		return &envoy_route_v3.Route_Route{
+			Route: &envoy_route_v3.RouteAction{
+				ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{Cluster: "db"},
+			},
+		}
+	}
+
+	return &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{
+			{
+				Name:    "db",
+				Domains: []string{"*"},
+				Routes: []*envoy_route_v3.Route{
+					{
+						Match: &envoy_route_v3.RouteMatch{
+							PathSpecifier: &envoy_route_v3.RouteMatch_Path{Path: "/stream/events"},
+						},
+						Action: routeAction(),
+					},
+					{
+						Match: &envoy_route_v3.RouteMatch{
+							PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"},
+						},
+						Action: routeAction(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func requireTimeouts(t *testing.T, routeConfig *envoy_route_v3.RouteConfiguration) {
+	t.Helper()
+
+	routes := routeConfig.VirtualHosts[0].Routes
+	require.Len(t, routes, 2)
+
+	stream := routes[0].GetRoute()
+	require.Equal(t, time.Duration(0), stream.Timeout.AsDuration())
+	require.Equal(t, time.Minute, stream.IdleTimeout.AsDuration())
+	require.Equal(t, time.Hour, stream.MaxStreamDuration.MaxStreamDuration.AsDuration())
+
+	other := routes[1].GetRoute()
+	require.Equal(t, 5*time.Second, other.Timeout.AsDuration())
+	require.Nil(t, other.IdleTimeout)
+	require.Nil(t, other.MaxStreamDuration)
+}
+
+func intPointer(i int) *int {
+	return &i
+}
@@ -0,0 +1,190 @@
+package strictheaders
+
+import (
+	"errors"
+	"fmt"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+
+// strictHeaders makes the inbound listener of a proxy, or the listeners of an
+// API gateway, enforce a stricter, RFC-compliant parsing of the requests they
+// receive.
+//
+// The envoy.http.header_validators.envoy_default universal header validator
+// is not available in the Envoy API version Consul is built against, so this
+// extension relies on the equivalent options of the http connection manager.
+type strictHeaders struct {
+	ProxyType string
+
+	// NormalizePath normalizes the path of the requests according to RFC
+	// 3986 before they are routed or authorized.
+	NormalizePath bool
+
+	// MergeSlashes merges the adjacent slashes in the path of the requests.
+	MergeSlashes bool
+
+	// PathWithEscapedSlashesAction is the action to take on the requests
+	// whose path contains escaped slashes: KEEP_UNCHANGED, REJECT_REQUEST,
+	// UNESCAPE_AND_REDIRECT or UNESCAPE_AND_FORWARD.
+	PathWithEscapedSlashesAction string
+
+	// HeadersWithUnderscoresAction is the action to take on the requests
+	// with header names that contain underscores: ALLOW, REJECT_REQUEST or
+	// DROP_HEADER.
+	HeadersWithUnderscoresAction string
+
+	// MaxRequestHeadersKB is the maximum size of the request headers, in
+	// kilobytes. Envoy uses 60 KiB by default.
+	MaxRequestHeadersKB uint32
+
+	// MaxHeadersCount is the maximum number of headers of a request.
+	// Envoy allows 100 headers by default.
+	MaxHeadersCount uint32
+
+	// StreamErrorOnInvalidHTTPMessage only resets the stream of an invalid
+	// HTTP/1.1 request instead of closing its connection.
+	StreamErrorOnInvalidHTTPMessage bool
+}
+
+var _ extensioncommon.BasicExtension = (*strictHeaders)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var s strictHeaders
+	if name := ext.Name; name != api.BuiltinStrictHeadersExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinStrictHeadersExtension, name)
+	}
+
+	if err := s.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &s,
+	}, nil
+}
+
+func (s *strictHeaders) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, s); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return s.validate()
+}
+
+func (s *strictHeaders) validate() error {
+	var resultErr error
+
+	switch api.ServiceKind(s.ProxyType) {
+	case api.ServiceKindConnectProxy, api.ServiceKindAPIGateway:
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", s.ProxyType))
+	}
+
+	if s.PathWithEscapedSlashesAction != "" {
+		if _, ok := envoy_http_v3.HttpConnectionManager_PathWithEscapedSlashesAction_value[s.PathWithEscapedSlashesAction]; !ok ||
+			s.PathWithEscapedSlashesAction == envoy_http_v3.HttpConnectionManager_IMPLEMENTATION_SPECIFIC_DEFAULT.String() {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected PathWithEscapedSlashesAction %q", s.PathWithEscapedSlashesAction))
+		}
+	}
+
+	if s.HeadersWithUnderscoresAction != "" {
+		if _, ok := envoy_core_v3.HttpProtocolOptions_HeadersWithUnderscoresAction_value[s.HeadersWithUnderscoresAction]; !ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected HeadersWithUnderscoresAction %q", s.HeadersWithUnderscoresAction))
+		}
+	}
+
+	// Envoy rejects the limits above 8192 KiB.
+	if s.MaxRequestHeadersKB > 8192 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("MaxRequestHeadersKB must be at most 8192, got %d", s.MaxRequestHeadersKB))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (s *strictHeaders) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	// The requests are parsed where they enter the proxy, on the inbound
+	// listener of the local service or the listeners of the gateway.
+	return string(config.Kind) == s.ProxyType && !config.IsUpstream()
+}
+
+// PatchRoute does nothing.
+func (s *strictHeaders) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster does nothing.
+func (s *strictHeaders) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter configures the request parsing of the
+// envoy.filters.network.http_connection_manager filters.
+func (s *strictHeaders) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	if s.NormalizePath {
+		config.NormalizePath = &wrappers.BoolValue{Value: true}
+	}
+	if s.MergeSlashes {
+		config.MergeSlashes = true
+	}
+	if s.PathWithEscapedSlashesAction != "" {
+		config.PathWithEscapedSlashesAction = envoy_http_v3.HttpConnectionManager_PathWithEscapedSlashesAction(
+			envoy_http_v3.HttpConnectionManager_PathWithEscapedSlashesAction_value[s.PathWithEscapedSlashesAction])
+	}
+	if s.MaxRequestHeadersKB != 0 {
+		config.MaxRequestHeadersKb = &wrappers.UInt32Value{Value: s.MaxRequestHeadersKB}
+	}
+
+	if s.HeadersWithUnderscoresAction != "" || s.MaxHeadersCount != 0 {
+		if config.CommonHttpProtocolOptions == nil {
+			config.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{}
+		}
+		if s.HeadersWithUnderscoresAction != "" {
+			config.CommonHttpProtocolOptions.HeadersWithUnderscoresAction = envoy_core_v3.HttpProtocolOptions_HeadersWithUnderscoresAction(
+				envoy_core_v3.HttpProtocolOptions_HeadersWithUnderscoresAction_value[s.HeadersWithUnderscoresAction])
+		}
+		if s.MaxHeadersCount != 0 {
+			config.CommonHttpProtocolOptions.MaxHeadersCount = &wrappers.UInt32Value{Value: s.MaxHeadersCount}
+		}
+	}
+
+	if s.StreamErrorOnInvalidHTTPMessage {
+		if config.HttpProtocolOptions == nil {
+			config.HttpProtocolOptions = &envoy_core_v3.Http1ProtocolOptions{}
+		}
+		config.HttpProtocolOptions.OverrideStreamErrorOnInvalidHttpMessage = &wrappers.BoolValue{Value: true}
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
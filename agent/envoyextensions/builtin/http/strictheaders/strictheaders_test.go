@@ -0,0 +1,210 @@
+package strictheaders
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	makeArguments := func(overrides map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"ProxyType": "connect-proxy",
+		}
+
+		for k, v := range overrides {
+			m[k] = v
+		}
+
+		return m
+	}
+
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       strictHeaders
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     makeArguments(map[string]interface{}{}),
+			extensionName: "bad",
+			ok:            false,
+		},
+		"invalid escaped slashes action": {
+			arguments: makeArguments(map[string]interface{}{
+				"PathWithEscapedSlashesAction": "IMPLEMENTATION_SPECIFIC_DEFAULT",
+			}),
+			expectedErrMsg: `unexpected PathWithEscapedSlashesAction "IMPLEMENTATION_SPECIFIC_DEFAULT"`,
+			ok:             false,
+		},
+		"invalid underscores action": {
+			arguments: makeArguments(map[string]interface{}{
+				"HeadersWithUnderscoresAction": "reject",
+			}),
+			expectedErrMsg: `unexpected HeadersWithUnderscoresAction "reject"`,
+			ok:             false,
+		},
+		"request headers too large": {
+			arguments: makeArguments(map[string]interface{}{
+				"MaxRequestHeadersKB": 8193,
+			}),
+			expectedErrMsg: "MaxRequestHeadersKB must be at most 8192, got 8193",
+			ok:             false,
+		},
+		"invalid proxy type": {
+			arguments: makeArguments(map[string]interface{}{
+				"ProxyType": "terminating-gateway",
+			}),
+			expectedErrMsg: `unexpected ProxyType "terminating-gateway"`,
+			ok:             false,
+		},
+		"api gateway": {
+			arguments: makeArguments(map[string]interface{}{
+				"ProxyType":    "api-gateway",
+				"MergeSlashes": true,
+			}),
+			expected: strictHeaders{
+				ProxyType:    "api-gateway",
+				MergeSlashes: true,
+			},
+			ok: true,
+		},
+		"valid everything": {
+			arguments: makeArguments(map[string]interface{}{
+				"NormalizePath":                   true,
+				"MergeSlashes":                    true,
+				"PathWithEscapedSlashesAction":    "REJECT_REQUEST",
+				"HeadersWithUnderscoresAction":    "DROP_HEADER",
+				"MaxRequestHeadersKB":             96,
+				"MaxHeadersCount":                 50,
+				"StreamErrorOnInvalidHTTPMessage": true,
+			}),
+			expected: strictHeaders{
+				ProxyType:                       "connect-proxy",
+				NormalizePath:                   true,
+				MergeSlashes:                    true,
+				PathWithEscapedSlashesAction:    "REJECT_REQUEST",
+				HeadersWithUnderscoresAction:    "DROP_HEADER",
+				MaxRequestHeadersKB:             96,
+				MaxHeadersCount:                 50,
+				StreamErrorOnInvalidHTTPMessage: true,
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinStrictHeadersExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		StatPrefix: "public_listener",
+	})
+	require.NoError(t, err)
+
+	t.Run("all options", func(t *testing.T) {
+		s := &strictHeaders{
+			ProxyType:                       "connect-proxy",
+			NormalizePath:                   true,
+			MergeSlashes:                    true,
+			PathWithEscapedSlashesAction:    "REJECT_REQUEST",
+			HeadersWithUnderscoresAction:    "REJECT_REQUEST",
+			MaxRequestHeadersKB:             96,
+			MaxHeadersCount:                 50,
+			StreamErrorOnInvalidHTTPMessage: true,
+		}
+
+		patched, ok, err := s.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.NotNil(t, config)
+		require.Equal(t, "public_listener", config.StatPrefix)
+		require.True(t, config.NormalizePath.GetValue())
+		require.True(t, config.MergeSlashes)
+		require.Equal(t, envoy_http_v3.HttpConnectionManager_REJECT_REQUEST, config.PathWithEscapedSlashesAction)
+		require.Equal(t, uint32(96), config.MaxRequestHeadersKb.GetValue())
+		require.Equal(t, envoy_core_v3.HttpProtocolOptions_REJECT_REQUEST, config.CommonHttpProtocolOptions.HeadersWithUnderscoresAction)
+		require.Equal(t, uint32(50), config.CommonHttpProtocolOptions.MaxHeadersCount.GetValue())
+		require.True(t, config.HttpProtocolOptions.OverrideStreamErrorOnInvalidHttpMessage.GetValue())
+	})
+
+	t.Run("no options", func(t *testing.T) {
+		s := &strictHeaders{ProxyType: "connect-proxy"}
+
+		patched, ok, err := s.PatchFilter(nil, filter)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+		require.NotNil(t, config)
+		require.Nil(t, config.NormalizePath)
+		require.Equal(t, envoy_http_v3.HttpConnectionManager_IMPLEMENTATION_SPECIFIC_DEFAULT, config.PathWithEscapedSlashesAction)
+		require.Nil(t, config.CommonHttpProtocolOptions)
+		require.Nil(t, config.HttpProtocolOptions)
+	})
+}
+
+func TestCanApply(t *testing.T) {
+	s := &strictHeaders{ProxyType: "connect-proxy"}
+	svc := api.CompoundServiceName{Name: "svc"}
+
+	require.True(t, s.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}))
+	require.False(t, s.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			svc: {},
+		},
+	}))
+}
+
+func TestCanApply_APIGateway(t *testing.T) {
+	s := &strictHeaders{ProxyType: "api-gateway"}
+	svc := api.CompoundServiceName{Name: "api-gateway"}
+
+	require.True(t, s.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindAPIGateway,
+		ServiceName: svc,
+	}))
+	require.False(t, s.CanApply(&extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: svc,
+	}))
+}
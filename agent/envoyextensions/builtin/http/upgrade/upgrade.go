@@ -0,0 +1,263 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const httpConnectionManagerFilterName = "envoy.filters.network.http_connection_manager"
+
+// upgrade allows the HTTP connections to a service to be upgraded to other
+// protocols, such as websocket or CONNECT.
+//
+// Envoy only forwards an upgrade if it is allowed by both the proxy of the
+// downstream and the proxy of the service, so the extension applies to the
+// inbound listener of the service and the outbound listeners of its
+// downstreams.
+type upgrade struct {
+	ProxyType string
+
+	// UpgradeTypes maps the case-insensitive names of the upgrades the
+	// listener allows to whether they are enabled on all routes by default.
+	// An upgrade that is disabled by default must be enabled on a route.
+	UpgradeTypes map[string]bool
+
+	// Routes overrides the upgrades of specific routes.
+	Routes []route
+}
+
+type route struct {
+	// PathPrefix selects the routes that match this path prefix. The
+	// routes are selected regardless of their path if it is empty.
+	PathPrefix string
+
+	// UpgradeTypes maps the names of upgrades to whether they are enabled
+	// on the routes. The upgrades must be in the UpgradeTypes of the
+	// listener.
+	UpgradeTypes map[string]bool
+
+	// IdleTimeout is the idle timeout of the streams of the routes, in
+	// seconds, including the upgraded ones. A value of 0 disables it.
+	IdleTimeout *int
+}
+
+var _ extensioncommon.BasicExtension = (*upgrade)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var u upgrade
+	if name := ext.Name; name != api.BuiltinUpgradeExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinUpgradeExtension, name)
+	}
+
+	if err := u.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &u,
+	}, nil
+}
+
+func (u *upgrade) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, u); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return u.validate()
+}
+
+func (u *upgrade) validate() error {
+	var resultErr error
+
+	if u.ProxyType != "connect-proxy" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", u.ProxyType))
+	}
+
+	if len(u.UpgradeTypes) == 0 {
+		resultErr = multierror.Append(resultErr, errors.New("UpgradeTypes is missing"))
+	}
+
+	allowed := make(map[string]struct{}, len(u.UpgradeTypes))
+	for name := range u.UpgradeTypes {
+		if name == "" {
+			resultErr = multierror.Append(resultErr, errors.New("UpgradeTypes: upgrade type cannot be empty"))
+			continue
+		}
+		if _, ok := allowed[strings.ToLower(name)]; ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("UpgradeTypes: upgrade type %q is duplicated", name))
+		}
+		allowed[strings.ToLower(name)] = struct{}{}
+	}
+
+	for i, r := range u.Routes {
+		if r.PathPrefix != "" && !strings.HasPrefix(r.PathPrefix, "/") {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: PathPrefix %q must begin with a '/'", i, r.PathPrefix))
+		}
+		if len(r.UpgradeTypes) == 0 && r.IdleTimeout == nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: one of UpgradeTypes or IdleTimeout is required", i))
+		}
+		for name := range r.UpgradeTypes {
+			// Envoy can only enable an upgrade on a route if the listener
+			// allows it.
+			if _, ok := allowed[strings.ToLower(name)]; !ok {
+				resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: upgrade type %q is not in UpgradeTypes", i, name))
+			}
+		}
+		if r.IdleTimeout != nil && *r.IdleTimeout < 0 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Routes[%d]: IdleTimeout(in second) cannot be negative, got %d", i, *r.IdleTimeout))
+		}
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (u *upgrade) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == u.ProxyType
+}
+
+// PatchRoute overrides the upgrades of the routes of the upstream service.
+func (u *upgrade) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, u.patchRouteConfiguration(route), nil
+}
+
+// PatchCluster does nothing.
+func (u *upgrade) PatchCluster(_ *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	return c, false, nil
+}
+
+// PatchFilter allows the upgrades on the
+// envoy.filters.network.http_connection_manager filters, and overrides the
+// upgrades of the routes of their inline route configuration.
+func (u *upgrade) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	if filter.Name != httpConnectionManagerFilterName {
+		return filter, false, nil
+	}
+	if typedConfig := filter.GetTypedConfig(); typedConfig == nil {
+		return filter, false, errors.New("error getting typed config for http filter")
+	}
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(filter)
+	if config == nil {
+		return filter, false, errors.New("error unmarshalling filter")
+	}
+
+	// Replace the upgrades that are already configured so that the last
+	// extension applied wins.
+	upgradeConfigs := make([]*envoy_http_v3.HttpConnectionManager_UpgradeConfig, 0, len(config.UpgradeConfigs)+len(u.UpgradeTypes))
+	for _, upgradeConfig := range config.UpgradeConfigs {
+		if !hasUpgradeType(u.UpgradeTypes, upgradeConfig.UpgradeType) {
+			upgradeConfigs = append(upgradeConfigs, upgradeConfig)
+		}
+	}
+	for _, name := range sortedUpgradeTypes(u.UpgradeTypes) {
+		upgradeConfigs = append(upgradeConfigs, &envoy_http_v3.HttpConnectionManager_UpgradeConfig{
+			UpgradeType: name,
+			Enabled:     &wrappers.BoolValue{Value: u.UpgradeTypes[name]},
+		})
+	}
+	config.UpgradeConfigs = upgradeConfigs
+
+	if routeConfig := config.GetRouteConfig(); routeConfig != nil {
+		u.patchRouteConfiguration(routeConfig)
+	}
+
+	newFilter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, config)
+	if err != nil {
+		return filter, false, errors.New("error making new filter")
+	}
+
+	return newFilter, true, nil
+}
+
+// patchRouteConfiguration applies the route overrides to the matching routes
+// of the route configuration, and returns whether any route was patched.
+func (u *upgrade) patchRouteConfiguration(routeConfig *envoy_route_v3.RouteConfiguration) bool {
+	var patched bool
+	for _, vh := range routeConfig.VirtualHosts {
+		for _, r := range vh.Routes {
+			action := r.GetRoute()
+			if action == nil {
+				continue
+			}
+			for _, override := range u.Routes {
+				if !override.matches(r) {
+					continue
+				}
+				override.patchRouteAction(action)
+				patched = true
+			}
+		}
+	}
+	return patched
+}
+
+func (r route) matches(envoyRoute *envoy_route_v3.Route) bool {
+	if r.PathPrefix == "" {
+		return true
+	}
+	return strings.HasPrefix(envoyRoute.GetMatch().GetPrefix(), r.PathPrefix) ||
+		strings.HasPrefix(envoyRoute.GetMatch().GetPath(), r.PathPrefix)
+}
+
+func (r route) patchRouteAction(action *envoy_route_v3.RouteAction) {
+	for _, name := range sortedUpgradeTypes(r.UpgradeTypes) {
+		upgradeConfig := &envoy_route_v3.RouteAction_UpgradeConfig{
+			UpgradeType: name,
+			Enabled:     &wrappers.BoolValue{Value: r.UpgradeTypes[name]},
+		}
+
+		replaced := false
+		for i, existing := range action.UpgradeConfigs {
+			if strings.EqualFold(existing.UpgradeType, name) {
+				action.UpgradeConfigs[i] = upgradeConfig
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			action.UpgradeConfigs = append(action.UpgradeConfigs, upgradeConfig)
+		}
+	}
+
+	if r.IdleTimeout != nil {
+		action.IdleTimeout = durationpb.New(time.Duration(*r.IdleTimeout) * time.Second)
+	}
+}
+
+// hasUpgradeType looks up an upgrade type case-insensitively, like Envoy.
+func hasUpgradeType(upgradeTypes map[string]bool, name string) bool {
+	for k := range upgradeTypes {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedUpgradeTypes returns the names of the upgrade types in order, so that
+// the generated configuration is stable.
+func sortedUpgradeTypes(upgradeTypes map[string]bool) []string {
+	names := make([]string, 0, len(upgradeTypes))
+	for name := range upgradeTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
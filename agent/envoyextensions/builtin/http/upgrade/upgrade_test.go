@@ -0,0 +1,251 @@
+package upgrade
+
+import (
+	"testing"
+	"time"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	makeArguments := func(overrides map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"ProxyType":    "connect-proxy",
+			"UpgradeTypes": map[string]bool{"websocket": true},
+		}
+
+		for k, v := range overrides {
+			m[k] = v
+		}
+
+		return m
+	}
+
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       upgrade
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     makeArguments(map[string]interface{}{}),
+			extensionName: "bad",
+			ok:            false,
+		},
+		"without upgrade types": {
+			arguments:      makeArguments(map[string]interface{}{"UpgradeTypes": nil}),
+			expectedErrMsg: "UpgradeTypes is missing",
+			ok:             false,
+		},
+		"duplicated upgrade type": {
+			arguments: makeArguments(map[string]interface{}{
+				"UpgradeTypes": map[string]bool{"websocket": true, "WebSocket": false},
+			}),
+			expectedErrMsg: "is duplicated",
+			ok:             false,
+		},
+		"route upgrade not allowed by the listener": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{
+					{"PathPrefix": "/tunnel", "UpgradeTypes": map[string]bool{"CONNECT": true}},
+				},
+			}),
+			expectedErrMsg: `Routes[0]: upgrade type "CONNECT" is not in UpgradeTypes`,
+			ok:             false,
+		},
+		"route without overrides": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{
+					{"PathPrefix": "/ws"},
+				},
+			}),
+			expectedErrMsg: "Routes[0]: one of UpgradeTypes or IdleTimeout is required",
+			ok:             false,
+		},
+		"route with an invalid prefix": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{
+					{"PathPrefix": "ws", "IdleTimeout": 60},
+				},
+			}),
+			expectedErrMsg: `Routes[0]: PathPrefix "ws" must begin with a '/'`,
+			ok:             false,
+		},
+		"route with a negative idle timeout": {
+			arguments: makeArguments(map[string]interface{}{
+				"Routes": []map[string]interface{}{
+					{"IdleTimeout": -1},
+				},
+			}),
+			expectedErrMsg: "Routes[0]: IdleTimeout(in second) cannot be negative, got -1",
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: makeArguments(map[string]interface{}{
+				"UpgradeTypes": map[string]bool{"websocket": false, "CONNECT": true},
+				"Routes": []map[string]interface{}{
+					{"PathPrefix": "/ws", "UpgradeTypes": map[string]bool{"WebSocket": true}, "IdleTimeout": 3600},
+				},
+			}),
+			expected: upgrade{
+				ProxyType:    "connect-proxy",
+				UpgradeTypes: map[string]bool{"websocket": false, "CONNECT": true},
+				Routes: []route{
+					{
+						PathPrefix:   "/ws",
+						UpgradeTypes: map[string]bool{"WebSocket": true},
+						IdleTimeout:  intPointer(3600),
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinUpgradeExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	filter, err := extensioncommon.MakeFilter(httpConnectionManagerFilterName, &envoy_http_v3.HttpConnectionManager{
+		UpgradeConfigs: []*envoy_http_v3.HttpConnectionManager_UpgradeConfig{
+			{UpgradeType: "WebSocket"},
+			{UpgradeType: "h2c"},
+		},
+		RouteSpecifier: &envoy_http_v3.HttpConnectionManager_RouteConfig{
+			RouteConfig: testRouteConfiguration(),
+		},
+	})
+	require.NoError(t, err)
+
+	u := &upgrade{
+		ProxyType:    "connect-proxy",
+		UpgradeTypes: map[string]bool{"websocket": false, "CONNECT": true},
+		Routes: []route{
+			{PathPrefix: "/ws", UpgradeTypes: map[string]bool{"websocket": true}, IdleTimeout: intPointer(3600)},
+		},
+	}
+	require.NoError(t, u.validate())
+
+	patched, ok, err := u.PatchFilter(nil, filter)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	config := envoy_resource_v3.GetHTTPConnectionManager(patched)
+	require.NotNil(t, config)
+	require.Len(t, config.UpgradeConfigs, 3)
+	require.Equal(t, "h2c", config.UpgradeConfigs[0].UpgradeType)
+	require.Nil(t, config.UpgradeConfigs[0].Enabled)
+	require.Equal(t, "CONNECT", config.UpgradeConfigs[1].UpgradeType)
+	require.True(t, config.UpgradeConfigs[1].Enabled.GetValue())
+	require.Equal(t, "websocket", config.UpgradeConfigs[2].UpgradeType)
+	require.NotNil(t, config.UpgradeConfigs[2].Enabled)
+	require.False(t, config.UpgradeConfigs[2].Enabled.GetValue())
+
+	requireRoutes(t, config.GetRouteConfig())
+}
+
+func TestPatchRoute(t *testing.T) {
+	u := &upgrade{
+		ProxyType:    "connect-proxy",
+		UpgradeTypes: map[string]bool{"websocket": false},
+		Routes: []route{
+			{PathPrefix: "/ws", UpgradeTypes: map[string]bool{"websocket": true}, IdleTimeout: intPointer(3600)},
+		},
+	}
+
+	patched, ok, err := u.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.True(t, ok)
+	requireRoutes(t, patched)
+
+	u.Routes[0].PathPrefix = "/other"
+	_, ok, err = u.PatchRoute(nil, testRouteConfiguration())
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func testRouteConfiguration() *envoy_route_v3.RouteConfiguration {
+	routeAction := func() *envoy_route_v3.Route_Route {
+		return &envoy_route_v3.Route_Route{
+			Route: &envoy_route_v3.RouteAction{
+				ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{Cluster: "local_app"},
+			},
+		}
+	}
+
+	return &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{
+			{
+				Name:    "public_listener",
+				Domains: []string{"*"},
+				Routes: []*envoy_route_v3.Route{
+					{
+						Match: &envoy_route_v3.RouteMatch{
+							PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/ws/chat"},
+						},
+						Action: routeAction(),
+					},
+					{
+						Match: &envoy_route_v3.RouteMatch{
+							PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"},
+						},
+						Action: routeAction(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func requireRoutes(t *testing.T, routeConfig *envoy_route_v3.RouteConfiguration) {
+	t.Helper()
+
+	routes := routeConfig.VirtualHosts[0].Routes
+	require.Len(t, routes, 2)
+
+	ws := routes[0].GetRoute()
+	require.Len(t, ws.UpgradeConfigs, 1)
+	require.Equal(t, "websocket", ws.UpgradeConfigs[0].UpgradeType)
+	require.True(t, ws.UpgradeConfigs[0].Enabled.GetValue())
+	require.Equal(t, time.Hour, ws.IdleTimeout.AsDuration())
+
+	other := routes[1].GetRoute()
+	require.Empty(t, other.UpgradeConfigs)
+	require.Nil(t, other.IdleTimeout)
+}
+
+func intPointer(i int) *int {
+	return &i
+}
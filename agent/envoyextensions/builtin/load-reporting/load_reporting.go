@@ -0,0 +1,98 @@
+package loadreporting
+
+import (
+	"fmt"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+// loadReporting makes the proxies of the downstreams of an upstream service
+// report the load they send to its instances to a Load Reporting Service
+// (LRS) collector, so that it can feed external global load balancing
+// systems.
+//
+// The collector is configured in the bootstrap configuration of the proxies
+// with the envoy_load_stats_cluster proxy config option. Envoy rejects the
+// clusters that report their load if it isn't set.
+type loadReporting struct {
+	ProxyType string
+}
+
+var _ extensioncommon.BasicExtension = (*loadReporting)(nil)
+
+// Constructor follows a specific function signature required for the extension registration.
+func Constructor(ext api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+	var l loadReporting
+	if name := ext.Name; name != api.BuiltinLoadReportingExtension {
+		return nil, fmt.Errorf("expected extension name %q but got %q", api.BuiltinLoadReportingExtension, name)
+	}
+
+	if err := l.fromArguments(ext.Arguments); err != nil {
+		return nil, err
+	}
+
+	return &extensioncommon.BasicEnvoyExtender{
+		Extension: &l,
+	}, nil
+}
+
+func (l *loadReporting) fromArguments(args map[string]interface{}) error {
+	if err := mapstructure.Decode(args, l); err != nil {
+		return fmt.Errorf("error decoding extension arguments: %v", err)
+	}
+	return l.validate()
+}
+
+func (l *loadReporting) validate() error {
+	var resultErr error
+
+	switch l.ProxyType {
+	case "connect-proxy", "terminating-gateway":
+	default:
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unexpected ProxyType %q", l.ProxyType))
+	}
+
+	return resultErr
+}
+
+// CanApply determines if the extension can apply to the given extension configuration.
+func (l *loadReporting) CanApply(config *extensioncommon.RuntimeConfig) bool {
+	return string(config.Kind) == l.ProxyType
+}
+
+// PatchRoute does nothing.
+func (l *loadReporting) PatchRoute(_ *extensioncommon.RuntimeConfig, route *envoy_route_v3.RouteConfiguration) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	return route, false, nil
+}
+
+// PatchCluster enables the load reports of the clusters of the upstream
+// service.
+func (l *loadReporting) PatchCluster(config *extensioncommon.RuntimeConfig, c *envoy_cluster_v3.Cluster) (*envoy_cluster_v3.Cluster, bool, error) {
+	if !config.IsUpstream() {
+		return c, false, nil
+	}
+
+	// Envoy only supports self, which reports the load to the LRS server of
+	// the bootstrap configuration.
+	c.LrsServer = &envoy_core_v3.ConfigSource{
+		ConfigSourceSpecifier: &envoy_core_v3.ConfigSource_Self{
+			Self: &envoy_core_v3.SelfConfigSource{},
+		},
+		ResourceApiVersion: envoy_core_v3.ApiVersion_V3,
+	}
+
+	return c, true, nil
+}
+
+// PatchFilter does nothing.
+func (l *loadReporting) PatchFilter(_ *extensioncommon.RuntimeConfig, filter *envoy_listener_v3.Filter) (*envoy_listener_v3.Filter, bool, error) {
+	return filter, false, nil
+}
@@ -0,0 +1,92 @@
+package loadreporting
+
+import (
+	"testing"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	cases := map[string]struct {
+		extensionName  string
+		arguments      map[string]interface{}
+		expected       loadReporting
+		ok             bool
+		expectedErrMsg string
+	}{
+		"with no arguments": {
+			arguments:      nil,
+			expectedErrMsg: `unexpected ProxyType ""`,
+			ok:             false,
+		},
+		"with an invalid name": {
+			arguments:     map[string]interface{}{"ProxyType": "connect-proxy"},
+			extensionName: "bad",
+			ok:            false,
+		},
+		"invalid proxy type": {
+			arguments:      map[string]interface{}{"ProxyType": "api-gateway"},
+			expectedErrMsg: `unexpected ProxyType "api-gateway"`,
+			ok:             false,
+		},
+		"valid everything": {
+			arguments: map[string]interface{}{"ProxyType": "terminating-gateway"},
+			expected:  loadReporting{ProxyType: "terminating-gateway"},
+			ok:        true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			extensionName := api.BuiltinLoadReportingExtension
+			if tc.extensionName != "" {
+				extensionName = tc.extensionName
+			}
+
+			e, err := Constructor(api.EnvoyExtension{
+				Name:      extensionName,
+				Arguments: tc.arguments,
+			})
+
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, &extensioncommon.BasicEnvoyExtender{Extension: &tc.expected}, e)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestPatchCluster(t *testing.T) {
+	l := &loadReporting{ProxyType: "connect-proxy"}
+	svc := api.CompoundServiceName{Name: "api"}
+
+	t.Run("upstream", func(t *testing.T) {
+		patched, ok, err := l.PatchCluster(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+			Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+				svc: {},
+			},
+		}, &envoy_cluster_v3.Cluster{Name: "api.default.dc1.internal.domain"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NotNil(t, patched.LrsServer.GetSelf())
+	})
+
+	t.Run("local app", func(t *testing.T) {
+		patched, ok, err := l.PatchCluster(&extensioncommon.RuntimeConfig{
+			Kind:        api.ServiceKindConnectProxy,
+			ServiceName: svc,
+		}, &envoy_cluster_v3.Cluster{Name: "local_app"})
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, patched.LrsServer)
+	})
+}
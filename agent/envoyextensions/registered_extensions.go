@@ -3,8 +3,21 @@ package envoyextensions
 import (
 	"fmt"
 
+	activehealthcheck "github.com/hashicorp/consul/agent/envoyextensions/builtin/active-health-check"
 	awslambda "github.com/hashicorp/consul/agent/envoyextensions/builtin/aws-lambda"
+	connectionlifetime "github.com/hashicorp/consul/agent/envoyextensions/builtin/connection-lifetime"
+	dnsresolver "github.com/hashicorp/consul/agent/envoyextensions/builtin/dns-resolver"
+	dualstack "github.com/hashicorp/consul/agent/envoyextensions/builtin/dual-stack"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/grpcjsontranscoder"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/grpcweb"
 	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/localratelimit"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/metadataexchange"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/preservecase"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/requestid"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/routetimeout"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/strictheaders"
+	"github.com/hashicorp/consul/agent/envoyextensions/builtin/http/upgrade"
+	loadreporting "github.com/hashicorp/consul/agent/envoyextensions/builtin/load-reporting"
 	"github.com/hashicorp/consul/agent/envoyextensions/builtin/lua"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
@@ -14,9 +27,22 @@ import (
 type extensionConstructor func(api.EnvoyExtension) (extensioncommon.EnvoyExtender, error)
 
 var extensionConstructors = map[string]extensionConstructor{
-	api.BuiltinLuaExtension:            lua.Constructor,
-	api.BuiltinAWSLambdaExtension:      awslambda.Constructor,
-	api.BuiltinLocalRatelimitExtension: localratelimit.Constructor,
+	api.BuiltinLuaExtension:                lua.Constructor,
+	api.BuiltinAWSLambdaExtension:          awslambda.Constructor,
+	api.BuiltinLocalRatelimitExtension:     localratelimit.Constructor,
+	api.BuiltinRequestIDExtension:          requestid.Constructor,
+	api.BuiltinPreserveCaseExtension:       preservecase.Constructor,
+	api.BuiltinStrictHeadersExtension:      strictheaders.Constructor,
+	api.BuiltinUpgradeExtension:            upgrade.Constructor,
+	api.BuiltinGRPCWebExtension:            grpcweb.Constructor,
+	api.BuiltinGRPCJSONTranscoderExtension: grpcjsontranscoder.Constructor,
+	api.BuiltinRouteTimeoutExtension:       routetimeout.Constructor,
+	api.BuiltinConnectionLifetimeExtension: connectionlifetime.Constructor,
+	api.BuiltinMetadataExchangeExtension:   metadataexchange.Constructor,
+	api.BuiltinDNSResolverExtension:        dnsresolver.Constructor,
+	api.BuiltinActiveHealthCheckExtension:  activehealthcheck.Constructor,
+	api.BuiltinDualStackExtension:          dualstack.Constructor,
+	api.BuiltinLoadReportingExtension:      loadreporting.Constructor,
 }
 
 // ConstructExtension attempts to lookup and build an extension from the registry with the
@@ -23,8 +23,11 @@ var (
 )
 
 // NewServer constructs a gRPC server for the external gRPC port, to which
-// handlers can be registered.
-func NewServer(logger agentmiddleware.Logger, metricsObj *metrics.Metrics, tls *tlsutil.Configurator, limiter rate.RequestLimitsHandler) *grpc.Server {
+// handlers can be registered. The calls made without a token use the one
+// obtained with the client certificate when certLogin is not nil, their
+// tokens are tracked when tokenUsage is not nil, and they are recorded in the
+// audit log when auditor is not nil.
+func NewServer(logger agentmiddleware.Logger, metricsObj *metrics.Metrics, tls *tlsutil.Configurator, limiter rate.RequestLimitsHandler, certLogin *agentmiddleware.ClientCertLoginInterceptor, tokenUsage *agentmiddleware.TokenUsageInterceptor, auditor *agentmiddleware.AuditInterceptor) *grpc.Server {
 	if metricsObj == nil {
 		metricsObj = metrics.Default()
 	}
@@ -46,6 +49,19 @@ func NewServer(logger agentmiddleware.Logger, metricsObj *metrics.Metrics, tls *
 		unaryInterceptors = append(unaryInterceptors, authInterceptor.InterceptUnary)
 		streamInterceptors = append(streamInterceptors, authInterceptor.InterceptStream)
 	}
+	if certLogin != nil {
+		// The token must be set before the calls are recorded in the audit log.
+		unaryInterceptors = append(unaryInterceptors, certLogin.InterceptUnary)
+		streamInterceptors = append(streamInterceptors, certLogin.InterceptStream)
+	}
+	if tokenUsage != nil {
+		unaryInterceptors = append(unaryInterceptors, tokenUsage.InterceptUnary)
+		streamInterceptors = append(streamInterceptors, tokenUsage.InterceptStream)
+	}
+	if auditor != nil {
+		unaryInterceptors = append(unaryInterceptors, auditor.InterceptUnary)
+		streamInterceptors = append(streamInterceptors, auditor.InterceptStream)
+	}
 	opts := []grpc.ServerOption{
 		grpc.MaxConcurrentStreams(2048),
 		grpc.MaxRecvMsgSize(50 * 1024 * 1024),
@@ -10,6 +10,14 @@ import (
 	"github.com/hashicorp/consul/proto-public/pbdataplane"
 )
 
+// serverFeatures are the features of the Consul Dataplane supported by the
+// server.
+var serverFeatures = []pbdataplane.DataplaneFeatures{
+	pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_WATCH_SERVERS,
+	pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_EDGE_CERTIFICATE_MANAGEMENT,
+	pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_ENVOY_BOOTSTRAP_CONFIGURATION,
+}
+
 func (s *Server) GetSupportedDataplaneFeatures(ctx context.Context, req *pbdataplane.GetSupportedDataplaneFeaturesRequest) (*pbdataplane.GetSupportedDataplaneFeaturesResponse, error) {
 	logger := s.Logger.Named("get-supported-dataplane-features").With("request_id", external.TraceID())
 
@@ -24,19 +32,12 @@ func (s *Server) GetSupportedDataplaneFeatures(ctx context.Context, req *pbdatap
 		return nil, err
 	}
 
-	supportedFeatures := []*pbdataplane.DataplaneFeatureSupport{
-		{
-			FeatureName: pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_WATCH_SERVERS,
-			Supported:   true,
-		},
-		{
-			FeatureName: pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_EDGE_CERTIFICATE_MANAGEMENT,
-			Supported:   true,
-		},
-		{
-			FeatureName: pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_ENVOY_BOOTSTRAP_CONFIGURATION,
+	supportedFeatures := make([]*pbdataplane.DataplaneFeatureSupport, 0, len(serverFeatures))
+	for _, feature := range serverFeatures {
+		supportedFeatures = append(supportedFeatures, &pbdataplane.DataplaneFeatureSupport{
+			FeatureName: feature,
 			Supported:   true,
-		},
+		})
 	}
 
 	return &pbdataplane.GetSupportedDataplaneFeaturesResponse{SupportedDataplaneFeatures: supportedFeatures}, nil
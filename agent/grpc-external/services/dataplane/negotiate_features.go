@@ -0,0 +1,126 @@
+package dataplane
+
+import (
+	"context"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-version"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	external "github.com/hashicorp/consul/agent/grpc-external"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+	"github.com/hashicorp/consul/proto-public/pbdataplane"
+	cslversion "github.com/hashicorp/consul/version"
+)
+
+var metricsKeyDataplaneVersionSkew = []string{"dataplane", "version_skew"}
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: metricsKeyDataplaneVersionSkew,
+		Help: "Increments each time a Consul Dataplane negotiates its features while it, or its Envoy proxy, is older or newer than what the server supports.",
+	},
+}
+
+// Reasons for a version skew, used as the "reason" label of the metric.
+const (
+	skewEnvoyTooOld     = "envoy_too_old"
+	skewEnvoyTooNew     = "envoy_too_new"
+	skewDataplaneTooOld = "dataplane_too_old"
+	skewDataplaneTooNew = "dataplane_too_new"
+)
+
+// maxEnvoyVersion is the most recent minor version of Envoy supported by the
+// server.
+var maxEnvoyVersion = version.Must(version.NewVersion(xdscommon.GetMaxEnvoyMinorVersion()))
+
+func (s *Server) NegotiateDataplaneFeatures(ctx context.Context, req *pbdataplane.NegotiateDataplaneFeaturesRequest) (*pbdataplane.NegotiateDataplaneFeaturesResponse, error) {
+	logger := s.Logger.Named("negotiate-dataplane-features").With(
+		"dataplane_version", req.GetDataplaneVersion(),
+		"envoy_version", req.GetEnvoyVersion(),
+		"request_id", external.TraceID(),
+	)
+
+	logger.Trace("Started processing request")
+	defer logger.Trace("Finished processing request")
+
+	options, err := external.QueryOptionsFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := external.RequireAnyValidACLToken(s.ACLResolver, options.Token); err != nil {
+		return nil, err
+	}
+
+	envoyVersion, err := version.NewVersion(req.GetEnvoyVersion())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid Envoy version %q: %v", req.GetEnvoyVersion(), err)
+	}
+
+	resp := &pbdataplane.NegotiateDataplaneFeaturesResponse{
+		// The xDS server only implements the incremental variant of the protocol.
+		XdsFlavor:             pbdataplane.XDSFlavor_XDS_FLAVOR_DELTA,
+		EnvoyVersionSupported: true,
+		ServerVersion:         cslversion.GetHumanVersion(),
+	}
+
+	var skews []string
+	if _, err := xdscommon.DetermineSupportedProxyFeaturesFromString(envoyVersion.String()); err != nil {
+		logger.Warn("Consul Dataplane is running an unsupported version of Envoy", "error", err)
+		resp.EnvoyVersionSupported = false
+		skews = append(skews, skewEnvoyTooOld)
+	} else if newerMinorVersion(envoyVersion, maxEnvoyVersion) {
+		logger.Warn("Consul Dataplane is running a version of Envoy more recent than the ones supported by this server",
+			"max_supported_version", xdscommon.GetMaxEnvoyMinorVersion())
+		skews = append(skews, skewEnvoyTooNew)
+	}
+	resp.EnvoyExtensionsAllowed = resp.EnvoyVersionSupported
+
+	dataplaneFeatures := make(map[pbdataplane.DataplaneFeatures]struct{}, len(req.GetSupportedFeatures()))
+	for _, feature := range req.GetSupportedFeatures() {
+		dataplaneFeatures[feature] = struct{}{}
+	}
+	supported := make(map[pbdataplane.DataplaneFeatures]struct{}, len(serverFeatures))
+	var missing []string
+	for _, feature := range serverFeatures {
+		supported[feature] = struct{}{}
+		if _, ok := dataplaneFeatures[feature]; ok {
+			resp.EnabledFeatures = append(resp.EnabledFeatures, feature)
+		} else {
+			missing = append(missing, feature.String())
+		}
+	}
+	if len(missing) > 0 {
+		logger.Debug("Consul Dataplane does not support all the features of this server", "missing_features", missing)
+		skews = append(skews, skewDataplaneTooOld)
+	}
+	for _, feature := range req.GetSupportedFeatures() {
+		if _, ok := supported[feature]; !ok && feature != pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_UNSPECIFIED {
+			logger.Debug("Consul Dataplane supports features unknown to this server")
+			skews = append(skews, skewDataplaneTooNew)
+			break
+		}
+	}
+
+	for _, reason := range skews {
+		metrics.IncrCounterWithLabels(metricsKeyDataplaneVersionSkew, 1, []metrics.Label{
+			{Name: "reason", Value: reason},
+			{Name: "dataplane_version", Value: req.GetDataplaneVersion()},
+			{Name: "envoy_version", Value: envoyVersion.String()},
+		})
+	}
+
+	return resp, nil
+}
+
+// newerMinorVersion returns true if the major or minor version of v is more
+// recent than the one of latest.
+func newerMinorVersion(v, latest *version.Version) bool {
+	vs, ms := v.Segments(), latest.Segments()
+	if vs[0] != ms[0] {
+		return vs[0] > ms[0]
+	}
+	return vs[1] > ms[1]
+}
@@ -0,0 +1,153 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/acl"
+	resolver "github.com/hashicorp/consul/acl/resolver"
+	external "github.com/hashicorp/consul/agent/grpc-external"
+	"github.com/hashicorp/consul/agent/grpc-external/testutils"
+	structs "github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+	"github.com/hashicorp/consul/proto-public/pbdataplane"
+)
+
+func TestNegotiateDataplaneFeatures(t *testing.T) {
+	allFeatures := []pbdataplane.DataplaneFeatures{
+		pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_WATCH_SERVERS,
+		pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_EDGE_CERTIFICATE_MANAGEMENT,
+		pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_ENVOY_BOOTSTRAP_CONFIGURATION,
+	}
+
+	testCases := map[string]struct {
+		req                   *pbdataplane.NegotiateDataplaneFeaturesRequest
+		enabled               []pbdataplane.DataplaneFeatures
+		envoyVersionSupported bool
+		extensionsAllowed     bool
+	}{
+		"supported versions": {
+			req: &pbdataplane.NegotiateDataplaneFeaturesRequest{
+				DataplaneVersion:  "1.1.0",
+				EnvoyVersion:      xdscommon.EnvoyVersions[0],
+				SupportedFeatures: allFeatures,
+			},
+			enabled:               allFeatures,
+			envoyVersionSupported: true,
+			extensionsAllowed:     true,
+		},
+		"older dataplane": {
+			req: &pbdataplane.NegotiateDataplaneFeaturesRequest{
+				DataplaneVersion: "1.0.0",
+				EnvoyVersion:     xdscommon.EnvoyVersions[len(xdscommon.EnvoyVersions)-1],
+				SupportedFeatures: []pbdataplane.DataplaneFeatures{
+					pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_WATCH_SERVERS,
+				},
+			},
+			enabled: []pbdataplane.DataplaneFeatures{
+				pbdataplane.DataplaneFeatures_DATAPLANE_FEATURES_WATCH_SERVERS,
+			},
+			envoyVersionSupported: true,
+			extensionsAllowed:     true,
+		},
+		"newer dataplane": {
+			req: &pbdataplane.NegotiateDataplaneFeaturesRequest{
+				DataplaneVersion:  "9.0.0",
+				EnvoyVersion:      "9.0.0",
+				SupportedFeatures: append([]pbdataplane.DataplaneFeatures{100}, allFeatures...),
+			},
+			enabled:               allFeatures,
+			envoyVersionSupported: true,
+			extensionsAllowed:     true,
+		},
+		"unsupported envoy version": {
+			req: &pbdataplane.NegotiateDataplaneFeaturesRequest{
+				DataplaneVersion:  "1.1.0",
+				EnvoyVersion:      "1.0.0",
+				SupportedFeatures: allFeatures,
+			},
+			enabled:               allFeatures,
+			envoyVersionSupported: false,
+			extensionsAllowed:     false,
+		},
+	}
+
+	aclResolver := &MockACLResolver{}
+	aclResolver.On("ResolveTokenAndDefaultMeta", testACLToken, mock.Anything, mock.Anything).
+		Return(testutils.ACLServiceWriteAny(t), nil)
+
+	options := structs.QueryOptions{Token: testACLToken}
+	ctx, err := external.ContextWithQueryOptions(context.Background(), options)
+	require.NoError(t, err)
+
+	server := NewServer(Config{
+		Logger:      hclog.NewNullLogger(),
+		ACLResolver: aclResolver,
+	})
+	client := testClient(t, server)
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.NegotiateDataplaneFeatures(ctx, tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.enabled, resp.EnabledFeatures)
+			require.Equal(t, tc.envoyVersionSupported, resp.EnvoyVersionSupported)
+			require.Equal(t, tc.extensionsAllowed, resp.EnvoyExtensionsAllowed)
+			require.Equal(t, pbdataplane.XDSFlavor_XDS_FLAVOR_DELTA, resp.XdsFlavor)
+			require.NotEmpty(t, resp.ServerVersion)
+		})
+	}
+}
+
+func TestNegotiateDataplaneFeatures_InvalidEnvoyVersion(t *testing.T) {
+	aclResolver := &MockACLResolver{}
+	aclResolver.On("ResolveTokenAndDefaultMeta", testACLToken, mock.Anything, mock.Anything).
+		Return(testutils.ACLServiceWriteAny(t), nil)
+
+	options := structs.QueryOptions{Token: testACLToken}
+	ctx, err := external.ContextWithQueryOptions(context.Background(), options)
+	require.NoError(t, err)
+
+	server := NewServer(Config{
+		Logger:      hclog.NewNullLogger(),
+		ACLResolver: aclResolver,
+	})
+	client := testClient(t, server)
+	resp, err := client.NegotiateDataplaneFeatures(ctx, &pbdataplane.NegotiateDataplaneFeaturesRequest{
+		DataplaneVersion: "1.1.0",
+		EnvoyVersion:     "not-a-version",
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument.String(), status.Code(err).String())
+	require.Nil(t, resp)
+}
+
+func TestNegotiateDataplaneFeatures_InvalidACLToken(t *testing.T) {
+	// Mock the ACL resolver to return ErrNotFound.
+	aclResolver := &MockACLResolver{}
+	aclResolver.On("ResolveTokenAndDefaultMeta", mock.Anything, mock.Anything, mock.Anything).
+		Return(resolver.Result{}, acl.ErrNotFound)
+
+	options := structs.QueryOptions{Token: testACLToken}
+	ctx, err := external.ContextWithQueryOptions(context.Background(), options)
+	require.NoError(t, err)
+
+	server := NewServer(Config{
+		Logger:      hclog.NewNullLogger(),
+		ACLResolver: aclResolver,
+	})
+	client := testClient(t, server)
+	resp, err := client.NegotiateDataplaneFeatures(ctx, &pbdataplane.NegotiateDataplaneFeaturesRequest{
+		DataplaneVersion: "1.1.0",
+		EnvoyVersion:     xdscommon.EnvoyVersions[0],
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.Unauthenticated.String(), status.Code(err).String())
+	require.Nil(t, resp)
+}
@@ -0,0 +1,31 @@
+package eventstream
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/proto-public/pbeventstream"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+type Server struct {
+	Config
+}
+
+type Config struct {
+	// Subscriber is the internal subscription service, which the streams are
+	// served from once converted to the public types.
+	Subscriber pbsubscribe.StateChangeSubscriptionServer
+	Logger     hclog.Logger
+}
+
+func NewServer(cfg Config) *Server {
+	return &Server{cfg}
+}
+
+var _ pbeventstream.EventStreamServiceServer = (*Server)(nil)
+
+func (s *Server) Register(grpcServer *grpc.Server) {
+	pbeventstream.RegisterEventStreamServiceServer(grpcServer, s)
+}
@@ -0,0 +1,158 @@
+package eventstream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/acl"
+	external "github.com/hashicorp/consul/agent/grpc-external"
+	"github.com/hashicorp/consul/proto-public/pbeventstream"
+	"github.com/hashicorp/consul/proto/pbconfigentry"
+	"github.com/hashicorp/consul/proto/pbservice"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+// Subscribe serves the stream from the internal subscription service, with
+// the events converted to the public types. The payloads without a public
+// equivalent are left out.
+func (s *Server) Subscribe(req *pbeventstream.SubscribeRequest, serverStream pbeventstream.EventStreamService_SubscribeServer) error {
+	options, err := external.QueryOptionsFromContext(serverStream.Context())
+	if err != nil {
+		return err
+	}
+
+	topic, ok := pbsubscribe.Topic_value[req.Topic]
+	if !ok || pbsubscribe.Topic(topic) == pbsubscribe.Topic_Unknown {
+		return status.Errorf(codes.InvalidArgument, "unknown topic %q", req.Topic)
+	}
+
+	subReq := &pbsubscribe.SubscribeRequest{
+		Topic:      pbsubscribe.Topic(topic),
+		Token:      options.Token,
+		Datacenter: req.Datacenter,
+		Index:      req.Index,
+	}
+	if req.Key == "" {
+		subReq.Subject = &pbsubscribe.SubscribeRequest_WildcardSubject{WildcardSubject: true}
+	} else {
+		subReq.Subject = &pbsubscribe.SubscribeRequest_NamedSubject{
+			NamedSubject: &pbsubscribe.NamedSubject{
+				Key:       req.Key,
+				Namespace: req.Namespace,
+				Partition: req.Partition,
+				PeerName:  req.PeerName,
+			},
+		}
+	}
+
+	err = s.Subscriber.Subscribe(subReq, &subscribeStream{ServerStream: serverStream, stream: serverStream})
+	switch {
+	case acl.IsErrNotFound(err):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case acl.IsErrPermissionDenied(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return err
+}
+
+// subscribeStream is handed to the internal subscription service in place of
+// its own stream, to convert the events it sends.
+type subscribeStream struct {
+	grpc.ServerStream
+	stream pbeventstream.EventStreamService_SubscribeServer
+}
+
+func (s *subscribeStream) Send(event *pbsubscribe.Event) error {
+	out, err := eventFromSubscription(event)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if out == nil {
+		return nil
+	}
+	return s.stream.Send(out)
+}
+
+// eventFromSubscription converts an event of the internal subscription service
+// to the public types. It returns nil for the payloads without a public
+// equivalent.
+func eventFromSubscription(event *pbsubscribe.Event) (*pbeventstream.Event, error) {
+	out := &pbeventstream.Event{Index: event.Index}
+	switch payload := event.Payload.(type) {
+	case *pbsubscribe.Event_EndOfSnapshot:
+		out.Payload = &pbeventstream.Event_EndOfSnapshot{EndOfSnapshot: payload.EndOfSnapshot}
+	case *pbsubscribe.Event_NewSnapshotToFollow:
+		out.Payload = &pbeventstream.Event_NewSnapshotToFollow{NewSnapshotToFollow: payload.NewSnapshotToFollow}
+	case *pbsubscribe.Event_EventBatch:
+		batch := &pbeventstream.EventBatch{}
+		for _, e := range payload.EventBatch.Events {
+			converted, err := eventFromSubscription(e)
+			if err != nil {
+				return nil, err
+			}
+			if converted != nil {
+				batch.Events = append(batch.Events, converted)
+			}
+		}
+		if len(batch.Events) == 0 {
+			return nil, nil
+		}
+		out.Payload = &pbeventstream.Event_EventBatch{EventBatch: batch}
+	case *pbsubscribe.Event_ServiceHealth:
+		csn, err := pbservice.CheckServiceNodeToStructs(payload.ServiceHealth.CheckServiceNode)
+		if err != nil {
+			return nil, err
+		}
+		// The health endpoints of the HTTP API serve the same JSON for the
+		// instances of a service.
+		entry, err := json.Marshal(csn)
+		if err != nil {
+			return nil, err
+		}
+		op := "register"
+		if payload.ServiceHealth.Op == pbsubscribe.CatalogOp_Deregister {
+			op = "deregister"
+		}
+		out.Payload = &pbeventstream.Event_ServiceHealth{
+			ServiceHealth: &pbeventstream.ServiceHealthUpdate{Op: op, ServiceEntry: entry},
+		}
+	case *pbsubscribe.Event_ConfigEntry:
+		entry := pbconfigentry.ConfigEntryToStructs(payload.ConfigEntry.ConfigEntry)
+		if entry == nil {
+			return nil, nil
+		}
+		// The kind of the entries is implied by their type, so it isn't
+		// always set on them.
+		var raw map[string]interface{}
+		if err := convertJSON(entry, &raw); err != nil {
+			return nil, err
+		}
+		raw["Kind"] = entry.GetKind()
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		op := "upsert"
+		if payload.ConfigEntry.Op == pbsubscribe.ConfigEntryUpdate_Delete {
+			op = "delete"
+		}
+		out.Payload = &pbeventstream.Event_ConfigEntry{
+			ConfigEntry: &pbeventstream.ConfigEntryUpdate{Op: op, ConfigEntry: encoded},
+		}
+	default:
+		return nil, nil
+	}
+	return out, nil
+}
+
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode %T: %w", in, err)
+	}
+	return json.Unmarshal(data, out)
+}
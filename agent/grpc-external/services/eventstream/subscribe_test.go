@@ -0,0 +1,200 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/grpc-external/testutils"
+	"github.com/hashicorp/consul/proto-public/pbeventstream"
+	"github.com/hashicorp/consul/proto/pbconfigentry"
+	"github.com/hashicorp/consul/proto/pbservice"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+type fakeSubscriber struct {
+	req    *pbsubscribe.SubscribeRequest
+	events []*pbsubscribe.Event
+	err    error
+}
+
+func (f *fakeSubscriber) Subscribe(req *pbsubscribe.SubscribeRequest, stream pbsubscribe.StateChangeSubscription_SubscribeServer) error {
+	f.req = req
+	for _, e := range f.events {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func testClient(t *testing.T, server *Server) pbeventstream.EventStreamServiceClient {
+	t.Helper()
+
+	addr := testutils.RunTestServer(t, server)
+
+	//nolint:staticcheck
+	conn, err := grpc.DialContext(context.Background(), addr.String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	return pbeventstream.NewEventStreamServiceClient(conn)
+}
+
+func TestSubscribe(t *testing.T) {
+	subscriber := &fakeSubscriber{
+		events: []*pbsubscribe.Event{
+			{
+				Index: 5,
+				Payload: &pbsubscribe.Event_ServiceHealth{ServiceHealth: &pbsubscribe.ServiceHealthUpdate{
+					Op: pbsubscribe.CatalogOp_Register,
+					CheckServiceNode: &pbservice.CheckServiceNode{
+						Node:    &pbservice.Node{Node: "node1", Address: "10.0.0.1"},
+						Service: &pbservice.NodeService{ID: "web1", Service: "web", Port: 8080},
+					},
+				}},
+			},
+			// Payloads without a public equivalent are left out.
+			{
+				Index:   6,
+				Payload: &pbsubscribe.Event_Service{Service: &pbsubscribe.ServiceListUpdate{}},
+			},
+			{Index: 7, Payload: &pbsubscribe.Event_EndOfSnapshot{EndOfSnapshot: true}},
+		},
+		err: status.Error(codes.Aborted, "reset"),
+	}
+	client := testClient(t, NewServer(Config{Subscriber: subscriber, Logger: hclog.NewNullLogger()}))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-consul-token", "secret")
+	stream, err := client.Subscribe(ctx, &pbeventstream.SubscribeRequest{
+		Topic:     "ServiceHealth",
+		Key:       "web",
+		Namespace: "ns",
+		Index:     3,
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), event.Index)
+	health := event.GetServiceHealth()
+	require.NotNil(t, health)
+	require.Equal(t, "register", health.Op)
+
+	var entry struct {
+		Node    struct{ Node, Address string }
+		Service struct {
+			ID   string
+			Port int
+		}
+	}
+	require.NoError(t, json.Unmarshal(health.ServiceEntry, &entry))
+	require.Equal(t, "node1", entry.Node.Node)
+	require.Equal(t, "10.0.0.1", entry.Node.Address)
+	require.Equal(t, "web1", entry.Service.ID)
+	require.Equal(t, 8080, entry.Service.Port)
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), event.Index)
+	require.True(t, event.GetEndOfSnapshot())
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	require.Equal(t, pbsubscribe.Topic_ServiceHealth, subscriber.req.Topic)
+	require.Equal(t, "secret", subscriber.req.Token)
+	require.Equal(t, uint64(3), subscriber.req.Index)
+	require.Equal(t, "web", subscriber.req.GetNamedSubject().Key)
+	require.Equal(t, "ns", subscriber.req.GetNamedSubject().Namespace)
+}
+
+func TestSubscribe_Errors(t *testing.T) {
+	subscriber := &fakeSubscriber{err: acl.ErrPermissionDenied}
+	client := testClient(t, NewServer(Config{Subscriber: subscriber, Logger: hclog.NewNullLogger()}))
+
+	recvErr := func(t *testing.T, req *pbeventstream.SubscribeRequest) error {
+		stream, err := client.Subscribe(context.Background(), req)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NotEqual(t, io.EOF, err)
+		return err
+	}
+
+	err := recvErr(t, &pbeventstream.SubscribeRequest{Topic: "Nope"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = recvErr(t, &pbeventstream.SubscribeRequest{Topic: "Unknown"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Keys are optional, in which case the subscription is a wildcard one.
+	err = recvErr(t, &pbeventstream.SubscribeRequest{Topic: "ServiceHealth"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.True(t, subscriber.req.GetWildcardSubject())
+
+	subscriber.err = acl.ErrNotFound
+	err = recvErr(t, &pbeventstream.SubscribeRequest{Topic: "ServiceHealth"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestEventFromSubscription(t *testing.T) {
+	event := &pbsubscribe.Event{
+		Index: 7,
+		Payload: &pbsubscribe.Event_EventBatch{EventBatch: &pbsubscribe.EventBatch{Events: []*pbsubscribe.Event{
+			{
+				Index: 7,
+				Payload: &pbsubscribe.Event_ServiceHealth{ServiceHealth: &pbsubscribe.ServiceHealthUpdate{
+					Op: pbsubscribe.CatalogOp_Deregister,
+					CheckServiceNode: &pbservice.CheckServiceNode{
+						Node:    &pbservice.Node{Node: "node1"},
+						Service: &pbservice.NodeService{ID: "web1", Service: "web"},
+					},
+				}},
+			},
+			{
+				Index: 7,
+				Payload: &pbsubscribe.Event_ConfigEntry{ConfigEntry: &pbsubscribe.ConfigEntryUpdate{
+					Op: pbsubscribe.ConfigEntryUpdate_Upsert,
+					ConfigEntry: &pbconfigentry.ConfigEntry{
+						Kind: pbconfigentry.Kind_KindServiceDefaults,
+						Name: "web",
+						Entry: &pbconfigentry.ConfigEntry_ServiceDefaults{
+							ServiceDefaults: &pbconfigentry.ServiceDefaults{Protocol: "http"},
+						},
+					},
+				}},
+			},
+			{
+				Index:   7,
+				Payload: &pbsubscribe.Event_Service{Service: &pbsubscribe.ServiceListUpdate{}},
+			},
+		}}},
+	}
+
+	out, err := eventFromSubscription(event)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), out.Index)
+	events := out.GetEventBatch().Events
+	require.Len(t, events, 2)
+
+	require.Equal(t, "deregister", events[0].GetServiceHealth().Op)
+
+	update := events[1].GetConfigEntry()
+	require.Equal(t, "upsert", update.Op)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(update.ConfigEntry, &entry))
+	require.Equal(t, "service-defaults", entry["Kind"])
+	require.Equal(t, "web", entry["Name"])
+	require.Equal(t, "http", entry["Protocol"])
+}
@@ -119,6 +119,22 @@ func makeServerAddrsResponse(
 	}, nil
 }
 
+func makeConfigEntriesResponse(
+	update cache.UpdateEvent,
+) (*pbpeerstream.ReplicationMessage_Response, error) {
+	any, _, err := marshalToProtoAny[*pbpeering.PeeringConfigEntries](update.Result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	return &pbpeerstream.ReplicationMessage_Response{
+		ResourceURL: pbpeerstream.TypeURLPeeringConfigEntries,
+		ResourceID:  "config-entries",
+		Operation:   pbpeerstream.Operation_OPERATION_UPSERT,
+		Resource:    any,
+	}, nil
+}
+
 // marshalToProtoAny takes any input and returns:
 // the protobuf.Any type, the asserted T type, and any errors
 // during marshalling or type assertion.
@@ -256,6 +272,14 @@ func (s *Server) handleUpsert(
 		}
 
 		return s.handleUpsertServerAddrs(peerName, partition, addrs)
+
+	case pbpeerstream.TypeURLPeeringConfigEntries:
+		entries := &pbpeering.PeeringConfigEntries{}
+		if err := resource.UnmarshalTo(entries); err != nil {
+			return fmt.Errorf("failed to unmarshal resource: %w", err)
+		}
+
+		return s.handleUpsertConfigEntries(peerName, partition, entries)
 	default:
 		return fmt.Errorf("unexpected resourceURL: %s", resourceURL)
 	}
@@ -578,6 +602,21 @@ func (s *Server) handleUpsertServerAddrs(
 	return s.Backend.PeeringWrite(req)
 }
 
+func (s *Server) handleUpsertConfigEntries(
+	peerName string,
+	partition string,
+	entries *pbpeering.PeeringConfigEntries,
+) error {
+	// We override the partition and peer name so that the config entries get stored
+	// in the importing partition with a reference to the peer they were imported from.
+	entries.Partition = partition
+	entries.PeerName = peerName
+	req := &pbpeering.PeeringConfigEntriesWriteRequest{
+		PeeringConfigEntries: entries,
+	}
+	return s.Backend.PeeringConfigEntriesWrite(req)
+}
+
 func makeACKReply(resourceURL, nonce string) *pbpeerstream.ReplicationMessage {
 	return makeReplicationRequest(&pbpeerstream.ReplicationMessage_Request{
 		ResourceURL:   resourceURL,
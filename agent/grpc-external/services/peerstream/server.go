@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/consul/acl"
@@ -45,6 +46,13 @@ type Config struct {
 
 	// incomingHeartbeatTimeout is how long we'll wait between receiving heartbeats before we close the connection.
 	incomingHeartbeatTimeout time.Duration
+
+	// IncomingMessageRateLimit and IncomingMessageBurst limit how quickly
+	// messages received from each peer are processed. When the limit is
+	// reached we stop reading from the stream, which applies backpressure
+	// to the peer. If unset, incoming messages are not limited.
+	IncomingMessageRateLimit rate.Limit
+	IncomingMessageBurst     int
 }
 
 //go:generate mockery --name ACLResolver --inpackage
@@ -66,6 +74,9 @@ func NewServer(cfg Config) *Server {
 	if cfg.incomingHeartbeatTimeout == 0 {
 		cfg.incomingHeartbeatTimeout = defaultIncomingHeartbeatTimeout
 	}
+	if cfg.IncomingMessageRateLimit == 0 {
+		cfg.IncomingMessageRateLimit = rate.Inf
+	}
 	return &Server{
 		Config:  cfg,
 		Tracker: NewTracker(cfg.incomingHeartbeatTimeout),
@@ -103,6 +114,7 @@ type Backend interface {
 	PeeringSecretsWrite(req *pbpeering.SecretsWriteRequest) error
 	PeeringTerminateByID(req *pbpeering.PeeringTerminateByIDRequest) error
 	PeeringTrustBundleWrite(req *pbpeering.PeeringTrustBundleWriteRequest) error
+	PeeringConfigEntriesWrite(req *pbpeering.PeeringConfigEntriesWriteRequest) error
 	CatalogRegister(req *structs.RegisterRequest) error
 	CatalogDeregister(req *structs.DeregisterRequest) error
 	PeeringWrite(req *pbpeering.PeeringWriteRequest) error
@@ -117,6 +129,7 @@ type StateStore interface {
 	PeeringTrustBundleList(ws memdb.WatchSet, entMeta acl.EnterpriseMeta) (uint64, []*pbpeering.PeeringTrustBundle, error)
 	PeeringSecretsRead(ws memdb.WatchSet, peerID string) (*pbpeering.PeeringSecrets, error)
 	ExportedServicesForPeer(ws memdb.WatchSet, peerID, dc string) (uint64, *structs.ExportedServiceList, error)
+	ExportedConfigEntriesForPeer(ws memdb.WatchSet, peerID, dc string) (uint64, []structs.ConfigEntry, error)
 	ServiceDump(ws memdb.WatchSet, kind structs.ServiceKind, useKind bool, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error)
 	CheckServiceNodes(ws memdb.WatchSet, serviceName string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error)
 	NodeServices(ws memdb.WatchSet, nodeNameOrID string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, *structs.NodeServices, error)
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
 	"github.com/hashicorp/go-hclog"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
@@ -25,6 +27,15 @@ import (
 	"github.com/hashicorp/consul/proto/pbpeerstream"
 )
 
+var metricsKeyPeeringStreamRateLimited = []string{"peering", "stream", "rate_limited"}
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: metricsKeyPeeringStreamRateLimited,
+		Help: "Increments each time a message received on a peering stream exceeds the configured rate limit.",
+	},
+}
+
 type BidirectionalStream interface {
 	Send(*pbpeerstream.ReplicationMessage) error
 	Recv() (*pbpeerstream.ReplicationMessage, error)
@@ -377,6 +388,9 @@ func (s *Server) realHandleStream(streamReq HandleStreamRequest) error {
 				status.TrackSendSuccess()
 			}
 		}
+		if err == nil {
+			status.TrackMessageSent()
+		}
 		return err
 	}
 
@@ -384,6 +398,7 @@ func (s *Server) realHandleStream(streamReq HandleStreamRequest) error {
 		pbpeerstream.TypeURLExportedService,
 		pbpeerstream.TypeURLExportedServiceList,
 		pbpeerstream.TypeURLPeeringTrustBundle,
+		pbpeerstream.TypeURLPeeringConfigEntries,
 	}
 	// Acceptors should not subscribe to server address updates, because they should always have an empty list.
 	if !streamReq.IsAcceptor() {
@@ -407,6 +422,13 @@ func (s *Server) realHandleStream(streamReq HandleStreamRequest) error {
 	// recvErrCh sends errors received from the gRPC stream.
 	recvErrCh := make(chan error)
 
+	// incomingLimiter bounds how quickly we process messages from the peer.
+	incomingLimiter := rate.NewLimiter(s.IncomingMessageRateLimit, s.IncomingMessageBurst)
+	rateLimitedLabels := []metrics.Label{
+		{Name: "peer_name", Value: streamReq.PeerName},
+		{Name: "peer_id", Value: streamReq.LocalID},
+	}
+
 	// Start a goroutine to read from the stream and pass to recvCh and recvErrCh.
 	// Using a separate goroutine allows us to process sends and receives all in the main for{} loop.
 	go func() {
@@ -417,6 +439,18 @@ func (s *Server) realHandleStream(streamReq HandleStreamRequest) error {
 				return
 			}
 			logTraceRecv(logger, msg)
+			status.TrackMessageReceived()
+
+			// Not reading from the stream while we wait for the limiter
+			// applies backpressure to the peer through gRPC flow control.
+			if !incomingLimiter.Allow() {
+				metrics.IncrCounterWithLabels(metricsKeyPeeringStreamRateLimited, 1, rateLimitedLabels)
+				status.TrackMessageRateLimited()
+				if err := incomingLimiter.Wait(handleStreamCtx); err != nil {
+					return
+				}
+			}
+
 			select {
 			case recvCh <- msg:
 			case <-handleStreamCtx.Done():
@@ -685,6 +719,13 @@ func (s *Server) realHandleStream(streamReq HandleStreamRequest) error {
 					continue
 				}
 
+			case update.CorrelationID == subExportedConfigEntries:
+				resp, err = makeConfigEntriesResponse(update)
+				if err != nil {
+					logger.Error("failed to create config entries response", "error", err)
+					continue
+				}
+
 			default:
 				logger.Warn("unrecognized update type from subscription manager: " + update.CorrelationID)
 				continue
@@ -125,7 +125,7 @@ func TestStreamResources_Server_LeaderBecomesFollower(t *testing.T) {
 
 	// Receive a subscription from a peer. This message arrives while the
 	// server is a leader and should work.
-	testutil.RunStep(t, "send subscription request to leader and consume its three requests", func(t *testing.T) {
+	testutil.RunStep(t, "send subscription request to leader and consume its four requests", func(t *testing.T) {
 		sub := &pbpeerstream.ReplicationMessage{
 			Payload: &pbpeerstream.ReplicationMessage_Open_{
 				Open: &pbpeerstream.ReplicationMessage_Open{
@@ -148,6 +148,10 @@ func TestStreamResources_Server_LeaderBecomesFollower(t *testing.T) {
 		msg3, err := client.Recv()
 		require.NoError(t, err)
 		require.NotEmpty(t, msg3)
+
+		msg4, err := client.Recv()
+		require.NoError(t, err)
+		require.NotEmpty(t, msg4)
 	})
 
 	// The ACK will be a new request but at this point the server is not the
@@ -1417,6 +1421,8 @@ func makeClient(t *testing.T, srv *testServer, peerID string) *MockClient {
 	require.NoError(t, err)
 	receivedSub3, err := client.Recv()
 	require.NoError(t, err)
+	receivedSub4, err := client.Recv()
+	require.NoError(t, err)
 
 	// This is required when the client subscribes to server address replication messages.
 	// We assert for the handler to be called at least once but the data doesn't matter.
@@ -1474,11 +1480,23 @@ func makeClient(t *testing.T, srv *testServer, peerID string) *MockClient {
 				},
 			},
 		},
+		{
+			Payload: &pbpeerstream.ReplicationMessage_Request_{
+				Request: &pbpeerstream.ReplicationMessage_Request{
+					ResourceURL: pbpeerstream.TypeURLPeeringConfigEntries,
+					// The PeerID field is only set for the messages coming FROM
+					// the establishing side and are going to be empty from the
+					// other side.
+					PeerID: "",
+				},
+			},
+		},
 	}
 	got := []*pbpeerstream.ReplicationMessage{
 		receivedSub1,
 		receivedSub2,
 		receivedSub3,
+		receivedSub4,
 	}
 	prototest.AssertElementsMatch(t, expect, got)
 
@@ -1527,6 +1545,10 @@ func (b *testStreamBackend) PeeringTrustBundleWrite(req *pbpeering.PeeringTrustB
 	panic("not implemented")
 }
 
+func (b *testStreamBackend) PeeringConfigEntriesWrite(req *pbpeering.PeeringConfigEntriesWriteRequest) error {
+	return b.store.PeeringConfigEntriesWrite(1, req.PeeringConfigEntries)
+}
+
 func (b *testStreamBackend) ValidateProposedPeeringSecret(id string) (bool, error) {
 	return true, nil
 }
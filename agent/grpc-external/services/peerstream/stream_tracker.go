@@ -117,6 +117,30 @@ func (t *Tracker) StreamStatus(id string) (resp Status, found bool) {
 	return s.GetStatus(), true
 }
 
+// StreamUsage returns the message counters for the stream to the given peer.
+func (t *Tracker) StreamUsage(id string) (StreamUsage, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.streams[id]
+	if !ok {
+		return StreamUsage{}, false
+	}
+	return s.GetUsage(), true
+}
+
+// StreamDiagnostics returns debugging information for the stream to the given peer.
+func (t *Tracker) StreamDiagnostics(id string) (StreamDiagnostics, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.streams[id]
+	if !ok {
+		return StreamDiagnostics{}, false
+	}
+	return s.GetDiagnostics(), true
+}
+
 func (t *Tracker) ConnectedStreams() map[string]chan struct{} {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -190,9 +214,62 @@ type MutableStatus struct {
 	// to the peer before the stream's context is cancelled.
 	doneCh chan struct{}
 
+	// usage and diagnostics are kept apart from Status since they change
+	// on every message.
+	usage       StreamUsage
+	diagnostics StreamDiagnostics
+
 	Status
 }
 
+// StreamUsage contains counters of the messages exchanged with a peer
+// over the lifetime of the local server.
+type StreamUsage struct {
+	// MessagesReceived is the number of messages received from the peer.
+	MessagesReceived uint64
+
+	// MessagesSent is the number of messages successfully sent to the peer.
+	MessagesSent uint64
+
+	// MessagesRateLimited is the number of received messages that were
+	// delayed because they exceeded the incoming message rate limit.
+	MessagesRateLimited uint64
+}
+
+// maxStreamErrorHistory is the number of recent errors kept for each stream.
+const maxStreamErrorHistory = 10
+
+// StreamDiagnostics contains information for debugging unstable streams.
+type StreamDiagnostics struct {
+	// PendingResponses is the number of resources sent to the peer that
+	// have not been ACKed or NACKed yet.
+	PendingResponses uint64
+
+	// RetryAttempts is the number of consecutive failed attempts to
+	// establish the stream. It is only tracked by the dialing side and is
+	// reset once the stream connects.
+	RetryAttempts uint64
+
+	// NextRetry is when the dialing side will next try to establish the
+	// stream, or nil if it is not waiting to retry.
+	NextRetry *time.Time
+
+	// RecentErrors holds the most recent stream errors, oldest first.
+	RecentErrors []StreamError
+}
+
+// StreamError is an error observed on a stream.
+type StreamError struct {
+	// Time is when the error happened.
+	Time time.Time
+
+	// Kind is where the error happened: "send", "recv", "nack" or "disconnect".
+	Kind string
+
+	// Message is the error message.
+	Message string
+}
+
 // Status contains information about the replication stream to a peer cluster.
 // TODO(peering): There's a lot of fields here...
 type Status struct {
@@ -274,6 +351,7 @@ func (s *MutableStatus) Done() <-chan struct{} {
 func (s *MutableStatus) TrackAck() {
 	s.mu.Lock()
 	s.LastAck = ptr(s.timeNow().UTC())
+	s.trackResponseDoneLocked()
 	s.mu.Unlock()
 }
 
@@ -281,12 +359,14 @@ func (s *MutableStatus) TrackSendError(error string) {
 	s.mu.Lock()
 	s.LastSendError = ptr(s.timeNow().UTC())
 	s.LastSendErrorMessage = error
+	s.trackErrorLocked(*s.LastSendError, "send", error)
 	s.mu.Unlock()
 }
 
 func (s *MutableStatus) TrackSendSuccess() {
 	s.mu.Lock()
 	s.LastSendSuccess = ptr(s.timeNow().UTC())
+	s.diagnostics.PendingResponses++
 	s.mu.Unlock()
 }
 
@@ -308,6 +388,7 @@ func (s *MutableStatus) TrackRecvError(error string) {
 	s.mu.Lock()
 	s.LastRecvError = ptr(s.timeNow().UTC())
 	s.LastRecvErrorMessage = error
+	s.trackErrorLocked(*s.LastRecvError, "recv", error)
 	s.mu.Unlock()
 }
 
@@ -315,6 +396,17 @@ func (s *MutableStatus) TrackNack(msg string) {
 	s.mu.Lock()
 	s.LastNack = ptr(s.timeNow().UTC())
 	s.LastNackMessage = msg
+	s.trackResponseDoneLocked()
+	s.trackErrorLocked(*s.LastNack, "nack", msg)
+	s.mu.Unlock()
+}
+
+// TrackRetry tracks a failed attempt to establish the stream, and when the
+// next attempt will be made.
+func (s *MutableStatus) TrackRetry(failedAttempts uint, wait time.Duration) {
+	s.mu.Lock()
+	s.diagnostics.RetryAttempts = uint64(failedAttempts)
+	s.diagnostics.NextRetry = ptr(s.timeNow().UTC().Add(wait))
 	s.mu.Unlock()
 }
 
@@ -323,6 +415,9 @@ func (s *MutableStatus) TrackConnected() {
 	s.Connected = true
 	s.DisconnectTime = &time.Time{}
 	s.DisconnectErrorMessage = ""
+	s.diagnostics.PendingResponses = 0
+	s.diagnostics.RetryAttempts = 0
+	s.diagnostics.NextRetry = nil
 	s.mu.Unlock()
 }
 
@@ -343,9 +438,29 @@ func (s *MutableStatus) TrackDisconnectedDueToError(error string) {
 	s.Connected = false
 	s.DisconnectTime = ptr(s.timeNow().UTC())
 	s.DisconnectErrorMessage = error
+	s.trackErrorLocked(*s.DisconnectTime, "disconnect", error)
 	s.mu.Unlock()
 }
 
+// trackResponseDoneLocked tracks that the peer ACKed or NACKed a response.
+func (s *MutableStatus) trackResponseDoneLocked() {
+	if s.diagnostics.PendingResponses > 0 {
+		s.diagnostics.PendingResponses--
+	}
+}
+
+// trackErrorLocked records an error in the bounded error history.
+func (s *MutableStatus) trackErrorLocked(t time.Time, kind, msg string) {
+	s.diagnostics.RecentErrors = append(s.diagnostics.RecentErrors, StreamError{
+		Time:    t,
+		Kind:    kind,
+		Message: msg,
+	})
+	if n := len(s.diagnostics.RecentErrors); n > maxStreamErrorHistory {
+		s.diagnostics.RecentErrors = s.diagnostics.RecentErrors[n-maxStreamErrorHistory:]
+	}
+}
+
 func (s *MutableStatus) IsConnected() bool {
 	var resp bool
 
@@ -364,6 +479,40 @@ func (s *MutableStatus) GetStatus() Status {
 	return copy
 }
 
+func (s *MutableStatus) TrackMessageReceived() {
+	s.mu.Lock()
+	s.usage.MessagesReceived++
+	s.mu.Unlock()
+}
+
+func (s *MutableStatus) TrackMessageSent() {
+	s.mu.Lock()
+	s.usage.MessagesSent++
+	s.mu.Unlock()
+}
+
+func (s *MutableStatus) TrackMessageRateLimited() {
+	s.mu.Lock()
+	s.usage.MessagesRateLimited++
+	s.mu.Unlock()
+}
+
+func (s *MutableStatus) GetUsage() StreamUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.usage
+}
+
+func (s *MutableStatus) GetDiagnostics() StreamDiagnostics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	copy := s.diagnostics
+	copy.RecentErrors = append([]StreamError(nil), s.diagnostics.RecentErrors...)
+	return copy
+}
+
 func (s *MutableStatus) SetImportedServices(serviceNames []structs.ServiceName) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
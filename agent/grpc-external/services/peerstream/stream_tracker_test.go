@@ -324,3 +324,71 @@ func TestMutableStatus_TrackDisconnectedDueToError(t *testing.T) {
 	require.Equal(t, disconnectTime, s.DisconnectTime)
 	require.Equal(t, "disconnect err", s.DisconnectErrorMessage)
 }
+
+func TestTracker_StreamUsage(t *testing.T) {
+	tracker := NewTracker(defaultIncomingHeartbeatTimeout)
+
+	_, ok := tracker.StreamUsage("not-found")
+	require.False(t, ok)
+
+	status, err := tracker.Connected("peer-id")
+	require.NoError(t, err)
+
+	status.TrackMessageReceived()
+	status.TrackMessageReceived()
+	status.TrackMessageSent()
+	status.TrackMessageRateLimited()
+
+	usage, ok := tracker.StreamUsage("peer-id")
+	require.True(t, ok)
+	require.Equal(t, StreamUsage{
+		MessagesReceived:    2,
+		MessagesSent:        1,
+		MessagesRateLimited: 1,
+	}, usage)
+
+	// Usage is tracked separately from the stream status.
+	require.Equal(t, Status{Connected: true}, status.GetStatus())
+}
+
+func TestMutableStatus_Diagnostics(t *testing.T) {
+	it := incrementalTime{
+		base: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	s := newMutableStatus(it.Now, true)
+
+	s.TrackSendSuccess()
+	s.TrackSendSuccess()
+	s.TrackAck()
+	s.TrackNack("bad resource")
+	s.TrackRetry(3, 5*time.Second)
+	s.TrackDisconnectedDueToError("heartbeat timeout")
+
+	require.Equal(t, StreamDiagnostics{
+		PendingResponses: 0,
+		RetryAttempts:    3,
+		NextRetry:        ptr(it.base.Add(10 * time.Second)),
+		RecentErrors: []StreamError{
+			{Time: it.base.Add(4 * time.Second), Kind: "nack", Message: "bad resource"},
+			{Time: it.base.Add(6 * time.Second), Kind: "disconnect", Message: "heartbeat timeout"},
+		},
+	}, s.GetDiagnostics())
+
+	// Connecting resets the retry state but keeps the error history.
+	s.TrackConnected()
+	diag := s.GetDiagnostics()
+	require.Zero(t, diag.RetryAttempts)
+	require.Nil(t, diag.NextRetry)
+	require.Len(t, diag.RecentErrors, 2)
+
+	// The error history is bounded.
+	for i := 0; i < 2*maxStreamErrorHistory; i++ {
+		s.TrackRecvError("recv error")
+	}
+	diag = s.GetDiagnostics()
+	require.Len(t, diag.RecentErrors, maxStreamErrorHistory)
+	for _, e := range diag.RecentErrors {
+		require.Equal(t, "recv", e.Kind)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/lib/retry"
+	"github.com/hashicorp/consul/proto/pbconfigentry"
+	"github.com/hashicorp/consul/proto/pbpeering"
 	"github.com/hashicorp/consul/proto/pbservice"
 )
 
@@ -39,6 +41,35 @@ func (m *subscriptionManager) notifyExportedServicesForPeerID(ctx context.Contex
 	}, subExportedServiceList, state.updateCh)
 }
 
+func (m *subscriptionManager) notifyExportedConfigEntriesForPeerID(ctx context.Context, state *subscriptionState, peerID string) {
+	// Wait until this is subscribed-to.
+	select {
+	case <-m.configEntriesSubReady:
+	case <-ctx.Done():
+		return
+	}
+
+	m.syncViaBlockingQuery(ctx, "exported-config-entries", func(ctx context.Context, store StateStore, ws memdb.WatchSet) (interface{}, error) {
+		_, entries, err := store.ExportedConfigEntriesForPeer(ws, peerID, m.config.Datacenter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch exported config entries for peer %q: %w", peerID, err)
+		}
+
+		result := &pbpeering.PeeringConfigEntries{
+			Entries: make([]*pbconfigentry.ConfigEntry, 0, len(entries)),
+		}
+		for _, entry := range entries {
+			pbEntry := pbconfigentry.ConfigEntryFromStructs(entry)
+			// Raft indexes are local to this cluster and would otherwise
+			// cause spurious updates to be sent to the peer.
+			pbEntry.RaftIndex = nil
+			result.Entries = append(result.Entries, pbEntry)
+		}
+
+		return result, nil
+	}, subExportedConfigEntries, state.updateCh)
+}
+
 // TODO: add a new streaming subscription type to list-by-kind-and-partition since we're getting evictions
 func (m *subscriptionManager) notifyMeshGatewaysForPartition(ctx context.Context, state *subscriptionState, partition string) {
 	// Wait until this is subscribed-to.
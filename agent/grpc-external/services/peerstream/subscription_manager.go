@@ -14,6 +14,7 @@ import (
 
 	"github.com/hashicorp/consul/ipaddr"
 	"github.com/hashicorp/consul/lib/retry"
+	"github.com/hashicorp/consul/lib/stringslice"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/cache"
@@ -41,15 +42,16 @@ type SubscriptionBackend interface {
 
 // subscriptionManager handlers requests to subscribe to events from an events publisher.
 type subscriptionManager struct {
-	logger               hclog.Logger
-	config               Config
-	trustDomain          string
-	viewStore            MaterializedViewStore
-	backend              SubscriptionBackend
-	getStore             func() StateStore
-	serviceSubReady      <-chan struct{}
-	trustBundlesSubReady <-chan struct{}
-	serverAddrsSubReady  <-chan struct{}
+	logger                hclog.Logger
+	config                Config
+	trustDomain           string
+	viewStore             MaterializedViewStore
+	backend               SubscriptionBackend
+	getStore              func() StateStore
+	serviceSubReady       <-chan struct{}
+	trustBundlesSubReady  <-chan struct{}
+	serverAddrsSubReady   <-chan struct{}
+	configEntriesSubReady <-chan struct{}
 }
 
 // TODO(peering): Maybe centralize so that there is a single manager per datacenter, rather than per peering.
@@ -76,6 +78,9 @@ func newSubscriptionManager(
 		serviceSubReady:      remoteSubTracker.SubscribedChan(pbpeerstream.TypeURLExportedService),
 		trustBundlesSubReady: remoteSubTracker.SubscribedChan(pbpeerstream.TypeURLPeeringTrustBundle),
 		serverAddrsSubReady:  remoteSubTracker.SubscribedChan(pbpeerstream.TypeURLPeeringServerAddresses),
+		// Peers running older versions never subscribe to config entries,
+		// so they are only sent to peers that know how to handle them.
+		configEntriesSubReady: remoteSubTracker.SubscribedChan(pbpeerstream.TypeURLPeeringConfigEntries),
 	}
 }
 
@@ -92,6 +97,7 @@ func (m *subscriptionManager) subscribe(ctx context.Context, peerID, peerName, p
 
 	// Wrap our bare state store queries in goroutines that emit events.
 	go m.notifyExportedServicesForPeerID(ctx, state, peerID)
+	go m.notifyExportedConfigEntriesForPeerID(ctx, state, peerID)
 	go m.notifyServerAddrUpdates(ctx, state.updateCh)
 	if m.config.ConnectEnabled {
 		go m.notifyMeshGatewaysForPartition(ctx, state, state.partition)
@@ -138,8 +144,20 @@ func (m *subscriptionManager) handleEvent(ctx context.Context, state *subscripti
 			return fmt.Errorf("invalid type for response: %T", u.Result)
 		}
 
+		prev := state.exportList
 		state.exportList = evt
 
+		// Resubscribe to services whose redacted fields changed so that the
+		// peer receives them with the new redactions applied.
+		if prev != nil {
+			for svc, cancel := range state.watchedServices {
+				if !stringslice.Equal(prev.RedactedFields[svc], evt.RedactedFields[svc]) {
+					cancel()
+					delete(state.watchedServices, svc)
+				}
+			}
+		}
+
 		pending := &pendingPayload{}
 		m.syncNormalServices(ctx, state, evt.Services)
 		if m.config.ConnectEnabled {
@@ -193,7 +211,10 @@ func (m *subscriptionManager) handleEvent(ctx context.Context, state *subscripti
 			// skip checks since we just generated one from scratch
 		}
 
-		id := servicePayloadIDPrefix + strings.TrimPrefix(u.CorrelationID, subExportedService)
+		serviceName := strings.TrimPrefix(u.CorrelationID, subExportedService)
+		redactFields(csn, state.redactedFieldsFor(serviceName))
+
+		id := servicePayloadIDPrefix + serviceName
 
 		// Just ferry this one directly along to the destination.
 		pending := &pendingPayload{}
@@ -274,6 +295,18 @@ func (m *subscriptionManager) handleEvent(ctx context.Context, state *subscripti
 
 		state.sendPendingEvents(ctx, m.logger, pending)
 
+	case u.CorrelationID == subExportedConfigEntries:
+		entries, ok := u.Result.(*pbpeering.PeeringConfigEntries)
+		if !ok {
+			return fmt.Errorf("invalid type for response: %T", u.Result)
+		}
+		pending := &pendingPayload{}
+		if err := pending.Add(exportedConfigEntriesPayloadID, u.CorrelationID, entries); err != nil {
+			return err
+		}
+
+		state.sendPendingEvents(ctx, m.logger, pending)
+
 	case u.CorrelationID == subServerAddrs:
 		addrs, ok := u.Result.(*pbpeering.PeeringServerAddresses)
 		if !ok {
@@ -291,6 +324,32 @@ func (m *subscriptionManager) handleEvent(ctx context.Context, state *subscripti
 	return nil
 }
 
+// redactFields removes the given instance data fields from the exported
+// service instances.
+func redactFields(orig *pbservice.IndexedCheckServiceNodes, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	for i := range orig.Nodes {
+		// The instances are shared with the materialized view, so they must
+		// be copied before being modified.
+		csn := proto.Clone(orig.Nodes[i]).(*pbservice.CheckServiceNode)
+		for _, field := range fields {
+			switch field {
+			case structs.RedactFieldNodeMeta:
+				csn.Node.Meta = nil
+			case structs.RedactFieldNodeTaggedAddresses:
+				csn.Node.TaggedAddresses = nil
+			case structs.RedactFieldServiceMeta:
+				csn.Service.Meta = nil
+			case structs.RedactFieldServiceTags:
+				csn.Service.Tags = nil
+			}
+		}
+		orig.Nodes[i] = csn
+	}
+}
+
 func filterConnectReferences(orig *pbservice.IndexedCheckServiceNodes) {
 	newNodes := make([]*pbservice.CheckServiceNode, 0, len(orig.Nodes))
 	for i := range orig.Nodes {
@@ -722,9 +781,10 @@ func flattenChecks(
 }
 
 const (
-	subExportedServiceList = "exported-service-list"
-	subExportedService     = "exported-service:"
-	subMeshGateway         = "mesh-gateway:"
+	subExportedServiceList   = "exported-service-list"
+	subExportedService       = "exported-service:"
+	subExportedConfigEntries = "exported-config-entries"
+	subMeshGateway           = "mesh-gateway:"
 )
 
 // NotifyStandardService will notify the given channel when there are updates
@@ -1136,6 +1136,35 @@ func newStateStore(t *testing.T, publisher *stream.EventPublisher) (*state.Store
 	return store, handler
 }
 
+func TestRedactFields(t *testing.T) {
+	newInstance := func() *pbservice.CheckServiceNode {
+		node := pbNode("foo", "10.0.0.1", "")
+		node.Meta = map[string]string{"env": "prod"}
+		node.TaggedAddresses = map[string]string{"lan": "10.0.0.1"}
+
+		svc := pbService("", "api-1", "api", 8080, nil)
+		svc.Meta = map[string]string{"version": "2"}
+		svc.Tags = []string{"primary"}
+
+		return &pbservice.CheckServiceNode{Node: node, Service: svc}
+	}
+
+	orig := newInstance()
+	csn := &pbservice.IndexedCheckServiceNodes{
+		Nodes: []*pbservice.CheckServiceNode{orig},
+	}
+
+	redactFields(csn, []string{structs.RedactFieldNodeMeta, structs.RedactFieldServiceTags})
+
+	expect := newInstance()
+	expect.Node.Meta = nil
+	expect.Service.Tags = nil
+	prototest.AssertDeepEqual(t, expect, csn.Nodes[0])
+
+	// The original instance is shared with the view and must not be modified.
+	prototest.AssertDeepEqual(t, newInstance(), orig)
+}
+
 func expectEvents(
 	t *testing.T,
 	ch <-chan cache.UpdateEvent,
@@ -53,6 +53,20 @@ func newSubscriptionState(peerName, partition string) *subscriptionState {
 	}
 }
 
+// redactedFieldsFor returns the fields to redact from the exported service
+// with the given name, formatted as by structs.ServiceName.String.
+func (s *subscriptionState) redactedFieldsFor(name string) []string {
+	if s.exportList == nil {
+		return nil
+	}
+	for sn, fields := range s.exportList.RedactedFields {
+		if sn.String() == name {
+			return fields
+		}
+	}
+	return nil
+}
+
 func (s *subscriptionState) sendPendingEvents(
 	ctx context.Context,
 	logger hclog.Logger,
@@ -99,6 +113,9 @@ func (s *subscriptionState) cleanupEventVersions(logger hclog.Logger) {
 		case id == exportedServiceListID:
 			keep = true
 
+		case id == exportedConfigEntriesPayloadID:
+			keep = true
+
 		case strings.HasPrefix(id, servicePayloadIDPrefix):
 			name := strings.TrimPrefix(id, servicePayloadIDPrefix)
 			sn := structs.ServiceNameFromString(name)
@@ -135,12 +152,13 @@ type pendingEvent struct {
 }
 
 const (
-	serverAddrsPayloadID          = "server-addrs"
-	caRootsPayloadID              = "roots"
-	meshGatewayPayloadID          = "mesh-gateway"
-	exportedServiceListID         = "exported-service-list"
-	servicePayloadIDPrefix        = "service:"
-	discoveryChainPayloadIDPrefix = "chain:"
+	serverAddrsPayloadID           = "server-addrs"
+	caRootsPayloadID               = "roots"
+	meshGatewayPayloadID           = "mesh-gateway"
+	exportedServiceListID          = "exported-service-list"
+	exportedConfigEntriesPayloadID = "exported-config-entries"
+	servicePayloadIDPrefix         = "service:"
+	discoveryChainPayloadIDPrefix  = "chain:"
 )
 
 func (p *pendingPayload) Add(id string, correlationID string, raw interface{}) error {
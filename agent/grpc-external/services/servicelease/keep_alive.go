@@ -0,0 +1,148 @@
+package servicelease
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/acl"
+	external "github.com/hashicorp/consul/agent/grpc-external"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto-public/pbservicelease"
+)
+
+// KeepAlive renews the leases of the services in the request for as long as
+// the stream is open. Each lease is renewed every third of its TTL, so that a
+// lease survives a missed renewal, and the client only has to keep the stream
+// open for its services to stay registered.
+func (s *Server) KeepAlive(req *pbservicelease.KeepAliveRequest, serverStream pbservicelease.ServiceLeaseService_KeepAliveServer) error {
+	logger := s.Logger.Named("keep-alive").With("request_id", external.TraceID())
+
+	logger.Debug("starting stream")
+	defer logger.Trace("stream closed")
+
+	if len(req.ServiceIds) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one service ID is required")
+	}
+
+	options, err := external.QueryOptionsFromContext(serverStream.Context())
+	if err != nil {
+		return err
+	}
+	entMeta := acl.NewEnterpriseMetaWithPartition(req.Partition, req.Namespace)
+
+	ctx, cancel := context.WithCancel(serverStream.Context())
+	defer cancel()
+
+	// The stream isn't safe for concurrent use, so the leases are renewed
+	// concurrently and their responses are sent from here.
+	responses := make(chan *pbservicelease.KeepAliveResponse)
+	errCh := make(chan error, len(req.ServiceIds))
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for _, id := range req.ServiceIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			k := keepAlive{
+				Server: s,
+				sid:    structs.NewServiceID(id, &entMeta),
+				token:  options.Token,
+				logger: logger.With("service", id),
+			}
+			if err := k.run(ctx, responses); err != nil {
+				errCh <- err
+				cancel()
+			}
+		}(id)
+	}
+	go func() {
+		wg.Wait()
+		close(responses)
+	}()
+
+	for rsp := range responses {
+		if err := serverStream.Send(rsp); err != nil {
+			logger.Error("failed to send response", "error", err)
+			cancel()
+			for range responses {
+			}
+			return err
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+type keepAlive struct {
+	*Server
+	sid    structs.ServiceID
+	token  string
+	logger hclog.Logger
+}
+
+// run renews the lease of the service until it no longer has one or ctx is
+// cancelled.
+func (k keepAlive) run(ctx context.Context, responses chan<- *pbservicelease.KeepAliveResponse) error {
+	send := func(rsp *pbservicelease.KeepAliveResponse) bool {
+		rsp.ServiceId = k.sid.ID
+		select {
+		case responses <- rsp:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		ttl, ok, err := k.Backend.RenewServiceLease(k.sid, k.token)
+		if err != nil {
+			return k.toStatus(ctx, err)
+		}
+		if !ok {
+			send(&pbservicelease.KeepAliveResponse{Event: pbservicelease.KeepAliveEvent_KEEP_ALIVE_EVENT_LEASE_NOT_FOUND})
+			return nil
+		}
+		if !send(&pbservicelease.KeepAliveResponse{
+			Event: pbservicelease.KeepAliveEvent_KEEP_ALIVE_EVENT_RENEWED,
+			Ttl:   durationpb.New(ttl),
+		}) {
+			return nil
+		}
+
+		select {
+		case <-time.After(ttl / 3):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (k keepAlive) toStatus(ctx context.Context, err error) error {
+	switch {
+	case ctx.Err() != nil:
+		return nil
+	case acl.IsErrNotFound(err):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case acl.IsErrPermissionDenied(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		k.logger.Error("failed to renew service lease", "error", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,142 @@
+package servicelease
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/grpc-external/testutils"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto-public/pbservicelease"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestKeepAlive(t *testing.T) {
+	ttls := map[string]time.Duration{
+		"web":   150 * time.Millisecond,
+		"batch": time.Hour,
+	}
+	backend := newFakeBackend()
+	for id, ttl := range ttls {
+		backend.leases[id] = ttl
+	}
+
+	client := testClient(t, backend)
+	stream, err := client.KeepAlive(context.Background(), &pbservicelease.KeepAliveRequest{
+		ServiceIds: []string{"web", "batch", "web"},
+	})
+	require.NoError(t, err)
+
+	// Both leases are renewed immediately, and then every third of their TTL.
+	renewed := make(map[string]int)
+	for renewed["web"] < 3 {
+		rsp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, pbservicelease.KeepAliveEvent_KEEP_ALIVE_EVENT_RENEWED, rsp.Event)
+		require.Equal(t, ttls[rsp.ServiceId], rsp.Ttl.AsDuration())
+		renewed[rsp.ServiceId]++
+	}
+	require.Equal(t, 1, renewed["batch"])
+
+	// The services that no longer have a lease are pushed at their next
+	// renewal.
+	backend.remove("web")
+	rsp := mustGetLeaseNotFound(t, stream)
+	require.Equal(t, "web", rsp.ServiceId)
+	require.Nil(t, rsp.Ttl)
+}
+
+func TestKeepAlive_UnknownService(t *testing.T) {
+	client := testClient(t, newFakeBackend())
+	stream, err := client.KeepAlive(context.Background(), &pbservicelease.KeepAliveRequest{ServiceIds: []string{"nope"}})
+	require.NoError(t, err)
+
+	rsp := mustGetLeaseNotFound(t, stream)
+	require.Equal(t, "nope", rsp.ServiceId)
+
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestKeepAlive_Errors(t *testing.T) {
+	backend := newFakeBackend()
+	client := testClient(t, backend)
+
+	stream, err := client.KeepAlive(context.Background(), &pbservicelease.KeepAliveRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	backend.err = acl.ErrPermissionDenied
+	stream, err = client.KeepAlive(context.Background(), &pbservicelease.KeepAliveRequest{ServiceIds: []string{"web"}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func mustGetLeaseNotFound(t *testing.T, stream pbservicelease.ServiceLeaseService_KeepAliveClient) *pbservicelease.KeepAliveResponse {
+	t.Helper()
+
+	for {
+		rsp, err := stream.Recv()
+		require.NoError(t, err)
+		if rsp.Event == pbservicelease.KeepAliveEvent_KEEP_ALIVE_EVENT_LEASE_NOT_FOUND {
+			return rsp
+		}
+	}
+}
+
+func testClient(t *testing.T, backend Backend) pbservicelease.ServiceLeaseServiceClient {
+	t.Helper()
+
+	server := NewServer(Config{
+		Backend: backend,
+		Logger:  testutil.Logger(t),
+	})
+	addr := testutils.RunTestServer(t, server)
+
+	//nolint:staticcheck
+	conn, err := grpc.DialContext(context.Background(), addr.String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	return pbservicelease.NewServiceLeaseServiceClient(conn)
+}
+
+// fakeBackend holds the TTLs of the leases of the services in memory.
+type fakeBackend struct {
+	mu     sync.Mutex
+	leases map[string]time.Duration
+	err    error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{leases: make(map[string]time.Duration)}
+}
+
+func (b *fakeBackend) remove(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.leases, id)
+}
+
+func (b *fakeBackend) RenewServiceLease(sid structs.ServiceID, token string) (time.Duration, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return 0, false, b.err
+	}
+	ttl, ok := b.leases[sid.ID]
+	return ttl, ok, nil
+}
@@ -0,0 +1,36 @@
+package servicelease
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto-public/pbservicelease"
+)
+
+type Server struct {
+	Config
+}
+
+type Config struct {
+	Backend Backend
+	Logger  hclog.Logger
+}
+
+// Backend is used to renew the leases of the services. It is implemented by
+// the agent, which holds the leases of its services.
+type Backend interface {
+	// RenewServiceLease renews the lease of a service and returns its TTL, or
+	// false if the service isn't registered with a lease.
+	RenewServiceLease(sid structs.ServiceID, token string) (time.Duration, bool, error)
+}
+
+func NewServer(cfg Config) *Server {
+	return &Server{cfg}
+}
+
+func (s *Server) Register(grpcServer *grpc.Server) {
+	pbservicelease.RegisterServiceLeaseServiceServer(grpcServer, s)
+}
@@ -0,0 +1,242 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/acl"
+	external "github.com/hashicorp/consul/agent/grpc-external"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto-public/pbsession"
+)
+
+// maxWaitTime is how long a session without a TTL is watched before the
+// blocking query is restarted.
+const maxWaitTime = 10 * time.Minute
+
+// KeepAlive renews the sessions in the request for as long as the stream is
+// open, and pushes a response as soon as one of them is invalidated. Each
+// session is watched with a blocking query that times out when the session is
+// due for renewal, so a single stream replaces both the periodic renewals
+// and the blocking queries lock holders would otherwise make.
+func (s *Server) KeepAlive(req *pbsession.KeepAliveRequest, serverStream pbsession.SessionService_KeepAliveServer) error {
+	logger := s.Logger.Named("keep-alive").With("request_id", external.TraceID())
+
+	logger.Debug("starting stream")
+	defer logger.Trace("stream closed")
+
+	if len(req.SessionIds) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one session ID is required")
+	}
+
+	options, err := external.QueryOptionsFromContext(serverStream.Context())
+	if err != nil {
+		return err
+	}
+	entMeta := acl.NewEnterpriseMetaWithPartition(req.Partition, req.Namespace)
+
+	err = s.keepAlive(serverStream.Context(), logger, options.Token, req.SessionIds, entMeta, serverStream.Send)
+	switch {
+	case err == nil:
+		return nil
+	case acl.IsErrNotFound(err):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case acl.IsErrPermissionDenied(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// KeepAliveSessions renews the sessions for as long as ctx isn't cancelled,
+// and calls send every time one of them is renewed or invalidated, in the
+// same way as the KeepAlive stream. It returns once all of the sessions are
+// invalidated, or with the first error. send is never called concurrently.
+func (s *Server) KeepAliveSessions(ctx context.Context, token string, ids []string, entMeta acl.EnterpriseMeta, send func(*pbsession.KeepAliveResponse) error) error {
+	return s.keepAlive(ctx, s.Logger.Named("keep-alive"), token, ids, entMeta, send)
+}
+
+func (s *Server) keepAlive(ctx context.Context, logger hclog.Logger, token string, ids []string, entMeta acl.EnterpriseMeta, send func(*pbsession.KeepAliveResponse) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The sessions are watched concurrently, but their responses are sent
+	// from here as the stream isn't safe for concurrent use.
+	responses := make(chan *pbsession.KeepAliveResponse)
+	errCh := make(chan error, len(ids))
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			k := keepAlive{
+				Server:    s,
+				sessionID: id,
+				token:     token,
+				entMeta:   entMeta,
+				logger:    logger.With("session", id),
+			}
+			if err := k.run(ctx, responses); err != nil {
+				errCh <- err
+				cancel()
+			}
+		}(id)
+	}
+	go func() {
+		wg.Wait()
+		close(responses)
+	}()
+
+	for rsp := range responses {
+		if err := send(rsp); err != nil {
+			logger.Error("failed to send response", "error", err)
+			cancel()
+			for range responses {
+			}
+			return err
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+type keepAlive struct {
+	*Server
+	sessionID string
+	token     string
+	entMeta   acl.EnterpriseMeta
+	logger    hclog.Logger
+}
+
+// run renews the session until it is invalidated or ctx is cancelled.
+func (k keepAlive) run(ctx context.Context, responses chan<- *pbsession.KeepAliveResponse) error {
+	send := func(rsp *pbsession.KeepAliveResponse) bool {
+		rsp.SessionId = k.sessionID
+		select {
+		case responses <- rsp:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var (
+		index   uint64
+		renewAt time.Time
+		renew   = true
+	)
+	for {
+		if renew {
+			session, err := k.renew(ctx)
+			if err != nil {
+				return k.checkErr(ctx, "renew", err)
+			}
+			if session == nil {
+				send(&pbsession.KeepAliveResponse{Event: pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_INVALIDATED})
+				return nil
+			}
+
+			// Sessions without a TTL never need to be renewed again, but they
+			// are still watched so their invalidation is pushed.
+			rsp := &pbsession.KeepAliveResponse{Event: pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_RENEWED}
+			renewAt = time.Time{}
+			if ttl, _ := time.ParseDuration(session.TTL); ttl > 0 {
+				rsp.Ttl = durationpb.New(ttl)
+				renewAt = time.Now().Add(ttl / 2)
+			}
+			if !send(rsp) {
+				return nil
+			}
+		}
+
+		wait := maxWaitTime
+		if !renewAt.IsZero() {
+			if d := time.Until(renewAt); d < wait {
+				wait = d
+			}
+		}
+		if wait > 0 {
+			var exists bool
+			var err error
+			if index, exists, err = k.watch(ctx, index, wait); err != nil {
+				return k.checkErr(ctx, "watch", err)
+			}
+			if !exists {
+				send(&pbsession.KeepAliveResponse{Event: pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_INVALIDATED})
+				return nil
+			}
+		}
+		renew = !renewAt.IsZero() && !time.Now().Before(renewAt)
+	}
+}
+
+func (k keepAlive) renew(ctx context.Context) (*structs.Session, error) {
+	args := structs.SessionSpecificRequest{
+		Datacenter:     k.Datacenter,
+		SessionID:      k.sessionID,
+		EnterpriseMeta: k.entMeta,
+		QueryOptions:   structs.QueryOptions{Token: k.token},
+	}
+	var reply structs.IndexedSessions
+	if err := k.Backend.RPC(ctx, "Session.Renew", &args, &reply); err != nil {
+		return nil, err
+	}
+	if len(reply.Sessions) == 0 {
+		return nil, nil
+	}
+	return reply.Sessions[0], nil
+}
+
+// watch blocks until the session changes or wait elapses, and returns
+// whether the session still exists.
+func (k keepAlive) watch(ctx context.Context, index uint64, wait time.Duration) (uint64, bool, error) {
+	args := structs.SessionSpecificRequest{
+		Datacenter:     k.Datacenter,
+		SessionID:      k.sessionID,
+		EnterpriseMeta: k.entMeta,
+		QueryOptions: structs.QueryOptions{
+			Token:         k.token,
+			MinQueryIndex: index,
+			MaxQueryTime:  wait,
+		},
+	}
+	var reply structs.IndexedSessions
+	if err := k.Backend.RPC(ctx, "Session.Get", &args, &reply); err != nil {
+		return 0, false, err
+	}
+	return reply.Index, len(reply.Sessions) != 0, nil
+}
+
+// checkErr returns the error of an operation on the session, which is nil if
+// it was cancelled with the stream.
+func (k keepAlive) checkErr(ctx context.Context, op string, err error) error {
+	switch {
+	case ctx.Err() != nil:
+		return nil
+	case acl.IsErrNotFound(err), acl.IsErrPermissionDenied(err), errors.Is(err, context.DeadlineExceeded):
+		return err
+	default:
+		k.logger.Error("failed to "+op+" session", "error", err)
+		return err
+	}
+}
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/grpc-external/testutils"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto-public/pbsession"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestKeepAlive(t *testing.T) {
+	backend := newFakeBackend()
+	backend.sessions["ttl"] = &structs.Session{ID: "ttl", TTL: "100ms"}
+	backend.sessions["no-ttl"] = &structs.Session{ID: "no-ttl"}
+
+	client := testClient(t, backend)
+	stream, err := client.KeepAlive(context.Background(), &pbsession.KeepAliveRequest{
+		SessionIds: []string{"ttl", "no-ttl", "ttl"},
+	})
+	require.NoError(t, err)
+
+	// Both sessions are renewed immediately, and the session with a TTL is
+	// renewed again every half of its TTL.
+	renewed := make(map[string]int)
+	for renewed["ttl"] < 3 {
+		rsp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_RENEWED, rsp.Event)
+		if rsp.SessionId == "ttl" {
+			require.Equal(t, 100*time.Millisecond, rsp.Ttl.AsDuration())
+		} else {
+			require.Nil(t, rsp.Ttl)
+		}
+		renewed[rsp.SessionId]++
+	}
+	require.Equal(t, 1, renewed["no-ttl"])
+
+	// Invalidated sessions are pushed, and the stream ends once all of them
+	// are gone.
+	backend.destroy("no-ttl")
+	rsp := mustGetInvalidated(t, stream)
+	require.Equal(t, "no-ttl", rsp.SessionId)
+
+	backend.destroy("ttl")
+	rsp = mustGetInvalidated(t, stream)
+	require.Equal(t, "ttl", rsp.SessionId)
+
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestKeepAlive_UnknownSession(t *testing.T) {
+	client := testClient(t, newFakeBackend())
+	stream, err := client.KeepAlive(context.Background(), &pbsession.KeepAliveRequest{SessionIds: []string{"nope"}})
+	require.NoError(t, err)
+
+	rsp := mustGetInvalidated(t, stream)
+	require.Equal(t, "nope", rsp.SessionId)
+}
+
+func TestKeepAlive_Errors(t *testing.T) {
+	backend := newFakeBackend()
+	client := testClient(t, backend)
+
+	stream, err := client.KeepAlive(context.Background(), &pbsession.KeepAliveRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	backend.err = acl.ErrPermissionDenied
+	stream, err = client.KeepAlive(context.Background(), &pbsession.KeepAliveRequest{SessionIds: []string{"foo"}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func mustGetInvalidated(t *testing.T, stream pbsession.SessionService_KeepAliveClient) *pbsession.KeepAliveResponse {
+	t.Helper()
+
+	for {
+		rsp, err := stream.Recv()
+		require.NoError(t, err)
+		if rsp.Event == pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_INVALIDATED {
+			return rsp
+		}
+	}
+}
+
+func testClient(t *testing.T, backend Backend) pbsession.SessionServiceClient {
+	t.Helper()
+
+	server := NewServer(Config{
+		Backend:    backend,
+		Logger:     testutil.Logger(t),
+		Datacenter: "dc1",
+	})
+	addr := testutils.RunTestServer(t, server)
+
+	//nolint:staticcheck
+	conn, err := grpc.DialContext(context.Background(), addr.String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	return pbsession.NewSessionServiceClient(conn)
+}
+
+// fakeBackend serves the session RPCs from memory, with blocking queries
+// that return when a session is destroyed.
+type fakeBackend struct {
+	mu       sync.Mutex
+	sessions map[string]*structs.Session
+	index    uint64
+	changed  chan struct{}
+	err      error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		sessions: make(map[string]*structs.Session),
+		index:    1,
+		changed:  make(chan struct{}),
+	}
+}
+
+func (b *fakeBackend) destroy(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.sessions, id)
+	b.index++
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *fakeBackend) RPC(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	req := args.(*structs.SessionSpecificRequest)
+	out := reply.(*structs.IndexedSessions)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+	if req.Datacenter != "dc1" {
+		return structs.ErrNoDCPath
+	}
+
+	if method == "Session.Get" && req.MinQueryIndex >= b.index {
+		changed := b.changed
+		b.mu.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(req.MaxQueryTime):
+		case <-ctx.Done():
+		}
+		b.mu.Lock()
+	}
+
+	out.Index = b.index
+	out.Sessions = nil
+	if s, ok := b.sessions[req.SessionID]; ok {
+		out.Sessions = structs.Sessions{s}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/consul/proto-public/pbsession"
+)
+
+type Server struct {
+	Config
+}
+
+type Config struct {
+	Backend    Backend
+	Logger     hclog.Logger
+	Datacenter string
+}
+
+// Backend is used to renew and watch sessions. It is implemented by the
+// agent, which forwards the requests to the servers.
+type Backend interface {
+	RPC(ctx context.Context, method string, args interface{}, reply interface{}) error
+}
+
+func NewServer(cfg Config) *Server {
+	return &Server{cfg}
+}
+
+func (s *Server) Register(grpcServer *grpc.Server) {
+	pbsession.RegisterSessionServiceServer(grpcServer, s)
+}
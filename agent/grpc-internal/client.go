@@ -67,6 +67,10 @@ const grpcServiceConfig = `
 					"service": "hashicorp.consul.dataplane.DataplaneService",
 					"method": "GetSupportedDataplaneFeatures"
 				},
+				{
+					"service": "hashicorp.consul.dataplane.DataplaneService",
+					"method": "NegotiateDataplaneFeatures"
+				},
 				{
 					"service": "hashicorp.consul.dns.DNSService",
 					"method": "Query"
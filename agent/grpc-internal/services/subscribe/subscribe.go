@@ -55,6 +55,15 @@ func (h *Server) Subscribe(req *pbsubscribe.SubscribeRequest, serverStream pbsub
 		return err
 	}
 
+	// A wildcard subscription to the health of the services streams the whole
+	// catalog, so it is limited to the tokens that can read it as operators.
+	// The events are still filtered by the service and node permissions.
+	if req.Topic == pbsubscribe.Topic_ServiceHealth && req.GetWildcardSubject() {
+		if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+			return err
+		}
+	}
+
 	subReq, err := state.PBToStreamSubscribeRequest(req, entMeta)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, err.Error())
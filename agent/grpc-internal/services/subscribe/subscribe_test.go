@@ -358,7 +358,7 @@ func newTestBackend(t *testing.T) *testBackend {
 	// and go during snapshot restores. For the purposes of this test backend though we
 	// just register them directly to
 	require.NoError(t, publisher.RegisterHandler(state.EventTopicCARoots, store.CARootsSnapshot, false))
-	require.NoError(t, publisher.RegisterHandler(state.EventTopicServiceHealth, store.ServiceHealthSnapshot, false))
+	require.NoError(t, publisher.RegisterHandler(state.EventTopicServiceHealth, store.ServiceHealthSnapshot, true))
 	require.NoError(t, publisher.RegisterHandler(state.EventTopicServiceHealthConnect, store.ServiceHealthSnapshot, false))
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -882,6 +882,120 @@ node "node1" {
 	})
 }
 
+func TestServer_Subscribe_IntegrationWithBackend_WildcardFilterEventsByACLToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	backend := newTestBackend(t)
+	addr := runTestServer(t, NewServer(backend, hclog.New(nil)))
+	token := "this-token-is-good"
+	tokenWithoutOperator := "this-token-is-not-an-operator"
+
+	testutil.RunStep(t, "create ACL policies", func(t *testing.T) {
+		rules := `
+service "foo" {
+	policy = "read"
+}
+node "node1" {
+	policy = "read"
+}
+`
+		cfg := &acl.Config{WildcardName: structs.WildcardSpecifier}
+		withoutOperator, err := acl.NewAuthorizerFromRules(rules, cfg, nil)
+		require.NoError(t, err)
+		withOperator, err := acl.NewAuthorizerFromRules(rules+`operator = "read"`, cfg, nil)
+		require.NoError(t, err)
+
+		backend.authorizer = func(tok string, _ *acl.EnterpriseMeta) acl.Authorizer {
+			switch tok {
+			case token:
+				return acl.NewChainedAuthorizer([]acl.Authorizer{withOperator, acl.DenyAll()})
+			case tokenWithoutOperator:
+				return acl.NewChainedAuthorizer([]acl.Authorizer{withoutOperator, acl.DenyAll()})
+			}
+			return acl.DenyAll()
+		}
+	})
+
+	ids := newCounter()
+	register := func(t *testing.T, service string, port int) {
+		req := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				ID:      service,
+				Service: service,
+				Port:    port,
+			},
+		}
+		require.NoError(t, backend.store.EnsureRegistration(ids.Next(service), req))
+	}
+
+	testutil.RunStep(t, "register services", func(t *testing.T) {
+		register(t, "foo", 1000)
+		register(t, "bar", 1000)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	//nolint:staticcheck
+	conn, err := gogrpc.DialContext(ctx, addr.String(), gogrpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(logError(t, conn.Close))
+	streamClient := pbsubscribe.NewStateChangeSubscriptionClient(conn)
+
+	subscribe := func(t *testing.T, token string) chan eventOrError {
+		streamHandle, err := streamClient.Subscribe(ctx, &pbsubscribe.SubscribeRequest{
+			Topic:   pbsubscribe.Topic_ServiceHealth,
+			Subject: &pbsubscribe.SubscribeRequest_WildcardSubject{WildcardSubject: true},
+			Token:   token,
+		})
+		require.NoError(t, err)
+
+		chEvents := make(chan eventOrError, 0)
+		go recvEvents(chEvents, streamHandle)
+		return chEvents
+	}
+
+	testutil.RunStep(t, "a wildcard subscription requires operator:read", func(t *testing.T) {
+		chEvents := subscribe(t, tokenWithoutOperator)
+		select {
+		case item := <-chEvents:
+			require.Error(t, item.err, "got event instead of an error: %v", item.event)
+			require.True(t, acl.IsErrPermissionDenied(item.err), "unexpected error: %v", item.err)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for permission denied error")
+		}
+	})
+
+	chEvents := make(chan eventOrError, 0)
+
+	testutil.RunStep(t, "the snapshot only has the readable services", func(t *testing.T) {
+		chEvents = subscribe(t, token)
+
+		event := getEvent(t, chEvents)
+		require.Equal(t, "foo", event.GetServiceHealth().CheckServiceNode.Service.Service)
+		require.True(t, getEvent(t, chEvents).GetEndOfSnapshot())
+	})
+
+	testutil.RunStep(t, "updates to the other services should not send an event", func(t *testing.T) {
+		register(t, "bar", 2000)
+		assertNoEvents(t, chEvents)
+	})
+
+	testutil.RunStep(t, "updates to the readable services send an event", func(t *testing.T) {
+		register(t, "foo", 2000)
+
+		event := getEvent(t, chEvents)
+		service := event.GetServiceHealth().CheckServiceNode.Service
+		require.Equal(t, "foo", service.Service)
+		require.Equal(t, int32(2000), service.Port)
+	})
+}
+
 func TestServer_Subscribe_IntegrationWithBackend_ACLUpdate(t *testing.T) {
 	backend := newTestBackend(t)
 	addr := runTestServer(t, NewServer(backend, hclog.New(nil)))
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/agent/consul/rate"
+)
+
+// AuditInterceptor provides gRPC interceptors recording the calls to the
+// services in the audit log.
+type AuditInterceptor struct {
+	Auditor *audit.Logger
+
+	// AccessorID returns the accessor of the ACL token with the given secret,
+	// or an empty string if it can't be resolved.
+	AccessorID func(secretID string) string
+}
+
+// InterceptUnary records the non-streaming gRPC calls once they returned.
+func (a *AuditInterceptor) InterceptUnary(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	a.record(ctx, info.FullMethod, err)
+	return resp, err
+}
+
+// InterceptStream records the streaming gRPC calls once they ended.
+func (a *AuditInterceptor) InterceptStream(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	err := handler(srv, ss)
+	a.record(ss.Context(), info.FullMethod, err)
+	return err
+}
+
+func (a *AuditInterceptor) record(ctx context.Context, fullMethod string, err error) {
+	ev := &audit.Event{
+		Protocol:  audit.ProtocolGRPC,
+		Operation: audit.OperationRead,
+		Resource:  fullMethod,
+		Result:    auditResultForError(err),
+	}
+	if rpcRateLimitSpecs[fullMethod] == rate.OperationTypeWrite {
+		ev.Operation = audit.OperationWrite
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ev.Actor.RemoteAddr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ev.Actor.RemoteAddr); err == nil {
+			ev.Actor.RemoteAddr = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && a.AccessorID != nil {
+		if tokens := md.Get("x-consul-token"); len(tokens) > 0 && tokens[0] != "" {
+			ev.Actor.AccessorID = a.AccessorID(tokens[0])
+		}
+	}
+
+	a.Auditor.Record(ev)
+}
+
+func auditResultForError(err error) audit.Result {
+	code := status.Code(err)
+	result := audit.Result{Status: audit.ResultFailure, Code: int(code)}
+	switch code {
+	case codes.OK:
+		result.Status = audit.ResultSuccess
+	case codes.PermissionDenied, codes.Unauthenticated:
+		result.Status = audit.ResultDenied
+	}
+	return result
+}
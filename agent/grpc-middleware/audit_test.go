@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestAuditInterceptor_InterceptUnary(t *testing.T) {
+	dir := testutil.TempDir(t, "audit")
+	auditor, err := audit.New(audit.Config{
+		Enabled: true,
+		Sinks: []audit.SinkConfig{{
+			Name:              "file",
+			Type:              audit.SinkTypeFile,
+			Format:            audit.FormatJSON,
+			DeliveryGuarantee: audit.DeliveryGuaranteeBestEffort,
+			Path:              filepath.Join(dir, "audit.json"),
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	interceptor := &AuditInterceptor{
+		Auditor: auditor,
+		AccessorID: func(secretID string) string {
+			return "accessor-of-" + secretID
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 12345},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-consul-token", "secret"))
+
+	ok := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	denied := func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "Permission denied")
+	}
+
+	resp, err := interceptor.InterceptUnary(ctx, nil, &grpc.UnaryServerInfo{
+		FullMethod: "/hashicorp.consul.internal.peering.PeeringService/PeeringWrite",
+	}, ok)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	_, err = interceptor.InterceptUnary(context.Background(), nil, &grpc.UnaryServerInfo{
+		FullMethod: "/hashicorp.consul.dns.DNSService/Query",
+	}, denied)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, auditor.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []audit.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev audit.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		events = append(events, ev)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 2)
+
+	require.Equal(t, audit.ProtocolGRPC, events[0].Protocol)
+	require.Equal(t, audit.Actor{AccessorID: "accessor-of-secret", RemoteAddr: "10.0.0.1"}, events[0].Actor)
+	require.Equal(t, audit.OperationWrite, events[0].Operation)
+	require.Equal(t, "/hashicorp.consul.internal.peering.PeeringService/PeeringWrite", events[0].Resource)
+	require.Equal(t, audit.Result{Status: audit.ResultSuccess, Code: int(codes.OK)}, events[0].Result)
+
+	require.Equal(t, audit.Actor{}, events[1].Actor)
+	require.Equal(t, audit.OperationRead, events[1].Operation)
+	require.Equal(t, audit.Result{Status: audit.ResultDenied, Code: int(codes.PermissionDenied)}, events[1].Result)
+}
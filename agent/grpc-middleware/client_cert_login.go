@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ClientCertLoginInterceptor provides gRPC interceptors passing on the ACL
+// token obtained with the client certificate verified during the TLS
+// handshake to the calls made without a token.
+type ClientCertLoginInterceptor struct {
+	// Login returns the secret of the token for the verified chain of
+	// certificates, or an empty string if the client certificates can't be
+	// used to login.
+	Login func(chain []*x509.Certificate) (string, error)
+}
+
+// InterceptUnary sets the token of the non-streaming gRPC calls.
+func (c *ClientCertLoginInterceptor) InterceptUnary(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, err := c.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// InterceptStream sets the token of the streaming gRPC calls.
+func (c *ClientCertLoginInterceptor) InterceptStream(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := c.login(ss.Context())
+	if err != nil {
+		return err
+	}
+	wrapped := middleware.WrapServerStream(ss)
+	wrapped.WrappedContext = ctx
+	return handler(srv, wrapped)
+}
+
+func (c *ClientCertLoginInterceptor) login(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get("x-consul-token"); len(tokens) > 0 && tokens[0] != "" {
+		return ctx, nil
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return ctx, nil
+	}
+
+	token, err := c.Login(tlsInfo.State.VerifiedChains[0])
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if token == "" {
+		return ctx, nil
+	}
+
+	md = md.Copy()
+	md.Set("x-consul-token", token)
+	return metadata.NewIncomingContext(ctx, md), nil
+}
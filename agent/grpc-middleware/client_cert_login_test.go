@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientCertLoginInterceptor_InterceptUnary(t *testing.T) {
+	leaf := &x509.Certificate{SerialNumber: big.NewInt(42)}
+	interceptor := &ClientCertLoginInterceptor{
+		Login: func(chain []*x509.Certificate) (string, error) {
+			if chain[0].SerialNumber.Int64() != 42 {
+				return "", errors.New("unknown certificate")
+			}
+			return "secret-of-42", nil
+		},
+	}
+
+	tlsPeer := func(chains ...[]*x509.Certificate) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: chains}},
+		})
+	}
+	token := func(ctx context.Context) string {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if tokens := md.Get("x-consul-token"); len(tokens) > 0 {
+			return tokens[0]
+		}
+		return ""
+	}
+
+	for name, tc := range map[string]struct {
+		ctx         context.Context
+		expectToken string
+		expectCode  codes.Code
+	}{
+		"verified certificate": {
+			ctx:         tlsPeer([]*x509.Certificate{leaf}),
+			expectToken: "secret-of-42",
+		},
+		"token provided": {
+			ctx:         metadata.NewIncomingContext(tlsPeer([]*x509.Certificate{leaf}), metadata.Pairs("x-consul-token", "other")),
+			expectToken: "other",
+		},
+		"no certificate": {
+			ctx: tlsPeer(),
+		},
+		"plaintext": {
+			ctx: context.Background(),
+		},
+		"login failure": {
+			ctx:        tlsPeer([]*x509.Certificate{{SerialNumber: big.NewInt(1)}}),
+			expectCode: codes.PermissionDenied,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+				got = token(ctx)
+				return nil, nil
+			}
+			_, err := interceptor.InterceptUnary(tc.ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if tc.expectCode != codes.OK {
+				require.Equal(t, tc.expectCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectToken, got)
+		})
+	}
+}
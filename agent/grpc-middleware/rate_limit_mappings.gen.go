@@ -10,7 +10,9 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"/hashicorp.consul.connectca.ConnectCAService/WatchRoots":                    rate.OperationTypeRead,
 	"/hashicorp.consul.dataplane.DataplaneService/GetEnvoyBootstrapParams":       rate.OperationTypeRead,
 	"/hashicorp.consul.dataplane.DataplaneService/GetSupportedDataplaneFeatures": rate.OperationTypeRead,
+	"/hashicorp.consul.dataplane.DataplaneService/NegotiateDataplaneFeatures":    rate.OperationTypeRead,
 	"/hashicorp.consul.dns.DNSService/Query":                                     rate.OperationTypeRead,
+	"/hashicorp.consul.eventstream.EventStreamService/Subscribe":                 rate.OperationTypeRead,
 	"/hashicorp.consul.internal.operator.OperatorService/TransferLeader":         rate.OperationTypeExempt,
 	"/hashicorp.consul.internal.peering.PeeringService/Establish":                rate.OperationTypeWrite,
 	"/hashicorp.consul.internal.peering.PeeringService/GenerateToken":            rate.OperationTypeWrite,
@@ -23,5 +25,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"/hashicorp.consul.internal.peerstream.PeerStreamService/ExchangeSecret":     rate.OperationTypeWrite,
 	"/hashicorp.consul.internal.peerstream.PeerStreamService/StreamResources":    rate.OperationTypeRead,
 	"/hashicorp.consul.serverdiscovery.ServerDiscoveryService/WatchServers":      rate.OperationTypeRead,
+	"/hashicorp.consul.servicelease.ServiceLeaseService/KeepAlive":               rate.OperationTypeWrite,
+	"/hashicorp.consul.session.SessionService/KeepAlive":                         rate.OperationTypeWrite,
 	"/subscribe.StateChangeSubscription/Subscribe":                               rate.OperationTypeRead,
 }
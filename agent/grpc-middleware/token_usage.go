@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// TokenUsageInterceptor provides gRPC interceptors tracking the ACL tokens
+// presented with the calls, so that their use is reported to the servers.
+type TokenUsageInterceptor struct {
+	// Record tracks that the token with the given secret was presented by a
+	// client at addr. The returned function is called once the call ended,
+	// so that the tokens of long-lived streams are known to still be in use.
+	Record func(secretID, addr string) (done func())
+}
+
+// InterceptUnary tracks the token of the non-streaming gRPC calls.
+func (t *TokenUsageInterceptor) InterceptUnary(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	defer t.record(ctx)()
+	return handler(ctx, req)
+}
+
+// InterceptStream tracks the token of the streaming gRPC calls until they
+// end.
+func (t *TokenUsageInterceptor) InterceptStream(
+	srv interface{},
+	ss grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	defer t.record(ss.Context())()
+	return handler(srv, ss)
+}
+
+func (t *TokenUsageInterceptor) record(ctx context.Context) func() {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return func() {}
+	}
+	tokens := md.Get("x-consul-token")
+	if len(tokens) == 0 || tokens[0] == "" {
+		return func() {}
+	}
+
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+	}
+	return t.Record(tokens[0], addr)
+}
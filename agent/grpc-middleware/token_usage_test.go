@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestTokenUsageInterceptor(t *testing.T) {
+	var calls, inProgress []string
+	interceptor := &TokenUsageInterceptor{
+		Record: func(secretID, addr string) func() {
+			calls = append(calls, secretID+" "+addr)
+			inProgress = append(inProgress, secretID)
+			return func() { inProgress = inProgress[:len(inProgress)-1] }
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 12345},
+	})
+	withToken := metadata.NewIncomingContext(ctx, metadata.Pairs("x-consul-token", "secret"))
+
+	_, err := interceptor.InterceptUnary(withToken, nil, &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) {
+			require.Equal(t, []string{"secret"}, inProgress)
+			return nil, nil
+		})
+	require.NoError(t, err)
+	require.Empty(t, inProgress)
+
+	// The calls without a token are not tracked.
+	_, err = interceptor.InterceptUnary(ctx, nil, &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	require.NoError(t, err)
+
+	// The token of a stream is in use until the stream ends.
+	err = interceptor.InterceptStream(nil, &testServerStream{ctx: withToken}, &grpc.StreamServerInfo{},
+		func(interface{}, grpc.ServerStream) error {
+			require.Equal(t, []string{"secret"}, inProgress)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Empty(t, inProgress)
+
+	require.Equal(t, []string{"secret 10.0.0.1", "secret 10.0.0.1"}, calls)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
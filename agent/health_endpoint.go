@@ -216,6 +216,24 @@ func (s *HTTPHandlers) healthServiceNodes(resp http.ResponseWriter, req *http.Re
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing service name"}
 	}
 
+	if err := parsePagination(req, &args.PageOptions); err != nil {
+		return nil, err
+	}
+	if args.IsPaginated() && args.Source.Node != "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Pagination cannot be used with ?near"}
+	}
+
+	// Filter to only passing if specified
+	filter, err := getBoolQueryParam(params, api.HealthPassing)
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Invalid value for ?passing"}
+	}
+	// The paginated instances are filtered by the servers before they are
+	// paged, so that the pages are full.
+	if filter && args.IsPaginated() {
+		args.OnlyPassing = true
+	}
+
 	out, md, err := s.agent.rpcClientHealth.ServiceNodes(req.Context(), args)
 	if err != nil {
 		return nil, err
@@ -227,18 +245,22 @@ func (s *HTTPHandlers) healthServiceNodes(resp http.ResponseWriter, req *http.Re
 	out.QueryMeta.ConsistencyLevel = args.QueryOptions.ConsistencyLevel()
 	setMeta(resp, &out.QueryMeta)
 
-	// FIXME: argument parsing should be done before performing the rpc
-	// Filter to only passing if specified
-	filter, err := getBoolQueryParam(params, api.HealthPassing)
-	if err != nil {
-		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Invalid value for ?passing"}
+	// Filter to the instances exposing the port, and to the checks of that
+	// port, if specified
+	if port := params.Get("port"); port != "" {
+		out.Nodes = filterServicePort(out.Nodes, port)
 	}
 
 	// FIXME: remove filterNonPassing, replace with nodes.Filter, which is used by DNSServer
-	if filter {
+	if filter && !args.OnlyPassing {
 		out.Nodes = filterNonPassing(out.Nodes)
 	}
 
+	// The page is taken before the instances are filtered by port, so it can
+	// hold fewer instances than the limit, but the cursor still points at the
+	// next page.
+	setNextCursor(resp, out.NextCursor, out.Index)
+
 	// Translate addresses after filtering so we don't waste effort.
 	s.agent.TranslateAddresses(args.Datacenter, out.Nodes, TranslateAddressAcceptAny)
 
@@ -287,6 +309,32 @@ func getBoolQueryParam(params url.Values, key string) (bool, error) {
 	return param, nil
 }
 
+// filterServicePort is used to filter out the nodes that don't expose the
+// named port, as well as the checks scoped to their other ports.
+func filterServicePort(nodes structs.CheckServiceNodes, port string) structs.CheckServiceNodes {
+	// Make a copy of the cached nodes rather than operating on the cache directly
+	out := make(structs.CheckServiceNodes, 0, len(nodes))
+	for _, node := range nodes {
+		ports := node.Service.Ports
+		if node.Service.Kind == structs.ServiceKindConnectProxy {
+			ports = node.Service.Proxy.LocalServicePorts
+		}
+		if _, ok := ports.Get(port); !ok {
+			continue
+		}
+
+		checks := make(structs.HealthChecks, 0, len(node.Checks))
+		for _, check := range node.Checks {
+			if check.PortName == "" || check.PortName == port {
+				checks = append(checks, check)
+			}
+		}
+		node.Checks = checks
+		out = append(out, node)
+	}
+	return out
+}
+
 // filterNonPassing is used to filter out any nodes that have check that are not passing
 func filterNonPassing(nodes structs.CheckServiceNodes) structs.CheckServiceNodes {
 	n := len(nodes)
@@ -764,6 +764,102 @@ func TestHealthServiceNodes(t *testing.T) {
 	}
 }
 
+func TestHealthServiceNodes_Pagination(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, node := range []string{"n1", "n2", "n3"} {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				ID:      "web",
+				Service: "web",
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	get := func(t *testing.T, query string) (structs.CheckServiceNodes, string, error) {
+		req, err := http.NewRequest("GET", "/v1/health/service/web?dc=dc1"+query, nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.HealthServiceNodes(resp, req)
+		if err != nil {
+			return nil, "", err
+		}
+		return obj.(structs.CheckServiceNodes), resp.Header().Get("X-Consul-Next-Cursor"), nil
+	}
+
+	nodes, next, err := get(t, "&limit=2")
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	require.Equal(t, "n1", nodes[0].Node.Node)
+	require.Equal(t, "n2", nodes[1].Node.Node)
+	require.NotEmpty(t, next)
+
+	nodes, last, err := get(t, "&limit=2&cursor="+next)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "n3", nodes[0].Node.Node)
+	require.Empty(t, last)
+
+	// The pages are taken at the index of the first one, so the cursor is
+	// rejected once the service changed.
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Deregister",
+		&structs.DeregisterRequest{Datacenter: "dc1", Node: "n3"}, &out))
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "n4",
+		Address:    "127.0.0.1",
+		Service:    &structs.NodeService{ID: "web", Service: "web"},
+	}, &out))
+
+	_, _, err = get(t, "&limit=2&cursor="+next)
+	require.True(t, structs.IsErrPaginationConflict(err))
+
+	req, _ := http.NewRequest("GET", "/v1/health/service/web?limit=2&cursor="+next, nil)
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusConflict, resp.Code)
+
+	// The instances which aren't passing are filtered out before the page
+	// is taken, so that it is full.
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "n1",
+		Address:    "127.0.0.1",
+		Check: &structs.HealthCheck{
+			CheckID:   "web-check",
+			Name:      "web",
+			ServiceID: "web",
+			Status:    api.HealthCritical,
+		},
+	}, &out))
+
+	nodes, last, err = get(t, "&passing&limit=2")
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	require.Equal(t, "n2", nodes[0].Node.Node)
+	require.Equal(t, "n4", nodes[1].Node.Node)
+	require.Empty(t, last)
+
+	// Pagination can't be combined with ?near since it changes the order.
+	_, _, err = get(t, "&limit=2&near=_agent")
+	httpErr, ok := err.(HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+}
+
 func TestHealthServiceNodes_Blocking(t *testing.T) {
 	t.Run("local data", func(t *testing.T) {
 		testHealthServiceNodes_Blocking(t, structs.DefaultPeerKeyword)
@@ -1896,6 +1992,49 @@ func TestFilterNonPassing(t *testing.T) {
 	}
 }
 
+func TestFilterServicePort(t *testing.T) {
+	t.Parallel()
+	ports := structs.ServicePorts{{Name: "admin", Port: 9090}}
+	nodes := structs.CheckServiceNodes{
+		{
+			Node:    &structs.Node{Node: "node1"},
+			Service: &structs.NodeService{ID: "web1", Service: "web", Port: 8080, Ports: ports},
+			Checks: structs.HealthChecks{
+				{CheckID: "serfHealth", Status: api.HealthPassing},
+				{CheckID: "web1", ServiceID: "web1", Status: api.HealthCritical},
+				{CheckID: "web1-admin", ServiceID: "web1", PortName: "admin", Status: api.HealthPassing},
+				{CheckID: "web1-metrics", ServiceID: "web1", PortName: "metrics", Status: api.HealthCritical},
+			},
+		},
+		{
+			Node:    &structs.Node{Node: "node2"},
+			Service: &structs.NodeService{ID: "web2", Service: "web", Port: 8080},
+		},
+		{
+			Node: &structs.Node{Node: "node3"},
+			Service: &structs.NodeService{
+				Kind:    structs.ServiceKindConnectProxy,
+				ID:      "web3-sidecar-proxy",
+				Service: "web-sidecar-proxy",
+				Proxy:   structs.ConnectProxyConfig{DestinationServiceName: "web", LocalServicePorts: ports},
+			},
+		},
+	}
+
+	out := filterServicePort(nodes, "admin")
+	require.Len(t, out, 2)
+	require.Equal(t, "web1", out[0].Service.ID)
+	var checks []types.CheckID
+	for _, check := range out[0].Checks {
+		checks = append(checks, check.CheckID)
+	}
+	require.Equal(t, []types.CheckID{"serfHealth", "web1", "web1-admin"}, checks)
+	require.Equal(t, "web3-sidecar-proxy", out[1].Service.ID)
+
+	// The cached nodes are left untouched.
+	require.Len(t, nodes[0].Checks, 4)
+}
+
 func TestListHealthyServiceNodes_MergeCentralConfig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
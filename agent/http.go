@@ -192,7 +192,8 @@ func (s *HTTPHandlers) handler(enableDebug bool) http.Handler {
 
 		var gzipHandler http.Handler
 		minSize := gziphandler.DefaultMinSize
-		if pattern == "/v1/agent/monitor" || pattern == "/v1/agent/metrics/stream" {
+		switch pattern {
+		case "/v1/agent/monitor", "/v1/agent/metrics/stream", "/v1/catalog/changes", "/v1/session/keepalive":
 			minSize = 0
 		}
 		gzipWrapper, err := gziphandler.GzipHandlerWithOpts(gziphandler.MinSize(minSize))
@@ -345,6 +346,12 @@ var (
 func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc {
 	httpLogger := s.agent.logger.Named(logging.HTTP)
 	return func(resp http.ResponseWriter, req *http.Request) {
+		if s.agent.auditor != nil {
+			auditResp := &auditResponseWriter{ResponseWriter: resp}
+			resp = auditResp
+			defer s.recordAudit(req, auditResp)
+		}
+
 		setHeaders(resp, s.agent.config.HTTPResponseHeaders)
 		setTranslateAddr(resp, s.agent.config.TranslateWANAddrs)
 		setACLDefaultPolicy(resp, s.agent.config.ACLResolverSettings.ACLDefaultPolicy)
@@ -477,6 +484,8 @@ func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc
 				resp.WriteHeader(http.StatusTooManyRequests)
 			case isServiceUnavailable(err):
 				resp.WriteHeader(http.StatusServiceUnavailable)
+			case structs.IsErrPaginationConflict(err):
+				resp.WriteHeader(http.StatusConflict)
 			case isMethodNotAllowed(err):
 				// RFC2616 states that for 405 Method Not Allowed the response
 				// MUST include an Allow header containing the list of valid
@@ -532,6 +541,9 @@ func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc
 			err = MethodNotAllowedError{req.Method, append([]string{"OPTIONS"}, methods...)}
 		} else {
 			err = s.checkWriteAccess(req)
+			if err == nil {
+				err = s.clientCertLogin(req)
+			}
 
 			// Give the user a hint that they might be doing something wrong if they issue a GET request
 			// with a non-empty body (e.g., parameters placed in body rather than query string).
@@ -548,6 +560,8 @@ func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc
 				// Invoke the handler
 				obj, err = handler(resp, req)
 			}
+
+			s.recordTokenUsage(req)
 		}
 		contentType := "application/json"
 		httpCode := http.StatusOK
@@ -1059,6 +1073,27 @@ func (s *HTTPHandlers) parseToken(req *http.Request, token *string) {
 	s.parseTokenWithDefault(req, token)
 }
 
+// recordTokenUsage tracks the token presented with the request, if any, so
+// that the agent reports its use to the servers. The address recorded is the
+// one of the peer, since X-Forwarded-For can't be trusted for auditing.
+func (s *HTTPHandlers) recordTokenUsage(req *http.Request) {
+	if !s.agent.config.ACLsEnabled || s.agent.tokenUsage == nil {
+		return
+	}
+
+	var token string
+	s.parseTokenInternal(req, &token)
+	if token == "" {
+		return
+	}
+
+	addr, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		addr = req.RemoteAddr
+	}
+	s.agent.tokenUsage.record(token, addr, time.Now())
+}
+
 func sourceAddrFromRequest(req *http.Request) string {
 	xff := req.Header.Get("X-Forwarded-For")
 	forwardHosts := strings.Split(xff, ",")
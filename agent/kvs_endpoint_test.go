@@ -7,6 +7,9 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul/testrpc"
 
@@ -504,6 +507,38 @@ func TestKVSEndpoint_PUT_ConflictingFlags(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_PUT_TTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	req, _ := http.NewRequest("PUT", "/v1/kv/test?ttl=nope", bytes.NewBufferString("test"))
+	resp := httptest.NewRecorder()
+	_, err := a.srv.KVSEndpoint(resp, req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Invalid TTL "nope"`)
+
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?ttl=1h", bytes.NewBufferString("test"))
+	resp = httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, true, obj)
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	entries := obj.(structs.DirEntries)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *entries[0].ExpirationTime, time.Minute)
+}
+
 func TestKVSEndpoint_GET(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -47,6 +48,10 @@ var StateCounters = []prometheus.CounterDefinition{
 		Name: []string{"acl", "blocked", "node", "registration"},
 		Help: "Increments whenever a registration fails for a node (blocked by an ACL)",
 	},
+	{
+		Name: []string{"agent", "sync", "deferred"},
+		Help: "Increments whenever a sync run reaches the batch size and defers the remaining changes",
+	},
 }
 
 const fullSyncReadMaxStale = 2 * time.Second
@@ -61,6 +66,12 @@ type Config struct {
 	NodeName            string
 	Partition           string // this defaults if empty
 	TaggedAddresses     map[string]string
+
+	// SyncBatchSize is the maximum number of service and check records
+	// that are synced with the servers in a single sync run. Another
+	// partial sync is triggered for the remaining records. A value of 0
+	// disables the limit.
+	SyncBatchSize int
 }
 
 // ServiceState describes the state of a service record.
@@ -84,6 +95,11 @@ type ServiceState struct {
 	// as opposed to being registered through the Agent API.
 	IsLocallyDefined bool
 
+	// remoteDrift is true when the service record was marked out of sync
+	// because the remote state diverged, rather than because of a local
+	// change. Local changes are synced first when the sync runs are batched.
+	remoteDrift bool
+
 	// WatchCh is closed when the service state changes. Suitable for use in a
 	// memdb.WatchSet when watching agent local changes with hash-based blocking.
 	WatchCh chan struct{}
@@ -99,6 +115,15 @@ func (s *ServiceState) Clone() *ServiceState {
 	return s2
 }
 
+// setRemoteInSync updates whether the service record is in sync with the
+// remote state, noting whether the remote state diverged.
+func (s *ServiceState) setRemoteInSync(inSync bool) {
+	if s.InSync && !inSync {
+		s.remoteDrift = true
+	}
+	s.InSync = inSync
+}
+
 // CheckState describes the state of a health check record.
 type CheckState struct {
 	// Check is the local copy of the health check record.
@@ -132,6 +157,11 @@ type CheckState struct {
 	// IsLocallyDefined indicates whether the check was defined locally in config
 	// as opposed to being registered through the Agent API.
 	IsLocallyDefined bool
+
+	// remoteDrift is true when the health check record was marked out of
+	// sync because the remote state diverged, rather than because of a local
+	// change. Local changes are synced first when the sync runs are batched.
+	remoteDrift bool
 }
 
 // Clone returns a shallow copy of the object.
@@ -146,6 +176,15 @@ func (c *CheckState) Clone() *CheckState {
 	return c2
 }
 
+// setRemoteInSync updates whether the health check record is in sync with
+// the remote state, noting whether the remote state diverged.
+func (c *CheckState) setRemoteInSync(inSync bool) {
+	if c.InSync && !inSync {
+		c.remoteDrift = true
+	}
+	c.InSync = inSync
+}
+
 // Critical returns true when the health check is in critical state.
 func (c *CheckState) Critical() bool {
 	return !c.CriticalTime.IsZero()
@@ -359,6 +398,7 @@ func (l *State) removeServiceLocked(id structs.ServiceID) error {
 	// entry around until it is actually removed.
 	s.InSync = false
 	s.Deleted = true
+	s.remoteDrift = false
 	if s.WatchCh != nil {
 		close(s.WatchCh)
 		s.WatchCh = nil
@@ -657,6 +697,7 @@ func (l *State) removeCheckLocked(id structs.CheckID) error {
 	// entry around until it is actually removed.
 	c.InSync = false
 	c.Deleted = true
+	c.remoteDrift = false
 	l.TriggerSyncChanges()
 
 	return nil
@@ -738,6 +779,7 @@ func (l *State) UpdateCheck(id structs.CheckID, status, output string) {
 	c.Check.Status = status
 	c.Check.Output = output
 	c.InSync = false
+	c.remoteDrift = false
 	l.TriggerSyncChanges()
 }
 
@@ -1082,7 +1124,7 @@ func (l *State) updateSyncState() error {
 	// syncing so that they will be pushed to the server later
 	for id, s := range l.services {
 		if remoteServices[id] == nil {
-			s.InSync = false
+			s.setRemoteInSync(false)
 		}
 	}
 
@@ -1100,7 +1142,7 @@ func (l *State) updateSyncState() error {
 
 			// Mark a remote service that does not exist locally as deleted so
 			// that it will be removed on the server later.
-			l.services[id] = &ServiceState{Deleted: true}
+			l.services[id] = &ServiceState{Deleted: true, remoteDrift: true}
 			continue
 		}
 
@@ -1143,7 +1185,7 @@ func (l *State) updateSyncState() error {
 		if changed {
 			ls.Service = &nextService
 		}
-		ls.InSync = ls.Service.IsSame(rs)
+		ls.setRemoteInSync(ls.Service.IsSame(rs))
 	}
 
 	// Check which checks need syncing
@@ -1152,7 +1194,7 @@ func (l *State) updateSyncState() error {
 	// syncing so that they will be pushed to the server later
 	for id, c := range l.checks {
 		if remoteChecks[id] == nil {
-			c.InSync = false
+			c.setRemoteInSync(false)
 		}
 	}
 
@@ -1172,7 +1214,7 @@ func (l *State) updateSyncState() error {
 
 			// Mark a remote check that does not exist locally as deleted so
 			// that it will be removed on the server later.
-			l.checks[id] = &CheckState{Deleted: true}
+			l.checks[id] = &CheckState{Deleted: true, remoteDrift: true}
 			continue
 		}
 
@@ -1183,7 +1225,7 @@ func (l *State) updateSyncState() error {
 
 		// If our definition is different, we need to update it
 		if l.config.CheckUpdateInterval == 0 {
-			lc.InSync = lc.Check.IsSame(rc)
+			lc.setRemoteInSync(lc.Check.IsSame(rc))
 			continue
 		}
 
@@ -1206,7 +1248,7 @@ func (l *State) updateSyncState() error {
 			lcCopy.Output = ""
 			rcCopy.Output = ""
 		}
-		lc.InSync = lcCopy.IsSame(rcCopy)
+		lc.setRemoteInSync(lcCopy.IsSame(rcCopy))
 	}
 	return nil
 }
@@ -1247,9 +1289,21 @@ func (l *State) SyncChanges() error {
 		}
 	}
 
+	// The number of records synced in a single run may be limited, in which
+	// case another partial sync is triggered for the remaining ones.
+	batch := &syncBatch{limit: l.config.SyncBatchSize}
+
 	// Sync the services
 	// (logging happens in the helper methods)
-	for id, s := range l.services {
+	for _, id := range l.servicesToSyncLocked() {
+		if batch.full() {
+			return l.deferSyncLocked()
+		}
+		s := l.services[id]
+		if s == nil {
+			continue
+		}
+
 		var err error
 		switch {
 		case s.Deleted:
@@ -1257,16 +1311,26 @@ func (l *State) SyncChanges() error {
 		case !s.InSync:
 			err = l.syncService(id)
 		default:
-			l.logger.Debug("Service in sync", "service", id.String())
+			// Synced along with a previous record.
+			continue
 		}
 		if err != nil {
 			return err
 		}
+		batch.synced++
 	}
 
 	// Sync the checks
 	// (logging happens in the helper methods)
-	for id, c := range l.checks {
+	for _, id := range l.checksToSyncLocked() {
+		if batch.full() {
+			return l.deferSyncLocked()
+		}
+		c := l.checks[id]
+		if c == nil {
+			continue
+		}
+
 		var err error
 		switch {
 		case c.Deleted:
@@ -1278,15 +1342,79 @@ func (l *State) SyncChanges() error {
 			}
 			err = l.syncCheck(id)
 		default:
-			l.logger.Debug("Check in sync", "check", id.String())
+			// Synced along with its service.
+			continue
 		}
 		if err != nil {
 			return err
 		}
+		batch.synced++
 	}
 	return nil
 }
 
+// syncBatch tracks the number of records synced in a single sync run.
+type syncBatch struct {
+	limit  int
+	synced int
+}
+
+// full returns true if no more records may be synced in this run.
+func (b *syncBatch) full() bool {
+	return b.limit > 0 && b.synced >= b.limit
+}
+
+// deferSyncLocked triggers another partial sync for the records that did not
+// fit in the current sync run.
+func (l *State) deferSyncLocked() error {
+	l.logger.Debug("Sync batch size reached, deferring remaining changes")
+	metrics.IncrCounter([]string{"agent", "sync", "deferred"}, 1)
+	l.TriggerSyncChanges()
+	return nil
+}
+
+// servicesToSyncLocked returns the IDs of the services that need to be synced,
+// with the ones changed locally ahead of the ones that diverged on the server.
+func (l *State) servicesToSyncLocked() []structs.ServiceID {
+	var ids []structs.ServiceID
+	for id, s := range l.services {
+		if s.Deleted || !s.InSync {
+			ids = append(ids, id)
+		} else {
+			l.logger.Debug("Service in sync", "service", id.String())
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := l.services[ids[i]], l.services[ids[j]]
+		if a.remoteDrift != b.remoteDrift {
+			return !a.remoteDrift
+		}
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+// checksToSyncLocked returns the IDs of the checks that need to be synced,
+// with the ones changed locally ahead of the ones that diverged on the server.
+func (l *State) checksToSyncLocked() []structs.CheckID {
+	var ids []structs.CheckID
+	for id, c := range l.checks {
+		if c.Deleted || !c.InSync {
+			ids = append(ids, id)
+		} else {
+			l.logger.Debug("Check in sync", "check", id.String())
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := l.checks[ids[i]], l.checks[ids[j]]
+		if a.remoteDrift != b.remoteDrift {
+			return !a.remoteDrift
+		}
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
 // deleteService is used to delete a service from the server
 func (l *State) deleteService(key structs.ServiceID) error {
 	if key.ID == "" {
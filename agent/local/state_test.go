@@ -2596,6 +2596,84 @@ func TestState_SyncChanges_DuplicateAddServiceOnlySyncsOnce(t *testing.T) {
 	require.Len(t, rpc.calls, 4)
 }
 
+func TestState_SyncChanges_BatchSize(t *testing.T) {
+	state := local.NewState(local.Config{SyncBatchSize: 2}, hclog.New(nil), new(token.Store))
+	rpc := &fakeRPC{}
+	state.Delegate = rpc
+	var triggered int
+	state.TriggerSyncChanges = func() { triggered++ }
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		srv := &structs.NodeService{
+			ID:             id,
+			Service:        "web",
+			EnterpriseMeta: *structs.DefaultEnterpriseMetaInDefaultPartition(),
+		}
+		require.NoError(t, state.AddServiceWithChecks(srv, nil, "", false))
+	}
+
+	// The node info is always synced, followed by at most two services.
+	triggered = 0
+	require.NoError(t, state.SyncChanges())
+	require.Len(t, rpc.calls, 3)
+	require.Equal(t, 1, triggered)
+
+	require.NoError(t, state.SyncChanges())
+	require.Len(t, rpc.calls, 5)
+	require.Equal(t, 2, triggered)
+
+	// The last run fits in the batch, so no other run is triggered.
+	require.NoError(t, state.SyncChanges())
+	require.Len(t, rpc.calls, 6)
+	require.Equal(t, 2, triggered)
+
+	for _, s := range state.ServiceStates(structs.DefaultEnterpriseMetaInDefaultPartition()) {
+		require.True(t, s.InSync)
+	}
+}
+
+func TestState_SyncChanges_LocalChangesFirst(t *testing.T) {
+	state := local.NewState(local.Config{SyncBatchSize: 1}, hclog.New(nil), new(token.Store))
+	rpc := &fakeRPC{}
+	state.Delegate = rpc
+	state.TriggerSyncChanges = func() {}
+
+	addService := func(id string) {
+		srv := &structs.NodeService{
+			ID:             id,
+			Service:        "web",
+			EnterpriseMeta: *structs.DefaultEnterpriseMetaInDefaultPartition(),
+		}
+		require.NoError(t, state.AddServiceWithChecks(srv, nil, "", false))
+	}
+	addService("a")
+	addService("b")
+	require.NoError(t, state.SyncChanges())
+	require.NoError(t, state.SyncChanges())
+
+	// The fake servers don't know about any service, so the full sync finds
+	// that a and b diverged. z was changed locally and is synced first even
+	// though it sorts last.
+	addService("z")
+	rpc.calls = nil
+	require.NoError(t, state.SyncFull())
+
+	var registered []string
+	for _, call := range rpc.calls {
+		if req, ok := call.args.(*structs.RegisterRequest); ok && req.Service != nil {
+			registered = append(registered, req.Service.ID)
+		}
+	}
+	require.Equal(t, []string{"z"}, registered)
+
+	// The diverged services follow in the next runs.
+	require.NoError(t, state.SyncChanges())
+	require.NoError(t, state.SyncChanges())
+	for _, s := range state.ServiceStates(structs.DefaultEnterpriseMetaInDefaultPartition()) {
+		require.True(t, s.InSync)
+	}
+}
+
 type fakeRPC struct {
 	calls []callRPC
 }
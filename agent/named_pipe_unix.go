@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package agent
+
+import (
+	"fmt"
+	"net"
+)
+
+func (a *Agent) listenNamedPipe(path string) (net.Listener, error) {
+	return nil, fmt.Errorf("cannot listen on the named pipe %s: named pipes are only supported on Windows", path)
+}
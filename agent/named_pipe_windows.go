@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package agent
+
+import (
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// listenNamedPipe listens on a named pipe, with the security descriptor of
+// named_pipes to control which users can connect to it.
+func (a *Agent) listenNamedPipe(path string) (net.Listener, error) {
+	return winio.ListenPipe(path, &winio.PipeConfig{
+		SecurityDescriptor: a.config.NamedPipeSecurityDescriptor,
+	})
+}
@@ -15,6 +15,7 @@ import (
 	autopilot "github.com/hashicorp/raft-autopilot"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 )
@@ -232,6 +233,32 @@ func keyringErrorsOrNil(responses []*structs.KeyringResponse) error {
 	return errs
 }
 
+// OperatorKeyringRotation is used to inspect the state of the automated
+// rotation of the gossip encryption key.
+func (s *HTTPHandlers) OperatorKeyringRotation(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.KeyringRotationStatus
+	if err := s.agent.RPC(req.Context(), "Operator.KeyringRotationStatus", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	out := api.KeyringRotationStatus{
+		Enabled:        reply.Enabled,
+		Interval:       api.ReadableDuration(reply.Interval),
+		GracePeriod:    api.ReadableDuration(reply.GracePeriod),
+		Phase:          reply.Phase,
+		PhaseStartedAt: reply.PhaseStartedAt,
+		LastRotation:   reply.LastRotation,
+		NextRotation:   reply.NextRotation,
+		LastError:      reply.LastError,
+	}
+	return out, nil
+}
+
 // OperatorAutopilotConfiguration is used to inspect the current Autopilot configuration.
 // This supports the stale query mode in case the cluster doesn't have a leader.
 func (s *HTTPHandlers) OperatorAutopilotConfiguration(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -382,6 +409,9 @@ func (s *HTTPHandlers) OperatorUsage(resp http.ResponseWriter, req *http.Request
 	if _, ok := req.URL.Query()["global"]; ok {
 		args.Global = true
 	}
+	if _, ok := req.URL.Query()["history"]; ok {
+		args.History = true
+	}
 
 	// Make the RPC request
 	var out structs.Usage
@@ -404,6 +434,72 @@ RETRY_ONCE:
 	return out, nil
 }
 
+// OperatorLockContention returns the holders and the waiters of the locks and
+// semaphores under the prefix given by the "prefix" query parameter.
+func (s *HTTPHandlers) OperatorLockContention(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.LockContentionRequest
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	args.Prefix = req.URL.Query().Get("prefix")
+
+	var out structs.IndexedLockContention
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "Operator.LockContention", &args, &out); err != nil {
+		return nil, err
+	}
+	if out.Locks == nil {
+		out.Locks = make([]*structs.LockContention, 0)
+	}
+	return out.Locks, nil
+}
+
+// OperatorGossipHealth returns the health of the LAN gossip pool as seen from
+// the agent answering the request: the RTTs to its peers, their failures and
+// whether a network partition is suspected. Requires an operator:read ACL
+// token.
+func (s *HTTPHandlers) OperatorGossipHealth(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return nil, err
+	}
+
+	return gossipHealthToAPI(s.agent.delegate.GossipHealth()), nil
+}
+
+func gossipHealthToAPI(health consul.GossipHealth) *api.GossipHealth {
+	out := &api.GossipHealth{
+		Node:               health.Node,
+		HealthScore:        health.HealthScore,
+		PartitionSuspected: health.PartitionSuspected,
+		Peers:              make([]api.GossipPeerHealth, 0, len(health.Peers)),
+	}
+	for _, p := range health.Peers {
+		peer := api.GossipPeerHealth{
+			Node:     p.Node,
+			Address:  p.Address,
+			Status:   p.Status,
+			RTT:      p.RTT,
+			Failures: p.Failures,
+			Flaps:    p.Flaps,
+		}
+		if !p.LastFailure.IsZero() {
+			lastFailure := p.LastFailure
+			peer.LastFailure = &lastFailure
+		}
+		out.Peers = append(out.Peers, peer)
+	}
+	return out
+}
+
 func stringIDs(ids []raft.ServerID) []string {
 	out := make([]string, len(ids))
 	for i, id := range ids {
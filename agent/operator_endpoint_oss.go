@@ -4,16 +4,25 @@
 package agent
 
 import (
+	"sort"
+
 	"github.com/hashicorp/consul/api"
 	autopilot "github.com/hashicorp/raft-autopilot"
 )
 
-func autopilotToAPIServerEnterprise(_ *autopilot.ServerState, _ *api.AutopilotServer) {
-	// noop in oss
+func autopilotToAPIServerEnterprise(_ *autopilot.ServerState, apiSrv *api.AutopilotServer) {
+	apiSrv.ReadReplica = apiSrv.NodeType == api.AutopilotTypeReadReplica
 }
 
 func autopilotToAPIStateEnterprise(state *autopilot.State, apiState *api.AutopilotState) {
 	// without the enterprise features there is no different between these two and we don't want to
 	// alarm anyone by leaving this as the zero value.
 	apiState.OptimisticFailureTolerance = state.FailureTolerance
+
+	for id, srv := range state.Servers {
+		if api.AutopilotServerType(srv.Server.NodeType) == api.AutopilotTypeReadReplica {
+			apiState.ReadReplicas = append(apiState.ReadReplicas, string(id))
+		}
+	}
+	sort.Strings(apiState.ReadReplicas)
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/stretchr/testify/require"
 )
 
@@ -61,9 +62,27 @@ func TestOperator_Usage(t *testing.T) {
 			},
 			// 4 = 6 total service instances - 1 connect proxy - 1 consul service
 			BillableServiceInstances: 4,
+			ServiceKindInstances: map[string]int{
+				"typical":             5,
+				"connect-proxy":       1,
+				"ingress-gateway":     0,
+				"mesh-gateway":        0,
+				"terminating-gateway": 0,
+				"api-gateway":         0,
+			},
 		},
 	}
 	require.Equal(t, expected, raw.(structs.Usage).Usage)
+	require.Nil(t, raw.(structs.Usage).History)
+
+	// The servers sample the usage when they start.
+	req, err = http.NewRequest("GET", "/v1/operator/usage?history", nil)
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		raw, err := a.srv.OperatorUsage(httptest.NewRecorder(), req)
+		require.NoError(r, err)
+		require.Len(r, raw.(structs.Usage).History["dc1"], 1)
+	})
 }
 
 func upsertTestService(rpc rpcFn, secret, datacenter, name, node, partition string, modifyFuncs ...func(*structs.NodeService)) error {
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// paginationCursor is the decoded form of the opaque ?cursor query parameter.
+// It records the index the first page was taken at and the key of the last
+// result of the previous page.
+type paginationCursor struct {
+	Index uint64
+	After string
+}
+
+// parsePagination parses the ?limit and ?cursor query parameters of a request
+// to an endpoint that supports paging through its results. The cursor is
+// opaque to the clients, it is the URL safe base64 encoding of a
+// paginationCursor.
+func parsePagination(req *http.Request, page *structs.PageOptions) error {
+	query := req.URL.Query()
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit < 0 {
+			return HTTPError{StatusCode: http.StatusBadRequest, Reason: "Invalid value for ?limit"}
+		}
+		page.Limit = limit
+	}
+	if rawCursor := query.Get("cursor"); rawCursor != "" {
+		buf, err := base64.RawURLEncoding.DecodeString(rawCursor)
+		if err != nil {
+			return HTTPError{StatusCode: http.StatusBadRequest, Reason: "Invalid value for ?cursor"}
+		}
+		var cursor paginationCursor
+		if err := json.Unmarshal(buf, &cursor); err != nil || cursor.After == "" {
+			return HTTPError{StatusCode: http.StatusBadRequest, Reason: "Invalid value for ?cursor"}
+		}
+		page.Cursor = cursor.After
+		page.CursorIndex = cursor.Index
+	}
+	return nil
+}
+
+// setNextCursor is used to set the header pointing at the next page of a
+// paginated response. index is the index of the results of the first page,
+// which the next pages must be taken at.
+func setNextCursor(resp http.ResponseWriter, next string, index uint64) {
+	if next == "" {
+		return
+	}
+	buf, err := json.Marshal(paginationCursor{Index: index, After: next})
+	if err != nil {
+		return
+	}
+	resp.Header().Set("X-Consul-Next-Cursor", base64.RawURLEncoding.EncodeToString(buf))
+}
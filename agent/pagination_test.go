@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestParsePagination(t *testing.T) {
+	t.Parallel()
+
+	parse := func(t *testing.T, query string) (structs.PageOptions, error) {
+		var page structs.PageOptions
+		req := httptest.NewRequest("GET", "/v1/catalog/services"+query, nil)
+		err := parsePagination(req, &page)
+		return page, err
+	}
+
+	page, err := parse(t, "")
+	require.NoError(t, err)
+	require.False(t, page.IsPaginated())
+
+	page, err = parse(t, "?limit=10")
+	require.NoError(t, err)
+	require.Equal(t, structs.PageOptions{Limit: 10}, page)
+
+	// The cursor round trips through the header of the previous page.
+	resp := httptest.NewRecorder()
+	setNextCursor(resp, "node1/web", 7)
+	page, err = parse(t, "?cursor="+resp.Header().Get("X-Consul-Next-Cursor"))
+	require.NoError(t, err)
+	require.Equal(t, structs.PageOptions{Cursor: "node1/web", CursorIndex: 7}, page)
+
+	for _, query := range []string{"?limit=foo", "?limit=-1", "?cursor=!!", "?cursor=Zm9v"} {
+		_, err = parse(t, query)
+		httpErr, ok := err.(HTTPError)
+		require.True(t, ok, query)
+		require.Equal(t, http.StatusBadRequest, httpErr.StatusCode, query)
+	}
+}
@@ -84,6 +84,9 @@ func (s *HTTPHandlers) PeeringList(resp http.ResponseWriter, req *http.Request)
 	if err != nil {
 		return nil, err
 	}
+	// The peerings can't be watched with blocking queries, but the index lets
+	// pollers tell whether they changed.
+	setIndex(resp, pbresp.Index)
 
 	return pbresp.ToAPI(), nil
 }
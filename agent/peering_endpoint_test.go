@@ -503,6 +503,7 @@ func TestHTTP_Peering_List(t *testing.T) {
 		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
 
 		require.Len(t, apiResp, 2)
+		require.NotEmpty(t, resp.Header().Get("X-Consul-Index"))
 
 		for _, p := range apiResp {
 			require.Equal(t, 0, len(p.StreamStatus.ImportedServices))
@@ -56,6 +56,12 @@ func (h *handlerAPIGateway) initialize(ctx context.Context) (ConfigSnapshot, err
 		return snap, err
 	}
 
+	// Watch the api-gateway-policy's config entry
+	err = h.subscribeToConfigEntry(ctx, structs.APIGatewayPolicy, h.service, gatewayPolicyConfigWatchID)
+	if err != nil {
+		return snap, err
+	}
+
 	snap.APIGateway.Listeners = make(map[string]structs.APIGatewayListener)
 	snap.APIGateway.BoundListeners = make(map[string]structs.BoundAPIGatewayListener)
 	snap.APIGateway.HTTPRoutes = watch.NewMap[structs.ResourceReference, *structs.HTTPRouteConfigEntry]()
@@ -119,6 +125,11 @@ func (h *handlerAPIGateway) handleUpdate(ctx context.Context, u UpdateEvent, sna
 		if err := h.handleRouteConfigUpdate(ctx, u, snap); err != nil {
 			return err
 		}
+	case u.CorrelationID == gatewayPolicyConfigWatchID:
+		// Handle change in the api-gateway-policy config entry
+		if err := h.handleGatewayPolicyConfigUpdate(u, snap); err != nil {
+			return err
+		}
 	default:
 		return (*handlerUpstreams)(h).handleUpdateUpstreams(ctx, u, snap)
 	}
@@ -236,6 +247,26 @@ func (h *handlerAPIGateway) handleGatewayConfigUpdate(ctx context.Context, u Upd
 	return h.watchIngressLeafCert(ctx, snap)
 }
 
+// handleGatewayPolicyConfigUpdate stores the api-gateway-policy attached to
+// the gateway. A deleted policy clears any previously stored policy.
+func (h *handlerAPIGateway) handleGatewayPolicyConfigUpdate(u UpdateEvent, snap *ConfigSnapshot) error {
+	resp, ok := u.Result.(*structs.ConfigEntryResponse)
+	if !ok {
+		return fmt.Errorf("invalid type for response: %T", u.Result)
+	} else if resp.Entry == nil {
+		snap.APIGateway.Policy = nil
+		return nil
+	}
+
+	policy, ok := resp.Entry.(*structs.APIGatewayPolicyConfigEntry)
+	if !ok {
+		return fmt.Errorf("invalid type for config entry: %T", resp.Entry)
+	}
+
+	snap.APIGateway.Policy = policy
+	return nil
+}
+
 // handleInlineCertConfigUpdate stores the certificate for the gateway
 func (h *handlerAPIGateway) handleInlineCertConfigUpdate(_ context.Context, u UpdateEvent, snap *ConfigSnapshot) error {
 	resp, ok := u.Result.(*structs.ConfigEntryResponse)
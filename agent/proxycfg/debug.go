@@ -0,0 +1,110 @@
+package proxycfg
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// DebugState is the state of a proxy tracked by the Manager, as reported to
+// operators troubleshooting the configuration of the proxies.
+type DebugState struct {
+	// ProxyID is the ID of the proxy service. The ACL token of the proxy is
+	// deliberately left out.
+	ProxyID  structs.ServiceID
+	NodeName string
+	Kind     structs.ServiceKind
+	Service  string
+	Source   ProxySource
+
+	// Watchers is the number of watches on the proxy, typically one per
+	// connected xDS stream.
+	Watchers int
+
+	// Failed is whether the state stopped because a data source is in an
+	// irrecoverable state, such as the ACL token of the proxy being deleted.
+	Failed bool
+
+	// Ready is whether all the data required to configure the proxy has been
+	// fetched. Snapshot is nil until then.
+	Ready    bool
+	Snapshot *ConfigSnapshot `json:"-"`
+
+	// Summary summarizes the snapshot when Ready.
+	Summary *DebugSnapshotSummary `json:",omitempty"`
+}
+
+// DebugSnapshotSummary is a summary of the parts of a snapshot which most
+// often explain how a proxy is configured.
+type DebugSnapshotSummary struct {
+	Datacenter string
+	Address    string
+	Port       int
+	Mode       structs.ProxyMode `json:",omitempty"`
+
+	ActiveRootID     string `json:",omitempty"`
+	LeafSerialNumber string `json:",omitempty"`
+	LeafValidBefore  time.Time
+
+	// Upstreams are the upstreams of a connect proxy.
+	Upstreams []string `json:",omitempty"`
+}
+
+// DebugStates returns the state of every proxy tracked by the Manager.
+func (m *Manager) DebugStates() []DebugState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := make([]DebugState, 0, len(m.proxies))
+	for id, state := range m.proxies {
+		debug := DebugState{
+			ProxyID:  id.ServiceID,
+			NodeName: id.NodeName,
+			Kind:     state.serviceInstance.kind,
+			Service:  state.serviceInstance.service,
+			Source:   state.source,
+			Watchers: len(m.watchers[id]),
+			Failed:   state.failed(),
+		}
+		if !debug.Failed {
+			if snap := state.CurrentSnapshot(); snap != nil {
+				debug.Ready = true
+				debug.Snapshot = snap
+				debug.Summary = snap.debugSummary()
+			}
+		}
+		states = append(states, debug)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].ProxyID.String() != states[j].ProxyID.String() {
+			return states[i].ProxyID.String() < states[j].ProxyID.String()
+		}
+		return states[i].Source < states[j].Source
+	})
+	return states
+}
+
+func (s *ConfigSnapshot) debugSummary() *DebugSnapshotSummary {
+	summary := &DebugSnapshotSummary{
+		Datacenter: s.Datacenter,
+		Address:    s.Address,
+		Port:       s.Port,
+		Mode:       s.Proxy.Mode,
+	}
+	if s.Roots != nil {
+		summary.ActiveRootID = s.Roots.ActiveRootID
+	}
+	if leaf := s.Leaf(); leaf != nil {
+		summary.LeafSerialNumber = leaf.SerialNumber
+		summary.LeafValidBefore = leaf.ValidBefore
+	}
+	if s.Kind == structs.ServiceKindConnectProxy {
+		for uid := range s.ConnectProxy.UpstreamConfig {
+			summary.Upstreams = append(summary.Upstreams, uid.String())
+		}
+		sort.Strings(summary.Upstreams)
+	}
+	return summary
+}
@@ -255,6 +255,9 @@ func (o *configSnapshotAPIGateway) DeepCopy() *configSnapshotAPIGateway {
 	if o.BoundGatewayConfig != nil {
 		cp.BoundGatewayConfig = o.BoundGatewayConfig.DeepCopy()
 	}
+	if o.Policy != nil {
+		cp.Policy = o.Policy.DeepCopy()
+	}
 	if o.Hosts != nil {
 		cp.Hosts = make([]string, len(o.Hosts))
 		copy(cp.Hosts, o.Hosts)
@@ -705,6 +705,10 @@ type configSnapshotAPIGateway struct {
 	BoundGatewayConfigLoaded bool
 	BoundGatewayConfig       *structs.BoundAPIGatewayConfigEntry
 
+	// Policy is the api-gateway-policy config entry attached to the gateway,
+	// or nil if the gateway has no policy.
+	Policy *structs.APIGatewayPolicyConfigEntry
+
 	// Hosts is the list of extra host entries to add to our leaf cert's DNS SANs
 	Hosts       []string
 	AreHostsSet bool
@@ -738,6 +742,18 @@ type configSnapshotAPIGateway struct {
 	BoundListeners map[string]structs.BoundAPIGatewayListener
 }
 
+// ListenerPolicy returns the name of the api-gateway listener bound to the
+// given port along with the policy attached to it. The policy is nil if the
+// listener has no policy.
+func (c *configSnapshotAPIGateway) ListenerPolicy(port int) (string, *structs.APIGatewayListenerPolicy) {
+	for name, listener := range c.Listeners {
+		if listener.Port == port {
+			return name, c.Policy.ListenerPolicy(name)
+		}
+	}
+	return "", nil
+}
+
 // ToIngress converts a configSnapshotAPIGateway to a configSnapshotIngressGateway.
 // This is temporary, for the sake of re-using existing codepaths when integrating
 // Consul API Gateway into Consul core.
@@ -764,8 +780,9 @@ func (c *configSnapshotAPIGateway) ToIngress(datacenter string) (configSnapshotI
 		}
 
 		ingressListener := structs.IngressListener{
-			Port:     listener.Port,
-			Protocol: string(listener.Protocol),
+			Port:       listener.Port,
+			Protocol:   string(listener.Protocol),
+			AccessLogs: listener.AccessLogs,
 		}
 
 		// Create a synthesized discovery chain for each service.
@@ -34,6 +34,7 @@ const (
 	gatewayConfigWatchID               = "gateway-config"
 	inlineCertificateConfigWatchID     = "inline-certificate-config"
 	routeConfigWatchID                 = "route-config"
+	gatewayPolicyConfigWatchID         = "gateway-policy-config"
 	externalServiceIDPrefix            = "external-service:"
 	serviceLeafIDPrefix                = "service-leaf:"
 	serviceConfigIDPrefix              = "service-config:"
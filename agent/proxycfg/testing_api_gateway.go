@@ -0,0 +1,221 @@
+package proxycfg
+
+import (
+	"time"
+
+	"github.com/mitchellh/go-testing-interface"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// TestConfigSnapshotAPIGateway returns a snapshot of an API gateway with an
+// HTTP listener on port 8080, named "http", that routes to the db service.
+// The given api-gateway-policy is attached to the gateway when it isn't nil.
+func TestConfigSnapshotAPIGateway(
+	t testing.T,
+	policy *structs.APIGatewayPolicyConfigEntry,
+	extraUpdates []UpdateEvent,
+) *ConfigSnapshot {
+	roots, placeholderLeaf := TestCerts(t)
+
+	entry := &structs.APIGatewayConfigEntry{
+		Kind: structs.APIGateway,
+		Name: "api-gateway",
+		Listeners: []structs.APIGatewayListener{
+			{
+				Name:     "http",
+				Port:     8080,
+				Protocol: structs.ListenerProtocolHTTP,
+			},
+		},
+	}
+	route := &structs.HTTPRouteConfigEntry{
+		Kind: structs.HTTPRoute,
+		Name: "db-route",
+		Parents: []structs.ResourceReference{
+			{
+				Kind:        structs.APIGateway,
+				Name:        "api-gateway",
+				SectionName: "http",
+			},
+		},
+		Rules: []structs.HTTPRouteRule{
+			{
+				Services: []structs.HTTPService{
+					{Name: "db"},
+				},
+			},
+		},
+	}
+	boundEntry := &structs.BoundAPIGatewayConfigEntry{
+		Kind: structs.BoundAPIGateway,
+		Name: "api-gateway",
+		Listeners: []structs.BoundAPIGatewayListener{
+			{
+				Name: "http",
+				Routes: []structs.ResourceReference{
+					{
+						Kind: structs.HTTPRoute,
+						Name: "db-route",
+					},
+				},
+			},
+		},
+	}
+
+	baseEvents := []UpdateEvent{
+		{
+			CorrelationID: rootsWatchID,
+			Result:        roots,
+		},
+		{
+			CorrelationID: leafWatchID,
+			Result:        placeholderLeaf,
+		},
+		{
+			CorrelationID: gatewayConfigWatchID,
+			Result: &structs.ConfigEntryResponse{
+				Entry: entry,
+			},
+		},
+		{
+			CorrelationID: gatewayConfigWatchID,
+			Result: &structs.ConfigEntryResponse{
+				Entry: boundEntry,
+			},
+		},
+		{
+			CorrelationID: routeConfigWatchID,
+			Result: &structs.ConfigEntryResponse{
+				Entry: route,
+			},
+		},
+	}
+	if policy != nil {
+		baseEvents = append(baseEvents, UpdateEvent{
+			CorrelationID: gatewayPolicyConfigWatchID,
+			Result: &structs.ConfigEntryResponse{
+				Entry: policy,
+			},
+		})
+	}
+
+	upstreams := structs.TestUpstreams(t)
+	upstreams = structs.Upstreams{upstreams[0]} // just keep 'db'
+
+	baseEvents = testSpliceEvents(baseEvents, setupTestVariationConfigEntriesAndSnapshot(
+		t, "default", upstreams, &structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     "db",
+			Protocol: "http",
+		},
+	))
+
+	return testConfigSnapshotFixture(t, &structs.NodeService{
+		Kind:    structs.ServiceKindAPIGateway,
+		Service: "api-gateway",
+		Address: "1.2.3.4",
+	}, nil, nil, testSpliceEvents(baseEvents, extraUpdates))
+}
+
+// TestConfigSnapshotAPIGatewayRateLimits returns a snapshot of an API gateway
+// with a policy enforcing local rate limits on the whole listener and below
+// the /api path, and a global rate limit service.
+func TestConfigSnapshotAPIGatewayRateLimits(t testing.T) *ConfigSnapshot {
+	return TestConfigSnapshotAPIGateway(t, &structs.APIGatewayPolicyConfigEntry{
+		Kind: structs.APIGatewayPolicy,
+		Name: "api-gateway",
+		Listeners: []structs.APIGatewayListenerPolicy{
+			{
+				Name: "http",
+				RateLimit: &structs.APIGatewayRateLimit{
+					Local: &structs.APIGatewayLocalRateLimit{
+						MaxTokens:     100,
+						TokensPerFill: 100,
+						FillInterval:  time.Second,
+					},
+					Routes: []structs.APIGatewayRouteRateLimit{
+						{
+							PathPrefix: "/api",
+							Local: structs.APIGatewayLocalRateLimit{
+								MaxTokens:     10,
+								TokensPerFill: 5,
+								FillInterval:  time.Second,
+							},
+						},
+					},
+					Global: &structs.APIGatewayGlobalRateLimit{
+						Domain:  "edge",
+						Address: "ratelimit.example.com",
+						Port:    8081,
+						Timeout: 50 * time.Millisecond,
+					},
+				},
+			},
+		},
+	}, nil)
+}
+
+// TestConfigSnapshotAPIGatewayJWT returns a snapshot of an API gateway with a
+// policy verifying tokens issued by a provider with a remote key set, trusted
+// through a custom CA, and a provider with a local key set.
+func TestConfigSnapshotAPIGatewayJWT(t testing.T) *ConfigSnapshot {
+	return TestConfigSnapshotAPIGateway(t, &structs.APIGatewayPolicyConfigEntry{
+		Kind: structs.APIGatewayPolicy,
+		Name: "api-gateway",
+		Listeners: []structs.APIGatewayListenerPolicy{
+			{
+				Name: "http",
+				JWT: &structs.APIGatewayJWTPolicy{
+					Providers: []structs.APIGatewayJWTProvider{
+						{
+							Name:      "okta",
+							Issuer:    "https://okta.example.com",
+							Audiences: []string{"db"},
+							JSONWebKeySet: structs.APIGatewayJSONWebKeySet{
+								RemoteURI:     "https://okta.example.com/oauth2/v1/keys",
+								CAFile:        "/etc/consul/okta-ca.pem",
+								CacheDuration: 10 * time.Minute,
+							},
+							ClaimsToHeaders: []structs.APIGatewayJWTClaimToHeader{
+								{Claim: "sub", Header: "x-user"},
+							},
+						},
+						{
+							Name:   "local",
+							Issuer: "local",
+							JSONWebKeySet: structs.APIGatewayJSONWebKeySet{
+								Local: `{"keys":[]}`,
+							},
+							Forward: true,
+						},
+					},
+					AllowMissingPaths: []string{"/health"},
+				},
+			},
+		},
+	}, nil)
+}
+
+// TestConfigSnapshotAPIGatewayExtAuthz returns a snapshot of an API gateway
+// with a policy authorizing requests with an HTTP authorization service,
+// except below the /public path.
+func TestConfigSnapshotAPIGatewayExtAuthz(t testing.T) *ConfigSnapshot {
+	return TestConfigSnapshotAPIGateway(t, &structs.APIGatewayPolicyConfigEntry{
+		Kind: structs.APIGatewayPolicy,
+		Name: "api-gateway",
+		Listeners: []structs.APIGatewayListenerPolicy{
+			{
+				Name: "http",
+				ExtAuthz: &structs.APIGatewayExtAuthz{
+					Protocol:       "http",
+					Address:        "10.0.0.5",
+					Port:           9000,
+					PathPrefix:     "/authz",
+					AllowedHeaders: []string{"x-api-key"},
+					DisabledPaths:  []string{"/public"},
+				},
+			},
+		},
+	}, nil)
+}
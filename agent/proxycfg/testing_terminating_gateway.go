@@ -1,6 +1,8 @@
 package proxycfg
 
 import (
+	"time"
+
 	"github.com/mitchellh/go-testing-interface"
 
 	"github.com/hashicorp/consul/agent/structs"
@@ -524,6 +526,135 @@ func TestConfigSnapshotTerminatingGatewayDestinations(t testing.T, populateDesti
 	}, nil, nil, testSpliceEvents(baseEvents, extraUpdates))
 }
 
+// TestConfigSnapshotTerminatingGatewayWildcardDestination returns a snapshot of
+// a terminating gateway linked to an HTTP Destination with a wildcard hostname.
+func TestConfigSnapshotTerminatingGatewayWildcardDestination(t testing.T) *ConfigSnapshot {
+	externalWildcardHTTP := structs.NewServiceName("external-wildcard-HTTP", nil)
+
+	return TestConfigSnapshotTerminatingGatewayDestinations(t, false, []UpdateEvent{
+		{
+			CorrelationID: gatewayServicesWatchID,
+			Result: &structs.IndexedGatewayServices{
+				Services: []*structs.GatewayService{
+					{
+						Service:     externalWildcardHTTP,
+						ServiceKind: structs.GatewayServiceKindDestination,
+					},
+				},
+			},
+		},
+		{
+			CorrelationID: serviceIntentionsIDPrefix + externalWildcardHTTP.String(),
+			Result:        structs.Intentions{},
+		},
+		{
+			CorrelationID: serviceLeafIDPrefix + externalWildcardHTTP.String(),
+			Result: &structs.IssuedCert{
+				CertPEM:       "placeholder.crt",
+				PrivateKeyPEM: "placeholder.key",
+			},
+		},
+		{
+			CorrelationID: serviceConfigIDPrefix + externalWildcardHTTP.String(),
+			Result: &structs.ServiceConfigResponse{
+				Mode:        structs.ProxyModeTransparent,
+				ProxyConfig: map[string]interface{}{"protocol": "http"},
+				Destination: structs.DestinationConfig{
+					Addresses: []string{"*.example.com"},
+					Port:      443,
+					DNS: &structs.DestinationDNSConfig{
+						RefreshRate: 30 * time.Second,
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestConfigSnapshotTerminatingGatewayDestinationHealthChecks(t testing.T) *ConfigSnapshot {
+	var (
+		externalHostnameTCP  = structs.NewServiceName("external-hostname-TCP", nil)
+		externalHostnameHTTP = structs.NewServiceName("external-hostname-HTTP", nil)
+	)
+
+	return TestConfigSnapshotTerminatingGatewayDestinations(t, false, []UpdateEvent{
+		{
+			CorrelationID: gatewayServicesWatchID,
+			Result: &structs.IndexedGatewayServices{
+				Services: []*structs.GatewayService{
+					{
+						Service:     externalHostnameTCP,
+						ServiceKind: structs.GatewayServiceKindDestination,
+					},
+					{
+						Service:     externalHostnameHTTP,
+						ServiceKind: structs.GatewayServiceKindDestination,
+					},
+				},
+			},
+		},
+		{
+			CorrelationID: serviceIntentionsIDPrefix + externalHostnameTCP.String(),
+			Result:        structs.Intentions{},
+		},
+		{
+			CorrelationID: serviceIntentionsIDPrefix + externalHostnameHTTP.String(),
+			Result:        structs.Intentions{},
+		},
+		{
+			CorrelationID: serviceLeafIDPrefix + externalHostnameTCP.String(),
+			Result: &structs.IssuedCert{
+				CertPEM:       "placeholder.crt",
+				PrivateKeyPEM: "placeholder.key",
+			},
+		},
+		{
+			CorrelationID: serviceLeafIDPrefix + externalHostnameHTTP.String(),
+			Result: &structs.IssuedCert{
+				CertPEM:       "placeholder.crt",
+				PrivateKeyPEM: "placeholder.key",
+			},
+		},
+		{
+			CorrelationID: serviceConfigIDPrefix + externalHostnameTCP.String(),
+			Result: &structs.ServiceConfigResponse{
+				Mode:        structs.ProxyModeTransparent,
+				ProxyConfig: map[string]interface{}{"protocol": "tcp"},
+				Destination: structs.DestinationConfig{
+					Addresses: []string{"api.hashicorp.com"},
+					Port:      8089,
+					HealthCheck: &structs.DestinationHealthCheck{
+						Protocol: "tcp",
+					},
+				},
+			},
+		},
+		{
+			CorrelationID: serviceConfigIDPrefix + externalHostnameHTTP.String(),
+			Result: &structs.ServiceConfigResponse{
+				Mode:        structs.ProxyModeTransparent,
+				ProxyConfig: map[string]interface{}{"protocol": "http"},
+				Destination: structs.DestinationConfig{
+					Addresses: []string{"httpbin.org"},
+					Port:      80,
+					HealthCheck: &structs.DestinationHealthCheck{
+						Protocol:           "http",
+						Path:               "/status/200",
+						Interval:           15 * time.Second,
+						Timeout:            2 * time.Second,
+						UnhealthyThreshold: 3,
+						HealthyThreshold:   1,
+					},
+					DNS: &structs.DestinationDNSConfig{
+						RespectTTL:    true,
+						DiscoveryType: structs.DestinationDNSDiscoveryStrict,
+					},
+				},
+			},
+		},
+	})
+}
+
 func TestConfigSnapshotTerminatingGatewayServiceSubsets(t testing.T) *ConfigSnapshot {
 	return testConfigSnapshotTerminatingGatewayServiceSubsets(t, false)
 }
@@ -891,6 +1022,63 @@ func TestConfigSnapshotTerminatingGatewayHostnameSubsets(t testing.T) *ConfigSna
 	})
 }
 
+// TestConfigSnapshotTerminatingGatewayMetaSubsets returns a snapshot of a
+// terminating gateway whose services have subsets selecting their instances by
+// service meta only.
+func TestConfigSnapshotTerminatingGatewayMetaSubsets(t testing.T) *ConfigSnapshot {
+	var (
+		web = structs.NewServiceName("web", nil)
+		api = structs.NewServiceName("api", nil)
+	)
+
+	return TestConfigSnapshotTerminatingGateway(t, true, nil, []UpdateEvent{
+		{
+			CorrelationID: serviceResolverIDPrefix + web.String(),
+			Result: &structs.ConfigEntryResponse{
+				Entry: &structs.ServiceResolverConfigEntry{
+					Kind: structs.ServiceResolver,
+					Name: "web",
+					Subsets: map[string]structs.ServiceResolverSubset{
+						"v1": {
+							Meta: map[string]string{"version": "1"},
+						},
+						"v2": {
+							Meta:        map[string]string{"version": "2"},
+							OnlyPassing: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			CorrelationID: serviceResolverIDPrefix + api.String(),
+			Result: &structs.ConfigEntryResponse{
+				Entry: &structs.ServiceResolverConfigEntry{
+					Kind: structs.ServiceResolver,
+					Name: "api",
+					Subsets: map[string]structs.ServiceResolverSubset{
+						"alt": {
+							Meta: map[string]string{"domain": "alt"},
+						},
+					},
+				},
+			},
+		},
+		{
+			CorrelationID: serviceConfigIDPrefix + web.String(),
+			Result: &structs.ServiceConfigResponse{
+				ProxyConfig: map[string]interface{}{"protocol": "http"},
+			},
+		},
+		{
+			CorrelationID: serviceConfigIDPrefix + api.String(),
+			Result: &structs.ServiceConfigResponse{
+				ProxyConfig: map[string]interface{}{"protocol": "http"},
+			},
+		},
+	})
+}
+
 func TestConfigSnapshotTerminatingGatewayIgnoreExtraResolvers(t testing.T) *ConfigSnapshot {
 	var (
 		web      = structs.NewServiceName("web", nil)
@@ -983,3 +1171,19 @@ func TestConfigSnapshotTerminatingGatewayWithLambdaServiceAndServiceResolvers(t
 			},
 		})
 }
+
+// TestConfigSnapshotTerminatingGatewayWithExtension returns a snapshot of a
+// terminating gateway with the given extension configured for the cache
+// service, whose instances are addressed by hostname.
+func TestConfigSnapshotTerminatingGatewayWithExtension(t testing.T, ext structs.EnvoyExtension) *ConfigSnapshot {
+	cache := structs.NewServiceName("cache", nil)
+	return TestConfigSnapshotTerminatingGateway(t, true, nil, []UpdateEvent{
+		{
+			CorrelationID: serviceConfigIDPrefix + cache.String(),
+			Result: &structs.ServiceConfigResponse{
+				ProxyConfig:     map[string]interface{}{"protocol": "tcp"},
+				EnvoyExtensions: []structs.EnvoyExtension{ext},
+			},
+		},
+	})
+}
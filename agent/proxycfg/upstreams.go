@@ -435,7 +435,7 @@ type targetWatchOpts struct {
 func (o *targetWatchOpts) fromChainTarget(c *structs.CompiledDiscoveryChain, t *structs.DiscoveryTarget) {
 	o.chainID = t.ID
 	o.service = t.Service
-	o.filter = t.Subset.Filter
+	o.filter = t.Subset.FilterExpression()
 	o.datacenter = t.Datacenter
 	o.peer = t.Peer
 	o.entMeta = t.GetEnterpriseMetadata()
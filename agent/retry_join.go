@@ -2,9 +2,14 @@ package agent
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/consul/agent/discover/endpointslices"
+	"github.com/hashicorp/consul/agent/discover/hetzner"
+	"github.com/hashicorp/consul/agent/discover/oci"
 	discoverhcp "github.com/hashicorp/consul/agent/hcp/discover"
 	discover "github.com/hashicorp/go-discover"
 	discoverk8s "github.com/hashicorp/go-discover/provider/k8s"
@@ -18,6 +23,7 @@ func (a *Agent) retryJoinLAN() {
 		variant:     retryJoinSerfVariant,
 		cluster:     "LAN",
 		addrs:       a.config.RetryJoinLAN,
+		filters:     a.config.RetryJoinFilters,
 		maxAttempts: a.config.RetryJoinMaxAttemptsLAN,
 		interval:    a.config.RetryJoinIntervalLAN,
 		join: func(addrs []string) (int, error) {
@@ -76,6 +82,7 @@ func (a *Agent) retryJoinWAN() {
 		variant:     retryJoinSerfVariant,
 		cluster:     "WAN",
 		addrs:       joinAddrs,
+		filters:     a.config.RetryJoinFilters,
 		maxAttempts: a.config.RetryJoinMaxAttemptsWAN,
 		interval:    a.config.RetryJoinIntervalWAN,
 		join:        a.JoinWAN,
@@ -91,6 +98,7 @@ func (a *Agent) refreshPrimaryGatewayFallbackAddresses() {
 		variant:     retryJoinMeshGatewayVariant,
 		cluster:     "primary",
 		addrs:       a.config.PrimaryGateways,
+		filters:     a.config.RetryJoinFilters,
 		maxAttempts: 0,
 		interval:    a.config.PrimaryGatewaysInterval,
 		join: func(addrs []string) (int, error) {
@@ -114,6 +122,9 @@ func newDiscover() (*discover.Discover, error) {
 	}
 	providers["k8s"] = &discoverk8s.Provider{}
 	providers["hcp"] = &discoverhcp.Provider{}
+	providers["hetzner"] = &hetzner.Provider{}
+	providers["oci"] = &oci.Provider{}
+	providers["k8s-endpointslices"] = &endpointslices.Provider{}
 
 	return discover.New(
 		discover.WithUserAgent(lib.UserAgent()),
@@ -121,7 +132,7 @@ func newDiscover() (*discover.Discover, error) {
 	)
 }
 
-func retryJoinAddrs(disco *discover.Discover, variant, cluster string, retryJoin []string, logger hclog.Logger) []string {
+func retryJoinAddrs(disco *discover.Discover, variant, cluster string, retryJoin []string, filters map[string]map[string]string, logger hclog.Logger) []string {
 	addrs := []string{}
 	if disco == nil {
 		return addrs
@@ -129,7 +140,7 @@ func retryJoinAddrs(disco *discover.Discover, variant, cluster string, retryJoin
 	for _, addr := range retryJoin {
 		switch {
 		case strings.Contains(addr, "provider="):
-			servers, err := disco.Addrs(addr, logger.StandardLogger(&hclog.StandardLoggerOptions{
+			servers, err := disco.Addrs(withRetryJoinFilters(addr, filters), logger.StandardLogger(&hclog.StandardLoggerOptions{
 				InferLevels: true,
 			}))
 			if err != nil {
@@ -164,6 +175,37 @@ func retryJoinAddrs(disco *discover.Discover, variant, cluster string, retryJoin
 	return addrs
 }
 
+// withRetryJoinFilters adds the filters configured for the provider of a
+// go-discover configuration to it. The arguments set in the configuration
+// take precedence over the filters.
+func withRetryJoinFilters(addr string, filters map[string]map[string]string) string {
+	args, err := discover.Parse(addr)
+	if err != nil || len(filters[args["provider"]]) == 0 {
+		// Parsing errors are reported by disco.Addrs.
+		return addr
+	}
+
+	providerFilters := filters[args["provider"]]
+	keys := make([]string, 0, len(providerFilters))
+	for k := range providerFilters {
+		if _, ok := args[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	quote := func(s string) string {
+		if strings.ContainsAny(s, ` "\=`) {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+	for _, k := range keys {
+		addr += " " + quote(k) + "=" + quote(providerFilters[k])
+	}
+	return addr
+}
+
 const (
 	retryJoinSerfVariant        = "serf"
 	retryJoinMeshGatewayVariant = "mesh-gateway"
@@ -183,6 +225,10 @@ type retryJoiner struct {
 	// to join with.
 	addrs []string
 
+	// filters are the arguments added to the go-discover configurations,
+	// by provider.
+	filters map[string]map[string]string
+
 	// maxAttempts is the number of join attempts before giving up.
 	maxAttempts int
 
@@ -224,7 +270,7 @@ func (r *retryJoiner) retryJoin() error {
 
 	attempt := 0
 	for {
-		addrs := retryJoinAddrs(disco, r.variant, r.cluster, r.addrs, r.logger)
+		addrs := retryJoinAddrs(disco, r.variant, r.cluster, r.addrs, r.filters, r.logger)
 		if len(addrs) > 0 {
 			n := 0
 			n, err = r.join(addrs)
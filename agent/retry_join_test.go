@@ -12,9 +12,9 @@ func TestAgentRetryNewDiscover(t *testing.T) {
 	d, err := newDiscover()
 	require.NoError(t, err)
 	expected := []string{
-		"aliyun", "aws", "azure", "digitalocean", "gce", "hcp", "k8s", "linode",
-		"mdns", "os", "packet", "scaleway", "softlayer", "tencentcloud",
-		"triton", "vsphere",
+		"aliyun", "aws", "azure", "digitalocean", "gce", "hcp", "hetzner", "k8s",
+		"k8s-endpointslices", "linode", "mdns", "oci", "os", "packet", "scaleway",
+		"softlayer", "tencentcloud", "triton", "vsphere",
 	}
 	require.Equal(t, expected, d.Names())
 }
@@ -52,7 +52,7 @@ func TestAgentRetryJoinAddrs(t *testing.T) {
 			var buf bytes.Buffer
 			logger := testutil.LoggerWithOutput(t, &buf)
 
-			output := retryJoinAddrs(d, retryJoinSerfVariant, "LAN", test.input, logger)
+			output := retryJoinAddrs(d, retryJoinSerfVariant, "LAN", test.input, nil, logger)
 			bufout := buf.String()
 			require.Equal(t, test.expected, output, bufout)
 			if i == 4 {
@@ -61,6 +61,45 @@ func TestAgentRetryJoinAddrs(t *testing.T) {
 		})
 	}
 	t.Run("handles nil discover", func(t *testing.T) {
-		require.Equal(t, []string{}, retryJoinAddrs(nil, retryJoinSerfVariant, "LAN", []string{"a"}, nil))
+		require.Equal(t, []string{}, retryJoinAddrs(nil, retryJoinSerfVariant, "LAN", []string{"a"}, nil, nil))
 	})
 }
+
+func TestAgentRetryJoinFilters(t *testing.T) {
+	filters := map[string]map[string]string{
+		"aws": {"tag_key": "consul", "tag_value": "server"},
+		"k8s": {"label_selector": "app=consul,component=server"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"adds the filters of the provider",
+			"provider=aws region=eu-west-1",
+			"provider=aws region=eu-west-1 tag_key=consul tag_value=server",
+		},
+		{"keeps the arguments of the configuration",
+			"provider=aws tag_key=consul tag_value=client",
+			"provider=aws tag_key=consul tag_value=client",
+		},
+		{"quotes the filters if needed",
+			"provider=k8s namespace=consul",
+			`provider=k8s namespace=consul label_selector="app=consul,component=server"`,
+		},
+		{"ignores providers without filters",
+			"provider=gce project_name=consul",
+			"provider=gce project_name=consul",
+		},
+		{"ignores invalid configurations",
+			"provider=aws region",
+			"provider=aws region",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, withRetryJoinFilters(test.input, filters))
+		})
+	}
+}
@@ -202,6 +202,19 @@ func (m *Manager) FindServer() *metadata.Server {
 	return l.servers[0]
 }
 
+// FindReadReplica searches through the list of servers for the first read
+// replica, which serves the stale reads. The list of servers is shuffled, so
+// that the stale reads of the clients spread over the read replicas. If there
+// are no read replicas, return nil.
+func (m *Manager) FindReadReplica() *metadata.Server {
+	for _, srv := range m.getServerList().servers {
+		if srv.ReadReplica {
+			return srv
+		}
+	}
+	return nil
+}
+
 func (m *Manager) checkServers(fn func(srv *metadata.Server) bool) bool {
 	if m == nil {
 		return true
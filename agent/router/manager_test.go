@@ -194,6 +194,25 @@ func TestServers_FindServer(t *testing.T) {
 	}
 }
 
+// func (m *Manager) FindReadReplica() (server *metadata.Server) {
+func TestServers_FindReadReplica(t *testing.T) {
+	m := testManager(t)
+	if m.FindReadReplica() != nil {
+		t.Fatalf("Expected nil return")
+	}
+
+	m.AddServer(&metadata.Server{Name: "s1"})
+	if m.FindReadReplica() != nil {
+		t.Fatalf("Expected nil return without read replicas")
+	}
+
+	m.AddServer(&metadata.Server{Name: "s2", ReadReplica: true})
+	s := m.FindReadReplica()
+	if s == nil || s.Name != "s2" {
+		t.Fatalf("Expected the read replica, got %v", s)
+	}
+}
+
 func TestServers_New(t *testing.T) {
 	logger := testutil.Logger(t)
 	shutdownCh := make(chan struct{})
@@ -387,6 +387,22 @@ func (r *Router) FindLANRoute() (*Manager, *metadata.Server) {
 	return mgr, mgr.FindServer()
 }
 
+// FindLANReadRoute returns a read replica within the local datacenter, to
+// serve a stale read. It falls back to FindLANRoute when the datacenter has
+// no read replicas.
+func (r *Router) FindLANReadRoute() (*Manager, *metadata.Server) {
+	mgr := r.GetLANManager()
+
+	if mgr == nil {
+		return nil, nil
+	}
+
+	if srv := mgr.FindReadReplica(); srv != nil {
+		return mgr, srv
+	}
+	return mgr, mgr.FindServer()
+}
+
 // FindLANServer will look for a server in the local datacenter.
 // This function may return a nil value if no server is available.
 func (r *Router) FindLANServer() *metadata.Server {
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+)
+
+var metricBlockingQueries = []string{"rpc", "server", "blocking_queries"}
+var metricBlockingQueryWait = []string{"rpc", "server", "blocking_query_wait"}
+
+var BlockingQueryGauges = []prometheus.GaugeDefinition{
+	{
+		Name: metricBlockingQueries,
+		Help: "Shows the current number of in-flight blocking queries received by the server, labeled by RPC method.",
+	},
+}
+
+var BlockingQuerySummaries = []prometheus.SummaryDefinition{
+	{
+		Name: metricBlockingQueryWait,
+		Help: "Measures the time in milliseconds a blocking query waited before returning, labeled by RPC method.",
+	},
+}
+
+// blockingQueries counts the in-flight blocking queries by method.
+type blockingQueries struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// add adds delta to the in-flight blocking queries of the method and returns
+// their number.
+func (b *blockingQueries) add(method string, delta int) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.counts == nil {
+		b.counts = make(map[string]int)
+	}
+	b.counts[method] += delta
+	count := b.counts[method]
+	if count == 0 {
+		delete(b.counts, method)
+	}
+	return count
+}
+
+// TrackBlockingQuery records the start of a blocking query, if the request is
+// one, and returns the function to call once it returns. It updates the
+// number of in-flight blocking queries and measures the wait of the query,
+// both labeled by method.
+func (r *RequestRecorder) TrackBlockingQuery(method string, request interface{}) func() {
+	rq, ok := request.(readQuery)
+	if !ok || rq.GetMinQueryIndex() == 0 {
+		return func() {}
+	}
+
+	start := time.Now()
+	labels := []metrics.Label{{Name: "method", Value: method}}
+	r.setGauge(metricBlockingQueries, float32(r.blockingQueries.add(method, 1)), labels)
+
+	return func() {
+		r.setGauge(metricBlockingQueries, float32(r.blockingQueries.add(method, -1)), labels)
+		if r.RecorderFunc != nil {
+			r.RecorderFunc(metricBlockingQueryWait, float32(time.Since(start).Microseconds())/1000, labels)
+		}
+	}
+}
+
+func (r *RequestRecorder) setGauge(key []string, val float32, labels []metrics.Label) {
+	if r.GaugeFunc != nil {
+		r.GaugeFunc(key, val, labels)
+	}
+}
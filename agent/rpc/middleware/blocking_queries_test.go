@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingRequest struct {
+	minQueryIndex uint64
+}
+
+func (r blockingRequest) GetMinQueryIndex() uint64 {
+	return r.minQueryIndex
+}
+
+func (r blockingRequest) AllowStaleRead() bool {
+	return true
+}
+
+func TestGetNetRPCInterceptor_BlockingQueries(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		gauges []float32
+		waits  []string
+	)
+	r := &RequestRecorder{
+		Logger: hclog.NewNullLogger(),
+		RecorderFunc: func(key []string, _ float32, labels []metrics.Label) {
+			mu.Lock()
+			defer mu.Unlock()
+			if reflect.DeepEqual(key, metricBlockingQueryWait) {
+				waits = append(waits, labels[0].Value)
+			}
+		},
+		GaugeFunc: func(key []string, val float32, labels []metrics.Label) {
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, metricBlockingQueries, key)
+			require.Equal(t, []metrics.Label{{Name: "method", Value: "Health.ServiceNodes"}}, labels)
+			gauges = append(gauges, val)
+		},
+	}
+	interceptor := GetNetRPCInterceptor(r)
+
+	call := func(request interface{}, handler func() error) {
+		interceptor("Health.ServiceNodes", reflect.ValueOf(request), reflect.Value{}, handler)
+	}
+
+	// The non-blocking queries aren't tracked.
+	call(blockingRequest{}, func() error { return nil })
+	call(struct{}{}, func() error { return nil })
+	require.Empty(t, gauges)
+	require.Empty(t, waits)
+
+	// The in-flight blocking queries are counted while they wait.
+	call(blockingRequest{minQueryIndex: 5}, func() error {
+		call(blockingRequest{minQueryIndex: 7}, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, []float32{1, 2}, gauges)
+			return nil
+		})
+		return nil
+	})
+	require.Equal(t, []float32{1, 2, 1, 0}, gauges)
+	require.Equal(t, []string{"Health.ServiceNodes", "Health.ServiceNodes"}, waits)
+	require.Empty(t, r.blockingQueries.counts)
+}
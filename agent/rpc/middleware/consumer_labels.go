@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/armon/go-metrics"
+)
+
+// ConsumerLabelOverflow is the value of the consumer labels once the number
+// of distinct values reaches the limit.
+const ConsumerLabelOverflow = "_other"
+
+// ConsumerLabelsConfig configures the labels attributing the RPC requests to
+// the services and peers they are made for.
+type ConsumerLabelsConfig struct {
+	// Service adds the name of the service of the request as the "service"
+	// label.
+	Service bool
+
+	// Peer adds the name of the peer of the request as the "peer" label.
+	Peer bool
+
+	// MaxValues is the maximum number of distinct values of each label. The
+	// values seen once it is reached are reported as ConsumerLabelOverflow.
+	MaxValues int
+}
+
+type serviceRequest interface {
+	RequestServiceName() string
+}
+
+type peerRequest interface {
+	RequestPeerName() string
+}
+
+// consumerLabels adds the consumer labels to the requests, bounding the
+// number of distinct values of each label.
+type consumerLabels struct {
+	config   ConsumerLabelsConfig
+	services *labelValues
+	peers    *labelValues
+}
+
+func newConsumerLabels(config ConsumerLabelsConfig) *consumerLabels {
+	return &consumerLabels{
+		config:   config,
+		services: newLabelValues(config.MaxValues),
+		peers:    newLabelValues(config.MaxValues),
+	}
+}
+
+func (c *consumerLabels) add(request interface{}, labels []metrics.Label) []metrics.Label {
+	if c.config.Service {
+		if r, ok := request.(serviceRequest); ok && r.RequestServiceName() != "" {
+			labels = append(labels, metrics.Label{Name: "service", Value: c.services.value(r.RequestServiceName())})
+		}
+	}
+	if c.config.Peer {
+		if r, ok := request.(peerRequest); ok && r.RequestPeerName() != "" {
+			labels = append(labels, metrics.Label{Name: "peer", Value: c.peers.value(r.RequestPeerName())})
+		}
+	}
+	return labels
+}
+
+// labelValues tracks the distinct values of a label.
+type labelValues struct {
+	max  int
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newLabelValues(max int) *labelValues {
+	return &labelValues{max: max, seen: make(map[string]struct{})}
+}
+
+// value returns v if it was already seen or if the limit isn't reached yet,
+// and ConsumerLabelOverflow otherwise.
+func (l *labelValues) value(v string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+	if len(l.seen) >= l.max {
+		return ConsumerLabelOverflow
+	}
+	l.seen[v] = struct{}{}
+	return v
+}
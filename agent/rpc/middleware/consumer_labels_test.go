@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type consumerRequest struct {
+	service string
+	peer    string
+}
+
+func (r consumerRequest) RequestServiceName() string {
+	return r.service
+}
+
+func (r consumerRequest) RequestPeerName() string {
+	return r.peer
+}
+
+func TestRequestRecorder_ConsumerLabels(t *testing.T) {
+	var recorded []metrics.Label
+	r := &RequestRecorder{
+		Logger: hclog.NewNullLogger(),
+		RecorderFunc: func(_ []string, _ float32, labels []metrics.Label) {
+			recorded = labels
+		},
+	}
+
+	consumerLabels := func(request interface{}) map[string]string {
+		r.Record("Health.ServiceNodes", RPCTypeNetRPC, time.Now(), request, false)
+		labels := make(map[string]string)
+		for _, l := range recorded {
+			if l.Name == "service" || l.Name == "peer" {
+				labels[l.Name] = l.Value
+			}
+		}
+		return labels
+	}
+
+	// The labels are disabled by default.
+	require.Empty(t, consumerLabels(consumerRequest{service: "web", peer: "cluster-01"}))
+
+	r.SetConsumerLabels(ConsumerLabelsConfig{Service: true, MaxValues: 2})
+	require.Equal(t, map[string]string{"service": "web"}, consumerLabels(consumerRequest{service: "web", peer: "cluster-01"}))
+	require.Equal(t, map[string]string{"service": "api"}, consumerLabels(consumerRequest{service: "api"}))
+	// The values beyond the limit are reported in the overflow bucket, the
+	// values already seen are kept.
+	require.Equal(t, map[string]string{"service": ConsumerLabelOverflow}, consumerLabels(consumerRequest{service: "db"}))
+	require.Equal(t, map[string]string{"service": "web"}, consumerLabels(consumerRequest{service: "web"}))
+	// The requests without a service aren't labeled.
+	require.Empty(t, consumerLabels(consumerRequest{}))
+	require.Empty(t, consumerLabels(struct{}{}))
+
+	// The values seen are kept when the configuration doesn't change.
+	r.SetConsumerLabels(ConsumerLabelsConfig{Service: true, MaxValues: 2})
+	require.Equal(t, map[string]string{"service": ConsumerLabelOverflow}, consumerLabels(consumerRequest{service: "db"}))
+
+	r.SetConsumerLabels(ConsumerLabelsConfig{Service: true, Peer: true, MaxValues: 2})
+	require.Equal(t, map[string]string{"service": "db", "peer": "cluster-01"}, consumerLabels(consumerRequest{service: "db", peer: "cluster-01"}))
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -37,19 +38,37 @@ var OneTwelveRPCSummary = []prometheus.SummaryDefinition{
 type RequestRecorder struct {
 	Logger         hclog.Logger
 	RecorderFunc   func(key []string, val float32, labels []metrics.Label)
+	GaugeFunc      func(key []string, val float32, labels []metrics.Label)
 	serverIsLeader func() bool
 	localDC        string
+
+	// consumerLabels holds the *consumerLabels added to the requests, it is
+	// unset when they are disabled.
+	consumerLabels atomic.Value
+
+	blockingQueries blockingQueries
 }
 
 func NewRequestRecorder(logger hclog.Logger, isLeader func() bool, localDC string) *RequestRecorder {
 	return &RequestRecorder{
 		Logger:         logger,
 		RecorderFunc:   metrics.AddSampleWithLabels,
+		GaugeFunc:      metrics.SetGaugeWithLabels,
 		serverIsLeader: isLeader,
 		localDC:        localDC,
 	}
 }
 
+// SetConsumerLabels configures the labels attributing the requests to the
+// services and peers they are made for. The values seen so far are reset
+// when the configuration changes.
+func (r *RequestRecorder) SetConsumerLabels(config ConsumerLabelsConfig) {
+	if c, ok := r.consumerLabels.Load().(*consumerLabels); ok && c.config == config {
+		return
+	}
+	r.consumerLabels.Store(newConsumerLabels(config))
+}
+
 func (r *RequestRecorder) Record(requestName string, rpcType string, start time.Time, request interface{}, respErrored bool) {
 	elapsed := time.Since(start).Microseconds()
 	elapsedMs := float32(elapsed) / 1000
@@ -108,6 +127,10 @@ func (r *RequestRecorder) addOptionalLabels(request interface{}, labels []metric
 		}
 	}
 
+	if c, ok := r.consumerLabels.Load().(*consumerLabels); ok {
+		labels = c.add(request, labels)
+	}
+
 	return labels
 }
 
@@ -154,7 +177,9 @@ func GetNetRPCInterceptor(recorder *RequestRecorder) rpc.ServerServiceCallInterc
 	return func(reqServiceMethod string, argv, replyv reflect.Value, handler func() error) {
 		reqStart := time.Now()
 
+		done := recorder.TrackBlockingQuery(reqServiceMethod, argv.Interface())
 		err := handler()
+		done()
 
 		recorder.Record(reqServiceMethod, RPCTypeNetRPC, reqStart, argv.Interface(), err != nil)
 	}
@@ -182,3 +207,9 @@ func GetNetRPCRateLimitingInterceptor(requestLimitsHandler rpcRate.RequestLimits
 		return requestLimitsHandler.Allow(op)
 	}
 }
+
+// NetRPCOperationType returns the type of the net/rpc endpoint for rate
+// limiting purposes.
+func NetRPCOperationType(reqServiceMethod string) rpcRate.OperationType {
+	return rpcRateLimitSpecs[reqServiceMethod]
+}
@@ -11,6 +11,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"ACL.AuthMethodRead":    rate.OperationTypeRead,
 	"ACL.AuthMethodSet":     rate.OperationTypeWrite,
 	"ACL.Authorize":         rate.OperationTypeRead,
+	"ACL.Explain":           rate.OperationTypeRead,
 	"ACL.BindingRuleDelete": rate.OperationTypeWrite,
 	"ACL.BindingRuleList":   rate.OperationTypeRead,
 	"ACL.BindingRuleRead":   rate.OperationTypeRead,
@@ -18,6 +19,8 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"ACL.BootstrapTokens":   rate.OperationTypeRead,
 	"ACL.Login":             rate.OperationTypeWrite,
 	"ACL.Logout":            rate.OperationTypeWrite,
+	"ACL.OIDCAuthURL":       rate.OperationTypeWrite,
+	"ACL.OIDCCallback":      rate.OperationTypeWrite,
 	"ACL.PolicyBatchRead":   rate.OperationTypeRead,
 	"ACL.PolicyDelete":      rate.OperationTypeWrite,
 	"ACL.PolicyList":        rate.OperationTypeRead,
@@ -32,16 +35,20 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"ACL.RoleResolve":       rate.OperationTypeRead,
 	"ACL.RoleSet":           rate.OperationTypeWrite,
 	"ACL.TokenBatchRead":    rate.OperationTypeRead,
+	"ACL.TokenBulk":         rate.OperationTypeWrite,
 	"ACL.TokenClone":        rate.OperationTypeRead,
 	"ACL.TokenDelete":       rate.OperationTypeWrite,
 	"ACL.TokenList":         rate.OperationTypeRead,
 	"ACL.TokenRead":         rate.OperationTypeRead,
+	"ACL.TokenRotate":       rate.OperationTypeWrite,
 	"ACL.TokenSet":          rate.OperationTypeWrite,
+	"ACL.TokenUsageReport":  rate.OperationTypeWrite,
 
 	"AutoConfig.InitialConfiguration": rate.OperationTypeRead,
 
 	"AutoEncrypt.Sign": rate.OperationTypeWrite,
 
+	"Catalog.Batch":               rate.OperationTypeWrite,
 	"Catalog.Deregister":          rate.OperationTypeWrite,
 	"Catalog.GatewayServices":     rate.OperationTypeRead,
 	"Catalog.ListDatacenters":     rate.OperationTypeRead,
@@ -52,6 +59,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"Catalog.Register":            rate.OperationTypeWrite,
 	"Catalog.ServiceList":         rate.OperationTypeRead,
 	"Catalog.ServiceNodes":        rate.OperationTypeRead,
+	"Catalog.ServiceTombstones":   rate.OperationTypeRead,
 	"Catalog.VirtualIPForService": rate.OperationTypeRead,
 
 	"ConfigEntry.Apply":                rate.OperationTypeWrite,
@@ -115,6 +123,8 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"Operator.AutopilotGetConfiguration": rate.OperationTypeExempt,
 	"Operator.AutopilotSetConfiguration": rate.OperationTypeExempt,
 	"Operator.AutopilotState":            rate.OperationTypeExempt,
+	"Operator.KeyringRotationStatus":     rate.OperationTypeExempt,
+	"Operator.LockContention":            rate.OperationTypeExempt,
 	"Operator.RaftGetConfiguration":      rate.OperationTypeExempt,
 	"Operator.RaftRemovePeerByAddress":   rate.OperationTypeExempt,
 	"Operator.RaftRemovePeerByID":        rate.OperationTypeExempt,
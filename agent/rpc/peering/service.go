@@ -742,6 +742,23 @@ func (s *Server) reconcilePeering(peering *pbpeering.Peering) *pbpeering.Peering
 			LastReceive:      pbpeering.TimePtrToProto(lastRecv),
 			LastSend:         pbpeering.TimePtrToProto(lastSend),
 		}
+		if usage, ok := s.Tracker.StreamUsage(peering.ID); ok {
+			cp.StreamStatus.MessagesReceived = usage.MessagesReceived
+			cp.StreamStatus.MessagesSent = usage.MessagesSent
+			cp.StreamStatus.MessagesRateLimited = usage.MessagesRateLimited
+		}
+		if diag, ok := s.Tracker.StreamDiagnostics(peering.ID); ok {
+			cp.StreamStatus.PendingResponses = diag.PendingResponses
+			cp.StreamStatus.RetryAttempts = diag.RetryAttempts
+			cp.StreamStatus.NextRetry = pbpeering.TimePtrToProto(diag.NextRetry)
+			for _, e := range diag.RecentErrors {
+				cp.StreamStatus.RecentErrors = append(cp.StreamStatus.RecentErrors, &pbpeering.StreamError{
+					Time:    timestamppb.New(e.Time),
+					Kind:    e.Kind,
+					Message: e.Message,
+				})
+			}
+		}
 
 		return cp
 	}
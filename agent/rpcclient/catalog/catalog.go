@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/submatview"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+// Client provides access to the catalog list of services.
+//
+// Only the list of services is served by the streaming backend. The nodes
+// which don't have any service instance are never part of the events of the
+// ServiceHealth topic, so the list of nodes can't be materialized from them
+// and is always served with blocking queries.
+type Client struct {
+	NetRPC              NetRPC
+	Cache               CacheGetter
+	ViewStore           MaterializedViewStore
+	MaterializerDeps    MaterializerDeps
+	CacheName           string
+	UseStreamingBackend bool
+	QueryOptionDefaults func(options *structs.QueryOptions)
+}
+
+type NetRPC interface {
+	RPC(ctx context.Context, method string, args interface{}, reply interface{}) error
+}
+
+type CacheGetter interface {
+	Get(ctx context.Context, t string, r cache.Request) (interface{}, cache.ResultMeta, error)
+}
+
+type MaterializedViewStore interface {
+	Get(ctx context.Context, req submatview.Request) (submatview.Result, error)
+}
+
+// ListServices returns the names of the services of the catalog, with the
+// union of the tags of their instances.
+func (c *Client) ListServices(
+	ctx context.Context,
+	req structs.DCSpecificRequest,
+) (structs.IndexedServices, cache.ResultMeta, error) {
+	if c.useStreaming(req) && (req.QueryOptions.UseCache || req.QueryOptions.MinQueryIndex > 0) {
+		c.QueryOptionDefaults(&req.QueryOptions)
+
+		result, err := c.ViewStore.Get(ctx, c.newServicesRequest(req))
+		if err != nil {
+			return structs.IndexedServices{}, cache.ResultMeta{}, err
+		}
+		meta := cache.ResultMeta{Index: result.Index, Hit: result.Cached}
+		return *result.Value.(*structs.IndexedServices), meta, err
+	}
+
+	out, md, err := c.getServices(ctx, req)
+	if err != nil {
+		return out, md, err
+	}
+
+	if req.QueryOptions.AllowStale && req.QueryOptions.MaxStaleDuration > 0 && out.QueryMeta.LastContact > req.MaxStaleDuration {
+		req.AllowStale = false
+		err := c.NetRPC.RPC(context.Background(), "Catalog.ListServices", &req, &out)
+		return out, cache.ResultMeta{}, err
+	}
+
+	return out, md, err
+}
+
+func (c *Client) getServices(
+	ctx context.Context,
+	req structs.DCSpecificRequest,
+) (structs.IndexedServices, cache.ResultMeta, error) {
+	var out structs.IndexedServices
+	if !req.QueryOptions.UseCache {
+		err := c.NetRPC.RPC(context.Background(), "Catalog.ListServices", &req, &out)
+		return out, cache.ResultMeta{}, err
+	}
+
+	raw, md, err := c.Cache.Get(ctx, c.CacheName, &req)
+	if err != nil {
+		return out, md, err
+	}
+
+	value, ok := raw.(*structs.IndexedServices)
+	if !ok {
+		panic("wrong response type for cachetype.CatalogListServicesName")
+	}
+
+	return *value, md, nil
+}
+
+// useStreaming returns whether the request can be served by the streaming
+// backend. The bexpr filters of the list of services are evaluated against
+// the structs.ServiceNode of the catalog, which the events don't carry, and
+// the pages are only computed by the servers.
+func (c *Client) useStreaming(req structs.DCSpecificRequest) bool {
+	return c.UseStreamingBackend && req.Filter == "" && req.PeerName == structs.DefaultPeerKeyword &&
+		!req.IsPaginated()
+}
+
+func (c *Client) newServicesRequest(req structs.DCSpecificRequest) servicesRequest {
+	return servicesRequest{
+		DCSpecificRequest: req,
+		deps:              c.MaterializerDeps,
+	}
+}
+
+type servicesRequest struct {
+	structs.DCSpecificRequest
+	deps MaterializerDeps
+}
+
+func (r servicesRequest) CacheInfo() cache.RequestInfo {
+	return r.DCSpecificRequest.CacheInfo()
+}
+
+func (r servicesRequest) Type() string {
+	return "agent.rpcclient.catalog.servicesRequest"
+}
+
+func (r servicesRequest) NewMaterializer() (submatview.Materializer, error) {
+	deps := submatview.Deps{
+		View:    NewServicesView(r.DCSpecificRequest),
+		Logger:  r.deps.Logger,
+		Request: NewMaterializerRequest(r.DCSpecificRequest),
+	}
+
+	return submatview.NewRPCMaterializer(pbsubscribe.NewStateChangeSubscriptionClient(r.deps.Conn), deps), nil
+}
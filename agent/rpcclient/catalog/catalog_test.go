@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/submatview"
+)
+
+func TestClient_ListServices_BackendRouting(t *testing.T) {
+	type testCase struct {
+		name     string
+		req      structs.DCSpecificRequest
+		expected string
+	}
+
+	run := func(t *testing.T, tc testCase) {
+		rpc := &fakeNetRPC{}
+		fc := &fakeCache{}
+		store := &fakeViewStore{}
+		c := &Client{
+			NetRPC:              rpc,
+			Cache:               fc,
+			ViewStore:           store,
+			CacheName:           "cache-no-streaming",
+			UseStreamingBackend: true,
+			QueryOptionDefaults: config.ApplyDefaultQueryOptions(&config.RuntimeConfig{}),
+		}
+
+		_, _, err := c.ListServices(context.Background(), tc.req)
+		require.NoError(t, err)
+
+		var calls []string
+		calls = append(calls, rpc.calls...)
+		calls = append(calls, fc.calls...)
+		for range store.calls {
+			calls = append(calls, "streaming")
+		}
+		require.Equal(t, []string{tc.expected}, calls)
+	}
+
+	var testCases = []testCase{
+		{
+			name:     "rpc by default",
+			req:      structs.DCSpecificRequest{Datacenter: "dc1"},
+			expected: "Catalog.ListServices",
+		},
+		{
+			name: "use streaming instead of cache",
+			req: structs.DCSpecificRequest{
+				Datacenter:   "dc1",
+				QueryOptions: structs.QueryOptions{UseCache: true},
+			},
+			expected: "streaming",
+		},
+		{
+			name: "use streaming for MinQueryIndex",
+			req: structs.DCSpecificRequest{
+				Datacenter:      "dc1",
+				NodeMetaFilters: map[string]string{"env": "prod"},
+				QueryOptions:    structs.QueryOptions{MinQueryIndex: 22},
+			},
+			expected: "streaming",
+		},
+		{
+			name: "use cache for filter",
+			req: structs.DCSpecificRequest{
+				Datacenter:   "dc1",
+				QueryOptions: structs.QueryOptions{UseCache: true, Filter: "ServiceName == web"},
+			},
+			expected: "cache-no-streaming",
+		},
+		{
+			name: "rpc for filter",
+			req: structs.DCSpecificRequest{
+				Datacenter:   "dc1",
+				QueryOptions: structs.QueryOptions{MinQueryIndex: 22, Filter: "ServiceName == web"},
+			},
+			expected: "Catalog.ListServices",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func TestClient_ListServices_SetsDefaults(t *testing.T) {
+	store := &fakeViewStore{}
+	c := &Client{
+		ViewStore:           store,
+		CacheName:           "cache-no-streaming",
+		UseStreamingBackend: true,
+		QueryOptionDefaults: config.ApplyDefaultQueryOptions(&config.RuntimeConfig{
+			MaxQueryTime:     200 * time.Second,
+			DefaultQueryTime: 100 * time.Second,
+		}),
+	}
+
+	req := structs.DCSpecificRequest{
+		Datacenter:   "dc1",
+		QueryOptions: structs.QueryOptions{MinQueryIndex: 22},
+	}
+
+	_, _, err := c.ListServices(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Len(t, store.calls, 1)
+	require.Equal(t, 100*time.Second, store.calls[0].CacheInfo().Timeout)
+}
+
+type fakeCache struct {
+	calls []string
+}
+
+func (f *fakeCache) Get(_ context.Context, t string, _ cache.Request) (interface{}, cache.ResultMeta, error) {
+	f.calls = append(f.calls, t)
+	return &structs.IndexedServices{}, cache.ResultMeta{}, nil
+}
+
+type fakeNetRPC struct {
+	calls []string
+}
+
+func (f *fakeNetRPC) RPC(_ context.Context, method string, _ interface{}, _ interface{}) error {
+	f.calls = append(f.calls, method)
+	return nil
+}
+
+type fakeViewStore struct {
+	calls []submatview.Request
+}
+
+func (f *fakeViewStore) Get(_ context.Context, req submatview.Request) (submatview.Result, error) {
+	f.calls = append(f.calls, req)
+	return submatview.Result{Value: &structs.IndexedServices{}}, nil
+}
@@ -0,0 +1,129 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+type MaterializerDeps struct {
+	Conn   *grpc.ClientConn
+	Logger hclog.Logger
+}
+
+// NewMaterializerRequest returns the subscription to the events of every
+// instance of every service, which the list of services is materialized from.
+func NewMaterializerRequest(srvReq structs.DCSpecificRequest) func(index uint64) *pbsubscribe.SubscribeRequest {
+	return func(index uint64) *pbsubscribe.SubscribeRequest {
+		return &pbsubscribe.SubscribeRequest{
+			Topic:      pbsubscribe.Topic_ServiceHealth,
+			Subject:    &pbsubscribe.SubscribeRequest_WildcardSubject{WildcardSubject: true},
+			Token:      srvReq.Token,
+			Datacenter: srvReq.Datacenter,
+			Index:      index,
+		}
+	}
+}
+
+func NewServicesView(req structs.DCSpecificRequest) *ServicesView {
+	return &ServicesView{
+		state:           make(map[string]serviceInstance),
+		entMeta:         req.EnterpriseMeta,
+		nodeMetaFilters: req.NodeMetaFilters,
+	}
+}
+
+// ServicesView implements submatview.View for storing the view state of the
+// catalog list of services. It keeps the name and tags of every instance,
+// keyed by the unique ID of the instance, because the tags of a service are
+// the union of the tags of all its instances.
+type ServicesView struct {
+	state           map[string]serviceInstance
+	entMeta         acl.EnterpriseMeta
+	nodeMetaFilters map[string]string
+}
+
+type serviceInstance struct {
+	name string
+	tags []string
+}
+
+// Update implements View
+func (s *ServicesView) Update(events []*pbsubscribe.Event) error {
+	for _, event := range events {
+		serviceHealth := event.GetServiceHealth()
+		if serviceHealth == nil {
+			return fmt.Errorf("unexpected event type for catalog services view: %T",
+				event.GetPayload())
+		}
+
+		csn := serviceHealth.CheckServiceNode
+		id := csn.UniqueID()
+		switch serviceHealth.Op {
+		case pbsubscribe.CatalogOp_Register:
+			if csn.Node == nil || csn.Service == nil {
+				return fmt.Errorf("check service node was unexpectedly incomplete")
+			}
+			svcEntMeta := acl.NewEnterpriseMetaWithPartition(
+				csn.Service.EnterpriseMeta.GetPartition(),
+				csn.Service.EnterpriseMeta.GetNamespace(),
+			)
+			if csn.Service.PeerName != "" ||
+				!s.entMeta.Matches(&svcEntMeta) ||
+				!structs.SatisfiesMetaFilters(csn.Node.Meta, s.nodeMetaFilters) {
+				delete(s.state, id)
+				continue
+			}
+			s.state[id] = serviceInstance{
+				name: csn.Service.Service,
+				tags: csn.Service.Tags,
+			}
+
+		case pbsubscribe.CatalogOp_Deregister:
+			delete(s.state, id)
+		}
+	}
+	return nil
+}
+
+// Result returns the structs.IndexedServices stored by this view.
+func (s *ServicesView) Result(index uint64) interface{} {
+	unique := make(map[string]map[string]struct{})
+	for _, instance := range s.state {
+		tags, ok := unique[instance.name]
+		if !ok {
+			tags = make(map[string]struct{})
+			unique[instance.name] = tags
+		}
+		for _, tag := range instance.tags {
+			tags[tag] = struct{}{}
+		}
+	}
+
+	result := structs.IndexedServices{
+		Services:       make(structs.Services, len(unique)),
+		EnterpriseMeta: s.entMeta,
+		QueryMeta: structs.QueryMeta{
+			Index:   index,
+			Backend: structs.QueryBackendStreaming,
+		},
+	}
+	for name, tags := range unique {
+		result.Services[name] = make([]string, 0, len(tags))
+		for tag := range tags {
+			result.Services[name] = append(result.Services[name], tag)
+		}
+		sort.Strings(result.Services[name])
+	}
+	return &result
+}
+
+func (s *ServicesView) Reset() {
+	s.state = make(map[string]serviceInstance)
+}
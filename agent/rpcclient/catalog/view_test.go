@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/pbservice"
+	"github.com/hashicorp/consul/proto/pbsubscribe"
+)
+
+func newServiceHealthEvent(op pbsubscribe.CatalogOp, node, svc, peerName string, tags []string, nodeMeta map[string]string) *pbsubscribe.Event {
+	return &pbsubscribe.Event{
+		Payload: &pbsubscribe.Event_ServiceHealth{
+			ServiceHealth: &pbsubscribe.ServiceHealthUpdate{
+				Op: op,
+				CheckServiceNode: &pbservice.CheckServiceNode{
+					Node: &pbservice.Node{
+						Node:     node,
+						PeerName: peerName,
+						Meta:     nodeMeta,
+					},
+					Service: &pbservice.NodeService{
+						ID:       fmt.Sprintf("%s-%s", svc, node),
+						Service:  svc,
+						Tags:     tags,
+						PeerName: peerName,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestServicesView(t *testing.T) {
+	view := NewServicesView(structs.DCSpecificRequest{Datacenter: "dc1"})
+
+	register := pbsubscribe.CatalogOp_Register
+	require.NoError(t, view.Update([]*pbsubscribe.Event{
+		newServiceHealthEvent(register, "node1", "web", "", []string{"v2", "primary"}, nil),
+		newServiceHealthEvent(register, "node2", "web", "", []string{"v1", "primary"}, nil),
+		newServiceHealthEvent(register, "node1", "db", "", nil, nil),
+		// The services imported from a peer aren't listed.
+		newServiceHealthEvent(register, "node1", "api", "peer1", nil, nil),
+	}))
+
+	result := view.Result(10).(*structs.IndexedServices)
+	require.Equal(t, uint64(10), result.Index)
+	require.Equal(t, structs.QueryBackendStreaming, result.Backend)
+	require.Equal(t, structs.Services{
+		"web": {"primary", "v1", "v2"},
+		"db":  {},
+	}, result.Services)
+
+	// The tags of the service are updated once its last instance with a tag
+	// is deregistered or updated.
+	require.NoError(t, view.Update([]*pbsubscribe.Event{
+		newServiceHealthEvent(pbsubscribe.CatalogOp_Deregister, "node2", "web", "", nil, nil),
+		newServiceHealthEvent(register, "node1", "web", "", []string{"primary"}, nil),
+		newServiceHealthEvent(pbsubscribe.CatalogOp_Deregister, "node1", "db", "", nil, nil),
+	}))
+
+	result = view.Result(11).(*structs.IndexedServices)
+	require.Equal(t, structs.Services{
+		"web": {"primary"},
+	}, result.Services)
+
+	view.Reset()
+	result = view.Result(12).(*structs.IndexedServices)
+	require.Empty(t, result.Services)
+
+	err := view.Update([]*pbsubscribe.Event{{Payload: &pbsubscribe.Event_EndOfSnapshot{EndOfSnapshot: true}}})
+	require.Error(t, err)
+}
+
+func TestServicesView_NodeMetaFilters(t *testing.T) {
+	view := NewServicesView(structs.DCSpecificRequest{
+		Datacenter:      "dc1",
+		NodeMetaFilters: map[string]string{"env": "prod"},
+	})
+
+	register := pbsubscribe.CatalogOp_Register
+	require.NoError(t, view.Update([]*pbsubscribe.Event{
+		newServiceHealthEvent(register, "node1", "web", "", nil, map[string]string{"env": "prod"}),
+		newServiceHealthEvent(register, "node2", "db", "", nil, map[string]string{"env": "dev"}),
+		newServiceHealthEvent(register, "node3", "api", "", nil, map[string]string{"env": "prod"}),
+	}))
+	require.Equal(t, structs.Services{
+		"web": {},
+		"api": {},
+	}, view.Result(10).(*structs.IndexedServices).Services)
+
+	// The instance is removed once its node no longer matches.
+	require.NoError(t, view.Update([]*pbsubscribe.Event{
+		newServiceHealthEvent(register, "node3", "api", "", nil, map[string]string{"env": "dev"}),
+	}))
+	require.Equal(t, structs.Services{
+		"web": {},
+	}, view.Result(11).(*structs.IndexedServices).Services)
+}
@@ -3,6 +3,8 @@ package health
 import (
 	"context"
 
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
 	"google.golang.org/grpc/connectivity"
 
 	"github.com/hashicorp/consul/agent/cache"
@@ -11,6 +13,15 @@ import (
 	"github.com/hashicorp/consul/proto/pbsubscribe"
 )
 
+var metricStreamingFallback = []string{"client", "streaming", "fallback"}
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: metricStreamingFallback,
+		Help: "Increments when a service health query is served with blocking queries although the streaming backend is enabled, labeled by reason.",
+	},
+}
+
 // Client provides access to service health data.
 type Client struct {
 	NetRPC              NetRPC
@@ -62,6 +73,9 @@ func (c *Client) ServiceNodes(
 		meta := cache.ResultMeta{Index: result.Index, Hit: result.Cached}
 		return *result.Value.(*structs.IndexedCheckServiceNodes), meta, err
 	}
+	if req.QueryOptions.UseCache || req.QueryOptions.MinQueryIndex > 0 {
+		c.recordStreamingFallback(req)
+	}
 
 	out, md, err := c.getServiceNodes(ctx, req)
 	if err != nil {
@@ -112,11 +126,35 @@ func (c *Client) Notify(
 		return c.ViewStore.NotifyCallback(ctx, sr, correlationID, cb)
 	}
 
+	c.recordStreamingFallback(req)
 	return c.Cache.NotifyCallback(ctx, c.CacheName, &req, correlationID, cb)
 }
 
 func (c *Client) useStreaming(req structs.ServiceSpecificRequest) bool {
-	return c.UseStreamingBackend && !req.Ingress && req.Source.Node == ""
+	return c.UseStreamingBackend && !req.Ingress && req.Source.Node == "" && !req.IsPaginated()
+}
+
+// recordStreamingFallback counts the blocking queries made for a request
+// because the streaming backend doesn't support it. Nothing is recorded when
+// the streaming backend is disabled.
+func (c *Client) recordStreamingFallback(req structs.ServiceSpecificRequest) {
+	if !c.UseStreamingBackend {
+		return
+	}
+	var reason string
+	switch {
+	case req.Ingress:
+		reason = "ingress"
+	case req.Source.Node != "":
+		reason = "source_node"
+	case req.MergeCentralConfig:
+		reason = "merge_central_config"
+	case req.IsPaginated():
+		reason = "pagination"
+	default:
+		return
+	}
+	metrics.IncrCounterWithLabels(metricStreamingFallback, 1, []metrics.Label{{Name: "reason", Value: reason}})
 }
 
 func (c *Client) newServiceRequest(req structs.ServiceSpecificRequest) serviceRequest {
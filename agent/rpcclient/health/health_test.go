@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul/agent/cache"
@@ -271,3 +272,51 @@ func TestClient_ServiceNodes_SetsDefaults(t *testing.T) {
 	require.Len(t, store.calls, 1)
 	require.Equal(t, 100*time.Second, store.calls[0].CacheInfo().Timeout)
 }
+
+func TestClient_StreamingFallbackMetric(t *testing.T) {
+	sink := metrics.NewInmemSink(1*time.Minute, 1*time.Minute)
+	cfg := metrics.DefaultConfig("consul")
+	cfg.EnableHostname = false
+	metrics.NewGlobal(cfg, sink)
+
+	t.Cleanup(func() {
+		sink := &metrics.BlackholeSink{}
+		metrics.NewGlobal(cfg, sink)
+	})
+
+	c := &Client{
+		NetRPC:              &fakeNetRPC{},
+		Cache:               &fakeCache{},
+		ViewStore:           &fakeViewStore{},
+		CacheName:           "cache-no-streaming",
+		UseStreamingBackend: true,
+		QueryOptionDefaults: config.ApplyDefaultQueryOptions(&config.RuntimeConfig{}),
+	}
+
+	serviceNodes := func(req structs.ServiceSpecificRequest) {
+		req.Datacenter = "dc1"
+		req.ServiceName = "web1"
+		_, _, err := c.ServiceNodes(context.Background(), req)
+		require.NoError(t, err)
+	}
+	// The streaming requests and the non-blocking ones aren't counted.
+	serviceNodes(structs.ServiceSpecificRequest{QueryOptions: structs.QueryOptions{UseCache: true}})
+	serviceNodes(structs.ServiceSpecificRequest{Ingress: true})
+
+	serviceNodes(structs.ServiceSpecificRequest{QueryOptions: structs.QueryOptions{UseCache: true}, Ingress: true})
+	serviceNodes(structs.ServiceSpecificRequest{QueryOptions: structs.QueryOptions{MinQueryIndex: 22}, MergeCentralConfig: true})
+	err := c.Notify(context.Background(), structs.ServiceSpecificRequest{
+		Datacenter:  "dc1",
+		ServiceName: "web1",
+		Source:      structs.QuerySource{Node: "node1"},
+	}, "correlation-id", nil)
+	require.NoError(t, err)
+
+	data := sink.Data()
+	require.Len(t, data, 1)
+	counters := data[0].Counters
+	require.Len(t, counters, 3)
+	require.Equal(t, 1, counters["consul.client.streaming.fallback;reason=ingress"].Count)
+	require.Equal(t, 1, counters["consul.client.streaming.fallback;reason=merge_central_config"].Count)
+	require.Equal(t, 1, counters["consul.client.streaming.fallback;reason=source_node"].Count)
+}
@@ -0,0 +1,323 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+// serviceDefinitions registers the services defined in the files of
+// services_auto_reload.dir, and keeps them up to date as the files are added,
+// changed and removed, without a reload of the agent.
+type serviceDefinitions struct {
+	agent   *Agent
+	dir     string
+	logger  hclog.Logger
+	watcher config.Watcher
+
+	// lock protects files. The changes to the registered services are made
+	// while holding the stateLock of the agent.
+	lock  sync.Mutex
+	files map[string]*serviceDefinitionsFile
+}
+
+// serviceDefinitionsFile is the state of a file of the directory.
+type serviceDefinitionsFile struct {
+	modTime  time.Time
+	loadedAt time.Time
+
+	// definitions are the services of the file that are registered. They are
+	// kept when the file becomes invalid, until it is fixed or removed.
+	definitions []*structs.ServiceDefinition
+
+	// services are the IDs of the registered services. Their sidecars are
+	// deregistered along with them.
+	services []structs.ServiceID
+
+	// err is the error of the last attempt to apply the file.
+	err error
+}
+
+// serviceDefinitionsChange is a file that was added, changed or removed since
+// the last scan of the directory.
+type serviceDefinitionsChange struct {
+	modTime     time.Time
+	removed     bool
+	definitions []*structs.ServiceDefinition
+	err         error
+}
+
+func newServiceDefinitions(a *Agent, dir string, debounce time.Duration, logger hclog.Logger) (*serviceDefinitions, error) {
+	logger = logger.Named("service-definitions")
+	w, err := config.NewRateLimitedFileWatcher([]string{dir}, logger, debounce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch the services_auto_reload.dir %q: %w", dir, err)
+	}
+	return &serviceDefinitions{
+		agent:   a,
+		dir:     dir,
+		logger:  logger,
+		watcher: w,
+		files:   make(map[string]*serviceDefinitionsFile),
+	}, nil
+}
+
+// Start watches the directory and applies the changes to its files until the
+// context is canceled or Stop is called.
+func (s *serviceDefinitions) Start(ctx context.Context) {
+	s.watcher.Start(ctx)
+	go func() {
+		for event := range s.watcher.EventsCh() {
+			s.logger.Debug("service definitions changed", "num-events", len(event.Filenames))
+			s.sync()
+		}
+	}()
+}
+
+// Stop stops watching the directory.
+func (s *serviceDefinitions) Stop() {
+	if err := s.watcher.Stop(); err != nil {
+		s.logger.Warn("failed to stop watching the service definitions", "error", err)
+	}
+}
+
+// sync applies the changes to the files of the directory.
+func (s *serviceDefinitions) sync() {
+	a := s.agent
+	a.stateLock.Lock()
+	defer a.stateLock.Unlock()
+
+	changes := s.scan()
+	if len(changes) == 0 {
+		return
+	}
+
+	persistedServiceConfigs, err := a.readPersistedServiceConfigs()
+	if err != nil {
+		s.logger.Error("failed to read the persisted service configs", "error", err)
+		return
+	}
+	s.applyLocked(changes, persistedServiceConfigs, nil)
+}
+
+// loadLocked registers the services of all the files, after the services of
+// the agent were unloaded by a reload or at startup. It must be called while
+// holding the stateLock of the agent.
+func (s *serviceDefinitions) loadLocked(persistedServiceConfigs map[structs.ServiceID]*structs.ServiceConfigResponse, snap map[structs.CheckID]*structs.HealthCheck) {
+	changes := s.scan()
+
+	s.lock.Lock()
+	unchanged := make(map[string]*serviceDefinitionsFile)
+	for path, file := range s.files {
+		if _, ok := changes[path]; !ok {
+			unchanged[path] = file
+		}
+	}
+	s.lock.Unlock()
+
+	for path, file := range unchanged {
+		changes[path] = &serviceDefinitionsChange{
+			modTime:     file.modTime,
+			definitions: file.definitions,
+			err:         file.err,
+		}
+	}
+	s.applyLocked(changes, persistedServiceConfigs, snap)
+}
+
+// scan returns the files of the directory that were added, changed or
+// removed since they were last applied, parsing the added and changed ones.
+func (s *serviceDefinitions) scan() map[string]*serviceDefinitionsChange {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		s.logger.Error("failed to read the service definitions directory", "dir", s.dir, "error", err)
+		return nil
+	}
+
+	s.lock.Lock()
+	known := make(map[string]time.Time, len(s.files))
+	for path, file := range s.files {
+		known[path] = file.modTime
+	}
+	s.lock.Unlock()
+
+	changes := make(map[string]*serviceDefinitionsChange)
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".hcl") || strings.HasSuffix(name, ".json")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// The file was removed since the directory was read.
+			continue
+		}
+
+		path := filepath.Join(s.dir, name)
+		seen[path] = struct{}{}
+		if modTime, ok := known[path]; ok && modTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		definitions, err := parseServiceDefinitionsFile(path)
+		changes[path] = &serviceDefinitionsChange{
+			modTime:     info.ModTime(),
+			definitions: definitions,
+			err:         err,
+		}
+	}
+	for path := range known {
+		if _, ok := seen[path]; !ok {
+			changes[path] = &serviceDefinitionsChange{removed: true}
+		}
+	}
+	return changes
+}
+
+// parseServiceDefinitionsFile returns the services defined in a file, using
+// the same format and validation as the agent configuration.
+func parseServiceDefinitionsFile(path string) ([]*structs.ServiceDefinition, error) {
+	// Service definition files aren't complete agent configurations, so they
+	// are loaded on top of the dev mode defaults.
+	devMode := true
+	result, err := config.Load(config.LoadOpts{
+		ConfigFiles: []string{path},
+		DevMode:     &devMode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.RuntimeConfig.Services) == 0 {
+		return nil, errors.New("the file does not define any service")
+	}
+	return result.RuntimeConfig.Services, nil
+}
+
+// applyLocked registers and deregisters the services of the changed files.
+// It must be called while holding the stateLock of the agent.
+func (s *serviceDefinitions) applyLocked(changes map[string]*serviceDefinitionsChange, persistedServiceConfigs map[structs.ServiceID]*structs.ServiceConfigResponse, snap map[structs.CheckID]*structs.HealthCheck) {
+	a := s.agent
+	now := time.Now()
+
+	// Apply the files in order, so the last definition of a service defined
+	// in several files is stable.
+	paths := make([]string, 0, len(changes))
+	for path := range changes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		change := changes[path]
+
+		s.lock.Lock()
+		file, ok := s.files[path]
+		s.lock.Unlock()
+		if !ok {
+			file = &serviceDefinitionsFile{}
+		}
+
+		if change.removed {
+			for _, sid := range file.services {
+				if err := a.removeServiceLocked(sid, false); err != nil {
+					s.logger.Warn("failed to deregister service", "file", path, "service", sid.String(), "error", err)
+				}
+			}
+			s.logger.Info("removed service definitions", "file", path, "services", len(file.services))
+
+			s.lock.Lock()
+			delete(s.files, path)
+			s.lock.Unlock()
+			continue
+		}
+
+		updated := &serviceDefinitionsFile{
+			modTime:  change.modTime,
+			loadedAt: now,
+		}
+
+		// Keep the services of an invalid file registered as they were, and
+		// report the error until the file is fixed.
+		definitions := change.definitions
+		if change.err != nil {
+			definitions = file.definitions
+			updated.err = change.err
+		}
+
+		var errs error
+		registered := make(map[structs.ServiceID]struct{})
+		for _, definition := range definitions {
+			if err := a.addServiceDefinitionLocked(definition, persistedServiceConfigs, snap); err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			sid := definition.NodeService().CompoundServiceID()
+			registered[sid] = struct{}{}
+			updated.definitions = append(updated.definitions, definition)
+			updated.services = append(updated.services, sid)
+		}
+		if change.err == nil {
+			updated.err = errs
+		}
+
+		// Deregister the services that were removed from the file.
+		for _, sid := range file.services {
+			if _, ok := registered[sid]; ok {
+				continue
+			}
+			if err := a.removeServiceLocked(sid, false); err != nil {
+				s.logger.Warn("failed to deregister service", "file", path, "service", sid.String(), "error", err)
+			}
+		}
+
+		if updated.err != nil {
+			s.logger.Error("invalid service definitions", "file", path, "error", updated.err)
+		} else {
+			s.logger.Info("applied service definitions", "file", path, "services", len(updated.services))
+		}
+
+		s.lock.Lock()
+		s.files[path] = updated
+		s.lock.Unlock()
+	}
+}
+
+// Files returns the state of the files of the directory, sorted by path.
+func (s *serviceDefinitions) Files() []api.AgentServiceDefinitionsFile {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	files := make([]api.AgentServiceDefinitionsFile, 0, len(s.files))
+	for path, file := range s.files {
+		f := api.AgentServiceDefinitionsFile{
+			File:     path,
+			Services: make([]string, 0, len(file.services)),
+			LoadedAt: file.loadedAt,
+		}
+		for _, sid := range file.services {
+			f.Services = append(f.Services, sid.ID)
+		}
+		if file.err != nil {
+			f.Error = file.err.Error()
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].File < files[j].File
+	})
+	return files
+}
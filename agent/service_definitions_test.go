@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestAgent_ServicesAutoReload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(t *testing.T, name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	writeFile(t, "web.hcl", `service { name = "web" port = 8080 }`)
+
+	a := NewTestAgent(t, fmt.Sprintf(`
+		services_auto_reload {
+			dir = %q
+			debounce = "50ms"
+		}
+	`, dir))
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	webID := structs.NewServiceID("web", nil)
+	require.NotNil(t, a.State.Service(webID))
+
+	// The services of a new file are registered.
+	writeFile(t, "db.json", `{"services": [{"name": "db", "port": 5432}, {"name": "cache", "port": 6379}]}`)
+	retry.Run(t, func(r *retry.R) {
+		require.NotNil(r, a.State.Service(structs.NewServiceID("db", nil)))
+		require.NotNil(r, a.State.Service(structs.NewServiceID("cache", nil)))
+	})
+
+	// The services removed from a file are deregistered.
+	writeFile(t, "db.json", `{"services": [{"name": "db", "port": 5433}]}`)
+	retry.Run(t, func(r *retry.R) {
+		require.Nil(r, a.State.Service(structs.NewServiceID("cache", nil)))
+		svc := a.State.Service(structs.NewServiceID("db", nil))
+		require.NotNil(r, svc)
+		require.Equal(r, 5433, svc.Port)
+	})
+
+	// An invalid file reports an error and keeps its services registered.
+	writeFile(t, "web.hcl", `service { name = "web" port = "not a port" }`)
+	retry.Run(t, func(r *retry.R) {
+		files := a.serviceDefinitions.Files()
+		require.Len(r, files, 2)
+		require.Equal(r, filepath.Join(dir, "web.hcl"), files[1].File)
+		require.NotEmpty(r, files[1].Error)
+		require.Equal(r, []string{"web"}, files[1].Services)
+	})
+	require.NotNil(t, a.State.Service(webID))
+
+	// The services of a removed file are deregistered.
+	require.NoError(t, os.Remove(filepath.Join(dir, "web.hcl")))
+	retry.Run(t, func(r *retry.R) {
+		require.Nil(r, a.State.Service(webID))
+		require.Len(r, a.serviceDefinitions.Files(), 1)
+	})
+
+	// The services are registered again after a reload.
+	require.NoError(t, a.reloadConfigInternal(a.Config))
+	require.NotNil(t, a.State.Service(structs.NewServiceID("db", nil)))
+
+	t.Run("HTTP", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/service-definitions", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.AgentServiceDefinitions(resp, req)
+		require.NoError(t, err)
+
+		files := obj.([]api.AgentServiceDefinitionsFile)
+		require.Len(t, files, 1)
+		require.Equal(t, filepath.Join(dir, "db.json"), files[0].File)
+		require.Equal(t, []string{"db"}, files[0].Services)
+		require.Empty(t, files[0].Error)
+	})
+}
+
+func TestAgent_ServiceDefinitions_Disabled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	req, _ := http.NewRequest("GET", "/v1/agent/service-definitions", nil)
+	resp := httptest.NewRecorder()
+	_, err := a.srv.AgentServiceDefinitions(resp, req)
+	require.Error(t, err)
+	httpErr, ok := err.(HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// minServiceLeaseTTL is the minimum TTL of the lease of a service, so that
+// the leases aren't renewed too often.
+const minServiceLeaseTTL = time.Second
+
+// serviceLeases tracks the services registered with a lease. A service is
+// deregistered once its lease expires, unless the lease is renewed before its
+// TTL elapses, so that the services of batch jobs and spot instances that die
+// without deregistering don't linger in the catalog.
+type serviceLeases struct {
+	// expire is called once the lease of a service expired.
+	expire func(structs.ServiceID)
+
+	lock    sync.Mutex
+	leases  map[structs.ServiceID]*serviceLease
+	stopped bool
+}
+
+type serviceLease struct {
+	ttl       time.Duration
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+func newServiceLeases(expire func(structs.ServiceID)) *serviceLeases {
+	return &serviceLeases{
+		expire: expire,
+		leases: make(map[structs.ServiceID]*serviceLease),
+	}
+}
+
+// set starts the lease of a service, replacing its current lease if any.
+func (l *serviceLeases) set(sid structs.ServiceID, ttl time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.stopped {
+		return
+	}
+	l.removeLocked(sid)
+
+	lease := &serviceLease{ttl: ttl, expiresAt: time.Now().Add(ttl)}
+	lease.timer = time.AfterFunc(ttl, func() { l.expired(sid, lease) })
+	l.leases[sid] = lease
+}
+
+// renew renews the lease of a service and returns its TTL, or false if the
+// service has no lease.
+func (l *serviceLeases) renew(sid structs.ServiceID) (time.Duration, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lease, ok := l.leases[sid]
+	if !ok {
+		return 0, false
+	}
+	lease.expiresAt = time.Now().Add(lease.ttl)
+	lease.timer.Reset(lease.ttl)
+	return lease.ttl, true
+}
+
+// ttl returns the TTL of the lease of a service, or 0 if it has no lease.
+func (l *serviceLeases) ttl(sid structs.ServiceID) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if lease, ok := l.leases[sid]; ok {
+		return lease.ttl
+	}
+	return 0
+}
+
+// remove drops the lease of a service, if any.
+func (l *serviceLeases) remove(sid structs.ServiceID) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.removeLocked(sid)
+}
+
+func (l *serviceLeases) removeLocked(sid structs.ServiceID) {
+	if lease, ok := l.leases[sid]; ok {
+		lease.timer.Stop()
+		delete(l.leases, sid)
+	}
+}
+
+// stop drops all the leases without expiring them. The leases of the
+// persisted services start again when they are restored.
+func (l *serviceLeases) stop() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for sid := range l.leases {
+		l.removeLocked(sid)
+	}
+	l.stopped = true
+}
+
+func (l *serviceLeases) expired(sid structs.ServiceID, lease *serviceLease) {
+	l.lock.Lock()
+	// The lease may have been renewed or replaced while the timer fired.
+	if l.stopped || l.leases[sid] != lease || time.Now().Before(lease.expiresAt) {
+		l.lock.Unlock()
+		return
+	}
+	delete(l.leases, sid)
+	l.lock.Unlock()
+
+	l.expire(sid)
+}
+
+// expireServiceLease deregisters a service whose lease expired.
+func (a *Agent) expireServiceLease(sid structs.ServiceID) {
+	a.logger.Info("service lease expired, deregistering service", "service", sid.String())
+	if err := a.RemoveService(sid); err != nil {
+		a.logger.Error("failed to deregister service with an expired lease",
+			"service", sid.String(),
+			"error", err,
+		)
+	}
+}
+
+// RenewServiceLease renews the lease of a service registered with one and
+// returns its TTL, or false if the service has no lease. The token must have
+// service:write on the service.
+func (a *Agent) RenewServiceLease(sid structs.ServiceID, token string) (time.Duration, bool, error) {
+	authz, err := a.delegate.ResolveTokenAndDefaultMeta(token, &sid.EnterpriseMeta, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	sid.Normalize()
+
+	if a.State.Service(sid) == nil {
+		return 0, false, nil
+	}
+	if err := a.vetServiceUpdateWithAuthorizer(authz, sid); err != nil {
+		return 0, false, err
+	}
+
+	ttl, ok := a.serviceLeases.renew(sid)
+	return ttl, ok, nil
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestServiceLeases(t *testing.T) {
+	expired := make(chan structs.ServiceID, 2)
+	leases := newServiceLeases(func(sid structs.ServiceID) { expired <- sid })
+
+	web := structs.NewServiceID("web", nil)
+	batch := structs.NewServiceID("batch", nil)
+	leases.set(web, 400*time.Millisecond)
+	leases.set(batch, 400*time.Millisecond)
+	require.Equal(t, 400*time.Millisecond, leases.ttl(web))
+
+	// The renewed lease outlives the other one.
+	time.Sleep(200 * time.Millisecond)
+	ttl, ok := leases.renew(web)
+	require.True(t, ok)
+	require.Equal(t, 400*time.Millisecond, ttl)
+
+	select {
+	case sid := <-expired:
+		require.Equal(t, batch, sid)
+	case <-time.After(time.Second):
+		t.Fatal("the lease didn't expire")
+	}
+	_, ok = leases.renew(batch)
+	require.False(t, ok)
+
+	select {
+	case sid := <-expired:
+		require.Equal(t, web, sid)
+	case <-time.After(time.Second):
+		t.Fatal("the lease didn't expire")
+	}
+	require.Zero(t, leases.ttl(web))
+
+	// The removed leases don't expire.
+	leases.set(web, 100*time.Millisecond)
+	leases.remove(web)
+	leases.set(batch, 100*time.Millisecond)
+	leases.stop()
+	select {
+	case sid := <-expired:
+		t.Fatalf("the lease of %s expired", sid)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
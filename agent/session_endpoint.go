@@ -1,13 +1,18 @@
 package agent
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	grpcSession "github.com/hashicorp/consul/agent/grpc-external/services/session"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/logging"
+	"github.com/hashicorp/consul/proto-public/pbsession"
 	"github.com/hashicorp/consul/types"
 )
 
@@ -113,6 +118,76 @@ func (s *HTTPHandlers) SessionRenew(resp http.ResponseWriter, req *http.Request)
 	return out.Sessions, nil
 }
 
+// sessionKeepAliveRequest lists the sessions to keep alive
+type sessionKeepAliveRequest struct {
+	SessionIDs []string
+}
+
+// SessionKeepAlive renews the sessions in the request for as long as the
+// request is open, in the same way as the gRPC KeepAlive stream. An
+// api.SessionKeepAliveEvent is streamed as a line of JSON every time one of
+// them is renewed or invalidated, and the response ends once all of them are
+// invalidated.
+func (s *HTTPHandlers) SessionKeepAlive(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.SessionSpecificRequest{}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	var body sessionKeepAliveRequest
+	if err := decodeBody(req.Body, &body); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if len(body.SessionIDs) == 0 {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing session IDs"}
+	}
+
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("Streaming not supported")
+	}
+
+	keepAlive := grpcSession.NewServer(grpcSession.Config{
+		Backend:    s.agent,
+		Logger:     s.agent.logger.Named(logging.Session),
+		Datacenter: args.Datacenter,
+	})
+
+	// The response is only started with the first event, so the errors
+	// which happen before, like ACL errors, are returned as usual.
+	var started bool
+	enc := json.NewEncoder(resp)
+	err := keepAlive.KeepAliveSessions(req.Context(), args.Token, body.SessionIDs, args.EnterpriseMeta, func(rsp *pbsession.KeepAliveResponse) error {
+		event := api.SessionKeepAliveEvent{
+			SessionID: rsp.SessionId,
+			Event:     api.SessionKeepAliveRenewed,
+		}
+		if rsp.Event == pbsession.KeepAliveEvent_KEEP_ALIVE_EVENT_INVALIDATED {
+			event.Event = api.SessionKeepAliveInvalidated
+		}
+		if rsp.Ttl != nil {
+			event.TTL = rsp.Ttl.AsDuration().String()
+		}
+
+		started = true
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		return nil, err
+	}
+	if err != nil {
+		s.agent.logger.Error("Failed to keep sessions alive", "error", err)
+	}
+	return nil, nil
+}
+
 // SessionGet is used to get info for a particular session
 func (s *HTTPHandlers) SessionGet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	args := structs.SessionSpecificRequest{}
@@ -471,6 +471,54 @@ func TestSessionDestroy(t *testing.T) {
 	}
 }
 
+func TestSessionKeepAlive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	t.Run("missing session IDs", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/v1/session/keepalive", jsonReader(sessionKeepAliveRequest{}))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.SessionKeepAlive(resp, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Missing session IDs")
+	})
+
+	t.Run("renewed until invalidated", func(t *testing.T) {
+		id := makeTestSessionTTL(t, a.srv, "10s")
+
+		// Destroy the session once it has been renewed.
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			req, _ := http.NewRequest("PUT", "/v1/session/destroy/"+id, nil)
+			a.srv.SessionDestroy(httptest.NewRecorder(), req)
+		}()
+
+		req, _ := http.NewRequest("PUT", "/v1/session/keepalive", jsonReader(sessionKeepAliveRequest{SessionIDs: []string{id}}))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.SessionKeepAlive(resp, req)
+		require.NoError(t, err)
+		require.Nil(t, obj)
+
+		dec := json.NewDecoder(resp.Body)
+		var events []api.SessionKeepAliveEvent
+		for dec.More() {
+			var event api.SessionKeepAliveEvent
+			require.NoError(t, dec.Decode(&event))
+			events = append(events, event)
+		}
+		require.Equal(t, []api.SessionKeepAliveEvent{
+			{SessionID: id, Event: api.SessionKeepAliveRenewed, TTL: "10s"},
+			{SessionID: id, Event: api.SessionKeepAliveInvalidated},
+		}, events)
+	})
+}
+
 func TestSessionCustomTTL(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
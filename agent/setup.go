@@ -13,14 +13,18 @@ import (
 
 	autoconf "github.com/hashicorp/consul/agent/auto-config"
 	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
+	"github.com/hashicorp/consul/agent/consul/changesink"
 	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/consul/rate"
 	"github.com/hashicorp/consul/agent/consul/stream"
 	"github.com/hashicorp/consul/agent/consul/usagemetrics"
 	"github.com/hashicorp/consul/agent/consul/xdscapacity"
 	"github.com/hashicorp/consul/agent/grpc-external/limiter"
+	"github.com/hashicorp/consul/agent/grpc-external/services/dataplane"
+	"github.com/hashicorp/consul/agent/grpc-external/services/peerstream"
 	grpcInt "github.com/hashicorp/consul/agent/grpc-internal"
 	"github.com/hashicorp/consul/agent/grpc-internal/balancer"
 	"github.com/hashicorp/consul/agent/grpc-internal/resolver"
@@ -30,6 +34,7 @@ import (
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/router"
 	"github.com/hashicorp/consul/agent/rpc/middleware"
+	"github.com/hashicorp/consul/agent/rpcclient/health"
 	"github.com/hashicorp/consul/agent/submatview"
 	"github.com/hashicorp/consul/agent/token"
 	"github.com/hashicorp/consul/agent/xds"
@@ -251,7 +256,9 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 	var gauges = [][]prometheus.GaugeDefinition{
 		cache.Gauges,
 		consul.RPCGauges,
+		middleware.BlockingQueryGauges,
 		consul.SessionGauges,
+		consul.LockGauges,
 		grpcWare.StatsGauges,
 		xds.StatsGauges,
 		usagemetrics.Gauges,
@@ -260,6 +267,8 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		Gauges,
 		raftGauges,
 		serverGauges,
+		consul.LogStoreCanaryGauges,
+		consul.GossipProfileGauges,
 	}
 
 	// TODO(ffmmm): conditionally add only leader specific metrics to gauges, counters, summaries, etc
@@ -269,6 +278,7 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 			consul.LeaderCertExpirationGauges,
 			consul.LeaderPeeringMetrics,
 			xdscapacity.StatsGauges,
+			stream.Gauges,
 		)
 	}
 
@@ -306,15 +316,26 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 	var counters = [][]prometheus.CounterDefinition{
 		CatalogCounters,
 		cache.Counters,
+		checks.CheckCounters,
 		consul.ACLCounters,
 		consul.CatalogCounters,
 		consul.ClientCounters,
+		consul.IntentionCounters,
+		consul.KeyringRotationCounters,
+		consul.KVCounters,
+		consul.LockCounters,
+		consul.LogStoreCanaryCounters,
 		consul.RPCCounters,
 		grpcWare.StatsCounters,
 		local.StateCounters,
 		xds.StatsCounters,
 		raftCounters,
 		rate.Counters,
+		peerstream.Counters,
+		dataplane.Counters,
+		stream.Counters,
+		health.Counters,
+		changesink.Counters,
 	}
 	// Flatten definitions
 	// NOTE(kit): Do we actually want to create a set here so we can ensure definition names are unique?
@@ -358,9 +379,11 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		consul.FederationStateSummaries,
 		consul.IntentionSummaries,
 		consul.KVSummaries,
+		consul.LockSummaries,
 		consul.LeaderSummaries,
 		consul.PreparedQuerySummaries,
 		consul.RPCSummaries,
+		middleware.BlockingQuerySummaries,
 		consul.SegmentOSSSummaries,
 		consul.SessionSummaries,
 		consul.SessionEndpointSummaries,
@@ -368,6 +391,7 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		fsm.CommandsSummaries,
 		fsm.SnapshotSummaries,
 		raftSummaries,
+		stream.Summaries,
 		xds.StatsSummaries,
 	}
 	// Flatten definitions
@@ -121,6 +121,9 @@ func sidecarServiceFromNodeService(ns *structs.NodeService, token string) (*stru
 			}
 		}
 	}
+	if len(sidecar.Proxy.LocalServicePorts) == 0 && sidecar.Proxy.LocalServiceSocketPath == "" {
+		sidecar.Proxy.LocalServicePorts = ns.Ports
+	}
 
 	// Setup checks
 	checks, err := ns.Connect.SidecarService.CheckTypes()
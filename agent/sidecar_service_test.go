@@ -160,6 +160,34 @@ func TestAgent_sidecarServiceFromNodeService(t *testing.T) {
 			},
 			wantChecks: nil,
 		},
+		{
+			name: "inherit named ports",
+			sd: &structs.ServiceDefinition{
+				ID:    "web1",
+				Name:  "web",
+				Port:  1111,
+				Ports: structs.ServicePorts{{Name: "admin", Port: 2222}},
+				Connect: &structs.ServiceConnect{
+					SidecarService: &structs.ServiceDefinition{},
+				},
+			},
+			wantNS: &structs.NodeService{
+				EnterpriseMeta:             *structs.DefaultEnterpriseMetaInDefaultPartition(),
+				Kind:                       structs.ServiceKindConnectProxy,
+				ID:                         "web1-sidecar-proxy",
+				Service:                    "web-sidecar-proxy",
+				Port:                       0,
+				LocallyRegisteredAsSidecar: true,
+				Proxy: structs.ConnectProxyConfig{
+					DestinationServiceName: "web",
+					DestinationServiceID:   "web1",
+					LocalServiceAddress:    "127.0.0.1",
+					LocalServicePort:       1111,
+					LocalServicePorts:      structs.ServicePorts{{Name: "admin", Port: 2222}},
+				},
+			},
+			wantChecks: nil,
+		},
 		{
 			name: "invalid check type",
 			sd: &structs.ServiceDefinition{
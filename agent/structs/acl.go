@@ -96,6 +96,7 @@ type ACLIdentity interface {
 	RoleIDs() []string
 	ServiceIdentityList() []*ACLServiceIdentity
 	NodeIdentityList() []*ACLNodeIdentity
+	TemplatedPolicyList() []*ACLTemplatedPolicy
 	IsExpired(asOf time.Time) bool
 	IsLocal() bool
 	EnterpriseMetadata() *acl.EnterpriseMeta
@@ -123,6 +124,16 @@ type ACLServiceIdentity struct {
 	//
 	// Only valid for global tokens. It is an error to specify this for local tokens.
 	Datacenters []string `json:",omitempty"`
+
+	// Node restricts the registration of the service, and of its sidecar
+	// proxy, to the node with this name. It is mutually exclusive with
+	// NodePrefix, and the service can be registered on any node if both are
+	// empty.
+	Node string `json:",omitempty"`
+
+	// NodePrefix restricts the registration of the service, and of its
+	// sidecar proxy, to the nodes with names starting with this prefix.
+	NodePrefix string `json:",omitempty"`
 }
 
 func (s *ACLServiceIdentity) Clone() *ACLServiceIdentity {
@@ -136,16 +147,46 @@ func (s *ACLServiceIdentity) AddToHash(h hash.Hash) {
 	for _, dc := range s.Datacenters {
 		h.Write([]byte(dc))
 	}
+	if s.Node != "" {
+		h.Write([]byte("node:" + s.Node))
+	}
+	if s.NodePrefix != "" {
+		h.Write([]byte("node_prefix:" + s.NodePrefix))
+	}
 }
 
 func (s *ACLServiceIdentity) EstimateSize() int {
-	size := len(s.ServiceName)
+	size := len(s.ServiceName) + len(s.Node) + len(s.NodePrefix)
 	for _, dc := range s.Datacenters {
 		size += len(dc)
 	}
 	return size
 }
 
+// IsNodeScoped returns whether the registration of the service is restricted
+// to some nodes.
+func (s *ACLServiceIdentity) IsNodeScoped() bool {
+	return s.Node != "" || s.NodePrefix != ""
+}
+
+// AllowsNode returns whether the service can be registered on the node.
+func (s *ACLServiceIdentity) AllowsNode(node string) bool {
+	switch {
+	case s.Node != "":
+		return strings.EqualFold(s.Node, node)
+	case s.NodePrefix != "":
+		return strings.HasPrefix(strings.ToLower(node), strings.ToLower(s.NodePrefix))
+	default:
+		return true
+	}
+}
+
+// grantsService returns whether the synthetic policy of the identity grants
+// write access to the service.
+func (s *ACLServiceIdentity) grantsService(service string) bool {
+	return service == s.ServiceName || service == s.ServiceName+"-sidecar-proxy"
+}
+
 func (s *ACLServiceIdentity) SyntheticPolicy(entMeta *acl.EnterpriseMeta) *ACLPolicy {
 	// Given that we validate this string name before persisting, we do not
 	// have to escape it before doing the following interpolation.
@@ -169,13 +210,17 @@ func (s *ACLServiceIdentity) SyntheticPolicy(entMeta *acl.EnterpriseMeta) *ACLPo
 type ACLServiceIdentities []*ACLServiceIdentity
 
 // Deduplicate returns a new list of service identities without duplicates.
-// Identities with the same ServiceName but different datacenters will be
-// merged into a single identity with all datacenters.
+// Identities with the same ServiceName and node scope but different
+// datacenters will be merged into a single identity with all datacenters.
 func (ids ACLServiceIdentities) Deduplicate() ACLServiceIdentities {
-	unique := make(map[string]*ACLServiceIdentity)
+	type key struct {
+		serviceName, node, nodePrefix string
+	}
+	unique := make(map[key]*ACLServiceIdentity)
 
 	for _, id := range ids {
-		entry, ok := unique[id.ServiceName]
+		k := key{id.ServiceName, id.Node, id.NodePrefix}
+		entry, ok := unique[k]
 		if ok {
 			dcs := stringslice.CloneStringSlice(id.Datacenters)
 			sort.Strings(dcs)
@@ -183,7 +228,7 @@ func (ids ACLServiceIdentities) Deduplicate() ACLServiceIdentities {
 		} else {
 			entry = id.Clone()
 			sort.Strings(entry.Datacenters)
-			unique[id.ServiceName] = entry
+			unique[k] = entry
 		}
 	}
 
@@ -194,6 +239,24 @@ func (ids ACLServiceIdentities) Deduplicate() ACLServiceIdentities {
 	return results
 }
 
+// AllowsServiceOnNode returns whether the service identities allow the
+// service to be registered on the node. The service can be registered
+// anywhere unless all the identities granting it are node-scoped, in which
+// case one of them must allow the node.
+func (ids ACLServiceIdentities) AllowsServiceOnNode(service, node string) bool {
+	allowed := true
+	for _, id := range ids {
+		if !id.grantsService(service) {
+			continue
+		}
+		if !id.IsNodeScoped() || id.AllowsNode(node) {
+			return true
+		}
+		allowed = false
+	}
+	return allowed
+}
+
 // ACLNodeIdentity represents a high-level grant of all privileges
 // necessary to assume the identity of that node and manage it.
 type ACLNodeIdentity struct {
@@ -284,6 +347,9 @@ type ACLToken struct {
 	// The node identities that this token should be allowed to manage.
 	NodeIdentities ACLNodeIdentities `json:",omitempty"`
 
+	// List of templated policies to generate synthetic policies for.
+	TemplatedPolicies ACLTemplatedPolicies `json:",omitempty"`
+
 	// Whether this token is DC local. This means that it will not be synced
 	// to the ACL datacenter and replicated to others.
 	Local bool
@@ -314,6 +380,18 @@ type ACLToken struct {
 	// The time when this token was created
 	CreateTime time.Time `json:",omitempty"`
 
+	// ReplacedBy is the AccessorID of the token issued to replace this one
+	// when it was rotated. A token can only be rotated once, its replacement
+	// must be rotated instead.
+	ReplacedBy string `json:",omitempty"`
+
+	// LastUsedTime and LastUsedAddr are the last time the token was presented
+	// to an agent of the datacenter and the address of the client that
+	// presented it. They are filled in from the token usage table when the
+	// token is read and are never persisted with the token.
+	LastUsedTime *time.Time `json:",omitempty"`
+	LastUsedAddr string     `json:",omitempty"`
+
 	// Hash of the contents of the token
 	//
 	// This is needed mainly for replication purposes. When replicating from
@@ -364,6 +442,7 @@ func (t *ACLToken) Clone() *ACLToken {
 	t2.Roles = nil
 	t2.ServiceIdentities = nil
 	t2.NodeIdentities = nil
+	t2.TemplatedPolicies = nil
 
 	if len(t.Policies) > 0 {
 		t2.Policies = make([]ACLTokenPolicyLink, len(t.Policies))
@@ -385,6 +464,12 @@ func (t *ACLToken) Clone() *ACLToken {
 			t2.NodeIdentities[i] = n.Clone()
 		}
 	}
+	if len(t.TemplatedPolicies) > 0 {
+		t2.TemplatedPolicies = make([]*ACLTemplatedPolicy, len(t.TemplatedPolicies))
+		for i, tp := range t.TemplatedPolicies {
+			t2.TemplatedPolicies[i] = tp.Clone()
+		}
+	}
 
 	return &t2
 }
@@ -433,6 +518,18 @@ func (t *ACLToken) ServiceIdentityList() []*ACLServiceIdentity {
 	return out
 }
 
+func (t *ACLToken) TemplatedPolicyList() []*ACLTemplatedPolicy {
+	if len(t.TemplatedPolicies) == 0 {
+		return nil
+	}
+
+	out := make([]*ACLTemplatedPolicy, 0, len(t.TemplatedPolicies))
+	for _, tp := range t.TemplatedPolicies {
+		out = append(out, tp.Clone())
+	}
+	return out
+}
+
 func (t *ACLToken) IsExpired(asOf time.Time) bool {
 	if asOf.IsZero() || !t.HasExpirationTime() {
 		return false
@@ -493,8 +590,18 @@ func (t *ACLToken) SetHash(force bool) []byte {
 			nodeID.AddToHash(hash)
 		}
 
+		for _, tp := range t.TemplatedPolicies {
+			tp.AddToHash(hash)
+		}
+
 		t.EnterpriseMeta.AddToHash(hash, false)
 
+		// The expiration of a token is shortened when it is rotated, which
+		// must be replicated too.
+		if t.ReplacedBy != "" {
+			hash.Write([]byte(t.ReplacedBy))
+		}
+
 		// Finalize the hash
 		hashVal := hash.Sum(nil)
 
@@ -506,7 +613,7 @@ func (t *ACLToken) SetHash(force bool) []byte {
 
 func (t *ACLToken) EstimateSize() int {
 	// 41 = 16 (RaftIndex) + 8 (Hash) + 8 (ExpirationTime) + 8 (CreateTime) + 1 (Local)
-	size := 41 + len(t.AccessorID) + len(t.SecretID) + len(t.Description) + len(t.AuthMethod)
+	size := 41 + len(t.AccessorID) + len(t.SecretID) + len(t.Description) + len(t.AuthMethod) + len(t.ReplacedBy)
 	for _, link := range t.Policies {
 		size += len(link.ID) + len(link.Name)
 	}
@@ -519,6 +626,9 @@ func (t *ACLToken) EstimateSize() int {
 	for _, nodeID := range t.NodeIdentities {
 		size += nodeID.EstimateSize()
 	}
+	for _, tp := range t.TemplatedPolicies {
+		size += tp.EstimateSize()
+	}
 	return size + t.EnterpriseMeta.EstimateSize()
 }
 
@@ -533,10 +643,13 @@ type ACLTokenListStub struct {
 	Roles             []ACLTokenRoleLink   `json:",omitempty"`
 	ServiceIdentities ACLServiceIdentities `json:",omitempty"`
 	NodeIdentities    ACLNodeIdentities    `json:",omitempty"`
+	TemplatedPolicies ACLTemplatedPolicies `json:",omitempty"`
 	Local             bool
 	AuthMethod        string     `json:",omitempty"`
 	ExpirationTime    *time.Time `json:",omitempty"`
 	CreateTime        time.Time  `json:",omitempty"`
+	LastUsedTime      *time.Time `json:",omitempty"`
+	LastUsedAddr      string     `json:",omitempty"`
 	Hash              []byte
 	CreateIndex       uint64
 	ModifyIndex       uint64
@@ -555,6 +668,7 @@ func (token *ACLToken) Stub() *ACLTokenListStub {
 		Roles:                       token.Roles,
 		ServiceIdentities:           token.ServiceIdentities,
 		NodeIdentities:              token.NodeIdentities,
+		TemplatedPolicies:           token.TemplatedPolicies,
 		Local:                       token.Local,
 		AuthMethod:                  token.AuthMethod,
 		ExpirationTime:              token.ExpirationTime,
@@ -840,6 +954,9 @@ type ACLRole struct {
 	// List of nodes to generate synthetic policies for.
 	NodeIdentities ACLNodeIdentities `json:",omitempty"`
 
+	// List of templated policies to generate synthetic policies for.
+	TemplatedPolicies ACLTemplatedPolicies `json:",omitempty"`
+
 	// Hash of the contents of the role
 	// This does not take into account the ID (which is immutable)
 	// nor the raft metadata.
@@ -879,6 +996,7 @@ func (r *ACLRole) Clone() *ACLRole {
 	r2.Policies = nil
 	r2.ServiceIdentities = nil
 	r2.NodeIdentities = nil
+	r2.TemplatedPolicies = nil
 
 	if len(r.Policies) > 0 {
 		r2.Policies = make([]ACLRolePolicyLink, len(r.Policies))
@@ -896,6 +1014,12 @@ func (r *ACLRole) Clone() *ACLRole {
 			r2.NodeIdentities[i] = n.Clone()
 		}
 	}
+	if len(r.TemplatedPolicies) > 0 {
+		r2.TemplatedPolicies = make([]*ACLTemplatedPolicy, len(r.TemplatedPolicies))
+		for i, tp := range r.TemplatedPolicies {
+			r2.TemplatedPolicies[i] = tp.Clone()
+		}
+	}
 	return &r2
 }
 
@@ -927,6 +1051,9 @@ func (r *ACLRole) SetHash(force bool) []byte {
 		for _, nodeID := range r.NodeIdentities {
 			nodeID.AddToHash(hash)
 		}
+		for _, tp := range r.TemplatedPolicies {
+			tp.AddToHash(hash)
+		}
 
 		r.EnterpriseMeta.AddToHash(hash, false)
 
@@ -954,6 +1081,9 @@ func (r *ACLRole) EstimateSize() int {
 	for _, nodeID := range r.NodeIdentities {
 		size += nodeID.EstimateSize()
 	}
+	for _, tp := range r.TemplatedPolicies {
+		size += tp.EstimateSize()
+	}
 
 	return size + r.EnterpriseMeta.EstimateSize()
 }
@@ -1242,6 +1372,28 @@ func (r *ACLTokenSetRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// ACLTokenRotateDefaultOverlap is how long a rotated token remains valid
+// when the rotate request doesn't specify an overlap window.
+const ACLTokenRotateDefaultOverlap = time.Hour
+
+// ACLTokenRotateRequest is used at the RPC layer to issue a replacement for
+// an existing token.
+type ACLTokenRotateRequest struct {
+	AccessorID string // Accessor ID of the token to rotate
+
+	// OverlapWindow is how long the rotated token remains valid after the
+	// replacement is issued. Defaults to ACLTokenRotateDefaultOverlap.
+	OverlapWindow time.Duration
+
+	Datacenter string // The datacenter to perform the request within
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *ACLTokenRotateRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 // ACLTokenGetRequest is used for token read operations at the RPC layer
 type ACLTokenGetRequest struct {
 	TokenID     string         // Accessor ID used for the token lookup
@@ -1327,6 +1479,123 @@ type ACLTokenBatchDeleteRequest struct {
 	TokenIDs []string // Tokens to delete
 }
 
+// ACLTokenBulkOp is the operation of an item of an ACLTokenBulkRequest.
+type ACLTokenBulkOp string
+
+const (
+	// ACLTokenBulkCreate creates the token of the operation.
+	ACLTokenBulkCreate ACLTokenBulkOp = "create"
+
+	// ACLTokenBulkUpdate replaces the token with the AccessorID of the token
+	// of the operation.
+	ACLTokenBulkUpdate ACLTokenBulkOp = "update"
+
+	// ACLTokenBulkDelete deletes the token with the AccessorID of the token
+	// of the operation.
+	ACLTokenBulkDelete ACLTokenBulkOp = "delete"
+
+	// ACLTokenBulkAttachPolicies links the policies of the token of the
+	// operation to the existing token with the same AccessorID.
+	ACLTokenBulkAttachPolicies ACLTokenBulkOp = "attach-policies"
+
+	// ACLTokenBulkDetachPolicies unlinks the policies of the token of the
+	// operation from the existing token with the same AccessorID.
+	ACLTokenBulkDetachPolicies ACLTokenBulkOp = "detach-policies"
+)
+
+// ACLTokenBulkOperation is an item of an ACLTokenBulkRequest. The delete,
+// attach-policies and detach-policies operations only use the AccessorID,
+// the Policies and the EnterpriseMeta of the token.
+type ACLTokenBulkOperation struct {
+	Op    ACLTokenBulkOp
+	Token ACLToken
+}
+
+// ACLTokenBulkRequest is used at the RPC layer to create, update and delete
+// many tokens at once. The operations are validated individually, and the
+// valid ones are applied in a single Raft log entry.
+type ACLTokenBulkRequest struct {
+	Operations []ACLTokenBulkOperation
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLTokenBulkRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenBulkResult is the result of an operation of an ACLTokenBulkRequest.
+type ACLTokenBulkResult struct {
+	AccessorID string
+
+	// Token is the token as written by a create, update, attach-policies or
+	// detach-policies operation.
+	Token *ACLToken `json:",omitempty"`
+
+	// Error is set if the operation failed, in which case nothing was
+	// written for it.
+	Error string `json:",omitempty"`
+}
+
+// ACLTokenBulkResponse returns the results of the operations of an
+// ACLTokenBulkRequest, in the same order as the operations.
+type ACLTokenBulkResponse struct {
+	Results []ACLTokenBulkResult
+}
+
+// ACLTokenBulkWriteRequest is used only at the Raft layer to apply the
+// token writes and deletions of an ACLTokenBulkRequest atomically.
+type ACLTokenBulkWriteRequest struct {
+	Tokens   ACLTokens // Tokens to create or update
+	TokenIDs []string  // Tokens to delete
+}
+
+// ACLTokenUsageGranularity is the resolution at which the last time a token
+// was used is recorded. Uses of a token that are closer than this to the
+// recorded time are not written to Raft, which bounds the write amplification
+// of busy tokens. It is much coarser than the interval at which agents report
+// the usages so that each token is written at most once an hour.
+const ACLTokenUsageGranularity = time.Hour
+
+// ACLTokenUsage records the last time a token was presented to an agent of
+// the datacenter, and from which address. It is stored separately from the
+// token so that recording it doesn't modify or replicate the token.
+type ACLTokenUsage struct {
+	AccessorID   string
+	LastUsedTime time.Time
+	LastUsedAddr string `json:",omitempty"`
+
+	RaftIndex
+}
+
+// ACLTokenUsageEntry is a use of a token reported by an agent, identified by
+// the secret that was presented.
+type ACLTokenUsageEntry struct {
+	SecretID string
+	Time     time.Time
+	Addr     string
+}
+
+// ACLTokenUsageReportRequest is used by agents to report the tokens that were
+// presented to their HTTP API since their last report.
+type ACLTokenUsageReportRequest struct {
+	Node       string
+	Entries    []ACLTokenUsageEntry
+	Datacenter string
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *ACLTokenUsageReportRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenUsageBatchSetRequest is used only at the Raft layer to record the
+// usage of multiple tokens at once.
+type ACLTokenUsageBatchSetRequest struct {
+	Usages []*ACLTokenUsage
+}
+
 type ACLInitialTokenBootstrapRequest struct {
 	BootstrapSecret string
 	Datacenter      string
@@ -1738,6 +2007,46 @@ func (r *ACLLogoutRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+type ACLOIDCAuthURLParams struct {
+	AuthMethod  string
+	RedirectURI string
+	ClientNonce string
+	Meta        map[string]string `json:",omitempty"`
+	acl.EnterpriseMeta
+}
+
+type ACLOIDCAuthURLRequest struct {
+	Auth       *ACLOIDCAuthURLParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCAuthURLRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+}
+
+type ACLOIDCCallbackParams struct {
+	AuthMethod  string
+	State       string
+	Code        string
+	ClientNonce string
+	acl.EnterpriseMeta
+}
+
+type ACLOIDCCallbackRequest struct {
+	Auth       *ACLOIDCCallbackParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCCallbackRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 type RemoteACLAuthorizationRequest struct {
 	Datacenter string
 	Requests   []ACLAuthorizationRequest
@@ -1778,6 +2087,60 @@ func CreateACLAuthorizationResponses(authz acl.Authorizer, requests []ACLAuthori
 	return responses, nil
 }
 
+// ACLExplainRequest asks why a token is, or isn't, authorized to access a
+// resource.
+type ACLExplainRequest struct {
+	Datacenter string
+
+	// AccessorID is the accessor of the token to explain. The token of the
+	// request is explained when it is empty. Explaining another token
+	// requires acl:read.
+	AccessorID string `json:",omitempty"`
+
+	ACLAuthorizationRequest
+	QueryOptions
+}
+
+func (r *ACLExplainRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+const (
+	// ACLExplainDecidedByPolicy is set when a rule of the policies of the
+	// token decided.
+	ACLExplainDecidedByPolicy = "policy"
+
+	// ACLExplainDecidedByDefaultPolicy is set when no rule of the policies of
+	// the token applies, and the default policy decided.
+	ACLExplainDecidedByDefaultPolicy = "default-policy"
+
+	// ACLExplainDecidedByBuiltinToken is set when the token is one of those
+	// managed by the agents and servers themselves, such as the agent
+	// recovery token, which are not bound to policies.
+	ACLExplainDecidedByBuiltinToken = "builtin-token"
+)
+
+type ACLExplainResponse struct {
+	ACLAuthorizationRequest
+	Allow bool
+
+	// AccessorID is the accessor of the token explained, if it has one.
+	AccessorID string `json:",omitempty"`
+
+	// DecidedBy is what made the decision, one of the ACLExplainDecidedBy
+	// constants.
+	DecidedBy string
+
+	// Rule is the rule of the policies of the token which decided, in the
+	// syntax of the policies. It is empty unless DecidedBy is "policy".
+	Rule string `json:",omitempty"`
+
+	// Policies are the policies of the token which contain Rule. They include
+	// the policies linked through roles, and the ones synthesized from service
+	// identities, node identities, and templated policies.
+	Policies []ACLTokenPolicyLink `json:",omitempty"`
+}
+
 type AgentRecoveryTokenIdentity struct {
 	agent    string
 	secretID string
@@ -1814,6 +2177,10 @@ func (id *AgentRecoveryTokenIdentity) NodeIdentityList() []*ACLNodeIdentity {
 	return nil
 }
 
+func (id *AgentRecoveryTokenIdentity) TemplatedPolicyList() []*ACLTemplatedPolicy {
+	return nil
+}
+
 func (id *AgentRecoveryTokenIdentity) IsExpired(asOf time.Time) bool {
 	return false
 }
@@ -1862,6 +2229,10 @@ func (i *ACLServerIdentity) NodeIdentityList() []*ACLNodeIdentity {
 	return nil
 }
 
+func (i *ACLServerIdentity) TemplatedPolicyList() []*ACLTemplatedPolicy {
+	return nil
+}
+
 func (i *ACLServerIdentity) IsExpired(asOf time.Time) bool {
 	return false
 }
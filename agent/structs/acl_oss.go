@@ -45,6 +45,18 @@ node "%[1]s" {
 service_prefix "" {
 	policy = "read"
 }`
+
+	// aclTemplatedPolicyDNS is the rules of the builtin/dns templated policy.
+	aclTemplatedPolicyDNS = `
+node_prefix "" {
+	policy = "read"
+}
+service_prefix "" {
+	policy = "read"
+}
+query_prefix "" {
+	policy = "read"
+}`
 )
 
 type ACLAuthMethodEnterpriseFields struct{}
@@ -67,6 +79,10 @@ func aclNodeIdentityRules(node string, _ *acl.EnterpriseMeta) string {
 	return fmt.Sprintf(aclPolicyTemplateNodeIdentity, node)
 }
 
+func aclDNSTemplatedPolicyRules(_ *acl.EnterpriseMeta) string {
+	return aclTemplatedPolicyDNS
+}
+
 func (p *ACLPolicy) EnterprisePolicyMeta() *acl.EnterprisePolicyMeta {
 	return nil
 }
@@ -0,0 +1,246 @@
+package structs
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/lib/stringslice"
+)
+
+const (
+	ACLTemplatedPolicyServiceName = "builtin/service"
+	ACLTemplatedPolicyNodeName    = "builtin/node"
+	ACLTemplatedPolicyDNSName     = "builtin/dns"
+
+	// ACLTemplatedPolicyNameVariable is the placeholder shown in place of
+	// the Name variable when a template is rendered without variables.
+	ACLTemplatedPolicyNameVariable = "${name}"
+)
+
+// ACLTemplatedPolicyBase is a built-in policy template. Templated policies
+// linked to tokens and roles are rendered from it into synthetic policies,
+// in the same way as service and node identities.
+type ACLTemplatedPolicyBase struct {
+	TemplateName string
+	Description  string
+
+	// RequiresName is true if the template must be given a Name variable.
+	RequiresName bool
+
+	// validName validates the Name variable.
+	validName func(name string) bool
+
+	// rules renders the rules of the template for the given variables.
+	rules func(vars *ACLTemplatedPolicyVariables, entMeta *acl.EnterpriseMeta) string
+}
+
+// Template returns the rules of the template, with placeholders in place of
+// the variables.
+func (b *ACLTemplatedPolicyBase) Template(entMeta *acl.EnterpriseMeta) string {
+	return b.rules(&ACLTemplatedPolicyVariables{Name: ACLTemplatedPolicyNameVariable}, entMeta)
+}
+
+var aclTemplatedPolicies = map[string]*ACLTemplatedPolicyBase{
+	ACLTemplatedPolicyServiceName: {
+		TemplateName: ACLTemplatedPolicyServiceName,
+		Description:  "Grants the permissions to register the named service and its sidecar proxy, and to discover the services and nodes in the catalog.",
+		RequiresName: true,
+		validName:    acl.IsValidServiceIdentityName,
+		rules: func(vars *ACLTemplatedPolicyVariables, entMeta *acl.EnterpriseMeta) string {
+			return aclServiceIdentityRules(vars.Name, entMeta)
+		},
+	},
+	ACLTemplatedPolicyNodeName: {
+		TemplateName: ACLTemplatedPolicyNodeName,
+		Description:  "Grants the permissions to register the named node in the catalog and to discover its services.",
+		RequiresName: true,
+		validName:    acl.IsValidNodeIdentityName,
+		rules: func(vars *ACLTemplatedPolicyVariables, entMeta *acl.EnterpriseMeta) string {
+			return aclNodeIdentityRules(vars.Name, entMeta)
+		},
+	},
+	ACLTemplatedPolicyDNSName: {
+		TemplateName: ACLTemplatedPolicyDNSName,
+		Description:  "Grants the permissions required by the DNS interface to resolve nodes, services and prepared queries.",
+		rules: func(_ *ACLTemplatedPolicyVariables, entMeta *acl.EnterpriseMeta) string {
+			return aclDNSTemplatedPolicyRules(entMeta)
+		},
+	},
+}
+
+// GetACLTemplatedPolicyBase returns the built-in template with the given
+// name, or false if there is none.
+func GetACLTemplatedPolicyBase(templateName string) (*ACLTemplatedPolicyBase, bool) {
+	base, ok := aclTemplatedPolicies[templateName]
+	return base, ok
+}
+
+// GetACLTemplatedPolicyList returns the built-in templates sorted by name.
+func GetACLTemplatedPolicyList() []*ACLTemplatedPolicyBase {
+	out := make([]*ACLTemplatedPolicyBase, 0, len(aclTemplatedPolicies))
+	for _, base := range aclTemplatedPolicies {
+		out = append(out, base)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].TemplateName < out[j].TemplateName
+	})
+	return out
+}
+
+// ACLTemplatedPolicyResponse describes a built-in template in the HTTP API.
+type ACLTemplatedPolicyResponse struct {
+	TemplateName string
+	Description  string
+	RequiresName bool
+
+	// Template is the rules of the template, with placeholders in place of
+	// the variables.
+	Template string
+}
+
+func (b *ACLTemplatedPolicyBase) Response(entMeta *acl.EnterpriseMeta) *ACLTemplatedPolicyResponse {
+	return &ACLTemplatedPolicyResponse{
+		TemplateName: b.TemplateName,
+		Description:  b.Description,
+		RequiresName: b.RequiresName,
+		Template:     b.Template(entMeta),
+	}
+}
+
+// ACLTemplatedPolicyVariables are the variables a template is rendered with.
+type ACLTemplatedPolicyVariables struct {
+	Name string `json:",omitempty"`
+}
+
+// ACLTemplatedPolicy links a token or a role to a built-in policy template,
+// rendered with the given variables.
+type ACLTemplatedPolicy struct {
+	TemplateName      string
+	TemplateVariables *ACLTemplatedPolicyVariables `json:",omitempty"`
+
+	// Datacenters that the synthetic policy will be valid within.
+	//   - No wildcards allowed
+	//   - If empty then the synthetic policy is valid within all datacenters
+	//
+	// Only valid for global tokens. It is an error to specify this for local tokens.
+	Datacenters []string `json:",omitempty"`
+}
+
+func (t *ACLTemplatedPolicy) Clone() *ACLTemplatedPolicy {
+	t2 := *t
+	if t.TemplateVariables != nil {
+		vars := *t.TemplateVariables
+		t2.TemplateVariables = &vars
+	}
+	t2.Datacenters = stringslice.CloneStringSlice(t.Datacenters)
+	return &t2
+}
+
+func (t *ACLTemplatedPolicy) AddToHash(h hash.Hash) {
+	h.Write([]byte(t.TemplateName))
+	h.Write([]byte(t.name()))
+	for _, dc := range t.Datacenters {
+		h.Write([]byte(dc))
+	}
+}
+
+func (t *ACLTemplatedPolicy) EstimateSize() int {
+	size := len(t.TemplateName) + len(t.name())
+	for _, dc := range t.Datacenters {
+		size += len(dc)
+	}
+	return size
+}
+
+func (t *ACLTemplatedPolicy) name() string {
+	if t.TemplateVariables == nil {
+		return ""
+	}
+	return t.TemplateVariables.Name
+}
+
+// Validate returns an error if the template doesn't exist or if the
+// variables don't match the ones required by the template.
+func (t *ACLTemplatedPolicy) Validate() error {
+	base, ok := GetACLTemplatedPolicyBase(t.TemplateName)
+	if !ok {
+		return fmt.Errorf("templated policy %q does not exist", t.TemplateName)
+	}
+
+	name := t.name()
+	switch {
+	case base.RequiresName && name == "":
+		return fmt.Errorf("templated policy %q requires the name variable", t.TemplateName)
+	case base.RequiresName && !base.validName(name):
+		return fmt.Errorf("templated policy %q has an invalid name %q. Only lowercase alphanumeric characters, '-' and '_' are allowed", t.TemplateName, name)
+	case !base.RequiresName && name != "":
+		return fmt.Errorf("templated policy %q doesn't accept variables", t.TemplateName)
+	}
+	return nil
+}
+
+func (t *ACLTemplatedPolicy) SyntheticPolicy(entMeta *acl.EnterpriseMeta) (*ACLPolicy, error) {
+	base, ok := GetACLTemplatedPolicyBase(t.TemplateName)
+	if !ok {
+		return nil, fmt.Errorf("templated policy %q does not exist", t.TemplateName)
+	}
+
+	vars := t.TemplateVariables
+	if vars == nil {
+		vars = &ACLTemplatedPolicyVariables{}
+	}
+
+	// Given that we validate the variables before persisting, we do not
+	// have to escape them before rendering the template.
+	rules := base.rules(vars, entMeta)
+
+	hasher := fnv.New128a()
+	hashID := fmt.Sprintf("%x", hasher.Sum([]byte(rules)))
+
+	policy := &ACLPolicy{}
+	policy.ID = hashID
+	policy.Name = fmt.Sprintf("synthetic-policy-%s", hashID)
+	if vars.Name != "" {
+		policy.Description = fmt.Sprintf("synthetic policy generated from templated policy %q with name %q", t.TemplateName, vars.Name)
+	} else {
+		policy.Description = fmt.Sprintf("synthetic policy generated from templated policy %q", t.TemplateName)
+	}
+	policy.Rules = rules
+	policy.Datacenters = t.Datacenters
+	policy.EnterpriseMeta.Merge(entMeta)
+	policy.SetHash(true)
+	return policy, nil
+}
+
+type ACLTemplatedPolicies []*ACLTemplatedPolicy
+
+// Deduplicate returns a new list of templated policies without duplicates.
+// Templated policies with the same template and variables but different
+// datacenters will be merged into a single templated policy with all
+// datacenters.
+func (tps ACLTemplatedPolicies) Deduplicate() ACLTemplatedPolicies {
+	type mapKey struct {
+		templateName, name string
+	}
+	unique := make(map[mapKey]*ACLTemplatedPolicy)
+
+	var results ACLTemplatedPolicies
+	for _, tp := range tps {
+		key := mapKey{tp.TemplateName, tp.name()}
+		entry, ok := unique[key]
+		if ok {
+			dcs := stringslice.CloneStringSlice(tp.Datacenters)
+			sort.Strings(dcs)
+			entry.Datacenters = stringslice.MergeSorted(dcs, entry.Datacenters)
+		} else {
+			entry = tp.Clone()
+			sort.Strings(entry.Datacenters)
+			unique[key] = entry
+			results = append(results, entry)
+		}
+	}
+	return results
+}
@@ -0,0 +1,117 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructs_ACLTemplatedPolicy_SyntheticPolicy(t *testing.T) {
+	cases := map[string]struct {
+		templatedPolicy *ACLTemplatedPolicy
+		expectRules     string
+	}{
+		"service": {
+			templatedPolicy: &ACLTemplatedPolicy{
+				TemplateName:      ACLTemplatedPolicyServiceName,
+				TemplateVariables: &ACLTemplatedPolicyVariables{Name: "web"},
+				Datacenters:       []string{"dc1"},
+			},
+			expectRules: aclServiceIdentityRules("web", nil),
+		},
+		"node": {
+			templatedPolicy: &ACLTemplatedPolicy{
+				TemplateName:      ACLTemplatedPolicyNodeName,
+				TemplateVariables: &ACLTemplatedPolicyVariables{Name: "node-1"},
+			},
+			expectRules: aclNodeIdentityRules("node-1", nil),
+		},
+		"dns": {
+			templatedPolicy: &ACLTemplatedPolicy{TemplateName: ACLTemplatedPolicyDNSName},
+			expectRules:     aclDNSTemplatedPolicyRules(nil),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, tc.templatedPolicy.Validate())
+
+			got, err := tc.templatedPolicy.SyntheticPolicy(nil)
+			require.NoError(t, err)
+			require.NotEmpty(t, got.ID)
+			require.True(t, strings.HasPrefix(got.Name, "synthetic-policy-"))
+			require.Contains(t, got.Description, tc.templatedPolicy.TemplateName)
+			require.Equal(t, tc.expectRules, got.Rules)
+			require.Equal(t, tc.templatedPolicy.Datacenters, got.Datacenters)
+		})
+	}
+
+	_, err := (&ACLTemplatedPolicy{TemplateName: "builtin/unknown"}).SyntheticPolicy(nil)
+	require.EqualError(t, err, `templated policy "builtin/unknown" does not exist`)
+}
+
+func TestStructs_ACLTemplatedPolicy_Validate(t *testing.T) {
+	cases := map[string]struct {
+		templatedPolicy *ACLTemplatedPolicy
+		err             string
+	}{
+		"unknown template": {
+			templatedPolicy: &ACLTemplatedPolicy{TemplateName: "builtin/unknown"},
+			err:             `templated policy "builtin/unknown" does not exist`,
+		},
+		"missing name": {
+			templatedPolicy: &ACLTemplatedPolicy{TemplateName: ACLTemplatedPolicyNodeName},
+			err:             `templated policy "builtin/node" requires the name variable`,
+		},
+		"invalid name": {
+			templatedPolicy: &ACLTemplatedPolicy{
+				TemplateName:      ACLTemplatedPolicyServiceName,
+				TemplateVariables: &ACLTemplatedPolicyVariables{Name: "web\"}"},
+			},
+			err: `templated policy "builtin/service" has an invalid name "web\"}". Only lowercase alphanumeric characters, '-' and '_' are allowed`,
+		},
+		"unexpected name": {
+			templatedPolicy: &ACLTemplatedPolicy{
+				TemplateName:      ACLTemplatedPolicyDNSName,
+				TemplateVariables: &ACLTemplatedPolicyVariables{Name: "web"},
+			},
+			err: `templated policy "builtin/dns" doesn't accept variables`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.EqualError(t, tc.templatedPolicy.Validate(), tc.err)
+		})
+	}
+}
+
+func TestStructs_ACLTemplatedPolicies_Deduplicate(t *testing.T) {
+	web := &ACLTemplatedPolicyVariables{Name: "web"}
+	templatedPolicies := ACLTemplatedPolicies{
+		{TemplateName: ACLTemplatedPolicyServiceName, TemplateVariables: web, Datacenters: []string{"dc2"}},
+		{TemplateName: ACLTemplatedPolicyServiceName, TemplateVariables: web, Datacenters: []string{"dc1"}},
+		{TemplateName: ACLTemplatedPolicyServiceName, TemplateVariables: &ACLTemplatedPolicyVariables{Name: "db"}},
+		{TemplateName: ACLTemplatedPolicyDNSName},
+		{TemplateName: ACLTemplatedPolicyDNSName},
+	}
+
+	require.Equal(t, ACLTemplatedPolicies{
+		{TemplateName: ACLTemplatedPolicyServiceName, TemplateVariables: web, Datacenters: []string{"dc1", "dc2"}},
+		{TemplateName: ACLTemplatedPolicyServiceName, TemplateVariables: &ACLTemplatedPolicyVariables{Name: "db"}},
+		{TemplateName: ACLTemplatedPolicyDNSName},
+	}, templatedPolicies.Deduplicate())
+}
+
+func TestStructs_ACLTemplatedPolicyBase_Template(t *testing.T) {
+	base, ok := GetACLTemplatedPolicyBase(ACLTemplatedPolicyServiceName)
+	require.True(t, ok)
+	require.Contains(t, base.Template(nil), `service "${name}" {`)
+
+	var names []string
+	for _, base := range GetACLTemplatedPolicyList() {
+		names = append(names, base.TemplateName)
+	}
+	require.Equal(t, []string{ACLTemplatedPolicyDNSName, ACLTemplatedPolicyNodeName, ACLTemplatedPolicyServiceName}, names)
+}
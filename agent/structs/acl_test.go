@@ -97,6 +97,38 @@ func TestStructs_ACLServiceIdentities_Deduplicate(t *testing.T) {
 	}, identities.Deduplicate())
 
 	require.Len(t, identities, 3, "original slice shouldn't have been mutated")
+
+	scoped := ACLServiceIdentities{
+		{ServiceName: "web", Node: "node-1"},
+		{ServiceName: "web", Node: "node-1"},
+		{ServiceName: "web"},
+	}
+	require.ElementsMatch(t, ACLServiceIdentities{
+		{ServiceName: "web", Node: "node-1"},
+		{ServiceName: "web"},
+	}, scoped.Deduplicate())
+}
+
+func TestStructs_ACLServiceIdentities_AllowsServiceOnNode(t *testing.T) {
+	scoped := ACLServiceIdentities{
+		{ServiceName: "web", Node: "node-1"},
+		{ServiceName: "web", NodePrefix: "web-"},
+		{ServiceName: "db"},
+	}
+
+	require.True(t, scoped.AllowsServiceOnNode("web", "node-1"))
+	require.True(t, scoped.AllowsServiceOnNode("web", "web-2"))
+	require.True(t, scoped.AllowsServiceOnNode("web-sidecar-proxy", "web-2"))
+	require.False(t, scoped.AllowsServiceOnNode("web", "node-2"))
+	require.False(t, scoped.AllowsServiceOnNode("web-sidecar-proxy", "node-2"))
+
+	// Unscoped identities, or the lack of identity for the service, leave the
+	// decision to the policies.
+	require.True(t, scoped.AllowsServiceOnNode("db", "node-2"))
+	require.True(t, scoped.AllowsServiceOnNode("api", "node-2"))
+
+	scoped = append(scoped, &ACLServiceIdentity{ServiceName: "web"})
+	require.True(t, scoped.AllowsServiceOnNode("web", "node-2"))
 }
 
 func TestStructs_ACLNodeIdentities_Deduplicate(t *testing.T) {
@@ -83,6 +83,9 @@ func (f *Filter) Filter(subject any) {
 	case *structs.IndexedServiceNodes:
 		v.QueryMeta.ResultsFilteredByACLs = f.filterServiceNodes(&v.ServiceNodes)
 
+	case *structs.IndexedServiceTombstones:
+		v.QueryMeta.ResultsFilteredByACLs = f.filterServiceTombstones(&v.Tombstones)
+
 	case *structs.IndexedServices:
 		v.QueryMeta.ResultsFilteredByACLs = f.filterServices(v.Services, &v.EnterpriseMeta)
 
@@ -260,6 +263,30 @@ func (f *Filter) filterServiceNodes(nodes *structs.ServiceNodes) bool {
 	return removed
 }
 
+// filterServiceTombstones is used to filter service tombstones based on ACL
+// rules. Returns true if any elements were removed.
+func (f *Filter) filterServiceTombstones(stones *structs.ServiceTombstones) bool {
+	st := *stones
+	var authzContext acl.AuthorizerContext
+	var removed bool
+
+	for i := 0; i < len(st); i++ {
+		stone := st[i]
+
+		stone.FillAuthzContext(&authzContext)
+		if f.allowNode(stone.Node, &authzContext) && f.allowService(stone.ServiceName, &authzContext) {
+			continue
+		}
+		removed = true
+		f.logger.Debug("dropping service tombstone from result due to ACLs",
+			"service", structs.NewServiceID(stone.ServiceID, &stone.EnterpriseMeta).String())
+		st = append(st[:i], st[i+1:]...)
+		i--
+	}
+	*stones = st
+	return removed
+}
+
 // filterNodeServices is used to filter services on a given node base on ACLs.
 // Returns true if any elements were removed
 func (f *Filter) filterNodeServices(services **structs.NodeServices) bool {
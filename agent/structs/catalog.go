@@ -15,6 +15,13 @@ const (
 	SerfCheckFailedOutput               = "Agent not live or unreachable"
 )
 
+// These are used to manage the "externalNodeHealth" check that's attached to
+// the external nodes pinged by the servers.
+const (
+	ExternalNodeCheckID   types.CheckID = "externalNodeHealth"
+	ExternalNodeCheckName               = "External Node Status"
+)
+
 const (
 	// These are used to manage the "consul" service that's attached to every
 	// Consul server node in the catalog.
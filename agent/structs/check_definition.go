@@ -16,6 +16,7 @@ type CheckDefinition struct {
 	Name      string
 	Notes     string
 	ServiceID string
+	PortName  string
 	Token     string
 	Status    string
 
@@ -39,11 +40,19 @@ type CheckDefinition struct {
 	Shell                          string
 	GRPC                           string
 	GRPCUseTLS                     bool
+	GRPCService                    string
+	GRPCAuthority                  string
 	OSService                      string
+	ProcessPID                     int
+	ProcessPIDFile                 string
+	ProcessCgroup                  string
 	TLSServerName                  string
 	TLSSkipVerify                  bool
 	AliasNode                      string
 	AliasService                   string
+	CompositeMode                  string
+	CompositeChecks                []types.CheckID
+	CompositeMinPassing            int
 	Timeout                        time.Duration
 	TTL                            time.Duration
 	SuccessBeforePassing           int
@@ -75,6 +84,7 @@ func (t *CheckDefinition) UnmarshalJSON(data []byte) (err error) {
 		TLSSkipVerifySnake                  bool        `json:"tls_skip_verify"`
 		GRPCUseTLSSnake                     bool        `json:"grpc_use_tls"`
 		ServiceIDSnake                      string      `json:"service_id"`
+		PortNameSnake                       string      `json:"port_name"`
 		H2PingUseTLSSnake                   bool        `json:"h2ping_use_tls"`
 		DisableRedirectsSnake               bool        `json:"disable_redirects"`
 
@@ -122,6 +132,9 @@ func (t *CheckDefinition) UnmarshalJSON(data []byte) (err error) {
 	if t.ServiceID == "" {
 		t.ServiceID = aux.ServiceIDSnake
 	}
+	if t.PortName == "" {
+		t.PortName = aux.PortNameSnake
+	}
 	if aux.DisableRedirectsSnake {
 		t.DisableRedirects = aux.DisableRedirectsSnake
 	}
@@ -183,6 +196,7 @@ func (c *CheckDefinition) HealthCheck(node string) *HealthCheck {
 		Status:         api.HealthCritical,
 		Notes:          c.Notes,
 		ServiceID:      c.ServiceID,
+		PortName:       c.PortName,
 		Interval:       c.Interval.String(),
 		Timeout:        c.Timeout.String(),
 		EnterpriseMeta: c.EnterpriseMeta,
@@ -203,14 +217,20 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		Status:  c.Status,
 		Notes:   c.Notes,
 
+		PortName:                       c.PortName,
 		ScriptArgs:                     c.ScriptArgs,
 		AliasNode:                      c.AliasNode,
 		AliasService:                   c.AliasService,
+		CompositeMode:                  c.CompositeMode,
+		CompositeChecks:                c.CompositeChecks,
+		CompositeMinPassing:            c.CompositeMinPassing,
 		HTTP:                           c.HTTP,
 		H2PING:                         c.H2PING,
 		H2PingUseTLS:                   c.H2PingUseTLS,
 		GRPC:                           c.GRPC,
 		GRPCUseTLS:                     c.GRPCUseTLS,
+		GRPCService:                    c.GRPCService,
+		GRPCAuthority:                  c.GRPCAuthority,
 		Header:                         c.Header,
 		Method:                         c.Method,
 		Body:                           c.Body,
@@ -222,6 +242,9 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		DockerContainerID:              c.DockerContainerID,
 		Shell:                          c.Shell,
 		OSService:                      c.OSService,
+		ProcessPID:                     c.ProcessPID,
+		ProcessPIDFile:                 c.ProcessPIDFile,
+		ProcessCgroup:                  c.ProcessCgroup,
 		TLSServerName:                  c.TLSServerName,
 		TLSSkipVerify:                  c.TLSSkipVerify,
 		Timeout:                        c.Timeout,
@@ -7,6 +7,7 @@ import (
 
 	fuzz "github.com/google/gofuzz"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/types"
 	"github.com/mitchellh/reflectwalk"
 	"github.com/stretchr/testify/require"
 )
@@ -91,6 +92,9 @@ func TestCheckDefinitionToCheckType(t *testing.T) {
 		DockerContainerID:              "abc123",
 		Shell:                          "/bin/ksh",
 		OSService:                      "myco-svctype-svcname-001",
+		CompositeMode:                  "or",
+		CompositeChecks:                []types.CheckID{"a", "b"},
+		CompositeMinPassing:            1,
 		TLSSkipVerify:                  true,
 		Timeout:                        2 * time.Second,
 		TTL:                            3 * time.Second,
@@ -110,6 +114,9 @@ func TestCheckDefinitionToCheckType(t *testing.T) {
 		DockerContainerID:              "abc123",
 		Shell:                          "/bin/ksh",
 		OSService:                      "myco-svctype-svcname-001",
+		CompositeMode:                  "or",
+		CompositeChecks:                []types.CheckID{"a", "b"},
+		CompositeMinPassing:            1,
 		TLSSkipVerify:                  true,
 		Timeout:                        2 * time.Second,
 		TTL:                            3 * time.Second,
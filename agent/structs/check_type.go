@@ -11,11 +11,22 @@ import (
 
 type CheckTypes []*CheckType
 
+// Composite check modes determine how many of the aggregated checks must be
+// passing for a composite check to be passing.
+const (
+	// CompositeModeAnd requires all of the aggregated checks to be passing.
+	CompositeModeAnd = "and"
+
+	// CompositeModeOr requires at least one of the aggregated checks to be
+	// passing.
+	CompositeModeOr = "or"
+)
+
 // CheckType is used to create either the CheckMonitor or the CheckTTL.
-// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, H2PING. Script,
+// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, H2PING, Composite. Script,
 // HTTP, Docker, TCP, GRPC, and H2PING all require Interval. Only one of the types may
 // to be provided: TTL or Script/Interval or HTTP/Interval or TCP/Interval or
-// Docker/Interval or GRPC/Interval or AliasService or H2PING/Interval.
+// Docker/Interval or GRPC/Interval or AliasService or H2PING/Interval or CompositeMode.
 // Since types like CheckHTTP and CheckGRPC derive from CheckType, there are
 // helper conversion methods that do the reverse conversion. ie. checkHTTP.CheckType()
 type CheckType struct {
@@ -27,6 +38,10 @@ type CheckType struct {
 	Status  string
 	Notes   string
 
+	// PortName is the name of the port of the service the check is scoped
+	// to. The check then only affects the health of that port.
+	PortName string
+
 	// fields copied to CheckDefinition
 	// Update CheckDefinition when adding fields here
 
@@ -43,11 +58,19 @@ type CheckType struct {
 	Interval               time.Duration
 	AliasNode              string
 	AliasService           string
+	CompositeMode          string
+	CompositeChecks        []types.CheckID
+	CompositeMinPassing    int
 	DockerContainerID      string
 	Shell                  string
 	GRPC                   string
 	GRPCUseTLS             bool
+	GRPCService            string
+	GRPCAuthority          string
 	OSService              string
+	ProcessPID             int
+	ProcessPIDFile         string
+	ProcessCgroup          string
 	TLSServerName          string
 	TLSSkipVerify          bool
 	Timeout                time.Duration
@@ -86,6 +109,7 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 		TLSSkipVerifySnake                  bool        `json:"tls_skip_verify"`
 		GRPCUseTLSSnake                     bool        `json:"grpc_use_tls"`
 		H2PingUseTLSSnake                   bool        `json:"h2ping_use_tls"`
+		PortNameSnake                       string      `json:"port_name"`
 
 		// These are going to be ignored but since we are disallowing unknown fields
 		// during parsing we have to be explicit about parsing but not using these.
@@ -131,6 +155,9 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 	if aux.GRPCUseTLSSnake {
 		t.GRPCUseTLS = aux.GRPCUseTLSSnake
 	}
+	if t.PortName == "" {
+		t.PortName = aux.PortNameSnake
+	}
 	if aux.Interval != nil {
 		switch v := aux.Interval.(type) {
 		case string:
@@ -181,13 +208,22 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 
 // Validate returns an error message if the check is invalid
 func (c *CheckType) Validate() error {
-	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != ""
+	intervalCheck := c.IsScript() || c.DockerContainerID != "" || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != "" || c.isProcessCheck()
 
 	if c.Interval > 0 && c.TTL > 0 {
 		return fmt.Errorf("Interval and TTL cannot both be specified")
 	}
 	if intervalCheck && c.Interval <= 0 {
-		return fmt.Errorf("Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks")
+		return fmt.Errorf("Interval must be > 0 for Script, Docker, HTTP, H2PING, TCP, UDP, OSService or Process checks")
+	}
+	if c.GRPC == "" && (c.GRPCService != "" || c.GRPCAuthority != "") {
+		return fmt.Errorf("GRPCService and GRPCAuthority are only valid for GRPC checks")
+	}
+	if c.ProcessPID < 0 {
+		return fmt.Errorf("ProcessPID must be positive")
+	}
+	if c.ProcessPID > 0 && c.ProcessPIDFile != "" {
+		return fmt.Errorf("ProcessPID and ProcessPIDFile cannot both be specified")
 	}
 	if intervalCheck && c.IsAlias() {
 		return fmt.Errorf("Interval cannot be set for Alias checks")
@@ -195,12 +231,18 @@ func (c *CheckType) Validate() error {
 	if c.IsAlias() && c.TTL > 0 {
 		return fmt.Errorf("TTL must be not be set for Alias checks")
 	}
-	if !intervalCheck && !c.IsAlias() && c.TTL <= 0 {
+	if err := c.validateComposite(intervalCheck); err != nil {
+		return err
+	}
+	if !intervalCheck && !c.IsAlias() && !c.IsComposite() && c.TTL <= 0 {
 		return fmt.Errorf("TTL must be > 0 for TTL checks")
 	}
 	if c.OutputMaxSize < 0 {
 		return fmt.Errorf("MaxOutputMaxSize must be positive")
 	}
+	if c.SuccessBeforePassing < 0 || c.FailuresBeforeWarning < 0 || c.FailuresBeforeCritical < 0 {
+		return fmt.Errorf("SuccessBeforePassing, FailuresBeforeWarning and FailuresBeforeCritical must be positive")
+	}
 	if c.FailuresBeforeWarning > c.FailuresBeforeCritical {
 		return fmt.Errorf("FailuresBeforeWarning can't be higher than FailuresBeforeCritical")
 	}
@@ -208,6 +250,42 @@ func (c *CheckType) Validate() error {
 	return nil
 }
 
+func (c *CheckType) validateComposite(intervalCheck bool) error {
+	if !c.IsComposite() {
+		if len(c.CompositeChecks) > 0 || c.CompositeMinPassing != 0 {
+			return fmt.Errorf("CompositeMode must be set for Composite checks")
+		}
+		return nil
+	}
+
+	switch c.CompositeMode {
+	case CompositeModeAnd, CompositeModeOr:
+	default:
+		return fmt.Errorf("CompositeMode must be %q or %q", CompositeModeAnd, CompositeModeOr)
+	}
+	if intervalCheck || c.IsAlias() {
+		return fmt.Errorf("Composite checks cannot be combined with other check types")
+	}
+	if c.Interval > 0 || c.TTL > 0 {
+		return fmt.Errorf("Interval and TTL must not be set for Composite checks")
+	}
+	if c.CompositeMinPassing < 0 {
+		return fmt.Errorf("CompositeMinPassing must be positive")
+	}
+	if len(c.CompositeChecks) > 0 && c.CompositeMinPassing > len(c.CompositeChecks) {
+		return fmt.Errorf("CompositeMinPassing can't be higher than the number of CompositeChecks")
+	}
+	for _, id := range c.CompositeChecks {
+		if id == "" {
+			return fmt.Errorf("CompositeChecks must not contain empty check IDs")
+		}
+		if c.CheckID != "" && id == c.CheckID {
+			return fmt.Errorf("Composite check %q cannot aggregate itself", c.CheckID)
+		}
+	}
+	return nil
+}
+
 // Empty checks if the CheckType has no fields defined. Empty checks parsed from json configs are filtered out
 func (c *CheckType) Empty() bool {
 	return reflect.DeepEqual(c, &CheckType{})
@@ -218,6 +296,11 @@ func (c *CheckType) IsAlias() bool {
 	return c.AliasNode != "" || c.AliasService != ""
 }
 
+// IsComposite checks if this is a composite check.
+func (c *CheckType) IsComposite() bool {
+	return c.CompositeMode != ""
+}
+
 // IsScript checks if this is a check that execs some kind of script.
 func (c *CheckType) IsScript() bool {
 	return len(c.ScriptArgs) > 0
@@ -247,9 +330,10 @@ func (c *CheckType) IsUDP() bool {
 	return c.UDP != "" && c.Interval > 0
 }
 
-// IsDocker returns true when checking a docker container.
+// IsDocker returns true when checking a docker container, either by running
+// a script inside of it or by inspecting its state.
 func (c *CheckType) IsDocker() bool {
-	return c.IsScript() && c.DockerContainerID != "" && c.Interval > 0
+	return c.DockerContainerID != "" && c.Interval > 0
 }
 
 // IsGRPC checks if this is a GRPC type
@@ -267,6 +351,15 @@ func (c *CheckType) IsOSService() bool {
 	return c.OSService != "" && c.Interval > 0
 }
 
+// IsProcess checks if this is a Process type
+func (c *CheckType) IsProcess() bool {
+	return c.isProcessCheck() && c.Interval > 0
+}
+
+func (c *CheckType) isProcessCheck() bool {
+	return c.ProcessPID > 0 || c.ProcessPIDFile != "" || c.ProcessCgroup != ""
+}
+
 func (c *CheckType) Type() string {
 	switch {
 	case c.IsGRPC():
@@ -281,6 +374,8 @@ func (c *CheckType) Type() string {
 		return "udp"
 	case c.IsAlias():
 		return "alias"
+	case c.IsComposite():
+		return "composite"
 	case c.IsDocker():
 		return "docker"
 	case c.IsScript():
@@ -289,6 +384,8 @@ func (c *CheckType) Type() string {
 		return "h2ping"
 	case c.IsOSService():
 		return "os_service"
+	case c.IsProcess():
+		return "process"
 	default:
 		return ""
 	}
@@ -0,0 +1,109 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/types"
+)
+
+func TestCheckType_Validate_Composite(t *testing.T) {
+	cases := map[string]struct {
+		check *CheckType
+		err   string
+	}{
+		"and": {
+			check: &CheckType{CompositeMode: CompositeModeAnd},
+		},
+		"or with min passing": {
+			check: &CheckType{
+				CompositeMode:       CompositeModeOr,
+				CompositeChecks:     []types.CheckID{"a", "b", "c"},
+				CompositeMinPassing: 2,
+			},
+		},
+		"invalid mode": {
+			check: &CheckType{CompositeMode: "xor"},
+			err:   `CompositeMode must be "and" or "or"`,
+		},
+		"missing mode": {
+			check: &CheckType{CompositeChecks: []types.CheckID{"a"}, TTL: time.Second},
+			err:   "CompositeMode must be set",
+		},
+		"interval": {
+			check: &CheckType{CompositeMode: CompositeModeAnd, Interval: time.Second},
+			err:   "Interval and TTL must not be set",
+		},
+		"combined with http": {
+			check: &CheckType{CompositeMode: CompositeModeAnd, HTTP: "http://foo", Interval: time.Second},
+			err:   "cannot be combined with other check types",
+		},
+		"min passing too high": {
+			check: &CheckType{
+				CompositeMode:       CompositeModeOr,
+				CompositeChecks:     []types.CheckID{"a"},
+				CompositeMinPassing: 2,
+			},
+			err: "CompositeMinPassing can't be higher",
+		},
+		"self reference": {
+			check: &CheckType{
+				CheckID:         "self",
+				CompositeMode:   CompositeModeAnd,
+				CompositeChecks: []types.CheckID{"a", "self"},
+			},
+			err: "cannot aggregate itself",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.check.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, "composite", tc.check.Type())
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestCheckType_Validate_GRPC(t *testing.T) {
+	cases := map[string]struct {
+		check *CheckType
+		err   string
+	}{
+		"unix socket with service and authority": {
+			check: &CheckType{
+				GRPC:          "unix:///var/run/app.sock",
+				GRPCService:   "app",
+				GRPCAuthority: "app.example.com",
+				Interval:      time.Second,
+			},
+		},
+		"service without grpc": {
+			check: &CheckType{GRPCService: "app", TTL: time.Second},
+			err:   "only valid for GRPC checks",
+		},
+		"authority without grpc": {
+			check: &CheckType{GRPCAuthority: "app.example.com", HTTP: "http://foo", Interval: time.Second},
+			err:   "only valid for GRPC checks",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.check.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, "grpc", tc.check.Type())
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -39,6 +39,8 @@ const (
 	InlineCertificate  string = "inline-certificate"
 	HTTPRoute          string = "http-route"
 	TCPRoute           string = "tcp-route"
+	APIGatewayPolicy   string = "api-gateway-policy"
+	SamenessGroup      string = "sameness-group"
 
 	ProxyConfigGlobal string = "global"
 	MeshConfigMesh    string = "mesh"
@@ -64,6 +66,8 @@ var AllConfigEntryKinds = []string{
 	HTTPRoute,
 	TCPRoute,
 	InlineCertificate,
+	APIGatewayPolicy,
+	SamenessGroup,
 }
 
 // ConfigEntry is the interface for centralized configuration stored in Raft.
@@ -136,6 +140,19 @@ type ServiceConfigEntry struct {
 	BalanceInboundConnections string                 `json:",omitempty" alias:"balance_inbound_connections"`
 	EnvoyExtensions           EnvoyExtensions        `json:",omitempty" alias:"envoy_extensions"`
 
+	// AccessLogs overrides the proxy-defaults access logging configuration
+	// for the proxies of this service.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
+
+	// RetryPolicy is the default retry policy of the routes to this service.
+	// It overrides the one of proxy-defaults.
+	RetryPolicy *RouteRetryPolicy `json:",omitempty" alias:"retry_policy"`
+
+	// UpstreamConnectionPool holds the connection settings that the proxies of
+	// the downstreams of this service use by default for its upstream
+	// clusters. The upstream config of the downstreams overrides them.
+	UpstreamConnectionPool *UpstreamConnectionPool `json:",omitempty" alias:"upstream_connection_pool"`
+
 	Meta               map[string]string `json:",omitempty"`
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	RaftIndex
@@ -145,6 +162,15 @@ func (e *ServiceConfigEntry) Clone() *ServiceConfigEntry {
 	e2 := *e
 	e2.Expose = e.Expose.Clone()
 	e2.UpstreamConfig = e.UpstreamConfig.Clone()
+	if e.AccessLogs != nil {
+		accessLogs := *e.AccessLogs
+		e2.AccessLogs = &accessLogs
+	}
+	e2.RetryPolicy = e.RetryPolicy.Clone()
+	if e.UpstreamConnectionPool != nil {
+		pool := *e.UpstreamConnectionPool
+		e2.UpstreamConnectionPool = &pool
+	}
 	return &e2
 }
 
@@ -194,6 +220,15 @@ func (e *ServiceConfigEntry) Normalize() error {
 		}
 	}
 
+	if e.Destination != nil {
+		if e.Destination.HealthCheck != nil {
+			e.Destination.HealthCheck.Protocol = strings.ToLower(e.Destination.HealthCheck.Protocol)
+		}
+		if e.Destination.DNS != nil {
+			e.Destination.DNS.DiscoveryType = strings.ToLower(e.Destination.DNS.DiscoveryType)
+		}
+	}
+
 	return validationErr
 }
 
@@ -253,6 +288,49 @@ func (e *ServiceConfigEntry) Validate() error {
 		if e.Destination.Port < 1 || e.Destination.Port > 65535 {
 			validationErr = multierror.Append(validationErr, fmt.Errorf("Invalid Port number %d", e.Destination.Port))
 		}
+
+		for _, address := range e.Destination.Addresses {
+			if IsWildcardHostname(address) && !IsProtocolHTTPLike(e.Protocol) {
+				validationErr = multierror.Append(validationErr, fmt.Errorf("Destination address '%s' is a wildcard, which is only supported for protocol 'http', 'http2' or 'grpc'", address))
+			}
+		}
+
+		if e.Destination.HealthCheck != nil || e.Destination.DNS != nil {
+			hasHostname := false
+			for _, address := range e.Destination.Addresses {
+				if IsHostname(address) {
+					hasHostname = true
+					break
+				}
+			}
+			if !hasHostname {
+				validationErr = multierror.Append(validationErr, errors.New("Destination HealthCheck and DNS are only supported for hostname addresses"))
+			}
+		}
+		if err := e.Destination.HealthCheck.Validate(); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid Destination HealthCheck: %w", err))
+		}
+		if err := e.Destination.DNS.Validate(); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid Destination DNS: %w", err))
+		}
+	}
+
+	if e.AccessLogs != nil {
+		if err := e.AccessLogs.Validate(); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid access logs: %w", err))
+		}
+	}
+
+	if e.RetryPolicy != nil {
+		if err := e.RetryPolicy.Validate(); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid retry policy: %w", err))
+		}
+	}
+
+	if e.UpstreamConnectionPool != nil {
+		if err := e.UpstreamConnectionPool.Validate(); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid upstream connection pool: %w", err))
+		}
 	}
 
 	if err := envoyextensions.ValidateExtensions(e.EnvoyExtensions.ToAPI()); err != nil {
@@ -268,8 +346,10 @@ func validateEndpointAddress(address string) error {
 	ip := net.ParseIP(address)
 	valid = ip != nil
 
-	hasWildcard := strings.Contains(address, "*")
-	_, ok := dns.IsDomainName(address)
+	// A wildcard is only allowed as the left-most label of a hostname.
+	hostname := strings.TrimPrefix(address, wildcardPrefix)
+	hasWildcard := strings.Contains(hostname, "*")
+	_, ok := dns.IsDomainName(hostname)
 	valid = valid || (ok && !hasWildcard)
 
 	if !valid {
@@ -340,11 +420,113 @@ func (c *UpstreamConfiguration) Clone() *UpstreamConfiguration {
 
 // DestinationConfig represents a virtual service, i.e. one that is external to Consul
 type DestinationConfig struct {
-	// Addresses of the endpoint; hostname or IP
+	// Addresses of the endpoint; hostname or IP. Hostnames may use a wildcard
+	// as their left-most label (e.g. "*.example.com") when the service
+	// protocol is HTTP-like, in which case terminating gateways resolve the
+	// requested host dynamically.
 	Addresses []string `json:",omitempty"`
 
 	// Port allowed within this endpoint
 	Port int `json:",omitempty"`
+
+	// HealthCheck configures active health checking of the hostname
+	// addresses by terminating gateways, so that unhealthy endpoints are
+	// ejected instead of timing out client requests. Wildcard hostnames are
+	// not health checked.
+	HealthCheck *DestinationHealthCheck `json:",omitempty" alias:"health_check"`
+
+	// DNS configures how terminating gateways re-resolve the hostname
+	// addresses.
+	DNS *DestinationDNSConfig `json:",omitempty"`
+}
+
+// DestinationHealthCheck is an active health check performed by terminating
+// gateways against hostname Destinations.
+type DestinationHealthCheck struct {
+	// Protocol used for the check, one of "tcp" or "http". Defaults to "tcp".
+	Protocol string `json:",omitempty"`
+
+	// Path requested by "http" checks. Defaults to "/".
+	Path string `json:",omitempty"`
+
+	// Interval between checks. Defaults to 10s.
+	Interval time.Duration `json:",omitempty"`
+
+	// Timeout for a single check. Defaults to 5s.
+	Timeout time.Duration `json:",omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed checks before an
+	// endpoint is ejected. Defaults to 2.
+	UnhealthyThreshold uint32 `json:",omitempty" alias:"unhealthy_threshold"`
+
+	// HealthyThreshold is the number of consecutive successful checks before
+	// an ejected endpoint is used again. Defaults to 2.
+	HealthyThreshold uint32 `json:",omitempty" alias:"healthy_threshold"`
+}
+
+func (hc *DestinationHealthCheck) Validate() error {
+	if hc == nil {
+		return nil
+	}
+	switch hc.Protocol {
+	case "", "tcp":
+		if hc.Path != "" {
+			return fmt.Errorf("Path is only supported for http health checks")
+		}
+	case "http":
+		if hc.Path != "" && !strings.HasPrefix(hc.Path, "/") {
+			return fmt.Errorf("Path must start with '/'")
+		}
+	default:
+		return fmt.Errorf("unsupported health check protocol %q, must be one of 'tcp' or 'http'", hc.Protocol)
+	}
+	if hc.Interval < 0 {
+		return fmt.Errorf("Interval cannot be negative")
+	}
+	if hc.Timeout < 0 {
+		return fmt.Errorf("Timeout cannot be negative")
+	}
+	return nil
+}
+
+const (
+	// DestinationDNSDiscoveryLogical only uses the first address a hostname
+	// resolves to.
+	DestinationDNSDiscoveryLogical = "logical"
+
+	// DestinationDNSDiscoveryStrict load balances across every address a
+	// hostname resolves to.
+	DestinationDNSDiscoveryStrict = "strict"
+)
+
+// DestinationDNSConfig configures the re-resolution of hostname Destinations
+// by terminating gateways.
+type DestinationDNSConfig struct {
+	// RefreshRate is the interval at which hostnames are re-resolved.
+	// Defaults to 10s.
+	RefreshRate time.Duration `json:",omitempty" alias:"refresh_rate"`
+
+	// RespectTTL uses the TTL of DNS records as the refresh rate instead of
+	// RefreshRate.
+	RespectTTL bool `json:",omitempty" alias:"respect_ttl"`
+
+	// DiscoveryType is one of "logical" or "strict". Defaults to "logical".
+	DiscoveryType string `json:",omitempty" alias:"discovery_type"`
+}
+
+func (c *DestinationDNSConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.DiscoveryType {
+	case "", DestinationDNSDiscoveryLogical, DestinationDNSDiscoveryStrict:
+	default:
+		return fmt.Errorf("unsupported discovery type %q, must be one of 'logical' or 'strict'", c.DiscoveryType)
+	}
+	if c.RefreshRate < 0 {
+		return fmt.Errorf("RefreshRate cannot be negative")
+	}
+	return nil
 }
 
 func IsHostname(address string) bool {
@@ -357,6 +539,12 @@ func IsIP(address string) bool {
 	return ip != nil
 }
 
+// IsWildcardHostname returns true if the address is a hostname with a
+// wildcard as its left-most label, e.g. "*.example.com".
+func IsWildcardHostname(address string) bool {
+	return strings.HasPrefix(address, wildcardPrefix)
+}
+
 // ProxyConfigEntry is the top-level struct for global proxy configuration defaults.
 type ProxyConfigEntry struct {
 	Kind             string
@@ -369,6 +557,10 @@ type ProxyConfigEntry struct {
 	AccessLogs       AccessLogsConfig       `json:",omitempty" alias:"access_logs"`
 	EnvoyExtensions  EnvoyExtensions        `json:",omitempty" alias:"envoy_extensions"`
 
+	// RetryPolicy is the default retry policy of the routes to all the
+	// services of the partition.
+	RetryPolicy *RouteRetryPolicy `json:",omitempty" alias:"retry_policy"`
+
 	Meta               map[string]string `json:",omitempty"`
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	RaftIndex
@@ -426,6 +618,12 @@ func (e *ProxyConfigEntry) Validate() error {
 		return err
 	}
 
+	if e.RetryPolicy != nil {
+		if err := e.RetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+
 	if err := validateConfigEntryMeta(e.Meta); err != nil {
 		return err
 	}
@@ -434,6 +632,10 @@ func (e *ProxyConfigEntry) Validate() error {
 		return err
 	}
 
+	if err := validateEnvoyRuntimeFlagsConfig(e.Config); err != nil {
+		return err
+	}
+
 	return e.validateEnterpriseMeta()
 }
 
@@ -677,6 +879,10 @@ func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &HTTPRouteConfigEntry{Name: name}, nil
 	case TCPRoute:
 		return &TCPRouteConfigEntry{Name: name}, nil
+	case APIGatewayPolicy:
+		return &APIGatewayPolicyConfigEntry{Name: name}, nil
+	case SamenessGroup:
+		return &SamenessGroupConfigEntry{Name: name}, nil
 	default:
 		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
 	}
@@ -690,6 +896,10 @@ type ConfigEntryQuery struct {
 
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	QueryOptions
+
+	// PageOptions paginates the list of the config entries of a kind, and is
+	// ignored by the lookups of a single entry.
+	PageOptions
 }
 
 func (c *ConfigEntryQuery) RequestDatacenter() string {
@@ -711,6 +921,7 @@ func (r *ConfigEntryQuery) CacheInfo() cache.RequestInfo {
 		r.Name,
 		r.Filter,
 		r.EnterpriseMeta,
+		r.PageOptions,
 	}, nil)
 	if err == nil {
 		// If there is an error, we don't set the key. A blank key forces
@@ -770,6 +981,10 @@ func (s *ServiceConfigRequest) RequestDatacenter() string {
 	return s.Datacenter
 }
 
+func (s *ServiceConfigRequest) RequestServiceName() string {
+	return s.Name
+}
+
 // GetLocalUpstreamIDs returns the list of non-peer service ids for upstreams defined on this request.
 // This is often used for fetching service-defaults config entries.
 func (s *ServiceConfigRequest) GetLocalUpstreamIDs() []ServiceID {
@@ -878,6 +1093,16 @@ type UpstreamConfig struct {
 	// BalanceOutboundConnections indicates how the proxy should attempt to distribute
 	// connections across worker threads. Only used by envoy proxies.
 	BalanceOutboundConnections string `json:",omitempty" alias:"balance_outbound_connections"`
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams the
+	// proxy opens on each HTTP/2 connection to the upstream. Only used for the
+	// http2 and grpc protocols. Defaults to the Envoy default if not set.
+	MaxConcurrentStreams int `json:",omitempty" alias:"max_concurrent_streams"`
+
+	// MaxRequestsPerConnection is the maximum number of requests the proxy
+	// sends on each connection to the upstream before closing it. Only used
+	// for HTTP-like protocols. Unlimited if not set.
+	MaxRequestsPerConnection int `json:",omitempty" alias:"max_requests_per_connection"`
 }
 
 func (cfg UpstreamConfig) Clone() UpstreamConfig {
@@ -925,6 +1150,12 @@ func (cfg UpstreamConfig) MergeInto(dst map[string]interface{}) {
 	if cfg.BalanceOutboundConnections != "" {
 		dst["balance_outbound_connections"] = cfg.BalanceOutboundConnections
 	}
+	if cfg.MaxConcurrentStreams != 0 {
+		dst["max_concurrent_streams"] = cfg.MaxConcurrentStreams
+	}
+	if cfg.MaxRequestsPerConnection != 0 {
+		dst["max_requests_per_connection"] = cfg.MaxRequestsPerConnection
+	}
 }
 
 func (cfg *UpstreamConfig) NormalizeWithoutName() error {
@@ -998,6 +1229,14 @@ func (cfg UpstreamConfig) validate(named bool) error {
 		validationErr = multierror.Append(validationErr, fmt.Errorf("invalid value for balance_outbound_connections: %v", cfg.BalanceOutboundConnections))
 	}
 
+	if cfg.MaxConcurrentStreams < 0 {
+		validationErr = multierror.Append(validationErr, fmt.Errorf("max concurrent streams cannot be negative"))
+	}
+
+	if cfg.MaxRequestsPerConnection < 0 {
+		validationErr = multierror.Append(validationErr, fmt.Errorf("max requests per connection cannot be negative"))
+	}
+
 	return validationErr
 }
 
@@ -1044,6 +1283,36 @@ func ParseUpstreamConfig(m map[string]interface{}) (UpstreamConfig, error) {
 	return cfg, err
 }
 
+// UpstreamConnectionPool holds the connection settings that the proxies of
+// the downstreams of a service use by default for its upstream clusters.
+type UpstreamConnectionPool struct {
+	// ConnectTimeoutMs is the number of milliseconds to timeout making a new
+	// connection to the service. The connect timeout of the service-resolver
+	// of the service takes precedence over it.
+	ConnectTimeoutMs int `json:",omitempty" alias:"connect_timeout_ms"`
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams the
+	// proxies open on each HTTP/2 connection to the service.
+	MaxConcurrentStreams int `json:",omitempty" alias:"max_concurrent_streams"`
+
+	// MaxRequestsPerConnection is the maximum number of requests the proxies
+	// send on each connection to the service before closing it.
+	MaxRequestsPerConnection int `json:",omitempty" alias:"max_requests_per_connection"`
+}
+
+func (p *UpstreamConnectionPool) Validate() error {
+	if p.ConnectTimeoutMs < 0 {
+		return fmt.Errorf("connect timeout cannot be negative")
+	}
+	if p.MaxConcurrentStreams < 0 {
+		return fmt.Errorf("max concurrent streams cannot be negative")
+	}
+	if p.MaxRequestsPerConnection < 0 {
+		return fmt.Errorf("max requests per connection cannot be negative")
+	}
+	return nil
+}
+
 type PassiveHealthCheck struct {
 	// Interval between health check analysis sweeps. Each sweep may remove
 	// hosts or return hosts to the pool.
@@ -0,0 +1,170 @@
+package structs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// TestValidateConfigEntry_MatchesAPI runs the same config entries through the
+// client-side validation of the api package and the validation of the
+// servers, so that the api package doesn't drift when the servers enforce new
+// rules.
+func TestValidateConfigEntry_MatchesAPI(t *testing.T) {
+	cases := map[string]struct {
+		entry api.ConfigEntry
+		// err is the error reported by both validations, empty if the entry is
+		// valid.
+		err string
+	}{
+		"service-defaults valid": {
+			entry: &api.ServiceConfigEntry{
+				Kind:     api.ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				RetryPolicy: &api.RouteRetryPolicy{
+					NumRetries:         3,
+					RetryOn:            []string{"connect-failure", "reset"},
+					RetryOnStatusCodes: []uint32{503},
+					PerTryTimeout:      2 * time.Second,
+				},
+			},
+		},
+		"service-defaults wildcard": {
+			entry: &api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "*"},
+			err:   "service-defaults name must be the name of a service, and not a wildcard",
+		},
+		"service-defaults retry policy without conditions": {
+			entry: &api.ServiceConfigEntry{
+				Kind:        api.ServiceDefaults,
+				Name:        "web",
+				RetryPolicy: &api.RouteRetryPolicy{NumRetries: 3},
+			},
+			err: "invalid retry policy: at least one of RetryOn or RetryOnStatusCodes must be set",
+		},
+		"service-defaults retry policy condition": {
+			entry: &api.ServiceConfigEntry{
+				Kind:        api.ServiceDefaults,
+				Name:        "web",
+				RetryPolicy: &api.RouteRetryPolicy{RetryOn: []string{"timeout"}},
+			},
+			err: `invalid retry policy: invalid retry condition: "timeout"`,
+		},
+		"service-defaults upstream connection pool": {
+			entry: &api.ServiceConfigEntry{
+				Kind:                   api.ServiceDefaults,
+				Name:                   "web",
+				UpstreamConnectionPool: &api.UpstreamConnectionPool{MaxConcurrentStreams: -1},
+			},
+			err: "max concurrent streams cannot be negative",
+		},
+		"proxy-defaults valid": {
+			entry: &api.ProxyConfigEntry{
+				Kind:        api.ProxyDefaults,
+				Name:        api.ProxyConfigGlobal,
+				RetryPolicy: &api.RouteRetryPolicy{RetryOnStatusCodes: []uint32{503}},
+			},
+		},
+		"proxy-defaults retry policy per try timeout": {
+			entry: &api.ProxyConfigEntry{
+				Kind:        api.ProxyDefaults,
+				Name:        api.ProxyConfigGlobal,
+				RetryPolicy: &api.RouteRetryPolicy{RetryOn: []string{"5xx"}, PerTryTimeout: -time.Second},
+			},
+			err: "invalid retry policy: PerTryTimeout must be greater than or equal to 0s, got -1s",
+		},
+		"service-resolver valid": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:          api.ServiceResolver,
+				Name:          "web",
+				DefaultSubset: "v1",
+				Subsets: map[string]api.ServiceResolverSubset{
+					"v1": {Filter: "Service.Meta.version == v1"},
+					"v2": {Filter: "Service.Meta.shard == eu", Meta: map[string]string{"version": "v2", "consul-owner": "team"}},
+				},
+			},
+		},
+		"service-resolver subset name": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:    api.ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]api.ServiceResolverSubset{"V1": {}},
+			},
+			err: `Subset "V1" is invalid`,
+		},
+		"service-resolver subset meta key": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:    api.ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]api.ServiceResolverSubset{"v2": {Meta: map[string]string{"version.major": "2"}}},
+			},
+			err: `Meta for subset "v2" is invalid: invalid meta pair ("version.major", "2"): Key contains invalid characters`,
+		},
+		"service-resolver subset meta key too long": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:    api.ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]api.ServiceResolverSubset{"v2": {Meta: map[string]string{strings.Repeat("k", 129): "2"}}},
+			},
+			err: "Key is too long (limit: 128 characters)",
+		},
+		"service-resolver subset meta value": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:    api.ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]api.ServiceResolverSubset{"v2": {Meta: map[string]string{"version": "`v2\""}}},
+			},
+			err: "Value cannot contain both double quotes and backticks",
+		},
+		"service-resolver unknown default subset": {
+			entry: &api.ServiceResolverConfigEntry{
+				Kind:          api.ServiceResolver,
+				Name:          "web",
+				DefaultSubset: "v1",
+			},
+			err: `DefaultSubset "v1" is not a valid subset`,
+		},
+		"service-splitter weights": {
+			entry: &api.ServiceSplitterConfigEntry{
+				Kind:   api.ServiceSplitter,
+				Name:   "web",
+				Splits: []api.ServiceSplit{{Weight: 90, ServiceSubset: "v1"}, {Weight: 9, ServiceSubset: "v2"}},
+			},
+			err: "the sum of all split weights must be 100",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			apiErr := api.ValidateConfigEntry(tc.entry)
+
+			b, err := json.Marshal(tc.entry)
+			require.NoError(t, err)
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal(b, &raw))
+			entry, err := DecodeConfigEntry(raw)
+			require.NoError(t, err)
+
+			serverErr := entry.Normalize()
+			if serverErr == nil {
+				serverErr = entry.Validate()
+			}
+
+			if tc.err == "" {
+				require.NoError(t, apiErr)
+				require.NoError(t, serverErr)
+				return
+			}
+			require.Error(t, apiErr)
+			require.Contains(t, apiErr.Error(), tc.err)
+			require.Error(t, serverErr)
+			require.Contains(t, serverErr.Error(), tc.err)
+		})
+	}
+}
@@ -504,6 +504,60 @@ func (d *ServiceRouteDestination) HasRetryFeatures() bool {
 	return d.NumRetries > 0 || d.RetryOnConnectFailure || len(d.RetryOnStatusCodes) > 0 || len(d.RetryOn) > 0
 }
 
+// RouteRetryPolicy is the default retry policy of the routes to a service. It
+// is set in the service-defaults or proxy-defaults config entries and applies
+// to all the routes of the discovery chain of the service, except the ones of
+// service-router destinations that configure their own retries.
+type RouteRetryPolicy struct {
+	// NumRetries is the number of times to retry the request when a retryable
+	// result occurs.
+	NumRetries uint32 `json:",omitempty" alias:"num_retries"`
+
+	// RetryOn is the list of conditions that trigger a retry. It supports the
+	// same conditions as the RetryOn field of service-router destinations.
+	RetryOn []string `json:",omitempty" alias:"retry_on"`
+
+	// RetryOnStatusCodes is a flat list of http response status codes that are
+	// eligible for retry.
+	RetryOnStatusCodes []uint32 `json:",omitempty" alias:"retry_on_status_codes"`
+
+	// PerTryTimeout is the timeout of each attempt, including the first one.
+	// The RequestTimeout of service-router destinations still bounds the
+	// whole request, retries included.
+	PerTryTimeout time.Duration `json:",omitempty" alias:"per_try_timeout"`
+}
+
+func (p *RouteRetryPolicy) Clone() *RouteRetryPolicy {
+	if p == nil {
+		return nil
+	}
+	p2 := *p
+	if p.RetryOn != nil {
+		p2.RetryOn = make([]string, len(p.RetryOn))
+		copy(p2.RetryOn, p.RetryOn)
+	}
+	if p.RetryOnStatusCodes != nil {
+		p2.RetryOnStatusCodes = make([]uint32, len(p.RetryOnStatusCodes))
+		copy(p2.RetryOnStatusCodes, p.RetryOnStatusCodes)
+	}
+	return &p2
+}
+
+func (p *RouteRetryPolicy) Validate() error {
+	if len(p.RetryOn) == 0 && len(p.RetryOnStatusCodes) == 0 {
+		return fmt.Errorf("at least one of RetryOn or RetryOnStatusCodes must be set")
+	}
+	for _, r := range p.RetryOn {
+		if !isValidRetryCondition(r) {
+			return fmt.Errorf("invalid retry condition: %q", r)
+		}
+	}
+	if p.PerTryTimeout < 0 {
+		return fmt.Errorf("PerTryTimeout must be greater than or equal to 0s, got %s", p.PerTryTimeout)
+	}
+	return nil
+}
+
 // ServiceSplitterConfigEntry defines how incoming requests are split across
 // different subsets of a single service (like during staged canary rollouts),
 // or perhaps across different services (like during a v2 rewrite or other type
@@ -975,6 +1029,9 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 					return fmt.Errorf("Filter for subset %q is not a valid expression: %v", name, err)
 				}
 			}
+			if err := subset.validateMeta(); err != nil {
+				return fmt.Errorf("Meta for subset %q is invalid: %v", name, err)
+			}
 		}
 	}
 
@@ -1059,7 +1116,7 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 			}
 
 			if f.isEmpty() {
-				return fmt.Errorf(errorPrefix + "one of Service, ServiceSubset, Namespace, Targets, or Datacenters is required")
+				return fmt.Errorf(errorPrefix + "one of Service, ServiceSubset, Namespace, Targets, Datacenters, or SamenessGroup is required")
 			}
 
 			if f.ServiceSubset != "" {
@@ -1082,6 +1139,10 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 				return fmt.Errorf("Bad Failover[%q]: Targets cannot be set with Service", subset)
 			}
 
+			if f.SamenessGroup != "" && (f.Service != "" || f.ServiceSubset != "" || len(f.Datacenters) != 0 || len(f.Targets) != 0) {
+				return fmt.Errorf("Bad Failover[%q]: SamenessGroup cannot be set with Service, ServiceSubset, Datacenters, or Targets", subset)
+			}
+
 			for i, target := range f.Targets {
 				errorPrefix := fmt.Sprintf("Bad Failover[%q].Targets[%d]: ", subset, i)
 
@@ -1089,6 +1150,10 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 					return fmt.Errorf(errorPrefix + err.Error())
 				}
 
+				if target.Priority < 0 {
+					return fmt.Errorf(errorPrefix + "Priority cannot be negative")
+				}
+
 				switch {
 				case target.Peer != "" && target.ServiceSubset != "":
 					return fmt.Errorf(errorPrefix + "Peer cannot be set with ServiceSubset")
@@ -1254,6 +1319,12 @@ type ServiceResolverSubset struct {
 	// instances of the requested service.
 	Filter string `json:",omitempty"`
 
+	// Meta specifies the service meta key/value pairs that the instances of the
+	// requested service must all have to be selected. It can be combined with
+	// Filter, and unlike arbitrary filter expressions, is resolved using the
+	// catalog indexes where possible.
+	Meta map[string]string `json:",omitempty"`
+
 	// OnlyPassing - Specifies the behavior of the resolver's health check
 	// filtering. If this is set to false, the results will include instances
 	// with checks in the passing as well as the warning states. If this is set
@@ -1263,6 +1334,53 @@ type ServiceResolverSubset struct {
 	OnlyPassing bool `json:",omitempty" alias:"only_passing"`
 }
 
+// FilterExpression returns the go-bexpr filter expression selecting the
+// instances of the subset, which combines the service meta matches with the
+// filter expression of the subset.
+func (s ServiceResolverSubset) FilterExpression() string {
+	if len(s.Meta) == 0 {
+		return s.Filter
+	}
+
+	keys := make([]string, 0, len(s.Meta))
+	for key := range s.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	matches := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		matches = append(matches, fmt.Sprintf("Service.Meta[%s] == %s", strconv.Quote(key), bexprStringLiteral(s.Meta[key])))
+	}
+	if s.Filter != "" {
+		matches = append(matches, "("+s.Filter+")")
+	}
+	return strings.Join(matches, " and ")
+}
+
+// validateMeta validates the service meta key/value pairs of the subset, which
+// must be valid service meta and representable in a filter expression.
+func (s ServiceResolverSubset) validateMeta() error {
+	for key, value := range s.Meta {
+		if err := validateMetaPair(key, value, true, nil); err != nil {
+			return fmt.Errorf("invalid meta pair (%q, %q): %v", key, value, err)
+		}
+		if strings.Contains(value, `"`) && strings.Contains(value, "`") {
+			return fmt.Errorf("invalid meta pair (%q, %q): Value cannot contain both double quotes and backticks", key, value)
+		}
+	}
+	return nil
+}
+
+// bexprStringLiteral returns the given string as a go-bexpr string literal,
+// which can't contain escaped double quotes.
+func bexprStringLiteral(s string) string {
+	if strings.Contains(s, `"`) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
 type ServiceResolverRedirect struct {
 	// Service is a service to resolve instead of the current service
 	// (optional).
@@ -1310,9 +1428,10 @@ func (r *ServiceResolverRedirect) isEmpty() bool {
 
 // There are some restrictions on what is allowed in here:
 //
-// - Service, ServiceSubset, Namespace, Datacenters, and Targets cannot all be
-// empty at once. When Targets is defined, the other fields should not be
-// populated.
+// - Service, ServiceSubset, Namespace, Datacenters, Targets, and SamenessGroup
+// cannot all be empty at once. When Targets is defined, the other fields
+// should not be populated. SamenessGroup cannot be used with Service,
+// ServiceSubset, Datacenters, or Targets.
 type ServiceResolverFailover struct {
 	// Service is the service to resolve instead of the default as the failover
 	// group of instances (optional).
@@ -1347,6 +1466,13 @@ type ServiceResolverFailover struct {
 	//
 	// This is a DESTINATION during failover.
 	Targets []ServiceResolverFailoverTarget `json:",omitempty"`
+
+	// SamenessGroup is the name of a sameness group whose members are tried,
+	// in order, as failover targets. The same service is resolved from each
+	// member cluster peer.
+	//
+	// This is a DESTINATION during failover.
+	SamenessGroup string `json:",omitempty" alias:"sameness_group"`
 }
 
 func (t *ServiceResolverFailover) ToDiscoveryTargetOpts() DiscoveryTargetOpts {
@@ -1358,7 +1484,7 @@ func (t *ServiceResolverFailover) ToDiscoveryTargetOpts() DiscoveryTargetOpts {
 }
 
 func (f *ServiceResolverFailover) isEmpty() bool {
-	return f.Service == "" && f.ServiceSubset == "" && f.Namespace == "" && len(f.Datacenters) == 0 && len(f.Targets) == 0
+	return f.Service == "" && f.ServiceSubset == "" && f.Namespace == "" && len(f.Datacenters) == 0 && len(f.Targets) == 0 && f.SamenessGroup == ""
 }
 
 type ServiceResolverFailoverTarget struct {
@@ -1379,6 +1505,12 @@ type ServiceResolverFailoverTarget struct {
 
 	// Peer specifies the name of the cluster peer to try during failover.
 	Peer string `json:",omitempty"`
+
+	// Priority specifies the failover group of the target. Groups are tried
+	// in ascending order of priority. Within a group, peer targets are tried
+	// in order of the health of their imported instances, healthiest first.
+	// If no target sets a priority, targets are tried in the order given.
+	Priority int `json:",omitempty"`
 }
 
 func (t *ServiceResolverFailoverTarget) ToDiscoveryTargetOpts() DiscoveryTargetOpts {
@@ -1552,6 +1684,10 @@ func (r *DiscoveryChainRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *DiscoveryChainRequest) RequestServiceName() string {
+	return r.Name
+}
+
 func (r *DiscoveryChainRequest) CacheInfo() cache.RequestInfo {
 	info := cache.RequestInfo{
 		Token:          r.Token,
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-bexpr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -1221,6 +1222,41 @@ func TestServiceResolverConfigEntry(t *testing.T) {
 			},
 			validateErr: `Filter for subset "v1" is not a valid expression`,
 		},
+		{
+			name: "subset meta",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Subsets: map[string]ServiceResolverSubset{
+					"v2": {
+						Filter: "Service.Meta.shard == eu",
+						Meta:   map[string]string{"version": "v2"},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid subset meta key",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Subsets: map[string]ServiceResolverSubset{
+					"v2": {Meta: map[string]string{"version.major": "2"}},
+				},
+			},
+			validateErr: `Meta for subset "v2" is invalid: invalid meta pair ("version.major", "2"): Key contains invalid characters`,
+		},
+		{
+			name: "invalid subset meta value",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Subsets: map[string]ServiceResolverSubset{
+					"v2": {Meta: map[string]string{"version": "`v2\""}},
+				},
+			},
+			validateErr: `Value cannot contain both double quotes and backticks`,
+		},
 		{
 			name: "default subset does not exist",
 			entry: &ServiceResolverConfigEntry{
@@ -1397,7 +1433,7 @@ func TestServiceResolverConfigEntry(t *testing.T) {
 					"v1": {},
 				},
 			},
-			validateErr: `Bad Failover["v1"]: one of Service, ServiceSubset, Namespace, Targets, or Datacenters is required`,
+			validateErr: `Bad Failover["v1"]: one of Service, ServiceSubset, Namespace, Targets, Datacenters, or SamenessGroup is required`,
 		},
 		{
 			name: "failover to self using invalid subset",
@@ -1486,6 +1522,19 @@ func TestServiceResolverConfigEntry(t *testing.T) {
 			},
 			validateErr: `Bad Failover["*"].Targets[0]: Peer cannot be set with Datacenter`,
 		},
+		{
+			name: "failover targets can't have negative Priority",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Failover: map[string]ServiceResolverFailover{
+					"*": {
+						Targets: []ServiceResolverFailoverTarget{{Peer: "cluster-01", Priority: -1}},
+					},
+				},
+			},
+			validateErr: `Bad Failover["*"].Targets[0]: Priority cannot be negative`,
+		},
 		{
 			name: "failover Targets cannot be set with Datacenters",
 			entry: &ServiceResolverConfigEntry{
@@ -1534,6 +1583,30 @@ func TestServiceResolverConfigEntry(t *testing.T) {
 			},
 			validateErr: `Bad Failover["*"]: Targets cannot be set with Service`,
 		},
+		{
+			name: "failover SamenessGroup",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Failover: map[string]ServiceResolverFailover{
+					"*": {SamenessGroup: "group"},
+				},
+			},
+		},
+		{
+			name: "failover SamenessGroup cannot be set with Targets",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				Failover: map[string]ServiceResolverFailover{
+					"*": {
+						SamenessGroup: "group",
+						Targets:       []ServiceResolverFailoverTarget{{Peer: "cluster-01"}},
+					},
+				},
+			},
+			validateErr: `Bad Failover["*"]: SamenessGroup cannot be set with Service, ServiceSubset, Datacenters, or Targets`,
+		},
 		{
 			name: "complicated failover targets",
 			entry: &ServiceResolverConfigEntry{
@@ -2781,6 +2854,54 @@ func TestValidateServiceSubset(t *testing.T) {
 	}
 }
 
+func TestServiceResolverSubset_FilterExpression(t *testing.T) {
+	cases := map[string]struct {
+		subset   ServiceResolverSubset
+		expected string
+	}{
+		"empty": {},
+		"filter only": {
+			subset:   ServiceResolverSubset{Filter: "Service.Meta.version == v1"},
+			expected: "Service.Meta.version == v1",
+		},
+		"meta only": {
+			subset: ServiceResolverSubset{
+				Meta: map[string]string{"version": "v2", "shard": "eu"},
+			},
+			expected: `Service.Meta["shard"] == "eu" and Service.Meta["version"] == "v2"`,
+		},
+		"meta and filter": {
+			subset: ServiceResolverSubset{
+				Filter: `"canary" in Service.Tags or Service.Meta.env == dev`,
+				Meta:   map[string]string{"version": `v"2`},
+			},
+			expected: "Service.Meta[\"version\"] == `v\"2` and (\"canary\" in Service.Tags or Service.Meta.env == dev)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expr := tc.subset.FilterExpression()
+			require.Equal(t, tc.expected, expr)
+
+			// The instances with the meta of the subset must match.
+			if len(tc.subset.Meta) == 0 {
+				return
+			}
+			filter, err := bexpr.CreateFilter(expr, nil, CheckServiceNodes{})
+			require.NoError(t, err)
+
+			matching := CheckServiceNode{Service: &NodeService{Meta: map[string]string{}, Tags: []string{"canary"}}}
+			for k, v := range tc.subset.Meta {
+				matching.Service.Meta[k] = v
+			}
+			result, err := filter.Execute(CheckServiceNodes{matching, {Service: &NodeService{Tags: []string{"canary"}}}})
+			require.NoError(t, err)
+			require.Equal(t, CheckServiceNodes{matching}, result)
+		})
+	}
+}
+
 func TestIsProtocolHTTPLike(t *testing.T) {
 	assert.False(t, IsProtocolHTTPLike(""))
 	assert.False(t, IsProtocolHTTPLike("tcp"))
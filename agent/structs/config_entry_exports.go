@@ -30,8 +30,38 @@ type ExportedService struct {
 	// Namespace is the namespace to export the service from.
 	Namespace string `json:",omitempty"`
 
+	// Exclude is a list of service names in the namespace that are not
+	// exported. It may only be set when Name is the wildcard.
+	Exclude []string `json:",omitempty"`
+
 	// Consumers is a list of downstream consumers of the service to be exported.
 	Consumers []ServiceConsumer `json:",omitempty"`
+
+	// ConfigEntries is a list of config entry kinds that are replicated
+	// alongside the service to the peers consuming it. Only service-defaults,
+	// service-resolver and service-intentions entries can be replicated.
+	ConfigEntries []string `json:",omitempty" alias:"config_entries"`
+}
+
+// Excludes returns true if the service with the given name is excluded from
+// a wildcard export.
+func (s ExportedService) Excludes(name string) bool {
+	for _, excluded := range s.Exclude {
+		if excluded == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReplicableConfigEntryKind returns true if config entries of the given
+// kind can be replicated to peers alongside an exported service.
+func IsReplicableConfigEntryKind(kind string) bool {
+	switch kind {
+	case ServiceDefaults, ServiceResolver, ServiceIntentions:
+		return true
+	}
+	return false
 }
 
 // ServiceConsumer represents a downstream consumer of the service to be exported.
@@ -43,6 +73,36 @@ type ServiceConsumer struct {
 
 	// Peer is the name of the peer to export the service to.
 	Peer string `json:",omitempty" alias:"peer_name"`
+
+	// RedactFields is a list of instance data fields that are removed from
+	// the service before it is replicated to the peer. It may only be set
+	// when Peer is set.
+	RedactFields []string `json:",omitempty" alias:"redact_fields"`
+}
+
+const (
+	// RedactFieldNodeMeta removes the metadata of the nodes running the service.
+	RedactFieldNodeMeta = "NodeMeta"
+
+	// RedactFieldNodeTaggedAddresses removes the tagged addresses of the
+	// nodes running the service.
+	RedactFieldNodeTaggedAddresses = "NodeTaggedAddresses"
+
+	// RedactFieldServiceMeta removes the metadata of the service instances.
+	RedactFieldServiceMeta = "ServiceMeta"
+
+	// RedactFieldServiceTags removes the tags of the service instances.
+	RedactFieldServiceTags = "ServiceTags"
+)
+
+// IsRedactableField returns true if the given instance data field can be
+// redacted from services exported to a peer.
+func IsRedactableField(field string) bool {
+	switch field {
+	case RedactFieldNodeMeta, RedactFieldNodeTaggedAddresses, RedactFieldServiceMeta, RedactFieldServiceTags:
+		return true
+	}
+	return false
 }
 
 func (e *ExportedServicesConfigEntry) ToMap() map[string]map[string][]string {
@@ -69,8 +129,11 @@ func (e *ExportedServicesConfigEntry) Clone() *ExportedServicesConfigEntry {
 		exportedSvc := svc
 		exportedSvc.Consumers = make([]ServiceConsumer, len(svc.Consumers))
 		for _, consumer := range svc.Consumers {
+			consumer.RedactFields = append([]string(nil), consumer.RedactFields...)
 			exportedSvc.Consumers = append(exportedSvc.Consumers, consumer)
 		}
+		exportedSvc.Exclude = append([]string(nil), svc.Exclude...)
+		exportedSvc.ConfigEntries = append([]string(nil), svc.ConfigEntries...)
 		e2.Services = append(e2.Services, exportedSvc)
 	}
 
@@ -126,6 +189,14 @@ func (e *ExportedServicesConfigEntry) Validate() error {
 		if svc.Namespace == WildcardSpecifier && svc.Name != WildcardSpecifier {
 			return fmt.Errorf("Services[%d]: service name must be wildcard if namespace is wildcard", i)
 		}
+		if len(svc.Exclude) > 0 && svc.Name != WildcardSpecifier {
+			return fmt.Errorf("Services[%d]: service name must be wildcard if services are excluded", i)
+		}
+		for j, name := range svc.Exclude {
+			if name == "" || name == WildcardSpecifier {
+				return fmt.Errorf("Services[%d].Exclude[%d]: excluded service name must be a non-empty, non-wildcard name", i, j)
+			}
+		}
 		if len(svc.Consumers) == 0 {
 			return fmt.Errorf("Services[%d]: must have at least one consumer", i)
 		}
@@ -139,6 +210,19 @@ func (e *ExportedServicesConfigEntry) Validate() error {
 			if consumer.Peer == WildcardSpecifier {
 				return fmt.Errorf("Services[%d].Consumers[%d]: exporting to all peers (wildcard) is not supported", i, j)
 			}
+			if len(consumer.RedactFields) > 0 && consumer.Peer == "" {
+				return fmt.Errorf("Services[%d].Consumers[%d]: fields can only be redacted for peer consumers", i, j)
+			}
+			for k, field := range consumer.RedactFields {
+				if !IsRedactableField(field) {
+					return fmt.Errorf("Services[%d].Consumers[%d].RedactFields[%d]: field %q cannot be redacted", i, j, k, field)
+				}
+			}
+		}
+		for j, kind := range svc.ConfigEntries {
+			if !IsReplicableConfigEntryKind(kind) {
+				return fmt.Errorf("Services[%d].ConfigEntries[%d]: config entries of kind %q cannot be replicated to peers", i, j, kind)
+			}
 		}
 	}
 	return nil
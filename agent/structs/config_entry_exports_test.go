@@ -88,6 +88,123 @@ func TestExportedServicesConfigEntry(t *testing.T) {
 			},
 			validateErr: `Services[0].Consumers[0]: must define at most one of Peer or Partition`,
 		},
+		"validate: exclude requires wildcard service name": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name:    "web",
+						Exclude: []string{"admin"},
+						Consumers: []ServiceConsumer{
+							{
+								Peer: "foo",
+							},
+						},
+					},
+				},
+			},
+			validateErr: `Services[0]: service name must be wildcard if services are excluded`,
+		},
+		"validate: no wildcard in exclude": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name:    "*",
+						Exclude: []string{"admin", "*"},
+						Consumers: []ServiceConsumer{
+							{
+								Peer: "foo",
+							},
+						},
+					},
+				},
+			},
+			validateErr: `Services[0].Exclude[1]: excluded service name must be a non-empty, non-wildcard name`,
+		},
+		"validate: wildcard with exclude": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name:    "*",
+						Exclude: []string{"admin"},
+						Consumers: []ServiceConsumer{
+							{
+								Peer: "foo",
+							},
+						},
+					},
+				},
+			},
+		},
+		"validate: config entry kind cannot be replicated": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name: "web",
+						Consumers: []ServiceConsumer{
+							{
+								Peer: "foo",
+							},
+						},
+						ConfigEntries: []string{ServiceDefaults, ProxyDefaults},
+					},
+				},
+			},
+			validateErr: `Services[0].ConfigEntries[1]: config entries of kind "proxy-defaults" cannot be replicated to peers`,
+		},
+		"validate: redacted fields": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name: "web",
+						Consumers: []ServiceConsumer{
+							{
+								Peer:         "foo",
+								RedactFields: []string{RedactFieldNodeMeta, RedactFieldServiceMeta},
+							},
+						},
+					},
+				},
+			},
+		},
+		"validate: redacted fields require a peer consumer": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name: "web",
+						Consumers: []ServiceConsumer{
+							{
+								Partition:    "foo",
+								RedactFields: []string{RedactFieldNodeMeta},
+							},
+						},
+					},
+				},
+			},
+			validateErr: `Services[0].Consumers[0]: fields can only be redacted for peer consumers`,
+		},
+		"validate: unknown redacted field": {
+			entry: &ExportedServicesConfigEntry{
+				Name: "default",
+				Services: []ExportedService{
+					{
+						Name: "web",
+						Consumers: []ServiceConsumer{
+							{
+								Peer:         "foo",
+								RedactFields: []string{"Address"},
+							},
+						},
+					},
+				},
+			},
+			validateErr: `Services[0].Consumers[0].RedactFields[0]: field "Address" cannot be redacted`,
+		},
 	}
 
 	testConfigEntryNormalizeAndValidate(t, cases)
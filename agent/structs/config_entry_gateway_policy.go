@@ -0,0 +1,634 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/lib"
+)
+
+// APIGatewayPolicyConfigEntry manages the edge policies applied to the
+// listeners of an API gateway. The policy is attached to the api-gateway
+// config entry with the same name, in the same way as the bound-api-gateway.
+type APIGatewayPolicyConfigEntry struct {
+	// Kind of the config entry. This will be set to structs.APIGatewayPolicy.
+	Kind string
+
+	// Name is used to match the config entry with its associated API gateway.
+	Name string
+
+	// Listeners is the set of per-listener policies. Each entry refers to an
+	// api-gateway listener by name.
+	Listeners []APIGatewayListenerPolicy
+
+	Meta               map[string]string `json:",omitempty"`
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+// APIGatewayListenerPolicy contains the policies for a single listener of
+// an API gateway.
+type APIGatewayListenerPolicy struct {
+	// Name is the name of the api-gateway listener the policy applies to.
+	// An empty name refers to the gateway's unnamed listener.
+	Name string
+
+	// RateLimit configures the local and global rate limits enforced on
+	// requests received by the listener.
+	RateLimit *APIGatewayRateLimit `json:",omitempty" alias:"rate_limit"`
+
+	// JWT configures the verification of JSON Web Tokens on requests received
+	// by the listener.
+	JWT *APIGatewayJWTPolicy `json:",omitempty"`
+
+	// ExtAuthz configures an external authorization service that is consulted
+	// for requests received by the listener.
+	ExtAuthz *APIGatewayExtAuthz `json:",omitempty" alias:"ext_authz"`
+}
+
+// APIGatewayRateLimit configures rate limiting for an API gateway listener.
+type APIGatewayRateLimit struct {
+	// Local is a token bucket enforced by each gateway instance for all
+	// requests received by the listener.
+	Local *APIGatewayLocalRateLimit `json:",omitempty"`
+
+	// Routes are token buckets enforced by each gateway instance for the
+	// requests matching a specific hostname and/or path prefix. Route limits
+	// take precedence over the listener-wide Local limit.
+	Routes []APIGatewayRouteRateLimit `json:",omitempty"`
+
+	// Global configures an external rate limit service that is consulted
+	// for every request received by the listener.
+	Global *APIGatewayGlobalRateLimit `json:",omitempty"`
+}
+
+// APIGatewayLocalRateLimit is a token bucket used for local rate limiting.
+type APIGatewayLocalRateLimit struct {
+	// MaxTokens is the maximum number of tokens in the bucket, which is also
+	// the number of requests allowed in a burst.
+	MaxTokens uint32 `json:",omitempty" alias:"max_tokens"`
+
+	// TokensPerFill is the number of tokens added to the bucket on each fill.
+	// Defaults to 1.
+	TokensPerFill uint32 `json:",omitempty" alias:"tokens_per_fill"`
+
+	// FillInterval is the interval at which tokens are added to the bucket.
+	FillInterval time.Duration `json:",omitempty" alias:"fill_interval"`
+}
+
+func (l *APIGatewayLocalRateLimit) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayLocalRateLimit
+	exported := &struct {
+		FillInterval string `json:",omitempty"`
+		*Alias
+	}{
+		FillInterval: l.FillInterval.String(),
+		Alias:        (*Alias)(l),
+	}
+	if l.FillInterval == 0 {
+		exported.FillInterval = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (l *APIGatewayLocalRateLimit) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayLocalRateLimit
+	aux := &struct {
+		FillInterval string
+		*Alias
+	}{
+		Alias: (*Alias)(l),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.FillInterval != "" {
+		if l.FillInterval, err = time.ParseDuration(aux.FillInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayRouteRateLimit is a local rate limit applied to the routes of a
+// listener that match the given hostname and path prefix.
+type APIGatewayRouteRateLimit struct {
+	// Hostname restricts the limit to routes served for the given host. If
+	// empty, routes for every hostname on the listener are matched.
+	Hostname string `json:",omitempty"`
+
+	// PathPrefix restricts the limit to the requests to the given path or
+	// below it. Whole path segments are matched, so /api doesn't match
+	// /api-v2. If empty, every route is matched.
+	PathPrefix string `json:",omitempty" alias:"path_prefix"`
+
+	// Local is the token bucket enforced for the matched routes.
+	Local APIGatewayLocalRateLimit
+}
+
+// APIGatewayGlobalRateLimit configures an external rate limit service that
+// implements the Envoy rate limit gRPC API.
+type APIGatewayGlobalRateLimit struct {
+	// Domain is the rate limit domain reported to the rate limit service.
+	Domain string
+
+	// Address is the IP address or hostname of the rate limit service.
+	Address string
+
+	// Port is the gRPC port of the rate limit service.
+	Port int
+
+	// Timeout is the time to wait for a response from the rate limit
+	// service. Defaults to 20ms.
+	Timeout time.Duration `json:",omitempty"`
+
+	// FailureModeDeny rejects requests when the rate limit service cannot be
+	// reached, instead of allowing them through.
+	FailureModeDeny bool `json:",omitempty" alias:"failure_mode_deny"`
+}
+
+func (g *APIGatewayGlobalRateLimit) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayGlobalRateLimit
+	exported := &struct {
+		Timeout string `json:",omitempty"`
+		*Alias
+	}{
+		Timeout: g.Timeout.String(),
+		Alias:   (*Alias)(g),
+	}
+	if g.Timeout == 0 {
+		exported.Timeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (g *APIGatewayGlobalRateLimit) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayGlobalRateLimit
+	aux := &struct {
+		Timeout string
+		*Alias
+	}{
+		Alias: (*Alias)(g),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Timeout != "" {
+		if g.Timeout, err = time.ParseDuration(aux.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayJWTPolicy configures JWT authentication for an API gateway
+// listener. Requests must carry a token that is valid for at least one of
+// the providers, unless their path matches one of AllowMissingPaths.
+type APIGatewayJWTPolicy struct {
+	// Providers is the set of token issuers accepted by the listener.
+	Providers []APIGatewayJWTProvider
+
+	// AllowMissingPaths is a list of path prefixes on which requests without
+	// a token are allowed through. Tokens that are present are still verified.
+	// Whole path segments are matched, so /public doesn't match /public-admin.
+	AllowMissingPaths []string `json:",omitempty" alias:"allow_missing_paths"`
+}
+
+// APIGatewayJWTProvider describes an issuer of JSON Web Tokens.
+type APIGatewayJWTProvider struct {
+	// Name uniquely identifies the provider within the listener.
+	Name string
+
+	// Issuer is the expected value of the "iss" claim. If empty, the issuer
+	// is not checked.
+	Issuer string `json:",omitempty"`
+
+	// Audiences is the list of accepted values of the "aud" claim. If empty,
+	// the audience is not checked.
+	Audiences []string `json:",omitempty"`
+
+	// JSONWebKeySet is the source of the keys used to verify tokens.
+	JSONWebKeySet APIGatewayJSONWebKeySet `alias:"json_web_key_set"`
+
+	// Forward retains the token in the request forwarded to the upstream.
+	Forward bool `json:",omitempty"`
+
+	// ClaimsToHeaders copies the value of token claims into request headers
+	// forwarded to the upstream.
+	ClaimsToHeaders []APIGatewayJWTClaimToHeader `json:",omitempty" alias:"claims_to_headers"`
+}
+
+// APIGatewayJSONWebKeySet is the source of a JSON Web Key Set. Exactly one of
+// Local and RemoteURI must be set.
+type APIGatewayJSONWebKeySet struct {
+	// Local is an inline JSON Web Key Set.
+	Local string `json:",omitempty"`
+
+	// RemoteURI is the HTTP or HTTPS URI the key set is fetched from.
+	RemoteURI string `json:",omitempty" alias:"remote_uri"`
+
+	// CAFile is the path on the gateway's host of the CA certificates that
+	// verify the certificate of an HTTPS RemoteURI. Defaults to the
+	// gateway's envoy_jwks_ca_file proxy config, or else to the system bundle
+	// at /etc/ssl/certs/ca-certificates.crt.
+	CAFile string `json:",omitempty" alias:"ca_file"`
+
+	// CacheDuration is how long a fetched key set is cached. Defaults to
+	// 5 minutes.
+	CacheDuration time.Duration `json:",omitempty" alias:"cache_duration"`
+}
+
+func (k *APIGatewayJSONWebKeySet) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayJSONWebKeySet
+	exported := &struct {
+		CacheDuration string `json:",omitempty"`
+		*Alias
+	}{
+		CacheDuration: k.CacheDuration.String(),
+		Alias:         (*Alias)(k),
+	}
+	if k.CacheDuration == 0 {
+		exported.CacheDuration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (k *APIGatewayJSONWebKeySet) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayJSONWebKeySet
+	aux := &struct {
+		CacheDuration string
+		*Alias
+	}{
+		Alias: (*Alias)(k),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.CacheDuration != "" {
+		if k.CacheDuration, err = time.ParseDuration(aux.CacheDuration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayJWTClaimToHeader maps a token claim to a request header.
+type APIGatewayJWTClaimToHeader struct {
+	// Claim is the name of the claim. Nested claims are separated by a '.'.
+	Claim string
+
+	// Header is the name of the request header set to the claim value.
+	Header string
+}
+
+// APIGatewayExtAuthz configures an external authorization service that
+// implements the Envoy ext_authz API over gRPC or HTTP.
+type APIGatewayExtAuthz struct {
+	// Protocol is the protocol spoken by the authorization service. One of
+	// "grpc" or "http". Defaults to "grpc".
+	Protocol string `json:",omitempty"`
+
+	// Address is the IP address or hostname of the authorization service.
+	Address string
+
+	// Port is the port of the authorization service.
+	Port int
+
+	// Timeout is the time to wait for a response from the authorization
+	// service. Defaults to 200ms.
+	Timeout time.Duration `json:",omitempty"`
+
+	// PathPrefix is prepended to the path of the authorization requests sent
+	// to an HTTP authorization service.
+	PathPrefix string `json:",omitempty" alias:"path_prefix"`
+
+	// AllowedHeaders is the list of client request headers sent to an HTTP
+	// authorization service in addition to the default set.
+	AllowedHeaders []string `json:",omitempty" alias:"allowed_headers"`
+
+	// FailureModeAllow lets requests through when the authorization service
+	// cannot be reached, instead of rejecting them.
+	FailureModeAllow bool `json:",omitempty" alias:"failure_mode_allow"`
+
+	// DisabledPaths is a list of path prefixes for which the authorization
+	// service is not consulted. Whole path segments are matched, so /public
+	// doesn't match /public-admin.
+	DisabledPaths []string `json:",omitempty" alias:"disabled_paths"`
+}
+
+func (a *APIGatewayExtAuthz) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayExtAuthz
+	exported := &struct {
+		Timeout string `json:",omitempty"`
+		*Alias
+	}{
+		Timeout: a.Timeout.String(),
+		Alias:   (*Alias)(a),
+	}
+	if a.Timeout == 0 {
+		exported.Timeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (a *APIGatewayExtAuthz) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayExtAuthz
+	aux := &struct {
+		Timeout string
+		*Alias
+	}{
+		Alias: (*Alias)(a),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Timeout != "" {
+		if a.Timeout, err = time.ParseDuration(aux.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *APIGatewayPolicyConfigEntry) GetKind() string {
+	return APIGatewayPolicy
+}
+
+func (e *APIGatewayPolicyConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+	return e.Name
+}
+
+func (e *APIGatewayPolicyConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *APIGatewayPolicyConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.Kind = APIGatewayPolicy
+	e.EnterpriseMeta.Normalize()
+
+	for _, listener := range e.Listeners {
+		if listener.ExtAuthz != nil {
+			listener.ExtAuthz.Protocol = strings.ToLower(listener.ExtAuthz.Protocol)
+			if listener.ExtAuthz.Protocol == "" {
+				listener.ExtAuthz.Protocol = "grpc"
+			}
+		}
+		if listener.RateLimit == nil {
+			continue
+		}
+		for i, route := range listener.RateLimit.Routes {
+			listener.RateLimit.Routes[i].Hostname = strings.ToLower(route.Hostname)
+		}
+	}
+	return nil
+}
+
+func (e *APIGatewayPolicyConfigEntry) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	listeners := make(map[string]struct{})
+	for _, listener := range e.Listeners {
+		if _, found := listeners[listener.Name]; found {
+			return fmt.Errorf("found multiple policies for listener %q", listener.Name)
+		}
+		listeners[listener.Name] = struct{}{}
+
+		if err := listener.RateLimit.validate(); err != nil {
+			return fmt.Errorf("listener %q: %w", listener.Name, err)
+		}
+		if err := listener.JWT.validate(); err != nil {
+			return fmt.Errorf("listener %q: %w", listener.Name, err)
+		}
+		if err := listener.ExtAuthz.validate(); err != nil {
+			return fmt.Errorf("listener %q: %w", listener.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *APIGatewayRateLimit) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Local != nil {
+		if err := r.Local.validate(); err != nil {
+			return fmt.Errorf("RateLimit.Local: %w", err)
+		}
+	}
+
+	for i, route := range r.Routes {
+		if route.Hostname == "" && route.PathPrefix == "" {
+			return fmt.Errorf("RateLimit.Routes[%d]: one of Hostname or PathPrefix is required", i)
+		}
+		if strings.ContainsRune(strings.TrimPrefix(route.Hostname, wildcardPrefix), '*') {
+			return fmt.Errorf("RateLimit.Routes[%d]: host %q is not valid, a wildcard specifier is only allowed as the left-most label", i, route.Hostname)
+		}
+		if route.PathPrefix != "" && !strings.HasPrefix(route.PathPrefix, "/") {
+			return fmt.Errorf("RateLimit.Routes[%d]: PathPrefix doesn't start with '/': %q", i, route.PathPrefix)
+		}
+		if err := route.Local.validate(); err != nil {
+			return fmt.Errorf("RateLimit.Routes[%d].Local: %w", i, err)
+		}
+	}
+
+	if r.Global != nil {
+		if r.Global.Domain == "" {
+			return fmt.Errorf("RateLimit.Global: Domain is required")
+		}
+		if r.Global.Address == "" {
+			return fmt.Errorf("RateLimit.Global: Address is required")
+		}
+		if r.Global.Port <= 0 || r.Global.Port > 65535 {
+			return fmt.Errorf("RateLimit.Global: port %d not in the range 1-65535", r.Global.Port)
+		}
+		if r.Global.Timeout < 0 {
+			return fmt.Errorf("RateLimit.Global: Timeout must be non-negative")
+		}
+	}
+	return nil
+}
+
+func (j *APIGatewayJWTPolicy) validate() error {
+	if j == nil {
+		return nil
+	}
+
+	if len(j.Providers) == 0 {
+		return fmt.Errorf("JWT: at least one provider is required")
+	}
+
+	providers := make(map[string]struct{})
+	for i, provider := range j.Providers {
+		if provider.Name == "" {
+			return fmt.Errorf("JWT.Providers[%d]: Name is required", i)
+		}
+		if _, found := providers[provider.Name]; found {
+			return fmt.Errorf("JWT: found multiple providers named %q", provider.Name)
+		}
+		providers[provider.Name] = struct{}{}
+
+		if err := provider.JSONWebKeySet.validate(); err != nil {
+			return fmt.Errorf("JWT.Providers[%d].JSONWebKeySet: %w", i, err)
+		}
+
+		for k, mapping := range provider.ClaimsToHeaders {
+			if mapping.Claim == "" {
+				return fmt.Errorf("JWT.Providers[%d].ClaimsToHeaders[%d]: Claim is required", i, k)
+			}
+			if mapping.Header == "" {
+				return fmt.Errorf("JWT.Providers[%d].ClaimsToHeaders[%d]: Header is required", i, k)
+			}
+		}
+	}
+
+	for _, path := range j.AllowMissingPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("JWT: AllowMissingPaths entry doesn't start with '/': %q", path)
+		}
+	}
+	return nil
+}
+
+func (a *APIGatewayExtAuthz) validate() error {
+	if a == nil {
+		return nil
+	}
+
+	switch a.Protocol {
+	case "grpc":
+		if a.PathPrefix != "" || len(a.AllowedHeaders) > 0 {
+			return fmt.Errorf("ExtAuthz: PathPrefix and AllowedHeaders are only supported with the http protocol")
+		}
+	case "http":
+		if a.PathPrefix != "" && !strings.HasPrefix(a.PathPrefix, "/") {
+			return fmt.Errorf("ExtAuthz: PathPrefix doesn't start with '/': %q", a.PathPrefix)
+		}
+	default:
+		return fmt.Errorf("ExtAuthz: unsupported protocol %q, must be one of grpc or http", a.Protocol)
+	}
+
+	if a.Address == "" {
+		return fmt.Errorf("ExtAuthz: Address is required")
+	}
+	if a.Port <= 0 || a.Port > 65535 {
+		return fmt.Errorf("ExtAuthz: port %d not in the range 1-65535", a.Port)
+	}
+	if a.Timeout < 0 {
+		return fmt.Errorf("ExtAuthz: Timeout must be non-negative")
+	}
+	for _, path := range a.DisabledPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("ExtAuthz: DisabledPaths entry doesn't start with '/': %q", path)
+		}
+	}
+	return nil
+}
+
+func (k APIGatewayJSONWebKeySet) validate() error {
+	switch {
+	case k.Local == "" && k.RemoteURI == "":
+		return fmt.Errorf("one of Local or RemoteURI is required")
+	case k.Local != "" && k.RemoteURI != "":
+		return fmt.Errorf("Local and RemoteURI are mutually exclusive")
+	case k.CacheDuration < 0:
+		return fmt.Errorf("CacheDuration must be non-negative")
+	}
+
+	if k.RemoteURI != "" {
+		u, err := url.Parse(k.RemoteURI)
+		if err != nil {
+			return fmt.Errorf("RemoteURI is not valid: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("RemoteURI scheme must be http or https: %q", k.RemoteURI)
+		}
+		if u.Hostname() == "" {
+			return fmt.Errorf("RemoteURI must contain a host: %q", k.RemoteURI)
+		}
+		if k.CAFile != "" && u.Scheme != "https" {
+			return fmt.Errorf("CAFile requires an https RemoteURI")
+		}
+	} else if k.CAFile != "" {
+		return fmt.Errorf("CAFile requires an https RemoteURI")
+	}
+	return nil
+}
+
+func (l APIGatewayLocalRateLimit) validate() error {
+	if l.MaxTokens == 0 {
+		return fmt.Errorf("MaxTokens must be greater than 0")
+	}
+	if l.FillInterval <= 0 {
+		return fmt.Errorf("FillInterval must be greater than 0")
+	}
+	return nil
+}
+
+func (e *APIGatewayPolicyConfigEntry) CanRead(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().ServiceReadAllowed(e.Name, &authzContext)
+}
+
+func (e *APIGatewayPolicyConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().MeshWriteAllowed(&authzContext)
+}
+
+func (e *APIGatewayPolicyConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+	return &e.RaftIndex
+}
+
+func (e *APIGatewayPolicyConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+	return &e.EnterpriseMeta
+}
+
+// ListenerPolicy returns the policy for the listener with the given name,
+// or nil if the listener has no policy.
+func (e *APIGatewayPolicyConfigEntry) ListenerPolicy(name string) *APIGatewayListenerPolicy {
+	if e == nil {
+		return nil
+	}
+	for i, listener := range e.Listeners {
+		if listener.Name == name {
+			return &e.Listeners[i]
+		}
+	}
+	return nil
+}
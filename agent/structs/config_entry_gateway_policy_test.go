@@ -0,0 +1,359 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIGatewayPolicy(t *testing.T) {
+	cases := map[string]configEntryTestcase{
+		"valid local and global limits": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Local: &APIGatewayLocalRateLimit{
+							MaxTokens:    100,
+							FillInterval: time.Second,
+						},
+						Global: &APIGatewayGlobalRateLimit{
+							Domain:  "gateway",
+							Address: "10.0.0.1",
+							Port:    8081,
+						},
+					},
+				}},
+			},
+		},
+		"duplicate listener": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind:      APIGatewayPolicy,
+				Name:      "gateway",
+				Listeners: []APIGatewayListenerPolicy{{Name: "http"}, {Name: "http"}},
+			},
+			validateErr: `found multiple policies for listener "http"`,
+		},
+		"local limit missing max tokens": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Local: &APIGatewayLocalRateLimit{FillInterval: time.Second},
+					},
+				}},
+			},
+			validateErr: "RateLimit.Local: MaxTokens must be greater than 0",
+		},
+		"route limit without match": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Routes: []APIGatewayRouteRateLimit{{
+							Local: APIGatewayLocalRateLimit{MaxTokens: 1, FillInterval: time.Second},
+						}},
+					},
+				}},
+			},
+			validateErr: "RateLimit.Routes[0]: one of Hostname or PathPrefix is required",
+		},
+		"route limit bad path prefix": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Routes: []APIGatewayRouteRateLimit{{
+							PathPrefix: "api",
+							Local:      APIGatewayLocalRateLimit{MaxTokens: 1, FillInterval: time.Second},
+						}},
+					},
+				}},
+			},
+			validateErr: "PathPrefix doesn't start with '/'",
+		},
+		"global limit invalid port": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Global: &APIGatewayGlobalRateLimit{
+							Domain:  "gateway",
+							Address: "ratelimit.local",
+						},
+					},
+				}},
+			},
+			validateErr: "port 0 not in the range 1-65535",
+		},
+		"valid jwt providers": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{
+							Name:          "okta",
+							Issuer:        "https://example.okta.com",
+							JSONWebKeySet: APIGatewayJSONWebKeySet{RemoteURI: "https://example.okta.com/keys"},
+							ClaimsToHeaders: []APIGatewayJWTClaimToHeader{{
+								Claim:  "sub",
+								Header: "x-user",
+							}},
+						}, {
+							Name:          "local",
+							JSONWebKeySet: APIGatewayJSONWebKeySet{Local: `{"keys":[]}`},
+						}},
+						AllowMissingPaths: []string{"/health"},
+					},
+				}},
+			},
+		},
+		"jwt without providers": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT:  &APIGatewayJWTPolicy{},
+				}},
+			},
+			validateErr: "JWT: at least one provider is required",
+		},
+		"jwt duplicate provider": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{
+							{Name: "okta", JSONWebKeySet: APIGatewayJSONWebKeySet{Local: "{}"}},
+							{Name: "okta", JSONWebKeySet: APIGatewayJSONWebKeySet{Local: "{}"}},
+						},
+					},
+				}},
+			},
+			validateErr: `JWT: found multiple providers named "okta"`,
+		},
+		"jwt missing key set": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{Name: "okta"}},
+					},
+				}},
+			},
+			validateErr: "JWT.Providers[0].JSONWebKeySet: one of Local or RemoteURI is required",
+		},
+		"jwt invalid remote uri scheme": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{
+							Name:          "okta",
+							JSONWebKeySet: APIGatewayJSONWebKeySet{RemoteURI: "ftp://example.com/keys"},
+						}},
+					},
+				}},
+			},
+			validateErr: "RemoteURI scheme must be http or https",
+		},
+		"jwt CA file with http remote uri": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{
+							Name: "okta",
+							JSONWebKeySet: APIGatewayJSONWebKeySet{
+								RemoteURI: "http://example.okta.com/keys",
+								CAFile:    "/etc/consul/okta-ca.pem",
+							},
+						}},
+					},
+				}},
+			},
+			validateErr: "CAFile requires an https RemoteURI",
+		},
+		"jwt CA file with local key set": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{
+							Name: "okta",
+							JSONWebKeySet: APIGatewayJSONWebKeySet{
+								Local:  "{}",
+								CAFile: "/etc/consul/okta-ca.pem",
+							},
+						}},
+					},
+				}},
+			},
+			validateErr: "CAFile requires an https RemoteURI",
+		},
+		"jwt claim mapping missing header": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers: []APIGatewayJWTProvider{{
+							Name:            "okta",
+							JSONWebKeySet:   APIGatewayJSONWebKeySet{Local: "{}"},
+							ClaimsToHeaders: []APIGatewayJWTClaimToHeader{{Claim: "sub"}},
+						}},
+					},
+				}},
+			},
+			validateErr: "JWT.Providers[0].ClaimsToHeaders[0]: Header is required",
+		},
+		"jwt invalid allow missing path": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					JWT: &APIGatewayJWTPolicy{
+						Providers:         []APIGatewayJWTProvider{{Name: "okta", JSONWebKeySet: APIGatewayJSONWebKeySet{Local: "{}"}}},
+						AllowMissingPaths: []string{"health"},
+					},
+				}},
+			},
+			validateErr: `AllowMissingPaths entry doesn't start with '/': "health"`,
+		},
+		"valid http ext authz": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					ExtAuthz: &APIGatewayExtAuthz{
+						Protocol:       "HTTP",
+						Address:        "authz.local",
+						Port:           9000,
+						PathPrefix:     "/check",
+						AllowedHeaders: []string{"x-api-key"},
+						DisabledPaths:  []string{"/public"},
+					},
+				}},
+			},
+		},
+		"ext authz unsupported protocol": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					ExtAuthz: &APIGatewayExtAuthz{
+						Protocol: "tcp",
+						Address:  "authz.local",
+						Port:     9000,
+					},
+				}},
+			},
+			validateErr: `ExtAuthz: unsupported protocol "tcp"`,
+		},
+		"ext authz grpc with path prefix": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					ExtAuthz: &APIGatewayExtAuthz{
+						Address:    "authz.local",
+						Port:       9000,
+						PathPrefix: "/check",
+					},
+				}},
+			},
+			validateErr: "only supported with the http protocol",
+		},
+		"ext authz missing address": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name:     "http",
+					ExtAuthz: &APIGatewayExtAuthz{Port: 9000},
+				}},
+			},
+			validateErr: "ExtAuthz: Address is required",
+		},
+		"ext authz invalid disabled path": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					ExtAuthz: &APIGatewayExtAuthz{
+						Address:       "authz.local",
+						Port:          9000,
+						DisabledPaths: []string{"public"},
+					},
+				}},
+			},
+			validateErr: `DisabledPaths entry doesn't start with '/': "public"`,
+		},
+		"normalize ext authz protocol": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name:     "http",
+					ExtAuthz: &APIGatewayExtAuthz{Address: "authz.local", Port: 9000},
+				}},
+			},
+			normalizeOnly: true,
+			check: func(t *testing.T, entry ConfigEntry) {
+				policy := entry.(*APIGatewayPolicyConfigEntry)
+				require.Equal(t, "grpc", policy.Listeners[0].ExtAuthz.Protocol)
+			},
+		},
+		"normalize route hostname": {
+			entry: &APIGatewayPolicyConfigEntry{
+				Kind: APIGatewayPolicy,
+				Name: "gateway",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Routes: []APIGatewayRouteRateLimit{{
+							Hostname: "*.Example.COM",
+							Local:    APIGatewayLocalRateLimit{MaxTokens: 1, FillInterval: time.Second},
+						}},
+					},
+				}},
+			},
+			normalizeOnly: true,
+			check: func(t *testing.T, entry ConfigEntry) {
+				policy := entry.(*APIGatewayPolicyConfigEntry)
+				require.Equal(t, "*.example.com", policy.Listeners[0].RateLimit.Routes[0].Hostname)
+			},
+		},
+	}
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
@@ -66,6 +66,10 @@ type IngressListener struct {
 	// TLS config for this listener.
 	TLS *GatewayTLSConfig `json:",omitempty"`
 
+	// AccessLogs overrides the proxy-defaults access logging configuration
+	// for this listener only.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
+
 	// Services declares the set of services to which the listener forwards
 	// traffic.
 	//
@@ -311,6 +315,12 @@ func (e *IngressGatewayConfigEntry) Validate() error {
 			}
 		}
 
+		if listener.AccessLogs != nil {
+			if err := listener.AccessLogs.Validate(); err != nil {
+				return fmt.Errorf("invalid access logs for listener on port %d: %w", listener.Port, err)
+			}
+		}
+
 		declaredHosts := make(map[string]bool)
 		serviceNames := make(map[ServiceID]struct{})
 		for _, s := range listener.Services {
@@ -827,6 +837,11 @@ func (e *APIGatewayConfigEntry) validateListeners() error {
 		if err := validateTLSConfig(listener.TLS.MinVersion, listener.TLS.MaxVersion, listener.TLS.CipherSuites); err != nil {
 			return err
 		}
+		if listener.AccessLogs != nil {
+			if err := listener.AccessLogs.Validate(); err != nil {
+				return fmt.Errorf("invalid access logs for listener %q: %w", listener.Name, err)
+			}
+		}
 	}
 	return nil
 }
@@ -895,6 +910,9 @@ type APIGatewayListener struct {
 	Protocol APIGatewayListenerProtocol
 	// TLS is the TLS settings for the listener.
 	TLS APIGatewayTLSConfiguration
+	// AccessLogs overrides the proxy-defaults access logging configuration
+	// for this listener only.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
 }
 
 // APIGatewayTLSConfiguration specifies the configuration of a listener’s
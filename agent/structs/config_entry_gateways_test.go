@@ -895,6 +895,51 @@ func TestIngressGatewayConfigEntry(t *testing.T) {
 			// since the service name is normalized differently on OSS and Ent
 			validateErr: "TLS.SDS.ClusterName is required if CertResource is set",
 		},
+		"listener access logs override": {
+			entry: &IngressGatewayConfigEntry{
+				Kind: "ingress-gateway",
+				Name: "ingress-web",
+				Listeners: []IngressListener{
+					{
+						Port:     1111,
+						Protocol: "http",
+						AccessLogs: &AccessLogsConfig{
+							Enabled:    true,
+							Type:       FileLogSinkType,
+							Path:       "/var/log/ingress-1111.log",
+							JSONFormat: `{"status": "%RESPONSE_CODE%"}`,
+						},
+						Services: []IngressService{
+							{
+								Name: "web",
+							},
+						},
+					},
+				},
+			},
+		},
+		"invalid listener access logs": {
+			entry: &IngressGatewayConfigEntry{
+				Kind: "ingress-gateway",
+				Name: "ingress-web",
+				Listeners: []IngressListener{
+					{
+						Port:     1111,
+						Protocol: "http",
+						AccessLogs: &AccessLogsConfig{
+							Enabled: true,
+							Type:    FileLogSinkType,
+						},
+						Services: []IngressService{
+							{
+								Name: "web",
+							},
+						},
+					},
+				},
+			},
+			validateErr: "invalid access logs for listener on port 1111: path must be specified when using file type access logs",
+		},
 	}
 
 	testConfigEntryNormalizeAndValidate(t, cases)
@@ -1272,6 +1317,25 @@ func TestAPIGateway_Listeners(t *testing.T) {
 			},
 			validateErr: "certificate reference must have a name",
 		},
+		"invalid listener access logs": {
+			entry: &APIGatewayConfigEntry{
+				Kind: "api-gateway",
+				Name: "api-gw-ten",
+				Listeners: []APIGatewayListener{
+					{
+						Name:     "internal",
+						Port:     80,
+						Protocol: APIGatewayListenerProtocol("http"),
+						AccessLogs: &AccessLogsConfig{
+							Enabled:    true,
+							JSONFormat: "{",
+							TextFormat: "%START_TIME%",
+						},
+					},
+				},
+			},
+			validateErr: `invalid access logs for listener "internal": cannot specify both access log JSONFormat and TextFormat`,
+		},
 	}
 	testConfigEntryNormalizeAndValidate(t, cases)
 }
@@ -124,12 +124,14 @@ func (e *ServiceIntentionsConfigEntry) ToIntention(src *SourceIntention) *Intent
 		ID:                   src.LegacyID,
 		Description:          src.Description,
 		SourcePeer:           src.Peer,
+		SourceSamenessGroup:  src.SamenessGroup,
 		SourcePartition:      src.PartitionOrEmpty(),
 		SourceNS:             src.NamespaceOrDefault(),
 		SourceName:           src.Name,
 		SourceType:           src.Type,
 		Action:               src.Action,
 		Permissions:          src.Permissions,
+		DestinationPorts:     src.DestinationPorts,
 		Meta:                 meta,
 		Precedence:           src.Precedence,
 		DestinationPartition: e.PartitionOrEmpty(),
@@ -217,6 +219,11 @@ type SourceIntention struct {
 	//   ]
 	Permissions []*IntentionPermission `json:",omitempty"`
 
+	// DestinationPorts are the names of the ports of the destination service
+	// the intention applies to. It applies to all of them if it is empty,
+	// including the main port of the service.
+	DestinationPorts []string `json:",omitempty" alias:"destination_ports"`
+
 	// Precedence is the order that the intention will be applied, with
 	// larger numbers being applied first. This is a read-only field, on
 	// any intention update it is updated.
@@ -263,6 +270,11 @@ type SourceIntention struct {
 
 	// Peer is the name of the remote peer of the source service, if applicable.
 	Peer string `json:",omitempty"`
+
+	// SamenessGroup is the name of a sameness group. The source service is
+	// the service with this name on any cluster peer that is a member of the
+	// group.
+	SamenessGroup string `json:",omitempty" alias:"sameness_group"`
 }
 
 type IntentionPermission struct {
@@ -343,6 +355,7 @@ func (x *SourceIntention) Clone() *SourceIntention {
 	x2 := *x
 
 	x2.LegacyMeta = cloneStringStringMap(x.LegacyMeta)
+	x2.DestinationPorts = stringslice.CloneStringSlice(x.DestinationPorts)
 
 	if len(x.Permissions) > 0 {
 		x2.Permissions = make([]*IntentionPermission, 0, len(x.Permissions))
@@ -430,12 +443,13 @@ func (e *ServiceIntentionsConfigEntry) normalize(legacyWrite bool) error {
 		// Normalize the source's namespace and partition.
 		// If the source is not peered, it inherits the destination's
 		// EnterpriseMeta.
-		if src.Peer == "" {
+		if src.Peer == "" && src.SamenessGroup == "" {
 			src.EnterpriseMeta.MergeNoWildcard(&e.EnterpriseMeta)
 			src.EnterpriseMeta.Normalize()
 		} else {
 			// If the source is peered, normalize the namespace only,
-			// since peer is mutually exclusive with partition.
+			// since peer and sameness group are mutually exclusive with
+			// partition.
 			src.EnterpriseMeta.NormalizeNamespace()
 		}
 
@@ -573,7 +587,11 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 		return fmt.Errorf("At least one source is required")
 	}
 
-	seenSources := make(map[PeeredServiceName]struct{})
+	type sourceKey struct {
+		PeeredServiceName
+		SamenessGroup string
+	}
+	seenSources := make(map[sourceKey]struct{})
 	for i, src := range e.Sources {
 		if src.Name == "" {
 			return fmt.Errorf("Sources[%d].Name is required", i)
@@ -583,6 +601,10 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			return fmt.Errorf("Sources[%d].%v", i, err)
 		}
 
+		if strings.Contains(src.SamenessGroup, WildcardSpecifier) {
+			return fmt.Errorf("Sources[%d].SamenessGroup: cannot use wildcard '*' in sameness group", i)
+		}
+
 		if err := validateSourceIntentionEnterpriseMeta(&src.EnterpriseMeta, &e.EnterpriseMeta); err != nil {
 			return fmt.Errorf("Sources[%d].%v", i, err)
 		}
@@ -591,6 +613,10 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			return fmt.Errorf("Sources[%d].Peer: cannot set Peer and Partition at the same time.", i)
 		}
 
+		if src.SamenessGroup != "" && (src.Peer != "" || src.PartitionOrEmpty() != "") {
+			return fmt.Errorf("Sources[%d].SamenessGroup: cannot set SamenessGroup with Peer or Partition.", i)
+		}
+
 		// Length of opaque values
 		if len(src.Description) > metaValueMaxLength {
 			return fmt.Errorf(
@@ -602,6 +628,10 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 				return fmt.Errorf("Sources[%d].Peer cannot be set by legacy intentions", i)
 			}
 
+			if src.SamenessGroup != "" {
+				return fmt.Errorf("Sources[%d].SamenessGroup cannot be set by legacy intentions", i)
+			}
+
 			if len(src.LegacyMeta) > metaMaxKeyPairs {
 				return fmt.Errorf(
 					"Sources[%d].Meta exceeds maximum element count %d", i, metaMaxKeyPairs)
@@ -667,6 +697,20 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			return fmt.Errorf("Sources[%d].Permissions cannot be specified on intentions with wildcarded destinations", i)
 		}
 
+		if len(src.DestinationPorts) > 0 {
+			if legacyWrite {
+				return fmt.Errorf("Sources[%d].DestinationPorts must be omitted", i)
+			}
+			if destIsWild {
+				return fmt.Errorf("Sources[%d].DestinationPorts cannot be specified on intentions with wildcarded destinations", i)
+			}
+			for j, port := range src.DestinationPorts {
+				if err := ValidateServicePortName(port); err != nil {
+					return fmt.Errorf("Sources[%d].DestinationPorts[%d] is invalid: %v", i, j, err)
+				}
+			}
+		}
+
 		switch src.Type {
 		case IntentionSourceConsul:
 		default:
@@ -761,15 +805,20 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			}
 		}
 
-		psn := PeeredServiceName{Peer: src.Peer, ServiceName: src.SourceServiceName()}
-		if _, exists := seenSources[psn]; exists {
-			if psn.Peer != "" {
-				return fmt.Errorf("Sources[%d] defines peer(%q) %q more than once", i, psn.Peer, psn.ServiceName.String())
+		key := sourceKey{
+			PeeredServiceName: PeeredServiceName{Peer: src.Peer, ServiceName: src.SourceServiceName()},
+			SamenessGroup:     src.SamenessGroup,
+		}
+		if _, exists := seenSources[key]; exists {
+			if key.Peer != "" {
+				return fmt.Errorf("Sources[%d] defines peer(%q) %q more than once", i, key.Peer, key.ServiceName.String())
+			} else if key.SamenessGroup != "" {
+				return fmt.Errorf("Sources[%d] defines sameness-group(%q) %q more than once", i, key.SamenessGroup, key.ServiceName.String())
 			} else {
-				return fmt.Errorf("Sources[%d] defines %q more than once", i, psn.ServiceName.String())
+				return fmt.Errorf("Sources[%d] defines %q more than once", i, key.ServiceName.String())
 			}
 		}
-		seenSources[psn] = struct{}{}
+		seenSources[key] = struct{}{}
 	}
 
 	return nil
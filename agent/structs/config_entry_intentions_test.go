@@ -1262,6 +1262,72 @@ func TestServiceIntentionsConfigEntry(t *testing.T) {
 			},
 			validateErr: `Sources[1] defines peer("peer1") "` + fooName.String() + `" more than once`,
 		},
+		"sameness group and peer intentions are different": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:   "foo",
+						Peer:   "peer1",
+						Action: IntentionActionDeny,
+					},
+					{
+						Name:          "foo",
+						SamenessGroup: "group1",
+						Action:        IntentionActionAllow,
+					},
+				},
+			},
+		},
+		"already have a sameness group intention for source": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:          "foo",
+						SamenessGroup: "group1",
+						Action:        IntentionActionAllow,
+					},
+					{
+						Name:          "foo",
+						SamenessGroup: "group1",
+						Action:        IntentionActionAllow,
+					},
+				},
+			},
+			validateErr: `Sources[1] defines sameness-group("group1") "` + fooName.String() + `" more than once`,
+		},
+		"sameness group with peer": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:          "foo",
+						Peer:          "peer1",
+						SamenessGroup: "group1",
+						Action:        IntentionActionAllow,
+					},
+				},
+			},
+			validateErr: `Sources[0].SamenessGroup: cannot set SamenessGroup with Peer or Partition.`,
+		},
+		"sameness group with wildcard": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:          "foo",
+						SamenessGroup: "*",
+						Action:        IntentionActionAllow,
+					},
+				},
+			},
+			validateErr: `Sources[0].SamenessGroup: cannot use wildcard '*' in sameness group`,
+		},
 	}
 	for name, tc := range cases {
 		tc := tc
@@ -19,6 +19,11 @@ type MeshConfigEntry struct {
 
 	Peering *PeeringMeshConfig `json:",omitempty"`
 
+	// MeshGateway is the default mesh gateway configuration for all proxies
+	// in the partition. It is overridden by proxy-defaults, service-defaults
+	// and upstream configuration.
+	MeshGateway MeshGatewayConfig `json:",omitempty" alias:"mesh_gateway"`
+
 	Meta               map[string]string `json:",omitempty"`
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	RaftIndex
@@ -48,8 +53,64 @@ type MeshDirectionalTLSConfig struct {
 
 type MeshHTTPConfig struct {
 	SanitizeXForwardedClientCert bool `alias:"sanitize_x_forwarded_client_cert"`
+
+	// Incoming configures the HTTP requests that the inbound listeners of the
+	// proxies receive.
+	Incoming *MeshDirectionalHTTPConfig `json:",omitempty"`
+}
+
+// MeshDirectionalHTTPConfig contains the mesh-wide options of the HTTP
+// requests in a given direction.
+type MeshDirectionalHTTPConfig struct {
+	RequestNormalization *RequestNormalizationMeshConfig `json:",omitempty" alias:"request_normalization"`
 }
 
+// RequestNormalizationMeshConfig contains the options that Envoy uses to
+// normalize the HTTP requests before it matches them against L7 intentions
+// and routes, so that all proxies in the mesh see the same path and headers
+// regardless of how clients encode them.
+type RequestNormalizationMeshConfig struct {
+	// NormalizePath normalizes the path of the requests according to
+	// RFC 3986, which resolves "." and ".." segments and decodes the
+	// unreserved characters.
+	NormalizePath bool `json:",omitempty" alias:"normalize_path"`
+
+	// MergeSlashes merges adjacent slashes in the path of the requests.
+	MergeSlashes bool `json:",omitempty" alias:"merge_slashes"`
+
+	// PathWithEscapedSlashesAction determines what Envoy does with the
+	// requests whose path contains escaped slashes (%2F, %2f, %5C and %5c).
+	PathWithEscapedSlashesAction PathWithEscapedSlashesAction `json:",omitempty" alias:"path_with_escaped_slashes_action"`
+
+	// HeadersWithUnderscoresAction determines what Envoy does with the
+	// requests whose header names contain underscores.
+	HeadersWithUnderscoresAction HeadersWithUnderscoresAction `json:",omitempty" alias:"headers_with_underscores_action"`
+}
+
+// PathWithEscapedSlashesAction is the action to take on the requests whose
+// path contains escaped slashes. The values match the ones of the
+// path_with_escaped_slashes_action option of Envoy's HTTP connection manager.
+type PathWithEscapedSlashesAction string
+
+const (
+	PathWithEscapedSlashesActionDefault             PathWithEscapedSlashesAction = "IMPLEMENTATION_SPECIFIC_DEFAULT"
+	PathWithEscapedSlashesActionKeep                PathWithEscapedSlashesAction = "KEEP_UNCHANGED"
+	PathWithEscapedSlashesActionReject              PathWithEscapedSlashesAction = "REJECT_REQUEST"
+	PathWithEscapedSlashesActionUnescapeAndRedirect PathWithEscapedSlashesAction = "UNESCAPE_AND_REDIRECT"
+	PathWithEscapedSlashesActionUnescapeAndForward  PathWithEscapedSlashesAction = "UNESCAPE_AND_FORWARD"
+)
+
+// HeadersWithUnderscoresAction is the action to take on the requests whose
+// header names contain underscores. The values match the ones of the
+// headers_with_underscores_action option of Envoy's HTTP protocol options.
+type HeadersWithUnderscoresAction string
+
+const (
+	HeadersWithUnderscoresActionAllow         HeadersWithUnderscoresAction = "ALLOW"
+	HeadersWithUnderscoresActionRejectRequest HeadersWithUnderscoresAction = "REJECT_REQUEST"
+	HeadersWithUnderscoresActionDropHeader    HeadersWithUnderscoresAction = "DROP_HEADER"
+)
+
 // PeeringMeshConfig contains cluster-wide options pertaining to peering.
 type PeeringMeshConfig struct {
 	// PeerThroughMeshGateways determines whether peering traffic between
@@ -97,6 +158,10 @@ func (e *MeshConfigEntry) Validate() error {
 		return err
 	}
 
+	if _, err := ValidateMeshGatewayMode(string(e.MeshGateway.Mode)); err != nil {
+		return err
+	}
+
 	if e.TLS != nil {
 		if e.TLS.Incoming != nil {
 			if err := validateMeshDirectionalTLSConfig(e.TLS.Incoming); err != nil {
@@ -110,6 +175,12 @@ func (e *MeshConfigEntry) Validate() error {
 		}
 	}
 
+	if rn := e.GetHTTPIncoming().GetRequestNormalization(); rn != nil {
+		if err := rn.validate(); err != nil {
+			return fmt.Errorf("error in HTTP incoming request normalization configuration: %v", err)
+		}
+	}
+
 	return e.validateEnterpriseMeta()
 }
 
@@ -162,6 +233,48 @@ func (e *MeshConfigEntry) PeerThroughMeshGateways() bool {
 	return e.Peering.PeerThroughMeshGateways
 }
 
+// GetHTTPIncoming returns the mesh configuration of the incoming HTTP
+// requests, if it is set.
+func (e *MeshConfigEntry) GetHTTPIncoming() *MeshDirectionalHTTPConfig {
+	if e == nil || e.HTTP == nil {
+		return nil
+	}
+	return e.HTTP.Incoming
+}
+
+// GetRequestNormalization returns the request normalization options, if they
+// are set.
+func (c *MeshDirectionalHTTPConfig) GetRequestNormalization() *RequestNormalizationMeshConfig {
+	if c == nil {
+		return nil
+	}
+	return c.RequestNormalization
+}
+
+func (c *RequestNormalizationMeshConfig) validate() error {
+	switch c.PathWithEscapedSlashesAction {
+	case "",
+		PathWithEscapedSlashesActionDefault,
+		PathWithEscapedSlashesActionKeep,
+		PathWithEscapedSlashesActionReject,
+		PathWithEscapedSlashesActionUnescapeAndRedirect,
+		PathWithEscapedSlashesActionUnescapeAndForward:
+	default:
+		return fmt.Errorf("invalid PathWithEscapedSlashesAction %q", c.PathWithEscapedSlashesAction)
+	}
+
+	switch c.HeadersWithUnderscoresAction {
+	case "",
+		HeadersWithUnderscoresActionAllow,
+		HeadersWithUnderscoresActionRejectRequest,
+		HeadersWithUnderscoresActionDropHeader:
+	default:
+		return fmt.Errorf("invalid HeadersWithUnderscoresAction %q", c.HeadersWithUnderscoresAction)
+	}
+
+	return nil
+}
+
 func validateMeshDirectionalTLSConfig(cfg *MeshDirectionalTLSConfig) error {
 	if cfg == nil {
 		return nil
@@ -44,3 +44,48 @@ func TestMeshConfigEntry_PeerThroughMeshGateways(t *testing.T) {
 		})
 	}
 }
+
+func TestMeshConfigEntry_RequestNormalization(t *testing.T) {
+	cases := map[string]configEntryTestcase{
+		"valid request normalization": {
+			entry: &MeshConfigEntry{
+				HTTP: &MeshHTTPConfig{
+					Incoming: &MeshDirectionalHTTPConfig{
+						RequestNormalization: &RequestNormalizationMeshConfig{
+							NormalizePath:                true,
+							MergeSlashes:                 true,
+							PathWithEscapedSlashesAction: PathWithEscapedSlashesActionUnescapeAndRedirect,
+							HeadersWithUnderscoresAction: HeadersWithUnderscoresActionRejectRequest,
+						},
+					},
+				},
+			},
+		},
+		"invalid path with escaped slashes action": {
+			entry: &MeshConfigEntry{
+				HTTP: &MeshHTTPConfig{
+					Incoming: &MeshDirectionalHTTPConfig{
+						RequestNormalization: &RequestNormalizationMeshConfig{
+							PathWithEscapedSlashesAction: "unescape",
+						},
+					},
+				},
+			},
+			validateErr: `error in HTTP incoming request normalization configuration: invalid PathWithEscapedSlashesAction "unescape"`,
+		},
+		"invalid headers with underscores action": {
+			entry: &MeshConfigEntry{
+				HTTP: &MeshHTTPConfig{
+					Incoming: &MeshDirectionalHTTPConfig{
+						RequestNormalization: &RequestNormalizationMeshConfig{
+							HeadersWithUnderscoresAction: "drop",
+						},
+					},
+				},
+			},
+			validateErr: `error in HTTP incoming request normalization configuration: invalid HeadersWithUnderscoresAction "drop"`,
+		},
+	}
+
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
@@ -0,0 +1,139 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/consul/acl"
+)
+
+// SamenessGroupConfigEntry defines a group of cluster peers that run the same
+// services. Services with the same name in the members of the group are
+// considered to be the same service, so queries can fail over to them.
+type SamenessGroupConfigEntry struct {
+	// Name is the name of the sameness group.
+	Name string
+
+	// Members are the cluster peers in the group, in failover order.
+	Members []SamenessGroupMember
+
+	Meta               map[string]string `json:",omitempty"`
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+// SamenessGroupMember is a cluster peer that is a member of a sameness group.
+type SamenessGroupMember struct {
+	// Peer is the name of the cluster peer.
+	Peer string
+}
+
+// FailoverTargets returns the members of the group as prepared query failover
+// targets.
+func (e *SamenessGroupConfigEntry) FailoverTargets() []QueryFailoverTarget {
+	targets := make([]QueryFailoverTarget, 0, len(e.Members))
+	for _, member := range e.Members {
+		targets = append(targets, QueryFailoverTarget{Peer: member.Peer})
+	}
+	return targets
+}
+
+func (e *SamenessGroupConfigEntry) GetKind() string {
+	return SamenessGroup
+}
+
+func (e *SamenessGroupConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.Name
+}
+
+func (e *SamenessGroupConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *SamenessGroupConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+	e.EnterpriseMeta.Normalize()
+	return nil
+}
+
+func (e *SamenessGroupConfigEntry) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if e.Name == WildcardSpecifier {
+		return fmt.Errorf("Name must not be a wildcard")
+	}
+
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	if len(e.Members) == 0 {
+		return fmt.Errorf("must have at least one member")
+	}
+	peers := make(map[string]struct{}, len(e.Members))
+	for i, member := range e.Members {
+		if member.Peer == "" {
+			return fmt.Errorf("Members[%d]: Peer is required", i)
+		}
+		if member.Peer == WildcardSpecifier {
+			return fmt.Errorf("Members[%d]: Peer must not be a wildcard", i)
+		}
+		if _, ok := peers[member.Peer]; ok {
+			return fmt.Errorf("Members[%d]: peer %q is already a member", i, member.Peer)
+		}
+		peers[member.Peer] = struct{}{}
+	}
+	return nil
+}
+
+func (e *SamenessGroupConfigEntry) CanRead(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().MeshReadAllowed(&authzContext)
+}
+
+func (e *SamenessGroupConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().MeshWriteAllowed(&authzContext)
+}
+
+func (e *SamenessGroupConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+
+	return &e.RaftIndex
+}
+
+func (e *SamenessGroupConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+
+	return &e.EnterpriseMeta
+}
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *SamenessGroupConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias SamenessGroupConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  SamenessGroup,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
@@ -429,8 +429,15 @@ func TestDecodeConfigEntry(t *testing.T) {
 							max_pending_requests = 4
 							max_concurrent_requests = 5
 						}
+						max_concurrent_streams = 100
+						max_requests_per_connection = 1000
 					}
 				}
+				upstream_connection_pool {
+					connect_timeout_ms = 2500
+					max_concurrent_streams = 200
+					max_requests_per_connection = 500
+				}
 			`,
 			camel: `
 				Kind = "service-defaults"
@@ -472,8 +479,15 @@ func TestDecodeConfigEntry(t *testing.T) {
 							MaxConcurrentRequests = 5
 						}
 						BalanceOutboundConnections = "exact_balance"
+						MaxConcurrentStreams = 100
+						MaxRequestsPerConnection = 1000
 					}
 				}
+				UpstreamConnectionPool {
+					ConnectTimeoutMs = 2500
+					MaxConcurrentStreams = 200
+					MaxRequestsPerConnection = 500
+				}
 			`,
 			expect: &ServiceConfigEntry{
 				Kind: "service-defaults",
@@ -513,8 +527,15 @@ func TestDecodeConfigEntry(t *testing.T) {
 							MaxConcurrentRequests: intPointer(5),
 						},
 						BalanceOutboundConnections: "exact_balance",
+						MaxConcurrentStreams:       100,
+						MaxRequestsPerConnection:   1000,
 					},
 				},
+				UpstreamConnectionPool: &UpstreamConnectionPool{
+					ConnectTimeoutMs:         2500,
+					MaxConcurrentStreams:     200,
+					MaxRequestsPerConnection: 500,
+				},
 			},
 		},
 		{
@@ -982,6 +1003,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 						filter = "Service.Meta.version == v2"
 						only_passing = true
 					},
+					"v3-eu" = {
+						meta = {
+							version = "v3"
+							shard = "eu"
+						}
+					},
 				}
 				failover = {
 					"v2" = {
@@ -1011,6 +1038,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 						Filter = "Service.Meta.version == v2"
 						OnlyPassing = true
 					},
+					"v3-eu" = {
+						Meta = {
+							version = "v3"
+							shard = "eu"
+						}
+					},
 				}
 				Failover = {
 					"v2" = {
@@ -1040,6 +1073,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 						Filter:      "Service.Meta.version == v2",
 						OnlyPassing: true,
 					},
+					"v3-eu": {
+						Meta: map[string]string{
+							"version": "v3",
+							"shard":   "eu",
+						},
+					},
 				},
 				Failover: map[string]ServiceResolverFailover{
 					"v2": {
@@ -1828,6 +1867,14 @@ func TestDecodeConfigEntry(t *testing.T) {
 				}
 				http {
 					sanitize_x_forwarded_client_cert = true
+					incoming {
+						request_normalization {
+							normalize_path = true
+							merge_slashes = true
+							path_with_escaped_slashes_action = "UNESCAPE_AND_FORWARD"
+							headers_with_underscores_action = "REJECT_REQUEST"
+						}
+					}
 				}
 				peering {
 					peer_through_mesh_gateways = true
@@ -1862,6 +1909,14 @@ func TestDecodeConfigEntry(t *testing.T) {
 				}
 				HTTP {
 					SanitizeXForwardedClientCert = true
+					Incoming {
+						RequestNormalization {
+							NormalizePath = true
+							MergeSlashes = true
+							PathWithEscapedSlashesAction = "UNESCAPE_AND_FORWARD"
+							HeadersWithUnderscoresAction = "REJECT_REQUEST"
+						}
+					}
 				}
 				Peering {
 					PeerThroughMeshGateways = true
@@ -1895,6 +1950,14 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 				HTTP: &MeshHTTPConfig{
 					SanitizeXForwardedClientCert: true,
+					Incoming: &MeshDirectionalHTTPConfig{
+						RequestNormalization: &RequestNormalizationMeshConfig{
+							NormalizePath:                true,
+							MergeSlashes:                 true,
+							PathWithEscapedSlashesAction: PathWithEscapedSlashesActionUnescapeAndForward,
+							HeadersWithUnderscoresAction: HeadersWithUnderscoresActionRejectRequest,
+						},
+					},
 				},
 				Peering: &PeeringMeshConfig{
 					PeerThroughMeshGateways: true,
@@ -1928,6 +1991,101 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "api-gateway-policy",
+			snake: `
+				kind = "api-gateway-policy"
+				name = "foo"
+				listeners = [
+					{
+						name = "http"
+						rate_limit {
+							local {
+								max_tokens = 100
+								tokens_per_fill = 10
+								fill_interval = "1s"
+							}
+							routes = [
+								{
+									path_prefix = "/admin"
+									local {
+										max_tokens = 5
+										fill_interval = "1m"
+									}
+								}
+							]
+							global {
+								domain = "gateway"
+								address = "ratelimit.local"
+								port = 8081
+								timeout = "50ms"
+								failure_mode_deny = true
+							}
+						}
+					}
+				]
+			`,
+			camel: `
+				Kind = "api-gateway-policy"
+				Name = "foo"
+				Listeners = [
+					{
+						Name = "http"
+						RateLimit {
+							Local {
+								MaxTokens = 100
+								TokensPerFill = 10
+								FillInterval = "1s"
+							}
+							Routes = [
+								{
+									PathPrefix = "/admin"
+									Local {
+										MaxTokens = 5
+										FillInterval = "1m"
+									}
+								}
+							]
+							Global {
+								Domain = "gateway"
+								Address = "ratelimit.local"
+								Port = 8081
+								Timeout = "50ms"
+								FailureModeDeny = true
+							}
+						}
+					}
+				]
+			`,
+			expect: &APIGatewayPolicyConfigEntry{
+				Kind: "api-gateway-policy",
+				Name: "foo",
+				Listeners: []APIGatewayListenerPolicy{{
+					Name: "http",
+					RateLimit: &APIGatewayRateLimit{
+						Local: &APIGatewayLocalRateLimit{
+							MaxTokens:     100,
+							TokensPerFill: 10,
+							FillInterval:  time.Second,
+						},
+						Routes: []APIGatewayRouteRateLimit{{
+							PathPrefix: "/admin",
+							Local: APIGatewayLocalRateLimit{
+								MaxTokens:    5,
+								FillInterval: time.Minute,
+							},
+						}},
+						Global: &APIGatewayGlobalRateLimit{
+							Domain:          "gateway",
+							Address:         "ratelimit.local",
+							Port:            8081,
+							Timeout:         50 * time.Millisecond,
+							FailureModeDeny: true,
+						},
+					},
+				}},
+			},
+		},
 		{
 			name: "inline-certificate",
 			snake: `
@@ -2724,6 +2882,124 @@ func TestServiceConfigEntry(t *testing.T) {
 			},
 			validateErr: "Invalid Port number",
 		},
+		"validate: wildcard destination hostname": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "http",
+				Destination: &DestinationConfig{
+					Addresses: []string{"*.example.com"},
+					Port:      443,
+				},
+			},
+		},
+		"validate: wildcard destination hostname requires http": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "tcp",
+				Destination: &DestinationConfig{
+					Addresses: []string{"*.example.com"},
+					Port:      443,
+				},
+			},
+			validateErr: "Destination address '*.example.com' is a wildcard, which is only supported for protocol 'http', 'http2' or 'grpc'",
+		},
+		"validate: wildcard not in left-most label": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "http",
+				Destination: &DestinationConfig{
+					Addresses: []string{"api.*.example.com"},
+					Port:      443,
+				},
+			},
+			validateErr: "Could not validate address api.*.example.com as an IP or Hostname",
+		},
+		"validate: destination health check and dns": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "http",
+				Destination: &DestinationConfig{
+					Addresses: []string{"api.example.com"},
+					Port:      443,
+					HealthCheck: &DestinationHealthCheck{
+						Protocol: "HTTP",
+						Path:     "/healthz",
+						Interval: 5 * time.Second,
+					},
+					DNS: &DestinationDNSConfig{
+						RefreshRate:   30 * time.Second,
+						DiscoveryType: "Strict",
+					},
+				},
+			},
+			check: func(t *testing.T, entry ConfigEntry) {
+				dest := entry.(*ServiceConfigEntry).Destination
+				require.Equal(t, "http", dest.HealthCheck.Protocol)
+				require.Equal(t, DestinationDNSDiscoveryStrict, dest.DNS.DiscoveryType)
+			},
+		},
+		"validate: destination health check requires a hostname": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "tcp",
+				Destination: &DestinationConfig{
+					Addresses:   []string{"10.0.0.1"},
+					Port:        443,
+					HealthCheck: &DestinationHealthCheck{},
+				},
+			},
+			validateErr: "Destination HealthCheck and DNS are only supported for hostname addresses",
+		},
+		"validate: destination health check path with tcp": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "tcp",
+				Destination: &DestinationConfig{
+					Addresses: []string{"api.example.com"},
+					Port:      443,
+					HealthCheck: &DestinationHealthCheck{
+						Path: "/healthz",
+					},
+				},
+			},
+			validateErr: "Path is only supported for http health checks",
+		},
+		"validate: destination health check unsupported protocol": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "tcp",
+				Destination: &DestinationConfig{
+					Addresses: []string{"api.example.com"},
+					Port:      443,
+					HealthCheck: &DestinationHealthCheck{
+						Protocol: "grpc",
+					},
+				},
+			},
+			validateErr: `unsupported health check protocol "grpc"`,
+		},
+		"validate: destination dns unsupported discovery type": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "external",
+				Protocol: "tcp",
+				Destination: &DestinationConfig{
+					Addresses: []string{"api.example.com"},
+					Port:      443,
+					DNS: &DestinationDNSConfig{
+						DiscoveryType: "static",
+					},
+				},
+			},
+			validateErr: `unsupported discovery type "static"`,
+		},
 		"validate: invalid hostname 1": {
 			entry: &ServiceConfigEntry{
 				Kind:     ServiceDefaults,
@@ -2866,6 +3142,102 @@ func TestServiceConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		"validate: access logs override": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				AccessLogs: &AccessLogsConfig{
+					Enabled:    true,
+					Type:       FileLogSinkType,
+					Path:       "/var/log/envoy/web.log",
+					TextFormat: "%START_TIME% %RESPONSE_CODE%",
+				},
+			},
+		},
+		"validate: invalid access logs override": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				AccessLogs: &AccessLogsConfig{
+					Enabled: true,
+					Type:    StdOutLogSinkType,
+					Path:    "/var/log/envoy/web.log",
+				},
+			},
+			validateErr: "invalid access logs: path is only valid for file type access logs",
+		},
+		"validate: retry policy": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				RetryPolicy: &RouteRetryPolicy{
+					NumRetries:    3,
+					RetryOn:       []string{"5xx", "gateway-error"},
+					PerTryTimeout: 500 * time.Millisecond,
+				},
+			},
+		},
+		"validate: invalid retry policy condition": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				RetryPolicy: &RouteRetryPolicy{
+					NumRetries: 3,
+					RetryOn:    []string{"4xx"},
+				},
+			},
+			validateErr: `invalid retry policy: invalid retry condition: "4xx"`,
+		},
+		"validate: negative retry policy per-try timeout": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http",
+				RetryPolicy: &RouteRetryPolicy{
+					RetryOn:       []string{"reset"},
+					PerTryTimeout: -time.Second,
+				},
+			},
+			validateErr: "invalid retry policy: PerTryTimeout must be greater than or equal to 0s, got -1s",
+		},
+		"validate: upstream connection pool": {
+			entry: &ServiceConfigEntry{
+				Kind:     ServiceDefaults,
+				Name:     "web",
+				Protocol: "http2",
+				UpstreamConnectionPool: &UpstreamConnectionPool{
+					ConnectTimeoutMs:         2500,
+					MaxConcurrentStreams:     100,
+					MaxRequestsPerConnection: 1000,
+				},
+			},
+		},
+		"validate: negative upstream connection pool max requests per connection": {
+			entry: &ServiceConfigEntry{
+				Kind: ServiceDefaults,
+				Name: "web",
+				UpstreamConnectionPool: &UpstreamConnectionPool{
+					MaxRequestsPerConnection: -1,
+				},
+			},
+			validateErr: "invalid upstream connection pool: max requests per connection cannot be negative",
+		},
+		"validate: negative upstream defaults max concurrent streams": {
+			entry: &ServiceConfigEntry{
+				Kind: ServiceDefaults,
+				Name: "web",
+				UpstreamConfig: &UpstreamConfiguration{
+					Defaults: &UpstreamConfig{
+						MaxConcurrentStreams: -1,
+					},
+				},
+			},
+			validateErr: "max concurrent streams cannot be negative",
+		},
 	}
 	testConfigEntryNormalizeAndValidate(t, cases)
 }
@@ -2894,7 +3266,9 @@ func TestUpstreamConfig_MergeInto(t *testing.T) {
 					MaxFailures: 3,
 					Interval:    2 * time.Second,
 				},
-				MeshGateway: MeshGatewayConfig{Mode: MeshGatewayModeRemote},
+				MeshGateway:              MeshGatewayConfig{Mode: MeshGatewayModeRemote},
+				MaxConcurrentStreams:     100,
+				MaxRequestsPerConnection: 1000,
 			},
 			destination: make(map[string]interface{}),
 			want: map[string]interface{}{
@@ -2912,7 +3286,9 @@ func TestUpstreamConfig_MergeInto(t *testing.T) {
 					MaxFailures: 3,
 					Interval:    2 * time.Second,
 				},
-				"mesh_gateway": MeshGatewayConfig{Mode: MeshGatewayModeRemote},
+				"mesh_gateway":                MeshGatewayConfig{Mode: MeshGatewayModeRemote},
+				"max_concurrent_streams":      100,
+				"max_requests_per_connection": 1000,
 			},
 		},
 		{
@@ -3246,6 +3622,79 @@ func TestProxyConfigEntry(t *testing.T) {
 			},
 			validateErr: "invalid access log json for JSON format",
 		},
+		"proxy config has allowed envoy runtime flags": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				Config: map[string]interface{}{
+					"envoy_runtime_flags": map[string]interface{}{
+						"envoy.reloadable_features.http_reject_path_with_fragment": false,
+						"re2.max_program_size.error_level":                         float64(200),
+					},
+				},
+			},
+			expected: &ProxyConfigEntry{
+				Name: ProxyConfigGlobal,
+				Kind: ProxyDefaults,
+				Config: map[string]interface{}{
+					"envoy_runtime_flags": map[string]interface{}{
+						"envoy.reloadable_features.http_reject_path_with_fragment": false,
+						"re2.max_program_size.error_level":                         float64(200),
+					},
+				},
+				EnterpriseMeta: *acl.DefaultEnterpriseMeta(),
+			},
+		},
+		"proxy config has disallowed envoy runtime flag": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				Config: map[string]interface{}{
+					"envoy_runtime_flags": map[string]interface{}{
+						"upstream.healthy_panic_threshold": 0,
+					},
+				},
+			},
+			validateErr: `envoy_runtime_flags: runtime flag "upstream.healthy_panic_threshold" is not allowed`,
+		},
+		"proxy config has invalid envoy runtime flag value": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				Config: map[string]interface{}{
+					"envoy_runtime_flags": map[string]interface{}{
+						"envoy.reloadable_features.foo": []string{"bar"},
+					},
+				},
+			},
+			validateErr: `envoy_runtime_flags: runtime flag "envoy.reloadable_features.foo" must be a boolean, a number or a string, got []string`,
+		},
+		"proxy config has invalid envoy runtime flags": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				Config: map[string]interface{}{
+					"envoy_runtime_flags": "envoy.reloadable_features.foo=true",
+				},
+			},
+			validateErr: "envoy_runtime_flags must be a map of runtime flags to values, got string",
+		},
+		"proxy config has retry policy": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				RetryPolicy: &RouteRetryPolicy{
+					NumRetries:         2,
+					RetryOn:            []string{"connect-failure", "reset"},
+					RetryOnStatusCodes: []uint32{503},
+					PerTryTimeout:      time.Second,
+				},
+			},
+		},
+		"proxy config has retry policy without conditions": {
+			entry: &ProxyConfigEntry{
+				Name: "global",
+				RetryPolicy: &RouteRetryPolicy{
+					NumRetries: 2,
+				},
+			},
+			validateErr: "invalid retry policy: at least one of RetryOn or RetryOnStatusCodes must be set",
+		},
 	}
 	testConfigEntryNormalizeAndValidate(t, cases)
 }
@@ -261,6 +261,12 @@ type ConnectProxyConfig struct {
 	// (DestinationServiceID is set) but otherwise will be ignored.
 	LocalServicePort int `json:",omitempty" alias:"local_service_port"`
 
+	// LocalServicePorts are the named ports of the local service instance.
+	// The public listener routes the connections to one of them according to
+	// the ALPN protocol of the upstream proxy. It will default to the named
+	// ports of the instance if the proxy is a "side-car".
+	LocalServicePorts ServicePorts `json:",omitempty" alias:"local_service_ports"`
+
 	// LocalServiceSocketPath is the socket of the local service instance. It is optional
 	// and should only be specified for "side-car" style proxies.
 	LocalServiceSocketPath string `json:",omitempty" alias:"local_service_socket_path"`
@@ -297,6 +303,7 @@ func (t *ConnectProxyConfig) UnmarshalJSON(data []byte) (err error) {
 		DestinationServiceIDSnake   string                 `json:"destination_service_id"`
 		LocalServiceAddressSnake    string                 `json:"local_service_address"`
 		LocalServicePortSnake       int                    `json:"local_service_port"`
+		LocalServicePortsSnake      ServicePorts           `json:"local_service_ports"`
 		LocalServiceSocketPathSnake string                 `json:"local_service_socket_path"`
 		MeshGatewaySnake            MeshGatewayConfig      `json:"mesh_gateway"`
 		TransparentProxySnake       TransparentProxyConfig `json:"transparent_proxy"`
@@ -320,6 +327,9 @@ func (t *ConnectProxyConfig) UnmarshalJSON(data []byte) (err error) {
 	if t.LocalServicePort == 0 {
 		t.LocalServicePort = aux.LocalServicePortSnake
 	}
+	if len(t.LocalServicePorts) == 0 {
+		t.LocalServicePorts = aux.LocalServicePortsSnake
+	}
 	if t.LocalServiceSocketPath == "" {
 		t.LocalServiceSocketPath = aux.LocalServiceSocketPathSnake
 	}
@@ -390,6 +400,7 @@ func (c *ConnectProxyConfig) ToAPI() *api.AgentServiceConnectProxyConfig {
 		DestinationServiceID:   c.DestinationServiceID,
 		LocalServiceAddress:    c.LocalServiceAddress,
 		LocalServicePort:       c.LocalServicePort,
+		LocalServicePorts:      c.LocalServicePorts.ToAPI(),
 		LocalServiceSocketPath: c.LocalServiceSocketPath,
 		Mode:                   api.ProxyMode(c.Mode),
 		TransparentProxy:       c.TransparentProxy.ToAPI(),
@@ -448,6 +459,10 @@ type Upstream struct {
 	DestinationPeer      string `json:",omitempty" alias:"destination_peer"`
 	DestinationName      string `alias:"destination_name"`
 
+	// DestinationPort is the name of the port of the destination service to
+	// connect to, when it isn't its main port.
+	DestinationPort string `json:",omitempty" alias:"destination_port"`
+
 	// Datacenter that the service discovery request should be run against. Note
 	// for prepared queries, the actual results might be from a different
 	// datacenter.
@@ -494,6 +509,7 @@ func (t *Upstream) UnmarshalJSON(data []byte) (err error) {
 		DestinationNamespaceSnake string `json:"destination_namespace"`
 		DestinationPeerSnake      string `json:"destination_peer"`
 		DestinationNameSnake      string `json:"destination_name"`
+		DestinationPortSnake      string `json:"destination_port"`
 
 		LocalBindAddressSnake string `json:"local_bind_address"`
 		LocalBindPortSnake    int    `json:"local_bind_port"`
@@ -525,6 +541,9 @@ func (t *Upstream) UnmarshalJSON(data []byte) (err error) {
 	if t.DestinationName == "" {
 		t.DestinationName = aux.DestinationNameSnake
 	}
+	if t.DestinationPort == "" {
+		t.DestinationPort = aux.DestinationPortSnake
+	}
 	if t.LocalBindAddress == "" {
 		t.LocalBindAddress = aux.LocalBindAddressSnake
 	}
@@ -562,6 +581,14 @@ func (u *Upstream) Validate() error {
 	if u.DestinationPeer != "" && u.Datacenter != "" {
 		return fmt.Errorf("upstream cannot specify both destination peer and datacenter")
 	}
+	if u.DestinationPort != "" {
+		if u.DestinationType == UpstreamDestTypePreparedQuery || u.DestinationPeer != "" {
+			return fmt.Errorf("upstream destination port is only supported for services in the local cluster")
+		}
+		if err := ValidateServicePortName(u.DestinationPort); err != nil {
+			return fmt.Errorf("upstream destination port is invalid: %w", err)
+		}
+	}
 
 	if u.LocalBindPort == 0 && u.LocalBindSocketPath == "" && !u.CentrallyConfigured {
 		return fmt.Errorf("upstream local bind port or local socket path must be defined and nonzero")
@@ -584,6 +611,7 @@ func (u *Upstream) ToAPI() api.Upstream {
 		DestinationPartition: u.DestinationPartition,
 		DestinationPeer:      u.DestinationPeer,
 		DestinationName:      u.DestinationName,
+		DestinationPort:      u.DestinationPort,
 		Datacenter:           u.Datacenter,
 		LocalBindAddress:     u.LocalBindAddress,
 		LocalBindPort:        u.LocalBindPort,
@@ -688,6 +716,7 @@ func UpstreamFromAPI(u api.Upstream) Upstream {
 		DestinationNamespace: u.DestinationNamespace,
 		DestinationPeer:      u.DestinationPeer,
 		DestinationName:      u.DestinationName,
+		DestinationPort:      u.DestinationPort,
 		Datacenter:           u.Datacenter,
 		LocalBindAddress:     u.LocalBindAddress,
 		LocalBindPort:        u.LocalBindPort,
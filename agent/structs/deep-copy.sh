@@ -52,4 +52,5 @@ deep-copy \
   -type UpstreamConfiguration \
   -type Status \
   -type BoundAPIGatewayConfigEntry \
+  -type APIGatewayPolicyConfigEntry \
   ./
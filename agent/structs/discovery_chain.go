@@ -41,6 +41,11 @@ type CompiledDiscoveryChain struct {
 	// EnvoyExtensions has a list of configurations for an extension that patches Envoy resources.
 	EnvoyExtensions []EnvoyExtension `json:",omitempty"`
 
+	// RetryPolicy is the default retry policy of the routes of the chain,
+	// from the service-defaults or proxy-defaults config entries. It is only
+	// set for HTTP-like protocols.
+	RetryPolicy *RouteRetryPolicy `json:",omitempty"`
+
 	// StartNode is the first key into the Nodes map that should be followed
 	// when walking the discovery chain.
 	StartNode string `json:",omitempty"`
@@ -178,6 +183,11 @@ type DiscoverySplit struct {
 // compiled form of ServiceResolverFailover
 type DiscoveryFailover struct {
 	Targets []string `json:",omitempty"`
+
+	// Priorities holds the priority group of each of the Targets, which are
+	// sorted in ascending order of priority. It is only set when at least
+	// one failover target has a priority.
+	Priorities []int `json:",omitempty"`
 }
 
 // DiscoveryTarget represents all of the inputs necessary to use a resolver
@@ -201,6 +211,11 @@ type DiscoveryTarget struct {
 
 	ConnectTimeout time.Duration `json:",omitempty"`
 
+	// MaxConcurrentStreams and MaxRequestsPerConnection are the defaults of
+	// the upstream connection pool of the service, if any.
+	MaxConcurrentStreams     int `json:",omitempty"`
+	MaxRequestsPerConnection int `json:",omitempty"`
+
 	// External is true if this target is outside of this consul cluster.
 	External bool `json:",omitempty"`
 
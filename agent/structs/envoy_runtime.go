@@ -0,0 +1,83 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// EnvoyRuntimeFlagsConfigKey is the key of the opaque proxy config option
+// that sets Envoy runtime flags in the static layer of the layered runtime of
+// the bootstrap configuration.
+const EnvoyRuntimeFlagsConfigKey = "envoy_runtime_flags"
+
+// envoyRuntimeFlagPrefixes are the prefixes of the Envoy runtime flags that
+// can be set with the envoy_runtime_flags proxy config option.
+var envoyRuntimeFlagPrefixes = []string{
+	"envoy.reloadable_features.",
+	"envoy.restart_features.",
+}
+
+// envoyRuntimeFlags are the other Envoy runtime flags that can be set with
+// the envoy_runtime_flags proxy config option.
+var envoyRuntimeFlags = map[string]bool{
+	"re2.max_program_size.error_level":           true,
+	"re2.max_program_size.warn_level":            true,
+	"overload.global_downstream_max_connections": true,
+}
+
+// validateEnvoyRuntimeFlagsConfig validates the envoy_runtime_flags option of
+// the given opaque proxy config, if it is set.
+func validateEnvoyRuntimeFlagsConfig(config map[string]interface{}) error {
+	raw, ok := config[EnvoyRuntimeFlagsConfigKey]
+	if !ok {
+		return nil
+	}
+
+	flags, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be a map of runtime flags to values, got %T", EnvoyRuntimeFlagsConfigKey, raw)
+	}
+	return ValidateEnvoyRuntimeFlags(flags)
+}
+
+// ValidateEnvoyRuntimeFlags validates the Envoy runtime flags set with the
+// envoy_runtime_flags proxy config option. Only the allowed flags can be set,
+// and their values must be booleans, numbers or strings.
+func ValidateEnvoyRuntimeFlags(flags map[string]interface{}) error {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var resultErr error
+	for _, name := range names {
+		if !isAllowedEnvoyRuntimeFlag(name) {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("%s: runtime flag %q is not allowed", EnvoyRuntimeFlagsConfigKey, name))
+			continue
+		}
+
+		switch flags[name].(type) {
+		case bool, string, int, int64, uint64, float64:
+		default:
+			resultErr = multierror.Append(resultErr, fmt.Errorf("%s: runtime flag %q must be a boolean, a number or a string, got %T", EnvoyRuntimeFlagsConfigKey, name, flags[name]))
+		}
+	}
+
+	return resultErr
+}
+
+func isAllowedEnvoyRuntimeFlag(name string) bool {
+	if envoyRuntimeFlags[name] {
+		return true
+	}
+	for _, prefix := range envoyRuntimeFlagPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
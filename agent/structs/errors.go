@@ -16,6 +16,7 @@ const (
 	errServiceNotFound            = "Service not found: "
 	errQueryNotFound              = "Query not found"
 	errLeaderNotTracked           = "Raft leader not found in server lookup mapping"
+	errPaginationConflict         = "Results changed since the first page was read, restart pagination"
 )
 
 var (
@@ -28,6 +29,7 @@ var (
 	ErrDCNotAvailable             = errors.New(errDCNotAvailable)
 	ErrQueryNotFound              = errors.New(errQueryNotFound)
 	ErrLeaderNotTracked           = errors.New(errLeaderNotTracked)
+	ErrPaginationConflict         = errors.New(errPaginationConflict)
 )
 
 func IsErrNoDCPath(err error) bool {
@@ -49,3 +51,7 @@ func IsErrRPCRateExceeded(err error) bool {
 func IsErrServiceNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errServiceNotFound)
 }
+
+func IsErrPaginationConflict(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errPaginationConflict)
+}
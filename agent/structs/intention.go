@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/lib/stringslice"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -62,6 +63,12 @@ type Intention struct {
 	// same level of tenancy (partition is local to cluster, peer is remote).
 	SourcePeer string `json:",omitempty"`
 
+	// SourceSamenessGroup is the name of the sameness group of the source,
+	// if any. The intention applies to the source service on every cluster
+	// peer that is a member of the group. It is only set by intentions
+	// defined in config entries.
+	SourceSamenessGroup string `json:",omitempty"`
+
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 
@@ -75,6 +82,13 @@ type Intention struct {
 	// service-intentions config entry directly.
 	Permissions []*IntentionPermission `bexpr:"-" json:",omitempty"`
 
+	// DestinationPorts are the names of the ports of the destination service
+	// the intention applies to. It applies to all of them if it is empty.
+	//
+	// NOTE: This field is not editable unless editing the underlying
+	// service-intentions config entry directly.
+	DestinationPorts []string `bexpr:"-" json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `bexpr:"-" codec:",omitempty" json:",omitempty"`
@@ -114,6 +128,7 @@ func (t *Intention) Clone() *Intention {
 			t2.Permissions = append(t2.Permissions, perm.Clone())
 		}
 	}
+	t2.DestinationPorts = stringslice.CloneStringSlice(t.DestinationPorts)
 	t2.Meta = cloneStringStringMap(t.Meta)
 	t2.Hash = nil
 	return &t2
@@ -299,6 +314,11 @@ func (x *Intention) Validate() error {
 			"Permissions must not be set when using the legacy APIs"))
 	}
 
+	if len(x.DestinationPorts) > 0 {
+		result = multierror.Append(result, fmt.Errorf(
+			"DestinationPorts must not be set when using the legacy APIs"))
+	}
+
 	switch x.SourceType {
 	case IntentionSourceConsul:
 	default:
@@ -408,6 +428,8 @@ func (x *Intention) String() string {
 	}
 	if x.SourcePeer != "" {
 		srcClusterPart = "peer(" + x.SourcePeer + ")/"
+	} else if x.SourceSamenessGroup != "" {
+		srcClusterPart = "sameness-group(" + x.SourceSamenessGroup + ")/"
 	}
 
 	var dstPartitionPart string
@@ -473,6 +495,7 @@ func (x *Intention) ToSourceIntention(legacy bool) *SourceIntention {
 		Name:             x.SourceName,
 		EnterpriseMeta:   *x.SourceEnterpriseMeta(),
 		Peer:             x.SourcePeer,
+		SamenessGroup:    x.SourceSamenessGroup,
 		Action:           x.Action,
 		Permissions:      nil, // explicitly not symmetric with the old APIs
 		Precedence:       0,   // Ignore, let it be computed.
@@ -485,6 +508,7 @@ func (x *Intention) ToSourceIntention(legacy bool) *SourceIntention {
 	}
 	if !legacy {
 		src.Permissions = x.Permissions
+		src.DestinationPorts = x.DestinationPorts
 	}
 	return src
 }
@@ -782,7 +806,7 @@ func (s IntentionPrecedenceSorter) Less(i, j int) bool {
 
 	// Tie break on lexicographic order of the tuple in canonical form:
 	//
-	//   (SrcPeer, SrcPxn, SrcNS, Src, DstPxn, DstNS, Dst)
+	//   (SrcPeer, SrcSamenessGroup, SrcPxn, SrcNS, Src, DstPxn, DstNS, Dst)
 	//
 	// This is arbitrary but it keeps sorting deterministic which is a nice
 	// property for consistency. It is arguably open to abuse if implementations
@@ -792,6 +816,11 @@ func (s IntentionPrecedenceSorter) Less(i, j int) bool {
 	if a.SourcePeer != b.SourcePeer {
 		return a.SourcePeer < b.SourcePeer
 	}
+	// Intentions for an explicit peer win over the ones for a sameness group
+	// that includes the peer.
+	if a.SourceSamenessGroup != b.SourceSamenessGroup {
+		return a.SourceSamenessGroup < b.SourceSamenessGroup
+	}
 	if a.SourcePartition != b.SourcePartition {
 		return a.SourcePartition < b.SourcePartition
 	}
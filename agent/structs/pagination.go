@@ -0,0 +1,65 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PageOptions selects a page of the results of the queries that support
+// pagination. The results are ordered by a unique key, and the cursor of a
+// page is the key of the last result of the previous one.
+//
+// All the pages are taken at the index of the first page, so that they are
+// consistent with each other. A page requested once the results have changed
+// since the first page is rejected with an error for which
+// IsErrPaginationConflict is true, and the caller has to restart from the
+// first page.
+type PageOptions struct {
+	// Limit is the maximum number of results to return. Zero returns all the
+	// results after the cursor.
+	Limit int
+
+	// Cursor is the NextCursor of the previous page, or empty for the first
+	// page.
+	Cursor string
+
+	// CursorIndex is the index of the results of the first page, set along
+	// with Cursor.
+	CursorIndex uint64
+}
+
+// IsPaginated returns whether a page of the results was requested.
+func (p PageOptions) IsPaginated() bool {
+	return p.Limit > 0 || p.Cursor != ""
+}
+
+// CheckIndex returns an error if the results, which are at the given index,
+// changed since the first page was taken. A lower index only means that the
+// server taking the page is behind the one that took the first page.
+func (p PageOptions) CheckIndex(index uint64) error {
+	if p.Cursor == "" || index <= p.CursorIndex {
+		return nil
+	}
+	return fmt.Errorf("%w (index %d, now %d)", ErrPaginationConflict, p.CursorIndex, index)
+}
+
+// Page returns the bounds of the page of keys, which must be sorted and
+// unique, and the cursor of the next page, which is empty on the last page.
+func (p PageOptions) Page(keys []string) (start, end int, next string) {
+	if p.Cursor != "" {
+		start = sort.SearchStrings(keys, p.Cursor)
+		if start < len(keys) && keys[start] == p.Cursor {
+			start++
+		}
+	}
+
+	end = len(keys)
+	if p.Limit > 0 && start+p.Limit < end {
+		end = start + p.Limit
+	}
+
+	if end < len(keys) {
+		next = keys[end-1]
+	}
+	return start, end, next
+}
@@ -0,0 +1,62 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageOptions_Page(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+
+	// Walk through all of the pages.
+	var (
+		got   []string
+		page  = PageOptions{Limit: 2}
+		pages int
+	)
+	for {
+		start, end, next := page.Page(keys)
+		got = append(got, keys[start:end]...)
+		pages++
+
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	require.Equal(t, keys, got)
+	require.Equal(t, 3, pages)
+
+	// The cursor doesn't have to be one of the keys, so the page continues
+	// after a key that was removed.
+	start, end, next := PageOptions{Limit: 2, Cursor: "bb"}.Page(keys)
+	require.Equal(t, []string{"c", "d"}, keys[start:end])
+	require.Equal(t, "d", next)
+
+	// Without a limit the rest of the results are returned.
+	start, end, next = PageOptions{Cursor: "b"}.Page(keys)
+	require.Equal(t, []string{"c", "d", "e"}, keys[start:end])
+	require.Empty(t, next)
+
+	// Past the last key the page is empty.
+	start, end, next = PageOptions{Limit: 2, Cursor: "z"}.Page(keys)
+	require.Empty(t, keys[start:end])
+	require.Empty(t, next)
+}
+
+func TestPageOptions_CheckIndex(t *testing.T) {
+	// The first page can be taken at any index.
+	require.NoError(t, PageOptions{Limit: 2}.CheckIndex(7))
+
+	// The next pages must be taken at the index of the first one.
+	page := PageOptions{Limit: 2, Cursor: "b", CursorIndex: 7}
+	require.NoError(t, page.CheckIndex(7))
+
+	err := page.CheckIndex(8)
+	require.Error(t, err)
+	require.True(t, IsErrPaginationConflict(err))
+
+	// A lower index doesn't mean the results changed.
+	require.NoError(t, page.CheckIndex(6))
+}
@@ -31,6 +31,15 @@ type ExportedServiceList struct {
 	// for service mesh purposes as defined in the exported-services
 	// configuration entry.
 	DiscoChains map[ServiceName]ExportedDiscoveryChainInfo
+
+	// ConfigEntries is a map of service names to the kinds of config entries
+	// that are replicated alongside them as defined in the exported-services
+	// configuration entry.
+	ConfigEntries map[ServiceName][]string
+
+	// RedactedFields is a map of service names to the instance data fields
+	// that are removed before the service is replicated to the peer.
+	RedactedFields map[ServiceName][]string
 }
 
 // NOTE: this is not serialized via msgpack so it can be changed without concern.
@@ -1,4 +1,4 @@
-// generated by deep-copy -pointer-receiver -o ./structs.deepcopy.go -type APIGatewayListener -type BoundAPIGatewayListener -type CARoot -type CheckServiceNode -type CheckType -type CompiledDiscoveryChain -type ConnectProxyConfig -type DiscoveryFailover -type DiscoveryGraphNode -type DiscoveryResolver -type DiscoveryRoute -type DiscoverySplit -type ExposeConfig -type GatewayService -type GatewayServiceTLSConfig -type HTTPHeaderModifiers -type HTTPRouteConfigEntry -type HashPolicy -type HealthCheck -type IndexedCARoots -type IngressListener -type InlineCertificateConfigEntry -type Intention -type IntentionPermission -type LoadBalancer -type MeshConfigEntry -type MeshDirectionalTLSConfig -type MeshTLSConfig -type Node -type NodeService -type PeeringServiceMeta -type ServiceConfigEntry -type ServiceConfigResponse -type ServiceConnect -type ServiceDefinition -type ServiceResolverConfigEntry -type ServiceResolverFailover -type ServiceRoute -type ServiceRouteDestination -type ServiceRouteMatch -type TCPRouteConfigEntry -type Upstream -type UpstreamConfiguration -type Status -type BoundAPIGatewayConfigEntry ./; DO NOT EDIT.
+// generated by deep-copy -pointer-receiver -o ./structs.deepcopy.go -type APIGatewayListener -type BoundAPIGatewayListener -type CARoot -type CheckServiceNode -type CheckType -type CompiledDiscoveryChain -type ConnectProxyConfig -type DiscoveryFailover -type DiscoveryGraphNode -type DiscoveryResolver -type DiscoveryRoute -type DiscoverySplit -type ExposeConfig -type GatewayService -type GatewayServiceTLSConfig -type HTTPHeaderModifiers -type HTTPRouteConfigEntry -type HashPolicy -type HealthCheck -type IndexedCARoots -type IngressListener -type InlineCertificateConfigEntry -type Intention -type IntentionPermission -type LoadBalancer -type MeshConfigEntry -type MeshDirectionalTLSConfig -type MeshTLSConfig -type Node -type NodeService -type PeeringServiceMeta -type ServiceConfigEntry -type ServiceConfigResponse -type ServiceConnect -type ServiceDefinition -type ServiceResolverConfigEntry -type ServiceResolverFailover -type ServiceRoute -type ServiceRouteDestination -type ServiceRouteMatch -type TCPRouteConfigEntry -type Upstream -type UpstreamConfiguration -type Status -type BoundAPIGatewayConfigEntry -type APIGatewayPolicyConfigEntry ./; DO NOT EDIT.
 
 package structs
 
@@ -18,6 +18,10 @@ func (o *APIGatewayListener) DeepCopy() *APIGatewayListener {
 		cp.TLS.CipherSuites = make([]types.TLSCipherSuite, len(o.TLS.CipherSuites))
 		copy(cp.TLS.CipherSuites, o.TLS.CipherSuites)
 	}
+	if o.AccessLogs != nil {
+		cp.AccessLogs = new(AccessLogsConfig)
+		*cp.AccessLogs = *o.AccessLogs
+	}
 	return &cp
 }
 
@@ -108,6 +112,18 @@ func (o *CompiledDiscoveryChain) DeepCopy() *CompiledDiscoveryChain {
 			}
 		}
 	}
+	if o.RetryPolicy != nil {
+		cp.RetryPolicy = new(RouteRetryPolicy)
+		*cp.RetryPolicy = *o.RetryPolicy
+		if o.RetryPolicy.RetryOn != nil {
+			cp.RetryPolicy.RetryOn = make([]string, len(o.RetryPolicy.RetryOn))
+			copy(cp.RetryPolicy.RetryOn, o.RetryPolicy.RetryOn)
+		}
+		if o.RetryPolicy.RetryOnStatusCodes != nil {
+			cp.RetryPolicy.RetryOnStatusCodes = make([]uint32, len(o.RetryPolicy.RetryOnStatusCodes))
+			copy(cp.RetryPolicy.RetryOnStatusCodes, o.RetryPolicy.RetryOnStatusCodes)
+		}
+	}
 	if o.Nodes != nil {
 		cp.Nodes = make(map[string]*DiscoveryGraphNode, len(o.Nodes))
 		for k2, v2 := range o.Nodes {
@@ -125,6 +141,12 @@ func (o *CompiledDiscoveryChain) DeepCopy() *CompiledDiscoveryChain {
 			if v2 != nil {
 				cp_Targets_v2 = new(DiscoveryTarget)
 				*cp_Targets_v2 = *v2
+				if v2.Subset.Meta != nil {
+					cp_Targets_v2.Subset.Meta = make(map[string]string, len(v2.Subset.Meta))
+					for k5, v5 := range v2.Subset.Meta {
+						cp_Targets_v2.Subset.Meta[k5] = v5
+					}
+				}
 			}
 			cp.Targets[k2] = cp_Targets_v2
 		}
@@ -177,6 +199,10 @@ func (o *DiscoveryFailover) DeepCopy() *DiscoveryFailover {
 		cp.Targets = make([]string, len(o.Targets))
 		copy(cp.Targets, o.Targets)
 	}
+	if o.Priorities != nil {
+		cp.Priorities = make([]int, len(o.Priorities))
+		copy(cp.Priorities, o.Priorities)
+	}
 	return &cp
 }
 
@@ -463,6 +489,10 @@ func (o *IngressListener) DeepCopy() *IngressListener {
 			copy(cp.TLS.CipherSuites, o.TLS.CipherSuites)
 		}
 	}
+	if o.AccessLogs != nil {
+		cp.AccessLogs = new(AccessLogsConfig)
+		*cp.AccessLogs = *o.AccessLogs
+	}
 	if o.Services != nil {
 		cp.Services = make([]IngressService, len(o.Services))
 		copy(cp.Services, o.Services)
@@ -587,6 +617,14 @@ func (o *MeshConfigEntry) DeepCopy() *MeshConfigEntry {
 	if o.HTTP != nil {
 		cp.HTTP = new(MeshHTTPConfig)
 		*cp.HTTP = *o.HTTP
+		if o.HTTP.Incoming != nil {
+			cp.HTTP.Incoming = new(MeshDirectionalHTTPConfig)
+			*cp.HTTP.Incoming = *o.HTTP.Incoming
+			if o.HTTP.Incoming.RequestNormalization != nil {
+				cp.HTTP.Incoming.RequestNormalization = new(RequestNormalizationMeshConfig)
+				*cp.HTTP.Incoming.RequestNormalization = *o.HTTP.Incoming.RequestNormalization
+			}
+		}
 	}
 	if o.Peering != nil {
 		cp.Peering = new(PeeringMeshConfig)
@@ -706,6 +744,14 @@ func (o *ServiceConfigEntry) DeepCopy() *ServiceConfigEntry {
 			cp.Destination.Addresses = make([]string, len(o.Destination.Addresses))
 			copy(cp.Destination.Addresses, o.Destination.Addresses)
 		}
+		if o.Destination.HealthCheck != nil {
+			cp.Destination.HealthCheck = new(DestinationHealthCheck)
+			*cp.Destination.HealthCheck = *o.Destination.HealthCheck
+		}
+		if o.Destination.DNS != nil {
+			cp.Destination.DNS = new(DestinationDNSConfig)
+			*cp.Destination.DNS = *o.Destination.DNS
+		}
 	}
 	if o.EnvoyExtensions != nil {
 		cp.EnvoyExtensions = make([]EnvoyExtension, len(o.EnvoyExtensions))
@@ -719,6 +765,26 @@ func (o *ServiceConfigEntry) DeepCopy() *ServiceConfigEntry {
 			}
 		}
 	}
+	if o.AccessLogs != nil {
+		cp.AccessLogs = new(AccessLogsConfig)
+		*cp.AccessLogs = *o.AccessLogs
+	}
+	if o.RetryPolicy != nil {
+		cp.RetryPolicy = new(RouteRetryPolicy)
+		*cp.RetryPolicy = *o.RetryPolicy
+		if o.RetryPolicy.RetryOn != nil {
+			cp.RetryPolicy.RetryOn = make([]string, len(o.RetryPolicy.RetryOn))
+			copy(cp.RetryPolicy.RetryOn, o.RetryPolicy.RetryOn)
+		}
+		if o.RetryPolicy.RetryOnStatusCodes != nil {
+			cp.RetryPolicy.RetryOnStatusCodes = make([]uint32, len(o.RetryPolicy.RetryOnStatusCodes))
+			copy(cp.RetryPolicy.RetryOnStatusCodes, o.RetryPolicy.RetryOnStatusCodes)
+		}
+	}
+	if o.UpstreamConnectionPool != nil {
+		cp.UpstreamConnectionPool = new(UpstreamConnectionPool)
+		*cp.UpstreamConnectionPool = *o.UpstreamConnectionPool
+	}
 	if o.Meta != nil {
 		cp.Meta = make(map[string]string, len(o.Meta))
 		for k2, v2 := range o.Meta {
@@ -769,6 +835,14 @@ func (o *ServiceConfigResponse) DeepCopy() *ServiceConfigResponse {
 		cp.Destination.Addresses = make([]string, len(o.Destination.Addresses))
 		copy(cp.Destination.Addresses, o.Destination.Addresses)
 	}
+	if o.Destination.HealthCheck != nil {
+		cp.Destination.HealthCheck = new(DestinationHealthCheck)
+		*cp.Destination.HealthCheck = *o.Destination.HealthCheck
+	}
+	if o.Destination.DNS != nil {
+		cp.Destination.DNS = new(DestinationDNSConfig)
+		*cp.Destination.DNS = *o.Destination.DNS
+	}
 	if o.Meta != nil {
 		cp.Meta = make(map[string]string, len(o.Meta))
 		for k2, v2 := range o.Meta {
@@ -853,7 +927,15 @@ func (o *ServiceResolverConfigEntry) DeepCopy() *ServiceResolverConfigEntry {
 	if o.Subsets != nil {
 		cp.Subsets = make(map[string]ServiceResolverSubset, len(o.Subsets))
 		for k2, v2 := range o.Subsets {
-			cp.Subsets[k2] = v2
+			var cp_Subsets_v2 ServiceResolverSubset
+			cp_Subsets_v2 = v2
+			if v2.Meta != nil {
+				cp_Subsets_v2.Meta = make(map[string]string, len(v2.Meta))
+				for k4, v4 := range v2.Meta {
+					cp_Subsets_v2.Meta[k4] = v4
+				}
+			}
+			cp.Subsets[k2] = cp_Subsets_v2
 		}
 	}
 	if o.Redirect != nil {
@@ -1100,3 +1182,71 @@ func (o *BoundAPIGatewayConfigEntry) DeepCopy() *BoundAPIGatewayConfigEntry {
 	}
 	return &cp
 }
+
+// DeepCopy generates a deep copy of *APIGatewayPolicyConfigEntry
+func (o *APIGatewayPolicyConfigEntry) DeepCopy() *APIGatewayPolicyConfigEntry {
+	var cp APIGatewayPolicyConfigEntry = *o
+	if o.Listeners != nil {
+		cp.Listeners = make([]APIGatewayListenerPolicy, len(o.Listeners))
+		copy(cp.Listeners, o.Listeners)
+		for i2 := range o.Listeners {
+			if o.Listeners[i2].RateLimit != nil {
+				cp.Listeners[i2].RateLimit = new(APIGatewayRateLimit)
+				*cp.Listeners[i2].RateLimit = *o.Listeners[i2].RateLimit
+				if o.Listeners[i2].RateLimit.Local != nil {
+					cp.Listeners[i2].RateLimit.Local = new(APIGatewayLocalRateLimit)
+					*cp.Listeners[i2].RateLimit.Local = *o.Listeners[i2].RateLimit.Local
+				}
+				if o.Listeners[i2].RateLimit.Routes != nil {
+					cp.Listeners[i2].RateLimit.Routes = make([]APIGatewayRouteRateLimit, len(o.Listeners[i2].RateLimit.Routes))
+					copy(cp.Listeners[i2].RateLimit.Routes, o.Listeners[i2].RateLimit.Routes)
+				}
+				if o.Listeners[i2].RateLimit.Global != nil {
+					cp.Listeners[i2].RateLimit.Global = new(APIGatewayGlobalRateLimit)
+					*cp.Listeners[i2].RateLimit.Global = *o.Listeners[i2].RateLimit.Global
+				}
+			}
+			if o.Listeners[i2].JWT != nil {
+				cp.Listeners[i2].JWT = new(APIGatewayJWTPolicy)
+				*cp.Listeners[i2].JWT = *o.Listeners[i2].JWT
+				if o.Listeners[i2].JWT.Providers != nil {
+					cp.Listeners[i2].JWT.Providers = make([]APIGatewayJWTProvider, len(o.Listeners[i2].JWT.Providers))
+					copy(cp.Listeners[i2].JWT.Providers, o.Listeners[i2].JWT.Providers)
+					for i6 := range o.Listeners[i2].JWT.Providers {
+						if o.Listeners[i2].JWT.Providers[i6].Audiences != nil {
+							cp.Listeners[i2].JWT.Providers[i6].Audiences = make([]string, len(o.Listeners[i2].JWT.Providers[i6].Audiences))
+							copy(cp.Listeners[i2].JWT.Providers[i6].Audiences, o.Listeners[i2].JWT.Providers[i6].Audiences)
+						}
+						if o.Listeners[i2].JWT.Providers[i6].ClaimsToHeaders != nil {
+							cp.Listeners[i2].JWT.Providers[i6].ClaimsToHeaders = make([]APIGatewayJWTClaimToHeader, len(o.Listeners[i2].JWT.Providers[i6].ClaimsToHeaders))
+							copy(cp.Listeners[i2].JWT.Providers[i6].ClaimsToHeaders, o.Listeners[i2].JWT.Providers[i6].ClaimsToHeaders)
+						}
+					}
+				}
+				if o.Listeners[i2].JWT.AllowMissingPaths != nil {
+					cp.Listeners[i2].JWT.AllowMissingPaths = make([]string, len(o.Listeners[i2].JWT.AllowMissingPaths))
+					copy(cp.Listeners[i2].JWT.AllowMissingPaths, o.Listeners[i2].JWT.AllowMissingPaths)
+				}
+			}
+			if o.Listeners[i2].ExtAuthz != nil {
+				cp.Listeners[i2].ExtAuthz = new(APIGatewayExtAuthz)
+				*cp.Listeners[i2].ExtAuthz = *o.Listeners[i2].ExtAuthz
+				if o.Listeners[i2].ExtAuthz.AllowedHeaders != nil {
+					cp.Listeners[i2].ExtAuthz.AllowedHeaders = make([]string, len(o.Listeners[i2].ExtAuthz.AllowedHeaders))
+					copy(cp.Listeners[i2].ExtAuthz.AllowedHeaders, o.Listeners[i2].ExtAuthz.AllowedHeaders)
+				}
+				if o.Listeners[i2].ExtAuthz.DisabledPaths != nil {
+					cp.Listeners[i2].ExtAuthz.DisabledPaths = make([]string, len(o.Listeners[i2].ExtAuthz.DisabledPaths))
+					copy(cp.Listeners[i2].ExtAuthz.DisabledPaths, o.Listeners[i2].ExtAuthz.DisabledPaths)
+				}
+			}
+		}
+	}
+	if o.Meta != nil {
+		cp.Meta = make(map[string]string, len(o.Meta))
+		for k2, v2 := range o.Meta {
+			cp.Meta[k2] = v2
+		}
+	}
+	return &cp
+}
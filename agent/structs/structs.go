@@ -16,6 +16,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/serf/coordinate"
+	"github.com/klauspost/compress/zstd"
 	"github.com/mitchellh/hashstructure"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -84,6 +85,13 @@ const (
 	PeeringTrustBundleDeleteType                = 39
 	PeeringSecretsWriteType                     = 40
 	RaftLogVerifierCheckpoint                   = 41 // Only used for log verifier, no-op on FSM.
+	PeeringConfigEntriesWriteType               = 42
+	PeeringConfigEntriesDeleteType              = 43
+	CatalogBatchRequestType                     = 44
+	ServiceTombstoneRequestType                 = 45
+	ACLTokenUsageSetRequestType                 = 46
+	ACLTokenBulkRequestType                     = 47
+	CompressedRequestType                       = 48 // Wraps a compressed entry of another type.
 )
 
 const (
@@ -151,6 +159,13 @@ var requestTypeStrings = map[MessageType]string{
 	PeeringTrustBundleDeleteType:    "PeeringTrustBundleDelete",
 	PeeringSecretsWriteType:         "PeeringSecret",
 	RaftLogVerifierCheckpoint:       "RaftLogVerifierCheckpoint",
+	PeeringConfigEntriesWriteType:   "PeeringConfigEntries",
+	PeeringConfigEntriesDeleteType:  "PeeringConfigEntriesDelete",
+	CatalogBatchRequestType:         "CatalogBatch",
+	ServiceTombstoneRequestType:     "ServiceTombstone",
+	ACLTokenUsageSetRequestType:     "ACLTokenUsage",
+	ACLTokenBulkRequestType:         "ACLTokenBulk",
+	CompressedRequestType:           "Compressed",
 }
 
 const (
@@ -167,6 +182,9 @@ const (
 	// ServiceMaintPrefix is the prefix for a service in maintenance mode.
 	ServiceMaintPrefix = "_service_maintenance:"
 
+	// ServiceDrainingPrefix is the prefix for a service that is draining.
+	ServiceDrainingPrefix = "_service_draining:"
+
 	// The meta key prefix reserved for Consul's internal use
 	MetaKeyReservedPrefix = "consul-"
 
@@ -189,6 +207,25 @@ const (
 	// MetaExternalSource is the metadata key used when a resource is managed by a source outside Consul like nomad/k8s
 	MetaExternalSource = "external-source"
 
+	// MetaExternalNode is the node metadata key set to "true" on the nodes of
+	// external services, which aren't running a Consul agent.
+	MetaExternalNode = "external-node"
+
+	// MetaExternalProbe is the node metadata key set to "true" on the external
+	// nodes that the servers must ping, when they monitor the health of the
+	// external services.
+	MetaExternalProbe = "external-probe"
+
+	// MetaDNSALPN is the service metadata key holding a comma separated list of
+	// ALPN protocol IDs that are advertised in SVCB and HTTPS DNS records.
+	MetaDNSALPN = "dns-alpn"
+
+	// MetaDNSSubnets is the node metadata key holding a comma separated list
+	// of client subnets, in CIDR notation, that are local to the node. DNS
+	// queries carrying an EDNS client subnet within one of them are answered
+	// with the node's service instances first.
+	MetaDNSSubnets = "dns-subnets"
+
 	// TaggedAddressVirtualIP is the key used to store tagged virtual IPs generated by Consul.
 	TaggedAddressVirtualIP = "consul-virtual"
 
@@ -483,6 +520,18 @@ func (r *RegisterRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// RequestServiceName returns the name of the service registered, if any.
+func (r *RegisterRequest) RequestServiceName() string {
+	if r.Service == nil {
+		return ""
+	}
+	return r.Service.Service
+}
+
+func (r *RegisterRequest) RequestPeerName() string {
+	return r.PeerName
+}
+
 // ChangesNode returns true if the given register request changes the given
 // node, which can be nil. This only looks for changes to the node record itself,
 // not any of the health checks.
@@ -526,6 +575,10 @@ type DeregisterRequest struct {
 	PeerName           string
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	WriteRequest
+
+	// Reason is recorded in the tombstones of the service instances that are
+	// removed. If empty, it is derived from what is being deregistered.
+	Reason string `json:",omitempty"`
 }
 
 func (r *DeregisterRequest) RequestDatacenter() string {
@@ -547,6 +600,45 @@ func (r *DeregisterRequest) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// CatalogBatchRequest is used by the Catalog endpoint to register and
+// deregister many nodes, services and checks atomically in a single
+// Raft transaction. Registrations are applied before deregistrations.
+// The datacenter and token of the batch are used for every request in it.
+// A batch can have at most MaxCatalogBatchEntries entries.
+type CatalogBatchRequest struct {
+	Datacenter string
+	Register   []*RegisterRequest   `json:",omitempty"`
+	Deregister []*DeregisterRequest `json:",omitempty"`
+	WriteRequest
+}
+
+// MaxCatalogBatchEntries is the largest number of registrations and
+// deregistrations a CatalogBatchRequest may have, as the whole batch is
+// committed as a single Raft entry.
+const MaxCatalogBatchEntries = 128
+
+func (r *CatalogBatchRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ServiceTombstonesRequest is used to query the service instances that were
+// recently removed from the catalog.
+type ServiceTombstonesRequest struct {
+	Datacenter string
+
+	// ServiceName limits the results to instances of a single service. If
+	// empty, the tombstones of all services are returned.
+	ServiceName string
+
+	PeerName           string
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	QueryOptions
+}
+
+func (r *ServiceTombstonesRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 // QuerySource is used to pass along information about the source node
 // in queries so that we can adjust the response based on its network
 // coordinates.
@@ -590,12 +682,20 @@ type DCSpecificRequest struct {
 	PeerName           string
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	QueryOptions
+
+	// PageOptions paginates the list of services, and is ignored by the
+	// other queries.
+	PageOptions
 }
 
 func (r *DCSpecificRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *DCSpecificRequest) RequestPeerName() string {
+	return r.PeerName
+}
+
 func (r *DCSpecificRequest) CacheInfo() cache.RequestInfo {
 	info := cache.RequestInfo{
 		Token:          r.Token,
@@ -607,13 +707,14 @@ func (r *DCSpecificRequest) CacheInfo() cache.RequestInfo {
 		MustRevalidate: r.MustRevalidate,
 	}
 
-	// To calculate the cache key we only hash the node meta filters and the bexpr filter.
-	// The datacenter is handled by the cache framework. The other fields are
+	// To calculate the cache key we only hash the node meta filters, the bexpr
+	// filter and the page. The datacenter is handled by the cache framework. The other fields are
 	// not, but should not be used in any cache types.
 	v, err := hashstructure.Hash([]interface{}{
 		r.NodeMetaFilters,
 		r.Filter,
 		r.EnterpriseMeta,
+		r.PageOptions,
 	}, nil)
 	if err == nil {
 		// If there is an error, we don't set the key. A blank key forces
@@ -633,6 +734,10 @@ type OperatorUsageRequest struct {
 	DCSpecificRequest
 
 	Global bool
+
+	// History requests the daily samples of the service usage of the last
+	// 30 days, along with the current usage.
+	History bool
 }
 
 type ServiceDumpRequest struct {
@@ -649,6 +754,10 @@ func (r *ServiceDumpRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *ServiceDumpRequest) RequestPeerName() string {
+	return r.PeerName
+}
+
 func (r *ServiceDumpRequest) CacheInfo() cache.RequestInfo {
 	info := cache.RequestInfo{
 		Token:          r.Token,
@@ -665,8 +774,8 @@ func (r *ServiceDumpRequest) CacheInfo() cache.RequestInfo {
 	if r.UseServiceKind {
 		keyKind = r.ServiceKind
 	}
-	// To calculate the cache key we only hash the node meta filters and the bexpr filter.
-	// The datacenter is handled by the cache framework. The other fields are
+	// To calculate the cache key we only hash the node meta filters, the bexpr
+	// filter and the page. The datacenter is handled by the cache framework. The other fields are
 	// not, but should not be used in any cache types.
 	v, err := hashstructure.Hash([]interface{}{
 		keyKind,
@@ -742,14 +851,31 @@ type ServiceSpecificRequest struct {
 	// especially when the service might not be written into the catalog that way.
 	MergeCentralConfig bool
 
+	// OnlyPassing if true will only return the health of the service
+	// instances whose checks are all passing. It is set by the paginated
+	// queries, so that the instances are filtered before they are paged.
+	OnlyPassing bool
+
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	QueryOptions
+
+	// PageOptions paginates the health of the service instances, and is
+	// ignored by the other queries.
+	PageOptions
 }
 
 func (r *ServiceSpecificRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *ServiceSpecificRequest) RequestServiceName() string {
+	return r.ServiceName
+}
+
+func (r *ServiceSpecificRequest) RequestPeerName() string {
+	return r.PeerName
+}
+
 func (r *ServiceSpecificRequest) CacheInfo() cache.RequestInfo {
 	info := cache.RequestInfo{
 		Token:          r.Token,
@@ -789,6 +915,8 @@ func (r *ServiceSpecificRequest) CacheInfo() cache.RequestInfo {
 		r.Ingress,
 		r.ServiceKind,
 		r.MergeCentralConfig,
+		r.OnlyPassing,
+		r.PageOptions,
 	}, nil)
 	if err == nil {
 		// If there is an error, we don't set the key. A blank key forces
@@ -823,6 +951,10 @@ func (r *NodeSpecificRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *NodeSpecificRequest) RequestPeerName() string {
+	return r.PeerName
+}
+
 func (r *NodeSpecificRequest) CacheInfo() cache.RequestInfo {
 	info := cache.RequestInfo{
 		Token:          r.Token,
@@ -1041,6 +1173,7 @@ type ServiceNode struct {
 	ServiceWeights           Weights
 	ServiceMeta              map[string]string
 	ServicePort              int
+	ServicePorts             ServicePorts `json:",omitempty"`
 	ServiceSocketPath        string
 	ServiceEnableTagOverride bool
 	ServiceProxy             ConnectProxyConfig
@@ -1098,6 +1231,7 @@ func (s *ServiceNode) PartialClone() *ServiceNode {
 		ServiceSocketPath:        s.ServiceSocketPath,
 		ServiceTaggedAddresses:   svcTaggedAddrs,
 		ServicePort:              s.ServicePort,
+		ServicePorts:             s.ServicePorts,
 		ServiceMeta:              nsmeta,
 		ServiceWeights:           s.ServiceWeights,
 		ServiceEnableTagOverride: s.ServiceEnableTagOverride,
@@ -1122,6 +1256,7 @@ func (s *ServiceNode) ToNodeService() *NodeService {
 		Address:           s.ServiceAddress,
 		TaggedAddresses:   s.ServiceTaggedAddresses,
 		Port:              s.ServicePort,
+		Ports:             s.ServicePorts,
 		SocketPath:        s.ServiceSocketPath,
 		Meta:              s.ServiceMeta,
 		Weights:           &s.ServiceWeights,
@@ -1247,6 +1382,69 @@ func (a ServiceAddress) ToAPIServiceAddress() api.ServiceAddress {
 	return api.ServiceAddress{Address: a.Address, Port: a.Port}
 }
 
+// validServicePortName is the format of the names of the ports of a service,
+// which are used in the ALPN protocols of the mesh.
+var validServicePortName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ServicePort is a named port of a service instance, exposed in addition to
+// its main port, e.g. the admin port of an application.
+type ServicePort struct {
+	Name string
+	Port int
+}
+
+// ServicePorts are the named ports of a service instance.
+type ServicePorts []ServicePort
+
+// Get returns the port with the given name.
+func (p ServicePorts) Get(name string) (ServicePort, bool) {
+	for _, port := range p {
+		if port.Name == name {
+			return port, true
+		}
+	}
+	return ServicePort{}, false
+}
+
+// Validate checks the names of the ports are valid and unique.
+func (p ServicePorts) Validate() error {
+	seen := make(map[string]struct{}, len(p))
+	for _, port := range p {
+		if err := ValidateServicePortName(port.Name); err != nil {
+			return err
+		}
+		if _, ok := seen[port.Name]; ok {
+			return fmt.Errorf("port name %q is used more than once", port.Name)
+		}
+		seen[port.Name] = struct{}{}
+		if port.Port < 1 || port.Port > 65535 {
+			return fmt.Errorf("port %q must be between 1 and 65535", port.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateServicePortName checks the format of the name of a port.
+func ValidateServicePortName(name string) error {
+	if !validServicePortName.MatchString(name) {
+		return fmt.Errorf("port name %q must only contain lowercase alphanumeric characters or '-' "+
+			"and start and end with an alphanumeric character", name)
+	}
+	return nil
+}
+
+// ToAPI returns the api structs with the same fields.
+func (p ServicePorts) ToAPI() []api.ServicePort {
+	if len(p) == 0 {
+		return nil
+	}
+	ports := make([]api.ServicePort, 0, len(p))
+	for _, port := range p {
+		ports = append(ports, api.ServicePort{Name: port.Name, Port: port.Port})
+	}
+	return ports
+}
+
 // NodeService is a service provided by a node
 type NodeService struct {
 	// Kind is the kind of service this is. Different kinds of services may
@@ -1265,6 +1463,11 @@ type NodeService struct {
 	Weights           *Weights
 	EnableTagOverride bool
 
+	// Ports are the named ports of the service instance, in addition to Port.
+	// Their health checks, intentions and the listeners of the sidecar proxy
+	// can be scoped to one of them.
+	Ports ServicePorts `json:",omitempty"`
+
 	// Proxy is the configuration set for Kind = connect-proxy. It is mandatory in
 	// that case and an error to be set for any other kind. This config is part of
 	// a proxy service definition. ProxyConfig may be a more natural name here, but
@@ -1543,6 +1746,16 @@ func (s *NodeService) ValidateForAgent() error {
 			bindAddrs[addr] = struct{}{}
 		}
 
+		if len(s.Proxy.LocalServicePorts) > 0 {
+			if s.Proxy.LocalServiceSocketPath != "" {
+				result = multierror.Append(result, fmt.Errorf(
+					"Proxy.LocalServicePorts cannot be set with Proxy.LocalServiceSocketPath"))
+			}
+			if err := s.Proxy.LocalServicePorts.Validate(); err != nil {
+				result = multierror.Append(result, fmt.Errorf("Proxy.LocalServicePorts: %w", err))
+			}
+		}
+
 		var knownListeners = make(map[int]bool)
 		for _, path := range s.Proxy.Expose.Paths {
 			if path.Path == "" {
@@ -1607,6 +1820,20 @@ func (s *NodeService) ValidateForAgent() error {
 		if len(s.Proxy.Upstreams) != 0 {
 			result = multierror.Append(result, fmt.Errorf("The Proxy.Upstreams configuration is invalid for a %s", s.Kind))
 		}
+
+		if len(s.Proxy.LocalServicePorts) != 0 {
+			result = multierror.Append(result, fmt.Errorf("The Proxy.LocalServicePorts configuration is invalid for a %s", s.Kind))
+		}
+	}
+
+	// Named ports validation
+	if len(s.Ports) > 0 {
+		if s.Kind != ServiceKindTypical {
+			result = multierror.Append(result, fmt.Errorf("Ports can only be set for typical services, not for a %s", s.Kind))
+		}
+		if err := s.Ports.Validate(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("Ports: %w", err))
+		}
 	}
 
 	// Nested sidecar validation
@@ -1642,6 +1869,7 @@ func (s *NodeService) IsSame(other *NodeService) bool {
 		!reflect.DeepEqual(s.Tags, other.Tags) ||
 		s.Address != other.Address ||
 		s.Port != other.Port ||
+		!reflect.DeepEqual(s.Ports, other.Ports) ||
 		s.SocketPath != other.SocketPath ||
 		!reflect.DeepEqual(s.TaggedAddresses, other.TaggedAddresses) ||
 		!reflect.DeepEqual(s.Weights, other.Weights) ||
@@ -1680,6 +1908,7 @@ func (s *ServiceNode) IsSameService(other *ServiceNode) bool {
 		s.ServiceAddress != other.ServiceAddress ||
 		!reflect.DeepEqual(s.ServiceTaggedAddresses, other.ServiceTaggedAddresses) ||
 		s.ServicePort != other.ServicePort ||
+		!reflect.DeepEqual(s.ServicePorts, other.ServicePorts) ||
 		!reflect.DeepEqual(s.ServiceMeta, other.ServiceMeta) ||
 		!reflect.DeepEqual(s.ServiceWeights, other.ServiceWeights) ||
 		s.ServiceEnableTagOverride != other.ServiceEnableTagOverride ||
@@ -1715,6 +1944,7 @@ func (s *NodeService) ToServiceNode(node string) *ServiceNode {
 		ServiceAddress:           s.Address,
 		ServiceTaggedAddresses:   s.TaggedAddresses,
 		ServicePort:              s.Port,
+		ServicePorts:             s.Ports,
 		ServiceSocketPath:        s.SocketPath,
 		ServiceMeta:              s.Meta,
 		ServiceWeights:           theWeights,
@@ -1757,6 +1987,10 @@ type HealthCheck struct {
 	ServiceTags []string      // optional service tags
 	Type        string        // Check type: http/ttl/tcp/udp/etc
 
+	// PortName is the name of the port of the service the check is scoped
+	// to. It is empty if the check applies to the whole service instance.
+	PortName string `json:",omitempty"`
+
 	Interval string // from definition
 	Timeout  string // from definition
 
@@ -1946,6 +2180,7 @@ func (c *HealthCheck) IsSame(other *HealthCheck) bool {
 		c.ServiceID != other.ServiceID ||
 		c.ServiceName != other.ServiceName ||
 		!reflect.DeepEqual(c.ServiceTags, other.ServiceTags) ||
+		c.PortName != other.PortName ||
 		!reflect.DeepEqual(c.Definition, other.Definition) ||
 		c.PeerName != other.PeerName ||
 		!c.EnterpriseMeta.IsSame(&other.EnterpriseMeta) {
@@ -1970,6 +2205,7 @@ func (c *HealthCheck) CheckType() *CheckType {
 		Status:  c.Status,
 		Notes:   c.Notes,
 
+		PortName:                       c.PortName,
 		ScriptArgs:                     c.Definition.ScriptArgs,
 		AliasNode:                      c.Definition.AliasNode,
 		AliasService:                   c.Definition.AliasService,
@@ -2030,6 +2266,17 @@ func (csn *CheckServiceNode) BestAddress(wan bool) (uint64, string, int) {
 	return idx, addr, port
 }
 
+// IsDraining returns true if the service instance has been marked as draining,
+// meaning it should not be sent new connections while existing ones finish.
+func (csn *CheckServiceNode) IsDraining() bool {
+	for _, check := range csn.Checks {
+		if strings.HasPrefix(string(check.CheckID), ServiceDrainingPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (csn *CheckServiceNode) CanRead(authz acl.Authorizer) acl.EnforcementDecision {
 	if csn.Node == nil || csn.Service == nil {
 		return acl.Deny
@@ -2050,6 +2297,39 @@ func (csn *CheckServiceNode) CanRead(authz acl.Authorizer) acl.EnforcementDecisi
 
 type CheckServiceNodes []CheckServiceNode
 
+// CatalogChangeOp is the kind of a CatalogChangeEvent.
+type CatalogChangeOp string
+
+const (
+	// CatalogChangeRegister is used when a service instance is registered or
+	// updated, including changes to its node and to its health checks.
+	CatalogChangeRegister CatalogChangeOp = "register"
+
+	// CatalogChangeDeregister is used when a service instance is deregistered.
+	CatalogChangeDeregister CatalogChangeOp = "deregister"
+
+	// CatalogChangeReset is used when the stream could not be resumed from
+	// the requested cursor. It is followed by a snapshot of every service
+	// instance, which replaces any state built from earlier events.
+	CatalogChangeReset CatalogChangeOp = "reset"
+
+	// CatalogChangeSnapshotDone is used once all the register events of the
+	// snapshot sent at the start of a stream have been sent.
+	CatalogChangeSnapshotDone CatalogChangeOp = "snapshot-done"
+)
+
+// CatalogChangeEvent is an event of the stream of changes to the catalog.
+type CatalogChangeEvent struct {
+	// Index is the raft index of the change, which can be used as the cursor
+	// to resume the stream. Events with the same index are sent together.
+	Index uint64
+	Op    CatalogChangeOp
+
+	// Entry is the service instance that changed, along with its node and
+	// health checks. It is only set for register and deregister events.
+	Entry *CheckServiceNode `json:",omitempty"`
+}
+
 func (csns CheckServiceNodes) DeepCopy() CheckServiceNodes {
 	dup := make(CheckServiceNodes, len(csns))
 	for idx, v := range csns {
@@ -2250,12 +2530,21 @@ type IndexedServices struct {
 	// In various situations we need to know the meta that the services are for - in particular
 	// this is needed to be able to properly filter the list based on ACLs
 	acl.EnterpriseMeta
+
+	// NextCursor is the cursor of the next page of a paginated query, empty
+	// on the last page.
+	NextCursor string
+
 	QueryMeta
 }
 
 type Usage struct {
 	Usage map[string]ServiceUsage
 
+	// History is a map of datacenter -> daily samples of the service usage,
+	// oldest first. It is only set if the request asked for it.
+	History map[string][]ServiceUsageSample `json:",omitempty"`
+
 	QueryMeta
 }
 
@@ -2265,9 +2554,25 @@ type ServiceUsage struct {
 	ServiceInstances         int
 	ConnectServiceInstances  map[string]int
 	BillableServiceInstances int
+
+	// ServiceKindInstances is the number of service instances of each kind,
+	// with the services of no kind counted as "typical".
+	ServiceKindInstances map[string]int
+
+	// ExternalServiceInstances is the number of service instances
+	// registered on external nodes.
+	ExternalServiceInstances int
+
 	EnterpriseServiceUsage
 }
 
+// ServiceUsageSample is the service usage of a datacenter at a point in
+// time.
+type ServiceUsageSample struct {
+	Time time.Time
+	ServiceUsage
+}
+
 // PeeredServiceName is a basic tuple of ServiceName and peer
 type PeeredServiceName struct {
 	ServiceName ServiceName
@@ -2332,6 +2637,59 @@ type IndexedServiceNodes struct {
 	QueryMeta
 }
 
+const (
+	// ServiceTombstoneReasonDeregistered is the reason recorded when a
+	// service instance is deregistered on its own.
+	ServiceTombstoneReasonDeregistered = "service-deregistered"
+
+	// ServiceTombstoneReasonNodeDeregistered is the reason recorded when a
+	// service instance is removed along with its node.
+	ServiceTombstoneReasonNodeDeregistered = "node-deregistered"
+)
+
+// ServiceTombstone records a service instance that was recently removed from
+// the catalog, so that it can be told apart from one that never existed.
+// Tombstones are kept until the service tombstone TTL has passed, or until the
+// service instance is registered again.
+type ServiceTombstone struct {
+	Node        string
+	ServiceID   string
+	ServiceName string
+	ServiceKind ServiceKind `json:",omitempty"`
+
+	// If not empty, PeerName represents the peer that the service instance was
+	// imported from.
+	PeerName string `json:",omitempty"`
+
+	// Reason describes why the service instance was removed.
+	Reason string
+
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash" bexpr:"-"`
+
+	// RaftIndex holds the index at which the service instance was removed.
+	RaftIndex `bexpr:"-"`
+}
+
+func (t *ServiceTombstone) FillAuthzContext(ctx *acl.AuthorizerContext) {
+	if ctx == nil {
+		return
+	}
+	ctx.Peer = t.PeerName
+
+	t.EnterpriseMeta.FillAuthzContext(ctx)
+}
+
+func (t *ServiceTombstone) PeerOrEmpty() string {
+	return t.PeerName
+}
+
+type ServiceTombstones []*ServiceTombstone
+
+type IndexedServiceTombstones struct {
+	Tombstones ServiceTombstones
+	QueryMeta
+}
+
 type IndexedNodeServices struct {
 	// TODO: This should not be a pointer, see comments in
 	// agent/catalog_endpoint.go.
@@ -2351,6 +2709,11 @@ type IndexedHealthChecks struct {
 
 type IndexedCheckServiceNodes struct {
 	Nodes CheckServiceNodes
+
+	// NextCursor is the cursor of the next page of a paginated query, empty
+	// on the last page.
+	NextCursor string
+
 	QueryMeta
 }
 
@@ -2415,6 +2778,11 @@ type ServiceTopology struct {
 type IndexedConfigEntries struct {
 	Kind    string
 	Entries []ConfigEntry
+
+	// NextCursor is the cursor of the next page of a paginated query, empty
+	// on the last page.
+	NextCursor string
+
 	QueryMeta
 }
 
@@ -2563,18 +2931,38 @@ type DirEntry struct {
 	Value     []byte
 	Session   string `json:",omitempty"`
 
+	// ExpirationTime is the time after which the entry is deleted. It is
+	// set by the leader from ExpirationTTL when the entry is written.
+	ExpirationTime *time.Time `json:",omitempty"`
+
+	// ExpirationTTL is how long the entry lives after it is written. It is
+	// cleared and used to set ExpirationTime by the leader.
+	ExpirationTTL time.Duration `json:",omitempty"`
+
+	// Compressed is true if Value is compressed with zstd. The servers
+	// compress values above their compression threshold before committing
+	// them, and decompress them when they are read.
+	Compressed bool `json:",omitempty"`
+
 	acl.EnterpriseMeta `bexpr:"-"`
 	RaftIndex
 }
 
+// HasExpirationTime returns true if the entry is deleted after some time.
+func (d *DirEntry) HasExpirationTime() bool {
+	return d.ExpirationTime != nil && !d.ExpirationTime.IsZero()
+}
+
 // Returns a clone of the given directory entry.
 func (d *DirEntry) Clone() *DirEntry {
 	return &DirEntry{
-		LockIndex: d.LockIndex,
-		Key:       d.Key,
-		Flags:     d.Flags,
-		Value:     d.Value,
-		Session:   d.Session,
+		LockIndex:      d.LockIndex,
+		Key:            d.Key,
+		Flags:          d.Flags,
+		Value:          d.Value,
+		Session:        d.Session,
+		ExpirationTime: d.ExpirationTime,
+		Compressed:     d.Compressed,
 		RaftIndex: RaftIndex{
 			CreateIndex: d.CreateIndex,
 			ModifyIndex: d.ModifyIndex,
@@ -2588,7 +2976,47 @@ func (d *DirEntry) Equal(o *DirEntry) bool {
 		d.Key == o.Key &&
 		d.Flags == o.Flags &&
 		bytes.Equal(d.Value, o.Value) &&
-		d.Session == o.Session
+		d.Session == o.Session &&
+		d.Compressed == o.Compressed &&
+		d.HasExpirationTime() == o.HasExpirationTime() &&
+		(!d.HasExpirationTime() || d.ExpirationTime.Equal(*o.ExpirationTime))
+}
+
+// CompressValue compresses the value of the entry if it is at least
+// threshold bytes long and compressing it makes it smaller. A threshold of
+// zero disables compression.
+func (d *DirEntry) CompressValue(threshold int) error {
+	if d.Compressed || threshold <= 0 || len(d.Value) < threshold {
+		return nil
+	}
+
+	compressed := zstdEncoder.EncodeAll(d.Value, make([]byte, 0, len(d.Value)))
+	if len(compressed) >= len(d.Value) {
+		return nil
+	}
+
+	d.Value = compressed
+	d.Compressed = true
+	return nil
+}
+
+// Decompressed returns the entry with its value decompressed. The entry
+// itself is returned if its value isn't compressed, otherwise a clone is
+// returned so entries owned by the state store are never modified.
+func (d *DirEntry) Decompressed() (*DirEntry, error) {
+	if !d.Compressed {
+		return d, nil
+	}
+
+	value, err := zstdDecoder.DecodeAll(d.Value, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress value of key %q: %v", d.Key, err)
+	}
+
+	clone := d.Clone()
+	clone.Value = value
+	clone.Compressed = false
+	return clone, nil
 }
 
 // IDValue implements the state.singleValueID interface for indexing.
@@ -2598,6 +3026,32 @@ func (d *DirEntry) IDValue() string {
 
 type DirEntries []*DirEntry
 
+// Decompressed returns the entries with their values decompressed. The
+// slice itself is returned if none of the values are compressed.
+func (d DirEntries) Decompressed() (DirEntries, error) {
+	var out DirEntries
+	for i, e := range d {
+		if !e.Compressed {
+			if out != nil {
+				out[i] = e
+			}
+			continue
+		}
+		if out == nil {
+			out = make(DirEntries, len(d))
+			copy(out, d[:i])
+		}
+		var err error
+		if out[i], err = e.Decompressed(); err != nil {
+			return nil, err
+		}
+	}
+	if out == nil {
+		return d, nil
+	}
+	return out, nil
+}
+
 // KVSRequest is used to operate on the Key-Value store
 type KVSRequest struct {
 	Datacenter string
@@ -2645,6 +3099,69 @@ type IndexedKeyList struct {
 	QueryMeta
 }
 
+// LockContentionRequest is used to request the contention of the locks and
+// semaphores under a key prefix.
+type LockContentionRequest struct {
+	Datacenter string
+	Prefix     string
+	acl.EnterpriseMeta
+	QueryOptions
+}
+
+func (r *LockContentionRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// LockContention describes the holders and the waiters of a lock or a
+// semaphore built on the KV store, as seen by the leader.
+type LockContention struct {
+	// Key is the key of the lock, or the prefix of the semaphore.
+	Key string
+
+	// Semaphore is true if Key is the prefix of a semaphore.
+	Semaphore bool
+
+	// Limit is the number of holders allowed by a semaphore.
+	Limit int `json:",omitempty"`
+
+	// Holders are the sessions holding the lock or the semaphore.
+	Holders []LockSession
+
+	// Waiters are the sessions that tried to acquire the lock, or that are
+	// contending for the semaphore, and are still waiting for it.
+	Waiters []LockSession
+
+	// QueueDepth is the number of waiters.
+	QueueDepth int
+
+	// Acquisitions is the number of times the lock or the semaphore was
+	// acquired, and Contentions the number of attempts to acquire the lock
+	// that failed because it was held.
+	Acquisitions uint64
+	Contentions  uint64
+
+	// MeanWaitTime and MaxWaitTime are computed from the time waiters took to
+	// acquire the lock or the semaphore.
+	MeanWaitTime time.Duration
+	MaxWaitTime  time.Duration
+
+	acl.EnterpriseMeta
+}
+
+// LockSession is a session holding or waiting for a lock.
+type LockSession struct {
+	Session string
+
+	// Since is when the session acquired the lock or started waiting for it.
+	// It is unset if that happened before the current leader was elected.
+	Since *time.Time `json:",omitempty"`
+}
+
+type IndexedLockContention struct {
+	Locks []*LockContention
+	QueryMeta
+}
+
 type SessionBehavior string
 
 const (
@@ -2877,6 +3394,28 @@ func Encode(t MessageType, msg interface{}) ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+// CompressEntry compresses an encoded raft log entry, including its type
+// prefix, into an entry of the CompressedRequestType.
+func CompressEntry(enc *zstd.Encoder, buf []byte) []byte {
+	out := make([]byte, 1, len(buf)/2)
+	out[0] = uint8(CompressedRequestType)
+	return enc.EncodeAll(buf, out)
+}
+
+// DecompressEntry returns the encoded raft log entry, including its type
+// prefix, compressed in an entry of the CompressedRequestType. Note that this
+// assumes the leading byte indicating the type has already been stripped off.
+func DecompressEntry(buf []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(buf, nil)
+}
+
+// zstdDecoder decompresses the compressed raft log entries and KV values.
+// DecodeAll can be called concurrently.
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
+// zstdEncoder compresses the KV values. EncodeAll can be called concurrently.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
 func EncodeProtoInterface(t MessageType, message interface{}) ([]byte, error) {
 	if marshaller, ok := message.(proto.Message); ok {
 		return EncodeProto(t, marshaller)
@@ -2975,9 +3514,56 @@ func (r *KeyringResponses) New() interface{} {
 	return new(KeyringResponses)
 }
 
+const (
+	// KeyringRotationIdle means no key is being rotated.
+	KeyringRotationIdle = "idle"
+
+	// KeyringRotationInstalled means the new key was installed on all the
+	// members of the pools and is waiting to be promoted.
+	KeyringRotationInstalled = "installed"
+
+	// KeyringRotationPromoted means the new key is the primary key of all the
+	// members of the pools and the previous one is waiting to be removed.
+	KeyringRotationPromoted = "promoted"
+)
+
+// KeyringRotationStatus reports the state of the automated rotation of the
+// gossip encryption key of a datacenter.
+type KeyringRotationStatus struct {
+	// Enabled is whether the leader rotates the key.
+	Enabled bool
+
+	// Interval is how often the key is rotated.
+	Interval time.Duration
+
+	// GracePeriod is how long the new key must be installed before being
+	// promoted, and how long the previous key is kept once it was demoted.
+	GracePeriod time.Duration
+
+	// Phase is the step of the rotation in progress, one of the
+	// KeyringRotation* constants.
+	Phase string
+
+	// PhaseStartedAt is when the current phase started.
+	PhaseStartedAt time.Time
+
+	// LastRotation is when the last rotation completed, or when the rotation
+	// was enabled if no key was rotated yet.
+	LastRotation time.Time
+
+	// NextRotation is when the next rotation is due to start.
+	NextRotation time.Time
+
+	// LastError is the error that prevented the last attempt to move the
+	// rotation forward, if any. It is reset once the attempt succeeds.
+	LastError string
+
+	QueryMeta
+}
+
 // String converts message type int to string
 func (m MessageType) String() string {
-	s, ok := requestTypeStrings[m]
+	s, ok := requestTypeStrings[m&^IgnoreUnknownTypeFlag]
 	if ok {
 		return s
 	}
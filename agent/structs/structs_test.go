@@ -1844,6 +1844,40 @@ func TestStructs_DirEntry_Clone(t *testing.T) {
 	}
 }
 
+func TestStructs_DirEntry_Compression(t *testing.T) {
+	value := []byte(strings.Repeat("abc", 100))
+
+	e := &DirEntry{Key: "hello", Value: []byte("short")}
+	require.NoError(t, e.CompressValue(16))
+	require.False(t, e.Compressed)
+
+	e = &DirEntry{Key: "hello", Value: value}
+	require.NoError(t, e.CompressValue(0))
+	require.False(t, e.Compressed)
+
+	require.NoError(t, e.CompressValue(16))
+	require.True(t, e.Compressed)
+	require.Less(t, len(e.Value), len(value))
+
+	compressed := e.Value
+	d, err := e.Decompressed()
+	require.NoError(t, err)
+	require.False(t, d.Compressed)
+	require.Equal(t, value, d.Value)
+	require.True(t, e.Compressed, "the original entry should be left compressed")
+	require.Equal(t, compressed, e.Value)
+
+	plain := &DirEntry{Key: "plain", Value: []byte("short")}
+	entries, err := DirEntries{plain, e}.Decompressed()
+	require.NoError(t, err)
+	require.Same(t, plain, entries[0])
+	require.Equal(t, value, entries[1].Value)
+
+	e.Value = []byte("garbage")
+	_, err = e.Decompressed()
+	require.Error(t, err)
+}
+
 func TestStructs_ValidateServiceAndNodeMetadata(t *testing.T) {
 	tooMuchMeta := make(map[string]string)
 	for i := 0; i < metaMaxKeyPairs+1; i++ {
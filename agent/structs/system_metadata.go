@@ -30,6 +30,7 @@ const (
 	SystemMetadataIntentionFormatLegacyValue   = "legacy"
 	SystemMetadataVirtualIPsEnabled            = "virtual-ips"
 	SystemMetadataTermGatewayVirtualIPsEnabled = "virtual-ips-term-gateway"
+	SystemMetadataKeyringRotationKey           = "keyring-rotation"
 )
 
 type SystemMetadataEntry struct {
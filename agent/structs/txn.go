@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/raft"
 )
 
 // TxnKVOp is used to define a single operation on the KVS inside a
@@ -13,6 +14,26 @@ import (
 type TxnKVOp struct {
 	Verb   api.KVOp
 	DirEnt DirEntry
+
+	// MaxValueSize is the largest value a patch operation may produce. It's
+	// set by the agent that received the request from its kv_max_value_size
+	// so that the leader and the FSM enforce the same limit.
+	MaxValueSize uint64
+
+	// CompressionThreshold is the size from which the value produced by a
+	// patch operation is compressed. It's set by the leader, once every server
+	// can decompress KV values, so that the FSM compresses the patched value as
+	// the leader does for a set.
+	CompressionThreshold int
+}
+
+// PatchLimit returns the largest value a patch operation may produce. Ops
+// without a MaxValueSize are limited to the default kv_max_value_size.
+func (op *TxnKVOp) PatchLimit() int {
+	if op.MaxValueSize == 0 {
+		return raft.SuggestedMaxDataSize
+	}
+	return int(op.MaxValueSize)
 }
 
 // TxnKVResult is used to define the result of a single operation on the KVS
@@ -0,0 +1,76 @@
+//go:build !windows
+// +build !windows
+
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd, from
+// https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html
+const listenFDsStart = 3
+
+// Activated returns the sockets passed by systemd with socket activation, by
+// the name given to them with the FileDescriptorName= option of the socket
+// units. It returns nil if the agent was not socket activated.
+//
+// The environment variables of socket activation are unset so that they are
+// not inherited by the processes started by the agent.
+func Activated() (*Sockets, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	sockets := &Sockets{
+		Listeners:   make(map[string][]net.Listener),
+		PacketConns: make(map[string][]net.PacketConn),
+	}
+	for i := 0; i < count; i++ {
+		fd := listenFDsStart + i
+		syscall.CloseOnExec(fd)
+
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		if err := sockets.add(name, os.NewFile(uintptr(fd), name)); err != nil {
+			sockets.Close()
+			return nil, fmt.Errorf("invalid socket %q passed by systemd: %w", name, err)
+		}
+	}
+	return sockets, nil
+}
+
+func (s *Sockets) add(name string, f *os.File) error {
+	// The file is duplicated by net.FileListener and net.FilePacketConn.
+	defer f.Close()
+
+	if l, err := net.FileListener(f); err == nil {
+		s.Listeners[name] = append(s.Listeners[name], l)
+		return nil
+	}
+	pc, err := net.FilePacketConn(f)
+	if err != nil {
+		return err
+	}
+	s.PacketConns[name] = append(s.PacketConns[name], pc)
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package systemd
+
+// Activated returns nil since there is no socket activation on Windows.
+func Activated() (*Sockets, error) {
+	return nil, nil
+}
@@ -0,0 +1,41 @@
+package systemd
+
+import "net"
+
+// Sockets are the sockets passed by systemd with socket activation.
+type Sockets struct {
+	// Listeners are the stream sockets, by name.
+	Listeners map[string][]net.Listener
+
+	// PacketConns are the datagram sockets, by name.
+	PacketConns map[string][]net.PacketConn
+}
+
+// Close closes the sockets.
+func (s *Sockets) Close() {
+	if s == nil {
+		return
+	}
+	for _, ls := range s.Listeners {
+		for _, l := range ls {
+			l.Close()
+		}
+	}
+	for _, pcs := range s.PacketConns {
+		for _, pc := range pcs {
+			pc.Close()
+		}
+	}
+}
+
+// Take returns the sockets with a name and removes them, so that the sockets
+// left once all the known names were taken can be closed.
+func (s *Sockets) Take(name string) ([]net.Listener, []net.PacketConn) {
+	if s == nil {
+		return nil, nil
+	}
+	ls, pcs := s.Listeners[name], s.PacketConns[name]
+	delete(s.Listeners, name)
+	delete(s.PacketConns, name)
+	return ls, pcs
+}
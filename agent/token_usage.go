@@ -0,0 +1,165 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	middleware "github.com/hashicorp/consul/agent/grpc-middleware"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib"
+)
+
+const (
+	// tokenUsageReportInterval is how often the agent reports the tokens
+	// presented to its HTTP and gRPC APIs to the servers.
+	tokenUsageReportInterval = time.Minute
+
+	// tokenUsageMaxEntries bounds the number of tokens tracked between two
+	// reports. The uses of new tokens are dropped once it is reached.
+	tokenUsageMaxEntries = 4096
+)
+
+// tokenUsageTracker keeps the last use of each token presented to the HTTP
+// and gRPC APIs since the last report, so that a busy token costs a single
+// entry per report no matter how many requests it was used for.
+type tokenUsageTracker struct {
+	lock    sync.Mutex
+	entries map[string]structs.ACLTokenUsageEntry
+
+	// active are the tokens of the calls in progress, such as the xDS streams
+	// of the proxies, which are reported as used by every report until the
+	// calls end.
+	active map[string]*activeTokenUse
+}
+
+type activeTokenUse struct {
+	addr  string
+	calls int
+}
+
+func newTokenUsageTracker() *tokenUsageTracker {
+	return &tokenUsageTracker{
+		entries: make(map[string]structs.ACLTokenUsageEntry),
+		active:  make(map[string]*activeTokenUse),
+	}
+}
+
+// record tracks that secretID was presented by a client at addr.
+func (t *tokenUsageTracker) record(secretID, addr string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.entries[secretID]; !ok && len(t.entries) >= tokenUsageMaxEntries {
+		return
+	}
+	t.entries[secretID] = structs.ACLTokenUsageEntry{
+		SecretID: secretID,
+		Time:     now,
+		Addr:     addr,
+	}
+}
+
+// recordCall tracks that secretID was presented by a client at addr for a
+// call that is in progress until the returned function is called.
+func (t *tokenUsageTracker) recordCall(secretID, addr string, now time.Time) (done func()) {
+	t.record(secretID, addr, now)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	use, ok := t.active[secretID]
+	if !ok {
+		use = &activeTokenUse{}
+		t.active[secretID] = use
+	}
+	use.addr = addr
+	use.calls++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+
+			use.calls--
+			if use.calls == 0 && t.active[secretID] == use {
+				delete(t.active, secretID)
+			}
+		})
+	}
+}
+
+// drain returns the tracked uses and starts tracking again from scratch. The
+// tokens of the calls in progress are returned as used at now.
+func (t *tokenUsageTracker) drain(now time.Time) []structs.ACLTokenUsageEntry {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for secretID, use := range t.active {
+		t.entries[secretID] = structs.ACLTokenUsageEntry{
+			SecretID: secretID,
+			Time:     now,
+			Addr:     use.addr,
+		}
+	}
+	if len(t.entries) == 0 {
+		return nil
+	}
+	entries := make([]structs.ACLTokenUsageEntry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, entry)
+	}
+	t.entries = make(map[string]structs.ACLTokenUsageEntry)
+	return entries
+}
+
+// reportTokenUsage is a long-running loop that periodically reports the tokens
+// presented to the HTTP and gRPC APIs to the servers, which record the last
+// time each token was used. Closing the agent's shutdownChannel will cause
+// this to exit.
+func (a *Agent) reportTokenUsage() {
+	for {
+		select {
+		case <-time.After(tokenUsageReportInterval + lib.RandomStagger(tokenUsageReportInterval/4)):
+			entries := a.tokenUsage.drain(time.Now())
+			if len(entries) == 0 {
+				continue
+			}
+
+			agentToken := a.tokens.AgentToken()
+			req := structs.ACLTokenUsageReportRequest{
+				Datacenter:     a.config.Datacenter,
+				Node:           a.config.NodeName,
+				Entries:        entries,
+				EnterpriseMeta: *a.AgentEnterpriseMeta(),
+				WriteRequest:   structs.WriteRequest{Token: agentToken},
+			}
+			var reply struct{}
+			if err := a.RPC(context.Background(), "ACL.TokenUsageReport", &req, &reply); err != nil {
+				if acl.IsErrPermissionDenied(err) {
+					accessorID := a.aclAccessorID(agentToken)
+					a.logger.Warn("Token usage report blocked by ACLs", "accessorID", acl.AliasIfAnonymousToken(accessorID))
+				} else {
+					a.logger.Error("Token usage report error", "error", err)
+				}
+			}
+		case <-a.shutdownCh:
+			return
+		}
+	}
+}
+
+// tokenUsageInterceptor returns the interceptor tracking the tokens presented
+// to the gRPC API, which include the ones of the xDS streams of the proxies.
+func (a *Agent) tokenUsageInterceptor() *middleware.TokenUsageInterceptor {
+	if !a.config.ACLsEnabled {
+		return nil
+	}
+	return &middleware.TokenUsageInterceptor{
+		Record: func(secretID, addr string) func() {
+			return a.tokenUsage.recordCall(secretID, addr, time.Now())
+		},
+	}
+}
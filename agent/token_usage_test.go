@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenUsageTracker(t *testing.T) {
+	tracker := newTokenUsageTracker()
+	require.Nil(t, tracker.drain(time.Now()))
+
+	now := time.Now()
+	tracker.record("secret1", "10.0.0.1", now)
+	tracker.record("secret2", "10.0.0.2", now)
+	tracker.record("secret1", "10.0.0.3", now.Add(time.Second))
+
+	// Only the last use of each token is kept.
+	entries := tracker.drain(time.Now())
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		switch entry.SecretID {
+		case "secret1":
+			require.Equal(t, "10.0.0.3", entry.Addr)
+			require.Equal(t, now.Add(time.Second), entry.Time)
+		case "secret2":
+			require.Equal(t, "10.0.0.2", entry.Addr)
+		default:
+			t.Fatalf("unexpected entry %#v", entry)
+		}
+	}
+
+	// Draining starts tracking again from scratch.
+	require.Nil(t, tracker.drain(time.Now()))
+
+	// The uses of new tokens are dropped once the limit is reached, but the
+	// tracked ones are still updated.
+	for i := 0; i < tokenUsageMaxEntries; i++ {
+		tracker.record(string(rune(i)), "10.0.0.1", now)
+	}
+	tracker.record("new", "10.0.0.1", now)
+	tracker.record(string(rune(0)), "10.0.0.4", now)
+	entries = tracker.drain(time.Now())
+	require.Len(t, entries, tokenUsageMaxEntries)
+	for _, entry := range entries {
+		require.NotEqual(t, "new", entry.SecretID)
+		if entry.SecretID == string(rune(0)) {
+			require.Equal(t, "10.0.0.4", entry.Addr)
+		}
+	}
+}
+
+func TestTokenUsageTracker_recordCall(t *testing.T) {
+	tracker := newTokenUsageTracker()
+
+	start := time.Now()
+	done1 := tracker.recordCall("secret", "10.0.0.1", start)
+	done2 := tracker.recordCall("secret", "10.0.0.2", start)
+
+	// The token of the calls in progress is reported by every drain.
+	for i := 1; i <= 2; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+		require.Equal(t, []structs.ACLTokenUsageEntry{
+			{SecretID: "secret", Time: now, Addr: "10.0.0.2"},
+		}, tracker.drain(now))
+	}
+
+	// The token stays in use until all its calls are done.
+	done1()
+	done1()
+	require.Len(t, tracker.drain(time.Now()), 1)
+	done2()
+	require.Nil(t, tracker.drain(time.Now()))
+}
+
+func TestHTTPHandlers_RecordTokenUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Drop the uses recorded while the agent was starting.
+	a.tokenUsage.drain(time.Now())
+
+	req, _ := http.NewRequest("GET", "/v1/agent/self", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	req.Header.Add("X-Consul-Token", "root")
+	a.srv.h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Requests without a token aren't recorded.
+	req, _ = http.NewRequest("GET", "/v1/agent/self", nil)
+	a.srv.h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := a.tokenUsage.drain(time.Now())
+	require.Len(t, entries, 1)
+	require.Equal(t, "root", entries[0].SecretID)
+	require.Equal(t, "10.0.0.1", entries[0].Addr)
+}
@@ -54,7 +54,7 @@ func decodeValue(rawKV interface{}) error {
 // isWrite returns true if the given operation alters the state store.
 func isWrite(op api.KVOp) bool {
 	switch op {
-	case api.KVSet, api.KVDelete, api.KVDeleteCAS, api.KVDeleteTree, api.KVCAS, api.KVLock, api.KVUnlock:
+	case api.KVSet, api.KVDelete, api.KVDeleteCAS, api.KVDeleteTree, api.KVCAS, api.KVLock, api.KVUnlock, api.KVPatch:
 		return true
 	}
 	return false
@@ -77,8 +77,9 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 	// the TxnMaxReqLen limit is above the raft's suggested threshold, large
 	// transactions are automatically set to attempt a chunking apply.
 	// Performance may degrade and warning messages may appear.
-	maxTxnLen := int64(s.agent.config.TxnMaxReqLen)
-	kvMaxValueSize := int64(s.agent.config.KVMaxValueSize)
+	limits := s.agent.getHTTPLimits()
+	maxTxnLen := int64(limits.txnMaxReqLen)
+	kvMaxValueSize := int64(limits.kvMaxValueSize)
 
 	// For backward compatibility, KVMaxValueSize is used as the max txn request
 	// length if it is configured greater than TxnMaxReqLen or its default
@@ -135,7 +136,7 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 			if int64(size) > kvMaxValueSize {
 				return nil, 0, HTTPError{
 					StatusCode: http.StatusRequestEntityTooLarge,
-					Reason:     fmt.Sprintf("Value for key %q is too large (%d > %d bytes)", in.KV.Key, size, s.agent.config.KVMaxValueSize),
+					Reason:     fmt.Sprintf("Value for key %q is too large (%d > %d bytes)", in.KV.Key, size, kvMaxValueSize),
 				}
 			}
 
@@ -160,6 +161,7 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 							ModifyIndex: in.KV.Index,
 						},
 					},
+					MaxValueSize: uint64(kvMaxValueSize),
 				},
 			}
 			opsRPC = append(opsRPC, out)
@@ -72,7 +72,11 @@ func (s *ResourceGenerator) clustersFromSnapshot(cfgSnap *proxycfg.ConfigSnapsho
 		if err != nil {
 			return nil, err
 		}
-		return res, nil
+		policyClusters, err := s.makeGatewayPolicyClusters(cfgSnap)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, policyClusters...), nil
 	default:
 		return nil, fmt.Errorf("Invalid service kind: %v", cfgSnap.Kind)
 	}
@@ -91,6 +95,18 @@ func (s *ResourceGenerator) clustersFromSnapshotConnectProxy(cfgSnap *proxycfg.C
 	}
 	clusters = append(clusters, appCluster)
 
+	// Include a cluster for each named port of the local service, unless the
+	// local cluster is overridden.
+	if cfg, _ := ParseProxyConfig(cfgSnap.Proxy.Config); cfg.LocalClusterJSON == "" {
+		for _, port := range cfgSnap.Proxy.LocalServicePorts {
+			portCluster, err := s.makeAppCluster(cfgSnap, localAppPortClusterName(port.Name), "", port.Port)
+			if err != nil {
+				return nil, err
+			}
+			clusters = append(clusters, portCluster)
+		}
+	}
+
 	if cfgSnap.Proxy.Mode == structs.ProxyModeTransparent {
 		passthroughs, err := makePassthroughClusters(cfgSnap)
 		if err != nil {
@@ -340,6 +356,7 @@ func clusterNameForDestination(cfgSnap *proxycfg.ConfigSnapshot, name string, ad
 }
 
 func destinationSpecificServiceName(name string, address string) string {
+	address = strings.ReplaceAll(address, "*", "wildcard")
 	address = strings.ReplaceAll(address, ":", "-")
 	address = strings.ReplaceAll(address, ".", "-")
 	return fmt.Sprintf("%s.%s", address, name)
@@ -696,10 +713,18 @@ func (s *ResourceGenerator) makeDestinationClusters(cfgSnap *proxycfg.ConfigSnap
 			}
 
 			var cluster *envoy_cluster_v3.Cluster
-			if structs.IsIP(address) {
+			switch {
+			case structs.IsIP(address):
 				cluster = s.makeExternalIPCluster(cfgSnap, opts)
-			} else {
+			case structs.IsWildcardHostname(address):
+				var err error
+				cluster, err = s.makeDynamicForwardProxyCluster(cfgSnap, opts, dest)
+				if err != nil {
+					return nil, err
+				}
+			default:
 				cluster = s.makeExternalHostnameCluster(cfgSnap, opts)
+				injectDestinationHostnameConfig(cluster, address, dest)
 			}
 			if err := s.injectGatewayDestinationAddons(cfgSnap, cluster, svcName); err != nil {
 				return nil, err
@@ -710,6 +735,72 @@ func (s *ResourceGenerator) makeDestinationClusters(cfgSnap *proxycfg.ConfigSnap
 	return clusters, nil
 }
 
+// injectDestinationHostnameConfig applies the DNS resolution and active health
+// checking settings of a Destination to the cluster of one of its hostnames.
+func injectDestinationHostnameConfig(c *envoy_cluster_v3.Cluster, hostname string, dest structs.DestinationConfig) {
+	if dns := dest.DNS; dns != nil {
+		if dns.RefreshRate > 0 {
+			c.DnsRefreshRate = durationpb.New(dns.RefreshRate)
+		}
+		c.RespectDnsTtl = dns.RespectTTL
+		if dns.DiscoveryType == structs.DestinationDNSDiscoveryStrict {
+			c.ClusterDiscoveryType = &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_STRICT_DNS}
+		}
+	}
+
+	hc := dest.HealthCheck
+	if hc == nil {
+		return
+	}
+
+	interval, timeout := hc.Interval, hc.Timeout
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	unhealthyThreshold, healthyThreshold := hc.UnhealthyThreshold, hc.HealthyThreshold
+	if unhealthyThreshold == 0 {
+		unhealthyThreshold = 2
+	}
+	if healthyThreshold == 0 {
+		healthyThreshold = 2
+	}
+
+	check := &envoy_core_v3.HealthCheck{
+		Interval:           durationpb.New(interval),
+		Timeout:            durationpb.New(timeout),
+		UnhealthyThreshold: makeUint32Value(int(unhealthyThreshold)),
+		HealthyThreshold:   makeUint32Value(int(healthyThreshold)),
+	}
+	if hc.Protocol == "http" {
+		path := hc.Path
+		if path == "" {
+			path = "/"
+		}
+		check.HealthChecker = &envoy_core_v3.HealthCheck_HttpHealthCheck_{
+			HttpHealthCheck: &envoy_core_v3.HealthCheck_HttpHealthCheck{
+				Host: hostname,
+				Path: path,
+			},
+		}
+	} else {
+		check.HealthChecker = &envoy_core_v3.HealthCheck_TcpHealthCheck_{
+			TcpHealthCheck: &envoy_core_v3.HealthCheck_TcpHealthCheck{},
+		}
+	}
+	c.HealthChecks = []*envoy_core_v3.HealthCheck{check}
+
+	// Without disabling the panic threshold Envoy would keep sending requests
+	// to the endpoints once all of them are unhealthy.
+	c.CommonLbConfig = &envoy_cluster_v3.Cluster_CommonLbConfig{
+		HealthyPanicThreshold: &envoy_type_v3.Percent{
+			Value: 0, // disable panic threshold
+		},
+	}
+}
+
 func (s *ResourceGenerator) injectGatewayServiceAddons(cfgSnap *proxycfg.ConfigSnapshot, c *envoy_cluster_v3.Cluster, svc structs.ServiceName, lb *structs.LoadBalancer) error {
 	switch cfgSnap.Kind {
 	case structs.ServiceKindMeshGateway:
@@ -871,6 +962,12 @@ func (s *ResourceGenerator) configIngressUpstreamCluster(c *envoy_cluster_v3.Clu
 	c.OutlierDetection = outlierDetection
 }
 
+// localAppPortClusterName returns the name of the cluster of a named port of
+// the local service.
+func localAppPortClusterName(port string) string {
+	return xdscommon.LocalAppClusterName + "_" + port
+}
+
 func (s *ResourceGenerator) makeAppCluster(cfgSnap *proxycfg.ConfigSnapshot, name, pathProtocol string, port int) (*envoy_cluster_v3.Cluster, error) {
 	var c *envoy_cluster_v3.Cluster
 	var err error
@@ -991,10 +1088,8 @@ func (s *ResourceGenerator) makeUpstreamClusterForPeerService(
 			},
 			OutlierDetection: outlierDetection,
 		}
-		if upstreamConfig.Protocol == "http2" || upstreamConfig.Protocol == "grpc" {
-			if err := s.setHttp2ProtocolOptions(c); err != nil {
-				return c, err
-			}
+		if err := s.setUpstreamHttpProtocolOptions(c, upstreamConfig.Protocol, upstreamConfig.MaxConcurrentStreams, upstreamConfig.MaxRequestsPerConnection); err != nil {
+			return c, err
 		}
 
 		useEDS := true
@@ -1106,10 +1201,8 @@ func (s *ResourceGenerator) makeUpstreamClusterForPreparedQuery(upstream structs
 			},
 			OutlierDetection: ToOutlierDetection(cfg.PassiveHealthCheck, nil, true),
 		}
-		if cfg.Protocol == "http2" || cfg.Protocol == "grpc" {
-			if err := s.setHttp2ProtocolOptions(c); err != nil {
-				return c, err
-			}
+		if err := s.setUpstreamHttpProtocolOptions(c, cfg.Protocol, cfg.MaxConcurrentStreams, cfg.MaxRequestsPerConnection); err != nil {
+			return c, err
 		}
 	}
 
@@ -1266,7 +1359,8 @@ func (s *ResourceGenerator) makeUpstreamClustersForDiscoveryChain(
 		var targetClustersData []targetClusterData
 		if failover != nil && !forMeshGateway {
 			var failoverClusterNames []string
-			for _, tid := range append([]string{primaryTargetID}, failover.Targets...) {
+			failoverTargets := orderFailoverTargetsByHealth(failover, upstreamsSnapshot.PeerUpstreamEndpoints.Get)
+			for _, tid := range append([]string{primaryTargetID}, failoverTargets...) {
 				if td, ok := s.getTargetClusterData(upstreamsSnapshot, chain, tid, forMeshGateway, true); ok {
 					targetClustersData = append(targetClustersData, td)
 					failoverClusterNames = append(failoverClusterNames, td.clusterName)
@@ -1361,10 +1455,18 @@ func (s *ResourceGenerator) makeUpstreamClustersForDiscoveryChain(
 				return nil, fmt.Errorf("failed to apply load balancer configuration to cluster %q: %v", targetData.clusterName, err)
 			}
 
-			if upstreamConfig.Protocol == "http2" || upstreamConfig.Protocol == "grpc" {
-				if err := s.setHttp2ProtocolOptions(c); err != nil {
-					return nil, err
-				}
+			// The upstream config of the proxy overrides the upstream
+			// connection pool defaults of the target service.
+			maxConcurrentStreams := upstreamConfig.MaxConcurrentStreams
+			if maxConcurrentStreams == 0 {
+				maxConcurrentStreams = target.MaxConcurrentStreams
+			}
+			maxRequestsPerConnection := upstreamConfig.MaxRequestsPerConnection
+			if maxRequestsPerConnection == 0 {
+				maxRequestsPerConnection = target.MaxRequestsPerConnection
+			}
+			if err := s.setUpstreamHttpProtocolOptions(c, upstreamConfig.Protocol, maxConcurrentStreams, maxRequestsPerConnection); err != nil {
+				return nil, err
 			}
 
 			configureTLS := true
@@ -1385,6 +1487,13 @@ func (s *ResourceGenerator) makeUpstreamClustersForDiscoveryChain(
 					return nil, fmt.Errorf("failed to inject SAN matcher rules for cluster %q: %v", sni, err)
 				}
 
+				// The named port of the destination is selected with ALPN, so
+				// that the inbound listener of its sidecar proxy can route the
+				// connection to it.
+				if upstream != nil && upstream.DestinationPort != "" && !forMeshGateway {
+					commonTLSContext.AlpnProtocols = []string{servicePortALPN(upstream.DestinationPort)}
+				}
+
 				tlsContext := &envoy_tls_v3.UpstreamTlsContext{
 					CommonTlsContext: commonTLSContext,
 					Sni:              sni,
@@ -1877,6 +1986,54 @@ func (s *ResourceGenerator) setHttp2ProtocolOptions(c *envoy_cluster_v3.Cluster)
 	return nil
 }
 
+// setUpstreamHttpProtocolOptions sets the HTTP protocol options of the given
+// upstream cluster, which uses HTTP/2 for the http2 and grpc protocols. They
+// are only set for the http protocol when there are connection limits, since
+// HTTP/1.1 is the default.
+func (s *ResourceGenerator) setUpstreamHttpProtocolOptions(c *envoy_cluster_v3.Cluster, protocol string, maxConcurrentStreams, maxRequestsPerConnection int) error {
+	cfg := &envoy_upstreams_v3.HttpProtocolOptions{}
+	switch {
+	case protocol == "http2" || protocol == "grpc":
+		http2 := &envoy_core_v3.Http2ProtocolOptions{}
+		if maxConcurrentStreams > 0 {
+			http2.MaxConcurrentStreams = makeUint32Value(maxConcurrentStreams)
+		}
+		cfg.UpstreamProtocolOptions = &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: http2,
+				},
+			},
+		}
+	case protocol == "http" && maxRequestsPerConnection > 0:
+		cfg.UpstreamProtocolOptions = &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
+					HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{},
+				},
+			},
+		}
+	default:
+		return nil
+	}
+
+	if maxRequestsPerConnection > 0 {
+		cfg.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{
+			MaxRequestsPerConnection: makeUint32Value(maxRequestsPerConnection),
+		}
+	}
+
+	any, err := anypb.New(cfg)
+	if err != nil {
+		return err
+	}
+	c.TypedExtensionProtocolOptions = map[string]*anypb.Any{
+		"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": any,
+	}
+
+	return nil
+}
+
 // generatePeeredClusterName returns an SNI-like cluster name which mimics PeeredServiceSNI
 // but excludes partition information which could be ambiguous (local vs remote partition).
 func generatePeeredClusterName(uid proxycfg.UpstreamID, tb *pbpeering.PeeringTrustBundle) string {
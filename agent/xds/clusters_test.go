@@ -245,6 +245,29 @@ func TestClustersFromSnapshot(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "connect-proxy-with-upstream-connection-pool",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", nil, nil, upstreamConnectionPoolServiceDefaults())
+			},
+		},
+		{
+			name: "connect-proxy-with-upstream-connection-pool-overrides",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", func(ns *structs.NodeService) {
+					for i := range ns.Proxy.Upstreams {
+						if ns.Proxy.Upstreams[i].DestinationName != "db" {
+							continue
+						}
+						if ns.Proxy.Upstreams[i].Config == nil {
+							ns.Proxy.Upstreams[i].Config = map[string]interface{}{}
+						}
+						ns.Proxy.Upstreams[i].Config["max_concurrent_streams"] = 10
+						ns.Proxy.Upstreams[i].Config["max_requests_per_connection"] = 50
+					}
+				}, nil, upstreamConnectionPoolServiceDefaults())
+			},
+		},
 		{
 			name: "connect-proxy-with-chain",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -719,6 +742,14 @@ func TestClustersFromSnapshot(t *testing.T) {
 			name:   "terminating-gateway-hostname-service-subsets",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewayHostnameSubsets,
 		},
+		{
+			name:   "terminating-gateway-meta-subsets",
+			create: proxycfg.TestConfigSnapshotTerminatingGatewayMetaSubsets,
+		},
+		{
+			name:   "terminating-gateway-destination-health-checks",
+			create: proxycfg.TestConfigSnapshotTerminatingGatewayDestinationHealthChecks,
+		},
 		{
 			name:   "terminating-gateway-sni",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewaySNI,
@@ -765,6 +796,24 @@ func TestClustersFromSnapshot(t *testing.T) {
 			name:   "transparent-proxy-dial-instances-directly",
 			create: proxycfg.TestConfigSnapshotTransparentProxyDialDirectly,
 		},
+		{
+			name: "api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotAPIGateway(t, nil, nil)
+			},
+		},
+		{
+			name:   "api-gateway-with-rate-limits",
+			create: proxycfg.TestConfigSnapshotAPIGatewayRateLimits,
+		},
+		{
+			name:   "api-gateway-with-jwt",
+			create: proxycfg.TestConfigSnapshotAPIGatewayJWT,
+		},
+		{
+			name:   "api-gateway-with-ext-authz",
+			create: proxycfg.TestConfigSnapshotAPIGatewayExtAuthz,
+		},
 	}
 
 	latestEnvoyVersion := xdscommon.EnvoyVersions[0]
@@ -948,3 +997,18 @@ func TestEnvoyLBConfig_InjectToCluster(t *testing.T) {
 func UID(input string) proxycfg.UpstreamID {
 	return proxycfg.UpstreamIDFromString(input)
 }
+
+// upstreamConnectionPoolServiceDefaults returns the service-defaults of the db
+// service with upstream connection pool defaults.
+func upstreamConnectionPoolServiceDefaults() *structs.ServiceConfigEntry {
+	return &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "db",
+		Protocol: "http2",
+		UpstreamConnectionPool: &structs.UpstreamConnectionPool{
+			ConnectTimeoutMs:         2500,
+			MaxConcurrentStreams:     100,
+			MaxRequestsPerConnection: 1000,
+		},
+	}
+}
@@ -70,6 +70,12 @@ type ProxyConfig struct {
 	// than the host port being advertised.
 	BindPort int `mapstructure:"bind_port"`
 
+	// OutboundListenerIPv6 adds a copy of the outbound listener of a
+	// transparent proxy bound to [::1], to receive the IPv6 traffic
+	// redirected with ip6tables or nftables. The outbound listener only
+	// receives IPv4 traffic otherwise.
+	OutboundListenerIPv6 bool `mapstructure:"outbound_listener_ipv6"`
+
 	// MaxInboundConnections is the maximum number of inbound connections to
 	// the proxy. If not set, the default is 0 (no limit).
 	MaxInboundConnections int `mapstructure:"max_inbound_connections"`
@@ -144,6 +150,12 @@ type GatewayConfig struct {
 	TcpKeepaliveTime     int  `mapstructure:"envoy_gateway_remote_tcp_keepalive_time"`
 	TcpKeepaliveInterval int  `mapstructure:"envoy_gateway_remote_tcp_keepalive_interval"`
 	TcpKeepaliveProbes   int  `mapstructure:"envoy_gateway_remote_tcp_keepalive_probes"`
+
+	// JWKSCAFile is the path on the gateway's host of the CA certificates
+	// that verify the certificates of the HTTPS remote JSON Web Key Sets of
+	// the API gateway, when their provider doesn't set its own. Defaults to
+	// the system bundle of the Envoy images.
+	JWKSCAFile string `mapstructure:"envoy_jwks_ca_file"`
 }
 
 // ParseGatewayConfig returns the GatewayConfig parsed from an opaque map. If an
@@ -0,0 +1,98 @@
+package xds
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+)
+
+// redacted replaces the private keys in the resources reported by DebugProxy.
+const redacted = "hidden"
+
+// ProxyDebug is the xDS configuration generated from the snapshot of a
+// proxy, as reported to operators troubleshooting the proxy.
+type ProxyDebug struct {
+	// Resources are the xDS resources by type URL, encoded as JSON. The
+	// private keys they contain are redacted.
+	Resources map[string][]json.RawMessage
+
+	// Extensions are the results of applying the Envoy extensions to the
+	// resources.
+	Extensions []ExtensionResult
+}
+
+// DebugProxy generates the xDS resources of the proxy from its snapshot and
+// applies the Envoy extensions to them, like the xDS server does for the
+// proxy's stream. The resources generated don't account for the features
+// supported by the proxy's Envoy version, since they're unknown outside of a
+// stream.
+func DebugProxy(logger hclog.Logger, cfgFetcher ConfigFetcher, cfgSnap *proxycfg.ConfigSnapshot) (*ProxyDebug, error) {
+	generator := NewResourceGenerator(logger, cfgFetcher, true)
+	all, err := generator.AllResourcesFromSnapshot(cfgSnap)
+	if err != nil {
+		return nil, err
+	}
+	resources := xdscommon.IndexResources(logger, all)
+
+	debug := &ProxyDebug{Resources: make(map[string][]json.RawMessage)}
+	// The failure of a required extension is reported in its result.
+	debug.Extensions, _ = applyEnvoyExtensions(logger, resources, cfgSnap)
+
+	for typeURL, byName := range resources.Index {
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		encoded := make([]json.RawMessage, 0, len(names))
+		for _, name := range names {
+			data, err := protojson.Marshal(byName[name])
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s %q: %w", typeURL, name, err)
+			}
+			data, err = redactPrivateKeys(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s %q: %w", typeURL, name, err)
+			}
+			encoded = append(encoded, data)
+		}
+		debug.Resources[typeURL] = encoded
+	}
+	return debug, nil
+}
+
+// redactPrivateKeys replaces the private keys of the TLS certificates in the
+// JSON encoding of a resource. They can be nested in the typed configs of the
+// filters and transport sockets, which protojson encodes inline.
+func redactPrivateKeys(data []byte) ([]byte, error) {
+	var resource interface{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return nil, err
+	}
+	redactValue(resource)
+	return json.Marshal(resource)
+}
+
+func redactValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if key == "privateKey" {
+				v[key] = map[string]interface{}{"inlineString": redacted}
+				continue
+			}
+			redactValue(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			redactValue(value)
+		}
+	}
+}
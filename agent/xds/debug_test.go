@@ -0,0 +1,34 @@
+package xds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/envoyextensions/xdscommon"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestDebugProxy(t *testing.T) {
+	snap := proxycfg.TestConfigSnapshot(t, nil, nil)
+	require.NotEmpty(t, snap.Leaf().PrivateKeyPEM)
+
+	debug, err := DebugProxy(testutil.Logger(t), nil, snap)
+	require.NoError(t, err)
+	require.Empty(t, debug.Extensions)
+
+	for _, typeURL := range []string{xdscommon.ListenerType, xdscommon.ClusterType, xdscommon.EndpointType} {
+		require.NotEmpty(t, debug.Resources[typeURL], typeURL)
+	}
+
+	var redactedKeys int
+	for _, resources := range debug.Resources {
+		for _, resource := range resources {
+			require.NotContains(t, string(resource), "PRIVATE KEY")
+			redactedKeys += strings.Count(string(resource), `"privateKey":{"inlineString":"hidden"}`)
+		}
+	}
+	require.NotZero(t, redactedKeys)
+}
@@ -14,6 +14,7 @@ import (
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/hashicorp/go-hclog"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -206,6 +207,9 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 
 			if node == nil && req.Node != nil {
 				node = req.Node
+				if err := s.checkEnvoyVersion(generator.Logger, req.Node); err != nil {
+					return err
+				}
 				var err error
 				generator.ProxyFeatures, err = xdscommon.DetermineSupportedProxyFeatures(req.Node)
 				if err != nil {
@@ -252,7 +256,7 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 				s.ResourceMapMutateFn(newResourceMap)
 			}
 
-			if err = s.applyEnvoyExtensions(newResourceMap, cfgSnap); err != nil {
+			if _, err = applyEnvoyExtensions(s.Logger, newResourceMap, cfgSnap); err != nil {
 				// err is already the result of calling status.Errorf
 				return err
 			}
@@ -397,13 +401,37 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 	}
 }
 
-func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, cfgSnap *proxycfg.ConfigSnapshot) error {
+// ExtensionResult is the result of applying an Envoy extension to the xDS
+// resources of a proxy.
+type ExtensionResult struct {
+	Name      string
+	Service   string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+	Required  bool
+	Applied   bool
+	Error     string `json:",omitempty"`
+}
+
+// applyEnvoyExtensions applies the Envoy extensions configured for the proxy
+// and the services it routes to, returning the result of each extension. It
+// returns an error if a required extension failed.
+func applyEnvoyExtensions(logger hclog.Logger, resources *xdscommon.IndexedResources, cfgSnap *proxycfg.ConfigSnapshot) ([]ExtensionResult, error) {
+	var results []ExtensionResult
 	serviceConfigs := extensionruntime.GetRuntimeConfigurations(cfgSnap)
 	for _, cfgs := range serviceConfigs {
 		for _, cfg := range cfgs {
-			logFn := s.Logger.Warn
+			result := ExtensionResult{
+				Name:      cfg.EnvoyExtension.Name,
+				Service:   cfg.ServiceName.Name,
+				Namespace: cfg.ServiceName.Namespace,
+				Partition: cfg.ServiceName.Partition,
+				Required:  cfg.EnvoyExtension.Required,
+			}
+
+			logFn := logger.Warn
 			if cfg.EnvoyExtension.Required {
-				logFn = s.Logger.Error
+				logFn = logger.Error
 			}
 			errorParams := []interface{}{
 				"extension", cfg.EnvoyExtension.Name,
@@ -428,9 +456,11 @@ func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, cfg
 			metrics.MeasureSinceWithLabels([]string{"envoy_extension", "validate_arguments"}, now, getMetricLabels(err))
 			if err != nil {
 				logFn("failed to construct extension", errorParams...)
+				result.Error = err.Error()
+				results = append(results, result)
 
 				if cfg.EnvoyExtension.Required {
-					return status.Errorf(codes.Unavailable, "failed to construct extension %q for service %q", cfg.EnvoyExtension.Name, cfg.ServiceName.Name)
+					return results, status.Errorf(codes.Unavailable, "failed to construct extension %q for service %q", cfg.EnvoyExtension.Name, cfg.ServiceName.Name)
 				}
 
 				continue
@@ -442,8 +472,10 @@ func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, cfg
 			if err != nil {
 				errorParams = append(errorParams, "error", err)
 				logFn("failed to validate extension arguments", errorParams...)
+				result.Error = err.Error()
+				results = append(results, result)
 				if cfg.EnvoyExtension.Required {
-					return status.Errorf(codes.Unavailable, "failed to validate arguments for extension %q for service %q", cfg.EnvoyExtension.Name, cfg.ServiceName.Name)
+					return results, status.Errorf(codes.Unavailable, "failed to validate arguments for extension %q for service %q", cfg.EnvoyExtension.Name, cfg.ServiceName.Name)
 				}
 
 				continue
@@ -453,17 +485,21 @@ func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, cfg
 			resources, err = extender.Extend(resources, &cfg)
 			metrics.MeasureSinceWithLabels([]string{"envoy_extension", "extend"}, now, getMetricLabels(err))
 			if err == nil {
+				result.Applied = true
+				results = append(results, result)
 				continue
 			}
 
 			logFn("failed to apply envoy extension", errorParams...)
+			result.Error = err.Error()
+			results = append(results, result)
 			if cfg.EnvoyExtension.Required {
-				return status.Errorf(codes.Unavailable, "failed to patch xDS resources in the %q extension: %v", cfg.EnvoyExtension.Name, err)
+				return results, status.Errorf(codes.Unavailable, "failed to patch xDS resources in the %q extension: %v", cfg.EnvoyExtension.Name, err)
 			}
 		}
 	}
 
-	return nil
+	return results, nil
 }
 
 var xDSUpdateOrder = []xDSUpdateOperation{
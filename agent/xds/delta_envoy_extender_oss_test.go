@@ -4,14 +4,17 @@
 package xds
 
 import (
+	"fmt"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/hashicorp/consul/agent/xds/testcommon"
 	testinf "github.com/mitchellh/go-testing-interface"
 	"github.com/stretchr/testify/require"
@@ -22,6 +25,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/xds/extensionruntime"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
 	"github.com/hashicorp/consul/envoyextensions/xdscommon"
 	"github.com/hashicorp/consul/sdk/testutil"
 )
@@ -85,9 +89,90 @@ end`,
 		}
 	}
 
+	// makeInboundSnapshot returns a snapshot of a connect-proxy for an http
+	// service with the given extension configured for the local service.
+	makeInboundSnapshot := func(t testinf.T, name string, args map[string]interface{}) *proxycfg.ConfigSnapshot {
+		return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+			ns.Proxy.Config["protocol"] = "http"
+			ns.Proxy.EnvoyExtensions = []structs.EnvoyExtension{
+				{Name: name, Arguments: args},
+			}
+		}, nil)
+	}
+
+	// makeUpstreamSnapshot returns a snapshot of a connect-proxy with the
+	// given extension configured for its http db upstream.
+	makeUpstreamSnapshot := func(t testinf.T, name string, args map[string]interface{}) *proxycfg.ConfigSnapshot {
+		return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", nil, nil, &structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     "db",
+			Protocol: "http",
+			EnvoyExtensions: []structs.EnvoyExtension{
+				{Name: name, Arguments: args},
+			},
+		})
+	}
+
+	// makeAPIGatewaySnapshot returns a snapshot of an API gateway with the
+	// given extension configured for the gateway.
+	makeAPIGatewaySnapshot := func(t testinf.T, name string, args map[string]interface{}) *proxycfg.ConfigSnapshot {
+		snap := proxycfg.TestConfigSnapshotAPIGateway(t, nil, nil)
+		snap.Proxy.EnvoyExtensions = []structs.EnvoyExtension{
+			{Name: name, Arguments: args},
+		}
+		return snap
+	}
+
+	requestIDArgs := func(proxyType string) map[string]interface{} {
+		return map[string]interface{}{
+			"ProxyType":                 proxyType,
+			"PreserveExternalRequestID": true,
+			"UUID": map[string]interface{}{
+				"PackTraceReason": false,
+			},
+			"SuppressEnvoyHeaders": true,
+			"RemoveRequestHeaders": []string{"x-envoy-original-path"},
+			"RenameRequestHeaders": map[string]string{"x-envoy-attempt-count": "x-attempt-count"},
+		}
+	}
+
+	strictHeadersArgs := func(proxyType string) map[string]interface{} {
+		return map[string]interface{}{
+			"ProxyType":                    proxyType,
+			"NormalizePath":                true,
+			"MergeSlashes":                 true,
+			"PathWithEscapedSlashesAction": "REJECT_REQUEST",
+			"HeadersWithUnderscoresAction": "DROP_HEADER",
+			"MaxHeadersCount":              50,
+		}
+	}
+
+	grpcWebArgs := func(proxyType string) map[string]interface{} {
+		return map[string]interface{}{
+			"ProxyType": proxyType,
+			"CORS": map[string]interface{}{
+				"AllowOrigins":  []string{"https://app.example.com"},
+				"ExposeHeaders": []string{"grpc-status", "grpc-message"},
+				"MaxAge":        600,
+			},
+		}
+	}
+
+	grpcJSONTranscoderArgs := func(proxyType string) map[string]interface{} {
+		return map[string]interface{}{
+			"ProxyType":           proxyType,
+			"ProtoDescriptorFile": "/etc/envoy/helloworld.pb",
+			"Services":            []string{"helloworld.Greeter"},
+			"AutoMapping":         true,
+			"ConvertGRPCStatus":   true,
+		}
+	}
+
 	tests := []struct {
 		name   string
 		create func(t testinf.T) *proxycfg.ConfigSnapshot
+		// extender is applied after the extensions of the snapshot.
+		extender extensioncommon.EnvoyExtender
 	}{
 		{
 			name: "lambda-connect-proxy",
@@ -229,6 +314,205 @@ end`,
 				}, nil)
 			},
 		},
+		{
+			name: "request-id-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinRequestIDExtension, requestIDArgs("connect-proxy"))
+			},
+		},
+		{
+			name: "request-id-api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeAPIGatewaySnapshot(t, api.BuiltinRequestIDExtension, requestIDArgs("api-gateway"))
+			},
+		},
+		{
+			name: "preserve-case-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinPreserveCaseExtension, map[string]interface{}{
+					"ProxyType":           "connect-proxy",
+					"ForwardReasonPhrase": true,
+				})
+			},
+		},
+		{
+			name: "preserve-case-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinPreserveCaseExtension, map[string]interface{}{
+					"ProxyType": "connect-proxy",
+				})
+			},
+		},
+		{
+			name: "strict-headers-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinStrictHeadersExtension, strictHeadersArgs("connect-proxy"))
+			},
+		},
+		{
+			name: "strict-headers-api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeAPIGatewaySnapshot(t, api.BuiltinStrictHeadersExtension, strictHeadersArgs("api-gateway"))
+			},
+		},
+		{
+			name: "upgrade-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinUpgradeExtension, map[string]interface{}{
+					"ProxyType":    "connect-proxy",
+					"UpgradeTypes": map[string]bool{"websocket": true},
+				})
+			},
+		},
+		{
+			name: "upgrade-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinUpgradeExtension, map[string]interface{}{
+					"ProxyType":    "connect-proxy",
+					"UpgradeTypes": map[string]bool{"websocket": false},
+					"Routes": []map[string]interface{}{
+						{
+							"PathPrefix":   "/",
+							"UpgradeTypes": map[string]bool{"websocket": true},
+							"IdleTimeout":  300,
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "grpc-web-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinGRPCWebExtension, grpcWebArgs("connect-proxy"))
+			},
+		},
+		{
+			name: "grpc-web-api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeAPIGatewaySnapshot(t, api.BuiltinGRPCWebExtension, grpcWebArgs("api-gateway"))
+			},
+		},
+		{
+			name: "grpc-json-transcoder-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinGRPCJSONTranscoderExtension, grpcJSONTranscoderArgs("connect-proxy"))
+			},
+		},
+		{
+			name: "grpc-json-transcoder-api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeAPIGatewaySnapshot(t, api.BuiltinGRPCJSONTranscoderExtension, grpcJSONTranscoderArgs("api-gateway"))
+			},
+		},
+		{
+			name: "route-timeout-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinRouteTimeoutExtension, map[string]interface{}{
+					"ProxyType": "connect-proxy",
+					"Routes": []map[string]interface{}{
+						{
+							"PathPrefix":          "/",
+							"RequestTimeoutMs":    5000,
+							"IdleTimeoutMs":       30000,
+							"MaxStreamDurationMs": 60000,
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "connection-lifetime-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinConnectionLifetimeExtension, map[string]interface{}{
+					"ProxyType":               "connect-proxy",
+					"MaxConnectionDurationMs": 600000,
+					"DrainTimeoutMs":          10000,
+				})
+			},
+		},
+		{
+			name: "connection-lifetime-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinConnectionLifetimeExtension, map[string]interface{}{
+					"ProxyType":                "connect-proxy",
+					"MaxConnectionDurationMs":  600000,
+					"MaxRequestsPerConnection": 1000,
+				})
+			},
+		},
+		{
+			name: "metadata-exchange-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeInboundSnapshot(t, api.BuiltinMetadataExchangeExtension, map[string]interface{}{
+					"ProxyType": "connect-proxy",
+				})
+			},
+		},
+		{
+			name: "metadata-exchange-doesnt-apply-to-upstreams",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinMetadataExchangeExtension, map[string]interface{}{
+					"ProxyType": "connect-proxy",
+				})
+			},
+		},
+		{
+			name: "dns-resolver-terminating-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotTerminatingGatewayWithExtension(t, structs.EnvoyExtension{
+					Name: api.BuiltinDNSResolverExtension,
+					Arguments: map[string]interface{}{
+						"ProxyType":        "terminating-gateway",
+						"Resolvers":        []string{"10.0.0.53:53", "[fd00::53]:5353"},
+						"DNSRefreshRateMs": 10000,
+						"RespectDNSTTL":    true,
+						"LookupFamily":     "V4_PREFERRED",
+					},
+				})
+			},
+		},
+		{
+			name: "active-health-check-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinActiveHealthCheckExtension, map[string]interface{}{
+					"ProxyType":             "connect-proxy",
+					"Path":                  "/healthz",
+					"ExpectedStatuses":      []int{200, 204},
+					"IntervalMs":            5000,
+					"TimeoutMs":             1000,
+					"UnhealthyThreshold":    3,
+					"HealthyThreshold":      2,
+					"PanicThresholdPercent": 25.0,
+				})
+			},
+		},
+		{
+			name: "dual-stack-terminating-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotTerminatingGatewayWithExtension(t, structs.EnvoyExtension{
+					Name: api.BuiltinDualStackExtension,
+					Arguments: map[string]interface{}{
+						"ProxyType":         "terminating-gateway",
+						"AddressPreference": "happy-eyeballs",
+					},
+				})
+			},
+		},
+		{
+			name: "load-reporting-upstream",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return makeUpstreamSnapshot(t, api.BuiltinLoadReportingExtension, map[string]interface{}{
+					"ProxyType": "connect-proxy",
+				})
+			},
+		},
+		{
+			name: "internal-listener-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, nil, nil)
+			},
+			extender: internalListenerExtender{},
+		},
 	}
 
 	latestEnvoyVersion := xdscommon.EnvoyVersions[0]
@@ -265,6 +549,11 @@ end`,
 						}
 					}
 
+					if tt.extender != nil {
+						indexedResources, err = tt.extender.Extend(indexedResources, &extensioncommon.RuntimeConfig{Kind: api.ServiceKind(snap.Kind)})
+						require.NoError(t, err)
+					}
+
 					entities := []struct {
 						name   string
 						key    string
@@ -331,3 +620,42 @@ end`,
 		})
 	}
 }
+
+// internalListenerExtender chains the public listener of a connect-proxy
+// through an internal listener: the public listener forwards the connections
+// to an internal cluster, whose internal listener runs the original filter
+// chains.
+type internalListenerExtender struct{}
+
+func (internalListenerExtender) Validate(*extensioncommon.RuntimeConfig) error {
+	return nil
+}
+
+func (internalListenerExtender) Extend(resources *xdscommon.IndexedResources, _ *extensioncommon.RuntimeConfig) (*xdscommon.IndexedResources, error) {
+	const internalName = "public_listener_internal"
+
+	var public *envoy_listener_v3.Listener
+	for name, msg := range resources.Index[xdscommon.ListenerType] {
+		if strings.HasPrefix(name, xdscommon.PublicListenerName+":") {
+			public = msg.(*envoy_listener_v3.Listener)
+		}
+	}
+	if public == nil {
+		return resources, fmt.Errorf("missing listener %q", xdscommon.PublicListenerName)
+	}
+
+	tcpProxy, err := extensioncommon.MakeFilter("envoy.filters.network.tcp_proxy", &envoy_tcp_proxy_v3.TcpProxy{
+		StatPrefix:       internalName,
+		ClusterSpecifier: &envoy_tcp_proxy_v3.TcpProxy_Cluster{Cluster: internalName},
+	})
+	if err != nil {
+		return resources, err
+	}
+
+	resources.Index[xdscommon.ListenerType][internalName] = extensioncommon.MakeInternalListener(internalName, public.FilterChains...)
+	resources.Index[xdscommon.ClusterType][internalName] = extensioncommon.MakeInternalCluster(internalName, internalName)
+	public.FilterChains = []*envoy_listener_v3.FilterChain{
+		{Filters: []*envoy_listener_v3.Filter{tcpProxy}},
+	}
+	return resources, nil
+}
@@ -1504,6 +1504,55 @@ func TestServer_DeltaAggregatedResources_v3_StreamDrained(t *testing.T) {
 	})
 }
 
+func TestServer_DeltaAggregatedResources_v3_UnsupportedEnvoyVersion(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) { return acl.ManageAll(), nil }
+
+	t.Run("warn", func(t *testing.T) {
+		scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0)
+		mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+		envoy.EnvoyVersion = "1.99.0"
+
+		sid := structs.NewServiceID("web-sidecar-proxy", nil)
+		mgr.RegisterProxy(t, sid)
+
+		envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+		mgr.DeliverConfig(t, sid, newTestSnapshot(t, nil, ""))
+
+		// The stream is not rejected.
+		select {
+		case <-envoy.deltaStream.sendCh:
+		case err := <-errCh:
+			t.Fatalf("unexpected error: %v", err)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("timed out waiting for the response")
+		}
+
+		data := scenario.sink.Data()
+		require.Len(t, data, 1)
+		val, ok := data[0].Counters["consul.xds.test.xds.server.unsupportedEnvoyVersion;version=1.99.0;reason=too_new"]
+		require.True(t, ok)
+		require.Equal(t, 1, val.Count)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0)
+		mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+		scenario.server.StrictEnvoyVersion = true
+		envoy.EnvoyVersion = "1.99.0"
+
+		mgr.RegisterProxy(t, structs.NewServiceID("web-sidecar-proxy", nil))
+		envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+
+		select {
+		case err := <-errCh:
+			require.Error(t, err)
+			require.Equal(t, codes.FailedPrecondition.String(), status.Code(err).String())
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("timed out waiting for handler to finish")
+		}
+	})
+}
+
 func assertDeltaChanBlocked(t *testing.T, ch chan *envoy_discovery_v3.DeltaDiscoveryResponse) {
 	t.Helper()
 	select {
@@ -0,0 +1,92 @@
+package xds
+
+import (
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_dfp_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/dynamic_forward_proxy/v3"
+	envoy_dfp_common_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/dynamic_forward_proxy/v3"
+	envoy_dfp_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/dynamic_forward_proxy/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_upstreams_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// Wildcard Destinations (e.g. "*.example.com") can't be resolved ahead of
+// time, so terminating gateways route them with Envoy's dynamic forward proxy:
+// the HTTP filter resolves the requested host into a DNS cache shared with the
+// cluster, which then connects to the resolved address.
+
+// makeDestinationDNSCacheConfig returns the DNS cache used by the dynamic
+// forward proxy filter and cluster of a wildcard Destination. Envoy requires
+// both to use identical configuration for a given cache name.
+func makeDestinationDNSCacheConfig(clusterName string, dest structs.DestinationConfig) *envoy_dfp_common_v3.DnsCacheConfig {
+	cfg := &envoy_dfp_common_v3.DnsCacheConfig{
+		Name:            clusterName,
+		DnsLookupFamily: envoy_cluster_v3.Cluster_V4_ONLY,
+	}
+	if dest.DNS != nil && dest.DNS.RefreshRate > 0 {
+		cfg.DnsRefreshRate = durationpb.New(dest.DNS.RefreshRate)
+	}
+	return cfg
+}
+
+// makeDynamicForwardProxyCluster creates an Envoy cluster that connects to the
+// hosts resolved by the dynamic forward proxy filter for a wildcard Destination.
+func (s *ResourceGenerator) makeDynamicForwardProxyCluster(snap *proxycfg.ConfigSnapshot, opts clusterOpts, dest structs.DestinationConfig) (*envoy_cluster_v3.Cluster, error) {
+	cfg, err := ParseGatewayConfig(snap.Proxy.Config)
+	if err != nil {
+		// Don't hard fail on a config typo, just warn. The parse func returns
+		// default config if there is an error so it's safe to continue.
+		s.Logger.Warn("failed to parse gateway config", "error", err)
+	}
+
+	clusterCfg, err := anypb.New(&envoy_dfp_cluster_v3.ClusterConfig{
+		DnsCacheConfig: makeDestinationDNSCacheConfig(opts.name, dest),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Connections are made to whichever host was requested, so the SNI used
+	// when originating TLS must follow it.
+	protocolOpts, err := anypb.New(&envoy_upstreams_v3.HttpProtocolOptions{
+		UpstreamHttpProtocolOptions: &envoy_core_v3.UpstreamHttpProtocolOptions{
+			AutoSni: true,
+		},
+		UpstreamProtocolOptions: &envoy_upstreams_v3.HttpProtocolOptions_UseDownstreamProtocolConfig{
+			UseDownstreamProtocolConfig: &envoy_upstreams_v3.HttpProtocolOptions_UseDownstreamHttpConfig{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_cluster_v3.Cluster{
+		Name:           opts.name,
+		ConnectTimeout: durationpb.New(time.Duration(cfg.ConnectTimeoutMs) * time.Millisecond),
+		LbPolicy:       envoy_cluster_v3.Cluster_CLUSTER_PROVIDED,
+		ClusterDiscoveryType: &envoy_cluster_v3.Cluster_ClusterType{
+			ClusterType: &envoy_cluster_v3.Cluster_CustomClusterType{
+				Name:        "envoy.clusters.dynamic_forward_proxy",
+				TypedConfig: clusterCfg,
+			},
+		},
+		TypedExtensionProtocolOptions: map[string]*anypb.Any{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protocolOpts,
+		},
+	}, nil
+}
+
+// makeDynamicForwardProxyFilter creates the HTTP filter that resolves the
+// requested host of a wildcard Destination.
+func makeDynamicForwardProxyFilter(dnsCache *envoy_dfp_common_v3.DnsCacheConfig) (*envoy_http_v3.HttpFilter, error) {
+	return makeEnvoyHTTPFilter("envoy.filters.http.dynamic_forward_proxy", &envoy_dfp_http_v3.FilterConfig{
+		DnsCacheConfig: dnsCache,
+	})
+}
@@ -179,8 +179,8 @@ func (s *ResourceGenerator) endpointsFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 func (s *ResourceGenerator) filterSubsetEndpoints(subset *structs.ServiceResolverSubset, endpoints structs.CheckServiceNodes) (structs.CheckServiceNodes, error) {
 	// locally execute the subsets filter
-	if subset.Filter != "" {
-		filter, err := bexpr.CreateFilter(subset.Filter, nil, endpoints)
+	if expr := subset.FilterExpression(); expr != "" {
+		filter, err := bexpr.CreateFilter(expr, nil, endpoints)
 		if err != nil {
 			return nil, err
 		}
@@ -656,8 +656,10 @@ func (s *ResourceGenerator) endpointsFromDiscoveryChain(
 	}
 
 	mgwMode := structs.MeshGatewayModeDefault
+	var destinationPort string
 	if upstream, _ := cfgSnap.ConnectProxy.GetUpstream(uid, &cfgSnap.ProxyID.EnterpriseMeta); upstream != nil {
 		mgwMode = upstream.MeshGateway.Mode
+		destinationPort = upstream.DestinationPort
 	}
 
 	// Find all resolver nodes.
@@ -709,9 +711,18 @@ func (s *ResourceGenerator) endpointsFromDiscoveryChain(
 				continue
 			}
 
+			targetEndpoints := upstreamEndpoints
+			if destinationPort != "" && !forMeshGateway {
+				if nodes, ok := upstreamEndpoints[targetOpt.targetID]; ok {
+					targetEndpoints = map[string]structs.CheckServiceNodes{
+						targetOpt.targetID: endpointsWithPort(nodes, destinationPort),
+					}
+				}
+			}
+
 			endpointGroup, valid := makeLoadAssignmentEndpointGroup(
 				chain.Targets,
-				upstreamEndpoints,
+				targetEndpoints,
 				gatewayEndpoints,
 				targetOpt.targetID,
 				gatewayKey,
@@ -943,5 +954,25 @@ func calculateEndpointHealthAndWeight(
 	if weight > 128 {
 		weight = 128
 	}
+	// Draining instances are healthy, but Envoy must not open new connections
+	// to them while letting the existing ones finish.
+	if healthStatus == envoy_core_v3.HealthStatus_HEALTHY && ep.IsDraining() {
+		healthStatus = envoy_core_v3.HealthStatus_DRAINING
+	}
 	return healthStatus, weight
 }
+
+// endpointsWithPort returns the endpoints whose sidecar proxy exposes the
+// named port of the service.
+func endpointsWithPort(nodes structs.CheckServiceNodes, port string) structs.CheckServiceNodes {
+	out := make(structs.CheckServiceNodes, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Service == nil {
+			continue
+		}
+		if _, ok := node.Service.Proxy.LocalServicePorts.Get(port); ok {
+			out = append(out, node)
+		}
+	}
+	return out
+}
@@ -94,6 +94,17 @@ func Test_makeLoadAssignment(t *testing.T) {
 	testWarningCheckServiceNodes[0].Checks[0].Status = "warning"
 	testWarningCheckServiceNodes[1].Checks[0].Status = "warning"
 
+	testDrainingCheckServiceNodesRaw, err := copystructure.Copy(testCheckServiceNodes)
+	require.NoError(t, err)
+	testDrainingCheckServiceNodes := testDrainingCheckServiceNodesRaw.(structs.CheckServiceNodes)
+
+	testDrainingCheckServiceNodes[1].Checks = append(testDrainingCheckServiceNodes[1].Checks, &structs.HealthCheck{
+		Node:      "node2",
+		ServiceID: "web",
+		CheckID:   structs.ServiceDrainingPrefix + "web",
+		Status:    "passing",
+	})
+
 	// TODO(rb): test onlypassing
 	tests := []struct {
 		name        string
@@ -204,6 +215,36 @@ func Test_makeLoadAssignment(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name:        "instances, draining",
+			clusterName: "service:test",
+			endpoints: []loadAssignmentEndpointGroup{
+				{Endpoints: testDrainingCheckServiceNodes},
+			},
+			want: &envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: "service:test",
+				Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{{
+					LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+						{
+							HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+								Endpoint: &envoy_endpoint_v3.Endpoint{
+									Address: makeAddress("10.10.10.10", 1234),
+								}},
+							HealthStatus:        envoy_core_v3.HealthStatus_HEALTHY,
+							LoadBalancingWeight: makeUint32Value(1),
+						},
+						{
+							HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+								Endpoint: &envoy_endpoint_v3.Endpoint{
+									Address: makeAddress("10.10.10.20", 1234),
+								}},
+							HealthStatus:        envoy_core_v3.HealthStatus_DRAINING,
+							LoadBalancingWeight: makeUint32Value(1),
+						},
+					},
+				}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -488,6 +529,10 @@ func TestEndpointsFromSnapshot(t *testing.T) {
 			name:   "terminating-gateway-service-subsets",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewayServiceSubsets,
 		},
+		{
+			name:   "terminating-gateway-meta-subsets",
+			create: proxycfg.TestConfigSnapshotTerminatingGatewayMetaSubsets,
+		},
 		{
 			name:   "terminating-gateway-default-service-subset",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewayDefaultServiceSubset,
@@ -91,6 +91,25 @@ func GetRuntimeConfigurations(cfgSnap *proxycfg.ConfigSnapshot) map[api.Compound
 			}
 			extensionConfigurationsMap[localSvc] = append(extensionConfigurationsMap[localSvc], extCfg)
 		}
+	case structs.ServiceKindAPIGateway:
+		kind = api.ServiceKindAPIGateway
+		// Extensions configured for an API gateway apply to its own listeners and routes, so they are
+		// added like the extensions of the local service of a connect-proxy.
+		localSvc := api.CompoundServiceName{
+			Name:      cfgSnap.Service,
+			Namespace: cfgSnap.ProxyID.NamespaceOrDefault(),
+			Partition: cfgSnap.ProxyID.PartitionOrEmpty(),
+		}
+		extensionConfigurationsMap[localSvc] = []extensioncommon.RuntimeConfig{}
+		for _, ext := range convertEnvoyExtensions(cfgSnap.Proxy.EnvoyExtensions) {
+			extCfg := extensioncommon.RuntimeConfig{
+				EnvoyExtension: ext,
+				ServiceName:    localSvc,
+				Upstreams:      nil,
+				Kind:           kind,
+			}
+			extensionConfigurationsMap[localSvc] = append(extensionConfigurationsMap[localSvc], extCfg)
+		}
 	case structs.ServiceKindTerminatingGateway:
 		kind = api.ServiceKindTerminatingGateway
 		for svc, c := range cfgSnap.TerminatingGateway.ServiceConfigs {
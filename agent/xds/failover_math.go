@@ -1,8 +1,11 @@
 package xds
 
 import (
+	"sort"
+
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 
+	"github.com/hashicorp/consul/agent/proxycfg"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
@@ -36,3 +39,67 @@ func firstHealthyTarget(
 
 	return primaryTarget // if everything is broken just use the primary for now
 }
+
+// orderFailoverTargetsByHealth returns the failover targets of a resolver
+// with the peer targets in each priority group sorted by the share of their
+// imported instances that are healthy, healthiest first. Other targets keep
+// their position, as does every target if no priorities were configured.
+func orderFailoverTargetsByHealth(
+	failover *structs.DiscoveryFailover,
+	peerEndpoints func(uid proxycfg.UpstreamID) (structs.CheckServiceNodes, bool),
+) []string {
+	if len(failover.Priorities) != len(failover.Targets) {
+		return failover.Targets
+	}
+
+	healthScore := func(targetID string) float64 {
+		endpoints, ok := peerEndpoints(proxycfg.NewUpstreamIDFromTargetID(targetID))
+		if !ok || len(endpoints) == 0 {
+			return 0
+		}
+		var healthy int
+		for _, ep := range endpoints {
+			if status, _ := calculateEndpointHealthAndWeight(ep, false); status == envoy_core_v3.HealthStatus_HEALTHY {
+				healthy++
+			}
+		}
+		return float64(healthy) / float64(len(endpoints))
+	}
+
+	ordered := make([]string, len(failover.Targets))
+	copy(ordered, failover.Targets)
+
+	for start := 0; start < len(ordered); {
+		end := start
+		for end < len(ordered) && failover.Priorities[end] == failover.Priorities[start] {
+			end++
+		}
+
+		// Only peer targets within the group are reordered, into the
+		// positions they already occupy.
+		var (
+			positions []int
+			peerIDs   []string
+		)
+		for i := start; i < end; i++ {
+			if proxycfg.NewUpstreamIDFromTargetID(ordered[i]).Peer != "" {
+				positions = append(positions, i)
+				peerIDs = append(peerIDs, ordered[i])
+			}
+		}
+		scores := make(map[string]float64, len(peerIDs))
+		for _, id := range peerIDs {
+			scores[id] = healthScore(id)
+		}
+		sort.SliceStable(peerIDs, func(i, j int) bool {
+			return scores[peerIDs[i]] > scores[peerIDs[j]]
+		})
+		for i, pos := range positions {
+			ordered[pos] = peerIDs[i]
+		}
+
+		start = end
+	}
+
+	return ordered
+}
@@ -140,3 +140,70 @@ func TestFirstHealthyTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderFailoverTargetsByHealth(t *testing.T) {
+	passing := proxycfg.TestUpstreamNodesInStatus(t, "passing")
+	critical := proxycfg.TestUpstreamNodesInStatus(t, "critical")
+	mixed := structs.CheckServiceNodes{passing[0], critical[1]}
+
+	const (
+		peerA = "db.default.default.external.peer-a"
+		peerB = "db.default.default.external.peer-b"
+		peerC = "db.default.default.external.peer-c"
+		dc2   = "db.default.default.dc2"
+	)
+
+	health := map[string]structs.CheckServiceNodes{
+		peerA: critical,
+		peerB: passing,
+		peerC: mixed,
+	}
+	peerEndpoints := func(uid proxycfg.UpstreamID) (structs.CheckServiceNodes, bool) {
+		for id, nodes := range health {
+			if proxycfg.NewUpstreamIDFromTargetID(id) == uid {
+				return nodes, true
+			}
+		}
+		return nil, false
+	}
+
+	cases := map[string]struct {
+		failover *structs.DiscoveryFailover
+		expect   []string
+	}{
+		"no priorities keeps configured order": {
+			failover: &structs.DiscoveryFailover{
+				Targets: []string{peerA, peerB, peerC},
+			},
+			expect: []string{peerA, peerB, peerC},
+		},
+		"healthiest peer first within a group": {
+			failover: &structs.DiscoveryFailover{
+				Targets:    []string{peerA, peerC, peerB},
+				Priorities: []int{1, 1, 1},
+			},
+			expect: []string{peerB, peerC, peerA},
+		},
+		"groups are not mixed": {
+			failover: &structs.DiscoveryFailover{
+				Targets:    []string{peerA, peerC, peerB},
+				Priorities: []int{1, 1, 2},
+			},
+			expect: []string{peerC, peerA, peerB},
+		},
+		"non-peer targets keep their position": {
+			failover: &structs.DiscoveryFailover{
+				Targets:    []string{peerA, dc2, peerB},
+				Priorities: []int{1, 1, 1},
+			},
+			expect: []string{peerB, dc2, peerA},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expect, orderFailoverTargetsByHealth(tc.failover, peerEndpoints))
+		})
+	}
+}
@@ -0,0 +1,608 @@
+package xds
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_ratelimit_config_v3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	envoy_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	envoy_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	envoy_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	localRateLimitFilterName  = "envoy.filters.http.local_ratelimit"
+	globalRateLimitFilterName = "envoy.filters.http.ratelimit"
+	jwtAuthnFilterName        = "envoy.filters.http.jwt_authn"
+	extAuthzFilterName        = "envoy.filters.http.ext_authz"
+
+	defaultGlobalRateLimitTimeout = 20 * time.Millisecond
+	defaultJWKSCacheDuration      = 5 * time.Minute
+	remoteJWKSFetchTimeout        = 5 * time.Second
+	defaultExtAuthzTimeout        = 200 * time.Millisecond
+
+	// defaultJWKSCAFile is the system bundle of the Envoy images, used to
+	// verify the certificate of remote JSON Web Key Sets unless the provider
+	// or the gateway's envoy_jwks_ca_file sets another.
+	defaultJWKSCAFile = "/etc/ssl/certs/ca-certificates.crt"
+)
+
+// apiGatewayListenerPolicy returns the api-gateway-policy entry for the
+// listener on the given port, or nil if the snapshot is not for an API
+// gateway or the listener has no policy.
+func apiGatewayListenerPolicy(cfgSnap *proxycfg.ConfigSnapshot, port int) (string, *structs.APIGatewayListenerPolicy) {
+	if cfgSnap.Kind != structs.ServiceKindAPIGateway {
+		return "", nil
+	}
+	return cfgSnap.APIGateway.ListenerPolicy(port)
+}
+
+// globalRateLimitClusterName returns the name of the cluster for the rate
+// limit service configured for the API gateway listener on the given port.
+func globalRateLimitClusterName(port int) string {
+	return fmt.Sprintf("api-gateway-ratelimit-%d", port)
+}
+
+// jwksClusterName returns the name of the cluster used to fetch the remote
+// JSON Web Key Set of a JWT provider of the API gateway listener on the
+// given port.
+func jwksClusterName(port int, provider string) string {
+	return fmt.Sprintf("api-gateway-jwks-%d-%s", port, provider)
+}
+
+// extAuthzClusterName returns the name of the cluster for the external
+// authorization service of the API gateway listener on the given port.
+func extAuthzClusterName(port int) string {
+	return fmt.Sprintf("api-gateway-ext-authz-%d", port)
+}
+
+// makeGatewayPolicyFilters returns the HTTP filters that enforce the
+// api-gateway-policy of the API gateway listener on the given port. Tokens
+// are verified first, then requests are authorized and finally rate limited.
+func makeGatewayPolicyFilters(cfgSnap *proxycfg.ConfigSnapshot, port int) ([]*envoy_http_v3.HttpFilter, error) {
+	_, policy := apiGatewayListenerPolicy(cfgSnap, port)
+	if policy == nil {
+		return nil, nil
+	}
+
+	var filters []*envoy_http_v3.HttpFilter
+
+	if policy.JWT != nil {
+		filter, err := makeJWTAuthnFilter(port, policy.JWT)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	if policy.ExtAuthz != nil {
+		filter, err := makeExtAuthzFilter(port, policy.ExtAuthz)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	rateLimitFilters, err := makeRateLimitFilters(port, policy.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	return append(filters, rateLimitFilters...), nil
+}
+
+func makeJWTAuthnFilter(port int, policy *structs.APIGatewayJWTPolicy) (*envoy_http_v3.HttpFilter, error) {
+	cfg := &envoy_jwt_authn_v3.JwtAuthentication{
+		Providers: make(map[string]*envoy_jwt_authn_v3.JwtProvider, len(policy.Providers)),
+	}
+
+	// A token is accepted if it is valid for any of the providers.
+	var anyProvider *envoy_jwt_authn_v3.JwtRequirement
+	var requirements []*envoy_jwt_authn_v3.JwtRequirement
+	for _, p := range policy.Providers {
+		provider := &envoy_jwt_authn_v3.JwtProvider{
+			Issuer:            p.Issuer,
+			Audiences:         p.Audiences,
+			Forward:           p.Forward,
+			PayloadInMetadata: p.Name,
+		}
+
+		if p.JSONWebKeySet.Local != "" {
+			provider.JwksSourceSpecifier = &envoy_jwt_authn_v3.JwtProvider_LocalJwks{
+				LocalJwks: &envoy_core_v3.DataSource{
+					Specifier: &envoy_core_v3.DataSource_InlineString{
+						InlineString: p.JSONWebKeySet.Local,
+					},
+				},
+			}
+		} else {
+			cacheDuration := p.JSONWebKeySet.CacheDuration
+			if cacheDuration == 0 {
+				cacheDuration = defaultJWKSCacheDuration
+			}
+			provider.JwksSourceSpecifier = &envoy_jwt_authn_v3.JwtProvider_RemoteJwks{
+				RemoteJwks: &envoy_jwt_authn_v3.RemoteJwks{
+					HttpUri: &envoy_core_v3.HttpUri{
+						Uri: p.JSONWebKeySet.RemoteURI,
+						HttpUpstreamType: &envoy_core_v3.HttpUri_Cluster{
+							Cluster: jwksClusterName(port, p.Name),
+						},
+						Timeout: durationpb.New(remoteJWKSFetchTimeout),
+					},
+					CacheDuration: durationpb.New(cacheDuration),
+				},
+			}
+		}
+		cfg.Providers[p.Name] = provider
+
+		requirements = append(requirements, &envoy_jwt_authn_v3.JwtRequirement{
+			RequiresType: &envoy_jwt_authn_v3.JwtRequirement_ProviderName{ProviderName: p.Name},
+		})
+	}
+	if len(requirements) == 1 {
+		anyProvider = requirements[0]
+	} else {
+		anyProvider = &envoy_jwt_authn_v3.JwtRequirement{
+			RequiresType: &envoy_jwt_authn_v3.JwtRequirement_RequiresAny{
+				RequiresAny: &envoy_jwt_authn_v3.JwtRequirementOrList{Requirements: requirements},
+			},
+		}
+	}
+
+	// Rules are evaluated in order, so the paths allowing missing tokens must
+	// come before the catch-all rule. Tokens present on those paths are still
+	// verified.
+	allowMissing := append(requirements[:len(requirements):len(requirements)], &envoy_jwt_authn_v3.JwtRequirement{
+		RequiresType: &envoy_jwt_authn_v3.JwtRequirement_AllowMissing{AllowMissing: &emptypb.Empty{}},
+	})
+	for _, path := range policy.AllowMissingPaths {
+		for _, match := range extensioncommon.PathPrefixMatches(path) {
+			cfg.Rules = append(cfg.Rules, &envoy_jwt_authn_v3.RequirementRule{
+				Match: match,
+				RequirementType: &envoy_jwt_authn_v3.RequirementRule_Requires{
+					Requires: &envoy_jwt_authn_v3.JwtRequirement{
+						RequiresType: &envoy_jwt_authn_v3.JwtRequirement_RequiresAny{
+							RequiresAny: &envoy_jwt_authn_v3.JwtRequirementOrList{Requirements: allowMissing},
+						},
+					},
+				},
+			})
+		}
+	}
+	cfg.Rules = append(cfg.Rules, &envoy_jwt_authn_v3.RequirementRule{
+		Match: &envoy_route_v3.RouteMatch{
+			PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"},
+		},
+		RequirementType: &envoy_jwt_authn_v3.RequirementRule_Requires{
+			Requires: anyProvider,
+		},
+	})
+
+	return makeEnvoyHTTPFilter(jwtAuthnFilterName, cfg)
+}
+
+func makeExtAuthzFilter(port int, authz *structs.APIGatewayExtAuthz) (*envoy_http_v3.HttpFilter, error) {
+	timeout := authz.Timeout
+	if timeout == 0 {
+		timeout = defaultExtAuthzTimeout
+	}
+
+	cfg := &envoy_ext_authz_v3.ExtAuthz{
+		TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+		FailureModeAllow:    authz.FailureModeAllow,
+	}
+
+	switch authz.Protocol {
+	case "http":
+		httpService := &envoy_ext_authz_v3.HttpService{
+			ServerUri: &envoy_core_v3.HttpUri{
+				Uri: fmt.Sprintf("http://%s", net.JoinHostPort(authz.Address, strconv.Itoa(authz.Port))),
+				HttpUpstreamType: &envoy_core_v3.HttpUri_Cluster{
+					Cluster: extAuthzClusterName(port),
+				},
+				Timeout: durationpb.New(timeout),
+			},
+			PathPrefix: authz.PathPrefix,
+		}
+		if len(authz.AllowedHeaders) > 0 {
+			var patterns []*envoy_matcher_v3.StringMatcher
+			for _, header := range authz.AllowedHeaders {
+				patterns = append(patterns, &envoy_matcher_v3.StringMatcher{
+					MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: header},
+					IgnoreCase:   true,
+				})
+			}
+			httpService.AuthorizationRequest = &envoy_ext_authz_v3.AuthorizationRequest{
+				AllowedHeaders: &envoy_matcher_v3.ListStringMatcher{Patterns: patterns},
+			}
+		}
+		cfg.Services = &envoy_ext_authz_v3.ExtAuthz_HttpService{HttpService: httpService}
+	default:
+		cfg.Services = &envoy_ext_authz_v3.ExtAuthz_GrpcService{
+			GrpcService: &envoy_core_v3.GrpcService{
+				TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+						ClusterName: extAuthzClusterName(port),
+					},
+				},
+				Timeout: durationpb.New(timeout),
+			},
+		}
+	}
+
+	return makeEnvoyHTTPFilter(extAuthzFilterName, cfg)
+}
+
+func makeRateLimitFilters(port int, rl *structs.APIGatewayRateLimit) ([]*envoy_http_v3.HttpFilter, error) {
+	if rl == nil {
+		return nil, nil
+	}
+
+	var filters []*envoy_http_v3.HttpFilter
+
+	if rl.Local != nil || len(rl.Routes) > 0 {
+		// Without a listener-wide bucket the filter is still required so that
+		// the per-route configuration is applied.
+		cfg := &envoy_local_ratelimit_v3.LocalRateLimit{
+			StatPrefix: "local_ratelimit",
+		}
+		if rl.Local != nil {
+			cfg = makeLocalRateLimit(*rl.Local)
+		}
+		filter, err := makeEnvoyHTTPFilter(localRateLimitFilterName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	if rl.Global != nil {
+		timeout := rl.Global.Timeout
+		if timeout == 0 {
+			timeout = defaultGlobalRateLimitTimeout
+		}
+		cfg := &envoy_ratelimit_v3.RateLimit{
+			Domain:          rl.Global.Domain,
+			Timeout:         durationpb.New(timeout),
+			FailureModeDeny: rl.Global.FailureModeDeny,
+			RateLimitService: &envoy_ratelimit_config_v3.RateLimitServiceConfig{
+				GrpcService: &envoy_core_v3.GrpcService{
+					TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+						EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+							ClusterName: globalRateLimitClusterName(port),
+						},
+					},
+				},
+				TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+			},
+		}
+		filter, err := makeEnvoyHTTPFilter(globalRateLimitFilterName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func makeLocalRateLimit(l structs.APIGatewayLocalRateLimit) *envoy_local_ratelimit_v3.LocalRateLimit {
+	tokensPerFill := l.TokensPerFill
+	if tokensPerFill == 0 {
+		tokensPerFill = 1
+	}
+	enabled := &envoy_core_v3.RuntimeFractionalPercent{
+		DefaultValue: &envoy_type_v3.FractionalPercent{
+			Numerator:   100,
+			Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+		},
+	}
+	return &envoy_local_ratelimit_v3.LocalRateLimit{
+		StatPrefix: "local_ratelimit",
+		TokenBucket: &envoy_type_v3.TokenBucket{
+			MaxTokens:     l.MaxTokens,
+			TokensPerFill: makeUint32Value(int(tokensPerFill)),
+			FillInterval:  durationpb.New(l.FillInterval),
+		},
+		FilterEnabled:  enabled,
+		FilterEnforced: enabled,
+	}
+}
+
+// injectGatewayPolicyToVirtualHost adds the route-level configuration of the
+// api-gateway-policy of the API gateway listener on the given port to the
+// virtual host.
+func injectGatewayPolicyToVirtualHost(cfgSnap *proxycfg.ConfigSnapshot, port int, vh *envoy_route_v3.VirtualHost) error {
+	name, policy := apiGatewayListenerPolicy(cfgSnap, port)
+	if policy == nil {
+		return nil
+	}
+	injectJWTClaimsToVirtualHost(policy.JWT, vh)
+	if err := injectExtAuthzToVirtualHost(policy.ExtAuthz, vh); err != nil {
+		return err
+	}
+	return injectRateLimitsToVirtualHost(name, policy.RateLimit, vh)
+}
+
+// injectExtAuthzToVirtualHost disables external authorization on the routes
+// matching one of the disabled paths.
+func injectExtAuthzToVirtualHost(authz *structs.APIGatewayExtAuthz, vh *envoy_route_v3.VirtualHost) error {
+	if authz == nil || len(authz.DisabledPaths) == 0 {
+		return nil
+	}
+
+	disabled, err := anypb.New(&envoy_ext_authz_v3.ExtAuthzPerRoute{
+		Override: &envoy_ext_authz_v3.ExtAuthzPerRoute_Disabled{Disabled: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range authz.DisabledPaths {
+		splitRoutesForPathPrefix(vh, path)
+		for _, route := range vh.Routes {
+			if !extensioncommon.RouteMatchesPathPrefix(route, path) {
+				continue
+			}
+			if route.TypedPerFilterConfig == nil {
+				route.TypedPerFilterConfig = make(map[string]*anypb.Any)
+			}
+			route.TypedPerFilterConfig[extAuthzFilterName] = disabled
+		}
+	}
+	return nil
+}
+
+// injectJWTClaimsToVirtualHost sets the request headers mapped from the
+// claims of verified tokens. Any value sent by the client for those headers
+// is removed first so that it can't be spoofed.
+func injectJWTClaimsToVirtualHost(policy *structs.APIGatewayJWTPolicy, vh *envoy_route_v3.VirtualHost) {
+	if policy == nil {
+		return
+	}
+	for _, provider := range policy.Providers {
+		for _, mapping := range provider.ClaimsToHeaders {
+			claim := strings.ReplaceAll(mapping.Claim, ".", ":")
+			vh.RequestHeadersToRemove = append(vh.RequestHeadersToRemove, mapping.Header)
+			vh.RequestHeadersToAdd = append(vh.RequestHeadersToAdd, &envoy_core_v3.HeaderValueOption{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   mapping.Header,
+					Value: fmt.Sprintf("%%DYNAMIC_METADATA(%s:%s:%s)%%", jwtAuthnFilterName, provider.Name, claim),
+				},
+				Append: makeBoolValue(false),
+			})
+		}
+	}
+}
+
+func injectRateLimitsToVirtualHost(name string, rl *structs.APIGatewayRateLimit, vh *envoy_route_v3.VirtualHost) error {
+	if rl == nil {
+		return nil
+	}
+
+	if rl.Global != nil {
+		// Every request sends the listener name and the client address as the
+		// descriptor to the rate limit service.
+		vh.RateLimits = append(vh.RateLimits, &envoy_route_v3.RateLimit{
+			Actions: []*envoy_route_v3.RateLimit_Action{
+				{
+					ActionSpecifier: &envoy_route_v3.RateLimit_Action_GenericKey_{
+						GenericKey: &envoy_route_v3.RateLimit_Action_GenericKey{
+							DescriptorValue: name,
+							DescriptorKey:   "listener",
+						},
+					},
+				},
+				{
+					ActionSpecifier: &envoy_route_v3.RateLimit_Action_RemoteAddress_{
+						RemoteAddress: &envoy_route_v3.RateLimit_Action_RemoteAddress{},
+					},
+				},
+			},
+		})
+	}
+
+	// Route limits are applied in order, so the first one that matches a route wins.
+	for _, limit := range rl.Routes {
+		if !virtualHostMatchesHostname(vh, limit.Hostname) {
+			continue
+		}
+		cfg, err := anypb.New(makeLocalRateLimit(limit.Local))
+		if err != nil {
+			return err
+		}
+		splitRoutesForPathPrefix(vh, limit.PathPrefix)
+		for _, route := range vh.Routes {
+			if _, ok := route.TypedPerFilterConfig[localRateLimitFilterName]; ok || !extensioncommon.RouteMatchesPathPrefix(route, limit.PathPrefix) {
+				continue
+			}
+			if route.TypedPerFilterConfig == nil {
+				route.TypedPerFilterConfig = make(map[string]*anypb.Any)
+			}
+			route.TypedPerFilterConfig[localRateLimitFilterName] = cfg
+		}
+	}
+	return nil
+}
+
+func virtualHostMatchesHostname(vh *envoy_route_v3.VirtualHost, hostname string) bool {
+	if hostname == "" {
+		return true
+	}
+	for _, domain := range vh.Domains {
+		// Domains may carry a port suffix, which the policy hostname does not.
+		if h, _, err := net.SplitHostPort(domain); err == nil {
+			domain = h
+		}
+		if domain == "*" || domain == hostname {
+			return true
+		}
+		if strings.HasPrefix(hostname, "*.") && strings.HasSuffix(domain, hostname[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRoutesForPathPrefix inserts, before each prefix route that matches
+// requests both under the path prefix and outside of it, copies of the route
+// that only match the requests under the path prefix, so that they can be
+// configured separately.
+func splitRoutesForPathPrefix(vh *envoy_route_v3.VirtualHost, prefix string) {
+	var routes []*envoy_route_v3.Route
+	for _, route := range vh.Routes {
+		m, ok := route.GetMatch().GetPathSpecifier().(*envoy_route_v3.RouteMatch_Prefix)
+		if ok && strings.HasPrefix(prefix, m.Prefix) && !extensioncommon.RouteMatchesPathPrefix(route, prefix) {
+			routes = append(routes, extensioncommon.PathPrefixRoutes(route, prefix)...)
+		}
+		routes = append(routes, route)
+	}
+	vh.Routes = routes
+}
+
+// makeGatewayPolicyClusters returns the clusters for the external services
+// referenced by the api-gateway-policy of an API gateway.
+func (s *ResourceGenerator) makeGatewayPolicyClusters(cfgSnap *proxycfg.ConfigSnapshot) ([]proto.Message, error) {
+	var clusters []proto.Message
+	for _, listener := range cfgSnap.APIGateway.Listeners {
+		_, policy := apiGatewayListenerPolicy(cfgSnap, listener.Port)
+		if policy == nil {
+			continue
+		}
+
+		if policy.JWT != nil {
+			for _, provider := range policy.JWT.Providers {
+				if provider.JSONWebKeySet.RemoteURI == "" {
+					continue
+				}
+				c, err := s.makeRemoteJWKSCluster(cfgSnap, jwksClusterName(listener.Port, provider.Name), provider.JSONWebKeySet)
+				if err != nil {
+					return nil, err
+				}
+				clusters = append(clusters, c)
+			}
+		}
+
+		if policy.ExtAuthz != nil {
+			c, err := s.makeGatewayPolicyServiceCluster(cfgSnap, extAuthzClusterName(listener.Port),
+				policy.ExtAuthz.Address, policy.ExtAuthz.Port, policy.ExtAuthz.Protocol == "grpc")
+			if err != nil {
+				return nil, err
+			}
+			clusters = append(clusters, c)
+		}
+
+		if policy.RateLimit != nil && policy.RateLimit.Global != nil {
+			global := policy.RateLimit.Global
+			c, err := s.makeGatewayPolicyServiceCluster(cfgSnap, globalRateLimitClusterName(listener.Port),
+				global.Address, global.Port, true)
+			if err != nil {
+				return nil, err
+			}
+			clusters = append(clusters, c)
+		}
+	}
+	return clusters, nil
+}
+
+// makeGatewayPolicyServiceCluster returns a cluster for a service outside of
+// the mesh that is called by the gateway, such as a rate limit service.
+func (s *ResourceGenerator) makeGatewayPolicyServiceCluster(cfgSnap *proxycfg.ConfigSnapshot, name, address string, port int, grpc bool) (*envoy_cluster_v3.Cluster, error) {
+	opts := clusterOpts{
+		name:      name,
+		addresses: []structs.ServiceAddress{{Address: address, Port: port}},
+	}
+	c := s.makeExternalHostnameCluster(cfgSnap, opts)
+	if net.ParseIP(address) != nil {
+		c = s.makeExternalIPCluster(cfgSnap, opts)
+	}
+	if grpc {
+		if err := s.setHttp2ProtocolOptions(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// makeRemoteJWKSCluster returns a cluster for the host of a remote JSON Web
+// Key Set URI. TLS is originated for https URIs, and the certificate of the
+// host is verified so that the keys can't be served by anyone else.
+func (s *ResourceGenerator) makeRemoteJWKSCluster(cfgSnap *proxycfg.ConfigSnapshot, name string, jwks structs.APIGatewayJSONWebKeySet) (*envoy_cluster_v3.Cluster, error) {
+	uri := jwks.RemoteURI
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS URI %q: %w", uri, err)
+	}
+
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		if port, err = strconv.Atoi(u.Port()); err != nil {
+			return nil, fmt.Errorf("invalid port in JWKS URI %q: %w", uri, err)
+		}
+	}
+
+	c, err := s.makeGatewayPolicyServiceCluster(cfgSnap, name, u.Hostname(), port, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "https" {
+		caFile := jwks.CAFile
+		if caFile == "" {
+			cfg, err := ParseGatewayConfig(cfgSnap.Proxy.Config)
+			if err != nil {
+				// Don't hard fail on a config typo, just warn. The parse func returns
+				// default config if there is an error so it's safe to continue.
+				s.Logger.Warn("failed to parse gateway config", "error", err)
+			}
+			caFile = cfg.JWKSCAFile
+		}
+		if caFile == "" {
+			caFile = defaultJWKSCAFile
+		}
+		tlsContext := &envoy_tls_v3.UpstreamTlsContext{
+			Sni: u.Hostname(),
+			CommonTlsContext: &envoy_tls_v3.CommonTlsContext{
+				ValidationContextType: &envoy_tls_v3.CommonTlsContext_ValidationContext{
+					ValidationContext: &envoy_tls_v3.CertificateValidationContext{
+						TrustedCa: &envoy_core_v3.DataSource{
+							Specifier: &envoy_core_v3.DataSource_Filename{Filename: caFile},
+						},
+					},
+				},
+			},
+		}
+		if err := injectSANMatcher(tlsContext.CommonTlsContext, u.Hostname()); err != nil {
+			return nil, err
+		}
+		transportSocket, err := makeUpstreamTLSTransportSocket(tlsContext)
+		if err != nil {
+			return nil, err
+		}
+		c.TransportSocket = transportSocket
+	}
+	return c, nil
+}
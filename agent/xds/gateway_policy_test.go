@@ -0,0 +1,198 @@
+package xds
+
+import (
+	"testing"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestInjectExtAuthzToVirtualHost_DisabledPaths(t *testing.T) {
+	vh := &envoy_route_v3.VirtualHost{
+		Routes: []*envoy_route_v3.Route{
+			{
+				Match: &envoy_route_v3.RouteMatch{
+					PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/public-admin"},
+				},
+			},
+			{
+				Match: &envoy_route_v3.RouteMatch{
+					PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/public"},
+				},
+				Action: &envoy_route_v3.Route_Route{Route: &envoy_route_v3.RouteAction{
+					PrefixRewrite: "/v1",
+				}},
+			},
+			{
+				Match: &envoy_route_v3.RouteMatch{
+					PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"},
+				},
+			},
+		},
+	}
+
+	err := injectExtAuthzToVirtualHost(&structs.APIGatewayExtAuthz{
+		DisabledPaths: []string{"/public"},
+	}, vh)
+	require.NoError(t, err)
+
+	type route struct {
+		match    string
+		rewrite  string
+		disabled bool
+	}
+	var got []route
+	for _, r := range vh.Routes {
+		var match string
+		switch m := r.Match.PathSpecifier.(type) {
+		case *envoy_route_v3.RouteMatch_Prefix:
+			match = "prefix " + m.Prefix
+		case *envoy_route_v3.RouteMatch_Path:
+			match = "path " + m.Path
+		}
+		_, disabled := r.TypedPerFilterConfig[extAuthzFilterName]
+		got = append(got, route{match: match, rewrite: r.GetRoute().GetPrefixRewrite(), disabled: disabled})
+	}
+
+	require.Equal(t, []route{
+		{match: "prefix /public-admin"},
+		// The /public prefix route also matches /public-admin, so only copies
+		// of it matching the disabled path have authorization disabled.
+		{match: "path /public", rewrite: "/v1", disabled: true},
+		{match: "prefix /public/", rewrite: "/v1/", disabled: true},
+		{match: "prefix /public", rewrite: "/v1"},
+		{match: "path /public", disabled: true},
+		{match: "prefix /public/", disabled: true},
+		{match: "prefix /"},
+	}, got)
+}
+
+func TestInjectRateLimitsToVirtualHost_PathPrefix(t *testing.T) {
+	vh := &envoy_route_v3.VirtualHost{
+		Domains: []string{"*"},
+		Routes: []*envoy_route_v3.Route{
+			{
+				Match: &envoy_route_v3.RouteMatch{
+					PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/api-v2"},
+				},
+			},
+			{
+				Match: &envoy_route_v3.RouteMatch{
+					PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/api/"},
+				},
+			},
+		},
+	}
+
+	err := injectRateLimitsToVirtualHost("http", &structs.APIGatewayRateLimit{
+		Routes: []structs.APIGatewayRouteRateLimit{
+			{PathPrefix: "/api", Local: structs.APIGatewayLocalRateLimit{MaxTokens: 10, TokensPerFill: 10}},
+		},
+	}, vh)
+	require.NoError(t, err)
+
+	require.Len(t, vh.Routes, 2)
+	require.NotContains(t, vh.Routes[0].TypedPerFilterConfig, localRateLimitFilterName)
+	require.Contains(t, vh.Routes[1].TypedPerFilterConfig, localRateLimitFilterName)
+}
+
+func TestMakeJWTAuthnFilter_AllowMissingPaths(t *testing.T) {
+	filter, err := makeJWTAuthnFilter(8080, &structs.APIGatewayJWTPolicy{
+		Providers: []structs.APIGatewayJWTProvider{
+			{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: structs.APIGatewayJSONWebKeySet{
+					Local: `{"keys":[]}`,
+				},
+			},
+		},
+		AllowMissingPaths: []string{"/public", "/docs/"},
+	})
+	require.NoError(t, err)
+
+	var cfg envoy_jwt_authn_v3.JwtAuthentication
+	require.NoError(t, filter.GetTypedConfig().UnmarshalTo(&cfg))
+
+	var matches []string
+	for _, rule := range cfg.Rules {
+		switch m := rule.Match.PathSpecifier.(type) {
+		case *envoy_route_v3.RouteMatch_Prefix:
+			matches = append(matches, "prefix "+m.Prefix)
+		case *envoy_route_v3.RouteMatch_Path:
+			matches = append(matches, "path "+m.Path)
+		}
+	}
+	require.Equal(t, []string{
+		"path /public",
+		"prefix /public/",
+		"prefix /docs/",
+		"prefix /",
+	}, matches)
+}
+
+func TestMakeRemoteJWKSCluster_TLS(t *testing.T) {
+	gatewayCA := map[string]interface{}{"envoy_jwks_ca_file": "/etc/consul/gateway-ca.pem"}
+
+	cases := map[string]struct {
+		jwks       structs.APIGatewayJSONWebKeySet
+		config     map[string]interface{}
+		wantCAFile string
+	}{
+		"system bundle": {
+			jwks:       structs.APIGatewayJSONWebKeySet{RemoteURI: "https://okta.example.com/keys"},
+			wantCAFile: defaultJWKSCAFile,
+		},
+		"gateway CA": {
+			jwks:       structs.APIGatewayJSONWebKeySet{RemoteURI: "https://okta.example.com/keys"},
+			config:     gatewayCA,
+			wantCAFile: "/etc/consul/gateway-ca.pem",
+		},
+		"provider CA": {
+			jwks: structs.APIGatewayJSONWebKeySet{
+				RemoteURI: "https://okta.example.com:8443/keys",
+				CAFile:    "/etc/consul/okta-ca.pem",
+			},
+			config:     gatewayCA,
+			wantCAFile: "/etc/consul/okta-ca.pem",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfgSnap := &proxycfg.ConfigSnapshot{}
+			cfgSnap.Proxy.Config = tc.config
+
+			s := NewResourceGenerator(testutil.Logger(t), nil, false)
+			c, err := s.makeRemoteJWKSCluster(cfgSnap, "jwks_okta", tc.jwks)
+			require.NoError(t, err)
+			require.NotNil(t, c.TransportSocket)
+
+			var tlsContext envoy_tls_v3.UpstreamTlsContext
+			require.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(&tlsContext))
+			require.Equal(t, "okta.example.com", tlsContext.Sni)
+
+			validationCtx := tlsContext.CommonTlsContext.GetValidationContext()
+			require.NotNil(t, validationCtx)
+			require.Equal(t, tc.wantCAFile, validationCtx.TrustedCa.GetFilename())
+			//nolint:staticcheck
+			sans := validationCtx.MatchSubjectAltNames
+			require.Len(t, sans, 1)
+			require.Equal(t, "okta.example.com", sans[0].GetExact())
+		})
+	}
+
+	t.Run("plain http", func(t *testing.T) {
+		s := NewResourceGenerator(testutil.Logger(t), nil, false)
+		c, err := s.makeRemoteJWKSCluster(&proxycfg.ConfigSnapshot{}, "jwks_okta", structs.APIGatewayJSONWebKeySet{
+			RemoteURI: "http://okta.example.com/keys",
+		})
+		require.NoError(t, err)
+		require.Nil(t, c.TransportSocket)
+	})
+}
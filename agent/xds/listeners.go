@@ -14,6 +14,7 @@ import (
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_dfp_common_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/dynamic_forward_proxy/v3"
 	envoy_grpc_http1_bridge_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_http1_bridge/v3"
 	envoy_grpc_stats_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_stats/v3"
 	envoy_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
@@ -41,7 +42,6 @@ import (
 	"github.com/hashicorp/consul/agent/xds/accesslogs"
 	"github.com/hashicorp/consul/envoyextensions/xdscommon"
 	"github.com/hashicorp/consul/lib"
-	"github.com/hashicorp/consul/lib/stringslice"
 	"github.com/hashicorp/consul/proto/pbpeering"
 	"github.com/hashicorp/consul/sdk/iptables"
 	"github.com/hashicorp/consul/types"
@@ -83,11 +83,11 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 	// In that situation there is a single listener where we are redirecting outbound traffic,
 	// and each upstream gets a filter chain attached to that listener.
 	var outboundListener *envoy_listener_v3.Listener
+	outboundPort := iptables.DefaultTProxyOutboundPort
 
 	if cfgSnap.Proxy.Mode == structs.ProxyModeTransparent {
-		port := iptables.DefaultTProxyOutboundPort
 		if cfgSnap.Proxy.TransparentProxy.OutboundListenerPort != 0 {
-			port = cfgSnap.Proxy.TransparentProxy.OutboundListenerPort
+			outboundPort = cfgSnap.Proxy.TransparentProxy.OutboundListenerPort
 		}
 
 		originalDstFilter, err := makeEnvoyListenerFilter("envoy.filters.listener.original_dst", &envoy_original_dst_v3.OriginalDst{})
@@ -99,7 +99,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			name:       xdscommon.OutboundListenerName,
 			accessLogs: cfgSnap.Proxy.AccessLogs,
 			addr:       "127.0.0.1",
-			port:       port,
+			port:       outboundPort,
 			direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
 			logger:     s.Logger,
 		}
@@ -554,6 +554,16 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// Only add the outbound listener if configured.
 		if len(outboundListener.FilterChains) > 0 || outboundListener.DefaultFilterChain != nil {
 			resources = append(resources, outboundListener)
+
+			// The IPv6 traffic is redirected to the loopback address of IPv6,
+			// where a copy of the listener with the same filter chains is
+			// bound. The chains match on both IPv4 and IPv6 virtual IPs.
+			if proxyCfg.OutboundListenerIPv6 {
+				ipv6Listener := proto.Clone(outboundListener).(*envoy_listener_v3.Listener)
+				ipv6Listener.Name = fmt.Sprintf("%s:%s:%d", xdscommon.OutboundListenerName, "::1", outboundPort)
+				ipv6Listener.Address = makeAddress("::1", outboundPort)
+				resources = append(resources, ipv6Listener)
+			}
 		}
 	}
 
@@ -995,7 +1005,7 @@ func (s *ResourceGenerator) injectConnectionBalanceConfig(balanceType string, li
 // the authz filter to prevent unauthorized access.
 func (s *ResourceGenerator) injectConnectFilters(cfgSnap *proxycfg.ConfigSnapshot, listener *envoy_listener_v3.Listener) error {
 	authzFilter, err := makeRBACNetworkFilter(
-		cfgSnap.ConnectProxy.Intentions,
+		intentionsForPort(cfgSnap.ConnectProxy.Intentions, ""),
 		cfgSnap.IntentionDefaultAllow,
 		rbacLocalInfo{
 			trustDomain: cfgSnap.Roots.TrustDomain,
@@ -1277,7 +1287,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 		// For HTTP-like services attach an RBAC http filter and do a best-effort insert
 		if useHTTPFilter {
 			httpAuthzFilter, err := makeRBACHTTPFilter(
-				cfgSnap.ConnectProxy.Intentions,
+				intentionsForPort(cfgSnap.ConnectProxy.Intentions, ""),
 				cfgSnap.IntentionDefaultAllow,
 				rbacLocalInfo{
 					trustDomain: cfgSnap.Roots.TrustDomain,
@@ -1359,7 +1369,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 	}
 	if useHTTPFilter {
 		rbacFilter, err := makeRBACHTTPFilter(
-			cfgSnap.ConnectProxy.Intentions,
+			intentionsForPort(cfgSnap.ConnectProxy.Intentions, ""),
 			cfgSnap.IntentionDefaultAllow,
 			rbacLocalInfo{
 				trustDomain: cfgSnap.Roots.TrustDomain,
@@ -1375,6 +1385,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 		filterOpts.httpAuthzFilters = []*envoy_http_v3.HttpFilter{rbacFilter}
 
 		meshConfig := cfgSnap.MeshConfig()
+		filterOpts.requestNormalization = meshConfig.GetHTTPIncoming().GetRequestNormalization()
 		includeXFCC := meshConfig == nil || meshConfig.HTTP == nil || !meshConfig.HTTP.SanitizeXForwardedClientCert
 		notGRPC := cfg.Protocol != "grpc"
 		if includeXFCC && notGRPC {
@@ -1428,9 +1439,122 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 		return nil, fmt.Errorf("failed to attach Consul filters and TLS context to custom public listener: %v", err)
 	}
 
+	// The connections to the named ports of the local service are matched
+	// with the ALPN protocol set by the upstream clusters of the downstream
+	// proxies. They are not supported with an overridden local cluster.
+	if len(cfgSnap.Proxy.LocalServicePorts) > 0 && cfg.LocalClusterJSON == "" {
+		portChains, err := s.makeInboundPortFilterChains(cfgSnap, cfg, filterOpts, useHTTPFilter)
+		if err != nil {
+			return nil, err
+		}
+		tlsInspector, err := makeTLSInspectorListenerFilter()
+		if err != nil {
+			return nil, err
+		}
+		l.ListenerFilters = append(l.ListenerFilters, tlsInspector)
+		l.FilterChains = append(l.FilterChains, portChains...)
+	}
+
 	return l, err
 }
 
+// makeInboundPortFilterChains returns a filter chain for each named port of
+// the local service, which only enforces the intentions of that port.
+func (s *ResourceGenerator) makeInboundPortFilterChains(
+	cfgSnap *proxycfg.ConfigSnapshot,
+	cfg ProxyConfig,
+	filterOpts listenerFilterOpts,
+	useHTTPFilter bool,
+) ([]*envoy_listener_v3.FilterChain, error) {
+	localInfo := rbacLocalInfo{
+		trustDomain: cfgSnap.Roots.TrustDomain,
+		datacenter:  cfgSnap.Datacenter,
+		partition:   cfgSnap.ProxyID.PartitionOrDefault(),
+	}
+	transportSocket, err := createDownstreamTransportSocketForConnectTLS(cfgSnap, cfgSnap.PeeringTrustBundles())
+	if err != nil {
+		return nil, err
+	}
+
+	var chains []*envoy_listener_v3.FilterChain
+	for _, port := range cfgSnap.Proxy.LocalServicePorts {
+		intentions := intentionsForPort(cfgSnap.ConnectProxy.Intentions, port.Name)
+
+		opts := filterOpts
+		opts.filterName = filterOpts.filterName + "_" + port.Name
+		opts.routeName = filterOpts.routeName + "_" + port.Name
+		opts.cluster = localAppPortClusterName(port.Name)
+
+		var filters []*envoy_listener_v3.Filter
+		if useHTTPFilter {
+			rbacFilter, err := makeRBACHTTPFilter(intentions, cfgSnap.IntentionDefaultAllow, localInfo, cfgSnap.ConnectProxy.InboundPeerTrustBundles)
+			if err != nil {
+				return nil, err
+			}
+			// The RBAC filter is always the first of the HTTP filters.
+			opts.httpAuthzFilters = append([]*envoy_http_v3.HttpFilter{rbacFilter}, filterOpts.httpAuthzFilters[1:]...)
+		} else {
+			rbacFilter, err := makeRBACNetworkFilter(intentions, cfgSnap.IntentionDefaultAllow, localInfo, cfgSnap.ConnectProxy.InboundPeerTrustBundles)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, rbacFilter)
+		}
+
+		if cfg.MaxInboundConnections > 0 {
+			connectionLimitFilter, err := makeConnectionLimitFilter(cfg.MaxInboundConnections)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, connectionLimitFilter)
+		}
+
+		filter, err := makeListenerFilter(opts)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+
+		chains = append(chains, &envoy_listener_v3.FilterChain{
+			FilterChainMatch: &envoy_listener_v3.FilterChainMatch{
+				ApplicationProtocols: []string{servicePortALPN(port.Name)},
+			},
+			Filters:         filters,
+			TransportSocket: transportSocket,
+		})
+	}
+	return chains, nil
+}
+
+// servicePortALPN returns the ALPN protocol used to select a named port of
+// the destination service.
+func servicePortALPN(port string) string {
+	return "consul~" + port
+}
+
+// intentionsForPort returns the intentions that apply to the connections to
+// a named port of the local service, or to its default port if port is
+// empty. The intentions without destination ports apply to all the ports.
+func intentionsForPort(intentions structs.Intentions, port string) structs.Intentions {
+	out := make(structs.Intentions, 0, len(intentions))
+	for _, ixn := range intentions {
+		if len(ixn.DestinationPorts) == 0 {
+			out = append(out, ixn)
+			continue
+		}
+		if port == "" {
+			continue
+		}
+		for _, p := range ixn.DestinationPorts {
+			if p == port {
+				out = append(out, ixn)
+				break
+			}
+		}
+	}
+	return out
+}
+
 // finalizePublicListenerFromConfig is used for best-effort injection of Consul filter-chains onto listeners.
 // This include L4 authorization filters and TLS context.
 func (s *ResourceGenerator) finalizePublicListenerFromConfig(l *envoy_listener_v3.Listener, cfgSnap *proxycfg.ConfigSnapshot, useHTTPFilter bool) error {
@@ -1645,6 +1769,10 @@ func (s *ResourceGenerator) makeTerminatingGatewayListener(
 
 			opts.cluster = clusterName
 			opts.address = address
+			opts.dnsCache = nil
+			if structs.IsWildcardHostname(address) {
+				opts.dnsCache = makeDestinationDNSCacheConfig(clusterName, *dest)
+			}
 			clusterChain, err := s.makeFilterChainTerminatingGateway(cfgSnap, opts)
 			if err != nil {
 				return nil, fmt.Errorf("failed to make filter chain for cluster %q: %v", clusterName, err)
@@ -1701,6 +1829,10 @@ type terminatingGatewayFilterChainOpts struct {
 	protocol   string
 	address    string // only valid for destination listeners
 	port       int    // only valid for destination listeners
+
+	// dnsCache is only set for wildcard destinations, whose requested host is
+	// resolved by the dynamic forward proxy.
+	dnsCache *envoy_dfp_common_v3.DnsCacheConfig
 }
 
 func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.ConfigSnapshot, tgtwyOpts terminatingGatewayFilterChainOpts) (*envoy_listener_v3.FilterChain, error) {
@@ -1790,6 +1922,14 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.
 
 		opts.httpAuthzFilters = []*envoy_http_v3.HttpFilter{rbacFilter}
 
+		if tgtwyOpts.dnsCache != nil {
+			dfpFilter, err := makeDynamicForwardProxyFilter(tgtwyOpts.dnsCache)
+			if err != nil {
+				return nil, err
+			}
+			opts.httpAuthzFilters = append(opts.httpAuthzFilters, dfpFilter)
+		}
+
 		opts.cluster = ""
 		opts.useRDS = true
 
@@ -1857,16 +1997,58 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 		peerNames := cfgSnap.MeshGateway.ExportedServicesWithPeers[svc]
 		chain := cfgSnap.MeshGateway.DiscoveryChain[svc]
 
-		filterChain, err := s.makeMeshGatewayPeerFilterChain(cfgSnap, svc, peerNames, chain)
-		if err != nil {
-			return nil, err
-		} else if filterChain == nil {
-			continue
+		// We need 1 Filter Chain per peer so that the traffic received from
+		// each peer is reported under its own stat prefix.
+		for _, peerName := range peerNames {
+			filterChain, err := s.makeMeshGatewayPeerFilterChain(cfgSnap, svc, peerName, chain)
+			if err != nil {
+				return nil, err
+			} else if filterChain == nil {
+				continue
+			}
+
+			l.FilterChains = append(l.FilterChains, filterChain)
 		}
+	}
 
-		l.FilterChains = append(l.FilterChains, filterChain)
+	// Create a filter chain per service imported from a peer. These are otherwise
+	// handled by the sni_cluster catch-all below, but routing them explicitly
+	// lets the traffic sent to each peer be reported under its own stat prefix.
+	var importedFilterChains []*envoy_listener_v3.FilterChain
+	for peerName, serviceGroups := range cfgSnap.MeshGateway.PeeringServices {
+		for sn, serviceGroup := range serviceGroups {
+			if len(serviceGroup.Nodes) == 0 || serviceGroup.Nodes[0].Service == nil {
+				continue
+			}
+			clusterName := serviceGroup.Nodes[0].Service.Connect.PeerMeta.PrimarySNI()
+
+			filterOpts := listenerFilterOpts{
+				accessLogs: &cfgSnap.Proxy.AccessLogs,
+				cluster:    clusterName,
+				filterName: fmt.Sprintf("%s.%s.%s.%s", sn.Name, sn.NamespaceOrDefault(), sn.PartitionOrDefault(), peerName),
+				logger:     s.Logger,
+				statPrefix: "mesh_gateway_remote_peered.",
+			}
+			tcpProxy, err := makeTCPProxyFilter(filterOpts)
+			if err != nil {
+				return nil, err
+			}
+
+			importedFilterChains = append(importedFilterChains, &envoy_listener_v3.FilterChain{
+				FilterChainMatch: makeSNIFilterChainMatch(clusterName),
+				Filters: []*envoy_listener_v3.Filter{
+					tcpProxy,
+				},
+			})
+		}
 	}
 
+	// Sort so the output is stable and the listener doesn't get drained
+	sort.Slice(importedFilterChains, func(i, j int) bool {
+		return importedFilterChains[i].FilterChainMatch.ServerNames[0] < importedFilterChains[j].FilterChainMatch.ServerNames[0]
+	})
+	l.FilterChains = append(l.FilterChains, importedFilterChains...)
+
 	// We need 1 Filter Chain per remote cluster
 	keys := cfgSnap.MeshGateway.GatewayKeys()
 	for _, key := range keys {
@@ -2043,7 +2225,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 func (s *ResourceGenerator) makeMeshGatewayPeerFilterChain(
 	cfgSnap *proxycfg.ConfigSnapshot,
 	svc structs.ServiceName,
-	peerNames []string,
+	peerName string,
 	chain *structs.CompiledDiscoveryChain,
 ) (*envoy_listener_v3.FilterChain, error) {
 	var (
@@ -2069,7 +2251,8 @@ func (s *ResourceGenerator) makeMeshGatewayPeerFilterChain(
 
 	uid := proxycfg.NewUpstreamIDFromServiceName(svc)
 
-	filterName := fmt.Sprintf("%s.%s.%s.%s", chain.ServiceName, chain.Namespace, chain.Partition, chain.Datacenter)
+	// The traffic of each peer is reported under its own stat prefix.
+	filterName := fmt.Sprintf("%s.%s.%s.%s", chain.ServiceName, chain.Namespace, chain.Partition, peerName)
 
 	filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
 		accessLogs:           &cfgSnap.Proxy.AccessLogs,
@@ -2086,26 +2269,20 @@ func (s *ResourceGenerator) makeMeshGatewayPeerFilterChain(
 		return nil, err
 	}
 
-	var peeredServerNames []string
-	for _, peerName := range peerNames {
-		peeredSNI := connect.PeeredServiceSNI(
-			svc.Name,
-			svc.NamespaceOrDefault(),
-			svc.PartitionOrDefault(),
-			peerName,
-			cfgSnap.Roots.TrustDomain,
-		)
-		peeredServerNames = append(peeredServerNames, peeredSNI)
-	}
-	filterChain.FilterChainMatch = &envoy_listener_v3.FilterChainMatch{
-		ServerNames: peeredServerNames,
-	}
+	peeredSNI := connect.PeeredServiceSNI(
+		svc.Name,
+		svc.NamespaceOrDefault(),
+		svc.PartitionOrDefault(),
+		peerName,
+		cfgSnap.Roots.TrustDomain,
+	)
+	filterChain.FilterChainMatch = makeSNIFilterChainMatch(peeredSNI)
 
 	if useHTTPFilter {
 		// We only terminate TLS if we're doing an L7 proxy.
 		var peerBundles []*pbpeering.PeeringTrustBundle
 		for _, bundle := range cfgSnap.MeshGateway.PeeringTrustBundles {
-			if stringslice.Contains(peerNames, bundle.PeerName) {
+			if bundle.PeerName == peerName {
 				peerBundles = append(peerBundles, bundle)
 			}
 		}
@@ -2296,6 +2473,7 @@ type listenerFilterOpts struct {
 	forwardClientPolicy  envoy_http_v3.HttpConnectionManager_ForwardClientCertDetails
 	httpAuthzFilters     []*envoy_http_v3.HttpFilter
 	idleTimeoutMs        *int
+	requestNormalization *structs.RequestNormalizationMeshConfig
 	requestTimeoutMs     *int
 	routeName            string
 	routePath            string
@@ -2484,6 +2662,10 @@ func makeHTTPFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error)
 		cfg.Http2ProtocolOptions = &envoy_core_v3.Http2ProtocolOptions{}
 	}
 
+	if opts.requestNormalization != nil {
+		applyRequestNormalizationOptions(cfg, opts.requestNormalization)
+	}
+
 	// Note the default leads to setting HttpConnectionManager_SANITIZE
 	if opts.forwardClientDetails {
 		cfg.ForwardClientCertDetails = opts.forwardClientPolicy
@@ -2538,6 +2720,28 @@ func makeHTTPFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error)
 	return makeFilter("envoy.filters.network.http_connection_manager", cfg)
 }
 
+// applyRequestNormalizationOptions sets the options of the mesh config entry
+// that normalize the requests before the HTTP filters of the connection
+// manager see them.
+func applyRequestNormalizationOptions(cfg *envoy_http_v3.HttpConnectionManager, rn *structs.RequestNormalizationMeshConfig) {
+	if rn.NormalizePath {
+		cfg.NormalizePath = makeBoolValue(true)
+	}
+	cfg.MergeSlashes = rn.MergeSlashes
+
+	if rn.PathWithEscapedSlashesAction != "" {
+		action := envoy_http_v3.HttpConnectionManager_PathWithEscapedSlashesAction_value[string(rn.PathWithEscapedSlashesAction)]
+		cfg.PathWithEscapedSlashesAction = envoy_http_v3.HttpConnectionManager_PathWithEscapedSlashesAction(action)
+	}
+
+	if rn.HeadersWithUnderscoresAction != "" {
+		action := envoy_core_v3.HttpProtocolOptions_HeadersWithUnderscoresAction_value[string(rn.HeadersWithUnderscoresAction)]
+		cfg.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{
+			HeadersWithUnderscoresAction: envoy_core_v3.HttpProtocolOptions_HeadersWithUnderscoresAction(action),
+		}
+	}
+}
+
 func makeEnvoyListenerFilter(name string, cfg proto.Message) (*envoy_listener_v3.ListenerFilter, error) {
 	any, err := anypb.New(cfg)
 	if err != nil {
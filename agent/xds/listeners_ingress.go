@@ -30,6 +30,12 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			return nil, err
 		}
 
+		// Listeners may override the access logging configured for the proxy.
+		accessLogs := cfgSnap.Proxy.AccessLogs
+		if listenerCfg.AccessLogs != nil {
+			accessLogs = *listenerCfg.AccessLogs
+		}
+
 		if listenerKey.Protocol == "tcp" {
 			// We rely on the invariant of upstreams slice always having at least 1
 			// member, because this key/value pair is created only when a
@@ -65,7 +71,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 
 			opts := makeListenerOpts{
 				name:       uid.EnvoyID(),
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: accessLogs,
 				addr:       address,
 				port:       u.LocalBindPort,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -73,7 +79,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			}
 			l := makeListener(opts)
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:  &cfgSnap.Proxy.AccessLogs,
+				accessLogs:  &accessLogs,
 				routeName:   uid.EnvoyID(),
 				useRDS:      useRDS,
 				clusterName: clusterName,
@@ -93,7 +99,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			// If multiple upstreams share this port, make a special listener for the protocol.
 			listenerOpts := makeListenerOpts{
 				name:       listenerKey.Protocol,
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: accessLogs,
 				addr:       address,
 				port:       listenerKey.Port,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -101,6 +107,11 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			}
 			listener := makeListener(listenerOpts)
 
+			policyFilters, err := makeGatewayPolicyFilters(cfgSnap, listenerKey.Port)
+			if err != nil {
+				return nil, err
+			}
+
 			filterOpts := listenerFilterOpts{
 				useRDS:           true,
 				protocol:         listenerKey.Protocol,
@@ -109,8 +120,8 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 				cluster:          "",
 				statPrefix:       "ingress_upstream_",
 				routePath:        "",
-				httpAuthzFilters: nil,
-				accessLogs:       &cfgSnap.Proxy.AccessLogs,
+				httpAuthzFilters: policyFilters,
+				accessLogs:       &accessLogs,
 				logger:           s.Logger,
 			}
 
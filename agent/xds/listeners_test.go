@@ -13,6 +13,7 @@ import (
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	testinf "github.com/mitchellh/go-testing-interface"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hashicorp/consul/agent/proxycfg"
 	"github.com/hashicorp/consul/agent/structs"
@@ -223,6 +224,34 @@ func TestListenersFromSnapshot(t *testing.T) {
 					})
 			},
 		},
+		{
+			name: "http-public-listener-request-normalization",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t,
+					func(ns *structs.NodeService) {
+						ns.Proxy.Config["protocol"] = "http"
+					},
+					[]proxycfg.UpdateEvent{
+						{
+							CorrelationID: "mesh",
+							Result: &structs.ConfigEntryResponse{
+								Entry: &structs.MeshConfigEntry{
+									HTTP: &structs.MeshHTTPConfig{
+										Incoming: &structs.MeshDirectionalHTTPConfig{
+											RequestNormalization: &structs.RequestNormalizationMeshConfig{
+												NormalizePath:                true,
+												MergeSlashes:                 true,
+												PathWithEscapedSlashesAction: structs.PathWithEscapedSlashesActionUnescapeAndForward,
+												HeadersWithUnderscoresAction: structs.HeadersWithUnderscoresActionRejectRequest,
+											},
+										},
+									},
+								},
+							},
+						},
+					})
+			},
+		},
 		{
 			name: "http-listener-with-timeouts",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -886,6 +915,61 @@ func TestListenersFromSnapshot(t *testing.T) {
 					nil)
 			},
 		},
+		{
+			name: "ingress-gateway-listener-access-logs",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotIngressGateway(t, true, "http", "default",
+					func(ns *structs.NodeService) {
+						// This should be passed into the snapshot through proxy-defaults
+						ns.Proxy.AccessLogs = structs.AccessLogsConfig{
+							Enabled: true,
+						}
+					},
+					func(entry *structs.IngressGatewayConfigEntry) {
+						entry.Listeners[0].AccessLogs = &structs.AccessLogsConfig{
+							Enabled:    true,
+							Type:       structs.FileLogSinkType,
+							Path:       "/var/log/ingress-8080.log",
+							TextFormat: "%START_TIME% %REQ(:PATH)% %RESPONSE_CODE%",
+						}
+					}, nil)
+			},
+		},
+		{
+			name: "ingress-gateway-listener-access-logs-disabled",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotIngressGateway(t, true, "tcp", "default",
+					func(ns *structs.NodeService) {
+						// This should be passed into the snapshot through proxy-defaults
+						ns.Proxy.AccessLogs = structs.AccessLogsConfig{
+							Enabled: true,
+						}
+					},
+					func(entry *structs.IngressGatewayConfigEntry) {
+						entry.Listeners[0].AccessLogs = &structs.AccessLogsConfig{
+							Enabled: false,
+						}
+					}, nil)
+			},
+		},
+		{
+			name: "api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotAPIGateway(t, nil, nil)
+			},
+		},
+		{
+			name:   "api-gateway-with-rate-limits",
+			create: proxycfg.TestConfigSnapshotAPIGatewayRateLimits,
+		},
+		{
+			name:   "api-gateway-with-jwt",
+			create: proxycfg.TestConfigSnapshotAPIGatewayJWT,
+		},
+		{
+			name:   "api-gateway-with-ext-authz",
+			create: proxycfg.TestConfigSnapshotAPIGatewayExtAuthz,
+		},
 	}
 
 	latestEnvoyVersion := xdscommon.EnvoyVersions[0]
@@ -946,6 +1030,50 @@ func TestListenersFromSnapshot(t *testing.T) {
 	}
 }
 
+func TestListenersFromSnapshot_TransparentProxyIPv6(t *testing.T) {
+	snap := proxycfg.TestConfigSnapshotTransparentProxy(t)
+	testcommon.SetupTLSRootsAndLeaf(t, snap)
+	snap.Proxy.Config = map[string]interface{}{
+		"outbound_listener_ipv6": true,
+	}
+
+	g := NewResourceGenerator(testutil.Logger(t), nil, false)
+	listeners, err := g.listenersFromSnapshot(snap)
+	require.NoError(t, err)
+
+	byName := make(map[string]*envoy_listener_v3.Listener)
+	for _, l := range listeners {
+		byName[l.(*envoy_listener_v3.Listener).Name] = l.(*envoy_listener_v3.Listener)
+	}
+
+	ipv4 := byName[xdscommon.OutboundListenerName+":127.0.0.1:15001"]
+	ipv6 := byName[xdscommon.OutboundListenerName+":::1:15001"]
+	require.NotNil(t, ipv4)
+	require.NotNil(t, ipv6)
+	require.Equal(t, "::1", ipv6.Address.GetSocketAddress().Address)
+	require.Equal(t, uint32(15001), ipv6.Address.GetSocketAddress().GetPortValue())
+
+	// Both listeners route the traffic with the same chains.
+	require.NotEmpty(t, ipv4.FilterChains)
+	require.Len(t, ipv6.FilterChains, len(ipv4.FilterChains))
+	for i := range ipv4.FilterChains {
+		require.True(t, proto.Equal(ipv4.FilterChains[i], ipv6.FilterChains[i]))
+	}
+	require.True(t, proto.Equal(ipv4.DefaultFilterChain, ipv6.DefaultFilterChain))
+}
+
+func TestMakeFilterChainMatchFromAddrs_IPv6(t *testing.T) {
+	match := makeFilterChainMatchFromAddrs(map[string]struct{}{
+		"240.0.0.1":     {},
+		"2001:db8::100": {},
+	})
+	require.Len(t, match.PrefixRanges, 2)
+	require.Equal(t, "2001:db8::100", match.PrefixRanges[0].AddressPrefix)
+	require.Equal(t, uint32(128), match.PrefixRanges[0].PrefixLen.GetValue())
+	require.Equal(t, "240.0.0.1", match.PrefixRanges[1].AddressPrefix)
+	require.Equal(t, uint32(32), match.PrefixRanges[1].PrefixLen.GetValue())
+}
+
 type customListenerJSONOptions struct {
 	Name       string
 	TLSContext string
@@ -1298,3 +1426,38 @@ func TestGetAlpnProtocols(t *testing.T) {
 		})
 	}
 }
+
+func TestIntentionsForPort(t *testing.T) {
+	all := &structs.Intention{SourceName: "all"}
+	admin := &structs.Intention{SourceName: "admin", DestinationPorts: []string{"admin"}}
+	data := &structs.Intention{SourceName: "data", DestinationPorts: []string{"data", "metrics"}}
+	intentions := structs.Intentions{all, admin, data}
+
+	tests := map[string]struct {
+		port string
+		want structs.Intentions
+	}{
+		"default port": {
+			port: "",
+			want: structs.Intentions{all},
+		},
+		"admin": {
+			port: "admin",
+			want: structs.Intentions{all, admin},
+		},
+		"metrics": {
+			port: "metrics",
+			want: structs.Intentions{all, data},
+		},
+		"unknown": {
+			port: "other",
+			want: structs.Intentions{all},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, intentionsForPort(intentions, tc.port))
+		})
+	}
+}
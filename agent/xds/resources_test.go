@@ -196,6 +196,10 @@ func getConnectProxyTransparentProxyGoldenTestCases() []goldenTestCase {
 				return proxycfg.TestConfigSnapshotTerminatingGatewayDestinations(t, true, nil)
 			},
 		},
+		{
+			name:   "terminating-gateway-wildcard-destination",
+			create: proxycfg.TestConfigSnapshotTerminatingGatewayWildcardDestination,
+		},
 	}
 }
 
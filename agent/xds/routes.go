@@ -192,6 +192,9 @@ func (s *ResourceGenerator) routesForTerminatingGateway(cfgSnap *proxycfg.Config
 			if err != nil {
 				return nil, err
 			}
+			if structs.IsWildcardHostname(address) {
+				restrictRoutesToWildcardDestination(routes, address, svcConfig.Destination.Port)
+			}
 			if routes != nil {
 				resources = append(resources, routes...)
 			}
@@ -201,6 +204,23 @@ func (s *ResourceGenerator) routesForTerminatingGateway(cfgSnap *proxycfg.Config
 	return resources, nil
 }
 
+// restrictRoutesToWildcardDestination limits the routes of a wildcard
+// Destination to the hosts matching it. The dynamic forward proxy resolves
+// whichever host is requested, so without this any Host header would be
+// forwarded to. Requests for other hosts match no virtual host and are
+// rejected.
+func restrictRoutesToWildcardDestination(routes []proto.Message, address string, port int) {
+	for _, r := range routes {
+		route, ok := r.(*envoy_route_v3.RouteConfiguration)
+		if !ok {
+			continue
+		}
+		for _, vh := range route.VirtualHosts {
+			vh.Domains = []string{address, fmt.Sprintf("%s:%d", address, port)}
+		}
+	}
+}
+
 func (s *ResourceGenerator) makeRoutes(
 	cfgSnap *proxycfg.ConfigSnapshot,
 	svc structs.ServiceName,
@@ -398,6 +418,10 @@ func (s *ResourceGenerator) routesForIngressGateway(cfgSnap *proxycfg.ConfigSnap
 				return nil, err
 			}
 
+			if err := injectGatewayPolicyToVirtualHost(cfgSnap, listenerKey.Port, virtualHost); err != nil {
+				return nil, err
+			}
+
 			// See if this upstream has its own route/filter chain
 			svcRouteName := routeNameForUpstream(lCfg, *svc)
 
@@ -598,6 +622,11 @@ func (s *ResourceGenerator) makeUpstreamRouteForDiscoveryChain(
 				}
 			}
 
+			// Routes without retries of their own use the default retry policy.
+			if routeAction.Route.RetryPolicy == nil && chain.RetryPolicy != nil {
+				routeAction.Route.RetryPolicy = getRetryPolicyForChain(chain.RetryPolicy)
+			}
+
 			route.Match = routeMatch
 			route.Action = routeAction
 
@@ -616,6 +645,9 @@ func (s *ResourceGenerator) makeUpstreamRouteForDiscoveryChain(
 		if err := injectLBToRouteAction(lb, routeAction.Route); err != nil {
 			return nil, fmt.Errorf("failed to apply load balancer configuration to route action: %v", err)
 		}
+		if chain.RetryPolicy != nil {
+			routeAction.Route.RetryPolicy = getRetryPolicyForChain(chain.RetryPolicy)
+		}
 
 		defaultRoute := &envoy_route_v3.Route{
 			Match:  makeDefaultRouteMatch(),
@@ -636,6 +668,9 @@ func (s *ResourceGenerator) makeUpstreamRouteForDiscoveryChain(
 		if err := injectLBToRouteAction(lb, routeAction.Route); err != nil {
 			return nil, fmt.Errorf("failed to apply load balancer configuration to route action: %v", err)
 		}
+		if chain.RetryPolicy != nil {
+			routeAction.Route.RetryPolicy = getRetryPolicyForChain(chain.RetryPolicy)
+		}
 
 		defaultRoute := &envoy_route_v3.Route{
 			Match:  makeDefaultRouteMatch(),
@@ -694,6 +729,29 @@ func getRetryPolicyForDestination(destination *structs.ServiceRouteDestination)
 	return retryPolicy
 }
 
+// getRetryPolicyForChain returns the default retry policy of the routes of a
+// discovery chain.
+func getRetryPolicyForChain(policy *structs.RouteRetryPolicy) *envoy_route_v3.RetryPolicy {
+	retryPolicy := &envoy_route_v3.RetryPolicy{}
+	if policy.NumRetries > 0 {
+		retryPolicy.NumRetries = makeUint32Value(int(policy.NumRetries))
+	}
+
+	if policy.PerTryTimeout > 0 {
+		retryPolicy.PerTryTimeout = durationpb.New(policy.PerTryTimeout)
+	}
+
+	retryStrings := append([]string{}, policy.RetryOn...)
+	if len(policy.RetryOnStatusCodes) > 0 {
+		retryStrings = append(retryStrings, "retriable-status-codes")
+		retryPolicy.RetriableStatusCodes = policy.RetryOnStatusCodes
+	}
+
+	retryPolicy.RetryOn = strings.Join(retryStrings, ",")
+
+	return retryPolicy
+}
+
 func makeRouteMatchForDiscoveryRoute(discoveryRoute *structs.DiscoveryRoute) *envoy_route_v3.RouteMatch {
 	match := discoveryRoute.Definition.Match
 	if match == nil || match.IsEmpty() {
@@ -74,6 +74,18 @@ func TestRoutesFromSnapshot(t *testing.T) {
 				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", nil, nil)
 			},
 		},
+		{
+			name: "connect-proxy-with-default-retry-policy",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", nil, nil, defaultRetryPolicyServiceDefaults())
+			},
+		},
+		{
+			name: "connect-proxy-with-chain-and-router-default-retry-policy",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", nil, nil, defaultRetryPolicyServiceDefaults())
+			},
+		},
 		{
 			name: "connect-proxy-lb-in-resolver",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -186,6 +198,24 @@ func TestRoutesFromSnapshot(t *testing.T) {
 			name:   "terminating-gateway-lb-config",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewayLBConfig,
 		},
+		{
+			name: "api-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotAPIGateway(t, nil, nil)
+			},
+		},
+		{
+			name:   "api-gateway-with-rate-limits",
+			create: proxycfg.TestConfigSnapshotAPIGatewayRateLimits,
+		},
+		{
+			name:   "api-gateway-with-jwt",
+			create: proxycfg.TestConfigSnapshotAPIGatewayJWT,
+		},
+		{
+			name:   "api-gateway-with-ext-authz",
+			create: proxycfg.TestConfigSnapshotAPIGatewayExtAuthz,
+		},
 	}
 
 	latestEnvoyVersion := xdscommon.EnvoyVersions[0]
@@ -484,3 +514,19 @@ func TestEnvoyLBConfig_InjectToRouteAction(t *testing.T) {
 		})
 	}
 }
+
+// defaultRetryPolicyServiceDefaults returns the service-defaults of the db
+// service with a default route retry policy.
+func defaultRetryPolicyServiceDefaults() *structs.ServiceConfigEntry {
+	return &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "db",
+		Protocol: "http",
+		RetryPolicy: &structs.RouteRetryPolicy{
+			NumRetries:         3,
+			RetryOn:            []string{"connect-failure", "reset"},
+			RetryOnStatusCodes: []uint32{503},
+			PerTryTimeout:      2 * time.Second,
+		},
+	}
+}
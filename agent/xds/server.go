@@ -3,9 +3,11 @@ package xds
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync/atomic"
 	"time"
 
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 
 	"github.com/hashicorp/consul/envoyextensions/xdscommon"
@@ -40,6 +42,10 @@ var (
 			Name: []string{"xds", "server", "streamDrained"},
 			Help: "Counts the number of xDS streams that are drained when rebalancing the load between servers.",
 		},
+		{
+			Name: []string{"xds", "server", "unsupportedEnvoyVersion"},
+			Help: "Counts the number of xDS streams opened by Envoy proxies of an unsupported version, labeled by version and reason.",
+		},
 	}
 	StatsSummaries = []prometheus.SummaryDefinition{
 		{
@@ -115,6 +121,11 @@ type Server struct {
 	// there has been no recent DiscoveryRequest).
 	AuthCheckFrequency time.Duration
 
+	// StrictEnvoyVersion rejects the xDS streams of the Envoy proxies of a
+	// version that is not supported, rather than only logging a warning.
+	// Versions older than the oldest supported version are always rejected.
+	StrictEnvoyVersion bool
+
 	// ResourceMapMutateFn exclusively exists for testing purposes.
 	ResourceMapMutateFn func(resourceMap *xdscommon.IndexedResources)
 
@@ -212,6 +223,42 @@ func (s *Server) authenticate(ctx context.Context) (acl.Authorizer, error) {
 // proxy ID. We assume that any data in the snapshot was already filtered,
 // which allows this authorization to be a shallow authorization check
 // for all the data in a ConfigSnapshot.
+// checkEnvoyVersion warns about the Envoy proxies of a version that is not
+// supported, and rejects them in strict mode.
+func (s *Server) checkEnvoyVersion(logger hclog.Logger, node *envoy_core_v3.Node) error {
+	v, support := xdscommon.CheckEnvoyVersion(node)
+	if support == xdscommon.EnvoyVersionSupported {
+		return nil
+	}
+
+	envoyVersion := "unknown"
+	if v != nil {
+		envoyVersion = v.String()
+	}
+	metrics.IncrCounterWithLabels([]string{"xds", "server", "unsupportedEnvoyVersion"}, 1, []metrics.Label{
+		{Name: "version", Value: envoyVersion},
+		{Name: "reason", Value: string(support)},
+	})
+
+	// The versions that are too old are rejected when determining the
+	// supported features.
+	if support == xdscommon.EnvoyVersionTooOld {
+		return nil
+	}
+
+	logger.Warn("Envoy version is not supported",
+		"envoy_version", envoyVersion,
+		"reason", support,
+		"supported_versions", strings.Join(xdscommon.EnvoyVersions, ", "),
+	)
+	if s.StrictEnvoyVersion {
+		return status.Errorf(codes.FailedPrecondition,
+			"Envoy version %s is not supported (%s), supported versions are %s",
+			envoyVersion, support, strings.Join(xdscommon.EnvoyVersions, ", "))
+	}
+	return nil
+}
+
 func (s *Server) authorize(ctx context.Context, cfgSnap *proxycfg.ConfigSnapshot) error {
 	if cfgSnap == nil {
 		return status.Errorf(codes.Unauthenticated, "unauthenticated: no config snapshot")
@@ -19,6 +19,9 @@ func SetupTLSRootsAndLeaf(t *testing.T, snap *proxycfg.ConfigSnapshot) {
 		case structs.ServiceKindIngressGateway:
 			snap.IngressGateway.Leaf.CertPEM = loadTestResource(t, "test-leaf-cert")
 			snap.IngressGateway.Leaf.PrivateKeyPEM = loadTestResource(t, "test-leaf-key")
+		case structs.ServiceKindAPIGateway:
+			snap.APIGateway.Leaf.CertPEM = loadTestResource(t, "test-leaf-cert")
+			snap.APIGateway.Leaf.PrivateKeyPEM = loadTestResource(t, "test-leaf-key")
 		case structs.ServiceKindMeshGateway:
 			snap.MeshGateway.Leaf.CertPEM = loadTestResource(t, "test-leaf-cert")
 			snap.MeshGateway.Leaf.PrivateKeyPEM = loadTestResource(t, "test-leaf-key")
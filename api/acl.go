@@ -37,6 +37,7 @@ type ACLToken struct {
 	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
 	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
 	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	TemplatedPolicies []*ACLTemplatedPolicy `json:",omitempty"`
 	Local             bool
 	AuthMethod        string        `json:",omitempty"`
 	ExpirationTTL     time.Duration `json:",omitempty"`
@@ -44,6 +45,17 @@ type ACLToken struct {
 	CreateTime        time.Time     `json:",omitempty"`
 	Hash              []byte        `json:",omitempty"`
 
+	// ReplacedBy is the AccessorID of the token issued to replace this one
+	// when it was rotated.
+	ReplacedBy string `json:",omitempty"`
+
+	// LastUsedTime and LastUsedAddr are the last time the token was presented
+	// to an agent of the datacenter and the address of the client that
+	// presented it. They are approximate, as agents report the use of tokens
+	// periodically and servers only record it with a granularity of a minute.
+	LastUsedTime *time.Time `json:",omitempty"`
+	LastUsedAddr string     `json:",omitempty"`
+
 	// DEPRECATED (ACL-Legacy-Compat)
 	// Rules are an artifact of legacy tokens deprecated in Consul 1.4
 	Rules string `json:"-"`
@@ -61,6 +73,36 @@ type ACLToken struct {
 	AuthMethodNamespace string `json:",omitempty"`
 }
 
+// ACLTokenBulkOp is the operation of an item of a bulk token request.
+type ACLTokenBulkOp string
+
+const (
+	ACLTokenBulkCreate         ACLTokenBulkOp = "create"
+	ACLTokenBulkUpdate         ACLTokenBulkOp = "update"
+	ACLTokenBulkDelete         ACLTokenBulkOp = "delete"
+	ACLTokenBulkAttachPolicies ACLTokenBulkOp = "attach-policies"
+	ACLTokenBulkDetachPolicies ACLTokenBulkOp = "detach-policies"
+)
+
+// ACLTokenBulkOperation is an item of a bulk token request. The delete,
+// attach-policies and detach-policies operations only use the AccessorID
+// and the Policies of the token.
+type ACLTokenBulkOperation struct {
+	Op    ACLTokenBulkOp
+	Token *ACLToken
+}
+
+// ACLTokenBulkResult is the result of an operation of a bulk token request.
+type ACLTokenBulkResult struct {
+	AccessorID string
+
+	// Token is the token as written by the operations other than delete.
+	Token *ACLToken `json:",omitempty"`
+
+	// Error is set if the operation failed.
+	Error string `json:",omitempty"`
+}
+
 type ACLTokenExpanded struct {
 	ExpandedPolicies []ACLPolicy
 	ExpandedRoles    []ACLRole
@@ -85,10 +127,13 @@ type ACLTokenListEntry struct {
 	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
 	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
 	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	TemplatedPolicies []*ACLTemplatedPolicy `json:",omitempty"`
 	Local             bool
 	AuthMethod        string     `json:",omitempty"`
 	ExpirationTime    *time.Time `json:",omitempty"`
 	CreateTime        time.Time
+	LastUsedTime      *time.Time `json:",omitempty"`
+	LastUsedAddr      string     `json:",omitempty"`
 	Hash              []byte
 	Legacy            bool `json:"-"` // DEPRECATED
 
@@ -136,6 +181,12 @@ type ACLReplicationStatus struct {
 type ACLServiceIdentity struct {
 	ServiceName string
 	Datacenters []string `json:",omitempty"`
+
+	// Node and NodePrefix restrict the registration of the service to the
+	// node with this name, or to the nodes with names starting with this
+	// prefix.
+	Node       string `json:",omitempty"`
+	NodePrefix string `json:",omitempty"`
 }
 
 // ACLNodeIdentity represents a high-level grant of all necessary privileges
@@ -145,6 +196,35 @@ type ACLNodeIdentity struct {
 	Datacenter string
 }
 
+// ACLTemplatedPolicy links a token or a role to a built-in policy template,
+// rendered into a synthetic policy with the given variables.
+type ACLTemplatedPolicy struct {
+	TemplateName      string
+	TemplateVariables *ACLTemplatedPolicyVariables `json:",omitempty"`
+
+	// Datacenters restricts the synthetic policy to the given datacenters.
+	// It is valid within all datacenters if empty.
+	Datacenters []string `json:",omitempty"`
+}
+
+// ACLTemplatedPolicyVariables are the variables a template is rendered with.
+type ACLTemplatedPolicyVariables struct {
+	Name string `json:",omitempty"`
+}
+
+// ACLTemplatedPolicyResponse describes a built-in policy template.
+type ACLTemplatedPolicyResponse struct {
+	TemplateName string
+	Description  string
+
+	// RequiresName is true if the template must be given a Name variable.
+	RequiresName bool
+
+	// Template is the rules of the template, with placeholders in place of
+	// the variables.
+	Template string
+}
+
 // ACLPolicy represents an ACL Policy.
 type ACLPolicy struct {
 	ID          string
@@ -193,6 +273,7 @@ type ACLRole struct {
 	Policies          []*ACLRolePolicyLink  `json:",omitempty"`
 	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
 	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	TemplatedPolicies []*ACLTemplatedPolicy `json:",omitempty"`
 	Hash              []byte
 	CreateIndex       uint64
 	ModifyIndex       uint64
@@ -744,6 +825,37 @@ func (a *ACL) TokenUpdate(token *ACLToken, q *WriteOptions) (*ACLToken, *WriteMe
 	return &out, wm, nil
 }
 
+// TokenRotate issues a replacement for an existing token, with the same policies,
+// roles and identities but its own auto-generated AccessorID and SecretID. The existing
+// token remains valid for the overlap window and then expires. A zero overlap uses the
+// server's default of one hour.
+func (a *ACL) TokenRotate(accessorID string, overlap time.Duration, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if accessorID == "" {
+		return nil, nil, fmt.Errorf("Must specify a token AccessorID for Token Rotation")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/token/"+accessorID+"/rotate")
+	r.setWriteOptions(q)
+	if overlap > 0 {
+		r.obj = struct{ OverlapWindow string }{overlap.String()}
+	}
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLToken
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
 // TokenClone will create a new token with the same policies and locality as the original
 // token but will have its own auto-generated AccessorID and SecretID as well having the
 // description passed to this function. The accessorID parameter must be a valid Accessor ID
@@ -892,6 +1004,32 @@ func (a *ACL) TokenList(q *QueryOptions) ([]*ACLTokenListEntry, *QueryMeta, erro
 	return entries, qm, nil
 }
 
+// TokenBulk creates, updates and deletes many tokens, and attaches policies to
+// and detaches policies from existing tokens, in a single request. The valid
+// operations are applied atomically, and the results are returned in the same
+// order as the operations: the result of an operation that failed has its
+// Error set, and nothing was written for it.
+func (a *ACL) TokenBulk(ops []*ACLTokenBulkOperation, q *WriteOptions) ([]*ACLTokenBulkResult, *WriteMeta, error) {
+	r := a.c.newRequest("PUT", "/v1/acl/tokens/bulk")
+	r.setWriteOptions(q)
+	r.obj = ops
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out []*ACLTokenBulkResult
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return out, wm, nil
+}
+
 // PolicyCreate will create a new policy. It is not allowed for the policy parameters
 // ID field to be set as this will be generated by Consul while processing the request.
 func (a *ACL) PolicyCreate(policy *ACLPolicy, q *WriteOptions) (*ACLPolicy, *WriteMeta, error) {
@@ -1040,6 +1178,60 @@ func (a *ACL) PolicyList(q *QueryOptions) ([]*ACLPolicyListEntry, *QueryMeta, er
 	return entries, qm, nil
 }
 
+// TemplatedPolicyList retrieves a listing of all the built-in policy templates
+// that can be linked to tokens and roles.
+func (a *ACL) TemplatedPolicyList(q *QueryOptions) (map[string]ACLTemplatedPolicyResponse, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/templated-policies")
+	r.setQueryOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var entries map[string]ACLTemplatedPolicyResponse
+	if err := decodeBody(resp, &entries); err != nil {
+		return nil, nil, err
+	}
+	return entries, qm, nil
+}
+
+// TemplatedPolicyReadByName retrieves the built-in policy template with the
+// given name.
+func (a *ACL) TemplatedPolicyReadByName(templateName string, q *QueryOptions) (*ACLTemplatedPolicyResponse, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/templated-policy/name/"+templateName)
+	r.setQueryOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	found, resp, err := requireNotFoundOrOK(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	if !found {
+		return nil, qm, nil
+	}
+
+	var out ACLTemplatedPolicyResponse
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
 // RulesTranslate translates the legacy rule syntax into the current syntax.
 //
 // Deprecated: Support for the legacy syntax translation has been removed.
@@ -570,6 +570,84 @@ func TestAPI_ACLToken_Clone(t *testing.T) {
 	require.Equal(t, cloned, read)
 }
 
+func TestAPI_ACLToken_Rotate(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	created, _, err := acl.TokenCreate(&ACLToken{
+		Description:       "agent",
+		ServiceIdentities: []*ACLServiceIdentity{{ServiceName: "web"}},
+	}, nil)
+	require.NoError(t, err)
+
+	rotated, _, err := acl.TokenRotate(created.AccessorID, 10*time.Minute, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rotated)
+	require.NotEqual(t, created.AccessorID, rotated.AccessorID)
+	require.NotEqual(t, created.SecretID, rotated.SecretID)
+	require.Equal(t, "agent", rotated.Description)
+	require.Equal(t, created.ServiceIdentities, rotated.ServiceIdentities)
+	require.Nil(t, rotated.ExpirationTime)
+
+	old, _, err := acl.TokenRead(created.AccessorID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, old.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), *old.ExpirationTime, time.Minute)
+}
+
+func TestAPI_ACLToken_Bulk(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	policy, _, err := acl.PolicyCreate(&ACLPolicy{
+		Name:  "bulk",
+		Rules: `node_prefix "" { policy = "read" }`,
+	}, nil)
+	require.NoError(t, err)
+
+	existing, _, err := acl.TokenCreate(&ACLToken{Description: "existing"}, nil)
+	require.NoError(t, err)
+
+	results, _, err := acl.TokenBulk([]*ACLTokenBulkOperation{
+		{
+			Op:    ACLTokenBulkCreate,
+			Token: &ACLToken{Description: "bulk", Policies: []*ACLTokenPolicyLink{{Name: "bulk"}}},
+		},
+		{
+			Op:    ACLTokenBulkAttachPolicies,
+			Token: &ACLToken{AccessorID: existing.AccessorID, Policies: []*ACLTokenPolicyLink{{ID: policy.ID}}},
+		},
+		{
+			Op:    ACLTokenBulkDelete,
+			Token: &ACLToken{AccessorID: "8f246b77-f3e1-ff88-5b48-8ec93abf3e05"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Empty(t, results[0].Error)
+	require.Equal(t, "bulk", results[0].Token.Description)
+	require.Len(t, results[0].Token.Policies, 1)
+	require.Equal(t, policy.ID, results[0].Token.Policies[0].ID)
+
+	require.Empty(t, results[1].Error)
+	require.Equal(t, existing.AccessorID, results[1].AccessorID)
+	require.Len(t, results[1].Token.Policies, 1)
+
+	require.Contains(t, results[2].Error, "Cannot find token")
+
+	// The tokens can be found with a filter.
+	tokens, _, err := acl.TokenList(&QueryOptions{Filter: `"bulk" in Policies.Name`})
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+}
+
 func TestAPI_AuthMethod_List(t *testing.T) {
 	t.Parallel()
 	c, s := makeACLClient(t)
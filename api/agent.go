@@ -3,10 +3,12 @@ package api
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // ServiceKind is the kind of service being registered.
@@ -66,6 +68,7 @@ type AgentCheck struct {
 	Output      string
 	ServiceID   string
 	ServiceName string
+	PortName    string `json:",omitempty"`
 	Type        string
 	ExposedPort int
 	Definition  HealthCheckDefinition
@@ -73,6 +76,23 @@ type AgentCheck struct {
 	Partition   string `json:",omitempty"`
 }
 
+// AgentServiceDefinitionsFile is the state of a file of service definitions
+// watched by the agent with services_auto_reload.
+type AgentServiceDefinitionsFile struct {
+	// File is the path of the file.
+	File string
+
+	// Services are the IDs of the services of the file that are registered.
+	Services []string
+
+	// Error is the error of the last attempt to apply the file. The services
+	// of an invalid file stay registered as they were until it is fixed.
+	Error string `json:",omitempty"`
+
+	// LoadedAt is the time of the last attempt to apply the file.
+	LoadedAt time.Time
+}
+
 // AgentWeights represent optional weights for a service
 type AgentWeights struct {
 	Passing int
@@ -87,6 +107,7 @@ type AgentService struct {
 	Tags              []string
 	Meta              map[string]string
 	Port              int
+	Ports             []ServicePort `json:",omitempty"`
 	Address           string
 	SocketPath        string                    `json:",omitempty"`
 	TaggedAddresses   map[string]ServiceAddress `json:",omitempty"`
@@ -128,6 +149,7 @@ type AgentServiceConnectProxyConfig struct {
 	DestinationServiceID   string                  `json:",omitempty"`
 	LocalServiceAddress    string                  `json:",omitempty"`
 	LocalServicePort       int                     `json:",omitempty"`
+	LocalServicePorts      []ServicePort           `json:",omitempty"`
 	LocalServiceSocketPath string                  `json:",omitempty"`
 	Mode                   ProxyMode               `json:",omitempty"`
 	TransparentProxy       *TransparentProxyConfig `json:",omitempty"`
@@ -279,6 +301,7 @@ type AgentServiceRegistration struct {
 	Name              string                    `json:",omitempty"`
 	Tags              []string                  `json:",omitempty"`
 	Port              int                       `json:",omitempty"`
+	Ports             []ServicePort             `json:",omitempty"`
 	Address           string                    `json:",omitempty"`
 	SocketPath        string                    `json:",omitempty"`
 	TaggedAddresses   map[string]ServiceAddress `json:",omitempty"`
@@ -300,6 +323,11 @@ type ServiceRegisterOpts struct {
 	// having to manually deregister checks.
 	ReplaceExistingChecks bool
 
+	// LeaseTTL registers the service with a lease of this TTL. The service is
+	// deregistered unless its lease is renewed before the TTL elapses, with
+	// the KeepAlive RPC of the ServiceLeaseService of the agent's gRPC API.
+	LeaseTTL time.Duration
+
 	// ctx is an optional context pass through to the underlying HTTP
 	// request layer. Use WithContext() to set the context.
 	ctx context.Context
@@ -327,6 +355,7 @@ type AgentCheckRegistration struct {
 type AgentServiceCheck struct {
 	CheckID                string              `json:",omitempty"`
 	Name                   string              `json:",omitempty"`
+	PortName               string              `json:",omitempty"`
 	Args                   []string            `json:"ScriptArgs,omitempty"`
 	DockerContainerID      string              `json:",omitempty"`
 	Shell                  string              `json:",omitempty"` // Only supported for Docker.
@@ -345,13 +374,22 @@ type AgentServiceCheck struct {
 	TLSSkipVerify          bool                `json:",omitempty"`
 	GRPC                   string              `json:",omitempty"`
 	GRPCUseTLS             bool                `json:",omitempty"`
+	GRPCService            string              `json:",omitempty"`
+	GRPCAuthority          string              `json:",omitempty"`
 	H2PING                 string              `json:",omitempty"`
 	H2PingUseTLS           bool                `json:",omitempty"`
 	AliasNode              string              `json:",omitempty"`
 	AliasService           string              `json:",omitempty"`
+	CompositeMode          string              `json:",omitempty"`
+	CompositeChecks        []string            `json:",omitempty"`
+	CompositeMinPassing    int                 `json:",omitempty"`
+	ProcessPID             int                 `json:",omitempty"`
+	ProcessPIDFile         string              `json:",omitempty"`
+	ProcessCgroup          string              `json:",omitempty"`
 	SuccessBeforePassing   int                 `json:",omitempty"`
 	FailuresBeforeWarning  int                 `json:",omitempty"`
 	FailuresBeforeCritical int                 `json:",omitempty"`
+	OutputMaxSize          int                 `json:",omitempty"`
 
 	// In Consul 0.7 and later, checks that are associated with a service
 	// may also contain this optional DeregisterCriticalServiceAfter field,
@@ -368,6 +406,78 @@ type AgentToken struct {
 	Token string
 }
 
+// AgentProxyDebug is the state of a proxy tracked by the agent and the xDS
+// configuration generated for it.
+type AgentProxyDebug struct {
+	ProxyID  AgentProxyDebugID
+	NodeName string
+	Kind     ServiceKind
+	Service  string
+	Source   string
+
+	// Watchers is the number of watches on the proxy's configuration,
+	// typically one per connected xDS stream.
+	Watchers int
+
+	// Failed is whether the agent stopped tracking the proxy's configuration
+	// because of an irrecoverable error, such as its ACL token being deleted.
+	Failed bool
+
+	// Ready is whether all the data required to configure the proxy has been
+	// fetched. Summary and XDS are only set once it is.
+	Ready   bool
+	Summary *AgentProxyDebugSummary `json:",omitempty"`
+	XDS     *AgentProxyXDS          `json:"xDS,omitempty"`
+
+	// Error is the error generating the xDS configuration of the proxy.
+	Error string `json:",omitempty"`
+}
+
+// AgentProxyDebugID identifies the proxy service of an AgentProxyDebug.
+type AgentProxyDebugID struct {
+	ID        string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+}
+
+// AgentProxyDebugSummary summarizes the data the configuration of a proxy is
+// generated from.
+type AgentProxyDebugSummary struct {
+	Datacenter string
+	Address    string
+	Port       int
+	Mode       ProxyMode `json:",omitempty"`
+
+	ActiveRootID     string `json:",omitempty"`
+	LeafSerialNumber string `json:",omitempty"`
+	LeafValidBefore  time.Time
+
+	Upstreams []string `json:",omitempty"`
+}
+
+// AgentProxyXDS is the xDS configuration generated for a proxy.
+type AgentProxyXDS struct {
+	// Resources are the xDS resources by type URL, in the JSON encoding of
+	// the Envoy API. The private keys are redacted.
+	Resources map[string][]json.RawMessage
+
+	// Extensions are the results of applying the Envoy extensions configured
+	// for the proxy.
+	Extensions []AgentProxyExtensionResult
+}
+
+// AgentProxyExtensionResult is the result of applying an Envoy extension to
+// the configuration of a proxy.
+type AgentProxyExtensionResult struct {
+	Name      string
+	Service   string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+	Required  bool
+	Applied   bool
+	Error     string `json:",omitempty"`
+}
+
 // Metrics info is used to store different types of metric values from the agent.
 type MetricsInfo struct {
 	Timestamp string
@@ -435,6 +545,7 @@ type Upstream struct {
 	DestinationNamespace string           `json:",omitempty"`
 	DestinationPeer      string           `json:",omitempty"`
 	DestinationName      string
+	DestinationPort      string                 `json:",omitempty"`
 	Datacenter           string                 `json:",omitempty"`
 	LocalBindAddress     string                 `json:",omitempty"`
 	LocalBindPort        int                    `json:",omitempty"`
@@ -498,6 +609,26 @@ func (a *Agent) Host() (map[string]interface{}, error) {
 	return out, nil
 }
 
+// ProxyDebug is used to retrieve the state of the proxies tracked by the
+// agent and the xDS configuration generated for them. This requires an
+// operator:read token.
+func (a *Agent) ProxyDebug() ([]*AgentProxyDebug, error) {
+	r := a.c.newRequest("GET", "/v1/agent/proxy/debug")
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+	var out []*AgentProxyDebug
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Metrics is used to query the agent we are speaking to for
 // its current internal metric data
 func (a *Agent) Metrics() (*MetricsInfo, error) {
@@ -626,6 +757,26 @@ func (a *Agent) ServicesWithFilterOpts(filter string, q *QueryOptions) (map[stri
 	return out, nil
 }
 
+// ServiceDefinitions returns the state of the files of service definitions
+// watched by the agent with services_auto_reload.
+func (a *Agent) ServiceDefinitions(q *QueryOptions) ([]*AgentServiceDefinitionsFile, error) {
+	r := a.c.newRequest("GET", "/v1/agent/service-definitions")
+	r.setQueryOptions(q)
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+	var out []*AgentServiceDefinitionsFile
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentHealthServiceByID returns for a given serviceID: the aggregated health status, the service definition or an error if any
 // - If the service is not found, will return status (critical, nil, nil)
 // - If the service is found, will return (critical|passing|warning), AgentServiceChecksInfo, nil)
@@ -805,6 +956,9 @@ func (a *Agent) serviceRegister(service *AgentServiceRegistration, opts ServiceR
 	if opts.ReplaceExistingChecks {
 		r.params.Set("replace-existing-checks", "true")
 	}
+	if opts.LeaseTTL > 0 {
+		r.params.Set("lease", opts.LeaseTTL.String())
+	}
 	_, resp, err := a.c.doRequest(r)
 	if err != nil {
 		return err
@@ -1179,6 +1333,53 @@ func (a *Agent) DisableServiceMaintenanceOpts(serviceID string, q *QueryOptions)
 	return nil
 }
 
+// EnableServiceDraining marks the given service ID as draining. The service
+// stays registered and passing, but stops being sent new connections while
+// existing ones finish. If timeout is not zero, draining is lifted once it
+// expires.
+func (a *Agent) EnableServiceDraining(serviceID, reason string, timeout time.Duration) error {
+	return a.EnableServiceDrainingOpts(serviceID, reason, timeout, nil)
+}
+
+func (a *Agent) EnableServiceDrainingOpts(serviceID, reason string, timeout time.Duration, q *QueryOptions) error {
+	r := a.c.newRequest("PUT", "/v1/agent/service/draining/"+serviceID)
+	r.setQueryOptions(q)
+	r.params.Set("enable", "true")
+	r.params.Set("reason", reason)
+	if timeout != 0 {
+		r.params.Set("timeout", timeout.String())
+	}
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DisableServiceDraining lifts the draining state of the given service ID.
+func (a *Agent) DisableServiceDraining(serviceID string) error {
+	return a.DisableServiceDrainingOpts(serviceID, nil)
+}
+
+func (a *Agent) DisableServiceDrainingOpts(serviceID string, q *QueryOptions) error {
+	r := a.c.newRequest("PUT", "/v1/agent/service/draining/"+serviceID)
+	r.setQueryOptions(q)
+	r.params.Set("enable", "false")
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return err
+	}
+	return nil
+}
+
 // EnableNodeMaintenance toggles node maintenance mode on for the
 // agent we are connected to.
 func (a *Agent) EnableNodeMaintenance(reason string) error {
@@ -842,7 +842,7 @@ func TestAPI_AgentService(t *testing.T) {
 		ID:          "foo",
 		Service:     "foo",
 		Tags:        []string{"bar", "baz"},
-		ContentHash: "3e352f348d44f7eb",
+		ContentHash: "c4bb6737c185ed93",
 		Port:        8000,
 		Weights: AgentWeights{
 			Passing: 1,
@@ -1485,6 +1485,47 @@ func TestAPI_ServiceMaintenanceOpts(t *testing.T) {
 	}
 }
 
+func TestAPI_ServiceDraining(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	agent := c.Agent()
+
+	// First register a service
+	serviceReg := &AgentServiceRegistration{
+		Name: "redis",
+	}
+	require.NoError(t, agent.ServiceRegister(serviceReg))
+
+	// Start draining the service
+	require.NoError(t, agent.EnableServiceDraining("redis", "deploy", 0))
+
+	// Ensure a passing draining check was added
+	checks, err := agent.Checks()
+	require.NoError(t, err)
+	check, ok := checks[ServiceDrainingPrefix+"redis"]
+	require.True(t, ok, "missing draining check: %#v", checks)
+	require.Equal(t, HealthPassing, check.Status)
+	require.Equal(t, "deploy", check.Notes)
+	require.Equal(t, "draining", check.Type)
+
+	// Stop draining the service
+	require.NoError(t, agent.DisableServiceDraining("redis"))
+
+	checks, err = agent.Checks()
+	require.NoError(t, err)
+	require.NotContains(t, checks, ServiceDrainingPrefix+"redis")
+
+	// Draining with a timeout is lifted once it expires
+	require.NoError(t, agent.EnableServiceDraining("redis", "", 100*time.Millisecond))
+	retry.Run(t, func(r *retry.R) {
+		checks, err := agent.Checks()
+		require.NoError(r, err)
+		require.NotContains(r, checks, ServiceDrainingPrefix+"redis")
+	})
+}
+
 func TestAPI_NodeMaintenance(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
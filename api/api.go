@@ -210,6 +210,17 @@ type QueryOptions struct {
 	// Global is used to request information from all datacenters. Currently only
 	// used for operator usage requests.
 	Global bool
+
+	// Limit is used to limit the number of results returned by endpoints that
+	// support pagination. When the results are truncated, QueryMeta.NextCursor
+	// is set and can be passed as Cursor to fetch the next page.
+	Limit int
+
+	// Cursor is used to continue a paginated query from the point given by a
+	// previous response's QueryMeta.NextCursor. The cursor points after the
+	// last result of the previous page, so the results changed in between are
+	// reflected by the following pages without the query being restarted.
+	Cursor string
 }
 
 func (o *QueryOptions) Context() context.Context {
@@ -315,6 +326,10 @@ type QueryMeta struct {
 	// filtered out by enforcing ACLs. It may be false because nothing was
 	// removed, or because the endpoint does not yet support this flag.
 	ResultsFilteredByACLs bool
+
+	// NextCursor is set when a paginated query has more results. It can be
+	// passed as QueryOptions.Cursor to fetch the next page.
+	NextCursor string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -355,6 +370,13 @@ type Config struct {
 	// used if not provided.
 	HttpClient *http.Client
 
+	// DialContext is used to open the connections to Consul, for example to
+	// go through a proxy or from another network namespace. It is used for
+	// unix socket addresses too, with the "unix" network and the path of the
+	// socket. It is ignored when HttpClient is provided, unless Address is a
+	// unix socket.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
 	// HttpAuth is the auth info to use for http access.
 	HttpAuth *HttpBasicAuth
 
@@ -379,6 +401,10 @@ type Config struct {
 	Partition string
 
 	TLSConfig TLSConfig
+
+	// RetryPolicy configures the retries of the failed requests. Requests
+	// are not retried when it is nil.
+	RetryPolicy *RetryPolicy
 }
 
 // TLSConfig is used to generate a TLSClientConfig that's useful for talking to
@@ -701,8 +727,15 @@ func NewClient(config *Config) (*Client, error) {
 	}
 
 	if config.HttpClient == nil {
+		transport := config.Transport
+		if config.DialContext != nil {
+			// The transport may be shared with other clients, so the dialer
+			// is only set on a copy of it.
+			transport = transport.Clone()
+			transport.DialContext = config.DialContext
+		}
 		var err error
-		config.HttpClient, err = NewHttpClient(config.Transport, config.TLSConfig)
+		config.HttpClient, err = NewHttpClient(transport, config.TLSConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -724,11 +757,23 @@ func NewClient(config *Config) (*Client, error) {
 		case "https":
 			config.Scheme = "https"
 		case "unix":
+			dial := config.DialContext
+			if dial == nil {
+				dial = (&net.Dialer{}).DialContext
+			}
+			socket := parts[1]
 			trans := cleanhttp.DefaultTransport()
-			trans.DialContext = func(_ context.Context, _, _ string) (net.Conn, error) {
-				return net.Dial("unix", parts[1])
+			trans.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dial(ctx, "unix", socket)
 			}
-			httpClient, err := NewHttpClient(trans, config.TLSConfig)
+			// The path of the socket stands for the host of the requests, so
+			// it can't be used to verify the certificate of the agent when
+			// the Scheme is https.
+			tlsConf := config.TLSConfig
+			if tlsConf.Address == "" {
+				tlsConf.Address = "localhost"
+			}
+			httpClient, err := NewHttpClient(trans, tlsConf)
 			if err != nil {
 				return nil, err
 			}
@@ -902,6 +947,12 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Global {
 		r.params.Set("global", "")
 	}
+	if q.Limit > 0 {
+		r.params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Cursor != "" {
+		r.params.Set("cursor", q.Cursor)
+	}
 
 	r.ctx = q.ctx
 }
@@ -1045,9 +1096,27 @@ func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
 		return 0, nil, err
 	}
 	start := time.Now()
-	resp, err := c.config.HttpClient.Do(req)
-	diff := time.Since(start)
-	return diff, resp, err
+	for attempt := 0; ; attempt++ {
+		attemptStart := time.Now()
+		resp, err := c.config.HttpClient.Do(req)
+		diff := time.Since(attemptStart)
+
+		wait, retry := c.config.RetryPolicy.retryWait(req, attempt, start, resp, err)
+		if !retry {
+			return diff, resp, err
+		}
+		if resp != nil {
+			closeResponseBody(resp)
+		}
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return diff, nil, err
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return diff, nil, err
+			}
+		}
+	}
 }
 
 // Query is used to do a GET request against an endpoint
@@ -1170,6 +1239,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	case QueryBackendStreaming, QueryBackendBlockingQuery:
 		q.QueryBackend = v
 	}
+
+	// Parse X-Consul-Next-Cursor
+	q.NextCursor = header.Get("X-Consul-Next-Cursor")
 	return nil
 }
 
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	crand "crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -1036,6 +1040,91 @@ func TestAPI_UnixSocket(t *testing.T) {
 	}
 }
 
+func TestAPI_UnixSocketTLS(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+
+	tempDir := testutil.TempDir(t, "consul")
+	socket := filepath.Join(tempDir, "test.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`"127.0.0.1:8300"`))
+	}))
+	srv.Listener = l
+	srv.StartTLS()
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	var dialed []string
+	c, err := NewClient(&Config{
+		Address: "unix://" + socket,
+		Scheme:  "https",
+		TLSConfig: TLSConfig{
+			Address: "example.com",
+			CAPem:   caPEM,
+		},
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, network+":"+addr)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	})
+	require.NoError(t, err)
+
+	leader, err := c.Status().Leader()
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:8300", leader)
+	require.Equal(t, []string{"unix:" + socket}, dialed)
+
+	// Without a server name, the certificate is verified for localhost.
+	c, err = NewClient(&Config{Address: "unix://" + socket, Scheme: "https"})
+	require.NoError(t, err)
+	transport := c.config.HttpClient.Transport.(*http.Transport)
+	require.Equal(t, "localhost", transport.TLSClientConfig.ServerName)
+}
+
+func TestAPI_DialContext(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`"127.0.0.1:8300"`))
+	}))
+	defer srv.Close()
+
+	// The client dials the test server whatever the address it is given.
+	var dialed []string
+	transport := cleanhttp.DefaultPooledTransport()
+	c, err := NewClient(&Config{
+		Address:   "consul.example.com:8500",
+		Transport: transport,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = c.Status().Leader()
+	require.NoError(t, err)
+	require.Equal(t, []string{"consul.example.com:8500"}, dialed)
+
+	// The transport given to the client may be shared with other clients, so
+	// it isn't changed.
+	other, err := NewClient(&Config{
+		Address:   srv.Listener.Addr().String(),
+		Transport: transport,
+	})
+	require.NoError(t, err)
+
+	_, err = other.Status().Leader()
+	require.NoError(t, err)
+	require.Equal(t, []string{"consul.example.com:8500"}, dialed)
+}
+
 func TestAPI_durToMsec(t *testing.T) {
 	t.Parallel()
 	if ms := durToMsec(0); ms != "0ms" {
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultBlockingQueryMinBackoff is the wait before retrying the first
+	// failed query of a BlockingQuery.
+	defaultBlockingQueryMinBackoff = time.Second
+
+	// defaultBlockingQueryMaxBackoff is the longest wait between two failed
+	// queries of a BlockingQuery.
+	defaultBlockingQueryMaxBackoff = time.Minute
+)
+
+// BlockingQueryFunc runs a single query with the given options, for example:
+//
+//	func(q *QueryOptions) ([]*ServiceEntry, *QueryMeta, error) {
+//		return client.Health().Service("web", "", true, q)
+//	}
+type BlockingQueryFunc[T any] func(q *QueryOptions) (T, *QueryMeta, error)
+
+// BlockingQueryResult is a result of a BlockingQuery. Either Err is set, or
+// Value and Meta are.
+type BlockingQueryResult[T any] struct {
+	Value T
+	Meta  *QueryMeta
+	Err   error
+}
+
+// BlockingQueryOptions configures a BlockingQuery.
+type BlockingQueryOptions struct {
+	// QueryOptions are the options of every query. The WaitIndex is only used
+	// by the first query, and then managed by BlockingQuery.
+	QueryOptions *QueryOptions
+
+	// MaxStale enables the fallback to stale queries when a query fails, for
+	// example because the cluster lost its leader. The result of a stale
+	// query is only delivered when the server answering it heard from the
+	// leader less than MaxStale ago. Zero disables the fallback.
+	MaxStale time.Duration
+
+	// MinBackoff and MaxBackoff bound the jittered exponential backoff
+	// between failed queries. They default to 1s and 1m.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// BlockingQuery runs query in a blocking query loop until ctx is cancelled,
+// and delivers its results on the returned channel, which is closed once the
+// loop stops.
+//
+// The first result is delivered as soon as it is received, and the following
+// ones only when the index of the results changes. The loop handles the
+// indexes going backwards, for example after a snapshot restore, by starting
+// over. Errors are delivered too and the query is retried with a backoff, so
+// the consumer decides which errors are fatal and cancels ctx accordingly.
+func BlockingQuery[T any](ctx context.Context, opts *BlockingQueryOptions, query BlockingQueryFunc[T]) <-chan BlockingQueryResult[T] {
+	if opts == nil {
+		opts = &BlockingQueryOptions{}
+	}
+	ch := make(chan BlockingQueryResult[T])
+	go runBlockingQuery(ctx, opts, query, ch)
+	return ch
+}
+
+func runBlockingQuery[T any](ctx context.Context, opts *BlockingQueryOptions, query BlockingQueryFunc[T], ch chan<- BlockingQueryResult[T]) {
+	defer close(ch)
+
+	var base QueryOptions
+	if opts.QueryOptions != nil {
+		base = *opts.QueryOptions
+	}
+	backoff := &RetryPolicy{
+		MinBackoff: opts.MinBackoff,
+		MaxBackoff: opts.MaxBackoff,
+	}
+	if backoff.MinBackoff <= 0 {
+		backoff.MinBackoff = defaultBlockingQueryMinBackoff
+	}
+	if backoff.MaxBackoff <= 0 {
+		backoff.MaxBackoff = defaultBlockingQueryMaxBackoff
+	}
+
+	send := func(result BlockingQueryResult[T]) bool {
+		select {
+		case ch <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	index := base.WaitIndex
+	var lastIndex uint64
+	delivered := false
+	stale := false
+	failures := 0
+	for {
+		q := base
+		q.WaitIndex = index
+		if stale {
+			q.AllowStale = true
+			q.RequireConsistent = false
+		}
+
+		value, meta, err := query(q.WithContext(ctx))
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil && meta == nil {
+			err = fmt.Errorf("blocking query returned no QueryMeta")
+		}
+		if err == nil && stale && meta.LastContact > opts.MaxStale {
+			err = fmt.Errorf("stale result is too old: last contact with the leader %s ago, max stale is %s",
+				meta.LastContact, opts.MaxStale)
+		}
+		if err != nil {
+			if !send(BlockingQueryResult[T]{Err: err}) {
+				return
+			}
+			// Retry the failed query as a stale one, unless it was stale
+			// already.
+			stale = opts.MaxStale > 0 && !base.AllowStale && !stale
+			if err := sleepContext(ctx, backoff.backoff(failures)); err != nil {
+				return
+			}
+			failures++
+			continue
+		}
+		// A successful stale query doesn't mean the cluster recovered, keep
+		// backing off until a regular query succeeds.
+		if !stale {
+			failures = 0
+		}
+		stale = false
+
+		switch {
+		case meta.LastIndex == 0:
+			// Never block on index zero, which would return immediately.
+			index = 1
+		case meta.LastIndex < index:
+			// The index went backwards, start over with a non-blocking
+			// query.
+			index = 0
+		default:
+			index = meta.LastIndex
+		}
+		if delivered && meta.LastIndex == lastIndex {
+			continue
+		}
+
+		if !send(BlockingQueryResult[T]{Value: value, Meta: meta}) {
+			return
+		}
+		lastIndex = meta.LastIndex
+		delivered = true
+	}
+}
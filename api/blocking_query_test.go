@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blockingQueryStep struct {
+	index       uint64
+	lastContact time.Duration
+	err         error
+}
+
+func TestAPI_BlockingQuery(t *testing.T) {
+	t.Parallel()
+
+	errNoLeader := errors.New("No cluster leader")
+	steps := []blockingQueryStep{
+		{index: 5},
+		{index: 5}, // wait time elapsed without a change
+		{index: 8},
+		{err: errNoLeader},
+		{index: 8, lastContact: time.Minute}, // stale, too old
+		{err: errNoLeader},
+		{index: 9, lastContact: time.Second}, // stale
+		{index: 3},                           // snapshot restored
+		{index: 3},
+		{index: 0},
+	}
+
+	type call struct {
+		waitIndex uint64
+		stale     bool
+	}
+	var calls []call
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	query := func(q *QueryOptions) (uint64, *QueryMeta, error) {
+		calls = append(calls, call{waitIndex: q.WaitIndex, stale: q.AllowStale})
+		require.NotNil(t, q.Context())
+		if len(calls) > len(steps) {
+			<-q.Context().Done()
+			return 0, nil, q.Context().Err()
+		}
+		step := steps[len(calls)-1]
+		if step.err != nil {
+			return 0, nil, step.err
+		}
+		return step.index, &QueryMeta{LastIndex: step.index, LastContact: step.lastContact}, nil
+	}
+
+	opts := &BlockingQueryOptions{
+		QueryOptions: &QueryOptions{WaitIndex: 2, WaitTime: time.Minute},
+		MaxStale:     10 * time.Second,
+		MinBackoff:   time.Millisecond,
+		MaxBackoff:   time.Millisecond,
+	}
+	var values []uint64
+	var errs int
+	for result := range BlockingQuery(ctx, opts, query) {
+		if result.Err != nil {
+			errs++
+			continue
+		}
+		values = append(values, result.Value)
+		if len(values) == 5 {
+			cancel()
+		}
+	}
+
+	require.Equal(t, []uint64{5, 8, 9, 3, 0}, values)
+	require.Equal(t, 3, errs)
+	require.Equal(t, []call{
+		{waitIndex: 2},
+		{waitIndex: 5},
+		{waitIndex: 5},
+		{waitIndex: 8},
+		{waitIndex: 8, stale: true},
+		{waitIndex: 8},
+		{waitIndex: 8, stale: true},
+		{waitIndex: 9},
+		{waitIndex: 0},
+		{waitIndex: 3},
+		{waitIndex: 1},
+	}, calls)
+}
+
+func TestAPI_BlockingQuery_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := BlockingQuery(ctx, nil, func(q *QueryOptions) (string, *QueryMeta, error) {
+		return "value", &QueryMeta{LastIndex: 1}, nil
+	})
+
+	result := <-ch
+	require.NoError(t, result.Err)
+	require.Equal(t, "value", result.Value)
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net"
 	"strconv"
 )
@@ -28,6 +29,13 @@ type ServiceAddress struct {
 	Port    int
 }
 
+// ServicePort is a named port of a service instance, exposed in addition to
+// its main port.
+type ServicePort struct {
+	Name string
+	Port int
+}
+
 type CatalogService struct {
 	ID                       string
 	Node                     string
@@ -42,6 +50,7 @@ type CatalogService struct {
 	ServiceTags              []string
 	ServiceMeta              map[string]string
 	ServicePort              int
+	ServicePorts             []ServicePort `json:",omitempty"`
 	ServiceWeights           Weights
 	ServiceEnableTagOverride bool
 	ServiceProxy             *AgentServiceConnectProxyConfig
@@ -84,6 +93,60 @@ type CatalogDeregistration struct {
 	CheckID    string
 	Namespace  string `json:",omitempty"`
 	Partition  string `json:",omitempty"`
+
+	// Reason is recorded in the tombstones of the service instances that are
+	// removed. If empty, it is derived from what is being deregistered.
+	Reason string `json:",omitempty"`
+}
+
+// CatalogServiceTombstone records a service instance that was recently
+// removed from the catalog.
+type CatalogServiceTombstone struct {
+	Node        string
+	ServiceID   string
+	ServiceName string
+	ServiceKind ServiceKind `json:",omitempty"`
+	PeerName    string      `json:",omitempty"`
+	Namespace   string      `json:",omitempty"`
+	Partition   string      `json:",omitempty"`
+
+	// Reason describes why the service instance was removed.
+	Reason string
+
+	// CreateIndex and ModifyIndex are the index at which the service
+	// instance was removed.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+const (
+	// CatalogChangeRegister is used when a service instance is registered or
+	// updated, including changes to its node and to its health checks.
+	CatalogChangeRegister = "register"
+
+	// CatalogChangeDeregister is used when a service instance is deregistered.
+	CatalogChangeDeregister = "deregister"
+
+	// CatalogChangeReset is used when the stream could not be resumed from
+	// the requested cursor. It is followed by a snapshot of every service
+	// instance, which replaces any state built from earlier events.
+	CatalogChangeReset = "reset"
+
+	// CatalogChangeSnapshotDone is used once all the register events of the
+	// snapshot sent at the start of a stream have been sent.
+	CatalogChangeSnapshotDone = "snapshot-done"
+)
+
+// CatalogChangeEvent is an event of the stream of changes to the catalog.
+type CatalogChangeEvent struct {
+	// Index is the raft index of the change, which can be used as the cursor
+	// to resume the stream. Events with the same index are sent together.
+	Index uint64
+	Op    string
+
+	// Entry is the service instance that changed, along with its node and
+	// health checks. It is only set for register and deregister events.
+	Entry *ServiceEntry `json:",omitempty"`
 }
 
 type CompoundServiceName struct {
@@ -159,6 +222,34 @@ func (c *Catalog) Deregister(dereg *CatalogDeregistration, q *WriteOptions) (*Wr
 	return wm, nil
 }
 
+// CatalogBatch is a set of registrations and deregistrations that are
+// applied atomically. Registrations are applied before deregistrations.
+type CatalogBatch struct {
+	Register   []*CatalogRegistration   `json:",omitempty"`
+	Deregister []*CatalogDeregistration `json:",omitempty"`
+}
+
+// Batch is used to register and deregister many nodes, services and checks
+// atomically. Either all of them are applied or none are.
+func (c *Catalog) Batch(batch *CatalogBatch, q *WriteOptions) (*WriteMeta, error) {
+	r := c.c.newRequest("PUT", "/v1/catalog/batch")
+	r.setWriteOptions(q)
+	r.obj = batch
+	rtt, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	return wm, nil
+}
+
 // Datacenters is used to query for all the known datacenters
 func (c *Catalog) Datacenters() ([]string, error) {
 	r := c.c.newRequest("GET", "/v1/catalog/datacenters")
@@ -286,6 +377,35 @@ func (c *Catalog) service(service string, tags []string, q *QueryOptions, connec
 	return out, qm, nil
 }
 
+// ServiceTombstones is used to query the service instances that were recently
+// removed from the catalog. If service is empty, the tombstones of all
+// services are returned.
+func (c *Catalog) ServiceTombstones(service string, q *QueryOptions) ([]*CatalogServiceTombstone, *QueryMeta, error) {
+	r := c.c.newRequest("GET", "/v1/catalog/service-tombstones")
+	r.setQueryOptions(q)
+	if service != "" {
+		r.params.Set("service", service)
+	}
+	rtt, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []*CatalogServiceTombstone
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
 // Node is used to query for service information about a single node
 func (c *Catalog) Node(node string, q *QueryOptions) (*CatalogNode, *QueryMeta, error) {
 	r := c.c.newRequest("GET", "/v1/catalog/node/"+node)
@@ -369,3 +489,60 @@ func ParseServiceAddr(addrPort string) (ServiceAddress, error) {
 	}
 	return ServiceAddress{Address: host, Port: port}, err
 }
+
+// Changes streams the changes to the service instances of the catalog. The
+// stream starts after the events with the given cursor index, or with a
+// snapshot of the catalog if the cursor is 0 or can no longer be resumed. If
+// service is not empty, only the changes to its instances are streamed.
+//
+// The returned channel is closed when stopCh is closed or when the stream
+// ends, in which case a new stream can be started using the index of the last
+// event received as the cursor.
+func (c *Catalog) Changes(service string, cursor uint64, stopCh <-chan struct{}, q *QueryOptions) (<-chan *CatalogChangeEvent, error) {
+	r := c.c.newRequest("GET", "/v1/catalog/changes")
+	r.setQueryOptions(q)
+	if service != "" {
+		r.params.Set("service", service)
+	}
+	if cursor != 0 {
+		r.params.Set("cursor", strconv.FormatUint(cursor, 10))
+	}
+	_, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	eventCh := make(chan *CatalogChangeEvent, 64)
+	doneCh := make(chan struct{})
+
+	// Closing the body unblocks the decoder when the caller stops the stream.
+	go func() {
+		select {
+		case <-stopCh:
+		case <-doneCh:
+		}
+		closeResponseBody(resp)
+	}()
+
+	go func() {
+		defer close(doneCh)
+		defer close(eventCh)
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var event CatalogChangeEvent
+			if err := dec.Decode(&event); err != nil {
+				return
+			}
+			select {
+			case eventCh <- &event:
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+	return eventCh, nil
+}
@@ -1002,6 +1002,63 @@ func TestAPI_CatalogRegistration(t *testing.T) {
 	})
 }
 
+func TestAPI_CatalogChanges(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+	catalog := c.Catalog()
+
+	reg := &CatalogRegistration{
+		Datacenter: "dc1",
+		Node:       "foobar",
+		Address:    "192.168.10.10",
+		Service: &AgentService{
+			ID:      "redis1",
+			Service: "redis",
+			Port:    8000,
+		},
+	}
+	_, err := catalog.Register(reg, nil)
+	require.NoError(t, err)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	eventCh, err := catalog.Changes("redis", 0, stopCh, nil)
+	require.NoError(t, err)
+
+	next := func() *CatalogChangeEvent {
+		select {
+		case event, ok := <-eventCh:
+			require.True(t, ok, "stream closed")
+			return event
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+		return nil
+	}
+
+	// The stream starts with a snapshot of the service instances.
+	event := next()
+	require.Equal(t, CatalogChangeRegister, event.Op)
+	require.Equal(t, "foobar", event.Entry.Node.Node)
+	require.Equal(t, "redis1", event.Entry.Service.ID)
+	require.Equal(t, CatalogChangeSnapshotDone, next().Op)
+
+	_, err = catalog.Deregister(&CatalogDeregistration{
+		Datacenter: "dc1",
+		Node:       "foobar",
+		ServiceID:  "redis1",
+	}, nil)
+	require.NoError(t, err)
+
+	event = next()
+	require.Equal(t, CatalogChangeDeregister, event.Op)
+	require.Equal(t, "redis1", event.Entry.Service.ID)
+	require.Greater(t, event.Index, uint64(0))
+}
+
 func TestAPI_CatalogEnableTagOverride(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
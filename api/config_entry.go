@@ -30,12 +30,27 @@ const (
 	TCPRoute          string = "tcp-route"
 	InlineCertificate string = "inline-certificate"
 	HTTPRoute         string = "http-route"
+	APIGatewayPolicy  string = "api-gateway-policy"
+	SamenessGroup     string = "sameness-group"
 )
 
 const (
-	BuiltinAWSLambdaExtension      string = "builtin/aws/lambda"
-	BuiltinLuaExtension            string = "builtin/lua"
-	BuiltinLocalRatelimitExtension string = "builtin/http/localratelimit"
+	BuiltinAWSLambdaExtension          string = "builtin/aws/lambda"
+	BuiltinLuaExtension                string = "builtin/lua"
+	BuiltinLocalRatelimitExtension     string = "builtin/http/localratelimit"
+	BuiltinRequestIDExtension          string = "builtin/http/request-id"
+	BuiltinPreserveCaseExtension       string = "builtin/http/preserve-case"
+	BuiltinStrictHeadersExtension      string = "builtin/http/strict-headers"
+	BuiltinUpgradeExtension            string = "builtin/http/upgrade"
+	BuiltinGRPCWebExtension            string = "builtin/http/grpc-web"
+	BuiltinGRPCJSONTranscoderExtension string = "builtin/http/grpc-json-transcoder"
+	BuiltinRouteTimeoutExtension       string = "builtin/http/route-timeout"
+	BuiltinConnectionLifetimeExtension string = "builtin/connection-lifetime"
+	BuiltinMetadataExchangeExtension   string = "builtin/http/metadata-exchange"
+	BuiltinDNSResolverExtension        string = "builtin/dns-resolver"
+	BuiltinActiveHealthCheckExtension  string = "builtin/active-health-check"
+	BuiltinDualStackExtension          string = "builtin/dual-stack"
+	BuiltinLoadReportingExtension      string = "builtin/load-reporting"
 )
 
 type ConfigEntry interface {
@@ -231,15 +246,74 @@ type UpstreamConfig struct {
 	// BalanceOutboundConnections indicates that the proxy should attempt to evenly distribute
 	// outbound connections across worker threads. Only used by envoy proxies.
 	BalanceOutboundConnections string `json:",omitempty" alias:"balance_outbound_connections"`
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams the
+	// proxy opens on each HTTP/2 connection to the upstream. Only used for the
+	// http2 and grpc protocols.
+	MaxConcurrentStreams int `json:",omitempty" alias:"max_concurrent_streams"`
+
+	// MaxRequestsPerConnection is the maximum number of requests the proxy
+	// sends on each connection to the upstream before closing it. Only used
+	// for HTTP-like protocols.
+	MaxRequestsPerConnection int `json:",omitempty" alias:"max_requests_per_connection"`
 }
 
 // DestinationConfig represents a virtual service, i.e. one that is external to Consul
 type DestinationConfig struct {
-	// Addresses of the endpoint; hostname or IP
+	// Addresses of the endpoint; hostname or IP. Hostnames may use a wildcard
+	// as their left-most label (e.g. "*.example.com") when the service
+	// protocol is HTTP-like.
 	Addresses []string `json:",omitempty"`
 
 	// Port allowed within this endpoint
 	Port int `json:",omitempty"`
+
+	// HealthCheck configures active health checking of the hostname
+	// addresses by terminating gateways.
+	HealthCheck *DestinationHealthCheck `json:",omitempty" alias:"health_check"`
+
+	// DNS configures how terminating gateways re-resolve the hostname
+	// addresses.
+	DNS *DestinationDNSConfig `json:",omitempty"`
+}
+
+// DestinationHealthCheck is an active health check performed by terminating
+// gateways against hostname Destinations.
+type DestinationHealthCheck struct {
+	// Protocol used for the check, one of "tcp" or "http". Defaults to "tcp".
+	Protocol string `json:",omitempty"`
+
+	// Path requested by "http" checks. Defaults to "/".
+	Path string `json:",omitempty"`
+
+	// Interval between checks. Defaults to 10s.
+	Interval time.Duration `json:",omitempty"`
+
+	// Timeout for a single check. Defaults to 5s.
+	Timeout time.Duration `json:",omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed checks before an
+	// endpoint is ejected. Defaults to 2.
+	UnhealthyThreshold uint32 `json:",omitempty" alias:"unhealthy_threshold"`
+
+	// HealthyThreshold is the number of consecutive successful checks before
+	// an ejected endpoint is used again. Defaults to 2.
+	HealthyThreshold uint32 `json:",omitempty" alias:"healthy_threshold"`
+}
+
+// DestinationDNSConfig configures the re-resolution of hostname Destinations
+// by terminating gateways.
+type DestinationDNSConfig struct {
+	// RefreshRate is the interval at which hostnames are re-resolved.
+	// Defaults to 10s.
+	RefreshRate time.Duration `json:",omitempty" alias:"refresh_rate"`
+
+	// RespectTTL uses the TTL of DNS records as the refresh rate instead of
+	// RefreshRate.
+	RespectTTL bool `json:",omitempty" alias:"respect_ttl"`
+
+	// DiscoveryType is one of "logical" or "strict". Defaults to "logical".
+	DiscoveryType string `json:",omitempty" alias:"discovery_type"`
 }
 
 type PassiveHealthCheck struct {
@@ -257,6 +331,42 @@ type PassiveHealthCheck struct {
 	EnforcingConsecutive5xx *uint32 `json:",omitempty" alias:"enforcing_consecutive_5xx"`
 }
 
+// RouteRetryPolicy is the default retry policy of the routes to a service.
+// The retries of service-router destinations take precedence over it.
+type RouteRetryPolicy struct {
+	// NumRetries is the number of times to retry the request when a retryable
+	// result occurs.
+	NumRetries uint32 `json:",omitempty" alias:"num_retries"`
+
+	// RetryOn is the list of conditions that trigger a retry.
+	RetryOn []string `json:",omitempty" alias:"retry_on"`
+
+	// RetryOnStatusCodes is a flat list of http response status codes that are
+	// eligible for retry.
+	RetryOnStatusCodes []uint32 `json:",omitempty" alias:"retry_on_status_codes"`
+
+	// PerTryTimeout is the timeout of each attempt.
+	PerTryTimeout time.Duration `json:",omitempty" alias:"per_try_timeout"`
+}
+
+// UpstreamConnectionPool holds the connection settings that the proxies of
+// the downstreams of a service use by default for its upstream clusters. The
+// upstream config of the downstreams overrides them.
+type UpstreamConnectionPool struct {
+	// ConnectTimeoutMs is the number of milliseconds to timeout making a new
+	// connection to the service. The connect timeout of the service-resolver
+	// of the service takes precedence over it.
+	ConnectTimeoutMs int `json:",omitempty" alias:"connect_timeout_ms"`
+
+	// MaxConcurrentStreams is the maximum number of concurrent streams the
+	// proxies open on each HTTP/2 connection to the service.
+	MaxConcurrentStreams int `json:",omitempty" alias:"max_concurrent_streams"`
+
+	// MaxRequestsPerConnection is the maximum number of requests the proxies
+	// send on each connection to the service before closing it.
+	MaxRequestsPerConnection int `json:",omitempty" alias:"max_requests_per_connection"`
+}
+
 // UpstreamLimits describes the limits that are associated with a specific
 // upstream of a service instance.
 type UpstreamLimits struct {
@@ -294,6 +404,9 @@ type ServiceConfigEntry struct {
 	LocalRequestTimeoutMs     int                     `json:",omitempty" alias:"local_request_timeout_ms"`
 	BalanceInboundConnections string                  `json:",omitempty" alias:"balance_inbound_connections"`
 	EnvoyExtensions           []EnvoyExtension        `json:",omitempty" alias:"envoy_extensions"`
+	AccessLogs                *AccessLogsConfig       `json:",omitempty" alias:"access_logs"`
+	RetryPolicy               *RouteRetryPolicy       `json:",omitempty" alias:"retry_policy"`
+	UpstreamConnectionPool    *UpstreamConnectionPool `json:",omitempty" alias:"upstream_connection_pool"`
 	Meta                      map[string]string       `json:",omitempty"`
 	CreateIndex               uint64
 	ModifyIndex               uint64
@@ -319,6 +432,7 @@ type ProxyConfigEntry struct {
 	Expose           ExposeConfig            `json:",omitempty"`
 	AccessLogs       *AccessLogsConfig       `json:",omitempty" alias:"access_logs"`
 	EnvoyExtensions  []EnvoyExtension        `json:",omitempty" alias:"envoy_extensions"`
+	RetryPolicy      *RouteRetryPolicy       `json:",omitempty" alias:"retry_policy"`
 
 	Meta        map[string]string `json:",omitempty"`
 	CreateIndex uint64
@@ -363,6 +477,10 @@ func makeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &InlineCertificateConfigEntry{Kind: kind, Name: name}, nil
 	case HTTPRoute:
 		return &HTTPRouteConfigEntry{Kind: kind, Name: name}, nil
+	case APIGatewayPolicy:
+		return &APIGatewayPolicyConfigEntry{Kind: kind, Name: name}, nil
+	case SamenessGroup:
+		return &SamenessGroupConfigEntry{Kind: kind, Name: name}, nil
 	default:
 		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
 	}
@@ -0,0 +1,442 @@
+package api
+
+import "time"
+
+// The config entry builders build config entries one setting at a time, and
+// validate them client-side when they are built. See ValidateConfigEntry for
+// the validation done.
+
+// ServiceDefaultsBuilder builds a service-defaults config entry.
+type ServiceDefaultsBuilder struct {
+	entry ServiceConfigEntry
+}
+
+// NewServiceDefaults starts building the service-defaults config entry of the
+// service.
+func NewServiceDefaults(service string) *ServiceDefaultsBuilder {
+	return &ServiceDefaultsBuilder{entry: ServiceConfigEntry{Kind: ServiceDefaults, Name: service}}
+}
+
+func (b *ServiceDefaultsBuilder) Partition(partition string) *ServiceDefaultsBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) Namespace(namespace string) *ServiceDefaultsBuilder {
+	b.entry.Namespace = namespace
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) Meta(key, value string) *ServiceDefaultsBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) Protocol(protocol string) *ServiceDefaultsBuilder {
+	b.entry.Protocol = protocol
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) Mode(mode ProxyMode) *ServiceDefaultsBuilder {
+	b.entry.Mode = mode
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) MeshGateway(mode MeshGatewayMode) *ServiceDefaultsBuilder {
+	b.entry.MeshGateway = MeshGatewayConfig{Mode: mode}
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) ExternalSNI(sni string) *ServiceDefaultsBuilder {
+	b.entry.ExternalSNI = sni
+	return b
+}
+
+func (b *ServiceDefaultsBuilder) MaxInboundConnections(max int) *ServiceDefaultsBuilder {
+	b.entry.MaxInboundConnections = max
+	return b
+}
+
+// UpstreamDefaults sets the configuration of all the upstreams of the service.
+func (b *ServiceDefaultsBuilder) UpstreamDefaults(defaults UpstreamConfig) *ServiceDefaultsBuilder {
+	if b.entry.UpstreamConfig == nil {
+		b.entry.UpstreamConfig = &UpstreamConfiguration{}
+	}
+	b.entry.UpstreamConfig.Defaults = &defaults
+	return b
+}
+
+// UpstreamOverride adds the configuration of the upstream named in the
+// override, which takes precedence over the upstream defaults.
+func (b *ServiceDefaultsBuilder) UpstreamOverride(override UpstreamConfig) *ServiceDefaultsBuilder {
+	if b.entry.UpstreamConfig == nil {
+		b.entry.UpstreamConfig = &UpstreamConfiguration{}
+	}
+	b.entry.UpstreamConfig.Overrides = append(b.entry.UpstreamConfig.Overrides, &override)
+	return b
+}
+
+// Destination makes the service a destination external to the mesh, reached
+// through a terminating gateway at the addresses and port.
+func (b *ServiceDefaultsBuilder) Destination(port int, addresses ...string) *ServiceDefaultsBuilder {
+	b.entry.Destination = &DestinationConfig{Addresses: addresses, Port: port}
+	return b
+}
+
+// Build validates and returns the config entry.
+func (b *ServiceDefaultsBuilder) Build() (*ServiceConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ProxyDefaultsBuilder builds the proxy-defaults config entry.
+type ProxyDefaultsBuilder struct {
+	entry ProxyConfigEntry
+}
+
+// NewProxyDefaults starts building the proxy-defaults config entry, of which
+// there is a single one per partition.
+func NewProxyDefaults() *ProxyDefaultsBuilder {
+	return &ProxyDefaultsBuilder{entry: ProxyConfigEntry{Kind: ProxyDefaults, Name: ProxyConfigGlobal}}
+}
+
+func (b *ProxyDefaultsBuilder) Partition(partition string) *ProxyDefaultsBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ProxyDefaultsBuilder) Meta(key, value string) *ProxyDefaultsBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Config sets a key of the opaque configuration of the proxies.
+func (b *ProxyDefaultsBuilder) Config(key string, value interface{}) *ProxyDefaultsBuilder {
+	if b.entry.Config == nil {
+		b.entry.Config = make(map[string]interface{})
+	}
+	b.entry.Config[key] = value
+	return b
+}
+
+func (b *ProxyDefaultsBuilder) Mode(mode ProxyMode) *ProxyDefaultsBuilder {
+	b.entry.Mode = mode
+	return b
+}
+
+func (b *ProxyDefaultsBuilder) MeshGateway(mode MeshGatewayMode) *ProxyDefaultsBuilder {
+	b.entry.MeshGateway = MeshGatewayConfig{Mode: mode}
+	return b
+}
+
+// Build validates and returns the config entry.
+func (b *ProxyDefaultsBuilder) Build() (*ProxyConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ServiceRouterBuilder builds a service-router config entry.
+type ServiceRouterBuilder struct {
+	entry ServiceRouterConfigEntry
+}
+
+// NewServiceRouter starts building the service-router config entry of the
+// service.
+func NewServiceRouter(service string) *ServiceRouterBuilder {
+	return &ServiceRouterBuilder{entry: ServiceRouterConfigEntry{Kind: ServiceRouter, Name: service}}
+}
+
+func (b *ServiceRouterBuilder) Partition(partition string) *ServiceRouterBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ServiceRouterBuilder) Namespace(namespace string) *ServiceRouterBuilder {
+	b.entry.Namespace = namespace
+	return b
+}
+
+func (b *ServiceRouterBuilder) Meta(key, value string) *ServiceRouterBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Route adds the route after the routes added before, which take precedence
+// over it.
+func (b *ServiceRouterBuilder) Route(route ServiceRoute) *ServiceRouterBuilder {
+	b.entry.Routes = append(b.entry.Routes, route)
+	return b
+}
+
+// RoutePathPrefix adds a route of the requests whose path starts with the
+// prefix to the destination.
+func (b *ServiceRouterBuilder) RoutePathPrefix(prefix string, destination ServiceRouteDestination) *ServiceRouterBuilder {
+	return b.Route(ServiceRoute{
+		Match:       &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{PathPrefix: prefix}},
+		Destination: &destination,
+	})
+}
+
+// RoutePathExact adds a route of the requests whose path is exactly the path
+// to the destination.
+func (b *ServiceRouterBuilder) RoutePathExact(path string, destination ServiceRouteDestination) *ServiceRouterBuilder {
+	return b.Route(ServiceRoute{
+		Match:       &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{PathExact: path}},
+		Destination: &destination,
+	})
+}
+
+// RouteHeader adds a route of the requests with the header set to the value
+// to the destination.
+func (b *ServiceRouterBuilder) RouteHeader(name, value string, destination ServiceRouteDestination) *ServiceRouterBuilder {
+	return b.Route(ServiceRoute{
+		Match: &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{
+			Header: []ServiceRouteHTTPMatchHeader{{Name: name, Exact: value}},
+		}},
+		Destination: &destination,
+	})
+}
+
+// Build validates and returns the config entry.
+func (b *ServiceRouterBuilder) Build() (*ServiceRouterConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ServiceSplitterBuilder builds a service-splitter config entry.
+type ServiceSplitterBuilder struct {
+	entry ServiceSplitterConfigEntry
+}
+
+// NewServiceSplitter starts building the service-splitter config entry of the
+// service.
+func NewServiceSplitter(service string) *ServiceSplitterBuilder {
+	return &ServiceSplitterBuilder{entry: ServiceSplitterConfigEntry{Kind: ServiceSplitter, Name: service}}
+}
+
+func (b *ServiceSplitterBuilder) Partition(partition string) *ServiceSplitterBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ServiceSplitterBuilder) Namespace(namespace string) *ServiceSplitterBuilder {
+	b.entry.Namespace = namespace
+	return b
+}
+
+func (b *ServiceSplitterBuilder) Meta(key, value string) *ServiceSplitterBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Split sends the percentage of the traffic given by the weight to the subset
+// of the service. An empty service is the service of the splitter, and an
+// empty subset is the default subset.
+func (b *ServiceSplitterBuilder) Split(weight float32, service, subset string) *ServiceSplitterBuilder {
+	return b.SplitTo(ServiceSplit{Weight: weight, Service: service, ServiceSubset: subset})
+}
+
+// SplitTo adds the split.
+func (b *ServiceSplitterBuilder) SplitTo(split ServiceSplit) *ServiceSplitterBuilder {
+	b.entry.Splits = append(b.entry.Splits, split)
+	return b
+}
+
+// Build validates and returns the config entry.
+func (b *ServiceSplitterBuilder) Build() (*ServiceSplitterConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ServiceResolverBuilder builds a service-resolver config entry.
+type ServiceResolverBuilder struct {
+	entry ServiceResolverConfigEntry
+}
+
+// NewServiceResolver starts building the service-resolver config entry of the
+// service.
+func NewServiceResolver(service string) *ServiceResolverBuilder {
+	return &ServiceResolverBuilder{entry: ServiceResolverConfigEntry{Kind: ServiceResolver, Name: service}}
+}
+
+func (b *ServiceResolverBuilder) Partition(partition string) *ServiceResolverBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ServiceResolverBuilder) Namespace(namespace string) *ServiceResolverBuilder {
+	b.entry.Namespace = namespace
+	return b
+}
+
+func (b *ServiceResolverBuilder) Meta(key, value string) *ServiceResolverBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Subset defines the subset of the instances of the service matching the
+// filter.
+func (b *ServiceResolverBuilder) Subset(name, filter string, onlyPassing bool) *ServiceResolverBuilder {
+	if b.entry.Subsets == nil {
+		b.entry.Subsets = make(map[string]ServiceResolverSubset)
+	}
+	b.entry.Subsets[name] = ServiceResolverSubset{Filter: filter, OnlyPassing: onlyPassing}
+	return b
+}
+
+func (b *ServiceResolverBuilder) DefaultSubset(subset string) *ServiceResolverBuilder {
+	b.entry.DefaultSubset = subset
+	return b
+}
+
+// Redirect resolves the service to another one. It can't be combined with
+// failovers.
+func (b *ServiceResolverBuilder) Redirect(redirect ServiceResolverRedirect) *ServiceResolverBuilder {
+	b.entry.Redirect = &redirect
+	return b
+}
+
+// Failover sets the failover of the subset, "*" being every subset.
+func (b *ServiceResolverBuilder) Failover(subset string, failover ServiceResolverFailover) *ServiceResolverBuilder {
+	if b.entry.Failover == nil {
+		b.entry.Failover = make(map[string]ServiceResolverFailover)
+	}
+	b.entry.Failover[subset] = failover
+	return b
+}
+
+func (b *ServiceResolverBuilder) ConnectTimeout(timeout time.Duration) *ServiceResolverBuilder {
+	b.entry.ConnectTimeout = timeout
+	return b
+}
+
+func (b *ServiceResolverBuilder) LoadBalancer(lb LoadBalancer) *ServiceResolverBuilder {
+	b.entry.LoadBalancer = &lb
+	return b
+}
+
+// Build validates and returns the config entry.
+func (b *ServiceResolverBuilder) Build() (*ServiceResolverConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ServiceIntentionsBuilder builds a service-intentions config entry.
+type ServiceIntentionsBuilder struct {
+	entry ServiceIntentionsConfigEntry
+}
+
+// NewServiceIntentions starts building the service-intentions config entry of
+// the destination service, "*" being every service.
+func NewServiceIntentions(destination string) *ServiceIntentionsBuilder {
+	return &ServiceIntentionsBuilder{entry: ServiceIntentionsConfigEntry{Kind: ServiceIntentions, Name: destination}}
+}
+
+func (b *ServiceIntentionsBuilder) Partition(partition string) *ServiceIntentionsBuilder {
+	b.entry.Partition = partition
+	return b
+}
+
+func (b *ServiceIntentionsBuilder) Namespace(namespace string) *ServiceIntentionsBuilder {
+	b.entry.Namespace = namespace
+	return b
+}
+
+func (b *ServiceIntentionsBuilder) Meta(key, value string) *ServiceIntentionsBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Allow allows the source service to connect to the destination.
+func (b *ServiceIntentionsBuilder) Allow(source string) *ServiceIntentionsBuilder {
+	return b.Source(SourceIntention{Name: source, Action: IntentionActionAllow, Type: IntentionSourceConsul})
+}
+
+// Deny denies the source service to connect to the destination.
+func (b *ServiceIntentionsBuilder) Deny(source string) *ServiceIntentionsBuilder {
+	return b.Source(SourceIntention{Name: source, Action: IntentionActionDeny, Type: IntentionSourceConsul})
+}
+
+// Permissions restricts the requests of the source service to the destination
+// with the L7 permissions, which are evaluated in order.
+func (b *ServiceIntentionsBuilder) Permissions(source string, permissions ...*IntentionPermission) *ServiceIntentionsBuilder {
+	return b.Source(SourceIntention{Name: source, Permissions: permissions, Type: IntentionSourceConsul})
+}
+
+// Source adds the source intention.
+func (b *ServiceIntentionsBuilder) Source(source SourceIntention) *ServiceIntentionsBuilder {
+	b.entry.Sources = append(b.entry.Sources, &source)
+	return b
+}
+
+// Build validates and returns the config entry.
+func (b *ServiceIntentionsBuilder) Build() (*ServiceIntentionsConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ExportedServicesBuilder builds an exported-services config entry.
+type ExportedServicesBuilder struct {
+	entry ExportedServicesConfigEntry
+}
+
+// NewExportedServices starts building the exported-services config entry of
+// the partition, which is "default" outside of Consul Enterprise.
+func NewExportedServices(partition string) *ExportedServicesBuilder {
+	return &ExportedServicesBuilder{entry: ExportedServicesConfigEntry{Name: partition}}
+}
+
+func (b *ExportedServicesBuilder) Meta(key, value string) *ExportedServicesBuilder {
+	b.entry.Meta = setConfigEntryMeta(b.entry.Meta, key, value)
+	return b
+}
+
+// Export adds the exported service.
+func (b *ExportedServicesBuilder) Export(service ExportedService) *ExportedServicesBuilder {
+	b.entry.Services = append(b.entry.Services, service)
+	return b
+}
+
+// ExportToPeers exports the service of the default namespace to the peers.
+func (b *ExportedServicesBuilder) ExportToPeers(service string, peers ...string) *ExportedServicesBuilder {
+	consumers := make([]ServiceConsumer, 0, len(peers))
+	for _, peer := range peers {
+		consumers = append(consumers, ServiceConsumer{Peer: peer})
+	}
+	return b.Export(ExportedService{Name: service, Consumers: consumers})
+}
+
+// Build validates and returns the config entry.
+func (b *ExportedServicesBuilder) Build() (*ExportedServicesConfigEntry, error) {
+	entry := b.entry
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func setConfigEntryMeta(meta map[string]string, key, value string) map[string]string {
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	meta[key] = value
+	return meta
+}
@@ -222,8 +222,9 @@ func (e *ServiceResolverConfigEntry) GetCreateIndex() uint64     { return e.Crea
 func (e *ServiceResolverConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
 
 type ServiceResolverSubset struct {
-	Filter      string `json:",omitempty"`
-	OnlyPassing bool   `json:",omitempty" alias:"only_passing"`
+	Filter      string            `json:",omitempty"`
+	Meta        map[string]string `json:",omitempty"`
+	OnlyPassing bool              `json:",omitempty" alias:"only_passing"`
 }
 
 type ServiceResolverRedirect struct {
@@ -233,15 +234,17 @@ type ServiceResolverRedirect struct {
 	Partition     string `json:",omitempty"`
 	Datacenter    string `json:",omitempty"`
 	Peer          string `json:",omitempty"`
+	Priority      int    `json:",omitempty"`
 }
 
 type ServiceResolverFailover struct {
 	Service       string `json:",omitempty"`
 	ServiceSubset string `json:",omitempty" alias:"service_subset"`
 	// Referencing other partitions is not supported.
-	Namespace   string                          `json:",omitempty"`
-	Datacenters []string                        `json:",omitempty"`
-	Targets     []ServiceResolverFailoverTarget `json:",omitempty"`
+	Namespace     string                          `json:",omitempty"`
+	Datacenters   []string                        `json:",omitempty"`
+	Targets       []ServiceResolverFailoverTarget `json:",omitempty"`
+	SamenessGroup string                          `json:",omitempty" alias:"sameness_group"`
 }
 
 type ServiceResolverFailoverTarget struct {
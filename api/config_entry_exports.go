@@ -39,8 +39,17 @@ type ExportedService struct {
 	// Namespace is the namespace to export the service from.
 	Namespace string `json:",omitempty"`
 
+	// Exclude is a list of service names in the namespace that are not
+	// exported. It may only be set when Name is the wildcard.
+	Exclude []string `json:",omitempty"`
+
 	// Consumers is a list of downstream consumers of the service to be exported.
 	Consumers []ServiceConsumer `json:",omitempty"`
+
+	// ConfigEntries is a list of config entry kinds that are replicated
+	// alongside the service to the peers consuming it. Only service-defaults,
+	// service-resolver and service-intentions entries can be replicated.
+	ConfigEntries []string `json:",omitempty" alias:"config_entries"`
 }
 
 // ServiceConsumer represents a downstream consumer of the service to be exported.
@@ -51,6 +60,10 @@ type ServiceConsumer struct {
 
 	// Peer is the name of the peer to export the service to.
 	Peer string `json:",omitempty" alias:"peer_name"`
+
+	// RedactFields is a list of instance data fields that are removed from
+	// the service before it is replicated to the peer.
+	RedactFields []string `json:",omitempty" alias:"redact_fields"`
 }
 
 func (e *ExportedServicesConfigEntry) GetKind() string            { return ExportedServices }
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// APIGatewayPolicyConfigEntry manages the edge policies applied to the
+// listeners of the API gateway with the same name.
+type APIGatewayPolicyConfigEntry struct {
+	// Kind of the config entry. This should be set to api.APIGatewayPolicy.
+	Kind string
+
+	// Name is used to match the config entry with its associated API gateway.
+	Name string
+
+	// Listeners is the set of per-listener policies. Each entry refers to an
+	// api-gateway listener by name.
+	Listeners []APIGatewayListenerPolicy `json:",omitempty"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+
+	// Partition is the partition the config entry is associated with.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the config entry is associated with.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+}
+
+func (g *APIGatewayPolicyConfigEntry) GetKind() string            { return APIGatewayPolicy }
+func (g *APIGatewayPolicyConfigEntry) GetName() string            { return g.Name }
+func (g *APIGatewayPolicyConfigEntry) GetPartition() string       { return g.Partition }
+func (g *APIGatewayPolicyConfigEntry) GetNamespace() string       { return g.Namespace }
+func (g *APIGatewayPolicyConfigEntry) GetMeta() map[string]string { return g.Meta }
+func (g *APIGatewayPolicyConfigEntry) GetCreateIndex() uint64     { return g.CreateIndex }
+func (g *APIGatewayPolicyConfigEntry) GetModifyIndex() uint64     { return g.ModifyIndex }
+
+// APIGatewayListenerPolicy contains the policies for a single listener of
+// an API gateway.
+type APIGatewayListenerPolicy struct {
+	// Name is the name of the api-gateway listener the policy applies to.
+	Name string
+
+	// RateLimit configures the local and global rate limits enforced on
+	// requests received by the listener.
+	RateLimit *APIGatewayRateLimit `json:",omitempty" alias:"rate_limit"`
+
+	// JWT configures the verification of JSON Web Tokens on requests received
+	// by the listener.
+	JWT *APIGatewayJWTPolicy `json:",omitempty"`
+
+	// ExtAuthz configures an external authorization service that is consulted
+	// for requests received by the listener.
+	ExtAuthz *APIGatewayExtAuthz `json:",omitempty" alias:"ext_authz"`
+}
+
+// APIGatewayRateLimit configures rate limiting for an API gateway listener.
+type APIGatewayRateLimit struct {
+	// Local is a token bucket enforced by each gateway instance for all
+	// requests received by the listener.
+	Local *APIGatewayLocalRateLimit `json:",omitempty"`
+
+	// Routes are token buckets enforced for the requests matching a specific
+	// hostname and/or path prefix.
+	Routes []APIGatewayRouteRateLimit `json:",omitempty"`
+
+	// Global configures an external rate limit service that is consulted
+	// for every request received by the listener.
+	Global *APIGatewayGlobalRateLimit `json:",omitempty"`
+}
+
+// APIGatewayLocalRateLimit is a token bucket used for local rate limiting.
+type APIGatewayLocalRateLimit struct {
+	MaxTokens     uint32        `json:",omitempty" alias:"max_tokens"`
+	TokensPerFill uint32        `json:",omitempty" alias:"tokens_per_fill"`
+	FillInterval  time.Duration `json:",omitempty" alias:"fill_interval"`
+}
+
+func (l *APIGatewayLocalRateLimit) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayLocalRateLimit
+	exported := &struct {
+		FillInterval string `json:",omitempty"`
+		*Alias
+	}{
+		FillInterval: l.FillInterval.String(),
+		Alias:        (*Alias)(l),
+	}
+	if l.FillInterval == 0 {
+		exported.FillInterval = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (l *APIGatewayLocalRateLimit) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayLocalRateLimit
+	aux := &struct {
+		FillInterval string
+		*Alias
+	}{
+		Alias: (*Alias)(l),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.FillInterval != "" {
+		if l.FillInterval, err = time.ParseDuration(aux.FillInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayRouteRateLimit is a local rate limit applied to the routes of a
+// listener that match the given hostname and path prefix.
+type APIGatewayRouteRateLimit struct {
+	Hostname   string `json:",omitempty"`
+	PathPrefix string `json:",omitempty" alias:"path_prefix"`
+	Local      APIGatewayLocalRateLimit
+}
+
+// APIGatewayGlobalRateLimit configures an external rate limit service that
+// implements the Envoy rate limit gRPC API.
+type APIGatewayGlobalRateLimit struct {
+	Domain          string
+	Address         string
+	Port            int
+	Timeout         time.Duration `json:",omitempty"`
+	FailureModeDeny bool          `json:",omitempty" alias:"failure_mode_deny"`
+}
+
+func (g *APIGatewayGlobalRateLimit) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayGlobalRateLimit
+	exported := &struct {
+		Timeout string `json:",omitempty"`
+		*Alias
+	}{
+		Timeout: g.Timeout.String(),
+		Alias:   (*Alias)(g),
+	}
+	if g.Timeout == 0 {
+		exported.Timeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (g *APIGatewayGlobalRateLimit) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayGlobalRateLimit
+	aux := &struct {
+		Timeout string
+		*Alias
+	}{
+		Alias: (*Alias)(g),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Timeout != "" {
+		if g.Timeout, err = time.ParseDuration(aux.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayJWTPolicy configures JWT authentication for an API gateway
+// listener.
+type APIGatewayJWTPolicy struct {
+	// Providers is the set of token issuers accepted by the listener.
+	Providers []APIGatewayJWTProvider
+
+	// AllowMissingPaths is a list of path prefixes on which requests without
+	// a token are allowed through. Whole path segments are matched, so
+	// /public doesn't match /public-admin.
+	AllowMissingPaths []string `json:",omitempty" alias:"allow_missing_paths"`
+}
+
+// APIGatewayJWTProvider describes an issuer of JSON Web Tokens.
+type APIGatewayJWTProvider struct {
+	Name            string
+	Issuer          string                       `json:",omitempty"`
+	Audiences       []string                     `json:",omitempty"`
+	JSONWebKeySet   APIGatewayJSONWebKeySet      `alias:"json_web_key_set"`
+	Forward         bool                         `json:",omitempty"`
+	ClaimsToHeaders []APIGatewayJWTClaimToHeader `json:",omitempty" alias:"claims_to_headers"`
+}
+
+// APIGatewayJSONWebKeySet is the source of a JSON Web Key Set. Exactly one of
+// Local and RemoteURI must be set.
+type APIGatewayJSONWebKeySet struct {
+	Local         string        `json:",omitempty"`
+	RemoteURI     string        `json:",omitempty" alias:"remote_uri"`
+	CAFile        string        `json:",omitempty" alias:"ca_file"`
+	CacheDuration time.Duration `json:",omitempty" alias:"cache_duration"`
+}
+
+func (k *APIGatewayJSONWebKeySet) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayJSONWebKeySet
+	exported := &struct {
+		CacheDuration string `json:",omitempty"`
+		*Alias
+	}{
+		CacheDuration: k.CacheDuration.String(),
+		Alias:         (*Alias)(k),
+	}
+	if k.CacheDuration == 0 {
+		exported.CacheDuration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (k *APIGatewayJSONWebKeySet) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayJSONWebKeySet
+	aux := &struct {
+		CacheDuration string
+		*Alias
+	}{
+		Alias: (*Alias)(k),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.CacheDuration != "" {
+		if k.CacheDuration, err = time.ParseDuration(aux.CacheDuration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIGatewayJWTClaimToHeader maps a token claim to a request header.
+type APIGatewayJWTClaimToHeader struct {
+	Claim  string
+	Header string
+}
+
+// APIGatewayExtAuthz configures an external authorization service that
+// implements the Envoy ext_authz API over gRPC or HTTP.
+type APIGatewayExtAuthz struct {
+	// Protocol is one of "grpc" or "http". Defaults to "grpc".
+	Protocol         string `json:",omitempty"`
+	Address          string
+	Port             int
+	Timeout          time.Duration `json:",omitempty"`
+	PathPrefix       string        `json:",omitempty" alias:"path_prefix"`
+	AllowedHeaders   []string      `json:",omitempty" alias:"allowed_headers"`
+	FailureModeAllow bool          `json:",omitempty" alias:"failure_mode_allow"`
+
+	// DisabledPaths is a list of path prefixes for which the authorization
+	// service is not consulted. Whole path segments are matched, so /public
+	// doesn't match /public-admin.
+	DisabledPaths []string `json:",omitempty" alias:"disabled_paths"`
+}
+
+func (a *APIGatewayExtAuthz) MarshalJSON() ([]byte, error) {
+	type Alias APIGatewayExtAuthz
+	exported := &struct {
+		Timeout string `json:",omitempty"`
+		*Alias
+	}{
+		Timeout: a.Timeout.String(),
+		Alias:   (*Alias)(a),
+	}
+	if a.Timeout == 0 {
+		exported.Timeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (a *APIGatewayExtAuthz) UnmarshalJSON(data []byte) error {
+	type Alias APIGatewayExtAuthz
+	aux := &struct {
+		Timeout string
+		*Alias
+	}{
+		Alias: (*Alias)(a),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Timeout != "" {
+		if a.Timeout, err = time.ParseDuration(aux.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
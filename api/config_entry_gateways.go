@@ -95,6 +95,10 @@ type IngressListener struct {
 
 	// TLS allows specifying some TLS configuration per listener.
 	TLS *GatewayTLSConfig `json:",omitempty"`
+
+	// AccessLogs overrides the proxy-defaults access logging configuration
+	// for this listener only.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
 }
 
 // IngressService manages configuration for services that are exposed to
@@ -282,6 +286,9 @@ type APIGatewayListener struct {
 	Protocol string
 	// TLS is the TLS settings for the listener.
 	TLS APIGatewayTLSConfiguration
+	// AccessLogs overrides the proxy-defaults access logging configuration
+	// for this listener only.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
 }
 
 // APIGatewayTLSConfiguration specifies the configuration of a listener’s
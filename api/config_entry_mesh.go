@@ -25,6 +25,11 @@ type MeshConfigEntry struct {
 
 	Peering *PeeringMeshConfig `json:",omitempty"`
 
+	// MeshGateway is the default mesh gateway configuration for all proxies
+	// in the partition. It is overridden by proxy-defaults, service-defaults
+	// and upstream configuration.
+	MeshGateway MeshGatewayConfig `json:",omitempty" alias:"mesh_gateway"`
+
 	Meta map[string]string `json:",omitempty"`
 
 	// CreateIndex is the Raft index this entry was created at. This is a
@@ -54,6 +59,31 @@ type MeshDirectionalTLSConfig struct {
 
 type MeshHTTPConfig struct {
 	SanitizeXForwardedClientCert bool `alias:"sanitize_x_forwarded_client_cert"`
+	// Incoming configures the HTTP requests that the inbound listeners of
+	// the proxies receive.
+	Incoming *MeshDirectionalHTTPConfig `json:",omitempty"`
+}
+
+type MeshDirectionalHTTPConfig struct {
+	RequestNormalization *RequestNormalizationMeshConfig `json:",omitempty" alias:"request_normalization"`
+}
+
+// RequestNormalizationMeshConfig contains the options that the proxies use
+// to normalize the HTTP requests before they match them against L7
+// intentions and routes.
+type RequestNormalizationMeshConfig struct {
+	// NormalizePath normalizes the path of the requests according to
+	// RFC 3986.
+	NormalizePath bool `json:",omitempty" alias:"normalize_path"`
+	// MergeSlashes merges adjacent slashes in the path of the requests.
+	MergeSlashes bool `json:",omitempty" alias:"merge_slashes"`
+	// PathWithEscapedSlashesAction is one of IMPLEMENTATION_SPECIFIC_DEFAULT,
+	// KEEP_UNCHANGED, REJECT_REQUEST, UNESCAPE_AND_REDIRECT or
+	// UNESCAPE_AND_FORWARD.
+	PathWithEscapedSlashesAction string `json:",omitempty" alias:"path_with_escaped_slashes_action"`
+	// HeadersWithUnderscoresAction is one of ALLOW, REJECT_REQUEST or
+	// DROP_HEADER.
+	HeadersWithUnderscoresAction string `json:",omitempty" alias:"headers_with_underscores_action"`
 }
 
 type PeeringMeshConfig struct {
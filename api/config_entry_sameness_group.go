@@ -0,0 +1,47 @@
+package api
+
+// SamenessGroupConfigEntry defines a group of cluster peers that run the same
+// services. Prepared queries can fail over to the members of the group.
+type SamenessGroupConfigEntry struct {
+	// Kind of the config entry. This should be set to api.SamenessGroup.
+	Kind string
+
+	// Name is the name of the sameness group.
+	Name string
+
+	// Members are the cluster peers in the group, in failover order.
+	Members []SamenessGroupMember `json:",omitempty"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+
+	// Partition is the partition the config entry is associated with.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the config entry is associated with.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+}
+
+// SamenessGroupMember is a cluster peer that is a member of a sameness group.
+type SamenessGroupMember struct {
+	// Peer is the name of the cluster peer.
+	Peer string
+}
+
+func (s *SamenessGroupConfigEntry) GetKind() string            { return SamenessGroup }
+func (s *SamenessGroupConfigEntry) GetName() string            { return s.Name }
+func (s *SamenessGroupConfigEntry) GetPartition() string       { return s.Partition }
+func (s *SamenessGroupConfigEntry) GetNamespace() string       { return s.Namespace }
+func (s *SamenessGroupConfigEntry) GetMeta() map[string]string { return s.Meta }
+func (s *SamenessGroupConfigEntry) GetCreateIndex() uint64     { return s.CreateIndex }
+func (s *SamenessGroupConfigEntry) GetModifyIndex() uint64     { return s.ModifyIndex }
@@ -1337,7 +1337,15 @@ func TestDecodeConfigEntry(t *testing.T) {
 					}
 				},
 				"HTTP": {
-					"SanitizeXForwardedClientCert": true
+					"SanitizeXForwardedClientCert": true,
+					"Incoming": {
+						"RequestNormalization": {
+							"NormalizePath": true,
+							"MergeSlashes": true,
+							"PathWithEscapedSlashesAction": "REJECT_REQUEST",
+							"HeadersWithUnderscoresAction": "DROP_HEADER"
+						}
+					}
 				},
 				"Peering": {
 					"PeerThroughMeshGateways": true
@@ -1372,6 +1380,14 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 				HTTP: &MeshHTTPConfig{
 					SanitizeXForwardedClientCert: true,
+					Incoming: &MeshDirectionalHTTPConfig{
+						RequestNormalization: &RequestNormalizationMeshConfig{
+							NormalizePath:                true,
+							MergeSlashes:                 true,
+							PathWithEscapedSlashesAction: "REJECT_REQUEST",
+							HeadersWithUnderscoresAction: "DROP_HEADER",
+						},
+					},
 				},
 				Peering: &PeeringMeshConfig{
 					PeerThroughMeshGateways: true,
@@ -0,0 +1,963 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	// The limits on the meta of the config entries enforced by the servers.
+	configEntryMetaMaxKeyPairs    = 64
+	configEntryMetaKeyMaxLength   = 128
+	configEntryMetaValueMaxLength = 512
+
+	// The limits on the service meta enforced by the servers.
+	serviceMetaKeyMaxLength   = 128
+	serviceMetaValueMaxLength = 512
+
+	configEntryWildcard = "*"
+)
+
+var (
+	validServiceSubsetName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	validServiceMetaKey    = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	validLoadBalancerPolicies = map[string]bool{
+		"":              true,
+		"random":        true,
+		"round_robin":   true,
+		"least_request": true,
+		"ring_hash":     true,
+		"maglev":        true,
+	}
+
+	validHashPolicyFields = map[string]bool{
+		"header":          true,
+		"cookie":          true,
+		"query_parameter": true,
+	}
+
+	validIngressListenerProtocols = map[string]bool{
+		"tcp":   true,
+		"http":  true,
+		"http2": true,
+		"grpc":  true,
+	}
+)
+
+// ValidateConfigEntry validates the config entry client-side, so that tools
+// can fail fast instead of waiting for the servers to reject it. It mirrors
+// the validation of the servers that depends neither on their state nor on
+// the edition of Consul: an entry it rejects would be rejected by the servers,
+// but the servers may still reject an entry it accepts.
+func ValidateConfigEntry(entry ConfigEntry) error {
+	if v := reflect.ValueOf(entry); entry == nil || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	expected, err := makeConfigEntry(entry.GetKind(), "")
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(expected) != reflect.TypeOf(entry) {
+		return fmt.Errorf("Kind %q doesn't match the config entry type %T", entry.GetKind(), entry)
+	}
+
+	if err := validateConfigEntryMeta(entry.GetMeta()); err != nil {
+		return err
+	}
+
+	if v, ok := entry.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	if entry.GetName() == "" {
+		return fmt.Errorf("Name is required")
+	}
+	return nil
+}
+
+// Validate validates the service-defaults config entry client-side. See
+// ValidateConfigEntry.
+func (s *ServiceConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(s.Kind, ServiceDefaults); err != nil {
+		return err
+	}
+	if s.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if s.Name == configEntryWildcard {
+		return fmt.Errorf("service-defaults name must be the name of a service, and not a wildcard")
+	}
+	if err := validateConfigEntryMeta(s.Meta); err != nil {
+		return err
+	}
+	if !isValidConnectionBalance(s.BalanceInboundConnections) {
+		return fmt.Errorf("invalid value for balance_inbound_connections: %v", s.BalanceInboundConnections)
+	}
+
+	if s.UpstreamConfig != nil && s.Destination != nil {
+		return fmt.Errorf("UpstreamConfig and Destination are mutually exclusive for service defaults")
+	}
+
+	if s.UpstreamConfig != nil {
+		for _, override := range s.UpstreamConfig.Overrides {
+			if override == nil {
+				continue
+			}
+			if err := override.validate(true); err != nil {
+				return fmt.Errorf("error in upstream override for %s: %v", override.Name, err)
+			}
+		}
+		if s.UpstreamConfig.Defaults != nil {
+			if err := s.UpstreamConfig.Defaults.validate(false); err != nil {
+				return fmt.Errorf("error in upstream defaults: %v", err)
+			}
+		}
+	}
+
+	if s.Destination != nil {
+		if len(s.Destination.Addresses) == 0 {
+			return fmt.Errorf("Destination must contain at least one valid address")
+		}
+		seen := make(map[string]bool, len(s.Destination.Addresses))
+		for _, address := range s.Destination.Addresses {
+			if seen[address] {
+				return fmt.Errorf("Duplicate address '%s' is not allowed", address)
+			}
+			seen[address] = true
+		}
+		if s.Destination.Port < 1 || s.Destination.Port > 65535 {
+			return fmt.Errorf("Invalid Port number %d", s.Destination.Port)
+		}
+	}
+
+	if p := s.UpstreamConnectionPool; p != nil {
+		if p.ConnectTimeoutMs < 0 {
+			return fmt.Errorf("invalid upstream connection pool: connect timeout cannot be negative")
+		}
+		if p.MaxConcurrentStreams < 0 {
+			return fmt.Errorf("invalid upstream connection pool: max concurrent streams cannot be negative")
+		}
+		if p.MaxRequestsPerConnection < 0 {
+			return fmt.Errorf("invalid upstream connection pool: max requests per connection cannot be negative")
+		}
+	}
+
+	if s.RetryPolicy != nil {
+		if err := s.RetryPolicy.validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+	return nil
+}
+
+func (u *UpstreamConfig) validate(named bool) error {
+	if named {
+		if u.Name == "" {
+			return fmt.Errorf("Name is required")
+		}
+		if u.Name == configEntryWildcard {
+			return fmt.Errorf("Wildcard name is not supported")
+		}
+		if u.Namespace == configEntryWildcard {
+			return fmt.Errorf("Wildcard namespace is not supported")
+		}
+	} else {
+		if u.Name != "" {
+			return fmt.Errorf("Name must be empty")
+		}
+		if u.Namespace != "" {
+			return fmt.Errorf("Namespace must be empty")
+		}
+		if u.Partition != "" {
+			return fmt.Errorf("Partition must be empty")
+		}
+	}
+
+	if u.PassiveHealthCheck != nil && u.PassiveHealthCheck.Interval < 0 {
+		return fmt.Errorf("passive health check interval cannot be negative")
+	}
+	if l := u.Limits; l != nil {
+		if l.MaxConnections != nil && *l.MaxConnections < 0 {
+			return fmt.Errorf("max connections cannot be negative")
+		}
+		if l.MaxPendingRequests != nil && *l.MaxPendingRequests < 0 {
+			return fmt.Errorf("max pending requests cannot be negative")
+		}
+		if l.MaxConcurrentRequests != nil && *l.MaxConcurrentRequests < 0 {
+			return fmt.Errorf("max concurrent requests cannot be negative")
+		}
+	}
+	if !isValidConnectionBalance(u.BalanceOutboundConnections) {
+		return fmt.Errorf("invalid value for balance_outbound_connections: %v", u.BalanceOutboundConnections)
+	}
+	if u.MaxConcurrentStreams < 0 {
+		return fmt.Errorf("max concurrent streams cannot be negative")
+	}
+	if u.MaxRequestsPerConnection < 0 {
+		return fmt.Errorf("max requests per connection cannot be negative")
+	}
+	return nil
+}
+
+// Validate validates the proxy-defaults config entry client-side. See
+// ValidateConfigEntry.
+func (p *ProxyConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(p.Kind, ProxyDefaults); err != nil {
+		return err
+	}
+	if p.Name != "" && p.Name != ProxyConfigGlobal {
+		return fmt.Errorf("invalid name (%q), only %q is supported", p.Name, ProxyConfigGlobal)
+	}
+	if p.RetryPolicy != nil {
+		if err := p.RetryPolicy.validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+	return validateConfigEntryMeta(p.Meta)
+}
+
+func (p *RouteRetryPolicy) validate() error {
+	if len(p.RetryOn) == 0 && len(p.RetryOnStatusCodes) == 0 {
+		return fmt.Errorf("at least one of RetryOn or RetryOnStatusCodes must be set")
+	}
+	for _, r := range p.RetryOn {
+		if !isValidRetryCondition(r) {
+			return fmt.Errorf("invalid retry condition: %q", r)
+		}
+	}
+	if p.PerTryTimeout < 0 {
+		return fmt.Errorf("PerTryTimeout must be greater than or equal to 0s, got %s", p.PerTryTimeout)
+	}
+	return nil
+}
+
+// Validate validates the service-router config entry client-side. See
+// ValidateConfigEntry.
+func (e *ServiceRouterConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(e.Kind, ServiceRouter); err != nil {
+		return err
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	for i, route := range e.Routes {
+		eligibleForPrefixRewrite := false
+		if route.Match != nil && route.Match.HTTP != nil {
+			match := route.Match.HTTP
+			pathParts := 0
+			if match.PathExact != "" {
+				eligibleForPrefixRewrite = true
+				pathParts++
+				if !strings.HasPrefix(match.PathExact, "/") {
+					return fmt.Errorf("Route[%d] PathExact doesn't start with '/': %q", i, match.PathExact)
+				}
+			}
+			if match.PathPrefix != "" {
+				eligibleForPrefixRewrite = true
+				pathParts++
+				if !strings.HasPrefix(match.PathPrefix, "/") {
+					return fmt.Errorf("Route[%d] PathPrefix doesn't start with '/': %q", i, match.PathPrefix)
+				}
+			}
+			if match.PathRegex != "" {
+				pathParts++
+			}
+			if pathParts > 1 {
+				return fmt.Errorf("Route[%d] should only contain at most one of PathExact, PathPrefix, or PathRegex", i)
+			}
+
+			for j, hdr := range match.Header {
+				if hdr.Name == "" {
+					return fmt.Errorf("Route[%d] Header[%d] missing required Name field", i, j)
+				}
+				if countSet(hdr.Present, hdr.Exact, hdr.Prefix, hdr.Suffix, hdr.Regex) != 1 {
+					return fmt.Errorf("Route[%d] Header[%d] should only contain one of Present, Exact, Prefix, Suffix, or Regex", i, j)
+				}
+			}
+
+			for j, qm := range match.QueryParam {
+				if qm.Name == "" {
+					return fmt.Errorf("Route[%d] QueryParam[%d] missing required Name field", i, j)
+				}
+				if countSet(qm.Present, qm.Exact, qm.Regex) != 1 {
+					return fmt.Errorf("Route[%d] QueryParam[%d] should only contain one of Present, Exact, or Regex", i, j)
+				}
+			}
+
+			if err := validateHTTPMethods(match.Methods); err != nil {
+				return fmt.Errorf("Route[%d] %v", i, err)
+			}
+		}
+
+		if route.Destination != nil {
+			if route.Destination.PrefixRewrite != "" && !eligibleForPrefixRewrite {
+				return fmt.Errorf("Route[%d] cannot make use of PrefixRewrite without configuring either PathExact or PathPrefix", i)
+			}
+			for _, r := range route.Destination.RetryOn {
+				if !isValidRetryCondition(r) {
+					return fmt.Errorf("Route[%d] contains an invalid retry condition: %q", i, r)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate validates the service-splitter config entry client-side. See
+// ValidateConfigEntry.
+func (e *ServiceSplitterConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(e.Kind, ServiceSplitter); err != nil {
+		return err
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if len(e.Splits) == 0 {
+		return fmt.Errorf("no splits configured")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	type splitKey struct {
+		Service, ServiceSubset, Namespace, Partition string
+	}
+	found := make(map[splitKey]struct{})
+	sumScaled := 0
+	for _, split := range e.Splits {
+		key := splitKey{
+			Service:       split.Service,
+			ServiceSubset: split.ServiceSubset,
+			Namespace:     split.Namespace,
+			Partition:     split.Partition,
+		}
+		if key.Service == "" {
+			key.Service = e.Name
+		}
+		if _, ok := found[key]; ok {
+			return fmt.Errorf(
+				"split destination occurs more than once: service=%q, subset=%q, namespace=%q, partition=%q",
+				key.Service, key.ServiceSubset, key.Namespace, key.Partition,
+			)
+		}
+		found[key] = struct{}{}
+
+		// The smallest weight the servers represent is .01%.
+		sumScaled += int(math.Round(float64(split.Weight * 100.0)))
+	}
+	if sumScaled != 100*100 {
+		return fmt.Errorf("the sum of all split weights must be 100, not %f", float32(sumScaled)/100)
+	}
+	return nil
+}
+
+// Validate validates the service-resolver config entry client-side. See
+// ValidateConfigEntry. The subset filters are only validated by the servers.
+func (e *ServiceResolverConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(e.Kind, ServiceResolver); err != nil {
+		return err
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	for name, subset := range e.Subsets {
+		if name == "" {
+			return fmt.Errorf("Subset defined with empty name")
+		}
+		if len(name) > 63 || !validServiceSubsetName.MatchString(name) {
+			return fmt.Errorf("Subset %q is invalid: must be 63 characters or fewer, begin or end with lower case alphanumeric characters, and contain lower case alphanumeric characters or '-' in between", name)
+		}
+		if err := subset.validateMeta(); err != nil {
+			return fmt.Errorf("Meta for subset %q is invalid: %v", name, err)
+		}
+	}
+	isSubset := func(subset string) bool {
+		_, ok := e.Subsets[subset]
+		return ok
+	}
+	inDefaultPartition := e.Partition == "" || e.Partition == "default"
+
+	if e.DefaultSubset != "" && !isSubset(e.DefaultSubset) {
+		return fmt.Errorf("DefaultSubset %q is not a valid subset", e.DefaultSubset)
+	}
+
+	if r := e.Redirect; r != nil {
+		if !inDefaultPartition && r.Datacenter != "" {
+			return fmt.Errorf("Cross-datacenter redirect is only supported in the default partition")
+		}
+		if len(e.Failover) > 0 {
+			return fmt.Errorf("Redirect and Failover cannot both be set")
+		}
+		if r.Service == "" && r.ServiceSubset == "" && r.Namespace == "" && r.Partition == "" && r.Datacenter == "" && r.Peer == "" {
+			return fmt.Errorf("Redirect is empty")
+		}
+
+		switch {
+		case r.Peer != "" && r.ServiceSubset != "":
+			return fmt.Errorf("Redirect.Peer cannot be set with Redirect.ServiceSubset")
+		case r.Peer != "" && r.Partition != "":
+			return fmt.Errorf("Redirect.Partition cannot be set with Redirect.Peer")
+		case r.Peer != "" && r.Datacenter != "":
+			return fmt.Errorf("Redirect.Peer cannot be set with Redirect.Datacenter")
+		case r.Service == "":
+			if r.ServiceSubset != "" {
+				return fmt.Errorf("Redirect.ServiceSubset defined without Redirect.Service")
+			}
+			if r.Namespace != "" {
+				return fmt.Errorf("Redirect.Namespace defined without Redirect.Service")
+			}
+			if r.Partition != "" {
+				return fmt.Errorf("Redirect.Partition defined without Redirect.Service")
+			}
+			if r.Peer != "" {
+				return fmt.Errorf("Redirect.Peer defined without Redirect.Service")
+			}
+		case r.ServiceSubset != "" && r.Service == e.Name:
+			if !isSubset(r.ServiceSubset) {
+				return fmt.Errorf("Redirect.ServiceSubset %q is not a valid subset of %q", r.ServiceSubset, e.Name)
+			}
+		}
+	}
+
+	for subset, f := range e.Failover {
+		errorPrefix := fmt.Sprintf("Bad Failover[%q]: ", subset)
+
+		if !inDefaultPartition && len(f.Datacenters) != 0 {
+			return fmt.Errorf("Cross-datacenter failover is only supported in the default partition")
+		}
+		if subset != configEntryWildcard && !isSubset(subset) {
+			return fmt.Errorf(errorPrefix + "not a valid subset subset")
+		}
+		if f.Service == "" && f.ServiceSubset == "" && f.Namespace == "" && len(f.Datacenters) == 0 && len(f.Targets) == 0 && f.SamenessGroup == "" {
+			return fmt.Errorf(errorPrefix + "one of Service, ServiceSubset, Namespace, Targets, Datacenters, or SamenessGroup is required")
+		}
+		if f.ServiceSubset != "" && (f.Service == "" || f.Service == e.Name) && !isSubset(f.ServiceSubset) {
+			return fmt.Errorf("%sServiceSubset %q is not a valid subset of %q", errorPrefix, f.ServiceSubset, f.Service)
+		}
+		if len(f.Targets) != 0 {
+			switch {
+			case len(f.Datacenters) != 0:
+				return fmt.Errorf(errorPrefix + "Targets cannot be set with Datacenters")
+			case f.ServiceSubset != "":
+				return fmt.Errorf(errorPrefix + "Targets cannot be set with ServiceSubset")
+			case f.Service != "":
+				return fmt.Errorf(errorPrefix + "Targets cannot be set with Service")
+			}
+		}
+		if f.SamenessGroup != "" && (f.Service != "" || f.ServiceSubset != "" || len(f.Datacenters) != 0 || len(f.Targets) != 0) {
+			return fmt.Errorf(errorPrefix + "SamenessGroup cannot be set with Service, ServiceSubset, Datacenters, or Targets")
+		}
+
+		for i, target := range f.Targets {
+			errorPrefix := fmt.Sprintf("Bad Failover[%q].Targets[%d]: ", subset, i)
+			switch {
+			case target.Peer != "" && target.ServiceSubset != "":
+				return fmt.Errorf(errorPrefix + "Peer cannot be set with ServiceSubset")
+			case target.Peer != "" && target.Partition != "":
+				return fmt.Errorf(errorPrefix + "Partition cannot be set with Peer")
+			case target.Peer != "" && target.Datacenter != "":
+				return fmt.Errorf(errorPrefix + "Peer cannot be set with Datacenter")
+			case target.Partition != "" && target.Datacenter != "":
+				return fmt.Errorf(errorPrefix + "Partition cannot be set with Datacenter")
+			case target.ServiceSubset != "" && (target.Service == "" || target.Service == e.Name):
+				if !isSubset(target.ServiceSubset) {
+					return fmt.Errorf("%sServiceSubset %q is not a valid subset of %q", errorPrefix, target.ServiceSubset, e.Name)
+				}
+			}
+		}
+
+		for _, dc := range f.Datacenters {
+			if dc == "" {
+				return fmt.Errorf("Bad Failover[%q].Datacenters: found empty datacenter", subset)
+			}
+		}
+	}
+
+	if e.ConnectTimeout < 0 {
+		return fmt.Errorf("Bad ConnectTimeout '%s', must be >= 0", e.ConnectTimeout)
+	}
+
+	if lb := e.LoadBalancer; lb != nil {
+		if !validLoadBalancerPolicies[lb.Policy] {
+			return fmt.Errorf("Bad LoadBalancer policy: %q is not supported", lb.Policy)
+		}
+		if lb.Policy != "ring_hash" && lb.RingHashConfig != nil {
+			return fmt.Errorf("Bad LoadBalancer configuration. "+
+				"RingHashConfig specified for incompatible load balancing policy %q", lb.Policy)
+		}
+		if lb.Policy != "least_request" && lb.LeastRequestConfig != nil {
+			return fmt.Errorf("Bad LoadBalancer configuration. "+
+				"LeastRequestConfig specified for incompatible load balancing policy %q", lb.Policy)
+		}
+		hashBased := lb.Policy == "ring_hash" || lb.Policy == "maglev"
+		if !hashBased && len(lb.HashPolicies) > 0 {
+			return fmt.Errorf("Bad LoadBalancer configuration: "+
+				"HashPolicies specified for non-hash-based Policy: %q", lb.Policy)
+		}
+
+		for i, hp := range lb.HashPolicies {
+			if hp.Field != "" && !validHashPolicyFields[hp.Field] {
+				return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: %q is not a supported field", i, hp.Field)
+			}
+			if hp.SourceIP && hp.Field != "" {
+				return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: "+
+					"A single hash policy cannot hash both a source address and a %q", i, hp.Field)
+			}
+			if hp.SourceIP && hp.FieldValue != "" {
+				return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: "+
+					"A FieldValue cannot be specified when hashing SourceIP", i)
+			}
+			if hp.Field != "" && hp.FieldValue == "" {
+				return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: Field %q was specified without a FieldValue", i, hp.Field)
+			}
+			if hp.FieldValue != "" && hp.Field == "" {
+				return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: FieldValue requires a Field to apply to", i)
+			}
+			if hp.CookieConfig != nil {
+				if hp.Field != "cookie" {
+					return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: cookie_config provided for %q", i, hp.Field)
+				}
+				if hp.CookieConfig.Session && hp.CookieConfig.TTL != 0 {
+					return fmt.Errorf("Bad LoadBalancer HashPolicy[%d]: a session cookie cannot have an associated TTL", i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate validates the service-intentions config entry client-side. See
+// ValidateConfigEntry.
+func (e *ServiceIntentionsConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(e.Kind, ServiceIntentions); err != nil {
+		return err
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateIntentionWildcards(e.Name, e.Namespace, e.Partition, ""); err != nil {
+		return err
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+	if len(e.Sources) == 0 {
+		return fmt.Errorf("At least one source is required")
+	}
+
+	type sourceKey struct {
+		Peer, Partition, Namespace, Name string
+	}
+	seen := make(map[sourceKey]struct{})
+	for i, src := range e.Sources {
+		if src == nil {
+			return fmt.Errorf("Sources[%d] is nil", i)
+		}
+		if src.Name == "" {
+			return fmt.Errorf("Sources[%d].Name is required", i)
+		}
+		if err := validateIntentionWildcards(src.Name, src.Namespace, src.Partition, src.Peer); err != nil {
+			return fmt.Errorf("Sources[%d].%v", i, err)
+		}
+		if src.Peer != "" && src.Partition != "" {
+			return fmt.Errorf("Sources[%d].Peer: cannot set Peer and Partition at the same time.", i)
+		}
+		if len(src.Description) > configEntryMetaValueMaxLength {
+			return fmt.Errorf("Sources[%d].Description exceeds maximum length %d", i, configEntryMetaValueMaxLength)
+		}
+		if len(src.LegacyMeta) > 0 {
+			return fmt.Errorf("Sources[%d].LegacyMeta must be omitted", i)
+		}
+		if src.LegacyCreateTime != nil {
+			return fmt.Errorf("Sources[%d].LegacyCreateTime must be omitted", i)
+		}
+		if src.LegacyUpdateTime != nil {
+			return fmt.Errorf("Sources[%d].LegacyUpdateTime must be omitted", i)
+		}
+		if src.LegacyID != "" {
+			return fmt.Errorf("Sources[%d].LegacyID must be omitted", i)
+		}
+
+		if len(src.Permissions) == 0 && !isValidIntentionAction(src.Action) {
+			return fmt.Errorf("Sources[%d].Action must be set to 'allow' or 'deny'", i)
+		}
+		if len(src.Permissions) > 0 && src.Action != "" {
+			return fmt.Errorf("Sources[%d].Action must be omitted if Permissions are specified", i)
+		}
+		if e.Name == configEntryWildcard && len(src.Permissions) > 0 {
+			return fmt.Errorf("Sources[%d].Permissions cannot be specified on intentions with wildcarded destinations", i)
+		}
+		if src.Type != "" && src.Type != IntentionSourceConsul {
+			return fmt.Errorf("Sources[%d].Type must be set to 'consul'", i)
+		}
+
+		for j, perm := range src.Permissions {
+			if err := perm.validate(); err != nil {
+				return fmt.Errorf("Sources[%d].Permissions[%d]%v", i, j, err)
+			}
+		}
+
+		key := sourceKey{Peer: src.Peer, Partition: src.Partition, Namespace: src.Namespace, Name: src.Name}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("Sources[%d] defines %q more than once", i, src.Name)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// validate validates the permission, the errors it returns start with the
+// name of the invalid field.
+func (p *IntentionPermission) validate() error {
+	if p == nil {
+		return fmt.Errorf(" is nil")
+	}
+	if !isValidIntentionAction(p.Action) {
+		return fmt.Errorf(".Action must be set to 'allow' or 'deny'")
+	}
+	if p.HTTP == nil {
+		return fmt.Errorf(".HTTP is required")
+	}
+
+	pathParts := 0
+	if p.HTTP.PathExact != "" {
+		pathParts++
+		if !strings.HasPrefix(p.HTTP.PathExact, "/") {
+			return fmt.Errorf(".HTTP.PathExact doesn't start with '/': %q", p.HTTP.PathExact)
+		}
+	}
+	if p.HTTP.PathPrefix != "" {
+		pathParts++
+		if !strings.HasPrefix(p.HTTP.PathPrefix, "/") {
+			return fmt.Errorf(".HTTP.PathPrefix doesn't start with '/': %q", p.HTTP.PathPrefix)
+		}
+	}
+	if p.HTTP.PathRegex != "" {
+		pathParts++
+	}
+	if pathParts > 1 {
+		return fmt.Errorf(".HTTP should only contain at most one of PathExact, PathPrefix, or PathRegex")
+	}
+
+	for k, hdr := range p.HTTP.Header {
+		if hdr.Name == "" {
+			return fmt.Errorf(".HTTP.Header[%d] missing required Name field", k)
+		}
+		if countSet(hdr.Present, hdr.Exact, hdr.Prefix, hdr.Suffix, hdr.Regex) != 1 {
+			return fmt.Errorf(".HTTP.Header[%d] should only contain one of Present, Exact, Prefix, Suffix, or Regex", k)
+		}
+	}
+	if err := validateHTTPMethods(p.HTTP.Methods); err != nil {
+		return fmt.Errorf(".HTTP.%v", err)
+	}
+
+	if pathParts == 0 && len(p.HTTP.Header) == 0 && len(p.HTTP.Methods) == 0 {
+		return fmt.Errorf(".HTTP should not be empty")
+	}
+	return nil
+}
+
+// Validate validates the exported-services config entry client-side. See
+// ValidateConfigEntry.
+func (e *ExportedServicesConfigEntry) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	for i, svc := range e.Services {
+		if svc.Name == "" {
+			return fmt.Errorf("Services[%d]: service name cannot be empty", i)
+		}
+		if svc.Namespace == configEntryWildcard && svc.Name != configEntryWildcard {
+			return fmt.Errorf("Services[%d]: service name must be wildcard if namespace is wildcard", i)
+		}
+		if len(svc.Exclude) > 0 && svc.Name != configEntryWildcard {
+			return fmt.Errorf("Services[%d]: service name must be wildcard if services are excluded", i)
+		}
+		for j, name := range svc.Exclude {
+			if name == "" || name == configEntryWildcard {
+				return fmt.Errorf("Services[%d].Exclude[%d]: excluded service name must be a non-empty, non-wildcard name", i, j)
+			}
+		}
+		if len(svc.Consumers) == 0 {
+			return fmt.Errorf("Services[%d]: must have at least one consumer", i)
+		}
+		for j, consumer := range svc.Consumers {
+			if consumer.Peer != "" && consumer.Partition != "" {
+				return fmt.Errorf("Services[%d].Consumers[%d]: must define at most one of Peer or Partition", i, j)
+			}
+			if consumer.Partition == configEntryWildcard {
+				return fmt.Errorf("Services[%d].Consumers[%d]: exporting to all partitions (wildcard) is not supported", i, j)
+			}
+			if consumer.Peer == configEntryWildcard {
+				return fmt.Errorf("Services[%d].Consumers[%d]: exporting to all peers (wildcard) is not supported", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate validates the ingress-gateway config entry client-side. See
+// ValidateConfigEntry.
+func (g *IngressGatewayConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(g.Kind, IngressGateway); err != nil {
+		return err
+	}
+	if g.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(g.Meta); err != nil {
+		return err
+	}
+
+	declaredPorts := make(map[int]bool)
+	for _, listener := range g.Listeners {
+		if declaredPorts[listener.Port] {
+			return fmt.Errorf("port %d declared on two listeners", listener.Port)
+		}
+		declaredPorts[listener.Port] = true
+
+		if !validIngressListenerProtocols[listener.Protocol] {
+			return fmt.Errorf("protocol must be 'tcp', 'http', 'http2', or 'grpc'. '%s' is an unsupported protocol", listener.Protocol)
+		}
+		if len(listener.Services) == 0 {
+			return fmt.Errorf("No service declared for listener with port %d", listener.Port)
+		}
+		if listener.Protocol == "tcp" && len(listener.Services) > 1 {
+			return fmt.Errorf("Multiple services per listener are only supported for L7 protocols, 'http', 'grpc' and 'http2' (listener on port %d)",
+				listener.Port)
+		}
+
+		declaredHosts := make(map[string]bool)
+		for _, s := range listener.Services {
+			if listener.Protocol == "tcp" {
+				if s.Name == configEntryWildcard {
+					return fmt.Errorf("Wildcard service name is only valid for protocol = 'http' (listener on port %d)", listener.Port)
+				}
+				if len(s.Hosts) != 0 {
+					return fmt.Errorf("Associating hosts to a service is not supported for the %s protocol (listener on port %d)", listener.Protocol, listener.Port)
+				}
+			}
+			if s.Name == "" {
+				return fmt.Errorf("Service name cannot be blank (listener on port %d)", listener.Port)
+			}
+			if s.Name == configEntryWildcard && len(s.Hosts) != 0 {
+				return fmt.Errorf("Associating hosts to a wildcard service is not supported (listener on port %d)", listener.Port)
+			}
+			if s.Namespace == configEntryWildcard {
+				return fmt.Errorf("Wildcard namespace is not supported for ingress services (listener on port %d)", listener.Port)
+			}
+			for _, h := range s.Hosts {
+				if declaredHosts[h] {
+					return fmt.Errorf("Hosts must be unique within a specific listener (listener on port %d)", listener.Port)
+				}
+				declaredHosts[h] = true
+			}
+		}
+	}
+	return nil
+}
+
+// Validate validates the terminating-gateway config entry client-side. See
+// ValidateConfigEntry.
+func (g *TerminatingGatewayConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(g.Kind, TerminatingGateway); err != nil {
+		return err
+	}
+	if g.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(g.Meta); err != nil {
+		return err
+	}
+
+	type serviceKey struct {
+		Namespace, Name string
+	}
+	seen := make(map[serviceKey]bool)
+	for _, svc := range g.Services {
+		if svc.Name == "" {
+			return fmt.Errorf("Service name cannot be blank.")
+		}
+		if svc.Namespace == configEntryWildcard {
+			return fmt.Errorf("Wildcard namespace is not supported for terminating gateway services")
+		}
+		key := serviceKey{Namespace: svc.Namespace, Name: svc.Name}
+		if seen[key] {
+			return fmt.Errorf("Service %q was specified more than once within a namespace", svc.Name)
+		}
+		seen[key] = true
+
+		// Specifying only a CAFile is allowed for one-way TLS.
+		if (svc.CertFile != "" || svc.KeyFile != "") && (svc.CAFile == "" || svc.CertFile == "" || svc.KeyFile == "") {
+			return fmt.Errorf("Service %q must have a CertFile, CAFile, and KeyFile specified for TLS origination", svc.Name)
+		}
+	}
+	return nil
+}
+
+// Validate validates the sameness-group config entry client-side. See
+// ValidateConfigEntry.
+func (s *SamenessGroupConfigEntry) Validate() error {
+	if err := validateConfigEntryKind(s.Kind, SamenessGroup); err != nil {
+		return err
+	}
+	if s.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if s.Name == configEntryWildcard {
+		return fmt.Errorf("Name must not be a wildcard")
+	}
+	if err := validateConfigEntryMeta(s.Meta); err != nil {
+		return err
+	}
+	if len(s.Members) == 0 {
+		return fmt.Errorf("must have at least one member")
+	}
+	peers := make(map[string]struct{}, len(s.Members))
+	for i, member := range s.Members {
+		if member.Peer == "" {
+			return fmt.Errorf("Members[%d]: Peer is required", i)
+		}
+		if member.Peer == configEntryWildcard {
+			return fmt.Errorf("Members[%d]: Peer must not be a wildcard", i)
+		}
+		if _, ok := peers[member.Peer]; ok {
+			return fmt.Errorf("Members[%d]: peer %q is already a member", i, member.Peer)
+		}
+		peers[member.Peer] = struct{}{}
+	}
+	return nil
+}
+
+// validateMeta validates the service meta key/value pairs of the subset, which
+// must be valid service meta and representable in a filter expression.
+func (s ServiceResolverSubset) validateMeta() error {
+	for key, value := range s.Meta {
+		var err error
+		switch {
+		case key == "":
+			err = fmt.Errorf("Key cannot be blank")
+		case !validServiceMetaKey.MatchString(key):
+			err = fmt.Errorf("Key contains invalid characters")
+		case len(key) > serviceMetaKeyMaxLength:
+			err = fmt.Errorf("Key is too long (limit: %d characters)", serviceMetaKeyMaxLength)
+		case len(value) > serviceMetaValueMaxLength:
+			err = fmt.Errorf("Value is too long (limit: %d characters)", serviceMetaValueMaxLength)
+		case strings.Contains(value, `"`) && strings.Contains(value, "`"):
+			err = fmt.Errorf("Value cannot contain both double quotes and backticks")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid meta pair (%q, %q): %v", key, value, err)
+		}
+	}
+	return nil
+}
+
+func validateConfigEntryKind(kind, expected string) error {
+	if kind != expected {
+		return fmt.Errorf("Kind must be %q, got %q", expected, kind)
+	}
+	return nil
+}
+
+func validateConfigEntryMeta(meta map[string]string) error {
+	if len(meta) > configEntryMetaMaxKeyPairs {
+		return fmt.Errorf("Meta exceeds maximum element count %d", configEntryMetaMaxKeyPairs)
+	}
+	for k, v := range meta {
+		if len(k) > configEntryMetaKeyMaxLength {
+			return fmt.Errorf("Meta key %q exceeds maximum length %d", k, configEntryMetaKeyMaxLength)
+		}
+		if len(v) > configEntryMetaValueMaxLength {
+			return fmt.Errorf("Meta value for key %q exceeds maximum length %d", k, configEntryMetaValueMaxLength)
+		}
+	}
+	return nil
+}
+
+func validateIntentionWildcards(name, namespace, partition, peer string) error {
+	if namespace != configEntryWildcard && strings.Contains(namespace, configEntryWildcard) {
+		return fmt.Errorf("Namespace: wildcard character '*' cannot be used with partial values")
+	}
+	if name != configEntryWildcard {
+		if strings.Contains(name, configEntryWildcard) {
+			return fmt.Errorf("Name: wildcard character '*' cannot be used with partial values")
+		}
+		if namespace == configEntryWildcard {
+			return fmt.Errorf("Name: exact value cannot follow wildcard namespace")
+		}
+	}
+	if strings.Contains(partition, configEntryWildcard) {
+		return fmt.Errorf("Partition: cannot use wildcard '*' in partition")
+	}
+	if strings.Contains(peer, configEntryWildcard) {
+		return fmt.Errorf("Peer: cannot use wildcard '*' in peer")
+	}
+	return nil
+}
+
+func validateHTTPMethods(methods []string) error {
+	found := make(map[string]struct{})
+	for _, m := range methods {
+		switch m {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+			http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		default:
+			return fmt.Errorf("Methods contains an invalid method %q", m)
+		}
+		if _, ok := found[m]; ok {
+			return fmt.Errorf("Methods contains %q more than once", m)
+		}
+		found[m] = struct{}{}
+	}
+	return nil
+}
+
+func isValidRetryCondition(retryOn string) bool {
+	switch retryOn {
+	case "5xx", "gateway-error", "reset", "connect-failure", "envoy-ratelimited", "retriable-4xx",
+		"refused-stream", "cancelled", "deadline-exceeded", "internal", "resource-exhausted", "unavailable":
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidIntentionAction(action IntentionAction) bool {
+	return action == IntentionActionAllow || action == IntentionActionDeny
+}
+
+func isValidConnectionBalance(s string) bool {
+	return s == "" || s == "exact_balance"
+}
+
+// countSet returns how many of the values are set, that is true or non-empty.
+func countSet(present bool, values ...string) int {
+	n := 0
+	if present {
+		n++
+	}
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
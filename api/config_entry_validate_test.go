@@ -0,0 +1,342 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigEntry(t *testing.T) {
+	cases := map[string]struct {
+		entry ConfigEntry
+		err   string
+	}{
+		"nil": {
+			entry: (*ServiceConfigEntry)(nil),
+			err:   "config entry is nil",
+		},
+		"unknown kind": {
+			entry: &ServiceConfigEntry{Kind: "service-default", Name: "web"},
+			err:   "invalid config entry kind: service-default",
+		},
+		"kind of another type": {
+			entry: &APIGatewayConfigEntry{Kind: HTTPRoute, Name: "gw"},
+			err:   `Kind "http-route" doesn't match the config entry type *api.APIGatewayConfigEntry`,
+		},
+		"meta too long": {
+			entry: &HTTPRouteConfigEntry{Kind: HTTPRoute, Name: "route", Meta: map[string]string{"k": strings.Repeat("v", 513)}},
+			err:   `Meta value for key "k" exceeds maximum length 512`,
+		},
+		"name required": {
+			entry: &TCPRouteConfigEntry{Kind: TCPRoute},
+			err:   "Name is required",
+		},
+		"service-defaults wildcard": {
+			entry: &ServiceConfigEntry{Kind: ServiceDefaults, Name: "*"},
+			err:   "service-defaults name must be the name of a service, and not a wildcard",
+		},
+		"service-defaults upstream override without name": {
+			entry: &ServiceConfigEntry{
+				Kind:           ServiceDefaults,
+				Name:           "web",
+				UpstreamConfig: &UpstreamConfiguration{Overrides: []*UpstreamConfig{{Protocol: "http"}}},
+			},
+			err: "error in upstream override for : Name is required",
+		},
+		"service-defaults upstream defaults max concurrent streams": {
+			entry: &ServiceConfigEntry{
+				Kind:           ServiceDefaults,
+				Name:           "web",
+				UpstreamConfig: &UpstreamConfiguration{Defaults: &UpstreamConfig{MaxConcurrentStreams: -1}},
+			},
+			err: "error in upstream defaults: max concurrent streams cannot be negative",
+		},
+		"service-defaults upstream connection pool": {
+			entry: &ServiceConfigEntry{
+				Kind:                   ServiceDefaults,
+				Name:                   "web",
+				UpstreamConnectionPool: &UpstreamConnectionPool{MaxRequestsPerConnection: -1},
+			},
+			err: "invalid upstream connection pool: max requests per connection cannot be negative",
+		},
+		"service-defaults destination port": {
+			entry: &ServiceConfigEntry{
+				Kind:        ServiceDefaults,
+				Name:        "external",
+				Destination: &DestinationConfig{Addresses: []string{"example.com"}},
+			},
+			err: "Invalid Port number 0",
+		},
+		"service-defaults retry policy without conditions": {
+			entry: &ServiceConfigEntry{
+				Kind:        ServiceDefaults,
+				Name:        "web",
+				RetryPolicy: &RouteRetryPolicy{NumRetries: 3},
+			},
+			err: "invalid retry policy: at least one of RetryOn or RetryOnStatusCodes must be set",
+		},
+		"service-defaults retry policy condition": {
+			entry: &ServiceConfigEntry{
+				Kind:        ServiceDefaults,
+				Name:        "web",
+				RetryPolicy: &RouteRetryPolicy{RetryOn: []string{"5xx", "timeout"}},
+			},
+			err: `invalid retry policy: invalid retry condition: "timeout"`,
+		},
+		"proxy-defaults retry policy per try timeout": {
+			entry: &ProxyConfigEntry{
+				Kind:        ProxyDefaults,
+				Name:        ProxyConfigGlobal,
+				RetryPolicy: &RouteRetryPolicy{RetryOnStatusCodes: []uint32{503}, PerTryTimeout: -time.Second},
+			},
+			err: "invalid retry policy: PerTryTimeout must be greater than or equal to 0s, got -1s",
+		},
+		"proxy-defaults name": {
+			entry: &ProxyConfigEntry{Kind: ProxyDefaults, Name: "web"},
+			err:   `invalid name ("web"), only "global" is supported`,
+		},
+		"proxy-defaults without name": {
+			entry: &ProxyConfigEntry{Kind: ProxyDefaults},
+		},
+		"service-router prefix rewrite without path": {
+			entry: &ServiceRouterConfigEntry{
+				Kind: ServiceRouter,
+				Name: "web",
+				Routes: []ServiceRoute{{
+					Match:       &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{Methods: []string{"GET"}}},
+					Destination: &ServiceRouteDestination{PrefixRewrite: "/"},
+				}},
+			},
+			err: "Route[0] cannot make use of PrefixRewrite without configuring either PathExact or PathPrefix",
+		},
+		"service-router invalid method": {
+			entry: &ServiceRouterConfigEntry{
+				Kind: ServiceRouter,
+				Name: "web",
+				Routes: []ServiceRoute{{
+					Match: &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{Methods: []string{"FETCH"}}},
+				}},
+			},
+			err: `Route[0] Methods contains an invalid method "FETCH"`,
+		},
+		"service-splitter weights": {
+			entry: &ServiceSplitterConfigEntry{
+				Kind:   ServiceSplitter,
+				Name:   "web",
+				Splits: []ServiceSplit{{Weight: 90, ServiceSubset: "v1"}, {Weight: 9.99, ServiceSubset: "v2"}},
+			},
+			err: "the sum of all split weights must be 100, not 99.989998",
+		},
+		"service-splitter duplicate": {
+			entry: &ServiceSplitterConfigEntry{
+				Kind:   ServiceSplitter,
+				Name:   "web",
+				Splits: []ServiceSplit{{Weight: 50}, {Weight: 50, Service: "web"}},
+			},
+			err: `split destination occurs more than once: service="web", subset="", namespace="", partition=""`,
+		},
+		"service-resolver redirect and failover": {
+			entry: &ServiceResolverConfigEntry{
+				Kind:     ServiceResolver,
+				Name:     "web",
+				Redirect: &ServiceResolverRedirect{Service: "api"},
+				Failover: map[string]ServiceResolverFailover{"*": {Datacenters: []string{"dc2"}}},
+			},
+			err: "Redirect and Failover cannot both be set",
+		},
+		"service-resolver unknown default subset": {
+			entry: &ServiceResolverConfigEntry{
+				Kind:          ServiceResolver,
+				Name:          "web",
+				DefaultSubset: "v1",
+			},
+			err: `DefaultSubset "v1" is not a valid subset`,
+		},
+		"service-resolver hash policies": {
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "web",
+				LoadBalancer: &LoadBalancer{
+					Policy:       "round_robin",
+					HashPolicies: []HashPolicy{{SourceIP: true}},
+				},
+			},
+			err: `Bad LoadBalancer configuration: HashPolicies specified for non-hash-based Policy: "round_robin"`,
+		},
+		"service-resolver subset meta key": {
+			entry: &ServiceResolverConfigEntry{
+				Kind:    ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]ServiceResolverSubset{"v2": {Meta: map[string]string{"version.major": "2"}}},
+			},
+			err: `Meta for subset "v2" is invalid: invalid meta pair ("version.major", "2"): Key contains invalid characters`,
+		},
+		"service-resolver subset meta value": {
+			entry: &ServiceResolverConfigEntry{
+				Kind:    ServiceResolver,
+				Name:    "web",
+				Subsets: map[string]ServiceResolverSubset{"v2": {Meta: map[string]string{"version": "`v2\""}}},
+			},
+			err: "Meta for subset \"v2\" is invalid: invalid meta pair (\"version\", \"`v2\\\"\"): Value cannot contain both double quotes and backticks",
+		},
+		"service-resolver valid": {
+			entry: &ServiceResolverConfigEntry{
+				Kind:           ServiceResolver,
+				Name:           "web",
+				DefaultSubset:  "v1",
+				Subsets:        map[string]ServiceResolverSubset{"v1": {Filter: "Service.Meta.version == v1"}},
+				Failover:       map[string]ServiceResolverFailover{"v1": {Targets: []ServiceResolverFailoverTarget{{Peer: "cluster-02"}}}},
+				ConnectTimeout: 5 * time.Second,
+			},
+		},
+		"service-intentions action": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind:    ServiceIntentions,
+				Name:    "db",
+				Sources: []*SourceIntention{{Name: "web"}},
+			},
+			err: "Sources[0].Action must be set to 'allow' or 'deny'",
+		},
+		"service-intentions empty permission": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "db",
+				Sources: []*SourceIntention{{
+					Name:        "web",
+					Permissions: []*IntentionPermission{{Action: IntentionActionAllow, HTTP: &IntentionHTTPPermission{}}},
+				}},
+			},
+			err: "Sources[0].Permissions[0].HTTP should not be empty",
+		},
+		"service-intentions duplicate source": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "db",
+				Sources: []*SourceIntention{
+					{Name: "web", Action: IntentionActionAllow},
+					{Name: "web", Action: IntentionActionDeny},
+				},
+			},
+			err: `Sources[1] defines "web" more than once`,
+		},
+		"service-intentions partial wildcard": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind:    ServiceIntentions,
+				Name:    "db",
+				Sources: []*SourceIntention{{Name: "web-*", Action: IntentionActionAllow}},
+			},
+			err: "Sources[0].Name: wildcard character '*' cannot be used with partial values",
+		},
+		"exported-services without consumer": {
+			entry: &ExportedServicesConfigEntry{
+				Name:     "default",
+				Services: []ExportedService{{Name: "web"}},
+			},
+			err: "Services[0]: must have at least one consumer",
+		},
+		"ingress-gateway tcp with multiple services": {
+			entry: &IngressGatewayConfigEntry{
+				Kind: IngressGateway,
+				Name: "ingress",
+				Listeners: []IngressListener{{
+					Port:     8080,
+					Protocol: "tcp",
+					Services: []IngressService{{Name: "web"}, {Name: "api"}},
+				}},
+			},
+			err: "Multiple services per listener are only supported for L7 protocols, 'http', 'grpc' and 'http2' (listener on port 8080)",
+		},
+		"terminating-gateway partial TLS": {
+			entry: &TerminatingGatewayConfigEntry{
+				Kind:     TerminatingGateway,
+				Name:     "terminating",
+				Services: []LinkedService{{Name: "billing", CertFile: "cert.pem"}},
+			},
+			err: `Service "billing" must have a CertFile, CAFile, and KeyFile specified for TLS origination`,
+		},
+		"sameness-group duplicate member": {
+			entry: &SamenessGroupConfigEntry{
+				Kind:    SamenessGroup,
+				Name:    "group",
+				Members: []SamenessGroupMember{{Peer: "dc2"}, {Peer: "dc2"}},
+			},
+			err: `Members[1]: peer "dc2" is already a member`,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := ValidateConfigEntry(tc.entry)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestConfigEntryBuilders(t *testing.T) {
+	defaults, err := NewServiceDefaults("web").
+		Protocol("http").
+		Meta("owner", "team-a").
+		UpstreamDefaults(UpstreamConfig{ConnectTimeoutMs: 1000}).
+		UpstreamOverride(UpstreamConfig{Name: "db", Protocol: "tcp"}).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, &ServiceConfigEntry{
+		Kind:     ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+		Meta:     map[string]string{"owner": "team-a"},
+		UpstreamConfig: &UpstreamConfiguration{
+			Defaults:  &UpstreamConfig{ConnectTimeoutMs: 1000},
+			Overrides: []*UpstreamConfig{{Name: "db", Protocol: "tcp"}},
+		},
+	}, defaults)
+
+	router, err := NewServiceRouter("web").
+		RoutePathPrefix("/admin", ServiceRouteDestination{Service: "admin"}).
+		RouteHeader("x-debug", "1", ServiceRouteDestination{ServiceSubset: "debug"}).
+		Build()
+	require.NoError(t, err)
+	require.Len(t, router.Routes, 2)
+	require.Equal(t, "/admin", router.Routes[0].Match.HTTP.PathPrefix)
+
+	_, err = NewServiceSplitter("web").Split(50, "", "v1").Split(40, "", "v2").Build()
+	require.EqualError(t, err, "the sum of all split weights must be 100, not 90.000000")
+
+	resolver, err := NewServiceResolver("web").
+		Subset("v1", "Service.Meta.version == v1", true).
+		DefaultSubset("v1").
+		ConnectTimeout(3 * time.Second).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, "v1", resolver.DefaultSubset)
+
+	intentions, err := NewServiceIntentions("db").
+		Allow("web").
+		Deny("*").
+		Permissions("api", &IntentionPermission{
+			Action: IntentionActionAllow,
+			HTTP:   &IntentionHTTPPermission{PathPrefix: "/v1", Methods: []string{"GET"}},
+		}).
+		Build()
+	require.NoError(t, err)
+	require.Len(t, intentions.Sources, 3)
+	require.NoError(t, ValidateConfigEntry(intentions))
+
+	_, err = NewExportedServices("default").ExportToPeers("web").Build()
+	require.EqualError(t, err, "Services[0]: must have at least one consumer")
+
+	exported, err := NewExportedServices("default").ExportToPeers("web", "cluster-02").Build()
+	require.NoError(t, err)
+	require.Equal(t, []ServiceConsumer{{Peer: "cluster-02"}}, exported.Services[0].Consumers)
+
+	proxy, err := NewProxyDefaults().Config("protocol", "http").Build()
+	require.NoError(t, err)
+	require.Equal(t, ProxyConfigGlobal, proxy.Name)
+}
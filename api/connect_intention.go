@@ -40,6 +40,10 @@ type Intention struct {
 	// same level of tenancy (partition is local to cluster, peer is remote).
 	SourcePeer string `json:",omitempty"`
 
+	// SourceSamenessGroup is the name of the sameness group of the source, if
+	// any. It is only set by intentions defined in config entries.
+	SourceSamenessGroup string `json:",omitempty"`
+
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 
@@ -53,6 +57,13 @@ type Intention struct {
 	// service-intentions config entry directly.
 	Permissions []*IntentionPermission `json:",omitempty"`
 
+	// DestinationPorts are the names of the ports of the destination service
+	// the intention applies to. It applies to all of them if it is empty.
+	//
+	// NOTE: This field is not editable unless editing the underlying
+	// service-intentions config entry directly.
+	DestinationPorts []string `json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `json:",omitempty"`
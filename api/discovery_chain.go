@@ -234,12 +234,14 @@ type DiscoveryTarget struct {
 	Namespace     string
 	Datacenter    string
 
-	MeshGateway    MeshGatewayConfig
-	Subset         ServiceResolverSubset
-	ConnectTimeout time.Duration
-	External       bool
-	SNI            string
-	Name           string
+	MeshGateway              MeshGatewayConfig
+	Subset                   ServiceResolverSubset
+	ConnectTimeout           time.Duration
+	MaxConcurrentStreams     int
+	MaxRequestsPerConnection int
+	External                 bool
+	SNI                      string
+	Name                     string
 }
 
 func (t *DiscoveryTarget) MarshalJSON() ([]byte, error) {
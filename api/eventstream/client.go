@@ -0,0 +1,102 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/proto-public/pbeventstream"
+)
+
+// NewClient returns a Client that opens the streams over a gRPC connection to
+// the gRPC port of a Consul server. The token of the subscriptions is sent in
+// the x-consul-token metadata.
+func NewClient(conn grpc.ClientConnInterface) Client {
+	return &client{client: pbeventstream.NewEventStreamServiceClient(conn)}
+}
+
+type client struct {
+	client pbeventstream.EventStreamServiceClient
+}
+
+func (c *client) Subscribe(ctx context.Context, req *Request) (Stream, error) {
+	if req.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-consul-token", req.Token)
+	}
+
+	stream, err := c.client.Subscribe(ctx, &pbeventstream.SubscribeRequest{
+		Topic:      string(req.Topic),
+		Key:        req.Key,
+		Datacenter: req.Datacenter,
+		Namespace:  req.Namespace,
+		Partition:  req.Partition,
+		PeerName:   req.PeerName,
+		Index:      req.Index,
+	})
+	if err != nil {
+		return nil, streamError(err)
+	}
+	return &clientStream{stream: stream}, nil
+}
+
+type clientStream struct {
+	stream pbeventstream.EventStreamService_SubscribeClient
+}
+
+func (s *clientStream) Recv() (*Event, error) {
+	event, err := s.stream.Recv()
+	if err != nil {
+		return nil, streamError(err)
+	}
+	return eventFromProto(event)
+}
+
+// streamError wraps the gRPC errors that the subscriptions handle differently
+// from a broken stream.
+func streamError(err error) error {
+	switch status.Code(err) {
+	case codes.Aborted:
+		return fmt.Errorf("%w: %v", ErrReset, err)
+	case codes.PermissionDenied, codes.Unauthenticated, codes.InvalidArgument, codes.Unimplemented:
+		return fmt.Errorf("%w: %v", ErrRefused, err)
+	}
+	return err
+}
+
+func eventFromProto(event *pbeventstream.Event) (*Event, error) {
+	out := &Event{Index: event.Index}
+	switch payload := event.Payload.(type) {
+	case *pbeventstream.Event_EndOfSnapshot:
+		out.EndOfSnapshot = payload.EndOfSnapshot
+	case *pbeventstream.Event_NewSnapshotToFollow:
+		out.NewSnapshotToFollow = payload.NewSnapshotToFollow
+	case *pbeventstream.Event_EventBatch:
+		out.Batch = make([]*Event, 0, len(payload.EventBatch.Events))
+		for _, e := range payload.EventBatch.Events {
+			converted, err := eventFromProto(e)
+			if err != nil {
+				return nil, err
+			}
+			out.Batch = append(out.Batch, converted)
+		}
+	case *pbeventstream.Event_ServiceHealth:
+		var entry api.ServiceEntry
+		if err := json.Unmarshal(payload.ServiceHealth.ServiceEntry, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode service entry: %w", err)
+		}
+		out.ServiceHealth = &ServiceHealthUpdate{Op: Op(payload.ServiceHealth.Op), ServiceEntry: &entry}
+	case *pbeventstream.Event_ConfigEntry:
+		entry, err := api.DecodeConfigEntryFromJSON(payload.ConfigEntry.ConfigEntry)
+		if err != nil {
+			return nil, err
+		}
+		out.ConfigEntry = &ConfigEntryUpdate{Op: Op(payload.ConfigEntry.Op), Entry: entry}
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package eventstream
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/proto-public/pbeventstream"
+)
+
+// testServer sends its events on every stream, then ends it with its error.
+type testServer struct {
+	events []*pbeventstream.Event
+	err    error
+
+	req   *pbeventstream.SubscribeRequest
+	token string
+}
+
+func (s *testServer) Subscribe(req *pbeventstream.SubscribeRequest, stream pbeventstream.EventStreamService_SubscribeServer) error {
+	s.req = req
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		if tokens := md.Get("x-consul-token"); len(tokens) > 0 {
+			s.token = tokens[0]
+		}
+	}
+	for _, e := range s.events {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func runTestServer(t *testing.T, srv *testServer) Client {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	pbeventstream.RegisterEventStreamServiceServer(grpcServer, srv)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewClient(conn)
+}
+
+func TestClient(t *testing.T) {
+	srv := &testServer{
+		events: []*pbeventstream.Event{
+			{
+				Index: 7,
+				Payload: &pbeventstream.Event_EventBatch{EventBatch: &pbeventstream.EventBatch{Events: []*pbeventstream.Event{
+					{
+						Index: 7,
+						Payload: &pbeventstream.Event_ServiceHealth{ServiceHealth: &pbeventstream.ServiceHealthUpdate{
+							Op:           "deregister",
+							ServiceEntry: []byte(`{"Node":{"Node":"node1","Address":"10.0.0.1"},"Service":{"ID":"web1","Service":"web","Port":8080}}`),
+						}},
+					},
+					{
+						Index: 7,
+						Payload: &pbeventstream.Event_ConfigEntry{ConfigEntry: &pbeventstream.ConfigEntryUpdate{
+							Op:          "upsert",
+							ConfigEntry: []byte(`{"Kind":"service-defaults","Name":"web","Protocol":"http"}`),
+						}},
+					},
+				}}},
+			},
+			{Index: 9, Payload: &pbeventstream.Event_EndOfSnapshot{EndOfSnapshot: true}},
+		},
+		err: status.Error(codes.Aborted, "reset"),
+	}
+	client := runTestServer(t, srv)
+
+	stream, err := client.Subscribe(context.Background(), &Request{
+		Topic: TopicServiceHealth,
+		Key:   "web",
+		Token: "secret",
+		Index: 3,
+	})
+	require.NoError(t, err)
+
+	out, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), out.Index)
+	require.Len(t, out.Batch, 2)
+
+	health := out.Batch[0].ServiceHealth
+	require.NotNil(t, health)
+	require.Equal(t, OpDeregister, health.Op)
+	require.Equal(t, "node1", health.ServiceEntry.Node.Node)
+	require.Equal(t, "10.0.0.1", health.ServiceEntry.Node.Address)
+	require.Equal(t, "web1", health.ServiceEntry.Service.ID)
+	require.Equal(t, 8080, health.ServiceEntry.Service.Port)
+
+	entry := out.Batch[1].ConfigEntry
+	require.NotNil(t, entry)
+	require.Equal(t, OpUpsert, entry.Op)
+	defaults, ok := entry.Entry.(*api.ServiceConfigEntry)
+	require.True(t, ok)
+	require.Equal(t, "web", defaults.Name)
+	require.Equal(t, "http", defaults.Protocol)
+
+	out, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, &Event{Index: 9, EndOfSnapshot: true}, out)
+
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, ErrReset)
+
+	require.Equal(t, "ServiceHealth", srv.req.Topic)
+	require.Equal(t, "web", srv.req.Key)
+	require.Equal(t, uint64(3), srv.req.Index)
+	require.Equal(t, "secret", srv.token)
+}
+
+func TestClient_Errors(t *testing.T) {
+	srv := &testServer{err: status.Error(codes.PermissionDenied, "denied")}
+	client := runTestServer(t, srv)
+
+	recvErr := func(t *testing.T) error {
+		stream, err := client.Subscribe(context.Background(), &Request{Topic: TopicServiceHealth})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		return err
+	}
+
+	require.ErrorIs(t, recvErr(t), ErrRefused)
+
+	srv.err = status.Error(codes.Unavailable, "leader lost")
+	err := recvErr(t)
+	require.False(t, errors.Is(err, ErrReset) || errors.Is(err, ErrRefused))
+}
@@ -0,0 +1,353 @@
+// Package eventstream is a client for the event streaming API of the Consul
+// servers. It subscribes to a topic, reconnects when the stream breaks and
+// hands the snapshots and the following events to typed callbacks, so that
+// consumers don't have to drive the stream themselves.
+//
+// The events carry the types of the api package. The stream itself is served
+// over gRPC, and the subscriptions are run with the Client returned by
+// NewClient for a connection to the gRPC port of a Consul server.
+package eventstream
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/api"
+)
+
+var (
+	// ErrReset is returned by Stream.Recv when the servers reset the stream,
+	// in which case the next stream starts over with a snapshot.
+	ErrReset = errors.New("event stream reset by the servers")
+
+	// ErrRefused is returned when the servers refuse the subscription, for
+	// example because the token is not allowed to read the topic. Retrying
+	// the subscription doesn't fix it.
+	ErrRefused = errors.New("subscription refused by the servers")
+)
+
+// Topic is a topic of the event stream.
+type Topic string
+
+const (
+	TopicServiceHealth        Topic = "ServiceHealth"
+	TopicServiceHealthConnect Topic = "ServiceHealthConnect"
+	TopicMeshConfig           Topic = "MeshConfig"
+	TopicServiceResolver      Topic = "ServiceResolver"
+	TopicIngressGateway       Topic = "IngressGateway"
+	TopicServiceIntentions    Topic = "ServiceIntentions"
+	TopicServiceDefaults      Topic = "ServiceDefaults"
+)
+
+// Op is the operation an event reports.
+type Op string
+
+const (
+	// OpRegister and OpDeregister are the operations of the ServiceHealth
+	// events.
+	OpRegister   Op = "register"
+	OpDeregister Op = "deregister"
+
+	// OpUpsert and OpDelete are the operations of the config entry events.
+	OpUpsert Op = "upsert"
+	OpDelete Op = "delete"
+)
+
+// Request is a request to open a stream of events.
+type Request struct {
+	Topic Topic
+
+	// Key restricts the stream to a single resource, or is empty for all the
+	// resources of the topic.
+	Key string
+
+	Datacenter string
+	Namespace  string
+	Partition  string
+	PeerName   string
+	Token      string
+
+	// Index is the index of the last event received, from which the stream
+	// is resumed, or 0 for a new stream that starts with a snapshot.
+	Index uint64
+}
+
+// Event is an event of the stream. Only one of its fields other than Index is
+// set.
+type Event struct {
+	Index uint64
+
+	// EndOfSnapshot marks the end of the events of a snapshot.
+	EndOfSnapshot bool
+
+	// NewSnapshotToFollow is sent when a stream could not be resumed, before
+	// the events of a new snapshot.
+	NewSnapshotToFollow bool
+
+	// Batch is a set of events that happened at the same index.
+	Batch []*Event
+
+	ServiceHealth *ServiceHealthUpdate
+	ConfigEntry   *ConfigEntryUpdate
+}
+
+// ServiceHealthUpdate is the payload of the events of the ServiceHealth and
+// ServiceHealthConnect topics.
+type ServiceHealthUpdate struct {
+	Op           Op
+	ServiceEntry *api.ServiceEntry
+}
+
+// ConfigEntryUpdate is the payload of the events of the config entry topics.
+type ConfigEntryUpdate struct {
+	Op    Op
+	Entry api.ConfigEntry
+}
+
+// Client opens the streams of a subscription.
+type Client interface {
+	Subscribe(ctx context.Context, req *Request) (Stream, error)
+}
+
+// Stream is a stream of events opened by a Client. Recv returns an error
+// matching ErrReset or ErrRefused with errors.Is for the corresponding
+// failures.
+type Stream interface {
+	Recv() (*Event, error)
+}
+
+// Handlers are the callbacks of a subscription. They are called from the
+// goroutine running Subscription.Run, one at a time and in the order of the
+// events. Only the handlers matching the topic of the subscription are used,
+// the others may be left nil.
+type Handlers struct {
+	// Reset is called before the events of a snapshot are replayed, which
+	// happens when the subscription starts and whenever the servers could not
+	// resume the stream where it stopped. Any state built from the previous
+	// events must be discarded.
+	Reset func()
+
+	// SnapshotDone is called once all the events of a snapshot have been
+	// replayed, with the index of the snapshot.
+	SnapshotDone func(index uint64)
+
+	// ServiceHealth is called for the events of the ServiceHealth and
+	// ServiceHealthConnect topics.
+	ServiceHealth func(index uint64, op Op, entry *api.ServiceEntry)
+
+	// ConfigEntry is called for the events of the config entry topics.
+	ConfigEntry func(index uint64, op Op, entry api.ConfigEntry)
+}
+
+// Subscription is a subscription to a topic of the event stream.
+type Subscription struct {
+	// Topic to subscribe to.
+	Topic Topic
+
+	// Key restricts the subscription to a single resource, for example the
+	// name of a service. It is required by the topics that do not support
+	// wildcard subscriptions. A wildcard subscription to TopicServiceHealth
+	// requires operator:read.
+	Key string
+
+	Datacenter string
+	Namespace  string
+	Partition  string
+	PeerName   string
+	Token      string
+
+	Handlers Handlers
+
+	// Logger is used to report the broken streams. It defaults to a null
+	// logger.
+	Logger hclog.Logger
+
+	// MinWait and MaxWait bound the backoff between the reconnection
+	// attempts, which doubles with each attempt that fails before receiving
+	// an event. They default to 200ms and a minute.
+	MinWait time.Duration
+	MaxWait time.Duration
+
+	// index is the index of the last event handed to the handlers, from which
+	// the stream is resumed after it breaks.
+	index uint64
+
+	// failures is the number of reconnection attempts since the last event.
+	failures uint
+}
+
+// ServiceHealth returns a subscription to the health of the instances of a
+// service, or of all the services when service is empty.
+func ServiceHealth(service string, handler func(index uint64, op Op, entry *api.ServiceEntry)) *Subscription {
+	return &Subscription{
+		Topic:    TopicServiceHealth,
+		Key:      service,
+		Handlers: Handlers{ServiceHealth: handler},
+	}
+}
+
+// ConfigEntries returns a subscription to a config entry topic such as
+// TopicServiceDefaults, restricted to the entry of the given name when name is
+// not empty.
+func ConfigEntries(topic Topic, name string, handler func(index uint64, op Op, entry api.ConfigEntry)) *Subscription {
+	return &Subscription{
+		Topic:    topic,
+		Key:      name,
+		Handlers: Handlers{ConfigEntry: handler},
+	}
+}
+
+// Index returns the index of the last event handed to the handlers.
+func (s *Subscription) Index() uint64 {
+	return s.index
+}
+
+// Run subscribes to the topic and calls the handlers until ctx is cancelled or
+// the servers refuse the subscription. Broken streams are retried with a
+// backoff and resumed from the last index when the servers allow it.
+func (s *Subscription) Run(ctx context.Context, client Client) error {
+	logger := s.Logger
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	for {
+		err := s.subscribeOnce(ctx, client)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, ErrRefused):
+			return err
+		}
+		logger.Warn("event stream broken, reconnecting",
+			"topic", s.Topic,
+			"key", s.Key,
+			"index", s.index,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.backoff()):
+		}
+	}
+}
+
+// backoff returns how long to wait before the next reconnection attempt.
+func (s *Subscription) backoff() time.Duration {
+	minWait, maxWait := s.MinWait, s.MaxWait
+	if minWait <= 0 {
+		minWait = 200 * time.Millisecond
+	}
+	if maxWait <= 0 {
+		maxWait = time.Minute
+	}
+
+	wait := maxWait
+	if s.failures < 32 && minWait<<s.failures < maxWait {
+		wait = minWait << s.failures
+	}
+	s.failures++
+
+	// The jitter spreads the reconnections of the clients of a server that
+	// went away.
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// subscribeOnce opens a stream and handles its events until it breaks.
+func (s *Subscription) subscribeOnce(ctx context.Context, client Client) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Subscribe(ctx, s.request())
+	if err != nil {
+		return err
+	}
+
+	// A fresh subscription starts with a snapshot, while a resumed one either
+	// continues with the following events or announces a new snapshot.
+	var snapshot []*Event
+	inSnapshot := s.index == 0
+	for {
+		event, err := stream.Recv()
+		switch {
+		case errors.Is(err, ErrReset):
+			// The next stream starts over with a snapshot.
+			s.index = 0
+			return err
+		case err != nil:
+			return err
+		}
+		s.failures = 0
+
+		switch {
+		case event.NewSnapshotToFollow:
+			inSnapshot = true
+			snapshot = nil
+		case event.EndOfSnapshot:
+			if s.Handlers.Reset != nil {
+				s.Handlers.Reset()
+			}
+			for _, e := range snapshot {
+				s.handle(e)
+			}
+			if s.Handlers.SnapshotDone != nil {
+				s.Handlers.SnapshotDone(event.Index)
+			}
+			inSnapshot = false
+			snapshot = nil
+			s.index = event.Index
+		case inSnapshot:
+			// The snapshot is buffered so that the handlers never see a
+			// partial one after Reset.
+			snapshot = append(snapshot, eventsFromEvent(event)...)
+		default:
+			for _, e := range eventsFromEvent(event) {
+				s.handle(e)
+			}
+			s.index = event.Index
+		}
+	}
+}
+
+func (s *Subscription) request() *Request {
+	return &Request{
+		Topic:      s.Topic,
+		Key:        s.Key,
+		Datacenter: s.Datacenter,
+		Namespace:  s.Namespace,
+		Partition:  s.Partition,
+		PeerName:   s.PeerName,
+		Token:      s.Token,
+		Index:      s.index,
+	}
+}
+
+// handle hands a single event to the handler of its payload. The events without
+// a matching handler are dropped.
+func (s *Subscription) handle(event *Event) {
+	switch {
+	case event.ServiceHealth != nil:
+		if s.Handlers.ServiceHealth != nil {
+			update := event.ServiceHealth
+			s.Handlers.ServiceHealth(event.Index, update.Op, update.ServiceEntry)
+		}
+	case event.ConfigEntry != nil:
+		if s.Handlers.ConfigEntry != nil {
+			update := event.ConfigEntry
+			s.Handlers.ConfigEntry(event.Index, update.Op, update.Entry)
+		}
+	}
+}
+
+func eventsFromEvent(event *Event) []*Event {
+	if event.Batch != nil {
+		return event.Batch
+	}
+	return []*Event{event}
+}
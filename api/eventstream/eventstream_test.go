@@ -0,0 +1,171 @@
+package eventstream
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// testClient replays a scripted stream for each call to Subscribe. A stream
+// ends with its error, or blocks until the context is cancelled when it has
+// none.
+type testClient struct {
+	streams  []testStream
+	requests []*Request
+}
+
+type testStream struct {
+	events []*Event
+	err    error
+}
+
+func (c *testClient) Subscribe(ctx context.Context, req *Request) (Stream, error) {
+	c.requests = append(c.requests, req)
+	if len(c.requests) > len(c.streams) {
+		return nil, fmt.Errorf("%w: no more streams", ErrRefused)
+	}
+	return &testSubscribeClient{ctx: ctx, stream: c.streams[len(c.requests)-1]}, nil
+}
+
+type testSubscribeClient struct {
+	ctx    context.Context
+	stream testStream
+}
+
+func (c *testSubscribeClient) Recv() (*Event, error) {
+	if len(c.stream.events) > 0 {
+		event := c.stream.events[0]
+		c.stream.events = c.stream.events[1:]
+		return event, nil
+	}
+	if c.stream.err != nil {
+		return nil, c.stream.err
+	}
+	<-c.ctx.Done()
+	return nil, c.ctx.Err()
+}
+
+func endOfSnapshot(index uint64) *Event {
+	return &Event{Index: index, EndOfSnapshot: true}
+}
+
+func serviceHealth(index uint64, op Op, node string) *Event {
+	return &Event{
+		Index: index,
+		ServiceHealth: &ServiceHealthUpdate{
+			Op: op,
+			ServiceEntry: &api.ServiceEntry{
+				Node:    &api.Node{Node: node},
+				Service: &api.AgentService{Service: "web"},
+			},
+		},
+	}
+}
+
+func TestSubscription_ServiceHealth(t *testing.T) {
+	client := &testClient{streams: []testStream{
+		{
+			events: []*Event{
+				serviceHealth(5, OpRegister, "node1"),
+				endOfSnapshot(5),
+				{
+					Index: 7,
+					Batch: []*Event{
+						serviceHealth(7, OpRegister, "node2"),
+						serviceHealth(7, OpDeregister, "node1"),
+					},
+				},
+			},
+			err: fmt.Errorf("leader lost"),
+		},
+		{
+			// The servers can't resume the stream at index 7.
+			events: []*Event{
+				{NewSnapshotToFollow: true},
+				serviceHealth(9, OpRegister, "node3"),
+				endOfSnapshot(9),
+			},
+			err: fmt.Errorf("%w: stream too slow", ErrReset),
+		},
+		{
+			events: []*Event{endOfSnapshot(12)},
+			err:    fmt.Errorf("connection closed"),
+		},
+	}}
+
+	var log []string
+	sub := ServiceHealth("web", func(index uint64, op Op, entry *api.ServiceEntry) {
+		log = append(log, fmt.Sprintf("%d %s %s", index, op, entry.Node.Node))
+	})
+	sub.Handlers.Reset = func() { log = append(log, "reset") }
+	sub.Handlers.SnapshotDone = func(index uint64) { log = append(log, fmt.Sprintf("snapshot %d", index)) }
+	sub.MinWait = time.Millisecond
+
+	err := sub.Run(context.Background(), client)
+	require.ErrorIs(t, err, ErrRefused)
+
+	require.Equal(t, []string{
+		"reset",
+		"5 register node1",
+		"snapshot 5",
+		"7 register node2",
+		"7 deregister node1",
+		"reset",
+		"9 register node3",
+		"snapshot 9",
+		"reset",
+		"snapshot 12",
+	}, log)
+
+	require.Len(t, client.requests, 4)
+	require.Equal(t, "web", client.requests[0].Key)
+	require.Equal(t, []uint64{0, 7, 0, 12}, []uint64{
+		client.requests[0].Index,
+		client.requests[1].Index,
+		client.requests[2].Index,
+		client.requests[3].Index,
+	})
+}
+
+func TestSubscription_ConfigEntries(t *testing.T) {
+	entry := &api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "web"}
+	client := &testClient{streams: []testStream{{
+		events: []*Event{
+			endOfSnapshot(3),
+			{
+				Index:       4,
+				ConfigEntry: &ConfigEntryUpdate{Op: OpUpsert, Entry: entry},
+			},
+		},
+	}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []api.ConfigEntry
+	sub := ConfigEntries(TopicServiceDefaults, "", func(index uint64, op Op, entry api.ConfigEntry) {
+		require.Equal(t, uint64(4), index)
+		require.Equal(t, OpUpsert, op)
+		got = append(got, entry)
+		cancel()
+	})
+
+	require.NoError(t, sub.Run(ctx, client))
+	require.Equal(t, []api.ConfigEntry{entry}, got)
+	require.Equal(t, uint64(4), sub.Index())
+	require.Empty(t, client.requests[0].Key)
+}
+
+func TestSubscription_backoff(t *testing.T) {
+	sub := &Subscription{MinWait: 10 * time.Millisecond, MaxWait: 50 * time.Millisecond}
+	for _, max := range []time.Duration{10, 20, 40, 50, 50} {
+		wait := sub.backoff()
+		require.GreaterOrEqual(t, wait, max*time.Millisecond/2)
+		require.LessOrEqual(t, wait, max*time.Millisecond)
+	}
+}
@@ -29,6 +29,9 @@ const (
 
 	// ServiceMaintPrefix is the prefix for a service in maintenance mode.
 	ServiceMaintPrefix = "_service_maintenance:"
+
+	// ServiceDrainingPrefix is the prefix for a service that is draining.
+	ServiceDrainingPrefix = "_service_draining:"
 )
 
 // HealthCheck is used to represent a single check
@@ -42,6 +45,7 @@ type HealthCheck struct {
 	ServiceID   string
 	ServiceName string
 	ServiceTags []string
+	PortName    string `json:",omitempty"`
 	Type        string
 	Namespace   string `json:",omitempty"`
 	Partition   string `json:",omitempty"`
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // KVPair is used to represent a single K/V entry
@@ -41,6 +42,15 @@ type KVPair struct {
 	// session ID.
 	Session string
 
+	// TTL is how long the key lives after it is written, as a duration string
+	// like "30s". The key is deleted by the servers once it expires. This is
+	// only used when writing the key.
+	TTL string `json:",omitempty"`
+
+	// ExpirationTime is the time after which the key is deleted, if it was
+	// written with a TTL. This is a read-only field.
+	ExpirationTime *time.Time `json:",omitempty"`
+
 	// Namespace is the namespace the KVPair is associated with
 	// Namespacing is a Consul Enterprise feature.
 	Namespace string `json:",omitempty"`
@@ -155,18 +165,21 @@ func (k *KV) getInternal(key string, params map[string]string, q *QueryOptions)
 }
 
 // Put is used to write a new value. Only the
-// Key, Flags and Value is respected.
+// Key, Flags, Value and TTL is respected.
 func (k *KV) Put(p *KVPair, q *WriteOptions) (*WriteMeta, error) {
 	params := make(map[string]string, 1)
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	_, wm, err := k.put(p.Key, params, p.Value, q)
 	return wm, err
 }
 
 // CAS is used for a Check-And-Set operation. The Key,
-// ModifyIndex, Flags and Value are respected. Returns true
+// ModifyIndex, Flags, Value and TTL are respected. Returns true
 // on success or false on failures.
 func (k *KV) CAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 	params := make(map[string]string, 2)
@@ -174,11 +187,14 @@ func (k *KV) CAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
 	params["cas"] = strconv.FormatUint(p.ModifyIndex, 10)
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	return k.put(p.Key, params, p.Value, q)
 }
 
 // Acquire is used for a lock acquisition operation. The Key,
-// Flags, Value and Session are respected. Returns true
+// Flags, Value, Session and TTL are respected. Returns true
 // on success or false on failures.
 func (k *KV) Acquire(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 	params := make(map[string]string, 2)
@@ -186,6 +202,9 @@ func (k *KV) Acquire(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
 	params["acquire"] = p.Session
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	return k.put(p.Key, params, p.Value, q)
 }
 
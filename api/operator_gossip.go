@@ -0,0 +1,63 @@
+package api
+
+import "time"
+
+// GossipHealth is the health of the LAN gossip pool as seen from the agent
+// answering the request.
+type GossipHealth struct {
+	// Node is the name of the agent.
+	Node string
+
+	// HealthScore is the memberlist awareness score of the agent. It is 0
+	// when the agent is healthy and rises when the agent misses probe acks,
+	// which points at the agent or its own network rather than its peers.
+	HealthScore int
+
+	// PartitionSuspected is true when a large share of the peers failed
+	// recently, which is more likely a network partition than that many
+	// independent node failures.
+	PartitionSuspected bool
+
+	Peers []GossipPeerHealth
+}
+
+// GossipPeerHealth is the health of the gossip between the agent and one of
+// its peers.
+type GossipPeerHealth struct {
+	Node    string
+	Address string
+	Status  string
+
+	// RTT is the round trip time to the peer estimated from the network
+	// coordinates. It is 0 when the coordinate of the peer isn't known.
+	RTT time.Duration
+
+	// Failures is the number of times the peer was declared failed and Flaps
+	// the number of times it came back alive after that, since the agent
+	// started.
+	Failures int
+	Flaps    int
+
+	LastFailure *time.Time `json:",omitempty"`
+}
+
+// GossipHealth returns the health of the LAN gossip pool as seen from the
+// agent the client talks to.
+func (op *Operator) GossipHealth(q *QueryOptions) (*GossipHealth, error) {
+	r := op.c.newRequest("GET", "/v1/operator/gossip/health")
+	r.setQueryOptions(q)
+	_, resp, err := op.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var out GossipHealth
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
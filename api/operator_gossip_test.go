@@ -0,0 +1,21 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_OperatorGossipHealth(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+	s.WaitForSerfCheck(t)
+
+	health, err := c.Operator().GossipHealth(nil)
+	require.NoError(t, err)
+	require.Equal(t, s.Config.NodeName, health.Node)
+	require.Equal(t, 0, health.HealthScore)
+	require.False(t, health.PartitionSuspected)
+	require.Empty(t, health.Peers)
+}
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // keyringRequest is used for performing Keyring operations
 type keyringRequest struct {
 	Key string
@@ -32,6 +34,39 @@ type KeyringResponse struct {
 	NumNodes int
 }
 
+// KeyringRotationStatus reports the state of the automated rotation of the
+// gossip encryption key of a datacenter.
+type KeyringRotationStatus struct {
+	// Enabled is whether the leader rotates the key.
+	Enabled bool
+
+	// Interval is how often the key is rotated.
+	Interval ReadableDuration
+
+	// GracePeriod is how long the new key is installed before being promoted,
+	// and how long the previous key is kept once it was demoted.
+	GracePeriod ReadableDuration
+
+	// Phase is the step of the rotation in progress: "idle", "installed" or
+	// "promoted".
+	Phase string
+
+	// PhaseStartedAt is when the current phase started.
+	PhaseStartedAt time.Time
+
+	// LastRotation is when the last rotation completed, or when the rotation
+	// was enabled if no key was rotated yet.
+	LastRotation time.Time
+
+	// NextRotation is when the next rotation is due to start. It is zero when
+	// the rotation is disabled.
+	NextRotation time.Time
+
+	// LastError is the error that prevented the rotation from moving forward
+	// on the last attempt, if any.
+	LastError string `json:",omitempty"`
+}
+
 // KeyringInstall is used to install a new gossip encryption key into the cluster
 func (op *Operator) KeyringInstall(key string, q *WriteOptions) error {
 	r := op.c.newRequest("POST", "/v1/operator/keyring")
@@ -105,3 +140,24 @@ func (op *Operator) KeyringUse(key string, q *WriteOptions) error {
 	}
 	return nil
 }
+
+// KeyringRotationStatus is used to retrieve the state of the automated rotation
+// of the gossip encryption key.
+func (op *Operator) KeyringRotationStatus(q *QueryOptions) (*KeyringRotationStatus, error) {
+	r := op.c.newRequest("GET", "/v1/operator/keyring/rotation")
+	r.setQueryOptions(q)
+	_, resp, err := op.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var out KeyringRotationStatus
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
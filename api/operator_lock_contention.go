@@ -0,0 +1,71 @@
+package api
+
+import "time"
+
+// LockContention describes the holders and the waiters of a lock or a
+// semaphore built on the KV store, as seen by the leader.
+type LockContention struct {
+	// Key is the key of the lock, or the prefix of the semaphore.
+	Key string
+
+	// Semaphore is true if Key is the prefix of a semaphore.
+	Semaphore bool
+
+	// Limit is the number of holders allowed by a semaphore.
+	Limit int `json:",omitempty"`
+
+	Holders []LockSession
+	Waiters []LockSession
+
+	// QueueDepth is the number of waiters.
+	QueueDepth int
+
+	// Acquisitions is the number of times the lock or the semaphore was
+	// acquired, and Contentions the number of attempts to acquire the lock
+	// that failed because it was held.
+	Acquisitions uint64
+	Contentions  uint64
+
+	MeanWaitTime time.Duration
+	MaxWaitTime  time.Duration
+
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+}
+
+// LockSession is a session holding or waiting for a lock.
+type LockSession struct {
+	Session string
+
+	// Since is when the session acquired the lock or started waiting for it.
+	// It is unset if that happened before the current leader was elected.
+	Since *time.Time `json:",omitempty"`
+}
+
+// LockContention returns the holders and the waiters of the locks and
+// semaphores under the given key prefix, sorted by queue depth.
+func (op *Operator) LockContention(prefix string, q *QueryOptions) ([]*LockContention, *QueryMeta, error) {
+	r := op.c.newRequest("GET", "/v1/operator/lock-contention")
+	r.setQueryOptions(q)
+	if prefix != "" {
+		r.params.Set("prefix", prefix)
+	}
+	rtt, resp, err := op.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []*LockContention
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
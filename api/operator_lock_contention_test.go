@@ -0,0 +1,54 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_OperatorLockContention(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+	s.WaitForSerfCheck(t)
+
+	holder, err := c.LockKey("test/lock")
+	require.NoError(t, err)
+	leaderCh, err := holder.Lock(nil)
+	require.NoError(t, err)
+	require.NotNil(t, leaderCh)
+	defer holder.Unlock()
+
+	// A Lock only tries to acquire a lock once it is released, so the waiter
+	// contends for the held lock with a plain acquire.
+	session, _, err := c.Session().Create(&SessionEntry{}, nil)
+	require.NoError(t, err)
+
+	acquired, _, err := c.KV().Acquire(&KVPair{
+		Key:     "test/lock",
+		Flags:   LockFlagValue,
+		Session: session,
+	}, nil)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	locks, _, err := c.Operator().LockContention("test/", nil)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+
+	lock := locks[0]
+	require.Equal(t, "test/lock", lock.Key)
+	require.False(t, lock.Semaphore)
+	require.Len(t, lock.Holders, 1)
+	require.Equal(t, holder.lockSession, lock.Holders[0].Session)
+	require.NotNil(t, lock.Holders[0].Since)
+	require.Len(t, lock.Waiters, 1)
+	require.Equal(t, session, lock.Waiters[0].Session)
+	require.Equal(t, 1, lock.QueueDepth)
+	require.Equal(t, uint64(1), lock.Acquisitions)
+	require.Equal(t, uint64(1), lock.Contentions)
+
+	locks, _, err = c.Operator().LockContention("other/", nil)
+	require.NoError(t, err)
+	require.Empty(t, locks)
+}
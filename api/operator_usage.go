@@ -1,8 +1,15 @@
 package api
 
+import "time"
+
 type Usage struct {
 	// Usage is a map of datacenter -> usage information
 	Usage map[string]ServiceUsage
+
+	// History is a map of datacenter -> daily samples of the usage
+	// information of the last 30 days, oldest first. It is only set by
+	// UsageHistory.
+	History map[string][]ServiceUsageSample `json:",omitempty"`
 }
 
 // ServiceUsage contains information about the number of services and service instances for a datacenter.
@@ -26,12 +33,40 @@ type ServiceUsage struct {
 
 	// A map of partition+namespace to number of billable instances registered in that namespace
 	PartitionNamespaceBillableServiceInstances map[string]map[string]int
+
+	// A map of service kind to number of service instances of that kind, with
+	// the services of no kind counted as "typical".
+	ServiceKindInstances map[string]int
+
+	// The number of service instances registered on external nodes, which
+	// have the "external-node" node meta.
+	ExternalServiceInstances int
+}
+
+// ServiceUsageSample is the usage information of a datacenter at a point in
+// time.
+type ServiceUsageSample struct {
+	Time time.Time
+	ServiceUsage
 }
 
 // Usage is used to query for usage information in the given datacenter.
 func (op *Operator) Usage(q *QueryOptions) (*Usage, *QueryMeta, error) {
+	return op.usage(q, false)
+}
+
+// UsageHistory is used to query for usage information in the given
+// datacenter, along with its daily samples of the last 30 days.
+func (op *Operator) UsageHistory(q *QueryOptions) (*Usage, *QueryMeta, error) {
+	return op.usage(q, true)
+}
+
+func (op *Operator) usage(q *QueryOptions, history bool) (*Usage, *QueryMeta, error) {
 	r := op.c.newRequest("GET", "/v1/operator/usage")
 	r.setQueryOptions(q)
+	if history {
+		r.params.Set("history", "")
+	}
 	rtt, resp, err := op.c.doRequest(r)
 	if err != nil {
 		return nil, nil, err
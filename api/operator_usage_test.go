@@ -60,4 +60,7 @@ func TestAPI_OperatorUsage(t *testing.T) {
 		"terminating-gateway": 0,
 	}, usage.Usage["dc1"].ConnectServiceInstances)
 	require.Equal(t, 3, usage.Usage["dc1"].BillableServiceInstances)
+	require.Equal(t, 4, usage.Usage["dc1"].ServiceKindInstances["typical"])
+	require.Equal(t, 1, usage.Usage["dc1"].ServiceKindInstances["connect-proxy"])
+	require.Nil(t, usage.History)
 }
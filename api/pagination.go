@@ -0,0 +1,190 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultPageSize is the number of results fetched per page by the
+	// iterators when QueryOptions.Limit is not set.
+	defaultPageSize = 500
+
+	// maxPaginationRestarts bounds the number of times an iterator restarts
+	// from the first page on its own when the results change.
+	maxPaginationRestarts = 3
+
+	// paginationConflictError is the error the servers return when the
+	// results changed since the first page of a paginated query was read.
+	paginationConflictError = "Results changed since the first page was read"
+)
+
+// PageIterator iterates over the results of an endpoint that supports
+// pagination, fetching the pages as they are needed:
+//
+//	it := client.Catalog().ServicesIterator(nil)
+//	for it.Next() {
+//		svc := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// All the pages are taken from the same index of the servers, so the results
+// are consistent with each other. When the results change before any of them
+// was returned, the iterator restarts from the first page on its own. Once
+// results were returned, Err returns an error for which IsPaginationConflict
+// is true, and the iteration can be restarted with Reset.
+//
+// A WaitIndex set in the options only makes the first page a blocking query,
+// the next ones are taken at the index of the first one.
+type PageIterator[T any] struct {
+	fetch func(q *QueryOptions) ([]T, *QueryMeta, error)
+	q     QueryOptions
+	opts  QueryOptions
+
+	page     []T
+	pos      int
+	meta     *QueryMeta
+	err      error
+	done     bool
+	returned bool
+	restarts int
+}
+
+// ServicesIterator iterates over the services of the catalog, by name.
+type ServicesIterator = PageIterator[CatalogServiceTags]
+
+// ServiceEntriesIterator iterates over the instances of a service and their
+// health checks.
+type ServiceEntriesIterator = PageIterator[*ServiceEntry]
+
+// ConfigEntriesIterator iterates over the config entries of a kind.
+type ConfigEntriesIterator = PageIterator[ConfigEntry]
+
+// CatalogServiceTags is a service of the catalog with the tags of its
+// instances.
+type CatalogServiceTags struct {
+	Name string
+	Tags []string
+}
+
+func newPageIterator[T any](q *QueryOptions, fetch func(q *QueryOptions) ([]T, *QueryMeta, error)) *PageIterator[T] {
+	it := &PageIterator[T]{fetch: fetch}
+	if q != nil {
+		it.q = *q
+	}
+	if it.q.Limit == 0 {
+		it.q.Limit = defaultPageSize
+	}
+	it.opts = it.q
+	return it
+}
+
+// Reset restarts the iteration from the first page, for example after Err
+// returned an error for which IsPaginationConflict is true. The results
+// returned before are returned again, along with the changes.
+func (it *PageIterator[T]) Reset() {
+	*it = PageIterator[T]{fetch: it.fetch, q: it.q, opts: it.q}
+}
+
+// Next advances the iterator to the next result, fetching the next page if
+// needed. It returns false once all the results were returned or a page could
+// not be fetched, in which case Err returns the error.
+func (it *PageIterator[T]) Next() bool {
+	for it.pos >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetchPage()
+	}
+	it.pos++
+	it.returned = true
+	return true
+}
+
+func (it *PageIterator[T]) fetchPage() {
+	page, meta, err := it.fetch(&it.opts)
+	if IsPaginationConflict(err) && !it.returned && it.restarts < maxPaginationRestarts {
+		// None of the results were returned yet, so the iteration can start
+		// over without the caller noticing.
+		it.restarts++
+		it.opts = it.q
+		it.page, it.pos = nil, 0
+		return
+	}
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page, it.pos, it.meta = page, 0, meta
+	it.opts.Cursor = meta.NextCursor
+	it.done = meta.NextCursor == ""
+
+	// The next pages are taken at the index of the first one, rather than
+	// waiting for the results to change again.
+	it.opts.WaitIndex = 0
+	it.opts.WaitHash = ""
+}
+
+// Value returns the current result. It must only be called after Next
+// returned true.
+func (it *PageIterator[T]) Value() T {
+	return it.page[it.pos-1]
+}
+
+// Meta returns the metadata of the last page that was fetched. Its LastIndex
+// is the index all the pages were taken from.
+func (it *PageIterator[T]) Meta() *QueryMeta {
+	return it.meta
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// IsPaginationConflict returns true if the error was returned because the
+// results of a paginated query changed since its first page was fetched.
+func IsPaginationConflict(err error) bool {
+	statusErr, ok := err.(StatusError)
+	return ok && statusErr.Code == http.StatusConflict &&
+		strings.Contains(statusErr.Body, paginationConflictError)
+}
+
+// ServicesIterator returns an iterator over the services of the catalog,
+// sorted by name.
+func (c *Catalog) ServicesIterator(q *QueryOptions) *ServicesIterator {
+	return newPageIterator(q, func(q *QueryOptions) ([]CatalogServiceTags, *QueryMeta, error) {
+		services, qm, err := c.Services(q)
+		if err != nil {
+			return nil, nil, err
+		}
+		page := make([]CatalogServiceTags, 0, len(services))
+		for name, tags := range services {
+			page = append(page, CatalogServiceTags{Name: name, Tags: tags})
+		}
+		sort.Slice(page, func(i, j int) bool {
+			return page[i].Name < page[j].Name
+		})
+		return page, qm, nil
+	})
+}
+
+// ServiceIterator returns an iterator over the instances of a service, like
+// Service.
+func (h *Health) ServiceIterator(service, tag string, passingOnly bool, q *QueryOptions) *ServiceEntriesIterator {
+	return newPageIterator(q, func(q *QueryOptions) ([]*ServiceEntry, *QueryMeta, error) {
+		return h.Service(service, tag, passingOnly, q)
+	})
+}
+
+// ListIterator returns an iterator over the config entries of a kind, like
+// List.
+func (conf *ConfigEntries) ListIterator(kind string, q *QueryOptions) *ConfigEntriesIterator {
+	return newPageIterator(q, func(q *QueryOptions) ([]ConfigEntry, *QueryMeta, error) {
+		return conf.List(kind, q)
+	})
+}
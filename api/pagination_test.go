@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_PageIterator(t *testing.T) {
+	t.Parallel()
+
+	// Three pages of services, the second of which is empty.
+	pages := map[string]struct {
+		body, next string
+	}{
+		"":   {body: `{"db":["primary"],"api":[]}`, next: "c1"},
+		"c1": {body: `{}`, next: "c2"},
+		"c2": {body: `{"web":["v1","v2"]}`},
+	}
+	var conflicts int
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cursor := req.URL.Query().Get("cursor")
+		requests = append(requests, req.URL.Query().Get("index")+"/"+req.URL.Query().Get("limit")+"/"+cursor)
+		if conflicts > 0 && cursor != "" {
+			conflicts--
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("Results changed since the first page was read, restart pagination (index 12, now 13)"))
+			return
+		}
+		page := pages[cursor]
+		w.Header().Set("X-Consul-Index", "12")
+		w.Header().Set("X-Consul-LastContact", "0")
+		w.Header().Set("X-Consul-Next-Cursor", page.next)
+		w.Write([]byte(page.body))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Address: srv.Listener.Addr().String()})
+	require.NoError(t, err)
+
+	collect := func(it *ServicesIterator) []string {
+		var names []string
+		for it.Next() {
+			names = append(names, it.Value().Name)
+		}
+		return names
+	}
+
+	// Only the first page is a blocking query.
+	it := c.Catalog().ServicesIterator(&QueryOptions{Limit: 2, WaitIndex: 10})
+	var services []CatalogServiceTags
+	for it.Next() {
+		services = append(services, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []CatalogServiceTags{
+		{Name: "api", Tags: []string{}},
+		{Name: "db", Tags: []string{"primary"}},
+		{Name: "web", Tags: []string{"v1", "v2"}},
+	}, services)
+	require.Equal(t, uint64(12), it.Meta().LastIndex)
+	require.Equal(t, []string{"10/2/", "/2/c1", "/2/c2"}, requests)
+
+	// The services changed after the first page was returned.
+	conflicts = 1
+	requests = nil
+	it = c.Catalog().ServicesIterator(nil)
+	require.Equal(t, []string{"api", "db"}, collect(it))
+	require.True(t, IsPaginationConflict(it.Err()))
+	require.False(t, it.Next())
+	require.Equal(t, []string{"/500/", "/500/c1"}, requests)
+
+	// The iteration can be restarted once the conflict is known.
+	requests = nil
+	it.Reset()
+	require.Equal(t, []string{"api", "db", "web"}, collect(it))
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"/500/", "/500/c1", "/500/c2"}, requests)
+
+	// The iterator restarts on its own while it didn't return any service.
+	pages[""] = struct{ body, next string }{body: `{}`, next: "c1"}
+	conflicts = 2
+	requests = nil
+	it = c.Catalog().ServicesIterator(nil)
+	require.Equal(t, []string{"web"}, collect(it))
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"/500/", "/500/c1", "/500/", "/500/c1", "/500/", "/500/c1", "/500/c2"}, requests)
+
+	// Up to a limit.
+	conflicts = maxPaginationRestarts + 1
+	it = c.Catalog().ServicesIterator(nil)
+	require.Empty(t, collect(it))
+	require.True(t, IsPaginationConflict(it.Err()))
+}
+
+func TestAPI_IsPaginationConflict(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsPaginationConflict(StatusError{
+		Code: http.StatusConflict,
+		Body: "Results changed since the first page was read, restart pagination (index 12, now 13)",
+	}))
+	require.False(t, IsPaginationConflict(StatusError{Code: http.StatusConflict, Body: "Session already held"}))
+	require.False(t, IsPaginationConflict(StatusError{Code: http.StatusInternalServerError}))
+	require.False(t, IsPaginationConflict(nil))
+}
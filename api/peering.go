@@ -90,6 +90,31 @@ type PeeringStreamStatus struct {
 	LastReceive *time.Time
 	// LastSend represents when any message was last sent, regardless of success or error.
 	LastSend *time.Time
+	// MessagesReceived is the number of messages received from the peer.
+	MessagesReceived uint64
+	// MessagesSent is the number of messages sent to the peer.
+	MessagesSent uint64
+	// MessagesRateLimited is the number of received messages that were delayed
+	// by the incoming message rate limit.
+	MessagesRateLimited uint64
+	// PendingResponses is the number of resources sent to the peer that have not been acknowledged yet.
+	PendingResponses uint64
+	// RetryAttempts is the number of consecutive failed attempts to establish the stream from the dialing side.
+	RetryAttempts uint64
+	// NextRetry represents when the dialing side will next try to establish the stream.
+	NextRetry *time.Time
+	// RecentErrors holds the most recent errors seen on the stream, oldest first.
+	RecentErrors []PeeringStreamError
+}
+
+// PeeringStreamError is an error observed on a peering stream.
+type PeeringStreamError struct {
+	// Time is when the error happened.
+	Time time.Time
+	// Kind is where the error happened: send, recv, nack or disconnect.
+	Kind string
+	// Message is the error message.
+	Message string
 }
 
 type PeeringReadResponse struct {
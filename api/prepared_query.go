@@ -15,6 +15,11 @@ type QueryFailoverOptions struct {
 	// Targets is a fixed list of datacenters and peers to try. This field cannot
 	// be populated with NearestN or Datacenters.
 	Targets []QueryFailoverTarget
+
+	// SamenessGroup is the name of a sameness group whose members are tried,
+	// in order, after the local datacenter. This field cannot be populated
+	// with NearestN, Datacenters or Targets.
+	SamenessGroup string `json:",omitempty"`
 }
 
 // Deprecated: use QueryFailoverOptions instead.
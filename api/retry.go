@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultRetryMinBackoff is the wait before the first retry when
+	// RetryPolicy.MinBackoff is not set.
+	defaultRetryMinBackoff = 100 * time.Millisecond
+
+	// defaultRetryMaxBackoff is the longest wait between two attempts when
+	// RetryPolicy.MaxBackoff is not set.
+	defaultRetryMaxBackoff = 10 * time.Second
+)
+
+// RetryPolicy configures how the client retries the requests that failed
+// because of a network error, a rate limit or a transient server error.
+//
+// Only the idempotent requests (GET, HEAD and OPTIONS) are retried by default,
+// since a failed write may still have been applied. The requests rejected by
+// the rate limiter of the servers (429 Too Many Requests) are the exception:
+// they were not processed, so they are retried whatever their method.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried after
+	// its first attempt. Zero disables the retries.
+	MaxRetries int
+
+	// MinBackoff is the wait before the first retry. It doubles with every
+	// retry, and is jittered. Defaults to 100ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait between two attempts. Defaults to 10s.
+	MaxBackoff time.Duration
+
+	// Budget is the maximum time spent on a request, across all of its
+	// attempts and the waits between them. A retry that would start after the
+	// budget is exhausted is not made, and the last response or error is
+	// returned instead. Zero means no budget.
+	Budget time.Duration
+
+	// RetryNonIdempotent also retries the non-idempotent requests (PUT, POST,
+	// DELETE) on network and server errors. It should only be set when all
+	// the writes made by the client are safe to apply twice.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most clients: up to
+// three retries of the idempotent requests, within a budget of 30 seconds.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: defaultRetryMinBackoff,
+		MaxBackoff: defaultRetryMaxBackoff,
+		Budget:     30 * time.Second,
+	}
+}
+
+// retryWait returns how long to wait before retrying a request, and whether it
+// should be retried at all. attempt is the number of retries already made and
+// start the time of the first attempt.
+func (p *RetryPolicy) retryWait(req *http.Request, attempt int, start time.Time, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxRetries {
+		return 0, false
+	}
+	// A request whose body can't be replayed can only be sent once.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if req.Context().Err() != nil || !p.retryMethod(req.Method) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusInternalServerError,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		if !p.retryMethod(req.Method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	wait := p.backoff(attempt)
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			wait = after
+		}
+	}
+	if p.Budget > 0 && time.Since(start)+wait > p.Budget {
+		return 0, false
+	}
+	return wait, true
+}
+
+func (p *RetryPolicy) retryMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// backoff returns the jittered exponential wait before the given retry.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	minWait, maxWait := p.MinBackoff, p.MaxBackoff
+	if minWait <= 0 {
+		minWait = defaultRetryMinBackoff
+	}
+	if maxWait <= 0 {
+		maxWait = defaultRetryMaxBackoff
+	}
+
+	wait := maxWait
+	if attempt < 31 {
+		if w := minWait << uint(attempt); w > 0 && w < maxWait {
+			wait = w
+		}
+	}
+	// Wait between half and all of the backoff, so that the clients rejected
+	// together do not retry together.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
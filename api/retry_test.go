@@ -0,0 +1,135 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// retryTestClient returns a client for a server answering the first failures
+// requests with the given status code, and the next ones with a 200.
+func retryTestClient(t *testing.T, policy *RetryPolicy, status, failures int, header http.Header) (*Client, *int32) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// The body must be replayed with every attempt.
+		body, _ := io.ReadAll(req.Body)
+		if req.Method == http.MethodPut && string(body) != "value" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if atomic.AddInt32(&hits, 1) <= int32(failures) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		if req.Method == http.MethodPut {
+			w.Write([]byte("true"))
+			return
+		}
+		w.Write([]byte(`"127.0.0.1:8300"`))
+	}))
+	t.Cleanup(srv.Close)
+
+	conf := DefaultConfig()
+	conf.Address = srv.Listener.Addr().String()
+	conf.RetryPolicy = policy
+	c, err := NewClient(conf)
+	require.NoError(t, err)
+	return c, &hits
+}
+
+func TestAPI_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+	t.Run("no policy", func(t *testing.T) {
+		c, hits := retryTestClient(t, nil, http.StatusServiceUnavailable, 1, nil)
+		_, _, err := c.KV().Get("key", nil)
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(hits))
+	})
+
+	t.Run("idempotent request", func(t *testing.T) {
+		c, hits := retryTestClient(t, policy, http.StatusServiceUnavailable, 2, nil)
+		_, err := c.Status().Leader()
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(hits))
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		c, hits := retryTestClient(t, policy, http.StatusInternalServerError, 5, nil)
+		_, err := c.Status().Leader()
+		require.Error(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(hits))
+	})
+
+	t.Run("write not retried", func(t *testing.T) {
+		c, hits := retryTestClient(t, policy, http.StatusServiceUnavailable, 1, nil)
+		_, err := c.KV().Put(&KVPair{Key: "key", Value: []byte("value")}, nil)
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(hits))
+	})
+
+	t.Run("write retried when allowed", func(t *testing.T) {
+		policy := *policy
+		policy.RetryNonIdempotent = true
+		c, hits := retryTestClient(t, &policy, http.StatusServiceUnavailable, 1, nil)
+		_, err := c.KV().Put(&KVPair{Key: "key", Value: []byte("value")}, nil)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(hits))
+	})
+
+	t.Run("rate limited write", func(t *testing.T) {
+		c, hits := retryTestClient(t, policy, http.StatusTooManyRequests, 1, http.Header{"Retry-After": {"0"}})
+		_, err := c.KV().Put(&KVPair{Key: "key", Value: []byte("value")}, nil)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(hits))
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		policy := *policy
+		policy.Budget = time.Second
+		c, hits := retryTestClient(t, &policy, http.StatusTooManyRequests, 1, http.Header{"Retry-After": {"5"}})
+		_, err := c.Status().Leader()
+		require.Equal(t, http.StatusTooManyRequests, err.(StatusError).Code)
+		require.Equal(t, int32(1), atomic.LoadInt32(hits))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c, hits := retryTestClient(t, policy, http.StatusNotFound, 1, nil)
+		_, err := c.Status().Leader()
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(hits))
+	})
+}
+
+func TestAPI_ParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	wait, ok := parseRetryAfter("3", now)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, wait)
+
+	wait, ok = parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Equal(t, 10*time.Second, wait)
+
+	wait, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Zero(t, wait)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(value, now)
+		require.False(t, ok, value)
+	}
+}
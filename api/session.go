@@ -1,6 +1,9 @@
 package api
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -17,6 +20,16 @@ const (
 	SessionBehaviorDelete = "delete"
 )
 
+const (
+	// SessionKeepAliveRenewed is the event sent by KeepAlive when a session
+	// is renewed.
+	SessionKeepAliveRenewed = "renewed"
+
+	// SessionKeepAliveInvalidated is the event sent by KeepAlive when a
+	// session no longer exists, and any lock it held has been released.
+	SessionKeepAliveInvalidated = "invalidated"
+)
+
 var ErrSessionExpired = errors.New("session expired")
 
 // SessionEntry represents a session in consul
@@ -209,6 +222,72 @@ func (s *Session) RenewPeriodic(initialTTL string, id string, q *WriteOptions, d
 	}
 }
 
+// SessionKeepAliveEvent is sent by KeepAlive every time one of the sessions
+// is renewed or invalidated.
+type SessionKeepAliveEvent struct {
+	SessionID string
+
+	// Event is either SessionKeepAliveRenewed or SessionKeepAliveInvalidated.
+	Event string
+
+	// TTL is the TTL of the session when it was renewed. It is empty if the
+	// session has no TTL or was invalidated.
+	TTL string `json:",omitempty"`
+}
+
+// KeepAlive keeps the sessions alive over a single streaming request until
+// doneCh is closed or all of them are invalidated, rather than renewing each
+// of them periodically. The agent renews each session immediately and then
+// every half of its TTL, and an event is sent on eventCh every time a session
+// is renewed or invalidated, as soon as it happens. Unlike RenewPeriodic,
+// closing doneCh doesn't destroy the sessions, they expire once their TTL
+// elapses.
+func (s *Session) KeepAlive(ids []string, q *WriteOptions, doneCh <-chan struct{}, eventCh chan<- *SessionKeepAliveEvent) error {
+	ctx, cancel := context.WithCancel(q.Context())
+	defer cancel()
+
+	r := s.c.newRequest("PUT", "/v1/session/keepalive")
+	r.setWriteOptions(q)
+	r.ctx = ctx
+	r.obj = struct{ SessionIDs []string }{ids}
+	_, resp, err := s.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return err
+	}
+
+	// The request is cancelled once doneCh is closed, which ends the scan.
+	go func() {
+		select {
+		case <-doneCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event SessionKeepAliveEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("failed to decode keep alive event: %v", err)
+		}
+		select {
+		case eventCh <- &event:
+		case <-doneCh:
+			return nil
+		}
+	}
+	select {
+	case <-doneCh:
+		return nil
+	default:
+	}
+	return scanner.Err()
+}
+
 // Info looks up a single session
 func (s *Session) Info(id string, q *QueryOptions) (*SessionEntry, *QueryMeta, error) {
 	var entries []*SessionEntry
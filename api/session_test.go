@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAPI_SessionCreateDestroy(t *testing.T) {
@@ -284,6 +285,87 @@ func TestAPI_SessionRenewPeriodic_Cancel(t *testing.T) {
 	})
 }
 
+func TestAPI_SessionKeepAlive(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	session := c.Session()
+	ttlID, _, err := session.Create(&SessionEntry{TTL: "10s"}, nil)
+	require.NoError(t, err)
+	noTTLID, _, err := session.Create(&SessionEntry{}, nil)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	doneCh := make(chan struct{})
+	eventCh := make(chan *SessionKeepAliveEvent)
+	go func() {
+		errCh <- session.KeepAlive([]string{ttlID, noTTLID}, nil, doneCh, eventCh)
+	}()
+
+	nextEvent := func() *SessionKeepAliveEvent {
+		t.Helper()
+		select {
+		case event := <-eventCh:
+			return event
+		case err := <-errCh:
+			t.Fatalf("keep alive ended: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no keep alive event")
+		}
+		return nil
+	}
+
+	// Both sessions are renewed right away.
+	renewed := make(map[string]*SessionKeepAliveEvent)
+	for len(renewed) < 2 {
+		event := nextEvent()
+		require.Equal(t, SessionKeepAliveRenewed, event.Event)
+		renewed[event.SessionID] = event
+	}
+	require.Equal(t, "10s", renewed[ttlID].TTL)
+	require.Empty(t, renewed[noTTLID].TTL)
+
+	// The invalidation of a session is pushed.
+	_, err = session.Destroy(noTTLID, nil)
+	require.NoError(t, err)
+	require.Equal(t, &SessionKeepAliveEvent{SessionID: noTTLID, Event: SessionKeepAliveInvalidated}, nextEvent())
+
+	// Closing doneCh ends the stream without destroying the session.
+	close(doneCh)
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("keep alive didn't terminate")
+	}
+
+	sess, _, err := session.Info(ttlID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, sess)
+}
+
+func TestAPI_SessionKeepAlive_Invalidated(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	session := c.Session()
+	id, _, err := session.Create(&SessionEntry{TTL: "10s"}, nil)
+	require.NoError(t, err)
+	_, err = session.Destroy(id, nil)
+	require.NoError(t, err)
+
+	// The stream ends once all of the sessions are invalidated.
+	eventCh := make(chan *SessionKeepAliveEvent, 1)
+	require.NoError(t, session.KeepAlive([]string{id}, nil, nil, eventCh))
+	require.Equal(t, &SessionKeepAliveEvent{SessionID: id, Event: SessionKeepAliveInvalidated}, <-eventCh)
+}
+
 func TestAPI_SessionInfo(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
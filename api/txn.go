@@ -72,6 +72,7 @@ const (
 	KVCheckSession   KVOp = "check-session"
 	KVCheckIndex     KVOp = "check-index"
 	KVCheckNotExists KVOp = "check-not-exists"
+	KVPatch          KVOp = "patch"
 )
 
 // KVTxnOp defines a single operation inside a transaction.
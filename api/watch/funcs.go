@@ -3,6 +3,7 @@ package watch
 import (
 	"context"
 	"fmt"
+	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
 )
@@ -26,6 +27,10 @@ func init() {
 		"connect_roots": connectRootsWatch,
 		"connect_leaf":  connectLeafWatch,
 		"agent_service": agentServiceWatch,
+
+		"config_entry":       configEntryWatch,
+		"service_intentions": serviceIntentionsWatch,
+		"peering":            peeringWatch,
 	}
 }
 
@@ -307,6 +312,102 @@ func agentServiceWatch(params map[string]interface{}) (WatcherFunc, error) {
 	return fn, nil
 }
 
+// peeringPollInterval is how often the peering watches poll the peerings,
+// since the peering endpoints don't support blocking queries.
+var peeringPollInterval = 5 * time.Second
+
+// configEntryWatch is used to watch the config entries of a kind, or a single
+// config entry when a name is given.
+func configEntryWatch(params map[string]interface{}) (WatcherFunc, error) {
+	var kind string
+	if err := assignValue(params, "kind", &kind); err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("Must specify a config entry kind to watch")
+	}
+	return makeConfigEntryWatch(params, kind, "name")
+}
+
+// serviceIntentionsWatch is used to watch the intentions of a destination
+// service, or of all the services.
+func serviceIntentionsWatch(params map[string]interface{}) (WatcherFunc, error) {
+	return makeConfigEntryWatch(params, consulapi.ServiceIntentions, "service")
+}
+
+func makeConfigEntryWatch(params map[string]interface{}, kind, nameParam string) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var name string
+	if err := assignValue(params, nameParam, &name); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		configEntries := p.client.ConfigEntries()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+		// A single entry is picked from the list of its kind, since reading
+		// a missing entry fails rather than blocking until it's created.
+		entries, meta, err := configEntries.List(kind, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == "" {
+			return WaitIndexVal(meta.LastIndex), entries, err
+		}
+		for _, entry := range entries {
+			if entry.GetName() == name {
+				return WaitIndexVal(meta.LastIndex), entry, err
+			}
+		}
+		return WaitIndexVal(meta.LastIndex), nil, err
+	}
+	return fn, nil
+}
+
+// peeringWatch is used to watch the peerings, or a single peering when a name
+// is given.
+func peeringWatch(params map[string]interface{}) (WatcherFunc, error) {
+	// The peering endpoints don't support stale reads.
+
+	var name string
+	if err := assignValue(params, "name", &name); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		if p.lastParamVal != nil {
+			select {
+			case <-time.After(peeringPollInterval):
+			case <-p.stopCh:
+				return nil, nil, nil
+			}
+		}
+
+		peerings := p.client.Peerings()
+		opts := makeQueryOptionsWithContext(p, false)
+		defer p.cancelFunc()
+		list, meta, err := peerings.List(opts.Context(), &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == "" {
+			return WaitIndexVal(meta.LastIndex), list, err
+		}
+		for _, peering := range list {
+			if peering.Name == name {
+				return WaitIndexVal(meta.LastIndex), peering, err
+			}
+		}
+		return WaitIndexVal(meta.LastIndex), nil, err
+	}
+	return fn, nil
+}
+
 func makeQueryOptionsWithContext(p *Plan, stale bool) consulapi.QueryOptions {
 	ctx, cancel := context.WithCancel(context.Background())
 	p.setCancelFunc(cancel)
@@ -2,6 +2,7 @@ package watch_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -1051,6 +1052,126 @@ func TestAgentServiceWatch(t *testing.T) {
 	}
 }
 
+func TestConfigEntryWatch(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	var (
+		wakeups  []interface{}
+		notifyCh = make(chan struct{})
+	)
+
+	plan := mustParse(t, `{"type":"config_entry", "kind":"service-defaults", "name":"web"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		wakeups = append(wakeups, raw)
+		notifyCh <- struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(s.HTTPAddr); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+	defer plan.Stop()
+
+	// Wait for first wakeup.
+	<-notifyCh
+	{
+		// Entries of other services don't wake the watch up.
+		_, _, err := c.ConfigEntries().Set(&api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     "api",
+			Protocol: "http",
+		}, nil)
+		require.NoError(t, err)
+
+		_, _, err = c.ConfigEntries().Set(&api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     "web",
+			Protocol: "grpc",
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	// Wait for second wakeup.
+	<-notifyCh
+
+	plan.Stop()
+	wg.Wait()
+
+	require.Len(t, wakeups, 2)
+	require.Nil(t, wakeups[0])
+
+	entry, ok := wakeups[1].(*api.ServiceConfigEntry)
+	require.True(t, ok)
+	require.Equal(t, "grpc", entry.Protocol)
+}
+
+func TestConfigEntryWatch_KindRequired(t *testing.T) {
+	t.Parallel()
+	params := map[string]interface{}{"type": "config_entry", "name": "web"}
+	_, err := watch.Parse(params)
+	require.EqualError(t, err, "Must specify a config entry kind to watch")
+}
+
+func TestPeeringWatch(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	var (
+		wakeups  [][]*api.Peering
+		notifyCh = make(chan struct{})
+	)
+
+	plan := mustParse(t, `{"type":"peering"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		v, ok := raw.([]*api.Peering)
+		if !ok {
+			return // ignore
+		}
+		wakeups = append(wakeups, v)
+		notifyCh <- struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(s.HTTPAddr); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+	defer plan.Stop()
+
+	// Wait for first wakeup.
+	<-notifyCh
+	{
+		// Generating a token creates a pending peering.
+		_, _, err := c.Peerings().GenerateToken(context.Background(), api.PeeringGenerateTokenRequest{PeerName: "peer1"}, nil)
+		require.NoError(t, err)
+	}
+
+	// Wait for second wakeup.
+	<-notifyCh
+
+	plan.Stop()
+	wg.Wait()
+
+	require.Len(t, wakeups, 2)
+	require.Empty(t, wakeups[0])
+	require.Len(t, wakeups[1], 1)
+	require.Equal(t, "peer1", wakeups[1][0].Name)
+}
+
 func mustParse(t *testing.T, q string) *watch.Plan {
 	t.Helper()
 	var params map[string]interface{}
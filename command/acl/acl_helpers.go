@@ -207,6 +207,58 @@ func ExtractNodeIdentities(nodeIdents []string) ([]*api.ACLNodeIdentity, error)
 	return out, nil
 }
 
+// ExtractTemplatedPolicies parses the -templated-policy arguments, of the form
+// TEMPLATENAME, TEMPLATENAME:NAME or TEMPLATENAME:NAME:DATACENTER1,DATACENTER2,...
+// where NAME is the name variable of the template.
+func ExtractTemplatedPolicies(templatedPolicies []string) ([]*api.ACLTemplatedPolicy, error) {
+	var out []*api.ACLTemplatedPolicy
+	for _, tpRaw := range templatedPolicies {
+		parts := strings.Split(tpRaw, ":")
+		if len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("Malformed -templated-policy argument: %q", tpRaw)
+		}
+
+		tp := &api.ACLTemplatedPolicy{TemplateName: parts[0]}
+		if len(parts) > 1 && parts[1] != "" {
+			tp.TemplateVariables = &api.ACLTemplatedPolicyVariables{Name: parts[1]}
+		}
+		if len(parts) > 2 {
+			tp.Datacenters = strings.Split(parts[2], ",")
+		}
+		out = append(out, tp)
+	}
+	return out, nil
+}
+
+// MergeTemplatedPolicies adds the templated policies in add to existing. A
+// templated policy with the same template and name as an existing one
+// replaces it.
+func MergeTemplatedPolicies(existing, add []*api.ACLTemplatedPolicy) []*api.ACLTemplatedPolicy {
+	name := func(tp *api.ACLTemplatedPolicy) string {
+		if tp.TemplateVariables == nil {
+			return ""
+		}
+		return tp.TemplateVariables.Name
+	}
+
+	for _, tp := range add {
+		found := -1
+		for i, link := range existing {
+			if link.TemplateName == tp.TemplateName && name(link) == name(tp) {
+				found = i
+				break
+			}
+		}
+
+		if found != -1 {
+			existing[found] = tp
+		} else {
+			existing = append(existing, tp)
+		}
+	}
+	return existing
+}
+
 // TestKubernetesJWT_A is a valid service account jwt extracted from a minikube setup.
 //
 //	{
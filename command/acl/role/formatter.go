@@ -86,6 +86,12 @@ func (f *prettyFormatter) FormatRole(role *api.ACLRole) (string, error) {
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(role.TemplatedPolicies) > 0 {
+		buffer.WriteString(fmt.Sprintln("Templated Policies:"))
+		for _, tp := range role.TemplatedPolicies {
+			buffer.WriteString(fmt.Sprintf("   %s\n", templatedPolicyString(tp)))
+		}
+	}
 
 	return buffer.String(), nil
 }
@@ -140,10 +146,27 @@ func (f *prettyFormatter) formatRoleListEntry(role *api.ACLRole) string {
 			buffer.WriteString(fmt.Sprintf("      %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(role.TemplatedPolicies) > 0 {
+		buffer.WriteString(fmt.Sprintln("   Templated Policies:"))
+		for _, tp := range role.TemplatedPolicies {
+			buffer.WriteString(fmt.Sprintf("      %s\n", templatedPolicyString(tp)))
+		}
+	}
 
 	return buffer.String()
 }
 
+func templatedPolicyString(tp *api.ACLTemplatedPolicy) string {
+	dcs := "all"
+	if len(tp.Datacenters) > 0 {
+		dcs = strings.Join(tp.Datacenters, ", ")
+	}
+	if tp.TemplateVariables != nil && tp.TemplateVariables.Name != "" {
+		return fmt.Sprintf("%s (Name: %s, Datacenters: %s)", tp.TemplateName, tp.TemplateVariables.Name, dcs)
+	}
+	return fmt.Sprintf("%s (Datacenters: %s)", tp.TemplateName, dcs)
+}
+
 func newJSONFormatter(showMeta bool) Formatter {
 	return &jsonFormatter{showMeta}
 }
@@ -25,13 +25,14 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	roleID        string
-	name          string
-	description   string
-	policyIDs     []string
-	policyNames   []string
-	serviceIdents []string
-	nodeIdents    []string
+	roleID            string
+	name              string
+	description       string
+	policyIDs         []string
+	policyNames       []string
+	serviceIdents     []string
+	nodeIdents        []string
+	templatedPolicies []string
 
 	noMerge  bool
 	showMeta bool
@@ -57,6 +58,10 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this role. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templatedPolicies), "templated-policy", "Name of a "+
+		"templated policy to use for this role. May be specified multiple times. Format is "+
+		"TEMPLATENAME, TEMPLATENAME:NAME or TEMPLATENAME:NAME:DATACENTER1,DATACENTER2,... "+
+		"where NAME is the name variable required by some templates")
 	c.flags.BoolVar(&c.noMerge, "no-merge", false, "Do not merge the current role "+
 		"information with what is provided to the command. Instead overwrite all fields "+
 		"with the exception of the role ID which is immutable.")
@@ -108,6 +113,12 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	parsedTemplatedPolicies, err := acl.ExtractTemplatedPolicies(c.templatedPolicies)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Read the current role in both cases so we can fail better if not found.
 	currentRole, _, err := client.ACL().RoleRead(roleID, nil)
 	if err != nil {
@@ -126,6 +137,7 @@ func (c *cmd) Run(args []string) int {
 			Description:       c.description,
 			ServiceIdentities: parsedServiceIdents,
 			NodeIdentities:    parsedNodeIdents,
+			TemplatedPolicies: parsedTemplatedPolicies,
 		}
 
 		for _, policyName := range c.policyNames {
@@ -218,6 +230,8 @@ func (c *cmd) Run(args []string) int {
 				r.NodeIdentities = append(r.NodeIdentities, nodeid)
 			}
 		}
+
+		r.TemplatedPolicies = acl.MergeTemplatedPolicies(r.TemplatedPolicies, parsedTemplatedPolicies)
 	}
 
 	r, _, err = client.ACL().RoleUpdate(r, nil)
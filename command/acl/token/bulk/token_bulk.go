@@ -0,0 +1,168 @@
+package tokenbulk
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	format string
+
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	switch c.format {
+	case token.PrettyFormat, token.JSONFormat:
+	default:
+		c.UI.Error(fmt.Sprintf("Invalid format: %s", c.format))
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error(fmt.Sprintf("Must specify exactly one operations file or '-' for stdin (got %d arguments)", len(args)))
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load operations: %v", err))
+		return 1
+	}
+
+	var ops []*api.ACLTokenBulkOperation
+	if err := json.Unmarshal([]byte(data), &ops); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to decode operations: %v", err))
+		return 1
+	}
+
+	if len(ops) == 0 {
+		c.UI.Error("Must provide at least one operation")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	results, _, err := client.ACL().TokenBulk(ops, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error applying token operations: %s", err))
+		return 1
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	if c.format == token.JSONFormat {
+		out, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error formatting results: %s", err))
+			return 1
+		}
+		c.UI.Output(string(out))
+	} else {
+		for i, result := range results {
+			op := ops[i].Op
+			if result.Error != "" {
+				c.UI.Error(fmt.Sprintf("Operations[%d] (%s): %s", i, op, result.Error))
+				continue
+			}
+			c.UI.Info(fmt.Sprintf("Token %q %s successfully", result.AccessorID, bulkOpVerb(op)))
+		}
+		c.UI.Info(fmt.Sprintf("Applied %d operations, %d failed", len(results)-failed, failed))
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func bulkOpVerb(op api.ACLTokenBulkOp) string {
+	switch op {
+	case api.ACLTokenBulkCreate:
+		return "created"
+	case api.ACLTokenBulkDelete:
+		return "deleted"
+	default:
+		return "updated"
+	}
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Apply several ACL token operations in a single request"
+const help = `
+Usage: consul acl token bulk [options] FILE
+
+  Applies a list of ACL token operations in a single request. The operations
+  are read from a JSON file, or from stdin when FILE is '-'. Each operation has
+  an "Op" of "create", "update", "delete", "attach-policies" or
+  "detach-policies" and a "Token" using the same format as the
+  /v1/acl/token endpoint. Operations that fail validation are reported
+  individually and do not prevent the remaining operations from being applied.
+
+  Apply operations from a file:
+
+      $ consul acl token bulk ops.json
+
+  Apply operations from stdin and keep the generated secrets:
+
+      $ cat ops.json | consul acl token bulk -format=json -
+
+  For a full list of options and examples, please see the Consul documentation.
+`
@@ -0,0 +1,144 @@
+package tokenbulk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenBulkCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenBulkCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		stdin  string
+		output string
+	}{
+		"no args": {
+			args:   nil,
+			output: "Must specify exactly one operations file",
+		},
+		"too many args": {
+			args:   []string{"a.json", "b.json"},
+			output: "Must specify exactly one operations file",
+		},
+		"bad format": {
+			args:   []string{"-format=yaml", "-"},
+			output: "Invalid format: yaml",
+		},
+		"bad json": {
+			args:   []string{"-"},
+			stdin:  "{",
+			output: "Failed to decode operations",
+		},
+		"no operations": {
+			args:   []string{"-"},
+			stdin:  "[]",
+			output: "Must provide at least one operation",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := New(ui)
+			cmd.testStdin = strings.NewReader(tc.stdin)
+
+			require.Equal(t, 1, cmd.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestTokenBulkCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	existing, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "existing"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		cmd.testStdin = strings.NewReader(fmt.Sprintf(`[
+			{"Op": "create", "Token": {"Description": "bulk"}},
+			{"Op": "delete", "Token": {"AccessorID": %q}},
+			{"Op": "delete", "Token": {"AccessorID": "3a0f2d6b-4a5e-4b0c-9d4e-5a8f6c1b2e77"}}
+		]`, existing.AccessorID))
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-",
+		})
+		require.Equal(t, 1, code)
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "created successfully")
+		require.Contains(t, output, fmt.Sprintf("Token %q deleted successfully", existing.AccessorID))
+		require.Contains(t, output, "Applied 2 operations, 1 failed")
+		require.Contains(t, ui.ErrorWriter.String(), "Operations[2] (delete)")
+
+		_, _, err := client.ACL().TokenRead(existing.AccessorID, &api.QueryOptions{Token: "root"})
+		require.ErrorContains(t, err, "ACL not found")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		cmd.testStdin = strings.NewReader(`[{"Op": "create", "Token": {"Description": "bulk-json"}}]`)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-format=json",
+			"-",
+		})
+		require.Equal(t, 0, code)
+		require.Empty(t, ui.ErrorWriter.String())
+
+		var results []*api.ACLTokenBulkResult
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &results))
+		require.Len(t, results, 1)
+		require.NotNil(t, results[0].Token)
+		require.NotEmpty(t, results[0].Token.SecretID)
+		require.Equal(t, "bulk-json", results[0].Token.Description)
+	})
+}
@@ -26,19 +26,20 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	accessor      string
-	secret        string
-	policyIDs     []string
-	policyNames   []string
-	description   string
-	roleIDs       []string
-	roleNames     []string
-	serviceIdents []string
-	nodeIdents    []string
-	expirationTTL time.Duration
-	local         bool
-	showMeta      bool
-	format        string
+	accessor          string
+	secret            string
+	policyIDs         []string
+	policyNames       []string
+	description       string
+	roleIDs           []string
+	roleNames         []string
+	serviceIdents     []string
+	nodeIdents        []string
+	templatedPolicies []string
+	expirationTTL     time.Duration
+	local             bool
+	showMeta          bool
+	format            string
 }
 
 func (c *cmd) init() {
@@ -65,6 +66,10 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this token. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templatedPolicies), "templated-policy", "Name of a "+
+		"templated policy to use for this token. May be specified multiple times. Format is "+
+		"TEMPLATENAME, TEMPLATENAME:NAME or TEMPLATENAME:NAME:DATACENTER1,DATACENTER2,... "+
+		"where NAME is the name variable required by some templates")
 	c.flags.DurationVar(&c.expirationTTL, "expires-ttl", 0, "Duration of time this "+
 		"token should be valid for")
 	c.flags.StringVar(
@@ -87,8 +92,8 @@ func (c *cmd) Run(args []string) int {
 
 	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 &&
 		len(c.roleNames) == 0 && len(c.roleIDs) == 0 &&
-		len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 {
-		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name, -policy-id, -role-name, -role-id, -service-identity, or -node-identity at least once"))
+		len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 && len(c.templatedPolicies) == 0 {
+		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name, -policy-id, -role-name, -role-id, -service-identity, -node-identity, or -templated-policy at least once"))
 		return 1
 	}
 
@@ -122,6 +127,13 @@ func (c *cmd) Run(args []string) int {
 	}
 	newToken.NodeIdentities = parsedNodeIdents
 
+	parsedTemplatedPolicies, err := acl.ExtractTemplatedPolicies(c.templatedPolicies)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	newToken.TemplatedPolicies = parsedTemplatedPolicies
+
 	for _, policyName := range c.policyNames {
 		// We could resolve names to IDs here but there isn't any reason why its would be better
 		// than allowing the agent to do it.
@@ -200,6 +212,7 @@ Usage: consul acl token create [options]
                                     -role-id c630d4ef-6 \
                                     -role-name "db-updater" \
                                     -service-identity "web" \
-                                    -service-identity "db:east,west"
+                                    -service-identity "db:east,west" \
+                                    -templated-policy "builtin/dns"
 `
 )
@@ -6,10 +6,15 @@ import (
 
 	"github.com/mitchellh/cli"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
 )
 
+// bulkDeleteSize is the number of tokens deleted per request when deleting
+// the tokens matching a filter, to bound the size of the Raft log entries.
+const bulkDeleteSize = 500
+
 func New(ui cli.Ui) *cmd {
 	c := &cmd{UI: ui}
 	c.init()
@@ -22,6 +27,7 @@ type cmd struct {
 	http            *flags.HTTPFlags
 	help            string
 	tokenAccessorID string
+	filter          string
 
 	tokenID string // DEPRECATED
 }
@@ -31,7 +37,9 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.tokenAccessorID, "accessor-id", "", "The Accessor ID of the token to delete. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
-	c.http = &flags.HTTPFlags{}
+	c.flags.StringVar(&c.filter, "filter", "", "Delete all the tokens matching this filter "+
+		"expression instead of a single token. The expression is evaluated against the "+
+		"tokens returned by 'consul acl token list'.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -48,10 +56,24 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.filter != "" {
+		if c.tokenAccessorID != "" || c.tokenID != "" {
+			c.UI.Error("The -filter and -accessor-id parameters are mutually exclusive")
+			return 1
+		}
+
+		client, err := c.http.APIClient()
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+			return 1
+		}
+		return c.deleteFiltered(client)
+	}
+
 	tokenAccessor := c.tokenAccessorID
 	if tokenAccessor == "" {
 		if c.tokenID == "" {
-			c.UI.Error("Must specify the -accessor-id parameter")
+			c.UI.Error("Must specify the -accessor-id or -filter parameter")
 			return 1
 		} else {
 			tokenAccessor = c.tokenID
@@ -80,6 +102,56 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// deleteFiltered deletes the tokens matching the filter with bulk requests.
+func (c *cmd) deleteFiltered(client *api.Client) int {
+	tokens, _, err := client.ACL().TokenList(&api.QueryOptions{Filter: c.filter})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the token list: %v", err))
+		return 1
+	}
+	if len(tokens) == 0 {
+		c.UI.Info("No tokens matched the filter")
+		return 0
+	}
+
+	var deleted, failed int
+	for start := 0; start < len(tokens); start += bulkDeleteSize {
+		end := start + bulkDeleteSize
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+
+		ops := make([]*api.ACLTokenBulkOperation, 0, end-start)
+		for _, token := range tokens[start:end] {
+			ops = append(ops, &api.ACLTokenBulkOperation{
+				Op:    api.ACLTokenBulkDelete,
+				Token: &api.ACLToken{AccessorID: token.AccessorID},
+			})
+		}
+
+		results, _, err := client.ACL().TokenBulk(ops, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error deleting tokens: %v", err))
+			return 1
+		}
+		for _, result := range results {
+			if result.Error != "" {
+				c.UI.Error(fmt.Sprintf("Error deleting token %q: %s", result.AccessorID, result.Error))
+				failed++
+				continue
+			}
+			c.UI.Info(fmt.Sprintf("Token %q deleted successfully", result.AccessorID))
+			deleted++
+		}
+	}
+
+	if failed > 0 {
+		c.UI.Error(fmt.Sprintf("Deleted %d tokens, failed to delete %d tokens", deleted, failed))
+		return 1
+	}
+	return 0
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -102,5 +174,12 @@ Usage: consul acl token delete [options] -accessor-id TOKEN
       Delete by full ID:
 
           $ consul acl token delete -accessor-id b6b856da-5193-4e78-845a-7d61ca8371ba
+
+  Deletes all the ACL tokens matching a filter expression. The matching tokens
+  can be reviewed beforehand with 'consul acl token list' and the same filter.
+
+      Delete the tokens of the CI runners:
+
+          $ consul acl token delete -filter 'Description matches "^ci-runner-"'
 `
 )
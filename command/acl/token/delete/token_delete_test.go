@@ -7,6 +7,7 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
@@ -73,3 +74,76 @@ func TestTokenDeleteCommand(t *testing.T) {
 	assert.ErrorContains(t, err, "Unexpected response code: 403")
 	assert.ErrorContains(t, err, "ACL not found")
 }
+
+func TestTokenDeleteCommand_Filter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	var tokens []*api.ACLToken
+	for _, description := range []string{"ci-runner-1", "ci-runner-2", "web"} {
+		token, _, err := client.ACL().TokenCreate(
+			&api.ACLToken{Description: description},
+			&api.WriteOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+		tokens = append(tokens, token)
+	}
+
+	t.Run("mutually exclusive", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-accessor-id=" + tokens[0].AccessorID,
+			"-filter=Description == web",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "mutually exclusive")
+	})
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		`-filter=Description matches "^ci-runner-"`,
+	})
+	require.Equal(t, 0, code)
+	require.Empty(t, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, fmt.Sprintf("Token %q deleted successfully", tokens[0].AccessorID))
+	require.Contains(t, output, fmt.Sprintf("Token %q deleted successfully", tokens[1].AccessorID))
+	require.NotContains(t, output, tokens[2].AccessorID)
+
+	_, _, err := client.ACL().TokenRead(tokens[0].AccessorID, &api.QueryOptions{Token: "root"})
+	require.ErrorContains(t, err, "ACL not found")
+	_, _, err = client.ACL().TokenRead(tokens[2].AccessorID, &api.QueryOptions{Token: "root"})
+	require.NoError(t, err)
+
+	// Nothing matches the filter anymore.
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		`-filter=Description matches "^ci-runner-"`,
+	})
+	require.Equal(t, 0, code)
+	require.Contains(t, ui.OutputWriter.String(), "No tokens matched the filter")
+}
@@ -73,6 +73,13 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.ReplacedBy != "" {
+		buffer.WriteString(fmt.Sprintf("Replaced By:      %s\n", token.ReplacedBy))
+	}
+	if token.LastUsedTime != nil && !token.LastUsedTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used Time:   %v\n", *token.LastUsedTime))
+		buffer.WriteString(fmt.Sprintf("Last Used Addr:   %s\n", token.LastUsedAddr))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -106,6 +113,12 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(token.TemplatedPolicies) > 0 {
+		buffer.WriteString(fmt.Sprintln("Templated Policies:"))
+		for _, tp := range token.TemplatedPolicies {
+			buffer.WriteString(fmt.Sprintf("   %s\n", templatedPolicyString(tp)))
+		}
+	}
 
 	return buffer.String(), nil
 }
@@ -130,6 +143,13 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.ReplacedBy != "" {
+		buffer.WriteString(fmt.Sprintf("Replaced By:      %s\n", token.ReplacedBy))
+	}
+	if token.LastUsedTime != nil && !token.LastUsedTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used Time:   %v\n", *token.LastUsedTime))
+		buffer.WriteString(fmt.Sprintf("Last Used Addr:   %s\n", token.LastUsedAddr))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -199,6 +219,29 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 		}
 	}
 
+	formatTemplatedPolicy := func(templatedPolicy *api.ACLTemplatedPolicy, indent string) {
+		buffer.WriteString(indent + templatedPolicyString(templatedPolicy) + "\n")
+		tp := structs.ACLTemplatedPolicy{TemplateName: templatedPolicy.TemplateName, Datacenters: templatedPolicy.Datacenters}
+		if templatedPolicy.TemplateVariables != nil {
+			tp.TemplateVariables = &structs.ACLTemplatedPolicyVariables{Name: templatedPolicy.TemplateVariables.Name}
+		}
+		policy, err := tp.SyntheticPolicy(&entMeta)
+		if err != nil {
+			buffer.WriteString(fmt.Sprintf(indent+WHITESPACE_2+"Error: %v\n\n", err))
+			return
+		}
+		buffer.WriteString(fmt.Sprintf(indent+WHITESPACE_2+"Description: %s\n", policy.Description))
+		buffer.WriteString(indent + WHITESPACE_2 + "Rules:")
+		buffer.WriteString(strings.ReplaceAll(policy.Rules, "\n", "\n"+indent+WHITESPACE_4))
+		buffer.WriteString("\n\n")
+	}
+	if len(token.ACLToken.TemplatedPolicies) > 0 {
+		buffer.WriteString("Templated Policies:\n")
+		for _, templatedPolicy := range token.ACLToken.TemplatedPolicies {
+			formatTemplatedPolicy(templatedPolicy, WHITESPACE_2)
+		}
+	}
+
 	formatRole := func(role api.ACLRole, indent string) {
 		buffer.WriteString(fmt.Sprintf(indent+"Role Name: %s\n", role.Name))
 		buffer.WriteString(fmt.Sprintf(indent+WHITESPACE_2+"ID: %s\n", role.ID))
@@ -224,6 +267,13 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 				formatNodeIdentity(nodeIdentity, indent+WHITESPACE_4)
 			}
 		}
+
+		if len(role.TemplatedPolicies) > 0 {
+			buffer.WriteString(indent + WHITESPACE_2 + "Templated Policies:\n")
+			for _, templatedPolicy := range role.TemplatedPolicies {
+				formatTemplatedPolicy(templatedPolicy, indent+WHITESPACE_4)
+			}
+		}
 	}
 	if len(token.ACLToken.Roles) > 0 {
 		buffer.WriteString("Roles:\n")
@@ -299,6 +349,10 @@ func (f *prettyFormatter) formatTokenListEntry(token *api.ACLTokenListEntry) str
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.LastUsedTime != nil && !token.LastUsedTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used Time:   %v\n", *token.LastUsedTime))
+		buffer.WriteString(fmt.Sprintf("Last Used Addr:   %s\n", token.LastUsedAddr))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -332,9 +386,26 @@ func (f *prettyFormatter) formatTokenListEntry(token *api.ACLTokenListEntry) str
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(token.TemplatedPolicies) > 0 {
+		buffer.WriteString(fmt.Sprintln("Templated Policies:"))
+		for _, tp := range token.TemplatedPolicies {
+			buffer.WriteString(fmt.Sprintf("   %s\n", templatedPolicyString(tp)))
+		}
+	}
 	return buffer.String()
 }
 
+func templatedPolicyString(tp *api.ACLTemplatedPolicy) string {
+	dcs := "all"
+	if len(tp.Datacenters) > 0 {
+		dcs = strings.Join(tp.Datacenters, ", ")
+	}
+	if tp.TemplateVariables != nil && tp.TemplateVariables.Name != "" {
+		return fmt.Sprintf("%s (Name: %s, Datacenters: %s)", tp.TemplateName, tp.TemplateVariables.Name, dcs)
+	}
+	return fmt.Sprintf("%s (Datacenters: %s)", tp.TemplateName, dcs)
+}
+
 func newJSONFormatter(showMeta bool) Formatter {
 	return &jsonFormatter{showMeta}
 }
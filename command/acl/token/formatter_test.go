@@ -68,6 +68,8 @@ func TestFormatToken(t *testing.T) {
 				AuthMethodNamespace: "baz",
 				CreateTime:          time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
 				ExpirationTime:      timeRef(time.Date(2020, 5, 22, 19, 52, 31, 0, time.UTC)),
+				LastUsedTime:        timeRef(time.Date(2020, 5, 22, 19, 2, 31, 0, time.UTC)),
+				LastUsedAddr:        "10.0.0.1",
 				Hash:                []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
 				CreateIndex:         5,
 				ModifyIndex:         10,
@@ -171,6 +173,8 @@ func TestFormatTokenList(t *testing.T) {
 					AuthMethodNamespace: "baz",
 					CreateTime:          time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
 					ExpirationTime:      timeRef(time.Date(2020, 5, 22, 19, 52, 31, 0, time.UTC)),
+					LastUsedTime:        timeRef(time.Date(2020, 5, 22, 19, 2, 31, 0, time.UTC)),
+					LastUsedAddr:        "10.0.0.1",
 					Hash:                []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
 					CreateIndex:         5,
 					ModifyIndex:         10,
@@ -0,0 +1,120 @@
+package tokenrotate
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	tokenAccessorID string
+	overlap         time.Duration
+	format          string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.tokenAccessorID, "accessor-id", "", "The Accessor ID of the token to rotate. "+
+		"It may be specified as a unique ID prefix but will error if the prefix "+
+		"matches multiple token Accessor IDs")
+	c.flags.DurationVar(&c.overlap, "overlap", 0, "How long the rotated token remains valid "+
+		"after the replacement token is issued. Defaults to 1h and must be between 1m and 24h")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.tokenAccessorID == "" {
+		c.UI.Error("Cannot rotate a token without specifying the -accessor-id parameter")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	tok, err := acl.GetTokenAccessorIDFromPartial(client, c.tokenAccessorID)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error determining token Accessor ID: %v", err))
+		return 1
+	}
+
+	t, _, err := client.ACL().TokenRotate(tok, c.overlap, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error rotating token: %v", err))
+		return 1
+	}
+
+	formatter, err := token.NewFormatter(c.format, false)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatToken(t)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Rotate an ACL token"
+	help     = `
+Usage: consul acl token rotate [options]
+
+    This command issues a replacement for a token, with the same policies, roles
+    and identities but a new Accessor ID and Secret ID. The rotated token remains
+    valid for the overlap window and then expires, giving its users time to switch
+    to the replacement.
+
+    Example:
+
+        $ consul acl token rotate -accessor-id abcd -overlap 30m
+`
+)
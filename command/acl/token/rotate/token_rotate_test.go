@@ -0,0 +1,79 @@
+package tokenrotate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenRotateCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenRotateCommand_JSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+   primary_datacenter = "dc1"
+   acl {
+      enabled = true
+      tokens {
+         initial_management = "root"
+      }
+   }`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	_, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "test-policy"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "test", Policies: []*api.ACLTokenPolicyLink{{Name: "test-policy"}}},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	code := cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-accessor-id=" + token.AccessorID,
+		"-token=root",
+		"-overlap=10m",
+		"-format=json",
+	})
+	require.Empty(t, ui.ErrorWriter.String())
+	require.Equal(t, 0, code)
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, `"Description": "test"`)
+	require.Contains(t, output, "test-policy")
+	require.NotContains(t, output, token.SecretID)
+
+	rotated, _, err := client.ACL().TokenRead(token.AccessorID, &api.QueryOptions{Token: "root"})
+	require.NoError(t, err)
+	require.NotNil(t, rotated.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), *rotated.ExpirationTime, time.Minute)
+}
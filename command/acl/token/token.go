@@ -45,6 +45,10 @@ Usage: consul acl token <subcommand> [options] [args]
 
       $ consul acl token update -accessor-id 986193 -description "WonderToken"
 
+  Rotate a token, keeping the old one valid for 30 minutes:
+
+      $ consul acl token rotate -accessor-id 986193 -overlap 30m
+
   Read a token with an accessor ID:
 
     $ consul acl token read -accessor-id 986193
@@ -53,5 +57,13 @@ Usage: consul acl token <subcommand> [options] [args]
 
     $ consul acl token delete -accessor-id 986193
 
+  Delete all tokens matching a filter:
+
+    $ consul acl token delete -filter 'Description contains "ci-"'
+
+  Apply several token operations from a file:
+
+    $ consul acl token bulk ops.json
+
   For more examples, ask for subcommand help or view the documentation.
 `
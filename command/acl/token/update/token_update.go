@@ -32,11 +32,13 @@ type cmd struct {
 	roleNames          []string
 	serviceIdents      []string
 	nodeIdents         []string
+	templatedPolicies  []string
 	description        string
 	mergePolicies      bool
 	mergeRoles         bool
 	mergeServiceIdents bool
 	mergeNodeIdents    bool
+	mergeTemplated     bool
 	showMeta           bool
 	format             string
 
@@ -55,6 +57,8 @@ func (c *cmd) init() {
 		"with the existing service identities")
 	c.flags.BoolVar(&c.mergeNodeIdents, "merge-node-identities", false, "Merge the new node identities "+
 		"with the existing node identities")
+	c.flags.BoolVar(&c.mergeTemplated, "merge-templated-policies", false, "Merge the new templated policies "+
+		"with the existing templated policies")
 	c.flags.StringVar(&c.tokenAccessorID, "accessor-id", "", "The Accessor ID of the token to update. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
@@ -73,6 +77,10 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this token. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templatedPolicies), "templated-policy", "Name of a "+
+		"templated policy to use for this token. May be specified multiple times. Format is "+
+		"TEMPLATENAME, TEMPLATENAME:NAME or TEMPLATENAME:NAME:DATACENTER1,DATACENTER2,... "+
+		"where NAME is the name variable required by some templates")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -147,6 +155,12 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	parsedTemplatedPolicies, err := acl.ExtractTemplatedPolicies(c.templatedPolicies)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	if c.mergePolicies {
 		for _, policyName := range c.policyNames {
 			found := false
@@ -295,6 +309,12 @@ func (c *cmd) Run(args []string) int {
 		t.NodeIdentities = parsedNodeIdents
 	}
 
+	if c.mergeTemplated {
+		t.TemplatedPolicies = acl.MergeTemplatedPolicies(t.TemplatedPolicies, parsedTemplatedPolicies)
+	} else {
+		t.TemplatedPolicies = parsedTemplatedPolicies
+	}
+
 	t, _, err = client.ACL().TokenUpdate(t, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to update token %s: %v", tok, err))
@@ -0,0 +1,113 @@
+package batch
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error(fmt.Sprintf("Must specify exactly one batch file or '-' for stdin (got %d arguments)", len(args)))
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load batch: %v", err))
+		return 1
+	}
+
+	var batch api.CatalogBatch
+	if err := json.Unmarshal([]byte(data), &batch); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to decode batch: %v", err))
+		return 1
+	}
+
+	if len(batch.Register) == 0 && len(batch.Deregister) == 0 {
+		c.UI.Error("Batch must contain at least one registration or deregistration")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	if _, err := client.Catalog().Batch(&batch, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error applying catalog batch: %s", err))
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Applied %d registrations and %d deregistrations",
+		len(batch.Register), len(batch.Deregister)))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Atomically register and deregister catalog entries"
+const help = `
+Usage: consul catalog batch [options] FILE
+
+  Applies a set of catalog registrations and deregistrations in a single
+  atomic operation. Either every entry in the batch is applied or none are.
+  The batch is read from a JSON file, or from stdin when FILE is '-'. The file
+  contains "Register" and "Deregister" lists whose entries use the same
+  format as the /v1/catalog/register and /v1/catalog/deregister endpoints.
+
+  Registrations are applied before deregistrations.
+
+  To apply a batch from a file:
+
+      $ consul catalog batch sync.json
+
+  To apply a batch from stdin:
+
+      $ cat sync.json | consul catalog batch -
+
+  For a full list of options and examples, please see the Consul documentation.
+`
@@ -0,0 +1,92 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestCatalogBatchCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCatalogBatchCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		stdin  string
+		output string
+	}{
+		"no args": {
+			args:   nil,
+			output: "Must specify exactly one batch file",
+		},
+		"too many args": {
+			args:   []string{"a.json", "b.json"},
+			output: "Must specify exactly one batch file",
+		},
+		"bad json": {
+			args:   []string{"-"},
+			stdin:  "{",
+			output: "Failed to decode batch",
+		},
+		"empty batch": {
+			args:   []string{"-"},
+			stdin:  "{}",
+			output: "at least one registration or deregistration",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			c.testStdin = strings.NewReader(tc.stdin)
+
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestCatalogBatchCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`{
+		"Register": [
+			{"Node": "ext1", "Address": "10.0.0.1", "Service": {"Service": "billing", "Port": 8080}},
+			{"Node": "ext2", "Address": "10.0.0.2", "Service": {"Service": "billing", "Port": 8080}}
+		]
+	}`)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-",
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Applied 2 registrations and 0 deregistrations")
+
+	services, _, err := a.Client().Catalog().Service("billing", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+}
@@ -46,5 +46,14 @@ Usage: consul catalog <subcommand> [options] [args]
 
       $ consul catalog services
 
+  Atomically apply a batch of registrations and deregistrations:
+
+      $ consul catalog batch sync.json
+
+  Copy the catalog of a datacenter into another one:
+
+      $ consul catalog export -datacenter dc1 > catalog.json
+      $ consul catalog import -datacenter dc2 catalog.json
+
   For more examples, ask for subcommand help or view the documentation.
 `
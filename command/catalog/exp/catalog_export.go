@@ -0,0 +1,222 @@
+package exp
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+const (
+	// consulServiceName is the service of the Consul servers, which is
+	// managed by the servers of each datacenter.
+	consulServiceName = "consul"
+
+	// serfHealthCheckID is the check maintained by the servers for each
+	// member of the cluster.
+	serfHealthCheckID = "serfHealth"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	nodes    flags.AppendSliceValue
+	services flags.AppendSliceValue
+	nodeMeta map[string]string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Var(&c.nodes, "node", "Name of a node to export. This flag may be "+
+		"specified multiple times, and all nodes are exported when it isn't.")
+	c.flags.Var(&c.services, "service", "Name of a service to export. Only the "+
+		"nodes with one of the services are exported. This flag may be specified "+
+		"multiple times, and all services are exported when it isn't.")
+	c.flags.Var((*flags.FlagMapValue)(&c.nodeMeta), "node-meta", "Metadata to "+
+		"filter the exported nodes with, in the form key=value. This flag may be "+
+		"specified multiple times to filter on multiple keys.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if l := len(c.flags.Args()); l > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", l))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	batch, err := c.export(client)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error exporting the catalog: %s", err))
+		return 1
+	}
+
+	marshaled, err := json.MarshalIndent(batch, "", "\t")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error exporting the catalog: %s", err))
+		return 1
+	}
+
+	c.UI.Info(strings.TrimSpace(string(marshaled)))
+	return 0
+}
+
+// export returns the registrations of the nodes, services and checks
+// matching the filters. Each node is registered with its node checks, then
+// each of its services with their checks.
+func (c *cmd) export(client *api.Client) (*api.CatalogBatch, error) {
+	q := &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+		NodeMeta:   c.nodeMeta,
+	}
+	nodes, _, err := client.Catalog().Nodes(q)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+
+	batch := &api.CatalogBatch{}
+	for _, node := range nodes {
+		if len(c.nodes) > 0 && !contains(c.nodes, node.Node) {
+			continue
+		}
+
+		services, err := c.nodeServices(client, node.Node)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.services) > 0 && len(services) == 0 {
+			continue
+		}
+
+		checks, _, err := client.Health().Node(node.Node, &api.QueryOptions{AllowStale: c.http.Stale()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the checks of node %q: %w", node.Node, err)
+		}
+		checksByService := make(map[string]api.HealthChecks)
+		for _, check := range checks {
+			if check.CheckID == serfHealthCheckID {
+				continue
+			}
+			check.CreateIndex, check.ModifyIndex = 0, 0
+			checksByService[check.ServiceID] = append(checksByService[check.ServiceID], check)
+		}
+
+		batch.Register = append(batch.Register, &api.CatalogRegistration{
+			Node:            node.Node,
+			Address:         node.Address,
+			TaggedAddresses: node.TaggedAddresses,
+			NodeMeta:        node.Meta,
+			Checks:          checksByService[""],
+			Partition:       node.Partition,
+		})
+		for _, service := range services {
+			batch.Register = append(batch.Register, &api.CatalogRegistration{
+				Node:           node.Node,
+				Address:        node.Address,
+				Service:        service,
+				Checks:         checksByService[service.ID],
+				SkipNodeUpdate: true,
+				Partition:      node.Partition,
+			})
+		}
+	}
+	return batch, nil
+}
+
+// nodeServices returns the services of the node to export, sorted by ID.
+func (c *cmd) nodeServices(client *api.Client, node string) ([]*api.AgentService, error) {
+	catalogNode, _, err := client.Catalog().Node(node, &api.QueryOptions{AllowStale: c.http.Stale()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the services of node %q: %w", node, err)
+	}
+	if catalogNode == nil {
+		return nil, nil
+	}
+
+	var services []*api.AgentService
+	for _, service := range catalogNode.Services {
+		if service.Service == consulServiceName && service.Kind == api.ServiceKindTypical {
+			continue
+		}
+		if len(c.services) > 0 && !contains(c.services, service.Service) {
+			continue
+		}
+		// The indexes are assigned by the datacenter the services are
+		// imported into.
+		service.CreateIndex, service.ModifyIndex = 0, 0
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+	return services, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Exports nodes, services and checks from the catalog as JSON"
+	help     = `
+Usage: consul catalog export [options]
+
+  Retrieves the nodes of the catalog with their services and checks, and
+  writes their registrations to stdout as JSON. This can be used with the
+  command "consul catalog import" to copy them into another datacenter, for
+  migrations and disaster recovery drills. The output uses the format of
+  "consul catalog batch".
+
+      $ consul catalog export > catalog.json
+
+  The exported nodes can be filtered by name, metadata and services:
+
+      $ consul catalog export -service web -node-meta rack=r1 > web.json
+
+  The Consul servers' "consul" service and the "serfHealth" checks, which are
+  managed by the servers of each datacenter, are not exported.
+
+  For a full list of options and examples, please see the Consul documentation.
+`
+)
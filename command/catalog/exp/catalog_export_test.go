@@ -0,0 +1,115 @@
+package exp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestCatalogExportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCatalogExportCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	for _, reg := range []*api.CatalogRegistration{
+		{
+			Node:     "ext1",
+			Address:  "10.0.0.1",
+			NodeMeta: map[string]string{"rack": "r1"},
+			Service:  &api.AgentService{ID: "web-1", Service: "web", Port: 8080, Tags: []string{"v1"}},
+			Checks: api.HealthChecks{
+				{Node: "ext1", CheckID: "web-1-http", Name: "web", ServiceID: "web-1", Status: api.HealthPassing,
+					Definition: api.HealthCheckDefinition{HTTP: "http://10.0.0.1:8080/health"}},
+				{Node: "ext1", CheckID: "disk", Name: "disk", Status: api.HealthWarning},
+			},
+		},
+		{
+			// The node meta is repeated, since registrations replace it.
+			Node:     "ext1",
+			Address:  "10.0.0.1",
+			NodeMeta: map[string]string{"rack": "r1"},
+			Service:  &api.AgentService{ID: "db-1", Service: "db", Port: 5432},
+		},
+		{
+			Node:     "ext2",
+			Address:  "10.0.0.2",
+			NodeMeta: map[string]string{"rack": "r2"},
+			Service:  &api.AgentService{ID: "web-2", Service: "web", Port: 8080},
+		},
+	} {
+		_, err := client.Catalog().Register(reg, nil)
+		require.NoError(t, err)
+	}
+
+	export := func(t *testing.T, args ...string) *api.CatalogBatch {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append([]string{"-http-addr=" + a.HTTPAddr()}, args...))
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var batch api.CatalogBatch
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &batch))
+		return &batch
+	}
+
+	t.Run("all", func(t *testing.T) {
+		batch := export(t)
+
+		var names []string
+		nodeMeta := make(map[string]map[string]string)
+		for _, reg := range batch.Register {
+			if reg.Service != nil {
+				require.NotEqual(t, "consul", reg.Service.Service)
+				names = append(names, reg.Node+"/"+reg.Service.ID)
+			} else {
+				names = append(names, reg.Node)
+				nodeMeta[reg.Node] = reg.NodeMeta
+			}
+			for _, check := range reg.Checks {
+				require.NotEqual(t, "serfHealth", check.CheckID)
+			}
+		}
+		require.Subset(t, names, []string{"ext1", "ext1/db-1", "ext1/web-1", "ext2", "ext2/web-2"})
+		require.Equal(t, map[string]string{"rack": "r1"}, nodeMeta["ext1"])
+		require.Equal(t, map[string]string{"rack": "r2"}, nodeMeta["ext2"])
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		batch := export(t, "-service", "web", "-node-meta", "rack=r1")
+		require.Len(t, batch.Register, 2)
+
+		node := batch.Register[0]
+		require.Equal(t, "ext1", node.Node)
+		require.Nil(t, node.Service)
+		require.Equal(t, map[string]string{"rack": "r1"}, node.NodeMeta)
+		require.Len(t, node.Checks, 1)
+		require.Equal(t, "disk", node.Checks[0].CheckID)
+
+		service := batch.Register[1]
+		require.True(t, service.SkipNodeUpdate)
+		require.Equal(t, "web-1", service.Service.ID)
+		require.Equal(t, []string{"v1"}, service.Service.Tags)
+		require.Zero(t, service.Service.ModifyIndex)
+		require.Len(t, service.Checks, 1)
+		require.Equal(t, "http://10.0.0.1:8080/health", service.Checks[0].Definition.HTTP)
+	})
+}
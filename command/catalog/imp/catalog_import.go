@@ -0,0 +1,157 @@
+package imp
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+// defaultBatchSize is the default number of registrations applied at once.
+const defaultBatchSize = 64
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	batchSize int
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.IntVar(&c.batchSize, "batch-size", defaultBatchSize, "Number of "+
+		"registrations applied atomically at once. Larger exports are imported "+
+		"in several batches, in order.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error(fmt.Sprintf("Must specify exactly one export file or '-' for stdin (got %d arguments)", len(args)))
+		return 1
+	}
+	if c.batchSize <= 0 {
+		c.UI.Error("The -batch-size must be positive")
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load export: %v", err))
+		return 1
+	}
+
+	var batch api.CatalogBatch
+	if err := json.Unmarshal([]byte(data), &batch); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to decode export: %v", err))
+		return 1
+	}
+	if len(batch.Deregister) > 0 {
+		c.UI.Error("The export must not contain deregistrations, use \"consul catalog batch\" to apply them")
+		return 1
+	}
+	if len(batch.Register) == 0 {
+		c.UI.Error("The export must contain at least one registration")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	var nodes, services, checks int
+	for start := 0; start < len(batch.Register); start += c.batchSize {
+		end := start + c.batchSize
+		if end > len(batch.Register) {
+			end = len(batch.Register)
+		}
+
+		register := batch.Register[start:end]
+		for _, reg := range register {
+			// The entries are registered in the datacenter they are imported
+			// into, rather than the one they were exported from.
+			reg.Datacenter = ""
+			reg.ID = ""
+		}
+
+		if _, err := client.Catalog().Batch(&api.CatalogBatch{Register: register}, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error importing registrations %d to %d: %s", start+1, end, err))
+			if start > 0 {
+				c.UI.Error(fmt.Sprintf("The first %d registrations were imported", start))
+			}
+			return 1
+		}
+
+		for _, reg := range register {
+			if reg.Service != nil {
+				services++
+			} else if !reg.SkipNodeUpdate {
+				nodes++
+			}
+			checks += len(reg.Checks)
+			if reg.Check != nil {
+				checks++
+			}
+		}
+	}
+
+	c.UI.Info(fmt.Sprintf("Imported %d nodes, %d services and %d checks", nodes, services, checks))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Imports nodes, services and checks exported as JSON into the catalog"
+	help     = `
+Usage: consul catalog import [options] FILE
+
+  Registers the nodes, services and checks exported by "consul catalog export"
+  in the catalog of the target datacenter. The export is read from a JSON file,
+  or from stdin when FILE is '-'.
+
+      $ consul catalog import -datacenter dc2 catalog.json
+
+  The registrations are applied in batches of -batch-size registrations, each
+  of which is applied atomically. If a batch fails, the previous ones remain
+  applied and the import can be safely run again once the error is resolved,
+  since registering the same entries again updates them.
+
+  For a full list of options and examples, please see the Consul documentation.
+`
+)
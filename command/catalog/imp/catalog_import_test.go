@@ -0,0 +1,114 @@
+package imp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestCatalogImportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCatalogImportCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		stdin  string
+		output string
+	}{
+		"no args": {
+			args:   nil,
+			output: "Must specify exactly one export file",
+		},
+		"bad json": {
+			args:   []string{"-"},
+			stdin:  "{",
+			output: "Failed to decode export",
+		},
+		"empty export": {
+			args:   []string{"-"},
+			stdin:  "{}",
+			output: "at least one registration",
+		},
+		"deregistrations": {
+			args:   []string{"-"},
+			stdin:  `{"Deregister": [{"Node": "ext1"}]}`,
+			output: "must not contain deregistrations",
+		},
+		"bad batch size": {
+			args:   []string{"-batch-size", "0", "-"},
+			stdin:  "{}",
+			output: "-batch-size must be positive",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			c.testStdin = strings.NewReader(tc.stdin)
+
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestCatalogImportCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`{
+		"Register": [
+			{"Node": "ext1", "Address": "10.0.0.1", "Datacenter": "dc-old", "NodeMeta": {"rack": "r1"},
+			 "Checks": [{"Node": "ext1", "CheckID": "disk", "Name": "disk", "Status": "warning"}]},
+			{"Node": "ext1", "Address": "10.0.0.1", "SkipNodeUpdate": true,
+			 "Service": {"ID": "web-1", "Service": "web", "Port": 8080},
+			 "Checks": [{"Node": "ext1", "CheckID": "web-1-tcp", "Name": "web", "ServiceID": "web-1", "Status": "passing",
+			             "Definition": {"TCP": "10.0.0.1:8080", "Interval": "10s"}}]},
+			{"Node": "ext2", "Address": "10.0.0.2"},
+			{"Node": "ext2", "Address": "10.0.0.2", "SkipNodeUpdate": true,
+			 "Service": {"ID": "web-2", "Service": "web", "Port": 8080}}
+		]
+	}`)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-batch-size=3",
+		"-",
+	}
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Imported 2 nodes, 2 services and 2 checks")
+
+	client := a.Client()
+	services, _, err := client.Catalog().Service("web", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+
+	node, _, err := client.Catalog().Node("ext1", nil)
+	require.NoError(t, err)
+	require.Equal(t, "r1", node.Node.Meta["rack"])
+
+	checks, _, err := client.Health().Node("ext1", nil)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+}
@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/consul/command/catalog"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/hashicorp/consul/command/helpers"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 	"github.com/ryanuber/columnize"
 )
 
@@ -160,6 +162,17 @@ func detailedNodes(nodes []*api.Node, detailed bool) []string {
 	return result
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"near":    completion.Nodes(),
+		"service": completion.Services(),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
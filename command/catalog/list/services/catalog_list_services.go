@@ -9,8 +9,10 @@ import (
 	"text/tabwriter"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -126,6 +128,16 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"node": completion.Nodes(),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
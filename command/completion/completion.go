@@ -0,0 +1,292 @@
+// Package completion provides the predictors used for the dynamic shell
+// completion of the arguments and flags of the commands, with the names of
+// the resources known to the Consul agent.
+package completion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+const (
+	// requestTimeout bounds the time spent querying the agent, so that a
+	// slow or unreachable agent doesn't block the shell.
+	requestTimeout = 2 * time.Second
+
+	// cacheTTL is how long the results are reused. The shell runs a new
+	// process for each completion, so the results are cached on disk.
+	cacheTTL = 30 * time.Second
+)
+
+// lister returns the candidates for the completion from the agent.
+type lister func(ctx context.Context, client *api.Client, args complete.Args) ([]string, error)
+
+// cacheDir returns the directory of the cached results, it is a variable so
+// tests can change it. The file names are hashes of the queries, so the
+// tokens used aren't written to the disk.
+var cacheDir = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "consul", "completion")
+}
+
+// predictor returns a predictor querying the agent with list. The key
+// identifies the query in the cache, along with the HTTP flags of the
+// command line. Errors are ignored, and no candidate is returned.
+func predictor(key func(args complete.Args) string, list lister) complete.Predictor {
+	return complete.PredictFunc(func(args complete.Args) []string {
+		http, fs := httpFlags(args)
+		cacheKey := []string{key(args)}
+		fs.VisitAll(func(f *flag.Flag) {
+			if v := f.Value.String(); v != "" {
+				cacheKey = append(cacheKey, f.Name+"="+v)
+			}
+		})
+		// The environment configures the client as well.
+		for _, env := range []string{api.HTTPAddrEnvName, api.HTTPTokenEnvName, api.HTTPTokenFileEnvName} {
+			cacheKey = append(cacheKey, os.Getenv(env))
+		}
+
+		if values, ok := readCache(cacheKey); ok {
+			return values
+		}
+
+		client, err := http.APIClient()
+		if err != nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		values, err := list(ctx, client, args)
+		if err != nil {
+			return nil
+		}
+		sort.Strings(values)
+		writeCache(cacheKey, values)
+		return values
+	})
+}
+
+func staticKey(name string) func(complete.Args) string {
+	return func(complete.Args) string {
+		return name
+	}
+}
+
+// Services predicts the names of the services of the catalog.
+func Services() complete.Predictor {
+	return predictor(staticKey("services"), func(ctx context.Context, client *api.Client, _ complete.Args) ([]string, error) {
+		services, _, err := client.Catalog().Services((&api.QueryOptions{}).WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(services))
+		for name := range services {
+			names = append(names, name)
+		}
+		return names, nil
+	})
+}
+
+// LocalServiceIDs predicts the IDs of the services registered with the
+// local agent.
+func LocalServiceIDs() complete.Predictor {
+	return predictor(staticKey("local-services"), func(ctx context.Context, client *api.Client, _ complete.Args) ([]string, error) {
+		services, err := client.Agent().ServicesWithFilterOpts("", (&api.QueryOptions{}).WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(services))
+		for id := range services {
+			ids = append(ids, id)
+		}
+		return ids, nil
+	})
+}
+
+// Nodes predicts the names of the nodes of the catalog.
+func Nodes() complete.Predictor {
+	return predictor(staticKey("nodes"), func(ctx context.Context, client *api.Client, _ complete.Args) ([]string, error) {
+		nodes, _, err := client.Catalog().Nodes((&api.QueryOptions{}).WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			names = append(names, node.Node)
+		}
+		return names, nil
+	})
+}
+
+// ConfigEntryKinds predicts the kinds of config entries.
+func ConfigEntryKinds() complete.Predictor {
+	return complete.PredictSet(structs.AllConfigEntryKinds...)
+}
+
+// ConfigEntryNames predicts the names of the config entries of the kind
+// given with the -kind flag.
+func ConfigEntryNames() complete.Predictor {
+	kind := func(args complete.Args) string {
+		v, _ := flagValue(args.Completed, "kind")
+		return v
+	}
+	return predictor(func(args complete.Args) string {
+		return "config-entries/" + kind(args)
+	}, func(ctx context.Context, client *api.Client, args complete.Args) ([]string, error) {
+		kind := kind(args)
+		if kind == "" {
+			return nil, nil
+		}
+		entries, _, err := client.ConfigEntries().List(kind, (&api.QueryOptions{}).WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.GetName())
+		}
+		return names, nil
+	})
+}
+
+// KVKeys predicts the keys of the KV store, one level of the hierarchy at a
+// time: the keys and the prefixes, ending with "/", under the prefix being
+// typed.
+func KVKeys() complete.Predictor {
+	prefix := func(args complete.Args) string {
+		if idx := strings.LastIndex(args.Last, "/"); idx >= 0 {
+			return args.Last[:idx+1]
+		}
+		return ""
+	}
+	return predictor(func(args complete.Args) string {
+		return "kv/" + prefix(args)
+	}, func(ctx context.Context, client *api.Client, args complete.Args) ([]string, error) {
+		keys, _, err := client.KV().Keys(prefix(args), "/", (&api.QueryOptions{}).WithContext(ctx))
+		return keys, err
+	})
+}
+
+// Flags returns the completion of the flags of fs, using the predictors for
+// the values of the flags in predictors, keyed by flag name, and no
+// prediction for the others.
+func Flags(fs *flag.FlagSet, predictors map[string]complete.Predictor) complete.Flags {
+	result := make(complete.Flags)
+	fs.VisitAll(func(f *flag.Flag) {
+		p, ok := predictors[f.Name]
+		if !ok {
+			p = complete.PredictNothing
+		}
+		result["-"+f.Name] = p
+	})
+	return result
+}
+
+// httpFlags returns the HTTP flags of the command line, so that the agent is
+// queried with the same address, token and tenancy as the command.
+func httpFlags(args complete.Args) (*flags.HTTPFlags, *flag.FlagSet) {
+	http := &flags.HTTPFlags{}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	flags.Merge(fs, http.ClientFlags())
+	flags.Merge(fs, http.ServerFlags())
+	flags.Merge(fs, http.MultiTenancyFlags())
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if v, ok := flagValue(args.Completed, f.Name); ok {
+			// Invalid values are ignored, the command will report them.
+			_ = fs.Set(f.Name, v)
+		}
+	})
+	return http, fs
+}
+
+// flagValue returns the last value of the flag name in args, given either as
+// "-name=value" or "-name value".
+func flagValue(args []string, name string) (string, bool) {
+	var value string
+	var found bool
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		arg = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if k, v, ok := strings.Cut(arg, "="); ok {
+			if k == name {
+				value, found = v, true
+			}
+			continue
+		}
+		if arg == name && i+1 < len(args) {
+			value, found = args[i+1], true
+		}
+	}
+	return value, found
+}
+
+func cachePath(key []string) string {
+	dir := cacheDir()
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(key, "\x00")))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// readCache returns the cached values of key, if they are recent enough.
+func readCache(key []string) ([]string, bool) {
+	path := cachePath(key)
+	if path == "" {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > cacheTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, false
+	}
+	return values, true
+}
+
+// writeCache caches the values of key. The values may include the names of
+// the resources readable with the token, so only the user can read them.
+func writeCache(key, values []string) {
+	path := cachePath(key)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,91 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestFlagValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args  []string
+		value string
+		found bool
+	}{
+		"missing":        {[]string{"-name", "web"}, "", false},
+		"separate value": {[]string{"-kind", "service-defaults"}, "service-defaults", true},
+		"equals":         {[]string{"-kind=proxy-defaults"}, "proxy-defaults", true},
+		"double dash":    {[]string{"--kind", "mesh"}, "mesh", true},
+		"last wins":      {[]string{"-kind", "mesh", "-kind=service-router"}, "service-router", true},
+		"after --":       {[]string{"--", "-kind", "mesh"}, "", false},
+		"no value yet":   {[]string{"-kind"}, "", false},
+	}
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			value, found := flagValue(tc.args, "kind")
+			require.Equal(t, tc.found, found)
+			require.Equal(t, tc.value, value)
+		})
+	}
+}
+
+func TestPredictors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir := t.TempDir()
+	cacheDir = func() string { return dir }
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	require.NoError(t, client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		ID:   "web-1",
+		Name: "web",
+	}))
+	_, err := client.KV().Put(&api.KVPair{Key: "app/config/port", Value: []byte("8080")}, nil)
+	require.NoError(t, err)
+	_, err = client.KV().Put(&api.KVPair{Key: "app/name", Value: []byte("web")}, nil)
+	require.NoError(t, err)
+	_, _, err = client.ConfigEntries().Set(&api.ServiceConfigEntry{
+		Kind:     api.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	}, nil)
+	require.NoError(t, err)
+
+	args := func(completed ...string) complete.Args {
+		return complete.Args{Completed: append([]string{"-http-addr=" + a.HTTPAddr()}, completed...)}
+	}
+
+	require.Equal(t, []string{"web-1"}, LocalServiceIDs().Predict(args()))
+	require.Contains(t, Services().Predict(args()), "web")
+	require.Equal(t, []string{a.Config.NodeName}, Nodes().Predict(args()))
+	require.Equal(t, []string{"web"}, ConfigEntryNames().Predict(args("-kind", api.ServiceDefaults)))
+	require.Empty(t, ConfigEntryNames().Predict(args()))
+
+	kv := args()
+	kv.Last = "app/"
+	require.Equal(t, []string{"app/config/", "app/name"}, KVKeys().Predict(kv))
+
+	// The results are cached.
+	_, err = client.KV().Put(&api.KVPair{Key: "app/other", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"app/config/", "app/name"}, KVKeys().Predict(kv))
+
+	// An unreachable agent doesn't return any candidate.
+	require.Empty(t, Services().Predict(complete.Args{Completed: []string{"-http-addr=127.0.0.1:1"}}))
+}
@@ -43,6 +43,10 @@ Usage: consul config <subcommand> [options] [args]
 
     $ consul config list -kind service-defaults
 
+  Compare a config with the stored one:
+
+    $ consul config diff web.serviceconf.hcl
+
   Delete a config:
 
     $ consul config delete -kind service-defaults -name web
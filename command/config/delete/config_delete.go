@@ -5,9 +5,11 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/hashicorp/consul/command/helpers"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -152,6 +154,18 @@ func (c *cmd) validateArgs() error {
 	return nil
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"kind":     completion.ConfigEntryKinds(),
+		"name":     completion.ConfigEntryNames(),
+		"filename": complete.PredictFiles("*"),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -0,0 +1,378 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+// exitDiff is the exit code of the command in -check mode when the config
+// entry differs from the stored one.
+const exitDiff = 2
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	check     bool
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.check, "check", false,
+		"Exit with code 2 if the config entry differs from the stored one, "+
+			"or isn't stored, rather than 0. This is useful to detect drift "+
+			"in CI pipelines.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must provide exactly one positional argument to specify the config entry to compare")
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load data: %v", err))
+		return 1
+	}
+
+	entry, err := helpers.ParseConfigEntry(data)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to decode config entry input: %v", err))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connect to Consul agent: %s", err))
+		return 1
+	}
+
+	opts := &api.QueryOptions{
+		Namespace: entry.GetNamespace(),
+		Partition: entry.GetPartition(),
+	}
+	stored, _, err := client.ConfigEntries().Get(entry.GetKind(), entry.GetName(), opts)
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		stored, err = nil, nil
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading config entry %s/%s: %v", entry.GetKind(), entry.GetName(), err))
+		return 1
+	}
+
+	changes, err := diffEntries(stored, entry)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error comparing config entry %s/%s: %v", entry.GetKind(), entry.GetName(), err))
+		return 1
+	}
+
+	switch {
+	case stored == nil:
+		c.UI.Output(fmt.Sprintf("Config entry %s/%s is not stored:", entry.GetKind(), entry.GetName()))
+	case len(changes) == 0:
+		c.UI.Output(fmt.Sprintf("Config entry %s/%s matches the stored entry", entry.GetKind(), entry.GetName()))
+		return 0
+	default:
+		c.UI.Output(fmt.Sprintf("Config entry %s/%s differs from the stored entry:", entry.GetKind(), entry.GetName()))
+	}
+	for _, change := range changes {
+		c.UI.Output("  " + change.String())
+	}
+
+	if c.check {
+		return exitDiff
+	}
+	return 0
+}
+
+// change is the change of a field of a config entry.
+type change struct {
+	// Path is the path of the field, such as "Sources[0].Action".
+	Path string
+
+	// From and To are the values of the field, nil if it is unset.
+	From, To interface{}
+}
+
+func (c change) String() string {
+	switch {
+	case c.From == nil:
+		return fmt.Sprintf("+ %s: %s", c.Path, encodeValue(c.To))
+	case c.To == nil:
+		return fmt.Sprintf("- %s: %s", c.Path, encodeValue(c.From))
+	default:
+		return fmt.Sprintf("~ %s: %s => %s", c.Path, encodeValue(c.From), encodeValue(c.To))
+	}
+}
+
+func encodeValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// ignoredFields are the fields managed by the servers, which are never set in
+// config entry files.
+var ignoredFields = map[string]bool{
+	"CreateIndex": true,
+	"ModifyIndex": true,
+}
+
+// diffEntries returns the changes of the fields of the config entry from the
+// stored one, which is nil if the entry isn't stored.
+func diffEntries(stored, local api.ConfigEntry) ([]change, error) {
+	from, err := normalizeEntry(stored)
+	if err != nil {
+		return nil, err
+	}
+	to, err := normalizeEntry(local)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []change
+	diffValues("", from, to, &changes)
+	return changes, nil
+}
+
+// normalizeEntry converts the config entry to its JSON representation, without
+// the fields set to their default value so that a field left out of a file
+// matches the stored default.
+func normalizeEntry(entry api.ConfigEntry) (map[string]interface{}, error) {
+	if entry == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	for field := range ignoredFields {
+		delete(raw, field)
+	}
+	// The servers set the default namespace and partition.
+	for _, field := range []string{"Namespace", "Partition"} {
+		if raw[field] == "default" {
+			delete(raw, field)
+		}
+	}
+
+	normalized, _ := normalizeValue(raw).(map[string]interface{})
+	return normalized, nil
+}
+
+// normalizeValue returns the value without its empty fields, or nil if the
+// value is empty itself.
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if normalized := normalizeValue(value); normalized != nil {
+				v[key] = normalized
+			} else {
+				delete(v, key)
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, value := range v {
+			// Empty elements are kept so that the indexes still match.
+			if normalized := normalizeValue(value); normalized != nil {
+				v[i] = normalized
+			} else {
+				v[i] = map[string]interface{}{}
+			}
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+	case float64:
+		if v == 0 {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	}
+	return v
+}
+
+// diffValues appends the changes from the from value to the to value, down to
+// the fields of maps and the elements of lists so that each change is the
+// change of a single field, such as "Meta.owner".
+func diffValues(path string, from, to interface{}, changes *[]change) {
+	switch {
+	case from == nil && to == nil:
+		return
+	case from == nil:
+		if !isLeaf(to) {
+			from = emptyLike(to)
+		}
+	case to == nil:
+		if !isLeaf(from) {
+			to = emptyLike(from)
+		}
+	}
+	if from == nil || to == nil {
+		*changes = append(*changes, change{Path: path, From: from, To: to})
+		return
+	}
+
+	fromMap, fromOK := from.(map[string]interface{})
+	toMap, toOK := to.(map[string]interface{})
+	if fromOK && toOK {
+		keys := make(map[string]struct{}, len(fromMap)+len(toMap))
+		for key := range fromMap {
+			keys[key] = struct{}{}
+		}
+		for key := range toMap {
+			keys[key] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			diffValues(joinPath(path, key), fromMap[key], toMap[key], changes)
+		}
+		return
+	}
+
+	fromSlice, fromOK := from.([]interface{})
+	toSlice, toOK := to.([]interface{})
+	if fromOK && toOK {
+		for i := 0; i < len(fromSlice) || i < len(toSlice); i++ {
+			var fromElem, toElem interface{}
+			if i < len(fromSlice) {
+				fromElem = fromSlice[i]
+			}
+			if i < len(toSlice) {
+				toElem = toSlice[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), fromElem, toElem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, change{Path: path, From: from, To: to})
+	}
+}
+
+// isLeaf returns whether the value is reported as a whole when it is added or
+// removed: a value which isn't a map or a list, or an empty map, which is how
+// the empty elements of lists are normalized.
+func isLeaf(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return true
+}
+
+// emptyLike returns an empty map or list of the type of the value, so that
+// the fields of an added or removed map or list are diffed one by one.
+func emptyLike(v interface{}) interface{} {
+	if _, ok := v.([]interface{}); ok {
+		return []interface{}{}
+	}
+	return map[string]interface{}{}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	return path + "." + key
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Compare a centralized config entry with the stored one"
+	help     = `
+Usage: consul config diff [options] <configuration>
+
+  Compare a config entry with the one currently stored, field by field.
+  The configuration argument is either a file path or '-' to indicate
+  that the config should be read from stdin. The data should be either
+  in HCL or JSON form.
+
+  The fields left out of the configuration and the ones set to their
+  default value are considered equal. The fields added, removed and
+  changed by writing the configuration are prefixed with '+', '-' and
+  '~' respectively.
+
+  Example:
+
+    $ consul config diff web.service.hcl
+
+  Exit with code 2 if the config entry differs from the stored one,
+  for instance in a CI pipeline:
+
+    $ consul config diff -check web.service.hcl
+`
+)
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestConfigDiff_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestConfigDiff(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	_, _, err := client.ConfigEntries().Set(&api.ServiceConfigEntry{
+		Kind:     api.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+		Meta: map[string]string{
+			"owner": "team-a",
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	run := func(t *testing.T, config string, extraArgs ...string) (int, *cli.MockUi) {
+		f := testutil.TempFile(t, "config-diff-svc-web.hcl")
+		_, err := f.WriteString(config)
+		require.NoError(t, err)
+
+		ui := cli.NewMockUi()
+		args := append([]string{"-http-addr=" + a.HTTPAddr()}, extraArgs...)
+		code := New(ui).Run(append(args, f.Name()))
+		require.Empty(t, ui.ErrorWriter.String())
+		return code, ui
+	}
+
+	t.Run("matches", func(t *testing.T) {
+		code, ui := run(t, `
+			Kind = "service-defaults"
+			Name = "web"
+			Protocol = "http"
+			Mode = ""
+			Meta {
+				owner = "team-a"
+			}
+		`, "-check")
+		require.Equal(t, 0, code)
+		require.Contains(t, ui.OutputWriter.String(), "Config entry service-defaults/web matches the stored entry")
+	})
+
+	t.Run("differs", func(t *testing.T) {
+		config := `
+			Kind = "service-defaults"
+			Name = "web"
+			Protocol = "grpc"
+			MaxInboundConnections = 10
+		`
+		code, ui := run(t, config)
+		require.Equal(t, 0, code)
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Config entry service-defaults/web differs from the stored entry")
+		require.Contains(t, output, `~ Protocol: "http" => "grpc"`)
+		require.Contains(t, output, `+ MaxInboundConnections: 10`)
+		require.Contains(t, output, `- Meta.owner: "team-a"`)
+
+		code, _ = run(t, config, "-check")
+		require.Equal(t, exitDiff, code)
+	})
+
+	t.Run("not stored", func(t *testing.T) {
+		code, ui := run(t, `
+			Kind = "service-defaults"
+			Name = "api"
+			Protocol = "http"
+		`, "-check")
+		require.Equal(t, exitDiff, code)
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Config entry service-defaults/api is not stored")
+		require.Contains(t, output, `+ Protocol: "http"`)
+	})
+}
+
+func TestDiffValues(t *testing.T) {
+	from := normalizeValue(map[string]interface{}{
+		"Sources": []interface{}{
+			map[string]interface{}{"Name": "web", "Action": "allow"},
+			map[string]interface{}{"Name": "db", "Action": "deny"},
+		},
+		"Config": map[string]interface{}{
+			"protocol":    "http",
+			"envoy.stats": "on",
+		},
+		"Mode": "",
+	})
+	to := normalizeValue(map[string]interface{}{
+		"Sources": []interface{}{
+			map[string]interface{}{"Name": "web", "Action": "deny"},
+		},
+		"Config": map[string]interface{}{
+			"protocol": "http",
+		},
+	})
+
+	var changes []change
+	diffValues("", from, to, &changes)
+
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	require.Equal(t, []string{
+		`- Config["envoy.stats"]: "on"`,
+		`~ Sources[0].Action: "allow" => "deny"`,
+		`- Sources[1].Action: "deny"`,
+		`- Sources[1].Name: "db"`,
+	}, lines)
+}
@@ -4,8 +4,10 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -61,6 +63,16 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"kind": completion.ConfigEntryKinds(),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
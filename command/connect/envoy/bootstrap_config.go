@@ -10,11 +10,17 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 )
 
 const (
 	selfAdminName = "self_admin"
+
+	// The static layer of the Envoy runtime always raises the maximum size of
+	// the regexes, unless it is overridden with envoy_runtime_flags.
+	defaultRE2MaxProgramSizeErrorLevelFlag = "re2.max_program_size.error_level"
+	defaultRE2MaxProgramSizeErrorLevel     = 1048576
 )
 
 // BootstrapConfig is the set of keys we care about in a Connect.Proxy.Config
@@ -71,6 +77,12 @@ type BootstrapConfig struct {
 	// a 404.
 	StatsBindAddr string `mapstructure:"envoy_stats_bind_addr"`
 
+	// MetricsMergingPort is the port `consul connect merge-metrics` serves the
+	// merged metrics of the proxy and its service on. When set, the
+	// PrometheusBindAddr listener points to the merged metrics rather than to
+	// the Envoy admin API, unless -prometheus-backend-port is given.
+	MetricsMergingPort string `mapstructure:"metrics_merging_port"`
+
 	// ReadyBindAddr configures an <ip>:<port> on which Envoy will listen and
 	// expose a single /ready HTTP endpoint. This is useful for checking the
 	// liveness of an Envoy instance when no other listeners are garaunteed to be
@@ -149,6 +161,26 @@ type BootstrapConfig struct {
 	// the bootstrap config. It's format may vary based on Envoy version used.
 	// See https://www.envoyproxy.io/docs/envoy/v1.9.0/api-v2/config/trace/v2/trace.proto.
 	TracingConfigJSON string `mapstructure:"envoy_tracing_json"`
+
+	// EnableInternalListeners enables the envoy.bootstrap.internal_listener
+	// bootstrap extension, which Envoy requires to accept the connections of
+	// internal listeners. Internal listeners let the Envoy extensions chain
+	// several stages of processing without extra TCP hops. It requires Envoy
+	// 1.23 or later.
+	EnableInternalListeners bool `mapstructure:"envoy_enable_internal_listeners"`
+
+	// LoadStatsCluster is the name of the cluster of the Load Reporting
+	// Service (LRS) collector that Envoy sends the load reports of its
+	// clusters to. The cluster must be defined in StaticClustersJSON. The
+	// clusters only report their load when the builtin/load-reporting
+	// extension is applied to them.
+	LoadStatsCluster string `mapstructure:"envoy_load_stats_cluster"`
+
+	// RuntimeFlags are Envoy runtime flags to set in the static layer of the
+	// layered runtime, such as envoy.reloadable_features.* feature flags.
+	// Only the flags allowed by structs.ValidateEnvoyRuntimeFlags can be
+	// set. Setting it in proxy-defaults applies it to every proxy.
+	RuntimeFlags map[string]interface{} `mapstructure:"envoy_runtime_flags"`
 }
 
 // Template returns the bootstrap template to use as a base.
@@ -211,6 +243,11 @@ func (c *BootstrapConfig) ConfigureArgs(args *BootstrapTplArgs, omitDeprecatedTa
 	if c.StaticListenersJSON != "" {
 		args.StaticListenersJSON = c.StaticListenersJSON
 	}
+	// The merged metrics are served on the host of the Envoy admin API, which
+	// is where the "prometheus_backend" cluster points to.
+	if args.PrometheusBackendPort == "" && c.MetricsMergingPort != "" && c.MetricsMergingPort != "0" {
+		args.PrometheusBackendPort = c.MetricsMergingPort
+	}
 	// Setup prometheus if needed. This MUST happen after the Static*JSON is set above
 	if c.PrometheusBindAddr != "" {
 		if err := c.generateListenerConfig(args, c.PrometheusBindAddr, "envoy_prometheus_metrics", "path", args.PrometheusScrapePath, "/stats/prometheus", args.PrometheusBackendPort); err != nil {
@@ -238,6 +275,30 @@ func (c *BootstrapConfig) ConfigureArgs(args *BootstrapTplArgs, omitDeprecatedTa
 		args.StatsFlushInterval = c.StatsFlushInterval
 	}
 
+	args.EnableInternalListeners = c.EnableInternalListeners
+
+	if c.LoadStatsCluster != "" {
+		args.LoadStatsCluster = c.LoadStatsCluster
+	}
+
+	if len(c.RuntimeFlags) > 0 {
+		if err := structs.ValidateEnvoyRuntimeFlags(c.RuntimeFlags); err != nil {
+			return err
+		}
+
+		layer := map[string]interface{}{
+			defaultRE2MaxProgramSizeErrorLevelFlag: defaultRE2MaxProgramSizeErrorLevel,
+		}
+		for name, value := range c.RuntimeFlags {
+			layer[name] = value
+		}
+		layerJSON, err := json.Marshal(layer)
+		if err != nil {
+			return err
+		}
+		args.RuntimeStaticLayerJSON = string(layerJSON)
+	}
+
 	return nil
 }
 
@@ -439,6 +500,30 @@ func resourceTagSpecifiers(omitDeprecatedTags bool) ([]string, error) {
 		{"consul.upstream.partition",
 			fmt.Sprintf(`^(?:tcp|http)\.upstream\.(%s(?:\.%s)?(?:\.(%s))?\.%s\.)`,
 				reSegment, reSegment, reSegment, reSegment)},
+
+		// Peered mesh gateway listener metrics are prefixed by consul.mesh_gateway
+		//
+		// Listener metric name format:
+		// <tcp|http>.mesh_gateway_<local|remote>_peered.<service>.<namespace>.<partition>.<peer>
+		//
+		// Examples:
+		// - tcp.mesh_gateway_local_peered.db.default.default.cloudpeer.downstream_cx_total: 0
+		// - http.mesh_gateway_remote_peered.web.frontend.west.cloudpeer.downstream_cx_tx_bytes_total: 0
+		{"consul.mesh_gateway.service",
+			fmt.Sprintf(`^(?:tcp|http)\.mesh_gateway_(?:local|remote)_peered\.((%s)\.%s\.%s\.%s\.)`,
+				reSegment, reSegment, reSegment, reSegment)},
+
+		{"consul.mesh_gateway.namespace",
+			fmt.Sprintf(`^(?:tcp|http)\.mesh_gateway_(?:local|remote)_peered\.(%s\.(%s)\.%s\.%s\.)`,
+				reSegment, reSegment, reSegment, reSegment)},
+
+		{"consul.mesh_gateway.partition",
+			fmt.Sprintf(`^(?:tcp|http)\.mesh_gateway_(?:local|remote)_peered\.(%s\.%s\.(%s)\.%s\.)`,
+				reSegment, reSegment, reSegment, reSegment)},
+
+		{"consul.mesh_gateway.peer",
+			fmt.Sprintf(`^(?:tcp|http)\.mesh_gateway_(?:local|remote)_peered\.(%s\.%s\.%s\.(%s)\.)`,
+				reSegment, reSegment, reSegment, reSegment)},
 	}
 
 	// These tags were deprecated in Consul 1.9.0
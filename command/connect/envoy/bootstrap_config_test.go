@@ -878,6 +878,30 @@ func TestBootstrapConfig_ConfigureArgs(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "prometheus-bind-addr-with-metrics-merging",
+			input: BootstrapConfig{
+				PrometheusBindAddr: "0.0.0.0:9000",
+				MetricsMergingPort: "20100",
+			},
+			baseArgs: BootstrapTplArgs{
+				AdminBindAddress:     "127.0.0.1",
+				AdminBindPort:        "19000",
+				PrometheusScrapePath: "/metrics",
+			},
+			wantArgs: BootstrapTplArgs{
+				AdminBindAddress: "127.0.0.1",
+				AdminBindPort:    "19000",
+				// Should point to the merged metrics with the
+				// "prometheus_backend" cluster
+				StaticClustersJSON:    expectedPrometheusBackendCluster,
+				StaticListenersJSON:   expectedPromListenerWithPrometheusBackendCluster,
+				StatsConfigJSON:       defaultStatsConfigJSON,
+				PrometheusBackendPort: "20100",
+				PrometheusScrapePath:  "/metrics",
+			},
+			wantErr: false,
+		},
 		{
 			name: "prometheus-bind-addr-with-backend-and-tls",
 			input: BootstrapConfig{
@@ -1004,6 +1028,64 @@ func TestBootstrapConfig_ConfigureArgs(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "enable-internal-listeners",
+			input: BootstrapConfig{
+				EnableInternalListeners: true,
+			},
+			wantArgs: BootstrapTplArgs{
+				StatsConfigJSON:         defaultStatsConfigJSON,
+				EnableInternalListeners: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "load-stats-cluster",
+			input: BootstrapConfig{
+				LoadStatsCluster: "lrs_collector",
+			},
+			wantArgs: BootstrapTplArgs{
+				StatsConfigJSON:  defaultStatsConfigJSON,
+				LoadStatsCluster: "lrs_collector",
+			},
+			wantErr: false,
+		},
+		{
+			name: "runtime-flags",
+			input: BootstrapConfig{
+				RuntimeFlags: map[string]interface{}{
+					"envoy.reloadable_features.http_reject_path_with_fragment": false,
+					"re2.max_program_size.warn_level":                          float64(1000),
+				},
+			},
+			wantArgs: BootstrapTplArgs{
+				StatsConfigJSON:        defaultStatsConfigJSON,
+				RuntimeStaticLayerJSON: `{"envoy.reloadable_features.http_reject_path_with_fragment":false,"re2.max_program_size.error_level":1048576,"re2.max_program_size.warn_level":1000}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "runtime-flags-override-default",
+			input: BootstrapConfig{
+				RuntimeFlags: map[string]interface{}{
+					"re2.max_program_size.error_level": float64(200),
+				},
+			},
+			wantArgs: BootstrapTplArgs{
+				StatsConfigJSON:        defaultStatsConfigJSON,
+				RuntimeStaticLayerJSON: `{"re2.max_program_size.error_level":200}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "err-runtime-flag-not-allowed",
+			input: BootstrapConfig{
+				RuntimeFlags: map[string]interface{}{
+					"upstream.healthy_panic_threshold": float64(0),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "err-bad-prometheus-addr",
 			input: BootstrapConfig{
@@ -1506,6 +1588,26 @@ func TestConsulTagSpecifiers(t *testing.T) {
 				"consul.upstream.service":   {"web.frontend.cloudpeer.", "web"},
 			},
 		},
+		{
+			name: "tcp mesh gateway exported to peer listener",
+			stat: "tcp.mesh_gateway_local_peered.db.default.default.cloudpeer.downstream_cx_rx_bytes_total",
+			expect: map[string][]string{
+				"consul.mesh_gateway.service":   {"db.default.default.cloudpeer.", "db"},
+				"consul.mesh_gateway.namespace": {"db.default.default.cloudpeer.", "default"},
+				"consul.mesh_gateway.partition": {"db.default.default.cloudpeer.", "default"},
+				"consul.mesh_gateway.peer":      {"db.default.default.cloudpeer.", "cloudpeer"},
+			},
+		},
+		{
+			name: "http mesh gateway imported from peer listener",
+			stat: "http.mesh_gateway_remote_peered.web.frontend.west.cloudpeer.downstream_cx_total",
+			expect: map[string][]string{
+				"consul.mesh_gateway.service":   {"web.frontend.west.cloudpeer.", "web"},
+				"consul.mesh_gateway.namespace": {"web.frontend.west.cloudpeer.", "frontend"},
+				"consul.mesh_gateway.partition": {"web.frontend.west.cloudpeer.", "west"},
+				"consul.mesh_gateway.peer":      {"web.frontend.west.cloudpeer.", "cloudpeer"},
+			},
+		},
 	}
 
 	for _, tc := range cases {
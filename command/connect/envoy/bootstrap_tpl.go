@@ -98,6 +98,22 @@ type BootstrapTplArgs struct {
 	// See https://www.envoyproxy.io/docs/envoy/v1.9.0/api-v2/config/trace/v2/trace.proto.
 	TracingConfigJSON string
 
+	// EnableInternalListeners enables the envoy.bootstrap.internal_listener
+	// bootstrap extension, see
+	// https://www.envoyproxy.io/docs/envoy/v1.23.0/api-v3/extensions/bootstrap/internal_listener/v3/internal_listener.proto.
+	EnableInternalListeners bool
+
+	// LoadStatsCluster is the name of the cluster of the Load Reporting
+	// Service collector, see
+	// https://www.envoyproxy.io/docs/envoy/v1.21.0/api-v3/config/bootstrap/v3/bootstrap.proto#envoy-v3-api-field-config-bootstrap-v3-clustermanager-load-stats-config.
+	LoadStatsCluster string
+
+	// RuntimeStaticLayerJSON is a JSON string containing the object to render
+	// as the static layer of the layered runtime, see
+	// https://www.envoyproxy.io/docs/envoy/v1.21.0/configuration/operations/runtime.
+	// The default static layer is rendered if it is empty.
+	RuntimeStaticLayerJSON string
+
 	// Namespace is the Consul Enterprise Namespace of the proxy service instance
 	// as registered with the Consul agent.
 	Namespace string
@@ -189,9 +205,13 @@ const bootstrapTemplate = `{
     "layers": [
       {
         "name": "base",
+        {{- if .RuntimeStaticLayerJSON }}
+        "static_layer": {{ .RuntimeStaticLayerJSON }}
+        {{- else }}
         "static_layer": {
           "re2.max_program_size.error_level": 1048576
         }
+        {{- end }}
       }
     ]
   },
@@ -273,6 +293,31 @@ const bootstrapTemplate = `{
   {{- if .TracingConfigJSON }}
   "tracing": {{ .TracingConfigJSON }},
   {{- end }}
+  {{- if .LoadStatsCluster }}
+  "cluster_manager": {
+    "load_stats_config": {
+      "api_type": "GRPC",
+      "transport_api_version": "V3",
+      "grpc_services": [
+        {
+          "envoy_grpc": {
+            "cluster_name": "{{ .LoadStatsCluster }}"
+          }
+        }
+      ]
+    }
+  },
+  {{- end }}
+  {{- if .EnableInternalListeners }}
+  "bootstrap_extensions": [
+    {
+      "name": "envoy.bootstrap.internal_listener",
+      "typed_config": {
+        "@type": "type.googleapis.com/envoy.extensions.bootstrap.internal_listener.v3.InternalListener"
+      }
+    }
+  ],
+  {{- end }}
   "dynamic_resources": {
     "lds_config": {
       "ads": {},
@@ -69,6 +69,13 @@ type cmd struct {
 	ignoreEnvoyCompatibility bool
 	enableLogging            bool
 
+	// hot restart
+	hotRestart         bool
+	restartEpoch       int
+	baseIDPath         string
+	drainTime          time.Duration
+	parentShutdownTime time.Duration
+
 	// mesh gateway registration information
 	register           bool
 	lanAddress         ServiceAddressValue
@@ -228,6 +235,26 @@ func (c *cmd) init() {
 	c.flags.BoolVar(&c.enableLogging, "enable-config-gen-logging", false,
 		"Output debug log messages during config generation")
 
+	c.flags.BoolVar(&c.hotRestart, "hot-restart", false,
+		"Run Envoy with hot restart enabled so that a new Envoy process, for example "+
+			"one running an upgraded Envoy binary, can take over the listeners of the "+
+			"running one without dropping connections. Each new process must be started "+
+			"with the next -restart-epoch.")
+	c.flags.IntVar(&c.restartEpoch, "restart-epoch", 0,
+		"The hot restart epoch of the Envoy process. It must be 0 for the first process "+
+			"and be incremented for each hot restart. Requires -hot-restart.")
+	c.flags.StringVar(&c.baseIDPath, "base-id-path", "",
+		"Path of the file where the first Envoy process writes the base ID of its shared "+
+			"memory and domain sockets, and where the next processes read it from. Set it "+
+			"to hot restart several Envoy processes on the same host. Requires -hot-restart.")
+	c.flags.DurationVar(&c.drainTime, "drain-time", 0,
+		"How long the previous Envoy process drains its connections during a hot "+
+			"restart. Defaults to Envoy's 600s. Requires -hot-restart.")
+	c.flags.DurationVar(&c.parentShutdownTime, "parent-shutdown-time", 0,
+		"How long the new Envoy process waits before shutting down the previous one "+
+			"during a hot restart. It must be greater than the drain time. Defaults to "+
+			"Envoy's 900s. Requires -hot-restart.")
+
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.MultiTenancyFlags())
@@ -306,6 +333,11 @@ func (c *cmd) run(args []string) int {
 		return 1
 	}
 
+	if err := c.validateHotRestart(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Fixup for deprecated mesh-gateway flag
 	if c.meshGateway && c.gateway != "" {
 		c.UI.Error("The mesh-gateway flag is deprecated and cannot be used alongside the gateway flag")
@@ -516,8 +548,14 @@ func (c *cmd) run(args []string) int {
 		}
 	}
 
+	hotRestartArgs, err := c.hotRestartArgs()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	c.logger.Debug("Executing envoy binary")
-	err = execEnvoy(binary, nil, args, bootstrapJson)
+	err = execEnvoy(binary, hotRestartArgs, args, bootstrapJson)
 	if err == errUnsupportedOS {
 		c.UI.Error("Directly running Envoy is only supported on linux and macOS " +
 			"since envoy itself doesn't build on other platforms currently.")
@@ -973,6 +1011,13 @@ Usage: consul connect envoy [options] [-- pass-through options]
   dash followed by a list of options.
 
     $ consul connect envoy -sidecar-for web -- --log-level debug
+
+  Envoy can be hot restarted, for example to upgrade its binary without
+  dropping connections, by starting a new process with the next restart epoch
+  once the first one runs.
+
+    $ consul connect envoy -sidecar-for web -hot-restart -base-id-path /var/run/web-envoy.base-id
+    $ consul connect envoy -sidecar-for web -hot-restart -base-id-path /var/run/web-envoy.base-id -restart-epoch 1
 `
 )
 
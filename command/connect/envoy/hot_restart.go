@@ -0,0 +1,162 @@
+package envoy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDrainTime and defaultParentShutdownTime are Envoy's defaults for
+	// the --drain-time-s and --parent-shutdown-time-s options.
+	defaultDrainTime          = 600 * time.Second
+	defaultParentShutdownTime = 900 * time.Second
+)
+
+// baseIDOptions are the Envoy options that set the base ID of the shared
+// memory and unix domain sockets used to coordinate hot restarts.
+var baseIDOptions = []string{
+	"--base-id",
+	"--use-dynamic-base-id",
+	"--base-id-path",
+}
+
+func isHotRestartOption(s string) bool {
+	restartOpts := []string{
+		"--restart-epoch",
+		"--hot-restart-version",
+		"--drain-time-s",
+		"--parent-shutdown-time-s",
+	}
+	return hasOption(s, restartOpts)
+}
+
+func hasHotRestartOption(argSets ...[]string) bool {
+	for _, args := range argSets {
+		for _, opt := range args {
+			if isHotRestartOption(opt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasOption(s string, opts []string) bool {
+	for _, opt := range opts {
+		if s == opt {
+			return true
+		}
+		if strings.HasPrefix(s, opt+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateHotRestart validates the hot restart flags against each other and
+// the pass-through options given to Envoy.
+func (c *cmd) validateHotRestart(args []string) error {
+	if !c.hotRestart {
+		if c.restartEpoch != 0 || c.baseIDPath != "" || c.drainTime != 0 || c.parentShutdownTime != 0 {
+			return errors.New("'-restart-epoch', '-base-id-path', '-drain-time' and '-parent-shutdown-time' require '-hot-restart'")
+		}
+		return nil
+	}
+
+	if c.bootstrap {
+		return errors.New("'-hot-restart' cannot be used with '-bootstrap'")
+	}
+
+	for _, arg := range args {
+		if isHotRestartOption(arg) || hasOption(arg, baseIDOptions) {
+			opt := strings.SplitN(arg, "=", 2)[0]
+			return fmt.Errorf("'-hot-restart' cannot be used with the %s pass-through option", opt)
+		}
+	}
+
+	if c.restartEpoch < 0 {
+		return errors.New("'-restart-epoch' cannot be negative")
+	}
+
+	drainTime, parentShutdownTime := defaultDrainTime, defaultParentShutdownTime
+	for _, f := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"-drain-time", c.drainTime},
+		{"-parent-shutdown-time", c.parentShutdownTime},
+	} {
+		if f.d < 0 {
+			return fmt.Errorf("'%s' cannot be negative", f.name)
+		}
+		if f.d%time.Second != 0 {
+			return fmt.Errorf("'%s' must be a whole number of seconds, got %s", f.name, f.d)
+		}
+	}
+	if c.drainTime != 0 {
+		drainTime = c.drainTime
+	}
+	if c.parentShutdownTime != 0 {
+		parentShutdownTime = c.parentShutdownTime
+	}
+	// The new Envoy process shuts the previous one down after the parent
+	// shutdown time, so it must leave enough time to drain its connections.
+	if parentShutdownTime <= drainTime {
+		return fmt.Errorf("the parent shutdown time (%s) must be greater than the drain time (%s)", parentShutdownTime, drainTime)
+	}
+
+	return nil
+}
+
+// hotRestartArgs returns the options that make Envoy take over the listeners
+// of the process of the previous restart epoch, if any, and drain it.
+//
+// When -base-id-path is set, the process of epoch 0 picks an unused base ID and
+// writes it to that file, so that several Envoy processes can be hot restarted
+// on the same host. The processes of the next epochs read it from the file to
+// find the previous process.
+func (c *cmd) hotRestartArgs() ([]string, error) {
+	if !c.hotRestart {
+		return nil, nil
+	}
+
+	args := []string{"--restart-epoch", strconv.Itoa(c.restartEpoch)}
+
+	if c.baseIDPath != "" {
+		if c.restartEpoch == 0 {
+			args = append(args, "--use-dynamic-base-id", "--base-id-path", c.baseIDPath)
+		} else {
+			baseID, err := readBaseID(c.baseIDPath)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading the Envoy base ID for restart epoch %d: %s", c.restartEpoch, err)
+			}
+			args = append(args, "--base-id", strconv.FormatUint(baseID, 10))
+		}
+	}
+
+	if c.drainTime != 0 {
+		args = append(args, "--drain-time-s", strconv.Itoa(int(c.drainTime/time.Second)))
+	}
+	if c.parentShutdownTime != 0 {
+		args = append(args, "--parent-shutdown-time-s", strconv.Itoa(int(c.parentShutdownTime/time.Second)))
+	}
+
+	return args, nil
+}
+
+// readBaseID reads the base ID that Envoy wrote to the given path.
+func readBaseID(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	baseID, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid base ID in %q: %w", path, err)
+	}
+	return baseID, nil
+}
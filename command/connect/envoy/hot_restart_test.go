@@ -0,0 +1,135 @@
+package envoy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHotRestart(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"disabled": {
+			args: []string{"-proxy-id", "web-sidecar-proxy", "--", "--restart-epoch", "1"},
+		},
+		"epoch without hot restart": {
+			args:    []string{"-restart-epoch", "1"},
+			wantErr: "require '-hot-restart'",
+		},
+		"base ID path without hot restart": {
+			args:    []string{"-base-id-path", "/tmp/base-id"},
+			wantErr: "require '-hot-restart'",
+		},
+		"with bootstrap": {
+			args:    []string{"-hot-restart", "-bootstrap"},
+			wantErr: "'-hot-restart' cannot be used with '-bootstrap'",
+		},
+		"pass-through epoch": {
+			args:    []string{"-hot-restart", "--", "--restart-epoch=1"},
+			wantErr: "'-hot-restart' cannot be used with the --restart-epoch pass-through option",
+		},
+		"pass-through base ID": {
+			args:    []string{"-hot-restart", "--", "--base-id", "3"},
+			wantErr: "'-hot-restart' cannot be used with the --base-id pass-through option",
+		},
+		"negative epoch": {
+			args:    []string{"-hot-restart", "-restart-epoch", "-1"},
+			wantErr: "'-restart-epoch' cannot be negative",
+		},
+		"fractional drain time": {
+			args:    []string{"-hot-restart", "-drain-time", "1500ms"},
+			wantErr: "'-drain-time' must be a whole number of seconds, got 1.5s",
+		},
+		"parent shutdown time before default drain time": {
+			args:    []string{"-hot-restart", "-parent-shutdown-time", "60s"},
+			wantErr: "the parent shutdown time (1m0s) must be greater than the drain time (10m0s)",
+		},
+		"parent shutdown time equal to drain time": {
+			args:    []string{"-hot-restart", "-drain-time", "30s", "-parent-shutdown-time", "30s"},
+			wantErr: "the parent shutdown time (30s) must be greater than the drain time (30s)",
+		},
+		"valid": {
+			args: []string{"-hot-restart", "-restart-epoch", "2", "-base-id-path", "/tmp/base-id",
+				"-drain-time", "30s", "-parent-shutdown-time", "45s", "--", "--log-level", "debug"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := New(nil)
+			require.NoError(t, c.flags.Parse(tc.args))
+
+			err := c.validateHotRestart(c.flags.Args())
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHotRestartArgs(t *testing.T) {
+	dir := t.TempDir()
+	baseIDPath := filepath.Join(dir, "base-id")
+	require.NoError(t, os.WriteFile(baseIDPath, []byte("12\n"), 0600))
+	invalidBaseIDPath := filepath.Join(dir, "invalid-base-id")
+	require.NoError(t, os.WriteFile(invalidBaseIDPath, []byte("twelve"), 0600))
+
+	cases := map[string]struct {
+		args     []string
+		wantArgs []string
+		wantErr  string
+	}{
+		"disabled": {
+			args: []string{},
+		},
+		"first epoch": {
+			args:     []string{"-hot-restart"},
+			wantArgs: []string{"--restart-epoch", "0"},
+		},
+		"first epoch with base ID path": {
+			args:     []string{"-hot-restart", "-base-id-path", baseIDPath},
+			wantArgs: []string{"--restart-epoch", "0", "--use-dynamic-base-id", "--base-id-path", baseIDPath},
+		},
+		"next epoch with base ID path": {
+			args:     []string{"-hot-restart", "-restart-epoch", "1", "-base-id-path", baseIDPath},
+			wantArgs: []string{"--restart-epoch", "1", "--base-id", "12"},
+		},
+		"next epoch with missing base ID": {
+			args:    []string{"-hot-restart", "-restart-epoch", "1", "-base-id-path", filepath.Join(dir, "missing")},
+			wantErr: "Error reading the Envoy base ID for restart epoch 1",
+		},
+		"next epoch with invalid base ID": {
+			args:    []string{"-hot-restart", "-restart-epoch", "1", "-base-id-path", invalidBaseIDPath},
+			wantErr: "invalid base ID",
+		},
+		"drain and parent shutdown times": {
+			args: []string{"-hot-restart", "-restart-epoch", "3", "-drain-time", "30s", "-parent-shutdown-time", "1m"},
+			wantArgs: []string{"--restart-epoch", "3",
+				"--drain-time-s", "30",
+				"--parent-shutdown-time-s", "60"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := New(nil)
+			require.NoError(t, c.flags.Parse(tc.args))
+
+			args, err := c.hotRestartArgs()
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
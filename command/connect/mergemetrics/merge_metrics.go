@@ -0,0 +1,260 @@
+package mergemetrics
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/connect/metrics"
+	"github.com/hashicorp/consul/logging"
+)
+
+func New(ui cli.Ui, shutdownCh <-chan struct{}) *cmd {
+	c := &cmd{UI: ui, shutdownCh: shutdownCh}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	shutdownCh <-chan struct{}
+
+	client *api.Client
+	logger hclog.Logger
+
+	// flags
+	logLevel   string
+	logJSON    bool
+	proxyID    string
+	nodeName   string
+	adminBind  string
+	bind       string
+	serviceURL string
+
+	// gatherer exposes the metrics of the dataplane, it's overridden by the
+	// tests.
+	gatherer prometheus.Gatherer
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.flags.StringVar(&c.proxyID, "proxy-id", os.Getenv("CONNECT_PROXY_ID"),
+		"The proxy's ID on the local agent.")
+
+	c.flags.StringVar(&c.nodeName, "node-name", "",
+		"The node name where the proxy service is registered. When set, the proxy "+
+			"configuration is read from the catalog rather than from the local agent, "+
+			"so that no client agent is required. It requires proxy-id to be specified.")
+
+	c.flags.StringVar(&c.adminBind, "admin-bind", "localhost:19000",
+		"The address:port of the Envoy admin API the metrics of Envoy are scraped "+
+			"from. It should match the -admin-bind of 'consul connect envoy'.")
+
+	c.flags.StringVar(&c.bind, "bind", "",
+		"The address:port the merged metrics are served on. Defaults to the host of "+
+			"-admin-bind and the metrics_merging_port of the proxy configuration.")
+
+	c.flags.StringVar(&c.serviceURL, "service-metrics-url", "",
+		"The URL of the metrics endpoint of the application. Overrides the "+
+			"metrics_merging_service_url of the proxy configuration.")
+
+	c.flags.StringVar(&c.logLevel, "log-level", "INFO",
+		"Specifies the log level.")
+
+	c.flags.BoolVar(&c.logJSON, "log-json", false,
+		"Output logs in JSON format.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.proxyID == "" {
+		c.UI.Error("-proxy-id is required")
+		return 1
+	}
+
+	logger, err := logging.Setup(logging.Config{
+		LogLevel: c.logLevel,
+		Name:     logging.Proxy,
+		LogJSON:  c.logJSON,
+	}, &cli.UiWriter{Ui: c.UI})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	c.logger = logger.Named("merge-metrics")
+
+	c.client, err = c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	cfg, err := c.mergingConfig()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	bind, err := c.bindAddr(cfg)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	gatherer := c.gatherer
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	envoyURL := "http://" + c.adminBind + metrics.EnvoyMetricsPath
+	merger, err := metrics.NewMerger(cfg, envoyURL, gatherer, c.logger)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", merger)
+	mux.Handle(metrics.EnvoyMetricsPath, merger)
+
+	ln, err := net.Listen("tcp", bind)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to listen on %s: %s", bind, err))
+		return 1
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	c.logger.Info("Serving the merged metrics", "address", ln.Addr().String(), "envoy", envoyURL, "service", cfg.ServiceURL)
+
+	select {
+	case <-c.shutdownCh:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			c.logger.Warn("Failed to shut down the metrics server", "error", err)
+		}
+		return 0
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			c.UI.Error(fmt.Sprintf("Failed to serve the merged metrics: %s", err))
+			return 1
+		}
+		return 0
+	}
+}
+
+// mergingConfig returns the metrics merging configuration of the proxy, with
+// the overrides of the flags.
+func (c *cmd) mergingConfig() (metrics.MergingConfig, error) {
+	var proxyConfig map[string]interface{}
+	if c.nodeName == "" {
+		svc, _, err := c.client.Agent().Service(c.proxyID, nil)
+		if err != nil {
+			return metrics.MergingConfig{}, fmt.Errorf("failed to fetch proxy config from local agent: %s", err)
+		}
+		if svc.Proxy == nil {
+			return metrics.MergingConfig{}, fmt.Errorf("service %q is not a Connect proxy or gateway", c.proxyID)
+		}
+		proxyConfig = svc.Proxy.Config
+	} else {
+		filter := fmt.Sprintf("ID == %q", c.proxyID)
+		svcList, _, err := c.client.Catalog().NodeServiceList(c.nodeName,
+			&api.QueryOptions{Filter: filter, MergeCentralConfig: true})
+		if err != nil {
+			return metrics.MergingConfig{}, fmt.Errorf("failed to fetch proxy config from catalog for node %q: %w", c.nodeName, err)
+		}
+		if svcList == nil || len(svcList.Services) == 0 {
+			return metrics.MergingConfig{}, fmt.Errorf("proxy service with ID %q not found", c.proxyID)
+		}
+		if svcList.Services[0].Proxy == nil {
+			return metrics.MergingConfig{}, fmt.Errorf("service %q is not a Connect proxy or gateway", c.proxyID)
+		}
+		proxyConfig = svcList.Services[0].Proxy.Config
+	}
+
+	cfg, err := metrics.ParseMergingConfig(proxyConfig)
+	if err != nil {
+		return cfg, err
+	}
+	if c.serviceURL != "" {
+		cfg.ServiceURL = c.serviceURL
+	}
+	return cfg, nil
+}
+
+// bindAddr returns the address the merged metrics are served on.
+func (c *cmd) bindAddr(cfg metrics.MergingConfig) (string, error) {
+	if c.bind != "" {
+		return c.bind, nil
+	}
+	if !cfg.Enabled() {
+		return "", fmt.Errorf("metrics merging isn't configured for proxy %q: set metrics_merging_port in its proxy config or use -bind", c.proxyID)
+	}
+	host, _, err := net.SplitHostPort(c.adminBind)
+	if err != nil {
+		return "", fmt.Errorf("invalid -admin-bind address: %s", err)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(cfg.Port)), nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Serves the merged metrics of a proxy and its service"
+	help     = `
+Usage: consul connect merge-metrics [options]
+
+  Serves the metrics of an Envoy proxy, of the application it is the sidecar
+  of and of this process on a single Prometheus endpoint, at /metrics and at
+  /stats/prometheus.
+
+  The endpoint is configured with the metrics_merging_* keys of the proxy
+  configuration, usually set for all the proxies in the proxy-defaults config
+  entry. When metrics_merging_port is set, 'consul connect envoy' points the
+  envoy_prometheus_bind_addr listener to the merged metrics.
+
+  With -node-name, the proxy configuration is read from the catalog so that
+  the command can run next to a dataplane without a client agent.
+
+  Example:
+
+    $ consul connect merge-metrics -proxy-id web-sidecar-proxy
+`
+)
@@ -0,0 +1,138 @@
+package mergemetrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/connect/metrics"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+func TestMergeMetricsCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi(), nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestMergeMetricsCommand_Validation(t *testing.T) {
+	ui := cli.NewMockUi()
+	cmd := New(ui, nil)
+	cmd.proxyID = ""
+
+	require.Equal(t, 1, cmd.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "-proxy-id is required")
+}
+
+func TestMergeMetricsCommand_bindAddr(t *testing.T) {
+	cmd := New(cli.NewMockUi(), nil)
+	cmd.proxyID = "web-sidecar-proxy"
+
+	_, err := cmd.bindAddr(metrics.MergingConfig{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "metrics_merging_port")
+
+	bind, err := cmd.bindAddr(metrics.MergingConfig{Port: 20200})
+	require.NoError(t, err)
+	require.Equal(t, "localhost:20200", bind)
+
+	cmd.bind = "0.0.0.0:9102"
+	bind, err = cmd.bindAddr(metrics.MergingConfig{Port: 20200})
+	require.NoError(t, err)
+	require.Equal(t, "0.0.0.0:9102", bind)
+}
+
+func TestMergeMetricsCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	envoy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, metrics.EnvoyMetricsPath, r.URL.Path)
+		io.WriteString(w, "# TYPE envoy_server_live gauge\nenvoy_server_live 1\n")
+	}))
+	defer envoy.Close()
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "# TYPE http_requests_total counter\nhttp_requests_total{code=\"200\"} 3\n")
+	}))
+	defer service.Close()
+
+	testServer, err := testutil.NewTestServerConfigT(t, nil)
+	require.NoError(t, err)
+	testServer.WaitForSerfCheck(t)
+	defer testServer.Stop()
+
+	client, err := api.NewClient(&api.Config{Address: testServer.HTTPAddr})
+	require.NoError(t, err)
+
+	port := freeport.GetOne(t)
+	_, err = client.Catalog().Register(&api.CatalogRegistration{
+		Node:    "dataplane-node",
+		Address: "127.0.0.1",
+		Service: &api.AgentService{
+			Kind:    api.ServiceKindConnectProxy,
+			ID:      "web-sidecar-proxy",
+			Service: "web-sidecar-proxy",
+			Port:    20000,
+			Proxy: &api.AgentServiceConnectProxyConfig{
+				DestinationServiceName: "web",
+				Config: map[string]interface{}{
+					"metrics_merging_port":        port,
+					"metrics_merging_service_url": service.URL,
+					"metrics_merging_relabel": []interface{}{
+						map[string]interface{}{"action": "replace", "target_label": "dc", "replacement": "dc1"},
+					},
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	shutdownCh := make(chan struct{})
+	ui := cli.NewMockUi()
+	cmd := New(ui, shutdownCh)
+	cmd.gatherer = prometheus.NewRegistry()
+
+	codeCh := make(chan int, 1)
+	go func() {
+		codeCh <- cmd.Run([]string{
+			"-http-addr=" + testServer.HTTPAddr,
+			"-proxy-id=web-sidecar-proxy",
+			"-node-name=dataplane-node",
+			"-admin-bind=" + envoy.Listener.Addr().String(),
+		})
+	}()
+
+	var body string
+	retry.Run(t, func(r *retry.R) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/metrics", port))
+		require.NoError(r, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(r, err)
+		body = string(b)
+	})
+	require.Contains(t, body, `envoy_server_live{dc="dc1"} 1`)
+	require.Contains(t, body, `http_requests_total{code="200",dc="dc1"} 3`)
+	require.Contains(t, body, `consul_merged_metrics_up{source="dataplane"} 1`)
+
+	close(shutdownCh)
+	select {
+	case code := <-codeCh:
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+	case <-time.After(10 * time.Second):
+		t.Fatal("the command didn't stop")
+	}
+}
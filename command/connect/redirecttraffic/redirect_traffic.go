@@ -48,6 +48,8 @@ type cmd struct {
 	excludeOutboundCIDRs []string
 	excludeUIDs          []string
 	netNS                string
+	backend              string
+	ipv6                 bool
 }
 
 func (c *cmd) init() {
@@ -72,6 +74,10 @@ func (c *cmd) init() {
 		"Additional user ID to exclude from traffic redirection. May be provided multiple times.")
 	c.flags.StringVar(&c.netNS, "netns", "", "The network namespace where traffic redirection rules should apply."+
 		"This must be a path to the network namespace, e.g. /var/run/netns/foo.")
+	c.flags.BoolVar(&c.ipv6, "ipv6", false, "Redirect IPv6 traffic as well, with ip6tables or nftables. "+
+		"This is enabled automatically when the proxy identified by -proxy-id sets outbound_listener_ipv6 in its configuration.")
+	c.flags.StringVar(&c.backend, "backend", iptables.BackendAuto, "The backend used to apply the traffic redirection rules, "+
+		"one of 'iptables', 'nftables' or 'auto'. When 'auto', iptables is used if it is installed and nftables otherwise.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -101,11 +107,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	switch c.backend {
+	case iptables.BackendIptables, iptables.BackendNftables, iptables.BackendAuto:
+	default:
+		c.UI.Error("-backend must be one of 'iptables', 'nftables' or 'auto'")
+		return 1
+	}
+
 	cfg, err := c.generateConfigFromFlags()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to create configuration to apply traffic redirection rules: %s", err))
 		return 1
 	}
+	cfg.Backend = c.backend
 
 	err = iptables.Setup(cfg)
 	if err != nil {
@@ -129,9 +143,10 @@ func (c *cmd) Help() string {
 // with only the configuration values that we need to parse from Proxy.Config
 // to apply traffic redirection rules.
 type trafficRedirectProxyConfig struct {
-	BindPort           int    `mapstructure:"bind_port"`
-	PrometheusBindAddr string `mapstructure:"envoy_prometheus_bind_addr"`
-	StatsBindAddr      string `mapstructure:"envoy_stats_bind_addr"`
+	BindPort             int    `mapstructure:"bind_port"`
+	PrometheusBindAddr   string `mapstructure:"envoy_prometheus_bind_addr"`
+	StatsBindAddr        string `mapstructure:"envoy_stats_bind_addr"`
+	OutboundListenerIPv6 bool   `mapstructure:"outbound_listener_ipv6"`
 }
 
 // generateConfigFromFlags generates iptables.Config based on command flags.
@@ -143,6 +158,7 @@ func (c *cmd) generateConfigFromFlags() (iptables.Config, error) {
 		ProxyInboundPort:  c.proxyInboundPort,
 		ProxyOutboundPort: c.proxyOutboundPort,
 		NetNS:             c.netNS,
+		IPv6:              c.ipv6,
 	}
 
 	// When proxyID is provided, we set up cfg with values
@@ -196,6 +212,11 @@ func (c *cmd) generateConfigFromFlags() (iptables.Config, error) {
 			cfg.ProxyInboundPort = trCfg.BindPort
 		}
 
+		// The proxy only receives IPv6 traffic when it listens for it.
+		if trCfg.OutboundListenerIPv6 {
+			cfg.IPv6 = true
+		}
+
 		// Set the proxy's outbound port.
 		cfg.ProxyOutboundPort = iptables.DefaultTProxyOutboundPort
 		if svc.Proxy.TransparentProxy != nil && svc.Proxy.TransparentProxy.OutboundListenerPort != 0 {
@@ -265,18 +286,22 @@ func (c *cmd) generateConfigFromFlags() (iptables.Config, error) {
 }
 
 const (
-	synopsis = "Applies iptables rules for traffic redirection"
+	synopsis = "Applies iptables or nftables rules for traffic redirection"
 	help     = `
 Usage: consul connect redirect-traffic [options]
 
-  Applies iptables rules for inbound and outbound traffic redirection.
+  Applies iptables or nftables rules for inbound and outbound traffic redirection.
 
-  Requires that the iptables command line utility is installed.
+  Requires that the iptables or the nft command line utility is installed.
+  The nftables rules are kept in the "consul" table, which is replaced
+  atomically each time they are applied.
 
   Examples:
 
     $ consul connect redirect-traffic -proxy-uid 1234 -proxy-id web
 
     $ consul connect redirect-traffic -proxy-uid 1234 -proxy-inbound-port 20000
+
+    $ consul connect redirect-traffic -backend nftables -proxy-uid 1234 -proxy-id web
 `
 )
@@ -43,6 +43,11 @@ func TestRun_FlagValidation(t *testing.T) {
 			[]string{"-proxy-uid=1234", "-proxy-id=test", "-proxy-inbound-port=15000", "-proxy-outbound-port=15001"},
 			"-proxy-inbound-port or -proxy-outbound-port cannot be provided together with -proxy-id.",
 		},
+		{
+			"-backend is invalid",
+			[]string{"-proxy-uid=1234", "-proxy-inbound-port=15000", "-backend=ebpf"},
+			"-backend must be one of 'iptables', 'nftables' or 'auto'",
+		},
 	}
 
 	for _, c := range cases {
@@ -158,6 +163,37 @@ func TestGenerateConfigFromFlags(t *testing.T) {
 				ProxyOutboundPort: iptables.DefaultTProxyOutboundPort,
 			},
 		},
+		{
+			name: "proxyID with outbound_listener_ipv6 provided",
+			command: func() cmd {
+				var c cmd
+				c.init()
+				c.proxyUID = "1234"
+				c.proxyID = "test-proxy-id"
+				return c
+			},
+			consulServices: []api.AgentServiceRegistration{
+				{
+					Kind:    api.ServiceKindConnectProxy,
+					ID:      "test-proxy-id",
+					Name:    "test-proxy",
+					Port:    20000,
+					Address: "2001:db8::1",
+					Proxy: &api.AgentServiceConnectProxyConfig{
+						DestinationServiceName: "foo",
+						Config: map[string]interface{}{
+							"outbound_listener_ipv6": true,
+						},
+					},
+				},
+			},
+			expCfg: iptables.Config{
+				ProxyUserID:       "1234",
+				ProxyInboundPort:  20000,
+				ProxyOutboundPort: iptables.DefaultTProxyOutboundPort,
+				IPv6:              true,
+			},
+		},
 		{
 			name: "proxyID with bind_port(string) provided",
 			command: func() cmd {
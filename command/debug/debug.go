@@ -119,7 +119,9 @@ func (c *cmd) init() {
 		fmt.Sprintf("One or more types of information to capture. This can be used "+
 			"to capture a subset of information, and defaults to capturing "+
 			"everything available. Possible information for capture: %s. "+
-			"This can be repeated multiple times.", strings.Join(defaultTargets, ", ")))
+			"The following must be requested explicitly: %s. "+
+			"This can be repeated multiple times.", strings.Join(defaultTargets, ", "),
+			strings.Join(optionalTargets, ", ")))
 	c.flags.DurationVar(&c.interval, "interval", debugInterval,
 		fmt.Sprintf("The interval in which to capture dynamic information such as "+
 			"telemetry, and profiling. Defaults to %s.", debugInterval))
@@ -197,7 +199,7 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	// Capture dynamic information from the target agent, blocking for duration
-	if c.captureTarget(targetMetrics) || c.captureTarget(targetLogs) || c.captureTarget(targetProfiles) {
+	if c.captureTarget(targetMetrics) || c.captureTarget(targetLogs) || c.captureTarget(targetProfiles) || c.captureTarget(targetProxy) {
 		g := new(errgroup.Group)
 		g.Go(func() error {
 			return c.captureInterval(ctx)
@@ -389,12 +391,15 @@ func (c *cmd) captureInterval(ctx context.Context) error {
 func captureShortLived(c *cmd) error {
 	g := new(errgroup.Group)
 
-	if c.captureTarget(targetProfiles) {
-		dir, err := makeIntervalDir(c.output, c.timeNow())
-		if err != nil {
-			return err
-		}
+	if !c.captureTarget(targetProfiles) && !c.captureTarget(targetProxy) {
+		return nil
+	}
+	dir, err := makeIntervalDir(c.output, c.timeNow())
+	if err != nil {
+		return err
+	}
 
+	if c.captureTarget(targetProfiles) {
 		g.Go(func() error {
 			return c.captureHeap(dir)
 		})
@@ -403,6 +408,11 @@ func captureShortLived(c *cmd) error {
 			return c.captureGoRoutines(dir)
 		})
 	}
+	if c.captureTarget(targetProxy) {
+		g.Go(func() error {
+			return c.captureProxies(dir)
+		})
+	}
 	return g.Wait()
 }
 
@@ -498,6 +508,17 @@ func (c *cmd) captureHeap(outputDir string) error {
 	return os.WriteFile(filepath.Join(outputDir, "heap.prof"), heap, 0644)
 }
 
+// captureProxies captures the state of the proxies tracked by the agent and
+// the xDS configuration generated for them.
+func (c *cmd) captureProxies(outputDir string) error {
+	proxies, err := c.client.Agent().ProxyDebug()
+	if err != nil {
+		return fmt.Errorf("failed to collect proxy state: %w", err)
+	}
+
+	return writeJSONFile(filepath.Join(outputDir, targetProxy+".json"), proxies)
+}
+
 func (c *cmd) captureLogs(ctx context.Context) error {
 	logCh, err := c.client.Agent().Monitor("DEBUG", ctx.Done(), nil)
 	if err != nil {
@@ -555,6 +576,11 @@ func allowedTarget(target string) bool {
 			return true
 		}
 	}
+	for _, t := range optionalTargets {
+		if t == target {
+			return true
+		}
+	}
 	for _, t := range deprecatedTargets {
 		if t == target {
 			return true
@@ -700,6 +726,7 @@ const (
 	targetHost     = "host"
 	targetAgent    = "agent"
 	targetMembers  = "members"
+	targetProxy    = "proxy"
 	// targetCluster is the now deprecated name for targetMembers
 	targetCluster = "cluster"
 )
@@ -714,6 +741,10 @@ var defaultTargets = []string{
 	targetMembers,
 }
 
+// optionalTargets specifies the list of targets that are only captured when
+// requested, since they can be large on agents with many proxies
+var optionalTargets = []string{targetProxy}
+
 var deprecatedTargets = []string{targetCluster}
 
 func (c *cmd) Synopsis() string {
@@ -755,6 +786,12 @@ Usage: consul debug [options]
 
       $ consul debug -capture metrics -capture agent
 
+  The state of the proxies tracked by the agent and the xDS configuration
+  generated for them are only captured when requested, for instance to
+  capture them alongside the metrics and profiles:
+
+      $ consul debug -capture proxy -capture metrics -capture pprof
+
   By default, the archive containing the debugging information is
   saved to the current directory as a .tar.gz file. The
   output path can be specified, as well as an option to disable
@@ -339,6 +339,11 @@ func TestDebugCommand_CaptureTargets(t *testing.T) {
 			[]string{"metrics.json"},
 			[]string{"agent.json", "host.json", "members.json"},
 		},
+		"proxy-only": {
+			[]string{"proxy"},
+			[]string{"*/proxy.json"},
+			[]string{"agent.json", "metrics.json", "*/heap.prof"},
+		},
 		"all-but-pprof": {
 			[]string{
 				"metrics",
@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -139,6 +141,14 @@ func (c *cmd) Run(args []string) int {
 	}
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return completion.KVKeys()
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, nil)
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
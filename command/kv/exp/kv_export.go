@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -22,10 +23,29 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	include    flags.AppendSliceValue
+	exclude    flags.AppendSliceValue
+	rewrite    flags.AppendSliceValue
+	encrypt    string
+	recipients flags.AppendSliceValue
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Var(&c.include, "include", "Glob pattern of the keys to export. A pattern "+
+		"also matches the keys under the folders it matches. This flag may be "+
+		"specified multiple times, and all keys are exported when it isn't.")
+	c.flags.Var(&c.exclude, "exclude", "Glob pattern of the keys not to export. A "+
+		"pattern also matches the keys under the folders it matches. This flag "+
+		"may be specified multiple times.")
+	c.flags.Var(&c.rewrite, "rewrite-prefix", "Rewrite the prefix of the exported "+
+		"keys, in the form FROM=TO. This flag may be specified multiple times, and "+
+		"the first rule that matches a key is applied.")
+	c.flags.StringVar(&c.encrypt, "encrypt", "", "Encrypt the exported data with "+
+		"the \"age\" or \"gpg\" tool, which must be installed.")
+	c.flags.Var(&c.recipients, "recipient", "Recipient of the encrypted data, "+
+		"required with -encrypt. This flag may be specified multiple times.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -58,6 +78,21 @@ func (c *cmd) Run(args []string) int {
 		key = key[1:]
 	}
 
+	filter := impexp.Filter{Include: c.include, Exclude: c.exclude}
+	if err := filter.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+	rewrite := impexp.Rewrite(c.rewrite)
+	if err := rewrite.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+	if c.encrypt != "" && len(c.recipients) == 0 {
+		c.UI.Error("Error! Missing -recipient for encryption")
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -73,9 +108,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	exported := make([]*impexp.Entry, len(pairs))
-	for i, pair := range pairs {
-		exported[i] = impexp.ToEntry(pair)
+	exported := make([]*impexp.Entry, 0, len(pairs))
+	for _, pair := range pairs {
+		if !filter.Match(pair.Key) {
+			continue
+		}
+		entry := impexp.ToEntry(pair)
+		entry.Key = rewrite.Key(entry.Key)
+		exported = append(exported, entry)
 	}
 
 	marshaled, err := json.MarshalIndent(exported, "", "\t")
@@ -84,7 +124,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	c.UI.Info(string(marshaled))
+	if c.encrypt != "" {
+		if marshaled, err = impexp.Encrypt(c.encrypt, c.recipients, marshaled); err != nil {
+			c.UI.Error(fmt.Sprintf("Error encrypting KV data: %s", err))
+			return 1
+		}
+	}
+
+	c.UI.Info(strings.TrimSpace(string(marshaled)))
 
 	return 0
 }
@@ -108,6 +155,18 @@ Usage: consul kv export [KEY_OR_PREFIX]
 
       $ consul kv export vault
 
+  The exported keys can be filtered with glob patterns, and their prefix
+  rewritten, for example to promote the configuration of an environment to
+  another:
+
+      $ consul kv export -exclude 'staging/secrets' \
+          -rewrite-prefix staging/=production/ staging/
+
+  The exported data can be encrypted with age or gpg. "consul kv import"
+  decrypts it using the same tool:
+
+      $ consul kv export -encrypt age -recipient age1ql3z7hjy54pw3hyww5ay... vault
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
@@ -30,6 +30,11 @@ type cmd struct {
 	help   string
 	prefix string
 
+	include  flags.AppendSliceValue
+	exclude  flags.AppendSliceValue
+	rewrite  flags.AppendSliceValue
+	identity string
+
 	// testStdin is the input for testing.
 	testStdin io.Reader
 }
@@ -37,6 +42,18 @@ type cmd struct {
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.prefix, "prefix", "", "Key prefix for imported data")
+	c.flags.Var(&c.include, "include", "Glob pattern of the keys to import. A pattern "+
+		"also matches the keys under the folders it matches. This flag may be "+
+		"specified multiple times, and all keys are imported when it isn't.")
+	c.flags.Var(&c.exclude, "exclude", "Glob pattern of the keys not to import. A "+
+		"pattern also matches the keys under the folders it matches. This flag "+
+		"may be specified multiple times.")
+	c.flags.Var(&c.rewrite, "rewrite-prefix", "Rewrite the prefix of the imported "+
+		"keys, in the form FROM=TO. This flag may be specified multiple times, and "+
+		"the first rule that matches a key is applied before -prefix.")
+	c.flags.StringVar(&c.identity, "identity", "", "Path to the age identity file "+
+		"used to decrypt data encrypted with age. Data encrypted with gpg is "+
+		"decrypted with the keys of the gpg keyring.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -57,6 +74,26 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	filter := impexp.Filter{Include: c.include, Exclude: c.exclude}
+	if err := filter.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+	rewrite := impexp.Rewrite(c.rewrite)
+	if err := rewrite.Validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+
+	if impexp.IsEncrypted([]byte(data)) {
+		decrypted, err := impexp.Decrypt(c.identity, []byte(data))
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error decrypting data: %s", err))
+			return 1
+		}
+		data = string(decrypted)
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -71,20 +108,25 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	for _, entry := range entries {
+		if !filter.Match(entry.Key) {
+			continue
+		}
+
 		value, err := base64.StdEncoding.DecodeString(entry.Value)
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error base 64 decoding value for key %s: %s", entry.Key, err))
 			return 1
 		}
 
+		key := rewrite.Key(entry.Key)
 		pair := &api.KVPair{
-			Key:   path.Join(c.prefix, entry.Key),
+			Key:   path.Join(c.prefix, key),
 			Flags: entry.Flags,
 			Value: value,
 		}
 
 		// if the key is a directory, we need to append /
-		if len(entry.Key) > 0 && entry.Key[len(entry.Key)-1] == '/' {
+		if len(key) > 0 && key[len(key)-1] == '/' {
 			pair.Key += "/"
 		}
 
@@ -169,6 +211,18 @@ Usage: consul kv import [DATA]
   Alternatively the data may be provided as the final parameter to the command,
   though care must be taken with regards to shell escaping.
 
+  The imported keys can be filtered with glob patterns, and their prefix
+  rewritten:
+
+      $ consul kv import -include 'staging/app' \
+          -rewrite-prefix staging/=production/ @filename.json
+
+  Data encrypted by "consul kv export -encrypt" is decrypted with the tool that
+  encrypted it, which must be installed. Data encrypted with age requires the
+  identity file of one of its recipients:
+
+      $ consul kv import -identity key.txt @filename.json.age
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
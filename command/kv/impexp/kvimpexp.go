@@ -1,7 +1,13 @@
 package impexp
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 )
@@ -23,3 +29,147 @@ func ToEntry(pair *api.KVPair) *Entry {
 		Partition: pair.Partition,
 	}
 }
+
+// Filter selects the keys to export or import using glob patterns, as
+// understood by path.Match. A pattern matches a key if it matches the key or
+// one of its parent folders, so "secret" matches "secret/db/password".
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+// Validate returns an error if one of the patterns is malformed.
+func (f Filter) Validate() error {
+	for _, pattern := range append(f.Include, f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match returns true if key matches one of the include patterns, or if there
+// are none, and doesn't match any of the exclude patterns.
+func (f Filter) Match(key string) bool {
+	return (len(f.Include) == 0 || matchAny(f.Include, key)) && !matchAny(f.Exclude, key)
+}
+
+func matchAny(patterns []string, key string) bool {
+	key = strings.TrimSuffix(key, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		for k := key; ; k = path.Dir(k) {
+			if ok, _ := path.Match(pattern, k); ok {
+				return true
+			}
+			if !strings.Contains(k, "/") {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// Rewrite replaces the prefix of keys. Each rule has the form "FROM=TO" and
+// only the first rule whose prefix matches a key is applied.
+type Rewrite []string
+
+// Validate returns an error if one of the rules is malformed.
+func (r Rewrite) Validate() error {
+	for _, rule := range r {
+		if !strings.Contains(rule, "=") {
+			return fmt.Errorf("invalid prefix rewrite %q: must be of the form FROM=TO", rule)
+		}
+	}
+	return nil
+}
+
+// Key returns the rewritten key.
+func (r Rewrite) Key(key string) string {
+	for _, rule := range r {
+		from, to, _ := strings.Cut(rule, "=")
+		if strings.HasPrefix(key, from) {
+			return to + strings.TrimPrefix(key, from)
+		}
+	}
+	return key
+}
+
+const (
+	// EncryptionAge encrypts the exported data with the age tool.
+	EncryptionAge = "age"
+
+	// EncryptionGPG encrypts the exported data with the gpg tool.
+	EncryptionGPG = "gpg"
+
+	ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+	gpgArmorHeader = "-----BEGIN PGP MESSAGE-----"
+)
+
+// execCommand is used to run the encryption tools, and is replaced in tests.
+var execCommand = exec.Command
+
+// Encrypt encrypts data for the recipients using the given tool, which must
+// be installed. The result is ASCII-armored so that it can be decrypted by
+// Decrypt, or by the tool itself.
+func Encrypt(tool string, recipients []string, data []byte) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one recipient is required to encrypt")
+	}
+
+	var args []string
+	switch tool {
+	case EncryptionAge:
+		args = []string{"--encrypt", "--armor"}
+		for _, r := range recipients {
+			args = append(args, "--recipient", r)
+		}
+	case EncryptionGPG:
+		args = []string{"--batch", "--yes", "--encrypt", "--armor"}
+		for _, r := range recipients {
+			args = append(args, "--recipient", r)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encryption %q, must be %q or %q", tool, EncryptionAge, EncryptionGPG)
+	}
+	return run(tool, args, data)
+}
+
+// IsEncrypted returns true if data was encrypted by Encrypt.
+func IsEncrypted(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return bytes.HasPrefix(data, []byte(ageArmorHeader)) || bytes.HasPrefix(data, []byte(gpgArmorHeader))
+}
+
+// Decrypt decrypts data encrypted by Encrypt, using the tool that encrypted
+// it. The identity file is required by age, and gpg uses the keys of the
+// user's keyring.
+func Decrypt(identity string, data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(ageArmorHeader)):
+		if identity == "" {
+			return nil, errors.New("an identity file is required to decrypt data encrypted with age")
+		}
+		return run(EncryptionAge, []string{"--decrypt", "--identity", identity}, data)
+	case bytes.HasPrefix(trimmed, []byte(gpgArmorHeader)):
+		return run(EncryptionGPG, []string{"--batch", "--quiet", "--decrypt"}, data)
+	default:
+		return nil, errors.New("data is not encrypted")
+	}
+}
+
+func run(tool string, args []string, data []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := execCommand(tool, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %v: %s", tool, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %v", tool, err)
+	}
+	return stdout.Bytes(), nil
+}
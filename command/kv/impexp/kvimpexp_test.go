@@ -0,0 +1,94 @@
+package impexp
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	f := Filter{
+		Include: []string{"app/*/config", "shared"},
+		Exclude: []string{"app/*/config/secrets", "*/tmp-*"},
+	}
+	require.NoError(t, f.Validate())
+
+	cases := map[string]bool{
+		"app/web/config":                true,
+		"app/web/config/":               true,
+		"app/web/config/port":           true,
+		"app/web/config/secrets/key":    false,
+		"app/web/data":                  false,
+		"shared/region":                 true,
+		"shared/tmp-cache":              false,
+		"sharedother":                   false,
+		"app/web/config/nested/tmp-foo": true,
+	}
+	for key, expected := range cases {
+		require.Equal(t, expected, f.Match(key), key)
+	}
+
+	require.True(t, Filter{}.Match("anything"))
+	require.Error(t, Filter{Include: []string{"["}}.Validate())
+}
+
+func TestRewrite(t *testing.T) {
+	r := Rewrite{"staging/app/=production/app/", "staging/=production/", "=copy/"}
+	require.NoError(t, r.Validate())
+
+	require.Equal(t, "production/app/port", r.Key("staging/app/port"))
+	require.Equal(t, "production/db", r.Key("staging/db"))
+	require.Equal(t, "copy/other", r.Key("other"))
+	require.Equal(t, "key", Rewrite{}.Key("key"))
+
+	require.EqualError(t, Rewrite{"staging/"}.Validate(), `invalid prefix rewrite "staging/": must be of the form FROM=TO`)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	var commands [][]string
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		commands = append(commands, append([]string{name}, args...))
+		return exec.Command("cat")
+	}
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	out, err := Encrypt(EncryptionAge, []string{"age1a", "age1b"}, []byte("data"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(out))
+
+	_, err = Encrypt(EncryptionGPG, []string{"ops@example.com"}, []byte("data"))
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		{"age", "--encrypt", "--armor", "--recipient", "age1a", "--recipient", "age1b"},
+		{"gpg", "--batch", "--yes", "--encrypt", "--armor", "--recipient", "ops@example.com"},
+	}, commands)
+
+	_, err = Encrypt("rot13", []string{"me"}, []byte("data"))
+	require.EqualError(t, err, `unsupported encryption "rot13", must be "age" or "gpg"`)
+	_, err = Encrypt(EncryptionAge, nil, []byte("data"))
+	require.Error(t, err)
+
+	commands = nil
+	age := []byte("-----BEGIN AGE ENCRYPTED FILE-----\nabc\n-----END AGE ENCRYPTED FILE-----\n")
+	gpg := []byte("\n-----BEGIN PGP MESSAGE-----\nabc\n-----END PGP MESSAGE-----\n")
+	require.True(t, IsEncrypted(age))
+	require.True(t, IsEncrypted(gpg))
+	require.False(t, IsEncrypted([]byte(`[{"key":"foo"}]`)))
+
+	_, err = Decrypt("", age)
+	require.EqualError(t, err, "an identity file is required to decrypt data encrypted with age")
+
+	out, err = Decrypt("key.txt", age)
+	require.NoError(t, err)
+	require.Equal(t, age, out)
+
+	_, err = Decrypt("", gpg)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		{"age", "--decrypt", "--identity", "key.txt"},
+		{"gpg", "--batch", "--quiet", "--decrypt"},
+	}, commands)
+}
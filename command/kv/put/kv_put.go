@@ -7,9 +7,11 @@ import (
 	"io"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/hashicorp/consul/command/helpers"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -190,6 +192,14 @@ func (c *cmd) dataFromArgs(args []string) (string, string, error) {
 	}
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return completion.KVKeys()
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, nil)
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -0,0 +1,106 @@
+package login
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// azureMetadataAddr is the address of the Azure Instance Metadata Service.
+	azureMetadataAddr = "http://169.254.169.254"
+
+	azureTokenPath = "/metadata/identity/oauth2/token"
+)
+
+type AzureLogin struct {
+	autoBearerToken bool
+	resource        string
+	clientID        string
+
+	// metadataAddr overrides the address of the metadata service in tests.
+	metadataAddr string
+}
+
+func (a *AzureLogin) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.BoolVar(&a.autoBearerToken, "azure-auto-bearer-token", false,
+		"Request a token for the managed identity of this VM from the Azure Instance Metadata "+
+			"Service and login to the Azure managed identity auth method with it. [azure-msi only]")
+
+	fs.StringVar(&a.resource, "azure-resource", "",
+		"Resource the managed identity token is requested for. Must match one of the "+
+			"BoundAudiences of the auth method. [azure-msi only]")
+
+	fs.StringVar(&a.clientID, "azure-client-id", "",
+		"Client ID of the user-assigned managed identity to use when the VM has more than one. "+
+			"[azure-msi only]")
+	return fs
+}
+
+// checkFlags validates flags for the azure-msi auth method.
+func (a *AzureLogin) checkFlags() error {
+	if !a.autoBearerToken {
+		if a.resource != "" || a.clientID != "" {
+			return fmt.Errorf("Missing '-azure-auto-bearer-token' flag")
+		}
+		return nil
+	}
+	if a.resource == "" {
+		return fmt.Errorf("Missing '-azure-resource' flag")
+	}
+	return nil
+}
+
+// createAzureBearerToken requests a token for the managed identity of the VM
+// from the Azure Instance Metadata Service.
+func (a *AzureLogin) createAzureBearerToken() (string, error) {
+	addr := a.metadataAddr
+	if addr == "" {
+		addr = azureMetadataAddr
+	}
+
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", a.resource)
+	if a.clientID != "" {
+		query.Set("client_id", a.clientID)
+	}
+	req, err := http.NewRequest("GET", addr+azureTokenPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to contact the Azure metadata service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response code from the Azure metadata service: %d (%s)",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("failed to decode the Azure metadata service response: %w", err)
+	}
+	if out.AccessToken == "" {
+		return "", fmt.Errorf("no token in the Azure metadata service response")
+	}
+	return out.AccessToken, nil
+}
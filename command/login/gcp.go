@@ -0,0 +1,97 @@
+package login
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// gcpMetadataAddr is the address of the GCP metadata server, which can be
+	// overridden with the GCE_METADATA_HOST environment variable like in the
+	// Google client libraries.
+	gcpMetadataAddr = "metadata.google.internal"
+
+	gcpIdentityPath = "/computeMetadata/v1/instance/service-accounts/default/identity"
+)
+
+type GCPLogin struct {
+	autoBearerToken bool
+	audience        string
+
+	// metadataAddr overrides the address of the metadata server in tests.
+	metadataAddr string
+}
+
+func (g *GCPLogin) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.BoolVar(&g.autoBearerToken, "gcp-auto-bearer-token", false,
+		"Request an identity token for the service account attached to this VM from the GCP "+
+			"metadata server and login to the GCP IAM auth method with it. [gcp-iam only]")
+
+	fs.StringVar(&g.audience, "gcp-audience", "",
+		"Audience of the identity token requested from the GCP metadata server. Must match "+
+			"one of the BoundAudiences of the auth method. [gcp-iam only]")
+	return fs
+}
+
+// checkFlags validates flags for the gcp-iam auth method.
+func (g *GCPLogin) checkFlags() error {
+	if !g.autoBearerToken {
+		if g.audience != "" {
+			return fmt.Errorf("Missing '-gcp-auto-bearer-token' flag")
+		}
+		return nil
+	}
+	if g.audience == "" {
+		return fmt.Errorf("Missing '-gcp-audience' flag")
+	}
+	return nil
+}
+
+// createGCPBearerToken requests an identity token for the default service
+// account of the VM from the GCP metadata server. The full format is requested
+// so that the token includes the details of the VM instance.
+func (g *GCPLogin) createGCPBearerToken() (string, error) {
+	addr := g.metadataAddr
+	if addr == "" {
+		addr = os.Getenv("GCE_METADATA_HOST")
+	}
+	if addr == "" {
+		addr = gcpMetadataAddr
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	query := url.Values{}
+	query.Set("audience", g.audience)
+	query.Set("format", "full")
+	req, err := http.NewRequest("GET", addr+gcpIdentityPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to contact the GCP metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response code from the GCP metadata server: %d (%s)",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return strings.TrimSpace(string(body)), nil
+}
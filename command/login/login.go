@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/consul/api"
@@ -36,7 +37,11 @@ type cmd struct {
 	tokenSinkFile   string
 	meta            map[string]string
 
-	aws AWSLogin
+	oidcCallbackListenAddr string
+
+	aws   AWSLogin
+	gcp   GCPLogin
+	azure AzureLogin
 
 	enterpriseCmd
 }
@@ -59,10 +64,16 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.FlagMapValue)(&c.meta), "meta",
 		"Metadata to set on the token, formatted as key=value. This flag "+
 			"may be specified multiple times to set multiple meta fields.")
+	c.flags.StringVar(&c.oidcCallbackListenAddr, "oidc-callback-listen-addr", defaultOIDCCallbackListenAddr,
+		"The address to bind a webserver on to handle the browser callback from the OIDC workflow. "+
+			"The redirect URI http://<addr>/oidc/callback must be allowed by the auth method. [oidc only]")
+
 	c.initEnterpriseFlags()
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.aws.flags())
+	flags.Merge(c.flags, c.gcp.flags())
+	flags.Merge(c.flags, c.azure.flags())
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
 	flags.Merge(c.flags, c.http.MultiTenancyFlags())
@@ -91,23 +102,54 @@ func (c *cmd) Run(args []string) int {
 }
 
 func (c *cmd) bearerTokenLogin() int {
-	if err := c.aws.checkFlags(); err != nil {
-		c.UI.Error(err.Error())
-		return 1
+	for _, check := range []func() error{c.aws.checkFlags, c.gcp.checkFlags, c.azure.checkFlags} {
+		if err := check(); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
 	}
 
-	if c.aws.autoBearerToken {
-		if c.bearerTokenFile != "" {
-			c.UI.Error("Cannot use '-bearer-token-file' flag with '-aws-auto-bearer-token'")
-			return 1
+	var autoFlags []string
+	for name, enabled := range map[string]bool{
+		"-aws-auto-bearer-token":   c.aws.autoBearerToken,
+		"-gcp-auto-bearer-token":   c.gcp.autoBearerToken,
+		"-azure-auto-bearer-token": c.azure.autoBearerToken,
+	} {
+		if enabled {
+			autoFlags = append(autoFlags, name)
 		}
+	}
+	if len(autoFlags) > 1 {
+		sort.Strings(autoFlags)
+		c.UI.Error(fmt.Sprintf("Cannot use more than one of the '%s' flags", strings.Join(autoFlags, "', '")))
+		return 1
+	}
+	if len(autoFlags) == 1 && c.bearerTokenFile != "" {
+		c.UI.Error(fmt.Sprintf("Cannot use '-bearer-token-file' flag with '%s'", autoFlags[0]))
+		return 1
+	}
 
+	if c.aws.autoBearerToken {
 		if token, err := c.aws.createAWSBearerToken(); err != nil {
 			c.UI.Error(fmt.Sprintf("Error with aws-iam auth method: %s", err))
 			return 1
 		} else {
 			c.bearerToken = token
 		}
+	} else if c.gcp.autoBearerToken {
+		if token, err := c.gcp.createGCPBearerToken(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error with gcp-iam auth method: %s", err))
+			return 1
+		} else {
+			c.bearerToken = token
+		}
+	} else if c.azure.autoBearerToken {
+		if token, err := c.azure.createAzureBearerToken(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error with azure-msi auth method: %s", err))
+			return 1
+		} else {
+			c.bearerToken = token
+		}
 	} else if c.bearerTokenFile == "" {
 		c.UI.Error("Missing required '-bearer-token-file' flag")
 		return 1
@@ -2,6 +2,8 @@ package login
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -167,6 +169,37 @@ func TestLoginCommand(t *testing.T) {
 
 	})
 
+	t.Run("gcp and azure flags require auto-bearer-token", func(t *testing.T) {
+		defer os.Remove(tokenSinkFile)
+
+		baseArgs := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-method=test",
+			"-token-sink-file", tokenSinkFile,
+		}
+
+		for _, c := range []struct {
+			args      []string
+			expectErr string
+		}{
+			{[]string{"-gcp-audience", "some-audience"}, "Missing '-gcp-auto-bearer-token' flag"},
+			{[]string{"-gcp-auto-bearer-token"}, "Missing '-gcp-audience' flag"},
+			{[]string{"-azure-resource", "some-resource"}, "Missing '-azure-auto-bearer-token' flag"},
+			{[]string{"-azure-client-id", "some-id"}, "Missing '-azure-auto-bearer-token' flag"},
+			{[]string{"-azure-auto-bearer-token"}, "Missing '-azure-resource' flag"},
+			{
+				[]string{"-gcp-auto-bearer-token", "-gcp-audience", "a", "-azure-auto-bearer-token", "-azure-resource", "r"},
+				"Cannot use more than one of the '-azure-auto-bearer-token', '-gcp-auto-bearer-token' flags",
+			},
+		} {
+			ui := cli.NewMockUi()
+			code := New(ui).Run(append(baseArgs, c.args...))
+			require.Equal(t, 1, code, "err: %s", ui.ErrorWriter.String())
+			require.Contains(t, ui.ErrorWriter.String(), c.expectErr)
+		}
+	})
+
 	bearerTokenFile := filepath.Join(testDir, "bearer.token")
 
 	t.Run("bearer-token-file is empty", func(t *testing.T) {
@@ -688,3 +721,107 @@ func newTestAgent(t *testing.T) *agent.TestAgent {
 	testrpc.WaitForLeader(t, a.RPC, "dc1")
 	return a
 }
+
+func TestGCPLogin_createGCPBearerToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		require.Equal(t, gcpIdentityPath, r.URL.Path)
+		require.Equal(t, "https://consul.test", r.URL.Query().Get("audience"))
+		require.Equal(t, "full", r.URL.Query().Get("format"))
+		fmt.Fprintln(w, "identity-token")
+	}))
+	defer srv.Close()
+
+	g := &GCPLogin{autoBearerToken: true, audience: "https://consul.test", metadataAddr: srv.URL}
+	token, err := g.createGCPBearerToken()
+	require.NoError(t, err)
+	require.Equal(t, "identity-token", token)
+}
+
+func TestAzureLogin_createAzureBearerToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Path != azureTokenPath {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		require.Equal(t, "https://consul.test", r.URL.Query().Get("resource"))
+		require.Equal(t, "client-id", r.URL.Query().Get("client_id"))
+		fmt.Fprint(w, `{"access_token": "managed-identity-token", "token_type": "Bearer"}`)
+	}))
+	defer srv.Close()
+
+	a := &AzureLogin{
+		autoBearerToken: true,
+		resource:        "https://consul.test",
+		clientID:        "client-id",
+		metadataAddr:    srv.URL,
+	}
+	token, err := a.createAzureBearerToken()
+	require.NoError(t, err)
+	require.Equal(t, "managed-identity-token", token)
+
+	a.metadataAddr = srv.URL + "/unknown"
+	_, err = a.createAzureBearerToken()
+	require.Error(t, err)
+}
+
+func TestLoginCommand_oidcFlags(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-type=oidc",
+		"-method=test",
+		"-token-sink-file", "consul.token",
+		"-bearer-token-file", "none.txt",
+	})
+	require.Equal(t, 1, code, "err: %s", ui.ErrorWriter.String())
+	require.Contains(t, ui.ErrorWriter.String(), "Cannot use '-bearer-token-file' flag with '-type=oidc'")
+}
+
+func TestOIDCCallbackHandler(t *testing.T) {
+	cases := map[string]struct {
+		query       string
+		expectCode  int
+		expectState string
+		expectErr   string
+	}{
+		"success": {
+			query:       "state=st&code=cd",
+			expectCode:  http.StatusOK,
+			expectState: "st",
+		},
+		"provider error": {
+			query:      "error=access_denied&error_description=nope",
+			expectCode: http.StatusBadRequest,
+			expectErr:  "OIDC provider returned an error: access_denied nope",
+		},
+		"missing code": {
+			query:      "state=st",
+			expectCode: http.StatusBadRequest,
+			expectErr:  "missing the code or state parameter",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resultCh := make(chan oidcCallbackResult, 1)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", oidcCallbackPath+"?"+tc.query, nil)
+			oidcCallbackHandler(resultCh).ServeHTTP(rec, req)
+			require.Equal(t, tc.expectCode, rec.Code)
+
+			result := <-resultCh
+			if tc.expectErr != "" {
+				require.Error(t, result.err)
+				require.Contains(t, result.err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(t, result.err)
+			require.Equal(t, tc.expectState, result.state)
+			require.Equal(t, "cd", result.code)
+		})
+	}
+}
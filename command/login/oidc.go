@@ -0,0 +1,177 @@
+package login
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// defaultOIDCCallbackListenAddr is where the callback from the browser is
+	// received by default. The matching redirect URI,
+	// http://localhost:8550/oidc/callback, must be in the AllowedRedirectURIs
+	// of the auth method.
+	defaultOIDCCallbackListenAddr = "localhost:8550"
+
+	oidcCallbackPath = "/oidc/callback"
+)
+
+// oidcCallbackResult is what the provider sent back to the redirect URI.
+type oidcCallbackResult struct {
+	state string
+	code  string
+	err   error
+}
+
+// oidcLogin logs in to an auth method of type oidc: it sends the user to the
+// provider with their browser, waits for the provider to redirect the browser
+// back to a local listener, and exchanges the authorization code it received
+// for a Consul token.
+func (c *cmd) oidcLogin() int {
+	if c.bearerTokenFile != "" {
+		c.UI.Error("Cannot use '-bearer-token-file' flag with '-type=oidc'")
+		return 1
+	}
+	if c.aws.autoBearerToken || c.gcp.autoBearerToken || c.azure.autoBearerToken {
+		c.UI.Error("Cannot use the '-*-auto-bearer-token' flags with '-type=oidc'")
+		return 1
+	}
+
+	// Ensure that we don't try to use a token when performing a login
+	// operation.
+	c.http.SetToken("")
+	c.http.SetTokenFile("")
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	listener, err := net.Listen("tcp", c.oidcCallbackListenAddr)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listening for the OIDC callback on %s: %s", c.oidcCallbackListenAddr, err))
+		return 1
+	}
+	defer listener.Close()
+
+	clientNonce, err := uuid.GenerateUUID()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error generating client nonce: %s", err))
+		return 1
+	}
+
+	authURL, _, err := client.ACL().OIDCAuthURL(&api.ACLOIDCAuthURLParams{
+		AuthMethod:  c.authMethodName,
+		RedirectURI: "http://" + c.oidcCallbackListenAddr + oidcCallbackPath,
+		ClientNonce: clientNonce,
+		Meta:        c.meta,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error fetching the OIDC authorization URL: %s", err))
+		return 1
+	}
+
+	resultCh := make(chan oidcCallbackResult, 1)
+	srv := &http.Server{Handler: oidcCallbackHandler(resultCh)}
+	go srv.Serve(listener)
+	defer srv.Shutdown(context.Background())
+
+	c.UI.Output("Complete the login via your OIDC provider. Launching browser to:\n")
+	c.UI.Output("    " + authURL + "\n")
+	if err := open.Start(authURL); err != nil {
+		c.UI.Warn(fmt.Sprintf("Error opening the browser, open the URL above manually: %s", err))
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	var result oidcCallbackResult
+	select {
+	case result = <-resultCh:
+	case <-sigCh:
+		c.UI.Error("Interrupted while waiting for the OIDC callback")
+		return 1
+	case <-c.shutdownCh:
+		return 1
+	}
+	if result.err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", result.err))
+		return 1
+	}
+
+	tok, _, err := client.ACL().OIDCCallback(&api.ACLOIDCCallbackParams{
+		AuthMethod:  c.authMethodName,
+		State:       result.state,
+		Code:        result.code,
+		ClientNonce: clientNonce,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if err := c.writeToSink(tok); err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing token to file sink: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+// oidcCallbackHandler handles the redirect of the browser by the provider and
+// sends the outcome to resultCh. Only the first callback is considered.
+func oidcCallbackHandler(resultCh chan<- oidcCallbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		var result oidcCallbackResult
+		switch {
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("OIDC provider returned an error: %s",
+				strings.TrimSpace(query.Get("error")+" "+query.Get("error_description")))
+		case query.Get("code") == "" || query.Get("state") == "":
+			result.err = errors.New("OIDC callback is missing the code or state parameter")
+		default:
+			result.state = query.Get("state")
+			result.code = query.Get("code")
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, oidcCallbackPage, "Login failed", html.EscapeString(result.err.Error()))
+		} else {
+			fmt.Fprintf(w, oidcCallbackPage, "Login successful", "You can close this window and return to the terminal.")
+		}
+
+		select {
+		case resultCh <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+const oidcCallbackPage = `<!DOCTYPE html>
+<html>
+<head><title>Consul</title></head>
+<body>
+<h1>%s</h1>
+<p>%s</p>
+</body>
+</html>
+`
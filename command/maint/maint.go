@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 // cmd is a Command implementation that enables or disables
@@ -147,6 +149,16 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"service": completion.LocalServiceIDs(),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -1,6 +1,7 @@
 package members
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net"
@@ -8,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
 	"github.com/ryanuber/columnize"
@@ -30,8 +32,15 @@ type cmd struct {
 	wan          bool
 	statusFilter string
 	segment      string
+	filter       string
+	format       string
 }
 
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
 func New(ui cli.Ui) *cmd {
 	c := &cmd{UI: ui}
 	c.init()
@@ -51,6 +60,12 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.segment, "segment", consulapi.AllSegments,
 		"(Enterprise-only) If provided, output is filtered to only nodes in"+
 			"the given segment.")
+	c.flags.StringVar(&c.filter, "filter", "",
+		"Filter to use with the request. The expression is evaluated against the "+
+			"fields of the JSON output, such as Status, Build or Meta.")
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}. The JSON output includes the node "+
+			"metadata of the members.", PrettyFormat, JSONFormat))
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -63,6 +78,21 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format: %s", c.format))
+		return 1
+	}
+
+	var filter *bexpr.Filter
+	if c.filter != "" {
+		var err error
+		filter, err = bexpr.CreateFilter(c.filter, nil, []*member{})
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to create filter: %v", err))
+			return 1
+		}
+	}
+
 	// Compile the regexp
 	statusRe, err := regexp.Compile(c.statusFilter)
 	if err != nil {
@@ -113,13 +143,48 @@ func (c *cmd) Run(args []string) int {
 	}
 	members = members[:n]
 
+	sort.Sort(ByMemberNamePartitionAndSegment(members))
+
+	if filter != nil || c.format == JSONFormat {
+		records, err := c.memberRecords(client, members)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error retrieving node metadata: %s", err))
+			return 1
+		}
+
+		if filter != nil {
+			raw, err := filter.Execute(records)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Failed to filter members: %v", err))
+				return 1
+			}
+			records = raw.([]*member)
+		}
+
+		members = members[:0]
+		for _, r := range records {
+			members = append(members, r.member)
+		}
+
+		if c.format == JSONFormat {
+			if len(records) == 0 {
+				return 2
+			}
+			out, err := json.MarshalIndent(records, "", "    ")
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error encoding members: %s", err))
+				return 1
+			}
+			c.UI.Output(string(out))
+			return 0
+		}
+	}
+
 	// No matching members
 	if len(members) == 0 {
 		return 2
 	}
 
-	sort.Sort(ByMemberNamePartitionAndSegment(members))
-
 	// Generate the output
 	var result []string
 	if c.detailed {
@@ -135,6 +200,75 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// member is a member of the cluster as output in JSON and evaluated by the
+// filter expression.
+type member struct {
+	Name       string
+	Address    string
+	Port       uint16
+	Status     string
+	Type       string
+	Build      string
+	Protocol   string
+	Datacenter string
+	Partition  string
+	Segment    string
+	Tags       map[string]string
+	Meta       map[string]string
+
+	member *consulapi.AgentMember
+}
+
+// memberRecords returns the records of the members, including the node
+// metadata of the LAN members from the catalog.
+func (c *cmd) memberRecords(client *consulapi.Client, members []*consulapi.AgentMember) ([]*member, error) {
+	meta := make(map[string]map[string]string)
+	if !c.wan {
+		nodes, _, err := client.Catalog().Nodes(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			meta[node.Node] = node.Meta
+		}
+	}
+
+	records := make([]*member, 0, len(members))
+	for _, m := range members {
+		tags := parseTags(m.Tags)
+
+		typ := "unknown"
+		switch tags.role {
+		case consulapi.MemberTagValueRoleClient:
+			typ = "client"
+		case consulapi.MemberTagValueRoleServer:
+			typ = "server"
+		}
+
+		build := m.Tags["build"]
+		if idx := strings.Index(build, ":"); idx != -1 {
+			build = build[:idx]
+		}
+
+		records = append(records, &member{
+			Name:       m.Name,
+			Address:    m.Addr,
+			Port:       m.Port,
+			Status:     serf.MemberStatus(m.Status).String(),
+			Type:       typ,
+			Build:      build,
+			Protocol:   m.Tags["vsn"],
+			Datacenter: tags.datacenter,
+			Partition:  tags.partition,
+			Segment:    tags.segment,
+			Tags:       m.Tags,
+			Meta:       meta[m.Name],
+			member:     m,
+		})
+	}
+	return records, nil
+}
+
 // ByMemberNamePartitionAndSegment sorts members by name with a stable sort.
 //
 // 1. servers go at the top
@@ -287,4 +421,12 @@ const help = `
 Usage: consul members [options]
 
   Outputs the members of a running Consul agent.
+
+  List the servers running a given build:
+
+      $ consul members -filter 'Type == server and Build == "1.15.0"'
+
+  Output the members, including their node metadata, in JSON:
+
+      $ consul members -format=json
 `
@@ -2,6 +2,7 @@ package members
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"sort"
@@ -14,6 +15,8 @@ import (
 	"github.com/hashicorp/consul/agent"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
 )
 
 // TODO(partitions): split these tests
@@ -114,6 +117,95 @@ func TestMembersCommand_statusFilter(t *testing.T) {
 	}
 }
 
+func TestMembersCommand_filter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, `node_meta { env = "prod" }`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	waitForNodeMeta(t, a, "env", "prod")
+
+	t.Run("match", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `Type == server and Meta.env == prod`,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), a.Config.NodeName)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `Meta.env == dev`,
+		})
+		require.Equal(t, 2, code, ui.ErrorWriter.String())
+		require.NotContains(t, ui.OutputWriter.String(), a.Config.NodeName)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `Unknown == foo`,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Failed to create filter")
+	})
+}
+
+func TestMembersCommand_jsonFormat(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, `node_meta { env = "prod" }`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	waitForNodeMeta(t, a, "env", "prod")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-format=json",
+	})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var out []map[string]interface{}
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Len(t, out, 1)
+	require.Equal(t, a.Config.NodeName, out[0]["Name"])
+	require.Equal(t, "server", out[0]["Type"])
+	require.Equal(t, "alive", out[0]["Status"])
+	require.Equal(t, "dc1", out[0]["Datacenter"])
+	require.NotEmpty(t, out[0]["Build"])
+	meta, ok := out[0]["Meta"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "prod", meta["env"])
+}
+
+// waitForNodeMeta waits for the node of the agent to have the meta key in the
+// catalog, which happens once the agent synced it.
+func waitForNodeMeta(t *testing.T, a *agent.TestAgent, key, value string) {
+	retry.Run(t, func(r *retry.R) {
+		node, _, err := a.Client().Catalog().Node(a.Config.NodeName, nil)
+		require.NoError(r, err)
+		require.NotNil(r, node)
+		require.Equal(r, value, node.Node.Meta[key])
+	})
+}
+
 func TestMembersCommand_statusFilter_failed(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,151 @@
+package health
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	format     string
+	failedOnly bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}", PrettyFormat, JSONFormat))
+	c.flags.BoolVar(&c.failedOnly, "failed-only", false, "Display only the peers that "+
+		"failed at least once since the agent started.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Unknown output format %q", c.format))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	health, err := client.Operator().GossipHealth(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying the gossip health: %s", err))
+		return 1
+	}
+
+	if c.failedOnly {
+		peers := make([]api.GossipPeerHealth, 0, len(health.Peers))
+		for _, p := range health.Peers {
+			if p.Failures > 0 || p.Status == "failed" {
+				peers = append(peers, p)
+			}
+		}
+		health.Peers = peers
+	}
+
+	if c.format == JSONFormat {
+		out, err := json.MarshalIndent(health, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding the gossip health: %s", err))
+			return 1
+		}
+		c.UI.Output(string(out))
+		return 0
+	}
+
+	c.UI.Output(formatHealth(health))
+	return 0
+}
+
+func formatHealth(health *api.GossipHealth) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Node:                %s\n", health.Node)
+	fmt.Fprintf(&b, "Health Score:        %d\n", health.HealthScore)
+	fmt.Fprintf(&b, "Partition Suspected: %t\n", health.PartitionSuspected)
+	if len(health.Peers) == 0 {
+		b.WriteString("\nNo peers to display.")
+		return b.String()
+	}
+
+	result := []string{"Node\x1fAddress\x1fStatus\x1fRTT\x1fFailures\x1fFlaps\x1fLast Failure"}
+	for _, p := range health.Peers {
+		rtt := "-"
+		if p.RTT > 0 {
+			rtt = p.RTT.Round(10 * time.Microsecond).String()
+		}
+		lastFailure := "-"
+		if p.LastFailure != nil {
+			lastFailure = p.LastFailure.Format(time.RFC3339)
+		}
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s\x1f%d\x1f%d\x1f%s",
+			p.Node, p.Address, p.Status, rtt, p.Failures, p.Flaps, lastFailure))
+	}
+	b.WriteString("\n")
+	b.WriteString(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return b.String()
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the health of the gossip with the peers of an agent"
+const help = `
+Usage: consul operator gossip health [options]
+
+  Displays the health of the LAN gossip pool as seen from the agent the
+  command talks to: the round trip times to its peers estimated from the
+  network coordinates, the number of times each peer failed and came back,
+  and whether a network partition is suspected.
+
+  Running the command against agents on both sides of a suspected flaky link
+  helps localizing it: a peer failing from a few agents only points at the
+  network between them, a high health score at the agent itself.
+
+  Display the peers of the local agent that failed at least once:
+
+      $ consul operator gossip health -failed-only
+`
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestOperatorGossipHealthCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestOperatorGossipHealthCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=json"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var health api.GossipHealth
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &health))
+	require.Equal(t, a.Config.NodeName, health.Node)
+	require.False(t, health.PartitionSuspected)
+}
+
+func TestFormatHealth(t *testing.T) {
+	lastFailure := time.Date(2023, 10, 16, 10, 0, 0, 0, time.UTC)
+	health := &api.GossipHealth{
+		Node:               "node1",
+		HealthScore:        1,
+		PartitionSuspected: true,
+		Peers: []api.GossipPeerHealth{
+			{Node: "node2", Address: "10.0.0.2", Status: "alive", RTT: 1234567 * time.Nanosecond},
+			{Node: "node3", Address: "10.0.0.3", Status: "failed", Failures: 2, Flaps: 1, LastFailure: &lastFailure},
+		},
+	}
+
+	require.Equal(t, `Node:                node1
+Health Score:        1
+Partition Suspected: true
+
+Node   Address   Status  RTT     Failures  Flaps  Last Failure
+node2  10.0.0.2  alive   1.23ms  0         0      -
+node3  10.0.0.3  failed  -       2         1      2023-10-16T10:00:00Z`, formatHealth(health))
+}
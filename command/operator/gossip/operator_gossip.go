@@ -0,0 +1,32 @@
+package gossip
+
+import (
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New() *cmd {
+	return &cmd{}
+}
+
+type cmd struct{}
+
+func (c *cmd) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(help, nil)
+}
+
+const synopsis = "Provides tools for troubleshooting the gossip network"
+const help = `
+Usage: consul operator gossip <subcommand> [options]
+
+  The gossip operator command is used to inspect the LAN gossip pool as seen
+  from an agent, to localize flaky network segments between agents.
+`
@@ -29,6 +29,7 @@ type cmd struct {
 	onlyBillable   bool
 	onlyConnect    bool
 	allDatacenters bool
+	history        bool
 }
 
 func (c *cmd) init() {
@@ -37,6 +38,8 @@ func (c *cmd) init() {
 	c.flags.BoolVar(&c.onlyConnect, "connect", false, "Display only Connect service info.")
 	c.flags.BoolVar(&c.allDatacenters, "all-datacenters", false, "Display service counts from "+
 		"all datacenters.")
+	c.flags.BoolVar(&c.history, "history", false, "Also display the daily service counts "+
+		"of the last 30 days, as sampled by the servers.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -63,7 +66,11 @@ func (c *cmd) Run(args []string) int {
 
 	billableTotal := 0
 	var datacenterBillableTotals []string
-	usage, _, err := client.Operator().Usage(&api.QueryOptions{Global: c.allDatacenters})
+	q := &api.QueryOptions{Global: c.allDatacenters}
+	usage, _, err := client.Operator().Usage(q)
+	if c.history {
+		usage, _, err = client.Operator().UsageHistory(q)
+	}
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error fetching usage information: %s", err))
 		return 1
@@ -102,14 +109,39 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Output(connectOutput)
 	}
 
+	// Output service counts by kind
+	if !c.onlyBillable && !c.onlyConnect {
+		c.UI.Output("\nService Instances by Kind")
+		kindOutput, err := formatServiceKindCounts(usage.Usage, c.allDatacenters)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.UI.Output(kindOutput + "\n")
+
+		externalTotal := 0
+		for _, usage := range usage.Usage {
+			externalTotal += usage.ExternalServiceInstances
+		}
+		c.UI.Output(fmt.Sprintf("External Service Instances Total: %d", externalTotal))
+	}
+
+	// Output the daily service counts
+	if c.history {
+		c.UI.Output("\nDaily Service Instances")
+		historyOutput, err := formatHistory(usage.History, c.allDatacenters)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.UI.Output(historyOutput)
+	}
+
 	return 0
 }
 
 func formatServiceCounts(usageStats map[string]api.ServiceUsage, billable, showDatacenter bool) (string, error) {
-	var output bytes.Buffer
-	tw := tabwriter.NewWriter(&output, 0, 2, 6, ' ', 0)
 	var serviceCounts []serviceCount
-
 	for datacenter, usage := range usageStats {
 		if billable {
 			serviceCounts = append(serviceCounts, getBillableInstanceCounts(usage, datacenter)...)
@@ -117,6 +149,20 @@ func formatServiceCounts(usageStats map[string]api.ServiceUsage, billable, showD
 			serviceCounts = append(serviceCounts, getConnectInstanceCounts(usage, datacenter)...)
 		}
 	}
+	return formatCounts(serviceCounts, billable, showDatacenter)
+}
+
+func formatServiceKindCounts(usageStats map[string]api.ServiceUsage, showDatacenter bool) (string, error) {
+	var serviceCounts []serviceCount
+	for datacenter, usage := range usageStats {
+		serviceCounts = append(serviceCounts, getServiceKindInstanceCounts(usage, datacenter)...)
+	}
+	return formatCounts(serviceCounts, false, showDatacenter)
+}
+
+func formatCounts(serviceCounts []serviceCount, billable, showDatacenter bool) (string, error) {
+	var output bytes.Buffer
+	tw := tabwriter.NewWriter(&output, 0, 2, 6, ' ', 0)
 
 	sortServiceCounts(serviceCounts)
 
@@ -183,6 +229,41 @@ func formatServiceCounts(usageStats map[string]api.ServiceUsage, billable, showD
 	return strings.TrimSpace(output.String()), nil
 }
 
+func formatHistory(history map[string][]api.ServiceUsageSample, showDatacenter bool) (string, error) {
+	var output bytes.Buffer
+	tw := tabwriter.NewWriter(&output, 0, 2, 6, ' ', 0)
+
+	datacenters := make([]string, 0, len(history))
+	for datacenter := range history {
+		datacenters = append(datacenters, datacenter)
+	}
+	sort.Strings(datacenters)
+
+	if showDatacenter {
+		fmt.Fprintf(tw, "Datacenter\t")
+	}
+	fmt.Fprintf(tw, "Date\tServices\tService instances\tBillable\tConnect proxies\tExternal\n")
+	for _, datacenter := range datacenters {
+		for _, sample := range history[datacenter] {
+			if showDatacenter {
+				fmt.Fprintf(tw, "%s\t", datacenter)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n",
+				sample.Time.UTC().Format("2006-01-02"),
+				sample.Services,
+				sample.ServiceInstances,
+				sample.BillableServiceInstances,
+				sample.ServiceKindInstances["connect-proxy"],
+				sample.ExternalServiceInstances)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("Error flushing tabwriter: %s", err)
+	}
+	return strings.TrimSpace(output.String()), nil
+}
+
 type serviceCount struct {
 	datacenter    string
 	partition     string
@@ -236,6 +317,14 @@ Usage: consul usage instances [options]
 
       $ consul usage instances -connect
 
+  The service instances are also counted by kind, including the typical
+  services and the gateways, and the instances registered on external nodes
+  with the "external-node" node meta are counted separately.
+
+  To also show the daily service instance counts of the last 30 days:
+
+      $ consul usage instances -history
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
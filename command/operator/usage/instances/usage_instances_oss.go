@@ -37,3 +37,19 @@ func getConnectInstanceCounts(usage api.ServiceUsage, datacenter string) []servi
 
 	return counts
 }
+
+func getServiceKindInstanceCounts(usage api.ServiceUsage, datacenter string) []serviceCount {
+	var counts []serviceCount
+
+	for serviceType, instanceCount := range usage.ServiceKindInstances {
+		counts = append(counts, serviceCount{
+			datacenter:    datacenter,
+			partition:     acl.DefaultPartitionName,
+			namespace:     acl.DefaultNamespaceName,
+			serviceType:   serviceType,
+			instanceCount: instanceCount,
+		})
+	}
+
+	return counts
+}
@@ -114,3 +114,23 @@ Total                                    45`,
 		})
 	}
 }
+
+func TestUsageInstances_formatServiceKindCounts(t *testing.T) {
+	usage := map[string]api.ServiceUsage{
+		"dc1": {
+			ServiceKindInstances: map[string]int{
+				"typical":         7,
+				"connect-proxy":   3,
+				"ingress-gateway": 1,
+			},
+		},
+	}
+
+	output, err := formatServiceKindCounts(usage, false)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(`
+Type                 Service instances
+connect-proxy        3
+ingress-gateway      1
+typical              7`), output)
+}
@@ -1,7 +1,9 @@
 package instances
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
@@ -47,4 +49,44 @@ func TestUsageInstancesCommand(t *testing.T) {
 	}
 	output := ui.OutputWriter.String()
 	require.Contains(t, output, "Billable Service Instances Total: 2")
+	require.Contains(t, output, "Service Instances by Kind")
+	require.Contains(t, output, "External Service Instances Total: 0")
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run(append(args, "-history"))
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Daily Service Instances")
+}
+
+func TestUsageInstances_formatHistory(t *testing.T) {
+	history := map[string][]api.ServiceUsageSample{
+		"dc1": {
+			{
+				Time: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC),
+				ServiceUsage: api.ServiceUsage{
+					Services:                 3,
+					ServiceInstances:         5,
+					BillableServiceInstances: 3,
+					ServiceKindInstances:     map[string]int{"typical": 4, "connect-proxy": 1},
+					ExternalServiceInstances: 2,
+				},
+			},
+			{
+				Time: time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC),
+				ServiceUsage: api.ServiceUsage{
+					Services:                 4,
+					ServiceInstances:         7,
+					BillableServiceInstances: 4,
+					ServiceKindInstances:     map[string]int{"typical": 5, "connect-proxy": 2},
+				},
+			},
+		},
+	}
+
+	output, err := formatHistory(history, false)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(`
+Date            Services      Service instances      Billable      Connect proxies      External
+2022-03-01      3             5                      3             1                    2
+2022-03-02      4             7                      4             2                    0`), output)
 }
@@ -137,6 +137,22 @@ func formatPeering(peering *api.Peering) string {
 	buffer.WriteString(fmt.Sprintf("Last Send:         %v\n", peering.StreamStatus.LastSend))
 	buffer.WriteString(fmt.Sprintf("Last Receive:      %v\n", peering.StreamStatus.LastReceive))
 	buffer.WriteString("\n")
+	buffer.WriteString(fmt.Sprintf("Messages Received:     %d\n", peering.StreamStatus.MessagesReceived))
+	buffer.WriteString(fmt.Sprintf("Messages Sent:         %d\n", peering.StreamStatus.MessagesSent))
+	buffer.WriteString(fmt.Sprintf("Messages Rate Limited: %d\n", peering.StreamStatus.MessagesRateLimited))
+	buffer.WriteString("\n")
+	buffer.WriteString(fmt.Sprintf("Pending Responses: %d\n", peering.StreamStatus.PendingResponses))
+	buffer.WriteString(fmt.Sprintf("Retry Attempts:    %d\n", peering.StreamStatus.RetryAttempts))
+	if peering.StreamStatus.NextRetry != nil {
+		buffer.WriteString(fmt.Sprintf("Next Retry:        %v\n", peering.StreamStatus.NextRetry))
+	}
+	if len(peering.StreamStatus.RecentErrors) > 0 {
+		buffer.WriteString("Recent Errors:\n")
+		for _, e := range peering.StreamStatus.RecentErrors {
+			buffer.WriteString(fmt.Sprintf("    %v [%s] %s\n", e.Time, e.Kind, e.Message))
+		}
+	}
+	buffer.WriteString("\n")
 	buffer.WriteString(fmt.Sprintf("Create Index: %d\n", peering.CreateIndex))
 	buffer.WriteString(fmt.Sprintf("Modify Index: %d\n", peering.ModifyIndex))
 
@@ -110,6 +110,8 @@ func TestReadCommand(t *testing.T) {
 		require.Contains(t, output, "Imported Services")
 		require.Contains(t, output, "Exported Services")
 		require.Contains(t, output, "Last Heartbeat")
+		require.Contains(t, output, "Messages Rate Limited")
+		require.Contains(t, output, "Pending Responses")
 		require.Contains(t, output, "Last Send")
 		require.Contains(t, output, "Last Receive")
 	})
@@ -5,9 +5,11 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/hashicorp/consul/command/services"
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -92,6 +94,16 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"id": completion.LocalServiceIDs(),
+	})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -0,0 +1,169 @@
+package drain
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/completion"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	flagID      string
+	flagEnable  bool
+	flagDisable bool
+	flagReason  string
+	flagTimeout time.Duration
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.flagID, "id", "",
+		"ID of the service to start or stop draining.")
+	c.flags.BoolVar(&c.flagEnable, "enable", false,
+		"Start draining the service.")
+	c.flags.BoolVar(&c.flagDisable, "disable", false,
+		"Stop draining the service.")
+	c.flags.StringVar(&c.flagReason, "reason", "",
+		"Text describing the draining reason.")
+	c.flags.DurationVar(&c.flagTimeout, "timeout", 0,
+		"Duration after which draining is lifted automatically. If not set, the "+
+			"service drains until draining is disabled.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Ensure we don't have conflicting args
+	if c.flagEnable && c.flagDisable {
+		c.UI.Error("Only one of -enable or -disable may be provided")
+		return 1
+	}
+	if !c.flagEnable && (c.flagReason != "" || c.flagTimeout != 0) {
+		c.UI.Error("Reason and timeout may only be provided with -enable")
+		return 1
+	}
+	if c.flagTimeout < 0 {
+		c.UI.Error("Timeout must not be negative")
+		return 1
+	}
+	if (c.flagEnable || c.flagDisable) && c.flagID == "" {
+		c.UI.Error("Service draining requires -id")
+		return 1
+	}
+	if !c.flagEnable && !c.flagDisable && c.flagID != "" {
+		c.UI.Error("Service ID requires either -enable or -disable")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+	a := client.Agent()
+
+	switch {
+	case c.flagEnable:
+		if err := a.EnableServiceDraining(c.flagID, c.flagReason, c.flagTimeout); err != nil {
+			c.UI.Error(fmt.Sprintf("Error enabling service draining: %s", err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("Service draining is now enabled for %q", c.flagID))
+
+	case c.flagDisable:
+		if err := a.DisableServiceDraining(c.flagID); err != nil {
+			c.UI.Error(fmt.Sprintf("Error disabling service draining: %s", err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("Service draining is now disabled for %q", c.flagID))
+
+	default:
+		// List mode - list the draining services
+		checks, err := a.Checks()
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error getting checks: %s", err))
+			return 1
+		}
+
+		for _, check := range checks {
+			if strings.HasPrefix(check.CheckID, api.ServiceDrainingPrefix) {
+				c.UI.Output("Service:")
+				c.UI.Output("  ID:     " + check.ServiceID)
+				c.UI.Output("  Reason: " + check.Notes)
+				c.UI.Output("")
+			}
+		}
+	}
+
+	return 0
+}
+
+func (c *cmd) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *cmd) AutocompleteFlags() complete.Flags {
+	return completion.Flags(c.flags, map[string]complete.Predictor{
+		"id": completion.LocalServiceIDs(),
+	})
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Drain services registered with the local agent"
+	help     = `
+Usage: consul services drain [options]
+
+  Starts or stops draining a service registered with the local agent. A
+  draining service stays registered and passing, but it is given a weight of
+  zero in DNS and the service mesh stops opening new connections to it, while
+  allowing the existing ones to finish. This is done by registering an
+  additional passing health check.
+
+      $ consul services drain -id=web -enable -reason="deploying v2"
+
+  The -timeout flag may be used to lift the draining state automatically:
+
+      $ consul services drain -id=web -enable -timeout=5m
+
+  Draining without a timeout is persistent, and will be restored in the event
+  of an agent restart. Draining with a timeout ends if the agent restarts.
+
+  If neither -enable nor -disable is given, the draining services of the
+  agent will be shown.
+`
+)
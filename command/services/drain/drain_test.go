@@ -0,0 +1,87 @@
+package drain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestCommand_ConflictingArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"enable and disable":        {"-id=web", "-enable", "-disable"},
+		"reason without enable":     {"-id=web", "-disable", "-reason=deploy"},
+		"timeout without enable":    {"-timeout=1m"},
+		"negative timeout":          {"-id=web", "-enable", "-timeout=-1m"},
+		"enable without id":         {"-enable"},
+		"id without enable/disable": {"-id=web"},
+	}
+
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			c.flags.SetOutput(ui.ErrorWriter)
+
+			require.Equal(t, 1, c.Run(args))
+		})
+	}
+}
+
+func TestCommand_EnableDisable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	addReq := agent.AddServiceRequest{
+		Service: &structs.NodeService{
+			ID:      "web",
+			Service: "web",
+		},
+		Source: agent.ConfigSourceLocal,
+	}
+	require.NoError(t, a.AddService(addReq))
+
+	checkID := structs.NewCheckID(structs.ServiceDrainingPrefix+"web", nil)
+
+	// Enable draining
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-id=web", "-enable", "-reason=deploying"}
+	require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "now enabled")
+	require.NotNil(t, a.State.Check(checkID))
+
+	// List the draining services
+	ui = cli.NewMockUi()
+	c = New(ui)
+	require.Equal(t, 0, c.Run([]string{"-http-addr=" + a.HTTPAddr()}), ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "ID:     web")
+	require.Contains(t, out, "Reason: deploying")
+
+	// Disable draining
+	ui = cli.NewMockUi()
+	c = New(ui)
+	args = []string{"-http-addr=" + a.HTTPAddr(), "-id=web", "-disable"}
+	require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "now disabled")
+	require.Nil(t, a.State.Check(checkID))
+}
@@ -69,6 +69,28 @@ func (_ *prettyFormatter) Format(info *OutputFormat) (string, error) {
 		fmt.Fprintf(tw, "\n Total\t\t%s", ByteSize(uint64(info.TotalSizeKV)))
 	}
 
+	if deep := info.Deep; deep != nil {
+		fmt.Fprintf(tw, "\n")
+		fmt.Fprintln(tw, "\n Config Entry Kind\tCount\tSize")
+		fmt.Fprintf(tw, " %s\t%s\t%s", "----", "----", "----")
+		for _, s := range deep.ConfigEntries {
+			fmt.Fprintf(tw, "\n %s\t%d\t%s", s.Name, s.Count, ByteSize(uint64(s.Sum)))
+		}
+
+		fmt.Fprintf(tw, "\n")
+		fmt.Fprintf(tw, "\n Intentions\t%d", deep.Intentions)
+		fmt.Fprintf(tw, "\n ACL Tokens\t%d", deep.ACLTokens)
+
+		fmt.Fprintf(tw, "\n")
+		fmt.Fprintln(tw, "\n Oversized Record\tType\tSize")
+		fmt.Fprintf(tw, " %s\t%s\t%s", "----", "----", "----")
+		for _, r := range deep.Oversized {
+			fmt.Fprintf(tw, "\n %s\t%s\t%s", r.Name, r.Type, ByteSize(uint64(r.Size)))
+		}
+		fmt.Fprintf(tw, "\n %s\t%s\t%s", "----", "----", "----")
+		fmt.Fprintf(tw, "\n Threshold\t\t%s", ByteSize(uint64(deep.OversizedThreshold)))
+	}
+
 	if err := tw.Flush(); err != nil {
 		return b.String(), err
 	}
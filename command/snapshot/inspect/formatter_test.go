@@ -50,3 +50,42 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat_Deep(t *testing.T) {
+	info := OutputFormat{
+		Meta: &MetadataInfo{ID: "one"},
+		Deep: &DeepInfo{
+			ConfigEntries: []typeStats{{
+				Name:  "service-defaults",
+				Sum:   2048,
+				Count: 3,
+			}},
+			Intentions:         4,
+			ACLTokens:          5,
+			OversizedThreshold: 1024,
+			Oversized: []recordStats{{
+				Type: "KVS",
+				Name: "big/key",
+				Size: 4096,
+			}},
+		},
+	}
+
+	out, err := newPrettyFormatter().Format(&info)
+	require.NoError(t, err)
+	require.Contains(t, out, "service-defaults")
+	require.Contains(t, out, "Intentions      4")
+	require.Contains(t, out, "ACL Tokens      5")
+	require.Contains(t, out, "big/key")
+	require.Contains(t, out, "4KB")
+
+	out, err = newJSONFormatter().Format(&info)
+	require.NoError(t, err)
+	require.Contains(t, out, `"Intentions": 4`)
+	require.Contains(t, out, `"Name": "big/key"`)
+
+	info.Deep = nil
+	out, err = newJSONFormatter().Format(&info)
+	require.NoError(t, err)
+	require.NotContains(t, out, "Deep")
+}
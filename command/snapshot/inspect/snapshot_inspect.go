@@ -33,9 +33,11 @@ type cmd struct {
 	format string
 
 	// flags
-	kvDetails bool
-	kvDepth   int
-	kvFilter  string
+	kvDetails          bool
+	kvDepth            int
+	kvFilter           string
+	deep               bool
+	oversizedThreshold int
 }
 
 func (c *cmd) init() {
@@ -46,6 +48,13 @@ func (c *cmd) init() {
 		"Can only be used with -kvdetails. The key prefix depth used to breakdown KV store data. Defaults to 2.")
 	c.flags.StringVar(&c.kvFilter, "kvfilter", "",
 		"Can only be used with -kvdetails. Limits KV key breakdown using this prefix filter.")
+	c.flags.BoolVar(&c.deep, "deep", false,
+		"Enumerates the contents of the snapshot by type: config entries per kind, "+
+			"intentions, ACL tokens and the KV breakdown of -kvdetails, and lists "+
+			"the records larger than -oversized-threshold.")
+	c.flags.IntVar(&c.oversizedThreshold, "oversized-threshold", 512*1024,
+		"Can only be used with -deep. The size in bytes above which a record is "+
+			"reported as oversized. Defaults to 524288 (512KB).")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -73,6 +82,37 @@ type SnapshotInfo struct {
 	StatsKV     map[string]typeStats
 	TotalSize   int
 	TotalSizeKV int
+	Deep        *DeepInfo
+}
+
+// DeepInfo is the breakdown of the snapshot contents produced with -deep.
+type DeepInfo struct {
+	// ConfigEntries are the stats of the config entries per kind.
+	ConfigEntries []typeStats
+
+	// Intentions is the number of intentions, both legacy ones and the
+	// sources of the service-intentions config entries.
+	Intentions int
+
+	// ACLTokens is the number of ACL tokens.
+	ACLTokens int
+
+	// OversizedThreshold is the size in bytes above which a record is
+	// reported in Oversized.
+	OversizedThreshold int
+
+	// Oversized are the records larger than OversizedThreshold, largest
+	// first.
+	Oversized []recordStats
+
+	configEntries map[string]typeStats
+}
+
+// recordStats identifies a single record of the snapshot and its size.
+type recordStats struct {
+	Type string
+	Name string
+	Size int
 }
 
 // OutputFormat is used for passing information
@@ -83,6 +123,7 @@ type OutputFormat struct {
 	StatsKV     []typeStats
 	TotalSize   int
 	TotalSizeKV int
+	Deep        *DeepInfo `json:",omitempty"`
 }
 
 func (c *cmd) Run(args []string) int {
@@ -91,6 +132,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.oversizedThreshold <= 0 {
+		c.UI.Error("-oversized-threshold must be greater than 0")
+		return 1
+	}
+	if c.deep {
+		// The deep mode includes the KV breakdown.
+		c.kvDetails = true
+	}
+
 	var file string
 	args = c.flags.Args()
 
@@ -180,6 +230,7 @@ func (c *cmd) Run(args []string) int {
 		StatsKV:     formattedStatsKV,
 		TotalSize:   info.TotalSize,
 		TotalSizeKV: info.TotalSizeKV,
+		Deep:        generateDeepStats(info),
 	}
 
 	out, err := formatter.Format(in)
@@ -232,6 +283,27 @@ func generateKVStats(info SnapshotInfo) []typeStats {
 	return nil
 }
 
+// generateDeepStats sorts the deep stats for the output struct, or returns
+// nil if -deep wasn't used.
+func generateDeepStats(info SnapshotInfo) *DeepInfo {
+	deep := info.Deep
+	if deep == nil {
+		return nil
+	}
+
+	deep.ConfigEntries = make([]typeStats, 0, len(deep.configEntries))
+	for _, s := range deep.configEntries {
+		deep.ConfigEntries = append(deep.ConfigEntries, s)
+	}
+	deep.ConfigEntries = sortTypeStats(deep.ConfigEntries)
+
+	sort.SliceStable(deep.Oversized, func(i, j int) bool {
+		return deep.Oversized[i].Size > deep.Oversized[j].Size
+	})
+
+	return deep
+}
+
 // sortTypeStats sorts the stat slice by size and then
 // alphabetically in the case the size is identical
 func sortTypeStats(stats []typeStats) []typeStats {
@@ -271,7 +343,20 @@ func (c *cmd) enhance(file io.Reader) (SnapshotInfo, error) {
 		TotalSize:   0,
 		TotalSizeKV: 0,
 	}
-	cr := &countingReader{wrappedReader: file}
+	if c.deep {
+		info.Deep = &DeepInfo{
+			OversizedThreshold: c.oversizedThreshold,
+			configEntries:      make(map[string]typeStats),
+		}
+	}
+	// Count the sizes of the decompressed data of compressed snapshots.
+	state, release, err := fsm.DecompressSnapshot(file)
+	if err != nil {
+		return info, err
+	}
+	defer release()
+
+	cr := &countingReader{wrappedReader: state}
 	handler := func(header *fsm.SnapshotHeader, msg structs.MessageType, dec *codec.Decoder) error {
 		name := structs.MessageType.String(msg)
 		s := info.Stats[msg]
@@ -292,6 +377,7 @@ func (c *cmd) enhance(file io.Reader) (SnapshotInfo, error) {
 		info.Stats[msg] = s
 
 		c.kvEnhance(s.Name, val, size, &info)
+		deepEnhance(msg, val, size, info.Deep)
 
 		return nil
 	}
@@ -347,6 +433,85 @@ func (c *cmd) kvEnhance(keyType string, val interface{}, size int, info *Snapsho
 	}
 }
 
+// deepEnhance populates the deep stats with a single record of the snapshot.
+func deepEnhance(msg structs.MessageType, val interface{}, size int, deep *DeepInfo) {
+	if deep == nil {
+		return
+	}
+
+	name := recordName(val)
+	switch msg {
+	case structs.ConfigEntryRequestType:
+		kind, entryName, sources := configEntryInfo(val)
+		if kind == "" {
+			kind = "unknown"
+		}
+		s := deep.configEntries[kind]
+		if s.Name == "" {
+			s.Name = kind
+		}
+		s.Sum += size
+		s.Count++
+		deep.configEntries[kind] = s
+
+		deep.Intentions += sources
+		name = kind + "/" + entryName
+	case structs.IntentionRequestType:
+		deep.Intentions++
+	case structs.ACLTokenSetRequestType:
+		deep.ACLTokens++
+	}
+
+	if size > deep.OversizedThreshold {
+		deep.Oversized = append(deep.Oversized, recordStats{
+			Type: msg.String(),
+			Name: name,
+			Size: size,
+		})
+	}
+}
+
+// recordName returns the field that best identifies a decoded record, such
+// as the key of a KV entry or the name of a node.
+func recordName(val interface{}) string {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, field := range []string{"Key", "Node", "Name", "AccessorID", "ID"} {
+		if v, ok := m[field].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// configEntryInfo returns the kind and name of a config entry record, along
+// with its number of intention sources. The config entries are stored with
+// the custom binary encoding of structs.ConfigEntryRequest.
+func configEntryInfo(val interface{}) (string, string, int) {
+	var raw []byte
+	switch v := val.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return "", "", 0
+	}
+
+	var req structs.ConfigEntryRequest
+	if err := req.UnmarshalBinary(raw); err != nil || req.Entry == nil {
+		return "", "", 0
+	}
+
+	var sources int
+	if entry, ok := req.Entry.(*structs.ServiceIntentionsConfigEntry); ok {
+		sources = len(entry.Sources)
+	}
+	return req.Entry.GetKind(), req.Entry.GetName(), sources
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -364,6 +529,11 @@ Usage: consul snapshot inspect [options] FILE
   To inspect the file "backup.snap":
 
     $ consul snapshot inspect backup.snap
-  
+
+  To break the contents of the file down by type and list the records
+  larger than 1MB:
+
+    $ consul snapshot inspect -deep -oversized-threshold 1048576 backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
@@ -9,6 +9,8 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
 )
 
 // update allows golden files to be updated based on the current output.
@@ -184,3 +186,64 @@ func TestSnapshotInspectInvalidFile(t *testing.T) {
 		t.Fatalf("should return an error code")
 	}
 }
+
+func TestSnapshotInspectDeepEnhance(t *testing.T) {
+	deep := &DeepInfo{
+		OversizedThreshold: 100,
+		configEntries:      make(map[string]typeStats),
+	}
+
+	encode := func(t *testing.T, entry structs.ConfigEntry) string {
+		req := &structs.ConfigEntryRequest{Entry: entry}
+		raw, err := req.MarshalBinary()
+		require.NoError(t, err)
+		return string(raw)
+	}
+
+	intentions := encode(t, &structs.ServiceIntentionsConfigEntry{
+		Kind: structs.ServiceIntentions,
+		Name: "web",
+		Sources: []*structs.SourceIntention{
+			{Name: "api", Action: structs.IntentionActionAllow},
+			{Name: "db", Action: structs.IntentionActionDeny},
+		},
+	})
+	defaults := encode(t, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "web",
+	})
+
+	deepEnhance(structs.ConfigEntryRequestType, intentions, 50, deep)
+	deepEnhance(structs.ConfigEntryRequestType, defaults, 20, deep)
+	deepEnhance(structs.ConfigEntryRequestType, defaults, 200, deep)
+	deepEnhance(structs.IntentionRequestType, map[string]interface{}{"ID": "legacy"}, 10, deep)
+	deepEnhance(structs.ACLTokenSetRequestType, map[string]interface{}{"AccessorID": "token"}, 10, deep)
+	deepEnhance(structs.KVSRequestType, map[string]interface{}{"Key": "big/key"}, 1000, deep)
+
+	out := generateDeepStats(SnapshotInfo{Deep: deep})
+	require.Equal(t, []typeStats{
+		{Name: structs.ServiceDefaults, Sum: 220, Count: 2},
+		{Name: structs.ServiceIntentions, Sum: 50, Count: 1},
+	}, out.ConfigEntries)
+	require.Equal(t, 3, out.Intentions)
+	require.Equal(t, 1, out.ACLTokens)
+	require.Equal(t, []recordStats{
+		{Type: "KVS", Name: "big/key", Size: 1000},
+		{Type: "ConfigEntry", Name: "service-defaults/web", Size: 200},
+	}, out.Oversized)
+}
+
+func TestSnapshotInspectDeepCommand(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{"-deep", "-oversized-threshold", "1024", "./testdata/backup.snap"}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Config Entry Kind")
+	require.Contains(t, out, "ACL Tokens")
+	require.Contains(t, out, "Oversized Record")
+	require.Contains(t, out, "Threshold")
+}
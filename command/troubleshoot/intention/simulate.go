@@ -0,0 +1,390 @@
+package intention
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// request is the simulated request from a source service to a destination.
+type request struct {
+	// SourcePartition, SourceNS and SourceName identify the source service.
+	SourcePartition string
+	SourceNS        string
+	SourceName      string
+
+	// Method, Path, Query and Header describe the HTTP request. They are
+	// only used with L7 intentions and routes.
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// decision is the outcome of the intentions for a request.
+type decision struct {
+	Allowed bool
+
+	// Intention is the intention that matched the source, or nil if the
+	// default intention policy applies.
+	Intention *api.Intention
+
+	// Permission is the index of the L7 permission of the intention that
+	// matched the request, or -1 if none did.
+	Permission int
+
+	// Reason describes how the decision was reached.
+	Reason string
+}
+
+// decide evaluates the intentions of the destination, sorted by precedence,
+// for the request. Like Envoy, the first intention matching the source
+// applies: its action is used for L4 intentions and its first permission
+// matching the request for L7 ones, and the default intention policy is used
+// if nothing matches. defaultAllow is nil if the default policy is unknown.
+func decide(ixns []*api.Intention, req *request, defaultAllow *bool) (*decision, error) {
+	d := &decision{Permission: -1}
+	for _, ixn := range ixns {
+		if !sourceMatches(ixn, req) {
+			continue
+		}
+		d.Intention = ixn
+
+		if len(ixn.Permissions) == 0 {
+			d.Allowed = ixn.Action == api.IntentionActionAllow
+			d.Reason = fmt.Sprintf("Matched L4 intention %s", ixn.String())
+			return d, nil
+		}
+
+		for i, perm := range ixn.Permissions {
+			ok, err := httpPermissionMatches(perm.HTTP, req)
+			if err != nil {
+				return nil, fmt.Errorf("invalid permission %d of intention %s: %w", i+1, ixn.String(), err)
+			}
+			if ok {
+				d.Allowed = perm.Action == api.IntentionActionAllow
+				d.Permission = i
+				d.Reason = fmt.Sprintf("Matched permission %d of L7 intention %s", i+1, ixn.String())
+				return d, nil
+			}
+		}
+		d.Reason = fmt.Sprintf("Matched L7 intention %s, but none of its permissions matched the request", ixn.String())
+		break
+	}
+
+	if d.Intention == nil {
+		d.Reason = "No intention matched the source"
+	}
+	if defaultAllow == nil {
+		return d, fmt.Errorf("%s and the default intention policy could not be determined", d.Reason)
+	}
+	d.Allowed = *defaultAllow
+	d.Reason += ", so the default intention policy applies"
+	return d, nil
+}
+
+// sourceMatches returns whether the source of the intention matches the
+// source service of the request. Intentions with a peer or sameness group
+// source never match, since the simulated source is local.
+func sourceMatches(ixn *api.Intention, req *request) bool {
+	if ixn.SourceType != "" && ixn.SourceType != api.IntentionSourceConsul {
+		return false
+	}
+	if ixn.SourcePeer != "" {
+		return false
+	}
+	if defaultName(ixn.SourcePartition) != defaultName(req.SourcePartition) {
+		return false
+	}
+	if ixn.SourceNS != "*" && defaultName(ixn.SourceNS) != defaultName(req.SourceNS) {
+		return false
+	}
+	return ixn.SourceName == "*" || ixn.SourceName == req.SourceName
+}
+
+func defaultName(name string) string {
+	if name == "" {
+		return api.IntentionDefaultNamespace
+	}
+	return name
+}
+
+// httpPermissionMatches returns whether the request matches the HTTP
+// attributes of an L7 permission.
+func httpPermissionMatches(perm *api.IntentionHTTPPermission, req *request) (bool, error) {
+	if perm == nil {
+		return true, nil
+	}
+	ok, err := pathMatches(perm.PathExact, perm.PathPrefix, perm.PathRegex, req.Path)
+	if err != nil || !ok {
+		return false, err
+	}
+	for _, h := range perm.Header {
+		ok, err := headerMatches(req.Header, headerMatch(h))
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return methodMatches(perm.Methods, req.Method), nil
+}
+
+// routeMatches returns whether the request matches a route of a
+// service-router.
+func routeMatches(match *api.ServiceRouteMatch, req *request) (bool, error) {
+	if match == nil || match.HTTP == nil {
+		return true, nil
+	}
+	m := match.HTTP
+	ok, err := pathMatches(m.PathExact, m.PathPrefix, m.PathRegex, req.Path)
+	if err != nil || !ok {
+		return false, err
+	}
+	for _, h := range m.Header {
+		ok, err := headerMatches(req.Header, headerMatch{
+			Name:    h.Name,
+			Present: h.Present,
+			Exact:   h.Exact,
+			Prefix:  h.Prefix,
+			Suffix:  h.Suffix,
+			Regex:   h.Regex,
+			Invert:  h.Invert,
+		})
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	for _, q := range m.QueryParam {
+		ok, err := queryParamMatches(req.Query, q)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return methodMatches(m.Methods, req.Method), nil
+}
+
+func pathMatches(exact, prefix, regex, path string) (bool, error) {
+	switch {
+	case exact != "":
+		return path == exact, nil
+	case prefix != "":
+		return strings.HasPrefix(path, prefix), nil
+	case regex != "":
+		return fullMatch(regex, path)
+	}
+	return true, nil
+}
+
+func methodMatches(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerMatch is the header match of either an L7 permission or a route,
+// which have the same semantics.
+type headerMatch api.IntentionHTTPHeaderPermission
+
+// headerMatches follows the semantics of Envoy header matchers: a missing
+// header never matches, unless the match is an inverted presence match.
+func headerMatches(header http.Header, h headerMatch) (bool, error) {
+	values, present := header[http.CanonicalHeaderKey(h.Name)]
+	if !present {
+		return h.Invert && h.Exact == "" && h.Prefix == "" && h.Suffix == "" && h.Regex == "", nil
+	}
+	value := strings.Join(values, ",")
+
+	var (
+		matched bool
+		err     error
+	)
+	switch {
+	case h.Exact != "":
+		matched = value == h.Exact
+	case h.Prefix != "":
+		matched = strings.HasPrefix(value, h.Prefix)
+	case h.Suffix != "":
+		matched = strings.HasSuffix(value, h.Suffix)
+	case h.Regex != "":
+		matched, err = fullMatch(h.Regex, value)
+	default:
+		matched = true
+	}
+	return matched != h.Invert, err
+}
+
+func queryParamMatches(query url.Values, q api.ServiceRouteHTTPMatchQueryParam) (bool, error) {
+	values, present := query[q.Name]
+	if !present {
+		return false, nil
+	}
+	switch {
+	case q.Exact != "":
+		return values[0] == q.Exact, nil
+	case q.Regex != "":
+		return fullMatch(q.Regex, values[0])
+	}
+	return true, nil
+}
+
+// fullMatch returns whether the regular expression matches the whole value,
+// like the RE2 matchers of Envoy.
+func fullMatch(expr, value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(value), nil
+}
+
+// route is the path of a request through a compiled discovery chain.
+type route struct {
+	// Router is the name of the router node, or empty if the chain doesn't
+	// have a service-router.
+	Router string
+
+	// Route is the index of the route of the router that matched the
+	// request, and Definition its definition.
+	Route      int
+	Definition *api.ServiceRoute
+
+	// Default is whether the route is the catch-all route the servers add
+	// after the routes of the service-router.
+	Default bool
+
+	// Targets are the targets the request is sent to, sorted by weight.
+	Targets []routeTarget
+}
+
+// routeTarget is a target of a request with the share of the requests it
+// receives, in percent.
+type routeTarget struct {
+	Weight   float32
+	Resolver string
+	Target   *api.DiscoveryTarget
+	Failover []*api.DiscoveryTarget
+}
+
+// resolveRoute walks the compiled discovery chain from its start node for
+// the request, through the matching route of the router and the splits of
+// the splitters, down to the targets of the resolvers.
+func resolveRoute(chain *api.CompiledDiscoveryChain, req *request) (*route, error) {
+	r := &route{Route: -1}
+	if err := r.walk(chain, req, chain.StartNode, 100, 0); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Targets, func(i, j int) bool { return r.Targets[i].Weight > r.Targets[j].Weight })
+	return r, nil
+}
+
+// maxChainDepth bounds the walk of a discovery chain, which the servers
+// compile without cycles.
+const maxChainDepth = 32
+
+func (r *route) walk(chain *api.CompiledDiscoveryChain, req *request, name string, weight float32, depth int) error {
+	if depth > maxChainDepth {
+		return fmt.Errorf("discovery chain is too deep")
+	}
+	node, ok := chain.Nodes[name]
+	if !ok {
+		return fmt.Errorf("discovery chain node %q not found", name)
+	}
+
+	switch node.Type {
+	case api.DiscoveryGraphNodeTypeRouter:
+		for i, rt := range node.Routes {
+			var match *api.ServiceRouteMatch
+			if rt.Definition != nil {
+				match = rt.Definition.Match
+			}
+			ok, err := routeMatches(match, req)
+			if err != nil {
+				return fmt.Errorf("invalid route %d of router %q: %w", i+1, node.Name, err)
+			}
+			if ok {
+				r.Router = node.Name
+				r.Route = i
+				r.Definition = rt.Definition
+				r.Default = i == len(node.Routes)-1
+				return r.walk(chain, req, rt.NextNode, weight, depth+1)
+			}
+		}
+		return fmt.Errorf("no route of router %q matched the request", node.Name)
+
+	case api.DiscoveryGraphNodeTypeSplitter:
+		for _, split := range node.Splits {
+			if split.Weight == 0 {
+				continue
+			}
+			if err := r.walk(chain, req, split.NextNode, weight*split.Weight/100, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case api.DiscoveryGraphNodeTypeResolver:
+		if node.Resolver == nil {
+			return fmt.Errorf("discovery chain node %q has no resolver", name)
+		}
+		t := routeTarget{
+			Weight:   weight,
+			Resolver: node.Name,
+			Target:   chain.Targets[node.Resolver.Target],
+		}
+		if t.Target == nil {
+			return fmt.Errorf("discovery chain target %q not found", node.Resolver.Target)
+		}
+		if node.Resolver.Failover != nil {
+			for _, id := range node.Resolver.Failover.Targets {
+				if target, ok := chain.Targets[id]; ok {
+					t.Failover = append(t.Failover, target)
+				}
+			}
+		}
+		r.Targets = append(r.Targets, t)
+		return nil
+	}
+	return fmt.Errorf("discovery chain node %q has unknown type %q", name, node.Type)
+}
+
+// clusterName returns the name of the Envoy cluster of the target.
+func clusterName(target *api.DiscoveryTarget) string {
+	if target.Name != "" {
+		return target.Name
+	}
+	return target.SNI
+}
+
+// describeHTTPMatch returns a short description of the HTTP attributes of a
+// permission or route, such as "path prefix /admin, methods GET".
+func describeHTTPMatch(exact, prefix, regex string, headers int, methods []string) string {
+	var parts []string
+	switch {
+	case exact != "":
+		parts = append(parts, "path "+exact)
+	case prefix != "":
+		parts = append(parts, "path prefix "+prefix)
+	case regex != "":
+		parts = append(parts, "path regex "+regex)
+	}
+	if headers > 0 {
+		parts = append(parts, fmt.Sprintf("%d header matches", headers))
+	}
+	if len(methods) > 0 {
+		parts = append(parts, "methods "+strings.Join(methods, " "))
+	}
+	if len(parts) == 0 {
+		return "any request"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,266 @@
+package intention
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+// exitDenied is the exit code of the command when the intentions deny the
+// simulated request.
+const exitDenied = 2
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	source      string
+	destination string
+	method      string
+	path        string
+	headers     flags.AppendSliceValue
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.source, "source", "", "The source service of the "+
+		"request, in the format [[partition/]namespace/]name. Required.")
+	c.flags.StringVar(&c.destination, "destination", "", "The destination "+
+		"service of the request. Use -namespace and -partition to specify its "+
+		"namespace and partition. Required.")
+	c.flags.StringVar(&c.method, "method", http.MethodGet, "The HTTP method of "+
+		"the request.")
+	c.flags.StringVar(&c.path, "path", "/", "The HTTP path of the request, "+
+		"which may include a query string.")
+	c.flags.Var(&c.headers, "header", "An HTTP header of the request, in the "+
+		"format 'Name: value'. This flag may be specified multiple times.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.source == "" || c.destination == "" {
+		c.UI.Error("The -source and -destination flags are required")
+		return 1
+	}
+
+	req, err := c.request()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+	q := &api.QueryOptions{AllowStale: c.http.Stale()}
+
+	chain, _, err := client.DiscoveryChain().Get(c.destination, nil, q)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading the discovery chain of %q: %s", c.destination, err))
+		return 1
+	}
+
+	matches, _, err := client.Connect().IntentionMatch(&api.IntentionMatch{
+		By:    api.IntentionMatchDestination,
+		Names: []string{c.destination},
+	}, q)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading the intentions of %q: %s", c.destination, err))
+		return 1
+	}
+
+	defaultAllow, err := intentionDefaultAllow(client)
+	if err != nil {
+		c.UI.Warn(fmt.Sprintf("Failed to read the default intention policy: %s", err))
+	}
+
+	d, err := decide(matches[c.destination], req, defaultAllow)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error evaluating the intentions: %s", err))
+		return 1
+	}
+
+	protocol := chain.Chain.Protocol
+	c.UI.Output("==> Request")
+	c.UI.Output(fmt.Sprintf("    Source:      %s", c.source))
+	c.UI.Output(fmt.Sprintf("    Destination: %s (protocol %s)", c.destination, protocol))
+	if isHTTP(protocol) {
+		c.UI.Output(fmt.Sprintf("    Request:     %s %s", req.Method, c.path))
+	}
+
+	c.UI.Output("\n==> Intentions")
+	c.UI.Output("    " + d.Reason)
+	if d.Permission >= 0 {
+		perm := d.Intention.Permissions[d.Permission]
+		c.UI.Output(fmt.Sprintf("    Permission:  %s (%s)", perm.Action, describePermission(perm)))
+	}
+	if d.Allowed {
+		c.UI.Output("    Decision:    allowed")
+	} else {
+		c.UI.Output("    Decision:    denied")
+	}
+
+	c.UI.Output("\n==> Route")
+	r, err := resolveRoute(chain.Chain, req)
+	if err != nil {
+		c.UI.Output("    " + err.Error())
+	} else {
+		if r.Router != "" {
+			description := "default route"
+			if !r.Default {
+				description = describeRoute(r.Definition)
+			}
+			c.UI.Output(fmt.Sprintf("    Router:  %s, route %d (%s)", r.Router, r.Route+1, description))
+		}
+		for _, t := range r.Targets {
+			c.UI.Output(fmt.Sprintf("    Cluster: %g%% %s (resolver %s)", t.Weight, clusterName(t.Target), t.Resolver))
+			for _, failover := range t.Failover {
+				c.UI.Output(fmt.Sprintf("      Failover: %s", clusterName(failover)))
+			}
+		}
+	}
+
+	if !d.Allowed {
+		return exitDenied
+	}
+	return 0
+}
+
+// request returns the simulated request described by the flags.
+func (c *cmd) request() (*request, error) {
+	req := &request{
+		Method: strings.ToUpper(c.method),
+		Header: make(http.Header),
+	}
+
+	parts := strings.Split(c.source, "/")
+	switch len(parts) {
+	case 1:
+		req.SourceName = parts[0]
+	case 2:
+		req.SourceNS, req.SourceName = parts[0], parts[1]
+	case 3:
+		req.SourcePartition, req.SourceNS, req.SourceName = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("Invalid source %q, expected [[partition/]namespace/]name", c.source)
+	}
+
+	u, err := url.Parse(c.path)
+	if err != nil || !strings.HasPrefix(u.Path, "/") {
+		return nil, fmt.Errorf("Invalid path %q, expected an absolute path", c.path)
+	}
+	req.Path, req.Query = u.Path, u.Query()
+
+	for _, h := range c.headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("Invalid header %q, expected 'Name: value'", h)
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return req, nil
+}
+
+// intentionDefaultAllow returns whether requests that match no intention are
+// allowed, which is the case unless ACLs are enabled with a default deny
+// policy.
+func intentionDefaultAllow(client *api.Client) (*bool, error) {
+	self, err := client.Agent().Self()
+	if err != nil {
+		return nil, err
+	}
+	config := self["DebugConfig"]
+	enabled, ok := config["ACLsEnabled"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("agent configuration doesn't include ACLsEnabled")
+	}
+	allow := true
+	if enabled {
+		settings, _ := config["ACLResolverSettings"].(map[string]interface{})
+		policy, _ := settings["ACLDefaultPolicy"].(string)
+		allow = policy == "allow"
+	}
+	return &allow, nil
+}
+
+func isHTTP(protocol string) bool {
+	switch protocol {
+	case "http", "http2", "grpc":
+		return true
+	}
+	return false
+}
+
+func describePermission(perm *api.IntentionPermission) string {
+	if perm.HTTP == nil {
+		return "any request"
+	}
+	h := perm.HTTP
+	return describeHTTPMatch(h.PathExact, h.PathPrefix, h.PathRegex, len(h.Header), h.Methods)
+}
+
+func describeRoute(rt *api.ServiceRoute) string {
+	if rt == nil || rt.Match == nil || rt.Match.HTTP == nil {
+		return "any request"
+	}
+	h := rt.Match.HTTP
+	return describeHTTPMatch(h.PathExact, h.PathPrefix, h.PathRegex, len(h.Header)+len(h.QueryParam), h.Methods)
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Simulate a request against the intentions and discovery chain of a service"
+	help     = `
+Usage: consul troubleshoot intention -source <name> -destination <name> [options]
+
+  Simulates a request from a source service to a destination service, and
+  reports which intention, and which L7 permission of the intention, allows
+  or denies it, and which route and clusters of the destination's discovery
+  chain it is sent to.
+
+  The intentions are evaluated in order of precedence: the first intention
+  matching the source applies, and the default intention policy applies if
+  none does or if none of the permissions of its L7 intention match. The
+  default policy is read from the configuration of the agent.
+
+    $ consul troubleshoot intention -source web -destination api \
+        -method POST -path /admin -header "X-Role: ops"
+
+  The command exits with code 2 if the request is denied, which can be used
+  to check intentions in CI pipelines.
+`
+)
@@ -0,0 +1,305 @@
+package intention
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTroubleshootIntentionCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestTroubleshootIntentionCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no source": {
+			[]string{"-destination", "api"},
+			"The -source and -destination flags are required",
+		},
+		"invalid source": {
+			[]string{"-source", "a/b/c/d", "-destination", "api"},
+			"Invalid source",
+		},
+		"relative path": {
+			[]string{"-source", "web", "-destination", "api", "-path", "admin"},
+			"Invalid path",
+		},
+		"invalid header": {
+			[]string{"-source", "web", "-destination", "api", "-header", "X-Role"},
+			"Invalid header",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			require.Equal(t, 1, New(ui).Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestTroubleshootIntentionCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	entries := []api.ConfigEntry{
+		&api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     "api",
+			Protocol: "http",
+		},
+		&api.ServiceResolverConfigEntry{
+			Kind: api.ServiceResolver,
+			Name: "api",
+			Subsets: map[string]api.ServiceResolverSubset{
+				"v2": {Filter: "Service.Meta.version == v2"},
+			},
+		},
+		&api.ServiceRouterConfigEntry{
+			Kind: api.ServiceRouter,
+			Name: "api",
+			Routes: []api.ServiceRoute{
+				{
+					Match: &api.ServiceRouteMatch{
+						HTTP: &api.ServiceRouteHTTPMatch{PathPrefix: "/v2"},
+					},
+					Destination: &api.ServiceRouteDestination{ServiceSubset: "v2"},
+				},
+			},
+		},
+		&api.ServiceIntentionsConfigEntry{
+			Kind: api.ServiceIntentions,
+			Name: "api",
+			Sources: []*api.SourceIntention{
+				{
+					Name: "web",
+					Permissions: []*api.IntentionPermission{
+						{
+							Action: api.IntentionActionDeny,
+							HTTP:   &api.IntentionHTTPPermission{PathPrefix: "/v2/admin"},
+						},
+						{
+							Action: api.IntentionActionAllow,
+							HTTP:   &api.IntentionHTTPPermission{Methods: []string{"GET"}},
+						},
+					},
+				},
+				{
+					Name:   "*",
+					Action: api.IntentionActionDeny,
+				},
+			},
+		},
+	}
+	for _, entry := range entries {
+		_, _, err := client.ConfigEntries().Set(entry, nil)
+		require.NoError(t, err)
+	}
+
+	run := func(t *testing.T, args ...string) (int, string) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append([]string{"-http-addr=" + a.HTTPAddr()}, args...))
+		require.Empty(t, ui.ErrorWriter.String())
+		return code, ui.OutputWriter.String()
+	}
+
+	t.Run("allowed by permission", func(t *testing.T) {
+		code, output := run(t, "-source", "web", "-destination", "api", "-path", "/v2/users")
+		require.Equal(t, 0, code)
+		require.Contains(t, output, "Matched permission 2 of L7 intention web => api (2 permissions)")
+		require.Contains(t, output, "Permission:  allow (methods GET)")
+		require.Contains(t, output, "Decision:    allowed")
+		require.Contains(t, output, "Router:  api.default.default, route 1 (path prefix /v2)")
+		require.Contains(t, output, "Cluster: 100% v2.api.default.dc1")
+	})
+
+	t.Run("denied by permission", func(t *testing.T) {
+		code, output := run(t, "-source", "web", "-destination", "api", "-path", "/v2/admin")
+		require.Equal(t, exitDenied, code)
+		require.Contains(t, output, "Matched permission 1 of L7 intention web => api (2 permissions)")
+		require.Contains(t, output, "Decision:    denied")
+	})
+
+	t.Run("default policy", func(t *testing.T) {
+		code, output := run(t, "-source", "web", "-destination", "api", "-method", "POST", "-path", "/users")
+		require.Equal(t, 0, code)
+		require.Contains(t, output, "none of its permissions matched the request, so the default intention policy applies")
+		require.Contains(t, output, "Router:  api.default.default, route 2 (default route)")
+		require.Contains(t, output, "Cluster: 100% api.default.dc1")
+	})
+
+	t.Run("denied by wildcard", func(t *testing.T) {
+		code, output := run(t, "-source", "billing", "-destination", "api")
+		require.Equal(t, exitDenied, code)
+		require.Contains(t, output, "Matched L4 intention * => api (deny)")
+	})
+}
+
+func TestDecide(t *testing.T) {
+	allow := true
+	ixns := []*api.Intention{
+		{
+			SourceName: "web",
+			Permissions: []*api.IntentionPermission{
+				{
+					Action: api.IntentionActionAllow,
+					HTTP: &api.IntentionHTTPPermission{
+						PathRegex: "/api/v[0-9]+",
+						Header: []api.IntentionHTTPHeaderPermission{
+							{Name: "x-role", Exact: "admin"},
+							{Name: "x-debug", Present: true, Invert: true},
+						},
+					},
+				},
+			},
+		},
+		{SourceNS: "*", SourceName: "*", Action: api.IntentionActionDeny},
+	}
+
+	cases := map[string]struct {
+		req        *request
+		allowed    bool
+		intention  int
+		permission int
+	}{
+		"permission": {
+			req: &request{
+				SourceName: "web",
+				Path:       "/api/v1",
+				Header:     http.Header{"X-Role": []string{"admin"}},
+			},
+			allowed:    true,
+			intention:  0,
+			permission: 0,
+		},
+		"regex is anchored": {
+			req: &request{
+				SourceName: "web",
+				Path:       "/api/v1/users",
+				Header:     http.Header{"X-Role": []string{"admin"}},
+			},
+			allowed:    true,
+			intention:  0,
+			permission: -1,
+		},
+		"inverted header": {
+			req: &request{
+				SourceName: "web",
+				Path:       "/api/v1",
+				Header:     http.Header{"X-Role": []string{"admin"}, "X-Debug": []string{"1"}},
+			},
+			allowed:    true,
+			intention:  0,
+			permission: -1,
+		},
+		"wildcard": {
+			req:        &request{SourceNS: "other", SourceName: "db", Path: "/"},
+			allowed:    false,
+			intention:  1,
+			permission: -1,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			d, err := decide(ixns, tc.req, &allow)
+			require.NoError(t, err)
+			require.Equal(t, tc.allowed, d.Allowed)
+			require.Same(t, ixns[tc.intention], d.Intention)
+			require.Equal(t, tc.permission, d.Permission)
+		})
+	}
+
+	t.Run("unknown default", func(t *testing.T) {
+		_, err := decide(nil, &request{SourceName: "web"}, nil)
+		require.EqualError(t, err, "No intention matched the source and the default intention policy could not be determined")
+	})
+}
+
+func TestResolveRoute(t *testing.T) {
+	chain := &api.CompiledDiscoveryChain{
+		StartNode: "router:api",
+		Nodes: map[string]*api.DiscoveryGraphNode{
+			"router:api": {
+				Type: api.DiscoveryGraphNodeTypeRouter,
+				Name: "api",
+				Routes: []*api.DiscoveryRoute{
+					{
+						Definition: &api.ServiceRoute{
+							Match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+								QueryParam: []api.ServiceRouteHTTPMatchQueryParam{{Name: "canary", Exact: "1"}},
+							}},
+						},
+						NextNode: "splitter:api",
+					},
+					{NextNode: "resolver:api"},
+				},
+			},
+			"splitter:api": {
+				Type: api.DiscoveryGraphNodeTypeSplitter,
+				Name: "api",
+				Splits: []*api.DiscoverySplit{
+					{Weight: 10, NextNode: "resolver:v2"},
+					{Weight: 90, NextNode: "resolver:api"},
+				},
+			},
+			"resolver:api": {
+				Type:     api.DiscoveryGraphNodeTypeResolver,
+				Name:     "api",
+				Resolver: &api.DiscoveryResolver{Target: "api"},
+			},
+			"resolver:v2": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Name: "v2",
+				Resolver: &api.DiscoveryResolver{
+					Target:   "v2",
+					Failover: &api.DiscoveryFailover{Targets: []string{"api"}},
+				},
+			},
+		},
+		Targets: map[string]*api.DiscoveryTarget{
+			"api": {ID: "api", Name: "api.default.dc1"},
+			"v2":  {ID: "v2", SNI: "v2.api.default.dc1"},
+		},
+	}
+
+	r, err := resolveRoute(chain, &request{Path: "/", Query: map[string][]string{"canary": {"1"}}})
+	require.NoError(t, err)
+	require.Equal(t, 0, r.Route)
+	require.Len(t, r.Targets, 2)
+	require.Equal(t, float32(90), r.Targets[0].Weight)
+	require.Equal(t, "api.default.dc1", clusterName(r.Targets[0].Target))
+	require.Equal(t, float32(10), r.Targets[1].Weight)
+	require.Equal(t, "v2.api.default.dc1", clusterName(r.Targets[1].Target))
+	require.Len(t, r.Targets[1].Failover, 1)
+
+	r, err = resolveRoute(chain, &request{Path: "/"})
+	require.NoError(t, err)
+	require.Equal(t, 1, r.Route)
+	require.Len(t, r.Targets, 1)
+	require.Equal(t, float32(100), r.Targets[0].Weight)
+}
@@ -40,5 +40,9 @@ Usage: consul troubleshoot <subcommand> [options]
 
     $ consul troubleshoot proxy -upstream [options]
 
+  Troubleshoot Intentions
+
+    $ consul troubleshoot intention -source web -destination api -path /admin
+
   For more examples, ask for subcommand help or view the documentation.
 `
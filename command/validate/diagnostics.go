@@ -0,0 +1,225 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// result is the result of the validation of the agent configuration, a
+// service definition file or a config entry file.
+type result struct {
+	// kind describes what was validated in the text output.
+	kind string
+
+	// file is the file validated. It is empty for the agent configuration,
+	// which can span several files and directories.
+	file string
+
+	warnings []string
+	err      error
+}
+
+// diagnostic is a problem found during validation, as reported in the JSON
+// output.
+type diagnostic struct {
+	File       string `json:",omitempty"`
+	Path       string `json:",omitempty"`
+	Severity   string
+	Message    string
+	Suggestion string `json:",omitempty"`
+}
+
+// output is the JSON output of the command.
+type output struct {
+	Valid       bool
+	Diagnostics []diagnostic
+}
+
+func (c *cmd) validateAgentConfig(files []string) *result {
+	r := &result{kind: "Config"}
+	loaded, err := config.Load(config.LoadOpts{
+		ConfigFiles:   files,
+		ConfigFormat:  c.configFormat,
+		Interpolation: c.interpolation,
+	})
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.warnings = loaded.Warnings
+	return r
+}
+
+func (c *cmd) validateServiceFile(file string) *result {
+	r := &result{kind: "Service definition", file: file}
+
+	// Service definition files aren't complete agent configurations, so they
+	// are loaded on top of the dev mode defaults.
+	devMode := true
+	loaded, err := config.Load(config.LoadOpts{
+		ConfigFiles:   []string{file},
+		ConfigFormat:  c.configFormat,
+		DevMode:       &devMode,
+		Interpolation: c.interpolation,
+	})
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.warnings = loaded.Warnings
+	if len(loaded.RuntimeConfig.Services) == 0 {
+		r.err = errNoService
+	}
+	return r
+}
+
+func (c *cmd) validateConfigEntryFile(file string) *result {
+	r := &result{kind: "Config entry", file: file}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	parsed, err := helpers.ParseConfigEntry(string(data))
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	// The entry is validated as the servers would when it is written, which
+	// catches invalid values as well as invalid keys.
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(encoded, &raw); err != nil {
+		r.err = err
+		return r
+	}
+	entry, err := structs.DecodeConfigEntry(raw)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	if err := entry.Normalize(); err != nil {
+		r.err = err
+		return r
+	}
+	r.err = entry.Validate()
+	return r
+}
+
+var errNoService = errors.New("no service definition found")
+
+var (
+	// parseErrorRe matches the errors of the agent configuration returned
+	// when a file can't be parsed.
+	parseErrorRe = regexp.MustCompile(`(?s)^failed to parse (\S+): (.*)$`)
+
+	// invalidKeyRe matches the errors of unknown keys.
+	invalidKeyRe = regexp.MustCompile(`^invalid config key "?([^"\s]+)"?$`)
+
+	// keyPathRe matches the errors prefixed with the path of the invalid
+	// field, such as "limits.request_limits.mode: invalid mode".
+	keyPathRe = regexp.MustCompile(`^([A-Za-z0-9_-]+(?:\[\d*\])?(?:\.[A-Za-z0-9_-]+(?:\[\d*\])?)*): `)
+)
+
+// diagnostics returns the diagnostics of the result, with one diagnostic for
+// each of the errors and warnings.
+func (r *result) diagnostics() []diagnostic {
+	var diags []diagnostic
+	for _, w := range r.warnings {
+		diags = append(diags, newDiagnostic(r.file, severityWarning, w))
+	}
+	for _, e := range splitErrors(r.err) {
+		file := r.file
+		if m := parseErrorRe.FindStringSubmatch(e); m != nil {
+			file, e = m[1], m[2]
+		}
+		for _, message := range splitErrorList(e) {
+			diags = append(diags, newDiagnostic(file, severityError, message))
+		}
+	}
+	return diags
+}
+
+func newDiagnostic(file, severity, message string) diagnostic {
+	d := diagnostic{
+		File:     file,
+		Severity: severity,
+		Message:  message,
+	}
+	if m := invalidKeyRe.FindStringSubmatch(message); m != nil {
+		d.Path = m[1]
+	} else if m := keyPathRe.FindStringSubmatch(message); m != nil && strings.ContainsAny(m[1], "._[") {
+		d.Path = m[1]
+	}
+
+	switch {
+	case invalidKeyRe.MatchString(message):
+		d.Suggestion = "Remove the key, or fix its name if it is misspelled."
+	case strings.Contains(message, "invalid duration"):
+		d.Suggestion = `Use a duration with a unit, such as "10s" or "5m".`
+	case strings.Contains(message, "kind/Kind key"):
+		d.Suggestion = "Add the Kind of the config entry, such as \"service-defaults\"."
+	case message == errNoService.Error():
+		d.Suggestion = "Define the service in a service block, or validate the file as agent configuration."
+	}
+	return d
+}
+
+// splitErrors returns the messages of the errors wrapped by err.
+func splitErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		var messages []string
+		for _, e := range merr.Errors {
+			messages = append(messages, splitErrors(e)...)
+		}
+		return messages
+	}
+	return []string{err.Error()}
+}
+
+// splitErrorList splits the message of a multierror.Error which was formatted
+// into a string.
+func splitErrorList(message string) []string {
+	if !strings.Contains(message, "\n\t* ") {
+		return []string{message}
+	}
+	var messages []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "\t* ") {
+			messages = append(messages, strings.TrimPrefix(line, "\t* "))
+		}
+	}
+	return messages
+}
+
+func (r *result) String() string {
+	if r.file == "" {
+		return fmt.Sprintf("%s validation failed: %v", r.kind, r.err)
+	}
+	return fmt.Sprintf("%s validation failed for %s: %v", r.kind, r.file, r.err)
+}
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 
@@ -23,7 +24,13 @@ type cmd struct {
 	// format independent of their extension.
 	configFormat string
 	quiet        bool
+	output       string
 	help         string
+
+	serviceFiles     []string
+	configEntryFiles []string
+
+	interpolation config.Interpolation
 }
 
 func (c *cmd) init() {
@@ -32,6 +39,20 @@ func (c *cmd) init() {
 		"Config files are in this format irrespective of their extension. Must be 'hcl' or 'json'")
 	c.flags.BoolVar(&c.quiet, "quiet", false,
 		"When given, a successful run will produce no output.")
+	c.flags.StringVar(&c.output, "output", "text",
+		"Output format, either 'text' or 'json'. The JSON output reports each "+
+			"error and warning with its file, path, severity and suggested fix.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.interpolation.EnvVars), "config-interpolate-env",
+		"Name of an environment variable that config files may reference with "+
+			"${env:NAME}. This flag may be specified multiple times.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.interpolation.FilePaths), "config-interpolate-file",
+		"Absolute path of a file, or of a directory of files, that config files "+
+			"may reference with ${file:PATH}. This flag may be specified multiple times.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.serviceFiles), "service-file",
+		"Service definition file to validate. This flag may be specified multiple times.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.configEntryFiles), "config-entry-file",
+		"Config entry file to validate, in HCL or JSON. This flag may be "+
+			"specified multiple times.")
 	c.help = flags.Usage(help, c.flags)
 }
 
@@ -42,7 +63,7 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	configFiles := c.flags.Args()
-	if len(configFiles) < 1 {
+	if len(configFiles) < 1 && len(c.serviceFiles) < 1 && len(c.configEntryFiles) < 1 {
 		c.UI.Error("Must specify at least one config file or directory")
 		return 1
 	}
@@ -52,16 +73,55 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	result, err := config.Load(config.LoadOpts{ConfigFiles: configFiles, ConfigFormat: c.configFormat})
-	if err != nil {
-		c.UI.Error(fmt.Sprintf("Config validation failed: %v", err.Error()))
+	if c.output != "text" && c.output != "json" {
+		c.UI.Error("-output must be either 'text' or 'json'")
 		return 1
 	}
-	if !c.quiet {
-		for _, w := range result.Warnings {
-			c.UI.Warn(w)
+
+	var results []*result
+	if len(configFiles) > 0 {
+		results = append(results, c.validateAgentConfig(configFiles))
+	}
+	for _, file := range c.serviceFiles {
+		results = append(results, c.validateServiceFile(file))
+	}
+	for _, file := range c.configEntryFiles {
+		results = append(results, c.validateConfigEntryFile(file))
+	}
+
+	out := output{Valid: true, Diagnostics: []diagnostic{}}
+	for _, r := range results {
+		if r.err != nil {
+			out.Valid = false
+		}
+		out.Diagnostics = append(out.Diagnostics, r.diagnostics()...)
+	}
+
+	if c.output == "json" {
+		b, err := json.MarshalIndent(out, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding output: %s", err))
+			return 1
+		}
+		c.UI.Output(string(b))
+	} else {
+		for _, r := range results {
+			if r.err != nil {
+				c.UI.Error(r.String())
+			}
+		}
+		if out.Valid && !c.quiet {
+			for _, r := range results {
+				for _, w := range r.warnings {
+					c.UI.Warn(w)
+				}
+			}
+			c.UI.Output("Configuration is valid!")
 		}
-		c.UI.Output("Configuration is valid!")
+	}
+
+	if !out.Valid {
+		return 1
 	}
 	return 0
 }
@@ -76,7 +136,7 @@ func (c *cmd) Help() string {
 
 const synopsis = "Validate config files/directories"
 const help = `
-Usage: consul validate [options] FILE_OR_DIRECTORY...
+Usage: consul validate [options] [FILE_OR_DIRECTORY...]
 
   Performs a thorough sanity test on Consul configuration files. For each file
   or directory given, the validate command will attempt to parse the contents
@@ -88,5 +148,14 @@ Usage: consul validate [options] FILE_OR_DIRECTORY...
   to be loaded by the agent. This command cannot operate on partial
   configuration fragments since those won't pass the full agent validation.
 
+  Service definition files and config entry files can be validated as well,
+  with the -service-file and -config-entry-file flags. Config entries are
+  validated as the servers would when they are written.
+
+      $ consul validate -service-file web.hcl -config-entry-file web-defaults.hcl
+
+  With -output=json, the problems are reported as JSON diagnostics for use in
+  CI pipelines.
+
   Returns 0 if the configuration is valid, or 1 if there are problems.
 `
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -134,3 +135,104 @@ func TestValidateCommand_Quiet(t *testing.T) {
 	require.Equalf(t, 0, code, "return code - expected: 0, bad: %d, %s", code, ui.ErrorWriter.String())
 	require.Equal(t, "", ui.OutputWriter.String())
 }
+
+func TestValidateCommand_JSONOutput(t *testing.T) {
+	t.Parallel()
+	td := testutil.TempDir(t, "consul")
+
+	fp := filepath.Join(td, "config.hcl")
+	err := os.WriteFile(fp, []byte("bind_addr = \"10.0.0.1\"\ndata_dir = \""+td+"\"\nbogus_key = 1"), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	args := []string{"-output=json", fp}
+
+	code := cmd.Run(args)
+	require.Equal(t, 1, code)
+
+	var out output
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.False(t, out.Valid)
+	require.Equal(t, []diagnostic{{
+		File:       fp,
+		Path:       "bogus_key",
+		Severity:   severityError,
+		Message:    "invalid config key bogus_key",
+		Suggestion: "Remove the key, or fix its name if it is misspelled.",
+	}}, out.Diagnostics)
+}
+
+func TestValidateCommand_ServiceFile(t *testing.T) {
+	t.Parallel()
+	td := testutil.TempDir(t, "consul")
+
+	valid := filepath.Join(td, "web.hcl")
+	err := os.WriteFile(valid, []byte(`service { name = "web" port = 8080 }`), 0644)
+	require.NoError(t, err)
+
+	empty := filepath.Join(td, "empty.hcl")
+	err = os.WriteFile(empty, []byte(`node_name = "foo"`), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-service-file", valid})
+	require.Equalf(t, 0, code, ui.ErrorWriter.String())
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{"-service-file", valid, "-service-file", empty})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Service definition validation failed for "+empty+": no service definition found")
+}
+
+func TestValidateCommand_ConfigEntryFile(t *testing.T) {
+	t.Parallel()
+	td := testutil.TempDir(t, "consul")
+
+	valid := filepath.Join(td, "web-defaults.hcl")
+	err := os.WriteFile(valid, []byte(`Kind = "service-defaults"
+Name = "web"
+Protocol = "http"`), 0644)
+	require.NoError(t, err)
+
+	invalid := filepath.Join(td, "wildcard-defaults.json")
+	err = os.WriteFile(invalid, []byte(`{"Kind": "service-defaults", "Name": "*"}`), 0644)
+	require.NoError(t, err)
+
+	unknownKey := filepath.Join(td, "unknown-key.hcl")
+	err = os.WriteFile(unknownKey, []byte(`Kind = "service-defaults"
+Name = "web"
+Protocl = "http"`), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-config-entry-file", valid})
+	require.Equalf(t, 0, code, ui.ErrorWriter.String())
+
+	ui = cli.NewMockUi()
+	args := []string{"-output=json", "-config-entry-file", valid, "-config-entry-file", invalid, "-config-entry-file", unknownKey}
+	code = New(ui).Run(args)
+	require.Equal(t, 1, code)
+
+	var out output
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.False(t, out.Valid)
+	require.Len(t, out.Diagnostics, 2)
+
+	require.Equal(t, invalid, out.Diagnostics[0].File)
+	require.Equal(t, severityError, out.Diagnostics[0].Severity)
+	require.Contains(t, out.Diagnostics[0].Message, "not a wildcard")
+
+	require.Equal(t, unknownKey, out.Diagnostics[1].File)
+	require.Equal(t, "Protocl", out.Diagnostics[1].Path)
+	require.NotEmpty(t, out.Diagnostics[1].Suggestion)
+}
+
+func TestValidateCommand_InvalidOutput(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-output=yaml", "config.hcl"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-output must be either 'text' or 'json'")
+}
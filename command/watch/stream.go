@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// formatJSONStream is the output format emitting one JSON event per line
+	// for each change, rather than running a handler.
+	formatJSONStream = "json-stream"
+)
+
+// streamEvent is emitted on a single line for each change in the
+// json-stream format.
+type streamEvent struct {
+	Index     uint64
+	Timestamp time.Time
+	Type      string
+
+	// Changes hints at the elements of the data view which changed since
+	// the previous event. It is nil when the elements of the data view
+	// can't be told apart, such as for the key type.
+	Changes *streamChanges `json:",omitempty"`
+
+	Data interface{}
+}
+
+// streamChanges are the identifiers of the elements added, removed and
+// modified since the previous event. Every element is added in the first
+// event.
+type streamChanges struct {
+	Added    []string `json:",omitempty"`
+	Removed  []string `json:",omitempty"`
+	Modified []string `json:",omitempty"`
+}
+
+// streamState tracks the data of the previous event to compute the changes.
+type streamState struct {
+	watchType string
+	previous  map[string]interface{}
+}
+
+func (s *streamState) event(idx uint64, data interface{}) *streamEvent {
+	event := &streamEvent{
+		Index:     idx,
+		Timestamp: time.Now().UTC(),
+		Type:      s.watchType,
+		Data:      data,
+	}
+
+	elements, ok := streamElements(data)
+	if !ok {
+		return event
+	}
+
+	changes := &streamChanges{}
+	for id, element := range elements {
+		previous, ok := s.previous[id]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, id)
+		case !reflect.DeepEqual(previous, element):
+			changes.Modified = append(changes.Modified, id)
+		}
+	}
+	for id := range s.previous {
+		if _, ok := elements[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Modified)
+
+	s.previous = elements
+	event.Changes = changes
+	return event
+}
+
+// streamElements returns the elements of the data of a watch by identifier,
+// or false if the data isn't made of separate elements.
+func streamElements(data interface{}) (map[string]interface{}, bool) {
+	elements := make(map[string]interface{})
+	switch data := data.(type) {
+	case api.KVPairs:
+		for _, pair := range data {
+			elements[pair.Key] = pair.ModifyIndex
+		}
+	case map[string][]string:
+		for service, tags := range data {
+			elements[service] = tags
+		}
+	case []*api.Node:
+		for _, node := range data {
+			elements[node.Node] = node.ModifyIndex
+		}
+	case []*api.ServiceEntry:
+		for _, entry := range data {
+			elements[entry.Node.Node+"/"+entry.Service.ID] = entry
+		}
+	case []*api.HealthCheck:
+		for _, check := range data {
+			elements[check.Node+"/"+check.CheckID] = check.Status
+		}
+	case []*api.UserEvent:
+		for _, event := range data {
+			elements[event.ID] = event.LTime
+		}
+	case []api.ConfigEntry:
+		for _, entry := range data {
+			elements[entry.GetKind()+"/"+entry.GetName()] = entry.GetModifyIndex()
+		}
+	case []*api.Peering:
+		for _, peering := range data {
+			elements[peering.Name] = peering.ModifyIndex
+		}
+	default:
+		return nil, false
+	}
+	return elements, true
+}
@@ -41,21 +41,23 @@ type cmd struct {
 	passingOnly string
 	state       string
 	name        string
+	kind        string
 	shell       bool
+	format      string
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.watchType, "type", "",
 		"Specifies the watch type. One of key, keyprefix, services, nodes, "+
-			"service, checks, or event.")
+			"service, checks, event, config_entry, service_intentions or peering.")
 	c.flags.StringVar(&c.key, "key", "",
 		"Specifies the key to watch. Only for 'key' type.")
 	c.flags.StringVar(&c.prefix, "prefix", "",
 		"Specifies the key prefix to watch. Only for 'keyprefix' type.")
 	c.flags.StringVar(&c.service, "service", "",
 		"Specifies the service to watch. Required for 'service' type, "+
-			"optional for 'checks' type.")
+			"optional for 'checks' and 'service_intentions' types.")
 	c.flags.Var((*flags.AppendSliceValue)(&c.tag), "tag", "Specifies the service tag(s) to filter on. "+
 		"Optional for 'service' type. May be specified multiple times")
 	c.flags.StringVar(&c.passingOnly, "passingonly", "",
@@ -67,7 +69,14 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.state, "state", "",
 		"Specifies the states to watch. Optional for 'checks' type.")
 	c.flags.StringVar(&c.name, "name", "",
-		"Specifies an event name to watch. Only for 'event' type.")
+		"Specifies an event name to watch for 'event' type, a config entry "+
+			"name for 'config_entry' type, or a peering name for 'peering' type.")
+	c.flags.StringVar(&c.kind, "kind", "",
+		"Specifies the config entry kind to watch. Only for 'config_entry' type.")
+	c.flags.StringVar(&c.format, "format", "",
+		"Output format. When set to 'json-stream', the watch keeps running and "+
+			"emits one JSON event per line on each change, rather than invoking "+
+			"a child process.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -99,6 +108,18 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	switch c.format {
+	case "":
+	case formatJSONStream:
+		if len(c.flags.Args()) > 0 {
+			c.UI.Error(fmt.Sprintf("A child process can't be specified with -format=%s", formatJSONStream))
+			return 1
+		}
+	default:
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be %q", c.format, formatJSONStream))
+		return 1
+	}
+
 	token, err := c.loadToken()
 	if err != nil {
 		c.UI.Error(err.Error())
@@ -137,6 +158,9 @@ func (c *cmd) Run(args []string) int {
 	if c.name != "" {
 		params["name"] = c.name
 	}
+	if c.kind != "" {
+		params["kind"] = c.kind
+	}
 	if c.passingOnly != "" {
 		b, err := strconv.ParseBool(c.passingOnly)
 		if err != nil {
@@ -182,7 +206,19 @@ func (c *cmd) Run(args []string) int {
 	//	0: false
 	//	1: true
 	errExit := 0
-	if len(c.flags.Args()) == 0 {
+	if c.format == formatJSONStream {
+		state := &streamState{watchType: wp.Type}
+		wp.Handler = func(idx uint64, data interface{}) {
+			buf, err := json.Marshal(state.event(idx, data))
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error encoding output: %s", err))
+				wp.Stop()
+				errExit = 1
+				return
+			}
+			c.UI.Output(string(buf))
+		}
+	} else if len(c.flags.Args()) == 0 {
 		wp.Handler = func(idx uint64, data interface{}) {
 			defer wp.Stop()
 			buf, err := json.MarshalIndent(data, "", "    ")
@@ -247,11 +283,14 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
-	// Watch for a shutdown
+	// Watch for a shutdown. No child process runs in the json-stream
+	// format, so the watch is stopped to return once the output is flushed.
 	go func() {
 		<-c.shutdownCh
 		wp.Stop()
-		os.Exit(0)
+		if c.format != formatJSONStream {
+			os.Exit(0)
+		}
 	}()
 
 	// Run the watch
@@ -281,4 +320,10 @@ Usage: consul watch [options] [child...]
 
   Providing the watch type is required, and other parameters may be required
   or supported depending on the watch type.
+
+  To process the changes in a pipeline, the watch can instead keep running and
+  emit one JSON event per line on each change, with the index, the time and
+  the elements added, removed or modified since the previous event:
+
+      $ consul watch -type=service -service=web -format=json-stream | jq .Changes
 `
@@ -1,13 +1,17 @@
 package watch
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
@@ -44,6 +48,125 @@ func TestWatchCommand(t *testing.T) {
 	}
 }
 
+func TestWatchCommand_JSONStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	shutdownCh := make(chan struct{})
+	ui := cli.NewMockUi()
+	c := New(ui, shutdownCh)
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-type=keyprefix", "-prefix=app/", "-format=json-stream"}
+
+	codeCh := make(chan int, 1)
+	go func() {
+		codeCh <- c.Run(args)
+	}()
+
+	events := func(r require.TestingT) []streamEvent {
+		var events []streamEvent
+		for _, line := range strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var event streamEvent
+			require.NoError(r, json.Unmarshal([]byte(line), &event))
+			events = append(events, event)
+		}
+		return events
+	}
+
+	retry.Run(t, func(r *retry.R) {
+		require.Len(r, events(r), 1)
+	})
+
+	kv := a.Client().KV()
+	_, err := kv.Put(&api.KVPair{Key: "app/a", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		require.Len(r, events(r), 2)
+	})
+
+	_, err = kv.Put(&api.KVPair{Key: "app/a", Value: []byte("2")}, nil)
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		require.Len(r, events(r), 3)
+	})
+
+	close(shutdownCh)
+	select {
+	case code := <-codeCh:
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+	case <-time.After(10 * time.Second):
+		t.Fatal("watch did not stop")
+	}
+
+	got := events(t)
+	require.Equal(t, "keyprefix", got[0].Type)
+	require.Equal(t, []string{"app/a"}, got[1].Changes.Added)
+	require.Equal(t, []string{"app/a"}, got[2].Changes.Modified)
+	require.Greater(t, got[2].Index, got[1].Index)
+}
+
+func TestWatchCommand_JSONStreamChildProcess(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui, nil)
+	args := []string{"-type=nodes", "-format=json-stream", "cat"}
+
+	require.Equal(t, 1, c.Run(args))
+	require.Contains(t, ui.ErrorWriter.String(), "A child process can't be specified with -format=json-stream")
+}
+
+func TestStreamState(t *testing.T) {
+	state := &streamState{watchType: "service"}
+	entry := func(node, id string, status string) *api.ServiceEntry {
+		return &api.ServiceEntry{
+			Node:    &api.Node{Node: node},
+			Service: &api.AgentService{ID: id, Service: "web"},
+			Checks:  api.HealthChecks{{Node: node, CheckID: "web", Status: status}},
+		}
+	}
+
+	event := state.event(5, []*api.ServiceEntry{
+		entry("node1", "web-1", api.HealthPassing),
+		entry("node2", "web-2", api.HealthPassing),
+	})
+	require.Equal(t, &streamChanges{Added: []string{"node1/web-1", "node2/web-2"}}, event.Changes)
+
+	event = state.event(6, []*api.ServiceEntry{
+		entry("node1", "web-1", api.HealthCritical),
+		entry("node3", "web-3", api.HealthPassing),
+	})
+	require.Equal(t, &streamChanges{
+		Added:    []string{"node3/web-3"},
+		Removed:  []string{"node2/web-2"},
+		Modified: []string{"node1/web-1"},
+	}, event.Changes)
+
+	event = state.event(7, &api.KVPair{Key: "app/a"})
+	require.Nil(t, event.Changes)
+
+	state = &streamState{watchType: "config_entry"}
+	state.event(8, []api.ConfigEntry{
+		&api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "web", ModifyIndex: 8},
+	})
+	event = state.event(9, []api.ConfigEntry{
+		&api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "web", ModifyIndex: 9},
+		&api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "api", ModifyIndex: 9},
+	})
+	require.Equal(t, &streamChanges{
+		Added:    []string{"service-defaults/api"},
+		Modified: []string{"service-defaults/web"},
+	}, event.Changes)
+}
+
 func TestWatchCommand_loadToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
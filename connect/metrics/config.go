@@ -0,0 +1,51 @@
+// Package metrics implements the merging of the metrics of a Connect
+// workload: the metrics of the application, of its Envoy sidecar and of the
+// process running the merger are exposed together on a single Prometheus
+// endpoint, so that a single scrape target covers the whole workload.
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// MergingConfig is the set of keys of the opaque Proxy.Config map configuring
+// the metrics merging. It's usually set for all the proxies with the
+// proxy-defaults config entry.
+type MergingConfig struct {
+	// Port is the port the merged metrics are exposed on. The merging is
+	// disabled when it's 0.
+	Port int `mapstructure:"metrics_merging_port"`
+
+	// ServiceURL is the URL of the metrics endpoint of the application, in the
+	// Prometheus text format. The metrics of the application aren't merged
+	// when it's empty.
+	ServiceURL string `mapstructure:"metrics_merging_service_url"`
+
+	// Relabel are the rules applied in order to the merged metrics.
+	Relabel []RelabelRule `mapstructure:"metrics_merging_relabel"`
+}
+
+// ParseMergingConfig returns the metrics merging configuration of the
+// Proxy.Config map of a proxy.
+func ParseMergingConfig(m map[string]interface{}) (MergingConfig, error) {
+	var cfg MergingConfig
+	if err := mapstructure.WeakDecode(m, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed parsing the metrics merging config: %w", err)
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return cfg, fmt.Errorf("invalid metrics_merging_port %d", cfg.Port)
+	}
+	for i, rule := range cfg.Relabel {
+		if _, err := rule.compile(); err != nil {
+			return cfg, fmt.Errorf("invalid metrics_merging_relabel rule %d: %w", i, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Enabled returns whether the metrics merging is configured.
+func (c MergingConfig) Enabled() bool {
+	return c.Port != 0
+}
@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// SourceEnvoy, SourceService and SourceDataplane are the values of the
+	// source label of the UpMetric.
+	SourceEnvoy     = "envoy"
+	SourceService   = "service"
+	SourceDataplane = "dataplane"
+
+	// UpMetric is the gauge reporting whether the metrics of each source were
+	// merged, it's set to 0 when the source couldn't be scraped.
+	UpMetric = "consul_merged_metrics_up"
+
+	// ScrapeTimeout is the maximum time a source is scraped for.
+	ScrapeTimeout = 5 * time.Second
+
+	// EnvoyMetricsPath is the path of the Prometheus endpoint of the Envoy
+	// admin API. The envoy_prometheus_bind_addr listener rewrites the scrapes
+	// to this path, so the merger serves the merged metrics on it as well.
+	EnvoyMetricsPath = "/stats/prometheus"
+
+	// maxScrapeSize bounds the size of the responses of the sources.
+	maxScrapeSize = 32 * 1024 * 1024
+)
+
+// Merger is an http.Handler serving the merged metrics of the Envoy sidecar,
+// the application and the dataplane in the Prometheus text format. The
+// sources are scraped on each request, a source that fails is reported with
+// the UpMetric and doesn't fail the others.
+type Merger struct {
+	envoyURL   string
+	serviceURL string
+	gatherer   prometheus.Gatherer
+	rules      []relabelRule
+
+	client *http.Client
+	logger hclog.Logger
+}
+
+// NewMerger returns a Merger of the metrics of the Envoy admin API at
+// envoyURL, of the application as configured by cfg and of the gatherer,
+// which exposes the metrics of the dataplane. The gatherer may be nil.
+func NewMerger(cfg MergingConfig, envoyURL string, gatherer prometheus.Gatherer, logger hclog.Logger) (*Merger, error) {
+	rules, err := compileRelabelRules(cfg.Relabel)
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &Merger{
+		envoyURL:   envoyURL,
+		serviceURL: cfg.ServiceURL,
+		gatherer:   gatherer,
+		rules:      rules,
+		client:     &http.Client{Timeout: ScrapeTimeout},
+		logger:     logger,
+	}, nil
+}
+
+type scrapeResult struct {
+	source   string
+	families []*dto.MetricFamily
+	err      error
+}
+
+// ServeHTTP implements http.Handler.
+func (m *Merger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	families := m.Merge(r.Context())
+
+	w.Header().Set("Content-Type", string(expfmt.FmtText))
+	for _, f := range families {
+		if _, err := expfmt.MetricFamilyToText(w, f); err != nil {
+			m.logger.Debug("failed to write the merged metrics", "error", err)
+			return
+		}
+	}
+}
+
+// Merge scrapes the sources concurrently and returns their merged metric
+// families sorted by name.
+func (m *Merger) Merge(ctx context.Context) []*dto.MetricFamily {
+	sources := []string{SourceEnvoy}
+	if m.serviceURL != "" {
+		sources = append(sources, SourceService)
+	}
+	if m.gatherer != nil {
+		sources = append(sources, SourceDataplane)
+	}
+
+	results := make([]scrapeResult, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			families, err := m.scrape(ctx, source)
+			results[i] = scrapeResult{source: source, families: families, err: err}
+		}(i, source)
+	}
+	wg.Wait()
+
+	merged := make(map[string]*dto.MetricFamily)
+	up := newGauge(UpMetric, "Whether the metrics of the source were merged.")
+	for _, result := range results {
+		value := 1.0
+		if result.err != nil {
+			m.logger.Warn("failed to scrape metrics", "source", result.source, "error", result.err)
+			value = 0
+		}
+		addGaugeValue(up, "source", result.source, value)
+
+		for _, f := range result.families {
+			if f = relabel(m.rules, f); f == nil {
+				continue
+			}
+			existing, ok := merged[f.GetName()]
+			if !ok {
+				merged[f.GetName()] = f
+				continue
+			}
+			// Two sources exposing the same metric with different types
+			// would make the whole scrape invalid, the first one is kept.
+			if existing.GetType() != f.GetType() {
+				m.logger.Warn("dropping metric with conflicting type",
+					"source", result.source, "metric", f.GetName(), "type", f.GetType(), "existing_type", existing.GetType())
+				continue
+			}
+			existing.Metric = append(existing.Metric, f.Metric...)
+		}
+	}
+	merged[UpMetric] = up
+
+	families := make([]*dto.MetricFamily, 0, len(merged))
+	for _, f := range merged {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+	return families
+}
+
+func (m *Merger) scrape(ctx context.Context, source string) ([]*dto.MetricFamily, error) {
+	switch source {
+	case SourceEnvoy:
+		return m.scrapeURL(ctx, m.envoyURL)
+	case SourceService:
+		return m.scrapeURL(ctx, m.serviceURL)
+	case SourceDataplane:
+		return m.gatherer.Gather()
+	}
+	return nil, fmt.Errorf("unknown source %q", source)
+}
+
+func (m *Merger) scrapeURL(ctx context.Context, url string) ([]*dto.MetricFamily, error) {
+	return scrapeURL(ctx, m.client, url)
+}
+
+// scrapeURL returns the metric families served in the Prometheus text format
+// at url, sorted by name.
+func scrapeURL(ctx context.Context, client *http.Client, url string) ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code from %s: %d", url, resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	parsed, err := parser.TextToMetricFamilies(io.LimitReader(resp.Body, maxScrapeSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics of %s: %w", url, err)
+	}
+	families := make([]*dto.MetricFamily, 0, len(parsed))
+	for _, f := range parsed {
+		families = append(families, f)
+	}
+	// The map of the parser isn't ordered, sort the families so that the
+	// output is stable.
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+	return families, nil
+}
+
+func newGauge(name, help string) *dto.MetricFamily {
+	typ := dto.MetricType_GAUGE
+	return &dto.MetricFamily{Name: &name, Help: &help, Type: &typ}
+}
+
+func addGaugeValue(f *dto.MetricFamily, label, labelValue string, value float64) {
+	f.Metric = append(f.Metric, &dto.Metric{
+		Label: []*dto.LabelPair{{Name: &label, Value: &labelValue}},
+		Gauge: &dto.Gauge{Value: &value},
+	})
+}
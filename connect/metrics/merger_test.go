@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+const envoyMetrics = `# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{consul_source_service="web",envoy_cluster_name="api"} 7
+envoy_cluster_upstream_rq_total{consul_source_service="web",envoy_cluster_name="local_app"} 3
+# TYPE envoy_server_live gauge
+envoy_server_live 1
+# TYPE shared gauge
+shared{source="envoy"} 1
+`
+
+const serviceMetrics = `# HELP http_requests_total The number of requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200",pod="web-1"} 12
+# TYPE shared gauge
+shared{source="service"} 2
+# TYPE envoy_server_live counter
+envoy_server_live 5
+`
+
+func metricsServer(t *testing.T, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func scrapeMerger(t *testing.T, m *Merger) string {
+	t.Helper()
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + EnvoyMetricsPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMerger(t *testing.T) {
+	envoy := metricsServer(t, envoyMetrics)
+	service := metricsServer(t, serviceMetrics)
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "consul_dataplane_requests_total", Help: "Requests."})
+	counter.Add(4)
+	registry.MustRegister(counter)
+
+	m, err := NewMerger(MergingConfig{ServiceURL: service.URL}, envoy.URL, registry, nil)
+	require.NoError(t, err)
+
+	expected := `# HELP consul_dataplane_requests_total Requests.
+# TYPE consul_dataplane_requests_total counter
+consul_dataplane_requests_total 4
+# HELP consul_merged_metrics_up Whether the metrics of the source were merged.
+# TYPE consul_merged_metrics_up gauge
+consul_merged_metrics_up{source="envoy"} 1
+consul_merged_metrics_up{source="service"} 1
+consul_merged_metrics_up{source="dataplane"} 1
+# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{consul_source_service="web",envoy_cluster_name="api"} 7
+envoy_cluster_upstream_rq_total{consul_source_service="web",envoy_cluster_name="local_app"} 3
+# TYPE envoy_server_live gauge
+envoy_server_live 1
+# HELP http_requests_total The number of requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200",pod="web-1"} 12
+# TYPE shared gauge
+shared{source="envoy"} 1
+shared{source="service"} 2
+`
+	require.Equal(t, expected, scrapeMerger(t, m))
+}
+
+func TestMerger_Relabel(t *testing.T) {
+	envoy := metricsServer(t, envoyMetrics)
+	service := metricsServer(t, serviceMetrics)
+
+	cfg, err := ParseMergingConfig(map[string]interface{}{
+		"metrics_merging_port":        "20200",
+		"metrics_merging_service_url": service.URL,
+		"metrics_merging_relabel": []interface{}{
+			map[string]interface{}{"action": "drop", "regex": "shared|envoy_server_.*"},
+			map[string]interface{}{"action": "keep", "source_label": "envoy_cluster_name", "regex": "|api"},
+			map[string]interface{}{"action": "replace", "source_label": "consul_source_service", "target_label": "service", "regex": "(.+)"},
+			map[string]interface{}{"action": "labeldrop", "regex": "consul_.*|pod"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, cfg.Enabled())
+	require.Equal(t, 20200, cfg.Port)
+
+	m, err := NewMerger(cfg, envoy.URL, nil, nil)
+	require.NoError(t, err)
+
+	expected := `# HELP consul_merged_metrics_up Whether the metrics of the source were merged.
+# TYPE consul_merged_metrics_up gauge
+consul_merged_metrics_up{source="envoy"} 1
+consul_merged_metrics_up{source="service"} 1
+# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{envoy_cluster_name="api",service="web"} 7
+# HELP http_requests_total The number of requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200"} 12
+`
+	require.Equal(t, expected, scrapeMerger(t, m))
+}
+
+func TestMerger_SourceDown(t *testing.T) {
+	envoy := metricsServer(t, envoyMetrics)
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer service.Close()
+
+	m, err := NewMerger(MergingConfig{ServiceURL: service.URL}, envoy.URL, nil, nil)
+	require.NoError(t, err)
+
+	out := scrapeMerger(t, m)
+	require.Contains(t, out, `consul_merged_metrics_up{source="envoy"} 1`)
+	require.Contains(t, out, `consul_merged_metrics_up{source="service"} 0`)
+	require.Contains(t, out, `envoy_server_live 1`)
+}
+
+func TestParseMergingConfig(t *testing.T) {
+	cfg, err := ParseMergingConfig(map[string]interface{}{"envoy_prometheus_bind_addr": "0.0.0.0:9000"})
+	require.NoError(t, err)
+	require.False(t, cfg.Enabled())
+
+	cases := map[string]map[string]interface{}{
+		"invalid port": {"metrics_merging_port": 70000},
+		"unknown action": {
+			"metrics_merging_relabel": []interface{}{map[string]interface{}{"action": "hashmod"}},
+		},
+		"invalid regex": {
+			"metrics_merging_relabel": []interface{}{map[string]interface{}{"action": "drop", "regex": "("}},
+		},
+		"replace without target label": {
+			"metrics_merging_relabel": []interface{}{map[string]interface{}{"action": "replace"}},
+		},
+		"replace of the name": {
+			"metrics_merging_relabel": []interface{}{map[string]interface{}{"action": "replace", "target_label": "__name__"}},
+		},
+	}
+	for name, m := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMergingConfig(m)
+			require.Error(t, err)
+		})
+	}
+}
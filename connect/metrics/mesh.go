@@ -0,0 +1,406 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	// DirectionInbound and DirectionUpstream are the values of the direction
+	// label of the mesh metrics. Inbound is the traffic the proxy receives for
+	// its service, upstream the traffic it sends to the upstreams.
+	DirectionInbound  = "inbound"
+	DirectionUpstream = "upstream"
+
+	// MeshMinScrapeInterval is the minimum time between two scrapes of the
+	// proxies. The metrics of the previous scrape are served to requests
+	// received sooner, so that the rates aren't computed over tiny windows.
+	MeshMinScrapeInterval = 5 * time.Second
+
+	// publicListenerPrefix is the stat prefix of the inbound HTTP listener of
+	// the sidecar proxies.
+	publicListenerPrefix = "public_listener"
+
+	// latencyQuantile is the quantile of the latency reported.
+	latencyQuantile = 0.99
+)
+
+// The metrics served by the MeshAggregator and their labels.
+const (
+	meshProxyUp        = "consul_mesh_proxy_up"
+	meshRequestsRate   = "consul_mesh_requests_per_second"
+	meshErrorsRate     = "consul_mesh_errors_per_second"
+	meshLatencyP99     = "consul_mesh_latency_p99_ms"
+	meshActiveConns    = "consul_mesh_active_connections"
+	labelService       = "service"
+	labelDirection     = "direction"
+	labelUpstream      = "upstream"
+	labelProxyID       = "proxy_id"
+	envoyUpstreamLabel = "consul_destination_service"
+)
+
+// MeshTarget is a local proxy whose Envoy metrics are aggregated.
+type MeshTarget struct {
+	// ProxyID is the ID of the proxy service.
+	ProxyID string
+
+	// Service is the name of the service the proxy is the sidecar of.
+	Service string
+
+	// URL is the URL of the Prometheus metrics of Envoy.
+	URL string
+}
+
+// MeshAggregator aggregates the golden signals of the local proxies, the
+// request and 5xx rates, the p99 latency and the active connections, by
+// service and upstream from their Envoy metrics.
+//
+// The rates and the latency are computed from the difference between the
+// Envoy metrics of two scrapes, they are reported from the second scrape of a
+// proxy on.
+type MeshAggregator struct {
+	client *http.Client
+	logger hclog.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	samples  map[string]meshSample
+	lastAt   time.Time
+	families []*dto.MetricFamily
+}
+
+// NewMeshAggregator returns a MeshAggregator.
+func NewMeshAggregator(logger hclog.Logger) *MeshAggregator {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &MeshAggregator{
+		client:  &http.Client{Timeout: ScrapeTimeout},
+		logger:  logger,
+		now:     time.Now,
+		samples: make(map[string]meshSample),
+	}
+}
+
+// meshKey identifies the traffic of a proxy in a direction.
+type meshKey struct {
+	direction string
+	upstream  string
+}
+
+// meshCounters are the Envoy metrics of a proxy for a meshKey.
+type meshCounters struct {
+	requests float64
+	errors   float64
+	active   float64
+
+	// buckets are the cumulative counts of the latency histogram by upper
+	// bound in milliseconds, including +Inf.
+	buckets map[float64]float64
+}
+
+type meshSample struct {
+	at       time.Time
+	counters map[meshKey]*meshCounters
+}
+
+// meshSignals are the golden signals of a service in a direction.
+type meshSignals struct {
+	service string
+	key     meshKey
+
+	hasRates bool
+	requests float64
+	errors   float64
+	active   float64
+	buckets  map[float64]float64
+}
+
+// Aggregate scrapes the targets concurrently and returns the golden signals
+// of their services sorted by name.
+func (a *MeshAggregator) Aggregate(ctx context.Context, targets []MeshTarget) []*dto.MetricFamily {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if a.families != nil && now.Sub(a.lastAt) < MeshMinScrapeInterval {
+		return a.families
+	}
+
+	results := make([]scrapeResult, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target MeshTarget) {
+			defer wg.Done()
+			families, err := scrapeURL(ctx, a.client, target.URL)
+			results[i] = scrapeResult{source: target.ProxyID, families: families, err: err}
+		}(i, target)
+	}
+	wg.Wait()
+
+	up := newGauge(meshProxyUp, "Whether the metrics of the proxy could be scraped.")
+	signals := make(map[string]*meshSignals)
+	samples := make(map[string]meshSample, len(targets))
+	for i, target := range targets {
+		result := results[i]
+		value := 1.0
+		if result.err != nil {
+			a.logger.Warn("failed to scrape proxy metrics", "proxy", target.ProxyID, "error", result.err)
+			value = 0
+		}
+		addGaugeLabels(up, value, labelService, target.Service, labelProxyID, target.ProxyID)
+		if result.err != nil {
+			continue
+		}
+
+		sample := meshSample{at: now, counters: envoyCounters(result.families)}
+		samples[target.ProxyID] = sample
+		prev, hasPrev := a.samples[target.ProxyID]
+		elapsed := sample.at.Sub(prev.at).Seconds()
+
+		for key, c := range sample.counters {
+			id := target.Service + "\x00" + key.direction + "\x00" + key.upstream
+			s, ok := signals[id]
+			if !ok {
+				s = &meshSignals{service: target.Service, key: key, buckets: make(map[float64]float64)}
+				signals[id] = s
+			}
+			s.active += c.active
+
+			if !hasPrev || elapsed <= 0 {
+				continue
+			}
+			p, ok := prev.counters[key]
+			if !ok {
+				p = &meshCounters{}
+			}
+			s.hasRates = true
+			s.requests += counterDelta(c.requests, p.requests) / elapsed
+			s.errors += counterDelta(c.errors, p.errors) / elapsed
+			for bound, delta := range histogramDelta(c.buckets, p.buckets) {
+				s.buckets[bound] += delta
+			}
+		}
+	}
+	// The proxies which are gone are forgotten.
+	a.samples = samples
+
+	a.families = meshFamilies(up, signals)
+	a.lastAt = now
+	return a.families
+}
+
+func meshFamilies(up *dto.MetricFamily, signals map[string]*meshSignals) []*dto.MetricFamily {
+	ids := make([]string, 0, len(signals))
+	for id := range signals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	requests := newGauge(meshRequestsRate, "The rate of requests of the service, by direction and upstream.")
+	errors := newGauge(meshErrorsRate, "The rate of requests of the service answered with a 5xx status code, by direction and upstream.")
+	latency := newGauge(meshLatencyP99, "The p99 latency of the requests of the service in milliseconds, by direction and upstream.")
+	active := newGauge(meshActiveConns, "The active connections of the service, by direction and upstream.")
+	for _, id := range ids {
+		s := signals[id]
+		labels := []string{labelService, s.service, labelDirection, s.key.direction}
+		if s.key.direction == DirectionUpstream {
+			labels = append(labels, labelUpstream, s.key.upstream)
+		}
+
+		addGaugeLabels(active, s.active, labels...)
+		if !s.hasRates {
+			continue
+		}
+		addGaugeLabels(requests, s.requests, labels...)
+		addGaugeLabels(errors, s.errors, labels...)
+		if p99, ok := histogramQuantile(latencyQuantile, s.buckets); ok {
+			addGaugeLabels(latency, p99, labels...)
+		}
+	}
+
+	var families []*dto.MetricFamily
+	for _, f := range []*dto.MetricFamily{active, errors, latency, up, requests} {
+		if len(f.Metric) > 0 {
+			families = append(families, f)
+		}
+	}
+	return families
+}
+
+// envoyCounters extracts the metrics of the golden signals from the Envoy
+// metrics of a proxy. The inbound traffic is measured on the HTTP public
+// listener, the upstream traffic on the clusters of the upstreams.
+func envoyCounters(families []*dto.MetricFamily) map[meshKey]*meshCounters {
+	counters := make(map[meshKey]*meshCounters)
+	get := func(m *dto.Metric, inbound bool) *meshCounters {
+		var key meshKey
+		if inbound {
+			if labelValue(m, "envoy_http_conn_manager_prefix") != publicListenerPrefix {
+				return nil
+			}
+			key = meshKey{direction: DirectionInbound}
+		} else {
+			// The clusters without the label, such as local_app, aren't
+			// upstreams.
+			upstream := labelValue(m, envoyUpstreamLabel)
+			if upstream == "" {
+				return nil
+			}
+			key = meshKey{direction: DirectionUpstream, upstream: upstream}
+		}
+		c, ok := counters[key]
+		if !ok {
+			c = &meshCounters{buckets: make(map[float64]float64)}
+			counters[key] = c
+		}
+		return c
+	}
+
+	for _, f := range families {
+		var inbound bool
+		switch f.GetName() {
+		case "envoy_http_downstream_rq_total", "envoy_http_downstream_rq_xx",
+			"envoy_http_downstream_rq_time", "envoy_http_downstream_cx_active":
+			inbound = true
+		case "envoy_cluster_upstream_rq_total", "envoy_cluster_upstream_rq_xx",
+			"envoy_cluster_upstream_rq_time", "envoy_cluster_upstream_cx_active":
+		default:
+			continue
+		}
+
+		for _, m := range f.Metric {
+			c := get(m, inbound)
+			if c == nil {
+				continue
+			}
+			switch f.GetName() {
+			case "envoy_http_downstream_rq_total", "envoy_cluster_upstream_rq_total":
+				c.requests += metricValue(m)
+			case "envoy_http_downstream_rq_xx", "envoy_cluster_upstream_rq_xx":
+				if labelValue(m, "envoy_response_code_class") == "5" {
+					c.errors += metricValue(m)
+				}
+			case "envoy_http_downstream_rq_time", "envoy_cluster_upstream_rq_time":
+				h := m.GetHistogram()
+				if h == nil {
+					continue
+				}
+				for _, b := range h.Bucket {
+					// The +Inf bucket is the sample count, which isn't
+					// always exposed as a bucket.
+					if math.IsInf(b.GetUpperBound(), 1) {
+						continue
+					}
+					c.buckets[b.GetUpperBound()] += float64(b.GetCumulativeCount())
+				}
+				c.buckets[math.Inf(1)] += float64(h.GetSampleCount())
+			case "envoy_http_downstream_cx_active", "envoy_cluster_upstream_cx_active":
+				c.active += metricValue(m)
+			}
+		}
+	}
+	return counters
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Untyped != nil:
+		return m.Untyped.GetValue()
+	}
+	return 0
+}
+
+// counterDelta returns the increase of a counter, which restarts from zero
+// when Envoy restarts.
+func counterDelta(cur, prev float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// histogramDelta returns the increase of the cumulative counts of a
+// histogram, which restarts from zero when Envoy restarts.
+func histogramDelta(cur, prev map[float64]float64) map[float64]float64 {
+	for bound, count := range cur {
+		if count < prev[bound] {
+			return cur
+		}
+	}
+	delta := make(map[float64]float64, len(cur))
+	for bound, count := range cur {
+		delta[bound] = count - prev[bound]
+	}
+	return delta
+}
+
+// histogramQuantile estimates the quantile of the cumulative counts by upper
+// bound, interpolating linearly within the bucket the quantile falls in like
+// the histogram_quantile function of Prometheus. It returns false when the
+// histogram is empty.
+func histogramQuantile(q float64, buckets map[float64]float64) (float64, bool) {
+	bounds := make([]float64, 0, len(buckets))
+	for bound := range buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+	if len(bounds) == 0 || buckets[bounds[len(bounds)-1]] <= 0 {
+		return 0, false
+	}
+
+	rank := q * buckets[bounds[len(bounds)-1]]
+	var lowerBound, lowerCount float64
+	for i, bound := range bounds {
+		count := buckets[bound]
+		if count < rank {
+			lowerBound, lowerCount = bound, count
+			continue
+		}
+		// The upper bound of the last bucket is infinite, the highest finite
+		// bound is the best estimate.
+		if math.IsInf(bound, 1) {
+			if i == 0 {
+				return 0, false
+			}
+			return bounds[i-1], true
+		}
+		if count == lowerCount {
+			return bound, true
+		}
+		return lowerBound + (bound-lowerBound)*(rank-lowerCount)/(count-lowerCount), true
+	}
+	return bounds[len(bounds)-1], true
+}
+
+// addGaugeLabels adds a value to the gauge with the labels, given as pairs of
+// names and values.
+func addGaugeLabels(f *dto.MetricFamily, value float64, labels ...string) {
+	m := &dto.Metric{Gauge: &dto.Gauge{Value: &value}}
+	for i := 0; i+1 < len(labels); i += 2 {
+		name, v := labels[i], labels[i+1]
+		m.Label = append(m.Label, &dto.LabelPair{Name: &name, Value: &v})
+	}
+	f.Metric = append(f.Metric, m)
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/require"
+)
+
+const envoyMeshMetricsBefore = `# TYPE envoy_http_downstream_rq_total counter
+envoy_http_downstream_rq_total{envoy_http_conn_manager_prefix="public_listener"} 100
+envoy_http_downstream_rq_total{envoy_http_conn_manager_prefix="upstream.db.default.default.dc1"} 40
+# TYPE envoy_http_downstream_rq_xx counter
+envoy_http_downstream_rq_xx{envoy_http_conn_manager_prefix="public_listener",envoy_response_code_class="2"} 95
+envoy_http_downstream_rq_xx{envoy_http_conn_manager_prefix="public_listener",envoy_response_code_class="5"} 5
+# TYPE envoy_http_downstream_cx_active gauge
+envoy_http_downstream_cx_active{envoy_http_conn_manager_prefix="public_listener"} 3
+# TYPE envoy_http_downstream_rq_time histogram
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="10"} 90
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="100"} 100
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="+Inf"} 100
+envoy_http_downstream_rq_time_sum{envoy_http_conn_manager_prefix="public_listener"} 1000
+envoy_http_downstream_rq_time_count{envoy_http_conn_manager_prefix="public_listener"} 100
+# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{consul_destination_service="db",envoy_cluster_name="db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"} 40
+envoy_cluster_upstream_rq_total{envoy_cluster_name="local_app"} 100
+# TYPE envoy_cluster_upstream_cx_active gauge
+envoy_cluster_upstream_cx_active{consul_destination_service="db",envoy_cluster_name="db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"} 2
+`
+
+const envoyMeshMetricsAfter = `# TYPE envoy_http_downstream_rq_total counter
+envoy_http_downstream_rq_total{envoy_http_conn_manager_prefix="public_listener"} 200
+envoy_http_downstream_rq_total{envoy_http_conn_manager_prefix="upstream.db.default.default.dc1"} 60
+# TYPE envoy_http_downstream_rq_xx counter
+envoy_http_downstream_rq_xx{envoy_http_conn_manager_prefix="public_listener",envoy_response_code_class="2"} 185
+envoy_http_downstream_rq_xx{envoy_http_conn_manager_prefix="public_listener",envoy_response_code_class="5"} 15
+# TYPE envoy_http_downstream_cx_active gauge
+envoy_http_downstream_cx_active{envoy_http_conn_manager_prefix="public_listener"} 4
+# TYPE envoy_http_downstream_rq_time histogram
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="10"} 170
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="100"} 200
+envoy_http_downstream_rq_time_bucket{envoy_http_conn_manager_prefix="public_listener",le="+Inf"} 200
+envoy_http_downstream_rq_time_sum{envoy_http_conn_manager_prefix="public_listener"} 2500
+envoy_http_downstream_rq_time_count{envoy_http_conn_manager_prefix="public_listener"} 200
+# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{consul_destination_service="db",envoy_cluster_name="db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"} 60
+envoy_cluster_upstream_rq_total{envoy_cluster_name="local_app"} 200
+# TYPE envoy_cluster_upstream_cx_active gauge
+envoy_cluster_upstream_cx_active{consul_destination_service="db",envoy_cluster_name="db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"} 2
+`
+
+func familiesText(t *testing.T, families []*dto.MetricFamily) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, f := range families {
+		_, err := expfmt.MetricFamilyToText(&buf, f)
+		require.NoError(t, err)
+	}
+	return buf.String()
+}
+
+func TestMeshAggregator(t *testing.T) {
+	var body atomic.Value
+	body.Store(envoyMeshMetricsBefore)
+	envoy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body.Load().(string))
+	}))
+	defer envoy.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	now := time.Date(2023, 10, 16, 10, 0, 0, 0, time.UTC)
+	a := NewMeshAggregator(nil)
+	a.now = func() time.Time { return now }
+
+	targets := []MeshTarget{
+		{ProxyID: "web-sidecar-proxy", Service: "web", URL: envoy.URL + "/metrics"},
+		{ProxyID: "api-sidecar-proxy", Service: "api", URL: down.URL + "/metrics"},
+	}
+
+	// The rates need two scrapes, only the active connections are reported
+	// on the first one.
+	require.Equal(t, `# HELP consul_mesh_active_connections The active connections of the service, by direction and upstream.
+# TYPE consul_mesh_active_connections gauge
+consul_mesh_active_connections{service="web",direction="inbound"} 3
+consul_mesh_active_connections{service="web",direction="upstream",upstream="db"} 2
+# HELP consul_mesh_proxy_up Whether the metrics of the proxy could be scraped.
+# TYPE consul_mesh_proxy_up gauge
+consul_mesh_proxy_up{service="web",proxy_id="web-sidecar-proxy"} 1
+consul_mesh_proxy_up{service="api",proxy_id="api-sidecar-proxy"} 0
+`, familiesText(t, a.Aggregate(context.Background(), targets)))
+
+	body.Store(envoyMeshMetricsAfter)
+
+	// The proxies aren't scraped again before the minimum interval.
+	now = now.Add(time.Second)
+	require.Contains(t, familiesText(t, a.Aggregate(context.Background(), targets)),
+		`consul_mesh_active_connections{service="web",direction="inbound"} 3`)
+
+	now = now.Add(9 * time.Second)
+	require.Equal(t, `# HELP consul_mesh_active_connections The active connections of the service, by direction and upstream.
+# TYPE consul_mesh_active_connections gauge
+consul_mesh_active_connections{service="web",direction="inbound"} 4
+consul_mesh_active_connections{service="web",direction="upstream",upstream="db"} 2
+# HELP consul_mesh_errors_per_second The rate of requests of the service answered with a 5xx status code, by direction and upstream.
+# TYPE consul_mesh_errors_per_second gauge
+consul_mesh_errors_per_second{service="web",direction="inbound"} 1
+consul_mesh_errors_per_second{service="web",direction="upstream",upstream="db"} 0
+# HELP consul_mesh_latency_p99_ms The p99 latency of the requests of the service in milliseconds, by direction and upstream.
+# TYPE consul_mesh_latency_p99_ms gauge
+consul_mesh_latency_p99_ms{service="web",direction="inbound"} 95.5
+# HELP consul_mesh_proxy_up Whether the metrics of the proxy could be scraped.
+# TYPE consul_mesh_proxy_up gauge
+consul_mesh_proxy_up{service="web",proxy_id="web-sidecar-proxy"} 1
+consul_mesh_proxy_up{service="api",proxy_id="api-sidecar-proxy"} 0
+# HELP consul_mesh_requests_per_second The rate of requests of the service, by direction and upstream.
+# TYPE consul_mesh_requests_per_second gauge
+consul_mesh_requests_per_second{service="web",direction="inbound"} 10
+consul_mesh_requests_per_second{service="web",direction="upstream",upstream="db"} 2
+`, familiesText(t, a.Aggregate(context.Background(), targets)))
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	inf := math.Inf(1)
+
+	cases := map[string]struct {
+		buckets  map[float64]float64
+		expected float64
+		ok       bool
+	}{
+		"empty": {
+			buckets: map[float64]float64{},
+		},
+		"no requests": {
+			buckets: map[float64]float64{10: 0, inf: 0},
+		},
+		"first bucket": {
+			buckets:  map[float64]float64{10: 100, 100: 100, inf: 100},
+			expected: 9.9,
+			ok:       true,
+		},
+		"interpolated": {
+			buckets:  map[float64]float64{10: 50, 100: 100, inf: 100},
+			expected: 98.2,
+			ok:       true,
+		},
+		"infinite bucket": {
+			buckets:  map[float64]float64{10: 50, 100: 90, inf: 100},
+			expected: 100,
+			ok:       true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p99, ok := histogramQuantile(0.99, tc.buckets)
+			require.Equal(t, tc.ok, ok)
+			require.InDelta(t, tc.expected, p99, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// The actions of the relabel rules, with the semantics of the Prometheus
+// relabel_config actions of the same name.
+const (
+	// RelabelActionDrop drops the series whose source label matches the
+	// regex.
+	RelabelActionDrop = "drop"
+
+	// RelabelActionKeep drops the series whose source label doesn't match the
+	// regex.
+	RelabelActionKeep = "keep"
+
+	// RelabelActionReplace sets the target label to the replacement, expanded
+	// with the groups of the regex, when the source label matches. The target
+	// label is removed when the replacement expands to an empty value.
+	RelabelActionReplace = "replace"
+
+	// RelabelActionLabelDrop removes the labels whose name matches the regex.
+	RelabelActionLabelDrop = "labeldrop"
+)
+
+// nameLabel is the source label matching the name of the metric.
+const nameLabel = "__name__"
+
+// RelabelRule is a rule modifying or filtering the merged metrics.
+type RelabelRule struct {
+	// Action is one of drop, keep, replace or labeldrop.
+	Action string `mapstructure:"action"`
+
+	// SourceLabel is the label whose value is matched against the regex. It
+	// defaults to the name of the metric.
+	SourceLabel string `mapstructure:"source_label"`
+
+	// Regex is the regular expression matched against the source label, or
+	// against the names of the labels for labeldrop. It's anchored at both
+	// ends and defaults to "(.*)".
+	Regex string `mapstructure:"regex"`
+
+	// TargetLabel is the label set by replace.
+	TargetLabel string `mapstructure:"target_label"`
+
+	// Replacement is the value of the target label set by replace. It may
+	// refer to the groups of the regex, for example "$1", and defaults to
+	// "$1".
+	Replacement string `mapstructure:"replacement"`
+}
+
+type relabelRule struct {
+	RelabelRule
+	regex *regexp.Regexp
+}
+
+func (r RelabelRule) compile() (relabelRule, error) {
+	expr := r.Regex
+	if expr == "" {
+		expr = "(.*)"
+	}
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return relabelRule{}, fmt.Errorf("invalid regex %q: %w", r.Regex, err)
+	}
+	if r.SourceLabel == "" {
+		r.SourceLabel = nameLabel
+	}
+	if r.Replacement == "" {
+		r.Replacement = "$1"
+	}
+
+	switch r.Action {
+	case RelabelActionDrop, RelabelActionKeep, RelabelActionLabelDrop:
+	case RelabelActionReplace:
+		if r.TargetLabel == "" {
+			return relabelRule{}, fmt.Errorf("target_label is required with the %q action", r.Action)
+		}
+		if r.TargetLabel == nameLabel {
+			return relabelRule{}, fmt.Errorf("the name of the metrics can't be replaced")
+		}
+	default:
+		return relabelRule{}, fmt.Errorf("unknown action %q", r.Action)
+	}
+	return relabelRule{RelabelRule: r, regex: regex}, nil
+}
+
+func compileRelabelRules(rules []RelabelRule) ([]relabelRule, error) {
+	compiled := make([]relabelRule, 0, len(rules))
+	for i, rule := range rules {
+		c, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid relabel rule %d: %w", i, err)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// relabel applies the rules to the series of a family, returning nil if
+// none of them is kept.
+func relabel(rules []relabelRule, family *dto.MetricFamily) *dto.MetricFamily {
+	if len(rules) == 0 {
+		return family
+	}
+
+	var kept []*dto.Metric
+	for _, m := range family.Metric {
+		labels := make(map[string]string, len(m.Label)+1)
+		for _, l := range m.Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		labels[nameLabel] = family.GetName()
+
+		if !relabelSeries(rules, labels) {
+			continue
+		}
+		delete(labels, nameLabel)
+		m.Label = labelPairs(labels)
+		kept = append(kept, m)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	family.Metric = kept
+	return family
+}
+
+// relabelSeries applies the rules to the labels of a series, returning false
+// if the series is dropped.
+func relabelSeries(rules []relabelRule, labels map[string]string) bool {
+	for _, r := range rules {
+		switch r.Action {
+		case RelabelActionDrop:
+			if r.regex.MatchString(labels[r.SourceLabel]) {
+				return false
+			}
+		case RelabelActionKeep:
+			if !r.regex.MatchString(labels[r.SourceLabel]) {
+				return false
+			}
+		case RelabelActionReplace:
+			value := labels[r.SourceLabel]
+			match := r.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			replaced := string(r.regex.ExpandString(nil, r.Replacement, value, match))
+			if replaced == "" {
+				delete(labels, r.TargetLabel)
+			} else {
+				labels[r.TargetLabel] = replaced
+			}
+		case RelabelActionLabelDrop:
+			for name := range labels {
+				if name != nameLabel && r.regex.MatchString(name) {
+					delete(labels, name)
+				}
+			}
+		}
+	}
+	return true
+}
+
+func labelPairs(labels map[string]string) []*dto.LabelPair {
+	pairs := make([]*dto.LabelPair, 0, len(labels))
+	for name, value := range labels {
+		name, value := name, value
+		pairs = append(pairs, &dto.LabelPair{Name: &name, Value: &value})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].GetName() < pairs[j].GetName()
+	})
+	return pairs
+}
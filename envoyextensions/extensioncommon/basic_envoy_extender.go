@@ -53,7 +53,7 @@ func (envoyExtender *BasicEnvoyExtender) Extend(resources *xdscommon.IndexedReso
 	var resultErr error
 
 	switch config.Kind {
-	case api.ServiceKindTerminatingGateway, api.ServiceKindConnectProxy:
+	case api.ServiceKindTerminatingGateway, api.ServiceKindConnectProxy, api.ServiceKindAPIGateway:
 	default:
 		return resources, nil
 	}
@@ -78,7 +78,7 @@ func (envoyExtender *BasicEnvoyExtender) Extend(resources *xdscommon.IndexedReso
 
 				// If the extension's config is for an an inbound listener, the Cluster's name
 				// must be xdscommon.LocalAppClusterName.
-				if !config.IsUpstream() && nameOrSNI == xdscommon.LocalAppClusterName {
+				if !config.IsUpstream() && nameOrSNI != xdscommon.LocalAppClusterName {
 					continue
 				}
 
@@ -104,13 +104,13 @@ func (envoyExtender *BasicEnvoyExtender) Extend(resources *xdscommon.IndexedReso
 			case *envoy_route_v3.RouteConfiguration:
 				// If the Envoy extension configuration is for an upstream service, the route's
 				// name must match the upstream service's Envoy ID.
-				matchesEnvoyID := config.EnvoyID() == nameOrSNI
-				if config.IsUpstream() && !config.MatchesUpstreamServiceSNI(nameOrSNI) && !matchesEnvoyID {
+				if config.IsUpstream() && !config.MatchesUpstreamServiceSNI(nameOrSNI) && config.EnvoyID() != nameOrSNI {
 					continue
 				}
 
-				// There aren't routes for inbound services.
-				if !config.IsUpstream() {
+				// There aren't routes for inbound services, except for the routes of the
+				// listeners of API gateways.
+				if !config.IsUpstream() && config.Kind != api.ServiceKindAPIGateway {
 					continue
 				}
 
@@ -137,6 +137,8 @@ func (envoyExtension BasicEnvoyExtender) patchListener(config *RuntimeConfig, l
 		return envoyExtension.patchTerminatingGatewayListener(config, l)
 	case api.ServiceKindConnectProxy:
 		return envoyExtension.patchConnectProxyListener(config, l)
+	case api.ServiceKindAPIGateway:
+		return envoyExtension.patchAPIGatewayListener(config, l)
 	}
 	return l, false, nil
 }
@@ -234,6 +236,39 @@ func (b BasicEnvoyExtender) patchConnectProxyListener(config *RuntimeConfig, l *
 	return l, patched, resultErr
 }
 
+func (b BasicEnvoyExtender) patchAPIGatewayListener(config *RuntimeConfig, l *envoy_listener_v3.Listener) (proto.Message, bool, error) {
+	// We don't support targeting the upstreams of API gateways with extensions.
+	// All the listeners of an API gateway are public, so they are all patched.
+	if config.IsUpstream() {
+		return l, false, nil
+	}
+
+	var resultErr error
+	patched := false
+	for _, filterChain := range l.FilterChains {
+		var filters []*envoy_listener_v3.Filter
+
+		for _, filter := range filterChain.Filters {
+			newFilter, ok, err := b.Extension.PatchFilter(config, filter)
+			if err != nil {
+				resultErr = multierror.Append(resultErr, fmt.Errorf("error patching listener filter: %w", err))
+				filters = append(filters, filter)
+				continue
+			}
+
+			if ok {
+				filters = append(filters, newFilter)
+				patched = true
+			} else {
+				filters = append(filters, filter)
+			}
+		}
+		filterChain.Filters = filters
+	}
+
+	return l, patched, resultErr
+}
+
 func (b BasicEnvoyExtender) patchTProxyListener(config *RuntimeConfig, l *envoy_listener_v3.Listener) (proto.Message, bool, error) {
 	var resultErr error
 	patched := false
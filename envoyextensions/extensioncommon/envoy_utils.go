@@ -1,26 +1,26 @@
-package awslambda
+package extensioncommon
 
 import (
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
-	"google.golang.org/protobuf/types/known/anypb"
-
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
-// This is copied from xds and not put into the shared package because I'm not
-// convinced it should be shared.
-
-func makeUpstreamTLSTransportSocket(tlsContext *envoy_tls_v3.UpstreamTlsContext) (*envoy_core_v3.TransportSocket, error) {
+// MakeUpstreamTLSTransportSocket returns a transport socket that originates
+// TLS with the given context, or nil if the context is nil.
+func MakeUpstreamTLSTransportSocket(tlsContext *envoy_tls_v3.UpstreamTlsContext) (*envoy_core_v3.TransportSocket, error) {
 	if tlsContext == nil {
 		return nil, nil
 	}
-	return makeTransportSocket("tls", tlsContext)
+	return MakeTransportSocket("tls", tlsContext)
 }
 
-func makeTransportSocket(name string, config proto.Message) (*envoy_core_v3.TransportSocket, error) {
+// MakeTransportSocket returns a transport socket with the given name and
+// typed config.
+func MakeTransportSocket(name string, config proto.Message) (*envoy_core_v3.TransportSocket, error) {
 	any, err := anypb.New(config)
 	if err != nil {
 		return nil, err
@@ -33,7 +33,9 @@ func makeTransportSocket(name string, config proto.Message) (*envoy_core_v3.Tran
 	}, nil
 }
 
-func makeEnvoyHTTPFilter(name string, cfg proto.Message) (*envoy_http_v3.HttpFilter, error) {
+// MakeEnvoyHTTPFilter returns an HTTP filter with the given name and typed
+// config.
+func MakeEnvoyHTTPFilter(name string, cfg proto.Message) (*envoy_http_v3.HttpFilter, error) {
 	any, err := anypb.New(cfg)
 	if err != nil {
 		return nil, err
@@ -45,7 +47,8 @@ func makeEnvoyHTTPFilter(name string, cfg proto.Message) (*envoy_http_v3.HttpFil
 	}, nil
 }
 
-func makeFilter(name string, cfg proto.Message) (*envoy_listener_v3.Filter, error) {
+// MakeFilter returns a network filter with the given name and typed config.
+func MakeFilter(name string, cfg proto.Message) (*envoy_listener_v3.Filter, error) {
 	any, err := anypb.New(cfg)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,79 @@
+package extensioncommon
+
+import (
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Internal listeners are Envoy listeners that don't bind to a socket. They
+// only accept the connections of the clusters that point at them with an
+// internal address, which lets extensions chain several stages of processing
+// in the same proxy without going through the network stack.
+//
+// They require the envoy.bootstrap.internal_listener bootstrap extension,
+// which is only enabled when the envoy_enable_internal_listeners proxy config
+// option is set, and Envoy 1.23 or later.
+
+// MakeInternalListener returns an internal listener with the given name and
+// filter chains.
+func MakeInternalListener(name string, filterChains ...*envoy_listener_v3.FilterChain) *envoy_listener_v3.Listener {
+	return &envoy_listener_v3.Listener{
+		Name:         name,
+		FilterChains: filterChains,
+		ListenerSpecifier: &envoy_listener_v3.Listener_InternalListener{
+			InternalListener: &envoy_listener_v3.Listener_InternalListenerConfig{},
+		},
+	}
+}
+
+// IsInternalListener returns true if the listener is an internal listener.
+func IsInternalListener(l *envoy_listener_v3.Listener) bool {
+	return l.GetInternalListener() != nil
+}
+
+// MakeInternalAddress returns the address of the internal listener with the
+// given name.
+func MakeInternalAddress(listenerName string) *envoy_core_v3.Address {
+	return &envoy_core_v3.Address{
+		Address: &envoy_core_v3.Address_EnvoyInternalAddress{
+			EnvoyInternalAddress: &envoy_core_v3.EnvoyInternalAddress{
+				AddressNameSpecifier: &envoy_core_v3.EnvoyInternalAddress_ServerListenerName{
+					ServerListenerName: listenerName,
+				},
+			},
+		},
+	}
+}
+
+// MakeInternalCluster returns a cluster with the given name that forwards
+// its connections to the internal listener with the given name.
+func MakeInternalCluster(name, listenerName string) *envoy_cluster_v3.Cluster {
+	return &envoy_cluster_v3.Cluster{
+		Name:                 name,
+		ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_STATIC},
+		// The connections never leave the proxy, Envoy only requires a
+		// positive timeout.
+		ConnectTimeout: durationpb.New(time.Second),
+		LoadAssignment: &envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+						{
+							HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+								Endpoint: &envoy_endpoint_v3.Endpoint{
+									Address: MakeInternalAddress(listenerName),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
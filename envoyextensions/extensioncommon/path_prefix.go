@@ -0,0 +1,78 @@
+package extensioncommon
+
+import (
+	"strings"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// RouteMatchesPathPrefix reports whether all the requests matched by the
+// route are to the given path prefix or below it. Whole path segments are
+// matched, so /public doesn't match /public-admin. Every route matches an
+// empty prefix.
+func RouteMatchesPathPrefix(route *envoy_route_v3.Route, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	switch m := route.GetMatch().GetPathSpecifier().(type) {
+	case *envoy_route_v3.RouteMatch_Prefix:
+		// A /public prefix route also matches /public-admin, so the route
+		// must only match the requests below the path prefix.
+		return strings.HasPrefix(m.Prefix, pathPrefixSubtree(prefix))
+	case *envoy_route_v3.RouteMatch_Path:
+		return m.Path == prefix || strings.HasPrefix(m.Path, pathPrefixSubtree(prefix))
+	}
+	return false
+}
+
+// pathPrefixSubtree returns the string prefix of the paths below the given
+// path prefix.
+func pathPrefixSubtree(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
+
+// PathPrefixMatches returns the route matches of the requests to the given
+// path prefix: the path itself and the paths below it.
+func PathPrefixMatches(prefix string) []*envoy_route_v3.RouteMatch {
+	subtree := &envoy_route_v3.RouteMatch{
+		PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: pathPrefixSubtree(prefix)},
+	}
+	if prefix == pathPrefixSubtree(prefix) {
+		return []*envoy_route_v3.RouteMatch{subtree}
+	}
+	exact := &envoy_route_v3.RouteMatch{
+		PathSpecifier: &envoy_route_v3.RouteMatch_Path{Path: prefix},
+	}
+	return []*envoy_route_v3.RouteMatch{exact, subtree}
+}
+
+// PathPrefixRoutes returns copies of the prefix route that only match the
+// requests to the given path prefix and below it, so that they can be
+// configured separately from the requests outside of the path prefix that the
+// route also matches. The prefix of the route must be a string prefix of the
+// path prefix, and the copies rewrite the matched paths as the route does.
+func PathPrefixRoutes(route *envoy_route_v3.Route, prefix string) []*envoy_route_v3.Route {
+	routePrefix := route.GetMatch().GetPrefix()
+
+	var routes []*envoy_route_v3.Route
+	for _, match := range PathPrefixMatches(prefix) {
+		clone := proto.Clone(route).(*envoy_route_v3.Route)
+		clone.Match.PathSpecifier = match.PathSpecifier
+		if action := clone.GetRoute(); action != nil && action.PrefixRewrite != "" {
+			var matched string
+			switch ps := match.PathSpecifier.(type) {
+			case *envoy_route_v3.RouteMatch_Path:
+				matched = ps.Path
+			case *envoy_route_v3.RouteMatch_Prefix:
+				matched = ps.Prefix
+			}
+			action.PrefixRewrite += matched[len(routePrefix):]
+		}
+		routes = append(routes, clone)
+	}
+	return routes
+}
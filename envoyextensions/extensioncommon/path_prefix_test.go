@@ -0,0 +1,71 @@
+package extensioncommon
+
+import (
+	"testing"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteMatchesPathPrefix(t *testing.T) {
+	prefixRoute := func(prefix string) *envoy_route_v3.Route {
+		return &envoy_route_v3.Route{Match: &envoy_route_v3.RouteMatch{
+			PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: prefix},
+		}}
+	}
+	pathRoute := func(path string) *envoy_route_v3.Route {
+		return &envoy_route_v3.Route{Match: &envoy_route_v3.RouteMatch{
+			PathSpecifier: &envoy_route_v3.RouteMatch_Path{Path: path},
+		}}
+	}
+
+	cases := map[string]struct {
+		route  *envoy_route_v3.Route
+		prefix string
+		want   bool
+	}{
+		"empty prefix":                    {prefixRoute("/"), "", true},
+		"exact path":                      {pathRoute("/public"), "/public", true},
+		"path below prefix":               {pathRoute("/public/docs"), "/public", true},
+		"path sharing the prefix string":  {pathRoute("/public-admin"), "/public", false},
+		"prefix below prefix":             {prefixRoute("/public/"), "/public", true},
+		"same prefix":                     {prefixRoute("/public"), "/public", false},
+		"prefix sharing the string":       {prefixRoute("/public-admin"), "/public", false},
+		"broader prefix":                  {prefixRoute("/"), "/public", false},
+		"prefix with trailing slash":      {prefixRoute("/public/docs"), "/public/", true},
+		"path outside trailing slash":     {pathRoute("/public"), "/public/", false},
+		"prefix sharing the string below": {prefixRoute("/publication/"), "/public", false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, RouteMatchesPathPrefix(tc.route, tc.prefix))
+		})
+	}
+}
+
+func TestPathPrefixRoutes(t *testing.T) {
+	route := &envoy_route_v3.Route{
+		Match: &envoy_route_v3.RouteMatch{
+			PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/api"},
+		},
+		Action: &envoy_route_v3.Route_Route{Route: &envoy_route_v3.RouteAction{
+			PrefixRewrite: "/v1",
+		}},
+	}
+
+	routes := PathPrefixRoutes(route, "/api/public")
+	require.Len(t, routes, 2)
+	require.Equal(t, "/api/public", routes[0].GetMatch().GetPath())
+	require.Equal(t, "/v1/public", routes[0].GetRoute().PrefixRewrite)
+	require.Equal(t, "/api/public/", routes[1].GetMatch().GetPrefix())
+	require.Equal(t, "/v1/public/", routes[1].GetRoute().PrefixRewrite)
+
+	// The original route is left unchanged.
+	require.Equal(t, "/api", route.GetMatch().GetPrefix())
+	require.Equal(t, "/v1", route.GetRoute().PrefixRewrite)
+
+	routes = PathPrefixRoutes(route, "/api/")
+	require.Len(t, routes, 1)
+	require.Equal(t, "/api/", routes[0].GetMatch().GetPrefix())
+	require.Equal(t, "/v1/", routes[0].GetRoute().PrefixRewrite)
+}
@@ -58,6 +58,9 @@ func (ec RuntimeConfig) EnvoyID() string {
 }
 
 func (ec RuntimeConfig) OutgoingProxyKind() api.ServiceKind {
-	u := ec.Upstreams[ec.ServiceName]
+	u, ok := ec.Upstreams[ec.ServiceName]
+	if !ok {
+		return ""
+	}
 	return u.OutgoingProxyKind
 }
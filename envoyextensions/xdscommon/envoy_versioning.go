@@ -12,6 +12,10 @@ var (
 	// the zero'th point release of the last element of xdscommon.EnvoyVersions.
 	minSupportedVersion = version.Must(version.NewVersion(GetMinEnvoyMinorVersion()))
 
+	// maxSupportedMinorVersion is the newest minor version we support. This should
+	// always be the minor version of the first element of xdscommon.EnvoyVersions.
+	maxSupportedMinorVersion = version.Must(version.NewVersion(GetMaxEnvoyMinorVersion()))
+
 	specificUnsupportedVersions = []unsupportedVersion{}
 )
 
@@ -26,6 +30,58 @@ type SupportedProxyFeatures struct {
 	// <insert PR here>.
 }
 
+// EnvoyVersionSupport describes whether an Envoy version is part of the
+// supported versions, see EnvoyVersions.
+type EnvoyVersionSupport string
+
+const (
+	// EnvoyVersionSupported versions are supported.
+	EnvoyVersionSupported EnvoyVersionSupport = "supported"
+
+	// EnvoyVersionTooOld versions are older than the oldest supported minor
+	// version. They are always rejected.
+	EnvoyVersionTooOld EnvoyVersionSupport = "too_old"
+
+	// EnvoyVersionTooNew versions are newer than the newest supported minor
+	// version, so the configuration generated for them may not be loaded.
+	EnvoyVersionTooNew EnvoyVersionSupport = "too_new"
+
+	// EnvoyVersionUnsupported versions are point releases of a supported minor
+	// version that are not supported.
+	EnvoyVersionUnsupported EnvoyVersionSupport = "unsupported"
+
+	// EnvoyVersionUnknown is used when the proxy does not report the version of
+	// an official Envoy build.
+	EnvoyVersionUnknown EnvoyVersionSupport = "unknown"
+)
+
+// CheckEnvoyVersion returns the Envoy version of a node, if it is known, and
+// whether it is supported.
+func CheckEnvoyVersion(node *envoy_core_v3.Node) (*version.Version, EnvoyVersionSupport) {
+	v := determineEnvoyVersionFromNode(node)
+	return v, checkEnvoyVersion(v)
+}
+
+func checkEnvoyVersion(v *version.Version) EnvoyVersionSupport {
+	if v == nil {
+		return EnvoyVersionUnknown
+	}
+	if v.LessThan(minSupportedVersion) {
+		return EnvoyVersionTooOld
+	}
+	for _, uv := range specificUnsupportedVersions {
+		if v.Equal(uv.Version) {
+			return EnvoyVersionUnsupported
+		}
+	}
+
+	segments, max := v.Segments(), maxSupportedMinorVersion.Segments()
+	if segments[0] > max[0] || (segments[0] == max[0] && segments[1] > max[1]) {
+		return EnvoyVersionTooNew
+	}
+	return EnvoyVersionSupported
+}
+
 func DetermineSupportedProxyFeatures(node *envoy_core_v3.Node) (SupportedProxyFeatures, error) {
 	version := determineEnvoyVersionFromNode(node)
 	return determineSupportedProxyFeaturesFromVersion(version)
@@ -155,3 +155,22 @@ func TestDetermineSupportedProxyFeaturesFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckEnvoyVersion(t *testing.T) {
+	cases := map[string]EnvoyVersionSupport{
+		"1.20.7": EnvoyVersionTooOld,
+		"1.21.0": EnvoyVersionSupported,
+		"1.23.2": EnvoyVersionSupported,
+		"1.24.0": EnvoyVersionSupported,
+		"1.24.9": EnvoyVersionSupported,
+		"1.25.0": EnvoyVersionTooNew,
+		"2.0.0":  EnvoyVersionTooNew,
+	}
+	for v, expect := range cases {
+		require.Equal(t, expect, checkEnvoyVersion(version.Must(version.NewVersion(v))), v)
+	}
+
+	v, support := CheckEnvoyVersion(&envoy_core_v3.Node{})
+	require.Nil(t, v)
+	require.Equal(t, EnvoyVersionUnknown, support)
+}
@@ -0,0 +1,179 @@
+package jsonpatch
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	cases := map[string]struct {
+		doc      string
+		patch    string
+		expected string
+		err      string
+	}{
+		"add member": {
+			doc:      `{"a":1}`,
+			patch:    `[{"op":"add","path":"/b","value":{"c":[1,2]}}]`,
+			expected: `{"a":1,"b":{"c":[1,2]}}`,
+		},
+		"add to array": {
+			doc:      `{"a":[1,3]}`,
+			patch:    `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/-","value":4}]`,
+			expected: `{"a":[1,2,3,4]}`,
+		},
+		"remove": {
+			doc:      `{"a":[1,2,3],"b":true}`,
+			patch:    `[{"op":"remove","path":"/a/0"},{"op":"remove","path":"/b"}]`,
+			expected: `{"a":[2,3]}`,
+		},
+		"replace": {
+			doc:      `{"a":{"b":"c"}}`,
+			patch:    `[{"op":"replace","path":"/a/b","value":null}]`,
+			expected: `{"a":{"b":null}}`,
+		},
+		"replace whole document": {
+			doc:      `{"a":1}`,
+			patch:    `[{"op":"replace","path":"","value":[1]}]`,
+			expected: `[1]`,
+		},
+		"move": {
+			doc:      `{"a":{"b":1},"c":{}}`,
+			patch:    `[{"op":"move","from":"/a/b","path":"/c/d"}]`,
+			expected: `{"a":{},"c":{"d":1}}`,
+		},
+		"copy": {
+			doc:      `{"a":{"b":1}}`,
+			patch:    `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			expected: `{"a":{"b":1},"c":{"b":2}}`,
+		},
+		"escaped pointer": {
+			doc:      `{"a/b":{"c~d":1}}`,
+			patch:    `[{"op":"replace","path":"/a~1b/c~0d","value":2}]`,
+			expected: `{"a/b":{"c~d":2}}`,
+		},
+		"test passes": {
+			doc:      `{"a":1.0,"b":"x"}`,
+			patch:    `[{"op":"test","path":"/a","value":1},{"op":"add","path":"/c","value":12345678901234567890}]`,
+			expected: `{"a":1.0,"b":"x","c":12345678901234567890}`,
+		},
+		"test fails": {
+			doc:   `{"a":1}`,
+			patch: `[{"op":"test","path":"/a","value":2}]`,
+			err:   `operation 0 (test): value at "/a" doesn't match`,
+		},
+		"missing member": {
+			doc:   `{"a":1}`,
+			patch: `[{"op":"replace","path":"/b","value":2}]`,
+			err:   `operation 0 (replace): member "b" doesn't exist`,
+		},
+		"array index out of bounds": {
+			doc:   `[1]`,
+			patch: `[{"op":"add","path":"/2","value":2}]`,
+			err:   `operation 0 (add): array index 2 out of bounds`,
+		},
+		"move into child": {
+			doc:   `{"a":{}}`,
+			patch: `[{"op":"move","from":"/a","path":"/a/b"}]`,
+			err:   `operation 0 (move): cannot move a value into one of its children`,
+		},
+		"unknown operation": {
+			doc:   `{}`,
+			patch: `[{"op":"frob","path":"/a"}]`,
+			err:   `operation 0 (frob): unknown operation "frob"`,
+		},
+		"invalid document": {
+			doc:   `not json`,
+			patch: `[]`,
+			err:   `invalid document: invalid character 'o' in literal null (expecting 'u')`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := ApplyPatch([]byte(tc.doc), []byte(tc.patch), 1024)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	cases := map[string]struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		"merge": {
+			doc:      `{"a":"b","c":{"d":"e","f":"g"}}`,
+			patch:    `{"a":"z","c":{"f":null}}`,
+			expected: `{"a":"z","c":{"d":"e"}}`,
+		},
+		"replace non-object": {
+			doc:      `{"a":[1,2]}`,
+			patch:    `{"a":{"b":1}}`,
+			expected: `{"a":{"b":1}}`,
+		},
+		"empty document": {
+			doc:      ``,
+			patch:    `{"a":1}`,
+			expected: `{"a":1}`,
+		},
+		"patch isn't an object": {
+			doc:      `{"a":1}`,
+			patch:    `"b"`,
+			expected: `"b"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := MergePatch([]byte(tc.doc), []byte(tc.patch), 1024)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	out, err := Apply([]byte(`{"a":1}`), []byte(` [{"op":"add","path":"/b","value":2}]`), 1024)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1,"b":2}`, string(out))
+
+	out, err = Apply([]byte(`{"a":1}`), []byte(`{"a":null,"b":2}`), 1024)
+	require.NoError(t, err)
+	require.Equal(t, `{"b":2}`, string(out))
+}
+
+func TestApply_Limits(t *testing.T) {
+	// Every copy of the whole document into one of its members doubles it.
+	var ops []string
+	for i := 0; i < 64; i++ {
+		ops = append(ops, fmt.Sprintf(`{"op":"copy","from":"","path":"/a%d"}`, i))
+	}
+	patch := "[" + strings.Join(ops, ",") + "]"
+	_, err := Apply([]byte(`{"x":"0123456789"}`), []byte(patch), 1<<20)
+	require.ErrorIs(t, err, ErrTooLarge)
+	require.Contains(t, err.Error(), "operation 15 (copy)")
+
+	// The final document is checked exactly.
+	_, err = Apply([]byte(`{}`), []byte(`{"a":"0123456789"}`), 16)
+	require.ErrorIs(t, err, ErrTooLarge)
+	out, err := Apply([]byte(`{}`), []byte(`{"a":"0123456789"}`), 18)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"0123456789"}`, string(out))
+
+	ops = nil
+	for i := 0; i <= MaxOperations; i++ {
+		ops = append(ops, `{"op":"test","path":"","value":{}}`)
+	}
+	_, err = Apply([]byte(`{}`), []byte("["+strings.Join(ops, ",")+"]"), 1024)
+	require.EqualError(t, err, "invalid patch: too many operations (129 > 128)")
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"reflect"
 	"sync"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/armon/go-metrics/prometheus"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
+	prom "github.com/prometheus/client_golang/prometheus"
 
 	"github.com/hashicorp/consul/lib/retry"
 )
@@ -188,6 +190,26 @@ type TelemetryConfig struct {
 	// hcl: telemetry { metrics_prefix = string }
 	MetricsPrefix string `json:"metrics_prefix,omitempty" mapstructure:"metrics_prefix"`
 
+	// RPCServiceLabels adds the name of the service a server RPC request is
+	// made for as the "service" label of the RPC request metrics.
+	//
+	// hcl: telemetry { rpc_service_labels = (true|false) }
+	RPCServiceLabels bool `json:"rpc_service_labels,omitempty" mapstructure:"rpc_service_labels"`
+
+	// RPCPeerLabels adds the name of the peer a server RPC request is made
+	// for as the "peer" label of the RPC request metrics.
+	//
+	// hcl: telemetry { rpc_peer_labels = (true|false) }
+	RPCPeerLabels bool `json:"rpc_peer_labels,omitempty" mapstructure:"rpc_peer_labels"`
+
+	// RPCLabelsMaxValues is the maximum number of distinct values of the
+	// "service" and "peer" labels, the values seen once it is reached are
+	// reported as "_other".
+	// Default: 100
+	//
+	// hcl: telemetry { rpc_labels_max_values = int }
+	RPCLabelsMaxValues int `json:"rpc_labels_max_values,omitempty" mapstructure:"rpc_labels_max_values"`
+
 	// StatsdAddr is the address of a statsd instance. If provided,
 	// metrics will be sent to that instance.
 	//
@@ -200,6 +222,57 @@ type TelemetryConfig struct {
 	// hcl: telemetry { statsite_address = string }
 	StatsiteAddr string `json:"statsite_address,omitempty" mapstructure:"statsite_address"`
 
+	// OTLPEndpoint is the endpoint of an OpenTelemetry collector. If
+	// provided, metrics will be exported to that collector with OTLP: a
+	// host:port with the gRPC protocol, or a URL with the HTTP protocol.
+	//
+	// hcl: telemetry { otlp_endpoint = string }
+	OTLPEndpoint string `json:"otlp_endpoint,omitempty" mapstructure:"otlp_endpoint"`
+
+	// OTLPProtocol is the OTLP protocol used to export metrics, either
+	// "grpc" or "http/protobuf".
+	// Default: "grpc"
+	//
+	// hcl: telemetry { otlp_protocol = string }
+	OTLPProtocol string `json:"otlp_protocol,omitempty" mapstructure:"otlp_protocol"`
+
+	// OTLPInsecure disables TLS when the OTLP endpoint doesn't specify a
+	// scheme.
+	//
+	// hcl: telemetry { otlp_insecure = (true|false) }
+	OTLPInsecure bool `json:"otlp_insecure,omitempty" mapstructure:"otlp_insecure"`
+
+	// OTLPHeaders are the headers sent with each export, such as the
+	// credentials of the collector.
+	//
+	// hcl: telemetry { otlp_headers = map[string]string }
+	OTLPHeaders map[string]string `json:"otlp_headers,omitempty" mapstructure:"otlp_headers"`
+
+	// OTLPExportInterval is the interval at which metrics are exported.
+	// Default: 60s
+	//
+	// hcl: telemetry { otlp_export_interval = "duration" }
+	OTLPExportInterval time.Duration `json:"otlp_export_interval,omitempty" mapstructure:"otlp_export_interval"`
+
+	// OTLPTemporality is the aggregation temporality of the counters and
+	// samples, either "cumulative" or "delta".
+	// Default: "cumulative"
+	//
+	// hcl: telemetry { otlp_temporality = string }
+	OTLPTemporality string `json:"otlp_temporality,omitempty" mapstructure:"otlp_temporality"`
+
+	// OTLPResourceAttributes are added to the attributes of the resource
+	// the metrics are exported for, "service.name" and "host.name".
+	//
+	// hcl: telemetry { otlp_resource_attributes = map[string]string }
+	OTLPResourceAttributes map[string]string `json:"otlp_resource_attributes,omitempty" mapstructure:"otlp_resource_attributes"`
+
+	// OTLPLogs exports the logs of the agent to the OTLP endpoint as well,
+	// at the levels enabled by the log level.
+	//
+	// hcl: telemetry { otlp_logs = (true|false) }
+	OTLPLogs bool `json:"otlp_logs,omitempty" mapstructure:"otlp_logs"`
+
 	// PrometheusOpts provides configuration for the PrometheusSink. Currently the only configuration
 	// we acquire from hcl is the retention time. We also use definition slices that are set in agent setup
 	// before being passed to InitTelemmetry.
@@ -218,6 +291,23 @@ type MetricsConfig struct {
 	Handler  MetricsHandler
 	mu       sync.Mutex
 	cancelFn context.CancelFunc
+
+	// sink is the sink installed in the global metrics, which forwards to the
+	// sinks of the current configuration. It is nil when the config wasn't
+	// created by InitTelemetry, in which case it can't be reloaded.
+	sink    *reloadableSink
+	memSink metrics.MetricSink
+	// hostname is the hostname given to the sinks.
+	hostname string
+	// running holds the sinks of the current configuration by kind.
+	running map[string]runningSink
+	logger  hclog.Logger
+
+	// logs is the logger whose logs are exported by logSink, it is nil if
+	// the logger given to InitTelemetry doesn't support sinks.
+	logs          hclog.InterceptLogger
+	logSink       *otlpLogSink
+	logSinkConfig *otlpConfig
 }
 
 func (cfg *MetricsConfig) Cancel() {
@@ -229,7 +319,288 @@ func (cfg *MetricsConfig) Cancel() {
 	}
 }
 
-func statsiteSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error) {
+// Reload re-creates the sinks whose configuration changed, and shuts down the
+// ones they replace. The sinks whose configuration didn't change are kept, so
+// that the Prometheus metrics aren't reset for instance. A sink which can't be
+// created is reported, and the sink it would replace is kept; it is retried
+// in the background if the error is retriable and retry_failed_connection is
+// set.
+//
+// The metrics prefix and the hostname settings are only applied by
+// InitTelemetry, changing them requires a restart.
+func (cfg *MetricsConfig) Reload(telemetry TelemetryConfig) error {
+	if cfg == nil || cfg.sink == nil {
+		return nil
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	// A pending retry would overwrite the new sinks with the previous
+	// configuration.
+	if cfg.cancelFn != nil {
+		cfg.cancelFn()
+		cfg.cancelFn = nil
+	}
+
+	errs := cfg.updateSinks(telemetry)
+	if errs != nil && isRetriableError(errs) && telemetry.RetryFailedConfiguration {
+		cfg.logger.Warn("failed configure sinks", "error", multierror.Flatten(errs))
+		cfg.retry(telemetry)
+		return nil
+	}
+	return errs
+}
+
+// updateSinks creates the sinks of the configuration which aren't running
+// yet, and swaps them in. The sinks which can't be created are reported, and
+// the sinks they would replace are kept. cfg.mu must be held.
+func (cfg *MetricsConfig) updateSinks(telemetry TelemetryConfig) error {
+	running := make(map[string]runningSink, len(sinkKinds))
+	var replaced []runningSink
+	var errs error
+
+	for _, kind := range sinkKinds {
+		conf := kind.config(telemetry)
+		old, ok := cfg.running[kind.name]
+		if ok && reflect.DeepEqual(old.config, conf) {
+			running[kind.name] = old
+			continue
+		}
+
+		if ok && kind.name == prometheusSinkName {
+			// The new sink can't be registered alongside the old one.
+			old.close()
+		}
+		s, err := kind.create(telemetry, cfg.hostname, cfg.logger)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			if ok {
+				old.register()
+				running[kind.name] = old
+			}
+			continue
+		}
+		if ok && kind.name != prometheusSinkName {
+			replaced = append(replaced, old)
+		}
+		if s != nil {
+			running[kind.name] = runningSink{
+				config:     conf,
+				sink:       s,
+				registerer: telemetry.PrometheusOpts.Registerer,
+			}
+		}
+	}
+
+	var sinks metrics.FanoutSink
+	for _, kind := range sinkKinds {
+		if r, ok := running[kind.name]; ok {
+			sinks = append(sinks, r.sink)
+		}
+	}
+	sinks = append(sinks, cfg.memSink)
+	cfg.sink.swap(sinks)
+	cfg.running = running
+
+	// The sinks replaced receive no more metrics once swapped out.
+	for _, r := range replaced {
+		r.close()
+	}
+
+	if err := cfg.updateLogSink(telemetry); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+// updateLogSink replaces the sink exporting the logs if its configuration
+// changed. The current sink is kept if the new one can't be created. cfg.mu
+// must be held.
+func (cfg *MetricsConfig) updateLogSink(telemetry TelemetryConfig) error {
+	conf := newOTLPLogsConfig(telemetry)
+	if cfg.logs == nil || reflect.DeepEqual(conf, cfg.logSinkConfig) {
+		return nil
+	}
+
+	var sink *otlpLogSink
+	if conf != nil {
+		var err error
+		sink, err = newOTLPLogSink(conf, cfg.hostname, levelEnabled(cfg.logs), cfg.logger)
+		if err != nil {
+			return err
+		}
+	}
+	cfg.closeLogSink()
+	if sink != nil {
+		cfg.logs.RegisterSink(sink)
+	}
+	cfg.logSink, cfg.logSinkConfig = sink, conf
+	return nil
+}
+
+// ShutdownLogSink stops exporting the logs, once the pending ones are
+// exported.
+func (cfg *MetricsConfig) ShutdownLogSink() {
+	if cfg == nil {
+		return
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.closeLogSink()
+}
+
+// closeLogSink deregisters and shuts down the sink exporting the logs. cfg.mu
+// must be held.
+func (cfg *MetricsConfig) closeLogSink() {
+	if cfg.logSink != nil {
+		cfg.logs.DeregisterSink(cfg.logSink)
+		cfg.logSink.Shutdown()
+		cfg.logSink, cfg.logSinkConfig = nil, nil
+	}
+}
+
+// levelEnabled returns whether a level is enabled by the current level of
+// logger, which changes when the configuration is reloaded.
+func levelEnabled(logger hclog.Logger) func(hclog.Level) bool {
+	return func(level hclog.Level) bool {
+		switch level {
+		case hclog.Trace:
+			return logger.IsTrace()
+		case hclog.Debug:
+			return logger.IsDebug()
+		case hclog.Info:
+			return logger.IsInfo()
+		case hclog.Warn:
+			return logger.IsWarn()
+		default:
+			return logger.IsError()
+		}
+	}
+}
+
+// retry retries updating the sinks in the background until it succeeds or is
+// cancelled. cfg.mu must be held.
+func (cfg *MetricsConfig) retry(telemetry TelemetryConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg.cancelFn = cancel
+
+	go func() {
+		waiter := &retry.Waiter{
+			MaxWait: 5 * time.Minute,
+		}
+		for {
+			if err := waiter.Wait(ctx); err != nil {
+				cfg.logger.Trace("stop retrying configure metrics sinks")
+				return
+			}
+
+			cfg.logger.Warn("retrying configure metric sinks", "retries", waiter.Failures())
+			cfg.mu.Lock()
+			if ctx.Err() != nil {
+				cfg.mu.Unlock()
+				return
+			}
+			err := cfg.updateSinks(telemetry)
+			cfg.mu.Unlock()
+			if err == nil {
+				cfg.logger.Info("successfully configured metrics sinks")
+				return
+			}
+			cfg.logger.Error("failed configure sinks", "error", multierror.Flatten(err))
+		}
+	}()
+}
+
+// runningSink is a sink created for a configuration.
+type runningSink struct {
+	// config is the part of the configuration the sink was created with.
+	config interface{}
+	sink   metrics.MetricSink
+	// registerer is the Prometheus registerer of the configuration.
+	registerer prom.Registerer
+}
+
+// close releases the resources of the sink once it is no longer in use. The
+// sinks which don't support it, such as the DogStatsD sink, are just dropped.
+func (r runningSink) close() {
+	switch s := r.sink.(type) {
+	case *prometheus.PrometheusSink:
+		r.prometheusRegisterer().Unregister(s)
+	case interface{ Shutdown() }:
+		s.Shutdown()
+	}
+}
+
+// register registers a Prometheus sink again after close. It does nothing for
+// the other sinks, which are only closed once replaced.
+func (r runningSink) register() {
+	if s, ok := r.sink.(*prometheus.PrometheusSink); ok {
+		_ = r.prometheusRegisterer().Register(s)
+	}
+}
+
+func (r runningSink) prometheusRegisterer() prom.Registerer {
+	if r.registerer == nil {
+		return prom.DefaultRegisterer
+	}
+	return r.registerer
+}
+
+// reloadableSink forwards the metrics to the sinks of the current
+// configuration. It is installed once in the global metrics, since
+// re-installing them leaks their runtime metrics collector.
+type reloadableSink struct {
+	mu    sync.RWMutex
+	sinks metrics.FanoutSink
+}
+
+// swap replaces the sinks. The previous sinks receive no more metrics once it
+// returns, so they can be shut down.
+func (s *reloadableSink) swap(sinks metrics.FanoutSink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sinks = sinks
+}
+
+func (s *reloadableSink) SetGauge(key []string, val float32) {
+	s.SetGaugeWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.sinks.SetGaugeWithLabels(key, val, labels)
+}
+
+func (s *reloadableSink) EmitKey(key []string, val float32) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.sinks.EmitKey(key, val)
+}
+
+func (s *reloadableSink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.sinks.IncrCounterWithLabels(key, val, labels)
+}
+
+func (s *reloadableSink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.sinks.AddSampleWithLabels(key, val, labels)
+}
+
+func statsiteSink(cfg TelemetryConfig, hostname string, _ hclog.Logger) (metrics.MetricSink, error) {
 	addr := cfg.StatsiteAddr
 	if addr == "" {
 		return nil, nil
@@ -237,7 +608,7 @@ func statsiteSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, err
 	return metrics.NewStatsiteSink(addr)
 }
 
-func statsdSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error) {
+func statsdSink(cfg TelemetryConfig, hostname string, _ hclog.Logger) (metrics.MetricSink, error) {
 	addr := cfg.StatsdAddr
 	if addr == "" {
 		return nil, nil
@@ -245,7 +616,7 @@ func statsdSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error
 	return metrics.NewStatsdSink(addr)
 }
 
-func dogstatdSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error) {
+func dogstatdSink(cfg TelemetryConfig, hostname string, _ hclog.Logger) (metrics.MetricSink, error) {
 	addr := cfg.DogstatsdAddr
 	if addr == "" {
 		return nil, nil
@@ -258,7 +629,7 @@ func dogstatdSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, err
 	return sink, nil
 }
 
-func prometheusSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error) {
+func prometheusSink(cfg TelemetryConfig, hostname string, _ hclog.Logger) (metrics.MetricSink, error) {
 
 	if cfg.PrometheusOpts.Expiration.Nanoseconds() < 1 {
 		return nil, nil
@@ -271,11 +642,11 @@ func prometheusSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, e
 	return sink, nil
 }
 
-func circonusSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, error) {
+func circonusConfig(cfg TelemetryConfig) *circonus.Config {
 	token := cfg.CirconusAPIToken
 	url := cfg.CirconusSubmissionURL
 	if token == "" && url == "" {
-		return nil, nil
+		return nil
 	}
 
 	conf := &circonus.Config{}
@@ -304,6 +675,14 @@ func circonusSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, err
 	if conf.CheckManager.Check.SearchTag == "" {
 		conf.CheckManager.Check.SearchTag = "service:consul"
 	}
+	return conf
+}
+
+func circonusSink(cfg TelemetryConfig, hostname string, _ hclog.Logger) (metrics.MetricSink, error) {
+	conf := circonusConfig(cfg)
+	if conf == nil {
+		return nil, nil
+	}
 
 	sink, err := circonus.NewCirconusSink(conf)
 	if err != nil {
@@ -313,47 +692,61 @@ func circonusSink(cfg TelemetryConfig, hostname string) (metrics.MetricSink, err
 	return sink, nil
 }
 
-func configureSinks(cfg TelemetryConfig, memSink metrics.MetricSink) (metrics.FanoutSink, error) {
+const prometheusSinkName = "prometheus"
+
+// sinkKinds are the kinds of sinks, in the order they are given the metrics.
+var sinkKinds = []struct {
+	name string
+	// config returns the part of the configuration the sink is created with.
+	config func(TelemetryConfig) interface{}
+	create func(cfg TelemetryConfig, hostname string, logger hclog.Logger) (metrics.MetricSink, error)
+}{
+	{
+		name:   "statsite",
+		config: func(cfg TelemetryConfig) interface{} { return cfg.StatsiteAddr },
+		create: statsiteSink,
+	},
+	{
+		name:   "statsd",
+		config: func(cfg TelemetryConfig) interface{} { return cfg.StatsdAddr },
+		create: statsdSink,
+	},
+	{
+		name:   "dogstatsd",
+		config: func(cfg TelemetryConfig) interface{} { return []interface{}{cfg.DogstatsdAddr, cfg.DogstatsdTags} },
+		create: dogstatdSink,
+	},
+	{
+		name:   "circonus",
+		config: func(cfg TelemetryConfig) interface{} { return circonusConfig(cfg) },
+		create: circonusSink,
+	},
+	{
+		name:   "otlp",
+		config: func(cfg TelemetryConfig) interface{} { return newOTLPConfig(cfg) },
+		create: otlpSink,
+	},
+	{
+		name:   prometheusSinkName,
+		config: func(cfg TelemetryConfig) interface{} { return cfg.PrometheusOpts.Expiration },
+		create: prometheusSink,
+	},
+}
+
+func newMetricsConfig(cfg TelemetryConfig) *metrics.Config {
 	metricsConf := metrics.DefaultConfig(cfg.MetricsPrefix)
 	metricsConf.EnableHostname = !cfg.DisableHostname
 	metricsConf.FilterDefault = cfg.FilterDefault
 	metricsConf.AllowedPrefixes = cfg.AllowedPrefixes
 	metricsConf.BlockedPrefixes = cfg.BlockedPrefixes
-
-	var sinks metrics.FanoutSink
-	var errors error
-	addSink := func(fn func(TelemetryConfig, string) (metrics.MetricSink, error)) {
-		s, err := fn(cfg, metricsConf.HostName)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-			return
-		}
-		if s != nil {
-			sinks = append(sinks, s)
-		}
-	}
-
-	addSink(statsiteSink)
-	addSink(statsdSink)
-	addSink(dogstatdSink)
-	addSink(circonusSink)
-	addSink(circonusSink)
-	addSink(prometheusSink)
-
-	if len(sinks) > 0 {
-		sinks = append(sinks, memSink)
-		metrics.NewGlobal(metricsConf, sinks)
-	} else {
-		metricsConf.EnableHostname = false
-		metrics.NewGlobal(metricsConf, memSink)
-	}
-	return sinks, errors
+	return metricsConf
 }
 
 // InitTelemetry configures go-metrics based on map of telemetry config
 // values as returned by Runtimecfg.Config().
 // InitTelemetry retries configurating the sinks in case error is retriable
-// and retry_failed_connection is set to true.
+// and retry_failed_connection is set to true. The sinks can be changed
+// later with MetricsConfig.Reload.
 func InitTelemetry(cfg TelemetryConfig, logger hclog.Logger) (*MetricsConfig, error) {
 	if cfg.Disable {
 		return nil, nil
@@ -362,41 +755,33 @@ func InitTelemetry(cfg TelemetryConfig, logger hclog.Logger) (*MetricsConfig, er
 	memSink := metrics.NewInmemSink(10*time.Second, time.Minute)
 	metrics.DefaultInmemSignal(memSink)
 
+	metricsConf := newMetricsConfig(cfg)
 	metricsConfig := &MetricsConfig{
-		Handler: memSink,
+		Handler:  memSink,
+		sink:     &reloadableSink{sinks: metrics.FanoutSink{memSink}},
+		memSink:  memSink,
+		hostname: metricsConf.HostName,
+		logger:   logger,
 	}
+	metricsConfig.logs, _ = logger.(hclog.InterceptLogger)
 
-	var cancel context.CancelFunc
-	var ctx context.Context
-	retryWithBackoff := func() {
-		waiter := &retry.Waiter{
-			MaxWait: 5 * time.Minute,
-		}
-		for {
-			logger.Warn("retrying configure metric sinks", "retries", waiter.Failures())
-			_, err := configureSinks(cfg, memSink)
-			if err == nil {
-				logger.Info("successfully configured metrics sinks")
-				return
-			}
-			logger.Error("failed configure sinks", "error", multierror.Flatten(err))
+	metricsConfig.mu.Lock()
+	defer metricsConfig.mu.Unlock()
 
-			if err := waiter.Wait(ctx); err != nil {
-				logger.Trace("stop retrying configure metrics sinks")
-			}
-		}
+	errs := metricsConfig.updateSinks(cfg)
+	if len(metricsConfig.running) == 0 {
+		// The hostname is only useful to tell apart the metrics sent to the
+		// sinks by several agents.
+		metricsConf.EnableHostname = false
 	}
+	metrics.NewGlobal(metricsConf, metricsConfig.sink)
 
-	if _, errs := configureSinks(cfg, memSink); errs != nil {
+	if errs != nil {
 		if isRetriableError(errs) && cfg.RetryFailedConfiguration {
 			logger.Warn("failed configure sinks", "error", multierror.Flatten(errs))
-			ctx, cancel = context.WithCancel(context.Background())
-
-			metricsConfig.mu.Lock()
-			metricsConfig.cancelFn = cancel
-			metricsConfig.mu.Unlock()
-			go retryWithBackoff()
+			metricsConfig.retry(cfg)
 		} else {
+			metricsConfig.closeLogSink()
 			return nil, errs
 		}
 	}
@@ -0,0 +1,561 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// OTLPProtocolGRPC exports the metrics with OTLP/gRPC.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP exports the metrics with OTLP/HTTP, encoded in
+	// protobuf.
+	OTLPProtocolHTTP = "http/protobuf"
+
+	// OTLPTemporalityCumulative exports the counters and samples accumulated
+	// since the sink was created.
+	OTLPTemporalityCumulative = "cumulative"
+	// OTLPTemporalityDelta exports the counters and samples accumulated since
+	// the previous export.
+	OTLPTemporalityDelta = "delta"
+
+	otlpScopeName = "consul"
+)
+
+// otlpSignal identifies the kind of telemetry data exported, which is sent to
+// a different gRPC method or HTTP path of the collector.
+type otlpSignal struct {
+	method string
+	path   string
+}
+
+var (
+	otlpMetricsSignal = otlpSignal{
+		method: "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+		path:   "/v1/metrics",
+	}
+	otlpLogsSignal = otlpSignal{
+		method: "/opentelemetry.proto.collector.logs.v1.LogsService/Export",
+		path:   "/v1/logs",
+	}
+)
+
+// otlpConfig is the part of the configuration the OTLP sink is created with.
+type otlpConfig struct {
+	Endpoint           string
+	Protocol           string
+	Insecure           bool
+	Headers            map[string]string
+	ExportInterval     time.Duration
+	Temporality        string
+	ResourceAttributes map[string]string
+}
+
+func newOTLPConfig(cfg TelemetryConfig) *otlpConfig {
+	if cfg.OTLPEndpoint == "" {
+		return nil
+	}
+	conf := &otlpConfig{
+		Endpoint:           cfg.OTLPEndpoint,
+		Protocol:           cfg.OTLPProtocol,
+		Insecure:           cfg.OTLPInsecure,
+		Headers:            cfg.OTLPHeaders,
+		ExportInterval:     cfg.OTLPExportInterval,
+		Temporality:        cfg.OTLPTemporality,
+		ResourceAttributes: cfg.OTLPResourceAttributes,
+	}
+	if conf.Protocol == "" {
+		conf.Protocol = OTLPProtocolGRPC
+	}
+	if conf.ExportInterval <= 0 {
+		conf.ExportInterval = time.Minute
+	}
+	if conf.Temporality == "" {
+		conf.Temporality = OTLPTemporalityCumulative
+	}
+	return conf
+}
+
+func otlpSink(cfg TelemetryConfig, hostname string, logger hclog.Logger) (metrics.MetricSink, error) {
+	conf := newOTLPConfig(cfg)
+	if conf == nil {
+		return nil, nil
+	}
+	sink, err := newOTLPMetricsSink(conf, hostname, logger)
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// otlpExporter sends the export requests of a signal to the collector.
+//
+// The requests are the MetricsData and LogsData messages of the OTLP
+// protocol, which are defined to be wire compatible with the
+// ExportMetricsServiceRequest and ExportLogsServiceRequest messages of the
+// collector services. Their packages aren't imported since they depend on
+// grpc-gateway.
+type otlpExporter interface {
+	export(ctx context.Context, req proto.Message) error
+	close() error
+}
+
+func newOTLPExporter(conf *otlpConfig, signal otlpSignal) (otlpExporter, error) {
+	switch conf.Protocol {
+	case OTLPProtocolGRPC:
+		return newOTLPGRPCExporter(conf, signal)
+	case OTLPProtocolHTTP:
+		return newOTLPHTTPExporter(conf, signal)
+	default:
+		return nil, fmt.Errorf("invalid OTLP protocol %q, must be %q or %q",
+			conf.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+}
+
+// otlpMetricsSink aggregates the metrics and exports them periodically to an
+// OpenTelemetry collector with OTLP. The gauges are exported with their last
+// value, the counters as monotonic sums and the samples as histograms
+// without buckets, whose count, sum, min and max are known.
+type otlpMetricsSink struct {
+	conf     *otlpConfig
+	exporter otlpExporter
+	logger   hclog.Logger
+	resource *resourcepb.Resource
+
+	mu sync.Mutex
+	// start is the start time of the counters and samples: the creation of
+	// the sink with the cumulative temporality, or the previous export with
+	// the delta temporality.
+	start    time.Time
+	gauges   map[string]*otlpPoint
+	counters map[string]*otlpPoint
+	samples  map[string]*otlpPoint
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// otlpPoint is the aggregated value of a metric with a set of labels.
+type otlpPoint struct {
+	name   string
+	labels []metrics.Label
+
+	// value is the last value of a gauge, or the sum of a counter.
+	value float64
+
+	// count, sum, min and max are the aggregation of the samples.
+	count    uint64
+	sum      float64
+	min, max float64
+}
+
+func newOTLPMetricsSink(conf *otlpConfig, hostname string, logger hclog.Logger) (*otlpMetricsSink, error) {
+	switch conf.Temporality {
+	case OTLPTemporalityCumulative, OTLPTemporalityDelta:
+	default:
+		return nil, fmt.Errorf("invalid OTLP temporality %q, must be %q or %q",
+			conf.Temporality, OTLPTemporalityCumulative, OTLPTemporalityDelta)
+	}
+
+	exporter, err := newOTLPExporter(conf, otlpMetricsSignal)
+	if err != nil {
+		return nil, err
+	}
+
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	s := &otlpMetricsSink{
+		conf:     conf,
+		exporter: exporter,
+		logger:   logger.Named("otlp"),
+		resource: otlpResource(otlpResourceAttributes(conf, hostname)),
+		start:    time.Now(),
+		gauges:   make(map[string]*otlpPoint),
+		counters: make(map[string]*otlpPoint),
+		samples:  make(map[string]*otlpPoint),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *otlpMetricsSink) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.conf.ExportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stopCh:
+			// Export the metrics aggregated since the last export.
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush exports the aggregated metrics.
+func (s *otlpMetricsSink) flush() {
+	req := s.collect(time.Now())
+	if req == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.conf.ExportInterval)
+	defer cancel()
+	if err := s.exporter.export(ctx, req); err != nil {
+		s.logger.Warn("failed to export metrics", "endpoint", s.conf.Endpoint, "error", err)
+	}
+}
+
+// Shutdown exports the remaining metrics and closes the connection to the
+// collector.
+func (s *otlpMetricsSink) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		<-s.doneCh
+		if err := s.exporter.close(); err != nil {
+			s.logger.Warn("failed to close the OTLP exporter", "error", err)
+		}
+	})
+}
+
+func (s *otlpMetricsSink) SetGauge(key []string, val float32) {
+	s.SetGaugeWithLabels(key, val, nil)
+}
+
+func (s *otlpMetricsSink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.point(s.gauges, key, labels)
+	p.value = float64(val)
+}
+
+// EmitKey is not supported, like with the Prometheus sink.
+func (s *otlpMetricsSink) EmitKey(key []string, val float32) {
+}
+
+func (s *otlpMetricsSink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+func (s *otlpMetricsSink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.point(s.counters, key, labels)
+	p.value += float64(val)
+}
+
+func (s *otlpMetricsSink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+func (s *otlpMetricsSink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.point(s.samples, key, labels)
+	v := float64(val)
+	if p.count == 0 || v < p.min {
+		p.min = v
+	}
+	if p.count == 0 || v > p.max {
+		p.max = v
+	}
+	p.count++
+	p.sum += v
+}
+
+// point returns the point of the metric in points, creating it if needed.
+// s.mu must be held.
+func (s *otlpMetricsSink) point(points map[string]*otlpPoint, key []string, labels []metrics.Label) *otlpPoint {
+	name := strings.Join(key, ".")
+	id := name
+	for _, l := range labels {
+		id += ";" + l.Name + "=" + l.Value
+	}
+	p, ok := points[id]
+	if !ok {
+		p = &otlpPoint{name: name, labels: labels}
+		points[id] = p
+	}
+	return p
+}
+
+// collect returns the ExportMetricsServiceRequest of the aggregated metrics,
+// or nil if there are none. With the delta temporality, the counters and
+// samples are reset.
+func (s *otlpMetricsSink) collect(now time.Time) *metricspb.MetricsData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.gauges) == 0 && len(s.counters) == 0 && len(s.samples) == 0 {
+		return nil
+	}
+
+	temporality := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	if s.conf.Temporality == OTLPTemporalityDelta {
+		temporality = metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	}
+	start := uint64(s.start.UnixNano())
+	ts := uint64(now.UnixNano())
+
+	var exported []*metricspb.Metric
+	for _, p := range sortedOTLPPoints(s.gauges) {
+		exported = append(exported, &metricspb.Metric{
+			Name: p.name,
+			Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+				DataPoints: []*metricspb.NumberDataPoint{otlpNumberPoint(p, 0, ts)},
+			}},
+		})
+	}
+	for _, p := range sortedOTLPPoints(s.counters) {
+		exported = append(exported, &metricspb.Metric{
+			Name: p.name,
+			Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				DataPoints:             []*metricspb.NumberDataPoint{otlpNumberPoint(p, start, ts)},
+				AggregationTemporality: temporality,
+				IsMonotonic:            true,
+			}},
+		})
+	}
+	for _, p := range sortedOTLPPoints(s.samples) {
+		exported = append(exported, &metricspb.Metric{
+			Name: p.name,
+			Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+				DataPoints:             []*metricspb.HistogramDataPoint{otlpHistogramPoint(p, start, ts)},
+				AggregationTemporality: temporality,
+			}},
+		})
+	}
+
+	if s.conf.Temporality == OTLPTemporalityDelta {
+		s.counters = make(map[string]*otlpPoint)
+		s.samples = make(map[string]*otlpPoint)
+		s.start = now
+	}
+
+	return &metricspb.MetricsData{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: s.resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   otlpScope(),
+				Metrics: exported,
+			}},
+		}},
+	}
+}
+
+func sortedOTLPPoints(points map[string]*otlpPoint) []*otlpPoint {
+	ids := make([]string, 0, len(points))
+	for id := range points {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	sorted := make([]*otlpPoint, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, points[id])
+	}
+	return sorted
+}
+
+// otlpResourceAttributes returns the attributes describing the agent the
+// telemetry data comes from.
+func otlpResourceAttributes(conf *otlpConfig, hostname string) map[string]string {
+	attributes := map[string]string{"service.name": "consul"}
+	if hostname != "" {
+		attributes["host.name"] = hostname
+	}
+	for k, v := range conf.ResourceAttributes {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+func otlpResource(attributes map[string]string) *resourcepb.Resource {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	resource := &resourcepb.Resource{}
+	for _, k := range keys {
+		resource.Attributes = append(resource.Attributes, otlpKeyValue(k, attributes[k]))
+	}
+	return resource
+}
+
+func otlpScope() *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: otlpScopeName}
+}
+
+// otlpKeyValue returns a KeyValue with a string value.
+func otlpKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func otlpLabels(labels []metrics.Label) []*commonpb.KeyValue {
+	var attributes []*commonpb.KeyValue
+	for _, l := range labels {
+		attributes = append(attributes, otlpKeyValue(l.Name, l.Value))
+	}
+	return attributes
+}
+
+// otlpNumberPoint returns the NumberDataPoint of a gauge or a counter,
+// without a start time if start is 0.
+func otlpNumberPoint(p *otlpPoint, start, ts uint64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:        otlpLabels(p.labels),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      ts,
+		Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: p.value},
+	}
+}
+
+// otlpHistogramPoint returns the HistogramDataPoint of the samples, with a
+// single bucket.
+func otlpHistogramPoint(p *otlpPoint, start, ts uint64) *metricspb.HistogramDataPoint {
+	point := &metricspb.HistogramDataPoint{
+		Attributes:        otlpLabels(p.labels),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      ts,
+		Count:             p.count,
+		Sum:               proto.Float64(p.sum),
+		BucketCounts:      []uint64{p.count},
+	}
+	if p.count > 0 {
+		point.Min = proto.Float64(p.min)
+		point.Max = proto.Float64(p.max)
+	}
+	return point
+}
+
+// otlpGRPCExporter exports a signal with OTLP/gRPC.
+type otlpGRPCExporter struct {
+	conn    *grpc.ClientConn
+	method  string
+	headers metadata.MD
+}
+
+func newOTLPGRPCExporter(conf *otlpConfig, signal otlpSignal) (*otlpGRPCExporter, error) {
+	target := conf.Endpoint
+	plaintext := conf.Insecure
+	if u, err := url.Parse(target); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		target = u.Host
+		plaintext = u.Scheme == "http"
+	}
+
+	creds := credentials.NewTLS(&tls.Config{})
+	if plaintext {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the OTLP endpoint %q: %w", conf.Endpoint, err)
+	}
+	return &otlpGRPCExporter{conn: conn, method: signal.method, headers: metadata.New(conf.Headers)}, nil
+}
+
+func (e *otlpGRPCExporter) export(ctx context.Context, req proto.Message) error {
+	ctx = metadata.NewOutgoingContext(ctx, e.headers)
+	// The partial success reported by the collector is ignored, the response
+	// is decoded as an empty message.
+	return e.conn.Invoke(ctx, e.method, req, &emptypb.Empty{})
+}
+
+func (e *otlpGRPCExporter) close() error {
+	return e.conn.Close()
+}
+
+// otlpHTTPExporter exports a signal with OTLP/HTTP.
+type otlpHTTPExporter struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func newOTLPHTTPExporter(conf *otlpConfig, signal otlpSignal) (*otlpHTTPExporter, error) {
+	endpoint := conf.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https://"
+		if conf.Insecure {
+			scheme = "http://"
+		}
+		endpoint = scheme + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", conf.Endpoint, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = signal.path
+	}
+	return &otlpHTTPExporter{
+		client:  &http.Client{},
+		url:     u.String(),
+		headers: conf.Headers,
+	}, nil
+}
+
+func (e *otlpHTTPExporter) export(ctx context.Context, req proto.Message) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range e.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code %d from %s", resp.StatusCode, e.url)
+	}
+	return nil
+}
+
+func (e *otlpHTTPExporter) close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
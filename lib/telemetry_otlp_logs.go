@@ -0,0 +1,270 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const (
+	// otlpLogsFlushInterval is the maximum time a log record waits before
+	// being exported.
+	otlpLogsFlushInterval = 5 * time.Second
+
+	// otlpLogsBatchSize is the number of log records which triggers an
+	// export before the end of the interval.
+	otlpLogsBatchSize = 512
+
+	// otlpLogsMaxPending is the maximum number of log records waiting to be
+	// exported. The records logged once it is reached are dropped, so that
+	// an unreachable collector doesn't grow the memory of the agent.
+	otlpLogsMaxPending = 8192
+
+	otlpLogsExportTimeout = 10 * time.Second
+)
+
+// newOTLPLogsConfig returns the part of the configuration the OTLP log sink
+// is created with, or nil if the logs aren't exported.
+func newOTLPLogsConfig(cfg TelemetryConfig) *otlpConfig {
+	if !cfg.OTLPLogs {
+		return nil
+	}
+	conf := newOTLPConfig(cfg)
+	if conf == nil {
+		return nil
+	}
+	// The aggregation of the metrics doesn't apply to the logs.
+	conf.ExportInterval = 0
+	conf.Temporality = ""
+	return conf
+}
+
+// otlpLogSink exports the logs of an hclog.InterceptLogger to an
+// OpenTelemetry collector with OTLP. The records are built when they are
+// logged and exported in batches in the background, the logging never blocks
+// on the collector.
+type otlpLogSink struct {
+	exporter otlpExporter
+	endpoint string
+	resource *resourcepb.Resource
+	// enabled reports whether a level is enabled by the log level of the
+	// agent, the sink receives the logs of all the levels.
+	enabled func(hclog.Level) bool
+
+	// logger reports the export errors. Its own logs aren't exported, since
+	// they would likely fail as well.
+	logger hclog.Logger
+	name   string
+
+	mu      sync.Mutex
+	pending []*logspb.LogRecord
+	dropped uint64
+
+	flushCh  chan struct{}
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func newOTLPLogSink(conf *otlpConfig, hostname string, enabled func(hclog.Level) bool, logger hclog.Logger) (*otlpLogSink, error) {
+	exporter, err := newOTLPExporter(conf, otlpLogsSignal)
+	if err != nil {
+		return nil, err
+	}
+
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	logger = logger.Named("otlp")
+
+	s := &otlpLogSink{
+		exporter: exporter,
+		endpoint: conf.Endpoint,
+		resource: otlpResource(otlpResourceAttributes(conf, hostname)),
+		enabled:  enabled,
+		logger:   logger,
+		name:     logger.Name(),
+		flushCh:  make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Accept implements hclog.SinkAdapter.
+func (s *otlpLogSink) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	if name == s.name || (s.enabled != nil && !s.enabled(level)) {
+		return
+	}
+	record := otlpLogRecord(time.Now(), name, level, msg, args)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) >= otlpLogsMaxPending {
+		s.dropped++
+		return
+	}
+	s.pending = append(s.pending, record)
+	if len(s.pending) >= otlpLogsBatchSize {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *otlpLogSink) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(otlpLogsFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		case <-s.stopCh:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush exports the pending log records.
+func (s *otlpLogSink) flush() {
+	s.mu.Lock()
+	records, dropped := s.pending, s.dropped
+	s.pending, s.dropped = nil, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.logger.Warn("dropped logs, the OTLP endpoint isn't keeping up", "endpoint", s.endpoint, "dropped", dropped)
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpLogsExportTimeout)
+	defer cancel()
+	if err := s.exporter.export(ctx, s.request(records)); err != nil {
+		s.logger.Warn("failed to export logs", "endpoint", s.endpoint, "records", len(records), "error", err)
+	}
+}
+
+// request returns the ExportLogsServiceRequest of the log records.
+func (s *otlpLogSink) request(records []*logspb.LogRecord) *logspb.LogsData {
+	return &logspb.LogsData{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: s.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      otlpScope(),
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+// Shutdown exports the pending log records and closes the connection to the
+// collector. The sink must be deregistered from the logger first.
+func (s *otlpLogSink) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		<-s.doneCh
+		if err := s.exporter.close(); err != nil {
+			s.logger.Warn("failed to close the OTLP exporter", "error", err)
+		}
+	})
+}
+
+// otlpSeverities maps the hclog levels to the OTLP severity numbers and
+// texts.
+var otlpSeverities = map[hclog.Level]struct {
+	number logspb.SeverityNumber
+	text   string
+}{
+	hclog.Trace: {logspb.SeverityNumber_SEVERITY_NUMBER_TRACE, "TRACE"},
+	hclog.Debug: {logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG, "DEBUG"},
+	hclog.Info:  {logspb.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO"},
+	hclog.Warn:  {logspb.SeverityNumber_SEVERITY_NUMBER_WARN, "WARN"},
+	hclog.Error: {logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR"},
+}
+
+// otlpLogRecord returns the LogRecord of a log. The message is the body of
+// the record, and the name of the logger and the arguments are its
+// attributes, like with the JSON format of hclog.
+func otlpLogRecord(ts time.Time, name string, level hclog.Level, msg string, args []interface{}) *logspb.LogRecord {
+	nanos := uint64(ts.UnixNano())
+	record := &logspb.LogRecord{
+		TimeUnixNano:         nanos,
+		ObservedTimeUnixNano: nanos,
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: msg}},
+	}
+	if severity, ok := otlpSeverities[level]; ok {
+		record.SeverityNumber = severity.number
+		record.SeverityText = severity.text
+	}
+	if name != "" {
+		record.Attributes = append(record.Attributes, otlpKeyValue("@module", name))
+	}
+	if len(args)%2 != 0 {
+		args = append(args[:len(args)-1:len(args)-1], hclog.MissingKey, args[len(args)-1])
+	}
+	for i := 0; i < len(args); i += 2 {
+		record.Attributes = append(record.Attributes, &commonpb.KeyValue{
+			Key:   fmt.Sprint(args[i]),
+			Value: otlpAnyValue(args[i+1]),
+		})
+	}
+	return record
+}
+
+// otlpAnyValue returns the AnyValue of an argument, keeping the type of the
+// booleans and numbers and formatting the other values.
+func otlpAnyValue(v interface{}) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return otlpIntValue(int64(v))
+	case int8:
+		return otlpIntValue(int64(v))
+	case int16:
+		return otlpIntValue(int64(v))
+	case int32:
+		return otlpIntValue(int64(v))
+	case int64:
+		return otlpIntValue(v)
+	case uint8:
+		return otlpIntValue(int64(v))
+	case uint16:
+		return otlpIntValue(int64(v))
+	case uint32:
+		return otlpIntValue(int64(v))
+	case uint:
+		if uint64(v) <= math.MaxInt64 {
+			return otlpIntValue(int64(v))
+		}
+	case uint64:
+		if v <= math.MaxInt64 {
+			return otlpIntValue(int64(v))
+		}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprintf("%v", v)}}
+}
+
+func otlpIntValue(v int64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}}
+}
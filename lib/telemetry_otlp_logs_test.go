@@ -0,0 +1,138 @@
+package lib
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpLogRecords decodes an ExportLogsServiceRequest, returning the resource
+// attributes and the log records.
+func otlpLogRecords(t *testing.T, req []byte) (map[string]string, []*logspb.LogRecord) {
+	t.Helper()
+	var data logspb.LogsData
+	require.NoError(t, proto.Unmarshal(req, &data))
+	require.Len(t, data.ResourceLogs, 1)
+	resourceLogs := data.ResourceLogs[0]
+
+	require.Len(t, resourceLogs.ScopeLogs, 1)
+	scopeLogs := resourceLogs.ScopeLogs[0]
+	require.Equal(t, "consul", scopeLogs.Scope.Name)
+	return otlpStringAttributes(resourceLogs.Resource.Attributes), scopeLogs.LogRecords
+}
+
+// otlpLogAttributes returns the values of the attributes of a log record by
+// key.
+func otlpLogAttributes(record *logspb.LogRecord) map[string]*commonpb.AnyValue {
+	attributes := make(map[string]*commonpb.AnyValue)
+	for _, kv := range record.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestOTLPLogSink(t *testing.T) {
+	reqCh := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/logs", r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		reqCh <- body
+	}))
+	defer srv.Close()
+
+	logger := hclog.NewInterceptLogger(&hclog.LoggerOptions{
+		Name:   "agent",
+		Level:  hclog.Info,
+		Output: io.Discard,
+	})
+	metricsCfg := &MetricsConfig{
+		sink:     &reloadableSink{},
+		memSink:  &metrics.BlackholeSink{},
+		hostname: "node1",
+		logger:   logger,
+		logs:     logger,
+	}
+
+	cfg := TelemetryConfig{
+		OTLPEndpoint:       srv.URL,
+		OTLPProtocol:       OTLPProtocolHTTP,
+		OTLPHeaders:        map[string]string{"X-Api-Key": "secret"},
+		OTLPExportInterval: time.Hour,
+		OTLPLogs:           true,
+	}
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	defer metricsCfg.updateSinks(TelemetryConfig{})
+	sink := metricsCfg.logSink
+	require.NotNil(t, sink)
+
+	// The log sink is kept when the settings of the metrics change.
+	cfg.OTLPTemporality = OTLPTemporalityDelta
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	require.Same(t, sink, metricsCfg.logSink)
+
+	server := logger.Named("server")
+	server.Debug("not exported, below the log level")
+	server.Info("started", "port", 8300, "leader", true, "error", errors.New("boom"))
+	// The logs of the sink itself aren't exported.
+	logger.Named("otlp").Warn("failed to export logs")
+
+	// The pending logs are exported when the sink is removed.
+	cfg.OTLPLogs = false
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	require.Nil(t, metricsCfg.logSink)
+
+	var req []byte
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the logs weren't exported")
+	}
+
+	attributes, records := otlpLogRecords(t, req)
+	require.Equal(t, map[string]string{"service.name": "consul", "host.name": "node1"}, attributes)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, record.SeverityNumber)
+	require.Equal(t, "INFO", record.SeverityText)
+	require.Equal(t, "started", record.Body.GetStringValue())
+	require.NotZero(t, record.TimeUnixNano)
+	require.Equal(t, record.TimeUnixNano, record.ObservedTimeUnixNano)
+
+	recordAttributes := otlpLogAttributes(record)
+	require.Equal(t, "agent.server", recordAttributes["@module"].GetStringValue())
+	require.Equal(t, int64(8300), recordAttributes["port"].GetIntValue())
+	require.True(t, recordAttributes["leader"].GetBoolValue())
+	require.Equal(t, "boom", recordAttributes["error"].GetStringValue())
+
+	// Nothing is exported once the sink is removed.
+	server.Info("stopped")
+	metricsCfg.ShutdownLogSink()
+	select {
+	case <-reqCh:
+		t.Fatal("unexpected export")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestOTLPLogRecord_MissingValue(t *testing.T) {
+	record := otlpLogRecord(time.Now(), "", hclog.Warn, "msg", []interface{}{"key", 1.5, "extra"})
+	require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber)
+
+	attributes := otlpLogAttributes(record)
+	require.Len(t, attributes, 2)
+	require.Equal(t, 1.5, attributes["key"].GetDoubleValue())
+	require.Equal(t, "extra", attributes[hclog.MissingKey].GetStringValue())
+}
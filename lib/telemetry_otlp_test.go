@@ -0,0 +1,161 @@
+package lib
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpMetrics decodes an ExportMetricsServiceRequest, returning the resource
+// attributes and the metrics by name.
+func otlpMetrics(t *testing.T, req []byte) (map[string]string, map[string]*metricspb.Metric) {
+	t.Helper()
+	var data metricspb.MetricsData
+	require.NoError(t, proto.Unmarshal(req, &data))
+	require.Len(t, data.ResourceMetrics, 1)
+	resourceMetrics := data.ResourceMetrics[0]
+
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+	scopeMetrics := resourceMetrics.ScopeMetrics[0]
+	require.Equal(t, "consul", scopeMetrics.Scope.Name)
+
+	result := make(map[string]*metricspb.Metric)
+	for _, m := range scopeMetrics.Metrics {
+		result[m.Name] = m
+	}
+	return otlpStringAttributes(resourceMetrics.Resource.Attributes), result
+}
+
+// otlpStringAttributes returns the string values of the attributes by key.
+func otlpStringAttributes(attributes []*commonpb.KeyValue) map[string]string {
+	result := make(map[string]string)
+	for _, kv := range attributes {
+		result[kv.Key] = kv.Value.GetStringValue()
+	}
+	return result
+}
+
+func TestOTLPSink_HTTP(t *testing.T) {
+	reqCh := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/metrics", r.URL.Path)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		reqCh <- body
+	}))
+	defer srv.Close()
+
+	cfg := TelemetryConfig{
+		OTLPEndpoint:           srv.URL,
+		OTLPProtocol:           OTLPProtocolHTTP,
+		OTLPHeaders:            map[string]string{"X-Api-Key": "secret"},
+		OTLPExportInterval:     time.Hour,
+		OTLPResourceAttributes: map[string]string{"deployment.environment": "test"},
+	}
+	s, err := otlpSink(cfg, "node1", nil)
+	require.NoError(t, err)
+	sink := s.(*otlpMetricsSink)
+
+	sink.SetGauge([]string{"consul", "gauge"}, 1)
+	sink.SetGauge([]string{"consul", "gauge"}, 2)
+	sink.IncrCounterWithLabels([]string{"consul", "counter"}, 1, []metrics.Label{{Name: "dc", Value: "dc1"}})
+	sink.IncrCounterWithLabels([]string{"consul", "counter"}, 2, []metrics.Label{{Name: "dc", Value: "dc1"}})
+	sink.AddSample([]string{"consul", "timer"}, 5)
+	sink.AddSample([]string{"consul", "timer"}, 1)
+
+	// The metrics are exported on shutdown.
+	sink.Shutdown()
+
+	var req []byte
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the metrics weren't exported")
+	}
+
+	attributes, exported := otlpMetrics(t, req)
+	require.Equal(t, map[string]string{
+		"service.name":           "consul",
+		"host.name":              "node1",
+		"deployment.environment": "test",
+	}, attributes)
+	require.Len(t, exported, 3)
+
+	gauge := exported["consul.gauge"].GetGauge()
+	require.NotNil(t, gauge)
+	require.Len(t, gauge.DataPoints, 1)
+	require.Equal(t, 2.0, gauge.DataPoints[0].GetAsDouble())
+	require.Zero(t, gauge.DataPoints[0].StartTimeUnixNano)
+
+	sum := exported["consul.counter"].GetSum()
+	require.NotNil(t, sum)
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	require.True(t, sum.IsMonotonic)
+	require.Len(t, sum.DataPoints, 1)
+	require.Equal(t, 3.0, sum.DataPoints[0].GetAsDouble())
+	require.Equal(t, map[string]string{"dc": "dc1"}, otlpStringAttributes(sum.DataPoints[0].Attributes))
+	require.NotZero(t, sum.DataPoints[0].StartTimeUnixNano)
+
+	histogram := exported["consul.timer"].GetHistogram()
+	require.NotNil(t, histogram)
+	require.Len(t, histogram.DataPoints, 1)
+	point := histogram.DataPoints[0]
+	require.Equal(t, uint64(2), point.Count)
+	require.Equal(t, 6.0, point.GetSum())
+	require.Equal(t, []uint64{2}, point.BucketCounts)
+	require.Equal(t, 1.0, point.GetMin())
+	require.Equal(t, 5.0, point.GetMax())
+}
+
+func TestOTLPSink_DeltaTemporality(t *testing.T) {
+	conf := newOTLPConfig(TelemetryConfig{
+		OTLPEndpoint:       "localhost:4318",
+		OTLPProtocol:       OTLPProtocolHTTP,
+		OTLPInsecure:       true,
+		OTLPExportInterval: time.Hour,
+		OTLPTemporality:    OTLPTemporalityDelta,
+	})
+	sink, err := newOTLPMetricsSink(conf, "", nil)
+	require.NoError(t, err)
+	defer sink.Shutdown()
+	require.Equal(t, "http://localhost:4318/v1/metrics", sink.exporter.(*otlpHTTPExporter).url)
+
+	sink.IncrCounter([]string{"consul", "counter"}, 1)
+	sink.SetGauge([]string{"consul", "gauge"}, 1)
+
+	_, exported := otlpMetrics(t, otlpMarshal(t, sink.collect(time.Now())))
+	require.Len(t, exported, 2)
+	sum := exported["consul.counter"].GetSum()
+	require.NotNil(t, sum)
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, sum.AggregationTemporality)
+
+	// The counters are reset after each export, the gauges are kept.
+	_, exported = otlpMetrics(t, otlpMarshal(t, sink.collect(time.Now())))
+	require.Len(t, exported, 1)
+	require.Contains(t, exported, "consul.gauge")
+}
+
+func TestOTLPSink_InvalidConfig(t *testing.T) {
+	_, err := otlpSink(TelemetryConfig{OTLPEndpoint: "localhost:4317", OTLPProtocol: "udp"}, "", nil)
+	require.Error(t, err)
+
+	_, err = otlpSink(TelemetryConfig{OTLPEndpoint: "localhost:4317", OTLPTemporality: "sometimes"}, "", nil)
+	require.Error(t, err)
+}
+
+func otlpMarshal(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+	return b
+}
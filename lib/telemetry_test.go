@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/consul/logging"
 	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/require"
@@ -19,19 +20,35 @@ func newCfg() TelemetryConfig {
 	}
 }
 
-func TestConfigureSinks(t *testing.T) {
+func TestMetricsConfig_updateSinks(t *testing.T) {
+	memSink := &metrics.BlackholeSink{}
+	metricsCfg := &MetricsConfig{
+		sink:    &reloadableSink{},
+		memSink: memSink,
+	}
+
 	cfg := newCfg()
-	sinks, err := configureSinks(cfg, nil)
+	err := metricsCfg.updateSinks(cfg)
 	require.Error(t, err)
-	// 3 sinks: statsd, statsite, inmem
-	require.Equal(t, 3, len(sinks))
+	// 3 sinks: statsite, statsd, inmem
+	require.Len(t, metricsCfg.sink.sinks, 3)
+	statsite := metricsCfg.sink.sinks[0]
+	statsd := metricsCfg.sink.sinks[1]
 
-	cfg = TelemetryConfig{
-		DogstatsdAddr: "",
-	}
-	_, err = configureSinks(cfg, nil)
-	require.NoError(t, err)
+	// The sinks whose configuration didn't change are kept.
+	cfg.DogstatsdAddr = ""
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	require.Equal(t, metrics.FanoutSink{statsite, statsd, memSink}, metricsCfg.sink.sinks)
+
+	cfg.StatsdAddr = "statsd.host:5678"
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	require.Len(t, metricsCfg.sink.sinks, 3)
+	require.Same(t, statsite, metricsCfg.sink.sinks[0])
+	require.NotSame(t, statsd, metricsCfg.sink.sinks[1])
 
+	cfg = TelemetryConfig{}
+	require.NoError(t, metricsCfg.updateSinks(cfg))
+	require.Equal(t, metrics.FanoutSink{memSink}, metricsCfg.sink.sinks)
 }
 
 func TestIsRetriableError(t *testing.T) {
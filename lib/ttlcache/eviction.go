@@ -35,6 +35,11 @@ func (e *Entry) Key() string {
 	return e.key
 }
 
+// Expiry returns the time the entry expires at.
+func (e *Entry) Expiry() time.Time {
+	return e.expiry
+}
+
 // ExpiryHeap is a heap that is ordered by the expiry time of entries. It may
 // be used by a cache or storage to expiry items after a TTL.
 //
@@ -41,10 +41,34 @@ type LogFile struct {
 	// Max rotated files to keep before removing them.
 	MaxFiles int
 
+	// mode is the permissions of the created files, 0640 if unset.
+	mode os.FileMode
+
 	//acquire is the mutex utilized to ensure we have no concurrency issues
 	acquire sync.Mutex
 }
 
+// NewLogFile returns a file based logger writing to path that rotates the file
+// when it reaches maxBytes or every duration, and keeps at most maxFiles of the
+// rotated files. A zero mode creates the files with the 0640 permissions.
+func NewLogFile(path string, mode os.FileMode, duration time.Duration, maxBytes, maxFiles int) *LogFile {
+	dir, fileName := filepath.Split(path)
+	if fileName == "" {
+		fileName = "consul.log"
+	}
+	if duration == 0 {
+		duration = defaultRotateDuration
+	}
+	return &LogFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: duration,
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+		mode:     mode,
+	}
+}
+
 func (l *LogFile) fileNamePattern() string {
 	// Extract the file extension
 	fileExt := filepath.Ext(l.fileName)
@@ -64,7 +88,11 @@ func (l *LogFile) openNew() error {
 	newfilePath := filepath.Join(l.logPath, newfileName)
 
 	// Try creating a file. We truncate the file because we are the only authority to write the logs
-	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	mode := l.mode
+	if mode == 0 {
+		mode = 0640
+	}
+	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
 	if err != nil {
 		return err
 	}
@@ -139,3 +167,15 @@ func (l *LogFile) Write(b []byte) (n int, err error) {
 	l.BytesWritten += int64(len(b))
 	return l.FileInfo.Write(b)
 }
+
+// Close closes the current log file, if any. The next write opens a new one.
+func (l *LogFile) Close() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+	if l.FileInfo == nil {
+		return nil
+	}
+	err := l.FileInfo.Close()
+	l.FileInfo = nil
+	return err
+}
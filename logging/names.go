@@ -4,6 +4,7 @@ const (
 	ACL                   string = "acl"
 	Agent                 string = "agent"
 	AntiEntropy           string = "anti_entropy"
+	Audit                 string = "audit"
 	AutoEncrypt           string = "auto_encrypt"
 	AutoConfig            string = "auto_config"
 	Autopilot             string = "autopilot"
@@ -12,6 +13,7 @@ const (
 	CA                    string = "ca"
 	Catalog               string = "catalog"
 	CentralConfig         string = "central_config"
+	ChangeSink            string = "change_sink"
 	ConfigEntry           string = "config_entry"
 	Connect               string = "connect"
 	Consul                string = "consul"
@@ -20,6 +22,7 @@ const (
 	Coordinate            string = "coordinate"
 	DNS                   string = "dns"
 	Envoy                 string = "envoy"
+	ExternalHealth        string = "external_health"
 	FederationState       string = "federation_state"
 	FSM                   string = "fsm"
 	APIGatewayController  string = "api_gateway_controller"
@@ -29,6 +32,7 @@ const (
 	IngressGateway        string = "ingress_gateway"
 	Intentions            string = "intentions"
 	Internal              string = "internal"
+	Keyring               string = "keyring"
 	KV                    string = "kvs"
 	LAN                   string = "lan"
 	Leader                string = "leader"
@@ -41,6 +45,7 @@ const (
 	NetworkAreas          string = "network_areas"
 	Operator              string = "operator"
 	PreparedQuery         string = "prepared_query"
+	Profiler              string = "profiler"
 	Proxy                 string = "proxy"
 	ProxyConfig           string = "proxycfg"
 	Raft                  string = "raft"
@@ -37,6 +37,26 @@ func (msg *GetSupportedDataplaneFeaturesResponse) UnmarshalBinary(b []byte) erro
 	return proto.Unmarshal(b, msg)
 }
 
+// MarshalBinary implements encoding.BinaryMarshaler
+func (msg *NegotiateDataplaneFeaturesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (msg *NegotiateDataplaneFeaturesRequest) UnmarshalBinary(b []byte) error {
+	return proto.Unmarshal(b, msg)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (msg *NegotiateDataplaneFeaturesResponse) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (msg *NegotiateDataplaneFeaturesResponse) UnmarshalBinary(b []byte) error {
+	return proto.Unmarshal(b, msg)
+}
+
 // MarshalBinary implements encoding.BinaryMarshaler
 func (msg *GetEnvoyBootstrapParamsRequest) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(msg)
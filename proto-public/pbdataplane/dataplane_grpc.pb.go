@@ -24,6 +24,7 @@ const _ = grpc.SupportPackageIsVersion7
 type DataplaneServiceClient interface {
 	GetSupportedDataplaneFeatures(ctx context.Context, in *GetSupportedDataplaneFeaturesRequest, opts ...grpc.CallOption) (*GetSupportedDataplaneFeaturesResponse, error)
 	GetEnvoyBootstrapParams(ctx context.Context, in *GetEnvoyBootstrapParamsRequest, opts ...grpc.CallOption) (*GetEnvoyBootstrapParamsResponse, error)
+	NegotiateDataplaneFeatures(ctx context.Context, in *NegotiateDataplaneFeaturesRequest, opts ...grpc.CallOption) (*NegotiateDataplaneFeaturesResponse, error)
 }
 
 type dataplaneServiceClient struct {
@@ -52,12 +53,22 @@ func (c *dataplaneServiceClient) GetEnvoyBootstrapParams(ctx context.Context, in
 	return out, nil
 }
 
+func (c *dataplaneServiceClient) NegotiateDataplaneFeatures(ctx context.Context, in *NegotiateDataplaneFeaturesRequest, opts ...grpc.CallOption) (*NegotiateDataplaneFeaturesResponse, error) {
+	out := new(NegotiateDataplaneFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.consul.dataplane.DataplaneService/NegotiateDataplaneFeatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataplaneServiceServer is the server API for DataplaneService service.
 // All implementations should embed UnimplementedDataplaneServiceServer
 // for forward compatibility
 type DataplaneServiceServer interface {
 	GetSupportedDataplaneFeatures(context.Context, *GetSupportedDataplaneFeaturesRequest) (*GetSupportedDataplaneFeaturesResponse, error)
 	GetEnvoyBootstrapParams(context.Context, *GetEnvoyBootstrapParamsRequest) (*GetEnvoyBootstrapParamsResponse, error)
+	NegotiateDataplaneFeatures(context.Context, *NegotiateDataplaneFeaturesRequest) (*NegotiateDataplaneFeaturesResponse, error)
 }
 
 // UnimplementedDataplaneServiceServer should be embedded to have forward compatible implementations.
@@ -70,6 +81,9 @@ func (UnimplementedDataplaneServiceServer) GetSupportedDataplaneFeatures(context
 func (UnimplementedDataplaneServiceServer) GetEnvoyBootstrapParams(context.Context, *GetEnvoyBootstrapParamsRequest) (*GetEnvoyBootstrapParamsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEnvoyBootstrapParams not implemented")
 }
+func (UnimplementedDataplaneServiceServer) NegotiateDataplaneFeatures(context.Context, *NegotiateDataplaneFeaturesRequest) (*NegotiateDataplaneFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NegotiateDataplaneFeatures not implemented")
+}
 
 // UnsafeDataplaneServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to DataplaneServiceServer will
@@ -118,6 +132,24 @@ func _DataplaneService_GetEnvoyBootstrapParams_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataplaneService_NegotiateDataplaneFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateDataplaneFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataplaneServiceServer).NegotiateDataplaneFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.consul.dataplane.DataplaneService/NegotiateDataplaneFeatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataplaneServiceServer).NegotiateDataplaneFeatures(ctx, req.(*NegotiateDataplaneFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DataplaneService_ServiceDesc is the grpc.ServiceDesc for DataplaneService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -133,6 +165,10 @@ var DataplaneService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEnvoyBootstrapParams",
 			Handler:    _DataplaneService_GetEnvoyBootstrapParams_Handler,
 		},
+		{
+			MethodName: "NegotiateDataplaneFeatures",
+			Handler:    _DataplaneService_NegotiateDataplaneFeatures_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto-public/pbdataplane/dataplane.proto",
@@ -0,0 +1,148 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto-public/pbservicelease/servicelease.proto
+
+package pbservicelease
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ServiceLeaseServiceClient is the client API for ServiceLeaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ServiceLeaseServiceClient interface {
+	// KeepAlive renews the leases of the services in the request for as long as
+	// the stream is open. Each lease is renewed immediately and then every third
+	// of its TTL, and a response is sent every time a lease is renewed. A
+	// response is also sent once a service no longer has a lease, for example
+	// because it was deregistered or registered again without a lease. The
+	// stream ends once none of the services has a lease.
+	//
+	// Closing the stream doesn't deregister the services, they are deregistered
+	// once their lease expires.
+	KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (ServiceLeaseService_KeepAliveClient, error)
+}
+
+type serviceLeaseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServiceLeaseServiceClient(cc grpc.ClientConnInterface) ServiceLeaseServiceClient {
+	return &serviceLeaseServiceClient{cc}
+}
+
+func (c *serviceLeaseServiceClient) KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (ServiceLeaseService_KeepAliveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ServiceLeaseService_ServiceDesc.Streams[0], "/hashicorp.consul.servicelease.ServiceLeaseService/KeepAlive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceLeaseServiceKeepAliveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ServiceLeaseService_KeepAliveClient interface {
+	Recv() (*KeepAliveResponse, error)
+	grpc.ClientStream
+}
+
+type serviceLeaseServiceKeepAliveClient struct {
+	grpc.ClientStream
+}
+
+func (x *serviceLeaseServiceKeepAliveClient) Recv() (*KeepAliveResponse, error) {
+	m := new(KeepAliveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ServiceLeaseServiceServer is the server API for ServiceLeaseService service.
+// All implementations should embed UnimplementedServiceLeaseServiceServer
+// for forward compatibility
+type ServiceLeaseServiceServer interface {
+	// KeepAlive renews the leases of the services in the request for as long as
+	// the stream is open. Each lease is renewed immediately and then every third
+	// of its TTL, and a response is sent every time a lease is renewed. A
+	// response is also sent once a service no longer has a lease, for example
+	// because it was deregistered or registered again without a lease. The
+	// stream ends once none of the services has a lease.
+	//
+	// Closing the stream doesn't deregister the services, they are deregistered
+	// once their lease expires.
+	KeepAlive(*KeepAliveRequest, ServiceLeaseService_KeepAliveServer) error
+}
+
+// UnimplementedServiceLeaseServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedServiceLeaseServiceServer struct {
+}
+
+func (UnimplementedServiceLeaseServiceServer) KeepAlive(*KeepAliveRequest, ServiceLeaseService_KeepAliveServer) error {
+	return status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
+}
+
+// UnsafeServiceLeaseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceLeaseServiceServer will
+// result in compilation errors.
+type UnsafeServiceLeaseServiceServer interface {
+	mustEmbedUnimplementedServiceLeaseServiceServer()
+}
+
+func RegisterServiceLeaseServiceServer(s grpc.ServiceRegistrar, srv ServiceLeaseServiceServer) {
+	s.RegisterService(&ServiceLeaseService_ServiceDesc, srv)
+}
+
+func _ServiceLeaseService_KeepAlive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(KeepAliveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServiceLeaseServiceServer).KeepAlive(m, &serviceLeaseServiceKeepAliveServer{stream})
+}
+
+type ServiceLeaseService_KeepAliveServer interface {
+	Send(*KeepAliveResponse) error
+	grpc.ServerStream
+}
+
+type serviceLeaseServiceKeepAliveServer struct {
+	grpc.ServerStream
+}
+
+func (x *serviceLeaseServiceKeepAliveServer) Send(m *KeepAliveResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ServiceLeaseService_ServiceDesc is the grpc.ServiceDesc for ServiceLeaseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServiceLeaseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.consul.servicelease.ServiceLeaseService",
+	HandlerType: (*ServiceLeaseServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "KeepAlive",
+			Handler:       _ServiceLeaseService_KeepAlive_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto-public/pbservicelease/servicelease.proto",
+}
@@ -0,0 +1,148 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto-public/pbsession/session.proto
+
+package pbsession
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SessionServiceClient is the client API for SessionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SessionServiceClient interface {
+	// KeepAlive renews the sessions in the request for as long as the stream is
+	// open. Each session is renewed immediately and then every half of its TTL,
+	// and a response is sent every time a session is renewed. A response is also
+	// pushed as soon as a session is invalidated, for example because it was
+	// destroyed or one of its health checks failed. The stream ends once all of
+	// the sessions have been invalidated.
+	//
+	// Closing the stream doesn't destroy the sessions, they expire once their
+	// TTL elapses.
+	KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (SessionService_KeepAliveClient, error)
+}
+
+type sessionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSessionServiceClient(cc grpc.ClientConnInterface) SessionServiceClient {
+	return &sessionServiceClient{cc}
+}
+
+func (c *sessionServiceClient) KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (SessionService_KeepAliveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SessionService_ServiceDesc.Streams[0], "/hashicorp.consul.session.SessionService/KeepAlive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sessionServiceKeepAliveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SessionService_KeepAliveClient interface {
+	Recv() (*KeepAliveResponse, error)
+	grpc.ClientStream
+}
+
+type sessionServiceKeepAliveClient struct {
+	grpc.ClientStream
+}
+
+func (x *sessionServiceKeepAliveClient) Recv() (*KeepAliveResponse, error) {
+	m := new(KeepAliveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SessionServiceServer is the server API for SessionService service.
+// All implementations should embed UnimplementedSessionServiceServer
+// for forward compatibility
+type SessionServiceServer interface {
+	// KeepAlive renews the sessions in the request for as long as the stream is
+	// open. Each session is renewed immediately and then every half of its TTL,
+	// and a response is sent every time a session is renewed. A response is also
+	// pushed as soon as a session is invalidated, for example because it was
+	// destroyed or one of its health checks failed. The stream ends once all of
+	// the sessions have been invalidated.
+	//
+	// Closing the stream doesn't destroy the sessions, they expire once their
+	// TTL elapses.
+	KeepAlive(*KeepAliveRequest, SessionService_KeepAliveServer) error
+}
+
+// UnimplementedSessionServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedSessionServiceServer struct {
+}
+
+func (UnimplementedSessionServiceServer) KeepAlive(*KeepAliveRequest, SessionService_KeepAliveServer) error {
+	return status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
+}
+
+// UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SessionServiceServer will
+// result in compilation errors.
+type UnsafeSessionServiceServer interface {
+	mustEmbedUnimplementedSessionServiceServer()
+}
+
+func RegisterSessionServiceServer(s grpc.ServiceRegistrar, srv SessionServiceServer) {
+	s.RegisterService(&SessionService_ServiceDesc, srv)
+}
+
+func _SessionService_KeepAlive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(KeepAliveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SessionServiceServer).KeepAlive(m, &sessionServiceKeepAliveServer{stream})
+}
+
+type SessionService_KeepAliveServer interface {
+	Send(*KeepAliveResponse) error
+	grpc.ServerStream
+}
+
+type sessionServiceKeepAliveServer struct {
+	grpc.ServerStream
+}
+
+func (x *sessionServiceKeepAliveServer) Send(m *KeepAliveResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SessionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.consul.session.SessionService",
+	HandlerType: (*SessionServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "KeepAlive",
+			Handler:       _SessionService_KeepAlive_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto-public/pbsession/session.proto",
+}
@@ -42,6 +42,158 @@ func APIGatewayFromStructs(t *structs.APIGatewayConfigEntry, s *APIGateway) {
 	}
 	s.Meta = t.Meta
 }
+func APIGatewayExtAuthzToStructs(s *APIGatewayExtAuthz, t *structs.APIGatewayExtAuthz) {
+	if s == nil {
+		return
+	}
+	t.Protocol = s.Protocol
+	t.Address = s.Address
+	t.Port = int(s.Port)
+	t.Timeout = structs.DurationFromProto(s.Timeout)
+	t.PathPrefix = s.PathPrefix
+	t.AllowedHeaders = s.AllowedHeaders
+	t.FailureModeAllow = s.FailureModeAllow
+	t.DisabledPaths = s.DisabledPaths
+}
+func APIGatewayExtAuthzFromStructs(t *structs.APIGatewayExtAuthz, s *APIGatewayExtAuthz) {
+	if s == nil {
+		return
+	}
+	s.Protocol = t.Protocol
+	s.Address = t.Address
+	s.Port = int32(t.Port)
+	s.Timeout = structs.DurationToProto(t.Timeout)
+	s.PathPrefix = t.PathPrefix
+	s.AllowedHeaders = t.AllowedHeaders
+	s.FailureModeAllow = t.FailureModeAllow
+	s.DisabledPaths = t.DisabledPaths
+}
+func APIGatewayGlobalRateLimitToStructs(s *APIGatewayGlobalRateLimit, t *structs.APIGatewayGlobalRateLimit) {
+	if s == nil {
+		return
+	}
+	t.Domain = s.Domain
+	t.Address = s.Address
+	t.Port = int(s.Port)
+	t.Timeout = structs.DurationFromProto(s.Timeout)
+	t.FailureModeDeny = s.FailureModeDeny
+}
+func APIGatewayGlobalRateLimitFromStructs(t *structs.APIGatewayGlobalRateLimit, s *APIGatewayGlobalRateLimit) {
+	if s == nil {
+		return
+	}
+	s.Domain = t.Domain
+	s.Address = t.Address
+	s.Port = int32(t.Port)
+	s.Timeout = structs.DurationToProto(t.Timeout)
+	s.FailureModeDeny = t.FailureModeDeny
+}
+func APIGatewayJSONWebKeySetToStructs(s *APIGatewayJSONWebKeySet, t *structs.APIGatewayJSONWebKeySet) {
+	if s == nil {
+		return
+	}
+	t.Local = s.Local
+	t.RemoteURI = s.RemoteURI
+	t.CAFile = s.CAFile
+	t.CacheDuration = structs.DurationFromProto(s.CacheDuration)
+}
+func APIGatewayJSONWebKeySetFromStructs(t *structs.APIGatewayJSONWebKeySet, s *APIGatewayJSONWebKeySet) {
+	if s == nil {
+		return
+	}
+	s.Local = t.Local
+	s.RemoteURI = t.RemoteURI
+	s.CAFile = t.CAFile
+	s.CacheDuration = structs.DurationToProto(t.CacheDuration)
+}
+func APIGatewayJWTClaimToHeaderToStructs(s *APIGatewayJWTClaimToHeader, t *structs.APIGatewayJWTClaimToHeader) {
+	if s == nil {
+		return
+	}
+	t.Claim = s.Claim
+	t.Header = s.Header
+}
+func APIGatewayJWTClaimToHeaderFromStructs(t *structs.APIGatewayJWTClaimToHeader, s *APIGatewayJWTClaimToHeader) {
+	if s == nil {
+		return
+	}
+	s.Claim = t.Claim
+	s.Header = t.Header
+}
+func APIGatewayJWTPolicyToStructs(s *APIGatewayJWTPolicy, t *structs.APIGatewayJWTPolicy) {
+	if s == nil {
+		return
+	}
+	{
+		t.Providers = make([]structs.APIGatewayJWTProvider, len(s.Providers))
+		for i := range s.Providers {
+			if s.Providers[i] != nil {
+				APIGatewayJWTProviderToStructs(s.Providers[i], &t.Providers[i])
+			}
+		}
+	}
+	t.AllowMissingPaths = s.AllowMissingPaths
+}
+func APIGatewayJWTPolicyFromStructs(t *structs.APIGatewayJWTPolicy, s *APIGatewayJWTPolicy) {
+	if s == nil {
+		return
+	}
+	{
+		s.Providers = make([]*APIGatewayJWTProvider, len(t.Providers))
+		for i := range t.Providers {
+			{
+				var x APIGatewayJWTProvider
+				APIGatewayJWTProviderFromStructs(&t.Providers[i], &x)
+				s.Providers[i] = &x
+			}
+		}
+	}
+	s.AllowMissingPaths = t.AllowMissingPaths
+}
+func APIGatewayJWTProviderToStructs(s *APIGatewayJWTProvider, t *structs.APIGatewayJWTProvider) {
+	if s == nil {
+		return
+	}
+	t.Name = s.Name
+	t.Issuer = s.Issuer
+	t.Audiences = s.Audiences
+	if s.JSONWebKeySet != nil {
+		APIGatewayJSONWebKeySetToStructs(s.JSONWebKeySet, &t.JSONWebKeySet)
+	}
+	t.Forward = s.Forward
+	{
+		t.ClaimsToHeaders = make([]structs.APIGatewayJWTClaimToHeader, len(s.ClaimsToHeaders))
+		for i := range s.ClaimsToHeaders {
+			if s.ClaimsToHeaders[i] != nil {
+				APIGatewayJWTClaimToHeaderToStructs(s.ClaimsToHeaders[i], &t.ClaimsToHeaders[i])
+			}
+		}
+	}
+}
+func APIGatewayJWTProviderFromStructs(t *structs.APIGatewayJWTProvider, s *APIGatewayJWTProvider) {
+	if s == nil {
+		return
+	}
+	s.Name = t.Name
+	s.Issuer = t.Issuer
+	s.Audiences = t.Audiences
+	{
+		var x APIGatewayJSONWebKeySet
+		APIGatewayJSONWebKeySetFromStructs(&t.JSONWebKeySet, &x)
+		s.JSONWebKeySet = &x
+	}
+	s.Forward = t.Forward
+	{
+		s.ClaimsToHeaders = make([]*APIGatewayJWTClaimToHeader, len(t.ClaimsToHeaders))
+		for i := range t.ClaimsToHeaders {
+			{
+				var x APIGatewayJWTClaimToHeader
+				APIGatewayJWTClaimToHeaderFromStructs(&t.ClaimsToHeaders[i], &x)
+				s.ClaimsToHeaders[i] = &x
+			}
+		}
+	}
+}
 func APIGatewayListenerToStructs(s *APIGatewayListener, t *structs.APIGatewayListener) {
 	if s == nil {
 		return
@@ -53,6 +205,11 @@ func APIGatewayListenerToStructs(s *APIGatewayListener, t *structs.APIGatewayLis
 	if s.TLS != nil {
 		APIGatewayTLSConfigurationToStructs(s.TLS, &t.TLS)
 	}
+	if s.AccessLogs != nil {
+		var x structs.AccessLogsConfig
+		AccessLogsConfigToStructs(s.AccessLogs, &x)
+		t.AccessLogs = &x
+	}
 }
 func APIGatewayListenerFromStructs(t *structs.APIGatewayListener, s *APIGatewayListener) {
 	if s == nil {
@@ -67,6 +224,169 @@ func APIGatewayListenerFromStructs(t *structs.APIGatewayListener, s *APIGatewayL
 		APIGatewayTLSConfigurationFromStructs(&t.TLS, &x)
 		s.TLS = &x
 	}
+	if t.AccessLogs != nil {
+		var x AccessLogsConfig
+		AccessLogsConfigFromStructs(t.AccessLogs, &x)
+		s.AccessLogs = &x
+	}
+}
+func APIGatewayListenerPolicyToStructs(s *APIGatewayListenerPolicy, t *structs.APIGatewayListenerPolicy) {
+	if s == nil {
+		return
+	}
+	t.Name = s.Name
+	if s.RateLimit != nil {
+		var x structs.APIGatewayRateLimit
+		APIGatewayRateLimitToStructs(s.RateLimit, &x)
+		t.RateLimit = &x
+	}
+	if s.JWT != nil {
+		var x structs.APIGatewayJWTPolicy
+		APIGatewayJWTPolicyToStructs(s.JWT, &x)
+		t.JWT = &x
+	}
+	if s.ExtAuthz != nil {
+		var x structs.APIGatewayExtAuthz
+		APIGatewayExtAuthzToStructs(s.ExtAuthz, &x)
+		t.ExtAuthz = &x
+	}
+}
+func APIGatewayListenerPolicyFromStructs(t *structs.APIGatewayListenerPolicy, s *APIGatewayListenerPolicy) {
+	if s == nil {
+		return
+	}
+	s.Name = t.Name
+	if t.RateLimit != nil {
+		var x APIGatewayRateLimit
+		APIGatewayRateLimitFromStructs(t.RateLimit, &x)
+		s.RateLimit = &x
+	}
+	if t.JWT != nil {
+		var x APIGatewayJWTPolicy
+		APIGatewayJWTPolicyFromStructs(t.JWT, &x)
+		s.JWT = &x
+	}
+	if t.ExtAuthz != nil {
+		var x APIGatewayExtAuthz
+		APIGatewayExtAuthzFromStructs(t.ExtAuthz, &x)
+		s.ExtAuthz = &x
+	}
+}
+func APIGatewayLocalRateLimitToStructs(s *APIGatewayLocalRateLimit, t *structs.APIGatewayLocalRateLimit) {
+	if s == nil {
+		return
+	}
+	t.MaxTokens = s.MaxTokens
+	t.TokensPerFill = s.TokensPerFill
+	t.FillInterval = structs.DurationFromProto(s.FillInterval)
+}
+func APIGatewayLocalRateLimitFromStructs(t *structs.APIGatewayLocalRateLimit, s *APIGatewayLocalRateLimit) {
+	if s == nil {
+		return
+	}
+	s.MaxTokens = t.MaxTokens
+	s.TokensPerFill = t.TokensPerFill
+	s.FillInterval = structs.DurationToProto(t.FillInterval)
+}
+func APIGatewayPolicyToStructs(s *APIGatewayPolicy, t *structs.APIGatewayPolicyConfigEntry) {
+	if s == nil {
+		return
+	}
+	{
+		t.Listeners = make([]structs.APIGatewayListenerPolicy, len(s.Listeners))
+		for i := range s.Listeners {
+			if s.Listeners[i] != nil {
+				APIGatewayListenerPolicyToStructs(s.Listeners[i], &t.Listeners[i])
+			}
+		}
+	}
+	t.Meta = s.Meta
+}
+func APIGatewayPolicyFromStructs(t *structs.APIGatewayPolicyConfigEntry, s *APIGatewayPolicy) {
+	if s == nil {
+		return
+	}
+	{
+		s.Listeners = make([]*APIGatewayListenerPolicy, len(t.Listeners))
+		for i := range t.Listeners {
+			{
+				var x APIGatewayListenerPolicy
+				APIGatewayListenerPolicyFromStructs(&t.Listeners[i], &x)
+				s.Listeners[i] = &x
+			}
+		}
+	}
+	s.Meta = t.Meta
+}
+func APIGatewayRateLimitToStructs(s *APIGatewayRateLimit, t *structs.APIGatewayRateLimit) {
+	if s == nil {
+		return
+	}
+	if s.Local != nil {
+		var x structs.APIGatewayLocalRateLimit
+		APIGatewayLocalRateLimitToStructs(s.Local, &x)
+		t.Local = &x
+	}
+	{
+		t.Routes = make([]structs.APIGatewayRouteRateLimit, len(s.Routes))
+		for i := range s.Routes {
+			if s.Routes[i] != nil {
+				APIGatewayRouteRateLimitToStructs(s.Routes[i], &t.Routes[i])
+			}
+		}
+	}
+	if s.Global != nil {
+		var x structs.APIGatewayGlobalRateLimit
+		APIGatewayGlobalRateLimitToStructs(s.Global, &x)
+		t.Global = &x
+	}
+}
+func APIGatewayRateLimitFromStructs(t *structs.APIGatewayRateLimit, s *APIGatewayRateLimit) {
+	if s == nil {
+		return
+	}
+	if t.Local != nil {
+		var x APIGatewayLocalRateLimit
+		APIGatewayLocalRateLimitFromStructs(t.Local, &x)
+		s.Local = &x
+	}
+	{
+		s.Routes = make([]*APIGatewayRouteRateLimit, len(t.Routes))
+		for i := range t.Routes {
+			{
+				var x APIGatewayRouteRateLimit
+				APIGatewayRouteRateLimitFromStructs(&t.Routes[i], &x)
+				s.Routes[i] = &x
+			}
+		}
+	}
+	if t.Global != nil {
+		var x APIGatewayGlobalRateLimit
+		APIGatewayGlobalRateLimitFromStructs(t.Global, &x)
+		s.Global = &x
+	}
+}
+func APIGatewayRouteRateLimitToStructs(s *APIGatewayRouteRateLimit, t *structs.APIGatewayRouteRateLimit) {
+	if s == nil {
+		return
+	}
+	t.Hostname = s.Hostname
+	t.PathPrefix = s.PathPrefix
+	if s.Local != nil {
+		APIGatewayLocalRateLimitToStructs(s.Local, &t.Local)
+	}
+}
+func APIGatewayRouteRateLimitFromStructs(t *structs.APIGatewayRouteRateLimit, s *APIGatewayRouteRateLimit) {
+	if s == nil {
+		return
+	}
+	s.Hostname = t.Hostname
+	s.PathPrefix = t.PathPrefix
+	{
+		var x APIGatewayLocalRateLimit
+		APIGatewayLocalRateLimitFromStructs(&t.Local, &x)
+		s.Local = &x
+	}
 }
 func APIGatewayTLSConfigurationToStructs(s *APIGatewayTLSConfiguration, t *structs.APIGatewayTLSConfiguration) {
 	if s == nil {
@@ -102,6 +422,28 @@ func APIGatewayTLSConfigurationFromStructs(t *structs.APIGatewayTLSConfiguration
 	s.MinVersion = tlsVersionFromStructs(t.MinVersion)
 	s.CipherSuites = cipherSuitesFromStructs(t.CipherSuites)
 }
+func AccessLogsConfigToStructs(s *AccessLogsConfig, t *structs.AccessLogsConfig) {
+	if s == nil {
+		return
+	}
+	t.Enabled = s.Enabled
+	t.DisableListenerLogs = s.DisableListenerLogs
+	t.Type = structs.LogSinkType(s.Type)
+	t.Path = s.Path
+	t.JSONFormat = s.JSONFormat
+	t.TextFormat = s.TextFormat
+}
+func AccessLogsConfigFromStructs(t *structs.AccessLogsConfig, s *AccessLogsConfig) {
+	if s == nil {
+		return
+	}
+	s.Enabled = t.Enabled
+	s.DisableListenerLogs = t.DisableListenerLogs
+	s.Type = string(t.Type)
+	s.Path = t.Path
+	s.JSONFormat = t.JSONFormat
+	s.TextFormat = t.TextFormat
+}
 func BoundAPIGatewayToStructs(s *BoundAPIGateway, t *structs.BoundAPIGatewayConfigEntry) {
 	if s == nil {
 		return
@@ -232,6 +574,16 @@ func DestinationConfigToStructs(s *DestinationConfig, t *structs.DestinationConf
 	}
 	t.Addresses = s.Addresses
 	t.Port = int(s.Port)
+	if s.HealthCheck != nil {
+		var x structs.DestinationHealthCheck
+		DestinationHealthCheckToStructs(s.HealthCheck, &x)
+		t.HealthCheck = &x
+	}
+	if s.DNS != nil {
+		var x structs.DestinationDNSConfig
+		DestinationDNSConfigToStructs(s.DNS, &x)
+		t.DNS = &x
+	}
 }
 func DestinationConfigFromStructs(t *structs.DestinationConfig, s *DestinationConfig) {
 	if s == nil {
@@ -239,6 +591,54 @@ func DestinationConfigFromStructs(t *structs.DestinationConfig, s *DestinationCo
 	}
 	s.Addresses = t.Addresses
 	s.Port = int32(t.Port)
+	if t.HealthCheck != nil {
+		var x DestinationHealthCheck
+		DestinationHealthCheckFromStructs(t.HealthCheck, &x)
+		s.HealthCheck = &x
+	}
+	if t.DNS != nil {
+		var x DestinationDNSConfig
+		DestinationDNSConfigFromStructs(t.DNS, &x)
+		s.DNS = &x
+	}
+}
+func DestinationDNSConfigToStructs(s *DestinationDNSConfig, t *structs.DestinationDNSConfig) {
+	if s == nil {
+		return
+	}
+	t.RefreshRate = structs.DurationFromProto(s.RefreshRate)
+	t.RespectTTL = s.RespectTTL
+	t.DiscoveryType = s.DiscoveryType
+}
+func DestinationDNSConfigFromStructs(t *structs.DestinationDNSConfig, s *DestinationDNSConfig) {
+	if s == nil {
+		return
+	}
+	s.RefreshRate = structs.DurationToProto(t.RefreshRate)
+	s.RespectTTL = t.RespectTTL
+	s.DiscoveryType = t.DiscoveryType
+}
+func DestinationHealthCheckToStructs(s *DestinationHealthCheck, t *structs.DestinationHealthCheck) {
+	if s == nil {
+		return
+	}
+	t.Protocol = s.Protocol
+	t.Path = s.Path
+	t.Interval = structs.DurationFromProto(s.Interval)
+	t.Timeout = structs.DurationFromProto(s.Timeout)
+	t.UnhealthyThreshold = s.UnhealthyThreshold
+	t.HealthyThreshold = s.HealthyThreshold
+}
+func DestinationHealthCheckFromStructs(t *structs.DestinationHealthCheck, s *DestinationHealthCheck) {
+	if s == nil {
+		return
+	}
+	s.Protocol = t.Protocol
+	s.Path = t.Path
+	s.Interval = structs.DurationToProto(t.Interval)
+	s.Timeout = structs.DurationToProto(t.Timeout)
+	s.UnhealthyThreshold = t.UnhealthyThreshold
+	s.HealthyThreshold = t.HealthyThreshold
 }
 func ExposeConfigToStructs(s *ExposeConfig, t *structs.ExposeConfig) {
 	if s == nil {
@@ -755,6 +1155,11 @@ func IngressListenerToStructs(s *IngressListener, t *structs.IngressListener) {
 		GatewayTLSConfigToStructs(s.TLS, &x)
 		t.TLS = &x
 	}
+	if s.AccessLogs != nil {
+		var x structs.AccessLogsConfig
+		AccessLogsConfigToStructs(s.AccessLogs, &x)
+		t.AccessLogs = &x
+	}
 	{
 		t.Services = make([]structs.IngressService, len(s.Services))
 		for i := range s.Services {
@@ -775,6 +1180,11 @@ func IngressListenerFromStructs(t *structs.IngressListener, s *IngressListener)
 		GatewayTLSConfigFromStructs(t.TLS, &x)
 		s.TLS = &x
 	}
+	if t.AccessLogs != nil {
+		var x AccessLogsConfig
+		AccessLogsConfigFromStructs(t.AccessLogs, &x)
+		s.AccessLogs = &x
+	}
 	{
 		s.Services = make([]*IngressService, len(t.Services))
 		for i := range t.Services {
@@ -1058,6 +1468,9 @@ func MeshConfigToStructs(s *MeshConfig, t *structs.MeshConfigEntry) {
 		PeeringMeshConfigToStructs(s.Peering, &x)
 		t.Peering = &x
 	}
+	if s.MeshGateway != nil {
+		MeshGatewayConfigToStructs(s.MeshGateway, &t.MeshGateway)
+	}
 	t.Meta = s.Meta
 }
 func MeshConfigFromStructs(t *structs.MeshConfigEntry, s *MeshConfig) {
@@ -1084,8 +1497,33 @@ func MeshConfigFromStructs(t *structs.MeshConfigEntry, s *MeshConfig) {
 		PeeringMeshConfigFromStructs(t.Peering, &x)
 		s.Peering = &x
 	}
+	{
+		var x MeshGatewayConfig
+		MeshGatewayConfigFromStructs(&t.MeshGateway, &x)
+		s.MeshGateway = &x
+	}
 	s.Meta = t.Meta
 }
+func MeshDirectionalHTTPConfigToStructs(s *MeshDirectionalHTTPConfig, t *structs.MeshDirectionalHTTPConfig) {
+	if s == nil {
+		return
+	}
+	if s.RequestNormalization != nil {
+		var x structs.RequestNormalizationMeshConfig
+		RequestNormalizationMeshConfigToStructs(s.RequestNormalization, &x)
+		t.RequestNormalization = &x
+	}
+}
+func MeshDirectionalHTTPConfigFromStructs(t *structs.MeshDirectionalHTTPConfig, s *MeshDirectionalHTTPConfig) {
+	if s == nil {
+		return
+	}
+	if t.RequestNormalization != nil {
+		var x RequestNormalizationMeshConfig
+		RequestNormalizationMeshConfigFromStructs(t.RequestNormalization, &x)
+		s.RequestNormalization = &x
+	}
+}
 func MeshDirectionalTLSConfigToStructs(s *MeshDirectionalTLSConfig, t *structs.MeshDirectionalTLSConfig) {
 	if s == nil {
 		return
@@ -1119,12 +1557,22 @@ func MeshHTTPConfigToStructs(s *MeshHTTPConfig, t *structs.MeshHTTPConfig) {
 		return
 	}
 	t.SanitizeXForwardedClientCert = s.SanitizeXForwardedClientCert
+	if s.Incoming != nil {
+		var x structs.MeshDirectionalHTTPConfig
+		MeshDirectionalHTTPConfigToStructs(s.Incoming, &x)
+		t.Incoming = &x
+	}
 }
 func MeshHTTPConfigFromStructs(t *structs.MeshHTTPConfig, s *MeshHTTPConfig) {
 	if s == nil {
 		return
 	}
 	s.SanitizeXForwardedClientCert = t.SanitizeXForwardedClientCert
+	if t.Incoming != nil {
+		var x MeshDirectionalHTTPConfig
+		MeshDirectionalHTTPConfigFromStructs(t.Incoming, &x)
+		s.Incoming = &x
+	}
 }
 func MeshTLSConfigToStructs(s *MeshTLSConfig, t *structs.MeshTLSConfig) {
 	if s == nil {
@@ -1184,6 +1632,24 @@ func PeeringMeshConfigFromStructs(t *structs.PeeringMeshConfig, s *PeeringMeshCo
 	}
 	s.PeerThroughMeshGateways = t.PeerThroughMeshGateways
 }
+func RequestNormalizationMeshConfigToStructs(s *RequestNormalizationMeshConfig, t *structs.RequestNormalizationMeshConfig) {
+	if s == nil {
+		return
+	}
+	t.NormalizePath = s.NormalizePath
+	t.MergeSlashes = s.MergeSlashes
+	t.PathWithEscapedSlashesAction = structs.PathWithEscapedSlashesAction(s.PathWithEscapedSlashesAction)
+	t.HeadersWithUnderscoresAction = structs.HeadersWithUnderscoresAction(s.HeadersWithUnderscoresAction)
+}
+func RequestNormalizationMeshConfigFromStructs(t *structs.RequestNormalizationMeshConfig, s *RequestNormalizationMeshConfig) {
+	if s == nil {
+		return
+	}
+	s.NormalizePath = t.NormalizePath
+	s.MergeSlashes = t.MergeSlashes
+	s.PathWithEscapedSlashesAction = string(t.PathWithEscapedSlashesAction)
+	s.HeadersWithUnderscoresAction = string(t.HeadersWithUnderscoresAction)
+}
 func ResourceReferenceToStructs(s *ResourceReference, t *structs.ResourceReference) {
 	if s == nil {
 		return
@@ -1216,6 +1682,24 @@ func RingHashConfigFromStructs(t *structs.RingHashConfig, s *RingHashConfig) {
 	s.MinimumRingSize = t.MinimumRingSize
 	s.MaximumRingSize = t.MaximumRingSize
 }
+func RouteRetryPolicyToStructs(s *RouteRetryPolicy, t *structs.RouteRetryPolicy) {
+	if s == nil {
+		return
+	}
+	t.NumRetries = s.NumRetries
+	t.RetryOn = s.RetryOn
+	t.RetryOnStatusCodes = s.RetryOnStatusCodes
+	t.PerTryTimeout = structs.DurationFromProto(s.PerTryTimeout)
+}
+func RouteRetryPolicyFromStructs(t *structs.RouteRetryPolicy, s *RouteRetryPolicy) {
+	if s == nil {
+		return
+	}
+	s.NumRetries = t.NumRetries
+	s.RetryOn = t.RetryOn
+	s.RetryOnStatusCodes = t.RetryOnStatusCodes
+	s.PerTryTimeout = structs.DurationToProto(t.PerTryTimeout)
+}
 func ServiceDefaultsToStructs(s *ServiceDefaults, t *structs.ServiceConfigEntry) {
 	if s == nil {
 		return
@@ -1247,6 +1731,21 @@ func ServiceDefaultsToStructs(s *ServiceDefaults, t *structs.ServiceConfigEntry)
 	t.LocalRequestTimeoutMs = int(s.LocalRequestTimeoutMs)
 	t.BalanceInboundConnections = s.BalanceInboundConnections
 	t.EnvoyExtensions = EnvoyExtensionsToStructs(s.EnvoyExtensions)
+	if s.AccessLogs != nil {
+		var x structs.AccessLogsConfig
+		AccessLogsConfigToStructs(s.AccessLogs, &x)
+		t.AccessLogs = &x
+	}
+	if s.RetryPolicy != nil {
+		var x structs.RouteRetryPolicy
+		RouteRetryPolicyToStructs(s.RetryPolicy, &x)
+		t.RetryPolicy = &x
+	}
+	if s.UpstreamConnectionPool != nil {
+		var x structs.UpstreamConnectionPool
+		UpstreamConnectionPoolToStructs(s.UpstreamConnectionPool, &x)
+		t.UpstreamConnectionPool = &x
+	}
 	t.Meta = s.Meta
 }
 func ServiceDefaultsFromStructs(t *structs.ServiceConfigEntry, s *ServiceDefaults) {
@@ -1286,6 +1785,21 @@ func ServiceDefaultsFromStructs(t *structs.ServiceConfigEntry, s *ServiceDefault
 	s.LocalRequestTimeoutMs = int32(t.LocalRequestTimeoutMs)
 	s.BalanceInboundConnections = t.BalanceInboundConnections
 	s.EnvoyExtensions = EnvoyExtensionsFromStructs(t.EnvoyExtensions)
+	if t.AccessLogs != nil {
+		var x AccessLogsConfig
+		AccessLogsConfigFromStructs(t.AccessLogs, &x)
+		s.AccessLogs = &x
+	}
+	if t.RetryPolicy != nil {
+		var x RouteRetryPolicy
+		RouteRetryPolicyFromStructs(t.RetryPolicy, &x)
+		s.RetryPolicy = &x
+	}
+	if t.UpstreamConnectionPool != nil {
+		var x UpstreamConnectionPool
+		UpstreamConnectionPoolFromStructs(t.UpstreamConnectionPool, &x)
+		s.UpstreamConnectionPool = &x
+	}
 	s.Meta = t.Meta
 }
 func ServiceIntentionsToStructs(s *ServiceIntentions, t *structs.ServiceIntentionsConfigEntry) {
@@ -1416,6 +1930,7 @@ func ServiceResolverFailoverToStructs(s *ServiceResolverFailover, t *structs.Ser
 			}
 		}
 	}
+	t.SamenessGroup = s.SamenessGroup
 }
 func ServiceResolverFailoverFromStructs(t *structs.ServiceResolverFailover, s *ServiceResolverFailover) {
 	if s == nil {
@@ -1435,6 +1950,7 @@ func ServiceResolverFailoverFromStructs(t *structs.ServiceResolverFailover, s *S
 			}
 		}
 	}
+	s.SamenessGroup = t.SamenessGroup
 }
 func ServiceResolverFailoverTargetToStructs(s *ServiceResolverFailoverTarget, t *structs.ServiceResolverFailoverTarget) {
 	if s == nil {
@@ -1446,6 +1962,7 @@ func ServiceResolverFailoverTargetToStructs(s *ServiceResolverFailoverTarget, t
 	t.Namespace = s.Namespace
 	t.Datacenter = s.Datacenter
 	t.Peer = s.Peer
+	t.Priority = int(s.Priority)
 }
 func ServiceResolverFailoverTargetFromStructs(t *structs.ServiceResolverFailoverTarget, s *ServiceResolverFailoverTarget) {
 	if s == nil {
@@ -1457,6 +1974,7 @@ func ServiceResolverFailoverTargetFromStructs(t *structs.ServiceResolverFailover
 	s.Namespace = t.Namespace
 	s.Datacenter = t.Datacenter
 	s.Peer = t.Peer
+	s.Priority = int32(t.Priority)
 }
 func ServiceResolverRedirectToStructs(s *ServiceResolverRedirect, t *structs.ServiceResolverRedirect) {
 	if s == nil {
@@ -1486,6 +2004,7 @@ func ServiceResolverSubsetToStructs(s *ServiceResolverSubset, t *structs.Service
 	}
 	t.Filter = s.Filter
 	t.OnlyPassing = s.OnlyPassing
+	t.Meta = s.Meta
 }
 func ServiceResolverSubsetFromStructs(t *structs.ServiceResolverSubset, s *ServiceResolverSubset) {
 	if s == nil {
@@ -1493,6 +2012,7 @@ func ServiceResolverSubsetFromStructs(t *structs.ServiceResolverSubset, s *Servi
 	}
 	s.Filter = t.Filter
 	s.OnlyPassing = t.OnlyPassing
+	s.Meta = t.Meta
 }
 func SourceIntentionToStructs(s *SourceIntention, t *structs.SourceIntention) {
 	if s == nil {
@@ -1510,6 +2030,7 @@ func SourceIntentionToStructs(s *SourceIntention, t *structs.SourceIntention) {
 			}
 		}
 	}
+	t.DestinationPorts = s.DestinationPorts
 	t.Precedence = int(s.Precedence)
 	t.LegacyID = s.LegacyID
 	t.Type = intentionSourceTypeToStructs(s.Type)
@@ -1519,6 +2040,7 @@ func SourceIntentionToStructs(s *SourceIntention, t *structs.SourceIntention) {
 	t.LegacyUpdateTime = timeToStructs(s.LegacyUpdateTime)
 	t.EnterpriseMeta = enterpriseMetaToStructs(s.EnterpriseMeta)
 	t.Peer = s.Peer
+	t.SamenessGroup = s.SamenessGroup
 }
 func SourceIntentionFromStructs(t *structs.SourceIntention, s *SourceIntention) {
 	if s == nil {
@@ -1536,6 +2058,7 @@ func SourceIntentionFromStructs(t *structs.SourceIntention, s *SourceIntention)
 			}
 		}
 	}
+	s.DestinationPorts = t.DestinationPorts
 	s.Precedence = int32(t.Precedence)
 	s.LegacyID = t.LegacyID
 	s.Type = intentionSourceTypeFromStructs(t.Type)
@@ -1545,6 +2068,7 @@ func SourceIntentionFromStructs(t *structs.SourceIntention, s *SourceIntention)
 	s.LegacyUpdateTime = timeFromStructs(t.LegacyUpdateTime)
 	s.EnterpriseMeta = enterpriseMetaFromStructs(t.EnterpriseMeta)
 	s.Peer = t.Peer
+	s.SamenessGroup = t.SamenessGroup
 }
 func StatusToStructs(s *Status, t *structs.Status) {
 	if s == nil {
@@ -1709,6 +2233,8 @@ func UpstreamConfigToStructs(s *UpstreamConfig, t *structs.UpstreamConfig) {
 		MeshGatewayConfigToStructs(s.MeshGateway, &t.MeshGateway)
 	}
 	t.BalanceOutboundConnections = s.BalanceOutboundConnections
+	t.MaxConcurrentStreams = int(s.MaxConcurrentStreams)
+	t.MaxRequestsPerConnection = int(s.MaxRequestsPerConnection)
 }
 func UpstreamConfigFromStructs(t *structs.UpstreamConfig, s *UpstreamConfig) {
 	if s == nil {
@@ -1737,6 +2263,8 @@ func UpstreamConfigFromStructs(t *structs.UpstreamConfig, s *UpstreamConfig) {
 		s.MeshGateway = &x
 	}
 	s.BalanceOutboundConnections = t.BalanceOutboundConnections
+	s.MaxConcurrentStreams = int32(t.MaxConcurrentStreams)
+	s.MaxRequestsPerConnection = int32(t.MaxRequestsPerConnection)
 }
 func UpstreamConfigurationToStructs(s *UpstreamConfiguration, t *structs.UpstreamConfiguration) {
 	if s == nil {
@@ -1778,6 +2306,22 @@ func UpstreamConfigurationFromStructs(t *structs.UpstreamConfiguration, s *Upstr
 		s.Defaults = &x
 	}
 }
+func UpstreamConnectionPoolToStructs(s *UpstreamConnectionPool, t *structs.UpstreamConnectionPool) {
+	if s == nil {
+		return
+	}
+	t.ConnectTimeoutMs = int(s.ConnectTimeoutMs)
+	t.MaxConcurrentStreams = int(s.MaxConcurrentStreams)
+	t.MaxRequestsPerConnection = int(s.MaxRequestsPerConnection)
+}
+func UpstreamConnectionPoolFromStructs(t *structs.UpstreamConnectionPool, s *UpstreamConnectionPool) {
+	if s == nil {
+		return
+	}
+	s.ConnectTimeoutMs = int32(t.ConnectTimeoutMs)
+	s.MaxConcurrentStreams = int32(t.MaxConcurrentStreams)
+	s.MaxRequestsPerConnection = int32(t.MaxRequestsPerConnection)
+}
 func UpstreamLimitsToStructs(s *UpstreamLimits, t *structs.UpstreamLimits) {
 	if s == nil {
 		return
@@ -89,6 +89,14 @@ func ConfigEntryToStructs(s *ConfigEntry) structs.ConfigEntry {
 		pbcommon.RaftIndexToStructs(s.RaftIndex, &target.RaftIndex)
 		pbcommon.EnterpriseMetaToStructs(s.EnterpriseMeta, &target.EnterpriseMeta)
 		return &target
+	case Kind_KindAPIGatewayPolicy:
+		var target structs.APIGatewayPolicyConfigEntry
+		target.Name = s.Name
+
+		APIGatewayPolicyToStructs(s.GetAPIGatewayPolicy(), &target)
+		pbcommon.RaftIndexToStructs(s.RaftIndex, &target.RaftIndex)
+		pbcommon.EnterpriseMetaToStructs(s.EnterpriseMeta, &target.EnterpriseMeta)
+		return &target
 	default:
 		panic(fmt.Sprintf("unable to convert ConfigEntry of kind %s to structs", s.Kind))
 	}
@@ -177,6 +185,14 @@ func ConfigEntryFromStructs(s structs.ConfigEntry) *ConfigEntry {
 		configEntry.Entry = &ConfigEntry_HTTPRoute{
 			HTTPRoute: &route,
 		}
+	case *structs.APIGatewayPolicyConfigEntry:
+		var policy APIGatewayPolicy
+		APIGatewayPolicyFromStructs(v, &policy)
+
+		configEntry.Kind = Kind_KindAPIGatewayPolicy
+		configEntry.Entry = &ConfigEntry_APIGatewayPolicy{
+			APIGatewayPolicy: &policy,
+		}
 	default:
 		panic(fmt.Sprintf("unable to convert %T to proto", s))
 	}
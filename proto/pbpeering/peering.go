@@ -159,6 +159,15 @@ func StreamStatusToAPI(status *StreamStatus) api.PeeringStreamStatus {
 		LastHeartbeat:    TimePtrFromProto(status.LastHeartbeat),
 		LastReceive:      TimePtrFromProto(status.LastReceive),
 		LastSend:         TimePtrFromProto(status.LastSend),
+
+		MessagesReceived:    status.MessagesReceived,
+		MessagesSent:        status.MessagesSent,
+		MessagesRateLimited: status.MessagesRateLimited,
+
+		PendingResponses: status.PendingResponses,
+		RetryAttempts:    status.RetryAttempts,
+		NextRetry:        TimePtrFromProto(status.NextRetry),
+		RecentErrors:     StreamErrorsToAPI(status.RecentErrors),
 	}
 }
 
@@ -169,7 +178,46 @@ func StreamStatusFromAPI(status api.PeeringStreamStatus) *StreamStatus {
 		LastHeartbeat:    TimePtrToProto(status.LastHeartbeat),
 		LastReceive:      TimePtrToProto(status.LastReceive),
 		LastSend:         TimePtrToProto(status.LastSend),
+
+		MessagesReceived:    status.MessagesReceived,
+		MessagesSent:        status.MessagesSent,
+		MessagesRateLimited: status.MessagesRateLimited,
+
+		PendingResponses: status.PendingResponses,
+		RetryAttempts:    status.RetryAttempts,
+		NextRetry:        TimePtrToProto(status.NextRetry),
+		RecentErrors:     StreamErrorsFromAPI(status.RecentErrors),
+	}
+}
+
+func StreamErrorsToAPI(errs []*StreamError) []api.PeeringStreamError {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make([]api.PeeringStreamError, 0, len(errs))
+	for _, e := range errs {
+		out = append(out, api.PeeringStreamError{
+			Time:    e.Time.AsTime(),
+			Kind:    e.Kind,
+			Message: e.Message,
+		})
+	}
+	return out
+}
+
+func StreamErrorsFromAPI(errs []api.PeeringStreamError) []*StreamError {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make([]*StreamError, 0, len(errs))
+	for _, e := range errs {
+		out = append(out, &StreamError{
+			Time:    timestamppb.New(e.Time),
+			Kind:    e.Kind,
+			Message: e.Message,
+		})
 	}
+	return out
 }
 
 func (p *Peering) IsActive() bool {
@@ -49,6 +49,28 @@ func NewMapHeadersFromStructs(t map[string][]string) map[string]*HeaderValue {
 	return s
 }
 
+func CheckIDsToStructs(s []string) []types.CheckID {
+	if s == nil {
+		return nil
+	}
+	t := make([]types.CheckID, len(s))
+	for i, v := range s {
+		t[i] = types.CheckID(v)
+	}
+	return t
+}
+
+func NewCheckIDsFromStructs(t []types.CheckID) []string {
+	if t == nil {
+		return nil
+	}
+	s := make([]string, len(t))
+	for i, v := range t {
+		s[i] = string(v)
+	}
+	return s
+}
+
 // TODO: use mog once it supports pointers and slices
 func CheckServiceNodeToStructs(s *CheckServiceNode) (*structs.CheckServiceNode, error) {
 	if s == nil {
@@ -145,6 +167,30 @@ func NewMapStringServiceAddressFromStructs(t map[string]structs.ServiceAddress)
 	return s
 }
 
+// TODO: handle this with mog
+func ServicePortsToStructs(s []*ServicePort) structs.ServicePorts {
+	if len(s) == 0 {
+		return nil
+	}
+	t := make(structs.ServicePorts, len(s))
+	for i, v := range s {
+		t[i] = structs.ServicePort{Name: v.Name, Port: int(v.Port)}
+	}
+	return t
+}
+
+// TODO: handle this with mog
+func NewServicePortsFromStructs(t structs.ServicePorts) []*ServicePort {
+	if len(t) == 0 {
+		return nil
+	}
+	s := make([]*ServicePort, len(t))
+	for i, v := range t {
+		s[i] = &ServicePort{Name: v.Name, Port: int32(v.Port)}
+	}
+	return s
+}
+
 // TODO: handle this with mog
 func ExposePathSliceToStructs(s []*ExposePath) []structs.ExposePath {
 	t := make([]structs.ExposePath, len(s))
@@ -12,6 +12,7 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.Name = s.Name
 	t.Status = s.Status
 	t.Notes = s.Notes
+	t.PortName = s.PortName
 	t.ScriptArgs = s.ScriptArgs
 	t.HTTP = s.HTTP
 	t.H2PING = s.H2PING
@@ -25,11 +26,19 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.Interval = structs.DurationFromProto(s.Interval)
 	t.AliasNode = s.AliasNode
 	t.AliasService = s.AliasService
+	t.CompositeMode = s.CompositeMode
+	t.CompositeChecks = CheckIDsToStructs(s.CompositeChecks)
+	t.CompositeMinPassing = int(s.CompositeMinPassing)
 	t.DockerContainerID = s.DockerContainerID
 	t.Shell = s.Shell
 	t.GRPC = s.GRPC
 	t.GRPCUseTLS = s.GRPCUseTLS
+	t.GRPCService = s.GRPCService
+	t.GRPCAuthority = s.GRPCAuthority
 	t.OSService = s.OSService
+	t.ProcessPID = int(s.ProcessPID)
+	t.ProcessPIDFile = s.ProcessPIDFile
+	t.ProcessCgroup = s.ProcessCgroup
 	t.TLSServerName = s.TLSServerName
 	t.TLSSkipVerify = s.TLSSkipVerify
 	t.Timeout = structs.DurationFromProto(s.Timeout)
@@ -50,6 +59,7 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.Name = t.Name
 	s.Status = t.Status
 	s.Notes = t.Notes
+	s.PortName = t.PortName
 	s.ScriptArgs = t.ScriptArgs
 	s.HTTP = t.HTTP
 	s.H2PING = t.H2PING
@@ -63,11 +73,19 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.Interval = structs.DurationToProto(t.Interval)
 	s.AliasNode = t.AliasNode
 	s.AliasService = t.AliasService
+	s.CompositeMode = t.CompositeMode
+	s.CompositeChecks = NewCheckIDsFromStructs(t.CompositeChecks)
+	s.CompositeMinPassing = int32(t.CompositeMinPassing)
 	s.DockerContainerID = t.DockerContainerID
 	s.Shell = t.Shell
 	s.GRPC = t.GRPC
 	s.GRPCUseTLS = t.GRPCUseTLS
+	s.GRPCService = t.GRPCService
+	s.GRPCAuthority = t.GRPCAuthority
 	s.OSService = t.OSService
+	s.ProcessPID = int32(t.ProcessPID)
+	s.ProcessPIDFile = t.ProcessPIDFile
+	s.ProcessCgroup = t.ProcessCgroup
 	s.TLSServerName = t.TLSServerName
 	s.TLSSkipVerify = t.TLSSkipVerify
 	s.Timeout = structs.DurationToProto(t.Timeout)
@@ -94,6 +112,7 @@ func HealthCheckToStructs(s *HealthCheck, t *structs.HealthCheck) {
 	t.ServiceName = s.ServiceName
 	t.ServiceTags = s.ServiceTags
 	t.Type = s.Type
+	t.PortName = s.PortName
 	t.Interval = s.Interval
 	t.Timeout = s.Timeout
 	t.ExposedPort = int(s.ExposedPort)
@@ -118,6 +137,7 @@ func HealthCheckFromStructs(t *structs.HealthCheck, s *HealthCheck) {
 	s.ServiceName = t.ServiceName
 	s.ServiceTags = t.ServiceTags
 	s.Type = t.Type
+	s.PortName = t.PortName
 	s.Interval = t.Interval
 	s.Timeout = t.Timeout
 	s.ExposedPort = int32(t.ExposedPort)
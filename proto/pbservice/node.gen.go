@@ -47,6 +47,7 @@ func NodeServiceToStructs(s *NodeService, t *structs.NodeService) {
 	t.SocketPath = s.SocketPath
 	t.Weights = WeightsPtrToStructs(s.Weights)
 	t.EnableTagOverride = s.EnableTagOverride
+	t.Ports = ServicePortsToStructs(s.Ports)
 	if s.Proxy != nil {
 		ConnectProxyConfigToStructs(s.Proxy, &t.Proxy)
 	}
@@ -73,6 +74,7 @@ func NodeServiceFromStructs(t *structs.NodeService, s *NodeService) {
 	s.SocketPath = t.SocketPath
 	s.Weights = NewWeightsPtrFromStructs(t.Weights)
 	s.EnableTagOverride = t.EnableTagOverride
+	s.Ports = NewServicePortsFromStructs(t.Ports)
 	{
 		var x ConnectProxyConfig
 		ConnectProxyConfigFromStructs(&t.Proxy, &x)
@@ -3,7 +3,9 @@ package iptables
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -25,6 +27,18 @@ const (
 	DefaultTProxyOutboundPort = 15001
 )
 
+const (
+	// BackendIptables applies the rules with the iptables command.
+	BackendIptables = "iptables"
+
+	// BackendNftables applies the rules with the nft command, for hosts
+	// without iptables.
+	BackendNftables = "nftables"
+
+	// BackendAuto uses iptables when it is installed and nftables otherwise.
+	BackendAuto = "auto"
+)
+
 // Config is used to configure which traffic interception and redirection
 // rules should be applied with the iptables commands.
 type Config struct {
@@ -64,11 +78,20 @@ type Config struct {
 	// e.g. /var/run/netns/foo.
 	NetNS string
 
+	// IPv6 enables the redirection of IPv6 traffic as well, with ip6tables
+	// or the inet family of nftables.
+	IPv6 bool
+
+	// Backend is the backend used to apply the rules, one of BackendIptables,
+	// BackendNftables or BackendAuto. It defaults to BackendIptables.
+	Backend string
+
 	// IptablesProvider is the Provider that will apply iptables rules.
 	IptablesProvider Provider
 }
 
-// Provider is an interface for executing iptables rules.
+// Provider is an interface for executing iptables rules, or the nft commands
+// of the nftables backend.
 type Provider interface {
 	// AddRule adds a rule without executing it.
 	AddRule(name string, args ...string)
@@ -85,8 +108,27 @@ type Provider interface {
 // This implementation was inspired by
 // https://github.com/openservicemesh/osm/blob/650a1a1dcf081ae90825f3b5dba6f30a0e532725/pkg/injector/iptables.go
 func Setup(cfg Config) error {
+	backend := cfg.Backend
+	switch backend {
+	case "":
+		backend = BackendIptables
+	case BackendAuto:
+		var err error
+		backend, err = detectBackend()
+		if err != nil {
+			return err
+		}
+	case BackendIptables, BackendNftables:
+	default:
+		return fmt.Errorf("invalid backend %q, must be one of %q, %q or %q", backend, BackendIptables, BackendNftables, BackendAuto)
+	}
+
 	if cfg.IptablesProvider == nil {
-		cfg.IptablesProvider = &iptablesExecutor{cfg: cfg}
+		if backend == BackendNftables {
+			cfg.IptablesProvider = &nftablesExecutor{cfg: cfg}
+		} else {
+			cfg.IptablesProvider = &iptablesExecutor{cfg: cfg}
+		}
 	}
 
 	err := validateConfig(cfg)
@@ -99,84 +141,115 @@ func Setup(cfg Config) error {
 		cfg.ProxyOutboundPort = DefaultTProxyOutboundPort
 	}
 
+	if backend == BackendNftables {
+		return setupNftables(cfg)
+	}
+
+	addIptablesRules(cfg, false)
+	if cfg.IPv6 {
+		addIptablesRules(cfg, true)
+	}
+
+	return cfg.IptablesProvider.ApplyRules()
+}
+
+// addIptablesRules adds the iptables rules of Setup to the provider, or the
+// ip6tables rules for IPv6 traffic.
+func addIptablesRules(cfg Config, ipv6 bool) {
+	command, localhost := "iptables", "127.0.0.1/32"
+	if ipv6 {
+		command, localhost = "ip6tables", "::1/128"
+	}
+	addRule := func(args ...string) {
+		cfg.IptablesProvider.AddRule(command, args...)
+	}
+
 	// Create chains we will use for redirection.
 	chains := []string{ProxyInboundChain, ProxyInboundRedirectChain, ProxyOutputChain, ProxyOutputRedirectChain, DNSChain}
 	for _, chain := range chains {
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-N", chain)
+		addRule("-t", "nat", "-N", chain)
 	}
 
 	// Configure outbound rules.
 	{
 		// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyOutputRedirectChain, "-p", "tcp", "-j", "REDIRECT", "--to-port", strconv.Itoa(cfg.ProxyOutboundPort))
+		addRule("-t", "nat", "-A", ProxyOutputRedirectChain, "-p", "tcp", "-j", "REDIRECT", "--to-port", strconv.Itoa(cfg.ProxyOutboundPort))
 
 		// The DNS rules are applied before the rules that directs all TCP traffic, so that the traffic going to port 53 goes through this rule first.
-		if cfg.ConsulDNSIP != "" && cfg.ConsulDNSPort == 0 {
+		// They are only applied for the IP family of the Consul DNS IP.
+		if cfg.ConsulDNSIP != "" && cfg.ConsulDNSPort == 0 && isIPv6(cfg.ConsulDNSIP) == ipv6 {
 			// Traffic in the DNSChain is directed to the Consul DNS Service IP.
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", DNSChain, "-p", "udp", "--dport", "53", "-j", "DNAT", "--to-destination", cfg.ConsulDNSIP)
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", DNSChain, "-p", "tcp", "--dport", "53", "-j", "DNAT", "--to-destination", cfg.ConsulDNSIP)
+			addRule("-t", "nat", "-A", DNSChain, "-p", "udp", "--dport", "53", "-j", "DNAT", "--to-destination", cfg.ConsulDNSIP)
+			addRule("-t", "nat", "-A", DNSChain, "-p", "tcp", "--dport", "53", "-j", "DNAT", "--to-destination", cfg.ConsulDNSIP)
 
 			// For outbound TCP and UDP traffic going to port 53 (DNS), jump to the DNSChain.
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "OUTPUT", "-p", "udp", "--dport", "53", "-j", DNSChain)
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "OUTPUT", "-p", "tcp", "--dport", "53", "-j", DNSChain)
-		} else if cfg.ConsulDNSPort != 0 {
+			addRule("-t", "nat", "-A", "OUTPUT", "-p", "udp", "--dport", "53", "-j", DNSChain)
+			addRule("-t", "nat", "-A", "OUTPUT", "-p", "tcp", "--dport", "53", "-j", DNSChain)
+		} else if cfg.ConsulDNSPort != 0 && isIPv6(cfg.ConsulDNSIP) == ipv6 {
 			consulDNSIP := "127.0.0.1"
 			if cfg.ConsulDNSIP != "" {
 				consulDNSIP = cfg.ConsulDNSIP
 			}
-			consulDNSHostPort := fmt.Sprintf("%s:%d", consulDNSIP, cfg.ConsulDNSPort)
+			consulDNSHostPort := net.JoinHostPort(consulDNSIP, strconv.Itoa(cfg.ConsulDNSPort))
 			// Traffic in the DNSChain is directed to the Consul DNS Service IP.
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", DNSChain, "-p", "udp", "-d", consulDNSIP, "--dport", "53", "-j", "DNAT", "--to-destination", consulDNSHostPort)
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", DNSChain, "-p", "tcp", "-d", consulDNSIP, "--dport", "53", "-j", "DNAT", "--to-destination", consulDNSHostPort)
+			addRule("-t", "nat", "-A", DNSChain, "-p", "udp", "-d", consulDNSIP, "--dport", "53", "-j", "DNAT", "--to-destination", consulDNSHostPort)
+			addRule("-t", "nat", "-A", DNSChain, "-p", "tcp", "-d", consulDNSIP, "--dport", "53", "-j", "DNAT", "--to-destination", consulDNSHostPort)
 
 			// For outbound TCP and UDP traffic going to port 53 (DNS), jump to the DNSChain. Only redirect traffic that's going to consul's DNS IP.
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "OUTPUT", "-p", "udp", "-d", consulDNSIP, "--dport", "53", "-j", DNSChain)
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "OUTPUT", "-p", "tcp", "-d", consulDNSIP, "--dport", "53", "-j", DNSChain)
+			addRule("-t", "nat", "-A", "OUTPUT", "-p", "udp", "-d", consulDNSIP, "--dport", "53", "-j", DNSChain)
+			addRule("-t", "nat", "-A", "OUTPUT", "-p", "tcp", "-d", consulDNSIP, "--dport", "53", "-j", DNSChain)
 		}
 
 		// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "OUTPUT", "-p", "tcp", "-j", ProxyOutputChain)
+		addRule("-t", "nat", "-A", "OUTPUT", "-p", "tcp", "-j", ProxyOutputChain)
 
 		// Don't redirect proxy traffic back to itself, return it to the next chain for processing.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyOutputChain, "-m", "owner", "--uid-owner", cfg.ProxyUserID, "-j", "RETURN")
+		addRule("-t", "nat", "-A", ProxyOutputChain, "-m", "owner", "--uid-owner", cfg.ProxyUserID, "-j", "RETURN")
 
 		// Skip localhost traffic, doesn't need to be routed via the proxy.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyOutputChain, "-d", "127.0.0.1/32", "-j", "RETURN")
+		addRule("-t", "nat", "-A", ProxyOutputChain, "-d", localhost, "-j", "RETURN")
 
 		// Redirect remaining outbound traffic to Envoy.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyOutputChain, "-j", ProxyOutputRedirectChain)
+		addRule("-t", "nat", "-A", ProxyOutputChain, "-j", ProxyOutputRedirectChain)
 
 		// We are using "insert" (-I) instead of "append" (-A) so the the provided rules take precedence over default ones.
 		for _, outboundPort := range cfg.ExcludeOutboundPorts {
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-I", ProxyOutputChain, "-p", "tcp", "--dport", outboundPort, "-j", "RETURN")
+			addRule("-t", "nat", "-I", ProxyOutputChain, "-p", "tcp", "--dport", outboundPort, "-j", "RETURN")
 		}
 
 		for _, outboundIP := range cfg.ExcludeOutboundCIDRs {
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-I", ProxyOutputChain, "-d", outboundIP, "-j", "RETURN")
+			// The CIDRs are split between iptables and ip6tables by family.
+			if cfg.IPv6 && isIPv6(outboundIP) != ipv6 {
+				continue
+			}
+			addRule("-t", "nat", "-I", ProxyOutputChain, "-d", outboundIP, "-j", "RETURN")
 		}
 
 		for _, uid := range cfg.ExcludeUIDs {
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-I", ProxyOutputChain, "-m", "owner", "--uid-owner", uid, "-j", "RETURN")
+			addRule("-t", "nat", "-I", ProxyOutputChain, "-m", "owner", "--uid-owner", uid, "-j", "RETURN")
 		}
 	}
 
 	// Configure inbound rules.
 	{
 		// Redirects inbound TCP traffic hitting the PROXY_IN_REDIRECT chain to Envoy's inbound listener port.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyInboundRedirectChain, "-p", "tcp", "-j", "REDIRECT", "--to-port", strconv.Itoa(cfg.ProxyInboundPort))
+		addRule("-t", "nat", "-A", ProxyInboundRedirectChain, "-p", "tcp", "-j", "REDIRECT", "--to-port", strconv.Itoa(cfg.ProxyInboundPort))
 
 		// For inbound traffic jump from PREROUTING chain to PROXY_INBOUND chain.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", "PREROUTING", "-p", "tcp", "-j", ProxyInboundChain)
+		addRule("-t", "nat", "-A", "PREROUTING", "-p", "tcp", "-j", ProxyInboundChain)
 
 		// Redirect remaining inbound traffic to Envoy.
-		cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-A", ProxyInboundChain, "-p", "tcp", "-j", ProxyInboundRedirectChain)
+		addRule("-t", "nat", "-A", ProxyInboundChain, "-p", "tcp", "-j", ProxyInboundRedirectChain)
 
 		for _, inboundPort := range cfg.ExcludeInboundPorts {
-			cfg.IptablesProvider.AddRule("iptables", "-t", "nat", "-I", ProxyInboundChain, "-p", "tcp", "--dport", inboundPort, "-j", "RETURN")
+			addRule("-t", "nat", "-I", ProxyInboundChain, "-p", "tcp", "--dport", inboundPort, "-j", "RETURN")
 		}
 	}
+}
 
-	return cfg.IptablesProvider.ApplyRules()
+// isIPv6 returns whether the IP address or CIDR is an IPv6 one.
+func isIPv6(addr string) bool {
+	return strings.Contains(addr, ":")
 }
 
 func validateConfig(cfg Config) error {
@@ -188,5 +261,9 @@ func validateConfig(cfg Config) error {
 		return errors.New("ProxyInboundPort is required to set up traffic redirection")
 	}
 
+	if isIPv6(cfg.ConsulDNSIP) && !cfg.IPv6 {
+		return fmt.Errorf("ConsulDNSIP %s is an IPv6 address, which requires IPv6 to be enabled", cfg.ConsulDNSIP)
+	}
+
 	return nil
 }
@@ -253,6 +253,187 @@ func TestSetup(t *testing.T) {
 				"iptables -t nat -A CONSUL_PROXY_INBOUND -p tcp -j CONSUL_PROXY_IN_REDIRECT",
 			},
 		},
+		{
+			"IPv6 enabled",
+			Config{
+				ProxyUserID:          "123",
+				ProxyInboundPort:     20000,
+				ConsulDNSIP:          "fd00::53",
+				ConsulDNSPort:        8600,
+				ExcludeOutboundCIDRs: []string{"1.1.1.1/32", "2001:db8::/32"},
+				IPv6:                 true,
+				IptablesProvider:     &fakeIptablesProvider{},
+			},
+			[]string{
+				"iptables -t nat -N CONSUL_PROXY_INBOUND",
+				"iptables -t nat -N CONSUL_PROXY_IN_REDIRECT",
+				"iptables -t nat -N CONSUL_PROXY_OUTPUT",
+				"iptables -t nat -N CONSUL_PROXY_REDIRECT",
+				"iptables -t nat -N CONSUL_DNS_REDIRECT",
+				"iptables -t nat -A CONSUL_PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001",
+				"iptables -t nat -A OUTPUT -p tcp -j CONSUL_PROXY_OUTPUT",
+				"iptables -t nat -A CONSUL_PROXY_OUTPUT -m owner --uid-owner 123 -j RETURN",
+				"iptables -t nat -A CONSUL_PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN",
+				"iptables -t nat -A CONSUL_PROXY_OUTPUT -j CONSUL_PROXY_REDIRECT",
+				"iptables -t nat -I CONSUL_PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN",
+				"iptables -t nat -A CONSUL_PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 20000",
+				"iptables -t nat -A PREROUTING -p tcp -j CONSUL_PROXY_INBOUND",
+				"iptables -t nat -A CONSUL_PROXY_INBOUND -p tcp -j CONSUL_PROXY_IN_REDIRECT",
+				"ip6tables -t nat -N CONSUL_PROXY_INBOUND",
+				"ip6tables -t nat -N CONSUL_PROXY_IN_REDIRECT",
+				"ip6tables -t nat -N CONSUL_PROXY_OUTPUT",
+				"ip6tables -t nat -N CONSUL_PROXY_REDIRECT",
+				"ip6tables -t nat -N CONSUL_DNS_REDIRECT",
+				"ip6tables -t nat -A CONSUL_PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001",
+				"ip6tables -t nat -A CONSUL_DNS_REDIRECT -p udp -d fd00::53 --dport 53 -j DNAT --to-destination [fd00::53]:8600",
+				"ip6tables -t nat -A CONSUL_DNS_REDIRECT -p tcp -d fd00::53 --dport 53 -j DNAT --to-destination [fd00::53]:8600",
+				"ip6tables -t nat -A OUTPUT -p udp -d fd00::53 --dport 53 -j CONSUL_DNS_REDIRECT",
+				"ip6tables -t nat -A OUTPUT -p tcp -d fd00::53 --dport 53 -j CONSUL_DNS_REDIRECT",
+				"ip6tables -t nat -A OUTPUT -p tcp -j CONSUL_PROXY_OUTPUT",
+				"ip6tables -t nat -A CONSUL_PROXY_OUTPUT -m owner --uid-owner 123 -j RETURN",
+				"ip6tables -t nat -A CONSUL_PROXY_OUTPUT -d ::1/128 -j RETURN",
+				"ip6tables -t nat -A CONSUL_PROXY_OUTPUT -j CONSUL_PROXY_REDIRECT",
+				"ip6tables -t nat -I CONSUL_PROXY_OUTPUT -d 2001:db8::/32 -j RETURN",
+				"ip6tables -t nat -A CONSUL_PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 20000",
+				"ip6tables -t nat -A PREROUTING -p tcp -j CONSUL_PROXY_INBOUND",
+				"ip6tables -t nat -A CONSUL_PROXY_INBOUND -p tcp -j CONSUL_PROXY_IN_REDIRECT",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Setup(c.cfg)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedRules, c.cfg.IptablesProvider.Rules())
+		})
+	}
+}
+
+func TestSetup_Nftables(t *testing.T) {
+	cases := []struct {
+		name          string
+		cfg           Config
+		expectedRules []string
+	}{
+		{
+			"no proxy outbound port provided",
+			Config{
+				ProxyUserID:      "123",
+				ProxyInboundPort: 20000,
+				Backend:          BackendNftables,
+				IptablesProvider: &fakeIptablesProvider{},
+			},
+			[]string{
+				"nft add table ip consul",
+				"nft delete table ip consul",
+				"nft add table inet consul",
+				"nft delete table inet consul",
+				"nft add table ip consul",
+				"nft add chain ip consul OUTPUT { type nat hook output priority -100 ; }",
+				"nft add chain ip consul PREROUTING { type nat hook prerouting priority -100 ; }",
+				"nft add chain ip consul CONSUL_PROXY_INBOUND",
+				"nft add chain ip consul CONSUL_PROXY_IN_REDIRECT",
+				"nft add chain ip consul CONSUL_PROXY_OUTPUT",
+				"nft add chain ip consul CONSUL_PROXY_REDIRECT",
+				"nft add chain ip consul CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_REDIRECT meta l4proto tcp redirect to :15001",
+				"nft add rule ip consul OUTPUT meta l4proto tcp jump CONSUL_PROXY_OUTPUT",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT meta skuid 123 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT ip daddr 127.0.0.1/32 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT jump CONSUL_PROXY_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_IN_REDIRECT meta l4proto tcp redirect to :20000",
+				"nft add rule ip consul PREROUTING meta l4proto tcp jump CONSUL_PROXY_INBOUND",
+				"nft add rule ip consul CONSUL_PROXY_INBOUND meta l4proto tcp jump CONSUL_PROXY_IN_REDIRECT",
+			},
+		},
+		{
+			"Consul DNS IP and port provided with exclusions",
+			Config{
+				ProxyUserID:          "123",
+				ProxyInboundPort:     20000,
+				ProxyOutboundPort:    21000,
+				ConsulDNSIP:          "10.0.34.16",
+				ConsulDNSPort:        8600,
+				ExcludeInboundPorts:  []string{"22000", "8000:8100"},
+				ExcludeOutboundPorts: []string{"22500"},
+				ExcludeOutboundCIDRs: []string{"1.1.1.1/32"},
+				ExcludeUIDs:          []string{"456"},
+				Backend:              BackendNftables,
+				IptablesProvider:     &fakeIptablesProvider{},
+			},
+			[]string{
+				"nft add table ip consul",
+				"nft delete table ip consul",
+				"nft add table inet consul",
+				"nft delete table inet consul",
+				"nft add table ip consul",
+				"nft add chain ip consul OUTPUT { type nat hook output priority -100 ; }",
+				"nft add chain ip consul PREROUTING { type nat hook prerouting priority -100 ; }",
+				"nft add chain ip consul CONSUL_PROXY_INBOUND",
+				"nft add chain ip consul CONSUL_PROXY_IN_REDIRECT",
+				"nft add chain ip consul CONSUL_PROXY_OUTPUT",
+				"nft add chain ip consul CONSUL_PROXY_REDIRECT",
+				"nft add chain ip consul CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_REDIRECT meta l4proto tcp redirect to :21000",
+				"nft add rule ip consul CONSUL_DNS_REDIRECT ip daddr 10.0.34.16 udp dport 53 dnat to 10.0.34.16:8600",
+				"nft add rule ip consul CONSUL_DNS_REDIRECT ip daddr 10.0.34.16 tcp dport 53 dnat to 10.0.34.16:8600",
+				"nft add rule ip consul OUTPUT ip daddr 10.0.34.16 udp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul OUTPUT ip daddr 10.0.34.16 tcp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul OUTPUT meta l4proto tcp jump CONSUL_PROXY_OUTPUT",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT meta skuid 123 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT ip daddr 127.0.0.1/32 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT jump CONSUL_PROXY_REDIRECT",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT tcp dport 22500 return",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT ip daddr 1.1.1.1/32 return",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT meta skuid 456 return",
+				"nft add rule ip consul CONSUL_PROXY_IN_REDIRECT meta l4proto tcp redirect to :20000",
+				"nft add rule ip consul PREROUTING meta l4proto tcp jump CONSUL_PROXY_INBOUND",
+				"nft add rule ip consul CONSUL_PROXY_INBOUND meta l4proto tcp jump CONSUL_PROXY_IN_REDIRECT",
+				"nft insert rule ip consul CONSUL_PROXY_INBOUND tcp dport 22000 return",
+				"nft insert rule ip consul CONSUL_PROXY_INBOUND tcp dport 8000-8100 return",
+			},
+		},
+		{
+			"IPv6 enabled",
+			Config{
+				ProxyUserID:          "123",
+				ProxyInboundPort:     20000,
+				ConsulDNSIP:          "10.0.34.16",
+				ExcludeOutboundCIDRs: []string{"2001:db8::/32"},
+				IPv6:                 true,
+				Backend:              BackendNftables,
+				IptablesProvider:     &fakeIptablesProvider{},
+			},
+			[]string{
+				"nft add table ip consul",
+				"nft delete table ip consul",
+				"nft add table inet consul",
+				"nft delete table inet consul",
+				"nft add table inet consul",
+				"nft add chain inet consul OUTPUT { type nat hook output priority -100 ; }",
+				"nft add chain inet consul PREROUTING { type nat hook prerouting priority -100 ; }",
+				"nft add chain inet consul CONSUL_PROXY_INBOUND",
+				"nft add chain inet consul CONSUL_PROXY_IN_REDIRECT",
+				"nft add chain inet consul CONSUL_PROXY_OUTPUT",
+				"nft add chain inet consul CONSUL_PROXY_REDIRECT",
+				"nft add chain inet consul CONSUL_DNS_REDIRECT",
+				"nft add rule inet consul CONSUL_PROXY_REDIRECT meta l4proto tcp redirect to :15001",
+				"nft add rule inet consul CONSUL_DNS_REDIRECT udp dport 53 dnat ip to 10.0.34.16",
+				"nft add rule inet consul CONSUL_DNS_REDIRECT tcp dport 53 dnat ip to 10.0.34.16",
+				"nft add rule inet consul OUTPUT udp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule inet consul OUTPUT tcp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule inet consul OUTPUT meta l4proto tcp jump CONSUL_PROXY_OUTPUT",
+				"nft add rule inet consul CONSUL_PROXY_OUTPUT meta skuid 123 return",
+				"nft add rule inet consul CONSUL_PROXY_OUTPUT ip daddr 127.0.0.1/32 return",
+				"nft add rule inet consul CONSUL_PROXY_OUTPUT ip6 daddr ::1/128 return",
+				"nft add rule inet consul CONSUL_PROXY_OUTPUT jump CONSUL_PROXY_REDIRECT",
+				"nft insert rule inet consul CONSUL_PROXY_OUTPUT ip6 daddr 2001:db8::/32 return",
+				"nft add rule inet consul CONSUL_PROXY_IN_REDIRECT meta l4proto tcp redirect to :20000",
+				"nft add rule inet consul PREROUTING meta l4proto tcp jump CONSUL_PROXY_INBOUND",
+				"nft add rule inet consul CONSUL_PROXY_INBOUND meta l4proto tcp jump CONSUL_PROXY_IN_REDIRECT",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -286,6 +467,26 @@ func TestSetup_errors(t *testing.T) {
 			},
 			"ProxyInboundPort is required to set up traffic redirection",
 		},
+		{
+			"invalid backend",
+			Config{
+				ProxyUserID:      "123",
+				ProxyInboundPort: 20000,
+				Backend:          "ebpf",
+				IptablesProvider: &iptablesExecutor{},
+			},
+			`invalid backend "ebpf", must be one of "iptables", "nftables" or "auto"`,
+		},
+		{
+			"IPv6 Consul DNS IP without IPv6",
+			Config{
+				ProxyUserID:      "123",
+				ProxyInboundPort: 20000,
+				ConsulDNSIP:      "fd00::53",
+				IptablesProvider: &iptablesExecutor{},
+			},
+			"ConsulDNSIP fd00::53 is an IPv6 address, which requires IPv6 to be enabled",
+		},
 	}
 
 	for _, c := range cases {
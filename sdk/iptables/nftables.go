@@ -0,0 +1,169 @@
+package iptables
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// NftablesTable is the nftables table holding the traffic redirection
+	// chains. It is replaced as a whole whenever the rules are applied.
+	NftablesTable = "consul"
+
+	// nftablesNatPriority is the priority of the nat hooks used by iptables,
+	// so that both backends redirect traffic at the same point.
+	nftablesNatPriority = "-100"
+)
+
+// setupNftables adds the nftables equivalent of the iptables rules of Setup
+// to the provider. Every rule is an nft command, and the commands are meant
+// to be applied in a single transaction.
+//
+// The table is added, deleted and added again first, which makes applying
+// the rules idempotent: the previous rules are replaced atomically rather
+// than duplicated. The table of the other family is deleted the same way, in
+// case IPv6 was enabled or disabled since the rules were last applied.
+//
+// The rules are in a table of the ip family, or of the inet family when IPv6
+// is enabled so that they apply to both IPv4 and IPv6 traffic.
+func setupNftables(cfg Config) error {
+	p := cfg.IptablesProvider
+	family := "ip"
+	if cfg.IPv6 {
+		family = "inet"
+	}
+	addRule := func(chain string, args ...string) {
+		p.AddRule("nft", append([]string{"add", "rule", family, NftablesTable, chain}, args...)...)
+	}
+	insertRule := func(chain string, args ...string) {
+		p.AddRule("nft", append([]string{"insert", "rule", family, NftablesTable, chain}, args...)...)
+	}
+	// daddr matches the destination address, in the family of the address.
+	daddr := func(addr string) []string {
+		if isIPv6(addr) {
+			return []string{"ip6", "daddr", addr}
+		}
+		return []string{"ip", "daddr", addr}
+	}
+	// dnatTo translates the destination address. The family of the address
+	// must be given in the inet family.
+	dnatTo := func(addr string) []string {
+		if family != "inet" {
+			return []string{"dnat", "to", addr}
+		}
+		if isIPv6(addr) {
+			return []string{"dnat", "ip6", "to", addr}
+		}
+		return []string{"dnat", "ip", "to", addr}
+	}
+	concat := func(args ...[]string) []string {
+		var result []string
+		for _, a := range args {
+			result = append(result, a...)
+		}
+		return result
+	}
+
+	for _, f := range []string{"ip", "inet"} {
+		p.AddRule("nft", "add", "table", f, NftablesTable)
+		p.AddRule("nft", "delete", "table", f, NftablesTable)
+	}
+	p.AddRule("nft", "add", "table", family, NftablesTable)
+
+	// Base chains hooking into the nat hooks, in place of the built-in
+	// chains of iptables.
+	p.AddRule("nft", "add", "chain", family, NftablesTable, "OUTPUT",
+		fmt.Sprintf("{ type nat hook output priority %s ; }", nftablesNatPriority))
+	p.AddRule("nft", "add", "chain", family, NftablesTable, "PREROUTING",
+		fmt.Sprintf("{ type nat hook prerouting priority %s ; }", nftablesNatPriority))
+
+	// Create chains we will use for redirection.
+	chains := []string{ProxyInboundChain, ProxyInboundRedirectChain, ProxyOutputChain, ProxyOutputRedirectChain, DNSChain}
+	for _, chain := range chains {
+		p.AddRule("nft", "add", "chain", family, NftablesTable, chain)
+	}
+
+	// Configure outbound rules.
+	{
+		// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port.
+		addRule(ProxyOutputRedirectChain, "meta", "l4proto", "tcp", "redirect", "to", ":"+strconv.Itoa(cfg.ProxyOutboundPort))
+
+		// The DNS rules are applied before the rules that directs all TCP traffic, so that the traffic going to port 53 goes through this rule first.
+		if cfg.ConsulDNSIP != "" && cfg.ConsulDNSPort == 0 {
+			// Traffic in the DNSChain is directed to the Consul DNS Service IP.
+			addRule(DNSChain, concat([]string{"udp", "dport", "53"}, dnatTo(cfg.ConsulDNSIP))...)
+			addRule(DNSChain, concat([]string{"tcp", "dport", "53"}, dnatTo(cfg.ConsulDNSIP))...)
+
+			// For outbound TCP and UDP traffic going to port 53 (DNS), jump to the DNSChain.
+			addRule("OUTPUT", "udp", "dport", "53", "jump", DNSChain)
+			addRule("OUTPUT", "tcp", "dport", "53", "jump", DNSChain)
+		} else if cfg.ConsulDNSPort != 0 {
+			consulDNSIP := "127.0.0.1"
+			if cfg.ConsulDNSIP != "" {
+				consulDNSIP = cfg.ConsulDNSIP
+			}
+			consulDNSHostPort := net.JoinHostPort(consulDNSIP, strconv.Itoa(cfg.ConsulDNSPort))
+			// Traffic in the DNSChain is directed to the Consul DNS Service IP.
+			addRule(DNSChain, concat(daddr(consulDNSIP), []string{"udp", "dport", "53"}, dnatTo(consulDNSHostPort))...)
+			addRule(DNSChain, concat(daddr(consulDNSIP), []string{"tcp", "dport", "53"}, dnatTo(consulDNSHostPort))...)
+
+			// For outbound TCP and UDP traffic going to port 53 (DNS), jump to the DNSChain. Only redirect traffic that's going to consul's DNS IP.
+			addRule("OUTPUT", concat(daddr(consulDNSIP), []string{"udp", "dport", "53", "jump", DNSChain})...)
+			addRule("OUTPUT", concat(daddr(consulDNSIP), []string{"tcp", "dport", "53", "jump", DNSChain})...)
+		}
+
+		// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain.
+		addRule("OUTPUT", "meta", "l4proto", "tcp", "jump", ProxyOutputChain)
+
+		// Don't redirect proxy traffic back to itself, return it to the next chain for processing.
+		addRule(ProxyOutputChain, "meta", "skuid", cfg.ProxyUserID, "return")
+
+		// Skip localhost traffic, doesn't need to be routed via the proxy.
+		addRule(ProxyOutputChain, "ip", "daddr", "127.0.0.1/32", "return")
+		if cfg.IPv6 {
+			addRule(ProxyOutputChain, "ip6", "daddr", "::1/128", "return")
+		}
+
+		// Redirect remaining outbound traffic to Envoy.
+		addRule(ProxyOutputChain, "jump", ProxyOutputRedirectChain)
+
+		// We are using "insert" instead of "add" so the the provided rules take precedence over default ones.
+		for _, outboundPort := range cfg.ExcludeOutboundPorts {
+			insertRule(ProxyOutputChain, "tcp", "dport", nftablesPort(outboundPort), "return")
+		}
+
+		for _, outboundIP := range cfg.ExcludeOutboundCIDRs {
+			insertRule(ProxyOutputChain, append(daddr(outboundIP), "return")...)
+		}
+
+		for _, uid := range cfg.ExcludeUIDs {
+			insertRule(ProxyOutputChain, "meta", "skuid", uid, "return")
+		}
+	}
+
+	// Configure inbound rules.
+	{
+		// Redirects inbound TCP traffic hitting the PROXY_IN_REDIRECT chain to Envoy's inbound listener port.
+		addRule(ProxyInboundRedirectChain, "meta", "l4proto", "tcp", "redirect", "to", ":"+strconv.Itoa(cfg.ProxyInboundPort))
+
+		// For inbound traffic jump from PREROUTING chain to PROXY_INBOUND chain.
+		addRule("PREROUTING", "meta", "l4proto", "tcp", "jump", ProxyInboundChain)
+
+		// Redirect remaining inbound traffic to Envoy.
+		addRule(ProxyInboundChain, "meta", "l4proto", "tcp", "jump", ProxyInboundRedirectChain)
+
+		for _, inboundPort := range cfg.ExcludeInboundPorts {
+			insertRule(ProxyInboundChain, "tcp", "dport", nftablesPort(inboundPort), "return")
+		}
+	}
+
+	return p.ApplyRules()
+}
+
+// nftablesPort converts a port or an iptables port range, such as
+// "8000:8100", to the nftables syntax.
+func nftablesPort(port string) string {
+	return strings.Replace(port, ":", "-", 1)
+}
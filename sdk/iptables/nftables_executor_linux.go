@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+package iptables
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// nftablesExecutor implements Provider by applying the rules as a single
+// nft script, so that they are applied atomically.
+type nftablesExecutor struct {
+	rules []string
+	cfg   Config
+}
+
+func (n *nftablesExecutor) AddRule(_ string, args ...string) {
+	n.rules = append(n.rules, strings.Join(args, " "))
+}
+
+func (n *nftablesExecutor) ApplyRules() error {
+	_, err := exec.LookPath("nft")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-f", "-"}
+	var cmd *exec.Cmd
+	if n.cfg.NetNS != "" {
+		// If network namespace is provided, then we need to execute the command in the given network namespace.
+		cmd = exec.Command("nsenter", append([]string{fmt.Sprintf("--net=%s", n.cfg.NetNS), "--", "nft"}, args...)...)
+	} else {
+		cmd = exec.Command("nft", args...)
+	}
+
+	var cmdOutput bytes.Buffer
+	cmd.Stdin = strings.NewReader(strings.Join(n.rules, "\n") + "\n")
+	cmd.Stdout = &cmdOutput
+	cmd.Stderr = &cmdOutput
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run command: %s, err: %v, output: %s", cmd.String(), err, cmdOutput.String())
+	}
+	return nil
+}
+
+func (n *nftablesExecutor) Rules() []string {
+	var rules []string
+	for _, rule := range n.rules {
+		rules = append(rules, "nft "+rule)
+	}
+	return rules
+}
+
+// detectBackend returns the backend available on the host. iptables is
+// preferred when both are installed, since it was the only backend before.
+func detectBackend() (string, error) {
+	if _, err := exec.LookPath("iptables"); err == nil {
+		return BackendIptables, nil
+	}
+	if _, err := exec.LookPath("nft"); err == nil {
+		return BackendNftables, nil
+	}
+	return "", errors.New("neither the iptables nor the nft command line utility is installed")
+}
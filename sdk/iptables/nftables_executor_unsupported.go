@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package iptables
+
+import "errors"
+
+// nftablesExecutor implements Provider and errors out on any non-linux OS.
+type nftablesExecutor struct {
+	cfg Config
+}
+
+func (n *nftablesExecutor) AddRule(_ string, _ ...string) {}
+
+func (n *nftablesExecutor) ApplyRules() error {
+	return errors.New("applying traffic redirection rules with 'nft' is not supported on this operating system; only linux OS is supported")
+}
+
+func (n *nftablesExecutor) Rules() []string {
+	return nil
+}
+
+func detectBackend() (string, error) {
+	return "", errors.New("traffic redirection is not supported on this operating system; only linux OS is supported")
+}
@@ -0,0 +1,253 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+// TestTopologyConfig describes the clusters of a TestTopology.
+type TestTopologyConfig struct {
+	// Datacenters are the names of the clusters, each of which is made of a
+	// single test server. The names are also used as the names of the peers.
+	Datacenters []string
+
+	// WANFederation joins the clusters over the WAN, with the first one as
+	// the primary datacenter, instead of peering them.
+	WANFederation bool
+
+	// ServerConfig is an optional callback to modify the configuration of
+	// the server of each cluster.
+	ServerConfig func(datacenter string, c *TestServerConfig)
+}
+
+// TestTopology is a set of test clusters that are either peered with each
+// other or federated over the WAN.
+type TestTopology struct {
+	Config TestTopologyConfig
+
+	// Servers are the test servers by datacenter.
+	Servers map[string]*TestServer
+}
+
+// NewTestTopology starts a test server for each datacenter, waits for them
+// to elect their leaders and then either peers every pair of clusters or
+// joins them over the WAN. If there is an error, none of the servers are
+// running when the function returns.
+func NewTestTopology(t testing.TB, cfg TestTopologyConfig) (*TestTopology, error) {
+	if len(cfg.Datacenters) == 0 {
+		return nil, fmt.Errorf("at least one datacenter is required")
+	}
+
+	topo := &TestTopology{
+		Config:  cfg,
+		Servers: make(map[string]*TestServer, len(cfg.Datacenters)),
+	}
+	for _, dc := range cfg.Datacenters {
+		if _, ok := topo.Servers[dc]; ok {
+			topo.Stop()
+			return nil, fmt.Errorf("duplicate datacenter %q", dc)
+		}
+		dc := dc
+		srv, err := NewTestServerConfigT(t, func(c *TestServerConfig) {
+			c.Datacenter = dc
+			if cfg.WANFederation {
+				c.PrimaryDatacenter = cfg.Datacenters[0]
+			}
+			if cfg.ServerConfig != nil {
+				cfg.ServerConfig(dc, c)
+			}
+		})
+		if err != nil {
+			topo.Stop()
+			return nil, errors.Wrapf(err, "failed starting the server of %s", dc)
+		}
+		topo.Servers[dc] = srv
+	}
+	for _, dc := range cfg.Datacenters {
+		topo.Servers[dc].WaitForLeader(t)
+	}
+
+	if cfg.WANFederation {
+		primary := topo.Servers[cfg.Datacenters[0]]
+		for _, dc := range cfg.Datacenters[1:] {
+			topo.Servers[dc].JoinWAN(t, primary.WANAddr)
+		}
+		for _, dc := range cfg.Datacenters {
+			topo.Servers[dc].waitForDatacenters(t, len(cfg.Datacenters))
+		}
+		return topo, nil
+	}
+
+	for i, dialer := range cfg.Datacenters {
+		for _, acceptor := range cfg.Datacenters[i+1:] {
+			if err := topo.Peer(t, acceptor, dialer); err != nil {
+				topo.Stop()
+				return nil, err
+			}
+		}
+	}
+	return topo, nil
+}
+
+// Stop stops the servers of all the clusters.
+func (topo *TestTopology) Stop() error {
+	var firstErr error
+	for _, srv := range topo.Servers {
+		if err := srv.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Peer establishes a peering between two clusters: the acceptor generates a
+// peering token for the dialer, which uses it to connect. The peers are named
+// after the datacenter of the other cluster. It waits until the peering is
+// active on both sides.
+func (topo *TestTopology) Peer(t testing.TB, acceptor, dialer string) error {
+	acceptorSrv, dialerSrv := topo.Servers[acceptor], topo.Servers[dialer]
+	if acceptorSrv == nil || dialerSrv == nil {
+		return fmt.Errorf("unknown datacenter %q or %q", acceptor, dialer)
+	}
+
+	var token struct {
+		PeeringToken string
+	}
+	err := acceptorSrv.privilegedJSON("POST", "/v1/peering/token",
+		map[string]string{"PeerName": dialer}, &token)
+	if err != nil {
+		return errors.Wrapf(err, "failed generating a peering token in %s", acceptor)
+	}
+
+	err = dialerSrv.privilegedJSON("POST", "/v1/peering/establish",
+		map[string]string{"PeerName": acceptor, "PeeringToken": token.PeeringToken}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed establishing the peering from %s", dialer)
+	}
+
+	acceptorSrv.WaitForPeeringActive(t, dialer)
+	dialerSrv.WaitForPeeringActive(t, acceptor)
+	return nil
+}
+
+// ExportServices exports services of a cluster to all the other clusters,
+// replacing the services it exported before. Services can only be exported
+// to peers, so this is a no-op for clusters federated over the WAN.
+func (topo *TestTopology) ExportServices(t testing.TB, datacenter string, services ...string) {
+	if topo.Config.WANFederation {
+		return
+	}
+
+	type consumer struct {
+		Peer string
+	}
+	type exportedService struct {
+		Name      string
+		Consumers []consumer
+	}
+	var consumers []consumer
+	for _, dc := range topo.Config.Datacenters {
+		if dc != datacenter {
+			consumers = append(consumers, consumer{Peer: dc})
+		}
+	}
+	exported := make([]exportedService, 0, len(services))
+	for _, name := range services {
+		exported = append(exported, exportedService{Name: name, Consumers: consumers})
+	}
+
+	entry := map[string]interface{}{
+		"Kind":     "exported-services",
+		"Name":     "default",
+		"Services": exported,
+	}
+	if err := topo.Servers[datacenter].privilegedJSON("PUT", "/v1/config", entry, nil); err != nil {
+		t.Fatalf("failed exporting services from %s: %s", datacenter, err)
+	}
+}
+
+// WaitForImportedService waits until a cluster sees at least one instance of
+// a service imported from a peer.
+func (topo *TestTopology) WaitForImportedService(t testing.TB, datacenter, peer, service string) {
+	srv := topo.Servers[datacenter]
+	retry.Run(t, func(r *retry.R) {
+		var nodes []interface{}
+		path := "/v1/health/service/" + url.PathEscape(service) + "?peer=" + url.QueryEscape(peer)
+		if err := srv.privilegedJSON("GET", path, nil, &nodes); err != nil {
+			r.Fatal(err)
+		}
+		if len(nodes) == 0 {
+			r.Fatalf("service %q not imported from %q yet", service, peer)
+		}
+	})
+}
+
+// WaitForPeeringActive waits until the peering with the given name is active.
+func (s *TestServer) WaitForPeeringActive(t testing.TB, peerName string) {
+	retry.Run(t, func(r *retry.R) {
+		var peering struct {
+			State string
+		}
+		if err := s.privilegedJSON("GET", "/v1/peering/"+url.PathEscape(peerName), nil, &peering); err != nil {
+			r.Fatal(err)
+		}
+		if peering.State != "ACTIVE" {
+			r.Fatalf("peering %q is %s", peerName, peering.State)
+		}
+	})
+}
+
+// waitForDatacenters waits until the server knows about the given number of
+// datacenters over the WAN.
+func (s *TestServer) waitForDatacenters(t testing.TB, n int) {
+	retry.Run(t, func(r *retry.R) {
+		var dcs []string
+		if err := s.privilegedJSON("GET", "/v1/catalog/datacenters", nil, &dcs); err != nil {
+			r.Fatal(err)
+		}
+		if len(dcs) != n {
+			r.Fatalf("expected %d datacenters, got %v", n, dcs)
+		}
+	})
+}
+
+// privilegedJSON makes a request with the initial management token, encoding
+// in as the body of the request when it is not nil, and decoding the response
+// into out when it is not nil.
+func (s *TestServer) privilegedJSON(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := s.encodePayload(in)
+		if err != nil {
+			return err
+		}
+		body = payload
+	}
+	req, err := http.NewRequest(method, s.url(path), body)
+	if err != nil {
+		return err
+	}
+	if s.Config.ACL.Tokens.InitialManagement != "" {
+		req.Header.Set("x-consul-token", s.Config.ACL.Tokens.InitialManagement)
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := s.requireOK(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
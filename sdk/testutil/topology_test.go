@@ -0,0 +1,32 @@
+package testutil
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestTopology_Peering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+	if _, err := exec.LookPath("consul"); err != nil {
+		t.Skip("consul not found on $PATH")
+	}
+
+	t.Parallel()
+	topo, err := NewTestTopology(t, TestTopologyConfig{
+		Datacenters: []string{"dc1", "dc2"},
+	})
+	require.NoError(t, err)
+	defer topo.Stop()
+
+	// The clusters are peered with each other, named after their datacenter.
+	topo.Servers["dc1"].WaitForPeeringActive(t, "dc2")
+	topo.Servers["dc2"].WaitForPeeringActive(t, "dc1")
+
+	topo.Servers["dc1"].AddService(t, "web", "passing", nil)
+	topo.ExportServices(t, "dc1", "web")
+	topo.WaitForImportedService(t, "dc2", "dc1", "web")
+}
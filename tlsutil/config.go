@@ -854,6 +854,27 @@ func (c *Configurator) IncomingHTTPSConfig() *tls.Config {
 	return config
 }
 
+// IncomingDNSConfig generates a *tls.Config for incoming DNS-over-TLS
+// connections. It uses the same certificate and settings as the HTTPS
+// listener, but negotiates the "dot" application protocol from RFC 7858.
+func (c *Configurator) IncomingDNSConfig() *tls.Config {
+	c.log("IncomingDNSConfig")
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	config := c.commonTLSConfig(
+		c.https,
+		c.base.HTTPS,
+		c.base.HTTPS.VerifyIncoming,
+	)
+	config.NextProtos = []string{"dot"}
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return c.IncomingDNSConfig(), nil
+	}
+	return config
+}
+
 // OutgoingTLSConfigForCheck generates a *tls.Config for outgoing TLS connections
 // for checks. This function is separated because there is an extra flag to
 // consider for checks. EnableAgentTLSForChecks and InsecureSkipVerify has to
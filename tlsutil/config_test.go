@@ -40,6 +40,10 @@ func TestConfigurator_IncomingConfig_Common(t *testing.T) {
 			func(lc ProtocolConfig) Config { return Config{HTTPS: lc} },
 			func(c *Configurator) *tls.Config { return c.IncomingHTTPSConfig() },
 		},
+		"DNS": {
+			func(lc ProtocolConfig) Config { return Config{HTTPS: lc} },
+			func(c *Configurator) *tls.Config { return c.IncomingDNSConfig() },
+		},
 	}
 
 	for desc, tc := range testCases {
@@ -1487,7 +1491,7 @@ func TestConfigurator_AuthorizeInternalRPCServerConn(t *testing.T) {
 	caPEM, caPK, err := GenerateCA(CAOpts{Days: 5, Domain: "consul"})
 	require.NoError(t, err)
 
-	dir := testutil.TempDir(t, "ca")
+	dir := t.TempDir()
 	caPath := filepath.Join(dir, "ca.pem")
 	err = os.WriteFile(caPath, []byte(caPEM), 0600)
 	require.NoError(t, err)
@@ -1500,10 +1504,10 @@ func TestConfigurator_AuthorizeInternalRPCServerConn(t *testing.T) {
 		CA:     caPEM,
 	})
 	require.NoError(t, err)
-	certFile := filepath.Join("cert.pem")
+	certFile := filepath.Join(dir, "cert.pem")
 	err = os.WriteFile(certFile, []byte(pub), 0600)
 	require.NoError(t, err)
-	keyFile := filepath.Join("cert.key")
+	keyFile := filepath.Join(dir, "cert.key")
 	err = os.WriteFile(keyFile, []byte(pk), 0600)
 	require.NoError(t, err)
 